@@ -0,0 +1,373 @@
+package apperrors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Code classifies a failure independently of the transport it is reported over
+type Code string
+
+// Error codes shared by all services. They mirror the gRPC status codes so a
+// failure keeps its meaning across HTTP and gRPC hops.
+const (
+	InvalidArgument    Code = "invalid_argument"
+	Unauthenticated    Code = "unauthenticated"
+	PermissionDenied   Code = "permission_denied"
+	NotFound           Code = "not_found"
+	AlreadyExists      Code = "already_exists"
+	FailedPrecondition Code = "failed_precondition"
+	ResourceExhausted  Code = "resource_exhausted"
+	Canceled           Code = "canceled"
+	DeadlineExceeded   Code = "deadline_exceeded"
+	Unavailable        Code = "unavailable"
+	Unimplemented      Code = "unimplemented"
+	Internal           Code = "internal"
+)
+
+// StatusClientClosedRequest is returned when the caller went away before the response was ready
+const StatusClientClosedRequest = 499
+
+// Error is a failure with a code and a client-safe message.
+// The wrapped cause is logged but never sent to clients.
+type Error struct {
+	Code    Code
+	Message string
+	Details string
+	Err     error
+}
+
+// New creates an error with the given code and client-facing message
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Newf creates an error with a formatted message
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap attaches a code and client-facing message to err
+func Wrap(err error, code Code, message string) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// Ensure returns err unchanged when it already carries a code and wraps it otherwise
+func Ensure(err error, code Code, message string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := classify(err); ok {
+		return err
+	}
+	return Wrap(err, code, message)
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithDetails attaches client-visible details, such as a validation message
+func (e *Error) WithDetails(details string) *Error {
+	e.Details = details
+	return e
+}
+
+// As returns the first *Error in err's chain
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// CodeOf returns the code carried by err, Internal for untyped errors and "" for nil
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	if code, ok := classify(err); ok {
+		return code
+	}
+	return Internal
+}
+
+func classify(err error) (Code, bool) {
+	if e, ok := As(err); ok {
+		return e.Code, true
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded, true
+	case errors.Is(err, context.Canceled):
+		return Canceled, true
+	}
+	return "", false
+}
+
+// Is reports whether err carries the given code
+func Is(err error, code Code) bool {
+	return CodeOf(err) == code
+}
+
+// Retryable reports whether the failure is transient and the call may be retried
+func Retryable(err error) bool {
+	switch CodeOf(err) {
+	case Unavailable, DeadlineExceeded, ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+// HTTPStatus maps a code to an HTTP status
+func HTTPStatus(code Code) int {
+	switch code {
+	case InvalidArgument:
+		return http.StatusBadRequest
+	case Unauthenticated:
+		return http.StatusUnauthorized
+	case PermissionDenied:
+		return http.StatusForbidden
+	case NotFound:
+		return http.StatusNotFound
+	case AlreadyExists:
+		return http.StatusConflict
+	case FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case ResourceExhausted:
+		return http.StatusTooManyRequests
+	case Canceled:
+		return StatusClientClosedRequest
+	case DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case Unimplemented:
+		return http.StatusNotImplemented
+	}
+	return http.StatusInternalServerError
+}
+
+// FromHTTPStatus maps an HTTP status from a peer that did not report a code
+func FromHTTPStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge:
+		return InvalidArgument
+	case http.StatusUnauthorized:
+		return Unauthenticated
+	case http.StatusForbidden:
+		return PermissionDenied
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return AlreadyExists
+	case http.StatusPreconditionFailed:
+		return FailedPrecondition
+	case http.StatusTooManyRequests:
+		return ResourceExhausted
+	case StatusClientClosedRequest:
+		return Canceled
+	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return DeadlineExceeded
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return Unavailable
+	case http.StatusNotImplemented:
+		return Unimplemented
+	}
+	return Internal
+}
+
+// gRPC status codes, as defined by google.golang.org/grpc/codes
+var grpcCodes = map[Code]uint32{
+	Canceled:           1,
+	InvalidArgument:    3,
+	DeadlineExceeded:   4,
+	NotFound:           5,
+	AlreadyExists:      6,
+	PermissionDenied:   7,
+	ResourceExhausted:  8,
+	FailedPrecondition: 9,
+	Unimplemented:      12,
+	Internal:           13,
+	Unavailable:        14,
+	Unauthenticated:    16,
+}
+
+// GRPCCode maps a code to its gRPC status code (codes.Code)
+func GRPCCode(code Code) uint32 {
+	if c, ok := grpcCodes[code]; ok {
+		return c
+	}
+	return grpcCodes[Internal]
+}
+
+// FromGRPCCode maps a gRPC status code to a code
+func FromGRPCCode(grpcCode uint32) Code {
+	for code, c := range grpcCodes {
+		if c == grpcCode {
+			return code
+		}
+	}
+	return Internal
+}
+
+// ContentType is the media type of error responses (RFC 7807)
+const ContentType = "application/problem+json"
+
+// TypeURI returns the problem type URI of a code
+func TypeURI(code Code) string {
+	return "urn:ai-platform:error:" + string(code)
+}
+
+// titles summarize each code for the problem document's title
+var titles = map[Code]string{
+	InvalidArgument:    "Invalid argument",
+	Unauthenticated:    "Unauthenticated",
+	PermissionDenied:   "Permission denied",
+	NotFound:           "Not found",
+	AlreadyExists:      "Already exists",
+	FailedPrecondition: "Failed precondition",
+	ResourceExhausted:  "Resource exhausted",
+	Canceled:           "Canceled",
+	DeadlineExceeded:   "Deadline exceeded",
+	Unavailable:        "Unavailable",
+	Unimplemented:      "Unimplemented",
+	Internal:           "Internal error",
+}
+
+// Response is the error body returned by every service: an RFC 7807 problem
+// document whose code, retryable and trace_id members let clients act on a
+// failure without parsing its message. error and details repeat detail and
+// the validation details for clients written before problem documents.
+type Response struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	Code      Code   `json:"code"`
+	Retryable bool   `json:"retryable"`
+	TraceID   string `json:"trace_id,omitempty"`
+	Error     string `json:"error"`
+	Details   string `json:"details,omitempty"`
+}
+
+// With returns the document with extension members, such as a rate limit's
+// retry_after, added to it
+func (r Response) With(members map[string]interface{}) map[string]interface{} {
+	var document map[string]interface{}
+	data, _ := json.Marshal(r)
+	json.Unmarshal(data, &document)
+	for name, value := range members {
+		document[name] = value
+	}
+	return document
+}
+
+// ToHTTP returns the status and body for err. Untyped errors become a generic
+// 500 so internal details are not leaked to clients.
+func ToHTTP(err error) (int, Response) {
+	code := CodeOf(err)
+	status := HTTPStatus(code)
+	resp := Response{
+		Type:      TypeURI(code),
+		Title:     titles[code],
+		Status:    status,
+		Code:      code,
+		Retryable: Retryable(err),
+		Detail:    "internal error",
+	}
+
+	if e, ok := As(err); ok {
+		resp.Detail = e.Message
+		resp.Details = e.Details
+	} else if code != Internal {
+		resp.Detail = string(code)
+	}
+	resp.Error = resp.Detail
+
+	return status, resp
+}
+
+// Problem returns the status and body for err, naming the trace of the
+// request it failed
+func Problem(ctx context.Context, err error) (int, Response) {
+	status, resp := ToHTTP(err)
+	resp.TraceID = logging.FieldsFromContext(ctx).TraceID
+	return status, resp
+}
+
+// Write answers r with err as a problem document
+//
+//	apperrors.Write(c.Writer, c.Request, err)
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	status, body := Problem(r.Context(), err)
+	WriteBody(w, status, body)
+}
+
+// WriteHTTP writes err as a problem document, for responses not tied to a request
+func WriteHTTP(w http.ResponseWriter, err error) {
+	status, body := ToHTTP(err)
+	WriteBody(w, status, body)
+}
+
+// WriteBody writes a problem document, such as one extended with With
+func WriteBody(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// maxErrorBody bounds how much of a peer's error response is read
+const maxErrorBody = 64 << 10
+
+// FromHTTPResponse decodes a non-2xx response from another platform service.
+// The peer's code is preserved when present and derived from the status otherwise.
+func FromHTTPResponse(resp *http.Response, service string) *Error {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+	var body Response
+	if err := json.Unmarshal(raw, &body); err == nil && body.Error == "" {
+		// Peers may send problem documents without the compatibility members
+		body.Error = body.Detail
+	}
+	if body.Error == "" {
+		body = Response{Error: http.StatusText(resp.StatusCode)}
+	}
+	if body.Code == "" {
+		body.Code = FromHTTPStatus(resp.StatusCode)
+	}
+
+	return &Error{
+		Code:    body.Code,
+		Message: body.Error,
+		Details: body.Details,
+		Err:     fmt.Errorf("%s returned status %d: %s", service, resp.StatusCode, raw),
+	}
+}
+
+// FromTransportError classifies a failure to reach another service
+func FromTransportError(err error, service string) *Error {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Wrap(err, Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return Wrap(err, DeadlineExceeded, service+" timed out")
+	}
+	return Wrap(err, Unavailable, service+" unavailable")
+}
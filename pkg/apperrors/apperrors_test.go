@@ -0,0 +1,145 @@
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+func TestCodeOf(t *testing.T) {
+	wrapped := fmt.Errorf("lookup: %w", New(NotFound, "model not found"))
+
+	assert.Equal(t, NotFound, CodeOf(wrapped))
+	assert.Equal(t, DeadlineExceeded, CodeOf(fmt.Errorf("call: %w", context.DeadlineExceeded)))
+	assert.Equal(t, Canceled, CodeOf(context.Canceled))
+	assert.Equal(t, Internal, CodeOf(errors.New("boom")))
+	assert.Equal(t, Code(""), CodeOf(nil))
+}
+
+func TestEnsure(t *testing.T) {
+	typed := New(InvalidArgument, "bad input")
+	assert.Same(t, typed, Ensure(typed, Internal, "inference failed"))
+
+	err := Ensure(errors.New("boom"), Internal, "inference failed")
+	assert.Equal(t, Internal, CodeOf(err))
+	assert.Equal(t, "inference failed: boom", err.Error())
+
+	assert.Nil(t, Ensure(nil, Internal, "unused"))
+}
+
+func TestToHTTP(t *testing.T) {
+	status, body := ToHTTP(New(NotFound, "model not found"))
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, Response{
+		Type:   "urn:ai-platform:error:not_found",
+		Title:  "Not found",
+		Status: http.StatusNotFound,
+		Detail: "model not found",
+		Code:   NotFound,
+		Error:  "model not found",
+	}, body)
+
+	// Untyped errors must not leak their message
+	status, body = ToHTTP(errors.New("pq: password authentication failed"))
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, "internal error", body.Error)
+
+	status, body = ToHTTP(context.DeadlineExceeded)
+	assert.Equal(t, http.StatusGatewayTimeout, status)
+	assert.True(t, body.Retryable)
+}
+
+func TestWrite_ProblemDocument(t *testing.T) {
+	req := httptest.NewRequest("GET", "/v1/models/resnet99", nil)
+	req = req.WithContext(logging.WithTrace(req.Context(), "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"))
+	w := httptest.NewRecorder()
+
+	Write(w, req, New(Unavailable, "backend for resnet18/v1 is unavailable"))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "urn:ai-platform:error:unavailable",
+		"title": "Unavailable",
+		"status": 503,
+		"detail": "backend for resnet18/v1 is unavailable",
+		"code": "unavailable",
+		"retryable": true,
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"error": "backend for resnet18/v1 is unavailable"
+	}`, w.Body.String())
+}
+
+func TestResponse_With(t *testing.T) {
+	_, body := ToHTTP(New(ResourceExhausted, "rate limit exceeded"))
+
+	document := body.With(map[string]interface{}{"retry_after": 30})
+
+	assert.Equal(t, 30, document["retry_after"])
+	assert.Equal(t, "resource_exhausted", document["code"])
+	assert.Equal(t, true, document["retryable"])
+}
+
+func TestHTTPStatusRoundTrip(t *testing.T) {
+	for code := range grpcCodes {
+		assert.Equal(t, code, FromHTTPStatus(HTTPStatus(code)), code)
+	}
+}
+
+func TestGRPCCodeRoundTrip(t *testing.T) {
+	for code := range grpcCodes {
+		assert.Equal(t, code, FromGRPCCode(GRPCCode(code)), code)
+	}
+	assert.Equal(t, uint32(14), GRPCCode(Unavailable))
+	assert.Equal(t, Internal, FromGRPCCode(2))
+}
+
+func TestFromHTTPResponse_PreservesPeerCode(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteHTTP(w, New(ResourceExhausted, "backend saturated"))
+
+	err := FromHTTPResponse(w.Result(), "model-router")
+
+	assert.Equal(t, ResourceExhausted, err.Code)
+	assert.Equal(t, "backend saturated", err.Message)
+	assert.True(t, Retryable(err))
+}
+
+func TestFromHTTPResponse_ProblemDocument(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(strings.NewReader(`{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "no such model"}`)),
+	}
+
+	err := FromHTTPResponse(resp, "triton")
+
+	assert.Equal(t, NotFound, err.Code)
+	assert.Equal(t, "no such model", err.Message)
+}
+
+func TestFromHTTPResponse_UntypedPeer(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Body:       io.NopCloser(strings.NewReader("upstream connect error")),
+	}
+
+	err := FromHTTPResponse(resp, "triton")
+
+	assert.Equal(t, Unavailable, err.Code)
+	assert.Equal(t, "Bad Gateway", err.Message)
+	assert.Contains(t, err.Error(), "upstream connect error")
+}
+
+func TestFromTransportError(t *testing.T) {
+	assert.Equal(t, DeadlineExceeded, FromTransportError(context.DeadlineExceeded, "model-router").Code)
+	assert.Equal(t, Unavailable, FromTransportError(errors.New("connection refused"), "model-router").Code)
+}
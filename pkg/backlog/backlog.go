@@ -0,0 +1,67 @@
+// Package backlog describes the batch worker's queue of unfinished work, which
+// the worker publishes and the gateway uses to push back on new batch jobs
+// before the queue grows without bound.
+package backlog
+
+import (
+	"math"
+	"time"
+)
+
+// Path is the batch worker endpoint serving its current Backlog
+const Path = "/v1/backlog"
+
+// Backlog is the batch work accepted but not yet finished
+type Backlog struct {
+	// Lag is the number of job messages queued in Kafka but not yet consumed
+	Lag int64 `json:"lag"`
+	// PendingJobs is the number of consumed jobs waiting for or in processing
+	PendingJobs int64 `json:"pending_jobs"`
+	// Throughput is the recent rate of finished jobs per second
+	Throughput float64 `json:"throughput"`
+	// EstimatedDelaySeconds is how long a job submitted now is expected to
+	// wait before it starts, or -1 when no jobs have finished recently to
+	// estimate from
+	EstimatedDelaySeconds float64   `json:"estimated_delay_seconds"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// New computes the estimated delay of a backlog
+func New(lag, pendingJobs int64, throughput float64, now time.Time) Backlog {
+	b := Backlog{Lag: lag, PendingJobs: pendingJobs, Throughput: throughput, UpdatedAt: now}
+	switch {
+	case b.Depth() == 0:
+		b.EstimatedDelaySeconds = 0
+	case throughput <= 0:
+		b.EstimatedDelaySeconds = -1
+	default:
+		b.EstimatedDelaySeconds = math.Round(float64(b.Depth())/throughput*10) / 10
+	}
+	return b
+}
+
+// Depth is the number of jobs ahead of a job submitted now
+func (b Backlog) Depth() int64 {
+	return b.Lag + b.PendingJobs
+}
+
+// EstimatedDelay returns the expected wait and whether it could be estimated
+func (b Backlog) EstimatedDelay() (time.Duration, bool) {
+	if b.EstimatedDelaySeconds < 0 {
+		return 0, false
+	}
+	return time.Duration(b.EstimatedDelaySeconds * float64(time.Second)), true
+}
+
+// TimeToDrain estimates how long the backlog takes to shrink to depth jobs,
+// or returns false when it cannot be estimated
+func (b Backlog) TimeToDrain(depth int64) (time.Duration, bool) {
+	excess := b.Depth() - depth
+	if excess <= 0 {
+		return 0, true
+	}
+	if b.Throughput <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(excess) / b.Throughput * float64(time.Second)), true
+}
@@ -0,0 +1,40 @@
+package backlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_EstimatesDelay(t *testing.T) {
+	now := time.Now()
+
+	b := New(30, 10, 4, now)
+	assert.Equal(t, int64(40), b.Depth())
+	delay, ok := b.EstimatedDelay()
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, delay)
+
+	drain, ok := b.TimeToDrain(20)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, drain)
+
+	drain, ok = b.TimeToDrain(100)
+	assert.True(t, ok)
+	assert.Zero(t, drain)
+}
+
+func TestNew_NoThroughput(t *testing.T) {
+	b := New(0, 0, 0, time.Now())
+	delay, ok := b.EstimatedDelay()
+	assert.True(t, ok, "an empty backlog has no delay")
+	assert.Zero(t, delay)
+
+	b = New(5, 0, 0, time.Now())
+	_, ok = b.EstimatedDelay()
+	assert.False(t, ok)
+	assert.Equal(t, float64(-1), b.EstimatedDelaySeconds)
+	_, ok = b.TimeToDrain(1)
+	assert.False(t, ok)
+}
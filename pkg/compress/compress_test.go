@@ -0,0 +1,158 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", Gzip},
+		{"gzip, deflate, br", Gzip},
+		{"gzip, zstd", Zstd},
+		{"zstd;q=0.5, gzip", Gzip},
+		{"zstd;q=0, gzip;q=0.1", Gzip},
+		{"GZIP", Gzip},
+		{"*", Zstd},
+		{"*, zstd;q=0", Gzip},
+		{"gzip;q=0", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Negotiate(tt.header), tt.header)
+	}
+}
+
+func serve(handler http.HandlerFunc, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/v1/batch/job-1", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	Middleware(handler, 64).ServeHTTP(w, req)
+	return w
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var r io.Reader
+	switch w.Header().Get("Content-Encoding") {
+	case Gzip:
+		gz, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		r = gz
+	case Zstd:
+		zr, err := zstd.NewReader(w.Body)
+		require.NoError(t, err)
+		defer zr.Close()
+		r = zr
+	default:
+		r = w.Body
+	}
+	body, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMiddleware_CompressesLargeResponses(t *testing.T) {
+	payload := `{"embedding":[` + strings.Repeat("0.125,", 200) + `0.5]}`
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", "999")
+		w.WriteHeader(http.StatusAccepted)
+		// Write in pieces so the threshold is crossed part way through
+		io.WriteString(w, payload[:10])
+		io.WriteString(w, payload[10:])
+	}
+
+	for _, encoding := range []string{Gzip, Zstd} {
+		t.Run(encoding, func(t *testing.T) {
+			w := serve(handler, encoding)
+
+			assert.Equal(t, http.StatusAccepted, w.Code)
+			assert.Equal(t, encoding, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			assert.Empty(t, w.Header().Get("Content-Length"))
+			assert.Less(t, w.Body.Len(), len(payload))
+			assert.Equal(t, payload, decode(t, w))
+		})
+	}
+}
+
+func TestMiddleware_SendsOthersAsIs(t *testing.T) {
+	large := strings.Repeat("a", 256)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		handler        http.HandlerFunc
+		wantBody       string
+	}{
+		{
+			name:           "not accepted",
+			acceptEncoding: "",
+			handler:        func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, large) },
+			wantBody:       large,
+		},
+		{
+			name:           "small",
+			acceptEncoding: "gzip",
+			handler:        func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, `{"status":"ok"}`) },
+			wantBody:       `{"status":"ok"}`,
+		},
+		{
+			name:           "event stream",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				io.WriteString(w, large)
+			},
+			wantBody: large,
+		},
+		{
+			name:           "already encoded",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "br")
+				io.WriteString(w, large)
+			},
+			wantBody: large,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.handler, tt.acceptEncoding)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NotContains(t, []string{Gzip, Zstd}, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestMiddleware_FlushCompressesWhatWasWritten(t *testing.T) {
+	w := serve(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "partial ")
+		w.(http.Flusher).Flush()
+		io.WriteString(w, "result")
+	}, "gzip")
+
+	assert.True(t, w.Flushed)
+	assert.Equal(t, Gzip, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "partial result", decode(t, w))
+}
@@ -0,0 +1,181 @@
+// Package drift describes training baselines and the drift events raised when
+// live feature or prediction distributions move away from them.
+package drift
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// DefaultTopic is the Kafka topic drift events are published to
+const DefaultTopic = "drift-events"
+
+// DefaultThreshold is the population stability index above which a
+// distribution is considered to have drifted
+const DefaultThreshold = 0.2
+
+// Kinds of distributions compared
+const (
+	KindFeature    = "feature"
+	KindPrediction = "prediction"
+)
+
+// epsilon stands in for empty bins so the stability index stays finite
+const epsilon = 1e-4
+
+// Distribution is the binned training distribution of one input or output
+// field. Numeric fields set Edges: bin i holds values <= Edges[i] and a final
+// bin holds larger values. Categorical fields set Categories: bin i holds
+// Categories[i] and a final bin holds every other value. Frequencies has one
+// proportion per bin, including the final one, and sums to 1.
+type Distribution struct {
+	Name        string    `json:"name"`
+	Edges       []float64 `json:"edges,omitempty"`
+	Categories  []string  `json:"categories,omitempty"`
+	Frequencies []float64 `json:"frequencies"`
+}
+
+// Numeric reports whether the distribution bins numbers rather than categories
+func (d Distribution) Numeric() bool {
+	return len(d.Edges) > 0
+}
+
+// Bins returns the number of bins, including the final overflow bin
+func (d Distribution) Bins() int {
+	if d.Numeric() {
+		return len(d.Edges) + 1
+	}
+	return len(d.Categories) + 1
+}
+
+// Bin returns the bin a value falls in, or false when the value's type does
+// not match the distribution
+func (d Distribution) Bin(value interface{}) (int, bool) {
+	if d.Numeric() {
+		var v float64
+		switch n := value.(type) {
+		case float64:
+			v = n
+		case int:
+			v = float64(n)
+		case int64:
+			v = float64(n)
+		default:
+			return 0, false
+		}
+		return sort.SearchFloat64s(d.Edges, v), true
+	}
+
+	var category string
+	switch c := value.(type) {
+	case string:
+		category = c
+	case bool:
+		category = fmt.Sprint(c)
+	default:
+		return 0, false
+	}
+	for i, known := range d.Categories {
+		if known == category {
+			return i, true
+		}
+	}
+	return len(d.Categories), true
+}
+
+// Validate checks that the bins are well formed
+func (d Distribution) Validate() error {
+	if d.Name == "" {
+		return apperrors.New(apperrors.InvalidArgument, "distribution name is required")
+	}
+	if d.Numeric() == (len(d.Categories) > 0) {
+		return apperrors.Newf(apperrors.InvalidArgument, "%s: exactly one of edges or categories is required", d.Name)
+	}
+	if !sort.Float64sAreSorted(d.Edges) {
+		return apperrors.Newf(apperrors.InvalidArgument, "%s: edges must be ascending", d.Name)
+	}
+	if len(d.Frequencies) != d.Bins() {
+		return apperrors.Newf(apperrors.InvalidArgument, "%s: want %d frequencies, got %d", d.Name, d.Bins(), len(d.Frequencies))
+	}
+	var total float64
+	for _, f := range d.Frequencies {
+		if f < 0 {
+			return apperrors.Newf(apperrors.InvalidArgument, "%s: frequencies must not be negative", d.Name)
+		}
+		total += f
+	}
+	if math.Abs(total-1) > 0.01 {
+		return apperrors.Newf(apperrors.InvalidArgument, "%s: frequencies sum to %.3f, want 1", d.Name, total)
+	}
+	return nil
+}
+
+// Baseline holds the training distributions of a model version's input
+// features and predictions
+type Baseline struct {
+	Model       string         `json:"model"`
+	Version     string         `json:"version"`
+	Features    []Distribution `json:"features"`
+	Predictions []Distribution `json:"predictions"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// Validate checks that the baseline describes at least one distribution and
+// that every distribution is well formed
+func (b *Baseline) Validate() error {
+	if len(b.Features) == 0 && len(b.Predictions) == 0 {
+		return apperrors.New(apperrors.InvalidArgument, "baseline has no features or predictions")
+	}
+	for _, d := range append(append([]Distribution(nil), b.Features...), b.Predictions...) {
+		if err := d.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PSI returns the population stability index of observed bin counts against
+// expected proportions: sum((o-e) * ln(o/e)) over bins. Values under 0.1 are
+// usually read as stable and above 0.2 as a significant shift.
+func PSI(expected []float64, observed []int64) float64 {
+	var total int64
+	for _, n := range observed {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var psi float64
+	for i, e := range expected {
+		o := float64(observed[i]) / float64(total)
+		e, o = math.Max(e, epsilon), math.Max(o, epsilon)
+		psi += (o - e) * math.Log(o/e)
+	}
+	return psi
+}
+
+// Result is the comparison of one distribution over a window
+type Result struct {
+	Kind    string  `json:"kind"`
+	Name    string  `json:"name"`
+	PSI     float64 `json:"psi"`
+	Samples int64   `json:"samples"`
+	Drifted bool    `json:"drifted"`
+}
+
+// Event reports that distributions of a model version drifted during a window
+type Event struct {
+	ID          string    `json:"id"`
+	Model       string    `json:"model"`
+	Version     string    `json:"version"`
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+	Threshold   float64   `json:"threshold"`
+	// Drifted lists only the distributions above the threshold
+	Drifted []Result `json:"drifted"`
+}
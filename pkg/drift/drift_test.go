@@ -0,0 +1,59 @@
+package drift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestDistribution_Bin(t *testing.T) {
+	numeric := Distribution{Name: "age", Edges: []float64{18, 65}, Frequencies: []float64{0.2, 0.7, 0.1}}
+	for value, want := range map[float64]int{10: 0, 18: 0, 40: 1, 65: 1, 90: 2} {
+		bin, ok := numeric.Bin(value)
+		assert.True(t, ok)
+		assert.Equal(t, want, bin, "value %v", value)
+	}
+	_, ok := numeric.Bin("old")
+	assert.False(t, ok)
+
+	categorical := Distribution{Name: "label", Categories: []string{"cat", "dog"}, Frequencies: []float64{0.5, 0.4, 0.1}}
+	bin, ok := categorical.Bin("dog")
+	assert.True(t, ok)
+	assert.Equal(t, 1, bin)
+	bin, _ = categorical.Bin("bird")
+	assert.Equal(t, 2, bin)
+	_, ok = categorical.Bin(3.0)
+	assert.False(t, ok)
+}
+
+func TestBaseline_Validate(t *testing.T) {
+	valid := Distribution{Name: "age", Edges: []float64{18, 65}, Frequencies: []float64{0.2, 0.7, 0.1}}
+	assert.NoError(t, (&Baseline{Features: []Distribution{valid}}).Validate())
+
+	for name, d := range map[string]Distribution{
+		"no name":        {Edges: []float64{1}, Frequencies: []float64{0.5, 0.5}},
+		"no bins":        {Name: "x", Frequencies: []float64{1}},
+		"both bins":      {Name: "x", Edges: []float64{1}, Categories: []string{"a"}, Frequencies: []float64{0.5, 0.5}},
+		"unsorted edges": {Name: "x", Edges: []float64{2, 1}, Frequencies: []float64{0.3, 0.3, 0.4}},
+		"missing bin":    {Name: "x", Edges: []float64{1}, Frequencies: []float64{1}},
+		"does not sum":   {Name: "x", Edges: []float64{1}, Frequencies: []float64{0.5, 0.2}},
+		"negative bin":   {Name: "x", Categories: []string{"a"}, Frequencies: []float64{1.5, -0.5}},
+	} {
+		err := (&Baseline{Predictions: []Distribution{d}}).Validate()
+		assert.True(t, apperrors.Is(err, apperrors.InvalidArgument), name)
+	}
+	assert.Error(t, (&Baseline{}).Validate())
+}
+
+func TestPSI(t *testing.T) {
+	expected := []float64{0.25, 0.25, 0.25, 0.25}
+
+	assert.InDelta(t, 0, PSI(expected, []int64{25, 25, 25, 25}), 1e-9)
+	assert.Less(t, PSI(expected, []int64{27, 24, 25, 24}), 0.1)
+	assert.Greater(t, PSI(expected, []int64{70, 10, 10, 10}), DefaultThreshold)
+	// Empty bins are smoothed rather than making the index infinite
+	assert.Greater(t, PSI(expected, []int64{100, 0, 0, 0}), DefaultThreshold)
+	assert.Zero(t, PSI(expected, []int64{0, 0, 0, 0}))
+}
@@ -0,0 +1,118 @@
+// Package events describes the operational events services publish for the
+// notification service: finished jobs, burning SLOs, promoted and quarantined
+// models and tripped circuit breakers.
+package events
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+)
+
+// DefaultTopic is the Kafka topic platform events are published to
+const DefaultTopic = "platform-events"
+
+// Type names what happened
+type Type string
+
+const (
+	JobCompleted     Type = "job.completed"
+	JobFailed        Type = "job.failed"
+	SLOBurning       Type = "slo.burning"
+	ModelPromoted    Type = "model.promoted"
+	ModelQuarantined Type = "model.quarantined"
+	CircuitOpened    Type = "circuit.opened"
+)
+
+// Severity ranks how urgently an event needs attention
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Rank orders severities from info (0) to critical (2); unknown severities rank as info
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityWarning:
+		return 1
+	case SeverityCritical:
+		return 2
+	}
+	return 0
+}
+
+// Event is something that happened on the platform. Subject identifies what it
+// happened to, such as a job ID, a model version or a backend, so repeated
+// events about the same thing can be deduplicated.
+type Event struct {
+	ID         string            `json:"id"`
+	Type       Type              `json:"type"`
+	Severity   Severity          `json:"severity"`
+	Tenant     string            `json:"tenant,omitempty"`
+	Service    string            `json:"service"`
+	Subject    string            `json:"subject"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// Emitter publishes events in the background so notifying never slows or
+// fails the work being reported. Events are dropped when the buffer is full.
+// A nil Emitter emits nothing.
+type Emitter struct {
+	service string
+	queue   *publish.Queue[Event]
+}
+
+// NewEmitter creates an emitter for service that buffers up to size events,
+// published keyed by subject so events about the same thing stay ordered
+func NewEmitter(service string, publisher publish.Publisher, size int, logger *zap.Logger) *Emitter {
+	return &Emitter{
+		service: service,
+		queue: publish.NewQueue("platform event", publisher, func(event Event) string {
+			return event.Subject
+		}, size, logger),
+	}
+}
+
+// Emit queues an event. ID, service, severity, tenant and time are filled in
+// from the emitter and the request context when left empty.
+func (e *Emitter) Emit(ctx context.Context, event Event) {
+	if e == nil {
+		return
+	}
+
+	if event.ID == "" {
+		event.ID = publish.NewID()
+	}
+	if event.Service == "" {
+		event.Service = e.service
+	}
+	if event.Severity == "" {
+		event.Severity = SeverityInfo
+	}
+	if event.Tenant == "" {
+		event.Tenant = logging.FieldsFromContext(ctx).Tenant
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	e.queue.Enqueue(event)
+}
+
+// Dropped returns how many events were discarded because the buffer was full
+func (e *Emitter) Dropped() int64 {
+	return e.queue.Dropped()
+}
+
+// Run publishes queued events until ctx is cancelled, then flushes what is left
+func (e *Emitter) Run(ctx context.Context) {
+	e.queue.Run(ctx)
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+)
+
+func TestEmitter_FillsDefaultsAndFlushesOnShutdown(t *testing.T) {
+	var keys []string
+	var published []Event
+	emitter := NewEmitter("batch-worker", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event Event
+		require.NoError(t, json.Unmarshal(value, &event))
+		keys = append(keys, key)
+		published = append(published, event)
+		return nil
+	}), 10, zap.NewNop())
+
+	ctx := logging.WithTenant(context.Background(), "acme")
+	emitter.Emit(ctx, Event{Type: JobCompleted, Subject: "job-1"})
+	emitter.Emit(context.Background(), Event{Type: CircuitOpened, Severity: SeverityCritical, Subject: "resnet18/v1"})
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	emitter.Run(runCtx)
+
+	require.Len(t, published, 2)
+	assert.Equal(t, "acme", published[0].Tenant)
+	assert.Equal(t, "batch-worker", published[0].Service)
+	assert.Equal(t, SeverityInfo, published[0].Severity)
+	assert.NotEmpty(t, published[0].ID)
+	assert.False(t, published[0].OccurredAt.IsZero())
+	assert.Empty(t, published[1].Tenant)
+	assert.Equal(t, []string{"job-1", "resnet18/v1"}, keys)
+}
+
+func TestEmitter_DropsWhenFullAndNilIsNoop(t *testing.T) {
+	emitter := NewEmitter("model-router", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		return nil
+	}), 1, zap.NewNop())
+	emitter.Emit(context.Background(), Event{Type: CircuitOpened})
+	emitter.Emit(context.Background(), Event{Type: CircuitOpened})
+	assert.Equal(t, int64(1), emitter.Dropped())
+
+	var nilEmitter *Emitter
+	nilEmitter.Emit(context.Background(), Event{Type: JobCompleted})
+}
+
+func TestSeverity_Rank(t *testing.T) {
+	assert.Less(t, SeverityInfo.Rank(), SeverityWarning.Rank())
+	assert.Less(t, SeverityWarning.Rank(), SeverityCritical.Rank())
+	assert.Equal(t, 0, Severity("").Rank())
+}
@@ -0,0 +1,15 @@
+package events
+
+import "github.com/IBM/sarama"
+
+// NewKafkaProducer creates a producer for the platform's Kafka topics. It
+// waits for every in-sync replica, so acknowledged records survive the loss
+// of a broker.
+func NewKafkaProducer(brokers []string) (sarama.SyncProducer, error) {
+	config := sarama.NewConfig()
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Retry.Max = 5
+	config.Producer.Return.Successes = true
+
+	return sarama.NewSyncProducer(brokers, config)
+}
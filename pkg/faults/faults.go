@@ -0,0 +1,388 @@
+package faults
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Type is the kind of failure a rule injects
+type Type string
+
+const (
+	// Latency delays the request before it is handled
+	Latency Type = "latency"
+	// Error answers the request with an error status instead of handling it
+	Error Type = "error"
+	// Drop closes the connection without writing a response
+	Drop Type = "drop"
+)
+
+// HeaderInjected names the fault applied to a response, for tests to tell injected failures from real ones
+const HeaderInjected = "X-Fault-Injected"
+
+// AdminPath serves the active rules when runtime changes are enabled
+const AdminPath = "/admin/faults"
+
+// maxModelPeek bounds how much of a request body is read to find the model name
+const maxModelPeek = 1 << 20
+
+// Duration is a time.Duration that encodes as a string such as "250ms"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"250ms\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Rule injects one kind of fault into matching requests.
+// Empty selectors match everything; Route matches by path prefix.
+type Rule struct {
+	Type    Type     `json:"type"`
+	Service string   `json:"service,omitempty"`
+	Route   string   `json:"route,omitempty"`
+	Model   string   `json:"model,omitempty"`
+	Rate    float64  `json:"rate,omitempty"`    // fraction of matching requests affected, default 1
+	Latency Duration `json:"latency,omitempty"` // for latency rules
+	Status  int      `json:"status,omitempty"`  // for error rules, default 503
+}
+
+// Validate checks the rule and fills in defaults
+func (r *Rule) Validate() error {
+	switch r.Type {
+	case Latency:
+		if r.Latency <= 0 {
+			return fmt.Errorf("latency rule needs a positive latency")
+		}
+	case Error:
+		if r.Status == 0 {
+			r.Status = http.StatusServiceUnavailable
+		}
+		if r.Status < 400 || r.Status > 599 {
+			return fmt.Errorf("error rule status must be 4xx or 5xx, got %d", r.Status)
+		}
+	case Drop:
+	default:
+		return fmt.Errorf("unknown fault type %q", r.Type)
+	}
+
+	if r.Rate == 0 {
+		r.Rate = 1
+	}
+	if r.Rate < 0 || r.Rate > 1 {
+		return fmt.Errorf("rate must be between 0 and 1, got %v", r.Rate)
+	}
+	return nil
+}
+
+// ParseRules decodes and validates a JSON array of rules
+func ParseRules(data []byte) ([]Rule, error) {
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse fault rules: %w", err)
+	}
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return nil, fmt.Errorf("fault rule %d: %w", i, err)
+		}
+	}
+	return rules, nil
+}
+
+// Injector applies fault rules to a service's inbound requests
+type Injector struct {
+	service string
+	logger  *zap.Logger
+	rules   atomic.Value // []Rule
+
+	// Runtime changes through the admin handler are refused unless enabled
+	mutable bool
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewInjector creates an injector for service with no rules
+func NewInjector(service string, logger *zap.Logger) *Injector {
+	i := &Injector{
+		service: service,
+		logger:  logger,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	i.rules.Store([]Rule{})
+	return i
+}
+
+// FromEnv creates an injector configured by FAULT_INJECTION_RULES (a JSON array)
+// or FAULT_INJECTION_FILE (a JSON file, e.g. a mounted flag ConfigMap, reloaded on
+// change). FAULT_INJECTION_ENABLED=true also allows changing rules at runtime
+// through AdminPath. Without any of these the injector is a no-op.
+func FromEnv(ctx context.Context, service string, logger *zap.Logger) (*Injector, error) {
+	i := NewInjector(service, logger)
+	i.mutable = os.Getenv("FAULT_INJECTION_ENABLED") == "true"
+
+	if inline := os.Getenv("FAULT_INJECTION_RULES"); inline != "" {
+		rules, err := ParseRules([]byte(inline))
+		if err != nil {
+			return nil, err
+		}
+		i.SetRules(rules)
+	}
+
+	if path := os.Getenv("FAULT_INJECTION_FILE"); path != "" {
+		if err := i.Watch(ctx, path, 10*time.Second); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// Enabled reports whether runtime rule changes are allowed
+func (i *Injector) Enabled() bool {
+	return i.mutable
+}
+
+// SetRules replaces the active rules. Rules must already be validated.
+func (i *Injector) SetRules(rules []Rule) {
+	i.rules.Store(rules)
+	if len(rules) > 0 {
+		i.logger.Warn("fault injection active", zap.String("service", i.service), zap.Int("rules", len(rules)))
+	}
+}
+
+// Rules returns the active rules
+func (i *Injector) Rules() []Rule {
+	return i.rules.Load().([]Rule)
+}
+
+// Watch loads rules from path and reloads them whenever the file changes.
+// A file that fails to parse leaves the previous rules in place.
+func (i *Injector) Watch(ctx context.Context, path string, interval time.Duration) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read fault rules: %w", err)
+	}
+	if err := i.load(path); err != nil {
+		return err
+	}
+
+	go func() {
+		modTime := info.ModTime()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Equal(modTime) {
+				continue
+			}
+			modTime = info.ModTime()
+			if err := i.load(path); err != nil {
+				i.logger.Error("failed to reload fault rules", zap.String("path", path), zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+func (i *Injector) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read fault rules: %w", err)
+	}
+	rules, err := ParseRules(data)
+	if err != nil {
+		return err
+	}
+	i.SetRules(rules)
+	return nil
+}
+
+// matching returns the rules that fire for this request
+func (i *Injector) matching(rules []Rule, path, model string) []Rule {
+	var fired []Rule
+	for _, rule := range rules {
+		if rule.Service != "" && rule.Service != i.service {
+			continue
+		}
+		if rule.Route != "" && !strings.HasPrefix(path, rule.Route) {
+			continue
+		}
+		// Probes only fail when a rule targets them explicitly
+		if rule.Route == "" && isProbe(path) {
+			continue
+		}
+		if rule.Model != "" && rule.Model != model {
+			continue
+		}
+		if rule.Rate < 1 && i.roll() >= rule.Rate {
+			continue
+		}
+		fired = append(fired, rule)
+	}
+	return fired
+}
+
+func (i *Injector) roll() float64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Float64()
+}
+
+func isProbe(path string) bool {
+	switch path {
+	case "/health", "/healthz", "/readyz", "/metrics", AdminPath:
+		return true
+	}
+	return false
+}
+
+// Middleware injects faults into inbound requests before next handles them
+func (i *Injector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rules := i.Rules()
+		if len(rules) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		model := ""
+		if needsModel(rules) {
+			model = peekModel(r)
+		}
+
+		fired := i.matching(rules, r.URL.Path, model)
+		if len(fired) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger := logging.With(r.Context(), i.logger)
+		for _, rule := range fired {
+			switch rule.Type {
+			case Latency:
+				logger.Info("injecting latency", zap.String("path", r.URL.Path), zap.Duration("latency", time.Duration(rule.Latency)))
+				w.Header().Add(HeaderInjected, string(Latency))
+				select {
+				case <-time.After(time.Duration(rule.Latency)):
+				case <-r.Context().Done():
+					return
+				}
+			case Drop:
+				logger.Info("injecting dropped connection", zap.String("path", r.URL.Path))
+				// net/http closes the connection without a response for this sentinel
+				panic(http.ErrAbortHandler)
+			case Error:
+				logger.Info("injecting error", zap.String("path", r.URL.Path), zap.Int("status", rule.Status))
+				w.Header().Add(HeaderInjected, string(Error))
+				err := apperrors.Newf(apperrors.FromHTTPStatus(rule.Status), "injected fault: %s", http.StatusText(rule.Status))
+				_, body := apperrors.ToHTTP(err)
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.WriteHeader(rule.Status)
+				json.NewEncoder(w).Encode(body)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func needsModel(rules []Rule) bool {
+	for _, rule := range rules {
+		if rule.Model != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// peekModel reads the "model" field of a JSON body and restores the body for the handler
+func peekModel(r *http.Request) string {
+	if r.Body == nil || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return r.URL.Query().Get("model")
+	}
+
+	head, err := io.ReadAll(io.LimitReader(r.Body, maxModelPeek))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+	if err != nil {
+		return ""
+	}
+
+	var body struct {
+		Model string `json:"model"`
+	}
+	json.Unmarshal(head, &body)
+	return body.Model
+}
+
+// AdminHandler serves GET and PUT of the active rules at AdminPath.
+// PUT is refused unless FAULT_INJECTION_ENABLED=true.
+func (i *Injector) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			if !i.mutable {
+				apperrors.WriteHTTP(w, apperrors.New(apperrors.PermissionDenied, "fault injection is disabled"))
+				return
+			}
+			data, err := io.ReadAll(io.LimitReader(r.Body, maxModelPeek))
+			if err != nil {
+				apperrors.WriteHTTP(w, apperrors.Wrap(err, apperrors.InvalidArgument, "failed to read rules"))
+				return
+			}
+			rules, err := ParseRules(data)
+			if err != nil {
+				apperrors.WriteHTTP(w, apperrors.New(apperrors.InvalidArgument, "invalid fault rules").WithDetails(err.Error()))
+				return
+			}
+			i.SetRules(rules)
+		default:
+			apperrors.WriteHTTP(w, apperrors.New(apperrors.Unimplemented, "method not allowed"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"service": i.service,
+			"rules":   i.Rules(),
+		})
+	})
+}
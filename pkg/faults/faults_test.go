@@ -0,0 +1,172 @@
+package faults
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func echoHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	})
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`[
+		{"type": "latency", "latency": "250ms"},
+		{"type": "error", "route": "/v1/infer", "rate": 0.5}
+	]`))
+	require.NoError(t, err)
+	assert.Equal(t, Duration(250*time.Millisecond), rules[0].Latency)
+	assert.Equal(t, 1.0, rules[0].Rate)
+	assert.Equal(t, http.StatusServiceUnavailable, rules[1].Status)
+
+	_, err = ParseRules([]byte(`[{"type": "latency"}]`))
+	assert.Error(t, err)
+	_, err = ParseRules([]byte(`[{"type": "error", "status": 200}]`))
+	assert.Error(t, err)
+	_, err = ParseRules([]byte(`[{"type": "explode"}]`))
+	assert.Error(t, err)
+}
+
+func TestMiddleware_InjectsErrorForMatchingRoute(t *testing.T) {
+	injector := NewInjector("api-gateway", zap.NewNop())
+	injector.SetRules([]Rule{
+		{Type: Error, Route: "/v1/infer", Status: http.StatusBadGateway, Rate: 1},
+		{Type: Error, Service: "model-router", Rate: 1, Status: 500},
+	})
+	handler := injector.Middleware(echoHandler())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Equal(t, "error", w.Header().Get(HeaderInjected))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Contains(t, body["error"], "injected fault")
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/v1/models", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestMiddleware_MatchesModelAndRestoresBody(t *testing.T) {
+	injector := NewInjector("api-gateway", zap.NewNop())
+	injector.SetRules([]Rule{{Type: Error, Model: "resnet18", Rate: 1, Status: 503}})
+	handler := injector.Middleware(echoHandler())
+
+	request := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/v1/infer", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, request(`{"model":"resnet18"}`).Code)
+
+	w := request(`{"model":"bert"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"model":"bert"}`, w.Body.String())
+}
+
+func TestMiddleware_InjectsLatency(t *testing.T) {
+	injector := NewInjector("model-router", zap.NewNop())
+	injector.SetRules([]Rule{{Type: Latency, Latency: Duration(50 * time.Millisecond), Rate: 1}})
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	injector.Middleware(echoHandler()).ServeHTTP(w, httptest.NewRequest("GET", "/route", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, "latency", w.Header().Get(HeaderInjected))
+}
+
+func TestMiddleware_DropsConnection(t *testing.T) {
+	injector := NewInjector("model-router", zap.NewNop())
+	injector.SetRules([]Rule{{Type: Drop, Rate: 1}})
+
+	server := httptest.NewServer(injector.Middleware(echoHandler()))
+	defer server.Close()
+
+	_, err := http.Get(server.URL + "/route")
+	assert.Error(t, err)
+}
+
+func TestMiddleware_LowRateRarelyFires(t *testing.T) {
+	injector := NewInjector("model-router", zap.NewNop())
+	injector.SetRules([]Rule{{Type: Error, Rate: 0.0001, Status: 503}})
+	handler := injector.Middleware(echoHandler())
+
+	failures := 0
+	for n := 0; n < 100; n++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/route", nil))
+		if w.Code != http.StatusOK {
+			failures++
+		}
+	}
+	assert.Less(t, failures, 5)
+}
+
+func TestAdminHandler(t *testing.T) {
+	injector := NewInjector("model-router", zap.NewNop())
+	handler := injector.AdminHandler()
+
+	put := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("PUT", AdminPath, bytes.NewBufferString(`[{"type":"drop"}]`)))
+		return w
+	}
+
+	assert.Equal(t, http.StatusForbidden, put().Code)
+	assert.Empty(t, injector.Rules())
+
+	injector.mutable = true
+	assert.Equal(t, http.StatusOK, put().Code)
+	assert.Equal(t, []Rule{{Type: Drop, Rate: 1}}, injector.Rules())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("PUT", AdminPath, bytes.NewBufferString(`[{"type":"latency"}]`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, injector.Rules(), 1)
+}
+
+func TestWatch_ReloadsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "faults.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[]`), 0o644))
+
+	injector := NewInjector("model-router", zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, injector.Watch(ctx, path, 10*time.Millisecond))
+	assert.Empty(t, injector.Rules())
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{"type":"error"}]`), 0o644))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	assert.Eventually(t, func() bool {
+		return len(injector.Rules()) == 1
+	}, time.Second, 10*time.Millisecond)
+}
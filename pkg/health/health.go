@@ -0,0 +1,272 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Probe paths served by every platform service
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// DefaultTimeout bounds each dependency check
+const DefaultTimeout = 2 * time.Second
+
+// Check reports whether a dependency is usable
+type Check func(ctx context.Context) error
+
+// CheckResult is the outcome of a single dependency check
+type CheckResult struct {
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+	// Optional checks degrade the service rather than make it unready
+	Optional bool `json:"optional,omitempty"`
+}
+
+// Report is the body returned by the readiness endpoint. Its status is
+// "ready", "degraded" when only optional checks failed, or "not_ready".
+type Report struct {
+	Status  string                 `json:"status"`
+	Service string                 `json:"service"`
+	Checks  map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Ready reports whether every required check passed
+func (r *Report) Ready() bool {
+	return r.Status != "not_ready"
+}
+
+// Health is the report's status as the health endpoint states it:
+// "healthy", "degraded" or "unhealthy"
+func (r *Report) Health() string {
+	switch r.Status {
+	case "ready":
+		return "healthy"
+	case "degraded":
+		return "degraded"
+	}
+	return "unhealthy"
+}
+
+type namedCheck struct {
+	name     string
+	check    Check
+	optional bool
+}
+
+// Checker runs a service's dependency checks for liveness and readiness probes
+type Checker struct {
+	service string
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []namedCheck
+}
+
+// NewChecker creates a new health checker for the given service
+func NewChecker(service string, timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{
+		service: service,
+		timeout: timeout,
+	}
+}
+
+// Add registers a readiness check
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// AddOptional registers a check of a dependency the service can work
+// without, such as one only some requests need. Its failure reports the
+// service degraded but still ready.
+func (c *Checker) AddOptional(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, namedCheck{name: name, check: check, optional: true})
+}
+
+// Run executes all checks concurrently, each bounded by the checker's timeout
+func (c *Checker) Run(ctx context.Context) *Report {
+	c.mu.RLock()
+	checks := make([]namedCheck, len(c.checks))
+	copy(checks, c.checks)
+	c.mu.RUnlock()
+
+	report := &Report{
+		Status:  "ready",
+		Service: c.service,
+		Checks:  make(map[string]CheckResult, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, nc := range checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+			result := c.runCheck(ctx, nc.check)
+			result.Optional = nc.optional
+
+			mu.Lock()
+			report.Checks[nc.name] = result
+			switch {
+			case result.Status == "up":
+			case !nc.optional:
+				report.Status = "not_ready"
+			case report.Status == "ready":
+				report.Status = "degraded"
+			}
+			mu.Unlock()
+		}(nc)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (c *Checker) runCheck(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		errCh <- check(ctx)
+	}()
+
+	// A check that ignores its context still cannot hold up the probe
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", c.timeout)
+	}
+
+	result := CheckResult{
+		Status:     "up",
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = "down"
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// LivenessHandler reports that the process is up. It never checks dependencies,
+// so an outage downstream does not cause the orchestrator to restart the pod.
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"status":  "healthy",
+			"service": c.service,
+		})
+	})
+}
+
+// ReadinessHandler runs all checks and returns 503 when any required one fails
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	})
+}
+
+// HealthHandler runs all checks and reports the service "healthy",
+// "degraded" or "unhealthy" with each dependency's result, for dashboards and
+// operators. Like readiness it returns 503 when the service is unhealthy.
+func (c *Checker) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, Report{
+			Status:  report.Health(),
+			Service: report.Service,
+			Checks:  report.Checks,
+		})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// HTTPCheck checks that url answers with a 2xx status.
+// Use it for downstream services, pointed at their LivenessPath.
+func HTTPCheck(client *http.Client, url string) Check {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// TCPCheck checks that at least one of addrs accepts connections.
+// Use it for Kafka, where any reachable broker can serve metadata.
+func TCPCheck(addrs ...string) Check {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		var lastErr error
+		for _, addr := range addrs {
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			if err == nil {
+				conn.Close()
+				return nil
+			}
+			lastErr = err
+		}
+
+		if lastErr == nil {
+			return fmt.Errorf("no addresses configured")
+		}
+		return lastErr
+	}
+}
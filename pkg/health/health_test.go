@@ -0,0 +1,155 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLivenessHandler_IgnoresChecks(t *testing.T) {
+	checker := NewChecker("model-router", time.Second)
+	checker.Add("orchestrator", func(context.Context) error { return errors.New("down") })
+
+	w := httptest.NewRecorder()
+	checker.LivenessHandler().ServeHTTP(w, httptest.NewRequest("GET", LivenessPath, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "healthy")
+	assert.Contains(t, w.Body.String(), "model-router")
+}
+
+func TestReadinessHandler_AllUp(t *testing.T) {
+	checker := NewChecker("metadata-service", time.Second)
+	checker.Add("postgres", func(context.Context) error { return nil })
+	checker.Add("redis", func(context.Context) error { return nil })
+
+	w := httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(w, httptest.NewRequest("GET", ReadinessPath, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, "ready", report.Status)
+	assert.Equal(t, "up", report.Checks["postgres"].Status)
+	assert.Equal(t, "up", report.Checks["redis"].Status)
+}
+
+func TestReadinessHandler_FailingCheck(t *testing.T) {
+	checker := NewChecker("metadata-service", time.Second)
+	checker.Add("postgres", func(context.Context) error { return nil })
+	checker.Add("redis", func(context.Context) error { return errors.New("connection refused") })
+
+	w := httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(w, httptest.NewRequest("GET", ReadinessPath, nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, "not_ready", report.Status)
+	assert.Equal(t, "down", report.Checks["redis"].Status)
+	assert.Equal(t, "connection refused", report.Checks["redis"].Error)
+}
+
+func TestReadinessHandler_OptionalCheck(t *testing.T) {
+	checker := NewChecker("api-gateway", time.Second)
+	checker.Add("redis", func(context.Context) error { return nil })
+	checker.AddOptional("kafka", func(context.Context) error { return errors.New("connection refused") })
+
+	w := httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(w, httptest.NewRequest("GET", ReadinessPath, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, "degraded", report.Status)
+	assert.Equal(t, "down", report.Checks["kafka"].Status)
+	assert.True(t, report.Checks["kafka"].Optional)
+
+	// A required check failing makes the service unready whatever else failed
+	checker.Add("model-router", func(context.Context) error { return errors.New("timeout") })
+	assert.Equal(t, "not_ready", checker.Run(context.Background()).Status)
+}
+
+func TestHealthHandler(t *testing.T) {
+	checker := NewChecker("api-gateway", time.Second)
+	checker.Add("redis", func(context.Context) error { return nil })
+
+	health := func() (int, Report) {
+		w := httptest.NewRecorder()
+		checker.HealthHandler().ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+		var report Report
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return w.Code, report
+	}
+
+	code, report := health()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "healthy", report.Status)
+	assert.Equal(t, "up", report.Checks["redis"].Status)
+
+	checker.AddOptional("kafka", func(context.Context) error { return errors.New("connection refused") })
+	code, report = health()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "degraded", report.Status)
+
+	checker.Add("model-router", func(context.Context) error { return errors.New("timeout") })
+	code, report = health()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unhealthy", report.Status)
+}
+
+func TestRun_Timeout(t *testing.T) {
+	checker := NewChecker("api-gateway", 50*time.Millisecond)
+	block := make(chan struct{})
+	defer close(block)
+	checker.Add("kafka", func(context.Context) error {
+		<-block // ignores its context
+		return nil
+	})
+
+	start := time.Now()
+	report := checker.Run(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, report.Ready())
+	assert.Contains(t, report.Checks["kafka"].Error, "timed out")
+}
+
+func TestHTTPCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == LivenessPath {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, HTTPCheck(nil, server.URL+LivenessPath)(context.Background()))
+	assert.Error(t, HTTPCheck(nil, server.URL+"/other")(context.Background()))
+}
+
+func TestTCPCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	assert.NoError(t, TCPCheck(closedAddr, listener.Addr().String())(context.Background()))
+	assert.Error(t, TCPCheck(closedAddr)(context.Background()))
+	assert.Error(t, TCPCheck()(context.Background()))
+}
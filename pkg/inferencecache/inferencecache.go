@@ -0,0 +1,164 @@
+// Package inferencecache answers repeated inferences of deterministic model
+// versions from a tiered cache: each replica's own recent results in front
+// of Redis. Only versions given a TTL by the cache's TTL source are cached,
+// so caching is opt-in; entries are keyed by a hash of the model, version
+// and input.
+package inferencecache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/tiered"
+)
+
+// MaxEntryBytes is the largest result cached
+const MaxEntryBytes = 1 << 20
+
+// localSize is how many results each replica keeps in its own tier
+const localSize = 1000
+
+// Lookup results reported to the cache's observer
+const (
+	Hit  = "hit"
+	Miss = "miss"
+	// Error is a miss while the remote tier is unavailable
+	Error = "error"
+)
+
+// TTLs says how long the results of a model version are cached; zero or less
+// is not cached
+type TTLs interface {
+	TTL(ctx context.Context, model, version string) time.Duration
+}
+
+// TTLsFunc adapts a function to TTLs
+type TTLsFunc func(ctx context.Context, model, version string) time.Duration
+
+// TTL calls f
+func (f TTLsFunc) TTL(ctx context.Context, model, version string) time.Duration {
+	return f(ctx, model, version)
+}
+
+// ModelTTLs caches every version of a model for the same TTL
+type ModelTTLs map[string]time.Duration
+
+// TTL returns the TTL of model
+func (m ModelTTLs) TTL(ctx context.Context, model, version string) time.Duration {
+	return m[model]
+}
+
+// ParseModelTTLs parses a JSON object of models and how long their results
+// are cached, such as {"text-embedding": "24h"}
+func ParseModelTTLs(data []byte) (ModelTTLs, error) {
+	ttls := make(ModelTTLs)
+	if len(data) == 0 {
+		return ttls, nil
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid cached models: %w", err)
+	}
+	for model, value := range raw {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid cache ttl %q for model %s", value, model)
+		}
+		ttls[model] = ttl
+	}
+	return ttls, nil
+}
+
+// Cache caches the results of the model versions its TTL source gives a
+// TTL. While the remote tier is unavailable each replica serves the results
+// it holds itself, and caches new ones only for itself. A nil Cache caches
+// nothing.
+type Cache struct {
+	namespace string
+	tiers     *tiered.Cache
+	ttls      TTLs
+	observe   func(model, result string)
+}
+
+// New creates a cache kept in remote under keys starting with namespace,
+// caching model versions for as long as ttls says
+func New(namespace string, remote tiered.Remote, ttls TTLs, logger *zap.Logger) *Cache {
+	return &Cache{
+		namespace: namespace,
+		tiers:     tiered.New(remote, tiered.Options{LocalSize: localSize}, logger),
+		ttls:      ttls,
+		observe:   func(model, result string) {},
+	}
+}
+
+// SetObserver reports each lookup's model and result: Hit, Miss or Error
+func (c *Cache) SetObserver(observe func(model, result string)) {
+	c.observe = observe
+}
+
+// Enabled reports whether the results of model version are cached
+func (c *Cache) Enabled(ctx context.Context, model, version string) bool {
+	return c != nil && c.ttls.TTL(ctx, model, version) > 0
+}
+
+// Get returns the cached result of model version for input
+func (c *Cache) Get(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, bool) {
+	if !c.Enabled(ctx, model, version) {
+		return nil, false
+	}
+	key, err := c.key(model, version, input)
+	if err != nil {
+		return nil, false
+	}
+
+	data, ok := c.tiers.Get(ctx, key)
+	var result map[string]interface{}
+	if !ok || json.Unmarshal(data, &result) != nil {
+		if c.tiers.Degraded() {
+			c.observe(model, Error)
+		} else {
+			c.observe(model, Miss)
+		}
+		return nil, false
+	}
+	c.observe(model, Hit)
+	return result, true
+}
+
+// Set caches the result of model version for input, unless it is over
+// MaxEntryBytes
+func (c *Cache) Set(ctx context.Context, model, version string, input, result map[string]interface{}) {
+	if c == nil {
+		return
+	}
+	ttl := c.ttls.TTL(ctx, model, version)
+	if ttl <= 0 {
+		return
+	}
+	key, err := c.key(model, version, input)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil || len(data) > MaxEntryBytes {
+		return
+	}
+	c.tiers.SetTTL(ctx, key, data, ttl)
+}
+
+// key returns the cache key of model, version and input. Inputs equal as
+// JSON share a key, whatever the order of their fields.
+func (c *Cache) key(model, version string, input map[string]interface{}) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s:%s:%s:%s", c.namespace, model, version, hex.EncodeToString(sum[:])), nil
+}
@@ -0,0 +1,140 @@
+package inferencecache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/tiered"
+)
+
+type memoryRemote struct {
+	values map[string][]byte
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newMemoryRemote() *memoryRemote {
+	return &memoryRemote{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (m *memoryRemote) Get(ctx context.Context, key string) ([]byte, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	value, ok := m.values[key]
+	if !ok {
+		return nil, tiered.ErrMiss
+	}
+	return value, nil
+}
+
+func (m *memoryRemote) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.values[key] = value
+	m.ttls[key] = ttl
+	return nil
+}
+
+func (m *memoryRemote) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(m.values, key)
+	}
+	return m.err
+}
+
+func TestCache_GetSet(t *testing.T) {
+	remote := newMemoryRemote()
+	cache := New("inference:cache", remote, ModelTTLs{"text-embedding": time.Hour}, zap.NewNop())
+	lookups := map[string]int{}
+	cache.SetObserver(func(model, result string) { lookups[result]++ })
+	ctx := context.Background()
+	input := map[string]interface{}{"text": "hello", "normalize": true}
+
+	_, ok := cache.Get(ctx, "text-embedding", "v1", input)
+	assert.False(t, ok)
+
+	cache.Set(ctx, "text-embedding", "v1", input, map[string]interface{}{"embedding": []float64{0.1, 0.2}})
+	result, ok := cache.Get(ctx, "text-embedding", "v1", map[string]interface{}{"normalize": true, "text": "hello"})
+	require.True(t, ok)
+	assert.Equal(t, []interface{}{0.1, 0.2}, result["embedding"])
+	require.Len(t, remote.ttls, 1)
+	for key, ttl := range remote.ttls {
+		assert.Contains(t, key, "inference:cache:text-embedding:v1:")
+		assert.Equal(t, time.Hour, ttl)
+	}
+
+	// Other versions and inputs are cached apart
+	_, ok = cache.Get(ctx, "text-embedding", "v2", input)
+	assert.False(t, ok)
+	_, ok = cache.Get(ctx, "text-embedding", "v1", map[string]interface{}{"text": "goodbye"})
+	assert.False(t, ok)
+	assert.Equal(t, map[string]int{Hit: 1, Miss: 3}, lookups)
+}
+
+func TestCache_OnlyVersionsWithATTL(t *testing.T) {
+	remote := newMemoryRemote()
+	ttls := func(ctx context.Context, model, version string) time.Duration {
+		if version == "2" {
+			return time.Minute
+		}
+		return 0
+	}
+	cache := New("inference:result", remote, TTLsFunc(ttls), zap.NewNop())
+	input := map[string]interface{}{"text": "hello"}
+
+	cache.Set(context.Background(), "llama", "1", input, map[string]interface{}{"text": "hi"})
+	huge := map[string]interface{}{"text": string(make([]byte, MaxEntryBytes))}
+	cache.Set(context.Background(), "llama", "2", input, huge)
+	assert.Empty(t, remote.values)
+	assert.False(t, cache.Enabled(context.Background(), "llama", "1"))
+	assert.True(t, cache.Enabled(context.Background(), "llama", "2"))
+
+	var nilCache *Cache
+	assert.False(t, nilCache.Enabled(context.Background(), "llama", "2"))
+	nilCache.Set(context.Background(), "llama", "2", input, map[string]interface{}{"text": "hi"})
+	_, ok := nilCache.Get(context.Background(), "llama", "2", input)
+	assert.False(t, ok)
+}
+
+func TestCache_ServesItsOwnResultsWhileRedisIsDown(t *testing.T) {
+	remote := newMemoryRemote()
+	remote.err = errors.New("connection refused")
+	cache := New("inference:cache", remote, ModelTTLs{"text-embedding": time.Hour}, zap.NewNop())
+	input := map[string]interface{}{"text": "hello"}
+
+	cache.Set(context.Background(), "text-embedding", "v1", input, map[string]interface{}{"embedding": []float64{0.1}})
+	_, ok := cache.Get(context.Background(), "text-embedding", "v1", input)
+	assert.True(t, ok)
+
+	// Other replicas cannot see it
+	other := New("inference:cache", remote, ModelTTLs{"text-embedding": time.Hour}, zap.NewNop())
+	var result string
+	other.SetObserver(func(model, r string) { result = r })
+	_, ok = other.Get(context.Background(), "text-embedding", "v1", input)
+	assert.False(t, ok)
+	assert.Equal(t, Error, result)
+}
+
+func TestParseModelTTLs(t *testing.T) {
+	ttls, err := ParseModelTTLs([]byte(`{"text-embedding": "24h", "resnet18": "90s"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, ttls["text-embedding"])
+	assert.Equal(t, 90*time.Second, ttls.TTL(context.Background(), "resnet18", "v3"))
+
+	ttls, err = ParseModelTTLs(nil)
+	require.NoError(t, err)
+	assert.Empty(t, ttls)
+
+	_, err = ParseModelTTLs([]byte(`{"text-embedding": "forever"}`))
+	assert.Error(t, err)
+	_, err = ParseModelTTLs([]byte(`{"text-embedding": "0s"}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,228 @@
+package inferencelog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+)
+
+// DefaultTopic is the Kafka topic inference records are published to
+const DefaultTopic = "inference-logs"
+
+// DefaultPrefix is the object prefix records are stored under in the data lake
+const DefaultPrefix = "inference-logs"
+
+// Redacted replaces the value of redacted fields
+const Redacted = "[REDACTED]"
+
+// Record is a sampled inference with its input and output. Payloads larger
+// than the configured limit are dropped and the record marked Truncated.
+type Record struct {
+	ID        string                 `json:"id"`
+	Timestamp time.Time              `json:"timestamp"`
+	Service   string                 `json:"service"`
+	Tenant    string                 `json:"tenant,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Model     string                 `json:"model"`
+	Version   string                 `json:"version"`
+	Input     map[string]interface{} `json:"input,omitempty"`
+	Output    map[string]interface{} `json:"output,omitempty"`
+	LatencyMs int64                  `json:"latency_ms"`
+	Error     string                 `json:"error,omitempty"`
+	Truncated bool                   `json:"truncated,omitempty"`
+
+	// ShadowOf is the version whose request was mirrored to Version, for
+	// shadow inferences whose output never reached the caller; PrimaryOutput
+	// is what that version answered, to compare against
+	ShadowOf      string                 `json:"shadow_of,omitempty"`
+	PrimaryOutput map[string]interface{} `json:"primary_output,omitempty"`
+}
+
+// Config controls which inferences are captured and what they may contain
+type Config struct {
+	Enabled bool
+	Topic   string
+	// SampleRate is the fraction of successful inferences captured; failed
+	// inferences are always captured
+	SampleRate float64
+	// Redact names input and output fields, at any depth, whose values are
+	// replaced before records leave the service
+	Redact []string
+	// MaxPayloadBytes bounds the encoded input plus output of a record
+	MaxPayloadBytes int
+}
+
+// ConfigFromEnv reads INFERENCE_LOG_* variables. Capture is off unless
+// INFERENCE_LOG_ENABLED=true.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Enabled:         os.Getenv("INFERENCE_LOG_ENABLED") == "true",
+		Topic:           DefaultTopic,
+		SampleRate:      0.01,
+		MaxPayloadBytes: 64 << 10,
+	}
+	if topic := os.Getenv("INFERENCE_LOG_TOPIC"); topic != "" {
+		cfg.Topic = topic
+	}
+	if value := os.Getenv("INFERENCE_LOG_SAMPLE_RATE"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return cfg, fmt.Errorf("invalid INFERENCE_LOG_SAMPLE_RATE %q", value)
+		}
+		cfg.SampleRate = rate
+	}
+	if value := os.Getenv("INFERENCE_LOG_REDACT"); value != "" {
+		for _, field := range strings.Split(value, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				cfg.Redact = append(cfg.Redact, field)
+			}
+		}
+	}
+	if value := os.Getenv("INFERENCE_LOG_MAX_PAYLOAD_BYTES"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return cfg, fmt.Errorf("invalid INFERENCE_LOG_MAX_PAYLOAD_BYTES %q", value)
+		}
+		cfg.MaxPayloadBytes = limit
+	}
+	return cfg, nil
+}
+
+// Capture samples, redacts and publishes inference records in the background,
+// so logging never adds latency to or fails an inference. Records are dropped
+// when the buffer is full. A nil Capture records nothing.
+type Capture struct {
+	cfg     Config
+	service string
+	redact  map[string]bool
+	queue   *publish.Queue[Record]
+	sample  func() float64
+}
+
+// NewCapture creates a capture for service that buffers up to size records,
+// published keyed by model so a model's records stay ordered. It returns nil
+// when capture is disabled.
+func NewCapture(cfg Config, service string, publisher publish.Publisher, size int, logger *zap.Logger) *Capture {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	redact := make(map[string]bool, len(cfg.Redact))
+	for _, field := range cfg.Redact {
+		redact[strings.ToLower(field)] = true
+	}
+	queue := publish.NewQueue("inference record", publisher, func(record Record) string {
+		return record.Model
+	}, size, logger)
+	queue.SetEncoder(func(record Record) ([]byte, error) {
+		return encode(record, cfg.MaxPayloadBytes)
+	})
+	return &Capture{
+		cfg:     cfg,
+		service: service,
+		redact:  redact,
+		queue:   queue,
+		sample:  mathrand.Float64,
+	}
+}
+
+// Record samples and queues a record. ID, service, tenant, request ID and
+// timestamp are filled in from the capture and the request context. Failed
+// and shadow inferences are always kept.
+func (c *Capture) Record(ctx context.Context, record Record) {
+	if c == nil {
+		return
+	}
+	if record.Error == "" && record.ShadowOf == "" && c.sample() >= c.cfg.SampleRate {
+		return
+	}
+
+	fields := logging.FieldsFromContext(ctx)
+	record.ID = publish.NewID()
+	record.Service = c.service
+	if record.Tenant == "" {
+		record.Tenant = fields.Tenant
+	}
+	if record.RequestID == "" {
+		record.RequestID = fields.RequestID
+	}
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now().UTC()
+	}
+	record.Input = c.redactMap(record.Input)
+	record.Output = c.redactMap(record.Output)
+	record.PrimaryOutput = c.redactMap(record.PrimaryOutput)
+
+	c.queue.Enqueue(record)
+}
+
+// Dropped returns how many sampled records were discarded because the buffer was full
+func (c *Capture) Dropped() int64 {
+	return c.queue.Dropped()
+}
+
+// Run publishes queued records until ctx is cancelled, then flushes what is left
+func (c *Capture) Run(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	c.queue.Run(ctx)
+}
+
+// encode encodes a record, dropping its payloads when they would take it
+// over maxPayloadBytes
+func encode(record Record, maxPayloadBytes int) ([]byte, error) {
+	value, err := json.Marshal(record)
+	if err != nil || len(value) <= maxPayloadBytes {
+		return value, err
+	}
+	record.Input, record.Output, record.PrimaryOutput, record.Truncated = nil, nil, nil, true
+	return json.Marshal(record)
+}
+
+// redactMap returns a copy of m with redacted fields replaced at any depth
+func (c *Capture) redactMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil || len(c.redact) == 0 {
+		return m
+	}
+	out := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		if c.redact[strings.ToLower(key)] {
+			out[key] = Redacted
+			continue
+		}
+		out[key] = c.redactValue(value)
+	}
+	return out
+}
+
+func (c *Capture) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return c.redactMap(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = c.redactValue(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// TenantPrefix returns the data lake prefix holding all of a tenant's records
+func TenantPrefix(prefix, tenant string) string {
+	return fmt.Sprintf("%s/tenant=%s/", prefix, url.PathEscape(tenant))
+}
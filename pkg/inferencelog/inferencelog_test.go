@@ -0,0 +1,121 @@
+package inferencelog
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+type capture struct {
+	mu      sync.Mutex
+	keys    []string
+	records []Record
+}
+
+func (c *capture) Publish(ctx context.Context, key string, value []byte) error {
+	var record Record
+	if err := json.Unmarshal(value, &record); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys = append(c.keys, key)
+	c.records = append(c.records, record)
+	return nil
+}
+
+func run(c *Capture) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.Run(ctx)
+}
+
+func TestCapture_SamplesSuccessesAndKeepsErrors(t *testing.T) {
+	published := &capture{}
+	c := NewCapture(Config{Enabled: true, SampleRate: 0.5, MaxPayloadBytes: 1 << 10}, "inference-orchestrator", published, 10, zap.NewNop())
+
+	draws := []float64{0.7, 0.2}
+	c.sample = func() float64 {
+		draw := draws[0]
+		draws = draws[1:]
+		return draw
+	}
+
+	ctx := logging.WithTenant(context.Background(), "acme")
+	c.Record(ctx, Record{Model: "resnet18", Version: "1"})                           // 0.7: not sampled
+	c.Record(ctx, Record{Model: "resnet18", Version: "1"})                           // 0.2: sampled
+	c.Record(ctx, Record{Model: "bert", Version: "2", Error: "backend unavailable"}) // always kept
+	c.Record(ctx, Record{Model: "bert", Version: "3", ShadowOf: "2"})                // always kept
+	run(c)
+
+	require.Len(t, published.records, 3)
+	assert.Equal(t, []string{"resnet18", "bert", "bert"}, published.keys)
+	for _, record := range published.records {
+		assert.NotEmpty(t, record.ID)
+		assert.Equal(t, "acme", record.Tenant)
+		assert.Equal(t, "inference-orchestrator", record.Service)
+		assert.False(t, record.Timestamp.IsZero())
+	}
+}
+
+func TestCapture_RedactsNestedFields(t *testing.T) {
+	published := &capture{}
+	c := NewCapture(Config{Enabled: true, SampleRate: 1, Redact: []string{"email", "SSN"}, MaxPayloadBytes: 1 << 10}, "inference-orchestrator", published, 10, zap.NewNop())
+
+	input := map[string]interface{}{
+		"email":    "jane@example.com",
+		"features": []interface{}{map[string]interface{}{"ssn": "123-45-6789", "age": 42.0}},
+	}
+	c.Record(context.Background(), Record{Model: "fraud", Input: input})
+	run(c)
+
+	require.Len(t, published.records, 1)
+	assert.Equal(t, map[string]interface{}{
+		"email":    Redacted,
+		"features": []interface{}{map[string]interface{}{"ssn": Redacted, "age": 42.0}},
+	}, published.records[0].Input)
+	assert.Equal(t, "jane@example.com", input["email"], "the caller's input is not modified")
+}
+
+func TestCapture_TruncatesLargePayloads(t *testing.T) {
+	published := &capture{}
+	c := NewCapture(Config{Enabled: true, SampleRate: 1, MaxPayloadBytes: 256}, "inference-orchestrator", published, 10, zap.NewNop())
+
+	c.Record(context.Background(), Record{Model: "resnet18", Input: map[string]interface{}{"data": strings.Repeat("x", 1024)}})
+	run(c)
+
+	require.Len(t, published.records, 1)
+	assert.True(t, published.records[0].Truncated)
+	assert.Nil(t, published.records[0].Input)
+}
+
+func TestNewCapture_Disabled(t *testing.T) {
+	var c *Capture = NewCapture(Config{}, "inference-orchestrator", &capture{}, 10, zap.NewNop())
+	assert.Nil(t, c)
+	c.Record(context.Background(), Record{Model: "resnet18"})
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("INFERENCE_LOG_ENABLED", "true")
+	t.Setenv("INFERENCE_LOG_SAMPLE_RATE", "0.25")
+	t.Setenv("INFERENCE_LOG_REDACT", "email, phone")
+
+	cfg, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.True(t, cfg.Enabled)
+	assert.Equal(t, 0.25, cfg.SampleRate)
+	assert.Equal(t, []string{"email", "phone"}, cfg.Redact)
+	assert.Equal(t, DefaultTopic, cfg.Topic)
+
+	t.Setenv("INFERENCE_LOG_SAMPLE_RATE", "2")
+	_, err = ConfigFromEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+// Package kservepb holds the messages and client of the KServe v2 gRPC
+// inference protocol, generated from grpc_predict_v2.proto, and converts
+// their tensors to and from the protocol's JSON form, the form inferences
+// are requested and answered in across the platform.
+package kservepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative grpc_predict_v2.proto
+
+import (
+	"crypto/tls"
+	"math"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Dial returns a connection to the server at target, "host:port", over TLS
+// when secure. It connects on the first call rather than at once.
+func Dial(target string, secure bool) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if secure {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	return grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+}
+
+// NewInferRequest builds the request running model version on input, whose
+// entries are the model's input tensors by name. Inputs are sent in name
+// order with their data in raw_input_contents, as Triton prefers.
+func NewInferRequest(model, version, id string, input map[string]interface{}) (*ModelInferRequest, error) {
+	tensors, err := inputTensors(input)
+	if err != nil {
+		return nil, err
+	}
+	req := &ModelInferRequest{ModelName: model, ModelVersion: version, Id: id}
+	for _, t := range tensors {
+		raw, err := t.raw()
+		if err != nil {
+			return nil, err
+		}
+		req.Inputs = append(req.Inputs, &ModelInferRequest_InferInputTensor{
+			Name:     t.name,
+			Datatype: t.datatype,
+			Shape:    t.shape,
+		})
+		req.RawInputContents = append(req.RawInputContents, raw)
+	}
+	return req, nil
+}
+
+// Inputs converts input, whose entries are the model's input tensors by
+// name, to the inputs of a KServe v2 JSON inference request, for servers
+// called over HTTP
+func Inputs(input map[string]interface{}) ([]map[string]interface{}, error) {
+	tensors, err := inputTensors(input)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make([]map[string]interface{}, 0, len(tensors))
+	for _, t := range tensors {
+		if _, err := t.raw(); err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, t.value())
+	}
+	return inputs, nil
+}
+
+// Response returns resp in the KServe v2 JSON form, with its outputs as
+// Outputs returns them
+func Response(resp *ModelInferResponse) (map[string]interface{}, error) {
+	outputs, err := Outputs(resp)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(outputs))
+	for i, output := range outputs {
+		values[i] = output
+	}
+	return map[string]interface{}{
+		"model_name":    resp.GetModelName(),
+		"model_version": resp.GetModelVersion(),
+		"id":            resp.GetId(),
+		"outputs":       values,
+	}, nil
+}
+
+// Outputs returns the output tensors of resp in the KServe v2 JSON form,
+// their data decoded from raw_output_contents or from their contents
+func Outputs(resp *ModelInferResponse) ([]map[string]interface{}, error) {
+	outputs := make([]map[string]interface{}, 0, len(resp.GetOutputs()))
+	for i, output := range resp.GetOutputs() {
+		t := &tensor{name: output.GetName(), datatype: output.GetDatatype(), shape: output.GetShape()}
+		if i < len(resp.GetRawOutputContents()) {
+			if err := t.decodeRaw(resp.GetRawOutputContents()[i]); err != nil {
+				return nil, err
+			}
+		} else {
+			t.data = contents(output.GetContents())
+		}
+		outputs = append(outputs, t.value())
+	}
+	return outputs, nil
+}
+
+// contents returns the elements of typed tensor contents
+func contents(c *InferTensorContents) []interface{} {
+	var data []interface{}
+	for _, v := range c.GetBoolContents() {
+		data = append(data, v)
+	}
+	for _, v := range c.GetIntContents() {
+		data = append(data, int64(v))
+	}
+	for _, v := range c.GetInt64Contents() {
+		data = append(data, v)
+	}
+	for _, v := range c.GetUintContents() {
+		data = append(data, uint64(v))
+	}
+	for _, v := range c.GetUint64Contents() {
+		data = append(data, v)
+	}
+	for _, v := range c.GetFp32Contents() {
+		data = append(data, float64(v))
+	}
+	for _, v := range c.GetFp64Contents() {
+		data = append(data, v)
+	}
+	for _, v := range c.GetBytesContents() {
+		data = append(data, string(v))
+	}
+	return data
+}
+
+// NewParameters converts parameters, each a bool, an integral or fractional
+// number or a string, to InferParameters. Numbers decoded from JSON that are
+// whole are sent as integers; values of other types are left out.
+func NewParameters(parameters map[string]interface{}) map[string]*InferParameter {
+	converted := make(map[string]*InferParameter, len(parameters))
+	for name, value := range parameters {
+		p := &InferParameter{}
+		switch v := value.(type) {
+		case bool:
+			p.ParameterChoice = &InferParameter_BoolParam{BoolParam: v}
+		case int64:
+			p.ParameterChoice = &InferParameter_Int64Param{Int64Param: v}
+		case string:
+			p.ParameterChoice = &InferParameter_StringParam{StringParam: v}
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+				p.ParameterChoice = &InferParameter_Int64Param{Int64Param: int64(v)}
+			} else {
+				p.ParameterChoice = &InferParameter_DoubleParam{DoubleParam: v}
+			}
+		default:
+			continue
+		}
+		converted[name] = p
+	}
+	return converted
+}
+
+// Value returns the parameter's value: a bool, int64, string, float64 or
+// uint64, or nil when it has none
+func (x *InferParameter) Value() interface{} {
+	switch v := x.GetParameterChoice().(type) {
+	case *InferParameter_BoolParam:
+		return v.BoolParam
+	case *InferParameter_Int64Param:
+		return v.Int64Param
+	case *InferParameter_StringParam:
+		return v.StringParam
+	case *InferParameter_DoubleParam:
+		return v.DoubleParam
+	case *InferParameter_Uint64Param:
+		return v.Uint64Param
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package kservepb
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestNewInferRequest(t *testing.T) {
+	req, err := NewInferRequest("resnet18", "1", "req-1", map[string]interface{}{
+		"image": []interface{}{[]interface{}{0.0, 0.0}, []interface{}{0.0, 0.5}},
+		"label": map[string]interface{}{"datatype": "INT64", "shape": []interface{}{1.0}, "data": []interface{}{7.0}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "resnet18", req.ModelName)
+	assert.Equal(t, "1", req.ModelVersion)
+	assert.Equal(t, "req-1", req.Id)
+
+	// Inputs are sent in name order with their data in raw
+	require.Len(t, req.Inputs, 2)
+	assert.Equal(t, "image", req.Inputs[0].Name)
+	assert.Equal(t, "FP32", req.Inputs[0].Datatype)
+	assert.Equal(t, []int64{2, 2}, req.Inputs[0].Shape)
+	assert.Equal(t, float32(0.5), math.Float32frombits(binary.LittleEndian.Uint32(req.RawInputContents[0][12:])))
+	assert.Equal(t, "label", req.Inputs[1].Name)
+	assert.Equal(t, "INT64", req.Inputs[1].Datatype)
+	assert.Equal(t, []byte{7, 0, 0, 0, 0, 0, 0, 0}, req.RawInputContents[1])
+}
+
+func TestNewInferRequest_RejectsInvalidInput(t *testing.T) {
+	for name, input := range map[string]interface{}{
+		"ragged":      []interface{}{[]interface{}{1.0, 2.0}, []interface{}{3.0}},
+		"mixed":       []interface{}{1.0, "two"},
+		"no datatype": map[string]interface{}{"data": []interface{}{1.0}},
+		"bad shape":   map[string]interface{}{"datatype": "FP32", "shape": []interface{}{3.0}, "data": []interface{}{1.0}},
+		"not integer": map[string]interface{}{"datatype": "INT32", "data": []interface{}{1.5}},
+	} {
+		_, err := NewInferRequest("resnet18", "1", "", map[string]interface{}{"x": input})
+		assert.True(t, apperrors.Is(err, apperrors.InvalidArgument), name)
+	}
+}
+
+func TestInputs(t *testing.T) {
+	inputs, err := Inputs(map[string]interface{}{
+		"tokens": []interface{}{"hello", "world"},
+		"image":  []interface{}{[]interface{}{0.0, 0.5}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"name": "image", "datatype": "FP32", "shape": []int64{1, 2}, "data": []interface{}{0.0, 0.5}},
+		{"name": "tokens", "datatype": "BYTES", "shape": []int64{2}, "data": []interface{}{"hello", "world"}},
+	}, inputs)
+
+	_, err = Inputs(map[string]interface{}{"x": map[string]interface{}{"datatype": "FP32", "shape": []interface{}{2.0}, "data": []interface{}{1.0}}})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+}
+
+func TestResponse(t *testing.T) {
+	probs := binary.LittleEndian.AppendUint32(nil, math.Float32bits(0.25))
+	probs = binary.LittleEndian.AppendUint32(probs, math.Float32bits(0.75))
+	classes := binary.LittleEndian.AppendUint32(nil, 3)
+	classes = append(classes, "cat"...)
+	result, err := Response(&ModelInferResponse{
+		ModelName:    "resnet18",
+		ModelVersion: "1",
+		Id:           "req-1",
+		Outputs: []*ModelInferResponse_InferOutputTensor{
+			{Name: "probs", Datatype: "FP32", Shape: []int64{1, 2}},
+			{Name: "class", Datatype: "BYTES", Shape: []int64{1}},
+		},
+		RawOutputContents: [][]byte{probs, classes},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"model_name":    "resnet18",
+		"model_version": "1",
+		"id":            "req-1",
+		"outputs": []interface{}{
+			map[string]interface{}{"name": "probs", "datatype": "FP32", "shape": []int64{1, 2}, "data": []interface{}{0.25, 0.75}},
+			map[string]interface{}{"name": "class", "datatype": "BYTES", "shape": []int64{1}, "data": []interface{}{"cat"}},
+		},
+	}, result)
+}
+
+func TestOutputs_ContentsAndRaw(t *testing.T) {
+	outputs, err := Outputs(&ModelInferResponse{
+		Outputs: []*ModelInferResponse_InferOutputTensor{
+			{Name: "y", Datatype: "FP32", Shape: []int64{2}, Contents: &InferTensorContents{Fp32Contents: []float32{1.5, -2}}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1.5, -2.0}, outputs[0]["data"])
+
+	outputs, err = Outputs(&ModelInferResponse{
+		Outputs:           []*ModelInferResponse_InferOutputTensor{{Name: "z", Datatype: "INT16", Shape: []int64{2}}},
+		RawOutputContents: [][]byte{{0xff, 0xff, 2, 0}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(-1), int64(2)}, outputs[0]["data"])
+
+	_, err = Outputs(&ModelInferResponse{
+		Outputs:           []*ModelInferResponse_InferOutputTensor{{Name: "z", Datatype: "INT16", Shape: []int64{2}}},
+		RawOutputContents: [][]byte{{1, 2, 3}},
+	})
+	assert.Error(t, err)
+}
+
+func TestNewParameters(t *testing.T) {
+	parameters := NewParameters(map[string]interface{}{
+		"stream":      true,
+		"max_tokens":  8.0,
+		"temperature": 0.5,
+		"stop":        "\n",
+		"timeout":     int64(1000),
+		"ignored":     []interface{}{1.0},
+	})
+	values := make(map[string]interface{}, len(parameters))
+	for name, p := range parameters {
+		values[name] = p.Value()
+	}
+	assert.Equal(t, map[string]interface{}{
+		"stream":      true,
+		"max_tokens":  int64(8),
+		"temperature": 0.5,
+		"stop":        "\n",
+		"timeout":     int64(1000),
+	}, values)
+}
@@ -0,0 +1,317 @@
+package kservepb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// tensor is a named input or output; data holds its elements in row-major
+// order as float64, int64, uint64, bool or string
+type tensor struct {
+	name     string
+	datatype string
+	shape    []int64
+	data     []interface{}
+}
+
+// elementSizes holds the raw size of each fixed-size datatype
+var elementSizes = map[string]int{
+	"BOOL": 1, "INT8": 1, "UINT8": 1,
+	"INT16": 2, "UINT16": 2,
+	"INT32": 4, "UINT32": 4, "FP32": 4,
+	"INT64": 8, "UINT64": 8, "FP64": 8,
+}
+
+// inputTensors converts input's entries to tensors, in name order
+func inputTensors(input map[string]interface{}) ([]*tensor, error) {
+	names := make([]string, 0, len(input))
+	for name := range input {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tensors := make([]*tensor, 0, len(names))
+	for _, name := range names {
+		t, err := fromValue(name, input[name])
+		if err != nil {
+			return nil, err
+		}
+		tensors = append(tensors, t)
+	}
+	return tensors, nil
+}
+
+// fromValue converts a JSON input to a tensor. The value is either a tensor
+// in the KServe v2 JSON form, with "datatype", "shape" and "data", or an
+// array of numbers (sent as FP32), strings (BYTES) or booleans (BOOL) whose
+// shape is taken from its nesting.
+func fromValue(name string, v interface{}) (*tensor, error) {
+	t := &tensor{name: name}
+	v, err := Plain(v)
+	if err != nil {
+		return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q: %v", name, err)
+	}
+
+	if object, ok := v.(map[string]interface{}); ok {
+		datatype, _ := object["datatype"].(string)
+		if datatype == "" {
+			return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q has no datatype", name)
+		}
+		data, shape, err := Flatten(object["data"])
+		if err != nil {
+			return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q: %v", name, err)
+		}
+		t.datatype, t.data, t.shape = datatype, data, shape
+		if declared, ok := object["shape"].([]interface{}); ok {
+			t.shape = make([]int64, len(declared))
+			for i, dim := range declared {
+				d, ok := dim.(float64)
+				if !ok || d < 0 || d != math.Trunc(d) {
+					return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q has an invalid shape", name)
+				}
+				t.shape[i] = int64(d)
+			}
+		}
+		return t, nil
+	}
+
+	data, shape, err := Flatten(v)
+	if err != nil {
+		return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q: %v", name, err)
+	}
+	t.data, t.shape = data, shape
+	if len(data) == 0 {
+		return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q is empty", name)
+	}
+	t.datatype = DatatypeOf(data)
+	return t, nil
+}
+
+// DatatypeOf returns the datatype bare arrays of data are sent as
+func DatatypeOf(data []interface{}) string {
+	if len(data) == 0 {
+		return ""
+	}
+	switch data[0].(type) {
+	case float64:
+		return "FP32"
+	case string:
+		return "BYTES"
+	case bool:
+		return "BOOL"
+	}
+	return ""
+}
+
+// Plain returns v as decoded from JSON. Inputs of other types, such as the
+// tensors images and audio clips are decoded into, are converted through
+// their JSON encoding.
+func Plain(v interface{}) (interface{}, error) {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}, float64, string, bool, nil:
+		return v, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// Flatten returns the elements of a rectangular array in row-major order
+// and its shape; a scalar is an array of one
+func Flatten(v interface{}) ([]interface{}, []int64, error) {
+	if _, ok := v.([]interface{}); !ok {
+		return []interface{}{v}, []int64{1}, nil
+	}
+
+	var data []interface{}
+	var shape []int64
+	var walk func(v interface{}, depth int) error
+	walk = func(v interface{}, depth int) error {
+		items, ok := v.([]interface{})
+		if !ok {
+			if depth != len(shape) {
+				return fmt.Errorf("array is not rectangular")
+			}
+			switch v.(type) {
+			case float64, string, bool:
+			default:
+				return fmt.Errorf("unsupported element %v", v)
+			}
+			if len(data) > 0 && fmt.Sprintf("%T", data[0]) != fmt.Sprintf("%T", v) {
+				return fmt.Errorf("array mixes element types")
+			}
+			data = append(data, v)
+			return nil
+		}
+		if depth == len(shape) && len(data) == 0 {
+			shape = append(shape, int64(len(items)))
+		} else if depth >= len(shape) || shape[depth] != int64(len(items)) {
+			return fmt.Errorf("array is not rectangular")
+		}
+		for _, item := range items {
+			if err := walk(item, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(v, 0); err != nil {
+		return nil, nil, err
+	}
+	return data, shape, nil
+}
+
+// elements returns how many elements the tensor's shape holds
+func (t *tensor) elements() int64 {
+	n := int64(1)
+	for _, dim := range t.shape {
+		n *= dim
+	}
+	return n
+}
+
+// raw encodes the tensor's data in the little-endian layout of
+// raw_input_contents; BYTES elements are each prefixed with their length
+func (t *tensor) raw() ([]byte, error) {
+	if int64(len(t.data)) != t.elements() {
+		return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q has %d elements, not the %d of shape %v", t.name, len(t.data), t.elements(), t.shape)
+	}
+
+	var b []byte
+	for _, v := range t.data {
+		var err error
+		if b, err = appendElement(b, t.datatype, v); err != nil {
+			return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q: %v", t.name, err)
+		}
+	}
+	return b, nil
+}
+
+func appendElement(b []byte, datatype string, v interface{}) ([]byte, error) {
+	switch datatype {
+	case "BYTES":
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%v is not a string", v)
+		}
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+		return append(b, s...), nil
+	case "BOOL":
+		flag, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%v is not a boolean", v)
+		}
+		if flag {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case "FP32", "FP64":
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%v is not a number", v)
+		}
+		if datatype == "FP32" {
+			return binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(f))), nil
+		}
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(f)), nil
+	}
+
+	size, ok := elementSizes[datatype]
+	if !ok {
+		return nil, fmt.Errorf("unsupported datatype %s", datatype)
+	}
+	f, ok := v.(float64)
+	if !ok || f != math.Trunc(f) {
+		return nil, fmt.Errorf("%v is not an integer", v)
+	}
+	bits := uint64(int64(f))
+	for i := 0; i < size; i++ {
+		b = append(b, byte(bits>>(8*i)))
+	}
+	return b, nil
+}
+
+// decodeRaw sets the tensor's data from raw_output_contents
+func (t *tensor) decodeRaw(b []byte) error {
+	t.data = nil
+	if t.datatype == "BYTES" {
+		for len(b) > 0 {
+			if len(b) < 4 {
+				return fmt.Errorf("output %q is truncated", t.name)
+			}
+			n := binary.LittleEndian.Uint32(b)
+			if uint64(len(b)-4) < uint64(n) {
+				return fmt.Errorf("output %q is truncated", t.name)
+			}
+			t.data = append(t.data, string(b[4:4+n]))
+			b = b[4+n:]
+		}
+		return nil
+	}
+
+	size, ok := elementSizes[t.datatype]
+	if !ok {
+		return fmt.Errorf("output %q has unsupported datatype %s", t.name, t.datatype)
+	}
+	if len(b)%size != 0 {
+		return fmt.Errorf("output %q is truncated", t.name)
+	}
+	for ; len(b) > 0; b = b[size:] {
+		var bits uint64
+		for i := size - 1; i >= 0; i-- {
+			bits = bits<<8 | uint64(b[i])
+		}
+		t.data = append(t.data, element(t.datatype, bits))
+	}
+	return nil
+}
+
+// element converts the little-endian bits of a fixed-size element
+func element(datatype string, bits uint64) interface{} {
+	switch datatype {
+	case "BOOL":
+		return bits != 0
+	case "INT8":
+		return int64(int8(bits))
+	case "INT16":
+		return int64(int16(bits))
+	case "INT32":
+		return int64(int32(bits))
+	case "INT64":
+		return int64(bits)
+	case "FP32":
+		return float64(math.Float32frombits(uint32(bits)))
+	case "FP64":
+		return math.Float64frombits(bits)
+	}
+	return bits
+}
+
+// value returns the tensor in the KServe v2 JSON form
+func (t *tensor) value() map[string]interface{} {
+	data := t.data
+	if data == nil {
+		data = []interface{}{}
+	}
+	shape := t.shape
+	if shape == nil {
+		shape = []int64{}
+	}
+	return map[string]interface{}{
+		"name":     t.name,
+		"datatype": t.datatype,
+		"shape":    shape,
+		"data":     data,
+	}
+}
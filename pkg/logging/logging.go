@@ -0,0 +1,225 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Headers that carry correlation fields between services, over HTTP and as Kafka record headers
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTenant      = "X-Tenant-ID"
+	HeaderJobID       = "X-Job-ID"
+	HeaderPriority    = "X-Priority"
+	HeaderTraceParent = "traceparent"
+)
+
+// HeaderTimeout carries how long, in milliseconds, the caller will wait for an
+// HTTP response, so services give up on work nobody is waiting for
+const HeaderTimeout = "X-Request-Timeout-Ms"
+
+// Fields identify the request, trace, tenant and batch job a log line belongs
+// to, and the priority class the request is served in
+type Fields struct {
+	TraceID   string
+	SpanID    string
+	RequestID string
+	Tenant    string
+	JobID     string
+	Priority  string
+}
+
+type fieldsKey struct{}
+
+// FieldsFromContext returns the correlation fields stored in ctx
+func FieldsFromContext(ctx context.Context) Fields {
+	if f, ok := ctx.Value(fieldsKey{}).(Fields); ok {
+		return f
+	}
+	return Fields{}
+}
+
+// NewContext returns ctx with f merged over any fields already present.
+// Empty values in f leave existing values untouched.
+func NewContext(ctx context.Context, f Fields) context.Context {
+	merged := FieldsFromContext(ctx)
+	if f.TraceID != "" {
+		merged.TraceID = f.TraceID
+		merged.SpanID = f.SpanID
+	}
+	if f.RequestID != "" {
+		merged.RequestID = f.RequestID
+	}
+	if f.Tenant != "" {
+		merged.Tenant = f.Tenant
+	}
+	if f.JobID != "" {
+		merged.JobID = f.JobID
+	}
+	if f.Priority != "" {
+		merged.Priority = f.Priority
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// WithRequestID returns ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return NewContext(ctx, Fields{RequestID: requestID})
+}
+
+// WithTenant returns ctx carrying the tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return NewContext(ctx, Fields{Tenant: tenant})
+}
+
+// WithJobID returns ctx carrying the batch job ID
+func WithJobID(ctx context.Context, jobID string) context.Context {
+	return NewContext(ctx, Fields{JobID: jobID})
+}
+
+// WithPriority returns ctx carrying the request's priority class
+func WithPriority(ctx context.Context, priority string) context.Context {
+	return NewContext(ctx, Fields{Priority: priority})
+}
+
+// WithTrace returns ctx carrying the current trace and span IDs (hex encoded)
+func WithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	return NewContext(ctx, Fields{TraceID: traceID, SpanID: spanID})
+}
+
+// RequestID returns the request ID stored in ctx
+func RequestID(ctx context.Context) string {
+	return FieldsFromContext(ctx).RequestID
+}
+
+// ZapFields returns the non-empty correlation fields as zap fields
+func (f Fields) ZapFields() []zap.Field {
+	fields := make([]zap.Field, 0, 4)
+	if f.TraceID != "" {
+		fields = append(fields, zap.String("trace_id", f.TraceID))
+	}
+	if f.RequestID != "" {
+		fields = append(fields, zap.String("request_id", f.RequestID))
+	}
+	if f.Tenant != "" {
+		fields = append(fields, zap.String("tenant", f.Tenant))
+	}
+	if f.JobID != "" {
+		fields = append(fields, zap.String("job_id", f.JobID))
+	}
+	if f.Priority != "" {
+		fields = append(fields, zap.String("priority", f.Priority))
+	}
+	return fields
+}
+
+// With returns logger annotated with the correlation fields in ctx
+func With(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	fields := FieldsFromContext(ctx).ZapFields()
+	if len(fields) == 0 {
+		return logger
+	}
+	return logger.With(fields...)
+}
+
+// NewRequestID returns a random request ID
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// traceParentPattern matches a W3C traceparent header: version-traceid-spanid-flags
+var traceParentPattern = regexp.MustCompile(`^[0-9a-f]{2}-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+
+// Headers returns the correlation fields in ctx as header key/value pairs
+func Headers(ctx context.Context) map[string]string {
+	f := FieldsFromContext(ctx)
+	headers := make(map[string]string, 4)
+	if f.RequestID != "" {
+		headers[HeaderRequestID] = f.RequestID
+	}
+	if f.Tenant != "" {
+		headers[HeaderTenant] = f.Tenant
+	}
+	if f.JobID != "" {
+		headers[HeaderJobID] = f.JobID
+	}
+	if f.Priority != "" {
+		headers[HeaderPriority] = f.Priority
+	}
+	if f.TraceID != "" && f.SpanID != "" {
+		headers[HeaderTraceParent] = "00-" + f.TraceID + "-" + f.SpanID + "-01"
+	}
+	return headers
+}
+
+// FromHeaders returns ctx with the correlation fields found through get,
+// which looks up a header by name (http.Header.Get or a Kafka header lookup)
+func FromHeaders(ctx context.Context, get func(key string) string) context.Context {
+	f := Fields{
+		RequestID: get(HeaderRequestID),
+		Tenant:    get(HeaderTenant),
+		JobID:     get(HeaderJobID),
+		Priority:  get(HeaderPriority),
+	}
+	if m := traceParentPattern.FindStringSubmatch(get(HeaderTraceParent)); m != nil {
+		f.TraceID, f.SpanID = m[1], m[2]
+	}
+	return NewContext(ctx, f)
+}
+
+// Inject copies the correlation fields and the deadline in ctx onto an
+// outbound request
+func Inject(ctx context.Context, req *http.Request) {
+	for key, value := range Headers(ctx) {
+		req.Header.Set(key, value)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(deadline).Milliseconds()
+		if remaining < 1 {
+			remaining = 1
+		}
+		req.Header.Set(HeaderTimeout, strconv.FormatInt(remaining, 10))
+	}
+}
+
+// ClientFor returns the client to send a request under ctx with. Requests
+// with a deadline are bounded by it alone rather than by client's fixed
+// timeout, so a caller's remaining budget is neither cut short nor
+// outlived at each hop.
+func ClientFor(ctx context.Context, client *http.Client) *http.Client {
+	if _, ok := ctx.Deadline(); !ok || client.Timeout == 0 {
+		return client
+	}
+	bounded := *client
+	bounded.Timeout = 0
+	return &bounded
+}
+
+// Middleware extracts correlation fields from inbound requests, assigns a
+// request ID when the caller did not send one and echoes it in the response.
+// Requests whose caller sent a timeout are cancelled once it passes.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := FromHeaders(r.Context(), r.Header.Get)
+		if RequestID(ctx) == "" {
+			ctx = WithRequestID(ctx, NewRequestID())
+		}
+		if ms, err := strconv.ParseInt(r.Header.Get(HeaderTimeout), 10, 64); err == nil && ms > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(ms)*time.Millisecond)
+			defer cancel()
+		}
+
+		w.Header().Set(HeaderRequestID, RequestID(ctx))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
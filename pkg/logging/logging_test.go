@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWith_AddsCorrelationFields(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithTenant(ctx, "acme")
+	ctx = WithJobID(ctx, "job-9")
+	ctx = WithTrace(ctx, "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+
+	With(ctx, logger).Info("processing")
+
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "req-1", fields["request_id"])
+	assert.Equal(t, "acme", fields["tenant"])
+	assert.Equal(t, "job-9", fields["job_id"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", fields["trace_id"])
+}
+
+func TestWith_NoFields(t *testing.T) {
+	logger := zap.NewNop()
+	assert.Same(t, logger, With(context.Background(), logger))
+}
+
+func TestNewContext_KeepsExistingValues(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithJobID(ctx, "job-9")
+
+	f := FieldsFromContext(ctx)
+	assert.Equal(t, "req-1", f.RequestID)
+	assert.Equal(t, "job-9", f.JobID)
+}
+
+func TestHeadersRoundTrip(t *testing.T) {
+	ctx := NewContext(context.Background(), Fields{
+		TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:    "00f067aa0ba902b7",
+		RequestID: "req-1",
+		Tenant:    "acme",
+		Priority:  "high",
+	})
+
+	headers := Headers(ctx)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", headers[HeaderTraceParent])
+
+	got := FieldsFromContext(FromHeaders(context.Background(), func(key string) string { return headers[key] }))
+	assert.Equal(t, FieldsFromContext(ctx), got)
+}
+
+func TestFromHeaders_IgnoresMalformedTraceParent(t *testing.T) {
+	ctx := FromHeaders(context.Background(), func(key string) string {
+		if key == HeaderTraceParent {
+			return "not-a-traceparent"
+		}
+		return ""
+	})
+
+	assert.Empty(t, FieldsFromContext(ctx).TraceID)
+}
+
+func TestMiddleware(t *testing.T) {
+	var seen Fields
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = FieldsFromContext(r.Context())
+	}))
+
+	// A request ID is assigned when the caller sends none
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/v1/route", nil))
+	assert.NotEmpty(t, seen.RequestID)
+	assert.Equal(t, seen.RequestID, w.Header().Get(HeaderRequestID))
+
+	// Inbound fields are preserved
+	req := httptest.NewRequest("GET", "/v1/route", nil)
+	req.Header.Set(HeaderRequestID, "req-1")
+	req.Header.Set(HeaderTenant, "acme")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "req-1", seen.RequestID)
+	assert.Equal(t, "acme", seen.Tenant)
+}
+
+func TestInject(t *testing.T) {
+	ctx := WithJobID(WithRequestID(context.Background(), "req-1"), "job-9")
+	req := httptest.NewRequest("POST", "http://orchestrator/v1/infer", nil)
+
+	Inject(ctx, req)
+
+	assert.Equal(t, "req-1", req.Header.Get(HeaderRequestID))
+	assert.Equal(t, "job-9", req.Header.Get(HeaderJobID))
+	assert.Empty(t, req.Header.Get(HeaderTraceParent))
+}
+
+func TestDeadlinePropagation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req := httptest.NewRequest("POST", "http://router/v1/route", nil)
+	Inject(ctx, req)
+
+	remaining, err := strconv.ParseInt(req.Header.Get(HeaderTimeout), 10, 64)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2000, remaining, 100)
+
+	// The receiving service stops waiting when the caller would
+	var deadline time.Time
+	var hasDeadline bool
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, hasDeadline = r.Context().Deadline()
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(2*time.Second), deadline, 200*time.Millisecond)
+
+	// Requests without a deadline carry none
+	req = httptest.NewRequest("POST", "http://router/v1/route", nil)
+	Inject(context.Background(), req)
+	assert.Empty(t, req.Header.Get(HeaderTimeout))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.False(t, hasDeadline)
+}
+
+func TestClientFor(t *testing.T) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	assert.Same(t, client, ClientFor(context.Background(), client))
+
+	// A deadline replaces the fixed timeout, whether it is shorter or longer
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	bounded := ClientFor(ctx, client)
+	assert.Zero(t, bounded.Timeout)
+	assert.Equal(t, 30*time.Second, client.Timeout)
+}
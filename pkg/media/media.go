@@ -0,0 +1,77 @@
+// Package media carries binary inputs, such as images and audio clips,
+// through the JSON inference path. The gateway wraps each binary input in a
+// Blob, which travels base64 encoded through the model router, and the
+// inference orchestrator decodes it into a tensor before calling Triton, so
+// clients need not send pixels or samples as JSON numbers.
+package media
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// Media types of the binary inputs the orchestrator can decode
+const (
+	JPEG = "image/jpeg"
+	PNG  = "image/png"
+	WAV  = "audio/wav"
+)
+
+// blobKey marks an input value as an encoded Blob
+const blobKey = "$blob"
+
+// Blob is a binary input and its media type
+type Blob struct {
+	ContentType string
+	Data        []byte
+}
+
+// Normalize returns the media type of a Content-Type header if it is one of
+// the supported types, accepting the common aliases of WAV
+func Normalize(contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+	switch mediaType = strings.ToLower(mediaType); mediaType {
+	case JPEG, PNG:
+		return mediaType, true
+	case WAV, "audio/x-wav", "audio/wave", "audio/vnd.wave":
+		return WAV, true
+	}
+	return "", false
+}
+
+// Value encodes the blob as an input value
+func (b Blob) Value() map[string]interface{} {
+	return map[string]interface{}{
+		blobKey:        base64.StdEncoding.EncodeToString(b.Data),
+		"content_type": b.ContentType,
+	}
+}
+
+// FromValue decodes an input value encoded with Blob.Value, reporting false
+// for values that are not blobs
+func FromValue(v interface{}) (Blob, bool, error) {
+	fields, ok := v.(map[string]interface{})
+	if !ok {
+		return Blob{}, false, nil
+	}
+	encoded, ok := fields[blobKey].(string)
+	if !ok {
+		return Blob{}, false, nil
+	}
+
+	contentType, _ := fields["content_type"].(string)
+	mediaType, ok := Normalize(contentType)
+	if !ok {
+		return Blob{}, true, fmt.Errorf("unsupported media type %q", contentType)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return Blob{}, true, fmt.Errorf("invalid blob encoding: %w", err)
+	}
+	return Blob{ContentType: mediaType, Data: data}, true, nil
+}
@@ -0,0 +1,57 @@
+package media
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+		ok          bool
+	}{
+		{"image/jpeg", JPEG, true},
+		{"image/PNG", PNG, true},
+		{"audio/x-wav", WAV, true},
+		{"audio/wave; codecs=1", WAV, true},
+		{"image/gif", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Normalize(tt.contentType)
+		assert.Equal(t, tt.ok, ok, tt.contentType)
+		assert.Equal(t, tt.want, got, tt.contentType)
+	}
+}
+
+func TestBlob_RoundTripsThroughJSON(t *testing.T) {
+	blob := Blob{ContentType: PNG, Data: []byte{0x89, 'P', 'N', 'G', 0}}
+
+	// The value crosses the gateway, router and orchestrator as JSON
+	data, err := json.Marshal(map[string]interface{}{"image": blob.Value()})
+	require.NoError(t, err)
+	var input map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &input))
+
+	decoded, ok, err := FromValue(input["image"])
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, blob, decoded)
+}
+
+func TestFromValue_NotBlobs(t *testing.T) {
+	for _, v := range []interface{}{[]interface{}{1.0}, "text", map[string]interface{}{"content_type": "image/png"}} {
+		_, ok, err := FromValue(v)
+		assert.False(t, ok)
+		assert.NoError(t, err)
+	}
+
+	_, ok, err := FromValue(map[string]interface{}{"$blob": "AAAA", "content_type": "image/gif"})
+	assert.True(t, ok)
+	assert.Error(t, err)
+}
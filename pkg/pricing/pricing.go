@@ -0,0 +1,69 @@
+// Package pricing estimates what inferences cost from per-model rates, so
+// callers see the cost of each request and the metering service can total
+// it per tenant. Estimates are indicative; invoices are computed from the
+// metered quantities.
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Wildcard names the rate of models without one of their own
+const Wildcard = "*"
+
+// Rate is what a model's inferences cost
+type Rate struct {
+	// PerRequest is charged for every inference
+	PerRequest float64 `json:"per_request"`
+	// PerSecond is charged for each second an inference takes
+	PerSecond float64 `json:"per_second"`
+	// PerMB is charged for each megabyte of input
+	PerMB float64 `json:"per_mb"`
+}
+
+// Table holds the rates of models. A nil Table prices everything at zero.
+type Table struct {
+	rates map[string]Rate
+}
+
+// Parse parses a JSON object mapping model names, or Wildcard, to rates
+func Parse(value string) (*Table, error) {
+	rates := make(map[string]Rate)
+	if value == "" {
+		return &Table{rates: rates}, nil
+	}
+	if err := json.Unmarshal([]byte(value), &rates); err != nil {
+		return nil, fmt.Errorf("invalid model pricing: %w", err)
+	}
+	for model, rate := range rates {
+		if rate.PerRequest < 0 || rate.PerSecond < 0 || rate.PerMB < 0 {
+			return nil, fmt.Errorf("invalid model pricing: %s has a negative rate", model)
+		}
+	}
+	return &Table{rates: rates}, nil
+}
+
+// Estimate returns the cost of an inference of model that took latencyMs and
+// was sent inputBytes, rounded to a millionth. Models without a rate, and
+// without a Wildcard rate to fall back to, cost nothing.
+func (t *Table) Estimate(model string, latencyMs, inputBytes int64) float64 {
+	if t == nil {
+		return 0
+	}
+	rate, ok := t.rates[model]
+	if !ok {
+		rate = t.rates[Wildcard]
+	}
+	cost := rate.PerRequest +
+		rate.PerSecond*float64(latencyMs)/1000 +
+		rate.PerMB*float64(inputBytes)/(1<<20)
+	return Round(cost)
+}
+
+// Round rounds a cost to a millionth, so sums of estimates do not carry
+// floating point noise
+func Round(cost float64) float64 {
+	return math.Round(cost*1e6) / 1e6
+}
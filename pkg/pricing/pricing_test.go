@@ -0,0 +1,46 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable_Estimate(t *testing.T) {
+	table, err := Parse(`{
+		"resnet18": {"per_request": 0.001, "per_second": 0.01, "per_mb": 0.002},
+		"*": {"per_request": 0.0005}
+	}`)
+	require.NoError(t, err)
+
+	// 0.001 + 0.01*0.25 + 0.002*0.5
+	assert.Equal(t, 0.0045, table.Estimate("resnet18", 250, 1<<19))
+	assert.Equal(t, 0.0005, table.Estimate("bert", 900, 1<<20), "wildcard rate")
+
+	var nilTable *Table
+	assert.Zero(t, nilTable.Estimate("resnet18", 250, 1<<19))
+}
+
+func TestTable_EstimateWithoutWildcard(t *testing.T) {
+	table, err := Parse(`{"resnet18": {"per_request": 0.001}}`)
+	require.NoError(t, err)
+	assert.Zero(t, table.Estimate("bert", 100, 100))
+
+	table, err = Parse("")
+	require.NoError(t, err)
+	assert.Zero(t, table.Estimate("resnet18", 100, 100))
+}
+
+func TestParse_RejectsInvalidRates(t *testing.T) {
+	_, err := Parse(`{"resnet18": {"per_second": -1}}`)
+	assert.Error(t, err)
+
+	_, err = Parse(`{"resnet18": 0.1}`)
+	assert.Error(t, err)
+}
+
+func TestRound(t *testing.T) {
+	assert.Equal(t, 0.3, Round(0.1+0.2))
+	assert.Equal(t, 0.000001, Round(0.0000014))
+}
@@ -0,0 +1,107 @@
+package privacy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Stores named in deletion reports
+const (
+	StorePostgres = "postgres"
+	StoreKafka    = "kafka"
+	StoreMinIO    = "minio"
+	StoreDataLake = "datalake"
+)
+
+// Request asks for a tenant's inference data, or only one data subject's
+// within the tenant, to be deleted
+type Request struct {
+	Tenant  string `json:"tenant"`
+	Subject string `json:"subject_id,omitempty"`
+}
+
+// Validate checks that the request names a tenant
+func (r Request) Validate() error {
+	if r.Tenant == "" {
+		return apperrors.New(apperrors.InvalidArgument, "tenant is required")
+	}
+	return nil
+}
+
+// StoreResult is the outcome of a deletion in one store
+type StoreResult struct {
+	Store   string `json:"store"`
+	Deleted int64  `json:"deleted"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Report records what a deletion removed. It is Complete only when every
+// store succeeded; an incomplete deletion is safe to request again.
+type Report struct {
+	ID          string        `json:"id"`
+	Tenant      string        `json:"tenant"`
+	Subject     string        `json:"subject_id,omitempty"`
+	RequestedAt time.Time     `json:"requested_at"`
+	CompletedAt *time.Time    `json:"completed_at,omitempty"`
+	Stores      []StoreResult `json:"stores"`
+	Complete    bool          `json:"complete"`
+}
+
+// NewReport starts the report for a request
+func NewReport(req Request) *Report {
+	return &Report{
+		ID:          newReportID(),
+		Tenant:      req.Tenant,
+		Subject:     req.Subject,
+		RequestedAt: time.Now().UTC(),
+		Stores:      []StoreResult{},
+	}
+}
+
+// Add records the outcome for a store
+func (r *Report) Add(store string, deleted int64, err error) {
+	result := StoreResult{Store: store, Deleted: deleted}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	r.Stores = append(r.Stores, result)
+}
+
+// Finish stamps the report and reports whether every store succeeded
+func (r *Report) Finish() bool {
+	now := time.Now().UTC()
+	r.CompletedAt = &now
+	r.Complete = true
+	for _, result := range r.Stores {
+		if result.Error != "" {
+			r.Complete = false
+		}
+	}
+	return r.Complete
+}
+
+// RetentionFromEnv returns how long inference inputs and outputs are kept,
+// from DATA_RETENTION_DAYS. Zero means data is kept until deleted by request.
+func RetentionFromEnv() (time.Duration, error) {
+	value := os.Getenv("DATA_RETENTION_DAYS")
+	if value == "" {
+		return 0, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("invalid DATA_RETENTION_DAYS %q", value)
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+func newReportID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
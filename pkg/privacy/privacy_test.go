@@ -0,0 +1,53 @@
+package privacy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestRequest_Validate(t *testing.T) {
+	assert.NoError(t, Request{Tenant: "acme"}.Validate())
+	assert.NoError(t, Request{Tenant: "acme", Subject: "user-42"}.Validate())
+
+	err := Request{Subject: "user-42"}.Validate()
+	require.Error(t, err)
+	assert.Equal(t, apperrors.InvalidArgument, apperrors.CodeOf(err))
+}
+
+func TestReport_Finish(t *testing.T) {
+	report := NewReport(Request{Tenant: "acme", Subject: "user-42"})
+	assert.NotEmpty(t, report.ID)
+	assert.Equal(t, "user-42", report.Subject)
+
+	report.Add(StoreMinIO, 3, nil)
+	report.Add(StorePostgres, 3, nil)
+	assert.True(t, report.Finish())
+	assert.NotNil(t, report.CompletedAt)
+
+	report = NewReport(Request{Tenant: "acme"})
+	report.Add(StoreKafka, 0, errors.New("broker unavailable"))
+	assert.False(t, report.Finish())
+	assert.Equal(t, "broker unavailable", report.Stores[0].Error)
+}
+
+func TestRetentionFromEnv(t *testing.T) {
+	t.Setenv("DATA_RETENTION_DAYS", "")
+	retention, err := RetentionFromEnv()
+	require.NoError(t, err)
+	assert.Zero(t, retention)
+
+	t.Setenv("DATA_RETENTION_DAYS", "30")
+	retention, err = RetentionFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, retention)
+
+	t.Setenv("DATA_RETENTION_DAYS", "a month")
+	_, err = RetentionFromEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+// Package publish delivers the records services report about their work,
+// such as usage events, platform events and inference logs, in the
+// background, so reporting never adds latency to or fails the work reported.
+package publish
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// flushTimeout bounds publishing what is left in a queue once it stops
+const flushTimeout = 5 * time.Second
+
+// Publisher delivers an encoded record. Records with the same key stay
+// ordered.
+type Publisher interface {
+	Publish(ctx context.Context, key string, value []byte) error
+}
+
+// PublisherFunc adapts a function to a Publisher
+type PublisherFunc func(ctx context.Context, key string, value []byte) error
+
+// Publish calls f
+func (f PublisherFunc) Publish(ctx context.Context, key string, value []byte) error {
+	return f(ctx, key, value)
+}
+
+// Queue publishes records in the background, encoded as JSON unless an
+// encoder is set. Records are dropped when the buffer is full. A nil Queue
+// publishes nothing.
+type Queue[T any] struct {
+	name      string
+	publisher Publisher
+	key       func(T) string
+	encode    func(T) ([]byte, error)
+	records   chan T
+	logger    *zap.Logger
+	dropped   atomic.Int64
+}
+
+// NewQueue creates a queue that buffers up to size records and publishes
+// each under the key returned by key. Name is what the records are called in
+// logs, such as "usage event".
+func NewQueue[T any](name string, publisher Publisher, key func(T) string, size int, logger *zap.Logger) *Queue[T] {
+	return &Queue[T]{
+		name:      name,
+		publisher: publisher,
+		key:       key,
+		encode:    func(record T) ([]byte, error) { return json.Marshal(record) },
+		records:   make(chan T, size),
+		logger:    logger,
+	}
+}
+
+// SetEncoder replaces JSON as the encoding of records
+func (q *Queue[T]) SetEncoder(encode func(T) ([]byte, error)) {
+	q.encode = encode
+}
+
+// Enqueue queues a record without waiting, dropping it when the buffer is full
+func (q *Queue[T]) Enqueue(record T) {
+	if q == nil {
+		return
+	}
+	select {
+	case q.records <- record:
+	default:
+		if q.dropped.Add(1)%100 == 1 {
+			q.logger.Warn(q.name+" buffer full, dropping records", zap.Int64("dropped", q.dropped.Load()))
+		}
+	}
+}
+
+// Dropped returns how many records were discarded because the buffer was full
+func (q *Queue[T]) Dropped() int64 {
+	if q == nil {
+		return 0
+	}
+	return q.dropped.Load()
+}
+
+// Run publishes queued records until ctx is cancelled, then flushes what is
+// left
+func (q *Queue[T]) Run(ctx context.Context) {
+	if q == nil {
+		return
+	}
+	for {
+		select {
+		case record := <-q.records:
+			q.publish(ctx, record)
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+			defer cancel()
+			for {
+				select {
+				case record := <-q.records:
+					q.publish(flushCtx, record)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (q *Queue[T]) publish(ctx context.Context, record T) {
+	value, err := q.encode(record)
+	if err != nil {
+		q.logger.Error("failed to encode "+q.name, zap.Error(err))
+		return
+	}
+	key := q.key(record)
+	if err := q.publisher.Publish(ctx, key, value); err != nil {
+		q.logger.Error("failed to publish "+q.name, zap.String("key", key), zap.Error(err))
+	}
+}
+
+// NewID returns a random ID for a record, which consumers use to ignore
+// redelivered copies
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
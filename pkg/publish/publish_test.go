@@ -0,0 +1,80 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type record struct {
+	Key   string `json:"key"`
+	Value int    `json:"value"`
+}
+
+func TestQueue_PublishesByKeyAndFlushesOnShutdown(t *testing.T) {
+	var keys, values []string
+	queue := NewQueue("test record", PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		keys = append(keys, key)
+		values = append(values, string(value))
+		return nil
+	}), func(r record) string { return r.Key }, 10, zap.NewNop())
+
+	queue.Enqueue(record{Key: "a", Value: 1})
+	queue.Enqueue(record{Key: "b", Value: 2})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	queue.Run(ctx)
+
+	assert.Equal(t, []string{"a", "b"}, keys)
+	assert.Equal(t, []string{`{"key":"a","value":1}`, `{"key":"b","value":2}`}, values)
+}
+
+func TestQueue_SetEncoder(t *testing.T) {
+	var published []string
+	queue := NewQueue("test record", PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		published = append(published, string(value))
+		return nil
+	}), func(r record) string { return r.Key }, 10, zap.NewNop())
+	queue.SetEncoder(func(r record) ([]byte, error) {
+		if r.Value < 0 {
+			return nil, errors.New("negative value")
+		}
+		return []byte(r.Key), nil
+	})
+
+	queue.Enqueue(record{Key: "a", Value: -1})
+	queue.Enqueue(record{Key: "b", Value: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	queue.Run(ctx)
+
+	// Records that cannot be encoded are logged and skipped
+	assert.Equal(t, []string{"b"}, published)
+}
+
+func TestQueue_DropsWhenFullAndNilIsNoop(t *testing.T) {
+	queue := NewQueue("test record", PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		return nil
+	}), func(r record) string { return r.Key }, 1, zap.NewNop())
+
+	queue.Enqueue(record{Key: "a"})
+	queue.Enqueue(record{Key: "b"})
+	assert.Equal(t, int64(1), queue.Dropped())
+
+	var nilQueue *Queue[record]
+	nilQueue.Enqueue(record{Key: "a"})
+	nilQueue.Run(context.Background())
+	assert.Zero(t, nilQueue.Dropped())
+}
+
+func TestNewID(t *testing.T) {
+	id := NewID()
+	require.Len(t, id, 32)
+	assert.NotEqual(t, id, NewID())
+}
@@ -0,0 +1,202 @@
+// Package scaling describes the load services report and the per-model
+// policies the autoscaler sizes Triton deployments with.
+package scaling
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// LoadPath is where services serve their load report
+const LoadPath = "/v1/load"
+
+// DefaultRateWindow is the shortest interval request rates are measured over
+const DefaultRateWindow = 10 * time.Second
+
+// Load is the traffic one service instance is handling for a model version
+type Load struct {
+	Model             string  `json:"model"`
+	Version           string  `json:"version"`
+	InFlight          int64   `json:"in_flight"`
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	// QueueSeconds and BatchSize are the average time requests recently
+	// waited in the model server's own queue and the average size of the
+	// batches it ran, when the service reads its model server's metrics
+	QueueSeconds float64 `json:"queue_seconds,omitempty"`
+	BatchSize    float64 `json:"batch_size,omitempty"`
+}
+
+// Report is a service instance's load across every model version it has served
+type Report struct {
+	Service string `json:"service"`
+	Loads   []Load `json:"loads"`
+	// GPUUtilization is the average utilization of the GPUs the instance's
+	// model server runs on, between 0 and 1, when the service reads it
+	GPUUtilization float64   `json:"gpu_utilization,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Policy sizes the deployment serving a model. Each non-zero target is a
+// signal; the deployment gets the most replicas any signal asks for, within
+// MinReplicas and MaxReplicas.
+type Policy struct {
+	Model      string `json:"model"`
+	Deployment string `json:"deployment" binding:"required"`
+	// MetricsURL is the deployment's Triton metrics endpoint, read for GPU utilization
+	MetricsURL  string `json:"metrics_url,omitempty"`
+	MinReplicas int32  `json:"min_replicas"`
+	MaxReplicas int32  `json:"max_replicas"`
+
+	// TargetInFlight is the concurrent requests one replica should handle
+	TargetInFlight float64 `json:"target_in_flight,omitempty"`
+	// TargetRPS is the requests per second one replica should handle
+	TargetRPS float64 `json:"target_rps,omitempty"`
+	// TargetGPUUtilization is the average GPU utilization to hold, between 0 and 1
+	TargetGPUUtilization float64 `json:"target_gpu_utilization,omitempty"`
+
+	// Cooldowns are the shortest waits after any scaling before scaling up or down again
+	ScaleUpCooldownSeconds   int `json:"scale_up_cooldown_seconds"`
+	ScaleDownCooldownSeconds int `json:"scale_down_cooldown_seconds"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks that the policy names a deployment, bounds its replicas and
+// has at least one target
+func (p Policy) Validate() error {
+	switch {
+	case p.Deployment == "":
+		return apperrors.New(apperrors.InvalidArgument, "scaling policy has no deployment")
+	case p.MinReplicas < 0 || p.MaxReplicas < 1 || p.MinReplicas > p.MaxReplicas:
+		return apperrors.Newf(apperrors.InvalidArgument, "invalid replica bounds %d-%d", p.MinReplicas, p.MaxReplicas)
+	case p.TargetInFlight < 0 || p.TargetRPS < 0:
+		return apperrors.New(apperrors.InvalidArgument, "load targets must not be negative")
+	case p.TargetGPUUtilization < 0 || p.TargetGPUUtilization > 1:
+		return apperrors.Newf(apperrors.InvalidArgument, "GPU utilization target %.2f is not between 0 and 1", p.TargetGPUUtilization)
+	case p.TargetGPUUtilization > 0 && p.MetricsURL == "":
+		return apperrors.New(apperrors.InvalidArgument, "a GPU utilization target needs a metrics URL")
+	case p.TargetInFlight == 0 && p.TargetRPS == 0 && p.TargetGPUUtilization == 0:
+		return apperrors.New(apperrors.InvalidArgument, "scaling policy has no target")
+	case p.ScaleUpCooldownSeconds < 0 || p.ScaleDownCooldownSeconds < 0:
+		return apperrors.New(apperrors.InvalidArgument, "cooldowns must not be negative")
+	}
+	return nil
+}
+
+// ScaleUpCooldown is the shortest wait after scaling before scaling up
+func (p Policy) ScaleUpCooldown() time.Duration {
+	return time.Duration(p.ScaleUpCooldownSeconds) * time.Second
+}
+
+// ScaleDownCooldown is the shortest wait after scaling before scaling down
+func (p Policy) ScaleDownCooldown() time.Duration {
+	return time.Duration(p.ScaleDownCooldownSeconds) * time.Second
+}
+
+type loadKey struct {
+	model   string
+	version string
+}
+
+type counter struct {
+	inFlight int64
+	total    int64
+	sampled  int64
+	rate     float64
+}
+
+// Tracker counts the requests a service is handling per model version and
+// serves them as its load report
+type Tracker struct {
+	service string
+	window  time.Duration
+
+	mu        sync.Mutex
+	counters  map[loadKey]*counter
+	sampledAt time.Time
+}
+
+// NewTracker creates a tracker for service that measures request rates over
+// at least window
+func NewTracker(service string, window time.Duration) *Tracker {
+	return &Tracker{
+		service:  service,
+		window:   window,
+		counters: make(map[loadKey]*counter),
+	}
+}
+
+// Start counts a request for a model version; call the returned function once it finishes
+func (t *Tracker) Start(model, version string) func() {
+	key := loadKey{model: model, version: version}
+
+	t.mu.Lock()
+	c, ok := t.counters[key]
+	if !ok {
+		c = &counter{}
+		t.counters[key] = c
+	}
+	c.inFlight++
+	c.total++
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			c.inFlight--
+			t.mu.Unlock()
+		})
+	}
+}
+
+// Report returns the current load. Rates are remeasured once the window has
+// passed since the last measurement, so frequent readers see the same rate.
+func (t *Tracker) Report(now time.Time) Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	elapsed := now.Sub(t.sampledAt)
+	remeasure := t.sampledAt.IsZero() || elapsed >= t.window
+	report := Report{Service: t.service, Loads: make([]Load, 0, len(t.counters)), UpdatedAt: now}
+	for key, c := range t.counters {
+		if remeasure {
+			if !t.sampledAt.IsZero() {
+				c.rate = float64(c.total-c.sampled) / elapsed.Seconds()
+			}
+			c.sampled = c.total
+		}
+		report.Loads = append(report.Loads, Load{
+			Model:             key.model,
+			Version:           key.version,
+			InFlight:          c.inFlight,
+			RequestsPerSecond: c.rate,
+		})
+	}
+	if remeasure {
+		t.sampledAt = now
+	}
+
+	sort.Slice(report.Loads, func(i, j int) bool {
+		if report.Loads[i].Model != report.Loads[j].Model {
+			return report.Loads[i].Model < report.Loads[j].Model
+		}
+		return report.Loads[i].Version < report.Loads[j].Version
+	})
+	return report
+}
+
+// ServeHTTP serves the load report
+func (t *Tracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apperrors.WriteHTTP(w, apperrors.New(apperrors.Unimplemented, "method not allowed"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(t.Report(time.Now().UTC()))
+}
@@ -0,0 +1,60 @@
+package scaling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestTracker_Report(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker("model-router", 10*time.Second)
+	assert.Empty(t, tracker.Report(now).Loads)
+
+	done := tracker.Start("resnet18", "v1")
+	tracker.Start("bert", "v1")
+	report := tracker.Report(now)
+	require.Len(t, report.Loads, 2)
+	assert.Equal(t, "bert", report.Loads[0].Model)
+	assert.Equal(t, int64(1), report.Loads[1].InFlight)
+	assert.Zero(t, report.Loads[1].RequestsPerSecond, "no rate before a full window")
+
+	done()
+	done()
+	for i := 0; i < 19; i++ {
+		tracker.Start("resnet18", "v1")()
+	}
+
+	// Inside the window the previous rate is kept
+	report = tracker.Report(now.Add(5 * time.Second))
+	assert.Zero(t, report.Loads[1].InFlight)
+	assert.Zero(t, report.Loads[1].RequestsPerSecond)
+
+	report = tracker.Report(now.Add(10 * time.Second))
+	assert.InDelta(t, 2, report.Loads[1].RequestsPerSecond, 0.001)
+	assert.InDelta(t, 0.1, report.Loads[0].RequestsPerSecond, 0.001)
+	assert.Equal(t, "model-router", report.Service)
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	valid := Policy{Model: "resnet18", Deployment: "triton-resnet18", MinReplicas: 1, MaxReplicas: 4, TargetInFlight: 8}
+	assert.NoError(t, valid.Validate())
+
+	for name, mutate := range map[string]func(*Policy){
+		"no deployment":   func(p *Policy) { p.Deployment = "" },
+		"inverted bounds": func(p *Policy) { p.MinReplicas = 5 },
+		"no target":       func(p *Policy) { p.TargetInFlight = 0 },
+		"gpu over 1":      func(p *Policy) { p.TargetGPUUtilization = 80; p.MetricsURL = "http://triton:8002/metrics" },
+		"gpu without url": func(p *Policy) { p.TargetGPUUtilization = 0.8 },
+	} {
+		policy := valid
+		mutate(&policy)
+		assert.True(t, apperrors.Is(policy.Validate(), apperrors.InvalidArgument), name)
+	}
+
+	assert.Equal(t, 30*time.Second, Policy{ScaleUpCooldownSeconds: 30}.ScaleUpCooldown())
+}
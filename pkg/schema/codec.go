@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// magicByte starts every message in the registry wire format, followed by
+// the 4-byte big-endian schema ID and the JSON payload
+const magicByte = 0
+
+const headerSize = 5
+
+// Codec validates messages against their contracts and, with a registry,
+// frames them with the ID of their registered schema. Without a registry
+// messages are plain JSON, which keeps local development free of the
+// registry; they are still validated. A nil Codec behaves like one without a
+// registry.
+type Codec struct {
+	registry *Registry
+}
+
+// NewCodec creates a codec that registers and checks schemas with registry,
+// which may be nil
+func NewCodec(registry *Registry) *Codec {
+	return &Codec{registry: registry}
+}
+
+// FromEnv creates a codec for the registry at SCHEMA_REGISTRY_URL, or one
+// without a registry when it is unset
+func FromEnv() *Codec {
+	registryURL := os.Getenv("SCHEMA_REGISTRY_URL")
+	if registryURL == "" {
+		return NewCodec(nil)
+	}
+	return NewCodec(NewRegistry(registryURL, &http.Client{Timeout: 10 * time.Second}))
+}
+
+// Enabled reports whether messages are framed with registry schema IDs
+func (c *Codec) Enabled() bool {
+	return c != nil && c.registry != nil
+}
+
+// Register registers the contracts a service produces, so an incompatible
+// contract fails at startup rather than on the first message
+func (c *Codec) Register(ctx context.Context, contracts ...*Contract) error {
+	if !c.Enabled() {
+		return nil
+	}
+	for _, contract := range contracts {
+		if _, err := c.registry.Register(ctx, contract.Subject, contract.Schema); err != nil {
+			return fmt.Errorf("failed to register %s v%d: %w", contract.Subject, contract.Version, err)
+		}
+	}
+	return nil
+}
+
+// Encode marshals v and frames it as a message of contract
+func (c *Codec) Encode(ctx context.Context, contract *Contract, v interface{}) ([]byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", contract.Subject, err)
+	}
+	return c.Frame(ctx, contract, payload)
+}
+
+// Frame validates an encoded JSON message against contract and prefixes the
+// contract's schema ID
+func (c *Codec) Frame(ctx context.Context, contract *Contract, payload []byte) ([]byte, error) {
+	if err := contract.Validate(payload); err != nil {
+		return nil, err
+	}
+	if !c.Enabled() {
+		return payload, nil
+	}
+
+	id, err := c.registry.Register(ctx, contract.Subject, contract.Schema)
+	if err != nil {
+		return nil, err
+	}
+	framed := make([]byte, headerSize+len(payload))
+	framed[0] = magicByte
+	binary.BigEndian.PutUint32(framed[1:headerSize], uint32(id))
+	copy(framed[headerSize:], payload)
+	return framed, nil
+}
+
+// Decode checks that data is a message of contract and unmarshals it into v.
+// With a registry, messages must carry the ID of a schema registered under
+// the contract's subject; any version is accepted as long as the payload
+// satisfies this reader's contract. Violations are InvalidArgument errors and
+// should be discarded rather than retried.
+func (c *Codec) Decode(ctx context.Context, contract *Contract, data []byte, v interface{}) error {
+	payload := data
+	if c.Enabled() {
+		if len(data) < headerSize || data[0] != magicByte {
+			return apperrors.Newf(apperrors.InvalidArgument, "%s message has no schema ID", contract.Subject)
+		}
+		id := int(binary.BigEndian.Uint32(data[1:headerSize]))
+		subjects, err := c.registry.Subjects(ctx, id)
+		if apperrors.CodeOf(err) == apperrors.NotFound {
+			return apperrors.Newf(apperrors.InvalidArgument, "unknown schema ID %d", id)
+		}
+		if err != nil {
+			return err
+		}
+		if !contains(subjects, contract.Subject) {
+			return apperrors.Newf(apperrors.InvalidArgument, "schema ID %d is not a version of %s", id, contract.Subject)
+		}
+		payload = data[headerSize:]
+	}
+
+	if err := contract.Validate(payload); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return apperrors.Wrap(err, apperrors.InvalidArgument, fmt.Sprintf("failed to decode %s", contract.Subject))
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// contentType is the media type of the schema registry REST API
+const contentType = "application/vnd.schemaregistry.v1+json"
+
+// Registry is a client for a Confluent-compatible schema registry. Schema IDs
+// are immutable, so lookups are cached for the life of the client.
+type Registry struct {
+	baseURL string
+	client  *http.Client
+
+	mu       sync.Mutex
+	ids      map[string]int   // subject and schema -> ID
+	subjects map[int][]string // ID -> subjects it is registered under
+}
+
+// NewRegistry creates a client for the registry at baseURL
+func NewRegistry(baseURL string, client *http.Client) *Registry {
+	return &Registry{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		client:   client,
+		ids:      make(map[string]int),
+		subjects: make(map[int][]string),
+	}
+}
+
+// Register registers schema under subject, or finds it if already
+// registered, and returns its ID. A schema incompatible with the subject's
+// latest version is rejected with FailedPrecondition. Registering the schema
+// a consumer reads also lets it recognise messages of that version without
+// further lookups.
+func (r *Registry) Register(ctx context.Context, subject, schema string) (int, error) {
+	key := subject + "\x00" + schema
+	r.mu.Lock()
+	id, ok := r.ids[key]
+	r.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"schemaType": "JSON", "schema": schema})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode schema: %w", err)
+	}
+
+	var registered struct {
+		ID int `json:"id"`
+	}
+	endpoint := fmt.Sprintf("%s/subjects/%s/versions", r.baseURL, url.PathEscape(subject))
+	if err := r.do(ctx, http.MethodPost, endpoint, body, &registered); err != nil {
+		if apperrors.CodeOf(err) == apperrors.AlreadyExists {
+			return 0, apperrors.Wrap(err, apperrors.FailedPrecondition, fmt.Sprintf("schema is incompatible with %s", subject))
+		}
+		return 0, err
+	}
+
+	r.mu.Lock()
+	r.ids[key] = registered.ID
+	if !contains(r.subjects[registered.ID], subject) {
+		r.subjects[registered.ID] = append(r.subjects[registered.ID], subject)
+	}
+	r.mu.Unlock()
+	return registered.ID, nil
+}
+
+// Subjects returns the subjects a schema ID is registered under
+func (r *Registry) Subjects(ctx context.Context, id int) ([]string, error) {
+	r.mu.Lock()
+	subjects, ok := r.subjects[id]
+	r.mu.Unlock()
+	if ok {
+		return subjects, nil
+	}
+
+	var versions []struct {
+		Subject string `json:"subject"`
+	}
+	if err := r.do(ctx, http.MethodGet, fmt.Sprintf("%s/schemas/ids/%d/versions", r.baseURL, id), nil, &versions); err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		subjects = append(subjects, version.Subject)
+	}
+
+	r.mu.Lock()
+	r.subjects[id] = subjects
+	r.mu.Unlock()
+	return subjects, nil
+}
+
+func (r *Registry) do(ctx context.Context, method, endpoint string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", contentType)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "schema registry")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.FromHTTPResponse(resp, "schema registry")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode schema registry response: %w", err)
+	}
+	return nil
+}
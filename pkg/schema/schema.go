@@ -0,0 +1,242 @@
+// Package schema defines the versioned contracts of the platform's Kafka
+// messages and encodes them in the schema registry wire format, so producers
+// and consumers agree on message shapes explicitly rather than through
+// untyped maps.
+//
+// Contracts are JSON Schema documents registered under a subject named after
+// the record (the registry's RecordNameStrategy), independent of the topic
+// they are published to. The registry rejects a new version that is not
+// compatible with the previous one under the subject's compatibility level.
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+//go:embed schemas/*.json
+var documents embed.FS
+
+// Contract is one version of a message format
+type Contract struct {
+	// Subject is the registry subject the schema is registered under
+	Subject string
+	// Version is the contract's version in this repository; registry version
+	// numbers are assigned by the registry and may differ
+	Version int
+	// Schema is the JSON Schema document
+	Schema string
+
+	root *node
+}
+
+// Contracts of the platform's messages
+var (
+	BatchJobs      = mustContract("ai_platform.BatchJob", 5, "schemas/batch_job.v5.json")
+	BatchControls  = mustContract("ai_platform.BatchControl", 1, "schemas/batch_control.v1.json")
+	UsageEvents    = mustContract("ai_platform.UsageEvent", 3, "schemas/usage_event.v3.json")
+	InferenceLogs  = mustContract("ai_platform.InferenceLog", 2, "schemas/inference_log.v2.json")
+	DriftEvents    = mustContract("ai_platform.DriftEvent", 1, "schemas/drift_event.v1.json")
+	PlatformEvents = mustContract("ai_platform.PlatformEvent", 1, "schemas/platform_event.v1.json")
+	AccessLogs     = mustContract("ai_platform.AccessLog", 1, "schemas/access_log.v1.json")
+	// RoutingDecisions are the model router's audit records
+	RoutingDecisions = mustContract("ai_platform.RoutingDecision", 1, "schemas/routing_decision.v1.json")
+)
+
+func mustContract(subject string, version int, path string) *Contract {
+	document, err := documents.ReadFile(path)
+	if err != nil {
+		panic(fmt.Sprintf("schema: %v", err))
+	}
+	var root node
+	if err := json.Unmarshal(document, &root); err != nil {
+		panic(fmt.Sprintf("schema: %s: %v", path, err))
+	}
+	return &Contract{Subject: subject, Version: version, Schema: string(document), root: &root}
+}
+
+// Validate checks an encoded message against the contract. It covers the
+// subset of JSON Schema the contracts use: type, required, properties, items,
+// enum, minLength and the date-time format.
+func (c *Contract) Validate(payload []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return apperrors.Wrap(err, apperrors.InvalidArgument, "message is not valid JSON")
+	}
+	if err := c.root.validate("$", value); err != nil {
+		return apperrors.Newf(apperrors.InvalidArgument, "message does not match %s v%d: %v", c.Subject, c.Version, err)
+	}
+	return nil
+}
+
+// node is a JSON Schema (sub)document
+type node struct {
+	Type       string           `json:"type"`
+	Required   []string         `json:"required"`
+	Properties map[string]*node `json:"properties"`
+	Items      *node            `json:"items"`
+	Enum       []string         `json:"enum"`
+	MinLength  int              `json:"minLength"`
+	Format     string           `json:"format"`
+}
+
+func (n *node) validate(path string, value interface{}) error {
+	if n == nil {
+		return nil
+	}
+
+	switch n.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: want object", path)
+		}
+		for _, name := range n.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s: missing required field %q", path, name)
+			}
+		}
+		for name, property := range n.Properties {
+			if v, ok := object[name]; ok {
+				if err := property.validate(path+"."+name, v); err != nil {
+					return err
+				}
+			}
+		}
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: want array", path)
+		}
+		for i, item := range items {
+			if err := n.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: want string", path)
+		}
+		if len(s) < n.MinLength {
+			return fmt.Errorf("%s: shorter than %d", path, n.MinLength)
+		}
+		if len(n.Enum) > 0 && !contains(n.Enum, s) {
+			return fmt.Errorf("%s: %q is not one of %v", path, s, n.Enum)
+		}
+		if n.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return fmt.Errorf("%s: want RFC 3339 date-time", path)
+			}
+		}
+
+	case "integer":
+		f, ok := value.(float64)
+		if !ok || f != float64(int64(f)) {
+			return fmt.Errorf("%s: want integer", path)
+		}
+
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: want number", path)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: want boolean", path)
+		}
+	}
+	return nil
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// BatchJob is a batch inference job queued by the API gateway for the batch worker
+type BatchJob struct {
+	JobID   string                   `json:"job_id"`
+	Model   string                   `json:"model"`
+	Version string                   `json:"version"`
+	Inputs  []map[string]interface{} `json:"inputs"`
+	// Tenant is the tenant that submitted the job, if any
+	Tenant string `json:"tenant,omitempty"`
+	// SubjectID identifies the data subject the inputs belong to, if any
+	SubjectID string `json:"subject_id,omitempty"`
+	// UserID is the user that submitted the job, if any
+	UserID string `json:"user_id,omitempty"`
+	// CallbackURL receives the results of async inferences when the job finishes
+	CallbackURL string `json:"callback_url,omitempty"`
+	// Priority is the job's priority, which also chose its topic; empty is
+	// normal
+	Priority  string    `json:"priority,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Batch job priorities. Each has its own topic, so small urgent jobs are not
+// queued behind large backfills.
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// Priorities lists the batch job priorities from most to least urgent
+var Priorities = []string{PriorityHigh, PriorityNormal, PriorityLow}
+
+// PriorityTopic names the topic jobs of priority are queued on: normal jobs
+// keep the base topic, and the others get it suffixed with their priority
+func PriorityTopic(base, priority string) string {
+	if priority == "" || priority == PriorityNormal {
+		return base
+	}
+	return base + "-" + priority
+}
+
+// TenantTopic names the base topic of a tenant given topics of its own,
+// whose jobs are kept apart from other tenants' and may be run by workers of
+// their own. Its priority topics are named after it like the shared ones'.
+func TenantTopic(base, tenant string) string {
+	return base + "-tenant-" + tenant
+}
+
+// JobTopics lists the priority topics of base and of the tenants with topics
+// of their own
+func JobTopics(base string, tenants []string) []string {
+	topics := PriorityTopics(base)
+	for _, tenant := range tenants {
+		topics = append(topics, PriorityTopics(TenantTopic(base, tenant))...)
+	}
+	return topics
+}
+
+// PriorityTopics lists the topics of every priority, most urgent first
+func PriorityTopics(base string) []string {
+	topics := make([]string, 0, len(Priorities))
+	for _, priority := range Priorities {
+		topics = append(topics, PriorityTopic(base, priority))
+	}
+	return topics
+}
+
+// ActionCancel stops a batch job from dispatching its remaining inputs
+const ActionCancel = "cancel"
+
+// BatchControl is an instruction from the API gateway to every batch worker
+// about a job one of them may be running
+type BatchControl struct {
+	JobID       string    `json:"job_id"`
+	Action      string    `json:"action"`
+	RequestedAt time.Time `json:"requested_at"`
+}
@@ -0,0 +1,195 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestContract_Validate(t *testing.T) {
+	job := BatchJob{
+		JobID:     "job-1",
+		Model:     "resnet18",
+		Version:   "v1",
+		Inputs:    []map[string]interface{}{{"x": 1}},
+		CreatedAt: time.Now(),
+	}
+	payload, err := json.Marshal(job)
+	require.NoError(t, err)
+	assert.NoError(t, BatchJobs.Validate(payload))
+
+	for name, payload := range map[string]string{
+		"not json":        `{`,
+		"missing field":   `{"job_id": "a", "model": "m", "version": "v1", "inputs": []}`,
+		"wrong type":      `{"job_id": "a", "model": "m", "version": "v1", "inputs": {}, "created_at": "2026-10-16T00:00:00Z"}`,
+		"empty required":  `{"job_id": "", "model": "m", "version": "v1", "inputs": [], "created_at": "2026-10-16T00:00:00Z"}`,
+		"bad date":        `{"job_id": "a", "model": "m", "version": "v1", "inputs": [], "created_at": "yesterday"}`,
+		"bad array items": `{"job_id": "a", "model": "m", "version": "v1", "inputs": [1], "created_at": "2026-10-16T00:00:00Z"}`,
+	} {
+		err := BatchJobs.Validate([]byte(payload))
+		assert.True(t, apperrors.Is(err, apperrors.InvalidArgument), name)
+	}
+
+	assert.Error(t, UsageEvents.Validate([]byte(`{"id": "a", "tenant": "", "service": "s", "kind": "stream", "model": "m", "version": "v1", "requests": 1, "errors": 0, "timestamp": "2026-10-16T00:00:00Z"}`)))
+	assert.Error(t, UsageEvents.Validate([]byte(`{"id": "a", "tenant": "", "service": "s", "kind": "batch", "model": "m", "version": "v1", "requests": 1.5, "errors": 0, "timestamp": "2026-10-16T00:00:00Z"}`)))
+	assert.NoError(t, UsageEvents.Validate([]byte(`{"id": "a", "tenant": "", "service": "s", "kind": "compute", "model": "m", "version": "1", "requests": 1, "errors": 0, "compute_ms": 40, "pool": "gpu-a100", "timestamp": "2026-10-16T00:00:00Z"}`)))
+	assert.NoError(t, UsageEvents.Validate([]byte(`{"id": "a", "tenant": "", "service": "s", "kind": "realtime", "model": "m", "version": "1", "requests": 1, "errors": 0, "estimated_cost": 0.0045, "timestamp": "2026-10-16T00:00:00Z"}`)))
+	assert.Error(t, UsageEvents.Validate([]byte(`{"id": "a", "tenant": "", "service": "s", "kind": "realtime", "model": "m", "version": "1", "requests": 1, "errors": 0, "estimated_cost": "0.01", "timestamp": "2026-10-16T00:00:00Z"}`)))
+
+	assert.NoError(t, InferenceLogs.Validate([]byte(`{"id": "a", "timestamp": "2026-10-16T00:00:00Z", "service": "model-router", "model": "m", "version": "v2", "latency_ms": 12, "output": {}, "shadow_of": "v1", "primary_output": {}}`)))
+	assert.Error(t, InferenceLogs.Validate([]byte(`{"id": "a", "timestamp": "2026-10-16T00:00:00Z", "service": "model-router", "model": "m", "version": "v2", "latency_ms": 12, "shadow_of": 1}`)))
+
+	assert.NoError(t, PlatformEvents.Validate([]byte(`{"id": "a", "type": "job.completed", "severity": "info", "service": "batch-worker", "subject": "job-1", "attributes": {"model": "m"}, "occurred_at": "2026-10-16T00:00:00Z"}`)))
+	assert.Error(t, PlatformEvents.Validate([]byte(`{"id": "a", "type": "job.completed", "severity": "urgent", "service": "batch-worker", "subject": "job-1", "occurred_at": "2026-10-16T00:00:00Z"}`)))
+
+	assert.NoError(t, BatchJobs.Validate([]byte(`{"job_id": "a", "model": "m", "version": "v1", "inputs": [], "priority": "low", "created_at": "2026-10-16T00:00:00Z"}`)))
+	assert.Error(t, BatchJobs.Validate([]byte(`{"job_id": "a", "model": "m", "version": "v1", "inputs": [], "priority": "urgent", "created_at": "2026-10-16T00:00:00Z"}`)))
+	assert.NoError(t, BatchJobs.Validate([]byte(`{"job_id": "a", "model": "m", "version": "v1", "inputs": [], "tenant": "acme", "created_at": "2026-10-16T00:00:00Z"}`)))
+	assert.Error(t, BatchJobs.Validate([]byte(`{"job_id": "a", "model": "m", "version": "v1", "inputs": [], "tenant": 7, "created_at": "2026-10-16T00:00:00Z"}`)))
+
+	assert.NoError(t, BatchControls.Validate([]byte(`{"job_id": "job-1", "action": "cancel", "requested_at": "2026-10-16T00:00:00Z"}`)))
+	assert.Error(t, BatchControls.Validate([]byte(`{"job_id": "job-1", "action": "pause", "requested_at": "2026-10-16T00:00:00Z"}`)))
+
+	assert.NoError(t, AccessLogs.Validate([]byte(`{"id": "a", "timestamp": "2026-10-16T00:00:00Z", "service": "api-gateway", "method": "POST", "route": "/v1/infer", "status": 200, "latency_ms": 12, "model": "resnet18"}`)))
+	assert.Error(t, AccessLogs.Validate([]byte(`{"id": "a", "timestamp": "2026-10-16T00:00:00Z", "service": "api-gateway", "method": "POST", "route": "/v1/infer", "status": "ok", "latency_ms": 12}`)))
+
+	assert.NoError(t, RoutingDecisions.Validate([]byte(`{"id": "a", "timestamp": "2026-10-16T00:00:00Z", "service": "model-router", "model": "llama", "version": "v1", "backend": "http://gpu-1:8082", "tried": ["http://gpu-2:8082", "http://gpu-1:8082"], "attempts": 2, "latency_ms": 40, "status": "ok"}`)))
+	assert.Error(t, RoutingDecisions.Validate([]byte(`{"id": "a", "timestamp": "2026-10-16T00:00:00Z", "service": "model-router", "model": "llama", "version": "v1", "tried": "http://gpu-1:8082", "attempts": 1, "latency_ms": 40, "status": "ok"}`)))
+}
+
+// fakeRegistry serves the registry endpoints the client uses
+type fakeRegistry struct {
+	schemas  map[int]string
+	subjects map[int]string
+	lookups  int
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/subjects/"):
+		subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			SchemaType string `json:"schemaType"`
+			Schema     string `json:"schema"`
+		}
+		json.Unmarshal(body, &req)
+		if req.SchemaType != "JSON" || strings.Contains(req.Schema, "incompatible") {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error_code": 409, "message": "incompatible"})
+			return
+		}
+		for id, schema := range f.schemas {
+			if schema == req.Schema && f.subjects[id] == subject {
+				json.NewEncoder(w).Encode(map[string]int{"id": id})
+				return
+			}
+		}
+		id := len(f.schemas) + 1
+		f.schemas[id], f.subjects[id] = req.Schema, subject
+		json.NewEncoder(w).Encode(map[string]int{"id": id})
+
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/schemas/ids/"):
+		f.lookups++
+		var id int
+		json.Unmarshal([]byte(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/schemas/ids/"), "/versions")), &id)
+		subject, ok := f.subjects[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode([]map[string]interface{}{{"subject": subject, "version": 1}})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestCodec_RoundTripWithRegistry(t *testing.T) {
+	registry := &fakeRegistry{schemas: map[int]string{}, subjects: map[int]string{}}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	producer := NewCodec(NewRegistry(server.URL, server.Client()))
+	consumer := NewCodec(NewRegistry(server.URL, server.Client()))
+	ctx := context.Background()
+
+	job := BatchJob{JobID: "job-1", Model: "resnet18", Version: "v1", Inputs: []map[string]interface{}{}, CreatedAt: time.Now().UTC()}
+	data, err := producer.Encode(ctx, BatchJobs, job)
+	require.NoError(t, err)
+	assert.Equal(t, byte(magicByte), data[0])
+
+	var decoded BatchJob
+	require.NoError(t, consumer.Decode(ctx, BatchJobs, data, &decoded))
+	assert.Equal(t, "job-1", decoded.JobID)
+	assert.Equal(t, 1, registry.lookups)
+
+	// IDs are cached
+	require.NoError(t, consumer.Decode(ctx, BatchJobs, data, &decoded))
+	assert.Equal(t, 1, registry.lookups)
+
+	// Unframed messages and messages of another subject are rejected
+	err = consumer.Decode(ctx, BatchJobs, data[headerSize:], &decoded)
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+	err = consumer.Decode(ctx, UsageEvents, data, &decoded)
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+
+	// Unknown schema IDs are rejected
+	unknown := append([]byte{magicByte, 0, 0, 0, 99}, data[headerSize:]...)
+	err = consumer.Decode(ctx, BatchJobs, unknown, &decoded)
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+}
+
+func TestCodec_RegisterIncompatible(t *testing.T) {
+	server := httptest.NewServer(&fakeRegistry{schemas: map[int]string{}, subjects: map[int]string{}})
+	defer server.Close()
+
+	codec := NewCodec(NewRegistry(server.URL, server.Client()))
+	incompatible := &Contract{Subject: "ai_platform.BatchJob", Version: 2, Schema: `{"title": "incompatible"}`}
+
+	err := codec.Register(context.Background(), BatchJobs, incompatible)
+	assert.True(t, apperrors.Is(err, apperrors.FailedPrecondition))
+}
+
+func TestCodec_WithoutRegistry(t *testing.T) {
+	var codec *Codec
+	ctx := context.Background()
+
+	data, err := codec.Encode(ctx, BatchJobs, BatchJob{JobID: "job-1", Model: "m", Version: "v1", Inputs: []map[string]interface{}{}, CreatedAt: time.Now()})
+	require.NoError(t, err)
+	assert.Equal(t, byte('{'), data[0])
+
+	var decoded BatchJob
+	require.NoError(t, codec.Decode(ctx, BatchJobs, data, &decoded))
+	assert.Equal(t, "job-1", decoded.JobID)
+
+	_, err = codec.Encode(ctx, BatchJobs, BatchJob{Model: "m"})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+}
+
+func TestPriorityTopic(t *testing.T) {
+	assert.Equal(t, "inference-jobs", PriorityTopic("inference-jobs", ""))
+	assert.Equal(t, "inference-jobs", PriorityTopic("inference-jobs", PriorityNormal))
+	assert.Equal(t, "inference-jobs-high", PriorityTopic("inference-jobs", PriorityHigh))
+	assert.Equal(t, "inference-jobs-low", PriorityTopic("inference-jobs", PriorityLow))
+	assert.Equal(t, []string{"inference-jobs-high", "inference-jobs", "inference-jobs-low"}, PriorityTopics("inference-jobs"))
+}
+
+func TestTenantTopic(t *testing.T) {
+	assert.Equal(t, "inference-jobs-tenant-acme", TenantTopic("inference-jobs", "acme"))
+	assert.Equal(t, "inference-jobs-tenant-acme-high", PriorityTopic(TenantTopic("inference-jobs", "acme"), PriorityHigh))
+	assert.Equal(t, []string{
+		"inference-jobs-high", "inference-jobs", "inference-jobs-low",
+		"inference-jobs-tenant-acme-high", "inference-jobs-tenant-acme", "inference-jobs-tenant-acme-low",
+	}, JobTopics("inference-jobs", []string{"acme"}))
+}
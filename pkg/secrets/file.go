@@ -0,0 +1,47 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileProvider reads secrets from a directory tree, as mounted by the
+// Secrets Store CSI driver for AWS Secrets Manager, GCP Secret Manager
+// and Azure Key Vault. Each file under <dir>/<path>/ is one key.
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a new file-backed provider
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+// Read loads every file in the secret directory as a key/value pair
+func (p *FileProvider) Read(ctx context.Context, path string) (*Secret, error) {
+	root := filepath.Join(p.dir, filepath.Clean("/"+path))
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret directory: %w", err)
+	}
+
+	secret := &Secret{Data: make(map[string]string, len(entries))}
+	for _, entry := range entries {
+		// CSI drivers publish atomically through hidden ..data symlinks
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(root, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret key %s: %w", entry.Name(), err)
+		}
+		secret.Data[entry.Name()] = strings.TrimSpace(string(data))
+	}
+
+	return secret, nil
+}
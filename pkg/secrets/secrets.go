@@ -0,0 +1,346 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Secret represents a resolved secret and its lease information
+type Secret struct {
+	Data          map[string]string
+	LeaseID       string
+	LeaseDuration time.Duration
+	Renewable     bool
+}
+
+// Get returns a single value from the secret
+func (s *Secret) Get(key string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	value, ok := s.Data[key]
+	return value, ok
+}
+
+// Provider reads secrets from a backing store
+type Provider interface {
+	Read(ctx context.Context, path string) (*Secret, error)
+}
+
+// Renewer is implemented by providers that issue leased secrets
+type Renewer interface {
+	RenewLease(ctx context.Context, leaseID string, increment time.Duration) (time.Duration, error)
+}
+
+// tokenRenewer is implemented by providers whose own credentials expire
+type tokenRenewer interface {
+	StartTokenRenewal(ctx context.Context, interval time.Duration)
+}
+
+// tokenRenewalInterval is how often provider tokens are refreshed
+const tokenRenewalInterval = 15 * time.Minute
+
+// Manager resolves secret references and keeps leased secrets fresh
+type Manager struct {
+	provider Provider
+	logger   *zap.Logger
+
+	mu     sync.Mutex
+	cache  map[string]*Secret
+	cancel []context.CancelFunc
+}
+
+// NewManager creates a new secret manager. A nil provider makes every lookup
+// return its fallback value, which keeps env-only deployments working.
+func NewManager(provider Provider, logger *zap.Logger) *Manager {
+	m := &Manager{
+		provider: provider,
+		logger:   logger,
+		cache:    make(map[string]*Secret),
+	}
+
+	if renewer, ok := provider.(tokenRenewer); ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		m.cancel = append(m.cancel, cancel)
+		renewer.StartTokenRenewal(ctx, tokenRenewalInterval)
+	}
+
+	return m
+}
+
+// FromEnv builds a provider from environment variables.
+// VAULT_ADDR selects HashiCorp Vault, SECRETS_DIR selects files mounted by a
+// cloud secret manager CSI driver. It returns nil when neither is set.
+func FromEnv(logger *zap.Logger) (Provider, error) {
+	if addr := os.Getenv("VAULT_ADDR"); addr != "" {
+		return NewVaultProvider(VaultConfig{
+			Address:   addr,
+			Token:     os.Getenv("VAULT_TOKEN"),
+			TokenFile: os.Getenv("VAULT_TOKEN_FILE"),
+			Role:      os.Getenv("VAULT_ROLE"),
+			AuthMount: getEnv("VAULT_AUTH_MOUNT", "kubernetes"),
+			Namespace: os.Getenv("VAULT_NAMESPACE"),
+		}, logger)
+	}
+
+	if dir := os.Getenv("SECRETS_DIR"); dir != "" {
+		return NewFileProvider(dir), nil
+	}
+
+	return nil, nil
+}
+
+// Enabled reports whether a secret backend is configured
+func (m *Manager) Enabled() bool {
+	return m.provider != nil
+}
+
+// Lookup resolves a reference of the form "path#key".
+// The fallback is returned when no provider is configured.
+func (m *Manager) Lookup(ctx context.Context, ref, fallback string) (string, error) {
+	if m.provider == nil {
+		return fallback, nil
+	}
+
+	path, key, err := parseRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := m.read(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Get(key)
+	if !ok {
+		return "", fmt.Errorf("secret %s has no key %q", path, key)
+	}
+
+	return value, nil
+}
+
+// LookupOr resolves a reference like Lookup, logging a failure and returning
+// fallback instead of an error
+func (m *Manager) LookupOr(ctx context.Context, ref, fallback string) string {
+	value, err := m.Lookup(ctx, ref, fallback)
+	if err != nil {
+		m.logger.Warn("failed to resolve secret, using fallback",
+			zap.String("ref", ref),
+			zap.Error(err),
+		)
+		return fallback
+	}
+	return value
+}
+
+// Watch re-reads a secret reference and invokes onChange when its value rotates.
+// Renewable leases are renewed at two thirds of their duration; static secrets
+// are polled at the given interval.
+func (m *Manager) Watch(ctx context.Context, ref string, interval time.Duration, onChange func(string)) {
+	m.WatchAll(ctx, []string{ref}, interval, func(values []string) {
+		onChange(values[0])
+	})
+}
+
+// WatchAll watches references that rotate together, such as a key pair, and
+// invokes onChange with all of their values, in the order of refs, when any
+// of them rotates. Watching them one by one would miss the rotation of all
+// but the first reference read from a shared path.
+func (m *Manager) WatchAll(ctx context.Context, refs []string, interval time.Duration, onChange func([]string)) {
+	if m.provider == nil || len(refs) == 0 {
+		return
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if path, _, err := parseRef(ref); err == nil && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m.mu.Lock()
+	m.cancel = append(m.cancel, cancel)
+	m.mu.Unlock()
+
+	go func() {
+		current := make([]string, len(refs))
+		for i, ref := range refs {
+			current[i], _ = m.Lookup(ctx, ref, "")
+		}
+
+		for {
+			wait := interval
+			for _, path := range paths {
+				if secret := m.cached(path); secret != nil && secret.LeaseDuration > 0 && secret.LeaseDuration*2/3 < wait {
+					wait = secret.LeaseDuration * 2 / 3
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			renewed := true
+			for _, path := range paths {
+				renewed = m.renew(ctx, path) && renewed
+			}
+			if renewed {
+				continue
+			}
+
+			values, err := m.refresh(ctx, paths, refs)
+			if err != nil {
+				m.logger.Error("failed to refresh secret", zap.Strings("refs", refs), zap.Error(err))
+				continue
+			}
+
+			changed := false
+			for i := range values {
+				if values[i] != current[i] {
+					m.logger.Info("secret rotated", zap.String("ref", refs[i]))
+					changed = true
+				}
+			}
+			if changed {
+				current = values
+				onChange(values)
+			}
+		}
+	}()
+}
+
+// Close stops all watchers
+func (m *Manager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, cancel := range m.cancel {
+		cancel()
+	}
+	m.cancel = nil
+}
+
+// renew extends the lease of the secret at path, returning false when the
+// secret must be re-read
+func (m *Manager) renew(ctx context.Context, path string) bool {
+	renewer, ok := m.provider.(Renewer)
+	if !ok {
+		return false
+	}
+
+	secret := m.cached(path)
+	if secret == nil || !secret.Renewable || secret.LeaseID == "" {
+		return false
+	}
+
+	ttl, err := renewer.RenewLease(ctx, secret.LeaseID, secret.LeaseDuration)
+	if err != nil {
+		m.logger.Warn("lease renewal failed, re-reading secret",
+			zap.String("path", path),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	// Vault caps renewals at the lease's max TTL; once the granted TTL
+	// shrinks below the original duration the secret has to be re-issued.
+	if ttl < secret.LeaseDuration {
+		return false
+	}
+
+	m.logger.Debug("lease renewed", zap.String("path", path), zap.Duration("ttl", ttl))
+	return true
+}
+
+// refresh drops the cached secrets at paths and resolves the references again
+func (m *Manager) refresh(ctx context.Context, paths, refs []string) ([]string, error) {
+	m.mu.Lock()
+	for _, path := range paths {
+		delete(m.cache, path)
+	}
+	m.mu.Unlock()
+
+	values := make([]string, len(refs))
+	for i, ref := range refs {
+		value, err := m.Lookup(ctx, ref, "")
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func (m *Manager) read(ctx context.Context, path string) (*Secret, error) {
+	if secret := m.cached(path); secret != nil {
+		return secret, nil
+	}
+
+	secret, err := m.provider.Read(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", path, err)
+	}
+
+	m.mu.Lock()
+	m.cache[path] = secret
+	m.mu.Unlock()
+
+	return secret, nil
+}
+
+func (m *Manager) cached(path string) *Secret {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cache[path]
+}
+
+// parseRef splits a "path#key" reference
+func parseRef(ref string) (string, string, error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || key == "" {
+		return "", "", fmt.Errorf("invalid secret reference %q, expected path#key", ref)
+	}
+	return path, key, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// Value holds a secret that may be rotated while the service is running
+type Value struct {
+	v atomic.Value
+}
+
+// NewValue creates a new rotatable secret value
+func NewValue(initial string) *Value {
+	value := &Value{}
+	value.v.Store(initial)
+	return value
+}
+
+// Load returns the current secret
+func (v *Value) Load() string {
+	return v.v.Load().(string)
+}
+
+// Store replaces the secret, typically from a Manager.Watch callback
+func (v *Value) Store(secret string) {
+	v.v.Store(secret)
+}
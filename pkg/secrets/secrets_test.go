@@ -0,0 +1,192 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestManager_NoProviderUsesFallback(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	manager := NewManager(nil, logger)
+
+	value, err := manager.Lookup(context.Background(), "kv/data/minio#secret_key", "from-env")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", value)
+	assert.False(t, manager.Enabled())
+}
+
+func TestManager_InvalidReference(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	manager := NewManager(NewFileProvider(t.TempDir()), logger)
+
+	_, err := manager.Lookup(context.Background(), "kv/data/minio", "")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid secret reference")
+}
+
+func TestVaultProvider_ReadKVv2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-token", r.Header.Get("X-Vault-Token"))
+		assert.Equal(t, "/v1/secret/data/minio", r.URL.Path)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]interface{}{"access_key": "ak", "secret_key": "sk"},
+				"metadata": map[string]interface{}{"version": 3},
+			},
+		})
+	}))
+	defer server.Close()
+
+	logger, _ := zap.NewDevelopment()
+	provider, err := NewVaultProvider(VaultConfig{Address: server.URL, Token: "test-token"}, logger)
+	assert.NoError(t, err)
+
+	manager := NewManager(provider, logger)
+	value, err := manager.Lookup(context.Background(), "secret/data/minio#secret_key", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sk", value)
+}
+
+func TestVaultProvider_ReadDynamicLease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"lease_id":       "database/creds/batch/abc",
+			"lease_duration": 3600,
+			"renewable":      true,
+			"data":           map[string]interface{}{"username": "v-batch", "password": "pw"},
+		})
+	}))
+	defer server.Close()
+
+	logger, _ := zap.NewDevelopment()
+	provider, err := NewVaultProvider(VaultConfig{Address: server.URL, Token: "test-token"}, logger)
+	assert.NoError(t, err)
+
+	secret, err := provider.Read(context.Background(), "database/creds/batch")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "database/creds/batch/abc", secret.LeaseID)
+	assert.Equal(t, time.Hour, secret.LeaseDuration)
+	assert.True(t, secret.Renewable)
+	assert.Equal(t, "v-batch", secret.Data["username"])
+}
+
+func TestVaultProvider_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":["permission denied"]}`))
+	}))
+	defer server.Close()
+
+	logger, _ := zap.NewDevelopment()
+	provider, err := NewVaultProvider(VaultConfig{Address: server.URL, Token: "bad"}, logger)
+	assert.NoError(t, err)
+
+	_, err = provider.Read(context.Background(), "secret/data/jwt")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+}
+
+func TestVaultProvider_RequiresCredentials(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	_, err := NewVaultProvider(VaultConfig{Address: "http://localhost:8200"}, logger)
+
+	assert.Error(t, err)
+}
+
+func TestVaultProvider_RenewTokenLogsInOnce(t *testing.T) {
+	var renewals, logins int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/kubernetes/login":
+			logins++
+			json.NewEncoder(w).Encode(map[string]interface{}{"auth": map[string]interface{}{"client_token": "fresh"}})
+		default:
+			// The token is not renewable, whichever it is
+			renewals++
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+		}
+	}))
+	defer server.Close()
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenPath, []byte("jwt"), 0o600))
+	defer func(path string) { kubernetesTokenPath = path }(kubernetesTokenPath)
+	kubernetesTokenPath = tokenPath
+
+	provider, err := NewVaultProvider(VaultConfig{Address: server.URL, Token: "stale", Role: "batch", AuthMount: "kubernetes"}, zap.NewNop())
+	assert.NoError(t, err)
+
+	_, err = provider.RenewToken(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 2, renewals)
+	assert.Equal(t, 1, logins)
+}
+
+func TestFileProvider_Read(t *testing.T) {
+	dir := t.TempDir()
+	secretDir := filepath.Join(dir, "minio")
+	assert.NoError(t, os.MkdirAll(secretDir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(secretDir, "access_key"), []byte("ak\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(secretDir, "..data"), []byte("ignored"), 0o600))
+
+	provider := NewFileProvider(dir)
+	secret, err := provider.Read(context.Background(), "minio")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ak", secret.Data["access_key"])
+	assert.NotContains(t, secret.Data, "..data")
+}
+
+func TestManager_WatchAllRotatesTogether(t *testing.T) {
+	dir := t.TempDir()
+	secretDir := filepath.Join(dir, "minio")
+	write := func(key, value string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(secretDir, key), []byte(value), 0o600))
+	}
+	assert.NoError(t, os.MkdirAll(secretDir, 0o755))
+	write("access_key", "ak1")
+	write("secret_key", "sk1")
+
+	logger, _ := zap.NewDevelopment()
+	manager := NewManager(NewFileProvider(dir), logger)
+	defer manager.Close()
+	_, err := manager.Lookup(context.Background(), "minio#access_key", "")
+	assert.NoError(t, err)
+
+	rotated := make(chan []string, 1)
+	manager.WatchAll(context.Background(), []string{"minio#access_key", "minio#secret_key"}, 10*time.Millisecond, func(values []string) {
+		rotated <- values
+	})
+	write("secret_key", "sk2")
+
+	select {
+	case values := <-rotated:
+		assert.Equal(t, []string{"ak1", "sk2"}, values)
+	case <-time.After(2 * time.Second):
+		t.Fatal("rotation was not observed")
+	}
+}
+
+func TestValue_Rotate(t *testing.T) {
+	value := NewValue("old")
+	assert.Equal(t, "old", value.Load())
+
+	value.Store("new")
+	assert.Equal(t, "new", value.Load())
+}
@@ -0,0 +1,257 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// kubernetesTokenPath is where the pod's service account token is mounted
+var kubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// VaultConfig configures the Vault provider
+type VaultConfig struct {
+	Address   string
+	Token     string
+	TokenFile string
+	// Role enables Kubernetes auth when no static token is given
+	Role      string
+	AuthMount string
+	Namespace string
+}
+
+// VaultProvider reads secrets from HashiCorp Vault over its HTTP API
+type VaultProvider struct {
+	cfg        VaultConfig
+	logger     *zap.Logger
+	httpClient *http.Client
+
+	mu    sync.RWMutex
+	token string
+}
+
+// vaultResponse is the envelope returned by Vault's logical endpoints
+type vaultResponse struct {
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int                    `json:"lease_duration"`
+	Renewable     bool                   `json:"renewable"`
+	Data          map[string]interface{} `json:"data"`
+	Auth          *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+// NewVaultProvider creates a new Vault provider and authenticates it
+func NewVaultProvider(cfg VaultConfig, logger *zap.Logger) (*VaultProvider, error) {
+	p := &VaultProvider{
+		cfg:    cfg,
+		logger: logger,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+	p.cfg.Address = strings.TrimRight(cfg.Address, "/")
+
+	token := cfg.Token
+	if token == "" && cfg.TokenFile != "" {
+		data, err := os.ReadFile(cfg.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vault token file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	p.token = token
+
+	if p.token == "" && cfg.Role != "" {
+		if err := p.loginKubernetes(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.token == "" {
+		return nil, fmt.Errorf("vault: no token or kubernetes role configured")
+	}
+
+	return p, nil
+}
+
+// Read reads a secret. KV v2 responses are unwrapped transparently so callers
+// can use the same "path#key" form for static and dynamic secrets.
+func (p *VaultProvider) Read(ctx context.Context, path string) (*Secret, error) {
+	resp, err := p.do(ctx, http.MethodGet, "/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := resp.Data
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, isKV := data["metadata"]; isKV {
+			data = inner
+		}
+	}
+
+	secret := &Secret{
+		Data:          make(map[string]string, len(data)),
+		LeaseID:       resp.LeaseID,
+		LeaseDuration: time.Duration(resp.LeaseDuration) * time.Second,
+		Renewable:     resp.Renewable,
+	}
+	for k, v := range data {
+		secret.Data[k] = fmt.Sprint(v)
+	}
+
+	return secret, nil
+}
+
+// RenewLease extends a secret lease and returns the granted TTL
+func (p *VaultProvider) RenewLease(ctx context.Context, leaseID string, increment time.Duration) (time.Duration, error) {
+	body := map[string]interface{}{
+		"lease_id":  leaseID,
+		"increment": int(increment.Seconds()),
+	}
+
+	resp, err := p.do(ctx, http.MethodPut, "/v1/sys/leases/renew", body)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(resp.LeaseDuration) * time.Second, nil
+}
+
+// RenewToken extends the provider's own token and re-authenticates once if
+// it has expired. A token that still cannot be renewed after logging in
+// again, such as one that is not renewable, fails rather than logging in
+// again and again.
+func (p *VaultProvider) RenewToken(ctx context.Context) (time.Duration, error) {
+	ttl, err := p.renewSelf(ctx)
+	if err == nil || p.cfg.Role == "" {
+		return ttl, err
+	}
+	if err := p.loginKubernetes(ctx); err != nil {
+		return 0, err
+	}
+	return p.renewSelf(ctx)
+}
+
+func (p *VaultProvider) renewSelf(ctx context.Context) (time.Duration, error) {
+	resp, err := p.do(ctx, http.MethodPost, "/v1/auth/token/renew-self", map[string]interface{}{})
+	if err != nil {
+		return 0, err
+	}
+	if resp.Auth == nil {
+		return 0, nil
+	}
+	return time.Duration(resp.Auth.LeaseDuration) * time.Second, nil
+}
+
+// StartTokenRenewal keeps the provider's token alive until ctx is cancelled
+func (p *VaultProvider) StartTokenRenewal(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ttl, err := p.RenewToken(ctx)
+				if err != nil {
+					p.logger.Error("vault token renewal failed", zap.Error(err))
+					continue
+				}
+				p.logger.Debug("vault token renewed", zap.Duration("ttl", ttl))
+			}
+		}
+	}()
+}
+
+// loginKubernetes exchanges the pod's service account token for a Vault token
+func (p *VaultProvider) loginKubernetes(ctx context.Context) error {
+	jwt, err := os.ReadFile(kubernetesTokenPath)
+	if err != nil {
+		return fmt.Errorf("failed to read service account token: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"role": p.cfg.Role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	}
+
+	resp, err := p.do(ctx, http.MethodPost, fmt.Sprintf("/v1/auth/%s/login", p.cfg.AuthMount), body)
+	if err != nil {
+		return fmt.Errorf("vault kubernetes login failed: %w", err)
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return fmt.Errorf("vault kubernetes login returned no token")
+	}
+
+	p.mu.Lock()
+	p.token = resp.Auth.ClientToken
+	p.mu.Unlock()
+
+	p.logger.Info("authenticated to vault", zap.String("role", p.cfg.Role))
+	return nil
+}
+
+// do executes a Vault API request
+func (p *VaultProvider) do(ctx context.Context, method, path string, body interface{}) (*vaultResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.cfg.Address+path, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	token := p.token
+	p.mu.RUnlock()
+
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if p.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.cfg.Namespace)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result vaultResponse
+	if resp.StatusCode == http.StatusNoContent {
+		return &result, nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("vault returned status %d: %s", resp.StatusCode, strings.Join(result.Errors, "; "))
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,21 @@
+// Package servicetest provides stand-ins for platform services in tests.
+package servicetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Down returns a server that drops every connection without answering,
+// standing in for a service that is down. Closing a server instead frees its
+// port for another test to take. The server is closed when the test ends.
+func Down(t testing.TB) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+			conn.Close()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
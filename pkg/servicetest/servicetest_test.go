@@ -0,0 +1,15 @@
+package servicetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDown_DropsConnections(t *testing.T) {
+	server := Down(t)
+
+	_, err := http.Get(server.URL)
+	assert.Error(t, err)
+}
@@ -0,0 +1,145 @@
+// Package sse reads and writes Server-Sent Events, the framing streamed
+// inferences use from the orchestrator through the router to the gateway's
+// callers.
+//
+// A streamed inference is a sequence of partial events, each carrying an
+// output chunk as the backend produced it, ended by exactly one done event
+// on success or one error event, whose data has the platform's error body
+// (error, code), on failure.
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ContentType is the media type of an event stream
+const ContentType = "text/event-stream"
+
+// Events of a streamed inference
+const (
+	EventPartial = "partial"
+	EventDone    = "done"
+	EventError   = "error"
+)
+
+// maxEventBytes bounds a single line of a stream being read
+const maxEventBytes = 1 << 20
+
+// Event is a single server-sent event
+type Event struct {
+	Name string
+	Data []byte
+}
+
+// Writer sends events to a client, flushing each as it is written
+type Writer struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// NewWriter starts an event stream on w. It fails when w cannot be flushed,
+// since events would otherwise be buffered until the response ends.
+func NewWriter(w http.ResponseWriter) (*Writer, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("response writer does not support flushing")
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", ContentType)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Keep reverse proxies such as nginx from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &Writer{w: w, flusher: flusher}, nil
+}
+
+// Write sends an event as is
+func (w *Writer) Write(event Event) error {
+	var buf bytes.Buffer
+	if event.Name != "" {
+		fmt.Fprintf(&buf, "event: %s\n", event.Name)
+	}
+	for _, line := range strings.Split(string(event.Data), "\n") {
+		fmt.Fprintf(&buf, "data: %s\n", line)
+	}
+	buf.WriteByte('\n')
+
+	if _, err := w.w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	w.flusher.Flush()
+	return nil
+}
+
+// Send sends an event whose data is v encoded as JSON
+func (w *Writer) Send(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", name, err)
+	}
+	return w.Write(Event{Name: name, Data: data})
+}
+
+// Reader reads events from a stream
+type Reader struct {
+	scanner *bufio.Scanner
+}
+
+// NewReader reads events from r
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventBytes)
+	return &Reader{scanner: scanner}
+}
+
+// Next returns the next event. It returns io.EOF when the stream ends
+// between events and io.ErrUnexpectedEOF when it ends within one. Comments
+// and fields other than event and data are skipped.
+func (r *Reader) Next() (Event, error) {
+	var event Event
+	var data []string
+	started := false
+
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if line == "" {
+			if !started {
+				continue
+			}
+			event.Data = []byte(strings.Join(data, "\n"))
+			return event, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Name = value
+			started = true
+		case "data":
+			data = append(data, value)
+			started = true
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	if started {
+		return Event{}, io.ErrUnexpectedEOF
+	}
+	return Event{}, io.EOF
+}
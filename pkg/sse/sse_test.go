@@ -0,0 +1,63 @@
+package sse
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_SendsEvents(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writer, err := NewWriter(recorder)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Send(EventPartial, map[string]string{"text": "Hel"}))
+	require.NoError(t, writer.Write(Event{Name: EventDone, Data: []byte("line1\nline2")}))
+
+	assert.Equal(t, ContentType, recorder.Header().Get("Content-Type"))
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, "event: partial\ndata: {\"text\":\"Hel\"}\n\nevent: done\ndata: line1\ndata: line2\n\n", recorder.Body.String())
+}
+
+func TestReader_RoundTrip(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writer, err := NewWriter(recorder)
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(Event{Name: EventPartial, Data: []byte(`{"text":"a"}`)}))
+	require.NoError(t, writer.Write(Event{Name: EventDone, Data: []byte("x\ny")}))
+
+	reader := NewReader(recorder.Body)
+	event, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, Event{Name: EventPartial, Data: []byte(`{"text":"a"}`)}, event)
+
+	event, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, Event{Name: EventDone, Data: []byte("x\ny")}, event)
+
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReader_UnnamedEventsAndComments(t *testing.T) {
+	reader := NewReader(strings.NewReader(": keep-alive\n\ndata:{\"text_output\":\"hi\"}\nid: 1\n\n"))
+
+	event, err := reader.Next()
+	require.NoError(t, err)
+	assert.Empty(t, event.Name)
+	assert.Equal(t, `{"text_output":"hi"}`, string(event.Data))
+
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReader_TruncatedEvent(t *testing.T) {
+	reader := NewReader(strings.NewReader("event: partial\ndata: {\"te"))
+
+	_, err := reader.Next()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
@@ -0,0 +1,175 @@
+package tenancy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// DefaultCacheTTL is how long lookups are reused before the tenant service is asked again
+const DefaultCacheTTL = 30 * time.Second
+
+// entry is a cached lookup; not-found results are cached too
+type entry struct {
+	value   interface{}
+	err     error
+	fetched time.Time
+}
+
+// Client looks up tenants, memberships and API keys in the tenant service.
+// Results are cached for the TTL, and expired results keep being served while
+// the tenant service is unreachable so a brief outage does not lock tenants out.
+type Client struct {
+	baseURL string
+	client  *http.Client
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]entry
+}
+
+// NewClient creates a client for the tenant service at baseURL
+func NewClient(baseURL string, client *http.Client, ttl time.Duration) *Client {
+	return &Client{
+		baseURL: baseURL,
+		client:  client,
+		ttl:     ttl,
+		now:     time.Now,
+		cache:   make(map[string]entry),
+	}
+}
+
+// Tenant returns a tenant with its effective limits
+func (c *Client) Tenant(ctx context.Context, id string) (*Tenant, error) {
+	value, err := c.lookup(ctx, "tenant|"+id, func(ctx context.Context) (interface{}, error) {
+		var tenant Tenant
+		err := c.do(ctx, http.MethodGet, "/v1/tenants/"+url.PathEscape(id), nil, &tenant)
+		return &tenant, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*Tenant), nil
+}
+
+// Authorize returns a tenant that may use the platform. Unknown and suspended
+// tenants are denied.
+func (c *Client) Authorize(ctx context.Context, id string) (*Tenant, error) {
+	tenant, err := c.Tenant(ctx, id)
+	if apperrors.Is(err, apperrors.NotFound) {
+		return nil, apperrors.Newf(apperrors.PermissionDenied, "unknown tenant %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tenant.Status != StatusActive {
+		return nil, apperrors.Newf(apperrors.PermissionDenied, "tenant %s is %s", id, tenant.Status)
+	}
+	return tenant, nil
+}
+
+// Member returns a user's membership of a tenant, denying users who are not members
+func (c *Client) Member(ctx context.Context, tenantID, userID string) (*Member, error) {
+	value, err := c.lookup(ctx, "member|"+tenantID+"|"+userID, func(ctx context.Context) (interface{}, error) {
+		var member Member
+		err := c.do(ctx, http.MethodGet, "/v1/tenants/"+url.PathEscape(tenantID)+"/members/"+url.PathEscape(userID), nil, &member)
+		return &member, err
+	})
+	if apperrors.Is(err, apperrors.NotFound) {
+		return nil, apperrors.Newf(apperrors.PermissionDenied, "user %s is not a member of tenant %s", userID, tenantID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value.(*Member), nil
+}
+
+// Verify resolves an API key to the principal it acts as. Unknown and revoked
+// keys are unauthenticated.
+func (c *Client) Verify(ctx context.Context, key string) (*Principal, error) {
+	value, err := c.lookup(ctx, "key|"+HashKey(key), func(ctx context.Context) (interface{}, error) {
+		var principal Principal
+		err := c.do(ctx, http.MethodPost, "/v1/keys/verify", map[string]string{"key": key}, &principal)
+		return &principal, err
+	})
+	if apperrors.Is(err, apperrors.NotFound) {
+		return nil, apperrors.New(apperrors.Unauthenticated, "invalid api key")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value.(*Principal), nil
+}
+
+// lookup returns a cached value or fetches it
+func (c *Client) lookup(ctx context.Context, key string, fetch func(context.Context) (interface{}, error)) (interface{}, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	cached, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok && now.Sub(cached.fetched) < c.ttl {
+		return cached.value, cached.err
+	}
+
+	value, err := fetch(ctx)
+	if err != nil && !apperrors.Is(err, apperrors.NotFound) {
+		// Serve the last answer while the tenant service is unreachable
+		if ok && apperrors.Retryable(err) {
+			return cached.value, cached.err
+		}
+		return nil, err
+	}
+	if err != nil {
+		value = nil
+	}
+
+	c.mu.Lock()
+	c.cache[key] = entry{value: value, err: err, fetched: now}
+	c.mu.Unlock()
+	return value, err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	logging.Inject(ctx, req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "tenant-service")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.FromHTTPResponse(resp, "tenant-service")
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode tenant-service response: %w", err)
+	}
+	return nil
+}
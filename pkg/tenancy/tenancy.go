@@ -0,0 +1,214 @@
+// Package tenancy describes tenants, their members, API keys and limits, and
+// provides the cached client services use to consult the tenant service
+package tenancy
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Tier is a tenant's plan; it sets the default limits
+type Tier string
+
+const (
+	TierFree       Tier = "free"
+	TierPro        Tier = "pro"
+	TierEnterprise Tier = "enterprise"
+)
+
+// Valid reports whether t is a known tier
+func (t Tier) Valid() bool {
+	_, ok := tierLimits[t]
+	return ok
+}
+
+// Status says whether a tenant may use the platform
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusSuspended Status = "suspended"
+)
+
+// Valid reports whether s is a known status
+func (s Status) Valid() bool {
+	return s == StatusActive || s == StatusSuspended
+}
+
+// Role is what a member or API key may do within a tenant
+type Role string
+
+const (
+	// RoleOwner and RoleAdmin manage the tenant's projects, members and keys
+	RoleOwner Role = "owner"
+	RoleAdmin Role = "admin"
+	// RoleMember runs inference and manages models
+	RoleMember Role = "member"
+	// RoleViewer has read-only access
+	RoleViewer Role = "viewer"
+)
+
+// Valid reports whether r is a known role
+func (r Role) Valid() bool {
+	switch r {
+	case RoleOwner, RoleAdmin, RoleMember, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// CanWrite reports whether r may submit work or change resources
+func (r Role) CanWrite() bool {
+	return r.Valid() && r != RoleViewer
+}
+
+// CanManage reports whether r may manage the tenant itself
+func (r Role) CanManage() bool {
+	return r == RoleOwner || r == RoleAdmin
+}
+
+// Limits bound what a tenant may use. Zero means unlimited.
+type Limits struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	MaxBatchItems     int `json:"max_batch_items"`
+	MaxModels         int `json:"max_models"`
+	// Monthly quotas, counted per calendar month in UTC
+	MonthlyRequests   int `json:"monthly_requests"`
+	MonthlyBatchItems int `json:"monthly_batch_items"`
+}
+
+// tierLimits are the defaults each tier grants
+var tierLimits = map[Tier]Limits{
+	TierFree:       {RequestsPerMinute: 60, MaxBatchItems: 100, MaxModels: 5, MonthlyRequests: 100000, MonthlyBatchItems: 10000},
+	TierPro:        {RequestsPerMinute: 600, MaxBatchItems: 10000, MaxModels: 50, MonthlyRequests: 10000000, MonthlyBatchItems: 1000000},
+	TierEnterprise: {RequestsPerMinute: 6000},
+}
+
+// TierLimits returns the default limits of a tier
+func TierLimits(tier Tier) Limits {
+	return tierLimits[tier]
+}
+
+// Merge applies quota overrides to limits. Zero overrides keep the limit and
+// negative overrides lift it.
+func (l Limits) Merge(overrides Limits) Limits {
+	pick := func(limit, override int) int {
+		switch {
+		case override < 0:
+			return 0
+		case override > 0:
+			return override
+		}
+		return limit
+	}
+	return Limits{
+		RequestsPerMinute: pick(l.RequestsPerMinute, overrides.RequestsPerMinute),
+		MaxBatchItems:     pick(l.MaxBatchItems, overrides.MaxBatchItems),
+		MaxModels:         pick(l.MaxModels, overrides.MaxModels),
+		MonthlyRequests:   pick(l.MonthlyRequests, overrides.MonthlyRequests),
+		MonthlyBatchItems: pick(l.MonthlyBatchItems, overrides.MonthlyBatchItems),
+	}
+}
+
+// Tenant is an organization using the platform
+type Tenant struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Tier   Tier   `json:"tier"`
+	Status Status `json:"status"`
+	// Quotas override the tier's limits for this tenant
+	Quotas Limits `json:"quotas"`
+	// Limits are the limits in effect, filled in by the tenant service
+	Limits    Limits    `json:"limits"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Effective returns the tier's limits with the tenant's overrides applied
+func (t *Tenant) Effective() Limits {
+	return TierLimits(t.Tier).Merge(t.Quotas)
+}
+
+// Project groups a tenant's API keys and workloads
+type Project struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Member is a user's membership of a tenant
+type Member struct {
+	TenantID  string    `json:"tenant_id"`
+	UserID    string    `json:"user_id"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// APIKey describes an API key; the key itself is only shown when created
+type APIKey struct {
+	ID        string     `json:"id"`
+	TenantID  string     `json:"tenant_id"`
+	ProjectID string     `json:"project_id,omitempty"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Role      Role       `json:"role"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Principal is who a verified API key acts as
+type Principal struct {
+	KeyID     string `json:"key_id"`
+	TenantID  string `json:"tenant_id"`
+	ProjectID string `json:"project_id,omitempty"`
+	Role      Role   `json:"role"`
+}
+
+// idPattern matches tenant IDs, which appear in JWT claims, headers and topics
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+// ValidID reports whether id may name a tenant
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// KeyPrefix starts every API key so they can be told apart from JWTs
+const KeyPrefix = "aip_"
+
+// keyDisplayLength is how much of a key is kept to identify it in listings
+const keyDisplayLength = len(KeyPrefix) + 8
+
+// NewKey generates an API key
+func NewKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return KeyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// IsKey reports whether a bearer token is an API key
+func IsKey(token string) bool {
+	return strings.HasPrefix(token, KeyPrefix)
+}
+
+// HashKey returns the digest API keys are stored and looked up by
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// DisplayPrefix returns the part of a key shown in listings
+func DisplayPrefix(key string) string {
+	if len(key) <= keyDisplayLength {
+		return key
+	}
+	return key[:keyDisplayLength]
+}
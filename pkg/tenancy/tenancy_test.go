@@ -0,0 +1,145 @@
+package tenancy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestLimits_Merge(t *testing.T) {
+	limits := TierLimits(TierFree).Merge(Limits{MaxBatchItems: 500, MaxModels: -1, MonthlyRequests: -1})
+	assert.Equal(t, Limits{RequestsPerMinute: 60, MaxBatchItems: 500, MaxModels: 0, MonthlyRequests: 0, MonthlyBatchItems: 10000}, limits)
+
+	tenant := &Tenant{Tier: TierEnterprise, Quotas: Limits{RequestsPerMinute: 100}}
+	assert.Equal(t, Limits{RequestsPerMinute: 100}, tenant.Effective())
+}
+
+func TestRoles(t *testing.T) {
+	assert.True(t, RoleMember.CanWrite())
+	assert.False(t, RoleViewer.CanWrite())
+	assert.False(t, Role("root").CanWrite())
+	assert.True(t, RoleAdmin.CanManage())
+	assert.False(t, RoleMember.CanManage())
+}
+
+func TestValidID(t *testing.T) {
+	assert.True(t, ValidID("acme"))
+	assert.True(t, ValidID("acme-labs-2"))
+	assert.False(t, ValidID("Acme"))
+	assert.False(t, ValidID("-acme"))
+	assert.False(t, ValidID("a"))
+}
+
+func TestKeys(t *testing.T) {
+	key, err := NewKey()
+	require.NoError(t, err)
+	other, err := NewKey()
+	require.NoError(t, err)
+
+	assert.True(t, IsKey(key))
+	assert.False(t, IsKey("eyJhbGciOiJIUzI1NiJ9.e30.sig"))
+	assert.NotEqual(t, key, other)
+	assert.Equal(t, HashKey(key), HashKey(key))
+	assert.NotEqual(t, HashKey(key), HashKey(other))
+	assert.Len(t, DisplayPrefix(key), len(KeyPrefix)+8)
+}
+
+func newTenantServer(t *testing.T, calls *int32, down *atomic.Bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if down.Load() {
+			apperrors.WriteHTTP(w, apperrors.New(apperrors.Unavailable, "database unavailable"))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/tenants/acme":
+			json.NewEncoder(w).Encode(Tenant{ID: "acme", Tier: TierPro, Status: StatusActive})
+		case "/v1/tenants/frozen":
+			json.NewEncoder(w).Encode(Tenant{ID: "frozen", Tier: TierFree, Status: StatusSuspended})
+		case "/v1/tenants/acme/members/alice":
+			json.NewEncoder(w).Encode(Member{TenantID: "acme", UserID: "alice", Role: RoleMember})
+		case "/v1/keys/verify":
+			var body struct {
+				Key string `json:"key"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body.Key != "aip_good" {
+				apperrors.WriteHTTP(w, apperrors.New(apperrors.NotFound, "api key not found"))
+				return
+			}
+			json.NewEncoder(w).Encode(Principal{KeyID: "k1", TenantID: "acme", Role: RoleMember})
+		default:
+			apperrors.WriteHTTP(w, apperrors.New(apperrors.NotFound, "not found"))
+		}
+	}))
+}
+
+func TestClient_AuthorizesAndCaches(t *testing.T) {
+	var calls int32
+	var down atomic.Bool
+	server := newTenantServer(t, &calls, &down)
+	defer server.Close()
+	client := NewClient(server.URL, server.Client(), time.Minute)
+	ctx := context.Background()
+
+	tenant, err := client.Authorize(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, TierPro, tenant.Tier)
+	_, err = client.Authorize(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	_, err = client.Authorize(ctx, "frozen")
+	assert.True(t, apperrors.Is(err, apperrors.PermissionDenied))
+
+	_, err = client.Authorize(ctx, "nobody")
+	assert.True(t, apperrors.Is(err, apperrors.PermissionDenied))
+	_, err = client.Authorize(ctx, "nobody")
+	assert.True(t, apperrors.Is(err, apperrors.PermissionDenied))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls), "unknown tenants are cached too")
+
+	member, err := client.Member(ctx, "acme", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, RoleMember, member.Role)
+	_, err = client.Member(ctx, "acme", "mallory")
+	assert.True(t, apperrors.Is(err, apperrors.PermissionDenied))
+
+	principal, err := client.Verify(ctx, "aip_good")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", principal.TenantID)
+	_, err = client.Verify(ctx, "aip_bad")
+	assert.True(t, apperrors.Is(err, apperrors.Unauthenticated))
+}
+
+func TestClient_ServesStaleWhileUnavailable(t *testing.T) {
+	var calls int32
+	var down atomic.Bool
+	server := newTenantServer(t, &calls, &down)
+	defer server.Close()
+	client := NewClient(server.URL, server.Client(), time.Minute)
+	now := time.Now()
+	client.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := client.Authorize(ctx, "acme")
+	require.NoError(t, err)
+
+	down.Store(true)
+	now = now.Add(2 * time.Minute)
+	tenant, err := client.Authorize(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenant.ID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	_, err = client.Authorize(ctx, "other")
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable))
+}
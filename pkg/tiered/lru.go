@@ -0,0 +1,88 @@
+package tiered
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// entry is a value held by the local tier
+type entry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// lru is a fixed-size, least recently used map of entries
+type lru struct {
+	mu    sync.Mutex
+	size  int
+	items map[string]*list.Element
+	order *list.List // front is most recently used
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:  size,
+		items: make(map[string]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+func (l *lru) get(key string) (entry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.items[key]
+	if !ok {
+		return entry{}, false
+	}
+	l.order.MoveToFront(element)
+	return *element.Value.(*entry), true
+}
+
+func (l *lru) set(key string, value []byte, expires time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.items[key]; ok {
+		e := element.Value.(*entry)
+		e.value = value
+		e.expires = expires
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&entry{key: key, value: value, expires: expires})
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*entry).key)
+	}
+}
+
+func (l *lru) delete(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := l.items[key]; ok {
+			l.order.Remove(element)
+			delete(l.items, key)
+		}
+	}
+}
+
+func (l *lru) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.items = make(map[string]*list.Element, l.size)
+	l.order.Init()
+}
+
+func (l *lru) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
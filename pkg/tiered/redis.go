@@ -0,0 +1,35 @@
+package tiered
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisRemote keeps the remote tier in Redis
+type redisRemote struct {
+	client *redis.Client
+}
+
+// Redis keeps the remote tier in Redis, shared by every replica of a service
+func Redis(client *redis.Client) Remote {
+	return &redisRemote{client: client}
+}
+
+func (r *redisRemote) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return data, err
+}
+
+func (r *redisRemote) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (r *redisRemote) Delete(ctx context.Context, keys ...string) error {
+	return r.client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,264 @@
+// Package tiered is a two-tier cache: a small in-process LRU with a short TTL
+// in front of a shared remote cache such as Redis. When the remote tier
+// fails, lookups are answered from the local tier, whose entries are served
+// for a while past their TTL, so an outage degrades to slightly stale data
+// rather than failed lookups or a stampede on the database behind the cache.
+package tiered
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrMiss is returned by a remote tier that does not hold a key
+var ErrMiss = errors.New("cache miss")
+
+// Remote is the shared cache tier
+type Remote interface {
+	// Get returns the value stored under key, or ErrMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Defaults for options left zero
+const (
+	DefaultLocalSize     = 10000
+	DefaultLocalTTL      = 30 * time.Second
+	DefaultStaleTTL      = 5 * time.Minute
+	DefaultRemoteTTL     = 15 * time.Minute
+	DefaultRetryInterval = 5 * time.Second
+)
+
+// Options configure the cache. Zero values take the defaults.
+type Options struct {
+	// LocalSize is how many entries each process keeps
+	LocalSize int
+	// LocalTTL is how long a local entry is used before the remote tier is
+	// asked again. It bounds how stale one replica's view is after another
+	// replica changes an entry.
+	LocalTTL time.Duration
+	// StaleTTL is how much longer local entries are served while the remote
+	// tier is unavailable
+	StaleTTL time.Duration
+	// RemoteTTL is how long entries live in the remote tier
+	RemoteTTL time.Duration
+	// RetryInterval is how long the remote tier is left alone after it fails
+	RetryInterval time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.LocalSize <= 0 {
+		o.LocalSize = DefaultLocalSize
+	}
+	if o.LocalTTL <= 0 {
+		o.LocalTTL = DefaultLocalTTL
+	}
+	if o.StaleTTL < 0 {
+		o.StaleTTL = 0
+	} else if o.StaleTTL == 0 {
+		o.StaleTTL = DefaultStaleTTL
+	}
+	if o.RemoteTTL <= 0 {
+		o.RemoteTTL = DefaultRemoteTTL
+	}
+	if o.RetryInterval <= 0 {
+		o.RetryInterval = DefaultRetryInterval
+	}
+	return o
+}
+
+// Cache reads through the local tier to the remote one. Values returned by
+// Get are shared and must not be modified.
+type Cache struct {
+	remote Remote
+	local  *lru
+	opts   Options
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu        sync.Mutex
+	down      bool
+	downUntil time.Time
+	// pending are remote deletes that failed, retried once the remote tier
+	// recovers so it does not serve entries that were invalidated
+	pending map[string]struct{}
+}
+
+// New creates a cache in front of remote
+func New(remote Remote, opts Options, logger *zap.Logger) *Cache {
+	opts = opts.withDefaults()
+	return &Cache{
+		remote:  remote,
+		local:   newLRU(opts.LocalSize),
+		opts:    opts,
+		logger:  logger,
+		now:     time.Now,
+		pending: make(map[string]struct{}),
+	}
+}
+
+// Get returns the value stored under key and whether there was one
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool) {
+	now := c.now()
+	local, ok := c.local.get(key)
+	if ok && now.Before(local.expires) {
+		return local.value, true
+	}
+
+	if c.remoteUp(ctx, now) {
+		value, err := c.remote.Get(ctx, key)
+		switch {
+		case err == nil:
+			c.recovered()
+			c.local.set(key, value, now.Add(c.opts.LocalTTL))
+			return value, true
+		case errors.Is(err, ErrMiss):
+			c.recovered()
+			c.local.delete(key)
+			return nil, false
+		default:
+			c.failed(ctx, err)
+		}
+	}
+
+	// The remote tier is unavailable; serve what this process last saw
+	if ok && now.Before(local.expires.Add(c.opts.StaleTTL)) {
+		return local.value, true
+	}
+	if ok {
+		c.local.delete(key)
+	}
+	return nil, false
+}
+
+// Set stores value under key in both tiers. While the remote tier is
+// unavailable the value is only kept locally.
+func (c *Cache) Set(ctx context.Context, key string, value []byte) {
+	c.SetTTL(ctx, key, value, c.opts.RemoteTTL)
+}
+
+// SetTTL is Set for an entry that lives in the remote tier for ttl rather
+// than RemoteTTL. The local tier keeps it for no longer than ttl either.
+func (c *Cache) SetTTL(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	now := c.now()
+	localTTL := c.opts.LocalTTL
+	if ttl < localTTL {
+		localTTL = ttl
+	}
+	c.local.set(key, value, now.Add(localTTL))
+
+	if !c.remoteUp(ctx, now) {
+		return
+	}
+	if err := c.remote.Set(ctx, key, value, ttl); err != nil {
+		c.failed(ctx, err)
+		return
+	}
+	c.recovered()
+}
+
+// Delete removes keys from both tiers. Deletes the remote tier misses while
+// it is unavailable are applied when it recovers.
+func (c *Cache) Delete(ctx context.Context, keys ...string) {
+	c.local.delete(keys...)
+
+	if c.remoteUp(ctx, c.now()) {
+		err := c.remote.Delete(ctx, keys...)
+		if err == nil {
+			c.recovered()
+			return
+		}
+		c.failed(ctx, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if len(c.pending) >= c.opts.LocalSize {
+			c.logger.Warn("too many pending cache deletes; remote entries may be served until they expire",
+				zap.Int("pending", len(c.pending)),
+			)
+			return
+		}
+		c.pending[key] = struct{}{}
+	}
+}
+
+// Clear drops every local entry
+func (c *Cache) Clear() {
+	c.local.clear()
+}
+
+// Degraded reports whether the remote tier is currently being bypassed
+func (c *Cache) Degraded() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.down
+}
+
+// remoteUp reports whether the remote tier should be tried: it has not
+// failed, or it failed at least a retry interval ago. Before the remote tier
+// is used again, the deletes it missed are applied.
+func (c *Cache) remoteUp(ctx context.Context, now time.Time) bool {
+	c.mu.Lock()
+	if !c.down {
+		c.mu.Unlock()
+		return true
+	}
+	if now.Before(c.downUntil) {
+		c.mu.Unlock()
+		return false
+	}
+	keys := make([]string, 0, len(c.pending))
+	for key := range c.pending {
+		keys = append(keys, key)
+	}
+	c.mu.Unlock()
+
+	if len(keys) == 0 {
+		return true
+	}
+	if err := c.remote.Delete(ctx, keys...); err != nil {
+		c.failed(ctx, err)
+		return false
+	}
+
+	c.mu.Lock()
+	for _, key := range keys {
+		delete(c.pending, key)
+	}
+	c.mu.Unlock()
+	c.logger.Info("applied cache deletes missed during the remote cache outage", zap.Int("keys", len(keys)))
+	return true
+}
+
+// failed bypasses the remote tier for a retry interval. Failures caused by
+// the caller giving up say nothing about the remote tier.
+func (c *Cache) failed(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.down {
+		c.logger.Warn("remote cache unavailable, serving from the local tier", zap.Error(err))
+	}
+	c.down = true
+	c.downUntil = c.now().Add(c.opts.RetryInterval)
+}
+
+// recovered resumes using the remote tier
+func (c *Cache) recovered() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.down {
+		c.down = false
+		c.logger.Info("remote cache recovered")
+	}
+}
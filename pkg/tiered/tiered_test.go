@@ -0,0 +1,194 @@
+package tiered
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeRemote is a remote tier that can be taken down
+type fakeRemote struct {
+	values  map[string][]byte
+	ttls    map[string]time.Duration
+	down    bool
+	gets    int
+	deleted []string
+}
+
+var errDown = errors.New("connection refused")
+
+func newFakeRemote() *fakeRemote {
+	return &fakeRemote{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (r *fakeRemote) Get(ctx context.Context, key string) ([]byte, error) {
+	r.gets++
+	if r.down {
+		return nil, errDown
+	}
+	value, ok := r.values[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+	return value, nil
+}
+
+func (r *fakeRemote) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if r.down {
+		return errDown
+	}
+	r.values[key] = value
+	r.ttls[key] = ttl
+	return nil
+}
+
+func (r *fakeRemote) Delete(ctx context.Context, keys ...string) error {
+	if r.down {
+		return errDown
+	}
+	for _, key := range keys {
+		delete(r.values, key)
+		r.deleted = append(r.deleted, key)
+	}
+	return nil
+}
+
+// newTestCache returns a cache on a controllable clock
+func newTestCache(remote Remote, opts Options) (*Cache, *time.Time) {
+	cache := New(remote, opts, zap.NewNop())
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func TestCache_ReadsThroughLocalTier(t *testing.T) {
+	remote := newFakeRemote()
+	remote.values["a"] = []byte("1")
+	cache, now := newTestCache(remote, Options{LocalTTL: 30 * time.Second})
+	ctx := context.Background()
+
+	value, ok := cache.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 1, remote.gets)
+
+	// Fresh local entries are served without asking the remote tier
+	cache.Get(ctx, "a")
+	assert.Equal(t, 1, remote.gets)
+
+	// Expired ones are refreshed from it
+	remote.values["a"] = []byte("2")
+	*now = now.Add(31 * time.Second)
+	value, _ = cache.Get(ctx, "a")
+	assert.Equal(t, []byte("2"), value)
+	assert.Equal(t, 2, remote.gets)
+
+	_, ok = cache.Get(ctx, "missing")
+	assert.False(t, ok)
+}
+
+func TestCache_SetTTL(t *testing.T) {
+	remote := newFakeRemote()
+	cache, now := newTestCache(remote, Options{LocalTTL: 30 * time.Second, RemoteTTL: time.Hour})
+	ctx := context.Background()
+
+	cache.Set(ctx, "a", []byte("1"))
+	cache.SetTTL(ctx, "b", []byte("2"), 10*time.Second)
+	assert.Equal(t, time.Hour, remote.ttls["a"])
+	assert.Equal(t, 10*time.Second, remote.ttls["b"])
+
+	// The local tier does not keep an entry past its TTL either
+	delete(remote.values, "b")
+	*now = now.Add(11 * time.Second)
+	_, ok := cache.Get(ctx, "b")
+	assert.False(t, ok)
+	_, ok = cache.Get(ctx, "a")
+	assert.True(t, ok)
+}
+
+func TestCache_ServesStaleEntriesWhileRemoteIsDown(t *testing.T) {
+	remote := newFakeRemote()
+	cache, now := newTestCache(remote, Options{LocalTTL: 30 * time.Second, StaleTTL: 5 * time.Minute, RetryInterval: 10 * time.Second})
+	ctx := context.Background()
+
+	cache.Set(ctx, "a", []byte("1"))
+	remote.down = true
+
+	*now = now.Add(time.Minute)
+	value, ok := cache.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.True(t, cache.Degraded())
+
+	// The remote tier is left alone until the retry interval passes
+	gets := remote.gets
+	*now = now.Add(5 * time.Second)
+	cache.Get(ctx, "a")
+	assert.Equal(t, gets, remote.gets)
+
+	// Writes during the outage are still served locally
+	cache.Set(ctx, "b", []byte("2"))
+	value, ok = cache.Get(ctx, "b")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("2"), value)
+
+	// Entries too stale to serve are misses
+	*now = now.Add(10 * time.Minute)
+	_, ok = cache.Get(ctx, "a")
+	assert.False(t, ok)
+	assert.Greater(t, remote.gets, gets)
+}
+
+func TestCache_AppliesMissedDeletesOnRecovery(t *testing.T) {
+	remote := newFakeRemote()
+	cache, now := newTestCache(remote, Options{RetryInterval: 10 * time.Second})
+	ctx := context.Background()
+
+	cache.Set(ctx, "a", []byte("1"))
+	remote.down = true
+	cache.Delete(ctx, "a")
+	_, ok := cache.Get(ctx, "a")
+	assert.False(t, ok)
+	assert.Equal(t, []byte("1"), remote.values["a"])
+
+	remote.down = false
+	*now = now.Add(11 * time.Second)
+	_, ok = cache.Get(ctx, "a")
+	assert.False(t, ok)
+	assert.False(t, cache.Degraded())
+	assert.NotContains(t, remote.values, "a")
+	assert.Equal(t, []string{"a"}, remote.deleted)
+}
+
+func TestCache_CanceledRequestsDoNotTripTheRemote(t *testing.T) {
+	remote := newFakeRemote()
+	remote.down = true
+	cache, _ := newTestCache(remote, Options{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cache.Get(ctx, "a")
+	assert.False(t, cache.Degraded())
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	l := newLRU(2)
+	expires := time.Now().Add(time.Minute)
+	l.set("a", []byte("1"), expires)
+	l.set("b", []byte("2"), expires)
+	l.get("a")
+	l.set("c", []byte("3"), expires)
+
+	_, ok := l.get("b")
+	assert.False(t, ok)
+	_, ok = l.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, l.len())
+
+	l.clear()
+	assert.Equal(t, 0, l.len())
+}
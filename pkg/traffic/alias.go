@@ -0,0 +1,33 @@
+package traffic
+
+import (
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Alias names a version of Model that requests may ask for in its stead,
+// such as "stable" or "latest". Repointing the alias moves its requests to
+// another version without clients changing the version they request.
+type Alias struct {
+	Model   string `json:"model"`
+	Name    string `json:"name"`
+	Version string `json:"version" binding:"required"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks that the alias has a name and points at a version other
+// than itself
+func (a Alias) Validate() error {
+	if a.Name == "" {
+		return apperrors.New(apperrors.InvalidArgument, "alias has no name")
+	}
+	if a.Version == "" {
+		return apperrors.Newf(apperrors.InvalidArgument, "alias %q has no version", a.Name)
+	}
+	if a.Version == a.Name {
+		return apperrors.Newf(apperrors.InvalidArgument, "alias %q points at itself", a.Name)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package traffic
+
+import (
+	"strings"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Fallback is the model version, such as a smaller model served on CPUs,
+// that answers a model version's requests while none of its own backends can
+type Fallback struct {
+	Model   string `json:"model"`
+	Version string `json:"version"`
+}
+
+// ParseFallback parses a fallback given as "model/version"
+func ParseFallback(value string) (Fallback, error) {
+	model, version, ok := strings.Cut(value, "/")
+	if !ok || model == "" || version == "" {
+		return Fallback{}, apperrors.Newf(apperrors.InvalidArgument, "fallback %q is not of the form model/version", value)
+	}
+	return Fallback{Model: model, Version: version}, nil
+}
+
+// String returns the fallback as "model/version"
+func (f Fallback) String() string {
+	return f.Model + "/" + f.Version
+}
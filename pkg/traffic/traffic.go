@@ -0,0 +1,68 @@
+// Package traffic describes how the model router splits the requests for a
+// model version between versions, and the aliases requests may name versions
+// by, so a new version can be canaried or promoted without clients changing
+// the version they request, and the fallbacks serving a version's requests
+// while it is down.
+package traffic
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Split sends the requests for Version of Model to versions in proportion to
+// Weights, in percent. Requests naming any other version are not split.
+type Split struct {
+	Model   string         `json:"model"`
+	Version string         `json:"version" binding:"required"`
+	Weights map[string]int `json:"weights" binding:"required"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks that the split names the version it applies to and that
+// its weights are percentages adding up to 100
+func (s Split) Validate() error {
+	if s.Version == "" {
+		return apperrors.New(apperrors.InvalidArgument, "traffic split has no version")
+	}
+	if len(s.Weights) == 0 {
+		return apperrors.New(apperrors.InvalidArgument, "traffic split has no weights")
+	}
+
+	total := 0
+	for version, weight := range s.Weights {
+		if version == "" || weight < 0 || weight > 100 {
+			return apperrors.Newf(apperrors.InvalidArgument, "invalid weight %d for version %q", weight, version)
+		}
+		total += weight
+	}
+	if total != 100 {
+		return apperrors.Newf(apperrors.InvalidArgument, "traffic split weights add up to %d, not 100", total)
+	}
+	return nil
+}
+
+// Versions returns the versions the split sends requests to, in order
+func (s Split) Versions() []string {
+	versions := make([]string, 0, len(s.Weights))
+	for version := range s.Weights {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// Pick returns the version a request is sent to, given a percentile drawn
+// uniformly from [0, 100)
+func (s Split) Pick(percentile int) string {
+	for _, version := range s.Versions() {
+		if percentile < s.Weights[version] {
+			return version
+		}
+		percentile -= s.Weights[version]
+	}
+	return s.Version
+}
@@ -0,0 +1,65 @@
+package traffic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit_Validate(t *testing.T) {
+	valid := Split{Model: "resnet18", Version: "v1", Weights: map[string]int{"v1": 95, "v2": 5}}
+	assert.NoError(t, valid.Validate())
+
+	for name, split := range map[string]Split{
+		"no version":       {Weights: map[string]int{"v1": 100}},
+		"no weights":       {Version: "v1"},
+		"negative weight":  {Version: "v1", Weights: map[string]int{"v1": 105, "v2": -5}},
+		"short of 100":     {Version: "v1", Weights: map[string]int{"v1": 90, "v2": 5}},
+		"unnamed version":  {Version: "v1", Weights: map[string]int{"v1": 95, "": 5}},
+		"weight above 100": {Version: "v1", Weights: map[string]int{"v1": 101}},
+	} {
+		assert.Error(t, split.Validate(), name)
+	}
+}
+
+func TestSplit_Pick(t *testing.T) {
+	split := Split{Version: "v1", Weights: map[string]int{"v1": 95, "v2": 5}}
+
+	picked := make(map[string]int)
+	for percentile := 0; percentile < 100; percentile++ {
+		picked[split.Pick(percentile)]++
+	}
+	assert.Equal(t, map[string]int{"v1": 95, "v2": 5}, picked)
+}
+
+func TestSplit_PickSkipsZeroWeights(t *testing.T) {
+	split := Split{Version: "v1", Weights: map[string]int{"v1": 0, "v2": 100}}
+
+	for percentile := 0; percentile < 100; percentile++ {
+		assert.Equal(t, "v2", split.Pick(percentile))
+	}
+}
+
+func TestAlias_Validate(t *testing.T) {
+	assert.NoError(t, Alias{Model: "resnet18", Name: "stable", Version: "v2"}.Validate())
+
+	for name, alias := range map[string]Alias{
+		"no name":    {Version: "v2"},
+		"no version": {Name: "stable"},
+		"itself":     {Name: "v2", Version: "v2"},
+	} {
+		assert.Error(t, alias.Validate(), name)
+	}
+}
+
+func TestParseFallback(t *testing.T) {
+	fallback, err := ParseFallback("resnet18-cpu/v1")
+	assert.NoError(t, err)
+	assert.Equal(t, Fallback{Model: "resnet18-cpu", Version: "v1"}, fallback)
+	assert.Equal(t, "resnet18-cpu/v1", fallback.String())
+
+	for _, value := range []string{"", "resnet18-cpu", "/v1", "resnet18-cpu/"} {
+		_, err := ParseFallback(value)
+		assert.Error(t, err, value)
+	}
+}
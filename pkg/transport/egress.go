@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// nonPublicNetworks are reserved ranges outside the ones net.IP classifies:
+// shared address space used by cluster and carrier networks, "this" network,
+// IETF protocol assignments, benchmarking and the reserved class E block
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"100.64.0.0/10",
+	"192.0.0.0/24",
+	"198.18.0.0/15",
+	"240.0.0.0/4",
+)
+
+// clusterSuffixes are host names that only resolve inside the cluster or
+// on the host itself
+var clusterSuffixes = []string{".localhost", ".local", ".internal", ".svc", ".cluster.local"}
+
+// IsPublicIP reports whether ip is routable on the internet, rejecting
+// loopback, private (RFC 1918 and unique local), link-local including the
+// cloud metadata address 169.254.169.254, multicast and reserved addresses
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckPublicHost rejects a URL host that is an address or name internal to
+// the platform. Names are not resolved here; PublicHTTPClient checks the
+// addresses they resolve to when it connects.
+func CheckPublicHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicIP(ip) {
+			return apperrors.Newf(apperrors.InvalidArgument, "%s is not a public address", host)
+		}
+		return nil
+	}
+	if host == "localhost" || !strings.Contains(host, ".") {
+		return apperrors.Newf(apperrors.InvalidArgument, "%s is not a public host", host)
+	}
+	for _, suffix := range clusterSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return apperrors.Newf(apperrors.InvalidArgument, "%s is not a public host", host)
+		}
+	}
+	return nil
+}
+
+// DialPublicOnly is a net.Dialer Control hook that refuses connections to
+// non-public addresses. It runs on the address a name resolved to, right
+// before connecting, so DNS rebinding cannot point a checked name inside.
+func DialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+		return apperrors.Newf(apperrors.PermissionDenied, "connecting to %s is not allowed", address)
+	}
+	return nil
+}
+
+// PublicHTTPClient returns a client for URLs supplied by users, such as
+// async inference callbacks, that only connects to public addresses. It
+// ignores proxy settings, which would make the proxy's address the only one
+// checked.
+func PublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   DialPublicOnly,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fd00::1", "fe80::1", "::ffff:127.0.0.1"} {
+		assert.False(t, IsPublicIP(net.ParseIP(addr)), addr)
+	}
+	for _, addr := range []string{"8.8.8.8", "93.184.216.34", "2606:4700::1111"} {
+		assert.True(t, IsPublicIP(net.ParseIP(addr)), addr)
+	}
+}
+
+func TestCheckPublicHost(t *testing.T) {
+	for _, host := range []string{"localhost", "LOCALHOST.", "10.0.0.5", "169.254.169.254", "batch-worker", "metadata-service.ai-platform.svc", "redis.ai-platform.svc.cluster.local", "metadata.google.internal"} {
+		assert.Equal(t, apperrors.InvalidArgument, apperrors.CodeOf(CheckPublicHost(host)), host)
+	}
+	for _, host := range []string{"hooks.example.com", "8.8.8.8"} {
+		assert.NoError(t, CheckPublicHost(host), host)
+	}
+}
+
+func TestPublicHTTPClient_RefusesLoopback(t *testing.T) {
+	reached := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer server.Close()
+
+	_, err := PublicHTTPClient(5*time.Second).Post(server.URL, "application/json", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, apperrors.PermissionDenied, apperrors.CodeOf(err))
+	assert.False(t, reached)
+}
@@ -0,0 +1,250 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultNamespace is the Kubernetes namespace encoded in platform SPIFFE IDs
+const DefaultNamespace = "ai-platform"
+
+// ServiceID returns the SPIFFE ID of a platform service,
+// e.g. spiffe://ai-platform.local/ns/ai-platform/sa/model-router
+func ServiceID(trustDomain, service string) string {
+	return fmt.Sprintf("spiffe://%s/ns/%s/sa/%s", trustDomain, DefaultNamespace, service)
+}
+
+// Identity is a workload identity (X.509 SVID) together with the trust bundle
+// used to verify peers. Certificates are read on every handshake so rotation
+// does not require restarting the service.
+type Identity struct {
+	trustDomain string
+	service     string
+	logger      *zap.Logger
+
+	mu     sync.RWMutex
+	cert   *tls.Certificate
+	bundle *x509.CertPool
+	expiry time.Time
+
+	// reload re-reads or re-issues the SVID
+	reload func() (*tls.Certificate, *x509.CertPool, error)
+}
+
+// Config configures how a workload identity is obtained
+type Config struct {
+	Service     string
+	TrustDomain string
+
+	// SVID files, as written by the SPIRE agent or spiffe-helper
+	CertFile   string
+	KeyFile    string
+	BundleFile string
+
+	// CA files used to issue SVIDs locally when no SPIRE agent is available
+	CACertFile string
+	CAKeyFile  string
+	SVIDTTL    time.Duration
+}
+
+// ConfigFromEnv reads identity settings from MTLS_* environment variables.
+// It returns nil when MTLS_ENABLED is not "true".
+func ConfigFromEnv(service string) *Config {
+	if os.Getenv("MTLS_ENABLED") != "true" {
+		return nil
+	}
+
+	ttl, err := time.ParseDuration(getEnv("MTLS_SVID_TTL", "1h"))
+	if err != nil {
+		ttl = time.Hour
+	}
+
+	return &Config{
+		Service:     service,
+		TrustDomain: getEnv("MTLS_TRUST_DOMAIN", "ai-platform.local"),
+		CertFile:    getEnv("MTLS_CERT_FILE", "/run/spiffe/svid.pem"),
+		KeyFile:     getEnv("MTLS_KEY_FILE", "/run/spiffe/svid_key.pem"),
+		BundleFile:  getEnv("MTLS_BUNDLE_FILE", "/run/spiffe/bundle.pem"),
+		CACertFile:  os.Getenv("MTLS_CA_CERT_FILE"),
+		CAKeyFile:   os.Getenv("MTLS_CA_KEY_FILE"),
+		SVIDTTL:     ttl,
+	}
+}
+
+// IdentityFromEnv loads the workload identity when MTLS_ENABLED=true and returns nil otherwise
+func IdentityFromEnv(service string, logger *zap.Logger) (*Identity, error) {
+	cfg := ConfigFromEnv(service)
+	if cfg == nil {
+		return nil, nil
+	}
+
+	identity, err := NewIdentity(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("loaded workload identity", zap.String("spiffe_id", identity.ID()))
+	return identity, nil
+}
+
+// NewIdentity loads or issues the workload identity described by cfg
+func NewIdentity(cfg *Config, logger *zap.Logger) (*Identity, error) {
+	id := &Identity{
+		trustDomain: cfg.TrustDomain,
+		service:     cfg.Service,
+		logger:      logger,
+	}
+
+	if cfg.CACertFile != "" && cfg.CAKeyFile != "" {
+		ca, err := LoadCA(cfg.CACertFile, cfg.CAKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		spiffeID := ServiceID(cfg.TrustDomain, cfg.Service)
+		id.reload = func() (*tls.Certificate, *x509.CertPool, error) {
+			cert, err := ca.Issue(spiffeID, cfg.SVIDTTL)
+			if err != nil {
+				return nil, nil, err
+			}
+			return cert, ca.Bundle(), nil
+		}
+	} else {
+		id.reload = func() (*tls.Certificate, *x509.CertPool, error) {
+			return loadSVID(cfg.CertFile, cfg.KeyFile, cfg.BundleFile)
+		}
+	}
+
+	if err := id.Reload(); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// Reload refreshes the SVID and trust bundle
+func (i *Identity) Reload() error {
+	cert, bundle, err := i.reload()
+	if err != nil {
+		return fmt.Errorf("failed to load workload identity: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse SVID: %w", err)
+	}
+
+	if _, err := spiffeIDFromCert(leaf); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	i.cert = cert
+	i.bundle = bundle
+	i.expiry = leaf.NotAfter
+	i.mu.Unlock()
+
+	return nil
+}
+
+// Watch reloads the identity at half its remaining lifetime, bounded by interval
+func (i *Identity) Watch(ctx context.Context, interval time.Duration) {
+	go func() {
+		for {
+			wait := interval
+			i.mu.RLock()
+			if remaining := time.Until(i.expiry) / 2; remaining > 0 && remaining < wait {
+				wait = remaining
+			}
+			i.mu.RUnlock()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			if err := i.Reload(); err != nil {
+				i.logger.Error("failed to rotate workload identity", zap.Error(err))
+				continue
+			}
+			i.logger.Debug("workload identity rotated", zap.String("spiffe_id", i.ID()))
+		}
+	}()
+}
+
+// ID returns the identity's SPIFFE ID
+func (i *Identity) ID() string {
+	return ServiceID(i.trustDomain, i.service)
+}
+
+// TrustDomain returns the identity's trust domain
+func (i *Identity) TrustDomain() string {
+	return i.trustDomain
+}
+
+func (i *Identity) certificate() *tls.Certificate {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.cert
+}
+
+func (i *Identity) roots() *x509.CertPool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.bundle
+}
+
+// loadSVID reads an SVID and trust bundle from PEM files
+func loadSVID(certFile, keyFile, bundleFile string) (*tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load SVID: %w", err)
+	}
+
+	bundlePEM, err := os.ReadFile(bundleFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read trust bundle: %w", err)
+	}
+
+	bundle := x509.NewCertPool()
+	if !bundle.AppendCertsFromPEM(bundlePEM) {
+		return nil, nil, fmt.Errorf("trust bundle %s contains no certificates", bundleFile)
+	}
+
+	return &cert, bundle, nil
+}
+
+// spiffeIDFromCert extracts the single spiffe:// URI SAN from a certificate
+func spiffeIDFromCert(cert *x509.Certificate) (*url.URL, error) {
+	var id *url.URL
+	for _, uri := range cert.URIs {
+		if uri.Scheme != "spiffe" {
+			continue
+		}
+		if id != nil {
+			return nil, fmt.Errorf("certificate has more than one SPIFFE ID")
+		}
+		id = uri
+	}
+
+	if id == nil {
+		return nil, fmt.Errorf("certificate has no SPIFFE ID")
+	}
+	return id, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return defaultValue
+}
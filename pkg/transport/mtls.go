@@ -0,0 +1,294 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Authorizer decides whether a peer's SPIFFE ID may talk to this service
+type Authorizer func(peerID *url.URL) error
+
+// AuthorizeAny accepts any peer that presents a valid SVID from the trust bundle
+func AuthorizeAny() Authorizer {
+	return func(*url.URL) error { return nil }
+}
+
+// AuthorizeTrustDomain accepts any workload in the given trust domain
+func AuthorizeTrustDomain(trustDomain string) Authorizer {
+	return func(peerID *url.URL) error {
+		if peerID.Host != trustDomain {
+			return fmt.Errorf("peer %s is not in trust domain %s", peerID, trustDomain)
+		}
+		return nil
+	}
+}
+
+// AuthorizeIDs accepts only the listed SPIFFE IDs
+func AuthorizeIDs(ids ...string) Authorizer {
+	allowed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		allowed[id] = true
+	}
+
+	return func(peerID *url.URL) error {
+		if !allowed[peerID.String()] {
+			return fmt.Errorf("peer %s is not authorized", peerID)
+		}
+		return nil
+	}
+}
+
+// platformPeers lists which services may call each platform service
+var platformPeers = map[string][]string{
+	"model-router":           {"api-gateway", "autoscaler"},
+	"inference-orchestrator": {"model-router", "batch-worker", "autoscaler", "api-gateway"},
+	"metadata-service":       {"api-gateway", "model-router", "batch-worker", "drift-service", "autoscaler", "artifact-scanner", "metadata-service"}, // peer regions replicate
+	"metering-service":       {"api-gateway"},
+	"tenant-service":         {"api-gateway", "metadata-service", "batch-worker"},
+	"batch-worker":           {"api-gateway", "autoscaler"},
+}
+
+// routePeers lists services that may call only some paths of a platform
+// service, on top of its platformPeers
+var routePeers = map[string]map[string][]string{
+	"model-router": {"/v1/backends": {"inference-orchestrator"}}, // orchestrators renew their backend leases
+}
+
+// PeerPolicy returns the inbound authorization policy for a platform service.
+// MTLS_ALLOWED_PEERS (comma-separated service names or SPIFFE IDs) overrides
+// the built-in call graph.
+func PeerPolicy(trustDomain, service string) Authorizer {
+	peers := append([]string(nil), platformPeers[service]...)
+	for _, routed := range routePeers[service] {
+		peers = append(peers, routed...)
+	}
+	if override := os.Getenv("MTLS_ALLOWED_PEERS"); override != "" {
+		peers = strings.Split(override, ",")
+	}
+
+	if len(peers) == 0 {
+		return AuthorizeTrustDomain(trustDomain)
+	}
+
+	return AuthorizeIDs(peerIDs(trustDomain, peers)...)
+}
+
+// RequireRoutePeers keeps the peers PeerPolicy admits for only some paths of
+// a platform service to those paths. It is a no-op when MTLS_ALLOWED_PEERS
+// overrides the built-in call graph.
+func RequireRoutePeers(next http.Handler, trustDomain, service string) http.Handler {
+	routes := routePeers[service]
+	if len(routes) == 0 || os.Getenv("MTLS_ALLOWED_PEERS") != "" {
+		return next
+	}
+
+	// Peers that may call the whole service are not narrowed
+	unrestricted := make(map[string]bool)
+	for _, id := range peerIDs(trustDomain, platformPeers[service]) {
+		unrestricted[id] = true
+	}
+	paths := make(map[string][]string)
+	for path, peers := range routes {
+		for _, id := range peerIDs(trustDomain, peers) {
+			if !unrestricted[id] {
+				paths[id] = append(paths[id], path)
+			}
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, narrowed := paths[PeerID(r)]
+		if !narrowed {
+			next.ServeHTTP(w, r)
+			return
+		}
+		for _, path := range allowed {
+			if r.URL.Path == path || strings.HasPrefix(r.URL.Path, path+"/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		apperrors.Write(w, r, apperrors.New(apperrors.PermissionDenied, "peer is not authorized for this path"))
+	})
+}
+
+// peerIDs resolves service names to SPIFFE IDs, passing SPIFFE IDs through
+func peerIDs(trustDomain string, peers []string) []string {
+	ids := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		peer = strings.TrimSpace(peer)
+		if !strings.HasPrefix(peer, "spiffe://") {
+			peer = ServiceID(trustDomain, peer)
+		}
+		ids = append(ids, peer)
+	}
+	return ids
+}
+
+// ProbePaths are served without a client certificate so kubelet probes and
+// Prometheus scrapes keep working; every other path requires an authorized SVID
+var ProbePaths = []string{"/health", "/healthz", "/readyz", "/metrics"}
+
+// ServerTLSConfig returns a TLS config that verifies client SVIDs with authorize.
+// Client certificates are requested rather than required at the TLS layer;
+// RequirePeer rejects certificate-less requests outside ProbePaths.
+func (i *Identity) ServerTLSConfig(authorize Authorizer) *tls.Config {
+	verify := i.verifyPeer(authorize, x509.ExtKeyUsageClientAuth)
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: tls.RequestClientCert,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return i.certificate(), nil
+		},
+		VerifyPeerCertificate: func(rawCerts [][]byte, chains [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return nil
+			}
+			return verify(rawCerts, chains)
+		},
+	}
+}
+
+// RequirePeer rejects requests that did not present a verified client SVID
+func RequirePeer(next http.Handler, exempt ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, path := range exempt {
+			if r.URL.Path == path {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		if PeerID(r) == "" {
+			apperrors.Write(w, r, apperrors.New(apperrors.Unauthenticated, "client certificate required"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequirePeerPolicy narrows a route to the peers authorize accepts, for
+// routes fewer services may call than the rest of the server they are on
+func RequirePeerPolicy(next http.Handler, authorize Authorizer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := PeerID(r)
+		if id == "" {
+			apperrors.Write(w, r, apperrors.New(apperrors.Unauthenticated, "client certificate required"))
+			return
+		}
+
+		peerID, err := url.Parse(id)
+		if err == nil {
+			err = authorize(peerID)
+		}
+		if err != nil {
+			apperrors.Write(w, r, apperrors.Wrap(err, apperrors.PermissionDenied, "peer is not authorized"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ListenAndServe serves srv over mTLS when identity is set and plain HTTP otherwise.
+// Inbound peers are authorized with the service's PeerPolicy and kept to
+// their paths with RequireRoutePeers.
+func ListenAndServe(srv *http.Server, identity *Identity) error {
+	if identity == nil {
+		return srv.ListenAndServe()
+	}
+
+	srv.TLSConfig = identity.ServerTLSConfig(PeerPolicy(identity.trustDomain, identity.service))
+	srv.Handler = RequirePeer(RequireRoutePeers(srv.Handler, identity.trustDomain, identity.service), ProbePaths...)
+	return srv.ListenAndServeTLS("", "")
+}
+
+// ClientTLSConfig returns a TLS config that presents this identity and
+// verifies the server's SVID with authorize
+func (i *Identity) ClientTLSConfig(authorize Authorizer) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return i.certificate(), nil
+		},
+		// SVIDs carry no DNS names; the chain and SPIFFE ID are checked in VerifyPeerCertificate
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: i.verifyPeer(authorize, x509.ExtKeyUsageServerAuth),
+	}
+}
+
+// HTTPTransport returns an http.Transport that dials peers over mTLS
+func (i *Identity) HTTPTransport(authorize Authorizer) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = i.ClientTLSConfig(authorize)
+	return transport
+}
+
+// HTTPClient returns an HTTP client for calling the given platform service
+func (i *Identity) HTTPClient(service string, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: i.HTTPTransport(AuthorizeIDs(ServiceID(i.trustDomain, service))),
+	}
+}
+
+// verifyPeer verifies the peer chain against the current bundle and authorizes its SPIFFE ID
+func (i *Identity) verifyPeer(authorize Authorizer, usage x509.ExtKeyUsage) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("peer presented no certificate")
+		}
+
+		certs := make([]*x509.Certificate, 0, len(rawCerts))
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse peer certificate: %w", err)
+			}
+			certs = append(certs, cert)
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		if _, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         i.roots(),
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{usage},
+		}); err != nil {
+			return fmt.Errorf("failed to verify peer SVID: %w", err)
+		}
+
+		peerID, err := spiffeIDFromCert(certs[0])
+		if err != nil {
+			return err
+		}
+
+		return authorize(peerID)
+	}
+}
+
+// PeerID returns the SPIFFE ID of the client that made an mTLS request
+func PeerID(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+
+	id, err := spiffeIDFromCert(r.TLS.PeerCertificates[0])
+	if err != nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,241 @@
+package transport
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+const testTrustDomain = "ai-platform.test"
+
+func newTestIdentity(t *testing.T, caCert, caKey, service string) *Identity {
+	logger, _ := zap.NewDevelopment()
+	id, err := NewIdentity(&Config{
+		Service:     service,
+		TrustDomain: testTrustDomain,
+		CACertFile:  caCert,
+		CAKeyFile:   caKey,
+		SVIDTTL:     time.Hour,
+	}, logger)
+	assert.NoError(t, err)
+	return id
+}
+
+func writeTestCA(t *testing.T) (string, string) {
+	ca, err := NewCA(testTrustDomain)
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "ca.pem")
+	keyFile := filepath.Join(dir, "ca_key.pem")
+	assert.NoError(t, ca.WriteFiles(certFile, keyFile))
+	return certFile, keyFile
+}
+
+// startMTLSServer serves over a TLS listener directly; httptest.StartTLS would
+// install its own certificate ahead of GetCertificate
+func startMTLSServer(t *testing.T, cfg *tls.Config, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = tls.NewListener(server.Listener, cfg)
+	server.Start()
+	server.URL = strings.Replace(server.URL, "http://", "https://", 1)
+	return server
+}
+
+func TestServiceID(t *testing.T) {
+	assert.Equal(t,
+		"spiffe://ai-platform.local/ns/ai-platform/sa/model-router",
+		ServiceID("ai-platform.local", "model-router"),
+	)
+}
+
+func TestMTLS_AuthorizedPeer(t *testing.T) {
+	caCert, caKey := writeTestCA(t)
+	router := newTestIdentity(t, caCert, caKey, "model-router")
+	gateway := newTestIdentity(t, caCert, caKey, "api-gateway")
+
+	server := startMTLSServer(t, router.ServerTLSConfig(PeerPolicy(testTrustDomain, "model-router")), func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(PeerID(r)))
+	})
+	defer server.Close()
+
+	client := gateway.HTTPClient("model-router", 5*time.Second)
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestMTLS_UnauthorizedPeer(t *testing.T) {
+	caCert, caKey := writeTestCA(t)
+	router := newTestIdentity(t, caCert, caKey, "model-router")
+	worker := newTestIdentity(t, caCert, caKey, "batch-worker")
+
+	server := startMTLSServer(t, router.ServerTLSConfig(PeerPolicy(testTrustDomain, "model-router")), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	client := worker.HTTPClient("model-router", 5*time.Second)
+	_, err := client.Get(server.URL)
+
+	assert.Error(t, err)
+}
+
+func TestRequirePeer_ProbePathsExempt(t *testing.T) {
+	caCert, caKey := writeTestCA(t)
+	router := newTestIdentity(t, caCert, caKey, "model-router")
+
+	handler := RequirePeer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), ProbePaths...)
+	server := startMTLSServer(t, router.ServerTLSConfig(AuthorizeAny()), handler.ServeHTTP)
+	defer server.Close()
+
+	// A probe without a client certificate only reaches the exempt paths
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+
+	resp, err := client.Get(server.URL + "/health")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = client.Get(server.URL + "/v1/route")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRequirePeerPolicy_NarrowsRoute(t *testing.T) {
+	caCert, caKey := writeTestCA(t)
+	worker := newTestIdentity(t, caCert, caKey, "batch-worker")
+	gateway := newTestIdentity(t, caCert, caKey, "api-gateway")
+	autoscaler := newTestIdentity(t, caCert, caKey, "autoscaler")
+
+	handler := RequirePeerPolicy(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), AuthorizeIDs(ServiceID(testTrustDomain, "api-gateway")))
+	server := startMTLSServer(t, worker.ServerTLSConfig(PeerPolicy(testTrustDomain, "batch-worker")), handler.ServeHTTP)
+	defer server.Close()
+
+	resp, err := gateway.HTTPClient("batch-worker", 5*time.Second).Get(server.URL + "/v1/privacy/deletions")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The autoscaler may call the batch worker, but not this route
+	resp, err = autoscaler.HTTPClient("batch-worker", 5*time.Second).Get(server.URL + "/v1/privacy/deletions")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestRequireRoutePeers_KeepsRoutePeersToTheirPaths(t *testing.T) {
+	caCert, caKey := writeTestCA(t)
+	router := newTestIdentity(t, caCert, caKey, "model-router")
+	orchestrator := newTestIdentity(t, caCert, caKey, "inference-orchestrator")
+	gateway := newTestIdentity(t, caCert, caKey, "api-gateway")
+
+	handler := RequireRoutePeers(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), testTrustDomain, "model-router")
+	server := startMTLSServer(t, router.ServerTLSConfig(PeerPolicy(testTrustDomain, "model-router")), handler.ServeHTTP)
+	defer server.Close()
+
+	get := func(client *http.Client, path string) int {
+		resp, err := client.Get(server.URL + path)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The orchestrator may renew its backend lease, and nothing else
+	orchestratorClient := orchestrator.HTTPClient("model-router", 5*time.Second)
+	assert.Equal(t, http.StatusOK, get(orchestratorClient, "/v1/backends"))
+	assert.Equal(t, http.StatusForbidden, get(orchestratorClient, "/v1/route"))
+	assert.Equal(t, http.StatusForbidden, get(orchestratorClient, "/admin/backends"))
+
+	gatewayClient := gateway.HTTPClient("model-router", 5*time.Second)
+	assert.Equal(t, http.StatusOK, get(gatewayClient, "/v1/route"))
+	assert.Equal(t, http.StatusOK, get(gatewayClient, "/v1/backends"))
+}
+
+func TestRequireRoutePeers_Override(t *testing.T) {
+	t.Setenv("MTLS_ALLOWED_PEERS", "inference-orchestrator")
+	caCert, caKey := writeTestCA(t)
+	router := newTestIdentity(t, caCert, caKey, "model-router")
+	orchestrator := newTestIdentity(t, caCert, caKey, "inference-orchestrator")
+
+	handler := RequireRoutePeers(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), testTrustDomain, "model-router")
+	server := startMTLSServer(t, router.ServerTLSConfig(PeerPolicy(testTrustDomain, "model-router")), handler.ServeHTTP)
+	defer server.Close()
+
+	// An explicit peer list admits its peers to every path
+	resp, err := orchestrator.HTTPClient("model-router", 5*time.Second).Get(server.URL + "/v1/route")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestMTLS_UnexpectedServer(t *testing.T) {
+	caCert, caKey := writeTestCA(t)
+	metadata := newTestIdentity(t, caCert, caKey, "metadata-service")
+	gateway := newTestIdentity(t, caCert, caKey, "api-gateway")
+
+	server := startMTLSServer(t, metadata.ServerTLSConfig(AuthorizeAny()), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	// The gateway expects to reach the router, not the metadata service
+	client := gateway.HTTPClient("model-router", 5*time.Second)
+	_, err := client.Get(server.URL)
+
+	assert.Error(t, err)
+}
+
+func TestMTLS_UntrustedCA(t *testing.T) {
+	caCert, caKey := writeTestCA(t)
+	otherCert, otherKey := writeTestCA(t)
+	router := newTestIdentity(t, caCert, caKey, "model-router")
+	gateway := newTestIdentity(t, otherCert, otherKey, "api-gateway")
+
+	server := startMTLSServer(t, router.ServerTLSConfig(AuthorizeAny()), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	client := gateway.HTTPClient("model-router", 5*time.Second)
+	_, err := client.Get(server.URL)
+
+	assert.Error(t, err)
+}
+
+func TestPeerPolicy_Override(t *testing.T) {
+	t.Setenv("MTLS_ALLOWED_PEERS", "batch-worker")
+	authorize := PeerPolicy(testTrustDomain, "model-router")
+
+	caCert, caKey := writeTestCA(t)
+	worker := newTestIdentity(t, caCert, caKey, "batch-worker")
+	gateway := newTestIdentity(t, caCert, caKey, "api-gateway")
+
+	assert.NoError(t, authorize(mustParseID(t, worker.ID())))
+	assert.Error(t, authorize(mustParseID(t, gateway.ID())))
+}
+
+func mustParseID(t *testing.T, id string) *url.URL {
+	parsed, err := url.Parse(id)
+	assert.NoError(t, err)
+	return parsed
+}
@@ -0,0 +1,113 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+)
+
+// DefaultTopic is the Kafka topic usage events are published to
+const DefaultTopic = "usage-events"
+
+// DefaultTenant is billed for requests that carry no tenant
+const DefaultTenant = "default"
+
+// Kind is the type of work an event meters
+type Kind string
+
+const (
+	// KindRealtime is a synchronous inference served through the gateway
+	KindRealtime Kind = "realtime"
+	// KindBatch is the inferences of one batch job
+	KindBatch Kind = "batch"
+	// KindCompute is backend execution time spent on inferences, for cost
+	// attribution. Its requests are executions already metered by realtime
+	// or batch events, so they are not billed again.
+	KindCompute Kind = "compute"
+)
+
+// Event reports billable work done for a tenant. Quantities are totals for the
+// event, so a batch job is reported once rather than per item.
+type Event struct {
+	// ID makes redelivered events idempotent for the metering service
+	ID          string    `json:"id"`
+	Tenant      string    `json:"tenant"`
+	Service     string    `json:"service"`
+	Kind        Kind      `json:"kind"`
+	Model       string    `json:"model"`
+	Version     string    `json:"version"`
+	Requests    int64     `json:"requests"`
+	Errors      int64     `json:"errors"`
+	LatencyMs   int64     `json:"latency_ms"`
+	InputBytes  int64     `json:"input_bytes"`
+	OutputBytes int64     `json:"output_bytes"`
+	Timestamp   time.Time `json:"timestamp"`
+
+	// ComputeMs is the execution time of a compute event and Pool the node
+	// pool it ran on
+	ComputeMs int64  `json:"compute_ms,omitempty"`
+	Pool      string `json:"pool,omitempty"`
+
+	// EstimatedCost is what the event's inferences cost at the service's
+	// model pricing, zero when it prices none
+	EstimatedCost float64 `json:"estimated_cost,omitempty"`
+}
+
+// Recorder publishes usage events in the background so metering never adds
+// latency to, or fails, the request being metered. Events are dropped when the
+// buffer is full. A nil Recorder records nothing.
+type Recorder struct {
+	service string
+	queue   *publish.Queue[Event]
+}
+
+// NewRecorder creates a recorder for service that buffers up to size events,
+// published keyed by tenant so a tenant's events stay ordered
+func NewRecorder(service string, publisher publish.Publisher, size int, logger *zap.Logger) *Recorder {
+	return &Recorder{
+		service: service,
+		queue: publish.NewQueue("usage event", publisher, func(event Event) string {
+			return event.Tenant
+		}, size, logger),
+	}
+}
+
+// Record queues an event. ID, service, tenant and timestamp are filled in from
+// the recorder and the request context when left empty.
+func (r *Recorder) Record(ctx context.Context, event Event) {
+	if r == nil {
+		return
+	}
+
+	if event.ID == "" {
+		event.ID = publish.NewID()
+	}
+	if event.Service == "" {
+		event.Service = r.service
+	}
+	if event.Tenant == "" {
+		event.Tenant = logging.FieldsFromContext(ctx).Tenant
+	}
+	if event.Tenant == "" {
+		event.Tenant = DefaultTenant
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	r.queue.Enqueue(event)
+}
+
+// Dropped returns how many events were discarded because the buffer was full
+func (r *Recorder) Dropped() int64 {
+	return r.queue.Dropped()
+}
+
+// Run publishes queued events until ctx is cancelled, then flushes what is left
+func (r *Recorder) Run(ctx context.Context) {
+	r.queue.Run(ctx)
+}
@@ -0,0 +1,68 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+type capture struct {
+	mu     sync.Mutex
+	keys   []string
+	events []Event
+}
+
+func (c *capture) Publish(ctx context.Context, key string, value []byte) error {
+	var event Event
+	if err := json.Unmarshal(value, &event); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys = append(c.keys, key)
+	c.events = append(c.events, event)
+	return nil
+}
+
+func TestRecorder_FillsDefaultsAndFlushesOnShutdown(t *testing.T) {
+	published := &capture{}
+	recorder := NewRecorder("api-gateway", published, 10, zap.NewNop())
+
+	ctx := logging.WithTenant(context.Background(), "acme")
+	recorder.Record(ctx, Event{Kind: KindRealtime, Model: "resnet18", Requests: 1})
+	recorder.Record(context.Background(), Event{Kind: KindBatch, Model: "resnet18", Requests: 5})
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(runCtx)
+
+	require.Len(t, published.events, 2)
+	first := published.events[0]
+	assert.Equal(t, "acme", first.Tenant)
+	assert.Equal(t, "api-gateway", first.Service)
+	assert.NotEmpty(t, first.ID)
+	assert.False(t, first.Timestamp.IsZero())
+	assert.Equal(t, []string{"acme", DefaultTenant}, published.keys)
+	assert.NotEqual(t, first.ID, published.events[1].ID)
+}
+
+func TestRecorder_DropsWhenFull(t *testing.T) {
+	recorder := NewRecorder("api-gateway", &capture{}, 1, zap.NewNop())
+
+	recorder.Record(context.Background(), Event{Requests: 1})
+	recorder.Record(context.Background(), Event{Requests: 1})
+
+	assert.Equal(t, int64(1), recorder.Dropped())
+}
+
+func TestRecorder_NilIsNoop(t *testing.T) {
+	var recorder *Recorder
+	recorder.Record(context.Background(), Event{Requests: 1})
+}
@@ -0,0 +1,104 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/events"
+)
+
+// Outcome says how a delivery attempt ended
+type Outcome string
+
+const (
+	OutcomeDelivered Outcome = "delivered"
+	// OutcomeRetrying attempts failed and will be tried again
+	OutcomeRetrying Outcome = "retrying"
+	// OutcomeFailed attempts failed and will not be tried again
+	OutcomeFailed Outcome = "failed"
+)
+
+// Delivery records one attempt at delivering an event to an endpoint
+type Delivery struct {
+	EventID    string      `json:"event_id"`
+	EndpointID string      `json:"endpoint_id"`
+	EventType  events.Type `json:"event_type"`
+	Attempt    int         `json:"attempt"`
+	Outcome    Outcome     `json:"outcome"`
+	StatusCode int         `json:"status_code,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	At         time.Time   `json:"at"`
+	LatencyMS  int64       `json:"latency_ms"`
+}
+
+// DeliveryLog records delivery attempts
+type DeliveryLog interface {
+	Record(delivery Delivery)
+}
+
+// MemoryLog keeps the most recent delivery attempts of this instance
+type MemoryLog struct {
+	mu         sync.Mutex
+	deliveries []Delivery
+	next       int
+	full       bool
+}
+
+// NewMemoryLog creates a log keeping the last size attempts
+func NewMemoryLog(size int) *MemoryLog {
+	return &MemoryLog{deliveries: make([]Delivery, size)}
+}
+
+// Record keeps an attempt, replacing the oldest once the log is full
+func (l *MemoryLog) Record(delivery Delivery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.deliveries) == 0 {
+		return
+	}
+	l.deliveries[l.next] = delivery
+	l.next = (l.next + 1) % len(l.deliveries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Recent returns up to limit attempts, newest first, for one endpoint or,
+// when endpointID is empty, for all of them
+func (l *MemoryLog) Recent(endpointID string, limit int) []Delivery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.deliveries)
+	}
+	recent := make([]Delivery, 0)
+	for i := 1; i <= count && len(recent) < limit; i++ {
+		delivery := l.deliveries[(l.next-i+len(l.deliveries))%len(l.deliveries)]
+		if endpointID == "" || delivery.EndpointID == endpointID {
+			recent = append(recent, delivery)
+		}
+	}
+	return recent
+}
+
+// Handler lists recent attempts as JSON, filtered by the endpoint query
+// parameter and capped by limit (default 100)
+func (l *MemoryLog) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := 100
+		if value := r.URL.Query().Get("limit"); value != "" {
+			if n, err := strconv.Atoi(value); err == nil && n > 0 {
+				limit = n
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"deliveries": l.Recent(r.URL.Query().Get("endpoint"), limit),
+		})
+	})
+}
@@ -0,0 +1,377 @@
+// Package webhooks delivers platform events to the HTTP endpoints external
+// systems register, signing each delivery with the endpoint's secret and
+// retrying failures with backoff.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+)
+
+// Headers sent with each delivery. The signature is the hex HMAC-SHA256,
+// under the endpoint's secret, of the timestamp, a dot and the body, so
+// receivers can check deliveries came from the platform and reject replays of
+// old ones. The delivery ID stays the same across retries so receivers can
+// drop duplicates.
+const (
+	SignatureHeader = "X-Signature"
+	TimestampHeader = "X-Signature-Timestamp"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	EventHeader     = "X-Webhook-Event"
+)
+
+// Sign returns the signature of a body sent at timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Endpoint is an external system's URL and the events it receives
+type Endpoint struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+	// Events lists the event types delivered; empty delivers every type
+	Events []events.Type `json:"events,omitempty"`
+	// Tenant restricts deliveries to one tenant's events
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// Subscribes reports whether an event is delivered to the endpoint
+func (e *Endpoint) Subscribes(event events.Event) bool {
+	if e.Tenant != "" && e.Tenant != event.Tenant {
+		return false
+	}
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, t := range e.Events {
+		if t == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseEndpoints reads endpoints from a JSON list. An empty document has none.
+func ParseEndpoints(document string) ([]Endpoint, error) {
+	if document == "" {
+		return nil, nil
+	}
+	var endpoints []Endpoint
+	if err := json.Unmarshal([]byte(document), &endpoints); err != nil {
+		return nil, fmt.Errorf("invalid webhook endpoints: %w", err)
+	}
+	seen := make(map[string]bool, len(endpoints))
+	for _, e := range endpoints {
+		if e.ID == "" || seen[e.ID] {
+			return nil, fmt.Errorf("webhook endpoints need unique ids, got %q", e.ID)
+		}
+		seen[e.ID] = true
+		if u, err := url.Parse(e.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("webhook endpoint %s has an invalid url", e.ID)
+		}
+		if e.Secret == "" {
+			return nil, fmt.Errorf("webhook endpoint %s has no signing secret", e.ID)
+		}
+	}
+	return endpoints, nil
+}
+
+// delivery is an event queued for one endpoint
+type delivery struct {
+	endpoint Endpoint
+	event    events.Event
+}
+
+// message is the body of a delivery and what identifies it to receivers
+type message struct {
+	id        string
+	eventType events.Type
+	body      []byte
+}
+
+// eventMessage encodes an event for delivery
+func eventMessage(event events.Event) (message, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return message{}, apperrors.Wrap(err, apperrors.Internal, "failed to encode webhook")
+	}
+	return message{id: event.ID, eventType: event.Type, body: body}, nil
+}
+
+// Dispatcher delivers events to the endpoints subscribing to them in the
+// background, so notifying never slows or fails the work being reported.
+// Deliveries are dropped when the queue is full. A nil Dispatcher delivers
+// nothing.
+type Dispatcher struct {
+	service string
+	client  *http.Client
+	log     DeliveryLog
+	logger  *zap.Logger
+
+	mu        sync.RWMutex
+	endpoints []Endpoint
+
+	queue      chan delivery
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	dropped    atomic.Int64
+	now        func() time.Time
+}
+
+// NewDispatcher creates a dispatcher for service that queues up to size
+// deliveries and records every attempt in log
+func NewDispatcher(service string, client *http.Client, log DeliveryLog, size int, logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		service:    service,
+		client:     client,
+		log:        log,
+		logger:     logger,
+		queue:      make(chan delivery, size),
+		attempts:   5,
+		backoff:    time.Second,
+		maxBackoff: time.Minute,
+		now:        time.Now,
+	}
+}
+
+// SetEndpoints replaces the endpoints events are delivered to
+func (d *Dispatcher) SetEndpoints(endpoints []Endpoint) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.endpoints = endpoints
+}
+
+// SetRetries sets how many times a delivery is attempted and the backoff
+// before the first retry, which doubles after each one up to maxBackoff
+func (d *Dispatcher) SetRetries(attempts int, backoff, maxBackoff time.Duration) {
+	d.attempts = attempts
+	d.backoff = backoff
+	d.maxBackoff = maxBackoff
+}
+
+// Notify queues an event for every endpoint subscribing to it. ID, service,
+// severity, tenant and time are filled in like events.Emitter does.
+func (d *Dispatcher) Notify(ctx context.Context, event events.Event) {
+	if d == nil {
+		return
+	}
+
+	if event.ID == "" {
+		event.ID = publish.NewID()
+	}
+	if event.Service == "" {
+		event.Service = d.service
+	}
+	if event.Severity == "" {
+		event.Severity = events.SeverityInfo
+	}
+	if event.Tenant == "" {
+		event.Tenant = logging.FieldsFromContext(ctx).Tenant
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = d.now().UTC()
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, endpoint := range d.endpoints {
+		if !endpoint.Subscribes(event) {
+			continue
+		}
+		select {
+		case d.queue <- delivery{endpoint: endpoint, event: event}:
+		default:
+			if d.dropped.Add(1)%100 == 1 {
+				d.logger.Warn("webhook queue full, dropping deliveries", zap.Int64("dropped", d.dropped.Load()))
+			}
+		}
+	}
+}
+
+// Dropped returns how many deliveries were discarded because the queue was full
+func (d *Dispatcher) Dropped() int64 {
+	return d.dropped.Load()
+}
+
+// Run delivers queued events with workers concurrent deliveries until ctx is
+// cancelled, then makes one last attempt at what is left
+func (d *Dispatcher) Run(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				// Leave what is queued once cancelled to the last attempt
+				// below rather than retrying it under a cancelled context
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case next := <-d.queue:
+					d.Deliver(ctx, next.endpoint, next.event)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for {
+		select {
+		case next := <-d.queue:
+			if msg, err := eventMessage(next.event); err == nil {
+				d.attempt(flushCtx, next.endpoint, msg, 1)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// Deliver posts an event to an endpoint, retrying failures that may be
+// transient. Deliveries the receiver turns away with a client error are not
+// retried.
+func (d *Dispatcher) Deliver(ctx context.Context, endpoint Endpoint, event events.Event) error {
+	msg, err := eventMessage(event)
+	if err != nil {
+		return err
+	}
+	return d.deliver(ctx, endpoint, msg)
+}
+
+// DeliverJSON posts value to an endpoint, signed and retried like events,
+// for deliveries with a body of their own such as async inference
+// callbacks. id identifies the delivery to the receiver across retries.
+func (d *Dispatcher) DeliverJSON(ctx context.Context, endpoint Endpoint, id string, value interface{}) error {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.Internal, "failed to encode webhook")
+	}
+	return d.deliver(ctx, endpoint, message{id: id, body: body})
+}
+
+// deliver posts a message until it is delivered, fails permanently or runs
+// out of attempts
+func (d *Dispatcher) deliver(ctx context.Context, endpoint Endpoint, msg message) error {
+	backoff := d.backoff
+	for attempt := 1; ; attempt++ {
+		err := d.attempt(ctx, endpoint, msg, attempt)
+		if err == nil || !retryable(err) || attempt >= d.attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > d.maxBackoff {
+			backoff = d.maxBackoff
+		}
+	}
+}
+
+// attempt posts an event once and records the outcome
+func (d *Dispatcher) attempt(ctx context.Context, endpoint Endpoint, msg message, attempt int) error {
+	started := d.now()
+	status, err := d.post(ctx, endpoint, msg)
+
+	record := Delivery{
+		EventID:    msg.id,
+		EndpointID: endpoint.ID,
+		EventType:  msg.eventType,
+		Attempt:    attempt,
+		StatusCode: status,
+		Outcome:    OutcomeDelivered,
+		At:         started.UTC(),
+		LatencyMS:  d.now().Sub(started).Milliseconds(),
+	}
+	logger := d.logger.With(
+		zap.String("endpoint", endpoint.ID),
+		zap.String("event_id", msg.id),
+		zap.String("type", string(msg.eventType)),
+		zap.Int("attempt", attempt),
+	)
+	if err != nil {
+		record.Error = err.Error()
+		record.Outcome = OutcomeFailed
+		if retryable(err) && attempt < d.attempts {
+			record.Outcome = OutcomeRetrying
+		}
+		logger.Warn("webhook delivery failed", zap.String("outcome", string(record.Outcome)), zap.Error(err))
+	} else {
+		logger.Debug("webhook delivered")
+	}
+	if d.log != nil {
+		d.log.Record(record)
+	}
+	return err
+}
+
+// post sends one signed delivery and returns the receiver's status code
+func (d *Dispatcher) post(ctx context.Context, endpoint Endpoint, msg message) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(msg.body))
+	if err != nil {
+		return 0, apperrors.Wrap(err, apperrors.InvalidArgument, "failed to create webhook request")
+	}
+
+	timestamp := strconv.FormatInt(d.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, timestamp, msg.body))
+	req.Header.Set(DeliveryHeader, msg.id)
+	if msg.eventType != "" {
+		req.Header.Set(EventHeader, string(msg.eventType))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, apperrors.Ensure(err, apperrors.Unavailable, fmt.Sprintf("failed to reach webhook %s", endpoint.ID))
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, apperrors.Newf(apperrors.FromHTTPStatus(resp.StatusCode), "webhook %s returned status %d", endpoint.ID, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryable reports whether a failed delivery may succeed when tried again
+func retryable(err error) bool {
+	switch apperrors.CodeOf(err) {
+	case apperrors.Unavailable, apperrors.DeadlineExceeded, apperrors.ResourceExhausted, apperrors.Internal:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+)
+
+func TestSign(t *testing.T) {
+	signature := Sign("s3cret", "1760572800", []byte(`{"id":"e1"}`))
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.Equal(t, signature, Sign("s3cret", "1760572800", []byte(`{"id":"e1"}`)))
+	assert.NotEqual(t, signature, Sign("other", "1760572800", []byte(`{"id":"e1"}`)))
+	assert.NotEqual(t, signature, Sign("s3cret", "1760572801", []byte(`{"id":"e1"}`)))
+}
+
+func TestEndpoint_Subscribes(t *testing.T) {
+	completed := events.Event{Type: events.JobCompleted, Tenant: "acme"}
+
+	assert.True(t, (&Endpoint{}).Subscribes(completed))
+	assert.True(t, (&Endpoint{Events: []events.Type{events.JobFailed, events.JobCompleted}}).Subscribes(completed))
+	assert.False(t, (&Endpoint{Events: []events.Type{events.ModelPromoted}}).Subscribes(completed))
+	assert.True(t, (&Endpoint{Tenant: "acme"}).Subscribes(completed))
+	assert.False(t, (&Endpoint{Tenant: "globex"}).Subscribes(completed))
+}
+
+func TestParseEndpoints(t *testing.T) {
+	endpoints, err := ParseEndpoints(`[{"id": "ci", "url": "https://ci.example.com/hooks", "secret": "s3cret", "events": ["job.completed"]}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []Endpoint{{ID: "ci", URL: "https://ci.example.com/hooks", Secret: "s3cret", Events: []events.Type{events.JobCompleted}}}, endpoints)
+
+	for _, document := range []string{
+		`[{"id": "ci", "url": "https://ci.example.com/hooks"}]`,
+		`[{"id": "ci", "url": "ftp://ci.example.com", "secret": "s"}]`,
+		`[{"id": "ci", "url": "https://a.example.com", "secret": "s"}, {"id": "ci", "url": "https://b.example.com", "secret": "s"}]`,
+		`{"id": "ci"}`,
+	} {
+		_, err := ParseEndpoints(document)
+		assert.Error(t, err, document)
+	}
+
+	endpoints, err = ParseEndpoints("")
+	assert.NoError(t, err)
+	assert.Empty(t, endpoints)
+}
+
+func TestDispatcher_DeliversSignedEvents(t *testing.T) {
+	received := make(chan events.Event, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		secret := map[string]string{"/ci": "ci-secret", "/audit": "audit-secret"}[r.URL.Path]
+		assert.Equal(t, Sign(secret, r.Header.Get(TimestampHeader), body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "job.completed", r.Header.Get(EventHeader))
+		assert.Equal(t, "job:1", r.Header.Get(DeliveryHeader))
+
+		var event events.Event
+		assert.NoError(t, json.Unmarshal(body, &event))
+		received <- event
+	}))
+	defer server.Close()
+
+	log := NewMemoryLog(10)
+	dispatcher := NewDispatcher("batch-worker", server.Client(), log, 10, zap.NewNop())
+	dispatcher.SetEndpoints([]Endpoint{
+		{ID: "ci", URL: server.URL + "/ci", Secret: "ci-secret", Events: []events.Type{events.JobCompleted}},
+		{ID: "audit", URL: server.URL + "/audit", Secret: "audit-secret"},
+		{ID: "models", URL: server.URL + "/models", Secret: "models-secret", Events: []events.Type{events.ModelPromoted}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx, 2)
+		close(done)
+	}()
+
+	dispatcher.Notify(context.Background(), events.Event{ID: "job:1", Type: events.JobCompleted, Subject: "1"})
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-received:
+			assert.Equal(t, "batch-worker", event.Service)
+			assert.Equal(t, events.SeverityInfo, event.Severity)
+			assert.False(t, event.OccurredAt.IsZero())
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook not delivered")
+		}
+	}
+	// Attempts are recorded once the receiver answers
+	assert.Eventually(t, func() bool { return len(log.Recent("", 10)) == 2 }, 5*time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	deliveries := log.Recent("ci", 10)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, OutcomeDelivered, deliveries[0].Outcome)
+	assert.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+}
+
+func TestDispatcher_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantAttempts int32
+		wantCode     apperrors.Code
+		wantOutcomes []Outcome
+	}{
+		{"recovers", []int{http.StatusServiceUnavailable, http.StatusOK}, 2, "", []Outcome{OutcomeDelivered, OutcomeRetrying}},
+		{"gives up", []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}, 3, apperrors.Unavailable, []Outcome{OutcomeFailed, OutcomeRetrying, OutcomeRetrying}},
+		{"client error", []int{http.StatusNotFound}, 1, apperrors.NotFound, []Outcome{OutcomeFailed}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(tt.statuses[n-1])
+			}))
+			defer server.Close()
+
+			log := NewMemoryLog(10)
+			dispatcher := NewDispatcher("batch-worker", server.Client(), log, 10, zap.NewNop())
+			dispatcher.SetRetries(3, time.Millisecond, 2*time.Millisecond)
+			err := dispatcher.Deliver(context.Background(), Endpoint{ID: "ci", URL: server.URL, Secret: "s"}, events.Event{ID: "e1", Type: events.JobFailed})
+
+			assert.Equal(t, tt.wantAttempts, atomic.LoadInt32(&attempts))
+			if tt.wantCode == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.wantCode, apperrors.CodeOf(err))
+			}
+			var outcomes []Outcome
+			for _, delivery := range log.Recent("ci", 10) {
+				outcomes = append(outcomes, delivery.Outcome)
+			}
+			assert.Equal(t, tt.wantOutcomes, outcomes)
+		})
+	}
+}
+
+func TestDispatcher_DeliverJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"job_id":"job-1"}`, string(body))
+		assert.Equal(t, Sign("s3cret", r.Header.Get(TimestampHeader), body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "job-1", r.Header.Get(DeliveryHeader))
+		assert.Empty(t, r.Header.Get(EventHeader))
+	}))
+	defer server.Close()
+
+	dispatcher := NewDispatcher("batch-worker", server.Client(), nil, 0, zap.NewNop())
+	err := dispatcher.DeliverJSON(context.Background(), Endpoint{ID: "callback", URL: server.URL, Secret: "s3cret"}, "job-1", map[string]string{"job_id": "job-1"})
+
+	assert.NoError(t, err)
+}
+
+func TestMemoryLog(t *testing.T) {
+	log := NewMemoryLog(3)
+	for i, endpoint := range []string{"a", "b", "a", "a"} {
+		log.Record(Delivery{EventID: string(rune('1' + i)), EndpointID: endpoint})
+	}
+
+	// The oldest attempt was replaced
+	ids := func(deliveries []Delivery) []string {
+		var ids []string
+		for _, d := range deliveries {
+			ids = append(ids, d.EventID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"4", "3", "2"}, ids(log.Recent("", 10)))
+	assert.Equal(t, []string{"4", "3"}, ids(log.Recent("a", 10)))
+	assert.Equal(t, []string{"4"}, ids(log.Recent("a", 1)))
+
+	w := httptest.NewRecorder()
+	log.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/deliveries?endpoint=b", nil))
+	assert.JSONEq(t, `{"deliveries": [{"event_id": "2", "endpoint_id": "b", "event_type": "", "attempt": 0, "outcome": "", "at": "0001-01-01T00:00:00Z", "latency_ms": 0}]}`, w.Body.String())
+}
@@ -9,18 +9,50 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/IBM/sarama"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
+	"github.com/yourusername/ai-platform/api-gateway/internal/accesslog"
+	"github.com/yourusername/ai-platform/api-gateway/internal/admin"
+	"github.com/yourusername/ai-platform/api-gateway/internal/admission"
+	"github.com/yourusername/ai-platform/api-gateway/internal/auth"
+	"github.com/yourusername/ai-platform/api-gateway/internal/backpressure"
+	"github.com/yourusername/ai-platform/api-gateway/internal/capture"
 	"github.com/yourusername/ai-platform/api-gateway/internal/config"
+	"github.com/yourusername/ai-platform/api-gateway/internal/drain"
 	"github.com/yourusername/ai-platform/api-gateway/internal/handlers"
 	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/api-gateway/internal/notify"
 	"github.com/yourusername/ai-platform/api-gateway/internal/observability"
+	"github.com/yourusername/ai-platform/api-gateway/internal/quota"
+	"github.com/yourusername/ai-platform/api-gateway/internal/resilience"
+	"github.com/yourusername/ai-platform/api-gateway/internal/settings"
+	"github.com/yourusername/ai-platform/api-gateway/internal/versions"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/compress"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/faults"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/inferencecache"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/pricing"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/secrets"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+	"github.com/yourusername/ai-platform/pkg/tiered"
+	"github.com/yourusername/ai-platform/pkg/transport"
+	"github.com/yourusername/ai-platform/pkg/usage"
+	"github.com/yourusername/ai-platform/pkg/webhooks"
 )
 
 func main() {
-	// Initialize logger
-	logger, err := zap.NewProduction()
+	// Initialize logger; its level can be changed at runtime via /admin/config
+	logConfig := zap.NewProductionConfig()
+	logger, err := logConfig.Build()
 	if err != nil {
 		panic(fmt.Sprintf("failed to initialize logger: %v", err))
 	}
@@ -28,11 +60,43 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	if level, err := zapcore.ParseLevel(cfg.LogLevel); err == nil {
+		logConfig.Level.SetLevel(level)
+	}
 	logger.Info("configuration loaded",
 		zap.String("port", cfg.Port),
 		zap.String("log_level", cfg.LogLevel),
 	)
 
+	// Resolve secrets from Vault or a mounted secret store when configured
+	secretProvider, err := secrets.FromEnv(logger)
+	if err != nil {
+		logger.Fatal("failed to initialize secret provider", zap.Error(err))
+	}
+	secretManager := secrets.NewManager(secretProvider, logger)
+	defer secretManager.Close()
+
+	jwtSecretRef := cfg.SecretsPath + "#jwt_secret"
+	jwtSecret := secretManager.LookupOr(context.Background(), jwtSecretRef, cfg.JWTSecret)
+	if jwtSecret == "" {
+		logger.Fatal("JWT secret is not configured; set JWT_SECRET or provide it via VAULT_ADDR")
+	}
+
+	// Tokens name the key that signed them, so those signed before a rotation
+	// of the secret stay valid for as long as any token lasts
+	signingKeys := auth.NewKeyring(jwtSecret, cfg.RefreshTokenTTL)
+	secretManager.Watch(context.Background(), jwtSecretRef, 5*time.Minute, signingKeys.Rotate)
+
+	// Load the SPIFFE workload identity for mTLS to internal services.
+	// The public listener stays plain HTTP; clients authenticate with JWTs.
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+	}
+
 	// Initialize observability
 	shutdown, err := observability.InitTracing(cfg.ServiceName, cfg.JaegerEndpoint)
 	if err != nil {
@@ -46,35 +110,346 @@ func main() {
 	redisClient := config.NewRedisClient(cfg.RedisHost)
 	defer redisClient.Close()
 
-	kafkaProducer, err := config.NewKafkaProducer(cfg.KafkaBrokers)
+	kafkaProducer, err := events.NewKafkaProducer(cfg.KafkaBrokers)
 	if err != nil {
 		logger.Fatal("failed to initialize kafka producer", zap.Error(err))
 	}
 	defer kafkaProducer.Close()
 
+	// Shutdown drains requests and Kafka sends under way before the producer
+	// closes, so accepted batch jobs are not lost on deploys
+	drainer := drain.New()
+	kafkaProducer = drainer.Producer(kafkaProducer)
+
+	// Frame Kafka messages with their registered schemas when a registry is
+	// configured. An unreachable registry is retried on first use, but an
+	// incompatible contract must not be deployed.
+	schemaCodec := schema.FromEnv()
+	if err := schemaCodec.Register(context.Background(), schema.BatchJobs, schema.BatchControls, schema.UsageEvents, schema.AccessLogs); apperrors.Is(err, apperrors.FailedPrecondition) {
+		logger.Fatal("message schema is incompatible with the registry", zap.Error(err))
+	} else if err != nil {
+		logger.Warn("failed to register message schemas", zap.Error(err))
+	}
+
+	// Meter inference usage for billing; events are published off the request path
+	usageRecorder := usage.NewRecorder(cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		value, err := schemaCodec.Frame(ctx, schema.UsageEvents, value)
+		if err != nil {
+			return err
+		}
+		_, _, err = kafkaProducer.SendMessage(&sarama.ProducerMessage{
+			Topic: cfg.UsageTopic,
+			Key:   sarama.StringEncoder(key),
+			Value: sarama.ByteEncoder(value),
+		})
+		return err
+	}), 10000, logger)
+	usageCtx, stopUsage := context.WithCancel(context.Background())
+	usageDone := make(chan struct{})
+	go func() {
+		usageRecorder.Run(usageCtx)
+		close(usageDone)
+	}()
+
+	// Publish sampled access events for analytics, separate from the zap log
+	accessLogger := accesslog.NewLogger(accesslog.Config{
+		SampleRate:      cfg.AccessLogSampleRate,
+		ErrorSampleRate: cfg.AccessLogErrorSampleRate,
+	}, cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		value, err := schemaCodec.Frame(ctx, schema.AccessLogs, value)
+		if err != nil {
+			return err
+		}
+		_, _, err = kafkaProducer.SendMessage(&sarama.ProducerMessage{
+			Topic: cfg.AccessLogTopic,
+			Key:   sarama.StringEncoder(key),
+			Value: sarama.ByteEncoder(value),
+		})
+		return err
+	}), 10000, logger)
+	accessLogCtx, stopAccessLog := context.WithCancel(context.Background())
+	accessLogDone := make(chan struct{})
+	go func() {
+		accessLogger.Run(accessLogCtx)
+		close(accessLogDone)
+	}()
+
+	// Capture sampled inferences and their responses for replay against
+	// another deployment; every failed inference is captured
+	var trafficCapture *inferencelog.Capture
+	if cfg.CapturePath != "" {
+		captureFile, err := capture.OpenFile(cfg.CapturePath)
+		if err != nil {
+			logger.Fatal("failed to open capture file", zap.Error(err))
+		}
+		defer captureFile.Close()
+		trafficCapture = inferencelog.NewCapture(inferencelog.Config{
+			Enabled:         true,
+			SampleRate:      cfg.CaptureSampleRate,
+			Redact:          cfg.CaptureRedact,
+			MaxPayloadBytes: cfg.CaptureMaxPayloadBytes,
+		}, cfg.ServiceName, captureFile, 10000, logger)
+		logger.Info("capturing traffic",
+			zap.String("path", cfg.CapturePath),
+			zap.Float64("sample_rate", cfg.CaptureSampleRate),
+		)
+	}
+	captureCtx, stopCapture := context.WithCancel(context.Background())
+	captureDone := make(chan struct{})
+	go func() {
+		trafficCapture.Run(captureCtx)
+		close(captureDone)
+	}()
+
+	// Deliver model changes announced on the platform event topic to the
+	// webhook endpoints kept in the secret store with their signing secrets
+	webhookLog := webhooks.NewMemoryLog(1000)
+	webhookDispatcher := webhooks.NewDispatcher(cfg.ServiceName, &http.Client{Timeout: 10 * time.Second}, webhookLog, 1000, logger)
+	webhookEndpointsRef := cfg.SecretsPath + "#webhook_endpoints"
+	loadWebhookEndpoints := func(document string) {
+		endpoints, err := webhooks.ParseEndpoints(document)
+		if err != nil {
+			logger.Error("failed to load webhook endpoints", zap.Error(err))
+			return
+		}
+		webhookDispatcher.SetEndpoints(endpoints)
+	}
+	loadWebhookEndpoints(secretManager.LookupOr(context.Background(), webhookEndpointsRef, cfg.WebhookEndpoints))
+	secretManager.Watch(context.Background(), webhookEndpointsRef, 5*time.Minute, loadWebhookEndpoints)
+	webhooksCtx, stopWebhooks := context.WithCancel(context.Background())
+	webhooksDone := make(chan struct{})
+	go func() {
+		webhookDispatcher.Run(webhooksCtx, 4)
+		close(webhooksDone)
+	}()
+	if webhookConsumer, err := notify.NewKafkaConsumer(cfg.KafkaBrokers, cfg.EventTopic, cfg.WebhookConsumerGroup, webhookDispatcher, logger); err != nil {
+		logger.Warn("model change webhooks are unavailable", zap.Error(err))
+	} else {
+		webhookConsumer.SetCodec(schemaCodec)
+		go func() {
+			if err := webhookConsumer.Start(webhooksCtx); err != nil {
+				logger.Error("webhook event consumer error", zap.Error(err))
+			}
+		}()
+	}
+
+	// Readiness checks cover the dependencies on the request path. Kafka only
+	// carries async and batch work, so the gateway is degraded without it.
+	routerClient := &http.Client{Timeout: health.DefaultTimeout}
+	if identity != nil {
+		routerClient = identity.HTTPClient("model-router", health.DefaultTimeout)
+	}
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("redis", func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	})
+	checker.AddOptional("kafka", health.TCPCheck(cfg.KafkaBrokers...))
+	checker.Add("draining", drainer.Check)
+	checker.Add("model-router", health.HTTPCheck(routerClient, cfg.RouterServiceURL+health.LivenessPath))
+
+	// Consult the tenant service for API keys, memberships and limits when one
+	// is configured; without it every authenticated caller shares the platform
+	var tenantClient *tenancy.Client
+	tenantHTTPClient := &http.Client{Timeout: 5 * time.Second}
+	if identity != nil {
+		tenantHTTPClient = identity.HTTPClient("tenant-service", 5*time.Second)
+	}
+	if cfg.TenantServiceURL != "" {
+		tenantClient = tenancy.NewClient(cfg.TenantServiceURL, tenantHTTPClient, cfg.TenantCacheTTL)
+		checker.Add("tenant-service", health.HTTPCheck(tenantHTTPClient, cfg.TenantServiceURL+health.LivenessPath))
+	}
+
 	// Setup router
 	if cfg.LogLevel == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	router := gin.New()
 
+	// Browser origins allowed to call the API, reloaded when their file changes
+	corsPolicies, err := middleware.CORSPoliciesFromEnv(context.Background(), logger)
+	if err != nil {
+		logger.Fatal("failed to load cors policies", zap.Error(err))
+	}
+
 	// Global middleware
 	router.Use(middleware.Logger(logger))
+	router.Use(accessLogger.Middleware())
 	router.Use(middleware.Recovery(logger))
 	router.Use(middleware.Tracing())
 	router.Use(middleware.Metrics())
-	router.Use(middleware.CORS())
+	router.Use(middleware.CORSWithPolicies(corsPolicies))
+	router.Use(middleware.BodyLimit(middleware.BodyLimits{
+		Default: cfg.MaxBodyBytes,
+		Routes:  cfg.RouteBodyLimits,
+	}))
+
+	// Rate limits per plan, reloaded when their file changes
+	rateTiers, err := middleware.RateTiersFromEnv(context.Background(), logger)
+	if err != nil {
+		logger.Fatal("failed to load rate tiers", zap.Error(err))
+	}
+
+	// Rate limits are counted in Redis; the failure policy covers outages
+	failurePolicy, err := middleware.ParseFailurePolicy(cfg.RateLimitFailurePolicy)
+	if err != nil {
+		logger.Fatal("failed to load rate limit failure policy", zap.Error(err))
+	}
+	rateLimiter := middleware.NewRateLimiter(redisClient, failurePolicy)
+
+	// Log level, rate tiers and maintenance mode changed through the admin API
+	runtimeSettings := settings.NewStore(redisClient, logConfig.Level, rateTiers, logger)
+	settingsCtx, stopSettings := context.WithCancel(context.Background())
+	defer stopSettings()
+	go runtimeSettings.Run(settingsCtx, cfg.SettingsRefresh)
+
+	// Expensive models get their own, lower limits per caller
+	configuredModelLimits, err := middleware.ParseModelRateLimits([]byte(cfg.ModelRateLimits))
+	if err != nil {
+		logger.Fatal("failed to load model rate limits", zap.Error(err))
+	}
+	modelLimits := middleware.NewModelRateLimits(configuredModelLimits, redisClient, logger)
+	modelLimitsCtx, stopModelLimits := context.WithCancel(context.Background())
+	defer stopModelLimits()
+	go modelLimits.Run(modelLimitsCtx, cfg.ModelRateLimitsRefresh)
 
-	// Health check endpoint (no auth required)
-	router.GET("/health", handlers.HealthCheck())
+	// Repeated inferences of deterministic models are answered from Redis,
+	// fronted by each gateway's own recent responses
+	responseCacheTTLs, err := inferencecache.ParseModelTTLs([]byte(cfg.ResponseCacheModels))
+	if err != nil {
+		logger.Fatal("failed to load response cache models", zap.Error(err))
+	}
+	responseCache := inferencecache.New("inference:cache", tiered.Redis(redisClient), responseCacheTTLs, logger)
+	responseCache.SetObserver(func(model, result string) {
+		observability.ResponseCacheLookups.WithLabelValues(model, result).Inc()
+	})
+
+	// Requests naming no version are served at the model's configured default
+	// or its latest active version
+	defaultVersions, err := versions.ParseDefaults(cfg.DefaultModelVersions)
+	if err != nil {
+		logger.Fatal("failed to load default model versions", zap.Error(err))
+	}
+	metadataHTTP := &http.Client{Timeout: 2 * time.Second}
+	if identity != nil {
+		metadataHTTP = identity.HTTPClient("metadata-service", 2*time.Second)
+	}
+	versionResolver := versions.NewResolver(versions.MetadataSource(metadataHTTP, cfg.MetadataServiceURL), defaultVersions, cfg.ModelVersionCacheTTL, logger)
+
+	// Inferences are returned and metered with their estimated cost
+	modelPricing, err := pricing.Parse(cfg.ModelPricing)
+	if err != nil {
+		logger.Fatal("failed to load model pricing", zap.Error(err))
+	}
+
+	// Inject faults for resilience testing; a no-op unless rules are configured
+	faultInjector, err := faults.FromEnv(context.Background(), cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load fault injection rules", zap.Error(err))
+	}
+
+	// The batch worker only serves its jobs, stats and deletions to the
+	// gateway, authenticated by mTLS when a workload identity is loaded
+	batchWorkerClient := func(timeout time.Duration) *http.Client {
+		if identity != nil {
+			return identity.HTTPClient("batch-worker", timeout)
+		}
+		return &http.Client{Timeout: timeout}
+	}
+
+	// Aggregated ops view; queue depths are omitted if Kafka offsets cannot be read
+	adminSources := admin.Sources{
+		Metadata:    admin.Endpoint{URL: cfg.MetadataServiceURL},
+		Router:      admin.Endpoint{URL: cfg.RouterServiceURL},
+		BatchWorker: admin.Endpoint{URL: cfg.BatchWorkerURL, Client: batchWorkerClient(5 * time.Second)},
+	}
+	if identity != nil {
+		adminSources.Metadata.Client = identity.HTTPClient("metadata-service", 5*time.Second)
+		adminSources.Router.Client = identity.HTTPClient("model-router", 5*time.Second)
+		adminSources.Orchestrators = identity.HTTPClient("inference-orchestrator", 5*time.Second)
+	}
+	queueInspector, err := admin.NewKafkaQueueInspector(cfg.KafkaBrokers, schema.JobTopics(cfg.KafkaTopic, cfg.TenantTopics), cfg.KafkaConsumerGroup)
+	if err != nil {
+		logger.Warn("kafka queue inspector unavailable", zap.Error(err))
+	} else {
+		defer queueInspector.Close()
+		adminSources.Queues = queueInspector
+	}
+	aggregator := admin.NewAggregator(adminSources, 5*time.Second, logger)
+
+	// Reject batch jobs while the batch worker's backlog is over its limits;
+	// jobs are admitted whenever the backlog cannot be fetched
+	var backlogGate *backpressure.Gate
+	if cfg.BatchBacklogLimit > 0 || cfg.BatchDelayLimit > 0 {
+		backlogGate = backpressure.NewGate(
+			cfg.BatchWorkerURL,
+			batchWorkerClient(cfg.BacklogPollInterval),
+			backpressure.Limits{Depth: cfg.BatchBacklogLimit, Delay: cfg.BatchDelayLimit},
+			3*cfg.BacklogPollInterval,
+			logger,
+		)
+		backlogCtx, stopBacklog := context.WithCancel(context.Background())
+		defer stopBacklog()
+		go backlogGate.Run(backlogCtx, cfg.BacklogPollInterval)
+	}
+
+	// Health check endpoints (no auth required)
+	router.GET("/health", handlers.Health(checker))
+	router.GET(health.LivenessPath, handlers.HealthCheck(checker))
+	router.GET(health.ReadinessPath, handlers.ReadinessCheck(checker))
 	router.GET("/metrics", handlers.MetricsHandler())
 
-	// API v1 routes
+	// Token issuance; callers authenticate with the credentials they exchange
+	tokenIssuer := auth.NewIssuer(signingKeys, auth.RedisUsedTokens(redisClient), cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	if tenantClient != nil {
+		tokenIssuer.SetAPIKeys(tenantClient)
+	}
+	authClientsRef := cfg.SecretsPath + "#auth_clients"
+	loadAuthClients := func(document string) {
+		clients, err := auth.ParseClients(document)
+		if err != nil {
+			logger.Error("failed to load auth clients", zap.Error(err))
+			return
+		}
+		tokenIssuer.SetClients(clients)
+	}
+	loadAuthClients(secretManager.LookupOr(context.Background(), authClientsRef, cfg.AuthClients))
+	secretManager.Watch(context.Background(), authClientsRef, 5*time.Minute, loadAuthClients)
+	router.POST("/v1/auth/token", handlers.IssueToken(tokenIssuer, logger))
+
+	// Tokens from an external OIDC provider are verified with its published keys
+	var tokenProvider middleware.TokenVerifier
+	if cfg.OIDCIssuerURL != "" {
+		tokenProvider = auth.NewOIDCVerifier(auth.OIDCConfig{
+			IssuerURL:   cfg.OIDCIssuerURL,
+			Audience:    cfg.OIDCAudience,
+			ClockSkew:   cfg.OIDCClockSkew,
+			TenantClaim: cfg.OIDCTenantClaim,
+			RoleClaim:   cfg.OIDCRoleClaim,
+			AdminRole:   cfg.OIDCAdminRole,
+			JWKSRefresh: cfg.OIDCJWKSRefresh,
+		}, &http.Client{Timeout: 5 * time.Second}, logger)
+		logger.Info("accepting OIDC tokens", zap.String("issuer", cfg.OIDCIssuerURL))
+	}
+
+	// API v1 and v2 routes
 	v1 := router.Group("/v1")
+	v2 := router.Group("/v2")
 	{
-		// Apply authentication and rate limiting
-		v1.Use(middleware.Auth(cfg.JWTSecret))
-		v1.Use(middleware.RateLimit(redisClient, 100, time.Minute))
+		for _, api := range []*gin.RouterGroup{v1, v2} {
+			api.Use(drainer.Middleware())
+			api.Use(runtimeSettings.Maintenance())
+			api.Use(middleware.RequestPriority())
+
+			// Apply authentication, tenant authorization and rate limiting
+			if tenantClient != nil {
+				api.Use(middleware.AuthWithProvider(signingKeys, tokenProvider, tenantClient))
+				api.Use(middleware.Tenants(tenantClient))
+				api.Use(middleware.TenantRateLimit(rateLimiter, rateTiers))
+			} else {
+				api.Use(middleware.AuthWithProvider(signingKeys, tokenProvider, nil))
+				api.Use(middleware.PlanRateLimit(rateLimiter, rateTiers))
+			}
+		}
 
 		// Inference endpoints
 		inferenceHandler := handlers.NewInferenceHandler(
@@ -83,15 +458,119 @@ func main() {
 			kafkaProducer,
 			cfg.KafkaTopic,
 		)
-		v1.POST("/infer", inferenceHandler.RealTimeInference)
-		v1.POST("/batch", inferenceHandler.BatchInference)
+		// Calls to the router fail fast while it is down instead of holding
+		// every caller's connection until the timeout
+		routerHTTP := &http.Client{Timeout: cfg.RouterTimeout}
+		if identity != nil {
+			routerHTTP = identity.HTTPClient("model-router", cfg.RouterTimeout)
+		}
+		routerResilience := resilience.DefaultConfig
+		routerResilience.MaxRetries = cfg.RouterMaxRetries
+		routerResilience.RetryBudget = cfg.RouterRetryBudget
+		routerResilience.BreakerOpenTimeout = cfg.RouterBreakerOpenTimeout
+		routerHTTP.Transport = resilience.NewTransport("model-router", routerHTTP.Transport, routerResilience, logger)
+		inferenceHandler.SetHTTPClient(routerHTTP)
+		inferenceHandler.SetUsageRecorder(usageRecorder)
+		inferenceHandler.SetSchemaCodec(schemaCodec)
+		inferenceHandler.SetBacklogGate(backlogGate)
+		inferenceHandler.SetCapture(trafficCapture)
+		inferenceHandler.SetResponseCache(responseCache)
+		inferenceHandler.SetVersionResolver(versionResolver)
+		inferenceHandler.SetPricing(modelPricing)
+		inferenceHandler.SetMaxStreamDuration(cfg.MaxStreamDuration)
+		inferenceHandler.SetMaxFanout(cfg.MaxFanoutTargets)
+		inferenceHandler.SetBatchWorker(batchWorkerClient(10*time.Second), cfg.BatchWorkerURL)
+		inferenceHandler.SetControlTopic(cfg.ControlTopic)
+		inferenceHandler.SetTenantTopics(cfg.TenantTopics)
+		inferenceHandler.SetSessionLimits(handlers.SessionLimits{
+			MaxInFlight:     cfg.SessionMaxInFlight,
+			MaxMessageBytes: cfg.SessionMaxMessageBytes,
+			IdleTimeout:     cfg.SessionIdleTimeout,
+			MaxDuration:     cfg.SessionMaxDuration,
+		})
+
+		// Inferences waiting on the router are bounded; the overflow queues
+		// briefly and is then turned away with 429
+		admit := func(c *gin.Context) { c.Next() }
+		if cfg.AdmissionMaxInFlight > 0 {
+			admit = middleware.Admission(admission.NewLimiter(admission.Config{
+				MaxInFlight:   cfg.AdmissionMaxInFlight,
+				MinInFlight:   cfg.AdmissionMinInFlight,
+				QueueSize:     cfg.AdmissionQueueSize,
+				MaxWait:       cfg.AdmissionMaxWait,
+				LatencyTarget: cfg.AdmissionLatencyTarget,
+			}))
+		}
+		modelLimit := middleware.ModelRateLimit(rateLimiter, modelLimits)
+		v1.POST("/infer", modelLimit, admit, inferenceHandler.RealTimeInference)
+		v1.POST("/infer/stream", modelLimit, inferenceHandler.StreamInference)
+		v1.POST("/infer/async", modelLimit, inferenceHandler.AsyncInference)
+		v1.POST("/infer/fanout", modelLimit, admit, inferenceHandler.FanoutInference)
+		v1.GET("/ws/infer", modelLimit, inferenceHandler.InferenceSession)
+		v1.POST("/batch", modelLimit, inferenceHandler.BatchInference)
+		v1.DELETE("/batch/:id", inferenceHandler.CancelJob)
+		v1.GET("/jobs", inferenceHandler.ListJobs)
 		v1.GET("/jobs/:id", inferenceHandler.GetJobStatus)
+		v1.GET("/jobs/:id/results", inferenceHandler.GetJobResults)
+
+		// v2 takes typed tensors, adapted to v1 requests
+		v2.POST("/infer", modelLimit, admit, inferenceHandler.RealTimeInferenceV2)
+
+		// Monthly quotas are part of tenants' plans
+		if tenantClient != nil {
+			quotaTracker := quota.NewTracker(quota.RedisCounters(redisClient), failurePolicy, logger)
+			inferenceHandler.SetQuotaTracker(quotaTracker)
+			v1.GET("/usage", handlers.QuotaUsage(quotaTracker))
+		}
+	}
+
+	// Admin routes for operators
+	adminGroup := router.Group("/admin")
+	{
+		adminGroup.Use(drainer.Middleware())
+		adminGroup.Use(middleware.AuthWithProvider(signingKeys, tokenProvider, nil))
+		adminGroup.Use(middleware.RequireRole("admin"))
+
+		adminGroup.GET("/overview", handlers.AdminOverview(aggregator))
+		adminGroup.GET("/topology", handlers.AdminTopology(aggregator))
+		adminGroup.Any("/faults", gin.WrapH(faultInjector.AdminHandler()))
+		adminGroup.GET("/webhooks/deliveries", gin.WrapH(webhookLog.Handler()))
+
+		// Runtime configuration, applied by every replica
+		adminGroup.GET("/config", handlers.AdminConfig(cfg.Redacted(), runtimeSettings))
+		adminGroup.PUT("/config/log-level", handlers.AdminSetLogLevel(runtimeSettings))
+		adminGroup.DELETE("/config/log-level", handlers.AdminSetLogLevel(runtimeSettings))
+		adminGroup.PUT("/config/rate-tiers", handlers.AdminSetRateTiers(runtimeSettings))
+		adminGroup.DELETE("/config/rate-tiers", handlers.AdminSetRateTiers(runtimeSettings))
+		adminGroup.PUT("/config/maintenance", handlers.AdminSetMaintenance(runtimeSettings))
+
+		// Data subject deletion; the batch worker holds stored inputs and results
+		privacyDeletions := handlers.PrivacyDeletions(batchWorkerClient(10*time.Second), cfg.BatchWorkerURL, logger)
+		adminGroup.POST("/privacy/deletions", privacyDeletions)
+		adminGroup.GET("/privacy/deletions/:id", privacyDeletions)
+
+		// Routing table changes; the router serves its admin API only to the
+		// gateway, and drains may wait up to ten minutes for requests in flight
+		routerAdminClient := &http.Client{Timeout: 11 * time.Minute}
+		if identity != nil {
+			routerAdminClient = identity.HTTPClient("model-router", 11*time.Minute)
+		}
+		routerAdmin := handlers.RouterAdmin(routerAdminClient, cfg.RouterServiceURL, logger)
+		adminGroup.Any("/router/backends", routerAdmin)
+		adminGroup.Any("/router/backends/*path", routerAdmin)
+
+		// Tenant, project, member and API key management
+		if tenantClient != nil {
+			tenantAdmin := handlers.TenantAdmin(tenantHTTPClient, cfg.TenantServiceURL, logger)
+			adminGroup.Any("/tenants", tenantAdmin)
+			adminGroup.Any("/tenants/*path", tenantAdmin)
+		}
 	}
 
 	// Create HTTP server
 	srv := &http.Server{
 		Addr:         ":" + cfg.Port,
-		Handler:      router,
+		Handler:      logging.Middleware(faultInjector.Middleware(compress.Middleware(router, cfg.CompressMinBytes))),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -112,13 +591,27 @@ func main() {
 
 	logger.Info("shutting down server...")
 
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Drain: fail readiness and turn new requests away, then wait for the
+	// requests and Kafka sends under way, bounded by the drain timeout
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DrainTimeout)
 	defer cancel()
-
+	drainer.Start()
 	if err := srv.Shutdown(ctx); err != nil {
-		logger.Fatal("server forced to shutdown", zap.Error(err))
+		logger.Error("server forced to shutdown", zap.Error(err))
+	}
+	if err := drainer.Wait(ctx); err != nil {
+		logger.Error("drain timed out", zap.Int("in_flight", drainer.InFlight()), zap.Error(err))
 	}
 
+	// Publish usage still buffered before the producer closes
+	stopUsage()
+	<-usageDone
+	stopAccessLog()
+	<-accessLogDone
+	stopCapture()
+	<-captureDone
+	stopWebhooks()
+	<-webhooksDone
+
 	logger.Info("server exited")
 }
@@ -0,0 +1,163 @@
+// Package accesslog publishes a structured event for each request the
+// gateway serves to Kafka for downstream analytics. Unlike the zap request
+// log, events have a registered schema and are sampled, so analytics can
+// consume them without parsing log lines.
+package accesslog
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+)
+
+// DefaultTopic is the Kafka topic access events are published to
+const DefaultTopic = "access-logs"
+
+// Context keys handlers set the model and version a request asked for under
+const (
+	ModelKey   = "access_model"
+	VersionKey = "access_version"
+)
+
+// Event describes one request
+type Event struct {
+	ID            string    `json:"id"`
+	Timestamp     time.Time `json:"timestamp"`
+	Service       string    `json:"service"`
+	RequestID     string    `json:"request_id,omitempty"`
+	TraceID       string    `json:"trace_id,omitempty"`
+	Tenant        string    `json:"tenant,omitempty"`
+	UserID        string    `json:"user_id,omitempty"`
+	Method        string    `json:"method"`
+	Route         string    `json:"route"`
+	Status        int       `json:"status"`
+	LatencyMs     int64     `json:"latency_ms"`
+	RequestBytes  int64     `json:"request_bytes"`
+	ResponseBytes int64     `json:"response_bytes"`
+	Model         string    `json:"model,omitempty"`
+	Version       string    `json:"version,omitempty"`
+	ClientIP      string    `json:"client_ip,omitempty"`
+	UserAgent     string    `json:"user_agent,omitempty"`
+}
+
+// Config controls which requests are logged
+type Config struct {
+	// SampleRate is the fraction of successful requests logged
+	SampleRate float64
+	// ErrorSampleRate is the fraction of requests answered with a 4xx or
+	// 5xx status logged, kept separate so failures can be logged in full
+	ErrorSampleRate float64
+}
+
+// Logger samples and publishes access events in the background, so logging
+// never adds latency to or fails a request. Events are dropped when the
+// buffer is full. A nil Logger logs nothing.
+type Logger struct {
+	cfg     Config
+	service string
+	queue   *publish.Queue[Event]
+	sample  func() float64
+}
+
+// NewLogger creates a logger for service that buffers up to size events,
+// published keyed by tenant so a tenant's events stay ordered
+func NewLogger(cfg Config, service string, publisher publish.Publisher, size int, logger *zap.Logger) *Logger {
+	return &Logger{
+		cfg:     cfg,
+		service: service,
+		queue: publish.NewQueue("access event", publisher, func(event Event) string {
+			return event.Tenant
+		}, size, logger),
+		sample: rand.Float64,
+	}
+}
+
+// Middleware logs each request once it has been answered
+func (l *Logger) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		if l == nil {
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		event := Event{
+			Timestamp:     start.UTC(),
+			Method:        c.Request.Method,
+			Route:         route,
+			Status:        c.Writer.Status(),
+			LatencyMs:     time.Since(start).Milliseconds(),
+			RequestBytes:  c.Request.ContentLength,
+			ResponseBytes: int64(c.Writer.Size()),
+			UserID:        c.GetString("user_id"),
+			Tenant:        c.GetString("tenant"),
+			Model:         c.GetString(ModelKey),
+			Version:       c.GetString(VersionKey),
+			ClientIP:      c.ClientIP(),
+			UserAgent:     c.Request.UserAgent(),
+		}
+		if event.RequestBytes < 0 {
+			event.RequestBytes = 0
+		}
+		if event.ResponseBytes < 0 {
+			event.ResponseBytes = 0
+		}
+		l.Record(c.Request.Context(), event)
+	}
+}
+
+// Record samples and queues an event. ID, service, request ID, trace ID and
+// tenant are filled in from the logger and the request context.
+func (l *Logger) Record(ctx context.Context, event Event) {
+	if l == nil {
+		return
+	}
+	rate := l.cfg.SampleRate
+	if event.Status >= 400 {
+		rate = l.cfg.ErrorSampleRate
+	}
+	if l.sample() >= rate {
+		return
+	}
+
+	fields := logging.FieldsFromContext(ctx)
+	event.ID = publish.NewID()
+	event.Service = l.service
+	if event.RequestID == "" {
+		event.RequestID = fields.RequestID
+	}
+	if event.TraceID == "" {
+		event.TraceID = fields.TraceID
+	}
+	if event.Tenant == "" {
+		event.Tenant = fields.Tenant
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	l.queue.Enqueue(event)
+}
+
+// Dropped returns how many sampled events were discarded because the buffer was full
+func (l *Logger) Dropped() int64 {
+	return l.queue.Dropped()
+}
+
+// Run publishes queued events until ctx is cancelled, then flushes what is left
+func (l *Logger) Run(ctx context.Context) {
+	if l == nil {
+		return
+	}
+	l.queue.Run(ctx)
+}
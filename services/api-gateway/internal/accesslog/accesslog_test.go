@@ -0,0 +1,105 @@
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+type recordingPublisher struct {
+	keys   []string
+	values [][]byte
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, key string, value []byte) error {
+	p.keys = append(p.keys, key)
+	p.values = append(p.values, value)
+	return nil
+}
+
+// drain publishes the queued events
+func drain(l *Logger) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.Run(ctx)
+}
+
+func TestMiddleware_LogsRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	publisher := &recordingPublisher{}
+	logger := NewLogger(Config{SampleRate: 1, ErrorSampleRate: 1}, "api-gateway", publisher, 10, zap.NewNop())
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(logging.NewContext(c.Request.Context(), logging.Fields{RequestID: "req-1", TraceID: "trace-1"}))
+		c.Next()
+	})
+	router.Use(logger.Middleware())
+	router.POST("/v1/infer", func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		c.Set("tenant", "acme")
+		c.Set(ModelKey, "resnet18")
+		c.Set(VersionKey, "v1")
+		c.String(http.StatusOK, "prediction")
+	})
+
+	req := httptest.NewRequest("POST", "/v1/infer", strings.NewReader(`{"model":"resnet18"}`))
+	req.Header.Set("User-Agent", "sdk/1.0")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	drain(logger)
+
+	require.Len(t, publisher.values, 1)
+	assert.Equal(t, "acme", publisher.keys[0])
+	assert.NoError(t, schema.AccessLogs.Validate(publisher.values[0]))
+
+	var event Event
+	require.NoError(t, json.Unmarshal(publisher.values[0], &event))
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, "api-gateway", event.Service)
+	assert.Equal(t, "req-1", event.RequestID)
+	assert.Equal(t, "trace-1", event.TraceID)
+	assert.Equal(t, "user-1", event.UserID)
+	assert.Equal(t, "/v1/infer", event.Route)
+	assert.Equal(t, http.StatusOK, event.Status)
+	assert.Equal(t, int64(20), event.RequestBytes)
+	assert.Equal(t, int64(10), event.ResponseBytes)
+	assert.Equal(t, "resnet18", event.Model)
+	assert.Equal(t, "v1", event.Version)
+	assert.Equal(t, "sdk/1.0", event.UserAgent)
+}
+
+func TestLogger_Samples(t *testing.T) {
+	publisher := &recordingPublisher{}
+	logger := NewLogger(Config{SampleRate: 0.5, ErrorSampleRate: 1}, "api-gateway", publisher, 10, zap.NewNop())
+	logger.sample = func() float64 { return 0.7 }
+
+	logger.Record(context.Background(), Event{Route: "/v1/infer", Status: http.StatusOK})
+	logger.Record(context.Background(), Event{Route: "/v1/infer", Status: http.StatusTooManyRequests})
+	drain(logger)
+
+	require.Len(t, publisher.values, 1)
+	var event Event
+	require.NoError(t, json.Unmarshal(publisher.values[0], &event))
+	assert.Equal(t, http.StatusTooManyRequests, event.Status)
+}
+
+func TestLogger_DropsWhenFull(t *testing.T) {
+	logger := NewLogger(Config{SampleRate: 1}, "api-gateway", &recordingPublisher{}, 1, zap.NewNop())
+	logger.Record(context.Background(), Event{Status: http.StatusOK})
+	logger.Record(context.Background(), Event{Status: http.StatusOK})
+	assert.Equal(t, int64(1), logger.Dropped())
+
+	var nilLogger *Logger
+	nilLogger.Record(context.Background(), Event{})
+}
@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
+// KafkaQueueInspector reports consumer lag for a consumer group's topics
+type KafkaQueueInspector struct {
+	client sarama.Client
+	admin  sarama.ClusterAdmin
+	topics []string
+	group  string
+}
+
+// NewKafkaQueueInspector connects to the brokers to read offsets for topics and group
+func NewKafkaQueueInspector(brokers []string, topics []string, group string) (*KafkaQueueInspector, error) {
+	client, err := sarama.NewClient(brokers, sarama.NewConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to create kafka cluster admin: %w", err)
+	}
+
+	return &KafkaQueueInspector{
+		client: client,
+		admin:  admin,
+		topics: topics,
+		group:  group,
+	}, nil
+}
+
+// QueueDepths returns each topic's consumer lag summed over its partitions.
+// sarama calls are not context aware; the caller's timeout bounds only the wait.
+func (k *KafkaQueueInspector) QueueDepths(ctx context.Context) ([]QueueDepth, error) {
+	type result struct {
+		depths []QueueDepth
+		err    error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		var depths []QueueDepth
+		for _, topic := range k.topics {
+			depth, err := k.lag(topic)
+			if err != nil {
+				done <- result{nil, fmt.Errorf("%s: %w", topic, err)}
+				return
+			}
+			depths = append(depths, depth)
+		}
+		done <- result{depths, nil}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.depths, r.err
+	}
+}
+
+func (k *KafkaQueueInspector) lag(topic string) (QueueDepth, error) {
+	depth := QueueDepth{Topic: topic, ConsumerGroup: k.group}
+
+	partitions, err := k.client.Partitions(topic)
+	if err != nil {
+		return depth, fmt.Errorf("failed to list partitions: %w", err)
+	}
+	depth.Partitions = len(partitions)
+
+	committed, err := k.admin.ListConsumerGroupOffsets(k.group, map[string][]int32{topic: partitions})
+	if err != nil {
+		return depth, fmt.Errorf("failed to fetch consumer group offsets: %w", err)
+	}
+
+	for _, partition := range partitions {
+		newest, err := k.client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return depth, fmt.Errorf("failed to fetch offset for partition %d: %w", partition, err)
+		}
+
+		// A group that never committed still has every retained message to consume
+		block := committed.GetBlock(topic, partition)
+		consumed := int64(0)
+		if block != nil && block.Offset >= 0 {
+			consumed = block.Offset
+		} else if consumed, err = k.client.GetOffset(topic, partition, sarama.OffsetOldest); err != nil {
+			return depth, fmt.Errorf("failed to fetch offset for partition %d: %w", partition, err)
+		}
+		if newest > consumed {
+			depth.Lag += newest - consumed
+		}
+	}
+
+	return depth, nil
+}
+
+// Close releases the Kafka connections
+func (k *KafkaQueueInspector) Close() error {
+	return k.admin.Close()
+}
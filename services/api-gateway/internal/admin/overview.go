@@ -0,0 +1,248 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Overview is the aggregated operational state of the platform.
+// Sources that could not be reached are listed in Errors; the rest are still reported.
+type Overview struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Models      *ModelSummary     `json:"models,omitempty"`
+	Backends    []BackendStatus   `json:"backends,omitempty"`
+	Queues      []QueueDepth      `json:"queues,omitempty"`
+	Jobs        map[string]int64  `json:"jobs,omitempty"`
+	Errors      map[string]string `json:"errors,omitempty"`
+}
+
+// ModelSummary aggregates the model registry
+type ModelSummary struct {
+	Total         int            `json:"total"`
+	ByStatus      map[string]int `json:"by_status"`
+	TotalRequests int64          `json:"total_requests"`
+	Models        []ModelStats   `json:"models"`
+}
+
+// ModelStats is the per-model view reported by the metadata service
+type ModelStats struct {
+	Name         string  `json:"name"`
+	Version      string  `json:"version"`
+	Status       string  `json:"status"`
+	RequestCount int64   `json:"request_count"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	ErrorRate    float64 `json:"error_rate"`
+}
+
+// BackendStatus is a model router backend and its health
+type BackendStatus struct {
+	Model        string    `json:"model"`
+	Version      string    `json:"version"`
+	URL          string    `json:"url"`
+	Weight       float64   `json:"weight"`
+	Healthy      bool      `json:"healthy"`
+	CircuitState string    `json:"circuit_state"`
+	AvgLatencyMs int64     `json:"avg_latency_ms"`
+	LastCheck    time.Time `json:"last_check"`
+}
+
+// QueueDepth is the backlog of a consumer group on a topic
+type QueueDepth struct {
+	Topic         string `json:"topic"`
+	ConsumerGroup string `json:"consumer_group"`
+	Partitions    int    `json:"partitions"`
+	Lag           int64  `json:"lag"`
+}
+
+// QueueInspector reports queue depths, e.g. Kafka consumer lag
+type QueueInspector interface {
+	QueueDepths(ctx context.Context) ([]QueueDepth, error)
+}
+
+// Endpoint is a service the aggregator queries over HTTP
+type Endpoint struct {
+	URL    string
+	Client *http.Client
+}
+
+// Sources configures where the aggregator collects data from
+type Sources struct {
+	Metadata    Endpoint
+	Router      Endpoint
+	BatchWorker Endpoint
+	Queues      QueueInspector
+
+	// Orchestrators calls the orchestrators discovered through the router
+	Orchestrators *http.Client
+}
+
+// Source names used as keys in Overview.Errors
+const (
+	SourceModels   = "models"
+	SourceBackends = "backends"
+	SourceQueues   = "queues"
+	SourceJobs     = "jobs"
+	SourceRegions  = "regions"
+)
+
+// maxModels bounds the registry page read from the metadata service
+const maxModels = 100
+
+// Aggregator collects state from the other services into an Overview
+type Aggregator struct {
+	sources Sources
+	timeout time.Duration
+	logger  *zap.Logger
+}
+
+// NewAggregator creates an aggregator that gives each source up to timeout to answer
+func NewAggregator(sources Sources, timeout time.Duration, logger *zap.Logger) *Aggregator {
+	for _, endpoint := range []*Endpoint{&sources.Metadata, &sources.Router, &sources.BatchWorker} {
+		if endpoint.Client == nil {
+			endpoint.Client = &http.Client{Timeout: timeout}
+		}
+	}
+	if sources.Orchestrators == nil {
+		sources.Orchestrators = &http.Client{Timeout: timeout}
+	}
+	return &Aggregator{
+		sources: sources,
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// Overview queries all sources concurrently
+func (a *Aggregator) Overview(ctx context.Context) *Overview {
+	overview := &Overview{GeneratedAt: time.Now().UTC()}
+
+	overview.Errors = a.gather(ctx, map[string]func(ctx context.Context) error{
+		SourceModels: func(ctx context.Context) (err error) {
+			overview.Models, err = a.models(ctx)
+			return err
+		},
+		SourceBackends: func(ctx context.Context) (err error) {
+			overview.Backends, err = a.backends(ctx)
+			return err
+		},
+		SourceQueues: func(ctx context.Context) (err error) {
+			if a.sources.Queues == nil {
+				return fmt.Errorf("queue inspector not configured")
+			}
+			overview.Queues, err = a.sources.Queues.QueueDepths(ctx)
+			return err
+		},
+		SourceJobs: func(ctx context.Context) (err error) {
+			overview.Jobs, err = a.jobs(ctx)
+			return err
+		},
+	})
+	return overview
+}
+
+// gather runs the fetches concurrently, giving each up to the aggregator's
+// timeout, and returns the errors of those that failed keyed by source
+func (a *Aggregator) gather(ctx context.Context, fetches map[string]func(ctx context.Context) error) map[string]string {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var errs map[string]string
+
+	for source, fetch := range fetches {
+		wg.Add(1)
+		go func(source string, fetch func(ctx context.Context) error) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, a.timeout)
+			defer cancel()
+
+			if err := fetch(ctx); err != nil {
+				logging.With(ctx, a.logger).Warn("admin source failed",
+					zap.String("source", source),
+					zap.Error(err),
+				)
+				mu.Lock()
+				if errs == nil {
+					errs = make(map[string]string)
+				}
+				errs[source] = err.Error()
+				mu.Unlock()
+			}
+		}(source, fetch)
+	}
+
+	wg.Wait()
+	return errs
+}
+
+func (a *Aggregator) models(ctx context.Context) (*ModelSummary, error) {
+	var body struct {
+		Models []ModelStats `json:"models"`
+	}
+	url := fmt.Sprintf("%s/v1/models?limit=%d", a.sources.Metadata.URL, maxModels)
+	if err := getJSON(ctx, a.sources.Metadata, url, "metadata-service", &body); err != nil {
+		return nil, err
+	}
+
+	summary := &ModelSummary{
+		Total:    len(body.Models),
+		ByStatus: make(map[string]int),
+		Models:   body.Models,
+	}
+	for _, model := range body.Models {
+		summary.ByStatus[model.Status]++
+		summary.TotalRequests += model.RequestCount
+	}
+	return summary, nil
+}
+
+func (a *Aggregator) backends(ctx context.Context) ([]BackendStatus, error) {
+	var body struct {
+		Backends []BackendStatus `json:"backends"`
+	}
+	if err := getJSON(ctx, a.sources.Router, a.sources.Router.URL+"/v1/backends", "model-router", &body); err != nil {
+		return nil, err
+	}
+	return body.Backends, nil
+}
+
+func (a *Aggregator) jobs(ctx context.Context) (map[string]int64, error) {
+	var body struct {
+		Jobs map[string]int64 `json:"jobs"`
+	}
+	if err := getJSON(ctx, a.sources.BatchWorker, a.sources.BatchWorker.URL+"/v1/jobs/stats", "batch-worker", &body); err != nil {
+		return nil, err
+	}
+	return body.Jobs, nil
+}
+
+// getJSON fetches url from a platform service and decodes the response into out
+func getJSON(ctx context.Context, endpoint Endpoint, url, service string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	logging.Inject(ctx, req)
+
+	resp, err := endpoint.Client.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, service)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.FromHTTPResponse(resp, service)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", service, err)
+	}
+	return nil
+}
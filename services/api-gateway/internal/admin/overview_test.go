@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/servicetest"
+)
+
+type fakeQueues struct {
+	depths []QueueDepth
+	err    error
+}
+
+func (f *fakeQueues) QueueDepths(ctx context.Context) ([]QueueDepth, error) {
+	return f.depths, f.err
+}
+
+func TestOverview_AggregatesSources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			w.Write([]byte(`{"models":[
+				{"name":"resnet18","version":"v1","status":"active","request_count":10},
+				{"name":"resnet18","version":"v2","status":"active","request_count":5},
+				{"name":"bert","version":"v1","status":"deprecated","request_count":1}]}`))
+		case "/v1/backends":
+			w.Write([]byte(`{"backends":[{"model":"resnet18","version":"v1","url":"http://orchestrator:8082","healthy":true,"circuit_state":"closed"}]}`))
+		case "/v1/jobs/stats":
+			w.Write([]byte(`{"jobs":{"pending":2,"completed":7}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	queues := &fakeQueues{depths: []QueueDepth{{Topic: "inference-jobs", ConsumerGroup: "batch-worker-group", Partitions: 3, Lag: 4}}}
+	aggregator := NewAggregator(Sources{
+		Metadata:    Endpoint{URL: server.URL},
+		Router:      Endpoint{URL: server.URL},
+		BatchWorker: Endpoint{URL: server.URL},
+		Queues:      queues,
+	}, time.Second, zap.NewNop())
+
+	overview := aggregator.Overview(context.Background())
+
+	assert.Empty(t, overview.Errors)
+	assert.Equal(t, 3, overview.Models.Total)
+	assert.Equal(t, 2, overview.Models.ByStatus["active"])
+	assert.Equal(t, int64(16), overview.Models.TotalRequests)
+	assert.Len(t, overview.Backends, 1)
+	assert.Equal(t, "closed", overview.Backends[0].CircuitState)
+	assert.Equal(t, int64(4), overview.Queues[0].Lag)
+	assert.Equal(t, int64(7), overview.Jobs["completed"])
+}
+
+func TestOverview_ReportsUnreachableSources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"backends":[]}`))
+	}))
+	defer server.Close()
+
+	downURL := servicetest.Down(t).URL
+
+	aggregator := NewAggregator(Sources{
+		Metadata:    Endpoint{URL: downURL},
+		Router:      Endpoint{URL: server.URL},
+		BatchWorker: Endpoint{URL: downURL},
+	}, time.Second, zap.NewNop())
+
+	overview := aggregator.Overview(context.Background())
+
+	assert.Contains(t, overview.Errors, SourceModels)
+	assert.Contains(t, overview.Errors, SourceJobs)
+	assert.Contains(t, overview.Errors, SourceQueues)
+	assert.NotContains(t, overview.Errors, SourceBackends)
+	assert.Nil(t, overview.Models)
+}
@@ -0,0 +1,232 @@
+package admin
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Topology is what is serving what, where: the local region and the peers it
+// replicates the registry from, the router and the orchestrators it routes to
+// with the models loaded on their backends, and where each model version is
+// served. Sources that could not be reached are listed in Errors; an
+// orchestrator that could not be reached carries its own error.
+type Topology struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Region      string            `json:"region,omitempty"`
+	Peers       []RegionPeer      `json:"peers,omitempty"`
+	Routers     []RouterNode      `json:"routers,omitempty"`
+	Models      []ModelPlacement  `json:"models"`
+	Errors      map[string]string `json:"errors,omitempty"`
+}
+
+// RegionPeer is a peer region and the health of replication from it
+type RegionPeer struct {
+	Region    string    `json:"region"`
+	URL       string    `json:"url"`
+	LastSync  time.Time `json:"last_sync"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// RouterNode is a model router and the orchestrators it routes to
+type RouterNode struct {
+	URL           string             `json:"url"`
+	Orchestrators []OrchestratorNode `json:"orchestrators"`
+}
+
+// OrchestratorNode is an inference orchestrator and the backend it executes on
+type OrchestratorNode struct {
+	URL     string        `json:"url"`
+	Backend string        `json:"backend,omitempty"`
+	Pool    string        `json:"pool,omitempty"`
+	Models  []LoadedModel `json:"models"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// LoadedModel is a model version in a backend's repository and its load state
+type LoadedModel struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	State   string `json:"state,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// ModelPlacement is a model version and the orchestrators serving it
+type ModelPlacement struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Status is the registry status, empty for versions routed but not registered
+	Status string  `json:"status,omitempty"`
+	Routes []Route `json:"routes"`
+}
+
+// Route is an orchestrator a model version is routed to
+type Route struct {
+	Orchestrator string  `json:"orchestrator"`
+	Weight       float64 `json:"weight"`
+	Healthy      bool    `json:"healthy"`
+	CircuitState string  `json:"circuit_state"`
+	// State is the version's load state on the orchestrator's backend, empty when unknown
+	State string `json:"state,omitempty"`
+}
+
+// Topology assembles the serving topology from the registry, the router's
+// routing table and the orchestrators it routes to
+func (a *Aggregator) Topology(ctx context.Context) *Topology {
+	topology := &Topology{GeneratedAt: time.Now().UTC()}
+
+	var registry *ModelSummary
+	var backends []BackendStatus
+	topology.Errors = a.gather(ctx, map[string]func(ctx context.Context) error{
+		SourceRegions: func(ctx context.Context) error {
+			var body struct {
+				Region string       `json:"region"`
+				Peers  []RegionPeer `json:"peers"`
+			}
+			if err := getJSON(ctx, a.sources.Metadata, a.sources.Metadata.URL+"/v1/replication/status", "metadata-service", &body); err != nil {
+				return err
+			}
+			topology.Region, topology.Peers = body.Region, body.Peers
+			return nil
+		},
+		SourceModels: func(ctx context.Context) (err error) {
+			registry, err = a.models(ctx)
+			return err
+		},
+		SourceBackends: func(ctx context.Context) (err error) {
+			backends, err = a.backends(ctx)
+			return err
+		},
+	})
+
+	// Orchestrators are discovered through the router's routing table
+	if _, failed := topology.Errors[SourceBackends]; !failed {
+		var urls []string
+		seen := make(map[string]bool)
+		for _, backend := range backends {
+			if !seen[backend.URL] {
+				seen[backend.URL] = true
+				urls = append(urls, backend.URL)
+			}
+		}
+		sort.Strings(urls)
+
+		nodes := make([]OrchestratorNode, len(urls))
+		var wg sync.WaitGroup
+		for i, url := range urls {
+			wg.Add(1)
+			go func(i int, url string) {
+				defer wg.Done()
+				nodes[i] = a.orchestrator(ctx, url)
+			}(i, url)
+		}
+		wg.Wait()
+
+		topology.Routers = []RouterNode{{URL: a.sources.Router.URL, Orchestrators: nodes}}
+	}
+
+	topology.Models = placements(registry, backends, topology.Routers)
+	return topology
+}
+
+// orchestrator describes the orchestrator at url and the models on its backend
+func (a *Aggregator) orchestrator(ctx context.Context, url string) OrchestratorNode {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	node := OrchestratorNode{URL: url, Models: []LoadedModel{}}
+	var body struct {
+		Backend string        `json:"backend"`
+		Pool    string        `json:"pool"`
+		Models  []LoadedModel `json:"models"`
+	}
+	endpoint := Endpoint{URL: url, Client: a.sources.Orchestrators}
+	if err := getJSON(ctx, endpoint, url+"/v1/models", "inference-orchestrator", &body); err != nil {
+		logging.With(ctx, a.logger).Warn("failed to describe orchestrator",
+			zap.String("url", url),
+			zap.Error(err),
+		)
+		node.Error = err.Error()
+		return node
+	}
+
+	node.Backend, node.Pool = body.Backend, body.Pool
+	if body.Models != nil {
+		node.Models = body.Models
+	}
+	return node
+}
+
+// placements lists every registered or routed model version with the
+// orchestrators it is routed to, ordered by name and version
+func placements(registry *ModelSummary, backends []BackendStatus, routers []RouterNode) []ModelPlacement {
+	type key struct{ name, version string }
+	byKey := make(map[key]*ModelPlacement)
+	place := func(name, version string) *ModelPlacement {
+		k := key{name, version}
+		if byKey[k] == nil {
+			byKey[k] = &ModelPlacement{Name: name, Version: version, Routes: []Route{}}
+		}
+		return byKey[k]
+	}
+
+	if registry != nil {
+		for _, model := range registry.Models {
+			place(model.Name, model.Version).Status = model.Status
+		}
+	}
+
+	loaded := make(map[string][]LoadedModel)
+	for _, router := range routers {
+		for _, node := range router.Orchestrators {
+			loaded[node.URL] = node.Models
+		}
+	}
+	for _, backend := range backends {
+		placement := place(backend.Model, backend.Version)
+		placement.Routes = append(placement.Routes, Route{
+			Orchestrator: backend.URL,
+			Weight:       backend.Weight,
+			Healthy:      backend.Healthy,
+			CircuitState: backend.CircuitState,
+			State:        loadState(loaded[backend.URL], backend.Model, backend.Version),
+		})
+	}
+
+	result := make([]ModelPlacement, 0, len(byKey))
+	for _, placement := range byKey {
+		result = append(result, *placement)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Version < result[j].Version
+	})
+	return result
+}
+
+// loadState finds a model version's state on a backend. Triton numbers
+// versions ("1") where the platform names them ("v1"); a repository entry
+// without a version stands for every version of the model.
+func loadState(models []LoadedModel, name, version string) string {
+	state := ""
+	for _, model := range models {
+		if model.Name != name {
+			continue
+		}
+		if strings.TrimPrefix(model.Version, "v") == strings.TrimPrefix(version, "v") {
+			return model.State
+		}
+		if model.Version == "" {
+			state = model.State
+		}
+	}
+	return state
+}
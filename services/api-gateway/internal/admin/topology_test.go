@@ -0,0 +1,109 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/servicetest"
+)
+
+func TestTopology_AssemblesRegistryRoutesAndBackends(t *testing.T) {
+	orchestrator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/models", r.URL.Path)
+		w.Write([]byte(`{"backend":"triton:8001","pool":"gpu-a100","models":[
+			{"name":"resnet18","version":"1","state":"READY"},
+			{"name":"resnet18","version":"2","state":"UNAVAILABLE","reason":"unloaded"}]}`))
+	}))
+	defer orchestrator.Close()
+
+	downURL := servicetest.Down(t).URL
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/replication/status":
+			w.Write([]byte(`{"region":"eu-west","peers":[{"region":"us-east","url":"http://metadata.us-east","last_error":"timeout"}]}`))
+		case "/v1/models":
+			w.Write([]byte(`{"models":[
+				{"name":"resnet18","version":"v1","status":"active"},
+				{"name":"resnet18","version":"v2","status":"active"},
+				{"name":"bert","version":"v1","status":"deprecated"}]}`))
+		case "/v1/backends":
+			w.Write([]byte(`{"backends":[
+				{"model":"resnet18","version":"v1","url":"` + orchestrator.URL + `","weight":0.5,"healthy":true,"circuit_state":"closed"},
+				{"model":"resnet18","version":"v1","url":"` + downURL + `","weight":0.5,"healthy":false,"circuit_state":"open"},
+				{"model":"resnet18","version":"v2","url":"` + orchestrator.URL + `","weight":1,"healthy":true,"circuit_state":"closed"},
+				{"model":"llama","version":"v1","url":"` + orchestrator.URL + `","weight":1,"healthy":true,"circuit_state":"closed"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	aggregator := NewAggregator(Sources{
+		Metadata: Endpoint{URL: server.URL},
+		Router:   Endpoint{URL: server.URL},
+	}, time.Second, zap.NewNop())
+
+	topology := aggregator.Topology(context.Background())
+
+	assert.Empty(t, topology.Errors)
+	assert.Equal(t, "eu-west", topology.Region)
+	require.Len(t, topology.Peers, 1)
+	assert.Equal(t, "timeout", topology.Peers[0].LastError)
+
+	require.Len(t, topology.Routers, 1)
+	nodes := topology.Routers[0].Orchestrators
+	require.Len(t, nodes, 2)
+	byURL := map[string]OrchestratorNode{nodes[0].URL: nodes[0], nodes[1].URL: nodes[1]}
+	assert.Equal(t, "gpu-a100", byURL[orchestrator.URL].Pool)
+	assert.Len(t, byURL[orchestrator.URL].Models, 2)
+	assert.NotEmpty(t, byURL[downURL].Error)
+	assert.Empty(t, byURL[downURL].Models)
+
+	require.Len(t, topology.Models, 4)
+	assert.Equal(t, ModelPlacement{Name: "bert", Version: "v1", Status: "deprecated", Routes: []Route{}}, topology.Models[0])
+	assert.Equal(t, "llama", topology.Models[1].Name)
+	assert.Empty(t, topology.Models[1].Status, "routed but not registered")
+	assert.Empty(t, topology.Models[1].Routes[0].State)
+
+	v1 := topology.Models[2]
+	require.Len(t, v1.Routes, 2)
+	states := map[string]string{v1.Routes[0].Orchestrator: v1.Routes[0].State, v1.Routes[1].Orchestrator: v1.Routes[1].State}
+	assert.Equal(t, "READY", states[orchestrator.URL])
+	assert.Empty(t, states[downURL])
+	assert.Equal(t, "UNAVAILABLE", topology.Models[3].Routes[0].State)
+}
+
+func TestTopology_RouterUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			w.Write([]byte(`{"models":[{"name":"resnet18","version":"v1","status":"active"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	downURL := servicetest.Down(t).URL
+
+	aggregator := NewAggregator(Sources{
+		Metadata: Endpoint{URL: server.URL},
+		Router:   Endpoint{URL: downURL},
+	}, time.Second, zap.NewNop())
+
+	topology := aggregator.Topology(context.Background())
+
+	assert.Contains(t, topology.Errors, SourceBackends)
+	assert.Contains(t, topology.Errors, SourceRegions)
+	assert.Nil(t, topology.Routers)
+	require.Len(t, topology.Models, 1)
+	assert.Empty(t, topology.Models[0].Routes)
+}
@@ -0,0 +1,179 @@
+// Package admission bounds the inferences the gateway has in flight. Requests
+// over the limit wait in a bounded queue and are turned away once it is full,
+// so a load spike costs callers a quick 429 instead of growing goroutines and
+// memory until the gateway falls over.
+package admission
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Config tunes a Limiter
+type Config struct {
+	// MaxInFlight is the most requests processed at once, and where an
+	// adaptive limit starts
+	MaxInFlight int
+	// MinInFlight is the lowest an adaptive limit falls to
+	MinInFlight int
+	// QueueSize is the most requests waiting for a slot; more are rejected
+	QueueSize int
+	// MaxWait is the longest a request waits in the queue
+	MaxWait time.Duration
+	// LatencyTarget makes the limit adaptive: it grows by one slot per
+	// limit's worth of requests finishing within the target and shrinks by
+	// a tenth when one is slower or downstream is overloaded. Zero keeps the
+	// limit at MaxInFlight.
+	LatencyTarget time.Duration
+}
+
+// Errors for requests turned away
+var (
+	ErrQueueFull    = apperrors.New(apperrors.ResourceExhausted, "inference queue is full")
+	ErrQueueTimeout = apperrors.New(apperrors.ResourceExhausted, "timed out waiting in the inference queue")
+)
+
+// decrease is the share of the limit kept after an overload signal
+const decrease = 0.9
+
+// Stats describe a limiter at one moment
+type Stats struct {
+	Limit    int `json:"limit"`
+	InFlight int `json:"in_flight"`
+	Queued   int `json:"queued"`
+}
+
+// Limiter admits requests up to a concurrency limit, queueing the overflow
+// in arrival order. A nil Limiter admits every request.
+type Limiter struct {
+	config Config
+
+	mu           sync.Mutex
+	limit        float64
+	inFlight     int
+	waiters      *list.List
+	lastDecrease time.Time
+	now          func() time.Time
+}
+
+// NewLimiter creates a limiter starting at config.MaxInFlight
+func NewLimiter(config Config) *Limiter {
+	if config.MinInFlight <= 0 || config.MinInFlight > config.MaxInFlight {
+		config.MinInFlight = config.MaxInFlight
+	}
+	return &Limiter{
+		config:  config,
+		limit:   float64(config.MaxInFlight),
+		waiters: list.New(),
+		now:     time.Now,
+	}
+}
+
+// Release hands a request's slot back, reporting how long it took and
+// whether downstream was overloaded
+type Release func(latency time.Duration, overloaded bool)
+
+// Acquire waits for a slot for a request. Requests finding the queue full fail
+// with ErrQueueFull, those waiting past MaxWait with ErrQueueTimeout, and
+// those whose ctx ends while queued with its error. The returned Release must
+// be called once the request finishes.
+func (l *Limiter) Acquire(ctx context.Context) (Release, error) {
+	if l == nil {
+		return func(time.Duration, bool) {}, nil
+	}
+
+	l.mu.Lock()
+	if l.inFlight < l.current() && l.waiters.Len() == 0 {
+		l.inFlight++
+		l.mu.Unlock()
+		return l.release, nil
+	}
+	if l.waiters.Len() >= l.config.QueueSize {
+		l.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+	ready := make(chan struct{})
+	waiter := l.waiters.PushBack(ready)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.config.MaxWait)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-ready:
+		return l.release, nil
+	case <-timer.C:
+		err = ErrQueueTimeout
+	case <-ctx.Done():
+		err = apperrors.FromTransportError(ctx.Err(), "inference queue")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-ready:
+		// The slot was handed over as the wait ended
+		return l.release, nil
+	default:
+		l.waiters.Remove(waiter)
+		return nil, err
+	}
+}
+
+// RetryAfter suggests how long rejected requests should wait, the queue's
+// longest wait rounded up to whole seconds
+func (l *Limiter) RetryAfter() time.Duration {
+	seconds := math.Ceil(l.config.MaxWait.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Stats reports the limit and the requests in flight and queued
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return Stats{Limit: l.current(), InFlight: l.inFlight, Queued: l.waiters.Len()}
+}
+
+func (l *Limiter) release(latency time.Duration, overloaded bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.adapt(latency, overloaded)
+	l.inFlight--
+	for l.waiters.Len() > 0 && l.inFlight < l.current() {
+		l.inFlight++
+		close(l.waiters.Remove(l.waiters.Front()).(chan struct{}))
+	}
+}
+
+// adapt moves an adaptive limit: additively up while requests are fast,
+// multiplicatively down when they are not. Decreases are spaced by the
+// latency target so one slow burst shrinks the limit once.
+func (l *Limiter) adapt(latency time.Duration, overloaded bool) {
+	if l.config.LatencyTarget <= 0 {
+		return
+	}
+	if overloaded || latency > l.config.LatencyTarget {
+		now := l.now()
+		if now.Sub(l.lastDecrease) < l.config.LatencyTarget {
+			return
+		}
+		l.lastDecrease = now
+		l.limit = math.Max(float64(l.config.MinInFlight), l.limit*decrease)
+		return
+	}
+	l.limit = math.Min(float64(l.config.MaxInFlight), l.limit+1/l.limit)
+}
+
+// current is the limit in whole requests
+func (l *Limiter) current() int {
+	return int(l.limit)
+}
@@ -0,0 +1,104 @@
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestLimiter_QueuesOverflow(t *testing.T) {
+	limiter := NewLimiter(Config{MaxInFlight: 1, QueueSize: 1, MaxWait: 5 * time.Second})
+
+	release, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+
+	admitted := make(chan error)
+	go func() {
+		_, err := limiter.Acquire(context.Background())
+		admitted <- err
+	}()
+	assert.Eventually(t, func() bool { return limiter.Stats().Queued == 1 }, time.Second, time.Millisecond)
+
+	// The queue holds one request; the next is turned away at once
+	_, err = limiter.Acquire(context.Background())
+	assert.ErrorIs(t, err, ErrQueueFull)
+	assert.True(t, apperrors.Is(err, apperrors.ResourceExhausted))
+
+	// A finished request hands its slot to the queued one
+	release(time.Millisecond, false)
+	assert.NoError(t, <-admitted)
+	assert.Equal(t, Stats{Limit: 1, InFlight: 1, Queued: 0}, limiter.Stats())
+}
+
+func TestLimiter_QueueWaitEnds(t *testing.T) {
+	limiter := NewLimiter(Config{MaxInFlight: 1, QueueSize: 10, MaxWait: 10 * time.Millisecond})
+	_, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+
+	_, err = limiter.Acquire(context.Background())
+	assert.ErrorIs(t, err, ErrQueueTimeout)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = limiter.Acquire(ctx)
+	assert.True(t, apperrors.Is(err, apperrors.Canceled))
+	assert.Equal(t, 0, limiter.Stats().Queued)
+}
+
+func TestLimiter_AdaptsToLatency(t *testing.T) {
+	limiter := NewLimiter(Config{MaxInFlight: 10, MinInFlight: 2, QueueSize: 10, MaxWait: time.Second, LatencyTarget: 100 * time.Millisecond})
+	now := time.Unix(0, 0)
+	limiter.now = func() time.Time { return now }
+
+	finish := func(latency time.Duration, overloaded bool) {
+		release, err := limiter.Acquire(context.Background())
+		require.NoError(t, err)
+		release(latency, overloaded)
+	}
+
+	// A burst of slow requests shrinks the limit once
+	now = now.Add(time.Second)
+	finish(time.Second, false)
+	finish(time.Second, false)
+	assert.Equal(t, 9, limiter.Stats().Limit)
+
+	// Overload shrinks it again once the latency target has passed
+	now = now.Add(time.Second)
+	finish(time.Millisecond, true)
+	assert.Equal(t, 8, limiter.Stats().Limit)
+
+	// Fast requests earn back one slot per limit's worth
+	for i := 0; i < 9; i++ {
+		finish(time.Millisecond, false)
+	}
+	assert.Equal(t, 9, limiter.Stats().Limit)
+
+	// The limit stays within its bounds
+	for i := 0; i < 100; i++ {
+		finish(time.Millisecond, false)
+	}
+	assert.Equal(t, 10, limiter.Stats().Limit)
+	for i := 0; i < 100; i++ {
+		now = now.Add(time.Second)
+		finish(time.Second, false)
+	}
+	assert.Equal(t, 2, limiter.Stats().Limit)
+}
+
+func TestLimiter_FixedLimit(t *testing.T) {
+	limiter := NewLimiter(Config{MaxInFlight: 4, QueueSize: 1, MaxWait: time.Second})
+	release, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+	release(time.Hour, true)
+	assert.Equal(t, 4, limiter.Stats().Limit)
+
+	var nilLimiter *Limiter
+	release, err = nilLimiter.Acquire(context.Background())
+	assert.NoError(t, err)
+	release(0, false)
+}
@@ -0,0 +1,321 @@
+// Package apiv2 defines the typed contracts of the gateway's /v2 API and
+// adapts them to the /v1 form the model router takes. Inputs and outputs are
+// tensors with an explicit datatype and shape, their data flattened in
+// row-major order.
+package apiv2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Datatype is the element type of a tensor
+type Datatype string
+
+const (
+	Bool   Datatype = "BOOL"
+	Int8   Datatype = "INT8"
+	Int16  Datatype = "INT16"
+	Int32  Datatype = "INT32"
+	Int64  Datatype = "INT64"
+	Uint8  Datatype = "UINT8"
+	Uint16 Datatype = "UINT16"
+	Uint32 Datatype = "UINT32"
+	Uint64 Datatype = "UINT64"
+	FP32   Datatype = "FP32"
+	FP64   Datatype = "FP64"
+	Bytes  Datatype = "BYTES"
+	// JSON holds one value of any shape, for inputs and outputs that are
+	// not tensors. Its shape is always [].
+	JSON Datatype = "JSON"
+)
+
+// Tensor is a named input or output
+type Tensor struct {
+	Name     string   `json:"name"`
+	Datatype Datatype `json:"datatype"`
+	Shape    []int64  `json:"shape"`
+	// Data holds the elements in row-major order
+	Data []interface{} `json:"data"`
+}
+
+// InferRequest is the body of POST /v2/infer
+type InferRequest struct {
+	Model   string   `json:"model"`
+	Version string   `json:"version,omitempty"`
+	Inputs  []Tensor `json:"inputs"`
+}
+
+// InferResponse is the answer to an InferRequest
+type InferResponse struct {
+	RequestID string   `json:"request_id"`
+	Model     string   `json:"model"`
+	Version   string   `json:"version"`
+	Outputs   []Tensor `json:"outputs"`
+	LatencyMs int64    `json:"latency_ms"`
+	// EstimatedCost is the inference's cost at the gateway's model pricing
+	EstimatedCost float64 `json:"estimated_cost,omitempty"`
+}
+
+// Decode reads a request from r. Unlike /v1, unknown fields are rejected.
+func Decode(r io.Reader) (*InferRequest, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	decoder.UseNumber()
+
+	var req InferRequest
+	if err := decoder.Decode(&req); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the request")
+	}
+	if req.Model == "" {
+		return nil, errors.New("model is required")
+	}
+	if len(req.Inputs) == 0 {
+		return nil, errors.New("at least one input is required")
+	}
+	return &req, nil
+}
+
+// Input validates the request's tensors and converts them to the /v1 input:
+// a map from each input's name to its data, nested to its shape
+func (r *InferRequest) Input() (map[string]interface{}, error) {
+	input := make(map[string]interface{}, len(r.Inputs))
+	for _, tensor := range r.Inputs {
+		if tensor.Name == "" {
+			return nil, errors.New("inputs must be named")
+		}
+		if _, ok := input[tensor.Name]; ok {
+			return nil, fmt.Errorf("input %q is given twice", tensor.Name)
+		}
+		value, err := tensor.value()
+		if err != nil {
+			return nil, fmt.Errorf("input %q: %w", tensor.Name, err)
+		}
+		input[tensor.Name] = value
+	}
+	return input, nil
+}
+
+// value checks the tensor's data against its datatype and shape and nests it
+func (t Tensor) value() (interface{}, error) {
+	if t.Datatype == JSON {
+		if len(t.Shape) != 0 || len(t.Data) != 1 {
+			return nil, errors.New("JSON tensors have shape [] and one element")
+		}
+		return t.Data[0], nil
+	}
+
+	size := int64(1)
+	for _, dim := range t.Shape {
+		if dim < 0 {
+			return nil, fmt.Errorf("invalid dimension %d", dim)
+		}
+		size *= dim
+	}
+	if int64(len(t.Data)) != size {
+		return nil, fmt.Errorf("shape %v needs %d elements, got %d", t.Shape, size, len(t.Data))
+	}
+
+	values := make([]interface{}, len(t.Data))
+	for i, element := range t.Data {
+		value, err := convert(t.Datatype, element)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		values[i] = value
+	}
+	if len(t.Shape) == 0 {
+		return values[0], nil
+	}
+	return reshape(values, t.Shape), nil
+}
+
+// convert checks that element is of datatype and returns it as the Go value
+// /v1 decodes it to
+func convert(datatype Datatype, element interface{}) (interface{}, error) {
+	switch datatype {
+	case Bool, Bytes, FP32, FP64:
+	default:
+		if _, ok := intBits[datatype]; !ok {
+			return nil, fmt.Errorf("unknown datatype %q", datatype)
+		}
+	}
+
+	switch datatype {
+	case Bool:
+		if b, ok := element.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("%v is not a BOOL", element)
+	case Bytes:
+		if s, ok := element.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("%v is not a BYTES string", element)
+	}
+
+	number, ok := element.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a number", element)
+	}
+	switch datatype {
+	case Int8, Int16, Int32, Int64:
+		n, err := strconv.ParseInt(number.String(), 10, intBits[datatype])
+		if err != nil {
+			return nil, fmt.Errorf("%s is not an %s", number, datatype)
+		}
+		return n, nil
+	case Uint8, Uint16, Uint32, Uint64:
+		n, err := strconv.ParseUint(number.String(), 10, intBits[datatype])
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a %s", number, datatype)
+		}
+		return n, nil
+	case FP32, FP64:
+		bits := 64
+		if datatype == FP32 {
+			bits = 32
+		}
+		f, err := strconv.ParseFloat(number.String(), bits)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not an %s", number, datatype)
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("unknown datatype %q", datatype)
+}
+
+var intBits = map[Datatype]int{
+	Int8: 8, Int16: 16, Int32: 32, Int64: 64,
+	Uint8: 8, Uint16: 16, Uint32: 32, Uint64: 64,
+}
+
+// reshape nests flat, row-major values into shape
+func reshape(values []interface{}, shape []int64) []interface{} {
+	rows := make([]interface{}, shape[0])
+	if len(shape) == 1 {
+		copy(rows, values)
+		return rows
+	}
+	stride := int64(len(values)) / max(shape[0], 1)
+	for i := range rows {
+		rows[i] = reshape(values[int64(i)*stride:int64(i+1)*stride], shape[1:])
+	}
+	return rows
+}
+
+// Outputs converts a /v1 prediction to tensors, ordered by name
+func Outputs(prediction map[string]interface{}) []Tensor {
+	names := make([]string, 0, len(prediction))
+	for name := range prediction {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	outputs := make([]Tensor, len(names))
+	for i, name := range names {
+		outputs[i] = FromValue(name, prediction[name])
+	}
+	return outputs
+}
+
+// FromValue converts a decoded JSON value to a tensor. Rectangular arrays of
+// numbers become INT64 when every element is integral and FP64 otherwise;
+// arrays of strings become BYTES and of booleans BOOL. Anything else is
+// returned whole as a JSON tensor.
+func FromValue(name string, value interface{}) Tensor {
+	f := flattener{shape: []int64{}, data: []interface{}{}, leafDepth: -1}
+	if !f.flatten(value, 0) {
+		return Tensor{Name: name, Datatype: JSON, Shape: []int64{}, Data: []interface{}{value}}
+	}
+	shape, data := f.shape, f.data
+
+	datatype, ok := elementType(data)
+	if !ok {
+		return Tensor{Name: name, Datatype: JSON, Shape: []int64{}, Data: []interface{}{value}}
+	}
+	if datatype == Int64 {
+		for i, element := range data {
+			data[i] = int64(element.(float64))
+		}
+	}
+	return Tensor{Name: name, Datatype: datatype, Shape: shape, Data: data}
+}
+
+// flattener collects the elements of nested arrays and their shape
+type flattener struct {
+	shape []int64
+	data  []interface{}
+	// leafDepth is the nesting level of the elements, once one is seen
+	leafDepth int
+}
+
+// flatten appends value's elements to data, recording the length of each
+// nesting level in shape. It reports false when value is not rectangular.
+func (f *flattener) flatten(value interface{}, depth int) bool {
+	rows, ok := value.([]interface{})
+	if !ok {
+		if len(f.shape) != depth {
+			return false
+		}
+		f.leafDepth = depth
+		f.data = append(f.data, value)
+		return true
+	}
+	if f.leafDepth >= 0 && depth >= f.leafDepth {
+		return false
+	}
+
+	switch {
+	case len(f.shape) == depth:
+		f.shape = append(f.shape, int64(len(rows)))
+	case f.shape[depth] != int64(len(rows)):
+		return false
+	}
+	for _, row := range rows {
+		if !f.flatten(row, depth+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// elementType returns the datatype shared by every element
+func elementType(data []interface{}) (Datatype, bool) {
+	datatype := Int64
+	for i, element := range data {
+		var kind Datatype
+		switch element := element.(type) {
+		case bool:
+			kind = Bool
+		case string:
+			kind = Bytes
+		case float64:
+			kind = Int64
+			if element != math.Trunc(element) || math.Abs(element) > 1<<53 {
+				kind = FP64
+			}
+		default:
+			return "", false
+		}
+
+		switch {
+		case i == 0:
+			datatype = kind
+		case kind == datatype:
+		case (kind == FP64 && datatype == Int64) || (kind == Int64 && datatype == FP64):
+			datatype = FP64
+		default:
+			return "", false
+		}
+	}
+	return datatype, true
+}
@@ -0,0 +1,115 @@
+package apiv2
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode_RejectsUnknownFields(t *testing.T) {
+	_, err := Decode(strings.NewReader(`{"model":"resnet18","input":{"data":[1]}}`))
+	assert.ErrorContains(t, err, "unknown field")
+
+	_, err = Decode(strings.NewReader(`{"model":"resnet18","inputs":[]}`))
+	assert.ErrorContains(t, err, "at least one input")
+
+	_, err = Decode(strings.NewReader(`{"inputs":[{"name":"x","datatype":"FP32","shape":[1],"data":[1]}]}`))
+	assert.ErrorContains(t, err, "model is required")
+}
+
+func TestInput_ReshapesTensors(t *testing.T) {
+	req, err := Decode(strings.NewReader(`{
+		"model": "resnet18",
+		"inputs": [
+			{"name": "pixels", "datatype": "UINT8", "shape": [2, 3], "data": [1, 2, 3, 4, 5, 6]},
+			{"name": "scale", "datatype": "FP32", "shape": [], "data": [0.5]},
+			{"name": "labels", "datatype": "BYTES", "shape": [2], "data": ["cat", "dog"]},
+			{"name": "options", "datatype": "JSON", "shape": [], "data": [{"top_k": 5}]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	input, err := req.Input()
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		[]interface{}{uint64(1), uint64(2), uint64(3)},
+		[]interface{}{uint64(4), uint64(5), uint64(6)},
+	}, input["pixels"])
+	assert.Equal(t, 0.5, input["scale"])
+	assert.Equal(t, []interface{}{"cat", "dog"}, input["labels"])
+
+	// Forwarded to the router, the input reads as a /v1 request would
+	data, err := json.Marshal(input)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"pixels":[[1,2,3],[4,5,6]],"scale":0.5,"labels":["cat","dog"],"options":{"top_k":5}}`, string(data))
+}
+
+func TestInput_RejectsInvalidTensors(t *testing.T) {
+	cases := map[string]string{
+		"shape":    `{"name":"x","datatype":"FP32","shape":[2,2],"data":[1,2,3]}`,
+		"range":    `{"name":"x","datatype":"INT8","shape":[1],"data":[200]}`,
+		"integer":  `{"name":"x","datatype":"INT32","shape":[1],"data":[1.5]}`,
+		"unsigned": `{"name":"x","datatype":"UINT16","shape":[1],"data":[-1]}`,
+		"bool":     `{"name":"x","datatype":"BOOL","shape":[1],"data":[1]}`,
+		"bytes":    `{"name":"x","datatype":"BYTES","shape":[1],"data":[1]}`,
+		"datatype": `{"name":"x","datatype":"FP16","shape":[1],"data":[1]}`,
+		"json":     `{"name":"x","datatype":"JSON","shape":[2],"data":[1,2]}`,
+		"name":     `{"datatype":"FP32","shape":[1],"data":[1]}`,
+	}
+	for name, tensor := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, err := Decode(strings.NewReader(`{"model":"m","inputs":[` + tensor + `]}`))
+			require.NoError(t, err)
+			_, err = req.Input()
+			assert.Error(t, err)
+		})
+	}
+
+	req, err := Decode(strings.NewReader(`{"model":"m","inputs":[
+		{"name":"x","datatype":"FP32","shape":[1],"data":[1]},
+		{"name":"x","datatype":"FP32","shape":[1],"data":[2]}
+	]}`))
+	require.NoError(t, err)
+	_, err = req.Input()
+	assert.ErrorContains(t, err, "given twice")
+}
+
+func TestOutputs(t *testing.T) {
+	var prediction map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"class": 281,
+		"scores": [[0.9, 0.1], [0.2, 0.8]],
+		"ids": [3, 7],
+		"labels": ["cat", "dog"],
+		"boxes": [[1, 2], [3]],
+		"meta": {"model": "resnet18"}
+	}`), &prediction))
+
+	outputs := Outputs(prediction)
+	byName := make(map[string]Tensor)
+	var names []string
+	for _, output := range outputs {
+		byName[output.Name] = output
+		names = append(names, output.Name)
+	}
+	assert.Equal(t, []string{"boxes", "class", "ids", "labels", "meta", "scores"}, names)
+
+	assert.Equal(t, Tensor{Name: "class", Datatype: Int64, Shape: []int64{}, Data: []interface{}{int64(281)}}, byName["class"])
+	assert.Equal(t, Tensor{Name: "scores", Datatype: FP64, Shape: []int64{2, 2}, Data: []interface{}{0.9, 0.1, 0.2, 0.8}}, byName["scores"])
+	assert.Equal(t, Tensor{Name: "ids", Datatype: Int64, Shape: []int64{2}, Data: []interface{}{int64(3), int64(7)}}, byName["ids"])
+	assert.Equal(t, Tensor{Name: "labels", Datatype: Bytes, Shape: []int64{2}, Data: []interface{}{"cat", "dog"}}, byName["labels"])
+	assert.Equal(t, JSON, byName["boxes"].Datatype, "ragged arrays are not tensors")
+	assert.Equal(t, JSON, byName["meta"].Datatype)
+	assert.Equal(t, []interface{}{prediction["meta"]}, byName["meta"].Data)
+}
+
+func TestFromValue_RejectsMixedNesting(t *testing.T) {
+	tensor := FromValue("x", []interface{}{2.0, []interface{}{1.0}})
+	assert.Equal(t, JSON, tensor.Datatype)
+
+	tensor = FromValue("x", []interface{}{1.0, 2.5})
+	assert.Equal(t, FP64, tensor.Datatype)
+}
@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+)
+
+// Token types, carried in the typ claim. Only access tokens authenticate
+// requests; refresh tokens are only accepted by Refresh.
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+)
+
+// issuerName is the iss claim of tokens the gateway issues
+const issuerName = "api-gateway"
+
+// KeyVerifier resolves tenant API keys to the principal they act as
+type KeyVerifier interface {
+	Verify(ctx context.Context, key string) (*tenancy.Principal, error)
+}
+
+// UsedTokens remembers refresh tokens that have been exchanged, so each is
+// only used once
+type UsedTokens interface {
+	// Use records the token ID as used until expireAt and reports whether it
+	// was unused
+	Use(ctx context.Context, id string, expireAt time.Time) (bool, error)
+}
+
+// Client is a service account that authenticates with client credentials
+type Client struct {
+	ID string `json:"id"`
+	// SecretSHA256 is the hex SHA-256 digest of the client's secret
+	SecretSHA256 string `json:"secret_sha256"`
+	// Role, TenantID and Plan are the claims of the client's tokens
+	Role     string `json:"role,omitempty"`
+	TenantID string `json:"tenant_id,omitempty"`
+	Plan     string `json:"plan,omitempty"`
+}
+
+// ParseClients reads clients from a JSON list
+func ParseClients(document string) ([]Client, error) {
+	if document == "" {
+		return nil, nil
+	}
+	var clients []Client
+	if err := json.Unmarshal([]byte(document), &clients); err != nil {
+		return nil, fmt.Errorf("invalid auth clients: %w", err)
+	}
+	for _, client := range clients {
+		if client.ID == "" || len(client.SecretSHA256) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid auth client %q: id and secret_sha256 are required", client.ID)
+		}
+	}
+	return clients, nil
+}
+
+// Identity is who a token is issued to, as the claims the Auth middleware reads
+type Identity struct {
+	UserID     string
+	TenantID   string
+	TenantRole string
+	Role       string
+	Plan       string
+}
+
+// Tokens is an access token and the refresh token that replaces it
+type Tokens struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int    `json:"refresh_expires_in"`
+}
+
+// Issuer exchanges credentials for token pairs and refreshes them
+type Issuer struct {
+	keys       *Keyring
+	used       UsedTokens
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	apiKeys    KeyVerifier
+	clients    map[string]Client
+	now        func() time.Time
+}
+
+// NewIssuer creates an issuer signing with keys. Access tokens last accessTTL
+// and refresh tokens refreshTTL, which keys must retain replaced secrets for.
+func NewIssuer(keys *Keyring, used UsedTokens, accessTTL, refreshTTL time.Duration) *Issuer {
+	return &Issuer{
+		keys:       keys,
+		used:       used,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		clients:    make(map[string]Client),
+		now:        time.Now,
+	}
+}
+
+// SetAPIKeys exchanges tenant API keys for tokens acting with the key's role
+func (i *Issuer) SetAPIKeys(verifier KeyVerifier) {
+	i.apiKeys = verifier
+}
+
+// SetClients replaces the clients that may exchange client credentials
+func (i *Issuer) SetClients(clients []Client) {
+	byID := make(map[string]Client, len(clients))
+	for _, client := range clients {
+		byID[client.ID] = client
+	}
+	i.clients = byID
+}
+
+// ExchangeKey issues tokens for a tenant API key
+func (i *Issuer) ExchangeKey(ctx context.Context, key string) (*Tokens, error) {
+	if i.apiKeys == nil {
+		return nil, apperrors.New(apperrors.Unimplemented, "api keys are not enabled")
+	}
+	if !tenancy.IsKey(key) {
+		return nil, apperrors.New(apperrors.Unauthenticated, "invalid api key")
+	}
+	principal, err := i.apiKeys.Verify(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return i.issue(Identity{
+		UserID:     "key:" + principal.KeyID,
+		TenantID:   principal.TenantID,
+		TenantRole: string(principal.Role),
+	})
+}
+
+// ExchangeClientCredentials issues tokens for a client's ID and secret
+func (i *Issuer) ExchangeClientCredentials(ctx context.Context, id, secret string) (*Tokens, error) {
+	client, ok := i.clients[id]
+	sum := sha256.Sum256([]byte(secret))
+	if !ok || subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(client.SecretSHA256)) != 1 {
+		return nil, apperrors.New(apperrors.Unauthenticated, "invalid client credentials")
+	}
+	return i.issue(Identity{
+		UserID:   "client:" + client.ID,
+		TenantID: client.TenantID,
+		Role:     client.Role,
+		Plan:     client.Plan,
+	})
+}
+
+// Refresh exchanges a refresh token for a new token pair. Each refresh token
+// is used once, so a stolen one stops working when its owner refreshes.
+func (i *Issuer) Refresh(ctx context.Context, refreshToken string) (*Tokens, error) {
+	claims, err := i.Parse(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if typ, _ := claims["typ"].(string); typ != TypeRefresh {
+		return nil, apperrors.New(apperrors.Unauthenticated, "not a refresh token")
+	}
+
+	id, _ := claims["jti"].(string)
+	expiresAt, err := claims.GetExpirationTime()
+	if id == "" || err != nil || expiresAt == nil {
+		return nil, apperrors.New(apperrors.Unauthenticated, "invalid refresh token")
+	}
+	unused, err := i.used.Use(ctx, id, expiresAt.Time)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Unavailable, "failed to check refresh token")
+	}
+	if !unused {
+		return nil, apperrors.New(apperrors.Unauthenticated, "refresh token was already used")
+	}
+
+	claim := func(name string) string {
+		value, _ := claims[name].(string)
+		return value
+	}
+	return i.issue(Identity{
+		UserID:     claim("user_id"),
+		TenantID:   claim("tenant_id"),
+		TenantRole: claim("tenant_role"),
+		Role:       claim("role"),
+		Plan:       claim("plan"),
+	})
+}
+
+// Parse verifies a token signed with any key of the keyring and returns its
+// claims
+func (i *Issuer) Parse(token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		return i.keys.Secret(kid)
+	}, jwt.WithIssuer(issuerName))
+	if err != nil || !parsed.Valid {
+		return nil, apperrors.New(apperrors.Unauthenticated, "invalid token")
+	}
+	return claims, nil
+}
+
+// issue signs an access and a refresh token for identity
+func (i *Issuer) issue(identity Identity) (*Tokens, error) {
+	now := i.now()
+	access, err := i.sign(identity, TypeAccess, now, i.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := i.sign(identity, TypeRefresh, now, i.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{
+		AccessToken:      access,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(i.accessTTL.Seconds()),
+		RefreshToken:     refresh,
+		RefreshExpiresIn: int(i.refreshTTL.Seconds()),
+	}, nil
+}
+
+func (i *Issuer) sign(identity Identity, typ string, now time.Time, ttl time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"iss":     issuerName,
+		"jti":     uuid.New().String(),
+		"typ":     typ,
+		"iat":     now.Unix(),
+		"exp":     now.Add(ttl).Unix(),
+		"user_id": identity.UserID,
+	}
+	for name, value := range map[string]string{
+		"tenant_id":   identity.TenantID,
+		"tenant_role": identity.TenantRole,
+		"role":        identity.Role,
+		"plan":        identity.Plan,
+	} {
+		if value != "" {
+			claims[name] = value
+		}
+	}
+
+	kid, secret := i.keys.Signing()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		return "", apperrors.Wrap(err, apperrors.Internal, "failed to sign token")
+	}
+	return signed, nil
+}
+
+// redisUsedTokens records used refresh tokens in Redis
+type redisUsedTokens struct {
+	client *redis.Client
+}
+
+// RedisUsedTokens records used refresh tokens in Redis, shared by every
+// gateway replica
+func RedisUsedTokens(client *redis.Client) UsedTokens {
+	return &redisUsedTokens{client: client}
+}
+
+func (r *redisUsedTokens) Use(ctx context.Context, id string, expireAt time.Time) (bool, error) {
+	ttl := time.Until(expireAt)
+	if ttl <= 0 {
+		return false, nil
+	}
+	return r.client.SetNX(ctx, "auth:refresh:"+id, 1, ttl).Result()
+}
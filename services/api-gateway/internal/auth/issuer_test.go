@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+)
+
+type memoryUsedTokens map[string]time.Time
+
+func (m memoryUsedTokens) Use(ctx context.Context, id string, expireAt time.Time) (bool, error) {
+	if _, ok := m[id]; ok {
+		return false, nil
+	}
+	m[id] = expireAt
+	return true, nil
+}
+
+type fakeKeys map[string]*tenancy.Principal
+
+func (f fakeKeys) Verify(ctx context.Context, key string) (*tenancy.Principal, error) {
+	principal, ok := f[key]
+	if !ok {
+		return nil, apperrors.New(apperrors.Unauthenticated, "invalid api key")
+	}
+	return principal, nil
+}
+
+func digest(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func newTestIssuer() *Issuer {
+	issuer := NewIssuer(NewKeyring("signing-secret", 24*time.Hour), memoryUsedTokens{}, 15*time.Minute, 24*time.Hour)
+	issuer.SetAPIKeys(fakeKeys{"aip_valid": {KeyID: "k1", TenantID: "acme", Role: tenancy.RoleViewer}})
+	issuer.SetClients([]Client{{ID: "ci", SecretSHA256: digest("s3cret"), Role: "admin"}})
+	return issuer
+}
+
+func TestIssuer_ExchangeKey(t *testing.T) {
+	issuer := newTestIssuer()
+
+	tokens, err := issuer.ExchangeKey(context.Background(), "aip_valid")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.Equal(t, 900, tokens.ExpiresIn)
+	assert.Equal(t, 86400, tokens.RefreshExpiresIn)
+
+	claims, err := issuer.Parse(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, TypeAccess, claims["typ"])
+	assert.Equal(t, "key:k1", claims["user_id"])
+	assert.Equal(t, "acme", claims["tenant_id"])
+	assert.Equal(t, "viewer", claims["tenant_role"])
+	assert.NotContains(t, claims, "role")
+
+	_, err = issuer.ExchangeKey(context.Background(), "aip_revoked")
+	assert.True(t, apperrors.Is(err, apperrors.Unauthenticated))
+	_, err = issuer.ExchangeKey(context.Background(), "not-a-key")
+	assert.True(t, apperrors.Is(err, apperrors.Unauthenticated))
+}
+
+func TestIssuer_ExchangeClientCredentials(t *testing.T) {
+	issuer := newTestIssuer()
+
+	tokens, err := issuer.ExchangeClientCredentials(context.Background(), "ci", "s3cret")
+	require.NoError(t, err)
+	claims, err := issuer.Parse(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "client:ci", claims["user_id"])
+	assert.Equal(t, "admin", claims["role"])
+
+	for _, credentials := range [][2]string{{"ci", "wrong"}, {"unknown", "s3cret"}} {
+		_, err := issuer.ExchangeClientCredentials(context.Background(), credentials[0], credentials[1])
+		assert.True(t, apperrors.Is(err, apperrors.Unauthenticated), credentials)
+	}
+}
+
+func TestIssuer_RefreshOnce(t *testing.T) {
+	issuer := newTestIssuer()
+	ctx := context.Background()
+
+	tokens, err := issuer.ExchangeKey(ctx, "aip_valid")
+	require.NoError(t, err)
+
+	// Access tokens cannot be used to refresh
+	_, err = issuer.Refresh(ctx, tokens.AccessToken)
+	assert.True(t, apperrors.Is(err, apperrors.Unauthenticated))
+
+	// Refreshing across a rotation keeps the identity and signs with the new key
+	issuer.keys.Rotate("rotated-secret")
+	refreshed, err := issuer.Refresh(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+	claims, err := issuer.Parse(refreshed.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "key:k1", claims["user_id"])
+	assert.Equal(t, "viewer", claims["tenant_role"])
+
+	_, err = issuer.Refresh(ctx, tokens.RefreshToken)
+	assert.True(t, apperrors.Is(err, apperrors.Unauthenticated))
+}
+
+func TestParseClients(t *testing.T) {
+	clients, err := ParseClients(`[{"id": "ci", "secret_sha256": "` + digest("s3cret") + `", "role": "admin"}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []Client{{ID: "ci", SecretSHA256: digest("s3cret"), Role: "admin"}}, clients)
+
+	_, err = ParseClients(`[{"id": "ci", "secret_sha256": "s3cret"}]`)
+	assert.Error(t, err)
+
+	clients, err = ParseClients("")
+	assert.NoError(t, err)
+	assert.Empty(t, clients)
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestKeyring_Rotate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	keys := NewKeyring("first-secret", time.Hour)
+	keys.now = func() time.Time { return now }
+
+	firstID, firstSecret := keys.Signing()
+	assert.Equal(t, []byte("first-secret"), firstSecret)
+
+	// Rotating to the same secret keeps the key
+	keys.Rotate("first-secret")
+	id, _ := keys.Signing()
+	assert.Equal(t, firstID, id)
+
+	keys.Rotate("second-secret")
+	secondID, secondSecret := keys.Signing()
+	assert.NotEqual(t, firstID, secondID)
+	assert.Equal(t, []byte("second-secret"), secondSecret)
+
+	// Tokens signed before the rotation verify until the old key is dropped
+	secret, err := keys.Secret(firstID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("first-secret"), secret)
+
+	// Tokens minted without a kid use the current secret
+	secret, err = keys.Secret("")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second-secret"), secret)
+
+	now = now.Add(time.Hour)
+	_, err = keys.Secret(firstID)
+	assert.True(t, apperrors.Is(err, apperrors.Unauthenticated))
+
+	// Every replica derives the same ID from the same secret
+	assert.Equal(t, secondID, func() string { id, _ := NewKeyring("second-secret", time.Hour).Signing(); return id }())
+}
@@ -0,0 +1,294 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// OIDCConfig describes an external OpenID Connect provider whose tokens the
+// gateway accepts
+type OIDCConfig struct {
+	// IssuerURL is the provider's issuer, e.g. a Keycloak realm URL; its
+	// discovery document names the JWKS
+	IssuerURL string
+	// Audience must be among the token's aud; empty skips the check
+	Audience string
+	// ClockSkew is allowed on exp, nbf and iat
+	ClockSkew time.Duration
+	// TenantClaim and RoleClaim name the claims, as dotted paths, holding the
+	// caller's tenant and roles
+	TenantClaim string
+	RoleClaim   string
+	// AdminRole is the provider role that grants the platform admin role
+	AdminRole string
+	// JWKSRefresh is how often the provider's keys are refetched
+	JWKSRefresh time.Duration
+}
+
+// jwksRetry is the least time between fetches of the JWKS for unknown key IDs
+const jwksRetry = time.Minute
+
+// OIDCVerifier validates tokens signed by an OIDC provider with the keys it
+// publishes, and maps their claims to the ones the gateway uses
+type OIDCVerifier struct {
+	config OIDCConfig
+	client *http.Client
+	logger *zap.Logger
+
+	mu        sync.Mutex
+	jwksURL   string
+	keys      map[string]interface{}
+	fetchedAt time.Time
+	now       func() time.Time
+}
+
+// NewOIDCVerifier creates a verifier for the provider; its keys are fetched
+// on first use
+func NewOIDCVerifier(config OIDCConfig, client *http.Client, logger *zap.Logger) *OIDCVerifier {
+	return &OIDCVerifier{
+		config: config,
+		client: client,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Verify validates a token's signature, issuer, audience and lifetime and
+// returns its claims with user_id, tenant_id and role set from the provider's
+func (v *OIDCVerifier) Verify(ctx context.Context, token string) (jwt.MapClaims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(v.config.IssuerURL),
+		jwt.WithLeeway(v.config.ClockSkew),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(v.now),
+	}
+	if v.config.Audience != "" {
+		options = append(options, jwt.WithAudience(v.config.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.key(ctx, kid)
+	}, options...)
+	if err != nil || !parsed.Valid {
+		if apperrors.Is(err, apperrors.Unavailable) {
+			return nil, apperrors.Wrap(err, apperrors.Unavailable, "identity provider keys are unavailable")
+		}
+		return nil, apperrors.New(apperrors.Unauthenticated, "invalid token")
+	}
+
+	mapped := jwt.MapClaims{}
+	if subject, _ := claims["sub"].(string); subject != "" {
+		mapped["user_id"] = subject
+	}
+	if tenant, ok := lookupClaim(claims, v.config.TenantClaim).(string); ok && tenant != "" {
+		mapped["tenant_id"] = tenant
+	}
+	if v.config.AdminRole != "" && hasRole(lookupClaim(claims, v.config.RoleClaim), v.config.AdminRole) {
+		mapped["role"] = "admin"
+	}
+	if plan, ok := claims["plan"].(string); ok {
+		mapped["plan"] = plan
+	}
+	return mapped, nil
+}
+
+// key returns the provider's public key named kid, refetching the JWKS when
+// it is stale or, at most once a minute, when the key is unknown, as after
+// the provider rotates its keys
+func (v *OIDCVerifier) key(ctx context.Context, kid string) (interface{}, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	stale := now.Sub(v.fetchedAt) >= v.config.JWKSRefresh
+	if key, ok := v.keys[kid]; ok && !stale {
+		return key, nil
+	}
+	if stale || now.Sub(v.fetchedAt) >= jwksRetry {
+		if err := v.fetch(ctx); err != nil {
+			v.logger.Warn("failed to fetch identity provider keys", zap.Error(err))
+			// Keep verifying with the keys already known
+			if key, ok := v.keys[kid]; ok {
+				return key, nil
+			}
+			return nil, err
+		}
+		v.fetchedAt = now
+	}
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// fetch reads the provider's discovery document, once, and its JWKS
+func (v *OIDCVerifier) fetch(ctx context.Context) error {
+	if v.jwksURL == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.get(ctx, strings.TrimRight(v.config.IssuerURL, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return err
+		}
+		if discovery.Issuer != v.config.IssuerURL || discovery.JWKSURI == "" {
+			return apperrors.Newf(apperrors.Unavailable, "discovery document of %s does not match its issuer", v.config.IssuerURL)
+		}
+		v.jwksURL = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := v.get(ctx, v.jwksURL, &set); err != nil {
+		return err
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			v.logger.Warn("skipping identity provider key", zap.String("kid", k.Kid), zap.Error(err))
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	v.keys = keys
+	return nil
+}
+
+func (v *OIDCVerifier) get(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.Internal, "failed to create identity provider request")
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "identity-provider")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.Newf(apperrors.Unavailable, "identity provider answered %s with %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return apperrors.Wrap(err, apperrors.Unavailable, "failed to decode identity provider response")
+	}
+	return nil
+}
+
+// jwk is a public key of a JSON Web Key Set
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	decode := func(value string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decode(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x: %w", err)
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// lookupClaim follows a dotted path, such as Keycloak's realm_access.roles,
+// into the claims
+func lookupClaim(claims jwt.MapClaims, path string) interface{} {
+	if path == "" {
+		return nil
+	}
+	var value interface{} = map[string]interface{}(claims)
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
+
+// hasRole reports whether a role claim, a string or a list, holds role
+func hasRole(claim interface{}, role string) bool {
+	switch value := claim.(type) {
+	case string:
+		return value == role
+	case []interface{}:
+		for _, item := range value {
+			if item == role {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// fakeProvider serves an OIDC discovery document and a JWKS
+type fakeProvider struct {
+	server *httptest.Server
+	keys   atomic.Value // []map[string]string
+	hits   atomic.Int32
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	p := &fakeProvider{}
+	p.keys.Store([]map[string]string{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/realms/ai/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   p.issuer(),
+			"jwks_uri": p.server.URL + "/realms/ai/certs",
+		})
+	})
+	mux.HandleFunc("/realms/ai/certs", func(w http.ResponseWriter, r *http.Request) {
+		p.hits.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": p.keys.Load()})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *fakeProvider) issuer() string {
+	return p.server.URL + "/realms/ai"
+}
+
+func encode(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) map[string]string {
+	return map[string]string{"kid": kid, "kty": "RSA", "use": "sig", "n": encode(key.N), "e": encode(big.NewInt(int64(key.E)))}
+}
+
+func ecJWK(kid string, key *ecdsa.PrivateKey) map[string]string {
+	return map[string]string{"kid": kid, "kty": "EC", "crv": "P-256", "x": encode(key.X), "y": encode(key.Y)}
+}
+
+func signWith(method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, _ := token.SignedString(key)
+	return signed
+}
+
+func TestOIDCVerifier_Verify(t *testing.T) {
+	provider := newFakeProvider(t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	provider.keys.Store([]map[string]string{rsaJWK("rsa-1", rsaKey), ecJWK("ec-1", ecKey)})
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	verifier := NewOIDCVerifier(OIDCConfig{
+		IssuerURL:   provider.issuer(),
+		Audience:    "ai-platform",
+		ClockSkew:   30 * time.Second,
+		TenantClaim: "tenant_id",
+		RoleClaim:   "realm_access.roles",
+		AdminRole:   "platform-admin",
+		JWKSRefresh: time.Hour,
+	}, provider.server.Client(), zap.NewNop())
+	verifier.now = func() time.Time { return now }
+
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":          provider.issuer(),
+			"aud":          []string{"account", "ai-platform"},
+			"sub":          "4f1c-alice",
+			"iat":          now.Unix(),
+			"exp":          now.Add(5 * time.Minute).Unix(),
+			"tenant_id":    "acme",
+			"realm_access": map[string]interface{}{"roles": []string{"offline_access", "platform-admin"}},
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	mapped, err := verifier.Verify(context.Background(), signWith(jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(nil)))
+	require.NoError(t, err)
+	assert.Equal(t, jwt.MapClaims{"user_id": "4f1c-alice", "tenant_id": "acme", "role": "admin"}, mapped)
+
+	mapped, err = verifier.Verify(context.Background(), signWith(jwt.SigningMethodES256, "ec-1", ecKey, claims(jwt.MapClaims{"realm_access": nil})))
+	require.NoError(t, err)
+	assert.Equal(t, jwt.MapClaims{"user_id": "4f1c-alice", "tenant_id": "acme"}, mapped)
+
+	// Tokens expired within the clock skew are still accepted
+	_, err = verifier.Verify(context.Background(), signWith(jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(jwt.MapClaims{"exp": now.Add(-20 * time.Second).Unix()})))
+	assert.NoError(t, err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired", signWith(jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()}))},
+		{"wrong issuer", signWith(jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(jwt.MapClaims{"iss": "https://elsewhere/realms/ai"}))},
+		{"wrong audience", signWith(jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(jwt.MapClaims{"aud": "account"}))},
+		{"no expiry", signWith(jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(jwt.MapClaims{"exp": nil}))},
+		{"wrong key", signWith(jwt.SigningMethodRS256, "rsa-1", otherKey, claims(nil))},
+		{"unknown key", signWith(jwt.SigningMethodRS256, "rsa-2", otherKey, claims(nil))},
+		{"shared secret", signWith(jwt.SigningMethodHS256, "rsa-1", []byte("secret"), claims(nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(context.Background(), tt.token)
+			assert.True(t, apperrors.Is(err, apperrors.Unauthenticated), err)
+		})
+	}
+}
+
+func TestOIDCVerifier_RotatedKeys(t *testing.T) {
+	provider := newFakeProvider(t)
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	provider.keys.Store([]map[string]string{rsaJWK("old", oldKey)})
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	verifier := NewOIDCVerifier(OIDCConfig{IssuerURL: provider.issuer(), JWKSRefresh: time.Hour}, provider.server.Client(), zap.NewNop())
+	verifier.now = func() time.Time { return now }
+
+	token := func(kid string, key *rsa.PrivateKey) string {
+		return signWith(jwt.SigningMethodRS256, kid, key, jwt.MapClaims{
+			"iss": provider.issuer(), "sub": "alice", "iat": now.Unix(), "exp": now.Add(time.Minute).Unix(),
+		})
+	}
+
+	_, err = verifier.Verify(context.Background(), token("old", oldKey))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), provider.hits.Load())
+
+	// A key published after the last fetch is found by refetching, at most
+	// once a minute
+	provider.keys.Store([]map[string]string{rsaJWK("old", oldKey), rsaJWK("new", newKey)})
+	_, err = verifier.Verify(context.Background(), token("new", newKey))
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), provider.hits.Load())
+
+	now = now.Add(time.Minute)
+	_, err = verifier.Verify(context.Background(), token("new", newKey))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), provider.hits.Load())
+
+	// Known keys keep verifying while the provider is down
+	provider.server.Close()
+	now = now.Add(2 * time.Hour)
+	_, err = verifier.Verify(context.Background(), token("old", oldKey))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,156 @@
+// Package backpressure turns batch jobs away while the batch worker's backlog
+// is too deep, so clients back off instead of queueing work that would wait
+// for hours.
+package backpressure
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/backlog"
+)
+
+// minRetryAfter is the shortest wait suggested to a rejected client
+const minRetryAfter = time.Second
+
+// Limits bound the backlog new jobs are accepted into. Zero values are not checked.
+type Limits struct {
+	// Depth is the most jobs that may be queued or in processing
+	Depth int64
+	// Delay is the longest a new job may be expected to wait before it starts
+	Delay time.Duration
+}
+
+// Decision is the outcome of checking the backlog for a new job
+type Decision struct {
+	Admitted bool
+	// RetryAfter estimates when the backlog will be back within limits
+	RetryAfter time.Duration
+	Backlog    *backlog.Backlog
+}
+
+// Gate polls the batch worker's backlog and decides whether new jobs are
+// accepted. It fails open: until the backlog is known, or once it is stale,
+// every job is admitted.
+type Gate struct {
+	url        string
+	client     *http.Client
+	limits     Limits
+	staleAfter time.Duration
+	logger     *zap.Logger
+
+	mu        sync.RWMutex
+	current   *backlog.Backlog
+	fetchedAt time.Time
+}
+
+// NewGate creates a gate for the batch worker at workerURL. Backlogs older
+// than staleAfter are ignored.
+func NewGate(workerURL string, client *http.Client, limits Limits, staleAfter time.Duration, logger *zap.Logger) *Gate {
+	return &Gate{
+		url:        workerURL + backlog.Path,
+		client:     client,
+		limits:     limits,
+		staleAfter: staleAfter,
+		logger:     logger,
+	}
+}
+
+// Run polls the backlog every interval until ctx is cancelled
+func (g *Gate) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		pollCtx, cancel := context.WithTimeout(ctx, interval)
+		if err := g.Poll(pollCtx); err != nil && ctx.Err() == nil {
+			g.logger.Warn("failed to fetch batch backlog", zap.Error(err))
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches the backlog once
+func (g *Gate) Poll(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "batch-worker")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.FromHTTPResponse(resp, "batch-worker")
+	}
+
+	var b backlog.Backlog
+	if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+		return apperrors.Wrap(err, apperrors.Internal, "failed to decode batch backlog")
+	}
+	g.Update(b, time.Now())
+	return nil
+}
+
+// Update records the latest backlog
+func (g *Gate) Update(b backlog.Backlog, now time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.current = &b
+	g.fetchedAt = now
+}
+
+// Admit decides whether a job submitted at now is accepted. A nil gate admits every job.
+func (g *Gate) Admit(now time.Time) Decision {
+	if g == nil {
+		return Decision{Admitted: true}
+	}
+
+	g.mu.RLock()
+	current, fetchedAt := g.current, g.fetchedAt
+	g.mu.RUnlock()
+	if current == nil || now.Sub(fetchedAt) > g.staleAfter {
+		return Decision{Admitted: true}
+	}
+
+	decision := Decision{Admitted: true, Backlog: current}
+	var known bool
+	if g.limits.Depth > 0 && current.Depth() > g.limits.Depth {
+		decision.Admitted = false
+		if drain, ok := current.TimeToDrain(g.limits.Depth); ok {
+			decision.RetryAfter, known = drain, true
+		}
+	}
+	if delay, ok := current.EstimatedDelay(); ok && g.limits.Delay > 0 && delay > g.limits.Delay {
+		decision.Admitted = false
+		if excess := delay - g.limits.Delay; excess > decision.RetryAfter {
+			decision.RetryAfter = excess
+		}
+		known = true
+	}
+
+	if !decision.Admitted {
+		if !known {
+			// Nothing is finishing; ask again once the backlog is next sampled
+			decision.RetryAfter = g.staleAfter
+		}
+		decision.RetryAfter = time.Duration(math.Ceil(decision.RetryAfter.Seconds())) * time.Second
+		if decision.RetryAfter < minRetryAfter {
+			decision.RetryAfter = minRetryAfter
+		}
+	}
+	return decision
+}
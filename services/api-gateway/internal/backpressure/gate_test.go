@@ -0,0 +1,66 @@
+package backpressure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/backlog"
+)
+
+func TestGate_Admit(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	g := NewGate("http://batch-worker", http.DefaultClient, Limits{Depth: 100, Delay: time.Minute}, 15*time.Second, zap.NewNop())
+
+	assert.True(t, g.Admit(now).Admitted, "jobs are admitted until the backlog is known")
+
+	g.Update(backlog.New(50, 40, 10, now), now)
+	assert.True(t, g.Admit(now).Admitted)
+
+	// 150 jobs draining at 10 per second: 5 seconds until back under 100
+	g.Update(backlog.New(100, 50, 10, now), now)
+	decision := g.Admit(now)
+	assert.False(t, decision.Admitted)
+	assert.Equal(t, 5*time.Second, decision.RetryAfter)
+	require.NotNil(t, decision.Backlog)
+	assert.Equal(t, int64(150), decision.Backlog.Depth())
+
+	// Under the depth limit but draining too slowly: 90s expected wait
+	g.Update(backlog.New(60, 30, 1, now), now)
+	decision = g.Admit(now)
+	assert.False(t, decision.Admitted)
+	assert.Equal(t, 30*time.Second, decision.RetryAfter)
+
+	// Nothing finishing: retry once the backlog is sampled again
+	g.Update(backlog.New(200, 0, 0, now), now)
+	decision = g.Admit(now)
+	assert.False(t, decision.Admitted)
+	assert.Equal(t, 15*time.Second, decision.RetryAfter)
+
+	assert.True(t, g.Admit(now.Add(time.Minute)).Admitted, "a stale backlog is ignored")
+
+	var nilGate *Gate
+	assert.True(t, nilGate.Admit(now).Admitted)
+}
+
+func TestGate_Poll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, backlog.Path, r.URL.Path)
+		json.NewEncoder(w).Encode(backlog.New(500, 0, 1, time.Now()))
+	}))
+	defer server.Close()
+
+	g := NewGate(server.URL, server.Client(), Limits{Depth: 100}, time.Minute, zap.NewNop())
+	require.NoError(t, g.Poll(context.Background()))
+	assert.False(t, g.Admit(time.Now()).Admitted)
+
+	server.Close()
+	assert.Error(t, g.Poll(context.Background()))
+}
@@ -0,0 +1,49 @@
+// Package capture records sampled gateway traffic for replay against
+// another deployment.
+//
+// Captured inferences are inference log records, one JSON object per line,
+// so a capture file can be replayed with tests/replay or loaded like any
+// other inference log.
+package capture
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// File appends records to a JSON lines file
+type File struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFile opens path for appending, creating it if needed
+func OpenFile(path string) (*File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open capture file: %w", err)
+	}
+	return &File{file: file}, nil
+}
+
+// Publish writes value as a line. It satisfies publish.Publisher.
+func (f *File) Publish(ctx context.Context, key string, value []byte) error {
+	line := make([]byte, 0, len(value)+1)
+	line = append(append(line, value...), '\n')
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write capture record: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,56 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+)
+
+func TestFile_AppendsRecordsAsLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.jsonl")
+	file, err := OpenFile(path)
+	require.NoError(t, err)
+
+	cfg := inferencelog.Config{Enabled: true, SampleRate: 1, MaxPayloadBytes: 1 << 10}
+	recorder := inferencelog.NewCapture(cfg, "api-gateway", file, 10, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		recorder.Run(ctx)
+		close(done)
+	}()
+
+	recorder.Record(context.Background(), inferencelog.Record{Model: "resnet18", Version: "v1", Input: map[string]interface{}{"x": 1.0}})
+	recorder.Record(context.Background(), inferencelog.Record{Model: "resnet18", Version: "v1", Error: "unavailable"})
+	cancel()
+	<-done
+	require.NoError(t, file.Close())
+
+	// Reopening appends rather than truncating
+	file, err = OpenFile(path)
+	require.NoError(t, err)
+	require.NoError(t, file.Publish(context.Background(), "bert", []byte(`{"model":"bert"}`)))
+	require.NoError(t, file.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	var record inferencelog.Record
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "api-gateway", record.Service)
+	assert.Equal(t, 1.0, record.Input["x"])
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "unavailable", record.Error)
+	assert.Equal(t, `{"model":"bert"}`, lines[2])
+}
@@ -2,13 +2,16 @@ package config
 
 import (
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/IBM/sarama"
 	"github.com/redis/go-redis/v9"
 )
 
-// Config holds application configuration
+// Config holds application configuration. Fields tagged secret are left
+// out of Redacted.
 type Config struct {
 	// Server
 	ServiceName string
@@ -16,14 +19,131 @@ type Config struct {
 	LogLevel    string
 
 	// Authentication
-	JWTSecret string
+	JWTSecret string `secret:"true"`
+
+	// Tokens issued by the gateway; AuthClients lists the client credentials
+	// accepted, as a JSON list
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	AuthClients     string `secret:"true"`
+
+	// External OIDC provider, such as Keycloak, whose tokens are accepted;
+	// an empty issuer URL disables it. Claims are dotted paths.
+	OIDCIssuerURL   string
+	OIDCAudience    string
+	OIDCClockSkew   time.Duration
+	OIDCTenantClaim string
+	OIDCRoleClaim   string
+	OIDCAdminRole   string
+	OIDCJWKSRefresh time.Duration
+
+	// Secrets
+	SecretsPath string
 
 	// Dependencies
 	RedisHost         string
 	RouterServiceURL  string
 	MetadataServiceURL string
+	BatchWorkerURL    string
 	KafkaBrokers      []string
 	KafkaTopic        string
+	KafkaConsumerGroup string
+	UsageTopic        string
+	ControlTopic      string
+
+	// Router client; a request's deadline is the router timeout, retries
+	// share a budget and the breaker opens while the router is unreachable
+	RouterTimeout            time.Duration
+	RouterMaxRetries         int
+	RouterRetryBudget        float64
+	RouterBreakerOpenTimeout time.Duration
+
+	// Per-model rate limits as a JSON object of models, overridden at runtime
+	// by the ratelimit:models Redis hash, which is read every refresh interval
+	ModelRateLimits        string
+	ModelRateLimitsRefresh time.Duration
+
+	// RateLimitFailurePolicy is how rate limits and quotas are enforced while
+	// Redis is unreachable: open, closed or local
+	RateLimitFailurePolicy string
+
+	// Settings changed through /admin/config are read from Redis every
+	// refresh interval
+	SettingsRefresh time.Duration
+
+	// Deterministic models whose real-time responses are cached in Redis, as
+	// a JSON object of models and TTLs
+	ResponseCacheModels string
+
+	// Versions served when requests name none, as a JSON object of models
+	// and versions; other models are served at their latest active version
+	// in the metadata service, looked up once per cache TTL
+	DefaultModelVersions string
+	ModelVersionCacheTTL time.Duration
+
+	// Per-model rates inferences' estimated costs are computed from, as a
+	// JSON object of models (or "*") and rates
+	ModelPricing string
+
+	// Access events for analytics; sample rates are the fractions of
+	// successful and failed requests published
+	AccessLogTopic           string
+	AccessLogSampleRate      float64
+	AccessLogErrorSampleRate float64
+
+	// Longest shutdown waits for requests and Kafka sends under way
+	DrainTimeout time.Duration
+
+	// Admission control for inferences; a zero limit admits every request and
+	// a zero latency target keeps the limit fixed
+	AdmissionMaxInFlight   int
+	AdmissionMinInFlight   int
+	AdmissionQueueSize     int
+	AdmissionMaxWait       time.Duration
+	AdmissionLatencyTarget time.Duration
+
+	// Tenancy; an empty tenant service URL keeps the gateway single-tenant.
+	// TenantTopics lists tenants whose batch jobs are queued on topics of
+	// their own.
+	TenantServiceURL string
+	TenantCacheTTL   time.Duration
+	TenantTopics     []string
+
+	// Batch backpressure; zero limits are not enforced
+	BatchBacklogLimit   int64
+	BatchDelayLimit     time.Duration
+	BacklogPollInterval time.Duration
+
+	// Traffic capture for replay; an empty path disables it
+	CapturePath            string
+	CaptureSampleRate      float64
+	CaptureRedact          []string
+	CaptureMaxPayloadBytes int
+
+	// Streamed inferences are ended after this long
+	MaxStreamDuration time.Duration
+
+	// Most targets a fan-out inference may list
+	MaxFanoutTargets int
+
+	// Request body limits; RouteBodyLimits overrides MaxBodyBytes per route
+	MaxBodyBytes    int64
+	RouteBodyLimits map[string]int64
+
+	// Responses smaller than this are sent uncompressed
+	CompressMinBytes int
+
+	// WebSocket inference sessions
+	SessionMaxInFlight     int
+	SessionMaxMessageBytes int64
+	SessionIdleTimeout     time.Duration
+	SessionMaxDuration     time.Duration
+
+	// Webhooks announcing model changes read from EventTopic; the endpoints
+	// are a JSON list
+	EventTopic           string
+	WebhookConsumerGroup string
+	WebhookEndpoints     string `secret:"true"`
 
 	// Observability
 	JaegerEndpoint string
@@ -35,16 +155,95 @@ func Load() *Config {
 		ServiceName:        getEnv("SERVICE_NAME", "api-gateway"),
 		Port:               getEnv("PORT", "8080"),
 		LogLevel:           getEnv("LOG_LEVEL", "info"),
-		JWTSecret:          getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
+		JWTSecret:          getEnv("JWT_SECRET", ""),
+		AccessTokenTTL:     getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:    getEnvDuration("REFRESH_TOKEN_TTL", 24*time.Hour),
+		AuthClients:        getEnv("AUTH_CLIENTS", ""),
+		OIDCIssuerURL:      getEnv("OIDC_ISSUER_URL", ""),
+		OIDCAudience:       getEnv("OIDC_AUDIENCE", ""),
+		OIDCClockSkew:      getEnvDuration("OIDC_CLOCK_SKEW", 30*time.Second),
+		OIDCTenantClaim:    getEnv("OIDC_TENANT_CLAIM", "tenant_id"),
+		OIDCRoleClaim:      getEnv("OIDC_ROLE_CLAIM", "realm_access.roles"),
+		OIDCAdminRole:      getEnv("OIDC_ADMIN_ROLE", "admin"),
+		OIDCJWKSRefresh:    getEnvDuration("OIDC_JWKS_REFRESH", time.Hour),
+		SecretsPath:        getEnv("SECRETS_PATH", "secret/data/api-gateway"),
 		RedisHost:          getEnv("REDIS_HOST", "localhost:6379"),
 		RouterServiceURL:   getEnv("ROUTER_SERVICE_URL", "http://localhost:8081"),
+		RouterTimeout:            getEnvDuration("ROUTER_TIMEOUT", 30*time.Second),
+		RouterMaxRetries:         int(getEnvInt64("ROUTER_MAX_RETRIES", 2)),
+		RouterRetryBudget:        getEnvFloat("ROUTER_RETRY_BUDGET", 0.1),
+		RouterBreakerOpenTimeout: getEnvDuration("ROUTER_BREAKER_OPEN_TIMEOUT", 15*time.Second),
+		ModelRateLimits:          getEnv("MODEL_RATE_LIMITS", ""),
+		RateLimitFailurePolicy:   getEnv("RATE_LIMIT_FAILURE_POLICY", "open"),
+		ModelRateLimitsRefresh:   getEnvDuration("MODEL_RATE_LIMITS_REFRESH", 30*time.Second),
+		SettingsRefresh:          getEnvDuration("SETTINGS_REFRESH", 10*time.Second),
+		ResponseCacheModels:      getEnv("RESPONSE_CACHE_MODELS", ""),
+		DefaultModelVersions:     getEnv("DEFAULT_MODEL_VERSIONS", ""),
+		ModelVersionCacheTTL:     getEnvDuration("MODEL_VERSION_CACHE_TTL", 30*time.Second),
+		ModelPricing:             getEnv("MODEL_PRICING", ""),
+		AccessLogTopic:           getEnv("ACCESS_LOG_TOPIC", "access-logs"),
+		AccessLogSampleRate:      getEnvFloat("ACCESS_LOG_SAMPLE_RATE", 0.1),
+		AccessLogErrorSampleRate: getEnvFloat("ACCESS_LOG_ERROR_SAMPLE_RATE", 1),
+		DrainTimeout:             getEnvDuration("DRAIN_TIMEOUT", 30*time.Second),
+		AdmissionMaxInFlight:     int(getEnvInt64("ADMISSION_MAX_IN_FLIGHT", 512)),
+		AdmissionMinInFlight:     int(getEnvInt64("ADMISSION_MIN_IN_FLIGHT", 16)),
+		AdmissionQueueSize:       int(getEnvInt64("ADMISSION_QUEUE_SIZE", 1024)),
+		AdmissionMaxWait:         getEnvDuration("ADMISSION_MAX_WAIT", time.Second),
+		AdmissionLatencyTarget:   getEnvDuration("ADMISSION_LATENCY_TARGET", 2*time.Second),
 		MetadataServiceURL: getEnv("METADATA_SERVICE_URL", "http://localhost:8083"),
+		BatchWorkerURL:     getEnv("BATCH_WORKER_URL", "http://localhost:8084"),
 		KafkaBrokers:       strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ","),
 		KafkaTopic:         getEnv("KAFKA_TOPIC", "inference-jobs"),
+		KafkaConsumerGroup: getEnv("KAFKA_CONSUMER_GROUP", "batch-worker-group"),
+		UsageTopic:         getEnv("USAGE_TOPIC", "usage-events"),
+		ControlTopic:       getEnv("CONTROL_TOPIC", "batch-control"),
+		TenantServiceURL:   getEnv("TENANT_SERVICE_URL", ""),
+		TenantCacheTTL:     getEnvDuration("TENANT_CACHE_TTL", 30*time.Second),
+		TenantTopics:       getEnvList("TENANT_TOPICS"),
+		BatchBacklogLimit:   getEnvInt64("BATCH_BACKLOG_LIMIT", 10000),
+		BatchDelayLimit:     getEnvDuration("BATCH_DELAY_LIMIT", 30*time.Minute),
+		BacklogPollInterval: getEnvDuration("BACKLOG_POLL_INTERVAL", 5*time.Second),
+		CapturePath:            getEnv("CAPTURE_PATH", ""),
+		CaptureSampleRate:      getEnvFloat("CAPTURE_SAMPLE_RATE", 0.01),
+		CaptureRedact:          getEnvList("CAPTURE_REDACT"),
+		CaptureMaxPayloadBytes: int(getEnvInt64("CAPTURE_MAX_PAYLOAD_BYTES", 1<<20)),
+		MaxStreamDuration:      getEnvDuration("MAX_STREAM_DURATION", 10*time.Minute),
+		MaxFanoutTargets:       int(getEnvInt64("FANOUT_MAX_TARGETS", 8)),
+		MaxBodyBytes:           getEnvInt64("MAX_BODY_BYTES", 1<<20),
+		RouteBodyLimits:        getEnvLimits("ROUTE_BODY_LIMITS", map[string]int64{"/v1/batch": 32 << 20, "/v1/infer": 8 << 20}),
+		CompressMinBytes:       int(getEnvInt64("COMPRESS_MIN_BYTES", 1024)),
+		SessionMaxInFlight:     int(getEnvInt64("WS_MAX_IN_FLIGHT", 8)),
+		SessionMaxMessageBytes: getEnvInt64("WS_MAX_MESSAGE_BYTES", 1<<20),
+		SessionIdleTimeout:     getEnvDuration("WS_IDLE_TIMEOUT", 5*time.Minute),
+		SessionMaxDuration:     getEnvDuration("WS_MAX_SESSION_DURATION", time.Hour),
+		EventTopic:             getEnv("EVENT_TOPIC", "platform-events"),
+		WebhookConsumerGroup:   getEnv("WEBHOOK_CONSUMER_GROUP", "api-gateway-webhooks"),
+		WebhookEndpoints:       getEnv("WEBHOOK_ENDPOINTS", ""),
 		JaegerEndpoint:     getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
 	}
 }
 
+// Redacted returns the configuration for display, by field name. Secrets
+// are replaced by "[redacted]" when set, and durations are written as
+// strings such as "1m30s".
+func (c *Config) Redacted() map[string]interface{} {
+	redacted := make(map[string]interface{})
+	value := reflect.ValueOf(*c)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		switch fieldValue := value.Field(i).Interface().(type) {
+		case time.Duration:
+			redacted[field.Name] = fieldValue.String()
+		default:
+			if field.Tag.Get("secret") == "true" && !value.Field(i).IsZero() {
+				fieldValue = "[redacted]"
+			}
+			redacted[field.Name] = fieldValue
+		}
+	}
+	return redacted
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -52,19 +251,66 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvLimits parses comma-separated name=value pairs, such as
+// "/v1/batch=33554432"; malformed pairs are skipped
+func getEnvLimits(key string, defaultValue map[string]int64) map[string]int64 {
+	values := getEnvList(key)
+	if len(values) == 0 {
+		return defaultValue
+	}
+	limits := make(map[string]int64)
+	for _, value := range values {
+		name, limit, ok := strings.Cut(value, "=")
+		if !ok {
+			continue
+		}
+		if parsed, err := strconv.ParseInt(strings.TrimSpace(limit), 10, 64); err == nil {
+			limits[strings.TrimSpace(name)] = parsed
+		}
+	}
+	return limits
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
 // NewRedisClient creates a new Redis client
 func NewRedisClient(addr string) *redis.Client {
 	return redis.NewClient(&redis.Options{
 		Addr: addr,
 	})
 }
-
-// NewKafkaProducer creates a new Kafka producer
-func NewKafkaProducer(brokers []string) (sarama.SyncProducer, error) {
-	config := sarama.NewConfig()
-	config.Producer.RequiredAcks = sarama.WaitForAll
-	config.Producer.Retry.Max = 5
-	config.Producer.Return.Successes = true
-
-	return sarama.NewSyncProducer(brokers, config)
-}
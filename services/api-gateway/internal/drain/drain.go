@@ -0,0 +1,162 @@
+// Package drain coordinates the gateway's shutdown: once draining starts it
+// turns new requests away and fails readiness, then waits for the requests
+// and Kafka sends already under way so that nothing accepted is lost when
+// the producer closes.
+package drain
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/IBM/sarama"
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Drainer counts the work in flight and reports when it is done
+type Drainer struct {
+	mu       sync.Mutex
+	inFlight int
+	draining bool
+	idle     chan struct{}
+}
+
+// New creates a drainer that is not draining
+func New() *Drainer {
+	return &Drainer{idle: make(chan struct{})}
+}
+
+// Begin registers work, reporting false once draining has started
+func (d *Drainer) Begin() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	d.inFlight++
+	return true
+}
+
+// Done ends work registered with Begin
+func (d *Drainer) Done() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inFlight--
+	d.signal()
+}
+
+// Draining reports whether draining has started
+func (d *Drainer) Draining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// Start stops new work from being accepted
+func (d *Drainer) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.draining {
+		d.draining = true
+		d.signal()
+	}
+}
+
+// Wait starts draining and waits until the work in flight is done or ctx
+// ends, returning ctx's error in that case
+func (d *Drainer) Wait(ctx context.Context) error {
+	d.Start()
+	select {
+	case <-d.idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// InFlight returns the work not yet done
+func (d *Drainer) InFlight() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.inFlight
+}
+
+// signal closes idle once draining has started and nothing is in flight
+func (d *Drainer) signal() {
+	if d.draining && d.inFlight == 0 {
+		select {
+		case <-d.idle:
+		default:
+			close(d.idle)
+		}
+	}
+}
+
+// Check fails readiness while draining, so load balancers stop sending
+// requests before the listener closes
+func (d *Drainer) Check(ctx context.Context) error {
+	if d.Draining() {
+		return apperrors.New(apperrors.Unavailable, "draining for shutdown")
+	}
+	return nil
+}
+
+// Middleware answers requests arriving while draining with 503 and asks
+// clients to reconnect, and counts the others until they finish. Inference
+// sessions are not counted, since they last until the client ends them.
+func (d *Drainer) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !d.Begin() {
+			c.Header("Connection", "close")
+			c.Header("Retry-After", "1")
+			apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.Unavailable, "gateway is shutting down"))
+			c.Abort()
+			return
+		}
+		if isUpgrade(c.Request) {
+			d.Done()
+			c.Next()
+			return
+		}
+		defer d.Done()
+		c.Next()
+	}
+}
+
+func isUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// Producer counts sends through producer, so draining waits for them. Sends
+// are still accepted while draining: they finish work already accepted, such
+// as buffered usage events.
+func (d *Drainer) Producer(producer sarama.SyncProducer) sarama.SyncProducer {
+	return &trackedProducer{SyncProducer: producer, drainer: d}
+}
+
+type trackedProducer struct {
+	sarama.SyncProducer
+	drainer *Drainer
+}
+
+func (p *trackedProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.drainer.track()
+	defer p.drainer.Done()
+	return p.SyncProducer.SendMessage(msg)
+}
+
+func (p *trackedProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	p.drainer.track()
+	defer p.drainer.Done()
+	return p.SyncProducer.SendMessages(msgs)
+}
+
+// track registers work whether or not draining has started
+func (d *Drainer) track() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inFlight++
+}
@@ -0,0 +1,87 @@
+package drain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainer_WaitsForRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	drainer := New()
+
+	started, finish := make(chan struct{}), make(chan struct{})
+	router := gin.New()
+	router.Use(drainer.Middleware())
+	router.POST("/v1/batch", func(c *gin.Context) {
+		close(started)
+		<-finish
+		c.Status(http.StatusAccepted)
+	})
+
+	first := httptest.NewRecorder()
+	go router.ServeHTTP(first, httptest.NewRequest("POST", "/v1/batch", nil))
+	<-started
+
+	drained := make(chan error)
+	go func() { drained <- drainer.Wait(context.Background()) }()
+	assert.Eventually(t, drainer.Draining, time.Second, time.Millisecond)
+	assert.Error(t, drainer.Check(context.Background()))
+
+	// New requests are turned away while the first finishes
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/batch", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "close", w.Header().Get("Connection"))
+
+	select {
+	case <-drained:
+		t.Fatal("drained with a request in flight")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(finish)
+	require.NoError(t, <-drained)
+	assert.Equal(t, http.StatusAccepted, first.Code)
+}
+
+func TestDrainer_WaitsForProducerSends(t *testing.T) {
+	drainer := New()
+	release := make(chan struct{})
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(val []byte) error {
+		<-release
+		return nil
+	})
+	tracked := drainer.Producer(producer)
+
+	sent := make(chan error)
+	go func() {
+		_, _, err := tracked.SendMessage(&sarama.ProducerMessage{Topic: "usage-events", Value: sarama.StringEncoder("{}")})
+		sent <- err
+	}()
+	assert.Eventually(t, func() bool { return drainer.InFlight() == 1 }, time.Second, time.Millisecond)
+
+	// A drain that runs out of time reports it
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, drainer.Wait(ctx), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, <-sent)
+	assert.NoError(t, drainer.Wait(context.Background()))
+}
+
+func TestDrainer_IdleDrainsAtOnce(t *testing.T) {
+	drainer := New()
+	assert.NoError(t, drainer.Check(context.Background()))
+	assert.NoError(t, drainer.Wait(context.Background()))
+	assert.False(t, drainer.Begin())
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/admin"
+	"github.com/yourusername/ai-platform/api-gateway/internal/settings"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// AdminOverview returns the aggregated platform state for the ops dashboard.
+// Unreachable sources are reported in the body rather than failing the request.
+func AdminOverview(aggregator *admin.Aggregator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, aggregator.Overview(c.Request.Context()))
+	}
+}
+
+// AdminTopology returns what is serving what, where. Unreachable sources are
+// reported in the body rather than failing the request.
+func AdminTopology(aggregator *admin.Aggregator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, aggregator.Topology(c.Request.Context()))
+	}
+}
+
+// AdminConfig returns the configuration the gateway started with, secrets
+// redacted, and the settings in force
+func AdminConfig(configured map[string]interface{}, store *settings.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"config": configured, "runtime": store.Current()})
+	}
+}
+
+// AdminSetLogLevel changes the log level of every gateway replica, from a
+// body such as {"level": "debug"}; DELETE restores the configured level
+func AdminSetLogLevel(store *settings.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Level string `json:"level" binding:"required"`
+		}
+		if c.Request.Method != http.MethodDelete {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+				return
+			}
+		}
+		writeSettings(c, store, store.SetLogLevel(c.Request.Context(), req.Level))
+	}
+}
+
+// AdminSetRateTiers replaces the rate tiers of every gateway replica with a
+// JSON object of plans, as in RATE_LIMIT_TIERS; DELETE restores the
+// configured tiers
+func AdminSetRateTiers(store *settings.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var tiers json.RawMessage
+		if c.Request.Method != http.MethodDelete {
+			if err := c.ShouldBindJSON(&tiers); err != nil {
+				apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+				return
+			}
+		}
+		writeSettings(c, store, store.SetRateTiers(c.Request.Context(), tiers))
+	}
+}
+
+// AdminSetMaintenance turns maintenance mode on or off, from a body such as
+// {"enabled": true, "message": "back at 14:00 UTC", "retry_after": 600}
+func AdminSetMaintenance(store *settings.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var maintenance settings.Maintenance
+		if err := c.ShouldBindJSON(&maintenance); err != nil {
+			apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+			return
+		}
+		writeSettings(c, store, store.SetMaintenance(c.Request.Context(), maintenance))
+	}
+}
+
+// writeSettings answers a settings change with the settings in force
+func writeSettings(c *gin.Context, store *settings.Store, err error) {
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+	c.JSON(http.StatusOK, store.Current())
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/api-gateway/internal/quota"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/transport"
+)
+
+// AsyncInferenceRequest is an inference whose result is posted to CallbackURL
+// instead of being returned
+type AsyncInferenceRequest struct {
+	InferenceRequest
+	CallbackURL string `json:"callback_url" binding:"required"`
+	// SubjectID optionally identifies the data subject the input belongs to
+	SubjectID string `json:"subject_id,omitempty"`
+}
+
+// AsyncInference queues an inference for the batch worker, which posts the
+// signed result to the request's callback URL once it is done. It suits
+// clients that cannot hold a connection open for slow models.
+func (h *InferenceHandler) AsyncInference(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("api-gateway")
+	ctx, span := tracer.Start(ctx, "AsyncInference")
+	defer span.End()
+
+	var req AsyncInferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if middleware.AbortBodyTooLarge(c, err) {
+			return
+		}
+		logging.With(ctx, h.logger).Error("invalid request", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+	if err := validateCallbackURL(req.CallbackURL); err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+
+	if !h.admitJob(ctx, c) {
+		return
+	}
+
+	tenant, limits := callerTenant(c)
+	if err := h.consumeQuota(ctx, tenant, limits, quota.Requests, 1); err != nil {
+		writeQuotaError(c, err)
+		return
+	}
+
+	// Set default version if not provided
+	if req.Version == "" {
+		req.Version = h.versions.Resolve(ctx, req.Model)
+	}
+	tagModel(c, req.Model, req.Version)
+
+	jobID := uuid.New().String()
+	ctx = logging.WithJobID(ctx, jobID)
+
+	span.SetAttributes(
+		attribute.String("model", req.Model),
+		attribute.String("version", req.Version),
+		attribute.String("job_id", jobID),
+	)
+
+	logging.With(ctx, h.logger).Info("submitting async inference",
+		zap.String("model", req.Model),
+		zap.String("callback_host", hostOf(req.CallbackURL)),
+	)
+
+	// Async inferences run as single-input batch jobs
+	job := schema.BatchJob{
+		JobID:       jobID,
+		Model:       req.Model,
+		Version:     req.Version,
+		Inputs:      []map[string]interface{}{req.Input},
+		Tenant:      tenant,
+		SubjectID:   req.SubjectID,
+		UserID:      c.GetString("user_id"),
+		CallbackURL: req.CallbackURL,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := h.enqueue(ctx, job); err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, BatchJobResponse{
+		JobID:     jobID,
+		Status:    "pending",
+		CreatedAt: job.CreatedAt,
+	})
+}
+
+// validateCallbackURL checks that results can be posted to a callback URL
+// and that it does not point inside the platform. The batch worker checks
+// the addresses names resolve to again when it connects.
+func validateCallbackURL(callbackURL string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return apperrors.New(apperrors.InvalidArgument, "callback_url must be an absolute URL")
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return apperrors.Newf(apperrors.InvalidArgument, "callback_url scheme %q is not supported", u.Scheme)
+	}
+	if u.User != nil {
+		return apperrors.New(apperrors.InvalidArgument, "callback_url must not carry credentials")
+	}
+	if err := transport.CheckPublicHost(u.Hostname()); err != nil {
+		return apperrors.Wrap(err, apperrors.InvalidArgument, "callback_url must be a public address")
+	}
+	return nil
+}
+
+// hostOf returns the host of a URL for logging, leaving out paths that may
+// carry tokens
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
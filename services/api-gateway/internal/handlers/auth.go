@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/auth"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Grant types accepted by IssueToken
+const (
+	GrantAPIKey            = "api_key"
+	GrantClientCredentials = "client_credentials"
+	GrantRefreshToken      = "refresh_token"
+)
+
+// TokenRequest exchanges credentials or a refresh token for a token pair
+type TokenRequest struct {
+	GrantType    string `json:"grant_type" binding:"required,oneof=api_key client_credentials refresh_token"`
+	APIKey       string `json:"api_key"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// IssueToken exchanges an API key, client credentials or a refresh token for
+// a short-lived access token and a single-use refresh token
+func IssueToken(issuer *auth.Issuer, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var req TokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+			return
+		}
+
+		var (
+			tokens *auth.Tokens
+			err    error
+		)
+		switch req.GrantType {
+		case GrantAPIKey:
+			tokens, err = issuer.ExchangeKey(ctx, req.APIKey)
+		case GrantClientCredentials:
+			tokens, err = issuer.ExchangeClientCredentials(ctx, req.ClientID, req.ClientSecret)
+		case GrantRefreshToken:
+			tokens, err = issuer.Refresh(ctx, req.RefreshToken)
+		}
+		if err != nil {
+			logging.With(ctx, logger).Warn("token request refused", zap.String("grant_type", req.GrantType), zap.Error(err))
+			apperrors.Write(c.Writer, c.Request, err)
+			return
+		}
+
+		// Tokens must not be kept by shared caches
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, tokens)
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/auth"
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+)
+
+type usedTokens map[string]bool
+
+func (u usedTokens) Use(ctx context.Context, id string, expireAt time.Time) (bool, error) {
+	if u[id] {
+		return false, nil
+	}
+	u[id] = true
+	return true, nil
+}
+
+type apiKeys map[string]*tenancy.Principal
+
+func (k apiKeys) Verify(ctx context.Context, key string) (*tenancy.Principal, error) {
+	if principal, ok := k[key]; ok {
+		return principal, nil
+	}
+	return nil, apperrors.New(apperrors.Unauthenticated, "invalid api key")
+}
+
+func TestIssueToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keys := auth.NewKeyring("signing-secret", 24*time.Hour)
+	issuer := auth.NewIssuer(keys, usedTokens{}, 15*time.Minute, 24*time.Hour)
+	issuer.SetAPIKeys(apiKeys{"aip_valid": {KeyID: "k1", TenantID: "acme", Role: tenancy.RoleMember}})
+
+	router := gin.New()
+	router.POST("/auth/token", IssueToken(issuer, zap.NewNop()))
+	router.GET("/v1/whoami", middleware.AuthWithSigningKeys(keys, nil), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetString("user_id"), "tenant": c.GetString("tenant")})
+	})
+
+	request := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/auth/token", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(`{"grant_type":"api_key","api_key":"aip_valid"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	var tokens auth.Tokens
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+
+	// The access token authenticates requests; the refresh token does not
+	whoami := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/v1/whoami", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	w = whoami(tokens.AccessToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_id":"key:k1","tenant":"acme"}`, w.Body.String())
+	assert.Equal(t, http.StatusUnauthorized, whoami(tokens.RefreshToken).Code)
+
+	// Tokens issued before a rotation keep working
+	keys.Rotate("rotated-secret")
+	assert.Equal(t, http.StatusOK, whoami(tokens.AccessToken).Code)
+
+	w = request(`{"grant_type":"refresh_token","refresh_token":"` + tokens.RefreshToken + `"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusUnauthorized, request(`{"grant_type":"refresh_token","refresh_token":"`+tokens.RefreshToken+`"}`).Code)
+
+	assert.Equal(t, http.StatusUnauthorized, request(`{"grant_type":"api_key","api_key":"aip_revoked"}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, request(`{"grant_type":"client_credentials","client_id":"ci","client_secret":"x"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request(`{"grant_type":"password"}`).Code)
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/inferencepb"
+	"github.com/yourusername/ai-platform/pkg/media"
+)
+
+// bindInference decodes an inference request sent as JSON, as an
+// inferencepb.InferenceRequest with Content-Type application/x-protobuf, as a
+// multipart form or as a raw image or audio clip
+func bindInference(c *gin.Context, req *InferenceRequest) error {
+	contentType := c.ContentType()
+	switch {
+	case contentType == inferencepb.ContentType:
+		return bindProtobuf(c, req)
+	case contentType == "multipart/form-data":
+		return bindMultipart(c, req)
+	}
+	if mediaType, ok := media.Normalize(contentType); ok {
+		return bindBinary(c, req, mediaType)
+	}
+	return c.ShouldBindJSON(req)
+}
+
+func bindProtobuf(c *gin.Context, req *InferenceRequest) error {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	var msg inferencepb.InferenceRequest
+	if err := proto.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+	if msg.Model == "" {
+		return errors.New("model is required")
+	}
+	if len(msg.Inputs) == 0 {
+		return errors.New("inputs are required")
+	}
+
+	req.Model = msg.Model
+	req.Version = msg.Version
+	req.Input = make(map[string]interface{}, len(msg.Inputs))
+	for name, tensor := range msg.Inputs {
+		value, err := tensor.Value()
+		if err != nil {
+			return fmt.Errorf("input %s: %w", name, err)
+		}
+		req.Input[name] = value
+	}
+	return nil
+}
+
+// bindMultipart decodes a multipart/form-data request: the model and
+// version fields name the model, each file is a binary input named after its
+// field, and other fields are inputs holding their JSON value, or their text
+// when it is not JSON
+func bindMultipart(c *gin.Context, req *InferenceRequest) error {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return err
+	}
+	req.Input = make(map[string]interface{})
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := part.FormName()
+		data, err := io.ReadAll(part)
+		part.Close()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case name == "":
+			continue
+		case name == "model":
+			req.Model = string(data)
+		case name == "version":
+			req.Version = string(data)
+		case part.FileName() != "":
+			contentType := part.Header.Get("Content-Type")
+			if contentType == "" || contentType == "application/octet-stream" {
+				contentType = http.DetectContentType(data)
+			}
+			mediaType, ok := media.Normalize(contentType)
+			if !ok {
+				return fmt.Errorf("input %s: unsupported media type %q", name, contentType)
+			}
+			req.Input[name] = media.Blob{ContentType: mediaType, Data: data}.Value()
+		default:
+			var value interface{}
+			if json.Unmarshal(data, &value) != nil {
+				value = string(data)
+			}
+			req.Input[name] = value
+		}
+	}
+	if req.Model == "" {
+		return errors.New("model is required")
+	}
+	if len(req.Input) == 0 {
+		return errors.New("inputs are required")
+	}
+	return nil
+}
+
+// bindBinary takes a raw image or audio body as the single input of the
+// model named by the model and version query parameters. The input is named
+// by the input parameter, "image" or "audio" by default.
+func bindBinary(c *gin.Context, req *InferenceRequest, mediaType string) error {
+	req.Model = c.Query("model")
+	req.Version = c.Query("version")
+	if req.Model == "" {
+		return errors.New("model is required")
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.New("inputs are required")
+	}
+	name := c.Query("input")
+	if name == "" {
+		name = "image"
+		if mediaType == media.WAV {
+			name = "audio"
+		}
+	}
+	req.Input = map[string]interface{}{name: media.Blob{ContentType: mediaType, Data: data}.Value()}
+	return nil
+}
+
+// wantsProtobuf reports whether the response should be protobuf: when the
+// Accept header asks for it, or mirroring the request when Accept names no
+// type
+func wantsProtobuf(c *gin.Context) bool {
+	accept := c.GetHeader("Accept")
+	if accept == "" || accept == "*/*" {
+		return c.ContentType() == inferencepb.ContentType
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mediaType == inferencepb.ContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// writeInference answers with the response in the negotiated encoding
+func writeInference(c *gin.Context, response *InferenceResponse) error {
+	c.Header("Vary", "Accept")
+	relayExperiment(c, response.Experiment, response.Variant)
+	if !wantsProtobuf(c) {
+		c.JSON(http.StatusOK, response)
+		return nil
+	}
+
+	msg := &inferencepb.InferenceResponse{
+		RequestId:     response.RequestID,
+		Model:         response.Model,
+		Version:       response.Version,
+		Outputs:       make(map[string]*inferencepb.Tensor, len(response.Prediction)),
+		LatencyMs:     response.Latency,
+		EstimatedCost: response.EstimatedCost,
+	}
+	for name, value := range response.Prediction {
+		tensor, err := inferencepb.TensorFromValue(value)
+		if err != nil {
+			return err
+		}
+		msg.Outputs[name] = tensor
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.Data(http.StatusOK, inferencepb.ContentType, data)
+	return nil
+}
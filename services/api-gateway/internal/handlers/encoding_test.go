@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/inferencepb"
+	"github.com/yourusername/ai-platform/pkg/media"
+)
+
+func encodingRouter(t *testing.T, forwarded *map[string]interface{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, forwarded))
+		w.Write([]byte(`{"probabilities":[[0.25,0.75]],"label":"cat"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	handler := NewInferenceHandler(zap.NewNop(), server.URL, nil, "inference-jobs")
+	router := gin.New()
+	router.POST("/v1/infer", handler.RealTimeInference)
+	return router
+}
+
+func TestRealTimeInference_Protobuf(t *testing.T) {
+	var forwarded map[string]interface{}
+	router := encodingRouter(t, &forwarded)
+
+	msg := &inferencepb.InferenceRequest{
+		Model:  "resnet18",
+		Inputs: map[string]*inferencepb.Tensor{"image": {Shape: []int64{1, 2}, FloatData: []float32{0.5, 1}}},
+	}
+	req := httptest.NewRequest("POST", "/v1/infer", bytes.NewReader(marshal(t, msg)))
+	req.Header.Set("Content-Type", inferencepb.ContentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, inferencepb.ContentType, w.Header().Get("Content-Type"))
+
+	// The router receives the same request a JSON client would send
+	assert.Equal(t, "resnet18", forwarded["model"])
+	assert.Equal(t, map[string]interface{}{"image": []interface{}{[]interface{}{0.5, 1.0}}}, forwarded["input"])
+
+	var resp inferencepb.InferenceResponse
+	require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "resnet18", resp.Model)
+	assert.Equal(t, "v1", resp.Version)
+	assert.True(t, proto.Equal(&inferencepb.Tensor{Shape: []int64{1, 2}, FloatData: []float32{0.25, 0.75}}, resp.Outputs["probabilities"]))
+	assert.True(t, proto.Equal(&inferencepb.Tensor{JsonData: `"cat"`}, resp.Outputs["label"]))
+}
+
+func TestRealTimeInference_NegotiatesResponse(t *testing.T) {
+	var forwarded map[string]interface{}
+	router := encodingRouter(t, &forwarded)
+
+	// A protobuf request may ask for JSON back
+	msg := &inferencepb.InferenceRequest{
+		Model:  "resnet18",
+		Inputs: map[string]*inferencepb.Tensor{"text": {StringData: []string{"hello"}}},
+	}
+	req := httptest.NewRequest("POST", "/v1/infer", bytes.NewReader(marshal(t, msg)))
+	req.Header.Set("Content-Type", inferencepb.ContentType)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	// and a JSON request protobuf
+	req = httptest.NewRequest("POST", "/v1/infer", bytes.NewBufferString(`{"model":"resnet18","input":{"data":[1.0]}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/x-protobuf, application/json;q=0.5")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, inferencepb.ContentType, w.Header().Get("Content-Type"))
+}
+
+func TestRealTimeInference_RejectsInvalidProtobuf(t *testing.T) {
+	var forwarded map[string]interface{}
+	router := encodingRouter(t, &forwarded)
+
+	tests := []struct {
+		name string
+		body []byte
+	}{
+		{"malformed", []byte{0x0a, 0x10, 'r'}},
+		{"no inputs", marshal(t, &inferencepb.InferenceRequest{Model: "resnet18"})},
+		{"shape mismatch", marshal(t, &inferencepb.InferenceRequest{
+			Model:  "resnet18",
+			Inputs: map[string]*inferencepb.Tensor{"image": {Shape: []int64{2, 2}, FloatData: []float32{1}}},
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/infer", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", inferencepb.ContentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Errors are problem documents whatever the negotiated encoding
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/problem+json")
+		})
+	}
+}
+
+func TestRealTimeInference_Multipart(t *testing.T) {
+	var forwarded map[string]interface{}
+	router := encodingRouter(t, &forwarded)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("model", "resnet18")
+	writer.WriteField("version", "v2")
+	writer.WriteField("top_k", "3")
+	writer.WriteField("prompt", "a cat")
+	part, _ := writer.CreateFormFile("image", "cat.png")
+	part.Write([]byte("\x89PNG\r\n\x1a\n"))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/v1/infer", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, "resnet18", forwarded["model"])
+	assert.Equal(t, "v2", forwarded["version"])
+	input := forwarded["input"].(map[string]interface{})
+	assert.Equal(t, 3.0, input["top_k"])
+	assert.Equal(t, "a cat", input["prompt"])
+
+	// The file is detected as PNG and passed on for the orchestrator to decode
+	blob, ok, err := media.FromValue(input["image"])
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, media.PNG, blob.ContentType)
+	assert.Equal(t, []byte("\x89PNG\r\n\x1a\n"), blob.Data)
+}
+
+func TestRealTimeInference_RawBinary(t *testing.T) {
+	var forwarded map[string]interface{}
+	router := encodingRouter(t, &forwarded)
+
+	req := httptest.NewRequest("POST", "/v1/infer?model=whisper", bytes.NewBufferString("RIFF"))
+	req.Header.Set("Content-Type", "audio/x-wav")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, "whisper", forwarded["model"])
+	blob, ok, err := media.FromValue(forwarded["input"].(map[string]interface{})["audio"])
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, media.WAV, blob.ContentType)
+
+	// The model must be named in the query
+	req = httptest.NewRequest("POST", "/v1/infer", bytes.NewBufferString("RIFF"))
+	req.Header.Set("Content-Type", "audio/wav")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRealTimeInference_RejectsUnsupportedFiles(t *testing.T) {
+	var forwarded map[string]interface{}
+	router := encodingRouter(t, &forwarded)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("model", "resnet18")
+	part, _ := writer.CreateFormFile("image", "cat.gif")
+	part.Write([]byte("GIF89a"))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/v1/infer", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unsupported media type")
+	assert.Nil(t, forwarded)
+}
+
+func marshal(t *testing.T, msg proto.Message) []byte {
+	t.Helper()
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return data
+}
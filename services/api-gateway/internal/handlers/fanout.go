@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/api-gateway/internal/quota"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/pricing"
+)
+
+// DefaultMaxFanout is the most targets a fan-out request may list by default
+const DefaultMaxFanout = 8
+
+// FanoutTarget is a model version a fan-out request is sent to
+type FanoutTarget struct {
+	Model   string `json:"model" binding:"required"`
+	Version string `json:"version"`
+}
+
+// FanoutRequest sends one input to several models
+type FanoutRequest struct {
+	Targets []FanoutTarget         `json:"targets" binding:"required,min=1,dive"`
+	Input   map[string]interface{} `json:"input" binding:"required"`
+}
+
+// FanoutResult is one target's outcome: its prediction, or why it failed
+type FanoutResult struct {
+	Model      string                 `json:"model"`
+	Version    string                 `json:"version"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	Prediction map[string]interface{} `json:"prediction,omitempty"`
+	Latency    int64                  `json:"latency_ms"`
+	Error      string                 `json:"error,omitempty"`
+	Code       apperrors.Code         `json:"code,omitempty"`
+	Details    string                 `json:"details,omitempty"`
+	// EstimatedCost is the inference's cost at the gateway's model pricing
+	EstimatedCost float64 `json:"estimated_cost,omitempty"`
+}
+
+// FanoutResponse holds the results in the order of the request's targets
+type FanoutResponse struct {
+	RequestID string         `json:"request_id"`
+	Results   []FanoutResult `json:"results"`
+	Succeeded int            `json:"succeeded"`
+	Latency   int64          `json:"latency_ms"`
+	// EstimatedCost totals the targets' estimated costs
+	EstimatedCost float64 `json:"estimated_cost,omitempty"`
+}
+
+// SetMaxFanout limits how many targets a fan-out request may list
+func (h *InferenceHandler) SetMaxFanout(n int) {
+	h.maxFanout = n
+}
+
+// FanoutInference runs one input against several model versions in
+// parallel. Targets fail independently: the response is 200 with each
+// target's prediction or error, and each target is metered and counted
+// against the quota as a request of its own.
+func (h *InferenceHandler) FanoutInference(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("api-gateway")
+	ctx, span := tracer.Start(ctx, "FanoutInference")
+	defer span.End()
+
+	// The logging middleware assigns the request ID; fall back for direct handler use
+	requestID := logging.RequestID(ctx)
+	if requestID == "" {
+		requestID = uuid.New().String()
+		ctx = logging.WithRequestID(ctx, requestID)
+	}
+
+	var req FanoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if middleware.AbortBodyTooLarge(c, err) {
+			return
+		}
+		logging.With(ctx, h.logger).Error("invalid request", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+	if h.maxFanout > 0 && len(req.Targets) > h.maxFanout {
+		apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.InvalidArgument,
+			"request lists %d targets; at most %d are allowed", len(req.Targets), h.maxFanout))
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("request_id", requestID),
+		attribute.Int("target_count", len(req.Targets)),
+	)
+
+	tenant, limits := callerTenant(c)
+	if err := h.consumeQuota(ctx, tenant, limits, quota.Requests, int64(len(req.Targets))); err != nil {
+		writeQuotaError(c, err)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	startTime := time.Now()
+	results := make([]FanoutResult, len(req.Targets))
+	var wg sync.WaitGroup
+	for i, target := range req.Targets {
+		// Set default version if not provided
+		if target.Version == "" {
+			target.Version = h.versions.Resolve(ctx, target.Model)
+		}
+
+		wg.Add(1)
+		go func(i int, target FanoutTarget) {
+			defer wg.Done()
+
+			targetID := uuid.New().String()
+			targetCtx, targetSpan := tracer.Start(ctx, "FanoutTarget")
+			defer targetSpan.End()
+			targetSpan.SetAttributes(
+				attribute.String("model", target.Model),
+				attribute.String("version", target.Version),
+				attribute.String("request_id", targetID),
+			)
+
+			started := time.Now()
+			response, err := h.infer(targetCtx, targetID, InferenceRequest{
+				Model:   target.Model,
+				Version: target.Version,
+				Input:   req.Input,
+				UserID:  userID,
+			})
+			if err != nil {
+				_, body := apperrors.ToHTTP(err)
+				results[i] = FanoutResult{
+					Model:   target.Model,
+					Version: target.Version,
+					Latency: time.Since(started).Milliseconds(),
+					Error:   body.Error,
+					Code:    body.Code,
+					Details: body.Details,
+				}
+				return
+			}
+			results[i] = FanoutResult{
+				Model:         response.Model,
+				Version:       response.Version,
+				RequestID:     response.RequestID,
+				Prediction:    response.Prediction,
+				Latency:       response.Latency,
+				EstimatedCost: response.EstimatedCost,
+			}
+		}(i, target)
+	}
+	wg.Wait()
+
+	response := FanoutResponse{
+		RequestID: requestID,
+		Results:   results,
+		Latency:   time.Since(startTime).Milliseconds(),
+	}
+	for _, result := range results {
+		if result.Code == "" {
+			response.Succeeded++
+		}
+		response.EstimatedCost += result.EstimatedCost
+	}
+	response.EstimatedCost = pricing.Round(response.EstimatedCost)
+
+	logging.With(ctx, h.logger).Info("fan-out inference completed",
+		zap.Int("targets", len(results)),
+		zap.Int("succeeded", response.Succeeded),
+		zap.Int64("latency_ms", response.Latency),
+	)
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestFanoutInference_CombinesResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Every target's request must be in flight before any is answered
+	var arrived sync.WaitGroup
+	arrived.Add(3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model   string `json:"model"`
+			Version string `json:"version"`
+			UserID  string `json:"user_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		assert.Equal(t, "alice", req.UserID)
+
+		arrived.Done()
+		waited := make(chan struct{})
+		go func() { arrived.Wait(); close(waited) }()
+		select {
+		case <-waited:
+		case <-time.After(5 * time.Second):
+			t.Error("targets were not sent in parallel")
+		}
+
+		if req.Model == "resnet99" {
+			apperrors.WriteHTTP(w, apperrors.New(apperrors.NotFound, "model not found: resnet99"))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"class": "cat", "version": req.Version})
+	}))
+	defer server.Close()
+
+	handler := NewInferenceHandler(zap.NewNop(), server.URL, nil, "inference-jobs")
+	router := gin.New()
+	router.POST("/v1/infer/fanout", func(c *gin.Context) { c.Set("user_id", "alice") }, handler.FanoutInference)
+
+	body := bytes.NewBufferString(`{"targets":[{"model":"resnet18","version":"v2"},{"model":"resnet99"},{"model":"resnet18"}],"input":{"data":[1.0]}}`)
+	req := httptest.NewRequest("POST", "/v1/infer/fanout", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp FanoutResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Succeeded)
+	require.Len(t, resp.Results, 3)
+
+	assert.Equal(t, "resnet18", resp.Results[0].Model)
+	assert.Equal(t, "v2", resp.Results[0].Prediction["version"])
+	assert.NotEmpty(t, resp.Results[0].RequestID)
+	assert.Empty(t, resp.Results[0].Code)
+
+	assert.Equal(t, "resnet99", resp.Results[1].Model)
+	assert.Equal(t, "v1", resp.Results[1].Version)
+	assert.Equal(t, apperrors.NotFound, resp.Results[1].Code)
+	assert.Nil(t, resp.Results[1].Prediction)
+
+	assert.Equal(t, "v1", resp.Results[2].Prediction["version"])
+}
+
+func TestFanoutInference_RejectsInvalidTargets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewInferenceHandler(zap.NewNop(), "http://model-router", nil, "inference-jobs")
+	handler.SetMaxFanout(2)
+	router := gin.New()
+	router.POST("/v1/infer/fanout", handler.FanoutInference)
+
+	tests := map[string]string{
+		"no targets":    `{"targets":[],"input":{"data":[1.0]}}`,
+		"unnamed model": `{"targets":[{"version":"v1"}],"input":{"data":[1.0]}}`,
+		"too many":      `{"targets":[{"model":"a"},{"model":"b"},{"model":"c"}],"input":{"data":[1.0]}}`,
+		"missing input": `{"targets":[{"model":"a"}]}`,
+	}
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/infer/fanout", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "invalid_argument")
+		})
+	}
+}
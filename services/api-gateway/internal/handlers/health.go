@@ -1,20 +1,26 @@
 package handlers
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/yourusername/ai-platform/pkg/health"
 )
 
-// HealthCheck returns a health check handler
-func HealthCheck() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status": "healthy",
-			"service": "api-gateway",
-		})
-	}
+// HealthCheck returns a liveness handler
+func HealthCheck(checker *health.Checker) gin.HandlerFunc {
+	return gin.WrapH(checker.LivenessHandler())
+}
+
+// Health returns a handler reporting the gateway healthy, degraded or
+// unhealthy with the state of each dependency
+func Health(checker *health.Checker) gin.HandlerFunc {
+	return gin.WrapH(checker.HealthHandler())
+}
+
+// ReadinessCheck returns a readiness handler that checks the gateway's dependencies
+func ReadinessCheck(checker *health.Checker) gin.HandlerFunc {
+	return gin.WrapH(checker.ReadinessHandler())
 }
 
 // MetricsHandler returns a Prometheus metrics handler
@@ -1,19 +1,24 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/ai-platform/pkg/health"
 )
 
 func TestHealthCheck(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	router := gin.New()
-	router.GET("/health", HealthCheck())
+	router.GET("/health", HealthCheck(health.NewChecker("api-gateway", time.Second)))
 
 	req := httptest.NewRequest("GET", "/health", nil)
 	w := httptest.NewRecorder()
@@ -24,6 +29,44 @@ func TestHealthCheck(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "healthy")
 }
 
+func TestReadinessCheck_DependencyDown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	checker := health.NewChecker("api-gateway", time.Second)
+	checker.Add("redis", func(context.Context) error { return errors.New("connection refused") })
+
+	router := gin.New()
+	router.GET("/readyz", ReadinessCheck(checker))
+
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "redis")
+}
+
+func TestHealth_Degraded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	checker := health.NewChecker("api-gateway", time.Second)
+	checker.Add("redis", func(context.Context) error { return nil })
+	checker.AddOptional("kafka", func(context.Context) error { return errors.New("connection refused") })
+
+	router := gin.New()
+	router.GET("/health", Health(checker))
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"degraded"`)
+	assert.Contains(t, w.Body.String(), "connection refused")
+}
+
 func TestMetricsHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/IBM/sarama"
@@ -12,7 +14,24 @@ import (
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/accesslog"
+	"github.com/yourusername/ai-platform/api-gateway/internal/backpressure"
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/api-gateway/internal/observability"
+	"github.com/yourusername/ai-platform/api-gateway/internal/quota"
+	"github.com/yourusername/ai-platform/api-gateway/internal/versions"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/inferencecache"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/pricing"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/sse"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+	"github.com/yourusername/ai-platform/pkg/usage"
 )
 
 // InferenceRequest represents a real-time inference request
@@ -20,6 +39,12 @@ type InferenceRequest struct {
 	Model   string                 `json:"model" binding:"required"`
 	Version string                 `json:"version"`
 	Input   map[string]interface{} `json:"input" binding:"required"`
+	// UserID is the authenticated caller, never read from the body; the
+	// router assigns users to the variants of A/B experiments by it
+	UserID string `json:"-"`
+	// SessionID keeps a session's requests on one replica of models routed
+	// by consistent hashing, such as those caching conversation state
+	SessionID string `json:"session_id,omitempty"`
 }
 
 // BatchInferenceRequest represents a batch inference request
@@ -27,6 +52,12 @@ type BatchInferenceRequest struct {
 	Model   string                   `json:"model" binding:"required"`
 	Version string                   `json:"version"`
 	Inputs  []map[string]interface{} `json:"inputs" binding:"required"`
+	// SubjectID optionally identifies the data subject the inputs belong to,
+	// so their data can later be deleted on its own
+	SubjectID string `json:"subject_id,omitempty"`
+	// Priority is high, normal (the default) or low. Batch workers prefer
+	// higher priority jobs, without starving lower ones.
+	Priority string `json:"priority,omitempty" binding:"omitempty,oneof=high normal low"`
 }
 
 // InferenceResponse represents the inference response
@@ -36,6 +67,37 @@ type InferenceResponse struct {
 	Version    string                 `json:"version"`
 	Prediction map[string]interface{} `json:"prediction"`
 	Latency    int64                  `json:"latency_ms"`
+	// EstimatedCost is the inference's cost at the gateway's model pricing;
+	// responses served from the cache cost nothing
+	EstimatedCost float64 `json:"estimated_cost,omitempty"`
+	// Experiment and Variant name the A/B experiment the router served the
+	// request within and the variant the caller is assigned to
+	Experiment string `json:"experiment,omitempty"`
+	Variant    string `json:"variant,omitempty"`
+	// ServedByFallback flags predictions made by the fallback model of the
+	// version requested, while none of its own backends could serve
+	ServedByFallback bool `json:"served_by_fallback,omitempty"`
+}
+
+// Headers the router names the A/B experiment and variant of a response in,
+// relayed to the caller so clients can report per-variant outcomes
+const (
+	ExperimentHeader = "X-Experiment"
+	VariantHeader    = "X-Experiment-Variant"
+)
+
+// FallbackHeader is the header the router flags responses served by a
+// fallback model in
+const FallbackHeader = "X-Served-By-Fallback"
+
+// relayExperiment names the experiment and variant a response was served
+// within, if any, in its headers
+func relayExperiment(c *gin.Context, experiment, variant string) {
+	if experiment == "" {
+		return
+	}
+	c.Header(ExperimentHeader, experiment)
+	c.Header(VariantHeader, variant)
 }
 
 // BatchJobResponse represents a batch job submission response
@@ -47,14 +109,18 @@ type BatchJobResponse struct {
 
 // JobStatusResponse represents job status
 type JobStatusResponse struct {
-	JobID      string    `json:"job_id"`
-	Status     string    `json:"status"`
-	Progress   float64   `json:"progress"`
-	TotalItems int       `json:"total_items"`
-	Completed  int       `json:"completed"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	ResultURL  string    `json:"result_url,omitempty"`
+	JobID       string     `json:"job_id"`
+	Model       string     `json:"model"`
+	Version     string     `json:"version"`
+	Status      string     `json:"status"`
+	Progress    float64    `json:"progress"`
+	TotalItems  int        `json:"total_items"`
+	Completed   int        `json:"completed"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ResultURL   string     `json:"result_url,omitempty"`
+	Error       string     `json:"error,omitempty"`
 }
 
 // InferenceHandler handles inference requests
@@ -64,6 +130,21 @@ type InferenceHandler struct {
 	kafkaProducer   sarama.SyncProducer
 	kafkaTopic      string
 	httpClient      *http.Client
+	usage           *usage.Recorder
+	codec           *schema.Codec
+	backlog         *backpressure.Gate
+	capture         *inferencelog.Capture
+	maxStream       time.Duration
+	sessionLimits   SessionLimits
+	quotas          *quota.Tracker
+	maxFanout       int
+	batchWorkerURL  string
+	jobsClient      *http.Client
+	controlTopic    string
+	responses       *inferencecache.Cache
+	tenantTopics    map[string]bool
+	versions        *versions.Resolver
+	pricing         *pricing.Table
 }
 
 // NewInferenceHandler creates a new inference handler
@@ -81,9 +162,63 @@ func NewInferenceHandler(
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		maxStream:     10 * time.Minute,
+		sessionLimits: DefaultSessionLimits,
+		maxFanout:     DefaultMaxFanout,
 	}
 }
 
+// SetHTTPClient replaces the client used to call the model router, e.g. with an mTLS client
+func (h *InferenceHandler) SetHTTPClient(client *http.Client) {
+	h.httpClient = client
+}
+
+// SetUsageRecorder meters completed inferences for billing
+func (h *InferenceHandler) SetUsageRecorder(recorder *usage.Recorder) {
+	h.usage = recorder
+}
+
+// SetBacklogGate rejects batch jobs while the batch worker's backlog is over its limits
+func (h *InferenceHandler) SetBacklogGate(gate *backpressure.Gate) {
+	h.backlog = gate
+}
+
+// SetSchemaCodec frames queued jobs with their registered schema
+func (h *InferenceHandler) SetSchemaCodec(codec *schema.Codec) {
+	h.codec = codec
+}
+
+// SetCapture records sampled real-time inferences, with their responses, for replay
+func (h *InferenceHandler) SetCapture(capture *inferencelog.Capture) {
+	h.capture = capture
+}
+
+// SetTenantTopics queues the jobs of tenants on topics of their own, named by
+// schema.TenantTopic, instead of the shared ones
+func (h *InferenceHandler) SetTenantTopics(tenants []string) {
+	h.tenantTopics = make(map[string]bool, len(tenants))
+	for _, tenant := range tenants {
+		h.tenantTopics[tenant] = true
+	}
+}
+
+// SetVersionResolver resolves the version of requests naming none, which
+// are otherwise served at versions.Fallback
+func (h *InferenceHandler) SetVersionResolver(resolver *versions.Resolver) {
+	h.versions = resolver
+}
+
+// SetPricing estimates the cost of each inference, returned to the caller
+// and metered with its usage
+func (h *InferenceHandler) SetPricing(table *pricing.Table) {
+	h.pricing = table
+}
+
+// SetMaxStreamDuration bounds how long a streamed inference may run
+func (h *InferenceHandler) SetMaxStreamDuration(d time.Duration) {
+	h.maxStream = d
+}
+
 // RealTimeInference handles synchronous inference requests
 func (h *InferenceHandler) RealTimeInference(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -91,20 +226,42 @@ func (h *InferenceHandler) RealTimeInference(c *gin.Context) {
 	ctx, span := tracer.Start(ctx, "RealTimeInference")
 	defer span.End()
 
-	requestID := uuid.New().String()
-	startTime := time.Now()
+	// The logging middleware assigns the request ID; fall back for direct handler use
+	requestID := logging.RequestID(ctx)
+	if requestID == "" {
+		requestID = uuid.New().String()
+		ctx = logging.WithRequestID(ctx, requestID)
+	}
 
 	var req InferenceRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("invalid request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": err.Error()})
+	if err := bindInference(c, &req); err != nil {
+		if middleware.AbortBodyTooLarge(c, err) {
+			return
+		}
+		logging.With(ctx, h.logger).Error("invalid request", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
 		return
 	}
 
+	response, ok := h.serveInference(c, ctx, span, requestID, req)
+	if !ok {
+		return
+	}
+	if err := writeInference(c, response); err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.Wrap(err, apperrors.Internal, "failed to encode response"))
+	}
+}
+
+// serveInference answers a bound real-time request, from the response cache
+// or the model router, after charging it to the caller's quota. It writes
+// the error and returns false when the request fails.
+func (h *InferenceHandler) serveInference(c *gin.Context, ctx context.Context, span trace.Span, requestID string, req InferenceRequest) (*InferenceResponse, bool) {
 	// Set default version if not provided
 	if req.Version == "" {
-		req.Version = "v1"
+		req.Version = h.versions.Resolve(ctx, req.Model)
 	}
+	tagModel(c, req.Model, req.Version)
+	req.UserID = c.GetString("user_id")
 
 	span.SetAttributes(
 		attribute.String("model", req.Model),
@@ -112,8 +269,36 @@ func (h *InferenceHandler) RealTimeInference(c *gin.Context) {
 		attribute.String("request_id", requestID),
 	)
 
-	h.logger.Info("processing inference request",
-		zap.String("request_id", requestID),
+	tenant, limits := callerTenant(c)
+	if err := h.consumeQuota(ctx, tenant, limits, quota.Requests, 1); err != nil {
+		writeQuotaError(c, err)
+		return nil, false
+	}
+
+	response, cached := h.cachedInference(c, requestID, req)
+	if !cached {
+		var err error
+		response, err = h.infer(ctx, requestID, req)
+		if err != nil {
+			apperrors.Write(c.Writer, c.Request, err)
+			return nil, false
+		}
+		// A variant's predictions are only for the users assigned to it, and
+		// a fallback's stand in for the model only while it is down
+		if response.Experiment == "" && !response.ServedByFallback {
+			h.responses.Set(ctx, req.Model, req.Version, req.Input, response.Prediction)
+		}
+	}
+	return response, true
+}
+
+// infer forwards a request to the model router, metering and capturing it
+// whether or not it succeeds
+func (h *InferenceHandler) infer(ctx context.Context, requestID string, req InferenceRequest) (*InferenceResponse, error) {
+	logger := logging.With(ctx, h.logger)
+	startTime := time.Now()
+
+	logger.Info("processing inference request",
 		zap.String("model", req.Model),
 		zap.String("version", req.Version),
 	)
@@ -124,13 +309,22 @@ func (h *InferenceHandler) RealTimeInference(c *gin.Context) {
 		"model":      req.Model,
 		"version":    req.Version,
 		"input":      req.Input,
+		"user_id":    req.UserID,
+		"session_id": req.SessionID,
 	}
 
 	reqBody, err := json.Marshal(routerReq)
 	if err != nil {
-		h.logger.Error("failed to marshal request", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
-		return
+		logger.Error("failed to marshal request", zap.Error(err))
+		return nil, err
+	}
+
+	// The client timeout becomes the request's deadline, which is passed on
+	// to the router so nothing downstream works past it
+	if h.httpClient.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.httpClient.Timeout)
+		defer cancel()
 	}
 
 	httpReq, err := http.NewRequestWithContext(
@@ -140,55 +334,322 @@ func (h *InferenceHandler) RealTimeInference(c *gin.Context) {
 		bytes.NewBuffer(reqBody),
 	)
 	if err != nil {
-		h.logger.Error("failed to create request", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
-		return
+		logger.Error("failed to create request", zap.Error(err))
+		return nil, err
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("X-Request-ID", requestID)
+	logging.Inject(ctx, httpReq)
+
+	// Failed attempts are metered too, so chargeback models can decide whether to bill them
+	event := usage.Event{
+		Kind:       usage.KindRealtime,
+		Model:      req.Model,
+		Version:    req.Version,
+		Requests:   1,
+		InputBytes: int64(len(reqBody)),
+	}
 
 	resp, err := h.httpClient.Do(httpReq)
 	if err != nil {
-		h.logger.Error("failed to forward request", zap.Error(err))
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "service unavailable"})
-		return
+		logger.Error("failed to forward request", zap.Error(err))
+		forwardErr := apperrors.FromTransportError(err, "model-router")
+		h.recordFailure(ctx, event, req.Input, forwardErr, startTime)
+		return nil, forwardErr
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		h.logger.Error("router returned error",
+		routerErr := apperrors.FromHTTPResponse(resp, "model-router")
+		logger.Error("router returned error",
 			zap.Int("status", resp.StatusCode),
-			zap.String("body", string(body)),
+			zap.String("code", string(routerErr.Code)),
+			zap.Error(routerErr),
 		)
-		c.JSON(resp.StatusCode, gin.H{"error": "inference failed"})
-		return
+		h.recordFailure(ctx, event, req.Input, routerErr, startTime)
+		return nil, routerErr
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Error("failed to read response", zap.Error(err))
+		readErr := apperrors.FromTransportError(err, "model-router")
+		h.recordFailure(ctx, event, req.Input, readErr, startTime)
+		return nil, readErr
 	}
 
 	var routerResp map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&routerResp); err != nil {
-		h.logger.Error("failed to decode response", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
-		return
+	if err := json.Unmarshal(respBody, &routerResp); err != nil {
+		logger.Error("failed to decode response", zap.Error(err))
+		h.recordFailure(ctx, event, req.Input, err, startTime)
+		return nil, err
 	}
 
 	latency := time.Since(startTime).Milliseconds()
 
-	response := InferenceResponse{
-		RequestID:  requestID,
+	event.LatencyMs = latency
+	event.OutputBytes = int64(len(respBody))
+	event.EstimatedCost = h.pricing.Estimate(req.Model, latency, event.InputBytes)
+	h.usage.Record(ctx, event)
+	recordInference(ctx, event, "success", startTime)
+	h.capture.Record(ctx, inferencelog.Record{
+		Model:     req.Model,
+		Version:   req.Version,
+		Input:     req.Input,
+		Output:    routerResp,
+		LatencyMs: latency,
+	})
+
+	logger.Info("inference completed",
+		zap.Int64("latency_ms", latency),
+	)
+
+	return &InferenceResponse{
+		RequestID:     requestID,
+		Model:         req.Model,
+		Version:       req.Version,
+		Prediction:    routerResp,
+		Latency:       latency,
+		EstimatedCost: event.EstimatedCost,
+		Experiment:    resp.Header.Get(ExperimentHeader),
+		Variant:       resp.Header.Get(VariantHeader),
+
+		ServedByFallback: resp.Header.Get(FallbackHeader) == "true",
+	}, nil
+}
+
+// StreamDone is the final event of a successful streamed inference
+type StreamDone struct {
+	RequestID string `json:"request_id"`
+	Model     string `json:"model"`
+	Version   string `json:"version"`
+	Chunks    int    `json:"chunks"`
+	Latency   int64  `json:"latency_ms"`
+	// EstimatedCost is the inference's cost at the gateway's model pricing
+	EstimatedCost float64 `json:"estimated_cost,omitempty"`
+}
+
+// StreamInference handles inference requests whose output is streamed back as
+// Server-Sent Events: a partial event per output chunk as the backend produces
+// it, then a done event or an error event. Failures before the stream starts
+// are returned as regular error responses.
+func (h *InferenceHandler) StreamInference(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("api-gateway")
+	ctx, span := tracer.Start(ctx, "StreamInference")
+	defer span.End()
+
+	requestID := logging.RequestID(ctx)
+	if requestID == "" {
+		requestID = uuid.New().String()
+		ctx = logging.WithRequestID(ctx, requestID)
+	}
+	logger := logging.With(ctx, h.logger)
+	startTime := time.Now()
+
+	var req InferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if middleware.AbortBodyTooLarge(c, err) {
+			return
+		}
+		logger.Error("invalid request", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+
+	if req.Version == "" {
+		req.Version = h.versions.Resolve(ctx, req.Model)
+	}
+	tagModel(c, req.Model, req.Version)
+
+	span.SetAttributes(
+		attribute.String("model", req.Model),
+		attribute.String("version", req.Version),
+		attribute.String("request_id", requestID),
+	)
+
+	tenant, limits := callerTenant(c)
+	if err := h.consumeQuota(ctx, tenant, limits, quota.Requests, 1); err != nil {
+		writeQuotaError(c, err)
+		return
+	}
+
+	logger.Info("processing streamed inference request",
+		zap.String("model", req.Model),
+		zap.String("version", req.Version),
+	)
+
+	// Streams outlive the server's write timeout; they are bounded by their own
+	ctx, cancel := context.WithTimeout(ctx, h.maxStream)
+	defer cancel()
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(h.maxStream))
+
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"request_id": requestID,
+		"model":      req.Model,
+		"version":    req.Version,
+		"input":      req.Input,
+		"user_id":    c.GetString("user_id"),
+		"session_id": req.SessionID,
+	})
+	if err != nil {
+		logger.Error("failed to marshal request", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", h.routerURL+"/v1/route/stream", bytes.NewBuffer(reqBody))
+	if err != nil {
+		logger.Error("failed to create request", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", sse.ContentType)
+	logging.Inject(ctx, httpReq)
+
+	event := usage.Event{
+		Kind:       usage.KindRealtime,
 		Model:      req.Model,
 		Version:    req.Version,
-		Prediction: routerResp,
-		Latency:    latency,
+		Requests:   1,
+		InputBytes: int64(len(reqBody)),
 	}
 
-	h.logger.Info("inference completed",
-		zap.String("request_id", requestID),
-		zap.Int64("latency_ms", latency),
-	)
+	streamClient := *h.httpClient
+	streamClient.Timeout = 0
+	resp, err := streamClient.Do(httpReq)
+	if err != nil {
+		logger.Error("failed to forward request", zap.Error(err))
+		forwardErr := apperrors.FromTransportError(err, "model-router")
+		h.meterStream(ctx, event, forwardErr, startTime)
+		apperrors.Write(c.Writer, c.Request, forwardErr)
+		return
+	}
+	defer resp.Body.Close()
 
-	c.JSON(http.StatusOK, response)
+	if resp.StatusCode != http.StatusOK {
+		routerErr := apperrors.FromHTTPResponse(resp, "model-router")
+		logger.Error("router returned error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("code", string(routerErr.Code)),
+			zap.Error(routerErr),
+		)
+		h.meterStream(ctx, event, routerErr, startTime)
+		apperrors.Write(c.Writer, c.Request, routerErr)
+		return
+	}
+
+	relayExperiment(c, resp.Header.Get(ExperimentHeader), resp.Header.Get(VariantHeader))
+	stream, err := sse.NewWriter(c.Writer)
+	if err != nil {
+		streamErr := apperrors.Wrap(err, apperrors.Internal, "streaming unsupported")
+		h.meterStream(ctx, event, streamErr, startTime)
+		apperrors.Write(c.Writer, c.Request, streamErr)
+		return
+	}
+
+	chunks := 0
+	reader := sse.NewReader(resp.Body)
+	for {
+		streamed, err := reader.Next()
+		if err != nil {
+			// The caller went away, the stream ran too long or the router dropped it
+			streamErr := apperrors.FromTransportError(err, "model-router")
+			if c.Request.Context().Err() == nil {
+				logger.Error("stream interrupted", zap.Error(err))
+				_, failure := apperrors.ToHTTP(streamErr)
+				stream.Send(sse.EventError, failure)
+			}
+			h.meterStream(ctx, event, streamErr, startTime)
+			return
+		}
+
+		switch streamed.Name {
+		case sse.EventPartial:
+			chunks++
+			event.OutputBytes += int64(len(streamed.Data))
+			if err := stream.Write(streamed); err != nil {
+				logger.Warn("caller went away during stream", zap.Error(err))
+				h.meterStream(ctx, event, apperrors.New(apperrors.Canceled, "request canceled"), startTime)
+				return
+			}
+		case sse.EventError:
+			var failure apperrors.Response
+			if err := json.Unmarshal(streamed.Data, &failure); err != nil || failure.Code == "" {
+				failure = apperrors.Response{Error: "internal error", Code: apperrors.Internal}
+			}
+			logger.Error("streamed inference failed",
+				zap.String("code", string(failure.Code)),
+				zap.String("error", failure.Error),
+			)
+			stream.Send(sse.EventError, failure)
+			h.meterStream(ctx, event, apperrors.New(failure.Code, failure.Error), startTime)
+			return
+		case sse.EventDone:
+			latency := time.Since(startTime).Milliseconds()
+			event.EstimatedCost = h.pricing.Estimate(req.Model, latency, event.InputBytes)
+			stream.Send(sse.EventDone, StreamDone{
+				RequestID:     requestID,
+				Model:         req.Model,
+				Version:       req.Version,
+				Chunks:        chunks,
+				Latency:       latency,
+				EstimatedCost: event.EstimatedCost,
+			})
+			h.meterStream(ctx, event, nil, startTime)
+			logger.Info("streamed inference completed",
+				zap.Int("chunks", chunks),
+				zap.Int64("latency_ms", latency),
+			)
+			return
+		}
+	}
+}
+
+// meterStream records a streamed inference's usage and metrics. Streams are
+// not captured for replay, which compares buffered responses.
+func (h *InferenceHandler) meterStream(ctx context.Context, event usage.Event, err error, startTime time.Time) {
+	status := "success"
+	if err != nil {
+		event.Errors = 1
+		status = "error"
+	}
+	event.LatencyMs = time.Since(startTime).Milliseconds()
+	h.usage.Record(ctx, event)
+	recordInference(ctx, event, status, startTime)
+}
+
+func (h *InferenceHandler) recordFailure(ctx context.Context, event usage.Event, input map[string]interface{}, err error, startTime time.Time) {
+	event.Errors = 1
+	event.LatencyMs = time.Since(startTime).Milliseconds()
+	h.usage.Record(ctx, event)
+	recordInference(ctx, event, "error", startTime)
+	// Failures are captured with the code the caller received, so a replay
+	// can tell whether the same request still fails the same way
+	h.capture.Record(ctx, inferencelog.Record{
+		Model:     event.Model,
+		Version:   event.Version,
+		Input:     input,
+		LatencyMs: event.LatencyMs,
+		Error:     string(apperrors.CodeOf(err)),
+	})
+}
+
+// recordInference counts a forwarded inference request and its latency, the
+// measurements SLOs are evaluated from
+func recordInference(ctx context.Context, event usage.Event, status string, startTime time.Time) {
+	observability.InferenceRequestsTotal.WithLabelValues(event.Model, event.Version, string(event.Kind), status, metricTenant(ctx)).Inc()
+	observability.InferenceRequestDuration.WithLabelValues(event.Model, event.Version, string(event.Kind)).Observe(time.Since(startTime).Seconds())
+}
+
+// metricTenant is the tenant a request's metrics are labelled with; requests
+// without one are the default tenant's, as in usage events
+func metricTenant(ctx context.Context) string {
+	if tenant := logging.FieldsFromContext(ctx).Tenant; tenant != "" {
+		return tenant
+	}
+	return usage.DefaultTenant
 }
 
 // BatchInference handles batch inference job submission
@@ -200,17 +661,43 @@ func (h *InferenceHandler) BatchInference(c *gin.Context) {
 
 	var req BatchInferenceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("invalid request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": err.Error()})
+		if middleware.AbortBodyTooLarge(c, err) {
+			return
+		}
+		logging.With(ctx, h.logger).Error("invalid request", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+
+	// Tenants may cap how many inputs a single job carries
+	if limits, ok := c.Get("tenant_limits"); ok {
+		if max := limits.(tenancy.Limits).MaxBatchItems; max > 0 && len(req.Inputs) > max {
+			apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.InvalidArgument,
+				"batch has %d inputs; the tenant limit is %d", len(req.Inputs), max))
+			return
+		}
+	}
+
+	// The backlog is checked before the quota so rejected jobs are not counted
+	if !h.admitJob(ctx, c) {
+		return
+	}
+
+	tenant, limits := callerTenant(c)
+	if err := h.consumeQuota(ctx, tenant, limits, quota.BatchItems, int64(len(req.Inputs))); err != nil {
+		writeQuotaError(c, err)
 		return
 	}
 
 	// Set default version if not provided
 	if req.Version == "" {
-		req.Version = "v1"
+		req.Version = h.versions.Resolve(ctx, req.Model)
 	}
+	tagModel(c, req.Model, req.Version)
 
 	jobID := uuid.New().String()
+	ctx = logging.WithJobID(ctx, jobID)
+	logger := logging.With(ctx, h.logger)
 
 	span.SetAttributes(
 		attribute.String("model", req.Model),
@@ -219,74 +706,114 @@ func (h *InferenceHandler) BatchInference(c *gin.Context) {
 		attribute.Int("input_count", len(req.Inputs)),
 	)
 
-	h.logger.Info("submitting batch job",
-		zap.String("job_id", jobID),
+	logger.Info("submitting batch job",
 		zap.String("model", req.Model),
 		zap.Int("input_count", len(req.Inputs)),
 	)
 
 	// Create job message
-	job := map[string]interface{}{
-		"job_id":     jobID,
-		"model":      req.Model,
-		"version":    req.Version,
-		"inputs":     req.Inputs,
-		"created_at": time.Now().UTC(),
+	job := schema.BatchJob{
+		JobID:     jobID,
+		Model:     req.Model,
+		Version:   req.Version,
+		Inputs:    req.Inputs,
+		Tenant:    tenant,
+		SubjectID: req.SubjectID,
+		UserID:    c.GetString("user_id"),
+		Priority:  req.Priority,
+		CreatedAt: time.Now().UTC(),
 	}
 
-	jobBytes, err := json.Marshal(job)
-	if err != nil {
-		h.logger.Error("failed to marshal job", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	if err := h.enqueue(ctx, job); err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
 		return
 	}
 
-	// Send to Kafka
-	msg := &sarama.ProducerMessage{
-		Topic: h.kafkaTopic,
-		Key:   sarama.StringEncoder(jobID),
-		Value: sarama.ByteEncoder(jobBytes),
+	response := BatchJobResponse{
+		JobID:     jobID,
+		Status:    "pending",
+		CreatedAt: time.Now().UTC(),
 	}
 
-	partition, offset, err := h.kafkaProducer.SendMessage(msg)
-	if err != nil {
-		h.logger.Error("failed to send message to kafka", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to submit job"})
-		return
+	c.JSON(http.StatusAccepted, response)
+}
+
+// tagModel records the model a request asked for in its access log event
+func tagModel(c *gin.Context, model, version string) {
+	c.Set(accesslog.ModelKey, model)
+	c.Set(accesslog.VersionKey, version)
+}
+
+// admitJob pushes back rather than queue jobs that would wait too long to
+// start, answering the request when the job is turned away
+func (h *InferenceHandler) admitJob(ctx context.Context, c *gin.Context) bool {
+	decision := h.backlog.Admit(time.Now())
+	if decision.Admitted {
+		return true
 	}
 
-	h.logger.Info("batch job submitted",
-		zap.String("job_id", jobID),
-		zap.Int32("partition", partition),
-		zap.Int64("offset", offset),
+	retryAfter := int(decision.RetryAfter.Seconds())
+	logging.With(ctx, h.logger).Warn("rejecting batch job: backlog over limit",
+		zap.Int64("depth", decision.Backlog.Depth()),
+		zap.Int("retry_after", retryAfter),
 	)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	status, body := apperrors.Problem(ctx, apperrors.New(apperrors.ResourceExhausted, "batch backlog is full"))
+	apperrors.WriteBody(c.Writer, status, body.With(map[string]interface{}{
+		"retry_after": retryAfter,
+		"backlog":     decision.Backlog,
+	}))
+	return false
+}
 
-	response := BatchJobResponse{
-		JobID:     jobID,
-		Status:    "pending",
-		CreatedAt: time.Now().UTC(),
+// enqueue queues a job for the batch worker on its priority's topic, or its
+// tenant's when the tenant has topics of its own, carrying the correlation
+// fields as record headers
+func (h *InferenceHandler) enqueue(ctx context.Context, job schema.BatchJob) error {
+	base := h.kafkaTopic
+	if job.Tenant != "" && h.tenantTopics[job.Tenant] {
+		base = schema.TenantTopic(base, job.Tenant)
+	}
+	topic := schema.PriorityTopic(base, job.Priority)
+	partition, offset, err := h.publish(ctx, topic, schema.BatchJobs, job.JobID, job)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.Unavailable, "failed to submit job")
 	}
+	observability.BatchJobsSubmitted.WithLabelValues(job.Model, job.Version, metricTenant(ctx)).Inc()
 
-	c.JSON(http.StatusAccepted, response)
+	logging.With(ctx, h.logger).Info("batch job submitted",
+		zap.String("topic", topic),
+		zap.Int32("partition", partition),
+		zap.Int64("offset", offset),
+	)
+	return nil
 }
 
-// GetJobStatus retrieves the status of a batch job
-func (h *InferenceHandler) GetJobStatus(c *gin.Context) {
-	jobID := c.Param("id")
+// publish encodes v as a message of contract and sends it to topic with the
+// request's correlation fields as record headers. Failures to send are
+// returned as they are, for the caller to describe.
+func (h *InferenceHandler) publish(ctx context.Context, topic string, contract *schema.Contract, key string, v interface{}) (int32, int64, error) {
+	logger := logging.With(ctx, h.logger)
 
-	h.logger.Info("retrieving job status", zap.String("job_id", jobID))
+	value, err := h.codec.Encode(ctx, contract, v)
+	if err != nil {
+		logger.Error("failed to encode message", zap.String("subject", contract.Subject), zap.Error(err))
+		return 0, 0, apperrors.Ensure(err, apperrors.Internal, "failed to encode message")
+	}
 
-	// TODO: Query metadata service or database for job status
-	// For now, return a mock response
-	response := JobStatusResponse{
-		JobID:      jobID,
-		Status:     "processing",
-		Progress:   0.45,
-		TotalItems: 100,
-		Completed:  45,
-		CreatedAt:  time.Now().Add(-5 * time.Minute).UTC(),
-		UpdatedAt:  time.Now().UTC(),
+	msg := &sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.StringEncoder(key),
+		Value: sarama.ByteEncoder(value),
+	}
+	for key, value := range logging.Headers(ctx) {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
 	}
 
-	c.JSON(http.StatusOK, response)
+	partition, offset, err := h.kafkaProducer.SendMessage(msg)
+	if err != nil {
+		logger.Error("failed to send message to kafka", zap.String("topic", topic), zap.Error(err))
+		return 0, 0, err
+	}
+	return partition, offset, nil
 }
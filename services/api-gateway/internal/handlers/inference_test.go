@@ -0,0 +1,615 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/backpressure"
+	"github.com/yourusername/ai-platform/api-gateway/internal/versions"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/backlog"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/pricing"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/servicetest"
+	"github.com/yourusername/ai-platform/pkg/sse"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+	"github.com/yourusername/ai-platform/pkg/usage"
+)
+
+func TestRealTimeInference_TranslatesRouterErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	tests := []struct {
+		name       string
+		routerErr  error
+		wantStatus int
+	}{
+		{"unknown model", apperrors.New(apperrors.NotFound, "model not found: resnet99"), http.StatusNotFound},
+		{"backend down", apperrors.New(apperrors.Unavailable, "backend for resnet18/v1 is unavailable"), http.StatusServiceUnavailable},
+		{"backend timeout", apperrors.New(apperrors.DeadlineExceeded, "inference-orchestrator timed out"), http.StatusGatewayTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				apperrors.WriteHTTP(w, tt.routerErr)
+			}))
+			defer server.Close()
+
+			handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+			router := gin.New()
+			router.POST("/v1/infer", handler.RealTimeInference)
+
+			body := bytes.NewBufferString(`{"model":"resnet18","input":{"data":[1.0]}}`)
+			req := httptest.NewRequest("POST", "/v1/infer", body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), string(apperrors.CodeOf(tt.routerErr)))
+		})
+	}
+}
+
+func TestRealTimeInference_RouterUnreachable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	routerURL := servicetest.Down(t).URL
+
+	handler := NewInferenceHandler(logger, routerURL, nil, "inference-jobs")
+	router := gin.New()
+	router.POST("/v1/infer", handler.RealTimeInference)
+
+	body := bytes.NewBufferString(`{"model":"resnet18","input":{"data":[1.0]}}`)
+	req := httptest.NewRequest("POST", "/v1/infer", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "unavailable")
+}
+
+func TestRealTimeInference_PropagatesRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	var forwarded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get(logging.HeaderRequestID)
+		w.Write([]byte(`{"prediction":[1]}`))
+	}))
+	defer server.Close()
+
+	handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+	router := gin.New()
+	router.POST("/v1/infer", handler.RealTimeInference)
+
+	body := bytes.NewBufferString(`{"model":"resnet18","input":{"data":[1.0]}}`)
+	req := httptest.NewRequest("POST", "/v1/infer", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(logging.HeaderRequestID, "req-1")
+	w := httptest.NewRecorder()
+
+	logging.Middleware(router).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "req-1", forwarded)
+	assert.Contains(t, w.Body.String(), `"request_id":"req-1"`)
+}
+
+func TestRealTimeInference_RelaysExperimentVariant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	var forwarded map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&forwarded)
+		w.Header().Set(ExperimentHeader, "resnet18-v2")
+		w.Header().Set(VariantHeader, "treatment")
+		w.Write([]byte(`{"prediction":[1]}`))
+	}))
+	defer server.Close()
+
+	handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+	router := gin.New()
+	router.POST("/v1/infer", func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		handler.RealTimeInference(c)
+	})
+
+	body := bytes.NewBufferString(`{"model":"resnet18","version":"v1","input":{"data":[1.0]},"user_id":"spoofed","session_id":"chat-7"}`)
+	req := httptest.NewRequest("POST", "/v1/infer", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "user-1", forwarded["user_id"], "the authenticated user is forwarded, not the body's")
+	assert.Equal(t, "chat-7", forwarded["session_id"])
+	assert.Equal(t, "treatment", w.Header().Get(VariantHeader))
+	assert.Contains(t, w.Body.String(), `"experiment":"resnet18-v2","variant":"treatment"`)
+}
+
+func TestRealTimeInference_RelaysFallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(FallbackHeader, "true")
+		w.Write([]byte(`{"prediction":[1],"served_by_fallback":true}`))
+	}))
+	defer server.Close()
+
+	handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+	router := gin.New()
+	router.POST("/v1/infer", handler.RealTimeInference)
+
+	req := httptest.NewRequest("POST", "/v1/infer", bytes.NewBufferString(`{"model":"resnet18","input":{"data":[1.0]}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response InferenceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.ServedByFallback)
+}
+
+func TestRealTimeInference_RecordsUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"prediction":[1]}`))
+	}))
+	defer server.Close()
+
+	var events []usage.Event
+	recorder := usage.NewRecorder("api-gateway", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event usage.Event
+		json.Unmarshal(value, &event)
+		events = append(events, event)
+		return nil
+	}), 10, logger)
+
+	// Only a per-request rate, so the estimate does not depend on latency
+	table, err := pricing.Parse(`{"resnet18":{"per_request":0.002}}`)
+	require.NoError(t, err)
+
+	handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+	handler.SetUsageRecorder(recorder)
+	handler.SetPricing(table)
+	router := gin.New()
+	router.POST("/v1/infer", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(logging.WithTenant(c.Request.Context(), "acme"))
+		handler.RealTimeInference(c)
+	})
+
+	body := bytes.NewBufferString(`{"model":"resnet18","input":{"data":[1.0]}}`)
+	req := httptest.NewRequest("POST", "/v1/infer", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response InferenceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 0.002, response.EstimatedCost)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "acme", events[0].Tenant)
+		assert.Equal(t, usage.KindRealtime, events[0].Kind)
+		assert.Equal(t, "v1", events[0].Version)
+		assert.Equal(t, int64(1), events[0].Requests)
+		assert.Zero(t, events[0].Errors)
+		assert.Equal(t, int64(len(`{"prediction":[1]}`)), events[0].OutputBytes)
+		assert.Equal(t, 0.002, events[0].EstimatedCost)
+	}
+}
+
+func TestRealTimeInference_CapturesResponsesAndFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			apperrors.WriteHTTP(w, apperrors.New(apperrors.Unavailable, "backend for resnet18/v1 is unavailable"))
+			return
+		}
+		w.Write([]byte(`{"class":"cat","score":0.9}`))
+	}))
+	defer server.Close()
+
+	var records []inferencelog.Record
+	cfg := inferencelog.Config{Enabled: true, SampleRate: 1, MaxPayloadBytes: 1 << 10}
+	capture := inferencelog.NewCapture(cfg, "api-gateway", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var record inferencelog.Record
+		json.Unmarshal(value, &record)
+		records = append(records, record)
+		return nil
+	}), 10, zap.NewNop())
+
+	handler := NewInferenceHandler(zap.NewNop(), server.URL, nil, "inference-jobs")
+	handler.SetCapture(capture)
+	router := gin.New()
+	router.POST("/v1/infer", handler.RealTimeInference)
+
+	for _, failing := range []bool{false, true} {
+		fail = failing
+		body := bytes.NewBufferString(`{"model":"resnet18","input":{"data":[1.0]}}`)
+		req := httptest.NewRequest("POST", "/v1/infer", body)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	capture.Run(ctx)
+
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "resnet18", records[0].Model)
+		assert.Equal(t, "v1", records[0].Version)
+		assert.Equal(t, []interface{}{1.0}, records[0].Input["data"])
+		assert.Equal(t, "cat", records[0].Output["class"])
+		assert.Empty(t, records[0].Error)
+
+		assert.Equal(t, string(apperrors.Unavailable), records[1].Error)
+		assert.Nil(t, records[1].Output)
+		assert.Equal(t, []interface{}{1.0}, records[1].Input["data"])
+	}
+}
+
+func TestBatchInference_RejectsWhenBacklogFull(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	gate := backpressure.NewGate("http://batch-worker", http.DefaultClient, backpressure.Limits{Depth: 100}, time.Minute, zap.NewNop())
+	gate.Update(backlog.New(150, 10, 2, time.Now()), time.Now())
+
+	// A nil producer would panic if the job were queued
+	handler := NewInferenceHandler(zap.NewNop(), "http://model-router", nil, "inference-jobs")
+	handler.SetBacklogGate(gate)
+	router := gin.New()
+	router.POST("/v1/batch", handler.BatchInference)
+
+	body := bytes.NewBufferString(`{"model":"resnet18","inputs":[{"data":[1.0]}]}`)
+	req := httptest.NewRequest("POST", "/v1/batch", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	var resp struct {
+		Code       apperrors.Code  `json:"code"`
+		RetryAfter int             `json:"retry_after"`
+		Backlog    backlog.Backlog `json:"backlog"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, apperrors.ResourceExhausted, resp.Code)
+	assert.Equal(t, 30, resp.RetryAfter)
+	assert.Equal(t, int64(160), resp.Backlog.Depth())
+}
+
+func TestBatchInference_PublishesToPriorityTopic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := map[string]struct {
+		priority  string
+		wantTopic string
+	}{
+		"default": {"", "inference-jobs"},
+		"high":    {"high", "inference-jobs-high"},
+		"low":     {"low", "inference-jobs-low"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var job schema.BatchJob
+			producer := mocks.NewSyncProducer(t, nil)
+			producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+				assert.Equal(t, tt.wantTopic, msg.Topic)
+				value, err := msg.Value.Encode()
+				require.NoError(t, err)
+				return json.Unmarshal(value, &job)
+			})
+			defer producer.Close()
+
+			handler := NewInferenceHandler(zap.NewNop(), "http://model-router", producer, "inference-jobs")
+			router := gin.New()
+			router.POST("/v1/batch", handler.BatchInference)
+
+			body := bytes.NewBufferString(`{"model":"resnet18","inputs":[{"data":[1.0]}],"priority":"` + tt.priority + `"}`)
+			req := httptest.NewRequest("POST", "/v1/batch", body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusAccepted, w.Code)
+			assert.Equal(t, tt.priority, job.Priority)
+		})
+	}
+
+	handler := NewInferenceHandler(zap.NewNop(), "http://model-router", nil, "inference-jobs")
+	router := gin.New()
+	router.POST("/v1/batch", handler.BatchInference)
+	req := httptest.NewRequest("POST", "/v1/batch", bytes.NewBufferString(`{"model":"resnet18","inputs":[{"data":[1.0]}],"priority":"urgent"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBatchInference_PublishesToTenantTopic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := map[string]struct {
+		tenant    string
+		priority  string
+		wantTopic string
+	}{
+		"own topics":    {"acme", "", "inference-jobs-tenant-acme"},
+		"own priority":  {"acme", "high", "inference-jobs-tenant-acme-high"},
+		"shared topics": {"globex", "", "inference-jobs"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var job schema.BatchJob
+			producer := mocks.NewSyncProducer(t, nil)
+			producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+				assert.Equal(t, tt.wantTopic, msg.Topic)
+				value, err := msg.Value.Encode()
+				require.NoError(t, err)
+				return json.Unmarshal(value, &job)
+			})
+			defer producer.Close()
+
+			handler := NewInferenceHandler(zap.NewNop(), "http://model-router", producer, "inference-jobs")
+			handler.SetTenantTopics([]string{"acme"})
+			router := gin.New()
+			router.POST("/v1/batch", func(c *gin.Context) {
+				c.Set("tenant", tt.tenant)
+				handler.BatchInference(c)
+			})
+
+			body := bytes.NewBufferString(`{"model":"resnet18","inputs":[{"data":[1.0]}],"priority":"` + tt.priority + `"}`)
+			req := httptest.NewRequest("POST", "/v1/batch", body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusAccepted, w.Code)
+			assert.Equal(t, tt.tenant, job.Tenant)
+		})
+	}
+}
+
+type latestVersions map[string]string
+
+func (v latestVersions) Latest(ctx context.Context, model string) (string, error) {
+	return v[model], nil
+}
+
+func TestInference_ResolvesDefaultVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var routed string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Version string `json:"version"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		routed = body.Version
+		w.Write([]byte(`{"prediction":[1]}`))
+	}))
+	defer server.Close()
+
+	var job schema.BatchJob
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		value, err := msg.Value.Encode()
+		require.NoError(t, err)
+		return json.Unmarshal(value, &job)
+	})
+	defer producer.Close()
+
+	handler := NewInferenceHandler(zap.NewNop(), server.URL, producer, "inference-jobs")
+	handler.SetVersionResolver(versions.NewResolver(latestVersions{"resnet18": "v3"}, nil, time.Minute, zap.NewNop()))
+	router := gin.New()
+	router.POST("/v1/infer", handler.RealTimeInference)
+	router.POST("/v1/batch", handler.BatchInference)
+
+	for path, body := range map[string]string{
+		"/v1/infer": `{"model":"resnet18","input":{"data":[1.0]}}`,
+		"/v1/batch": `{"model":"resnet18","inputs":[{"data":[1.0]}]}`,
+	} {
+		req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Less(t, w.Code, 300, path)
+	}
+
+	assert.Equal(t, "v3", routed)
+	assert.Equal(t, "v3", job.Version, "batch jobs get the same version as real-time requests")
+}
+
+func TestBatchInference_EnforcesTenantBatchLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// A nil producer would panic if the job were queued
+	handler := NewInferenceHandler(zap.NewNop(), "http://model-router", nil, "inference-jobs")
+	router := gin.New()
+	router.POST("/v1/batch", func(c *gin.Context) {
+		c.Set("tenant_limits", tenancy.Limits{MaxBatchItems: 2})
+		handler.BatchInference(c)
+	})
+
+	body := bytes.NewBufferString(`{"model":"resnet18","inputs":[{"data":[1.0]},{"data":[2.0]},{"data":[3.0]}]}`)
+	req := httptest.NewRequest("POST", "/v1/batch", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the tenant limit is 2")
+}
+
+func streamingRouter(t *testing.T, events string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/route/stream", r.URL.Path)
+		w.Header().Set("Content-Type", sse.ContentType)
+		w.Write([]byte(events))
+	}))
+}
+
+func streamEvents(t *testing.T, body *bytes.Buffer) []sse.Event {
+	var events []sse.Event
+	reader := sse.NewReader(body)
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return events
+		}
+		if !assert.NoError(t, err) {
+			return events
+		}
+		events = append(events, event)
+	}
+}
+
+func TestStreamInference_RelaysChunks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	server := streamingRouter(t, "event: partial\ndata: {\"text_output\":\"Hel\"}\n\n"+
+		"event: partial\ndata: {\"text_output\":\"lo\"}\n\n"+
+		"event: done\ndata: {\"chunks\":2}\n\n")
+	defer server.Close()
+
+	var events []usage.Event
+	recorder := usage.NewRecorder("api-gateway", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event usage.Event
+		json.Unmarshal(value, &event)
+		events = append(events, event)
+		return nil
+	}), 10, logger)
+
+	handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+	handler.SetUsageRecorder(recorder)
+	router := gin.New()
+	router.POST("/v1/infer/stream", handler.StreamInference)
+
+	req := httptest.NewRequest("POST", "/v1/infer/stream", bytes.NewBufferString(`{"model":"llama","input":{"text_input":"Hi"}}`))
+	req.Header.Set(logging.HeaderRequestID, "req-1")
+	w := httptest.NewRecorder()
+	logging.Middleware(router).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, sse.ContentType, w.Header().Get("Content-Type"))
+
+	streamed := streamEvents(t, w.Body)
+	if assert.Len(t, streamed, 3) {
+		assert.Equal(t, sse.Event{Name: sse.EventPartial, Data: []byte(`{"text_output":"Hel"}`)}, streamed[0])
+		assert.Equal(t, sse.EventDone, streamed[2].Name)
+
+		var done StreamDone
+		assert.NoError(t, json.Unmarshal(streamed[2].Data, &done))
+		assert.Equal(t, "req-1", done.RequestID)
+		assert.Equal(t, "v1", done.Version)
+		assert.Equal(t, 2, done.Chunks)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+	if assert.Len(t, events, 1) {
+		assert.Zero(t, events[0].Errors)
+		assert.Equal(t, int64(len(`{"text_output":"Hel"}`)+len(`{"text_output":"lo"}`)), events[0].OutputBytes)
+	}
+}
+
+func TestStreamInference_FailsBeforeStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apperrors.WriteHTTP(w, apperrors.New(apperrors.NotFound, "model not found: llama"))
+	}))
+	defer server.Close()
+
+	handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+	router := gin.New()
+	router.POST("/v1/infer/stream", handler.StreamInference)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer/stream", bytes.NewBufferString(`{"model":"llama","input":{}}`)))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), string(apperrors.NotFound))
+}
+
+func TestStreamInference_StreamInterrupted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	server := streamingRouter(t, "event: partial\ndata: {\"text_output\":\"Hel\"}\n\n")
+	defer server.Close()
+
+	var events []usage.Event
+	recorder := usage.NewRecorder("api-gateway", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event usage.Event
+		json.Unmarshal(value, &event)
+		events = append(events, event)
+		return nil
+	}), 10, logger)
+
+	handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+	handler.SetUsageRecorder(recorder)
+	router := gin.New()
+	router.POST("/v1/infer/stream", handler.StreamInference)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer/stream", bytes.NewBufferString(`{"model":"llama","input":{}}`)))
+
+	streamed := streamEvents(t, w.Body)
+	if assert.Len(t, streamed, 2) {
+		assert.Equal(t, sse.EventError, streamed[1].Name)
+		assert.Contains(t, string(streamed[1].Data), string(apperrors.Unavailable))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, int64(1), events[0].Errors)
+	}
+}
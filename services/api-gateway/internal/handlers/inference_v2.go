@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/apiv2"
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// RealTimeInferenceV2 handles POST /v2/infer. The typed request is checked
+// and adapted to a /v1 request, served the same way, and its prediction
+// returned as typed outputs.
+func (h *InferenceHandler) RealTimeInferenceV2(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("api-gateway")
+	ctx, span := tracer.Start(ctx, "RealTimeInferenceV2")
+	defer span.End()
+
+	requestID := logging.RequestID(ctx)
+	if requestID == "" {
+		requestID = uuid.New().String()
+		ctx = logging.WithRequestID(ctx, requestID)
+	}
+
+	req, err := apiv2.Decode(c.Request.Body)
+	var input map[string]interface{}
+	if err == nil {
+		input, err = req.Input()
+	}
+	if err != nil {
+		if middleware.AbortBodyTooLarge(c, err) {
+			return
+		}
+		logging.With(ctx, h.logger).Error("invalid request", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+
+	response, ok := h.serveInference(c, ctx, span, requestID, InferenceRequest{
+		Model:   req.Model,
+		Version: req.Version,
+		Input:   input,
+	})
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, apiv2.InferResponse{
+		RequestID:     response.RequestID,
+		Model:         response.Model,
+		Version:       response.Version,
+		Outputs:       apiv2.Outputs(response.Prediction),
+		LatencyMs:     response.Latency,
+		EstimatedCost: response.EstimatedCost,
+	})
+}
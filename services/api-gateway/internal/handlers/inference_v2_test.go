@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/apiv2"
+)
+
+func TestRealTimeInferenceV2_AdaptsToV1(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	var forwarded map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&forwarded)
+		w.Write([]byte(`{"scores":[[0.25,0.75]],"label":"cat"}`))
+	}))
+	defer server.Close()
+
+	handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+	router := gin.New()
+	router.POST("/v2/infer", handler.RealTimeInferenceV2)
+
+	body := bytes.NewBufferString(`{"model":"resnet18","inputs":[{"name":"data","datatype":"FP32","shape":[2,2],"data":[1,2,3,4]}]}`)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v2/infer", body))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, "v1", forwarded["version"])
+	assert.Equal(t, map[string]interface{}{
+		"data": []interface{}{[]interface{}{1.0, 2.0}, []interface{}{3.0, 4.0}},
+	}, forwarded["input"])
+
+	var response apiv2.InferResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "resnet18", response.Model)
+	require.Len(t, response.Outputs, 2)
+	assert.Equal(t, "label", response.Outputs[0].Name)
+	assert.Equal(t, apiv2.Bytes, response.Outputs[0].Datatype)
+	assert.Equal(t, apiv2.FP64, response.Outputs[1].Datatype)
+	assert.Equal(t, []int64{1, 2}, response.Outputs[1].Shape)
+	assert.Equal(t, []interface{}{0.25, 0.75}, response.Outputs[1].Data)
+}
+
+func TestRealTimeInferenceV2_RejectsUntypedInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	handler := NewInferenceHandler(logger, "http://router", nil, "inference-jobs")
+	router := gin.New()
+	router.POST("/v2/infer", handler.RealTimeInferenceV2)
+
+	for _, body := range []string{
+		`{"model":"resnet18","input":{"data":[1.0]}}`,
+		`{"model":"resnet18","inputs":[{"name":"data","datatype":"INT8","shape":[1],"data":[1.5]}]}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/v2/infer", bytes.NewBufferString(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+// jobsPath is where the batch worker serves batch jobs
+const jobsPath = "/v1/jobs"
+
+// JobListResponse is a page of batch jobs
+type JobListResponse struct {
+	Jobs []JobStatusResponse `json:"jobs"`
+	// NextCursor fetches the next page; it is omitted on the last one
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// batchJob is a job's state as the batch worker reports it
+type batchJob struct {
+	JobStatusResponse
+	Tenant string `json:"tenant"`
+	UserID string `json:"user_id"`
+}
+
+// SetBatchWorker looks batch jobs up on the batch worker at batchWorkerURL
+func (h *InferenceHandler) SetBatchWorker(client *http.Client, batchWorkerURL string) {
+	h.jobsClient = client
+	h.batchWorkerURL = batchWorkerURL
+}
+
+// SetControlTopic publishes job cancellations to topic, which every batch
+// worker reads
+func (h *InferenceHandler) SetControlTopic(topic string) {
+	h.controlTopic = topic
+}
+
+// GetJobStatus retrieves the status of a batch job
+func (h *InferenceHandler) GetJobStatus(c *gin.Context) {
+	jobID := c.Param("id")
+	ctx := logging.WithJobID(c.Request.Context(), jobID)
+
+	logging.With(ctx, h.logger).Info("retrieving job status")
+
+	tenant, _ := callerTenant(c)
+	job, err := h.getJob(ctx, tenant, c.GetString("user_id"), jobID)
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job.JobStatusResponse)
+}
+
+// GetJobResults streams a finished job's results from the batch worker, after
+// the same ownership check as GetJobStatus. Results can be large, so they are
+// copied through rather than buffered.
+func (h *InferenceHandler) GetJobResults(c *gin.Context) {
+	jobID := c.Param("id")
+	ctx := logging.WithJobID(c.Request.Context(), jobID)
+
+	tenant, _ := callerTenant(c)
+	job, err := h.getJob(ctx, tenant, c.GetString("user_id"), jobID)
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+	if job.ResultURL == "" {
+		switch job.Status {
+		case "completed", "failed", "cancelled":
+			apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.NotFound, "job %s has no results", jobID))
+		default:
+			apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.FailedPrecondition, "job is still %s", job.Status))
+		}
+		return
+	}
+
+	// Downloads outlive the server's write timeout; like streams, they are
+	// bounded by the longest a stream may run
+	ctx, cancel := context.WithTimeout(ctx, h.maxStream)
+	defer cancel()
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(h.maxStream))
+
+	endpoint := strings.TrimRight(h.batchWorkerURL, "/") + jobsPath + "/" + url.PathEscape(jobID) + "/results"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.Wrap(err, apperrors.Internal, "failed to create results request"))
+		return
+	}
+	logging.Inject(ctx, req)
+
+	// The client's timeout would cut off long downloads; the request's
+	// context ends the copy if the caller goes away
+	client := *h.jobsClient
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	if err != nil {
+		logging.With(ctx, h.logger).Error("failed to reach batch worker", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, apperrors.FromTransportError(err, "batch-worker"))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apperrors.Write(c.Writer, c.Request, apperrors.FromHTTPResponse(resp, "batch-worker"))
+		return
+	}
+
+	c.Header("Content-Type", resp.Header.Get("Content-Type"))
+	if length := resp.Header.Get("Content-Length"); length != "" {
+		c.Header("Content-Length", length)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", jobID+"-results.json"))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, resp.Body); err != nil {
+		logging.With(ctx, h.logger).Warn("failed to stream job results", zap.Error(err))
+	}
+}
+
+// ListJobs lists the caller's batch jobs, newest first. The status, model,
+// created_after and created_before query parameters filter them, limit sets
+// the page size and cursor continues from a previous page's next_cursor.
+// Callers of a tenant see its jobs, and callers without one the jobs they
+// submitted; only admins may list every job, filtered by tenant if they like.
+func (h *InferenceHandler) ListJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query := url.Values{}
+	for _, key := range []string{"status", "model", "tenant", "created_after", "created_before", "limit", "cursor"} {
+		if value := c.Query(key); value != "" {
+			query.Set(key, value)
+		}
+	}
+	tenant, _ := callerTenant(c)
+	switch {
+	case tenant != "":
+		if filter := query.Get("tenant"); filter != "" && filter != tenant {
+			apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.PermissionDenied, "jobs of other tenants cannot be listed"))
+			return
+		}
+		query.Set("tenant", tenant)
+	case c.GetString("role") == "admin":
+	case query.Get("tenant") != "":
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.PermissionDenied, "admin role required to list jobs by tenant"))
+		return
+	default:
+		userID := c.GetString("user_id")
+		if userID == "" {
+			apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.PermissionDenied, "jobs can only be listed by the user that submitted them"))
+			return
+		}
+		query.Set("user_id", userID)
+	}
+
+	var page struct {
+		Jobs       []batchJob `json:"jobs"`
+		NextCursor string     `json:"next_cursor"`
+	}
+	if err := h.fetchJobs(ctx, jobsPath, query, &page); err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+
+	response := JobListResponse{
+		Jobs:       make([]JobStatusResponse, 0, len(page.Jobs)),
+		NextCursor: page.NextCursor,
+	}
+	for _, job := range page.Jobs {
+		response.Jobs = append(response.Jobs, job.JobStatusResponse)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CancelJob asks the batch workers to stop a job. The worker running it
+// finishes the inputs already sent for inference, skips the rest and marks the
+// job cancelled, so the request is answered with 202 before that happens.
+func (h *InferenceHandler) CancelJob(c *gin.Context) {
+	jobID := c.Param("id")
+	ctx := logging.WithJobID(c.Request.Context(), jobID)
+
+	if h.controlTopic == "" {
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.Unavailable, "job cancellation is not available"))
+		return
+	}
+
+	tenant, _ := callerTenant(c)
+	job, err := h.getJob(ctx, tenant, c.GetString("user_id"), jobID)
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+	switch job.Status {
+	case "completed", "failed", "cancelled":
+		apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.FailedPrecondition, "job is already %s", job.Status))
+		return
+	}
+
+	instruction := schema.BatchControl{
+		JobID:       jobID,
+		Action:      schema.ActionCancel,
+		RequestedAt: time.Now().UTC(),
+	}
+	if _, _, err := h.publish(ctx, h.controlTopic, schema.BatchControls, jobID, instruction); err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.Ensure(err, apperrors.Unavailable, "failed to cancel job"))
+		return
+	}
+
+	logging.With(ctx, h.logger).Info("batch job cancellation requested", zap.String("status", job.Status))
+
+	c.JSON(http.StatusAccepted, BatchJobResponse{
+		JobID:     jobID,
+		Status:    "cancelling",
+		CreatedAt: job.CreatedAt,
+	})
+}
+
+// getJob fetches a job's state from the batch worker for the caller, a user
+// of tenant. Callers of a tenant see its jobs, and callers without one only
+// the jobs they submitted; others are reported as not found, so callers
+// cannot probe for them.
+func (h *InferenceHandler) getJob(ctx context.Context, tenant, userID, jobID string) (*batchJob, error) {
+	var job batchJob
+	if err := h.fetchJobs(ctx, jobsPath+"/"+url.PathEscape(jobID), nil, &job); err != nil {
+		return nil, err
+	}
+	if (tenant != "" && job.Tenant != tenant) || (tenant == "" && job.UserID != userID) {
+		return nil, apperrors.Newf(apperrors.NotFound, "job not found: %s", jobID)
+	}
+	return &job, nil
+}
+
+// fetchJobs decodes the batch worker's answer to a GET of path into v
+func (h *InferenceHandler) fetchJobs(ctx context.Context, path string, query url.Values, v interface{}) error {
+	if h.batchWorkerURL == "" {
+		return apperrors.New(apperrors.Unavailable, "batch jobs are not available")
+	}
+
+	endpoint := strings.TrimRight(h.batchWorkerURL, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.Internal, "failed to create job request")
+	}
+	logging.Inject(ctx, req)
+
+	resp, err := h.jobsClient.Do(req)
+	if err != nil {
+		logging.With(ctx, h.logger).Error("failed to reach batch worker", zap.Error(err))
+		return apperrors.FromTransportError(err, "batch-worker")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.FromHTTPResponse(resp, "batch-worker")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return apperrors.Wrap(err, apperrors.Unavailable, "failed to decode batch worker response")
+	}
+	return nil
+}
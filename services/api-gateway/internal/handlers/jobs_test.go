@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+// batchWorkerJobs are the jobs the fake batch worker knows, by path
+var batchWorkerJobs = map[string]string{
+	"/v1/jobs/job-1": `{"job_id":"job-1","tenant":"acme","model":"resnet18","version":"v1","status":"completed",` +
+		`"progress":1,"total_items":2,"completed":2,"result_url":"/v1/jobs/job-1/results",` +
+		`"created_at":"2026-10-01T12:00:00Z","updated_at":"2026-10-01T12:01:00Z","completed_at":"2026-10-01T12:01:00Z"}`,
+	"/v1/jobs/job-1/results": `[{"prediction":{"class":"cat"}},{"prediction":{"class":"dog"}}]`,
+	"/v1/jobs/job-2": `{"job_id":"job-2","tenant":"acme","model":"resnet18","version":"v1","status":"processing",` +
+		`"progress":0.5,"total_items":2,"completed":1,"created_at":"2026-10-01T12:00:00Z","updated_at":"2026-10-01T12:01:00Z"}`,
+	"/v1/jobs/job-4": `{"job_id":"job-4","user_id":"alice","model":"resnet18","version":"v1","status":"completed",` +
+		`"progress":1,"total_items":1,"completed":1,"result_url":"/v1/jobs/job-4/results",` +
+		`"created_at":"2026-10-01T12:00:00Z","updated_at":"2026-10-01T12:01:00Z","completed_at":"2026-10-01T12:01:00Z"}`,
+	"/v1/jobs/job-4/results": `[{"prediction":{"class":"cat"}}]`,
+}
+
+func newJobsRouter(t *testing.T, tenant, user string, producer sarama.SyncProducer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	batchWorker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		job, ok := batchWorkerJobs[r.URL.Path]
+		if !ok {
+			apperrors.WriteHTTP(w, apperrors.New(apperrors.NotFound, "job not found"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(job))
+	}))
+	t.Cleanup(batchWorker.Close)
+
+	handler := NewInferenceHandler(zap.NewNop(), "http://model-router", producer, "inference-jobs")
+	handler.SetBatchWorker(batchWorker.Client(), batchWorker.URL)
+	handler.SetControlTopic("batch-control")
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if tenant != "" {
+			c.Set("tenant", tenant)
+		}
+		c.Set("user_id", user)
+	})
+	router.GET("/v1/jobs/:id", handler.GetJobStatus)
+	router.GET("/v1/jobs/:id/results", handler.GetJobResults)
+	router.DELETE("/v1/batch/:id", handler.CancelJob)
+	return router
+}
+
+func TestGetJobStatus_ReturnsBatchWorkerState(t *testing.T) {
+	router := newJobsRouter(t, "acme", "alice", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/jobs/job-1", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "tenant")
+
+	var resp JobStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "job-1", resp.JobID)
+	assert.Equal(t, "completed", resp.Status)
+	assert.Equal(t, 1.0, resp.Progress)
+	assert.Equal(t, 2, resp.Completed)
+	assert.Equal(t, "/v1/jobs/job-1/results", resp.ResultURL)
+	require.NotNil(t, resp.CompletedAt)
+}
+
+func TestGetJobStatus_NotFound(t *testing.T) {
+	tests := map[string]struct {
+		tenant string
+		path   string
+	}{
+		"unknown job":    {"acme", "/v1/jobs/job-3"},
+		"another tenant": {"globex", "/v1/jobs/job-1"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := newJobsRouter(t, tt.tenant, "alice", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Contains(t, w.Body.String(), "not_found")
+		})
+	}
+}
+
+func TestGetJob_OnlyTheSubmitterWithoutTenancy(t *testing.T) {
+	router := newJobsRouter(t, "", "alice", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/jobs/job-4", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Another user cannot see, download or cancel it
+	router = newJobsRouter(t, "", "bob", nil)
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/v1/jobs/job-4", nil),
+		httptest.NewRequest("GET", "/v1/jobs/job-4/results", nil),
+		httptest.NewRequest("DELETE", "/v1/batch/job-4", nil),
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code, req.Method+" "+req.URL.Path)
+		assert.NotContains(t, w.Body.String(), "prediction")
+	}
+}
+
+func TestGetJobStatus_BatchWorkerUnreachable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	batchWorker := httptest.NewServer(http.NotFoundHandler())
+	batchWorker.Close()
+
+	handler := NewInferenceHandler(zap.NewNop(), "http://model-router", nil, "inference-jobs")
+	handler.SetBatchWorker(&http.Client{}, batchWorker.URL)
+	router := gin.New()
+	router.GET("/v1/jobs/:id", handler.GetJobStatus)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/jobs/job-1", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestGetJobResults_StreamsFromBatchWorker(t *testing.T) {
+	router := newJobsRouter(t, "acme", "alice", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/jobs/job-1/results", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, batchWorkerJobs["/v1/jobs/job-1/results"], w.Body.String())
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="job-1-results.json"`, w.Header().Get("Content-Disposition"))
+}
+
+func TestGetJobResults_OutlivesWriteTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The results take longer to arrive than the gateway may spend writing a
+	// response
+	results := strings.Repeat("x", 64<<10)
+	batchWorker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/jobs/job-1" {
+			w.Write([]byte(batchWorkerJobs["/v1/jobs/job-1"]))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(results[:len(results)/2]))
+		w.(http.Flusher).Flush()
+		time.Sleep(300 * time.Millisecond)
+		w.Write([]byte(results[len(results)/2:]))
+	}))
+	defer batchWorker.Close()
+
+	handler := NewInferenceHandler(zap.NewNop(), "http://model-router", nil, "inference-jobs")
+	handler.SetBatchWorker(batchWorker.Client(), batchWorker.URL)
+	router := gin.New()
+	router.GET("/v1/jobs/:id/results", handler.GetJobResults)
+	gateway := httptest.NewUnstartedServer(router)
+	gateway.Config.WriteTimeout = 100 * time.Millisecond
+	gateway.Start()
+	defer gateway.Close()
+
+	resp, err := http.Get(gateway.URL + "/v1/jobs/job-1/results")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, len(results), len(body))
+}
+
+func TestGetJobResults_Rejected(t *testing.T) {
+	tests := map[string]struct {
+		tenant     string
+		path       string
+		wantStatus int
+	}{
+		"running job":    {"acme", "/v1/jobs/job-2/results", http.StatusPreconditionFailed},
+		"unknown job":    {"acme", "/v1/jobs/job-3/results", http.StatusNotFound},
+		"another tenant": {"globex", "/v1/jobs/job-1/results", http.StatusNotFound},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := newJobsRouter(t, tt.tenant, "alice", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.NotContains(t, w.Body.String(), "prediction")
+		})
+	}
+}
+
+func TestCancelJob_PublishesCancellation(t *testing.T) {
+	var instruction schema.BatchControl
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(value []byte) error {
+		return json.Unmarshal(value, &instruction)
+	})
+	defer producer.Close()
+
+	router := newJobsRouter(t, "acme", "alice", producer)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/v1/batch/job-2", nil))
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var resp BatchJobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "cancelling", resp.Status)
+
+	assert.Equal(t, "job-2", instruction.JobID)
+	assert.Equal(t, schema.ActionCancel, instruction.Action)
+	assert.False(t, instruction.RequestedAt.IsZero())
+}
+
+func TestCancelJob_Rejected(t *testing.T) {
+	tests := map[string]struct {
+		tenant     string
+		path       string
+		wantStatus int
+	}{
+		"finished job":   {"acme", "/v1/batch/job-1", http.StatusPreconditionFailed},
+		"unknown job":    {"acme", "/v1/batch/job-3", http.StatusNotFound},
+		"another tenant": {"globex", "/v1/batch/job-2", http.StatusNotFound},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// A nil producer would panic if the cancellation were published
+			router := newJobsRouter(t, tt.tenant, "alice", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("DELETE", tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestListJobs_PinsCallerTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var query url.Values
+	batchWorker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/jobs", r.URL.Path)
+		query = r.URL.Query()
+		w.Write([]byte(`{"jobs":[` + batchWorkerJobs["/v1/jobs/job-2"] + `],"next_cursor":"abc"}`))
+	}))
+	defer batchWorker.Close()
+
+	handler := NewInferenceHandler(zap.NewNop(), "http://model-router", nil, "inference-jobs")
+	handler.SetBatchWorker(batchWorker.Client(), batchWorker.URL)
+	router := gin.New()
+	router.GET("/v1/jobs", func(c *gin.Context) {
+		c.Set("tenant", "acme")
+		handler.ListJobs(c)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/jobs?status=processing&limit=1&cursor=xyz&subject_id=s1", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, url.Values{
+		"tenant": {"acme"},
+		"status": {"processing"},
+		"limit":  {"1"},
+		"cursor": {"xyz"},
+	}, query)
+	assert.NotContains(t, w.Body.String(), "tenant")
+
+	var resp JobListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Jobs, 1)
+	assert.Equal(t, "job-2", resp.Jobs[0].JobID)
+	assert.Equal(t, "abc", resp.NextCursor)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/jobs?tenant=globex", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestListJobs_WithoutTenancy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var query url.Values
+	batchWorker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"jobs":[]}`))
+	}))
+	defer batchWorker.Close()
+
+	handler := NewInferenceHandler(zap.NewNop(), "http://model-router", nil, "inference-jobs")
+	handler.SetBatchWorker(batchWorker.Client(), batchWorker.URL)
+
+	tests := map[string]struct {
+		user, role, path string
+		wantStatus       int
+		wantQuery        url.Values
+	}{
+		"own jobs":          {"alice", "", "/v1/jobs?status=completed", http.StatusOK, url.Values{"user_id": {"alice"}, "status": {"completed"}}},
+		"tenant filter":     {"alice", "", "/v1/jobs?tenant=acme", http.StatusForbidden, nil},
+		"no user":           {"", "", "/v1/jobs", http.StatusForbidden, nil},
+		"admin":             {"root", "admin", "/v1/jobs", http.StatusOK, url.Values{}},
+		"admin of a tenant": {"root", "admin", "/v1/jobs?tenant=acme", http.StatusOK, url.Values{"tenant": {"acme"}}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			query = nil
+			router := gin.New()
+			router.GET("/v1/jobs", func(c *gin.Context) {
+				c.Set("user_id", tt.user)
+				c.Set("role", tt.role)
+				handler.ListJobs(c)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantQuery, query)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// privacyDeletionsPath is where the batch worker serves deletion requests
+const privacyDeletionsPath = "/v1/privacy/deletions"
+
+// PrivacyDeletions forwards deletion requests, and lookups of their reports by
+// :id, to the batch worker, which holds the stored inference inputs and results
+func PrivacyDeletions(client *http.Client, batchWorkerURL string, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		url := strings.TrimRight(batchWorkerURL, "/") + privacyDeletionsPath
+		if id := c.Param("id"); id != "" {
+			url += "/" + id
+		}
+
+		req, err := http.NewRequestWithContext(ctx, c.Request.Method, url, c.Request.Body)
+		if err != nil {
+			apperrors.Write(c.Writer, c.Request, err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		logging.Inject(ctx, req)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			logging.With(ctx, logger).Error("failed to reach batch worker", zap.Error(err))
+			apperrors.Write(c.Writer, c.Request, apperrors.FromTransportError(err, "batch-worker"))
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			apperrors.Write(c.Writer, c.Request, apperrors.Wrap(err, apperrors.Unavailable, "failed to read batch worker response"))
+			return
+		}
+		c.Data(resp.StatusCode, "application/json", body)
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/servicetest"
+)
+
+func TestPrivacyDeletions_ForwardsToBatchWorker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotMethod, gotPath, gotBody string
+	batchWorker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotPath, gotBody = r.Method, r.URL.Path, string(body)
+		w.Write([]byte(`{"id":"d1","complete":true}`))
+	}))
+	defer batchWorker.Close()
+
+	handler := PrivacyDeletions(batchWorker.Client(), batchWorker.URL, zap.NewNop())
+	router := gin.New()
+	router.POST("/admin/privacy/deletions", handler)
+	router.GET("/admin/privacy/deletions/:id", handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/admin/privacy/deletions", strings.NewReader(`{"tenant":"acme"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "POST", gotMethod)
+	assert.Equal(t, "/v1/privacy/deletions", gotPath)
+	assert.Equal(t, `{"tenant":"acme"}`, gotBody)
+	assert.JSONEq(t, `{"id":"d1","complete":true}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/privacy/deletions/d1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/v1/privacy/deletions/d1", gotPath)
+}
+
+func TestPrivacyDeletions_BatchWorkerUnreachable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	url := servicetest.Down(t).URL
+
+	router := gin.New()
+	router.POST("/admin/privacy/deletions", PrivacyDeletions(http.DefaultClient, url, zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/admin/privacy/deletions", strings.NewReader(`{"tenant":"acme"}`)))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/quota"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+)
+
+// SetQuotaTracker enforces tenants' monthly quotas
+func (h *InferenceHandler) SetQuotaTracker(tracker *quota.Tracker) {
+	h.quotas = tracker
+}
+
+// callerTenant returns the tenant the caller acts for and its limits, set by
+// the Tenants middleware; callers without a tenant have no quotas
+func callerTenant(c *gin.Context) (string, tenancy.Limits) {
+	var limits tenancy.Limits
+	if value, ok := c.Get("tenant_limits"); ok {
+		limits = value.(tenancy.Limits)
+	}
+	return c.GetString("tenant"), limits
+}
+
+// consumeQuota counts n of resource against the tenant's monthly quota
+func (h *InferenceHandler) consumeQuota(ctx context.Context, tenant string, limits tenancy.Limits, resource quota.Resource, n int64) error {
+	if h.quotas == nil || tenant == "" {
+		return nil
+	}
+	return h.quotas.Consume(ctx, tenant, resource, n, quota.Limit(limits, resource))
+}
+
+// writeQuotaError answers a request turned away by a quota with 402, so that
+// clients can tell it from rate limiting and stop retrying until it resets
+func writeQuotaError(c *gin.Context, err error) {
+	var exhausted *quota.ExhaustedError
+	if !errors.As(err, &exhausted) {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+	c.Header("Retry-After", strconv.Itoa(int(time.Until(exhausted.ResetsAt).Seconds())+1))
+	_, body := apperrors.Problem(c.Request.Context(), apperrors.New(apperrors.ResourceExhausted, exhausted.Error()))
+	// Retrying before the quota resets is pointless
+	body.Status = http.StatusPaymentRequired
+	body.Retryable = false
+	apperrors.WriteBody(c.Writer, body.Status, body.With(map[string]interface{}{
+		"quota":     exhausted.Resource,
+		"limit":     exhausted.Limit,
+		"resets_at": exhausted.ResetsAt,
+	}))
+}
+
+// QuotaUsage reports the caller's tenant's use of its monthly quotas
+func QuotaUsage(tracker *quota.Tracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant, limits := callerTenant(c)
+		if tenant == "" {
+			apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.PermissionDenied, "credentials do not name a tenant"))
+			return
+		}
+
+		report, err := tracker.Report(c.Request.Context(), tenant, limits)
+		if err != nil {
+			apperrors.Write(c.Writer, c.Request, err)
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/api-gateway/internal/quota"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+)
+
+type memoryCounters map[string]int64
+
+func (m memoryCounters) Add(ctx context.Context, key string, n int64, expireAt time.Time) (int64, error) {
+	m[key] += n
+	return m[key], nil
+}
+
+func (m memoryCounters) Get(ctx context.Context, keys ...string) ([]int64, error) {
+	counts := make([]int64, len(keys))
+	for i, key := range keys {
+		counts[i] = m[key]
+	}
+	return counts, nil
+}
+
+// asTenant acts for tenant acme with limits, as the Tenants middleware would
+func asTenant(limits tenancy.Limits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("tenant", "acme")
+		c.Set("tenant_limits", limits)
+	}
+}
+
+func TestRealTimeInference_EnforcesMonthlyQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"prediction":[1]}`))
+	}))
+	defer server.Close()
+
+	tracker := quota.NewTracker(memoryCounters{}, middleware.FailOpen, zap.NewNop())
+	handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+	handler.SetQuotaTracker(tracker)
+	router := gin.New()
+	router.POST("/v1/infer", asTenant(tenancy.Limits{MonthlyRequests: 1}), handler.RealTimeInference)
+
+	infer := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/v1/infer", bytes.NewBufferString(`{"model":"resnet18","input":{"data":[1.0]}}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, infer().Code)
+
+	w := infer()
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, string(apperrors.ResourceExhausted), body["code"])
+	assert.Equal(t, "requests", body["quota"])
+	assert.Equal(t, 1.0, body["limit"])
+}
+
+func TestBatchInference_EnforcesMonthlyItemQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	counters := memoryCounters{}
+	handler := NewInferenceHandler(logger, "http://router", nil, "inference-jobs")
+	handler.SetQuotaTracker(quota.NewTracker(counters, middleware.FailOpen, zap.NewNop()))
+	router := gin.New()
+	router.POST("/v1/batch", asTenant(tenancy.Limits{MonthlyBatchItems: 2}), handler.BatchInference)
+
+	req := httptest.NewRequest("POST", "/v1/batch", bytes.NewBufferString(`{"model":"resnet18","inputs":[{"a":1},{"a":2},{"a":3}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+	assert.Contains(t, w.Body.String(), `"quota":"batch_items"`)
+	for _, count := range counters {
+		assert.Zero(t, count, "rejected items are not counted")
+	}
+}
+
+func TestQuotaUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tracker := quota.NewTracker(memoryCounters{}, middleware.FailOpen, zap.NewNop())
+	require.NoError(t, tracker.Consume(context.Background(), "acme", quota.Requests, 3, 10))
+	router := gin.New()
+	router.GET("/v1/usage", asTenant(tenancy.Limits{MonthlyRequests: 10}), QuotaUsage(tracker))
+	router.GET("/v1/anonymous/usage", QuotaUsage(tracker))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/usage", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report quota.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, "acme", report.Tenant)
+	require.Len(t, report.Quotas, 2)
+	assert.Equal(t, int64(3), report.Quotas[0].Used)
+	assert.Equal(t, int64(7), *report.Quotas[0].Remaining)
+	assert.Nil(t, report.Quotas[1].Remaining, "batch items are unlimited")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/anonymous/usage", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
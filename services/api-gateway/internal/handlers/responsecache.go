@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/ai-platform/pkg/inferencecache"
+)
+
+// SetResponseCache answers repeated real-time inferences of deterministic
+// models from cache
+func (h *InferenceHandler) SetResponseCache(cache *inferencecache.Cache) {
+	h.responses = cache
+}
+
+// cachedInference returns the cached response to req, and says in X-Cache
+// whether it was a hit when req's model is cached. Requests sent with
+// Cache-Control: no-cache always reach the model, and refresh its entry.
+func (h *InferenceHandler) cachedInference(c *gin.Context, requestID string, req InferenceRequest) (*InferenceResponse, bool) {
+	if !h.responses.Enabled(c.Request.Context(), req.Model, req.Version) {
+		return nil, false
+	}
+	if !strings.Contains(c.GetHeader("Cache-Control"), "no-cache") {
+		if prediction, ok := h.responses.Get(c.Request.Context(), req.Model, req.Version, req.Input); ok {
+			c.Header("X-Cache", "HIT")
+			return &InferenceResponse{
+				RequestID:  requestID,
+				Model:      req.Model,
+				Version:    req.Version,
+				Prediction: prediction,
+			}, true
+		}
+	}
+	c.Header("X-Cache", "MISS")
+	return nil, false
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/inferencecache"
+	"github.com/yourusername/ai-platform/pkg/tiered"
+)
+
+type memoryResponses map[string][]byte
+
+func (m memoryResponses) Get(ctx context.Context, key string) ([]byte, error) {
+	value, ok := m[key]
+	if !ok {
+		return nil, tiered.ErrMiss
+	}
+	return value, nil
+}
+
+func (m memoryResponses) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m[key] = value
+	return nil
+}
+
+func (m memoryResponses) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(m, key)
+	}
+	return nil
+}
+
+func TestRealTimeInference_ResponseCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"embedding":[0.1,0.2]}`))
+	}))
+	defer server.Close()
+
+	handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+	handler.SetResponseCache(inferencecache.New("inference:cache", memoryResponses{}, inferencecache.ModelTTLs{"text-embedding": time.Hour}, logger))
+	router := gin.New()
+	router.POST("/v1/infer", handler.RealTimeInference)
+
+	infer := func(body string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/v1/infer", bytes.NewBufferString(body))
+		req.Header = header.Clone()
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	embed := `{"model":"text-embedding","input":{"text":"hello"}}`
+
+	w := infer(embed, http.Header{})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+
+	w = infer(embed, http.Header{})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.Contains(t, w.Body.String(), `"embedding":[0.1,0.2]`)
+	assert.Equal(t, 1, calls)
+
+	// no-cache skips the lookup
+	w = infer(embed, http.Header{"Cache-Control": {"no-cache"}})
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, 2, calls)
+
+	// Models without a TTL are not cached
+	infer(`{"model":"resnet18","input":{"data":[1.0]}}`, http.Header{})
+	w = infer(`{"model":"resnet18","input":{"data":[1.0]}}`, http.Header{})
+	assert.Empty(t, w.Header().Get("X-Cache"))
+	assert.Equal(t, 4, calls)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// routerBackendsPath is where the model router changes its routing table
+const routerBackendsPath = "/admin/backends"
+
+// RouterAdmin forwards routing table changes, with anything below the
+// route's *path, to the model router's admin API. Drains may wait for
+// requests in flight, so the response may take as long as client's timeout.
+func RouterAdmin(client *http.Client, routerURL string, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(client.Timeout))
+
+		url := strings.TrimRight(routerURL, "/") + routerBackendsPath + strings.TrimRight(c.Param("path"), "/")
+		if c.Request.URL.RawQuery != "" {
+			url += "?" + c.Request.URL.RawQuery
+		}
+
+		req, err := http.NewRequestWithContext(ctx, c.Request.Method, url, c.Request.Body)
+		if err != nil {
+			apperrors.Write(c.Writer, c.Request, err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		logging.Inject(ctx, req)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			logging.With(ctx, logger).Error("failed to reach model router", zap.Error(err))
+			apperrors.Write(c.Writer, c.Request, apperrors.FromTransportError(err, "model-router"))
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			apperrors.Write(c.Writer, c.Request, apperrors.Wrap(err, apperrors.Unavailable, "failed to read model router response"))
+			return
+		}
+		c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRouterAdmin_ForwardsToRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotMethod, gotPath, gotQuery, gotBody string
+	modelRouter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotPath, gotQuery, gotBody = r.Method, r.URL.Path, r.URL.RawQuery, string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"url":"http://gpu-node-3:8082","drained":true}`))
+	}))
+	defer modelRouter.Close()
+
+	handler := RouterAdmin(modelRouter.Client(), modelRouter.URL, zap.NewNop())
+	router := gin.New()
+	router.Any("/admin/router/backends", handler)
+	router.Any("/admin/router/backends/*path", handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/admin/router/backends/drain", strings.NewReader(`{"url":"http://gpu-node-3:8082"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "PUT", gotMethod)
+	assert.Equal(t, "/admin/backends/drain", gotPath)
+	assert.Equal(t, `{"url":"http://gpu-node-3:8082"}`, gotBody)
+	assert.JSONEq(t, `{"url":"http://gpu-node-3:8082","drained":true}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/router/backends?model=resnet18", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/admin/backends", gotPath)
+	assert.Equal(t, "model=resnet18", gotQuery)
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/api-gateway/internal/quota"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// sessionWriteTimeout bounds how long a result or ping may take to reach the client
+const sessionWriteTimeout = 10 * time.Second
+
+// SessionLimits bound an inference session
+type SessionLimits struct {
+	// MaxInFlight is how many requests a session may have outstanding
+	MaxInFlight int
+	// MaxMessageBytes is the largest request a client may send
+	MaxMessageBytes int64
+	// IdleTimeout closes sessions that neither send requests nor answer pings
+	IdleTimeout time.Duration
+	// MaxDuration closes sessions after this long, so that clients reconnect
+	// and are authenticated again
+	MaxDuration time.Duration
+}
+
+// DefaultSessionLimits are the limits sessions get unless configured otherwise
+var DefaultSessionLimits = SessionLimits{
+	MaxInFlight:     8,
+	MaxMessageBytes: 1 << 20,
+	IdleTimeout:     5 * time.Minute,
+	MaxDuration:     time.Hour,
+}
+
+// SessionRequest is an inference request sent over a session. ID is chosen
+// by the client to match results to requests, since results are sent as
+// requests complete rather than in the order they were made.
+type SessionRequest struct {
+	ID string `json:"id"`
+	InferenceRequest
+}
+
+// SessionResult answers a SessionRequest with either its inference response
+// or the platform's error body
+type SessionResult struct {
+	ID string `json:"id"`
+	*InferenceResponse
+	Error   string         `json:"error,omitempty"`
+	Code    apperrors.Code `json:"code,omitempty"`
+	Details string         `json:"details,omitempty"`
+}
+
+var sessionUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+}
+
+// session is an open inference session; writes are serialized since results
+// are sent from the requests' goroutines
+type session struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+}
+
+func (s *session) send(result SessionResult) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.conn.SetWriteDeadline(time.Now().Add(sessionWriteTimeout))
+	return s.conn.WriteJSON(result)
+}
+
+func (s *session) ping() error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(sessionWriteTimeout))
+}
+
+func (s *session) close(code int, reason string) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(sessionWriteTimeout))
+}
+
+// SetSessionLimits bounds inference sessions
+func (h *InferenceHandler) SetSessionLimits(limits SessionLimits) {
+	h.sessionLimits = limits
+}
+
+// InferenceSession upgrades to a WebSocket over which a client sends
+// inference requests and receives their results as they complete. The
+// session is authenticated once, when it opens; each request is counted
+// against the caller's rate limit and quota and metered like a real-time
+// inference.
+func (h *InferenceHandler) InferenceSession(c *gin.Context) {
+	conn, err := sessionUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already answered the request
+		logging.With(c.Request.Context(), h.logger).Warn("failed to open inference session", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	logger := logging.With(ctx, h.logger)
+
+	var allow middleware.Allowance
+	if value, ok := c.Get(middleware.AllowanceKey); ok {
+		allow = value.(middleware.Allowance)
+	}
+	var allowModel middleware.ModelAllowance
+	if value, ok := c.Get(middleware.ModelAllowanceKey); ok {
+		allowModel = value.(middleware.ModelAllowance)
+	}
+	tenant, tenantLimits := callerTenant(c)
+	userID := c.GetString("user_id")
+
+	limits := h.sessionLimits
+	s := &session{conn: conn}
+	conn.SetReadLimit(limits.MaxMessageBytes)
+	conn.SetReadDeadline(time.Now().Add(limits.IdleTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(limits.IdleTimeout))
+	})
+
+	// Closing the connection ends the read loop once the session has lasted
+	// its maximum duration
+	expired := time.AfterFunc(limits.MaxDuration, func() {
+		s.close(websocket.CloseNormalClosure, "session expired")
+		conn.Close()
+	})
+	defer expired.Stop()
+
+	go func() {
+		ticker := time.NewTicker(limits.IdleTimeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.ping(); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	logger.Info("inference session opened")
+	startTime := time.Now()
+
+	inFlight := make(chan struct{}, limits.MaxInFlight)
+	var wg sync.WaitGroup
+	requests := 0
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Debug("inference session read ended", zap.Error(err))
+			}
+			break
+		}
+		conn.SetReadDeadline(time.Now().Add(limits.IdleTimeout))
+
+		var req SessionRequest
+		if err := json.Unmarshal(message, &req); err != nil {
+			s.send(sessionError("", apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error())))
+			continue
+		}
+		if req.Model == "" || req.Input == nil {
+			s.send(sessionError(req.ID, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails("model and input are required")))
+			continue
+		}
+		if req.Version == "" {
+			req.Version = h.versions.Resolve(ctx, req.Model)
+		}
+		req.UserID = userID
+
+		if allow != nil && !allow(ctx) {
+			s.send(sessionError(req.ID, apperrors.New(apperrors.ResourceExhausted, "rate limit exceeded")))
+			continue
+		}
+		if allowModel != nil && !allowModel(ctx, req.Model) {
+			s.send(sessionError(req.ID, apperrors.Newf(apperrors.ResourceExhausted, "rate limit exceeded for model %s", req.Model)))
+			continue
+		}
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			s.send(sessionError(req.ID, apperrors.Newf(apperrors.ResourceExhausted, "session has %d requests in flight", limits.MaxInFlight)))
+			continue
+		}
+		if err := h.consumeQuota(ctx, tenant, tenantLimits, quota.Requests, 1); err != nil {
+			<-inFlight
+			s.send(sessionError(req.ID, err))
+			continue
+		}
+
+		requests++
+		wg.Add(1)
+		go func(req SessionRequest) {
+			defer wg.Done()
+			defer func() { <-inFlight }()
+
+			requestID := uuid.New().String()
+			reqCtx := logging.WithRequestID(ctx, requestID)
+			reqCtx, span := otel.Tracer("api-gateway").Start(reqCtx, "SessionInference")
+			defer span.End()
+			span.SetAttributes(
+				attribute.String("model", req.Model),
+				attribute.String("version", req.Version),
+				attribute.String("request_id", requestID),
+			)
+
+			response, err := h.infer(reqCtx, requestID, req.InferenceRequest)
+			result := SessionResult{ID: req.ID, InferenceResponse: response}
+			if err != nil {
+				result = sessionError(req.ID, err)
+			}
+			if err := s.send(result); err != nil {
+				logging.With(reqCtx, h.logger).Warn("failed to send session result", zap.Error(err))
+			}
+		}(req)
+	}
+
+	// Results can no longer be delivered, so outstanding requests are abandoned
+	cancel()
+	wg.Wait()
+
+	logger.Info("inference session closed",
+		zap.Int("requests", requests),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+}
+
+// sessionError answers request id with err's error body
+func sessionError(id string, err error) SessionResult {
+	_, body := apperrors.ToHTTP(err)
+	return SessionResult{ID: id, Error: body.Error, Code: body.Code, Details: body.Details}
+}
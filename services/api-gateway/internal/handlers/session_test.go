@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// dialSession serves handler's sessions and connects to one
+func dialSession(t *testing.T, handler *InferenceHandler, middlewares ...gin.HandlerFunc) *websocket.Conn {
+	router := gin.New()
+	router.GET("/v1/ws/infer", append(middlewares, handler.InferenceSession)...)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/v1/ws/infer", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func readResults(t *testing.T, conn *websocket.Conn, n int) map[string]SessionResult {
+	results := make(map[string]SessionResult)
+	for i := 0; i < n; i++ {
+		var result SessionResult
+		require.NoError(t, conn.ReadJSON(&result))
+		results[result.ID] = result
+	}
+	return results
+}
+
+func TestInferenceSession_AnswersRequestsAsTheyComplete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch body["model"] {
+		case "slow":
+			<-release
+		case "resnet99":
+			apperrors.WriteHTTP(w, apperrors.New(apperrors.NotFound, "model not found: resnet99"))
+			return
+		}
+		w.Write([]byte(`{"prediction":[1]}`))
+	}))
+	defer server.Close()
+
+	conn := dialSession(t, NewInferenceHandler(logger, server.URL, nil, "inference-jobs"))
+
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"id": "a", "model": "slow", "input": map[string]interface{}{"data": []float64{1}}}))
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"id": "b", "model": "resnet18", "input": map[string]interface{}{"data": []float64{1}}}))
+
+	// The fast request is answered while the slow one is outstanding
+	var first SessionResult
+	require.NoError(t, conn.ReadJSON(&first))
+	assert.Equal(t, "b", first.ID)
+	require.NotNil(t, first.InferenceResponse)
+	assert.Equal(t, "v1", first.Version)
+	assert.NotEmpty(t, first.RequestID)
+	assert.Equal(t, []interface{}{1.0}, first.Prediction["prediction"])
+
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"id": "c", "model": "resnet99", "input": map[string]interface{}{}}))
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"d","model":"resnet18"}`)))
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`not json`)))
+	results := readResults(t, conn, 3)
+	assert.Equal(t, apperrors.NotFound, results["c"].Code)
+	assert.Equal(t, apperrors.InvalidArgument, results["d"].Code)
+	assert.Equal(t, apperrors.InvalidArgument, results[""].Code)
+
+	close(release)
+	results = readResults(t, conn, 1)
+	assert.Empty(t, results["a"].Error)
+	assert.NotEqual(t, first.RequestID, results["a"].RequestID, "each request gets its own ID")
+}
+
+func TestInferenceSession_SendsCallerToRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userIDs := make(chan interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		userIDs <- body["user_id"]
+		w.Write([]byte(`{"prediction":[1]}`))
+	}))
+	defer server.Close()
+
+	conn := dialSession(t, NewInferenceHandler(zap.NewNop(), server.URL, nil, "inference-jobs"), func(c *gin.Context) {
+		c.Set("user_id", "alice")
+	})
+
+	// The caller is the authenticated user, whatever the request says
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"id": "a", "model": "resnet18", "user_id": "bob", "input": map[string]interface{}{}}))
+	readResults(t, conn, 1)
+	assert.Equal(t, "alice", <-userIDs)
+}
+
+func TestInferenceSession_LimitsRequestsInFlight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"prediction":[1]}`))
+	}))
+	defer server.Close()
+
+	handler := NewInferenceHandler(logger, server.URL, nil, "inference-jobs")
+	limits := DefaultSessionLimits
+	limits.MaxInFlight = 1
+	handler.SetSessionLimits(limits)
+	conn := dialSession(t, handler)
+
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"id": "a", "model": "resnet18", "input": map[string]interface{}{}}))
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"id": "b", "model": "resnet18", "input": map[string]interface{}{}}))
+
+	results := readResults(t, conn, 1)
+	assert.Equal(t, apperrors.ResourceExhausted, results["b"].Code)
+
+	close(release)
+	results = readResults(t, conn, 1)
+	assert.Empty(t, results["a"].Error)
+}
+
+func TestInferenceSession_CountsRequestsAgainstRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"prediction":[1]}`))
+	}))
+	defer server.Close()
+
+	budget := 1
+	conn := dialSession(t, NewInferenceHandler(logger, server.URL, nil, "inference-jobs"), func(c *gin.Context) {
+		c.Set(middleware.AllowanceKey, middleware.Allowance(func(ctx context.Context) bool {
+			budget--
+			return budget >= 0
+		}))
+	})
+
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"id": "a", "model": "resnet18", "input": map[string]interface{}{}}))
+	results := readResults(t, conn, 1)
+	assert.Empty(t, results["a"].Error)
+
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"id": "b", "model": "resnet18", "input": map[string]interface{}{}}))
+	results = readResults(t, conn, 1)
+	assert.Equal(t, apperrors.ResourceExhausted, results["b"].Code)
+	assert.Equal(t, "rate limit exceeded", results["b"].Error)
+}
+
+func TestInferenceSession_CountsRequestsAgainstModelRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"prediction":[1]}`))
+	}))
+	defer server.Close()
+
+	conn := dialSession(t, NewInferenceHandler(logger, server.URL, nil, "inference-jobs"), func(c *gin.Context) {
+		c.Set(middleware.ModelAllowanceKey, middleware.ModelAllowance(func(ctx context.Context, model string) bool {
+			return model != "llama-70b"
+		}))
+	})
+
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"id": "a", "model": "llama-70b", "input": map[string]interface{}{}}))
+	results := readResults(t, conn, 1)
+	assert.Equal(t, apperrors.ResourceExhausted, results["a"].Code)
+	assert.Equal(t, "rate limit exceeded for model llama-70b", results["a"].Error)
+
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"id": "b", "model": "resnet18", "input": map[string]interface{}{}}))
+	results = readResults(t, conn, 1)
+	assert.Empty(t, results["b"].Error)
+}
+
+func TestInferenceSession_ClosesExpiredSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, _ := zap.NewDevelopment()
+
+	handler := NewInferenceHandler(logger, "http://localhost:0", nil, "inference-jobs")
+	limits := DefaultSessionLimits
+	limits.MaxDuration = 50 * time.Millisecond
+	handler.SetSessionLimits(limits)
+	conn := dialSession(t, handler)
+
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), "got %v", err)
+}
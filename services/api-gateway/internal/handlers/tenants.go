@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// tenantsPath is where the tenant service manages tenants
+const tenantsPath = "/v1/tenants"
+
+// TenantAdmin forwards tenant management requests, with anything below the
+// route's *path, to the tenant service
+func TenantAdmin(client *http.Client, tenantServiceURL string, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		url := strings.TrimRight(tenantServiceURL, "/") + tenantsPath + strings.TrimRight(c.Param("path"), "/")
+		if c.Request.URL.RawQuery != "" {
+			url += "?" + c.Request.URL.RawQuery
+		}
+
+		req, err := http.NewRequestWithContext(ctx, c.Request.Method, url, c.Request.Body)
+		if err != nil {
+			apperrors.Write(c.Writer, c.Request, err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		logging.Inject(ctx, req)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			logging.With(ctx, logger).Error("failed to reach tenant service", zap.Error(err))
+			apperrors.Write(c.Writer, c.Request, apperrors.FromTransportError(err, "tenant-service"))
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			apperrors.Write(c.Writer, c.Request, apperrors.Wrap(err, apperrors.Unavailable, "failed to read tenant service response"))
+			return
+		}
+		c.Data(resp.StatusCode, "application/json", body)
+	}
+}
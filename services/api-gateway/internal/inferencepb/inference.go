@@ -0,0 +1,136 @@
+// Package inferencepb holds the messages in inference.proto, the protobuf
+// form of the real-time inference API, generated with protoc-gen-go, and
+// converts their tensors to and from the JSON values the platform passes
+// on. Clients generate theirs from inference.proto.
+package inferencepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative inference.proto
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ContentType is the media type of protobuf requests and responses
+const ContentType = "application/x-protobuf"
+
+// Value converts the tensor to the JSON value the model router expects:
+// numbers or strings nested to the tensor's shape, or the decoded JSON data
+func (t *Tensor) Value() (interface{}, error) {
+	if t.JsonData != "" {
+		var v interface{}
+		if err := json.Unmarshal([]byte(t.JsonData), &v); err != nil {
+			return nil, fmt.Errorf("invalid json_data: %w", err)
+		}
+		return v, nil
+	}
+
+	var flat []interface{}
+	switch {
+	case len(t.FloatData) > 0:
+		for _, v := range t.FloatData {
+			flat = append(flat, float64(v))
+		}
+	case len(t.IntData) > 0:
+		for _, v := range t.IntData {
+			flat = append(flat, v)
+		}
+	default:
+		for _, v := range t.StringData {
+			flat = append(flat, v)
+		}
+	}
+	if flat == nil {
+		flat = []interface{}{}
+	}
+	if len(t.Shape) <= 1 {
+		if len(t.Shape) == 1 && t.Shape[0] != int64(len(flat)) {
+			return nil, fmt.Errorf("shape %v does not match %d values", t.Shape, len(flat))
+		}
+		return flat, nil
+	}
+
+	size := int64(1)
+	for _, dim := range t.Shape {
+		if dim <= 0 {
+			return nil, fmt.Errorf("invalid shape %v", t.Shape)
+		}
+		size *= dim
+	}
+	if size != int64(len(flat)) {
+		return nil, fmt.Errorf("shape %v does not match %d values", t.Shape, len(flat))
+	}
+	return reshape(flat, t.Shape), nil
+}
+
+func reshape(flat []interface{}, shape []int64) []interface{} {
+	if len(shape) == 1 {
+		return flat
+	}
+	rows := make([]interface{}, shape[0])
+	stride := len(flat) / int(shape[0])
+	for i := range rows {
+		rows[i] = reshape(flat[i*stride:(i+1)*stride], shape[1:])
+	}
+	return rows
+}
+
+// TensorFromValue converts a decoded JSON value to a tensor. Rectangular
+// arrays of numbers become float data and of strings string data, with
+// their shape; anything else is carried as JSON.
+func TensorFromValue(v interface{}) (*Tensor, error) {
+	if t, ok := arrayTensor(v); ok {
+		return t, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Tensor{JsonData: string(data)}, nil
+}
+
+func arrayTensor(v interface{}) (*Tensor, bool) {
+	if _, ok := v.([]interface{}); !ok {
+		return nil, false
+	}
+	t := &Tensor{}
+	var shape []int64
+	var walk func(v interface{}, depth int) bool
+	walk = func(v interface{}, depth int) bool {
+		switch v := v.(type) {
+		case []interface{}:
+			if depth == len(shape) {
+				if t.FloatData != nil || t.StringData != nil {
+					return false
+				}
+				shape = append(shape, int64(len(v)))
+			} else if depth > len(shape) || shape[depth] != int64(len(v)) {
+				return false
+			}
+			for _, item := range v {
+				if !walk(item, depth+1) {
+					return false
+				}
+			}
+			return true
+		case float64:
+			if depth != len(shape) || t.StringData != nil {
+				return false
+			}
+			t.FloatData = append(t.FloatData, float32(v))
+			return true
+		case string:
+			if depth != len(shape) || t.FloatData != nil {
+				return false
+			}
+			t.StringData = append(t.StringData, v)
+			return true
+		}
+		return false
+	}
+	if !walk(v, 0) {
+		return nil, false
+	}
+	t.Shape = shape
+	return t, true
+}
@@ -0,0 +1,107 @@
+package inferencepb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestInferenceRequest_RoundTrip(t *testing.T) {
+	req := &InferenceRequest{
+		Model:   "resnet18",
+		Version: "v2",
+		Inputs: map[string]*Tensor{
+			"image":  {Shape: []int64{2, 2}, FloatData: []float32{0.5, 1, -2, 3.25}},
+			"ids":    {IntData: []int64{1, -1, 1 << 40}},
+			"labels": {StringData: []string{"cat", "dog"}},
+		},
+	}
+
+	data, err := proto.Marshal(req)
+	require.NoError(t, err)
+	var decoded InferenceRequest
+	require.NoError(t, proto.Unmarshal(data, &decoded))
+	assert.True(t, proto.Equal(req, &decoded))
+}
+
+func TestInferenceResponse_RoundTrip(t *testing.T) {
+	resp := &InferenceResponse{
+		RequestId:     "req-1",
+		Model:         "resnet18",
+		Version:       "v1",
+		Outputs:       map[string]*Tensor{"meta": {JsonData: `{"k":1}`}},
+		LatencyMs:     42,
+		EstimatedCost: 0.0045,
+	}
+
+	data, err := proto.Marshal(resp)
+	require.NoError(t, err)
+	var decoded InferenceResponse
+	require.NoError(t, proto.Unmarshal(data, &decoded))
+	assert.True(t, proto.Equal(resp, &decoded))
+}
+
+func TestTensor_UnmarshalUnpackedAndUnknownFields(t *testing.T) {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, 2)
+	b = protowire.AppendTag(b, 2, protowire.Fixed32Type)
+	b = protowire.AppendFixed32(b, 0x3f800000)
+	b = protowire.AppendTag(b, 2, protowire.Fixed32Type)
+	b = protowire.AppendFixed32(b, 0x40000000)
+	b = protowire.AppendTag(b, 99, protowire.BytesType)
+	b = protowire.AppendString(b, "ignored")
+
+	var tensor Tensor
+	require.NoError(t, proto.Unmarshal(b, &tensor))
+	assert.Equal(t, []int64{2}, tensor.Shape)
+	assert.Equal(t, []float32{1, 2}, tensor.FloatData)
+
+	assert.Error(t, proto.Unmarshal([]byte{0x12, 0x05, 0x00}, &tensor))
+}
+
+func TestTensor_Value(t *testing.T) {
+	value, err := (&Tensor{Shape: []int64{2, 3}, FloatData: []float32{1, 2, 3, 4, 5, 6}}).Value()
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		[]interface{}{1.0, 2.0, 3.0},
+		[]interface{}{4.0, 5.0, 6.0},
+	}, value)
+
+	value, err = (&Tensor{IntData: []int64{7, 8}}).Value()
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(7), int64(8)}, value)
+
+	value, err = (&Tensor{JsonData: `{"top_k":3}`}).Value()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"top_k": 3.0}, value)
+
+	_, err = (&Tensor{Shape: []int64{2, 2}, FloatData: []float32{1, 2, 3}}).Value()
+	assert.Error(t, err)
+}
+
+func TestTensorFromValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  *Tensor
+	}{
+		{"matrix", []interface{}{[]interface{}{1.0, 2.0}, []interface{}{3.0, 4.0}}, &Tensor{Shape: []int64{2, 2}, FloatData: []float32{1, 2, 3, 4}}},
+		{"strings", []interface{}{"cat", "dog"}, &Tensor{Shape: []int64{2}, StringData: []string{"cat", "dog"}}},
+		{"ragged", []interface{}{[]interface{}{1.0}, []interface{}{2.0, 3.0}}, &Tensor{JsonData: `[[1],[2,3]]`}},
+		{"mixed", []interface{}{1.0, "cat"}, &Tensor{JsonData: `[1,"cat"]`}},
+		{"scalar", 0.5, &Tensor{JsonData: `0.5`}},
+		{"object", map[string]interface{}{"label": "cat"}, &Tensor{JsonData: `{"label":"cat"}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tensor, err := TensorFromValue(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tensor)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/admission"
+	"github.com/yourusername/ai-platform/api-gateway/internal/observability"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Admission runs a route's requests through limiter, answering 429 with
+// Retry-After when there is no room for them. Answers saying downstream is
+// unavailable or timed out count as overload for an adaptive limit.
+func Admission(limiter *admission.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		release, err := limiter.Acquire(c.Request.Context())
+		recordAdmission(limiter)
+		if err != nil {
+			reason := "queue_full"
+			if errors.Is(err, admission.ErrQueueTimeout) {
+				reason = "timeout"
+			} else if !errors.Is(err, admission.ErrQueueFull) {
+				// The caller gave up while queued
+				apperrors.Write(c.Writer, c.Request, err)
+				c.Abort()
+				return
+			}
+			observability.AdmissionRejected.WithLabelValues(reason).Inc()
+
+			retryAfter := limiter.RetryAfter()
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+			status, body := apperrors.Problem(c.Request.Context(), err)
+			apperrors.WriteBody(c.Writer, status, body.With(map[string]interface{}{
+				"retry_after": retryAfter.Seconds(),
+			}))
+			c.Abort()
+			return
+		}
+
+		start := time.Now()
+		defer func() {
+			release(time.Since(start), overloaded(c.Writer.Status()))
+			recordAdmission(limiter)
+		}()
+		c.Next()
+	}
+}
+
+// overloaded reports whether a status says downstream could not keep up
+func overloaded(status int) bool {
+	return status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+func recordAdmission(limiter *admission.Limiter) {
+	stats := limiter.Stats()
+	observability.AdmissionLimit.Set(float64(stats.Limit))
+	observability.AdmissionInFlight.Set(float64(stats.InFlight))
+	observability.AdmissionQueued.Set(float64(stats.Queued))
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/admission"
+)
+
+func TestAdmission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := admission.NewLimiter(admission.Config{MaxInFlight: 1, QueueSize: 0, MaxWait: 1500 * time.Millisecond})
+
+	started, finish := make(chan struct{}), make(chan struct{})
+	router := gin.New()
+	router.POST("/v1/infer", Admission(limiter), func(c *gin.Context) {
+		close(started)
+		<-finish
+		c.Status(http.StatusOK)
+	})
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(first, httptest.NewRequest("POST", "/v1/infer", nil))
+		close(done)
+	}()
+	<-started
+
+	// No room is left for a second request
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "resource_exhausted", body["code"])
+	assert.Equal(t, 2.0, body["retry_after"])
+
+	close(finish)
+	<-done
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, 0, limiter.Stats().InFlight)
+}
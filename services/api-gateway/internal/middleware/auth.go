@@ -1,20 +1,73 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
 )
 
+// KeyVerifier resolves tenant API keys to the principal they act as
+type KeyVerifier interface {
+	Verify(ctx context.Context, key string) (*tenancy.Principal, error)
+}
+
+// SigningKeys resolves the secret a JWT was signed with from its kid header
+type SigningKeys interface {
+	Secret(kid string) ([]byte, error)
+}
+
+// TokenVerifier validates tokens issued by an external identity provider and
+// returns their claims mapped to the ones the gateway uses
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (jwt.MapClaims, error)
+}
+
+// secretFunc verifies every JWT with one secret that may rotate at runtime
+type secretFunc func() string
+
+func (f secretFunc) Secret(kid string) ([]byte, error) {
+	return []byte(f()), nil
+}
+
 // Auth middleware validates JWT tokens or API keys
 func Auth(jwtSecret string) gin.HandlerFunc {
+	return AuthWithSecret(func() string { return jwtSecret })
+}
+
+// AuthWithSecret validates JWT tokens against a secret that may rotate at runtime
+func AuthWithSecret(jwtSecret func() string) gin.HandlerFunc {
+	return AuthWithKeys(jwtSecret, nil)
+}
+
+// AuthWithKeys validates JWT tokens like AuthWithSecret and, when keys is set,
+// also accepts tenant API keys. A key acts for its tenant with the role it was
+// issued with, which is kept apart from the platform role claim.
+func AuthWithKeys(jwtSecret func() string, keys KeyVerifier) gin.HandlerFunc {
+	return AuthWithSigningKeys(secretFunc(jwtSecret), keys)
+}
+
+// AuthWithSigningKeys validates JWT tokens signed with any of signing's keys,
+// such as those the gateway issues, and API keys like AuthWithKeys. Refresh
+// tokens do not authenticate requests.
+func AuthWithSigningKeys(signing SigningKeys, keys KeyVerifier) gin.HandlerFunc {
+	return AuthWithProvider(signing, nil, keys)
+}
+
+// AuthWithProvider validates tokens like AuthWithSigningKeys and, when provider
+// is set, also accepts tokens signed with an asymmetric key by an external
+// identity provider such as an OIDC server.
+func AuthWithProvider(signing SigningKeys, provider TokenVerifier, keys KeyVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing authorization header"})
+			apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.Unauthenticated, "missing authorization header"))
 			c.Abort()
 			return
 		}
@@ -30,32 +83,119 @@ func Auth(jwtSecret string) gin.HandlerFunc {
 		}
 
 		// For demo purposes, accept "demo-token" as valid
+		// (it carries no role, so admin routes stay closed to it)
 		if token == "demo-token" {
 			c.Set("user_id", "demo-user")
 			c.Next()
 			return
 		}
 
+		if keys != nil && tenancy.IsKey(token) {
+			principal, err := keys.Verify(c.Request.Context(), token)
+			if err != nil {
+				apperrors.Write(c.Writer, c.Request, err)
+				c.Abort()
+				return
+			}
+			c.Set("user_id", "key:"+principal.KeyID)
+			c.Set("tenant_role", string(principal.Role))
+			setTenant(c, principal.TenantID)
+			c.Next()
+			return
+		}
+
+		// Tokens signed with an asymmetric key come from the identity provider
+		if provider != nil && !isHMAC(token) {
+			claims, err := provider.Verify(c.Request.Context(), token)
+			if err != nil {
+				apperrors.Write(c.Writer, c.Request, err)
+				c.Abort()
+				return
+			}
+			setClaims(c, claims)
+			c.Next()
+			return
+		}
+
 		// Validate JWT
 		claims := jwt.MapClaims{}
 		parsedToken, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
 			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 			}
-			return []byte(jwtSecret), nil
+			kid, _ := t.Header["kid"].(string)
+			return signing.Secret(kid)
 		})
 
 		if err != nil || !parsedToken.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.Unauthenticated, "invalid token"))
 			c.Abort()
 			return
 		}
-
-		// Extract user ID from claims
-		if userID, ok := claims["user_id"].(string); ok {
-			c.Set("user_id", userID)
+		if typ, _ := claims["typ"].(string); typ == "refresh" {
+			apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.Unauthenticated, "refresh tokens cannot authenticate requests"))
+			c.Abort()
+			return
 		}
 
+		setClaims(c, claims)
+		c.Next()
+	}
+}
+
+// isHMAC reports whether a token is signed with a shared secret, judging by
+// its header alone
+func isHMAC(token string) bool {
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return true
+	}
+	_, ok := parsed.Method.(*jwt.SigningMethodHMAC)
+	return ok
+}
+
+// setClaims records a verified token's identity for handlers
+func setClaims(c *gin.Context, claims jwt.MapClaims) {
+	// Extract user ID from claims
+	if userID, ok := claims["user_id"].(string); ok {
+		c.Set("user_id", userID)
+	}
+
+	if role, ok := claims["role"].(string); ok {
+		c.Set("role", role)
+	}
+
+	// Tokens issued for API keys keep the key's role within its tenant
+	if role, ok := claims["tenant_role"].(string); ok && role != "" {
+		c.Set("tenant_role", role)
+	}
+
+	// The plan sets the caller's rate tier
+	if plan, ok := claims["plan"].(string); ok {
+		c.Set("plan", plan)
+	}
+
+	// Tag logs and downstream calls with the caller's tenant
+	if tenant, ok := claims["tenant_id"].(string); ok && tenant != "" {
+		setTenant(c, tenant)
+	}
+}
+
+// setTenant records the caller's tenant for handlers, logs and downstream calls
+func setTenant(c *gin.Context, tenant string) {
+	c.Set("tenant", tenant)
+	c.Request = c.Request.WithContext(logging.WithTenant(c.Request.Context(), tenant))
+}
+
+// RequireRole rejects callers whose token does not carry the given role claim.
+// It must run after Auth.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != role {
+			apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.PermissionDenied, "%s role required", role))
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
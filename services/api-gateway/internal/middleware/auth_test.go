@@ -1,12 +1,20 @@
 package middleware
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
 )
 
 func TestAuth_ValidJWT(t *testing.T) {
@@ -115,3 +123,147 @@ func TestAuth_BearerFormat(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w2.Code)
 }
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Auth("test-secret"), RequireRole("admin"))
+	router.GET("/admin/overview", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	sign := func(claims jwt.MapClaims) string {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+		return token
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{"admin token", sign(jwt.MapClaims{"user_id": "ops", "role": "admin"}), http.StatusOK},
+		{"user token", sign(jwt.MapClaims{"user_id": "alice"}), http.StatusForbidden},
+		{"demo token", "demo-token", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/admin/overview", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+// keyring maps key IDs to their secrets
+type keyring map[string]string
+
+func (k keyring) Secret(kid string) ([]byte, error) {
+	secret, ok := k[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	return []byte(secret), nil
+}
+
+func TestAuthWithSigningKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(AuthWithSigningKeys(keyring{"current": "new-secret", "old": "old-secret"}, nil))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetString("user_id"), "tenant_role": c.GetString("tenant_role")})
+	})
+
+	sign := func(kid, secret string, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		token.Header["kid"] = kid
+		signed, _ := token.SignedString([]byte(secret))
+		return signed
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{"current key", sign("current", "new-secret", jwt.MapClaims{"user_id": "key:k1", "tenant_role": "viewer", "typ": "access"}), http.StatusOK},
+		{"rotated key", sign("old", "old-secret", jwt.MapClaims{"user_id": "key:k1", "tenant_role": "viewer"}), http.StatusOK},
+		{"wrong secret for key", sign("old", "new-secret", jwt.MapClaims{"user_id": "key:k1"}), http.StatusUnauthorized},
+		{"unknown key", sign("retired", "retired-secret", jwt.MapClaims{"user_id": "key:k1"}), http.StatusUnauthorized},
+		{"refresh token", sign("current", "new-secret", jwt.MapClaims{"user_id": "key:k1", "typ": "refresh"}), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, `{"user_id":"key:k1","tenant_role":"viewer"}`, w.Body.String())
+			}
+		})
+	}
+}
+
+type fakeProvider map[string]jwt.MapClaims
+
+func (f fakeProvider) Verify(ctx context.Context, token string) (jwt.MapClaims, error) {
+	if claims, ok := f[token]; ok {
+		return claims, nil
+	}
+	return nil, apperrors.New(apperrors.Unauthenticated, "invalid token")
+}
+
+func TestAuthWithProvider(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	providerToken, _ := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "alice"}).SignedString(rsaKey)
+	forgedToken, _ := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "mallory"}).SignedString(rsaKey)
+	gatewayToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "key:k1"}).SignedString([]byte("test-secret"))
+
+	provider := fakeProvider{providerToken: {"user_id": "alice", "tenant_id": "acme", "role": "admin"}}
+	router := gin.New()
+	router.Use(AuthWithProvider(keyring{"": "test-secret"}, provider, nil))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetString("user_id"), "tenant": c.GetString("tenant"), "role": c.GetString("role")})
+	})
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantBody   string
+	}{
+		{"provider token", providerToken, http.StatusOK, `{"user_id":"alice","tenant":"acme","role":"admin"}`},
+		{"gateway token", gatewayToken, http.StatusOK, `{"user_id":"key:k1","tenant":"","role":""}`},
+		{"rejected by provider", forgedToken, http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
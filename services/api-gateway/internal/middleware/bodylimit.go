@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// BodyLimits bound the size of request bodies
+type BodyLimits struct {
+	// Default applies to routes without their own limit; zero is unlimited
+	Default int64
+	// Routes maps route patterns, such as "/v1/batch", to their limits
+	Routes map[string]int64
+}
+
+// limit returns the limit of a route
+func (l BodyLimits) limit(route string) int64 {
+	if limit, ok := l.Routes[route]; ok {
+		return limit
+	}
+	return l.Default
+}
+
+// BodyLimit rejects request bodies over the route's limit with 413. Bodies
+// that declare their length are rejected before they are read; others are cut
+// off at the limit, and handlers that read them answer through
+// AbortBodyTooLarge.
+func BodyLimit(limits BodyLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := limits.limit(c.FullPath())
+		if limit <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			writeBodyTooLarge(c, limit)
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// AbortBodyTooLarge reports whether err came from reading a body past its
+// limit, and if so answers the request with 413
+func AbortBodyTooLarge(c *gin.Context, err error) bool {
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) {
+		return false
+	}
+	writeBodyTooLarge(c, tooLarge.Limit)
+	c.Abort()
+	return true
+}
+
+func writeBodyTooLarge(c *gin.Context, limit int64) {
+	_, body := apperrors.Problem(c.Request.Context(), apperrors.New(apperrors.InvalidArgument, "request body too large"))
+	body.Status = http.StatusRequestEntityTooLarge
+	apperrors.WriteBody(c.Writer, body.Status, body.With(map[string]interface{}{
+		"limit_bytes": limit,
+	}))
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func bodyLimitRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimit(BodyLimits{Default: 16, Routes: map[string]int64{"/v1/batch": 64}}))
+
+	bind := func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			if AbortBodyTooLarge(c, err) {
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			return
+		}
+		c.JSON(http.StatusOK, body)
+	}
+	router.POST("/v1/infer", bind)
+	router.POST("/v1/batch", bind)
+	return router
+}
+
+func TestBodyLimit(t *testing.T) {
+	router := bodyLimitRouter()
+	large := `{"data":"` + strings.Repeat("x", 32) + `"}`
+	larger := `{"data":"` + strings.Repeat("x", 128) + `"}`
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		chunked    bool
+		wantStatus int
+	}{
+		{"within default", "/v1/infer", `{"a":1}`, false, http.StatusOK},
+		{"declared over default", "/v1/infer", large, false, http.StatusRequestEntityTooLarge},
+		{"undeclared over default", "/v1/infer", large, true, http.StatusRequestEntityTooLarge},
+		{"within route limit", "/v1/batch", large, false, http.StatusOK},
+		{"over route limit", "/v1/batch", larger, true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader(tt.body)
+			if tt.chunked {
+				// Hide the length so the body is only cut off while it is read
+				body = io.MultiReader(body)
+			}
+			req := httptest.NewRequest("POST", tt.path, body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusRequestEntityTooLarge {
+				assert.Contains(t, w.Body.String(), `"code":"invalid_argument"`)
+				assert.Contains(t, w.Body.String(), `"limit_bytes"`)
+			}
+		})
+	}
+}
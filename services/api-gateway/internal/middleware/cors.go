@@ -1,22 +1,303 @@
 package middleware
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
-// CORS middleware handles Cross-Origin Resource Sharing
+// CORSPolicy says which browser origins may call the API and how
+type CORSPolicy struct {
+	// AllowOrigins lists origins such as "https://app.example.com". "*"
+	// allows any origin and "https://*.example.com" any subdomain of
+	// example.com.
+	AllowOrigins     []string `json:"allow_origins"`
+	AllowMethods     []string `json:"allow_methods,omitempty"`
+	AllowHeaders     []string `json:"allow_headers,omitempty"`
+	ExposeHeaders    []string `json:"expose_headers,omitempty"`
+	AllowCredentials bool     `json:"allow_credentials,omitempty"`
+	// MaxAge is how long browsers may cache a preflight answer, in seconds
+	MaxAge int `json:"max_age,omitempty"`
+}
+
+// CORSConfig is the default policy and the policies of routes that differ
+type CORSConfig struct {
+	Default CORSPolicy `json:"default"`
+	// Routes maps path prefixes, such as "/admin", to their policies; the
+	// longest matching prefix applies
+	Routes map[string]CORSPolicy `json:"routes,omitempty"`
+}
+
+// DefaultCORSConfig allows any origin, without credentials
+var DefaultCORSConfig = CORSConfig{
+	Default: CORSPolicy{
+		AllowOrigins: []string{"*"},
+		AllowMethods: []string{"POST", "OPTIONS", "GET", "PUT", "DELETE"},
+		AllowHeaders: []string{"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "accept", "origin", "Cache-Control", "X-Requested-With", "X-Priority"},
+	},
+}
+
+// CORSPolicies holds the CORS configuration; it can be replaced at runtime
+type CORSPolicies struct {
+	config atomic.Value
+	logger *zap.Logger
+}
+
+// NewCORSPolicies creates policies starting from config
+func NewCORSPolicies(config CORSConfig, logger *zap.Logger) *CORSPolicies {
+	p := &CORSPolicies{logger: logger}
+	p.config.Store(config)
+	return p
+}
+
+// CORSPoliciesFromEnv creates the default policy from CORS_ALLOWED_ORIGINS,
+// CORS_ALLOWED_METHODS and CORS_ALLOWED_HEADERS (comma separated) and
+// CORS_ALLOW_CREDENTIALS, or loads the whole configuration from
+// CORS_CONFIG_FILE (a JSON file, e.g. a mounted ConfigMap, reloaded on
+// change), falling back to DefaultCORSConfig
+func CORSPoliciesFromEnv(ctx context.Context, logger *zap.Logger) (*CORSPolicies, error) {
+	config := DefaultCORSConfig
+	if origins := os.Getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
+		config.Default.AllowOrigins = splitList(origins)
+	}
+	if methods := os.Getenv("CORS_ALLOWED_METHODS"); methods != "" {
+		config.Default.AllowMethods = splitList(methods)
+	}
+	if headers := os.Getenv("CORS_ALLOWED_HEADERS"); headers != "" {
+		config.Default.AllowHeaders = splitList(headers)
+	}
+	if credentials := os.Getenv("CORS_ALLOW_CREDENTIALS"); credentials != "" {
+		allow, err := strconv.ParseBool(credentials)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS %q", credentials)
+		}
+		config.Default.AllowCredentials = allow
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	p := NewCORSPolicies(config, logger)
+
+	if path := os.Getenv("CORS_CONFIG_FILE"); path != "" {
+		if err := p.Watch(ctx, path, 10*time.Second); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// ParseCORSConfig parses and validates a JSON CORS configuration. Route
+// policies leaving methods or headers empty take the default policy's.
+func ParseCORSConfig(data []byte) (CORSConfig, error) {
+	var config CORSConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("invalid cors config: %w", err)
+	}
+	for prefix, policy := range config.Routes {
+		if len(policy.AllowMethods) == 0 {
+			policy.AllowMethods = config.Default.AllowMethods
+		}
+		if len(policy.AllowHeaders) == 0 {
+			policy.AllowHeaders = config.Default.AllowHeaders
+		}
+		config.Routes[prefix] = policy
+	}
+	return config, config.validate()
+}
+
+func (c CORSConfig) validate() error {
+	policies := map[string]CORSPolicy{"default": c.Default}
+	for prefix, policy := range c.Routes {
+		policies[prefix] = policy
+	}
+	for name, policy := range policies {
+		for _, origin := range policy.AllowOrigins {
+			if origin != "*" && !strings.Contains(origin, "://") {
+				return fmt.Errorf("invalid cors config: %s allows origin %q without a scheme", name, origin)
+			}
+		}
+		if policy.MaxAge < 0 {
+			return fmt.Errorf("invalid cors config: %s has a negative max age", name)
+		}
+	}
+	return nil
+}
+
+// Set replaces the configuration. It must already be validated.
+func (p *CORSPolicies) Set(config CORSConfig) {
+	p.config.Store(config)
+	p.logger.Info("cors policies loaded", zap.Int("routes", len(config.Routes)))
+}
+
+// Lookup returns the policy of a request path
+func (p *CORSPolicies) Lookup(path string) CORSPolicy {
+	config := p.config.Load().(CORSConfig)
+	policy, longest := config.Default, -1
+	for prefix, routePolicy := range config.Routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			policy, longest = routePolicy, len(prefix)
+		}
+	}
+	return policy
+}
+
+// Watch loads the configuration from path and reloads it whenever the file
+// changes. A file that fails to parse leaves the previous configuration in
+// place.
+func (p *CORSPolicies) Watch(ctx context.Context, path string, interval time.Duration) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read cors config: %w", err)
+	}
+	if err := p.load(path); err != nil {
+		return err
+	}
+
+	go func() {
+		modTime := info.ModTime()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Equal(modTime) {
+				continue
+			}
+			modTime = info.ModTime()
+			if err := p.load(path); err != nil {
+				p.logger.Error("failed to reload cors config", zap.String("path", path), zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+func (p *CORSPolicies) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read cors config: %w", err)
+	}
+	config, err := ParseCORSConfig(data)
+	if err != nil {
+		return err
+	}
+	p.Set(config)
+	return nil
+}
+
+// CORS middleware handles Cross-Origin Resource Sharing for any origin
 func CORS() gin.HandlerFunc {
+	return CORSWithPolicies(NewCORSPolicies(DefaultCORSConfig, zap.NewNop()))
+}
+
+// CORSWithPolicies answers cross-origin requests from the origins the path's
+// policy allows. Other origins get no CORS headers, so browsers block them,
+// and their preflight requests are refused with 403.
+func CORSWithPolicies(policies *CORSPolicies) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		origin := c.Request.Header.Get("Origin")
+		preflight := c.Request.Method == http.MethodOptions
+		if origin == "" {
+			if preflight {
+				c.AbortWithStatus(http.StatusNoContent)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		policy := policies.Lookup(c.Request.URL.Path)
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		allowed, wildcard := policy.allows(origin)
+		if !allowed {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// Credentials are only shared with origins allowed by name
+		if wildcard {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+			if policy.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+		header.Set("Access-Control-Allow-Headers", strings.Join(policy.AllowHeaders, ", "))
+		header.Set("Access-Control-Allow-Methods", strings.Join(policy.AllowMethods, ", "))
+		if len(policy.ExposeHeaders) > 0 {
+			header.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposeHeaders, ", "))
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if preflight {
+			if policy.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(policy.MaxAge))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
 		c.Next()
 	}
 }
+
+// allows reports whether the policy allows origin, and whether only because
+// it allows any origin
+func (p CORSPolicy) allows(origin string) (allowed, wildcard bool) {
+	for _, pattern := range p.AllowOrigins {
+		if pattern == origin || matchesSubdomain(pattern, origin) {
+			return true, false
+		}
+	}
+	for _, pattern := range p.AllowOrigins {
+		if pattern == "*" {
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// matchesSubdomain reports whether origin is a subdomain matched by a
+// pattern such as "https://*.example.com"
+func matchesSubdomain(pattern, origin string) bool {
+	scheme, host, ok := strings.Cut(pattern, "://")
+	if !ok || !strings.HasPrefix(host, "*.") {
+		return false
+	}
+	rest, ok := strings.CutPrefix(origin, scheme+"://")
+	if !ok {
+		return false
+	}
+	subdomain, ok := strings.CutSuffix(rest, host[1:])
+	return ok && subdomain != "" && !strings.ContainsAny(subdomain, "/:")
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestCORS_Headers(t *testing.T) {
@@ -50,3 +52,74 @@ func TestCORS_PreflightRequest(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, w.Code)
 	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
 }
+
+func TestCORSWithPolicies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config, err := ParseCORSConfig([]byte(`{
+		"default": {"allow_origins": ["https://app.example.com", "https://*.preview.example.com"], "allow_methods": ["GET", "POST"], "allow_headers": ["Authorization"], "allow_credentials": true, "max_age": 600},
+		"routes": {"/admin": {"allow_origins": ["https://ops.example.com"]}}
+	}`))
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(CORSWithPolicies(NewCORSPolicies(config, zap.NewNop())))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.POST("/v1/infer", ok)
+	router.GET("/admin/overview", ok)
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		origin          string
+		wantStatus      int
+		wantOrigin      string
+		wantCredentials string
+	}{
+		{"allowed origin", "POST", "/v1/infer", "https://app.example.com", http.StatusOK, "https://app.example.com", "true"},
+		{"wildcard subdomain", "POST", "/v1/infer", "https://pr-42.preview.example.com", http.StatusOK, "https://pr-42.preview.example.com", "true"},
+		{"wildcard needs a subdomain", "POST", "/v1/infer", "https://preview.example.com", http.StatusOK, "", ""},
+		{"wildcard keeps the scheme", "POST", "/v1/infer", "http://pr-42.preview.example.com", http.StatusOK, "", ""},
+		{"unknown origin", "POST", "/v1/infer", "https://evil.example.net", http.StatusOK, "", ""},
+		{"unknown origin preflight", "OPTIONS", "/v1/infer", "https://evil.example.net", http.StatusForbidden, "", ""},
+		{"allowed preflight", "OPTIONS", "/v1/infer", "https://app.example.com", http.StatusNoContent, "https://app.example.com", "true"},
+		{"route override", "GET", "/admin/overview", "https://ops.example.com", http.StatusOK, "https://ops.example.com", ""},
+		{"route override replaces origins", "GET", "/admin/overview", "https://app.example.com", http.StatusOK, "", ""},
+		{"same origin", "POST", "/v1/infer", "", http.StatusOK, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.method == "OPTIONS" {
+				req.Header.Set("Access-Control-Request-Method", "POST")
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tt.wantCredentials, w.Header().Get("Access-Control-Allow-Credentials"))
+			if tt.wantStatus == http.StatusNoContent {
+				assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+				assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+			}
+		})
+	}
+}
+
+func TestParseCORSConfig(t *testing.T) {
+	config, err := ParseCORSConfig([]byte(`{"default": {"allow_origins": ["*"], "allow_methods": ["GET"]}, "routes": {"/admin": {"allow_origins": ["https://ops.example.com"]}}}`))
+	require.NoError(t, err)
+	// Route policies inherit what they leave out
+	assert.Equal(t, []string{"GET"}, config.Routes["/admin"].AllowMethods)
+
+	_, err = ParseCORSConfig([]byte(`{"default": {"allow_origins": ["app.example.com"]}}`))
+	assert.Error(t, err)
+	_, err = ParseCORSConfig([]byte(`{"default": {"allow_origins": ["*"], "max_age": -1}}`))
+	assert.Error(t, err)
+}
@@ -5,6 +5,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
 )
 
 // Logger middleware logs HTTP requests
@@ -19,7 +22,8 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 		latency := time.Since(start)
 		statusCode := c.Writer.Status()
 
-		logger.Info("http request",
+		// Read the context after c.Next so fields added by later middleware are included
+		logging.With(c.Request.Context(), logger).Info("http request",
 			zap.String("method", c.Request.Method),
 			zap.String("path", path),
 			zap.String("query", query),
@@ -36,11 +40,12 @@ func Recovery(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				logger.Error("panic recovered",
+				logging.With(c.Request.Context(), logger).Error("panic recovered",
 					zap.Any("error", err),
 					zap.String("path", c.Request.URL.Path),
 				)
-				c.JSON(500, gin.H{"error": "internal server error"})
+				apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.Internal, "internal server error"))
+				c.Abort()
 			}
 		}()
 		c.Next()
@@ -0,0 +1,250 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/inferencepb"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// ModelRateLimitsKey is the Redis hash of per-model overrides: each field is
+// a model name and its value the model's RateTier as JSON
+const ModelRateLimitsKey = "ratelimit:models"
+
+// ModelAllowanceKey is the context key ModelRateLimit stores the caller's
+// ModelAllowance under
+const ModelAllowanceKey = "model_rate_allowance"
+
+// ModelAllowance counts one more request for a model against the caller's
+// budget for it and reports whether it is within it, like Allowance
+type ModelAllowance func(ctx context.Context, model string) bool
+
+// ModelRateLimits holds the rate limit of each model that has one: the
+// configured limits, overridden by those in Redis
+type ModelRateLimits struct {
+	configured  map[string]RateTier
+	limits      atomic.Value
+	redisClient *redis.Client
+	logger      *zap.Logger
+}
+
+// NewModelRateLimits creates limits starting from configured
+func NewModelRateLimits(configured map[string]RateTier, redisClient *redis.Client, logger *zap.Logger) *ModelRateLimits {
+	m := &ModelRateLimits{configured: configured, redisClient: redisClient, logger: logger}
+	m.limits.Store(configured)
+	return m
+}
+
+// ParseModelRateLimits parses and validates a JSON object mapping models to
+// their limits. An empty document has none.
+func ParseModelRateLimits(data []byte) (map[string]RateTier, error) {
+	limits := map[string]RateTier{}
+	if len(data) == 0 {
+		return limits, nil
+	}
+	if err := json.Unmarshal(data, &limits); err != nil {
+		return nil, fmt.Errorf("invalid model rate limits: %w", err)
+	}
+	for model, limit := range limits {
+		if limit.RequestsPerMinute < 0 || limit.Burst < 0 {
+			return nil, fmt.Errorf("invalid model rate limits: %s has a negative limit", model)
+		}
+	}
+	return limits, nil
+}
+
+// Lookup returns the limit of model, reporting whether it has one
+func (m *ModelRateLimits) Lookup(model string) (RateTier, bool) {
+	limit, ok := m.limits.Load().(map[string]RateTier)[model]
+	return limit, ok && (limit.RequestsPerMinute > 0 || limit.Burst > 0)
+}
+
+// Refresh reads the overrides from Redis. Overrides that fail to parse are
+// skipped; if Redis is down the previous limits stay in place.
+func (m *ModelRateLimits) Refresh(ctx context.Context) error {
+	overrides, err := m.redisClient.HGetAll(ctx, ModelRateLimitsKey).Result()
+	if err != nil {
+		return err
+	}
+	m.override(overrides)
+	return nil
+}
+
+// Run refreshes the overrides every interval until ctx is cancelled
+func (m *ModelRateLimits) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := m.Refresh(ctx); err != nil && ctx.Err() == nil {
+			m.logger.Warn("failed to refresh model rate limits", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// override replaces the overrides with those read from the Redis hash
+func (m *ModelRateLimits) override(overrides map[string]string) {
+	limits := make(map[string]RateTier, len(m.configured)+len(overrides))
+	for model, limit := range m.configured {
+		limits[model] = limit
+	}
+	for model, value := range overrides {
+		var limit RateTier
+		if err := json.Unmarshal([]byte(value), &limit); err != nil || limit.RequestsPerMinute < 0 || limit.Burst < 0 {
+			m.logger.Warn("skipping invalid model rate limit", zap.String("model", model), zap.String("value", value))
+			continue
+		}
+		limits[model] = limit
+	}
+	m.limits.Store(limits)
+}
+
+// ModelRateLimit limits each caller's requests for each model that has a
+// limit, on top of the caller's overall rate limit. Models are read from the
+// request body's model, or its targets' models for fan-outs; each target
+// counts as one request for its model. Sessions check each request through
+// the ModelAllowance stored under ModelAllowanceKey.
+func ModelRateLimit(limiter *RateLimiter, limits *ModelRateLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := callerKey(c)
+		spendModel := func(ctx context.Context, model string) (time.Duration, error) {
+			limit, ok := limits.Lookup(model)
+			if !ok {
+				return 0, nil
+			}
+			_, retryAfter, err := limiter.spend(ctx, budget{
+				key:   key + ":model:" + model,
+				limit: limit.RequestsPerMinute,
+				burst: limit.Burst,
+			}, time.Minute)
+			return retryAfter, err
+		}
+		c.Set(ModelAllowanceKey, ModelAllowance(func(ctx context.Context, model string) bool {
+			retryAfter, err := spendModel(ctx, model)
+			return err == nil && retryAfter == 0
+		}))
+
+		models, err := requestModels(c)
+		if err != nil {
+			if !AbortBodyTooLarge(c, err) {
+				apperrors.Write(c.Writer, c.Request, apperrors.Wrap(err, apperrors.InvalidArgument, "failed to read request"))
+				c.Abort()
+			}
+			return
+		}
+		for _, model := range models {
+			retryAfter, err := spendModel(c.Request.Context(), model)
+			if err != nil {
+				c.Header("Retry-After", "1")
+				apperrors.Write(c.Writer, c.Request, err)
+				c.Abort()
+				return
+			}
+			if retryAfter > 0 {
+				c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(retryAfter).Unix()))
+				status, body := apperrors.Problem(c.Request.Context(), apperrors.Newf(apperrors.ResourceExhausted, "rate limit exceeded for model %s", model))
+				apperrors.WriteBody(c.Writer, status, body.With(map[string]interface{}{
+					"retry_after": retryAfter.Seconds(),
+					"model":       model,
+				}))
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// requestModels reads the models a request body asks for, leaving the body
+// in place for the handler. Bodies that are not JSON ask for none; the
+// handler rejects them.
+func requestModels(c *gin.Context) ([]string, error) {
+	if c.Request.Body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(c.Request.Body)
+	c.Request.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	switch contentType := c.ContentType(); {
+	case contentType == inferencepb.ContentType:
+		var req inferencepb.InferenceRequest
+		if proto.Unmarshal(data, &req) != nil || req.Model == "" {
+			return nil, nil
+		}
+		return []string{req.Model}, nil
+	case contentType == "multipart/form-data":
+		return multipartModel(c, data), nil
+	case strings.HasPrefix(contentType, "image/") || strings.HasPrefix(contentType, "audio/"):
+		// Raw binary inputs name their model in the query
+		if model := c.Query("model"); model != "" {
+			return []string{model}, nil
+		}
+		return nil, nil
+	}
+
+	var body struct {
+		Model   string `json:"model"`
+		Targets []struct {
+			Model string `json:"model"`
+		} `json:"targets"`
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return nil, nil
+	}
+	var models []string
+	if body.Model != "" {
+		models = append(models, body.Model)
+	}
+	for _, target := range body.Targets {
+		if target.Model != "" {
+			models = append(models, target.Model)
+		}
+	}
+	return models, nil
+}
+
+// multipartModel returns the model field of a multipart form
+func multipartModel(c *gin.Context, data []byte) []string {
+	_, params, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil {
+		return nil
+	}
+	reader := multipart.NewReader(bytes.NewReader(data), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil
+		}
+		if part.FormName() == "model" {
+			model, err := io.ReadAll(part)
+			if err != nil || len(model) == 0 {
+				return nil
+			}
+			return []string{string(model)}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/inferencepb"
+)
+
+func TestParseModelRateLimits(t *testing.T) {
+	limits, err := ParseModelRateLimits([]byte(`{"llama-70b": {"requests_per_minute": 10, "burst": 2}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]RateTier{"llama-70b": {RequestsPerMinute: 10, Burst: 2}}, limits)
+
+	limits, err = ParseModelRateLimits(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, limits)
+
+	_, err = ParseModelRateLimits([]byte(`{"llama-70b": {"requests_per_minute": -1}}`))
+	assert.Error(t, err)
+	_, err = ParseModelRateLimits([]byte(`["llama-70b"]`))
+	assert.Error(t, err)
+}
+
+func TestModelRateLimits_Overrides(t *testing.T) {
+	limits := NewModelRateLimits(map[string]RateTier{
+		"llama-70b": {RequestsPerMinute: 10},
+		"bert":      {RequestsPerMinute: 1000},
+	}, nil, zap.NewNop())
+
+	limit, ok := limits.Lookup("llama-70b")
+	assert.True(t, ok)
+	assert.Equal(t, 10, limit.RequestsPerMinute)
+	_, ok = limits.Lookup("resnet18")
+	assert.False(t, ok)
+
+	limits.override(map[string]string{
+		"llama-70b": `{"requests_per_minute": 5, "burst": 1}`,
+		"resnet18":  `{"requests_per_minute": 100}`,
+		"whisper":   `not json`,
+		"bert":      `{"requests_per_minute": 0}`,
+	})
+	limit, _ = limits.Lookup("llama-70b")
+	assert.Equal(t, RateTier{RequestsPerMinute: 5, Burst: 1}, limit)
+	limit, _ = limits.Lookup("resnet18")
+	assert.Equal(t, 100, limit.RequestsPerMinute)
+	_, ok = limits.Lookup("whisper")
+	assert.False(t, ok)
+	// A zero override lifts the configured limit
+	_, ok = limits.Lookup("bert")
+	assert.False(t, ok)
+
+	// Overrides removed from Redis fall back to the configured limits
+	limits.override(nil)
+	limit, _ = limits.Lookup("llama-70b")
+	assert.Equal(t, 10, limit.RequestsPerMinute)
+}
+
+func TestRequestModels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	protobuf, err := proto.Marshal(&inferencepb.InferenceRequest{Model: "resnet18"})
+	require.NoError(t, err)
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	writer.WriteField("model", "resnet18")
+	part, _ := writer.CreateFormFile("image", "cat.png")
+	part.Write([]byte("\x89PNG"))
+	writer.Close()
+
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+		want        []string
+	}{
+		{"inference", "/v1/infer", "application/json", `{"model": "resnet18", "input": {}}`, []string{"resnet18"}},
+		{"fanout", "/v1/infer/fanout", "application/json", `{"targets": [{"model": "resnet18"}, {"model": "llama-70b", "version": "v2"}], "input": {}}`, []string{"resnet18", "llama-70b"}},
+		{"not json", "/v1/infer", "application/json", `model=resnet18`, nil},
+		{"protobuf", "/v1/infer", inferencepb.ContentType, string(protobuf), []string{"resnet18"}},
+		{"multipart", "/v1/infer", writer.FormDataContentType(), form.String(), []string{"resnet18"}},
+		{"binary", "/v1/infer?model=resnet18", "image/png", "\x89PNG", []string{"resnet18"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+
+			models, err := requestModels(c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, models)
+
+			// The handler still reads the whole body
+			body, _ := io.ReadAll(c.Request.Body)
+			assert.Equal(t, tt.body, string(body))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+// RequestPriority rejects requests whose X-Priority header is not one of
+// high, normal or low. Accepted values are already on the request's context,
+// put there by the logging middleware, and travel with it to the router and
+// orchestrators, which serve high-priority requests ahead of bulk traffic.
+func RequestPriority() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		priority := c.GetHeader(logging.HeaderPriority)
+		if priority == "" {
+			c.Next()
+			return
+		}
+		for _, valid := range schema.Priorities {
+			if priority == valid {
+				c.Next()
+				return
+			}
+		}
+		apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.InvalidArgument,
+			"invalid %s %q; use high, normal or low", logging.HeaderPriority, priority))
+		c.Abort()
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+func TestRequestPriority(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var forwarded string
+	router := gin.New()
+	router.Use(RequestPriority())
+	router.POST("/v1/infer", func(c *gin.Context) {
+		forwarded = logging.Headers(c.Request.Context())[logging.HeaderPriority]
+		c.Status(http.StatusOK)
+	})
+
+	tests := map[string]int{
+		"":       http.StatusOK,
+		"high":   http.StatusOK,
+		"low":    http.StatusOK,
+		"urgent": http.StatusBadRequest,
+	}
+	for priority, want := range tests {
+		forwarded = ""
+		req := httptest.NewRequest("POST", "/v1/infer", nil)
+		if priority != "" {
+			req.Header.Set(logging.HeaderPriority, priority)
+		}
+		w := httptest.NewRecorder()
+		logging.Middleware(router).ServeHTTP(w, req)
+
+		assert.Equal(t, want, w.Code, priority)
+		if want == http.StatusOK {
+			assert.Equal(t, priority, forwarded)
+		}
+	}
+}
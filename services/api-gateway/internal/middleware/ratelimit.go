@@ -3,57 +3,190 @@ package middleware
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
 )
 
-// RateLimit implements token bucket rate limiting using Redis
-func RateLimit(redisClient *redis.Client, limit int, window time.Duration) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Get user ID from context (set by Auth middleware)
-		userID, exists := c.Get("user_id")
-		if !exists {
-			userID = c.ClientIP() // Fallback to IP if no user ID
+// AllowanceKey is the context key rate limiting stores the caller's Allowance
+// under; it is unset for callers that are not throttled
+const AllowanceKey = "rate_allowance"
+
+// Allowance counts one more request against the caller's budget and reports
+// whether it is within it. Sessions that carry many requests over one
+// connection use it so that each request is throttled like its own call.
+type Allowance func(ctx context.Context) bool
+
+// RateLimit allows each caller limit requests in any window, using Redis
+func RateLimit(limiter *RateLimiter, limit int, window time.Duration) gin.HandlerFunc {
+	return rateLimit(limiter, window, func(c *gin.Context) budget {
+		return budget{key: callerKey(c), limit: limit}
+	})
+}
+
+// PlanRateLimit limits each caller by the rate tier of their plan, the "plan"
+// claim of their token; callers without a known plan get the default plan's.
+func PlanRateLimit(limiter *RateLimiter, tiers *RateTiers) gin.HandlerFunc {
+	return rateLimit(limiter, time.Minute, func(c *gin.Context) budget {
+		tier := tiers.Lookup(c.GetString("plan"))
+		return budget{key: callerKey(c), limit: tier.RequestsPerMinute, burst: tier.Burst}
+	})
+}
+
+// TenantRateLimit gives each tenant one per-minute budget shared by its users
+// and keys, sized by the limits Tenants resolved; tenants without a limit are
+// not throttled. Bursts are bounded by the rate tier of the tenant's tier.
+// Callers without a tenant are limited by their plan like PlanRateLimit.
+func TenantRateLimit(limiter *RateLimiter, tiers *RateTiers) gin.HandlerFunc {
+	return rateLimit(limiter, time.Minute, func(c *gin.Context) budget {
+		tier := tiers.Lookup(c.GetString("plan"))
+		limits, ok := c.Get("tenant_limits")
+		tenant := c.GetString("tenant")
+		if !ok || tenant == "" {
+			return budget{key: callerKey(c), limit: tier.RequestsPerMinute, burst: tier.Burst}
 		}
+		return budget{key: "ratelimit:tenant:" + tenant, limit: limits.(tenancy.Limits).RequestsPerMinute, burst: tier.Burst}
+	})
+}
 
-		key := fmt.Sprintf("ratelimit:%v", userID)
-		ctx := context.Background()
+// callerKey counts requests per user, or per client IP before authentication
+func callerKey(c *gin.Context) string {
+	// Get user ID from context (set by Auth middleware)
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = c.ClientIP() // Fallback to IP if no user ID
+	}
+	return fmt.Sprintf("ratelimit:%v", userID)
+}
 
-		// Increment counter
-		count, err := redisClient.Incr(ctx, key).Result()
-		if err != nil {
-			// If Redis is down, allow the request (fail open)
+// budget is what a caller may spend: limit requests per window counted under
+// key and, when burst is set, burst requests per second. Zero values are
+// unlimited.
+type budget struct {
+	key   string
+	limit int
+	burst int
+}
+
+// rateLimit counts requests per key in sliding windows
+func rateLimit(limiter *RateLimiter, window time.Duration, budgetOf func(c *gin.Context) budget) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		b := budgetOf(c)
+		if b.limit <= 0 && b.burst <= 0 {
 			c.Next()
 			return
 		}
+		c.Set(AllowanceKey, Allowance(func(ctx context.Context) bool {
+			_, retryAfter, err := limiter.spend(ctx, b, window)
+			return err == nil && retryAfter == 0
+		}))
 
-		// Set expiry on first request
-		if count == 1 {
-			redisClient.Expire(ctx, key, window)
+		remaining, retryAfter, err := limiter.spend(context.Background(), b, window)
+		if err != nil {
+			c.Header("Retry-After", "1")
+			apperrors.Write(c.Writer, c.Request, err)
+			c.Abort()
+			return
 		}
 
 		// Check if limit exceeded
-		if count > int64(limit) {
-			c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", limit))
+		if retryAfter > 0 {
+			if b.limit > 0 {
+				c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", b.limit))
+			}
 			c.Header("X-RateLimit-Remaining", "0")
-			c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(window).Unix()))
-			
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": "rate limit exceeded",
-				"retry_after": window.Seconds(),
-			})
+			c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(retryAfter).Unix()))
+
+			status, body := apperrors.Problem(c.Request.Context(), apperrors.New(apperrors.ResourceExhausted, "rate limit exceeded"))
+			apperrors.WriteBody(c.Writer, status, body.With(map[string]interface{}{
+				"retry_after": retryAfter.Seconds(),
+			}))
 			c.Abort()
 			return
 		}
 
 		// Set rate limit headers
-		remaining := limit - int(count)
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", limit))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+		if remaining >= 0 {
+			c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", b.limit))
+			c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+		}
 
 		c.Next()
 	}
 }
+
+// slidingWindow admits a request if every limit allows it, counting it
+// against all of them, atomically so concurrent gateways cannot overspend.
+// Each limit estimates the requests of the last window from the current and
+// previous fixed windows, weighting the previous one by how much of it the
+// sliding window still covers, so requests bunched around a window boundary
+// cannot double the limit. Rejected requests are not counted.
+//
+// KEYS are the current and previous window counters of each limit, in pairs.
+// ARGV are each limit's request limit, window length and time elapsed in the
+// current window, in milliseconds. It returns how long to wait before a
+// request would be admitted, 0 when it was, and the requests the first limit
+// has left.
+var slidingWindow = redis.NewScript(`
+local wait, remaining = 0, 0
+for i = 1, #KEYS / 2 do
+	local limit = tonumber(ARGV[3 * i - 2])
+	local window = tonumber(ARGV[3 * i - 1])
+	local elapsed = tonumber(ARGV[3 * i])
+	local current = tonumber(redis.call('GET', KEYS[2 * i - 1]) or '0')
+	local previous = tonumber(redis.call('GET', KEYS[2 * i]) or '0')
+	local count = previous * (window - elapsed) / window + current
+
+	if count + 1 > limit then
+		local until_admitted
+		if current + 1 > limit then
+			-- Wait for the next window, then until enough of this one slides out
+			until_admitted = window - elapsed + math.max(0, window - (limit - 1) * window / current)
+		else
+			-- Wait until enough of the previous window slides out
+			until_admitted = window - elapsed - (limit - current - 1) * window / previous
+		end
+		wait = math.max(wait, math.ceil(until_admitted))
+	end
+	if i == 1 then
+		remaining = math.max(0, math.floor(limit - count - 1))
+	end
+end
+if wait > 0 then
+	return {wait, 0}
+end
+
+for i = 1, #KEYS / 2 do
+	redis.call('INCR', KEYS[2 * i - 1])
+	redis.call('PEXPIRE', KEYS[2 * i - 1], 2 * tonumber(ARGV[3 * i - 1]))
+end
+return {0, remaining}
+`)
+
+// spendRedis counts a request against each window with slidingWindow,
+// returning the requests the first has left and how long to wait when the
+// request is over a limit
+func (l *RateLimiter) spendRedis(ctx context.Context, windows []limitWindow, now time.Time) (int64, time.Duration, error) {
+	var keys []string
+	var args []interface{}
+	for _, window := range windows {
+		size := window.size.Milliseconds()
+		index := now.UnixMilli() / size
+		keys = append(keys, window.key+":"+strconv.FormatInt(index, 10), window.key+":"+strconv.FormatInt(index-1, 10))
+		args = append(args, window.limit, size, now.UnixMilli()-index*size)
+	}
+
+	result, err := slidingWindow.Run(ctx, l.redis, keys, args...).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(result) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rate limit result %v", result)
+	}
+	return result[1], time.Duration(result[0]) * time.Millisecond, nil
+}
@@ -2,8 +2,10 @@ package middleware
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockRedisClient is a mock Redis client for testing
@@ -47,7 +50,7 @@ func TestRateLimit_WithinLimit(t *testing.T) {
 	})
 
 	router := gin.New()
-	router.Use(RateLimit(client, 10, time.Minute))
+	router.Use(RateLimit(NewRateLimiter(client, FailOpen), 10, time.Minute))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
@@ -72,7 +75,7 @@ func TestRateLimit_Headers(t *testing.T) {
 	})
 
 	router := gin.New()
-	router.Use(RateLimit(client, 100, time.Minute))
+	router.Use(RateLimit(NewRateLimiter(client, FailOpen), 100, time.Minute))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
@@ -96,7 +99,7 @@ func TestRateLimit_DifferentIPs(t *testing.T) {
 	})
 
 	router := gin.New()
-	router.Use(RateLimit(client, 5, time.Minute))
+	router.Use(RateLimit(NewRateLimiter(client, FailOpen), 5, time.Minute))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
@@ -117,3 +120,128 @@ func TestRateLimit_DifferentIPs(t *testing.T) {
 
 	// Both should succeed (different rate limit buckets)
 }
+
+// testLimiter counts in a local Redis, skipping the test when none is running
+func testLimiter(t *testing.T) (*RateLimiter, *redis.Client) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not available:", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return NewRateLimiter(client, FailOpen), client
+}
+
+// unreachableLimiter counts in a Redis that is down
+func unreachableLimiter(failure FailurePolicy) *RateLimiter {
+	return NewRateLimiter(redis.NewClient(&redis.Options{Addr: "localhost:1", MaxRetries: -1}), failure)
+}
+
+func TestSpend_SlidingWindow(t *testing.T) {
+	limiter, client := testLimiter(t)
+	ctx := context.Background()
+	b := budget{key: fmt.Sprintf("ratelimit:test-%d", time.Now().UnixNano()), limit: 3}
+
+	for want := int64(2); want >= 0; want-- {
+		remaining, retryAfter, err := limiter.spend(ctx, b, time.Minute)
+		require.NoError(t, err)
+		assert.Zero(t, retryAfter)
+		assert.Equal(t, want, remaining)
+	}
+
+	// Rejected requests wait for the window to slide and are not counted
+	for i := 0; i < 2; i++ {
+		_, retryAfter, err := limiter.spend(ctx, b, time.Minute)
+		require.NoError(t, err)
+		assert.Positive(t, retryAfter)
+		assert.LessOrEqual(t, retryAfter, 2*time.Minute)
+	}
+	current, err := client.Get(ctx, b.key+":"+strconv.FormatInt(time.Now().UnixMilli()/time.Minute.Milliseconds(), 10)).Int()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, current, 3)
+}
+
+func TestSpend_PreviousWindowCounts(t *testing.T) {
+	limiter, client := testLimiter(t)
+	ctx := context.Background()
+	b := budget{key: fmt.Sprintf("ratelimit:test-%d", time.Now().UnixNano()), limit: 4}
+
+	// A full previous window still weighs on the start of this one, so
+	// callers cannot spend two windows' budget around the boundary
+	index := time.Now().UnixMilli() / time.Hour.Milliseconds()
+	client.Set(ctx, b.key+":"+strconv.FormatInt(index-1, 10), 4, time.Hour)
+
+	_, retryAfter, err := limiter.spend(ctx, b, time.Hour)
+	require.NoError(t, err)
+	assert.Positive(t, retryAfter)
+}
+
+func TestSpend_Burst(t *testing.T) {
+	limiter, _ := testLimiter(t)
+	b := budget{key: fmt.Sprintf("ratelimit:test-%d", time.Now().UnixNano()), limit: 100, burst: 2}
+
+	var rejected int
+	for i := 0; i < 4; i++ {
+		_, retryAfter, err := limiter.spend(context.Background(), b, time.Minute)
+		require.NoError(t, err)
+		if retryAfter > 0 {
+			assert.LessOrEqual(t, retryAfter, 2*time.Second)
+			rejected++
+		}
+	}
+	assert.Equal(t, 2, rejected)
+}
+
+func TestRateLimit_FailurePolicies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		failure FailurePolicy
+		want    []int
+	}{
+		{FailOpen, []int{http.StatusOK, http.StatusOK, http.StatusOK}},
+		{FailClosed, []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable}},
+		{FailLocal, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.failure), func(t *testing.T) {
+			router := gin.New()
+			router.Use(RateLimit(unreachableLimiter(tt.failure), 2, time.Minute))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			})
+
+			var codes []int
+			for i := 0; i < 3; i++ {
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+				codes = append(codes, w.Code)
+			}
+			assert.Equal(t, tt.want, codes)
+		})
+	}
+}
+
+func TestLocalWindows_SlidesAndForgets(t *testing.T) {
+	local := &localWindows{counts: make(map[string]*localCount)}
+	windows := []limitWindow{{key: "ratelimit:user-1", limit: 2, size: time.Minute}}
+	start := time.UnixMilli(10 * time.Minute.Milliseconds())
+
+	remaining, wait := local.spend(windows, start)
+	assert.Equal(t, int64(1), remaining)
+	assert.Zero(t, wait)
+	local.spend(windows, start)
+	_, wait = local.spend(windows, start.Add(time.Second))
+	assert.Equal(t, 89*time.Second, wait)
+
+	// Until half way into the next window, over half of the previous one
+	// still counts
+	_, wait = local.spend(windows, start.Add(89*time.Second))
+	assert.Equal(t, time.Second, wait)
+	_, wait = local.spend(windows, start.Add(90*time.Second))
+	assert.Zero(t, wait)
+
+	// Idle keys are swept away
+	local.spend([]limitWindow{{key: "ratelimit:user-2", limit: 2, size: time.Minute}}, start.Add(10*time.Minute))
+	assert.NotContains(t, local.counts, "ratelimit:user-1")
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/observability"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// FailurePolicy says how rate limits are enforced while Redis is unreachable
+type FailurePolicy string
+
+const (
+	// FailOpen lets every request through unthrottled
+	FailOpen FailurePolicy = "open"
+	// FailClosed rejects every throttled request with 503
+	FailClosed FailurePolicy = "closed"
+	// FailLocal counts requests in the gateway's memory instead. Replicas do
+	// not share their counts, so each allows callers their whole budget.
+	FailLocal FailurePolicy = "local"
+)
+
+// ParseFailurePolicy parses "open", "closed" or "local"
+func ParseFailurePolicy(value string) (FailurePolicy, error) {
+	switch policy := FailurePolicy(value); policy {
+	case FailOpen, FailClosed, FailLocal:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid rate limit failure policy %q", value)
+}
+
+var errLimiterUnavailable = apperrors.New(apperrors.Unavailable, "rate limiter unavailable")
+
+// RateLimiter counts requests against callers' budgets in Redis, falling
+// back to its failure policy when Redis cannot be reached
+type RateLimiter struct {
+	redis   *redis.Client
+	failure FailurePolicy
+	local   *localWindows
+}
+
+// NewRateLimiter creates a limiter counting in redisClient
+func NewRateLimiter(redisClient *redis.Client, failure FailurePolicy) *RateLimiter {
+	return &RateLimiter{
+		redis:   redisClient,
+		failure: failure,
+		local:   &localWindows{counts: make(map[string]*localCount)},
+	}
+}
+
+// limitWindow is one limit of a budget: limit requests in any window of size
+// counted under key
+type limitWindow struct {
+	key   string
+	limit int
+	size  time.Duration
+}
+
+// spend counts a request against b. It returns the requests left in the
+// window, or -1 when they are not known, and how long to wait when the
+// request is over budget. While Redis is unreachable the failure policy
+// decides, and FailClosed rejects the request with an Unavailable error.
+func (l *RateLimiter) spend(ctx context.Context, b budget, window time.Duration) (int64, time.Duration, error) {
+	var windows []limitWindow
+	if b.limit > 0 {
+		windows = append(windows, limitWindow{key: b.key, limit: b.limit, size: window})
+	}
+	if b.burst > 0 {
+		windows = append(windows, limitWindow{key: b.key + ":burst", limit: b.burst, size: time.Second})
+	}
+
+	now := time.Now()
+	remaining, wait, err := l.spendRedis(ctx, windows, now)
+	if err != nil {
+		decision := "allowed"
+		switch l.failure {
+		case FailClosed:
+			observability.RateLimitRedisUnavailable.WithLabelValues(string(l.failure), "rejected").Inc()
+			return -1, 0, errLimiterUnavailable
+		case FailLocal:
+			remaining, wait = l.local.spend(windows, now)
+			if wait > 0 {
+				decision = "rejected"
+			}
+		default:
+			remaining, wait = -1, 0
+		}
+		observability.RateLimitRedisUnavailable.WithLabelValues(string(l.failure), decision).Inc()
+	}
+
+	if wait > 0 || b.limit <= 0 {
+		remaining = -1
+	}
+	return remaining, wait, nil
+}
+
+// localWindows counts requests like slidingWindow, in memory
+type localWindows struct {
+	mu     sync.Mutex
+	counts map[string]*localCount
+	swept  time.Time
+}
+
+// localCount is a key's requests in its current and previous windows
+type localCount struct {
+	size     int64
+	index    int64
+	current  int64
+	previous int64
+}
+
+func (w *localWindows) spend(windows []limitWindow, now time.Time) (int64, time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sweep(now)
+
+	var remaining, wait int64
+	counts := make([]*localCount, len(windows))
+	for i, window := range windows {
+		size := window.size.Milliseconds()
+		index := now.UnixMilli() / size
+		elapsed := now.UnixMilli() - index*size
+
+		count, ok := w.counts[window.key]
+		if !ok {
+			count = &localCount{size: size, index: index}
+			w.counts[window.key] = count
+		}
+		switch count.index {
+		case index:
+		case index - 1:
+			count.previous, count.current = count.current, 0
+		default:
+			count.previous, count.current = 0, 0
+		}
+		count.index = index
+		counts[i] = count
+
+		limit := int64(window.limit)
+		estimate := float64(count.previous)*float64(size-elapsed)/float64(size) + float64(count.current)
+		if estimate+1 > float64(limit) {
+			var until float64
+			if count.current+1 > limit {
+				until = float64(size-elapsed) + math.Max(0, float64(size)-float64((limit-1)*size)/float64(count.current))
+			} else {
+				until = float64(size-elapsed) - float64((limit-count.current-1)*size)/float64(count.previous)
+			}
+			if ms := int64(math.Ceil(until)); ms > wait {
+				wait = ms
+			}
+		}
+		if i == 0 {
+			remaining = int64(math.Max(0, math.Floor(float64(limit)-estimate-1)))
+		}
+	}
+	if wait > 0 {
+		return 0, time.Duration(wait) * time.Millisecond
+	}
+
+	for _, count := range counts {
+		count.current++
+	}
+	return remaining, 0
+}
+
+// sweep forgets keys idle for two windows, at most once a minute
+func (w *localWindows) sweep(now time.Time) {
+	if now.Sub(w.swept) < time.Minute {
+		return
+	}
+	w.swept = now
+	for key, count := range w.counts {
+		if now.UnixMilli()/count.size > count.index+1 {
+			delete(w.counts, key)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+)
+
+// TenantResolver authorizes tenants and their members
+type TenantResolver interface {
+	Authorize(ctx context.Context, id string) (*tenancy.Tenant, error)
+	Member(ctx context.Context, tenantID, userID string) (*tenancy.Member, error)
+}
+
+// Tenants requires callers to act for an active tenant they belong to. API
+// keys carry their role; users are looked up as members. Viewers may only
+// read, and the tenant's limits are kept for later handlers as
+// "tenant_limits", with its tier as the caller's "plan". It must run after
+// Auth.
+func Tenants(resolver TenantResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		tenantID := c.GetString("tenant")
+		if tenantID == "" {
+			apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.PermissionDenied, "credentials do not name a tenant"))
+			c.Abort()
+			return
+		}
+
+		tenant, err := resolver.Authorize(ctx, tenantID)
+		if err != nil {
+			apperrors.Write(c.Writer, c.Request, err)
+			c.Abort()
+			return
+		}
+
+		role := tenancy.Role(c.GetString("tenant_role"))
+		if role == "" {
+			member, err := resolver.Member(ctx, tenantID, c.GetString("user_id"))
+			if err != nil {
+				apperrors.Write(c.Writer, c.Request, err)
+				c.Abort()
+				return
+			}
+			role = member.Role
+			c.Set("tenant_role", string(role))
+		}
+
+		if !role.CanWrite() && c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.PermissionDenied, "%s role is read-only", role))
+			c.Abort()
+			return
+		}
+
+		c.Set("tenant_limits", tenant.Limits)
+		c.Set("plan", string(tenant.Tier))
+		c.Next()
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+)
+
+// fakeTenants answers key, tenant and membership lookups from maps
+type fakeTenants struct {
+	keys    map[string]*tenancy.Principal
+	tenants map[string]*tenancy.Tenant
+	members map[string]tenancy.Role
+}
+
+func (f *fakeTenants) Verify(ctx context.Context, key string) (*tenancy.Principal, error) {
+	if principal, ok := f.keys[key]; ok {
+		return principal, nil
+	}
+	return nil, apperrors.New(apperrors.Unauthenticated, "invalid api key")
+}
+
+func (f *fakeTenants) Authorize(ctx context.Context, id string) (*tenancy.Tenant, error) {
+	tenant, ok := f.tenants[id]
+	if !ok || tenant.Status != tenancy.StatusActive {
+		return nil, apperrors.Newf(apperrors.PermissionDenied, "tenant %s may not use the platform", id)
+	}
+	return tenant, nil
+}
+
+func (f *fakeTenants) Member(ctx context.Context, tenantID, userID string) (*tenancy.Member, error) {
+	role, ok := f.members[tenantID+"/"+userID]
+	if !ok {
+		return nil, apperrors.Newf(apperrors.PermissionDenied, "user %s is not a member of tenant %s", userID, tenantID)
+	}
+	return &tenancy.Member{TenantID: tenantID, UserID: userID, Role: role}, nil
+}
+
+func TestTenants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tenants := &fakeTenants{
+		keys: map[string]*tenancy.Principal{
+			"aip_member": {KeyID: "k1", TenantID: "acme", Role: tenancy.RoleMember},
+			"aip_viewer": {KeyID: "k2", TenantID: "acme", Role: tenancy.RoleViewer},
+			"aip_frozen": {KeyID: "k3", TenantID: "frozen", Role: tenancy.RoleOwner},
+		},
+		tenants: map[string]*tenancy.Tenant{
+			"acme":   {ID: "acme", Status: tenancy.StatusActive, Limits: tenancy.Limits{MaxBatchItems: 10}},
+			"frozen": {ID: "frozen", Status: tenancy.StatusSuspended},
+		},
+		members: map[string]tenancy.Role{"acme/alice": tenancy.RoleAdmin},
+	}
+
+	router := gin.New()
+	router.Use(AuthWithKeys(func() string { return "test-secret" }, tenants), Tenants(tenants))
+	handler := func(c *gin.Context) {
+		limits, _ := c.Get("tenant_limits")
+		c.JSON(http.StatusOK, gin.H{
+			"tenant":          c.GetString("tenant"),
+			"role":            c.GetString("tenant_role"),
+			"max_batch_items": limits.(tenancy.Limits).MaxBatchItems,
+		})
+	}
+	router.GET("/v1/jobs/1", handler)
+	router.POST("/v1/batch", handler)
+
+	sign := func(claims jwt.MapClaims) string {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+		return token
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		token      string
+		wantStatus int
+		wantBody   string
+	}{
+		{"member key", "POST", "aip_member", http.StatusOK, `"role":"member"`},
+		{"limits are kept", "POST", "aip_member", http.StatusOK, `"max_batch_items":10`},
+		{"viewer key reads", "GET", "aip_viewer", http.StatusOK, `"tenant":"acme"`},
+		{"viewer key cannot write", "POST", "aip_viewer", http.StatusForbidden, "read-only"},
+		{"unknown key", "GET", "aip_unknown", http.StatusUnauthorized, "invalid api key"},
+		{"suspended tenant", "GET", "aip_frozen", http.StatusForbidden, "may not use the platform"},
+		{"member user", "POST", sign(jwt.MapClaims{"user_id": "alice", "tenant_id": "acme"}), http.StatusOK, `"role":"admin"`},
+		{"non-member user", "GET", sign(jwt.MapClaims{"user_id": "mallory", "tenant_id": "acme"}), http.StatusForbidden, "not a member"},
+		{"no tenant", "GET", sign(jwt.MapClaims{"user_id": "alice"}), http.StatusForbidden, "do not name a tenant"},
+		{"demo token", "GET", "demo-token", http.StatusForbidden, "do not name a tenant"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/v1/jobs/1"
+			if tt.method == "POST" {
+				path = "/v1/batch"
+			}
+			req := httptest.NewRequest(tt.method, path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultPlan is the plan of callers whose plan is unknown
+const DefaultPlan = "free"
+
+// RateTier is the rate limit of a plan. Zero values are not enforced.
+type RateTier struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	// Burst is the most requests accepted within any one second
+	Burst int `json:"burst"`
+}
+
+// DefaultRateTiers are the tiers used unless others are configured
+var DefaultRateTiers = map[string]RateTier{
+	"free":       {RequestsPerMinute: 100, Burst: 20},
+	"pro":        {RequestsPerMinute: 1000, Burst: 100},
+	"enterprise": {RequestsPerMinute: 10000, Burst: 500},
+}
+
+// RateTiers holds the rate tier of each plan; they can be replaced at runtime
+type RateTiers struct {
+	tiers    atomic.Value
+	override atomic.Value
+	logger   *zap.Logger
+}
+
+// NewRateTiers creates rate tiers starting from tiers
+func NewRateTiers(tiers map[string]RateTier, logger *zap.Logger) *RateTiers {
+	t := &RateTiers{logger: logger}
+	t.tiers.Store(tiers)
+	t.override.Store(map[string]RateTier(nil))
+	return t
+}
+
+// RateTiersFromEnv creates rate tiers from RATE_LIMIT_TIERS (a JSON object of
+// plans) or RATE_LIMIT_TIERS_FILE (a JSON file, e.g. a mounted ConfigMap,
+// reloaded on change), falling back to DefaultRateTiers
+func RateTiersFromEnv(ctx context.Context, logger *zap.Logger) (*RateTiers, error) {
+	t := NewRateTiers(DefaultRateTiers, logger)
+
+	if inline := os.Getenv("RATE_LIMIT_TIERS"); inline != "" {
+		tiers, err := ParseRateTiers([]byte(inline))
+		if err != nil {
+			return nil, err
+		}
+		t.Set(tiers)
+	}
+
+	if path := os.Getenv("RATE_LIMIT_TIERS_FILE"); path != "" {
+		if err := t.Watch(ctx, path, 10*time.Second); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// ParseRateTiers parses and validates a JSON object mapping plans to tiers.
+// The default plan must be present since unknown plans fall back to it.
+func ParseRateTiers(data []byte) (map[string]RateTier, error) {
+	var tiers map[string]RateTier
+	if err := json.Unmarshal(data, &tiers); err != nil {
+		return nil, fmt.Errorf("invalid rate tiers: %w", err)
+	}
+	if _, ok := tiers[DefaultPlan]; !ok {
+		return nil, fmt.Errorf("invalid rate tiers: missing %q plan", DefaultPlan)
+	}
+	for plan, tier := range tiers {
+		if tier.RequestsPerMinute < 0 || tier.Burst < 0 {
+			return nil, fmt.Errorf("invalid rate tiers: %s has a negative limit", plan)
+		}
+	}
+	return tiers, nil
+}
+
+// Set replaces the tiers. Tiers must already be validated.
+func (t *RateTiers) Set(tiers map[string]RateTier) {
+	t.tiers.Store(tiers)
+	t.logger.Info("rate tiers loaded", zap.Int("plans", len(tiers)))
+}
+
+// Override puts tiers in force over those Set or loaded from a file, until
+// it is called with nil. Tiers must already be validated.
+func (t *RateTiers) Override(tiers map[string]RateTier) {
+	t.override.Store(tiers)
+	if tiers == nil {
+		t.logger.Info("rate tier override cleared")
+		return
+	}
+	t.logger.Info("rate tiers overridden", zap.Int("plans", len(tiers)))
+}
+
+// Current returns the tiers in force
+func (t *RateTiers) Current() map[string]RateTier {
+	if tiers := t.override.Load().(map[string]RateTier); tiers != nil {
+		return tiers
+	}
+	return t.tiers.Load().(map[string]RateTier)
+}
+
+// Lookup returns the tier of plan, or of the default plan for unknown plans
+func (t *RateTiers) Lookup(plan string) RateTier {
+	tiers := t.Current()
+	if tier, ok := tiers[plan]; ok {
+		return tier
+	}
+	return tiers[DefaultPlan]
+}
+
+// Watch loads tiers from path and reloads them whenever the file changes.
+// A file that fails to parse leaves the previous tiers in place.
+func (t *RateTiers) Watch(ctx context.Context, path string, interval time.Duration) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read rate tiers: %w", err)
+	}
+	if err := t.load(path); err != nil {
+		return err
+	}
+
+	go func() {
+		modTime := info.ModTime()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Equal(modTime) {
+				continue
+			}
+			modTime = info.ModTime()
+			if err := t.load(path); err != nil {
+				t.logger.Error("failed to reload rate tiers", zap.String("path", path), zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+func (t *RateTiers) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read rate tiers: %w", err)
+	}
+	tiers, err := ParseRateTiers(data)
+	if err != nil {
+		return err
+	}
+	t.Set(tiers)
+	return nil
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseRateTiers(t *testing.T) {
+	tiers, err := ParseRateTiers([]byte(`{"free":{"requests_per_minute":10,"burst":2},"pro":{"requests_per_minute":100}}`))
+	require.NoError(t, err)
+	assert.Equal(t, RateTier{RequestsPerMinute: 100}, tiers["pro"])
+
+	_, err = ParseRateTiers([]byte(`{"pro":{"requests_per_minute":100}}`))
+	assert.ErrorContains(t, err, `missing "free" plan`)
+
+	_, err = ParseRateTiers([]byte(`{"free":{"requests_per_minute":-1}}`))
+	assert.Error(t, err)
+
+	_, err = ParseRateTiers([]byte(`[]`))
+	assert.Error(t, err)
+}
+
+func TestRateTiers_LookupFallsBackToDefaultPlan(t *testing.T) {
+	tiers := NewRateTiers(DefaultRateTiers, zap.NewNop())
+
+	assert.Equal(t, DefaultRateTiers["pro"], tiers.Lookup("pro"))
+	assert.Equal(t, DefaultRateTiers[DefaultPlan], tiers.Lookup("platinum"))
+	assert.Equal(t, DefaultRateTiers[DefaultPlan], tiers.Lookup(""))
+}
+
+func TestRateTiers_Override(t *testing.T) {
+	tiers := NewRateTiers(DefaultRateTiers, zap.NewNop())
+	override := map[string]RateTier{DefaultPlan: {RequestsPerMinute: 5}}
+
+	tiers.Override(override)
+	tiers.Set(map[string]RateTier{DefaultPlan: {RequestsPerMinute: 50}})
+	assert.Equal(t, override, tiers.Current())
+	assert.Equal(t, 5, tiers.Lookup("pro").RequestsPerMinute)
+
+	// Clearing the override restores the tiers set since
+	tiers.Override(nil)
+	assert.Equal(t, 50, tiers.Lookup(DefaultPlan).RequestsPerMinute)
+}
+
+func TestRateTiers_WatchReloadsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tiers.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"free":{"requests_per_minute":10}}`), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tiers := NewRateTiers(DefaultRateTiers, zap.NewNop())
+	require.NoError(t, tiers.Watch(ctx, path, 10*time.Millisecond))
+	assert.Equal(t, 10, tiers.Lookup("free").RequestsPerMinute)
+
+	// A broken file keeps the previous tiers
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 10, tiers.Lookup("free").RequestsPerMinute)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"free":{"requests_per_minute":20}}`), 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Second)))
+	assert.Eventually(t, func() bool {
+		return tiers.Lookup("free").RequestsPerMinute == 20
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestPlanRateLimit_EnforcesBurst(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	client := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not available:", err)
+	}
+
+	tiers := NewRateTiers(map[string]RateTier{
+		"free": {RequestsPerMinute: 100, Burst: 2},
+		"pro":  {RequestsPerMinute: 100},
+	}, zap.NewNop())
+	// Each plan is tried by a new caller
+	statuses := func(plan string) []int {
+		router := gin.New()
+		user := "tiers-" + plan + "-" + time.Now().Format(time.RFC3339Nano)
+		router.Use(func(c *gin.Context) {
+			c.Set("user_id", user)
+			c.Set("plan", plan)
+		}, PlanRateLimit(NewRateLimiter(client, FailOpen), tiers))
+		router.GET("/test", func(c *gin.Context) {})
+
+		var codes []int
+		for i := 0; i < 3; i++ {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+			codes = append(codes, w.Code)
+		}
+		return codes
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, statuses("free"))
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusOK}, statuses("pro"))
+}
@@ -5,6 +5,8 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
 )
 
 // Tracing middleware adds OpenTelemetry tracing to requests
@@ -27,8 +29,9 @@ func Tracing() gin.HandlerFunc {
 		)
 
 		// Store trace ID in context for logging
-		if span.SpanContext().HasTraceID() {
-			c.Set("trace_id", span.SpanContext().TraceID().String())
+		if sc := span.SpanContext(); sc.IsValid() {
+			c.Set("trace_id", sc.TraceID().String())
+			ctx = logging.WithTrace(ctx, sc.TraceID().String(), sc.SpanID().String())
 		}
 
 		// Update request context
@@ -0,0 +1,128 @@
+// Package notify delivers the model changes announced on the platform event
+// topic to the webhook endpoints external systems register with the gateway
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+// Dispatcher delivers an event to the endpoints subscribing to it
+type Dispatcher interface {
+	Notify(ctx context.Context, event events.Event)
+}
+
+// KafkaConsumer feeds model events from Kafka to the dispatcher. Job events
+// are delivered by the batch worker that ran the job.
+type KafkaConsumer struct {
+	consumer sarama.ConsumerGroup
+	topic    string
+	handler  *consumerGroupHandler
+	logger   *zap.Logger
+}
+
+// NewKafkaConsumer creates a consumer for the platform event topic. Gateway
+// replicas share groupID so each event is delivered once.
+func NewKafkaConsumer(brokers []string, topic, groupID string, dispatcher Dispatcher, logger *zap.Logger) (*KafkaConsumer, error) {
+	config := sarama.NewConfig()
+	config.Version = sarama.V3_3_0_0
+	config.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRoundRobin
+	// Webhooks announce what is happening now; a new group skips the backlog
+	config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	config.Consumer.Return.Errors = true
+
+	consumer, err := sarama.NewConsumerGroup(brokers, groupID, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	return &KafkaConsumer{
+		consumer: consumer,
+		topic:    topic,
+		handler:  &consumerGroupHandler{dispatcher: dispatcher, logger: logger},
+		logger:   logger,
+	}, nil
+}
+
+// SetCodec checks platform events against their registered schema
+func (c *KafkaConsumer) SetCodec(codec *schema.Codec) {
+	c.handler.codec = codec
+}
+
+// Start consumes platform events until ctx is cancelled
+func (c *KafkaConsumer) Start(ctx context.Context) error {
+	c.logger.Info("starting webhook event consumer", zap.String("topic", c.topic))
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("shutting down webhook event consumer")
+			return c.consumer.Close()
+		default:
+			if err := c.consumer.Consume(ctx, []string{c.topic}, c.handler); err != nil {
+				c.logger.Error("consumer error", zap.Error(err))
+				return err
+			}
+		}
+	}
+}
+
+// consumerGroupHandler implements sarama.ConsumerGroupHandler
+type consumerGroupHandler struct {
+	dispatcher Dispatcher
+	codec      *schema.Codec
+	logger     *zap.Logger
+}
+
+// Setup is run at the beginning of a new session
+func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// Cleanup is run at the end of a session
+func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim queues each model event for delivery and marks it consumed.
+// The dispatcher retries deliveries itself.
+func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case <-session.Context().Done():
+			return nil
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if message == nil {
+				continue
+			}
+			h.handle(session.Context(), message)
+			session.MarkMessage(message, "")
+		}
+	}
+}
+
+func (h *consumerGroupHandler) handle(ctx context.Context, message *sarama.ConsumerMessage) {
+	var event events.Event
+	if err := h.codec.Decode(ctx, schema.PlatformEvents, message.Value, &event); err != nil {
+		h.logger.Error("discarding malformed platform event",
+			zap.Int32("partition", message.Partition),
+			zap.Int64("offset", message.Offset),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if strings.HasPrefix(string(event.Type), "model.") {
+		h.dispatcher.Notify(ctx, event)
+	}
+}
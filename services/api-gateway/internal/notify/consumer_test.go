@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/events"
+)
+
+type fakeDispatcher struct {
+	notified []events.Event
+}
+
+func (f *fakeDispatcher) Notify(ctx context.Context, event events.Event) {
+	f.notified = append(f.notified, event)
+}
+
+func TestHandle_DispatchesModelEvents(t *testing.T) {
+	dispatcher := &fakeDispatcher{}
+	h := &consumerGroupHandler{dispatcher: dispatcher, logger: zap.NewNop()}
+
+	message := func(event events.Event) *sarama.ConsumerMessage {
+		value, err := json.Marshal(event)
+		assert.NoError(t, err)
+		return &sarama.ConsumerMessage{Value: value}
+	}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	h.handle(context.Background(), message(events.Event{ID: "e1", Type: events.ModelPromoted, Severity: events.SeverityInfo, Service: "metadata-service", Subject: "resnet18:v2", OccurredAt: now}))
+	h.handle(context.Background(), message(events.Event{ID: "e2", Type: events.JobCompleted, Severity: events.SeverityInfo, Service: "batch-worker", Subject: "job-1", OccurredAt: now}))
+	h.handle(context.Background(), message(events.Event{ID: "e3", Type: events.ModelQuarantined, Severity: events.SeverityCritical, Service: "artifact-scanner", Subject: "resnet18:v3", OccurredAt: now}))
+	h.handle(context.Background(), &sarama.ConsumerMessage{Value: []byte("not json")})
+
+	var ids []string
+	for _, event := range dispatcher.notified {
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"e1", "e3"}, ids)
+}
@@ -12,7 +12,7 @@ var (
 			Name: "inference_requests_total",
 			Help: "Total number of inference requests",
 		},
-		[]string{"model", "version", "type", "status"},
+		[]string{"model", "version", "type", "status", "tenant"},
 	)
 
 	// InferenceRequestDuration tracks inference request latency
@@ -31,7 +31,67 @@ var (
 			Name: "batch_jobs_submitted_total",
 			Help: "Total number of batch jobs submitted",
 		},
-		[]string{"model", "version"},
+		[]string{"model", "version", "tenant"},
+	)
+
+	// AdmissionRejected counts inferences turned away by admission control
+	AdmissionRejected = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "admission_rejected_total",
+			Help: "Total number of inference requests rejected by admission control",
+		},
+		[]string{"reason"},
+	)
+
+	// AdmissionLimit tracks the in-flight inference limit
+	AdmissionLimit = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "admission_limit",
+			Help: "Inference requests admission control lets run at once",
+		},
+	)
+
+	// AdmissionInFlight tracks the inferences admitted and not yet finished
+	AdmissionInFlight = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "admission_in_flight",
+			Help: "Inference requests running",
+		},
+	)
+
+	// AdmissionQueued tracks the inferences waiting for a slot
+	AdmissionQueued = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "admission_queued",
+			Help: "Inference requests waiting to run",
+		},
+	)
+
+	// RateLimitRedisUnavailable counts rate limit decisions made without Redis
+	RateLimitRedisUnavailable = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limit_redis_unavailable_total",
+			Help: "Total number of rate limit decisions made by the failure policy while Redis was unreachable",
+		},
+		[]string{"policy", "decision"},
+	)
+
+	// QuotaCountersUnavailable counts quota decisions made without Redis
+	QuotaCountersUnavailable = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "quota_counters_unavailable_total",
+			Help: "Total number of quota decisions made by the failure policy while usage could not be counted",
+		},
+		[]string{"resource", "decision"},
+	)
+
+	// ResponseCacheLookups counts response cache lookups by model and result
+	ResponseCacheLookups = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_response_cache_lookups_total",
+			Help: "Total number of inference response cache lookups by result: hit, miss or error",
+		},
+		[]string{"model", "result"},
 	)
 )
 
@@ -0,0 +1,210 @@
+// Package quota counts each tenant's monthly usage against the quotas of its
+// plan. Unlike rate limits, which smooth bursts, quotas cap how much a tenant
+// may use in a billing period; a tenant over its quota is turned away until
+// the next period starts.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/api-gateway/internal/observability"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+)
+
+// Resource is what a quota counts
+type Resource string
+
+const (
+	Requests   Resource = "requests"
+	BatchItems Resource = "batch_items"
+)
+
+// Resources lists every resource with a quota
+var Resources = []Resource{Requests, BatchItems}
+
+// Limit returns the monthly quota limits set for resource; zero is unlimited
+func Limit(limits tenancy.Limits, resource Resource) int64 {
+	switch resource {
+	case Requests:
+		return int64(limits.MonthlyRequests)
+	case BatchItems:
+		return int64(limits.MonthlyBatchItems)
+	}
+	return 0
+}
+
+// Counters stores usage counts
+type Counters interface {
+	// Add adds n to the count under key, which expires at expireAt, and
+	// returns the new count
+	Add(ctx context.Context, key string, n int64, expireAt time.Time) (int64, error)
+	// Get returns the counts under keys, zero for missing keys
+	Get(ctx context.Context, keys ...string) ([]int64, error)
+}
+
+// ExhaustedError is returned when a tenant has used up a quota
+type ExhaustedError struct {
+	Resource Resource
+	Limit    int64
+	ResetsAt time.Time
+}
+
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("monthly %s quota of %d is exhausted", e.Resource, e.Limit)
+}
+
+// Unwrap classifies the error for transports that do not report quotas specially
+func (e *ExhaustedError) Unwrap() error {
+	return apperrors.New(apperrors.ResourceExhausted, e.Error())
+}
+
+// Usage is a tenant's use of one quota in the current period
+type Usage struct {
+	Resource Resource `json:"resource"`
+	Used     int64    `json:"used"`
+	// Limit and Remaining are omitted for unlimited resources
+	Limit     int64  `json:"limit,omitempty"`
+	Remaining *int64 `json:"remaining,omitempty"`
+}
+
+// Report is a tenant's use of its quotas in the current period
+type Report struct {
+	Tenant      string    `json:"tenant"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Quotas      []Usage   `json:"quotas"`
+}
+
+// Tracker counts usage per tenant and calendar month. Usage that cannot be
+// counted is handled by the rate limits' failure policy: FailClosed turns it
+// away, while FailOpen and FailLocal allow it, as a replica's own count of a
+// month's usage would mean nothing.
+type Tracker struct {
+	counters Counters
+	failure  middleware.FailurePolicy
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+// NewTracker creates a tracker keeping its counts in counters
+func NewTracker(counters Counters, failure middleware.FailurePolicy, logger *zap.Logger) *Tracker {
+	return &Tracker{counters: counters, failure: failure, logger: logger, now: time.Now}
+}
+
+// Period returns the start and end of the current period
+func (t *Tracker) Period() (time.Time, time.Time) {
+	now := t.now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Consume counts n of resource against the tenant's quota. When that would
+// exceed the quota nothing is counted and an *ExhaustedError is returned. A
+// zero limit is unlimited, but usage is still counted for reporting.
+func (t *Tracker) Consume(ctx context.Context, tenant string, resource Resource, n, limit int64) error {
+	start, end := t.Period()
+	key := counterKey(tenant, resource, start)
+
+	// Counts are kept a day past the period so late reports still see them
+	count, err := t.counters.Add(ctx, key, n, end.Add(24*time.Hour))
+	if err != nil {
+		return t.unavailable(tenant, resource, err)
+	}
+	if limit > 0 && count > limit {
+		// Give back what was not used so the rejection does not count
+		t.counters.Add(ctx, key, -n, end.Add(24*time.Hour))
+		return &ExhaustedError{Resource: resource, Limit: limit, ResetsAt: end}
+	}
+	return nil
+}
+
+// unavailable applies the failure policy to usage that could not be counted
+func (t *Tracker) unavailable(tenant string, resource Resource, err error) error {
+	decision := "allowed"
+	if t.failure == middleware.FailClosed {
+		decision = "rejected"
+	}
+	observability.QuotaCountersUnavailable.WithLabelValues(string(resource), decision).Inc()
+	t.logger.Warn("failed to count quota usage",
+		zap.String("tenant", tenant),
+		zap.String("resource", string(resource)),
+		zap.String("decision", decision),
+		zap.Error(err))
+	if decision == "rejected" {
+		return apperrors.Wrap(err, apperrors.Unavailable, "quota usage is unavailable")
+	}
+	return nil
+}
+
+// Report returns the tenant's usage of each quota in limits this period
+func (t *Tracker) Report(ctx context.Context, tenant string, limits tenancy.Limits) (*Report, error) {
+	start, end := t.Period()
+	keys := make([]string, len(Resources))
+	for i, resource := range Resources {
+		keys[i] = counterKey(tenant, resource, start)
+	}
+	counts, err := t.counters.Get(ctx, keys...)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Unavailable, "quota usage is unavailable")
+	}
+
+	report := &Report{Tenant: tenant, PeriodStart: start, PeriodEnd: end}
+	for i, resource := range Resources {
+		usage := Usage{Resource: resource, Used: counts[i], Limit: Limit(limits, resource)}
+		if usage.Limit > 0 {
+			remaining := usage.Limit - usage.Used
+			if remaining < 0 {
+				remaining = 0
+			}
+			usage.Remaining = &remaining
+		}
+		report.Quotas = append(report.Quotas, usage)
+	}
+	return report, nil
+}
+
+func counterKey(tenant string, resource Resource, period time.Time) string {
+	return fmt.Sprintf("quota:%s:%s:%s", tenant, period.Format("2006-01"), resource)
+}
+
+// redisCounters keeps counts in Redis
+type redisCounters struct {
+	client *redis.Client
+}
+
+// RedisCounters keeps counts in Redis, shared by every gateway replica
+func RedisCounters(client *redis.Client) Counters {
+	return &redisCounters{client: client}
+}
+
+func (r *redisCounters) Add(ctx context.Context, key string, n int64, expireAt time.Time) (int64, error) {
+	pipe := r.client.TxPipeline()
+	count := pipe.IncrBy(ctx, key, n)
+	pipe.ExpireAt(ctx, key, expireAt)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+func (r *redisCounters) Get(ctx context.Context, keys ...string) ([]int64, error) {
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]int64, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			counts[i], _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+	return counts, nil
+}
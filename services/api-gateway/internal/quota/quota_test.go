@@ -0,0 +1,114 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+)
+
+type memoryCounters struct {
+	counts  map[string]int64
+	expires map[string]time.Time
+	err     error
+}
+
+func newMemoryCounters() *memoryCounters {
+	return &memoryCounters{counts: map[string]int64{}, expires: map[string]time.Time{}}
+}
+
+func (m *memoryCounters) Add(ctx context.Context, key string, n int64, expireAt time.Time) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.counts[key] += n
+	m.expires[key] = expireAt
+	return m.counts[key], nil
+}
+
+func (m *memoryCounters) Get(ctx context.Context, keys ...string) ([]int64, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	counts := make([]int64, len(keys))
+	for i, key := range keys {
+		counts[i] = m.counts[key]
+	}
+	return counts, nil
+}
+
+func newTestTracker(counters Counters) *Tracker {
+	tracker := NewTracker(counters, middleware.FailOpen, zap.NewNop())
+	tracker.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	return tracker
+}
+
+func TestTracker_ConsumeRejectsOnceExhausted(t *testing.T) {
+	counters := newMemoryCounters()
+	tracker := newTestTracker(counters)
+	ctx := context.Background()
+
+	require.NoError(t, tracker.Consume(ctx, "acme", BatchItems, 6, 10))
+	err := tracker.Consume(ctx, "acme", BatchItems, 5, 10)
+
+	var exhausted *ExhaustedError
+	require.True(t, errors.As(err, &exhausted))
+	assert.Equal(t, BatchItems, exhausted.Resource)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), exhausted.ResetsAt)
+	assert.Equal(t, apperrors.ResourceExhausted, apperrors.CodeOf(err))
+
+	// The rejected items are not counted, so a smaller job still fits
+	assert.Equal(t, int64(6), counters.counts["quota:acme:2026-10:batch_items"])
+	assert.NoError(t, tracker.Consume(ctx, "acme", BatchItems, 4, 10))
+	assert.Equal(t, time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC), counters.expires["quota:acme:2026-10:batch_items"])
+
+	// Other tenants and unlimited resources are unaffected
+	assert.NoError(t, tracker.Consume(ctx, "globex", BatchItems, 10, 10))
+	assert.NoError(t, tracker.Consume(ctx, "acme", Requests, 1000, 0))
+}
+
+func TestTracker_FailsOpen(t *testing.T) {
+	counters := newMemoryCounters()
+	counters.err = errors.New("connection refused")
+	tracker := newTestTracker(counters)
+
+	assert.NoError(t, tracker.Consume(context.Background(), "acme", Requests, 1, 1))
+
+	_, err := tracker.Report(context.Background(), "acme", tenancy.Limits{})
+	assert.Equal(t, apperrors.Unavailable, apperrors.CodeOf(err))
+}
+
+func TestTracker_FailsClosed(t *testing.T) {
+	counters := newMemoryCounters()
+	counters.err = errors.New("connection refused")
+	tracker := newTestTracker(counters)
+	tracker.failure = middleware.FailClosed
+
+	err := tracker.Consume(context.Background(), "acme", Requests, 1, 0)
+	assert.Equal(t, apperrors.Unavailable, apperrors.CodeOf(err))
+}
+
+func TestTracker_Report(t *testing.T) {
+	tracker := newTestTracker(newMemoryCounters())
+	ctx := context.Background()
+	require.NoError(t, tracker.Consume(ctx, "acme", Requests, 40, 100))
+	require.NoError(t, tracker.Consume(ctx, "acme", BatchItems, 500, 0))
+
+	report, err := tracker.Report(ctx, "acme", tenancy.Limits{MonthlyRequests: 100})
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), report.PeriodStart)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), report.PeriodEnd)
+	require.Len(t, report.Quotas, 2)
+	remaining := int64(60)
+	assert.Equal(t, Usage{Resource: Requests, Used: 40, Limit: 100, Remaining: &remaining}, report.Quotas[0])
+	assert.Equal(t, Usage{Resource: BatchItems, Used: 500}, report.Quotas[1])
+}
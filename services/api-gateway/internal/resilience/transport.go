@@ -0,0 +1,252 @@
+// Package resilience wraps the gateway's clients for other services with a
+// circuit breaker and budgeted retries, so a failing service is answered for
+// quickly instead of tying up every caller's connection.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Config tunes a Transport
+type Config struct {
+	// MaxRetries is how many times a failed request may be retried
+	MaxRetries int
+	// BaseBackoff and MaxBackoff bound the jittered wait before each retry,
+	// which doubles from BaseBackoff up to MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// RetryBudget is the share of requests that may be retried, so retries
+	// cannot multiply the load on a struggling service
+	RetryBudget float64
+	// The breaker opens when at least BreakerMinRequests requests in
+	// BreakerInterval fail at BreakerFailureRatio or more, and lets a trial
+	// request through after BreakerOpenTimeout
+	BreakerMinRequests  uint32
+	BreakerFailureRatio float64
+	BreakerInterval     time.Duration
+	BreakerOpenTimeout  time.Duration
+}
+
+// DefaultConfig retries twice within a 10% budget and opens after half of at
+// least 10 requests fail
+var DefaultConfig = Config{
+	MaxRetries:          2,
+	BaseBackoff:         50 * time.Millisecond,
+	MaxBackoff:          time.Second,
+	RetryBudget:         0.1,
+	BreakerMinRequests:  10,
+	BreakerFailureRatio: 0.5,
+	BreakerInterval:     10 * time.Second,
+	BreakerOpenTimeout:  15 * time.Second,
+}
+
+// budgetCap is the most retries saved up while a service is healthy
+const budgetCap = 10
+
+// Transport is an http.RoundTripper for one service that fails fast while
+// the service is down and retries requests the service did not act on
+type Transport struct {
+	service string
+	base    http.RoundTripper
+	config  Config
+	breaker *gobreaker.CircuitBreaker
+	logger  *zap.Logger
+
+	mu     sync.Mutex
+	tokens float64
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+// NewTransport wraps base, or http.DefaultTransport when nil, for service
+func NewTransport(service string, base http.RoundTripper, config Config, logger *zap.Logger) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{
+		service: service,
+		base:    base,
+		config:  config,
+		logger:  logger,
+		tokens:  budgetCap,
+		sleep:   sleep,
+	}
+	t.breaker = gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        service,
+		MaxRequests: 1,
+		Interval:    config.BreakerInterval,
+		Timeout:     config.BreakerOpenTimeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
+			return counts.Requests >= config.BreakerMinRequests && failureRatio >= config.BreakerFailureRatio
+		},
+		// Callers giving up say nothing about the service's health
+		IsSuccessful: func(err error) bool {
+			return err == nil || errors.Is(err, context.Canceled)
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			logger.Warn("circuit breaker changed state",
+				zap.String("service", name),
+				zap.String("from", from.String()),
+				zap.String("to", to.String()),
+			)
+		},
+	})
+	return t
+}
+
+// State reports the breaker's state: closed, half-open or open
+func (t *Transport) State() string {
+	return t.breaker.State().String()
+}
+
+// RoundTrip sends a request through the breaker, retrying it while the
+// retry limit, the budget and the request's deadline allow
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.deposit()
+
+	backoff := t.config.BaseBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req, attempt)
+		if !t.shouldRetry(req, resp, err, attempt) {
+			return resp, err
+		}
+
+		// Jitter spreads the retries of callers that failed together
+		wait := time.Duration(rand.Int63n(int64(backoff) + 1))
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) <= wait {
+			return resp, err
+		}
+		if !t.withdraw() {
+			return resp, err
+		}
+		t.logger.Debug("retrying request",
+			zap.String("service", t.service),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("backoff", wait),
+		)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if sleepErr := t.sleep(req.Context(), wait); sleepErr != nil {
+			return nil, sleepErr
+		}
+
+		backoff *= 2
+		if backoff > t.config.MaxBackoff {
+			backoff = t.config.MaxBackoff
+		}
+	}
+}
+
+// attempt sends one try of a request through the breaker; retries replay its
+// body. An open breaker answers without sending anything.
+func (t *Transport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	try := req
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		try = req.Clone(req.Context())
+		try.Body = body
+	}
+
+	result, err := t.breaker.Execute(func() (interface{}, error) {
+		resp, err := t.base.RoundTrip(try)
+		if err != nil {
+			return nil, err
+		}
+		if unhealthy(resp.StatusCode) {
+			return resp, &statusError{status: resp.StatusCode}
+		}
+		return resp, nil
+	})
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		return nil, apperrors.Newf(apperrors.Unavailable, "%s circuit breaker is open", t.service)
+	}
+	var status *statusError
+	if errors.As(err, &status) {
+		// The response reaches the caller, who reads the service's error
+		return result.(*http.Response), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.(*http.Response), nil
+}
+
+// shouldRetry reports whether a try failed in a way the service did not act
+// on: it could not be reached, or a proxy in front of it could not reach it
+func (t *Transport) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if attempt >= t.config.MaxRetries || req.Context().Err() != nil {
+		return false
+	}
+	// Bodies that cannot be replayed cannot be retried
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+	return resp.StatusCode == http.StatusBadGateway
+}
+
+// deposit earns a share of a retry for each request
+func (t *Transport) deposit() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tokens += t.config.RetryBudget
+	if t.tokens > budgetCap {
+		t.tokens = budgetCap
+	}
+}
+
+// withdraw spends a retry from the budget, reporting whether one was left
+func (t *Transport) withdraw() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.tokens < 1 {
+		return false
+	}
+	t.tokens--
+	return true
+}
+
+// unhealthy reports whether a status says the service could not be reached.
+// A service answering 503 itself, say for a model without healthy backends,
+// is up and keeps the breaker closed.
+func unhealthy(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusGatewayTimeout
+}
+
+// statusError marks responses that count as failures for the breaker
+type statusError struct {
+	status int
+}
+
+func (e *statusError) Error() string {
+	return http.StatusText(e.status)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,136 @@
+package resilience
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// fakeService answers each try with the next of its answers, an error or a
+// status, repeating the last one
+type fakeService struct {
+	answers []interface{}
+	bodies  []string
+}
+
+func (f *fakeService) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	f.bodies = append(f.bodies, string(body))
+
+	answer := f.answers[len(f.answers)-1]
+	if len(f.bodies) <= len(f.answers) {
+		answer = f.answers[len(f.bodies)-1]
+	}
+	if err, ok := answer.(error); ok {
+		return nil, err
+	}
+	return &http.Response{StatusCode: answer.(int), Body: io.NopCloser(bytes.NewReader(nil))}, nil
+}
+
+var refused = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+func newTestTransport(service *fakeService, config Config) *Transport {
+	t := NewTransport("model-router", service, config, zap.NewNop())
+	t.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	return t
+}
+
+func post(t *testing.T, transport *Transport) (*http.Response, error) {
+	req, err := http.NewRequest("POST", "http://model-router/v1/route", bytes.NewBufferString(`{"model":"resnet18"}`))
+	require.NoError(t, err)
+	return transport.RoundTrip(req)
+}
+
+func TestTransport_Retries(t *testing.T) {
+	config := DefaultConfig
+	config.BreakerMinRequests = 100
+
+	tests := []struct {
+		name       string
+		answers    []interface{}
+		wantTries  int
+		wantStatus int
+		wantErr    bool
+	}{
+		{"recovers from refused connections", []interface{}{refused, http.StatusOK}, 2, http.StatusOK, false},
+		{"recovers from bad gateways", []interface{}{http.StatusBadGateway, http.StatusOK}, 2, http.StatusOK, false},
+		{"gives up after the retry limit", []interface{}{refused}, 3, 0, true},
+		{"does not retry inferences the router may have run", []interface{}{errors.New("connection reset")}, 1, 0, true},
+		{"does not retry answers", []interface{}{http.StatusServiceUnavailable}, 1, http.StatusServiceUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{answers: tt.answers}
+			resp, err := post(t, newTestTransport(service, config))
+
+			assert.Len(t, service.bodies, tt.wantTries)
+			for _, body := range service.bodies {
+				assert.Equal(t, `{"model":"resnet18"}`, body)
+			}
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestTransport_RetryBudget(t *testing.T) {
+	config := DefaultConfig
+	config.MaxRetries = 1
+	config.RetryBudget = 0.5
+	config.BreakerMinRequests = 100
+	service := &fakeService{answers: []interface{}{http.StatusBadGateway}}
+	transport := newTestTransport(service, config)
+
+	// The saved-up budget covers a burst of 19 retries, each request adding
+	// half a retry, and then only every other request earns one
+	for i := 0; i < 30; i++ {
+		post(t, transport)
+	}
+	assert.Len(t, service.bodies, 30+19+5)
+}
+
+func TestTransport_BreakerOpens(t *testing.T) {
+	config := DefaultConfig
+	config.MaxRetries = 0
+	config.BreakerMinRequests = 3
+	service := &fakeService{answers: []interface{}{http.StatusOK, refused, refused, http.StatusOK}}
+	transport := newTestTransport(service, config)
+
+	for i := 0; i < 3; i++ {
+		post(t, transport)
+	}
+	assert.Equal(t, "open", transport.State())
+
+	// An open breaker answers without reaching the router
+	_, err := post(t, transport)
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable))
+	assert.Len(t, service.bodies, 3)
+}
+
+func TestTransport_CancelledCallsKeepBreakerClosed(t *testing.T) {
+	config := DefaultConfig
+	config.BreakerMinRequests = 3
+	service := &fakeService{answers: []interface{}{context.Canceled}}
+	transport := newTestTransport(service, config)
+
+	for i := 0; i < 5; i++ {
+		post(t, transport)
+	}
+	assert.Equal(t, "closed", transport.State())
+}
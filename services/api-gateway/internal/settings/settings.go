@@ -0,0 +1,254 @@
+// Package settings holds the gateway settings operators change at runtime
+// through the admin API: the log level, the rate tiers and maintenance mode.
+// Changes are stored in Redis so that every replica applies them; replicas
+// read them every refresh interval, and the one that made the change at once.
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Key is the Redis hash the settings changed at runtime are kept in, one
+// field per setting
+const Key = "gateway:settings"
+
+// Fields of Key
+const (
+	LogLevelField    = "log_level"
+	RateTiersField   = "rate_tiers"
+	MaintenanceField = "maintenance"
+)
+
+// Maintenance mode turns API requests away while operators work on the platform
+type Maintenance struct {
+	Enabled bool `json:"enabled"`
+	// Message tells callers why, and when the API is expected back
+	Message string `json:"message,omitempty"`
+	// RetryAfter is sent to callers as Retry-After, in seconds
+	RetryAfter int `json:"retry_after,omitempty"`
+}
+
+// Settings are the settings in force
+type Settings struct {
+	LogLevel    string                         `json:"log_level"`
+	RateTiers   map[string]middleware.RateTier `json:"rate_tiers"`
+	Maintenance Maintenance                    `json:"maintenance"`
+	// Overridden lists the settings changed at runtime, which replace the
+	// configured ones until they are reset
+	Overridden []string `json:"overridden,omitempty"`
+}
+
+// Store applies the settings in Redis to the gateway
+type Store struct {
+	redisClient *redis.Client
+	level       zap.AtomicLevel
+	configured  zapcore.Level
+	tiers       *middleware.RateTiers
+	logger      *zap.Logger
+
+	mu          sync.Mutex
+	applied     map[string]string
+	maintenance atomic.Value
+}
+
+// NewStore creates a store that sets level and overrides tiers. The level's
+// current value is the configured one, restored when its override is reset.
+func NewStore(redisClient *redis.Client, level zap.AtomicLevel, tiers *middleware.RateTiers, logger *zap.Logger) *Store {
+	s := &Store{
+		redisClient: redisClient,
+		level:       level,
+		configured:  level.Level(),
+		tiers:       tiers,
+		logger:      logger,
+		applied:     make(map[string]string),
+	}
+	s.maintenance.Store(Maintenance{})
+	return s
+}
+
+// Current returns the settings in force
+func (s *Store) Current() Settings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	settings := Settings{
+		LogLevel:    s.level.Level().String(),
+		RateTiers:   s.tiers.Current(),
+		Maintenance: s.maintenance.Load().(Maintenance),
+	}
+	for field, value := range s.applied {
+		if value != "" {
+			settings.Overridden = append(settings.Overridden, field)
+		}
+	}
+	sort.Strings(settings.Overridden)
+	return settings
+}
+
+// Refresh reads the settings from Redis. If Redis is down the settings in
+// force stay in place.
+func (s *Store) Refresh(ctx context.Context) error {
+	fields, err := s.redisClient.HGetAll(ctx, Key).Result()
+	if err != nil {
+		return err
+	}
+	s.apply(fields)
+	return nil
+}
+
+// Run refreshes the settings every interval until ctx is cancelled
+func (s *Store) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("failed to refresh runtime settings", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// apply puts the settings read from the Redis hash in force. Fields that
+// have not changed since they were last applied are left alone, so settings
+// changed another way, such as rate tiers reloaded from their file, are not
+// reverted; invalid fields are skipped.
+func (s *Store) apply(fields map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, field := range []string{LogLevelField, RateTiersField, MaintenanceField} {
+		value := fields[field]
+		if previous, ok := s.applied[field]; ok && previous == value {
+			continue
+		}
+		s.applied[field] = value
+
+		var err error
+		switch field {
+		case LogLevelField:
+			level := s.configured
+			if value != "" {
+				level, err = zapcore.ParseLevel(value)
+			}
+			if err == nil && level != s.level.Level() {
+				s.level.SetLevel(level)
+				s.logger.Info("log level changed", zap.Stringer("level", level))
+			}
+		case RateTiersField:
+			var tiers map[string]middleware.RateTier
+			if value != "" {
+				tiers, err = middleware.ParseRateTiers([]byte(value))
+			}
+			if err == nil {
+				s.tiers.Override(tiers)
+			}
+		case MaintenanceField:
+			var maintenance Maintenance
+			if value != "" {
+				err = json.Unmarshal([]byte(value), &maintenance)
+			}
+			if err == nil && maintenance != s.maintenance.Load().(Maintenance) {
+				s.maintenance.Store(maintenance)
+				s.logger.Info("maintenance mode changed", zap.Bool("enabled", maintenance.Enabled))
+			}
+		}
+		if err != nil {
+			s.logger.Warn("skipping invalid runtime setting", zap.String("setting", field), zap.String("value", value), zap.Error(err))
+		}
+	}
+}
+
+// SetLogLevel overrides the log level; an empty level resets it
+func (s *Store) SetLogLevel(ctx context.Context, level string) error {
+	if level != "" {
+		if _, err := zapcore.ParseLevel(level); err != nil {
+			return apperrors.Newf(apperrors.InvalidArgument, "invalid log level %q", level)
+		}
+	}
+	return s.write(ctx, LogLevelField, level)
+}
+
+// SetRateTiers overrides the rate tiers with a JSON object of plans, as in
+// RATE_LIMIT_TIERS; an empty document resets them
+func (s *Store) SetRateTiers(ctx context.Context, data []byte) error {
+	if len(data) > 0 {
+		if _, err := middleware.ParseRateTiers(data); err != nil {
+			return apperrors.New(apperrors.InvalidArgument, "invalid rate tiers").WithDetails(err.Error())
+		}
+	}
+	return s.write(ctx, RateTiersField, string(data))
+}
+
+// SetMaintenance turns maintenance mode on or off
+func (s *Store) SetMaintenance(ctx context.Context, maintenance Maintenance) error {
+	if maintenance.RetryAfter < 0 {
+		return apperrors.New(apperrors.InvalidArgument, "retry_after must not be negative")
+	}
+	value := ""
+	if maintenance.Enabled {
+		data, err := json.Marshal(maintenance)
+		if err != nil {
+			return err
+		}
+		value = string(data)
+	}
+	return s.write(ctx, MaintenanceField, value)
+}
+
+// write stores a field, deleting it when value is empty, and applies the
+// settings at once
+func (s *Store) write(ctx context.Context, field, value string) error {
+	var err error
+	if value == "" {
+		err = s.redisClient.HDel(ctx, Key, field).Err()
+	} else {
+		err = s.redisClient.HSet(ctx, Key, field, value).Err()
+	}
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.Unavailable, "failed to store settings")
+	}
+	if err := s.Refresh(ctx); err != nil {
+		return apperrors.Wrap(err, apperrors.Unavailable, "failed to read settings")
+	}
+	return nil
+}
+
+// Maintenance answers requests with 503 while maintenance mode is on
+func (s *Store) Maintenance() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		maintenance := s.maintenance.Load().(Maintenance)
+		if !maintenance.Enabled {
+			c.Next()
+			return
+		}
+
+		message := maintenance.Message
+		if message == "" {
+			message = "the API is down for maintenance"
+		}
+		if maintenance.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(maintenance.RetryAfter))
+		}
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.Unavailable, message))
+		c.Abort()
+	}
+}
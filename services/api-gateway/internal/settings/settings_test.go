@@ -0,0 +1,112 @@
+package settings
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/yourusername/ai-platform/api-gateway/internal/middleware"
+)
+
+func newStore(client *redis.Client) *Store {
+	tiers := middleware.NewRateTiers(map[string]middleware.RateTier{
+		"free": {RequestsPerMinute: 100, Burst: 20},
+	}, zap.NewNop())
+	return NewStore(client, zap.NewAtomicLevelAt(zapcore.InfoLevel), tiers, zap.NewNop())
+}
+
+func TestStore_Apply(t *testing.T) {
+	store := newStore(nil)
+
+	store.apply(map[string]string{
+		LogLevelField:    "debug",
+		RateTiersField:   `{"free": {"requests_per_minute": 5}}`,
+		MaintenanceField: `{"enabled": true, "message": "upgrading"}`,
+	})
+	current := store.Current()
+	assert.Equal(t, "debug", current.LogLevel)
+	assert.Equal(t, 5, current.RateTiers["free"].RequestsPerMinute)
+	assert.Equal(t, Maintenance{Enabled: true, Message: "upgrading"}, current.Maintenance)
+	assert.Equal(t, []string{LogLevelField, MaintenanceField, RateTiersField}, current.Overridden)
+
+	// Reset settings return to the configured ones
+	store.apply(map[string]string{})
+	current = store.Current()
+	assert.Equal(t, "info", current.LogLevel)
+	assert.Equal(t, 100, current.RateTiers["free"].RequestsPerMinute)
+	assert.False(t, current.Maintenance.Enabled)
+	assert.Empty(t, current.Overridden)
+}
+
+func TestStore_ApplySkipsInvalid(t *testing.T) {
+	store := newStore(nil)
+
+	store.apply(map[string]string{
+		LogLevelField:  "loud",
+		RateTiersField: `{"free": `,
+	})
+	current := store.Current()
+	assert.Equal(t, "info", current.LogLevel)
+	assert.Equal(t, 100, current.RateTiers["free"].RequestsPerMinute)
+}
+
+func TestStore_Maintenance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := newStore(nil)
+
+	router := gin.New()
+	router.Use(store.Maintenance())
+	router.GET("/v1/models", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/models", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	store.apply(map[string]string{MaintenanceField: `{"enabled": true, "message": "back at 14:00 UTC", "retry_after": 600}`})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/models", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "600", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "back at 14:00 UTC")
+}
+
+func TestStore_Set(t *testing.T) {
+	client := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not available:", err)
+	}
+	ctx := context.Background()
+	client.Del(ctx, Key)
+	defer client.Del(ctx, Key)
+
+	store := newStore(client)
+	require.NoError(t, store.SetLogLevel(ctx, "warn"))
+	require.NoError(t, store.SetMaintenance(ctx, Maintenance{Enabled: true}))
+	assert.Equal(t, "warn", store.Current().LogLevel)
+	assert.True(t, store.Current().Maintenance.Enabled)
+
+	// Other replicas pick the settings up when they refresh
+	replica := newStore(client)
+	require.NoError(t, replica.Refresh(ctx))
+	assert.Equal(t, "warn", replica.Current().LogLevel)
+
+	require.NoError(t, store.SetLogLevel(ctx, ""))
+	require.NoError(t, store.SetMaintenance(ctx, Maintenance{}))
+	assert.Equal(t, "info", store.Current().LogLevel)
+	assert.False(t, store.Current().Maintenance.Enabled)
+
+	assert.Error(t, store.SetLogLevel(ctx, "loud"))
+	assert.Error(t, store.SetRateTiers(ctx, []byte(`{"free": `)))
+}
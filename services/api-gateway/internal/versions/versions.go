@@ -0,0 +1,173 @@
+// Package versions resolves the version of a model that requests naming no
+// version are served by: a configured default, or else the latest active
+// version registered with the metadata service.
+package versions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Fallback is the version used when no other can be resolved
+const Fallback = "v1"
+
+// Source looks up the latest active version of a model. It returns "" when
+// the model has no active version.
+type Source interface {
+	Latest(ctx context.Context, model string) (string, error)
+}
+
+// Resolver resolves default versions, caching what the source reports for
+// a short while. A nil Resolver always resolves Fallback.
+type Resolver struct {
+	source   Source
+	defaults map[string]string
+	ttl      time.Duration
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	version string
+	expires time.Time
+}
+
+// NewResolver creates a resolver asking source, once per ttl for each
+// tenant's view of a model. defaults maps models to versions configured in
+// place of the latest.
+func NewResolver(source Source, defaults map[string]string, ttl time.Duration, logger *zap.Logger) *Resolver {
+	return &Resolver{
+		source:   source,
+		defaults: defaults,
+		ttl:      ttl,
+		logger:   logger,
+		now:      time.Now,
+		entries:  make(map[string]entry),
+	}
+}
+
+// ParseDefaults parses a JSON object mapping model names to versions
+func ParseDefaults(value string) (map[string]string, error) {
+	defaults := make(map[string]string)
+	if value == "" {
+		return defaults, nil
+	}
+	if err := json.Unmarshal([]byte(value), &defaults); err != nil {
+		return nil, fmt.Errorf("invalid default model versions: %w", err)
+	}
+	for model, version := range defaults {
+		if version == "" {
+			return nil, fmt.Errorf("invalid default model versions: %s has an empty version", model)
+		}
+	}
+	return defaults, nil
+}
+
+// Resolve returns the version to serve model at. Models without an active
+// version, and lookups that fail, resolve to Fallback so the request can
+// still be routed.
+func (r *Resolver) Resolve(ctx context.Context, model string) string {
+	if r == nil {
+		return Fallback
+	}
+	if version, ok := r.defaults[model]; ok {
+		return version
+	}
+
+	// Tenants see their own models besides the shared ones
+	key := logging.FieldsFromContext(ctx).Tenant + "/" + model
+	now := r.now()
+	r.mu.Lock()
+	cached, ok := r.entries[key]
+	r.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.version
+	}
+
+	version, err := r.source.Latest(ctx, model)
+	if err != nil {
+		// Failures are cached too, so an unreachable metadata service is not
+		// asked on every request
+		logging.With(ctx, r.logger).Warn("failed to resolve default model version",
+			zap.String("model", model),
+			zap.Error(err),
+		)
+	}
+	if version == "" {
+		version = Fallback
+	}
+
+	r.mu.Lock()
+	r.entries[key] = entry{version: version, expires: now.Add(r.ttl)}
+	r.sweep(now)
+	r.mu.Unlock()
+	return version
+}
+
+// sweep drops expired entries once the cache has grown; r.mu must be held
+func (r *Resolver) sweep(now time.Time) {
+	if len(r.entries) < 1024 {
+		return
+	}
+	for key, cached := range r.entries {
+		if !now.Before(cached.expires) {
+			delete(r.entries, key)
+		}
+	}
+}
+
+// MetadataSource asks the metadata service at baseURL for models' latest
+// active versions
+func MetadataSource(client *http.Client, baseURL string) Source {
+	return &metadataSource{client: client, baseURL: baseURL}
+}
+
+type metadataSource struct {
+	client  *http.Client
+	baseURL string
+}
+
+func (s *metadataSource) Latest(ctx context.Context, model string) (string, error) {
+	query := url.Values{"name": {model}, "status": {"active"}, "limit": {"1"}}
+	req, err := http.NewRequestWithContext(ctx, "GET", s.baseURL+"/v1/models?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	logging.Inject(ctx, req)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", apperrors.FromTransportError(err, "metadata-service")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", apperrors.FromHTTPResponse(resp, "metadata-service")
+	}
+	// Models are listed newest first
+	var body struct {
+		Models []struct {
+			Version string `json:"version"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode metadata-service response: %w", err)
+	}
+	if len(body.Models) == 0 {
+		return "", nil
+	}
+	return body.Models[0].Version, nil
+}
@@ -0,0 +1,105 @@
+package versions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+type fakeSource struct {
+	versions map[string]string
+	err      error
+	calls    int
+}
+
+func (s *fakeSource) Latest(ctx context.Context, model string) (string, error) {
+	s.calls++
+	return s.versions[model], s.err
+}
+
+func TestResolver_PrefersConfiguredDefaults(t *testing.T) {
+	source := &fakeSource{versions: map[string]string{"resnet18": "v3", "bert": "v2"}}
+	resolver := NewResolver(source, map[string]string{"resnet18": "v2"}, time.Minute, zap.NewNop())
+
+	assert.Equal(t, "v2", resolver.Resolve(context.Background(), "resnet18"))
+	assert.Equal(t, "v2", resolver.Resolve(context.Background(), "bert"))
+	assert.Equal(t, Fallback, resolver.Resolve(context.Background(), "unknown"), "models without an active version")
+
+	var nilResolver *Resolver
+	assert.Equal(t, Fallback, nilResolver.Resolve(context.Background(), "bert"))
+}
+
+func TestResolver_CachesPerTenant(t *testing.T) {
+	source := &fakeSource{versions: map[string]string{"bert": "v2"}}
+	resolver := NewResolver(source, nil, time.Minute, zap.NewNop())
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	assert.Equal(t, "v2", resolver.Resolve(ctx, "bert"))
+	assert.Equal(t, "v2", resolver.Resolve(ctx, "bert"))
+	assert.Equal(t, 1, source.calls)
+
+	resolver.Resolve(logging.WithTenant(ctx, "acme"), "bert")
+	assert.Equal(t, 2, source.calls, "tenants may see different versions")
+
+	source.versions["bert"] = "v3"
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, "v3", resolver.Resolve(ctx, "bert"))
+}
+
+func TestResolver_FallsBackWhenSourceFails(t *testing.T) {
+	source := &fakeSource{err: errors.New("connection refused")}
+	resolver := NewResolver(source, nil, time.Minute, zap.NewNop())
+
+	assert.Equal(t, Fallback, resolver.Resolve(context.Background(), "bert"))
+	assert.Equal(t, Fallback, resolver.Resolve(context.Background(), "bert"))
+	assert.Equal(t, 1, source.calls, "failures are cached")
+}
+
+func TestParseDefaults(t *testing.T) {
+	defaults, err := ParseDefaults(`{"resnet18":"v2"}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"resnet18": "v2"}, defaults)
+
+	defaults, err = ParseDefaults("")
+	require.NoError(t, err)
+	assert.Empty(t, defaults)
+
+	_, err = ParseDefaults(`{"resnet18":""}`)
+	assert.Error(t, err)
+}
+
+func TestMetadataSource(t *testing.T) {
+	var tenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/models", r.URL.Path)
+		assert.Equal(t, "active", r.URL.Query().Get("status"))
+		tenant = r.Header.Get(logging.HeaderTenant)
+		if r.URL.Query().Get("name") == "bert" {
+			w.Write([]byte(`{"models":[{"name":"bert","version":"v4"}],"count":1}`))
+			return
+		}
+		w.Write([]byte(`{"models":[],"count":0}`))
+	}))
+	defer server.Close()
+
+	source := MetadataSource(server.Client(), server.URL)
+	version, err := source.Latest(logging.WithTenant(context.Background(), "acme"), "bert")
+	require.NoError(t, err)
+	assert.Equal(t, "v4", version)
+	assert.Equal(t, "acme", tenant)
+
+	version, err = source.Latest(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, version)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/yourusername/ai-platform/artifact-scanner/internal/config"
+	"github.com/yourusername/ai-platform/artifact-scanner/internal/handlers"
+	"github.com/yourusername/ai-platform/artifact-scanner/internal/registry"
+	"github.com/yourusername/ai-platform/artifact-scanner/internal/scan"
+	"github.com/yourusername/ai-platform/artifact-scanner/internal/store"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/secrets"
+	"github.com/yourusername/ai-platform/pkg/transport"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// Initialize logger
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize logger: %v", err))
+	}
+	defer logger.Sync()
+
+	// Load configuration
+	cfg := config.Load()
+	logger.Info("configuration loaded",
+		zap.String("service", cfg.ServiceName),
+		zap.String("port", cfg.Port),
+		zap.Int64("max_artifact_size", cfg.MaxArtifactSize),
+		zap.Bool("clamav", cfg.ClamAVAddr != ""),
+	)
+
+	// Resolve credentials from Vault or a mounted secret store when configured
+	secretProvider, err := secrets.FromEnv(logger)
+	if err != nil {
+		logger.Fatal("failed to initialize secret provider", zap.Error(err))
+	}
+	secretManager := secrets.NewManager(secretProvider, logger)
+	defer secretManager.Close()
+
+	minioKeyRefs := []string{cfg.SecretsPath + "#minio_access_key", cfg.SecretsPath + "#minio_secret_key"}
+	cfg.MinIOAccessKey = secretManager.LookupOr(context.Background(), minioKeyRefs[0], cfg.MinIOAccessKey)
+	cfg.MinIOSecretKey = secretManager.LookupOr(context.Background(), minioKeyRefs[1], cfg.MinIOSecretKey)
+	if cfg.MinIOAccessKey == "" || cfg.MinIOSecretKey == "" {
+		logger.Fatal("minio credentials are not configured; set MINIO_ACCESS_KEY/MINIO_SECRET_KEY or provide them via VAULT_ADDR")
+	}
+
+	// Load the SPIFFE workload identity for mTLS between services
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	metadataClient := &http.Client{Timeout: 10 * time.Second}
+	healthClient := &http.Client{Timeout: health.DefaultTimeout}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+		metadataClient = identity.HTTPClient("metadata-service", 10*time.Second)
+		healthClient = identity.HTTPClient("metadata-service", health.DefaultTimeout)
+	}
+
+	// Artifacts are published to the models bucket once they pass and kept in
+	// the quarantine bucket when they do not
+	artifactStore, err := store.NewMinIOStore(
+		cfg.MinIOEndpoint,
+		cfg.MinIOAccessKey,
+		cfg.MinIOSecretKey,
+		[]string{cfg.ModelsBucket, cfg.QuarantineBucket},
+		logger,
+	)
+	if err != nil {
+		logger.Fatal("failed to initialize minio store", zap.Error(err))
+	}
+	logger.Info("connected to MinIO")
+
+	// Use the new keys when the MinIO credentials rotate
+	secretManager.WatchAll(context.Background(), minioKeyRefs, 5*time.Minute, func(keys []string) {
+		if err := artifactStore.SetCredentials(keys[0], keys[1]); err != nil {
+			logger.Error("failed to update minio credentials", zap.Error(err))
+		}
+	})
+
+	scanner := scan.NewScanner(scan.Policy{
+		MaxSize:      cfg.MaxArtifactSize,
+		AllowedTypes: cfg.AllowedTypes,
+		SafeGlobals:  cfg.PickleSafeGlobals,
+	})
+	if cfg.ClamAVAddr != "" {
+		scanner.SetAntivirus(scan.NewClamAV(cfg.ClamAVAddr, cfg.ClamAVTimeout))
+	}
+
+	// Readiness requires the registry and the artifact buckets
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("metadata-service", health.HTTPCheck(healthClient, cfg.MetadataServiceURL+health.LivenessPath))
+	checker.Add("minio", artifactStore.Ping)
+
+	artifactHandler := handlers.NewArtifactHandler(
+		registry.NewClient(cfg.MetadataServiceURL, metadataClient),
+		artifactStore,
+		scanner,
+		cfg.ModelsBucket,
+		cfg.QuarantineBucket,
+		cfg.ScanDir,
+		logger,
+	)
+
+	// Announce rejected artifacts to the notification service when Kafka is configured
+	var eventEmitter *events.Emitter
+	if len(cfg.KafkaBrokers) > 0 {
+		kafkaProducer, err := events.NewKafkaProducer(cfg.KafkaBrokers)
+		if err != nil {
+			logger.Fatal("failed to initialize kafka producer", zap.Error(err))
+		}
+		defer kafkaProducer.Close()
+
+		schemaCodec := schema.FromEnv()
+		if err := schemaCodec.Register(context.Background(), schema.PlatformEvents); apperrors.Is(err, apperrors.FailedPrecondition) {
+			logger.Fatal("message schema is incompatible with the registry", zap.Error(err))
+		} else if err != nil {
+			logger.Warn("failed to register message schemas", zap.Error(err))
+		}
+
+		eventEmitter = events.NewEmitter(cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+			value, err := schemaCodec.Frame(ctx, schema.PlatformEvents, value)
+			if err != nil {
+				return err
+			}
+			_, _, err = kafkaProducer.SendMessage(&sarama.ProducerMessage{
+				Topic: cfg.EventTopic,
+				Key:   sarama.StringEncoder(key),
+				Value: sarama.ByteEncoder(value),
+			})
+			return err
+		}), 100, logger)
+		artifactHandler.SetEventEmitter(eventEmitter)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventsDone := make(chan struct{})
+	go func() {
+		if eventEmitter != nil {
+			eventEmitter.Run(ctx)
+		}
+		close(eventsDone)
+	}()
+
+	// Setup router
+	if cfg.LogLevel == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Health checks
+	router.GET("/health", gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.LivenessPath, gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.ReadinessPath, gin.WrapH(checker.ReadinessHandler()))
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Artifact uploads
+	artifactHandler.Register(router.Group("/v1"))
+
+	// Create HTTP server. Artifacts can be gigabytes and are scanned before
+	// the response, so reads and writes get far longer than other services.
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           logging.Middleware(router),
+		ReadHeaderTimeout: 15 * time.Second,
+		ReadTimeout:       30 * time.Minute,
+		WriteTimeout:      45 * time.Minute,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	go func() {
+		logger.Info("starting artifact scanner", zap.String("port", cfg.Port))
+		if err := transport.ListenAndServe(srv, identity); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("failed to start server", zap.Error(err))
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("shutting down server...")
+
+	// Let in-flight scans finish before the last events are flushed
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("server forced to shutdown", zap.Error(err))
+	}
+
+	cancel()
+	<-eventsDone
+
+	logger.Info("server exited")
+}
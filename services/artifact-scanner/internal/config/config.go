@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the artifact scanner configuration
+type Config struct {
+	ServiceName        string
+	Port               string
+	MetadataServiceURL string
+	SecretsPath        string
+	JaegerEndpoint     string
+	LogLevel           string
+
+	// Passed artifacts are published to the models bucket serving backends
+	// load from; rejected ones are kept in the quarantine bucket for review
+	MinIOEndpoint    string
+	MinIOAccessKey   string
+	MinIOSecretKey   string
+	ModelsBucket     string
+	QuarantineBucket string
+
+	// Scan policy
+	MaxArtifactSize   int64
+	AllowedTypes      []string
+	PickleSafeGlobals []string
+	ScanDir           string
+
+	// ClamAV is only consulted when an address is configured
+	ClamAVAddr    string
+	ClamAVTimeout time.Duration
+
+	// model.quarantined events are only published when brokers are configured
+	KafkaBrokers []string
+	EventTopic   string
+}
+
+// Load loads configuration from environment variables
+func Load() *Config {
+	return &Config{
+		ServiceName:        getEnv("SERVICE_NAME", "artifact-scanner"),
+		Port:               getEnv("PORT", "8092"),
+		MetadataServiceURL: getEnv("METADATA_SERVICE_URL", "http://localhost:8083"),
+		SecretsPath:        getEnv("SECRETS_PATH", "secret/data/artifact-scanner"),
+		JaegerEndpoint:     getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
+		LogLevel:           getEnv("LOG_LEVEL", "info"),
+
+		MinIOEndpoint:    getEnv("MINIO_ENDPOINT", "localhost:9000"),
+		MinIOAccessKey:   getEnv("MINIO_ACCESS_KEY", ""),
+		MinIOSecretKey:   getEnv("MINIO_SECRET_KEY", ""),
+		ModelsBucket:     getEnv("MODELS_BUCKET", "models"),
+		QuarantineBucket: getEnv("QUARANTINE_BUCKET", "models-quarantine"),
+
+		MaxArtifactSize:   getEnvInt64("MAX_ARTIFACT_SIZE", 2<<30),
+		AllowedTypes:      getEnvList("ALLOWED_ARTIFACT_TYPES"),
+		PickleSafeGlobals: getEnvList("PICKLE_SAFE_GLOBALS"),
+		ScanDir:           getEnv("SCAN_DIR", os.TempDir()),
+
+		ClamAVAddr:    getEnv("CLAMAV_ADDR", ""),
+		ClamAVTimeout: getEnvDuration("CLAMAV_TIMEOUT", 5*time.Minute),
+
+		KafkaBrokers: getEnvList("KAFKA_BROKERS"),
+		EventTopic:   getEnv("EVENT_TOPIC", "platform-events"),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvList(key string) []string {
+	if value := os.Getenv(key); value != "" {
+		return strings.Split(value, ",")
+	}
+	return nil
+}
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
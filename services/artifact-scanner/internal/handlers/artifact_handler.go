@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/artifact-scanner/internal/registry"
+	"github.com/yourusername/ai-platform/artifact-scanner/internal/scan"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Metadata keys the scan outcome is recorded under on the model
+const (
+	MetaScan           = "artifact_scan"
+	MetaScannedAt      = "artifact_scanned_at"
+	MetaFindings       = "artifact_findings"
+	MetaURI            = "artifact_uri"
+	MetaSHA256         = "artifact_sha256"
+	MetaQuarantinedURI = "artifact_quarantined_uri"
+)
+
+// Scan states recorded under MetaScan besides the scan verdicts
+const (
+	scanPending = "pending"
+	scanError   = "error"
+)
+
+// Registry reads and updates registered models
+type Registry interface {
+	Get(ctx context.Context, id string) (*registry.Model, error)
+	SetStatus(ctx context.Context, id, status string, metadata map[string]string) (*registry.Model, error)
+}
+
+// Store keeps artifacts
+type Store interface {
+	Put(ctx context.Context, bucket, object string, r io.Reader, size int64, metadata map[string]string) (string, error)
+}
+
+// ArtifactHandler accepts model artifact uploads. Each upload quarantines the
+// model, is scanned, and only reaches the bucket serving backends load from
+// when it passes; the model is then made active again.
+type ArtifactHandler struct {
+	registry         Registry
+	store            Store
+	scanner          *scan.Scanner
+	modelsBucket     string
+	quarantineBucket string
+	scanDir          string
+	events           *events.Emitter
+	logger           *zap.Logger
+}
+
+// NewArtifactHandler creates a new artifact handler. Uploads are spooled to
+// scanDir while they are scanned.
+func NewArtifactHandler(registry Registry, store Store, scanner *scan.Scanner, modelsBucket, quarantineBucket, scanDir string, logger *zap.Logger) *ArtifactHandler {
+	return &ArtifactHandler{
+		registry:         registry,
+		store:            store,
+		scanner:          scanner,
+		modelsBucket:     modelsBucket,
+		quarantineBucket: quarantineBucket,
+		scanDir:          scanDir,
+		logger:           logger,
+	}
+}
+
+// SetEventEmitter sets where rejected artifacts are announced
+func (h *ArtifactHandler) SetEventEmitter(emitter *events.Emitter) {
+	h.events = emitter
+}
+
+// Register adds the artifact routes to group
+func (h *ArtifactHandler) Register(group *gin.RouterGroup) {
+	group.POST("/models/:id/artifact", h.Upload)
+}
+
+// UploadResponse is the outcome of an upload
+type UploadResponse struct {
+	ModelID string       `json:"model_id"`
+	Status  string       `json:"status"`
+	URI     string       `json:"uri,omitempty"`
+	Report  *scan.Report `json:"report"`
+}
+
+// log returns the handler logger annotated with the request's correlation fields
+func (h *ArtifactHandler) log(c *gin.Context) *zap.Logger {
+	return logging.With(c.Request.Context(), h.logger)
+}
+
+// Upload scans the request body as the artifact of a model. The file name
+// comes from the filename query parameter and decides the artifact type.
+func (h *ArtifactHandler) Upload(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	filename := c.Query("filename")
+	if filename == "" || filename != path.Base(filename) || strings.ContainsAny(filename, `/\`) || strings.HasPrefix(filename, ".") {
+		c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.InvalidArgument, "filename must be a plain file name such as model.onnx")))
+		return
+	}
+	maxSize := h.scanner.MaxSize()
+	if maxSize > 0 && c.Request.ContentLength > maxSize {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "artifact exceeds the %d byte limit", maxSize)))
+		return
+	}
+
+	// Tenants may only upload artifacts for their own models
+	model, err := h.registry.Get(ctx, id)
+	if err == nil {
+		if tenant := logging.FieldsFromContext(ctx).Tenant; tenant != "" && model.Tenant != tenant {
+			err = apperrors.Newf(apperrors.PermissionDenied, "model %s does not belong to tenant %s", id, tenant)
+		}
+	}
+	if err != nil {
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to get model")))
+		return
+	}
+
+	// Quarantine the model before accepting the artifact, so it cannot be
+	// served from an artifact that has not been scanned
+	metadata := withScan(model.Metadata, scanPending)
+	if _, err := h.registry.SetStatus(ctx, id, registry.Quarantined, metadata); err != nil {
+		h.log(c).Error("failed to quarantine model", zap.String("model_id", id), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to quarantine model")))
+		return
+	}
+
+	report, file, err := h.scan(ctx, filename, c.Request.Body, maxSize)
+	if file != nil {
+		defer os.Remove(file.Name())
+		defer file.Close()
+	}
+	if err != nil {
+		h.log(c).Error("failed to scan artifact", zap.String("model_id", id), zap.String("file", filename), zap.Error(err))
+		if _, err := h.registry.SetStatus(ctx, id, registry.Quarantined, withScan(model.Metadata, scanError)); err != nil {
+			h.log(c).Warn("failed to record scan error", zap.String("model_id", id), zap.Error(err))
+		}
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Unavailable, "failed to scan artifact")))
+		return
+	}
+
+	metadata = withScan(model.Metadata, report.Verdict)
+	metadata[MetaScannedAt] = report.ScannedAt.Format(time.RFC3339)
+	objectMetadata := map[string]string{"model-id": id, "sha256": report.SHA256}
+
+	if report.Verdict == scan.Rejected {
+		metadata[MetaFindings] = strings.Join(report.Rules(), ",")
+		// Truncated uploads over the size limit are not worth keeping
+		if report.Size <= maxSize || maxSize <= 0 {
+			object := id + "/" + report.SHA256[:12] + "-" + filename
+			uri, err := h.store.Put(ctx, h.quarantineBucket, object, io.NewSectionReader(file, 0, report.Size), report.Size, objectMetadata)
+			if err != nil {
+				h.log(c).Warn("failed to keep rejected artifact", zap.String("model_id", id), zap.Error(err))
+			} else {
+				metadata[MetaQuarantinedURI] = uri
+			}
+		}
+		if _, err := h.registry.SetStatus(ctx, id, registry.Quarantined, metadata); err != nil {
+			h.log(c).Error("failed to record rejected artifact", zap.String("model_id", id), zap.Error(err))
+		}
+
+		h.log(c).Warn("artifact rejected",
+			zap.String("model_id", id),
+			zap.String("file", filename),
+			zap.Strings("rules", report.Rules()),
+		)
+		h.events.Emit(ctx, events.Event{
+			Type:     events.ModelQuarantined,
+			Severity: events.SeverityWarning,
+			Tenant:   model.Tenant,
+			Subject:  model.Name + "/" + model.Version,
+			Attributes: map[string]string{
+				"id":       id,
+				"model":    model.Name,
+				"version":  model.Version,
+				"file":     filename,
+				"findings": metadata[MetaFindings],
+			},
+		})
+		c.JSON(http.StatusUnprocessableEntity, UploadResponse{ModelID: id, Status: registry.Quarantined, Report: report})
+		return
+	}
+
+	// Publish where serving backends load from, then release the model
+	object := model.Name + "/" + model.Version + "/" + filename
+	uri, err := h.store.Put(ctx, h.modelsBucket, object, io.NewSectionReader(file, 0, report.Size), report.Size, objectMetadata)
+	if err != nil {
+		h.log(c).Error("failed to publish artifact", zap.String("model_id", id), zap.Error(err))
+		if _, err := h.registry.SetStatus(ctx, id, registry.Quarantined, withScan(model.Metadata, scanError)); err != nil {
+			h.log(c).Warn("failed to record scan error", zap.String("model_id", id), zap.Error(err))
+		}
+		c.JSON(apperrors.ToHTTP(apperrors.Wrap(err, apperrors.Unavailable, "failed to store artifact")))
+		return
+	}
+	metadata[MetaURI] = uri
+	metadata[MetaSHA256] = report.SHA256
+
+	updated, err := h.registry.SetStatus(ctx, id, "active", metadata)
+	if err != nil {
+		h.log(c).Error("failed to release model from quarantine", zap.String("model_id", id), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to release model from quarantine")))
+		return
+	}
+
+	h.log(c).Info("artifact passed scan",
+		zap.String("model_id", id),
+		zap.String("file", filename),
+		zap.String("uri", uri),
+	)
+	c.JSON(http.StatusOK, UploadResponse{ModelID: id, Status: updated.Status, URI: uri, Report: report})
+}
+
+// scan spools body to a temporary file, reading at most one byte past
+// maxSize, and scans it. The caller removes the returned file.
+func (h *ArtifactHandler) scan(ctx context.Context, filename string, body io.Reader, maxSize int64) (*scan.Report, *os.File, error) {
+	file, err := os.CreateTemp(h.scanDir, "artifact-*")
+	if err != nil {
+		return nil, nil, apperrors.Wrap(err, apperrors.Internal, "failed to spool artifact")
+	}
+
+	if maxSize > 0 {
+		body = io.LimitReader(body, maxSize+1)
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), body)
+	if err != nil {
+		return nil, file, apperrors.Wrap(err, apperrors.InvalidArgument, "failed to read artifact")
+	}
+
+	report, err := h.scanner.Scan(ctx, filename, file, size)
+	if err != nil {
+		return nil, file, err
+	}
+	report.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	report.ScannedAt = time.Now().UTC()
+	return report, file, nil
+}
+
+// withScan returns a copy of metadata recording a new scan state, without
+// the outcome of earlier scans
+func withScan(metadata map[string]string, state string) map[string]string {
+	updated := make(map[string]string, len(metadata)+1)
+	for key, value := range metadata {
+		switch key {
+		case MetaScannedAt, MetaFindings, MetaQuarantinedURI:
+			continue
+		}
+		updated[key] = value
+	}
+	updated[MetaScan] = state
+	return updated
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/artifact-scanner/internal/registry"
+	"github.com/yourusername/ai-platform/artifact-scanner/internal/scan"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// fakeRegistry holds models and records the statuses they went through
+type fakeRegistry struct {
+	models   map[string]*registry.Model
+	statuses []string
+}
+
+func (r *fakeRegistry) Get(ctx context.Context, id string) (*registry.Model, error) {
+	model, ok := r.models[id]
+	if !ok {
+		return nil, apperrors.Newf(apperrors.NotFound, "model not found: %s", id)
+	}
+	copied := *model
+	return &copied, nil
+}
+
+func (r *fakeRegistry) SetStatus(ctx context.Context, id, status string, metadata map[string]string) (*registry.Model, error) {
+	model := r.models[id]
+	model.Status = status
+	model.Metadata = metadata
+	r.statuses = append(r.statuses, status)
+	copied := *model
+	return &copied, nil
+}
+
+// fakeStore keeps objects by bucket/object
+type fakeStore struct {
+	objects map[string][]byte
+}
+
+func (s *fakeStore) Put(ctx context.Context, bucket, object string, r io.Reader, size int64, metadata map[string]string) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	s.objects[bucket+"/"+object] = data
+	return "s3://" + bucket + "/" + object, nil
+}
+
+func setupRouter(t *testing.T) (*gin.Engine, *fakeRegistry, *fakeStore) {
+	gin.SetMode(gin.TestMode)
+	reg := &fakeRegistry{models: map[string]*registry.Model{
+		"m1": {ID: "m1", Name: "resnet18", Version: "v1", Status: "active", Tenant: "acme", Metadata: map[string]string{"owner": "vision"}},
+	}}
+	store := &fakeStore{objects: make(map[string][]byte)}
+
+	handler := NewArtifactHandler(reg, store, scan.NewScanner(scan.Policy{MaxSize: 1024}), "models", "models-quarantine", t.TempDir(), zap.NewNop())
+	router := gin.New()
+	handler.Register(router.Group("/v1"))
+	return router, reg, store
+}
+
+func upload(router *gin.Engine, url string, body []byte, tenant string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", url, bytes.NewReader(body))
+	if tenant != "" {
+		req = req.WithContext(logging.WithTenant(req.Context(), tenant))
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestArtifactHandler_PassedArtifactIsPublished(t *testing.T) {
+	router, reg, store := setupRouter(t)
+	artifact := []byte("\x08\x07\x12\x07pytorch")
+
+	w := upload(router, "/v1/models/m1/artifact?filename=model.onnx", artifact, "acme")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp UploadResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "active", resp.Status)
+	assert.Equal(t, "s3://models/resnet18/v1/model.onnx", resp.URI)
+	assert.Equal(t, scan.Passed, resp.Report.Verdict)
+
+	assert.Equal(t, []string{registry.Quarantined, "active"}, reg.statuses)
+	assert.Equal(t, artifact, store.objects["models/resnet18/v1/model.onnx"])
+
+	metadata := reg.models["m1"].Metadata
+	assert.Equal(t, "vision", metadata["owner"])
+	assert.Equal(t, scan.Passed, metadata[MetaScan])
+	assert.Equal(t, resp.URI, metadata[MetaURI])
+	assert.Len(t, metadata[MetaSHA256], 64)
+}
+
+func TestArtifactHandler_RejectedArtifactStaysQuarantined(t *testing.T) {
+	router, reg, store := setupRouter(t)
+	// A pickle calling os.system, named like an ONNX model
+	artifact := []byte("\x80\x02cposix\nsystem\nq\x00X\x02\x00\x00\x00idq\x01\x85q\x02Rq\x03.")
+
+	w := upload(router, "/v1/models/m1/artifact?filename=model.onnx", artifact, "")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+	var resp UploadResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, registry.Quarantined, resp.Status)
+	assert.Equal(t, scan.Rejected, resp.Report.Verdict)
+
+	assert.Equal(t, []string{registry.Quarantined, registry.Quarantined}, reg.statuses)
+	metadata := reg.models["m1"].Metadata
+	assert.Equal(t, scan.Rejected, metadata[MetaScan])
+	assert.Equal(t, scan.RuleFileType, metadata[MetaFindings])
+	assert.Contains(t, metadata[MetaQuarantinedURI], "s3://models-quarantine/m1/")
+
+	// Nothing reaches the bucket serving backends load from
+	for key := range store.objects {
+		assert.NotContains(t, key, "models/resnet18")
+	}
+}
+
+func TestArtifactHandler_RejectsBadRequests(t *testing.T) {
+	router, reg, _ := setupRouter(t)
+	artifact := []byte("\x08\x07")
+
+	assert.Equal(t, http.StatusBadRequest, upload(router, "/v1/models/m1/artifact", artifact, "").Code)
+	assert.Equal(t, http.StatusBadRequest, upload(router, "/v1/models/m1/artifact?filename=../model.onnx", artifact, "").Code)
+	assert.Equal(t, http.StatusBadRequest, upload(router, "/v1/models/m1/artifact?filename=model.onnx", make([]byte, 2048), "").Code)
+	assert.Equal(t, http.StatusNotFound, upload(router, "/v1/models/m2/artifact?filename=model.onnx", artifact, "").Code)
+	assert.Equal(t, http.StatusForbidden, upload(router, "/v1/models/m1/artifact?filename=model.onnx", artifact, "globex").Code)
+
+	// None of them touched the model
+	assert.Empty(t, reg.statuses)
+}
@@ -0,0 +1,97 @@
+// Package registry reads and updates models in the metadata service
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Quarantined is the status of models whose artifact has not passed a scan.
+// Serving backends only load artifacts from models that left it.
+const Quarantined = "quarantined"
+
+// Model is the part of a registered model the scanner needs
+type Model struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Version  string            `json:"version"`
+	Status   string            `json:"status"`
+	Tenant   string            `json:"tenant,omitempty"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// Client talks to the metadata service
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient creates a client for the metadata service at baseURL
+func NewClient(baseURL string, client *http.Client) *Client {
+	return &Client{baseURL: baseURL, client: client}
+}
+
+// Get returns the model, as visible to the tenant in ctx
+func (c *Client) Get(ctx context.Context, id string) (*Model, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/models/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	logging.Inject(ctx, req)
+
+	var model Model
+	if err := c.do(req, &model); err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// SetStatus changes the model's status and replaces its metadata. Status
+// changes are made as the platform rather than the uploading tenant, since
+// tenants may not move models in or out of quarantine.
+func (c *Client) SetStatus(ctx context.Context, id, status string, metadata map[string]string) (*Model, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"status":   status,
+		"metadata": metadata,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal update: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/v1/models/"+url.PathEscape(id), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	logging.Inject(ctx, req)
+	req.Header.Del(logging.HeaderTenant)
+
+	var model Model
+	if err := c.do(req, &model); err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+func (c *Client) do(req *http.Request, out interface{}) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "metadata-service")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.FromHTTPResponse(resp, "metadata-service")
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode metadata-service response: %w", err)
+	}
+	return nil
+}
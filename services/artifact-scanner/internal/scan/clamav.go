@@ -0,0 +1,91 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// clamChunkSize is how much of the artifact is sent to clamd per INSTREAM chunk
+const clamChunkSize = 64 * 1024
+
+// ClamAV scans artifacts with a clamd daemon over its INSTREAM protocol.
+// clamd's StreamMaxLength must be at least the artifact size limit, or large
+// artifacts fail to scan.
+type ClamAV struct {
+	addr    string
+	timeout time.Duration
+}
+
+// NewClamAV creates a client for the clamd daemon at addr (host:port)
+func NewClamAV(addr string, timeout time.Duration) *ClamAV {
+	return &ClamAV{addr: addr, timeout: timeout}
+}
+
+// Scan streams r to clamd and returns the name of the signature it matched,
+// or "" when the stream is clean
+func (c *ClamAV) Scan(ctx context.Context, r io.Reader) (string, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return "", apperrors.Wrap(err, apperrors.Unavailable, "clamd unreachable")
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return "", apperrors.Wrap(err, apperrors.Unavailable, "failed to write to clamd")
+	}
+
+	buf := make([]byte, 4+clamChunkSize)
+	for {
+		n, readErr := r.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, err := conn.Write(buf[:4+n]); err != nil {
+				return "", apperrors.Wrap(err, apperrors.Unavailable, "failed to write to clamd")
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return "", fmt.Errorf("failed to read artifact: %w", readErr)
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return "", apperrors.Wrap(err, apperrors.Unavailable, "failed to write to clamd")
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return "", apperrors.Wrap(err, apperrors.Unavailable, "failed to read clamd reply")
+	}
+	return parseClamReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamReply interprets "stream: OK", "stream: <signature> FOUND" and
+// "<message> ERROR" replies
+func parseClamReply(reply string) (string, error) {
+	reply = strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case reply == "OK":
+		return "", nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return strings.TrimSuffix(reply, " FOUND"), nil
+	default:
+		return "", apperrors.Newf(apperrors.Unavailable, "clamd failed to scan artifact: %s", reply)
+	}
+}
@@ -0,0 +1,89 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// fakeClamd answers one INSTREAM command per connection, flagging streams
+// that contain "EICAR"
+func fakeClamd(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				command := make([]byte, len("zINSTREAM\x00"))
+				if _, err := io.ReadFull(conn, command); err != nil || string(command) != "zINSTREAM\x00" {
+					conn.Write([]byte("UNKNOWN COMMAND\x00"))
+					return
+				}
+
+				var stream bytes.Buffer
+				for {
+					var size uint32
+					if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					io.CopyN(&stream, conn, int64(size))
+				}
+
+				reply := "stream: OK\x00"
+				if strings.Contains(stream.String(), "EICAR") {
+					reply = "stream: Eicar-Test-Signature FOUND\x00"
+				}
+				conn.Write([]byte(reply))
+			}(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestClamAV_Scan(t *testing.T) {
+	clam := NewClamAV(fakeClamd(t), 5*time.Second)
+
+	signature, err := clam.Scan(context.Background(), bytes.NewReader(make([]byte, 3*clamChunkSize+7)))
+	require.NoError(t, err)
+	assert.Empty(t, signature)
+
+	signature, err = clam.Scan(context.Background(), strings.NewReader("X5O!P%@AP EICAR"))
+	require.NoError(t, err)
+	assert.Equal(t, "Eicar-Test-Signature", signature)
+}
+
+func TestClamAV_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	_, err = NewClamAV(addr, time.Second).Scan(context.Background(), strings.NewReader("model"))
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable))
+}
+
+func TestParseClamReply(t *testing.T) {
+	_, err := parseClamReply("INSTREAM size limit exceeded. ERROR")
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable))
+}
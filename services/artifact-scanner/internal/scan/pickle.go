@@ -0,0 +1,277 @@
+package scan
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SafeGlobals are the imports a pickled model may make: the containers and
+// tensor rebuild functions PyTorch and NumPy use to serialize weights. Any
+// other import can run arbitrary code when the pickle is loaded.
+var SafeGlobals = []string{
+	"collections.OrderedDict",
+	"torch._utils._rebuild_tensor",
+	"torch._utils._rebuild_tensor_v2",
+	"torch._utils._rebuild_parameter",
+	"torch._utils._rebuild_parameter_with_state",
+	"torch._tensor._rebuild_from_type_v2",
+	"torch.Size",
+	"torch.device",
+	"torch.BFloat16Storage",
+	"torch.BoolStorage",
+	"torch.ByteStorage",
+	"torch.CharStorage",
+	"torch.DoubleStorage",
+	"torch.FloatStorage",
+	"torch.HalfStorage",
+	"torch.IntStorage",
+	"torch.LongStorage",
+	"torch.ShortStorage",
+	"numpy.core.multiarray._reconstruct",
+	"numpy.core.multiarray.scalar",
+	"numpy.ndarray",
+	"numpy.dtype",
+}
+
+// errUnknownOpcode stops a pickle walk at a byte that is not an opcode
+var errUnknownOpcode = errors.New("unknown pickle opcode")
+
+// pickleImports returns every module.name global the pickle stream in r
+// imports, in order. Imports whose name is computed rather than a string
+// literal are reported as "?". Concatenated pickles are all read.
+func pickleImports(r io.Reader) ([]string, error) {
+	br := bufio.NewReader(r)
+	var imports []string
+	for {
+		found, err := walkPickle(br)
+		imports = append(imports, found...)
+		if err != nil {
+			return imports, err
+		}
+		if _, err := br.Peek(1); err == io.EOF {
+			return imports, nil
+		}
+	}
+}
+
+// walkPickle reads one pickle up to its STOP opcode. It does not execute
+// anything; it only tracks the strings pushed on the stack and memo so the
+// module and name of STACK_GLOBAL imports can be resolved.
+func walkPickle(r *bufio.Reader) ([]string, error) {
+	var (
+		imports []string
+		stack   []*string
+		memo    = make(map[uint64]*string)
+	)
+	push := func(s *string) { stack = append(stack, s) }
+	pushString := func(s string) { push(&s) }
+	top := func() *string {
+		if len(stack) == 0 {
+			return nil
+		}
+		return stack[len(stack)-1]
+	}
+
+	for {
+		op, err := r.ReadByte()
+		if err != nil {
+			return imports, unexpectedEOF(err)
+		}
+
+		switch op {
+		case '.': // STOP
+			return imports, nil
+
+		// Imports
+		case 'c', 'i': // GLOBAL, INST
+			module, err := readLine(r)
+			if err != nil {
+				return imports, err
+			}
+			name, err := readLine(r)
+			if err != nil {
+				return imports, err
+			}
+			imports = append(imports, module+"."+name)
+			push(nil)
+		case 0x93: // STACK_GLOBAL
+			global := "?"
+			if len(stack) >= 2 && stack[len(stack)-2] != nil && stack[len(stack)-1] != nil {
+				global = *stack[len(stack)-2] + "." + *stack[len(stack)-1]
+			}
+			imports = append(imports, global)
+			push(nil)
+
+		// Strings, tracked so STACK_GLOBAL can be resolved
+		case 'S', 'V': // STRING, UNICODE
+			line, err := readLine(r)
+			if err != nil {
+				return imports, err
+			}
+			pushString(strings.Trim(line, `'"`))
+		case 'U', 'C', 0x8c: // SHORT_BINSTRING, SHORT_BINBYTES, SHORT_BINUNICODE
+			s, err := readCounted(r, 1)
+			if err != nil {
+				return imports, err
+			}
+			pushString(s)
+		case 'T', 'X', 'B': // BINSTRING, BINUNICODE, BINBYTES
+			s, err := readCounted(r, 4)
+			if err != nil {
+				return imports, err
+			}
+			pushString(s)
+		case 0x8d, 0x8e, 0x96: // BINUNICODE8, BINBYTES8, BYTEARRAY8
+			s, err := readCounted(r, 8)
+			if err != nil {
+				return imports, err
+			}
+			pushString(s)
+
+		// Memo
+		case 0x94: // MEMOIZE
+			memo[uint64(len(memo))] = top()
+		case 'p': // PUT
+			line, err := readLine(r)
+			if err != nil {
+				return imports, err
+			}
+			var index uint64
+			fmt.Sscan(line, &index)
+			memo[index] = top()
+		case 'q': // BINPUT
+			index, err := readUint(r, 1)
+			if err != nil {
+				return imports, err
+			}
+			memo[index] = top()
+		case 'r': // LONG_BINPUT
+			index, err := readUint(r, 4)
+			if err != nil {
+				return imports, err
+			}
+			memo[index] = top()
+		case 'g': // GET
+			line, err := readLine(r)
+			if err != nil {
+				return imports, err
+			}
+			var index uint64
+			fmt.Sscan(line, &index)
+			push(memo[index])
+		case 'h': // BINGET
+			index, err := readUint(r, 1)
+			if err != nil {
+				return imports, err
+			}
+			push(memo[index])
+		case 'j': // LONG_BINGET
+			index, err := readUint(r, 4)
+			if err != nil {
+				return imports, err
+			}
+			push(memo[index])
+
+		// Framing
+		case 0x80: // PROTO
+			if _, err := r.Discard(1); err != nil {
+				return imports, unexpectedEOF(err)
+			}
+		case 0x95: // FRAME
+			if _, err := r.Discard(8); err != nil {
+				return imports, unexpectedEOF(err)
+			}
+
+		// Everything else only matters for the arguments to skip
+		default:
+			size, ok := opcodeArgs[op]
+			if !ok {
+				return imports, fmt.Errorf("%w 0x%02x", errUnknownOpcode, op)
+			}
+			switch size {
+			case argLine:
+				if _, err := readLine(r); err != nil {
+					return imports, err
+				}
+			case argCounted1, argCounted4:
+				width := 1
+				if size == argCounted4 {
+					width = 4
+				}
+				if _, err := readCounted(r, width); err != nil {
+					return imports, err
+				}
+			default:
+				if _, err := r.Discard(size); err != nil {
+					return imports, unexpectedEOF(err)
+				}
+			}
+			push(nil)
+		}
+	}
+}
+
+// Argument encodings of opcodes without special handling; non-negative
+// sizes are fixed-width arguments
+const (
+	argLine     = -1
+	argCounted1 = -2
+	argCounted4 = -3
+)
+
+var opcodeArgs = map[byte]int{
+	'(': 0, '0': 0, '1': 0, '2': 0, 'N': 0, 'Q': 0, 'R': 0, 'a': 0, 'b': 0,
+	'd': 0, '}': 0, 'e': 0, 'l': 0, ']': 0, 'o': 0, 's': 0, 't': 0, ')': 0,
+	'u': 0, 0x81: 0, 0x85: 0, 0x86: 0, 0x87: 0, 0x88: 0, 0x89: 0, 0x8f: 0,
+	0x90: 0, 0x91: 0, 0x92: 0, 0x97: 0, 0x98: 0,
+	'F': argLine, 'I': argLine, 'L': argLine, 'P': argLine,
+	'K': 1, 'M': 2, 'J': 4, 'G': 8, 0x82: 1, 0x83: 2, 0x84: 4,
+	0x8a: argCounted1, 0x8b: argCounted4,
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", unexpectedEOF(err)
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+func readUint(r *bufio.Reader, width int) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:width]); err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+// readCounted reads a length-prefixed argument. Only short values are kept;
+// longer ones (tensor bytes) are skipped since they cannot name an import.
+func readCounted(r *bufio.Reader, width int) (string, error) {
+	n, err := readUint(r, width)
+	if err != nil {
+		return "", err
+	}
+	if n > 1024 {
+		if _, err := io.CopyN(io.Discard, r, int64(n)); err != nil {
+			return "", unexpectedEOF(err)
+		}
+		return "", nil
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", unexpectedEOF(err)
+	}
+	return string(buf), nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package scan
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Pickles as Python writes them
+var (
+	// pickle.dumps(collections.OrderedDict(), protocol=2)
+	safePickle = []byte("\x80\x02ccollections\nOrderedDict\nq\x00)Rq\x01.")
+	// A __reduce__ returning (os.system, ("id",)), protocol 2
+	globalPickle = []byte("\x80\x02cposix\nsystem\nq\x00X\x02\x00\x00\x00idq\x01\x85q\x02Rq\x03.")
+	// The same with protocol 4, importing through STACK_GLOBAL
+	stackGlobalPickle = []byte("\x80\x04\x95\x1d\x00\x00\x00\x00\x00\x00\x00\x8c\x05posix\x94\x8c\x06system\x94\x93\x94\x8c\x02id\x94\x85\x94R\x94.")
+	// STACK_GLOBAL whose module and name come from the memo
+	memoPickle = []byte("\x80\x04\x8c\x08builtins\x94\x8c\x04eval\x94N0h\x00h\x01\x93\x94.")
+)
+
+func TestPickleImports(t *testing.T) {
+	tests := []struct {
+		name    string
+		pickle  []byte
+		imports []string
+	}{
+		{"global", safePickle, []string{"collections.OrderedDict"}},
+		{"reduce", globalPickle, []string{"posix.system"}},
+		{"stack global", stackGlobalPickle, []string{"posix.system"}},
+		{"memo", memoPickle, []string{"builtins.eval"}},
+		{"concatenated", append(append([]byte{}, safePickle...), globalPickle...), []string{"collections.OrderedDict", "posix.system"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imports, err := pickleImports(bytes.NewReader(tt.pickle))
+			require.NoError(t, err)
+			assert.Equal(t, tt.imports, imports)
+		})
+	}
+}
+
+func TestPickleImports_Malformed(t *testing.T) {
+	_, err := pickleImports(bytes.NewReader([]byte("\x80\x02cposix\nsys")))
+	assert.Error(t, err)
+
+	_, err = pickleImports(bytes.NewReader([]byte("\x80\x02\xff.")))
+	assert.ErrorIs(t, err, errUnknownOpcode)
+}
@@ -0,0 +1,303 @@
+// Package scan inspects uploaded model artifacts before serving backends may
+// load them: size limits, a file-type allowlist, pickle imports that would
+// run code on load, and optionally a ClamAV signature scan.
+package scan
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Verdicts
+const (
+	Passed   = "passed"
+	Rejected = "rejected"
+)
+
+// Rules a finding can break
+const (
+	RuleSize     = "size_limit"
+	RuleFileType = "file_type"
+	RulePickle   = "unsafe_pickle"
+	RuleMalware  = "malware"
+)
+
+// DefaultAllowedTypes are the artifact types accepted unless configured
+// otherwise. Plain pickles are left out: they exist only to hold Python objects.
+var DefaultAllowedTypes = []string{"onnx", "safetensors", "pytorch", "tensorflow", "tensorrt"}
+
+// extensionTypes maps file extensions to artifact types
+var extensionTypes = map[string]string{
+	".onnx":        "onnx",
+	".safetensors": "safetensors",
+	".pt":          "pytorch",
+	".pth":         "pytorch",
+	".pb":          "tensorflow",
+	".plan":        "tensorrt",
+	".engine":      "tensorrt",
+	".pkl":         "pickle",
+	".pickle":      "pickle",
+	".joblib":      "pickle",
+}
+
+// Finding is a reason to reject an artifact
+type Finding struct {
+	Rule string `json:"rule"`
+	// File is the archive entry the finding is about, if any
+	File   string `json:"file,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// Report is the outcome of scanning one artifact
+type Report struct {
+	File      string    `json:"file"`
+	Type      string    `json:"type,omitempty"`
+	Size      int64     `json:"size"`
+	SHA256    string    `json:"sha256,omitempty"`
+	Verdict   string    `json:"verdict"`
+	Findings  []Finding `json:"findings,omitempty"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+// Rules returns the distinct rules the findings break
+func (r *Report) Rules() []string {
+	seen := make(map[string]bool)
+	rules := make([]string, 0, len(r.Findings))
+	for _, finding := range r.Findings {
+		if !seen[finding.Rule] {
+			seen[finding.Rule] = true
+			rules = append(rules, finding.Rule)
+		}
+	}
+	return rules
+}
+
+// Policy configures what artifacts are accepted
+type Policy struct {
+	// MaxSize bounds artifacts and what archives may expand to, in bytes
+	MaxSize int64
+	// AllowedTypes are the accepted artifact types; empty takes DefaultAllowedTypes
+	AllowedTypes []string
+	// SafeGlobals are pickle imports allowed on top of SafeGlobals
+	SafeGlobals []string
+}
+
+// Antivirus scans a stream for malware signatures
+type Antivirus interface {
+	// Scan returns the matched signature, or "" when the stream is clean
+	Scan(ctx context.Context, r io.Reader) (string, error)
+}
+
+// Scanner applies a policy to artifacts
+type Scanner struct {
+	maxSize   int64
+	allowed   map[string]bool
+	safe      map[string]bool
+	antivirus Antivirus
+}
+
+// NewScanner creates a scanner for policy
+func NewScanner(policy Policy) *Scanner {
+	allowedTypes := policy.AllowedTypes
+	if len(allowedTypes) == 0 {
+		allowedTypes = DefaultAllowedTypes
+	}
+
+	s := &Scanner{
+		maxSize: policy.MaxSize,
+		allowed: make(map[string]bool),
+		safe:    make(map[string]bool),
+	}
+	for _, t := range allowedTypes {
+		s.allowed[strings.TrimSpace(t)] = true
+	}
+	for _, global := range append(SafeGlobals, policy.SafeGlobals...) {
+		s.safe[strings.TrimSpace(global)] = true
+	}
+	return s
+}
+
+// SetAntivirus adds a malware signature scan to every artifact
+func (s *Scanner) SetAntivirus(antivirus Antivirus) {
+	s.antivirus = antivirus
+}
+
+// MaxSize is the largest artifact accepted, in bytes
+func (s *Scanner) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Scan inspects the artifact of size bytes in r, uploaded as name. Findings
+// reject the artifact; errors mean it could not be scanned.
+func (s *Scanner) Scan(ctx context.Context, name string, r io.ReaderAt, size int64) (*Report, error) {
+	report := &Report{File: name, Size: size}
+	defer func() {
+		report.Verdict = Passed
+		if len(report.Findings) > 0 {
+			report.Verdict = Rejected
+		}
+	}()
+
+	if s.maxSize > 0 && size > s.maxSize {
+		report.Findings = append(report.Findings, Finding{
+			Rule:   RuleSize,
+			Detail: fmt.Sprintf("artifact exceeds the %d byte limit", s.maxSize),
+		})
+		return report, nil
+	}
+
+	header := make([]byte, 16)
+	n, err := r.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	header = header[:n]
+
+	// The extension decides the type; the content must agree with it
+	report.Type = extensionTypes[strings.ToLower(path.Ext(name))]
+	switch {
+	case report.Type == "":
+		report.Findings = append(report.Findings, Finding{Rule: RuleFileType, Detail: fmt.Sprintf("unknown artifact type %q", path.Ext(name))})
+		return report, nil
+	case !s.allowed[report.Type]:
+		report.Findings = append(report.Findings, Finding{Rule: RuleFileType, Detail: fmt.Sprintf("%s artifacts are not allowed", report.Type)})
+		return report, nil
+	}
+	if problem := checkContent(report.Type, header, size); problem != "" {
+		report.Findings = append(report.Findings, Finding{Rule: RuleFileType, Detail: problem})
+		return report, nil
+	}
+
+	// Pickles run code on load, whether bare or inside a PyTorch archive
+	switch {
+	case isPickle(header):
+		report.Findings = append(report.Findings, s.scanPickle("", io.NewSectionReader(r, 0, size))...)
+	case isZip(header):
+		report.Findings = append(report.Findings, s.scanArchive(r, size)...)
+	}
+
+	if s.antivirus != nil {
+		signature, err := s.antivirus.Scan(ctx, io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, err
+		}
+		if signature != "" {
+			report.Findings = append(report.Findings, Finding{Rule: RuleMalware, Detail: "matched signature " + signature})
+		}
+	}
+
+	return report, nil
+}
+
+// scanArchive checks the pickles in a zip archive such as a PyTorch checkpoint
+func (s *Scanner) scanArchive(r io.ReaderAt, size int64) []Finding {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return []Finding{{Rule: RuleFileType, Detail: "corrupt archive: " + err.Error()}}
+	}
+
+	var (
+		findings []Finding
+		expanded uint64
+	)
+	for _, file := range archive.File {
+		expanded += file.UncompressedSize64
+		if s.maxSize > 0 && expanded > uint64(s.maxSize) {
+			return append(findings, Finding{
+				Rule:   RuleSize,
+				Detail: fmt.Sprintf("archive expands beyond the %d byte limit", s.maxSize),
+			})
+		}
+
+		entry, err := file.Open()
+		if err != nil {
+			findings = append(findings, Finding{Rule: RuleFileType, File: file.Name, Detail: "unreadable archive entry: " + err.Error()})
+			continue
+		}
+		head := make([]byte, 2)
+		n, _ := io.ReadFull(entry, head)
+		entry.Close()
+		if !strings.HasSuffix(file.Name, ".pkl") && !isPickle(head[:n]) {
+			continue
+		}
+
+		// Reopen to read the pickle from the start, bounded in case the
+		// entry lies about its size
+		entry, err = file.Open()
+		if err != nil {
+			continue
+		}
+		findings = append(findings, s.scanPickle(file.Name, io.LimitReader(entry, int64(file.UncompressedSize64)))...)
+		entry.Close()
+	}
+	return findings
+}
+
+// scanPickle reports imports outside the safe globals
+func (s *Scanner) scanPickle(file string, r io.Reader) []Finding {
+	var findings []Finding
+	imports, err := pickleImports(r)
+	seen := make(map[string]bool)
+	for _, global := range imports {
+		if s.safe[global] || seen[global] {
+			continue
+		}
+		seen[global] = true
+		detail := "imports " + global
+		if global == "?" {
+			detail = "imports a computed global"
+		}
+		findings = append(findings, Finding{Rule: RulePickle, File: file, Detail: detail})
+	}
+	if err != nil {
+		findings = append(findings, Finding{Rule: RulePickle, File: file, Detail: "unparseable pickle: " + err.Error()})
+	}
+	return findings
+}
+
+// checkContent returns why header does not look like an artifact of type t, or ""
+func checkContent(t string, header []byte, size int64) string {
+	switch t {
+	case "onnx":
+		// ModelProto starts with its ir_version varint (field 1)
+		if len(header) == 0 || header[0] != 0x08 {
+			return "content is not an ONNX model"
+		}
+	case "safetensors":
+		// An 8-byte little-endian header length, then the JSON header
+		if len(header) < 9 || header[8] != '{' || binary.LittleEndian.Uint64(header[:8]) > uint64(size-8) {
+			return "content is not a safetensors file"
+		}
+	case "pytorch":
+		if !isZip(header) && !isPickle(header) {
+			return "content is not a PyTorch checkpoint"
+		}
+	case "pickle":
+		if !isPickle(header) {
+			return "content is not a pickle"
+		}
+	default:
+		// Protobuf and engine formats have no magic number, but must not be
+		// pickles or archives under another name
+		if isZip(header) || isPickle(header) {
+			return fmt.Sprintf("content is not a %s artifact", t)
+		}
+	}
+	return ""
+}
+
+func isZip(header []byte) bool {
+	return bytes.HasPrefix(header, []byte("PK\x03\x04"))
+}
+
+// isPickle matches the PROTO opcode pickles of protocol 2 and later start with
+func isPickle(header []byte) bool {
+	return len(header) >= 2 && header[0] == 0x80 && header[1] >= 2 && header[1] <= 5
+}
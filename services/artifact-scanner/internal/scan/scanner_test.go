@@ -0,0 +1,149 @@
+package scan
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scanBytes(t *testing.T, s *Scanner, name string, data []byte) *Report {
+	t.Helper()
+	report, err := s.Scan(context.Background(), name, bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	return report
+}
+
+// checkpoint builds a PyTorch zip checkpoint around a pickle
+func checkpoint(t *testing.T, pickle []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("archive/data.pkl")
+	require.NoError(t, err)
+	f.Write(pickle)
+	f, err = w.Create("archive/data/0")
+	require.NoError(t, err)
+	f.Write(make([]byte, 64))
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func safetensors() []byte {
+	header := []byte(`{"weight":{"dtype":"F32","shape":[1],"data_offsets":[0,4]}}`)
+	data := make([]byte, 8, 8+len(header)+4)
+	binary.LittleEndian.PutUint64(data, uint64(len(header)))
+	return append(append(data, header...), 0, 0, 0, 0)
+}
+
+func TestScanner_AcceptsModels(t *testing.T) {
+	s := NewScanner(Policy{MaxSize: 1 << 20})
+
+	tests := []struct {
+		name string
+		file string
+		data []byte
+		kind string
+	}{
+		{"onnx", "model.onnx", []byte("\x08\x07\x12\x07pytorch"), "onnx"},
+		{"safetensors", "model.safetensors", safetensors(), "safetensors"},
+		{"pytorch checkpoint", "model.pt", checkpoint(t, safePickle), "pytorch"},
+		{"legacy pytorch", "model.pth", safePickle, "pytorch"},
+		{"tensorrt", "model.plan", []byte("ftrt\x00\x00\x00\x00"), "tensorrt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := scanBytes(t, s, tt.file, tt.data)
+			assert.Equal(t, Passed, report.Verdict, "%+v", report.Findings)
+			assert.Equal(t, tt.kind, report.Type)
+		})
+	}
+}
+
+func TestScanner_RejectsUnsafeArtifacts(t *testing.T) {
+	s := NewScanner(Policy{MaxSize: 1024})
+
+	tests := []struct {
+		name string
+		file string
+		data []byte
+		rule string
+	}{
+		{"too large", "model.onnx", append([]byte{0x08}, make([]byte, 2048)...), RuleSize},
+		{"unknown type", "model.exe", []byte("MZ"), RuleFileType},
+		{"pickle not allowed", "model.pkl", safePickle, RuleFileType},
+		{"disguised pickle", "model.onnx", globalPickle, RuleFileType},
+		{"bad safetensors header", "model.safetensors", []byte("\xff\xff\x00\x00\x00\x00\x00\x00{}"), RuleFileType},
+		{"checkpoint running code", "model.pt", checkpoint(t, stackGlobalPickle), RulePickle},
+		{"legacy checkpoint running code", "model.pth", globalPickle, RulePickle},
+		{"truncated pickle", "model.pth", globalPickle[:12], RulePickle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := scanBytes(t, s, tt.file, tt.data)
+			assert.Equal(t, Rejected, report.Verdict)
+			assert.Equal(t, []string{tt.rule}, report.Rules(), "%+v", report.Findings)
+		})
+	}
+
+	report := scanBytes(t, s, "model.pt", checkpoint(t, globalPickle))
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "archive/data.pkl", report.Findings[0].File)
+	assert.Equal(t, "imports posix.system", report.Findings[0].Detail)
+}
+
+func TestScanner_PolicyOverrides(t *testing.T) {
+	s := NewScanner(Policy{AllowedTypes: []string{"pickle"}, SafeGlobals: []string{"posix.system"}})
+
+	assert.Equal(t, Passed, scanBytes(t, s, "model.pkl", globalPickle).Verdict)
+	assert.Equal(t, Rejected, scanBytes(t, s, "model.onnx", []byte{0x08}).Verdict)
+}
+
+func TestScanner_ArchiveExpansionLimit(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("archive/data/0")
+	require.NoError(t, err)
+	f.Write(make([]byte, 1<<20))
+	require.NoError(t, w.Close())
+
+	report := scanBytes(t, NewScanner(Policy{MaxSize: 64 * 1024}), "model.pt", buf.Bytes())
+	assert.Equal(t, []string{RuleSize}, report.Rules())
+}
+
+// fakeAntivirus flags streams containing a marker
+type fakeAntivirus struct{ err error }
+
+func (a *fakeAntivirus) Scan(ctx context.Context, r io.Reader) (string, error) {
+	if a.err != nil {
+		return "", a.err
+	}
+	data, _ := io.ReadAll(r)
+	if bytes.Contains(data, []byte("EICAR")) {
+		return "Eicar-Test-Signature", nil
+	}
+	return "", nil
+}
+
+func TestScanner_Antivirus(t *testing.T) {
+	s := NewScanner(Policy{})
+	s.SetAntivirus(&fakeAntivirus{})
+
+	assert.Equal(t, Passed, scanBytes(t, s, "model.onnx", []byte{0x08, 0x07}).Verdict)
+
+	report := scanBytes(t, s, "model.onnx", []byte("\x08EICAR"))
+	assert.Equal(t, []string{RuleMalware}, report.Rules())
+	assert.Equal(t, "matched signature Eicar-Test-Signature", report.Findings[0].Detail)
+
+	// Artifacts that cannot be scanned are not passed
+	s.SetAntivirus(&fakeAntivirus{err: io.ErrUnexpectedEOF})
+	_, err := s.Scan(context.Background(), "model.onnx", bytes.NewReader([]byte{0x08}), 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.uber.org/zap"
+)
+
+// MinIOStore keeps artifacts in MinIO buckets
+type MinIOStore struct {
+	client   atomic.Pointer[minio.Client]
+	endpoint string
+	buckets  []string
+	logger   *zap.Logger
+}
+
+// NewMinIOStore creates a new MinIO store, creating the buckets if needed
+func NewMinIOStore(endpoint, accessKey, secretKey string, buckets []string, logger *zap.Logger) (*MinIOStore, error) {
+	client, err := newClient(endpoint, accessKey, secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &MinIOStore{
+		endpoint: endpoint,
+		buckets:  buckets,
+		logger:   logger,
+	}
+	store.client.Store(client)
+
+	for _, bucket := range buckets {
+		if err := store.ensureBucket(context.Background(), bucket); err != nil {
+			return nil, fmt.Errorf("failed to ensure bucket %s: %w", bucket, err)
+		}
+	}
+
+	return store, nil
+}
+
+// newClient creates a MinIO client that signs requests with the given keys
+func newClient(endpoint, accessKey, secretKey string) (*minio.Client, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: false, // Set to true for HTTPS
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create minio client: %w", err)
+	}
+	return client, nil
+}
+
+// SetCredentials replaces the MinIO client with one using new keys, typically
+// after the MinIO credentials rotate
+func (s *MinIOStore) SetCredentials(accessKey, secretKey string) error {
+	client, err := newClient(s.endpoint, accessKey, secretKey)
+	if err != nil {
+		return err
+	}
+	s.client.Store(client)
+	return nil
+}
+
+// Ping verifies that MinIO is reachable and the artifact buckets exist
+func (s *MinIOStore) Ping(ctx context.Context) error {
+	for _, bucket := range s.buckets {
+		exists, err := s.client.Load().BucketExists(ctx, bucket)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("bucket %s does not exist", bucket)
+		}
+	}
+	return nil
+}
+
+// ensureBucket creates the bucket if it doesn't exist
+func (s *MinIOStore) ensureBucket(ctx context.Context, bucket string) error {
+	exists, err := s.client.Load().BucketExists(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if err := s.client.Load().MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+			return err
+		}
+		s.logger.Info("created bucket", zap.String("bucket", bucket))
+	}
+
+	return nil
+}
+
+// Put stores size bytes from r as bucket/object and returns its s3:// URI
+func (s *MinIOStore) Put(ctx context.Context, bucket, object string, r io.Reader, size int64, metadata map[string]string) (string, error) {
+	_, err := s.client.Load().PutObject(ctx, bucket, object, r, size, minio.PutObjectOptions{
+		ContentType:  "application/octet-stream",
+		UserMetadata: metadata,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload artifact: %w", err)
+	}
+
+	s.logger.Info("stored artifact",
+		zap.String("bucket", bucket),
+		zap.String("object", object),
+		zap.Int64("size_bytes", size),
+	)
+
+	return fmt.Sprintf("s3://%s/%s", bucket, object), nil
+}
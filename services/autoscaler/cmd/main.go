@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/autoscaler/internal/config"
+	"github.com/yourusername/ai-platform/autoscaler/internal/controller"
+	"github.com/yourusername/ai-platform/autoscaler/internal/kube"
+	"github.com/yourusername/ai-platform/autoscaler/internal/sources"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/scaling"
+	"github.com/yourusername/ai-platform/pkg/transport"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// Initialize logger
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize logger: %v", err))
+	}
+	defer logger.Sync()
+
+	// Load configuration
+	cfg := config.Load()
+	logger.Info("configuration loaded",
+		zap.String("service", cfg.ServiceName),
+		zap.String("port", cfg.Port),
+		zap.Duration("interval", cfg.Interval),
+		zap.Bool("dry_run", cfg.DryRun),
+	)
+
+	// Load the SPIFFE workload identity for mTLS between services
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	clientFor := func(service string) *http.Client {
+		if identity != nil {
+			return identity.HTTPClient(service, 5*time.Second)
+		}
+		return &http.Client{Timeout: 5 * time.Second}
+	}
+	healthClient := &http.Client{Timeout: health.DefaultTimeout}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+		healthClient = identity.HTTPClient("metadata-service", health.DefaultTimeout)
+	}
+
+	// Deployments are resized through the Kubernetes API with the pod's service account
+	kubeClient, err := kube.InCluster(cfg.Namespace)
+	if err != nil {
+		logger.Fatal("failed to create kubernetes client", zap.Error(err))
+	}
+
+	scaler := controller.NewController(
+		sources.NewPolicyClient(cfg.MetadataServiceURL, clientFor("metadata-service")),
+		sources.NewLoadClient("model-router", cfg.ModelRouterURLs, clientFor("model-router")),
+		sources.NewLoadClient("inference-orchestrator", cfg.OrchestratorURLs, clientFor("inference-orchestrator")),
+		sources.NewGPUClient(&http.Client{Timeout: 5 * time.Second}),
+		kubeClient,
+		logger,
+	)
+	scaler.SetDryRun(cfg.DryRun)
+
+	// Size the batch worker from its backlog
+	if cfg.WorkerDeployment != "" {
+		workerPolicy := scaling.Policy{
+			Deployment:               cfg.WorkerDeployment,
+			MinReplicas:              int32(cfg.WorkerMinReplicas),
+			MaxReplicas:              int32(cfg.WorkerMaxReplicas),
+			TargetInFlight:           float64(cfg.WorkerJobsPerReplica),
+			ScaleUpCooldownSeconds:   int(cfg.WorkerScaleUpCooldown.Seconds()),
+			ScaleDownCooldownSeconds: int(cfg.WorkerScaleDownCooldown.Seconds()),
+		}
+		if err := workerPolicy.Validate(); err != nil {
+			logger.Fatal("invalid batch worker scaling policy", zap.Error(err))
+		}
+		scaler.SetWorkerPolicy(workerPolicy, sources.NewBacklogClient(cfg.BatchWorkerURL, clientFor("batch-worker")))
+	}
+
+	// Readiness requires the registry holding the policies
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("metadata-service", health.HTTPCheck(healthClient, cfg.MetadataServiceURL+health.LivenessPath))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scaler.Run(ctx, cfg.Interval)
+
+	// Setup router
+	if cfg.LogLevel == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Health checks
+	router.GET("/health", gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.LivenessPath, gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.ReadinessPath, gin.WrapH(checker.ReadinessHandler()))
+
+	// Latest sizing decision per deployment
+	router.GET("/v1/scaling", gin.WrapH(scaler))
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      logging.Middleware(router),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	go func() {
+		logger.Info("starting autoscaler", zap.String("port", cfg.Port))
+		if err := transport.ListenAndServe(srv, identity); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("failed to start server", zap.Error(err))
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("shutting down server...")
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Fatal("server forced to shutdown", zap.Error(err))
+	}
+
+	logger.Info("server exited")
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the autoscaler configuration
+type Config struct {
+	ServiceName        string
+	Port               string
+	MetadataServiceURL string
+	// Every replica is listed so load is summed over all of them
+	ModelRouterURLs  []string
+	OrchestratorURLs []string
+	BatchWorkerURL   string
+	JaegerEndpoint   string
+	LogLevel         string
+
+	// Control loop
+	Interval  time.Duration
+	DryRun    bool
+	Namespace string
+
+	// Batch worker sizing; an empty deployment leaves the worker unmanaged
+	WorkerDeployment        string
+	WorkerMinReplicas       int
+	WorkerMaxReplicas       int
+	WorkerJobsPerReplica    int
+	WorkerScaleUpCooldown   time.Duration
+	WorkerScaleDownCooldown time.Duration
+}
+
+// Load loads configuration from environment variables
+func Load() *Config {
+	return &Config{
+		ServiceName:        getEnv("SERVICE_NAME", "autoscaler"),
+		Port:               getEnv("PORT", "8088"),
+		MetadataServiceURL: getEnv("METADATA_SERVICE_URL", "http://localhost:8083"),
+		ModelRouterURLs:    strings.Split(getEnv("MODEL_ROUTER_URLS", "http://localhost:8081"), ","),
+		OrchestratorURLs:   strings.Split(getEnv("ORCHESTRATOR_URLS", "http://localhost:8082"), ","),
+		BatchWorkerURL:     getEnv("BATCH_WORKER_URL", "http://localhost:8084"),
+		JaegerEndpoint:     getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
+		LogLevel:           getEnv("LOG_LEVEL", "info"),
+
+		Interval:  getEnvDuration("SCALE_INTERVAL", 15*time.Second),
+		DryRun:    getEnvBool("SCALE_DRY_RUN", false),
+		Namespace: getEnv("KUBE_NAMESPACE", ""),
+
+		WorkerDeployment:        getEnv("WORKER_DEPLOYMENT", "batch-worker"),
+		WorkerMinReplicas:       getEnvInt("WORKER_MIN_REPLICAS", 1),
+		WorkerMaxReplicas:       getEnvInt("WORKER_MAX_REPLICAS", 10),
+		WorkerJobsPerReplica:    getEnvInt("WORKER_JOBS_PER_REPLICA", 100),
+		WorkerScaleUpCooldown:   getEnvDuration("WORKER_SCALE_UP_COOLDOWN", time.Minute),
+		WorkerScaleDownCooldown: getEnvDuration("WORKER_SCALE_DOWN_COOLDOWN", 5*time.Minute),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
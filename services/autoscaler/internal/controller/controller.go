@@ -0,0 +1,325 @@
+// Package controller sizes Triton deployments and the batch worker from the
+// load they are under, following per-model scaling policies.
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/backlog"
+	"github.com/yourusername/ai-platform/pkg/scaling"
+)
+
+// tolerance is how far GPU utilization may stray from its target before the
+// deployment is resized, so measurement noise does not cause churn
+const tolerance = 0.1
+
+// Scaler reads and sets deployment replicas
+type Scaler interface {
+	Replicas(ctx context.Context, deployment string) (int32, error)
+	Scale(ctx context.Context, deployment string, replicas int32) error
+}
+
+// PolicySource lists the scaling policies
+type PolicySource interface {
+	Policies(ctx context.Context) ([]scaling.Policy, error)
+}
+
+// LoadSource reports each model's load
+type LoadSource interface {
+	Load(ctx context.Context) (map[string]scaling.Load, error)
+}
+
+// GPUSource reports the average GPU utilization at a metrics endpoint
+type GPUSource interface {
+	Utilization(ctx context.Context, url string) (float64, error)
+}
+
+// BacklogSource reports the batch worker's backlog
+type BacklogSource interface {
+	Backlog(ctx context.Context) (*backlog.Backlog, error)
+}
+
+// Signals are the measurements a deployment is sized from
+type Signals struct {
+	InFlight          int64   `json:"in_flight"`
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	GPUUtilization    float64 `json:"gpu_utilization,omitempty"`
+}
+
+// Decision is the latest sizing of a deployment
+type Decision struct {
+	Model      string  `json:"model,omitempty"`
+	Deployment string  `json:"deployment"`
+	Current    int32   `json:"current_replicas"`
+	Desired    int32   `json:"desired_replicas"`
+	Signals    Signals `json:"signals"`
+	Scaled     bool    `json:"scaled"`
+	// Held explains why a desired size was not applied
+	Held  string    `json:"held,omitempty"`
+	Error string    `json:"error,omitempty"`
+	At    time.Time `json:"at"`
+}
+
+// Desired returns the replicas a policy asks for: enough for the most
+// demanding signal, within the policy's bounds
+func Desired(policy scaling.Policy, current int32, signals Signals) int32 {
+	var desired int32
+	if policy.TargetInFlight > 0 {
+		desired = max32(desired, int32(math.Ceil(float64(signals.InFlight)/policy.TargetInFlight)))
+	}
+	if policy.TargetRPS > 0 {
+		desired = max32(desired, int32(math.Ceil(signals.RequestsPerSecond/policy.TargetRPS)))
+	}
+	if policy.TargetGPUUtilization > 0 {
+		ratio := signals.GPUUtilization / policy.TargetGPUUtilization
+		sized := current
+		if math.Abs(ratio-1) > tolerance {
+			sized = int32(math.Ceil(float64(current) * ratio))
+		}
+		desired = max32(desired, sized)
+	}
+
+	if desired < policy.MinReplicas {
+		return policy.MinReplicas
+	}
+	if desired > policy.MaxReplicas {
+		return policy.MaxReplicas
+	}
+	return desired
+}
+
+// Controller periodically resizes every deployment with a scaling policy.
+// Model deployments are sized from the larger of the router's and the
+// orchestrator's load, since a request counted by both is one request.
+type Controller struct {
+	policies     PolicySource
+	routed       LoadSource
+	queued       LoadSource
+	gpu          GPUSource
+	scaler       Scaler
+	logger       *zap.Logger
+	dryRun       bool
+	worker       *scaling.Policy
+	workerSource BacklogSource
+
+	mu         sync.Mutex
+	lastScaled map[string]time.Time
+	decisions  map[string]Decision
+}
+
+// NewController creates a controller; routed and queued report the model
+// router's and the inference orchestrator's load
+func NewController(policies PolicySource, routed, queued LoadSource, gpu GPUSource, scaler Scaler, logger *zap.Logger) *Controller {
+	return &Controller{
+		policies:   policies,
+		routed:     routed,
+		queued:     queued,
+		gpu:        gpu,
+		scaler:     scaler,
+		logger:     logger,
+		lastScaled: make(map[string]time.Time),
+		decisions:  make(map[string]Decision),
+	}
+}
+
+// SetDryRun makes the controller decide sizes without applying them
+func (c *Controller) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
+// SetWorkerPolicy sizes the batch worker deployment so each replica has at
+// most the policy's TargetInFlight jobs of backlog
+func (c *Controller) SetWorkerPolicy(policy scaling.Policy, source BacklogSource) {
+	c.worker = &policy
+	c.workerSource = source
+}
+
+// Reconcile sizes every deployment once. Model deployments are left alone
+// when the load reports cannot be read, rather than sized down on missing data.
+func (c *Controller) Reconcile(ctx context.Context, now time.Time) error {
+	seen := make(map[string]bool)
+
+	if c.worker != nil {
+		seen[c.worker.Deployment] = true
+		b, err := c.workerSource.Backlog(ctx)
+		if err != nil {
+			c.record(Decision{Deployment: c.worker.Deployment, Error: err.Error(), At: now})
+			c.logger.Warn("failed to read batch backlog", zap.Error(err))
+		} else {
+			c.reconcile(ctx, *c.worker, Signals{InFlight: b.Depth()}, now)
+		}
+	}
+
+	policies, err := c.policies.Policies(ctx)
+	if err != nil {
+		return err
+	}
+	routed, err := c.routed.Load(ctx)
+	if err != nil {
+		return err
+	}
+	queued, err := c.queued.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		if seen[policy.Deployment] {
+			c.logger.Warn("deployment has more than one scaling policy",
+				zap.String("deployment", policy.Deployment),
+				zap.String("model", policy.Model),
+			)
+			continue
+		}
+		seen[policy.Deployment] = true
+
+		signals := Signals{
+			InFlight:          routed[policy.Model].InFlight,
+			RequestsPerSecond: math.Max(routed[policy.Model].RequestsPerSecond, queued[policy.Model].RequestsPerSecond),
+		}
+		if queued[policy.Model].InFlight > signals.InFlight {
+			signals.InFlight = queued[policy.Model].InFlight
+		}
+		if policy.TargetGPUUtilization > 0 {
+			utilization, err := c.gpu.Utilization(ctx, policy.MetricsURL)
+			if err != nil {
+				c.record(Decision{Model: policy.Model, Deployment: policy.Deployment, Error: err.Error(), At: now})
+				c.logger.Warn("failed to read GPU utilization",
+					zap.String("deployment", policy.Deployment),
+					zap.Error(err),
+				)
+				continue
+			}
+			signals.GPUUtilization = utilization
+		}
+		c.reconcile(ctx, policy, signals, now)
+	}
+
+	// Deployments whose policy was removed are no longer managed
+	c.mu.Lock()
+	for deployment := range c.decisions {
+		if !seen[deployment] {
+			delete(c.decisions, deployment)
+		}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// reconcile sizes one deployment
+func (c *Controller) reconcile(ctx context.Context, policy scaling.Policy, signals Signals, now time.Time) {
+	decision := Decision{Model: policy.Model, Deployment: policy.Deployment, Signals: signals, At: now}
+	logger := c.logger.With(zap.String("deployment", policy.Deployment))
+
+	current, err := c.scaler.Replicas(ctx, policy.Deployment)
+	if err != nil {
+		decision.Error = err.Error()
+		c.record(decision)
+		logger.Warn("failed to read deployment replicas", zap.Error(err))
+		return
+	}
+	decision.Current = current
+	decision.Desired = Desired(policy, current, signals)
+
+	c.mu.Lock()
+	sinceScaled := now.Sub(c.lastScaled[policy.Deployment])
+	c.mu.Unlock()
+
+	switch {
+	case decision.Desired == current:
+	case decision.Desired > current && sinceScaled < policy.ScaleUpCooldown():
+		decision.Held = "scale-up cooldown"
+	case decision.Desired < current && sinceScaled < policy.ScaleDownCooldown():
+		decision.Held = "scale-down cooldown"
+	case c.dryRun:
+		decision.Held = "dry run"
+	default:
+		if err := c.scaler.Scale(ctx, policy.Deployment, decision.Desired); err != nil {
+			decision.Error = err.Error()
+			logger.Error("failed to scale deployment", zap.Error(err))
+			break
+		}
+		decision.Scaled = true
+		c.mu.Lock()
+		c.lastScaled[policy.Deployment] = now
+		c.mu.Unlock()
+		logger.Info("scaled deployment",
+			zap.Int32("from", current),
+			zap.Int32("to", decision.Desired),
+			zap.Int64("in_flight", signals.InFlight),
+			zap.Float64("requests_per_second", signals.RequestsPerSecond),
+			zap.Float64("gpu_utilization", signals.GPUUtilization),
+		)
+	}
+	c.record(decision)
+}
+
+func (c *Controller) record(decision Decision) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.decisions[decision.Deployment] = decision
+}
+
+// Run reconciles every interval until ctx is cancelled
+func (c *Controller) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		reconcileCtx, cancel := context.WithTimeout(ctx, interval)
+		if err := c.Reconcile(reconcileCtx, time.Now().UTC()); err != nil && ctx.Err() == nil {
+			c.logger.Warn("failed to reconcile deployments", zap.Error(err))
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Decisions returns the latest decision per deployment, ordered by deployment
+func (c *Controller) Decisions() []Decision {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	decisions := make([]Decision, 0, len(c.decisions))
+	for _, decision := range c.decisions {
+		decisions = append(decisions, decision)
+	}
+	sort.Slice(decisions, func(i, j int) bool {
+		return decisions[i].Deployment < decisions[j].Deployment
+	})
+	return decisions
+}
+
+// ServeHTTP serves the latest decisions
+func (c *Controller) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apperrors.WriteHTTP(w, apperrors.New(apperrors.Unimplemented, "method not allowed"))
+		return
+	}
+	decisions := c.Decisions()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"decisions": decisions,
+		"count":     len(decisions),
+	})
+}
+
+func max32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
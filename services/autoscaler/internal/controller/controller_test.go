@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/backlog"
+	"github.com/yourusername/ai-platform/pkg/scaling"
+)
+
+type fakeScaler map[string]int32
+
+func (f fakeScaler) Replicas(ctx context.Context, deployment string) (int32, error) {
+	replicas, ok := f[deployment]
+	if !ok {
+		return 0, apperrors.New(apperrors.NotFound, "deployment not found")
+	}
+	return replicas, nil
+}
+
+func (f fakeScaler) Scale(ctx context.Context, deployment string, replicas int32) error {
+	f[deployment] = replicas
+	return nil
+}
+
+type fakePolicies []scaling.Policy
+
+func (f fakePolicies) Policies(ctx context.Context) ([]scaling.Policy, error) {
+	return f, nil
+}
+
+type fakeLoad struct {
+	loads map[string]scaling.Load
+	err   error
+}
+
+func (f *fakeLoad) Load(ctx context.Context) (map[string]scaling.Load, error) {
+	return f.loads, f.err
+}
+
+type fakeGPU float64
+
+func (f fakeGPU) Utilization(ctx context.Context, url string) (float64, error) {
+	return float64(f), nil
+}
+
+type fakeBacklog backlog.Backlog
+
+func (f fakeBacklog) Backlog(ctx context.Context) (*backlog.Backlog, error) {
+	b := backlog.Backlog(f)
+	return &b, nil
+}
+
+func TestDesired(t *testing.T) {
+	policy := scaling.Policy{MinReplicas: 1, MaxReplicas: 10, TargetInFlight: 4, TargetRPS: 50}
+
+	assert.Equal(t, int32(1), Desired(policy, 3, Signals{}), "idle deployments shrink to the minimum")
+	assert.Equal(t, int32(5), Desired(policy, 1, Signals{InFlight: 17}))
+	assert.Equal(t, int32(6), Desired(policy, 1, Signals{InFlight: 17, RequestsPerSecond: 260}), "the busiest signal wins")
+	assert.Equal(t, int32(10), Desired(policy, 1, Signals{InFlight: 400}))
+
+	gpu := scaling.Policy{MinReplicas: 1, MaxReplicas: 10, TargetGPUUtilization: 0.6, MetricsURL: "http://triton:8002/metrics"}
+	assert.Equal(t, int32(6), Desired(gpu, 4, Signals{GPUUtilization: 0.9}))
+	assert.Equal(t, int32(4), Desired(gpu, 4, Signals{GPUUtilization: 0.64}), "within tolerance")
+	assert.Equal(t, int32(2), Desired(gpu, 4, Signals{GPUUtilization: 0.3}))
+}
+
+func TestController_Reconcile(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	scaler := fakeScaler{"triton-resnet18": 1, "triton-bert": 2}
+	policies := fakePolicies{
+		{Model: "resnet18", Deployment: "triton-resnet18", MinReplicas: 1, MaxReplicas: 8, TargetInFlight: 4, ScaleDownCooldownSeconds: 300},
+		{Model: "bert", Deployment: "triton-bert", MinReplicas: 1, MaxReplicas: 8, TargetGPUUtilization: 0.5, MetricsURL: "http://triton-bert:8002/metrics"},
+	}
+	routed := &fakeLoad{loads: map[string]scaling.Load{"resnet18": {Model: "resnet18", InFlight: 10}}}
+	queued := &fakeLoad{loads: map[string]scaling.Load{"resnet18": {Model: "resnet18", InFlight: 14}}}
+
+	c := NewController(policies, routed, queued, fakeGPU(1), scaler, zap.NewNop())
+	require.NoError(t, c.Reconcile(context.Background(), now))
+	assert.Equal(t, int32(4), scaler["triton-resnet18"], "sized from the orchestrator's larger queue")
+	assert.Equal(t, int32(4), scaler["triton-bert"])
+
+	decisions := c.Decisions()
+	require.Len(t, decisions, 2)
+	assert.Equal(t, "triton-bert", decisions[0].Deployment)
+	assert.True(t, decisions[1].Scaled)
+
+	// Load drops, but scaling down waits out the cooldown
+	routed.loads, queued.loads = nil, nil
+	require.NoError(t, c.Reconcile(context.Background(), now.Add(time.Minute)))
+	assert.Equal(t, int32(4), scaler["triton-resnet18"])
+	assert.Equal(t, "scale-down cooldown", c.Decisions()[1].Held)
+
+	require.NoError(t, c.Reconcile(context.Background(), now.Add(6*time.Minute)))
+	assert.Equal(t, int32(1), scaler["triton-resnet18"])
+
+	// Without load reports nothing is resized
+	routed.loads = map[string]scaling.Load{"resnet18": {Model: "resnet18", InFlight: 100}}
+	queued.err = errors.New("orchestrator unavailable")
+	assert.Error(t, c.Reconcile(context.Background(), now.Add(time.Hour)))
+	assert.Equal(t, int32(1), scaler["triton-resnet18"])
+}
+
+func TestController_DryRunAndWorker(t *testing.T) {
+	now := time.Now()
+	scaler := fakeScaler{"batch-worker": 2}
+	c := NewController(fakePolicies{}, &fakeLoad{}, &fakeLoad{}, fakeGPU(0), scaler, zap.NewNop())
+	c.SetWorkerPolicy(
+		scaling.Policy{Deployment: "batch-worker", MinReplicas: 1, MaxReplicas: 10, TargetInFlight: 100},
+		fakeBacklog(backlog.New(450, 100, 5, now)),
+	)
+	c.SetDryRun(true)
+
+	require.NoError(t, c.Reconcile(context.Background(), now))
+	assert.Equal(t, int32(2), scaler["batch-worker"])
+	decisions := c.Decisions()
+	require.Len(t, decisions, 1)
+	assert.Equal(t, int32(6), decisions[0].Desired)
+	assert.Equal(t, "dry run", decisions[0].Held)
+
+	c.SetDryRun(false)
+	require.NoError(t, c.Reconcile(context.Background(), now))
+	assert.Equal(t, int32(6), scaler["batch-worker"])
+}
@@ -0,0 +1,148 @@
+// Package kube reads and sets deployment replica counts through the
+// Kubernetes API's scale subresource.
+package kube
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// serviceAccountDir holds the credentials Kubernetes mounts into every pod
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// Client scales deployments in one namespace
+type Client struct {
+	baseURL   string
+	namespace string
+	client    *http.Client
+	// token returns the bearer token; service account tokens rotate, so it is reread per request
+	token func() (string, error)
+}
+
+// NewClient creates a client for the API server at baseURL
+func NewClient(baseURL, namespace string, client *http.Client, token func() (string, error)) *Client {
+	return &Client{
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		namespace: namespace,
+		client:    client,
+		token:     token,
+	}
+}
+
+// InCluster creates a client from the pod's service account. namespace
+// defaults to the pod's own. It fails with FailedPrecondition outside a cluster.
+func InCluster(namespace string) (*Client, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, apperrors.New(apperrors.FailedPrecondition, "not running in a Kubernetes cluster")
+	}
+
+	ca, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no certificates in cluster CA")
+	}
+
+	if namespace == "" {
+		data, err := os.ReadFile(serviceAccountDir + "/namespace")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pod namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	client := &http.Client{Timeout: 10 * time.Second, Transport: transport}
+
+	token := func() (string, error) {
+		data, err := os.ReadFile(serviceAccountDir + "/token")
+		if err != nil {
+			return "", fmt.Errorf("failed to read service account token: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	return NewClient("https://"+net.JoinHostPort(host, port), namespace, client, token), nil
+}
+
+// scale is the autoscaling/v1 Scale object
+type scale struct {
+	Spec struct {
+		Replicas int32 `json:"replicas"`
+	} `json:"spec"`
+	Status struct {
+		Replicas int32 `json:"replicas"`
+	} `json:"status"`
+}
+
+// Replicas returns the replicas a deployment is set to
+func (c *Client) Replicas(ctx context.Context, deployment string) (int32, error) {
+	resp, err := c.do(ctx, http.MethodGet, deployment, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var s scale
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return 0, fmt.Errorf("failed to decode scale of %s: %w", deployment, err)
+	}
+	return s.Spec.Replicas, nil
+}
+
+// Scale sets the replicas of a deployment
+func (c *Client) Scale(ctx context.Context, deployment string, replicas int32) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	resp, err := c.do(ctx, http.MethodPatch, deployment, patch)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, deployment string, body []byte) (*http.Response, error) {
+	endpoint := fmt.Sprintf("%s/apis/apps/v1/namespaces/%s/deployments/%s/scale",
+		c.baseURL, url.PathEscape(c.namespace), url.PathEscape(deployment))
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+	}
+	if c.token != nil {
+		token, err := c.token()
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, "kubernetes")
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, apperrors.FromHTTPResponse(resp, "kubernetes")
+	}
+	return resp, nil
+}
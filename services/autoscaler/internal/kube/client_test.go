@@ -0,0 +1,59 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestClient_Scale(t *testing.T) {
+	replicas := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/apis/apps/v1/namespaces/ai-platform/deployments/triton-resnet18/scale", r.URL.Path)
+		assert.Equal(t, "Bearer sa-token", r.Header.Get("Authorization"))
+
+		if r.Method == http.MethodPatch {
+			assert.Equal(t, "application/merge-patch+json", r.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"spec":{"replicas":5}}`, string(body))
+			replicas = 5
+		}
+		fmt.Fprintf(w, `{"kind":"Scale","spec":{"replicas":%d},"status":{"replicas":2}}`, replicas)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "ai-platform", server.Client(), func() (string, error) { return "sa-token", nil })
+
+	current, err := client.Replicas(context.Background(), "triton-resnet18")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), current)
+
+	require.NoError(t, client.Scale(context.Background(), "triton-resnet18", 5))
+	current, err = client.Replicas(context.Background(), "triton-resnet18")
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), current)
+}
+
+func TestClient_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"kind":"Status","message":"deployments.apps \"missing\" not found","code":404}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "ai-platform", server.Client(), nil)
+	_, err := client.Replicas(context.Background(), "missing")
+	assert.True(t, apperrors.Is(err, apperrors.NotFound))
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	_, err = InCluster("")
+	assert.True(t, apperrors.Is(err, apperrors.FailedPrecondition))
+}
@@ -0,0 +1,44 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/backlog"
+)
+
+// BacklogClient reads the batch worker's backlog
+type BacklogClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewBacklogClient creates a client for the batch worker at workerURL
+func NewBacklogClient(workerURL string, client *http.Client) *BacklogClient {
+	return &BacklogClient{url: workerURL + backlog.Path, client: client}
+}
+
+// Backlog returns the latest backlog sample
+func (c *BacklogClient) Backlog(ctx context.Context) (*backlog.Backlog, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, "batch-worker")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, apperrors.FromHTTPResponse(resp, "batch-worker")
+	}
+
+	var b backlog.Backlog
+	if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+		return nil, fmt.Errorf("failed to decode batch backlog: %w", err)
+	}
+	return &b, nil
+}
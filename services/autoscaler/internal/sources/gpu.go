@@ -0,0 +1,79 @@
+package sources
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// gpuUtilizationMetric is Triton's per-GPU utilization gauge, between 0 and 1
+const gpuUtilizationMetric = "nv_gpu_utilization"
+
+// GPUClient reads GPU utilization from Triton metrics endpoints
+type GPUClient struct {
+	client *http.Client
+}
+
+// NewGPUClient creates a GPU metrics client
+func NewGPUClient(client *http.Client) *GPUClient {
+	return &GPUClient{client: client}
+}
+
+// Utilization returns the average utilization of the GPUs reported at url
+func (c *GPUClient) Utilization(ctx context.Context, url string) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, apperrors.FromTransportError(err, "triton")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, apperrors.FromHTTPResponse(resp, "triton")
+	}
+
+	var sum float64
+	var gpus int
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		value, ok := sample(scanner.Text(), gpuUtilizationMetric)
+		if ok {
+			sum += value
+			gpus++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read triton metrics: %w", err)
+	}
+	if gpus == 0 {
+		return 0, apperrors.Newf(apperrors.FailedPrecondition, "%s reports no GPU utilization", url)
+	}
+	return sum / float64(gpus), nil
+}
+
+// sample parses a Prometheus text-format line of the named metric
+func sample(line, metric string) (float64, bool) {
+	if !strings.HasPrefix(line, metric) {
+		return 0, false
+	}
+	rest := line[len(metric):]
+	if !strings.HasPrefix(rest, "{") && !strings.HasPrefix(rest, " ") {
+		return 0, false
+	}
+	if end := strings.LastIndex(rest, "}"); end >= 0 {
+		rest = rest[end+1:]
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	return value, err == nil
+}
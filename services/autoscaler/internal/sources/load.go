@@ -0,0 +1,66 @@
+// Package sources fetches what the autoscaler sizes deployments from: load
+// reports, GPU metrics, the batch backlog and the registry's scaling policies.
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/scaling"
+)
+
+// LoadClient reads the load reports of every instance of a service
+type LoadClient struct {
+	service string
+	urls    []string
+	client  *http.Client
+}
+
+// NewLoadClient creates a client for the instances of service at urls
+func NewLoadClient(service string, urls []string, client *http.Client) *LoadClient {
+	return &LoadClient{service: service, urls: urls, client: client}
+}
+
+// Load returns each model's load summed over every instance and version. A
+// partial sum would understate the load, so any unreachable instance fails it.
+func (c *LoadClient) Load(ctx context.Context) (map[string]scaling.Load, error) {
+	loads := make(map[string]scaling.Load)
+	for _, url := range c.urls {
+		report, err := c.fetch(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range report.Loads {
+			total := loads[l.Model]
+			total.Model = l.Model
+			total.InFlight += l.InFlight
+			total.RequestsPerSecond += l.RequestsPerSecond
+			loads[l.Model] = total
+		}
+	}
+	return loads, nil
+}
+
+func (c *LoadClient) fetch(ctx context.Context, url string) (*scaling.Report, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+scaling.LoadPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, c.service)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, apperrors.FromHTTPResponse(resp, c.service)
+	}
+
+	var report scaling.Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode %s load report: %w", c.service, err)
+	}
+	return &report, nil
+}
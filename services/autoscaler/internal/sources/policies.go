@@ -0,0 +1,49 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/scaling"
+)
+
+// PolicyClient reads scaling policies from the metadata service
+type PolicyClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewPolicyClient creates a client for the metadata service at baseURL
+func NewPolicyClient(baseURL string, client *http.Client) *PolicyClient {
+	return &PolicyClient{baseURL: baseURL, client: client}
+}
+
+// Policies returns every registered scaling policy
+func (c *PolicyClient) Policies(ctx context.Context) ([]scaling.Policy, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/scaling-policies", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	logging.Inject(ctx, req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, "metadata-service")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, apperrors.FromHTTPResponse(resp, "metadata-service")
+	}
+
+	var body struct {
+		Policies []scaling.Policy `json:"policies"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata-service response: %w", err)
+	}
+	return body.Policies, nil
+}
@@ -0,0 +1,82 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/scaling"
+)
+
+func loadServer(loads ...scaling.Load) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(scaling.Report{Service: "model-router", Loads: loads, UpdatedAt: time.Now()})
+	}))
+}
+
+func TestLoadClient_SumsInstancesAndVersions(t *testing.T) {
+	a := loadServer(
+		scaling.Load{Model: "resnet18", Version: "v1", InFlight: 3, RequestsPerSecond: 10},
+		scaling.Load{Model: "resnet18", Version: "v2", InFlight: 1, RequestsPerSecond: 2},
+	)
+	defer a.Close()
+	b := loadServer(scaling.Load{Model: "resnet18", Version: "v1", InFlight: 2, RequestsPerSecond: 5})
+	defer b.Close()
+
+	loads, err := NewLoadClient("model-router", []string{a.URL, b.URL}, http.DefaultClient).Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), loads["resnet18"].InFlight)
+	assert.InDelta(t, 17, loads["resnet18"].RequestsPerSecond, 0.001)
+
+	b.Close()
+	_, err = NewLoadClient("model-router", []string{a.URL, b.URL}, http.DefaultClient).Load(context.Background())
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable))
+}
+
+func TestGPUClient_Utilization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`# HELP nv_gpu_utilization GPU utilization rate [0.0 - 1.0)
+# TYPE nv_gpu_utilization gauge
+nv_gpu_utilization{gpu_uuid="GPU-1"} 0.600000
+nv_gpu_utilization{gpu_uuid="GPU-2"} 0.900000
+nv_gpu_utilization_total 12
+nv_inference_count{model="resnet18",version="1"} 42
+`))
+	}))
+	defer server.Close()
+
+	utilization, err := NewGPUClient(server.Client()).Utilization(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.75, utilization, 0.001)
+}
+
+func TestGPUClient_NoGPUs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("nv_inference_count{model=\"resnet18\",version=\"1\"} 42\n"))
+	}))
+	defer server.Close()
+
+	_, err := NewGPUClient(server.Client()).Utilization(context.Background(), server.URL)
+	assert.True(t, apperrors.Is(err, apperrors.FailedPrecondition))
+}
+
+func TestPolicyClient_Policies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/scaling-policies", r.URL.Path)
+		w.Write([]byte(`{"policies":[{"model":"resnet18","deployment":"triton-resnet18","min_replicas":1,"max_replicas":4,"target_in_flight":8}],"count":1}`))
+	}))
+	defer server.Close()
+
+	policies, err := NewPolicyClient(server.URL, server.Client()).Policies(context.Background())
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	assert.Equal(t, "triton-resnet18", policies[0].Deployment)
+	assert.Equal(t, float64(8), policies[0].TargetInFlight)
+}
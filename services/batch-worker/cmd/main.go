@@ -2,15 +2,37 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/IBM/sarama"
+	"github.com/yourusername/ai-platform/batch-worker/internal/callback"
 	"github.com/yourusername/ai-platform/batch-worker/internal/config"
 	"github.com/yourusername/ai-platform/batch-worker/internal/consumer"
+	"github.com/yourusername/ai-platform/batch-worker/internal/control"
+	"github.com/yourusername/ai-platform/batch-worker/internal/deletion"
+	"github.com/yourusername/ai-platform/batch-worker/internal/jobs"
+	"github.com/yourusername/ai-platform/batch-worker/internal/monitor"
 	"github.com/yourusername/ai-platform/batch-worker/internal/storage"
 	"github.com/yourusername/ai-platform/batch-worker/internal/worker"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/backlog"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/pricing"
+	"github.com/yourusername/ai-platform/pkg/privacy"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/secrets"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+	"github.com/yourusername/ai-platform/pkg/transport"
+	"github.com/yourusername/ai-platform/pkg/usage"
+	"github.com/yourusername/ai-platform/pkg/webhooks"
 	"go.uber.org/zap"
 )
 
@@ -31,6 +53,26 @@ func main() {
 		zap.Int("worker_pool_size", cfg.WorkerPoolSize),
 	)
 
+	// Resolve credentials from Vault or a mounted secret store when configured
+	secretProvider, err := secrets.FromEnv(logger)
+	if err != nil {
+		logger.Fatal("failed to initialize secret provider", zap.Error(err))
+	}
+	secretManager := secrets.NewManager(secretProvider, logger)
+	defer secretManager.Close()
+
+	postgresURLRef := cfg.SecretsPath + "#postgres_url"
+	minioKeyRefs := []string{cfg.SecretsPath + "#minio_access_key", cfg.SecretsPath + "#minio_secret_key"}
+	cfg.PostgresURL = secretManager.LookupOr(context.Background(), postgresURLRef, cfg.PostgresURL)
+	cfg.MinIOAccessKey = secretManager.LookupOr(context.Background(), minioKeyRefs[0], cfg.MinIOAccessKey)
+	cfg.MinIOSecretKey = secretManager.LookupOr(context.Background(), minioKeyRefs[1], cfg.MinIOSecretKey)
+	if cfg.PostgresURL == "" {
+		logger.Fatal("postgres is not configured; set POSTGRES_URL or provide it via VAULT_ADDR")
+	}
+	if cfg.MinIOAccessKey == "" || cfg.MinIOSecretKey == "" {
+		logger.Fatal("minio credentials are not configured; set MINIO_ACCESS_KEY/MINIO_SECRET_KEY or provide them via VAULT_ADDR")
+	}
+
 	// Initialize PostgreSQL store
 	pgStore, err := storage.NewPostgresStore(cfg.PostgresURL, logger)
 	if err != nil {
@@ -57,6 +99,152 @@ func main() {
 	pool := worker.NewPool(cfg.WorkerPoolSize, orchestratorURL, pgStore, minioStore, logger)
 	logger.Info("worker pool created", zap.Int("size", cfg.WorkerPoolSize))
 
+	// Meter processed jobs for billing
+	kafkaProducer, err := events.NewKafkaProducer(cfg.KafkaBrokers)
+	if err != nil {
+		logger.Fatal("failed to initialize kafka producer", zap.Error(err))
+	}
+	defer kafkaProducer.Close()
+
+	// Check jobs and frame usage events with their registered schemas when a
+	// registry is configured
+	schemaCodec := schema.FromEnv()
+	if err := schemaCodec.Register(context.Background(), schema.BatchJobs, schema.UsageEvents, schema.PlatformEvents); apperrors.Is(err, apperrors.FailedPrecondition) {
+		logger.Fatal("message schema is incompatible with the registry", zap.Error(err))
+	} else if err != nil {
+		logger.Warn("failed to register message schemas", zap.Error(err))
+	}
+
+	usageRecorder := usage.NewRecorder(cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		value, err := schemaCodec.Frame(ctx, schema.UsageEvents, value)
+		if err != nil {
+			return err
+		}
+		_, _, err = kafkaProducer.SendMessage(&sarama.ProducerMessage{
+			Topic: cfg.UsageTopic,
+			Key:   sarama.StringEncoder(key),
+			Value: sarama.ByteEncoder(value),
+		})
+		return err
+	}), 1000, logger)
+	pool.SetUsageRecorder(usageRecorder)
+
+	// Results are returned and metered with their estimated cost
+	modelPricing, err := pricing.Parse(cfg.ModelPricing)
+	if err != nil {
+		logger.Fatal("failed to load model pricing", zap.Error(err))
+	}
+	pool.SetPricing(modelPricing)
+
+	// Announce finished jobs to the notification service
+	eventEmitter := events.NewEmitter(cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		value, err := schemaCodec.Frame(ctx, schema.PlatformEvents, value)
+		if err != nil {
+			return err
+		}
+		_, _, err = kafkaProducer.SendMessage(&sarama.ProducerMessage{
+			Topic: cfg.EventTopic,
+			Key:   sarama.StringEncoder(key),
+			Value: sarama.ByteEncoder(value),
+		})
+		return err
+	}), 1000, logger)
+	pool.SetEventEmitter(eventEmitter)
+
+	// Purge inference data on request and once it outlives the retention period
+	retention, err := privacy.RetentionFromEnv()
+	if err != nil {
+		logger.Fatal("invalid retention configuration", zap.Error(err))
+	}
+	deleter := deletion.NewDeleter(pgStore, minioStore, kafkaProducer, cfg.KafkaTopic, logger)
+	deleter.SetTenantTopics(cfg.TenantTopics)
+	minioStores := []*storage.MinIOStore{minioStore}
+	if cfg.LakeBucket != "" {
+		lakeStore, err := storage.NewMinIOStore(
+			cfg.MinIOEndpoint,
+			cfg.MinIOAccessKey,
+			cfg.MinIOSecretKey,
+			cfg.LakeBucket,
+			logger,
+		)
+		if err != nil {
+			logger.Fatal("failed to initialize data lake store", zap.Error(err))
+		}
+		deleter.SetDataLake(lakeStore, cfg.LakePrefix)
+		minioStores = append(minioStores, lakeStore)
+	}
+
+	// Reconnect to PostgreSQL and MinIO when their credentials rotate
+	secretManager.Watch(context.Background(), postgresURLRef, 5*time.Minute, func(url string) {
+		if err := pgStore.Reconnect(url); err != nil {
+			logger.Error("failed to reconnect to postgres with rotated credentials", zap.Error(err))
+		}
+	})
+	secretManager.WatchAll(context.Background(), minioKeyRefs, 5*time.Minute, func(keys []string) {
+		for _, store := range minioStores {
+			if err := store.SetCredentials(keys[0], keys[1]); err != nil {
+				logger.Error("failed to update minio credentials", zap.Error(err))
+			}
+		}
+	})
+
+	// Load the SPIFFE workload identity for mTLS to the orchestrator
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	orchestratorClient := &http.Client{Timeout: health.DefaultTimeout}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+		pool.SetHTTPClient(identity.HTTPClient("inference-orchestrator", 30*time.Second))
+		orchestratorClient = identity.HTTPClient("inference-orchestrator", health.DefaultTimeout)
+	}
+
+	// Check each job's tenant and limits when a tenant service is configured
+	if cfg.TenantServiceURL != "" {
+		tenantClient := &http.Client{Timeout: 5 * time.Second}
+		if identity != nil {
+			tenantClient = identity.HTTPClient("tenant-service", 5*time.Second)
+		}
+		pool.SetTenants(tenancy.NewClient(cfg.TenantServiceURL, tenantClient, cfg.TenantCacheTTL))
+	}
+
+	// Post async inference results to their callbacks, signed with a secret
+	// that can be rotated in the secret store. Callback URLs come from
+	// clients, so only public addresses are connected to.
+	callbackSecretRef := cfg.SecretsPath + "#callback_signing_secret"
+	callbackSecret := secrets.NewValue(secretManager.LookupOr(context.Background(), callbackSecretRef, cfg.CallbackSecret))
+	if callbackSecret.Load() != "" {
+		secretManager.Watch(context.Background(), callbackSecretRef, 5*time.Minute, callbackSecret.Store)
+		pool.SetCallbacks(callback.NewNotifier(transport.PublicHTTPClient(10*time.Second), callbackSecret.Load, logger))
+	} else {
+		logger.Warn("async inference callbacks are disabled; set CALLBACK_SIGNING_SECRET to enable them")
+	}
+
+	// Deliver finished jobs to the webhook endpoints external systems
+	// register. They are kept in the secret store with their signing secrets.
+	webhookLog := webhooks.NewMemoryLog(1000)
+	webhookDispatcher := webhooks.NewDispatcher(cfg.ServiceName, &http.Client{Timeout: 10 * time.Second}, webhookLog, 1000, logger)
+	webhookEndpointsRef := cfg.SecretsPath + "#webhook_endpoints"
+	loadWebhookEndpoints := func(document string) {
+		endpoints, err := webhooks.ParseEndpoints(document)
+		if err != nil {
+			logger.Error("failed to load webhook endpoints", zap.Error(err))
+			return
+		}
+		webhookDispatcher.SetEndpoints(endpoints)
+	}
+	loadWebhookEndpoints(secretManager.LookupOr(context.Background(), webhookEndpointsRef, cfg.WebhookEndpoints))
+	secretManager.Watch(context.Background(), webhookEndpointsRef, 5*time.Minute, loadWebhookEndpoints)
+	pool.SetWebhooks(webhookDispatcher)
+
+	// Readiness covers everything a batch job touches
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("postgres", pgStore.Ping)
+	checker.Add("minio", minioStore.Ping)
+	checker.Add("kafka", health.TCPCheck(cfg.KafkaBrokers...))
+	checker.Add("inference-orchestrator", health.HTTPCheck(orchestratorClient, orchestratorURL+health.LivenessPath))
+
 	// Create Kafka consumer
 	kafkaConsumer, err := consumer.NewKafkaConsumer(
 		cfg.KafkaBrokers,
@@ -69,12 +257,63 @@ func main() {
 	if err != nil {
 		logger.Fatal("failed to create kafka consumer", zap.Error(err))
 	}
+	kafkaConsumer.SetCodec(schemaCodec)
+	kafkaConsumer.SetTopics(consumer.BaseTopics(cfg.KafkaTopic, cfg.TenantTopics, cfg.WorkerTenants))
+	kafkaConsumer.SetPriorities(cfg.ConcurrentJobs, cfg.PriorityWeights)
 	logger.Info("kafka consumer created")
 
+	// Stop jobs cancelled through the gateway. Every worker hears every
+	// cancellation, since any of them may be running the job.
+	var controlListener *control.Listener
+	if controlConsumer, err := config.NewControlConsumer(cfg.KafkaBrokers); err != nil {
+		logger.Warn("batch job cancellation is unavailable", zap.Error(err))
+	} else {
+		defer controlConsumer.Close()
+		controlListener = control.NewListener(controlConsumer, cfg.ControlTopic, pool, logger)
+		controlListener.SetCodec(schemaCodec)
+	}
+
+	// Publish the backlog for the gateway's backpressure; without Kafka
+	// offsets it covers only consumed jobs
+	var lagSource monitor.LagSource
+	if kafkaLag, err := monitor.NewKafkaLag(cfg.KafkaBrokers, kafkaConsumer.Topics(), cfg.ConsumerGroup); err != nil {
+		logger.Warn("failed to connect to kafka for consumer lag", zap.Error(err))
+	} else {
+		defer kafkaLag.Close()
+		lagSource = kafkaLag
+	}
+	backlogMonitor := monitor.NewMonitor(lagSource, pgStore, logger)
+
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	usageDone := make(chan struct{})
+	go func() {
+		usageRecorder.Run(ctx)
+		close(usageDone)
+	}()
+	eventsDone := make(chan struct{})
+	go func() {
+		eventEmitter.Run(ctx)
+		close(eventsDone)
+	}()
+	webhooksDone := make(chan struct{})
+	go func() {
+		webhookDispatcher.Run(ctx, 4)
+		close(webhooksDone)
+	}()
+
+	go deleter.RunRetention(ctx, retention, time.Hour)
+	go backlogMonitor.Run(ctx, cfg.BacklogInterval)
+	if controlListener != nil {
+		go func() {
+			if err := controlListener.Run(ctx); err != nil {
+				logger.Error("job control listener error", zap.Error(err))
+			}
+		}()
+	}
+
 	// Start consumer in goroutine
 	go func() {
 		if err := kafkaConsumer.Start(ctx); err != nil {
@@ -82,6 +321,49 @@ func main() {
 		}
 	}()
 
+	// Serve health probes, job counts for the gateway's admin overview, the
+	// backlog for its backpressure and the autoscaler, job listings and
+	// status lookups, the webhook delivery log, and deletion requests proxied
+	// by its admin API. With a workload identity everything but the probes
+	// requires mTLS, and all but the backlog only the gateway may call.
+	gatewayOnly := func(next http.Handler) http.Handler { return next }
+	if identity != nil {
+		gateway := transport.AuthorizeIDs(transport.ServiceID(identity.TrustDomain(), "api-gateway"))
+		gatewayOnly = func(next http.Handler) http.Handler {
+			return transport.RequirePeerPolicy(next, gateway)
+		}
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/health", checker.LivenessHandler())
+	mux.Handle(health.LivenessPath, checker.LivenessHandler())
+	mux.Handle(health.ReadinessPath, checker.ReadinessHandler())
+	mux.Handle(deletion.Path, gatewayOnly(deleter))
+	mux.Handle(deletion.Path+"/", gatewayOnly(deleter))
+	mux.Handle(backlog.Path, backlogMonitor)
+	jobsHandler := gatewayOnly(jobs.NewHandler(pgStore, minioStore, logger))
+	mux.Handle(jobs.Path, jobsHandler)
+	mux.Handle(jobs.Path+"/", jobsHandler)
+	mux.Handle("/v1/webhooks/deliveries", gatewayOnly(webhookLog.Handler()))
+	mux.Handle("/v1/jobs/stats", gatewayOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counts, err := pgStore.CountJobsByStatus(r.Context())
+		if err != nil {
+			logger.Error("failed to count jobs", zap.Error(err))
+			apperrors.WriteHTTP(w, apperrors.Wrap(err, apperrors.Unavailable, "failed to count jobs"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jobs": counts})
+	})))
+	healthSrv := &http.Server{
+		Addr:    ":" + cfg.HealthPort,
+		Handler: mux,
+	}
+	go func() {
+		if err := transport.ListenAndServe(healthSrv, identity); err != nil && err != http.ErrServerClosed {
+			logger.Error("health server error", zap.Error(err))
+		}
+	}()
+
 	logger.Info("batch worker started successfully")
 
 	// Wait for interrupt signal
@@ -92,6 +374,13 @@ func main() {
 	logger.Info("shutting down batch worker...")
 	cancel()
 
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	healthSrv.Shutdown(shutdownCtx)
+	<-usageDone
+	<-eventsDone
+	<-webhooksDone
+
 	logger.Info("batch worker exited")
 }
 
@@ -0,0 +1,77 @@
+// Package callback posts the results of async inferences to the callback URLs
+// their clients registered
+package callback
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/webhooks"
+)
+
+// Headers carrying a callback's signature, made under the platform's signing
+// secret the same way as webhooks' signatures
+const (
+	SignatureHeader = webhooks.SignatureHeader
+	TimestampHeader = webhooks.TimestampHeader
+)
+
+// Payload is posted to a callback URL when its job finishes
+type Payload struct {
+	JobID   string `json:"job_id"`
+	Status  string `json:"status"`
+	Model   string `json:"model"`
+	Version string `json:"version"`
+	// Result is the job's result: input, prediction, latency_ms and, for
+	// failed inferences, error and code
+	Result map[string]interface{} `json:"result,omitempty"`
+	// Error explains why the job failed before its input was inferred
+	Error       string    `json:"error,omitempty"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// Notifier delivers callbacks through a webhook dispatcher, so they are
+// signed, retried and logged like webhooks
+type Notifier struct {
+	dispatcher *webhooks.Dispatcher
+	secret     func() string
+	logger     *zap.Logger
+}
+
+// NewNotifier creates a notifier signing callbacks with the current secret
+func NewNotifier(client *http.Client, secret func() string, logger *zap.Logger) *Notifier {
+	dispatcher := webhooks.NewDispatcher("batch-worker", client, nil, 0, logger)
+	dispatcher.SetRetries(3, time.Second, time.Minute)
+	return &Notifier{
+		dispatcher: dispatcher,
+		secret:     secret,
+		logger:     logger,
+	}
+}
+
+// SetRetries sets how many times a callback is attempted and the backoff
+// before the first retry, which doubles after each one
+func (n *Notifier) SetRetries(attempts int, backoff time.Duration) {
+	n.dispatcher.SetRetries(attempts, backoff, time.Minute)
+}
+
+// Sign returns the signature of a callback body sent at timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	return webhooks.Sign(secret, timestamp, body)
+}
+
+// Deliver posts payload to url. Callbacks the receiver turns away with a
+// client error are not retried.
+func (n *Notifier) Deliver(ctx context.Context, url string, payload Payload) error {
+	endpoint := webhooks.Endpoint{ID: "callback", URL: url, Secret: n.secret()}
+	if err := n.dispatcher.DeliverJSON(ctx, endpoint, payload.JobID, payload); err != nil {
+		return err
+	}
+
+	logging.With(ctx, n.logger).Info("callback delivered", zap.String("status", payload.Status))
+	return nil
+}
@@ -0,0 +1,86 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestDeliver_SignsPayload(t *testing.T) {
+	var payload Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get(TimestampHeader)
+		assert.NotEmpty(t, timestamp)
+		assert.Equal(t, Sign("s3cret", timestamp, body), r.Header.Get(SignatureHeader))
+		assert.NoError(t, json.Unmarshal(body, &payload))
+	}))
+	defer server.Close()
+
+	notifier := NewNotifier(server.Client(), func() string { return "s3cret" }, zap.NewNop())
+	err := notifier.Deliver(context.Background(), server.URL, Payload{
+		JobID:  "job-1",
+		Status: "completed",
+		Model:  "resnet18",
+		Result: map[string]interface{}{"prediction": map[string]interface{}{"class": "cat"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", payload.JobID)
+	assert.Equal(t, "cat", payload.Result["prediction"].(map[string]interface{})["class"])
+}
+
+func TestSign(t *testing.T) {
+	signature := Sign("s3cret", "1760572800", []byte(`{"job_id":"job-1"}`))
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.Equal(t, signature, Sign("s3cret", "1760572800", []byte(`{"job_id":"job-1"}`)))
+	assert.NotEqual(t, signature, Sign("other", "1760572800", []byte(`{"job_id":"job-1"}`)))
+	assert.NotEqual(t, signature, Sign("s3cret", "1760572801", []byte(`{"job_id":"job-1"}`)))
+}
+
+func TestDeliver_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantAttempts int32
+		wantCode     apperrors.Code
+	}{
+		{"recovers", []int{http.StatusServiceUnavailable, http.StatusOK}, 2, ""},
+		{"gives up", []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}, 3, apperrors.Unavailable},
+		{"client error", []int{http.StatusNotFound}, 1, apperrors.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(tt.statuses[n-1])
+			}))
+			defer server.Close()
+
+			notifier := NewNotifier(server.Client(), func() string { return "s3cret" }, zap.NewNop())
+			notifier.SetRetries(3, time.Millisecond)
+			err := notifier.Deliver(context.Background(), server.URL, Payload{JobID: "job-1"})
+
+			assert.Equal(t, tt.wantAttempts, atomic.LoadInt32(&attempts))
+			if tt.wantCode == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.wantCode, apperrors.CodeOf(err))
+			}
+		})
+	}
+}
@@ -2,13 +2,14 @@ package consumer
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/yourusername/ai-platform/batch-worker/internal/storage"
 	"github.com/yourusername/ai-platform/batch-worker/internal/worker"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/schema"
 	"go.uber.org/zap"
 )
 
@@ -18,16 +19,19 @@ type PostgresStoreInterface interface {
 	GetJob(ctx context.Context, jobID string) (*storage.BatchJob, error)
 	UpdateJobProgress(ctx context.Context, jobID string, completed int, progress float64) error
 	UpdateJobStatus(ctx context.Context, jobID string, status storage.JobStatus, resultURL, errorMsg string) error
+	DeletedSince(ctx context.Context, tenant, subject string, submittedAt time.Time) (bool, error)
 	Close() error
 }
 
 // KafkaConsumer handles consuming batch jobs from Kafka
 type KafkaConsumer struct {
-	consumer sarama.ConsumerGroup
-	topic    string
-	pool     *worker.Pool
-	pgStore  PostgresStoreInterface
-	logger   *zap.Logger
+	consumer  sarama.ConsumerGroup
+	topics    []string
+	pool      *worker.Pool
+	pgStore   PostgresStoreInterface
+	codec     *schema.Codec
+	scheduler *scheduler
+	logger    *zap.Logger
 }
 
 // NewKafkaConsumer creates a new Kafka consumer
@@ -52,23 +56,86 @@ func NewKafkaConsumer(
 
 	return &KafkaConsumer{
 		consumer: consumer,
-		topic:    topic,
+		topics:   []string{topic},
 		pool:     pool,
 		pgStore:  pgStore,
 		logger:   logger,
 	}, nil
 }
 
+// SetCodec checks job messages against their registered schemas
+func (c *KafkaConsumer) SetCodec(codec *schema.Codec) {
+	c.codec = codec
+}
+
+// SetTopics replaces the job topic with bases, such as the topics of tenants
+// given their own by BaseTopics
+func (c *KafkaConsumer) SetTopics(bases []string) {
+	c.topics = bases
+}
+
+// BaseTopics returns the job topics a worker consumes: topic and the topics
+// of every tenant with its own, or only those of dedicated tenants when a
+// worker is set aside for them
+func BaseTopics(topic string, tenants, dedicated []string) []string {
+	if len(dedicated) > 0 {
+		tenants = dedicated
+	} else {
+		tenants = append([]string{""}, tenants...)
+	}
+	bases := make([]string, 0, len(tenants))
+	for _, tenant := range tenants {
+		if tenant == "" {
+			bases = append(bases, topic)
+		} else {
+			bases = append(bases, schema.TenantTopic(topic, tenant))
+		}
+	}
+	return bases
+}
+
+// Topics lists the topics consumed, with their priority topics when
+// priorities are set
+func (c *KafkaConsumer) Topics() []string {
+	if c.scheduler == nil {
+		return c.topics
+	}
+	var topics []string
+	for _, base := range c.topics {
+		topics = append(topics, schema.PriorityTopics(base)...)
+	}
+	return topics
+}
+
+// SetPriorities also consumes the high and low priority topics next to the
+// base topic, running at most slots jobs at once and choosing between waiting
+// jobs of different priorities in proportion to weights
+func (c *KafkaConsumer) SetPriorities(slots int, weights map[string]int) {
+	c.scheduler = newScheduler(slots, weights)
+}
+
 // Start starts consuming messages
 func (c *KafkaConsumer) Start(ctx context.Context) error {
 	handler := &consumerGroupHandler{
-		pool:    c.pool,
-		pgStore: c.pgStore,
-		logger:  c.logger,
+		pool:      c.pool,
+		pgStore:   c.pgStore,
+		codec:     c.codec,
+		scheduler: c.scheduler,
+		logger:    c.logger,
+	}
+
+	topics := c.Topics()
+	if c.scheduler != nil {
+		handler.priorities = make(map[string]string, len(topics))
+		for _, base := range c.topics {
+			for _, priority := range schema.Priorities {
+				handler.priorities[schema.PriorityTopic(base, priority)] = priority
+			}
+		}
 	}
 
 	c.logger.Info("starting kafka consumer",
-		zap.String("topic", c.topic),
+		zap.Strings("topics", topics),
 	)
 
 	for {
@@ -77,7 +144,7 @@ func (c *KafkaConsumer) Start(ctx context.Context) error {
 			c.logger.Info("shutting down kafka consumer")
 			return c.consumer.Close()
 		default:
-			if err := c.consumer.Consume(ctx, []string{c.topic}, handler); err != nil {
+			if err := c.consumer.Consume(ctx, topics, handler); err != nil {
 				c.logger.Error("consumer error", zap.Error(err))
 				return err
 			}
@@ -87,9 +154,13 @@ func (c *KafkaConsumer) Start(ctx context.Context) error {
 
 // consumerGroupHandler implements sarama.ConsumerGroupHandler
 type consumerGroupHandler struct {
-	pool    *worker.Pool
-	pgStore PostgresStoreInterface
-	logger  *zap.Logger
+	pool      *worker.Pool
+	pgStore   PostgresStoreInterface
+	codec     *schema.Codec
+	scheduler *scheduler
+	// priorities maps each consumed topic to the priority of its jobs
+	priorities map[string]string
+	logger     *zap.Logger
 }
 
 // Setup is run at the beginning of a new session
@@ -118,59 +189,39 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 				continue
 			}
 
-			h.logger.Info("received batch job message",
-				zap.String("key", string(message.Key)),
-				zap.Int64("offset", message.Offset),
-			)
-
-			// Parse job message
-			var jobMsg map[string]interface{}
-			if err := json.Unmarshal(message.Value, &jobMsg); err != nil {
-				h.logger.Error("failed to unmarshal message", zap.Error(err))
+			// Tombstones left by deletion requests carry no job
+			if message.Value == nil {
 				session.MarkMessage(message, "")
 				continue
 			}
 
-			// Extract job details
-			jobID, _ := jobMsg["job_id"].(string)
-			model, _ := jobMsg["model"].(string)
-			version, _ := jobMsg["version"].(string)
-			inputsRaw, _ := jobMsg["inputs"].([]interface{})
-
-			// Convert inputs
-			inputs := make([]map[string]interface{}, 0, len(inputsRaw))
-			for _, input := range inputsRaw {
-				if inputMap, ok := input.(map[string]interface{}); ok {
-					inputs = append(inputs, inputMap)
-				}
-			}
+			// The gateway forwards the submitting request's correlation fields as record headers
+			ctx := logging.FromHeaders(session.Context(), headerLookup(message))
 
-			// Create job record
-			job := &storage.BatchJob{
-				ID:         jobID,
-				Model:      model,
-				Version:    version,
-				Inputs:     inputs,
-				Status:     storage.StatusPending,
-				TotalItems: len(inputs),
-				Completed:  0,
-				CreatedAt:  time.Now(),
-				UpdatedAt:  time.Now(),
-			}
+			logging.With(ctx, h.logger).Info("received batch job message",
+				zap.String("key", string(message.Key)),
+				zap.Int64("offset", message.Offset),
+			)
 
-			// Save job to database
-			if err := h.pgStore.CreateJob(session.Context(), job); err != nil {
-				h.logger.Error("failed to create job", zap.Error(err))
+			// Parse job message; messages that break the contract can never
+			// be processed and are skipped
+			var jobMsg schema.BatchJob
+			if err := h.codec.Decode(ctx, schema.BatchJobs, message.Value, &jobMsg); err != nil {
+				logging.With(ctx, h.logger).Error("failed to decode message", zap.Error(err))
 				session.MarkMessage(message, "")
 				continue
 			}
 
-			// Process job with worker pool
-			if err := h.pool.ProcessJob(session.Context(), job); err != nil {
-				h.logger.Error("failed to process job",
-					zap.String("job_id", jobID),
-					zap.Error(err),
-				)
+			// Wait for a slot; when the session ends first the message is
+			// left for the next one
+			if h.scheduler != nil {
+				if err := h.scheduler.acquire(ctx, h.priorities[message.Topic]); err != nil {
+					return nil
+				}
+			}
+			h.process(ctx, &jobMsg)
+			if h.scheduler != nil {
+				h.scheduler.release()
 			}
 
 			// Mark message as processed
@@ -178,3 +229,70 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 		}
 	}
 }
+
+// process records a job and runs it on the worker pool
+func (h *consumerGroupHandler) process(ctx context.Context, jobMsg *schema.BatchJob) {
+	// Jobs queued before they carried their tenant have it in their headers
+	tenant := jobMsg.Tenant
+	if tenant == "" {
+		tenant = logging.FieldsFromContext(ctx).Tenant
+	} else {
+		ctx = logging.WithTenant(ctx, tenant)
+	}
+
+	ctx = logging.WithJobID(ctx, jobMsg.JobID)
+	logger := logging.With(ctx, h.logger)
+
+	// Drop jobs whose data was requested deleted while they were queued
+	if tenant != "" {
+		deleted, err := h.pgStore.DeletedSince(ctx, tenant, jobMsg.SubjectID, jobMsg.CreatedAt)
+		if err != nil {
+			logger.Error("failed to check deletions", zap.Error(err))
+			return
+		}
+		if deleted {
+			logger.Info("discarding job deleted while queued")
+			return
+		}
+	}
+
+	// Create job record
+	job := &storage.BatchJob{
+		ID:          jobMsg.JobID,
+		Tenant:      tenant,
+		SubjectID:   jobMsg.SubjectID,
+		UserID:      jobMsg.UserID,
+		Model:       jobMsg.Model,
+		Version:     jobMsg.Version,
+		Inputs:      jobMsg.Inputs,
+		CallbackURL: jobMsg.CallbackURL,
+		Status:      storage.StatusPending,
+		TotalItems:  len(jobMsg.Inputs),
+		Completed:   0,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	// Save job to database
+	if err := h.pgStore.CreateJob(ctx, job); err != nil {
+		logger.Error("failed to create job", zap.Error(err))
+		return
+	}
+
+	// Process job with worker pool
+	if err := h.pool.ProcessJob(ctx, job); err != nil {
+		logger.Error("failed to process job", zap.Error(err))
+	}
+}
+
+// headerLookup returns a lookup over a message's record headers for logging.FromHeaders
+func headerLookup(message *sarama.ConsumerMessage) func(key string) string {
+	return func(key string) string {
+		for _, header := range message.Headers {
+			if header != nil && string(header.Key) == key {
+				return string(header.Value)
+			}
+		}
+		return ""
+	}
+}
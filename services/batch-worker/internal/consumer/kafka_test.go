@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/yourusername/ai-platform/batch-worker/internal/storage"
 	"github.com/yourusername/ai-platform/batch-worker/internal/worker"
+	"github.com/yourusername/ai-platform/pkg/logging"
 	"go.uber.org/zap"
 )
 
@@ -159,9 +160,12 @@ func TestConsumerGroupHandler_ConsumeClaim_ValidMessage(t *testing.T) {
 		"job_id":  "test-job-123",
 		"model":   "resnet18",
 		"version": "v1",
+		"tenant":  "acme",
+		"user_id": "alice",
 		"inputs": []interface{}{
 			map[string]interface{}{"data": []float64{1.0, 2.0}},
 		},
+		"created_at": time.Now().UTC(),
 	}
 
 	msgData, _ := json.Marshal(jobMsg)
@@ -185,6 +189,9 @@ func TestConsumerGroupHandler_ConsumeClaim_ValidMessage(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), session.marked["test-topic"])
+	assert.Contains(t, pgStore.jobs, "test-job-123")
+	assert.Equal(t, "acme", pgStore.jobs["test-job-123"].Tenant)
+	assert.Equal(t, "alice", pgStore.jobs["test-job-123"].UserID)
 }
 
 func TestConsumerGroupHandler_ConsumeClaim_InvalidJSON(t *testing.T) {
@@ -224,9 +231,88 @@ func TestConsumerGroupHandler_ConsumeClaim_InvalidJSON(t *testing.T) {
 	assert.Equal(t, int64(1), session.marked["test-topic"])
 }
 
+func TestConsumerGroupHandler_ConsumeClaim_DeletedWhileQueued(t *testing.T) {
+	logger := zap.NewNop()
+	pgStore := &MockPostgresStore{jobs: make(map[string]*storage.BatchJob), deleted: true}
+	minioStore := &MockMinIOStore{uploadedResults: make(map[string][]map[string]interface{})}
+	pool := worker.NewPool(1, "http://localhost:8082", pgStore, minioStore, logger)
+
+	handler := &consumerGroupHandler{
+		pool:    pool,
+		pgStore: pgStore,
+		logger:  logger,
+	}
+
+	session := NewMockConsumerGroupSession()
+	claim := NewMockConsumerGroupClaim("test-topic", 0)
+
+	msgData, _ := json.Marshal(map[string]interface{}{
+		"job_id":     "test-job-deleted",
+		"model":      "resnet18",
+		"version":    "v1",
+		"subject_id": "user-42",
+		"inputs":     []interface{}{map[string]interface{}{"data": 1.0}},
+		"created_at": time.Now().UTC(),
+	})
+	go func() {
+		claim.messages <- &sarama.ConsumerMessage{
+			Topic:   "test-topic",
+			Offset:  1,
+			Key:     []byte("test-job-deleted"),
+			Value:   msgData,
+			Headers: []*sarama.RecordHeader{{Key: []byte(logging.HeaderTenant), Value: []byte("acme")}},
+		}
+		// A tombstone for an earlier job
+		claim.messages <- &sarama.ConsumerMessage{Topic: "test-topic", Offset: 2, Key: []byte("test-job-old")}
+		close(claim.messages)
+	}()
+
+	err := handler.ConsumeClaim(session, claim)
+
+	assert.NoError(t, err)
+	assert.Empty(t, pgStore.jobs)
+	assert.Equal(t, int64(2), session.marked["test-topic"])
+}
+
+func TestConsumerGroupHandler_ConsumeClaim_IncompatibleMessage(t *testing.T) {
+	logger := zap.NewNop()
+	pgStore := &MockPostgresStore{jobs: make(map[string]*storage.BatchJob)}
+	minioStore := &MockMinIOStore{uploadedResults: make(map[string][]map[string]interface{})}
+	pool := worker.NewPool(1, "http://localhost:8082", pgStore, minioStore, logger)
+
+	handler := &consumerGroupHandler{
+		pool:    pool,
+		pgStore: pgStore,
+		logger:  logger,
+	}
+
+	session := NewMockConsumerGroupSession()
+	claim := NewMockConsumerGroupClaim("test-topic", 0)
+
+	// Inputs must be a list of objects
+	msgData, _ := json.Marshal(map[string]interface{}{
+		"job_id":     "test-job-incompatible",
+		"model":      "resnet18",
+		"version":    "v1",
+		"inputs":     []interface{}{1.0, 2.0},
+		"created_at": time.Now().UTC(),
+	})
+	go func() {
+		claim.messages <- &sarama.ConsumerMessage{Topic: "test-topic", Offset: 1, Value: msgData}
+		close(claim.messages)
+	}()
+
+	err := handler.ConsumeClaim(session, claim)
+
+	assert.NoError(t, err)
+	assert.Empty(t, pgStore.jobs)
+	assert.Equal(t, int64(1), session.marked["test-topic"])
+}
+
 // Mock implementations for testing
 type MockPostgresStore struct {
-	jobs map[string]*storage.BatchJob
+	jobs    map[string]*storage.BatchJob
+	deleted bool
 }
 
 func (m *MockPostgresStore) CreateJob(ctx context.Context, job *storage.BatchJob) error {
@@ -258,6 +344,10 @@ func (m *MockPostgresStore) UpdateJobStatus(ctx context.Context, jobID string, s
 	return nil
 }
 
+func (m *MockPostgresStore) DeletedSince(ctx context.Context, tenant, subject string, submittedAt time.Time) (bool, error) {
+	return m.deleted, nil
+}
+
 func (m *MockPostgresStore) Close() error {
 	return nil
 }
@@ -270,3 +360,21 @@ func (m *MockMinIOStore) UploadResults(ctx context.Context, jobID string, result
 	m.uploadedResults[jobID] = results
 	return "http://minio/results/" + jobID + ".json", nil
 }
+
+func TestBaseTopics(t *testing.T) {
+	assert.Equal(t, []string{"batch-inference"}, BaseTopics("batch-inference", nil, nil))
+	assert.Equal(t, []string{"batch-inference", "batch-inference-tenant-acme"}, BaseTopics("batch-inference", []string{"acme"}, nil))
+	assert.Equal(t, []string{"batch-inference-tenant-acme"}, BaseTopics("batch-inference", []string{"acme", "globex"}, []string{"acme"}))
+}
+
+func TestKafkaConsumer_Topics(t *testing.T) {
+	c := &KafkaConsumer{topics: []string{"batch-inference"}}
+	c.SetTopics(BaseTopics("batch-inference", []string{"acme"}, nil))
+	assert.Equal(t, []string{"batch-inference", "batch-inference-tenant-acme"}, c.Topics())
+
+	c.SetPriorities(2, map[string]int{"high": 6, "normal": 3, "low": 1})
+	assert.Equal(t, []string{
+		"batch-inference-high", "batch-inference", "batch-inference-low",
+		"batch-inference-tenant-acme-high", "batch-inference-tenant-acme", "batch-inference-tenant-acme-low",
+	}, c.Topics())
+}
@@ -0,0 +1,112 @@
+package consumer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+// scheduler limits how many jobs run at once and, when jobs of several
+// priorities are waiting for a slot, chooses between them by weighted round
+// robin, so a backlog of low priority jobs delays urgent ones by at most one
+// job per slot without being starved by them.
+type scheduler struct {
+	mu      sync.Mutex
+	free    int
+	weights map[string]int
+	current map[string]int
+	waiting map[string][]chan struct{}
+}
+
+func newScheduler(slots int, weights map[string]int) *scheduler {
+	if slots < 1 {
+		slots = 1
+	}
+	return &scheduler{
+		free:    slots,
+		weights: weights,
+		current: make(map[string]int),
+		waiting: make(map[string][]chan struct{}),
+	}
+}
+
+// acquire waits for a slot for a job of priority, or until ctx is done
+func (s *scheduler) acquire(ctx context.Context, priority string) error {
+	if _, ok := s.weights[priority]; !ok {
+		priority = schema.PriorityNormal
+	}
+
+	s.mu.Lock()
+	// Slots are only free while no job is waiting
+	if s.free > 0 {
+		s.free--
+		s.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	s.waiting[priority] = append(s.waiting[priority], ready)
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, waiter := range s.waiting[priority] {
+			if waiter == ready {
+				s.waiting[priority] = append(s.waiting[priority][:i], s.waiting[priority][i+1:]...)
+				return ctx.Err()
+			}
+		}
+		// The slot was granted as ctx ended; pass it on
+		s.free++
+		s.grant()
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire
+func (s *scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.free++
+	s.grant()
+}
+
+// grant hands free slots to waiting jobs; s.mu must be held
+func (s *scheduler) grant() {
+	for s.free > 0 {
+		priority, ok := s.next()
+		if !ok {
+			return
+		}
+		ready := s.waiting[priority][0]
+		s.waiting[priority] = s.waiting[priority][1:]
+		s.free--
+		close(ready)
+	}
+}
+
+// next picks the priority to run next among those with waiting jobs, by
+// smooth weighted round robin
+func (s *scheduler) next() (string, bool) {
+	var best string
+	total := 0
+	for _, priority := range schema.Priorities {
+		if len(s.waiting[priority]) == 0 {
+			continue
+		}
+		s.current[priority] += s.weights[priority]
+		total += s.weights[priority]
+		if best == "" || s.current[priority] > s.current[best] {
+			best = priority
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	s.current[best] -= total
+	return best, true
+}
@@ -0,0 +1,75 @@
+package consumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+// queue makes a job of priority wait on s, reporting its priority on granted
+// once it gets a slot
+func queue(t *testing.T, s *scheduler, priority string, granted chan<- string) {
+	s.mu.Lock()
+	waiting := len(s.waiting[priority])
+	s.mu.Unlock()
+
+	go func() {
+		if assert.NoError(t, s.acquire(context.Background(), priority)) {
+			granted <- priority
+		}
+	}()
+
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.waiting[priority]) > waiting
+	}, time.Second, time.Millisecond)
+}
+
+func TestScheduler_PrefersHigherPriorityWithoutStarving(t *testing.T) {
+	s := newScheduler(1, map[string]int{schema.PriorityHigh: 3, schema.PriorityNormal: 2, schema.PriorityLow: 1})
+	require.NoError(t, s.acquire(context.Background(), schema.PriorityNormal))
+
+	granted := make(chan string, 12)
+	for i := 0; i < 4; i++ {
+		queue(t, s, schema.PriorityLow, granted)
+		queue(t, s, schema.PriorityHigh, granted)
+		queue(t, s, schema.PriorityNormal, granted)
+	}
+
+	var order []string
+	for i := 0; i < 12; i++ {
+		s.release()
+		select {
+		case priority := <-granted:
+			order = append(order, priority)
+		case <-time.After(time.Second):
+			t.Fatal("no waiting job was granted the slot")
+		}
+	}
+
+	// While all three wait, six grants go three to high, two to normal and
+	// one to low; the rest share the slot once high runs out
+	assert.Equal(t, []string{
+		"high", "normal", "high", "low", "normal", "high",
+		"high", "normal", "normal", "low", "low", "low",
+	}, order)
+}
+
+func TestScheduler_AcquireCancelled(t *testing.T) {
+	s := newScheduler(1, map[string]int{schema.PriorityHigh: 6, schema.PriorityNormal: 3, schema.PriorityLow: 1})
+	require.NoError(t, s.acquire(context.Background(), "unknown"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.acquire(ctx, schema.PriorityHigh), context.DeadlineExceeded)
+
+	// The abandoned wait does not hold on to the slot
+	s.release()
+	require.NoError(t, s.acquire(context.Background(), schema.PriorityLow))
+}
@@ -0,0 +1,135 @@
+// Package control applies the gateway's instructions about batch jobs, such as
+// cancellations, to the jobs this worker is running
+package control
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+// Canceller stops jobs this worker is running
+type Canceller interface {
+	Cancel(jobID string) bool
+}
+
+// Listener reads job instructions from the control topic. Every worker reads
+// every instruction, outside any consumer group, since any of them may be
+// running the job.
+type Listener struct {
+	consumer sarama.Consumer
+	topic    string
+	jobs     Canceller
+	codec    *schema.Codec
+	logger   *zap.Logger
+}
+
+// NewListener creates a listener applying instructions on topic to jobs
+func NewListener(consumer sarama.Consumer, topic string, jobs Canceller, logger *zap.Logger) *Listener {
+	return &Listener{
+		consumer: consumer,
+		topic:    topic,
+		jobs:     jobs,
+		logger:   logger,
+	}
+}
+
+// SetCodec checks control messages against their registered schema
+func (l *Listener) SetCodec(codec *schema.Codec) {
+	l.codec = codec
+}
+
+// Run reads every partition of the topic from its newest message until ctx
+// is done. Instructions sent while no worker was running do not apply to any
+// job and are not replayed.
+func (l *Listener) Run(ctx context.Context) error {
+	partitions, err := l.consumer.Partitions(l.topic)
+	if err != nil {
+		return fmt.Errorf("failed to list partitions of %s: %w", l.topic, err)
+	}
+
+	var consumers []sarama.PartitionConsumer
+	for _, partition := range partitions {
+		pc, err := l.consumer.ConsumePartition(l.topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			for _, pc := range consumers {
+				pc.Close()
+			}
+			return fmt.Errorf("failed to consume partition %d of %s: %w", partition, l.topic, err)
+		}
+		consumers = append(consumers, pc)
+	}
+
+	l.logger.Info("listening for batch job instructions",
+		zap.String("topic", l.topic),
+		zap.Int("partitions", len(partitions)),
+	)
+
+	var wg sync.WaitGroup
+	for _, pc := range consumers {
+		wg.Add(1)
+		go func(pc sarama.PartitionConsumer) {
+			defer wg.Done()
+			defer pc.Close()
+			l.consume(ctx, pc)
+		}(pc)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (l *Listener) consume(ctx context.Context, pc sarama.PartitionConsumer) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-pc.Messages():
+			if !ok {
+				return
+			}
+			l.apply(ctx, message)
+		}
+	}
+}
+
+// apply carries out one instruction. Instructions about jobs this worker is
+// not running are ignored.
+func (l *Listener) apply(ctx context.Context, message *sarama.ConsumerMessage) {
+	ctx = logging.FromHeaders(ctx, headerLookup(message))
+
+	var instruction schema.BatchControl
+	if err := l.codec.Decode(ctx, schema.BatchControls, message.Value, &instruction); err != nil {
+		logging.With(ctx, l.logger).Error("failed to decode control message", zap.Error(err))
+		return
+	}
+
+	ctx = logging.WithJobID(ctx, instruction.JobID)
+	logger := logging.With(ctx, l.logger)
+
+	switch instruction.Action {
+	case schema.ActionCancel:
+		if l.jobs.Cancel(instruction.JobID) {
+			logger.Info("cancelling batch job")
+		}
+	default:
+		logger.Warn("ignoring unknown job instruction", zap.String("action", instruction.Action))
+	}
+}
+
+// headerLookup returns a lookup over a message's record headers for logging.FromHeaders
+func headerLookup(message *sarama.ConsumerMessage) func(key string) string {
+	return func(key string) string {
+		for _, header := range message.Headers {
+			if header != nil && string(header.Key) == key {
+				return string(header.Value)
+			}
+		}
+		return ""
+	}
+}
@@ -0,0 +1,69 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+type fakeCanceller struct {
+	running   map[string]bool
+	cancelled chan string
+}
+
+func (f *fakeCanceller) Cancel(jobID string) bool {
+	f.cancelled <- jobID
+	return f.running[jobID]
+}
+
+func encode(t *testing.T, instruction schema.BatchControl) []byte {
+	data, err := json.Marshal(instruction)
+	require.NoError(t, err)
+	return data
+}
+
+func TestListener_CancelsJobsOnEveryPartition(t *testing.T) {
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{"batch-control": {0, 1}})
+	first := consumer.ExpectConsumePartition("batch-control", 0, sarama.OffsetNewest)
+	second := consumer.ExpectConsumePartition("batch-control", 1, sarama.OffsetNewest)
+
+	jobs := &fakeCanceller{running: map[string]bool{"job-2": true}, cancelled: make(chan string, 4)}
+	listener := NewListener(consumer, "batch-control", jobs, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- listener.Run(ctx) }()
+
+	first.YieldMessage(&sarama.ConsumerMessage{Value: encode(t, schema.BatchControl{
+		JobID: "job-1", Action: schema.ActionCancel, RequestedAt: time.Now(),
+	})})
+	second.YieldMessage(&sarama.ConsumerMessage{Value: []byte(`{"job_id": "job-3", "action": "pause"}`)})
+	second.YieldMessage(&sarama.ConsumerMessage{Value: encode(t, schema.BatchControl{
+		JobID: "job-2", Action: schema.ActionCancel, RequestedAt: time.Now(),
+	})})
+
+	var cancelled []string
+	for len(cancelled) < 2 {
+		select {
+		case id := <-jobs.cancelled:
+			cancelled = append(cancelled, id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("instructions were not applied")
+		}
+	}
+	assert.ElementsMatch(t, []string{"job-1", "job-2"}, cancelled)
+
+	cancel()
+	assert.NoError(t, <-done)
+	assert.NoError(t, consumer.Close())
+}
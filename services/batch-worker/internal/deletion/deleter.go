@@ -0,0 +1,240 @@
+package deletion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/privacy"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"go.uber.org/zap"
+)
+
+// Path is where deletion requests are served
+const Path = "/v1/privacy/deletions"
+
+// JobStore holds batch jobs, their inputs, and deletion reports
+type JobStore interface {
+	JobIDsForSubject(ctx context.Context, tenant, subject string) ([]string, error)
+	JobIDsBefore(ctx context.Context, cutoff time.Time) ([]string, error)
+	DeleteJobs(ctx context.Context, ids []string) (int64, error)
+	SaveDeletion(ctx context.Context, report *privacy.Report) error
+	GetDeletion(ctx context.Context, id string) (*privacy.Report, error)
+}
+
+// ResultStore holds batch job results
+type ResultStore interface {
+	DeleteResults(ctx context.Context, jobIDs []string) (int64, error)
+}
+
+// LakeStore holds sampled inference records written by the datalake writer
+type LakeStore interface {
+	DeletePrefix(ctx context.Context, prefix string) (int64, error)
+}
+
+// Deleter purges batch inference inputs and outputs, on request for a tenant
+// or data subject and once they are older than the retention period
+type Deleter struct {
+	jobs       JobStore
+	results    ResultStore
+	producer   sarama.SyncProducer
+	topic      string
+	tenants    map[string]bool
+	lake       LakeStore
+	lakePrefix string
+	logger     *zap.Logger
+}
+
+// NewDeleter creates a deleter that tombstones job messages on topic and its
+// priority topics
+func NewDeleter(jobs JobStore, results ResultStore, producer sarama.SyncProducer, topic string, logger *zap.Logger) *Deleter {
+	return &Deleter{
+		jobs:     jobs,
+		results:  results,
+		producer: producer,
+		topic:    topic,
+		logger:   logger,
+	}
+}
+
+// SetTenantTopics also tombstones the jobs of tenants with topics of their
+// own on those topics
+func (d *Deleter) SetTenantTopics(tenants []string) {
+	d.tenants = make(map[string]bool, len(tenants))
+	for _, tenant := range tenants {
+		d.tenants[tenant] = true
+	}
+}
+
+// SetDataLake also deletes a tenant's sampled inference records from the data
+// lake. Records are partitioned by tenant but carry no data subject, so
+// subject requests leave them to the lake's retention.
+func (d *Deleter) SetDataLake(store LakeStore, prefix string) {
+	d.lake = store
+	d.lakePrefix = prefix
+}
+
+// Delete purges the request's data and returns the report. The request is
+// recorded first so jobs still queued in Kafka are discarded when consumed.
+// Job rows are removed last, and only once results and messages are gone, so
+// an incomplete deletion can be requested again.
+func (d *Deleter) Delete(ctx context.Context, req privacy.Request) (*privacy.Report, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	report := privacy.NewReport(req)
+	if err := d.jobs.SaveDeletion(ctx, report); err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Unavailable, "failed to record deletion")
+	}
+
+	logger := logging.With(ctx, d.logger).With(zap.String("deletion_id", report.ID))
+
+	ids, err := d.jobs.JobIDsForSubject(ctx, req.Tenant, req.Subject)
+	if err != nil {
+		report.Add(privacy.StorePostgres, 0, err)
+	} else {
+		deletedResults, resultsErr := d.results.DeleteResults(ctx, ids)
+		report.Add(privacy.StoreMinIO, deletedResults, resultsErr)
+
+		tombstoned, kafkaErr := d.tombstone(req.Tenant, ids)
+		report.Add(privacy.StoreKafka, tombstoned, kafkaErr)
+
+		if resultsErr == nil && kafkaErr == nil {
+			deletedJobs, jobsErr := d.jobs.DeleteJobs(ctx, ids)
+			report.Add(privacy.StorePostgres, deletedJobs, jobsErr)
+		} else {
+			report.Add(privacy.StorePostgres, 0, fmt.Errorf("skipped until results and messages are deleted"))
+		}
+	}
+
+	if d.lake != nil && req.Subject == "" {
+		deletedRecords, lakeErr := d.lake.DeletePrefix(ctx, inferencelog.TenantPrefix(d.lakePrefix, req.Tenant))
+		report.Add(privacy.StoreDataLake, deletedRecords, lakeErr)
+	}
+
+	if !report.Finish() {
+		logger.Warn("deletion incomplete", zap.Any("stores", report.Stores))
+	} else {
+		logger.Info("deletion complete", zap.Int("jobs", len(ids)))
+	}
+
+	if err := d.jobs.SaveDeletion(ctx, report); err != nil {
+		logger.Error("failed to save deletion report", zap.Error(err))
+	}
+	return report, nil
+}
+
+// tombstone publishes a null value for each job's key. On a compacted topic
+// this removes the job messages; on a delete-policy topic they age out with
+// the topic's retention and the consumer already skips deleted jobs. Jobs do
+// not record their priority, so every priority topic gets the tombstones, and
+// those of the tenant's own topics too when it has them, since its jobs may
+// have been queued before it did.
+func (d *Deleter) tombstone(tenant string, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	topics := schema.PriorityTopics(d.topic)
+	if d.tenants[tenant] {
+		topics = append(topics, schema.PriorityTopics(schema.TenantTopic(d.topic, tenant))...)
+	}
+	messages := make([]*sarama.ProducerMessage, 0, len(ids)*len(topics))
+	for _, topic := range topics {
+		for _, id := range ids {
+			messages = append(messages, &sarama.ProducerMessage{
+				Topic: topic,
+				Key:   sarama.StringEncoder(id),
+			})
+		}
+	}
+	if err := d.producer.SendMessages(messages); err != nil {
+		return 0, fmt.Errorf("failed to publish tombstones: %w", err)
+	}
+	return int64(len(ids)), nil
+}
+
+// Expire deletes finished jobs, and their results, created before cutoff.
+// Kafka messages expire through the topic's own retention.
+func (d *Deleter) Expire(ctx context.Context, cutoff time.Time) (int64, error) {
+	ids, err := d.jobs.JobIDsBefore(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := d.results.DeleteResults(ctx, ids); err != nil {
+		return 0, err
+	}
+	return d.jobs.DeleteJobs(ctx, ids)
+}
+
+// RunRetention expires jobs older than retention every interval until ctx is
+// cancelled. A zero retention keeps data until it is deleted by request.
+func (d *Deleter) RunRetention(ctx context.Context, retention, interval time.Duration) {
+	if retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := d.Expire(ctx, time.Now().Add(-retention))
+			if err != nil {
+				d.logger.Error("failed to expire batch jobs", zap.Error(err))
+				continue
+			}
+			if expired > 0 {
+				d.logger.Info("expired batch jobs", zap.Int64("count", expired))
+			}
+		}
+	}
+}
+
+// ServeHTTP accepts deletion requests with POST and returns earlier reports
+// with GET {Path}/{id}
+func (d *Deleter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, Path), "/")
+
+	switch {
+	case r.Method == http.MethodPost && id == "":
+		var req privacy.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apperrors.WriteHTTP(w, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+			return
+		}
+		// Finish the deletion even if the caller gives up; the report stays retrievable
+		report, err := d.Delete(context.WithoutCancel(r.Context()), req)
+		if err != nil {
+			apperrors.WriteHTTP(w, err)
+			return
+		}
+		writeJSON(w, report)
+
+	case r.Method == http.MethodGet && id != "":
+		report, err := d.jobs.GetDeletion(r.Context(), id)
+		if err != nil {
+			apperrors.WriteHTTP(w, apperrors.Ensure(err, apperrors.Unavailable, "failed to get deletion"))
+			return
+		}
+		writeJSON(w, report)
+
+	default:
+		apperrors.WriteHTTP(w, apperrors.New(apperrors.Unimplemented, "method not allowed"))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,205 @@
+package deletion
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/privacy"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"go.uber.org/zap"
+)
+
+type fakeJobStore struct {
+	jobs    map[string]string // job ID -> subject
+	deleted []string
+	reports map[string]*privacy.Report
+}
+
+func (s *fakeJobStore) JobIDsForSubject(ctx context.Context, tenant, subject string) ([]string, error) {
+	var ids []string
+	for id, jobSubject := range s.jobs {
+		if subject == "" || jobSubject == subject {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *fakeJobStore) JobIDsBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	return s.JobIDsForSubject(ctx, "", "")
+}
+
+func (s *fakeJobStore) DeleteJobs(ctx context.Context, ids []string) (int64, error) {
+	for _, id := range ids {
+		delete(s.jobs, id)
+	}
+	s.deleted = append(s.deleted, ids...)
+	return int64(len(ids)), nil
+}
+
+func (s *fakeJobStore) SaveDeletion(ctx context.Context, report *privacy.Report) error {
+	copied := *report
+	s.reports[report.ID] = &copied
+	return nil
+}
+
+func (s *fakeJobStore) GetDeletion(ctx context.Context, id string) (*privacy.Report, error) {
+	if report, ok := s.reports[id]; ok {
+		return report, nil
+	}
+	return nil, apperrors.Newf(apperrors.NotFound, "deletion not found: %s", id)
+}
+
+type fakeResultStore struct {
+	err error
+}
+
+func (s *fakeResultStore) DeleteResults(ctx context.Context, jobIDs []string) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	return int64(len(jobIDs)), nil
+}
+
+type fakeLakeStore struct {
+	prefixes []string
+}
+
+func (s *fakeLakeStore) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	s.prefixes = append(s.prefixes, prefix)
+	return 4, nil
+}
+
+func newJobStore() *fakeJobStore {
+	return &fakeJobStore{
+		jobs:    map[string]string{"job-1": "user-42", "job-2": "user-42", "job-3": "user-7"},
+		reports: make(map[string]*privacy.Report),
+	}
+}
+
+func TestDelete_Subject(t *testing.T) {
+	jobs := newJobStore()
+	producer := mocks.NewSyncProducer(t, nil)
+	expectTombstones(producer, 2)
+	deleter := NewDeleter(jobs, &fakeResultStore{}, producer, "batch-inference", zap.NewNop())
+
+	report, err := deleter.Delete(context.Background(), privacy.Request{Tenant: "acme", Subject: "user-42"})
+	require.NoError(t, err)
+
+	assert.True(t, report.Complete)
+	assert.Equal(t, []privacy.StoreResult{
+		{Store: privacy.StoreMinIO, Deleted: 2},
+		{Store: privacy.StoreKafka, Deleted: 2},
+		{Store: privacy.StorePostgres, Deleted: 2},
+	}, report.Stores)
+	assert.ElementsMatch(t, []string{"job-1", "job-2"}, jobs.deleted)
+	assert.Contains(t, jobs.jobs, "job-3")
+	assert.True(t, jobs.reports[report.ID].Complete)
+}
+
+func TestDelete_TenantTopics(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	expectTombstones(producer, 2*2)
+	deleter := NewDeleter(newJobStore(), &fakeResultStore{}, producer, "batch-inference", zap.NewNop())
+	deleter.SetTenantTopics([]string{"acme"})
+
+	report, err := deleter.Delete(context.Background(), privacy.Request{Tenant: "acme", Subject: "user-42"})
+	require.NoError(t, err)
+	assert.True(t, report.Complete)
+	assert.NoError(t, producer.Close())
+}
+
+func TestDelete_KeepsJobsWhenResultsFail(t *testing.T) {
+	jobs := newJobStore()
+	producer := mocks.NewSyncProducer(t, nil)
+	expectTombstones(producer, 3)
+	deleter := NewDeleter(jobs, &fakeResultStore{err: errors.New("minio unavailable")}, producer, "batch-inference", zap.NewNop())
+
+	report, err := deleter.Delete(context.Background(), privacy.Request{Tenant: "acme"})
+	require.NoError(t, err)
+
+	assert.False(t, report.Complete)
+	assert.Empty(t, jobs.deleted)
+	assert.Len(t, jobs.jobs, 3)
+}
+
+func TestDelete_TenantIncludesDataLake(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	expectTombstones(producer, 3)
+	lake := &fakeLakeStore{}
+	deleter := NewDeleter(newJobStore(), &fakeResultStore{}, producer, "batch-inference", zap.NewNop())
+	deleter.SetDataLake(lake, "inference-logs")
+
+	report, err := deleter.Delete(context.Background(), privacy.Request{Tenant: "acme"})
+	require.NoError(t, err)
+	assert.True(t, report.Complete)
+	assert.Contains(t, report.Stores, privacy.StoreResult{Store: privacy.StoreDataLake, Deleted: 4})
+	assert.Equal(t, []string{"inference-logs/tenant=acme/"}, lake.prefixes)
+
+	// Lake records carry no subject, so subject requests leave them alone
+	expectTombstones(producer, 2)
+	_, err = deleter.Delete(context.Background(), privacy.Request{Tenant: "acme", Subject: "user-42"})
+	require.NoError(t, err)
+	assert.Len(t, lake.prefixes, 1)
+}
+
+func TestDelete_RequiresTenant(t *testing.T) {
+	deleter := NewDeleter(newJobStore(), &fakeResultStore{}, mocks.NewSyncProducer(t, nil), "batch-inference", zap.NewNop())
+
+	_, err := deleter.Delete(context.Background(), privacy.Request{Subject: "user-42"})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+}
+
+func TestServeHTTP(t *testing.T) {
+	jobs := newJobStore()
+	producer := mocks.NewSyncProducer(t, nil)
+	expectTombstones(producer, 1)
+	deleter := NewDeleter(jobs, &fakeResultStore{}, producer, "batch-inference", zap.NewNop())
+
+	w := httptest.NewRecorder()
+	deleter.ServeHTTP(w, httptest.NewRequest("POST", Path, strings.NewReader(`{"tenant":"acme","subject_id":"user-7"}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"complete":true`)
+
+	var id string
+	for reportID := range jobs.reports {
+		id = reportID
+	}
+	w = httptest.NewRecorder()
+	deleter.ServeHTTP(w, httptest.NewRequest("GET", Path+"/"+id, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"subject_id":"user-7"`)
+
+	w = httptest.NewRecorder()
+	deleter.ServeHTTP(w, httptest.NewRequest("GET", Path+"/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	deleter.ServeHTTP(w, httptest.NewRequest("POST", Path, strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// tombstone checks that a message is keyed by job and carries no value
+// expectTombstones expects a tombstone for each of jobs on each priority topic
+func expectTombstones(producer *mocks.SyncProducer, jobs int) {
+	for i := 0; i < jobs*len(schema.PriorityTopics("batch-inference")); i++ {
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(tombstone)
+	}
+}
+
+func tombstone(msg *sarama.ProducerMessage) error {
+	if msg.Key == nil || msg.Value != nil {
+		return errors.New("expected a tombstone")
+	}
+	return nil
+}
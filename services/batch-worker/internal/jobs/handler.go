@@ -0,0 +1,256 @@
+// Package jobs serves the state of batch jobs to the gateway
+package jobs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/batch-worker/internal/storage"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Path is where batch jobs are served
+const Path = "/v1/jobs"
+
+// Page sizes of job listings
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// Store holds batch jobs
+type Store interface {
+	GetJob(ctx context.Context, jobID string) (*storage.BatchJob, error)
+	ListJobs(ctx context.Context, filter storage.JobFilter) ([]storage.BatchJob, error)
+}
+
+// ResultStore holds the results of finished jobs
+type ResultStore interface {
+	OpenResults(ctx context.Context, jobID string) (io.ReadCloser, int64, error)
+}
+
+// Page is one page of a job listing. NextCursor, when set, fetches the next one.
+type Page struct {
+	Jobs       []Status `json:"jobs"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// Status is a batch job's state, without its inputs
+type Status struct {
+	JobID       string            `json:"job_id"`
+	Tenant      string            `json:"tenant,omitempty"`
+	UserID      string            `json:"user_id,omitempty"`
+	Model       string            `json:"model"`
+	Version     string            `json:"version"`
+	Status      storage.JobStatus `json:"status"`
+	Progress    float64           `json:"progress"`
+	TotalItems  int               `json:"total_items"`
+	Completed   int               `json:"completed"`
+	ResultURL   string            `json:"result_url,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+}
+
+// NewStatus returns the state of job
+func NewStatus(job *storage.BatchJob) Status {
+	return Status{
+		JobID:       job.ID,
+		Tenant:      job.Tenant,
+		UserID:      job.UserID,
+		Model:       job.Model,
+		Version:     job.Version,
+		Status:      job.Status,
+		Progress:    job.Progress,
+		TotalItems:  job.TotalItems,
+		Completed:   job.Completed,
+		ResultURL:   job.ResultURL,
+		Error:       job.ErrorMsg,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+		CompletedAt: job.CompletedAt,
+	}
+}
+
+// Handler answers job lookups proxied by the gateway
+type Handler struct {
+	store   Store
+	results ResultStore
+	logger  *zap.Logger
+}
+
+// NewHandler creates a handler reading jobs from store and their results from results
+func NewHandler(store Store, results ResultStore, logger *zap.Logger) *Handler {
+	return &Handler{
+		store:   store,
+		results: results,
+		logger:  logger,
+	}
+}
+
+// ServeHTTP lists jobs with GET {Path}, returns a job's state with GET
+// {Path}/{id} and streams its results with GET {Path}/{id}/results
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, Path), "/")
+	id, resource, _ := strings.Cut(id, "/")
+	if resource != "" && (resource != "results" || id == "") {
+		apperrors.WriteHTTP(w, apperrors.Newf(apperrors.NotFound, "no such path: %s", r.URL.Path))
+		return
+	}
+	if r.Method != http.MethodGet {
+		apperrors.WriteHTTP(w, apperrors.New(apperrors.Unimplemented, "method not allowed"))
+		return
+	}
+	switch {
+	case id == "":
+		h.list(w, r)
+		return
+	case resource == "results":
+		h.streamResults(w, r, id)
+		return
+	}
+
+	ctx := logging.WithJobID(r.Context(), id)
+	job, err := h.store.GetJob(ctx, id)
+	if err != nil {
+		if apperrors.CodeOf(err) != apperrors.NotFound {
+			logging.With(ctx, h.logger).Error("failed to get job", zap.Error(err))
+		}
+		apperrors.WriteHTTP(w, apperrors.Ensure(err, apperrors.Unavailable, "failed to get job"))
+		return
+	}
+	writeJSON(w, NewStatus(job))
+}
+
+// streamResults copies a job's stored results to w
+func (h *Handler) streamResults(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := logging.WithJobID(r.Context(), id)
+	logger := logging.With(ctx, h.logger)
+
+	results, size, err := h.results.OpenResults(ctx, id)
+	if err != nil {
+		if apperrors.CodeOf(err) != apperrors.NotFound {
+			logger.Error("failed to open results", zap.Error(err))
+		}
+		apperrors.WriteHTTP(w, apperrors.Ensure(err, apperrors.Unavailable, "failed to open results"))
+		return
+	}
+	defer results.Close()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	if _, err := io.Copy(w, results); err != nil {
+		// The status is already sent; the short body tells the client
+		logger.Warn("failed to stream results", zap.Error(err))
+	}
+}
+
+// list answers a listing filtered by the status, model, tenant, user_id,
+// created_after and created_before query parameters, continuing from cursor
+func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r.URL.Query())
+	if err != nil {
+		apperrors.WriteHTTP(w, err)
+		return
+	}
+	pageSize := filter.Limit
+
+	// One extra job tells whether there is another page
+	filter.Limit++
+	jobs, err := h.store.ListJobs(r.Context(), filter)
+	if err != nil {
+		logging.With(r.Context(), h.logger).Error("failed to list jobs", zap.Error(err))
+		apperrors.WriteHTTP(w, apperrors.Ensure(err, apperrors.Unavailable, "failed to list jobs"))
+		return
+	}
+
+	page := Page{Jobs: []Status{}}
+	if len(jobs) > pageSize {
+		jobs = jobs[:pageSize]
+		page.NextCursor = encodeCursor(jobs[pageSize-1])
+	}
+	for i := range jobs {
+		page.Jobs = append(page.Jobs, NewStatus(&jobs[i]))
+	}
+	writeJSON(w, page)
+}
+
+// parseFilter reads a listing's filter from its query parameters
+func parseFilter(query url.Values) (storage.JobFilter, error) {
+	filter := storage.JobFilter{
+		Tenant: query.Get("tenant"),
+		UserID: query.Get("user_id"),
+		Status: storage.JobStatus(query.Get("status")),
+		Model:  query.Get("model"),
+		Limit:  DefaultPageSize,
+	}
+
+	for key, bound := range map[string]*time.Time{"created_after": &filter.CreatedAfter, "created_before": &filter.CreatedBefore} {
+		if value := query.Get(key); value != "" {
+			t, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return filter, apperrors.Newf(apperrors.InvalidArgument, "%s must be an RFC 3339 time", key)
+			}
+			*bound = t
+		}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return filter, apperrors.New(apperrors.InvalidArgument, "limit must be a positive integer")
+		}
+		filter.Limit = limit
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		createdAt, id, err := decodeCursor(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.AfterCreatedAt, filter.AfterID = createdAt, id
+	}
+	return filter, nil
+}
+
+// encodeCursor returns the cursor continuing a listing after job
+func encodeCursor(job storage.BatchJob) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(job.CreatedAt.Format(time.RFC3339Nano) + "|" + job.ID))
+}
+
+func decodeCursor(cursor string) (time.Time, string, error) {
+	invalid := apperrors.New(apperrors.InvalidArgument, "invalid cursor")
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", invalid
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", invalid
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return time.Time{}, "", invalid
+	}
+	return t, id, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
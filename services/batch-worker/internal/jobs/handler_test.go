@@ -0,0 +1,199 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/batch-worker/internal/storage"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+type fakeStore struct {
+	jobs map[string]*storage.BatchJob
+	err  error
+}
+
+func (s *fakeStore) GetJob(ctx context.Context, jobID string) (*storage.BatchJob, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if job, ok := s.jobs[jobID]; ok {
+		return job, nil
+	}
+	return nil, apperrors.Newf(apperrors.NotFound, "job not found: %s", jobID)
+}
+
+func (s *fakeStore) ListJobs(ctx context.Context, filter storage.JobFilter) ([]storage.BatchJob, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var jobs []storage.BatchJob
+	for _, job := range s.jobs {
+		switch {
+		case filter.Tenant != "" && job.Tenant != filter.Tenant,
+			filter.UserID != "" && job.UserID != filter.UserID,
+			filter.Status != "" && job.Status != filter.Status,
+			filter.Model != "" && job.Model != filter.Model,
+			!filter.CreatedAfter.IsZero() && job.CreatedAt.Before(filter.CreatedAfter),
+			!filter.CreatedBefore.IsZero() && !job.CreatedAt.Before(filter.CreatedBefore),
+			filter.AfterID != "" && !job.CreatedAt.Before(filter.AfterCreatedAt):
+			continue
+		}
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	if len(jobs) > filter.Limit {
+		jobs = jobs[:filter.Limit]
+	}
+	return jobs, nil
+}
+
+type fakeResults map[string]string
+
+func (r fakeResults) OpenResults(ctx context.Context, jobID string) (io.ReadCloser, int64, error) {
+	results, ok := r[jobID]
+	if !ok {
+		return nil, 0, apperrors.Newf(apperrors.NotFound, "no results for job %s", jobID)
+	}
+	return io.NopCloser(strings.NewReader(results)), int64(len(results)), nil
+}
+
+func TestServeHTTP_StreamsResults(t *testing.T) {
+	handler := NewHandler(&fakeStore{}, fakeResults{"job-1": `[{"prediction":{"class":"cat"}}]`}, zap.NewNop())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path+"/job-1/results", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "32", w.Header().Get("Content-Length"))
+	assert.Equal(t, `[{"prediction":{"class":"cat"}}]`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path+"/job-2/results", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestServeHTTP_ReturnsJobStatus(t *testing.T) {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{jobs: map[string]*storage.BatchJob{
+		"job-1": {
+			ID:         "job-1",
+			Tenant:     "acme",
+			Model:      "resnet18",
+			Version:    "v1",
+			Inputs:     []map[string]interface{}{{"data": []float64{1.0}}},
+			Status:     storage.StatusProcessing,
+			Progress:   0.5,
+			TotalItems: 4,
+			Completed:  2,
+			CreatedAt:  created,
+			UpdatedAt:  created.Add(time.Minute),
+		},
+	}}
+	handler := NewHandler(store, nil, zap.NewNop())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path+"/job-1", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "inputs")
+
+	var status Status
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, "job-1", status.JobID)
+	assert.Equal(t, "acme", status.Tenant)
+	assert.Equal(t, storage.StatusProcessing, status.Status)
+	assert.Equal(t, 0.5, status.Progress)
+	assert.Equal(t, 2, status.Completed)
+	assert.Equal(t, created, status.CreatedAt)
+}
+
+func TestServeHTTP_ListsJobsByPage(t *testing.T) {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{jobs: map[string]*storage.BatchJob{}}
+	for i, id := range []string{"job-1", "job-2", "job-3", "job-4", "job-5"} {
+		store.jobs[id] = &storage.BatchJob{
+			ID:        id,
+			Tenant:    "acme",
+			Model:     "resnet18",
+			Status:    storage.StatusCompleted,
+			CreatedAt: created.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	store.jobs["job-4"].Model = "bert"
+	store.jobs["job-5"].Tenant = "globex"
+	store.jobs["job-2"].UserID = "alice"
+	handler := NewHandler(store, nil, zap.NewNop())
+
+	list := func(query string) Page {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path+"?"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var page Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page
+	}
+	ids := func(page Page) []string {
+		var ids []string
+		for _, job := range page.Jobs {
+			ids = append(ids, job.JobID)
+		}
+		return ids
+	}
+
+	first := list("tenant=acme&limit=2")
+	assert.Equal(t, []string{"job-4", "job-3"}, ids(first))
+	require.NotEmpty(t, first.NextCursor)
+
+	second := list("tenant=acme&limit=2&cursor=" + first.NextCursor)
+	assert.Equal(t, []string{"job-2", "job-1"}, ids(second))
+	assert.Empty(t, second.NextCursor)
+
+	assert.Equal(t, []string{"job-3", "job-2", "job-1"}, ids(list("tenant=acme&model=resnet18")))
+	assert.Equal(t, []string{"job-3", "job-2"}, ids(list("tenant=acme&model=resnet18&created_after=2026-10-01T12:01:00Z&created_before=2026-10-01T12:03:00Z")))
+	assert.Equal(t, []string{"job-2"}, ids(list("user_id=alice")))
+	assert.Empty(t, list("status=failed").Jobs)
+}
+
+func TestServeHTTP_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		storeErr   error
+		wantStatus int
+	}{
+		{"unknown job", http.MethodGet, Path + "/job-2", nil, http.StatusNotFound},
+		{"bad cursor", http.MethodGet, Path + "?cursor=bm9wZQ", nil, http.StatusBadRequest},
+		{"bad time", http.MethodGet, Path + "?created_after=yesterday", nil, http.StatusBadRequest},
+		{"bad limit", http.MethodGet, Path + "?limit=0", nil, http.StatusBadRequest},
+		{"listing store down", http.MethodGet, Path, errors.New("connection refused"), http.StatusServiceUnavailable},
+		{"nested path", http.MethodGet, Path + "/job-1/inputs", nil, http.StatusNotFound},
+		{"wrong method", http.MethodPut, Path + "/job-1", nil, http.StatusNotImplemented},
+		{"store down", http.MethodGet, Path + "/job-1", errors.New("connection refused"), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(&fakeStore{err: tt.storeErr}, nil, zap.NewNop())
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
+// KafkaLag reports how many job messages the worker's consumer group has yet
+// to consume, across every replica
+type KafkaLag struct {
+	client sarama.Client
+	admin  sarama.ClusterAdmin
+	topics []string
+	group  string
+}
+
+// NewKafkaLag connects to the brokers to read offsets for topics and group
+func NewKafkaLag(brokers []string, topics []string, group string) (*KafkaLag, error) {
+	client, err := sarama.NewClient(brokers, sarama.NewConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to create kafka cluster admin: %w", err)
+	}
+
+	return &KafkaLag{client: client, admin: admin, topics: topics, group: group}, nil
+}
+
+// Lag returns the consumer lag summed over the topics' partitions. sarama
+// calls are not context aware; ctx bounds only the wait.
+func (k *KafkaLag) Lag(ctx context.Context) (int64, error) {
+	type result struct {
+		lag int64
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var total int64
+		for _, topic := range k.topics {
+			lag, err := k.lag(topic)
+			if err != nil {
+				done <- result{0, fmt.Errorf("%s: %w", topic, err)}
+				return
+			}
+			total += lag
+		}
+		done <- result{total, nil}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case r := <-done:
+		return r.lag, r.err
+	}
+}
+
+func (k *KafkaLag) lag(topic string) (int64, error) {
+	partitions, err := k.client.Partitions(topic)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	committed, err := k.admin.ListConsumerGroupOffsets(k.group, map[string][]int32{topic: partitions})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch consumer group offsets: %w", err)
+	}
+
+	var lag int64
+	for _, partition := range partitions {
+		newest, err := k.client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch offset for partition %d: %w", partition, err)
+		}
+
+		// The group starts from the newest offset, so a partition it never
+		// committed on has nothing queued for it
+		block := committed.GetBlock(topic, partition)
+		if block != nil && block.Offset >= 0 && newest > block.Offset {
+			lag += newest - block.Offset
+		}
+	}
+	return lag, nil
+}
+
+// Close releases the Kafka connections
+func (k *KafkaLag) Close() error {
+	return k.admin.Close()
+}
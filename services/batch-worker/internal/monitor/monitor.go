@@ -0,0 +1,135 @@
+// Package monitor tracks the batch worker's backlog of unfinished jobs and
+// serves it to the gateway, which stops accepting jobs while it is too deep.
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/batch-worker/internal/storage"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/backlog"
+	"go.uber.org/zap"
+)
+
+// smoothing is the weight of the latest sample in the throughput average
+const smoothing = 0.3
+
+// LagSource reports the job messages not yet consumed
+type LagSource interface {
+	Lag(ctx context.Context) (int64, error)
+}
+
+// JobCounter counts jobs by status
+type JobCounter interface {
+	CountJobsByStatus(ctx context.Context) (map[storage.JobStatus]int64, error)
+}
+
+// Monitor samples the Kafka lag and the unfinished jobs, and estimates how
+// quickly the backlog drains from the rate jobs finish at
+type Monitor struct {
+	lag    LagSource
+	jobs   JobCounter
+	logger *zap.Logger
+
+	mu         sync.Mutex
+	current    *backlog.Backlog
+	finished   int64
+	sampledAt  time.Time
+	throughput float64
+	rated      bool
+}
+
+// NewMonitor creates a monitor; lag may be nil when Kafka offsets are unavailable
+func NewMonitor(lag LagSource, jobs JobCounter, logger *zap.Logger) *Monitor {
+	return &Monitor{lag: lag, jobs: jobs, logger: logger}
+}
+
+// Refresh samples the backlog
+func (m *Monitor) Refresh(ctx context.Context, now time.Time) error {
+	counts, err := m.jobs.CountJobsByStatus(ctx)
+	if err != nil {
+		return err
+	}
+	var lag int64
+	if m.lag != nil {
+		if lag, err = m.lag.Lag(ctx); err != nil {
+			return err
+		}
+	}
+
+	pending := counts[storage.StatusPending] + counts[storage.StatusProcessing]
+	finished := counts[storage.StatusCompleted] + counts[storage.StatusFailed] + counts[storage.StatusCancelled]
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.sampledAt.IsZero() {
+		// Retention and deletions remove finished jobs, so the count can shrink
+		done := finished - m.finished
+		if done < 0 {
+			done = 0
+		}
+		rate := float64(done) / now.Sub(m.sampledAt).Seconds()
+		if m.rated {
+			m.throughput = smoothing*rate + (1-smoothing)*m.throughput
+		} else {
+			m.throughput = rate
+			m.rated = true
+		}
+	}
+	m.finished = finished
+	m.sampledAt = now
+
+	b := backlog.New(lag, pending, m.throughput, now)
+	m.current = &b
+	return nil
+}
+
+// Run refreshes the backlog every interval until ctx is cancelled
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		refreshCtx, cancel := context.WithTimeout(ctx, interval)
+		if err := m.Refresh(refreshCtx, time.Now().UTC()); err != nil && ctx.Err() == nil {
+			m.logger.Warn("failed to sample batch backlog", zap.Error(err))
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Backlog returns the latest sample, or nil before the first
+func (m *Monitor) Backlog() *backlog.Backlog {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.current == nil {
+		return nil
+	}
+	b := *m.current
+	return &b
+}
+
+// ServeHTTP serves the latest sample
+func (m *Monitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apperrors.WriteHTTP(w, apperrors.New(apperrors.Unimplemented, "method not allowed"))
+		return
+	}
+	b := m.Backlog()
+	if b == nil {
+		apperrors.WriteHTTP(w, apperrors.New(apperrors.Unavailable, "backlog not sampled yet"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(b)
+}
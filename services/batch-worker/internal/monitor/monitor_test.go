@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ai-platform/batch-worker/internal/storage"
+	"github.com/yourusername/ai-platform/pkg/backlog"
+	"go.uber.org/zap"
+)
+
+type fakeCounts map[storage.JobStatus]int64
+
+func (f fakeCounts) CountJobsByStatus(ctx context.Context) (map[storage.JobStatus]int64, error) {
+	return f, nil
+}
+
+type fakeLag int64
+
+func (f fakeLag) Lag(ctx context.Context) (int64, error) {
+	return int64(f), nil
+}
+
+func TestMonitor_Refresh(t *testing.T) {
+	counts := fakeCounts{storage.StatusPending: 15, storage.StatusProcessing: 5, storage.StatusCompleted: 100}
+	m := NewMonitor(fakeLag(20), counts, zap.NewNop())
+	assert.Nil(t, m.Backlog())
+
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, m.Refresh(context.Background(), start))
+	b := m.Backlog()
+	require.NotNil(t, b)
+	assert.Equal(t, int64(40), b.Depth())
+	_, ok := b.EstimatedDelay()
+	assert.False(t, ok, "throughput is unknown after one sample")
+
+	// 18 jobs completed and 2 failed in 10 seconds
+	counts[storage.StatusCompleted] = 118
+	counts[storage.StatusFailed] = 2
+	require.NoError(t, m.Refresh(context.Background(), start.Add(10*time.Second)))
+	b = m.Backlog()
+	assert.InDelta(t, 2, b.Throughput, 0.001)
+	delay, ok := b.EstimatedDelay()
+	assert.True(t, ok)
+	assert.Equal(t, 20*time.Second, delay)
+
+	// Purged jobs do not count as negative throughput
+	counts[storage.StatusCompleted] = 0
+	counts[storage.StatusFailed] = 0
+	require.NoError(t, m.Refresh(context.Background(), start.Add(20*time.Second)))
+	assert.InDelta(t, 1.4, m.Backlog().Throughput, 0.001)
+}
+
+func TestMonitor_ServeHTTP(t *testing.T) {
+	m := NewMonitor(nil, fakeCounts{storage.StatusPending: 3}, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, backlog.Path, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	require.NoError(t, m.Refresh(context.Background(), time.Now()))
+	rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, backlog.Path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var b backlog.Backlog
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &b))
+	assert.Equal(t, int64(3), b.PendingJobs)
+	assert.Zero(t, b.Lag)
+}
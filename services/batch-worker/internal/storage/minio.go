@@ -5,34 +5,43 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"sync/atomic"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
 	"go.uber.org/zap"
 )
 
+// ResultsPath is where the gateway serves a job's results. It is recorded as
+// the job's result URL so clients download results through the gateway's
+// auth checks rather than from MinIO directly.
+func ResultsPath(jobID string) string {
+	return "/v1/jobs/" + jobID + "/results"
+}
+
 // MinIOStore handles object storage operations
 type MinIOStore struct {
-	client *minio.Client
-	bucket string
-	logger *zap.Logger
+	client   atomic.Pointer[minio.Client]
+	endpoint string
+	bucket   string
+	logger   *zap.Logger
 }
 
 // NewMinIOStore creates a new MinIO store
 func NewMinIOStore(endpoint, accessKey, secretKey, bucket string, logger *zap.Logger) (*MinIOStore, error) {
-	client, err := minio.New(endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
-		Secure: false, // Set to true for HTTPS
-	})
+	client, err := newClient(endpoint, accessKey, secretKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create minio client: %w", err)
+		return nil, err
 	}
 
 	store := &MinIOStore{
-		client: client,
-		bucket: bucket,
-		logger: logger,
+		endpoint: endpoint,
+		bucket:   bucket,
+		logger:   logger,
 	}
+	store.client.Store(client)
 
 	// Ensure bucket exists
 	if err := store.ensureBucket(context.Background()); err != nil {
@@ -42,15 +51,50 @@ func NewMinIOStore(endpoint, accessKey, secretKey, bucket string, logger *zap.Lo
 	return store, nil
 }
 
+// newClient creates a MinIO client that signs requests with the given keys
+func newClient(endpoint, accessKey, secretKey string) (*minio.Client, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: false, // Set to true for HTTPS
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create minio client: %w", err)
+	}
+	return client, nil
+}
+
+// SetCredentials replaces the MinIO client with one using new keys, typically
+// after the MinIO credentials rotate
+func (s *MinIOStore) SetCredentials(accessKey, secretKey string) error {
+	client, err := newClient(s.endpoint, accessKey, secretKey)
+	if err != nil {
+		return err
+	}
+	s.client.Store(client)
+	return nil
+}
+
+// Ping verifies that MinIO is reachable and the results bucket exists
+func (s *MinIOStore) Ping(ctx context.Context) error {
+	exists, err := s.client.Load().BucketExists(ctx, s.bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", s.bucket)
+	}
+	return nil
+}
+
 // ensureBucket creates the bucket if it doesn't exist
 func (s *MinIOStore) ensureBucket(ctx context.Context) error {
-	exists, err := s.client.BucketExists(ctx, s.bucket)
+	exists, err := s.client.Load().BucketExists(ctx, s.bucket)
 	if err != nil {
 		return err
 	}
 
 	if !exists {
-		err = s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{})
+		err = s.client.Load().MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{})
 		if err != nil {
 			return err
 		}
@@ -60,7 +104,8 @@ func (s *MinIOStore) ensureBucket(ctx context.Context) error {
 	return nil
 }
 
-// UploadResults uploads batch inference results to MinIO
+// UploadResults uploads batch inference results to MinIO and returns the
+// path they are downloaded from
 func (s *MinIOStore) UploadResults(ctx context.Context, jobID string, results []map[string]interface{}) (string, error) {
 	// Convert results to JSON
 	data, err := json.MarshalIndent(results, "", "  ")
@@ -72,7 +117,7 @@ func (s *MinIOStore) UploadResults(ctx context.Context, jobID string, results []
 	objectName := fmt.Sprintf("results/%s.json", jobID)
 
 	// Upload to MinIO
-	_, err = s.client.PutObject(
+	_, err = s.client.Load().PutObject(
 		ctx,
 		s.bucket,
 		objectName,
@@ -87,26 +132,84 @@ func (s *MinIOStore) UploadResults(ctx context.Context, jobID string, results []
 		return "", fmt.Errorf("failed to upload results: %w", err)
 	}
 
-	// Generate presigned URL (valid for 7 days)
-	url, err := s.client.PresignedGetObject(ctx, s.bucket, objectName, 7*24*3600, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate presigned URL: %w", err)
-	}
-
 	s.logger.Info("uploaded results",
 		zap.String("job_id", jobID),
 		zap.String("object", objectName),
 		zap.Int("size_bytes", len(data)),
 	)
 
-	return url.String(), nil
+	return ResultsPath(jobID), nil
+}
+
+// OpenResults returns a reader over a job's stored results and their size
+func (s *MinIOStore) OpenResults(ctx context.Context, jobID string) (io.ReadCloser, int64, error) {
+	objectName := fmt.Sprintf("results/%s.json", jobID)
+
+	object, err := s.client.Load().GetObject(ctx, s.bucket, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get object: %w", err)
+	}
+	// GetObject is lazy; Stat reports a missing object
+	info, err := object.Stat()
+	if err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, 0, apperrors.Newf(apperrors.NotFound, "no results for job %s", jobID)
+		}
+		return nil, 0, fmt.Errorf("failed to stat object: %w", err)
+	}
+	return object, info.Size, nil
+}
+
+// DeleteResults removes the result objects of the given jobs and returns how
+// many existed. Jobs without results are skipped.
+func (s *MinIOStore) DeleteResults(ctx context.Context, jobIDs []string) (int64, error) {
+	var deleted int64
+	for _, jobID := range jobIDs {
+		objectName := fmt.Sprintf("results/%s.json", jobID)
+
+		if _, err := s.client.Load().StatObject(ctx, s.bucket, objectName, minio.StatObjectOptions{}); err != nil {
+			if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+				continue
+			}
+			return deleted, fmt.Errorf("failed to stat object: %w", err)
+		}
+		if err := s.client.Load().RemoveObject(ctx, s.bucket, objectName, minio.RemoveObjectOptions{}); err != nil {
+			return deleted, fmt.Errorf("failed to delete object: %w", err)
+		}
+		deleted++
+	}
+
+	if deleted > 0 {
+		s.logger.Info("deleted results", zap.Int64("objects", deleted))
+	}
+	return deleted, nil
+}
+
+// DeletePrefix removes every object under prefix and returns how many were deleted
+func (s *MinIOStore) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	var deleted int64
+	for object := range s.client.Load().ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return deleted, fmt.Errorf("failed to list objects: %w", object.Err)
+		}
+		if err := s.client.Load().RemoveObject(ctx, s.bucket, object.Key, minio.RemoveObjectOptions{}); err != nil {
+			return deleted, fmt.Errorf("failed to delete object: %w", err)
+		}
+		deleted++
+	}
+
+	if deleted > 0 {
+		s.logger.Info("deleted objects", zap.String("prefix", prefix), zap.Int64("objects", deleted))
+	}
+	return deleted, nil
 }
 
 // GetResults retrieves batch inference results from MinIO
 func (s *MinIOStore) GetResults(ctx context.Context, jobID string) ([]map[string]interface{}, error) {
 	objectName := fmt.Sprintf("results/%s.json", jobID)
 
-	object, err := s.client.GetObject(ctx, s.bucket, objectName, minio.GetObjectOptions{})
+	object, err := s.client.Load().GetObject(ctx, s.bucket, objectName, minio.GetObjectOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get object: %w", err)
 	}
@@ -5,9 +5,13 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/privacy"
 	"go.uber.org/zap"
 )
 
@@ -19,11 +23,16 @@ const (
 	StatusProcessing JobStatus = "processing"
 	StatusCompleted  JobStatus = "completed"
 	StatusFailed     JobStatus = "failed"
+	StatusCancelled  JobStatus = "cancelled"
 )
 
 // BatchJob represents a batch inference job
 type BatchJob struct {
 	ID          string                   `json:"id"`
+	Tenant      string                   `json:"tenant,omitempty"`
+	SubjectID   string                   `json:"subject_id,omitempty"`
+	UserID      string                   `json:"user_id,omitempty"`
+	CallbackURL string                   `json:"callback_url,omitempty"`
 	Model       string                   `json:"model"`
 	Version     string                   `json:"version"`
 	Inputs      []map[string]interface{} `json:"inputs"`
@@ -40,12 +49,32 @@ type BatchJob struct {
 
 // PostgresStore handles database operations for batch jobs
 type PostgresStore struct {
-	db     *sql.DB
+	db     atomic.Pointer[sql.DB]
 	logger *zap.Logger
 }
 
 // NewPostgresStore creates a new PostgreSQL store
 func NewPostgresStore(connectionURL string, logger *zap.Logger) (*PostgresStore, error) {
+	db, err := openDB(connectionURL)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &PostgresStore{
+		logger: logger,
+	}
+	store.db.Store(db)
+
+	// Initialize schema
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	return store, nil
+}
+
+// openDB opens a connection pool and checks that the database is reachable
+func openDB(connectionURL string) (*sql.DB, error) {
 	db, err := sql.Open("postgres", connectionURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -60,18 +89,21 @@ func NewPostgresStore(connectionURL string, logger *zap.Logger) (*PostgresStore,
 	db.SetMaxOpenConns(25)
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(5 * time.Minute)
+	return db, nil
+}
 
-	store := &PostgresStore{
-		db:     db,
-		logger: logger,
+// Reconnect replaces the connection pool with one opened with connectionURL,
+// typically after the database credentials rotate. Queries already running
+// on the old pool finish before it closes.
+func (s *PostgresStore) Reconnect(connectionURL string) error {
+	db, err := openDB(connectionURL)
+	if err != nil {
+		return err
 	}
-
-	// Initialize schema
-	if err := store.initSchema(); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	if old := s.db.Swap(db); old != nil {
+		return old.Close()
 	}
-
-	return store, nil
+	return nil
 }
 
 // initSchema creates the batch_jobs table if it doesn't exist
@@ -95,9 +127,28 @@ func (s *PostgresStore) initSchema() error {
 
 	CREATE INDEX IF NOT EXISTS idx_batch_jobs_status ON batch_jobs(status);
 	CREATE INDEX IF NOT EXISTS idx_batch_jobs_created_at ON batch_jobs(created_at);
+
+	-- Owner of the inputs and results, for deletion requests
+	ALTER TABLE batch_jobs ADD COLUMN IF NOT EXISTS tenant VARCHAR(255) NOT NULL DEFAULT '';
+	ALTER TABLE batch_jobs ADD COLUMN IF NOT EXISTS subject_id VARCHAR(255) NOT NULL DEFAULT '';
+	CREATE INDEX IF NOT EXISTS idx_batch_jobs_tenant_subject ON batch_jobs(tenant, subject_id);
+	CREATE INDEX IF NOT EXISTS idx_batch_jobs_tenant_created_at ON batch_jobs(tenant, created_at DESC, id DESC);
+
+	-- User that submitted the job, who alone may see it without tenancy
+	ALTER TABLE batch_jobs ADD COLUMN IF NOT EXISTS user_id VARCHAR(255) NOT NULL DEFAULT '';
+
+	CREATE TABLE IF NOT EXISTS privacy_deletions (
+		id VARCHAR(64) PRIMARY KEY,
+		tenant VARCHAR(255) NOT NULL,
+		subject_id VARCHAR(255) NOT NULL DEFAULT '',
+		requested_at TIMESTAMP NOT NULL,
+		report JSONB NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_privacy_deletions_tenant ON privacy_deletions(tenant, subject_id);
 	`
 
-	_, err := s.db.Exec(query)
+	_, err := s.db.Load().Exec(query)
 	return err
 }
 
@@ -109,12 +160,15 @@ func (s *PostgresStore) CreateJob(ctx context.Context, job *BatchJob) error {
 	}
 
 	query := `
-		INSERT INTO batch_jobs (id, model, version, inputs, status, total_items, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO batch_jobs (id, tenant, subject_id, user_id, model, version, inputs, status, total_items, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
-	_, err = s.db.ExecContext(ctx, query,
+	_, err = s.db.Load().ExecContext(ctx, query,
 		job.ID,
+		job.Tenant,
+		job.SubjectID,
+		job.UserID,
 		job.Model,
 		job.Version,
 		inputsJSON,
@@ -145,7 +199,7 @@ func (s *PostgresStore) UpdateJobProgress(ctx context.Context, jobID string, com
 		WHERE id = $4
 	`
 
-	_, err := s.db.ExecContext(ctx, query, completed, progress, time.Now(), jobID)
+	_, err := s.db.Load().ExecContext(ctx, query, completed, progress, time.Now(), jobID)
 	if err != nil {
 		return fmt.Errorf("failed to update job progress: %w", err)
 	}
@@ -162,12 +216,12 @@ func (s *PostgresStore) UpdateJobStatus(ctx context.Context, jobID string, statu
 	`
 
 	var completedAt *time.Time
-	if status == StatusCompleted || status == StatusFailed {
+	if status == StatusCompleted || status == StatusFailed || status == StatusCancelled {
 		now := time.Now()
 		completedAt = &now
 	}
 
-	_, err := s.db.ExecContext(ctx, query, status, resultURL, errorMsg, time.Now(), completedAt, jobID)
+	_, err := s.db.Load().ExecContext(ctx, query, status, resultURL, errorMsg, time.Now(), completedAt, jobID)
 	if err != nil {
 		return fmt.Errorf("failed to update job status: %w", err)
 	}
@@ -183,7 +237,7 @@ func (s *PostgresStore) UpdateJobStatus(ctx context.Context, jobID string, statu
 // GetJob retrieves a batch job by ID
 func (s *PostgresStore) GetJob(ctx context.Context, jobID string) (*BatchJob, error) {
 	query := `
-		SELECT id, model, version, inputs, status, progress, total_items, completed,
+		SELECT id, tenant, subject_id, user_id, model, version, inputs, status, progress, total_items, completed,
 		       result_url, error_msg, created_at, updated_at, completed_at
 		FROM batch_jobs
 		WHERE id = $1
@@ -194,8 +248,11 @@ func (s *PostgresStore) GetJob(ctx context.Context, jobID string) (*BatchJob, er
 	var resultURL, errorMsg sql.NullString
 	var completedAt sql.NullTime
 
-	err := s.db.QueryRowContext(ctx, query, jobID).Scan(
+	err := s.db.Load().QueryRowContext(ctx, query, jobID).Scan(
 		&job.ID,
+		&job.Tenant,
+		&job.SubjectID,
+		&job.UserID,
 		&job.Model,
 		&job.Version,
 		&inputsJSON,
@@ -211,7 +268,7 @@ func (s *PostgresStore) GetJob(ctx context.Context, jobID string) (*BatchJob, er
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("job not found: %s", jobID)
+		return nil, apperrors.Newf(apperrors.NotFound, "job not found: %s", jobID)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get job: %w", err)
@@ -234,7 +291,244 @@ func (s *PostgresStore) GetJob(ctx context.Context, jobID string) (*BatchJob, er
 	return &job, nil
 }
 
+// JobFilter selects the batch jobs to list. Zero fields match every job.
+type JobFilter struct {
+	Tenant        string
+	UserID        string
+	Status        JobStatus
+	Model         string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// AfterCreatedAt and AfterID continue a listing after the job last returned
+	AfterCreatedAt time.Time
+	AfterID        string
+	Limit          int
+}
+
+// ListJobs returns the jobs matching filter, newest first, without their inputs
+func (s *PostgresStore) ListJobs(ctx context.Context, filter JobFilter) ([]BatchJob, error) {
+	var conditions []string
+	var args []interface{}
+	// where adds a condition whose %d verbs are numbered placeholders for values
+	where := func(condition string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if filter.Tenant != "" {
+		where("tenant = $%d", filter.Tenant)
+	}
+	if filter.UserID != "" {
+		where("user_id = $%d", filter.UserID)
+	}
+	if filter.Status != "" {
+		where("status = $%d", filter.Status)
+	}
+	if filter.Model != "" {
+		where("model = $%d", filter.Model)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		where("created_at >= $%d", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		where("created_at < $%d", filter.CreatedBefore)
+	}
+	if filter.AfterID != "" {
+		where("(created_at, id) < ($%d, $%d)", filter.AfterCreatedAt, filter.AfterID)
+	}
+
+	query := `
+		SELECT id, tenant, subject_id, user_id, model, version, status, progress, total_items, completed,
+		       result_url, error_msg, created_at, updated_at, completed_at
+		FROM batch_jobs`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := s.db.Load().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []BatchJob
+	for rows.Next() {
+		var job BatchJob
+		var resultURL, errorMsg sql.NullString
+		var completedAt sql.NullTime
+		if err := rows.Scan(
+			&job.ID,
+			&job.Tenant,
+			&job.SubjectID,
+			&job.UserID,
+			&job.Model,
+			&job.Version,
+			&job.Status,
+			&job.Progress,
+			&job.TotalItems,
+			&job.Completed,
+			&resultURL,
+			&errorMsg,
+			&job.CreatedAt,
+			&job.UpdatedAt,
+			&completedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		job.ResultURL = resultURL.String
+		job.ErrorMsg = errorMsg.String
+		if completedAt.Valid {
+			job.CompletedAt = &completedAt.Time
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// CountJobsByStatus returns the number of batch jobs in each status
+func (s *PostgresStore) CountJobsByStatus(ctx context.Context) (map[JobStatus]int64, error) {
+	rows, err := s.db.Load().QueryContext(ctx, `SELECT status, COUNT(*) FROM batch_jobs GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count jobs: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[JobStatus]int64{
+		StatusPending:    0,
+		StatusProcessing: 0,
+		StatusCompleted:  0,
+		StatusFailed:     0,
+		StatusCancelled:  0,
+	}
+	for rows.Next() {
+		var status JobStatus
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan job count: %w", err)
+		}
+		counts[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count jobs: %w", err)
+	}
+
+	return counts, nil
+}
+
+// JobIDsForSubject returns the IDs of a tenant's jobs, or only those of one
+// data subject when subject is set
+func (s *PostgresStore) JobIDsForSubject(ctx context.Context, tenant, subject string) ([]string, error) {
+	query := `SELECT id FROM batch_jobs WHERE tenant = $1 AND ($2 = '' OR subject_id = $2)`
+	return s.queryJobIDs(ctx, query, tenant, subject)
+}
+
+// JobIDsBefore returns the IDs of finished jobs created before cutoff
+func (s *PostgresStore) JobIDsBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	query := `SELECT id FROM batch_jobs WHERE created_at < $1 AND status IN ($2, $3, $4)`
+	return s.queryJobIDs(ctx, query, cutoff, StatusCompleted, StatusFailed, StatusCancelled)
+}
+
+func (s *PostgresStore) queryJobIDs(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := s.db.Load().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan job id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return ids, nil
+}
+
+// DeleteJobs removes jobs, with their inputs, by ID
+func (s *PostgresStore) DeleteJobs(ctx context.Context, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result, err := s.db.Load().ExecContext(ctx, `DELETE FROM batch_jobs WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// SaveDeletion stores a deletion report, replacing an earlier version of it
+func (s *PostgresStore) SaveDeletion(ctx context.Context, report *privacy.Report) error {
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deletion report: %w", err)
+	}
+
+	query := `
+		INSERT INTO privacy_deletions (id, tenant, subject_id, requested_at, report)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET report = EXCLUDED.report
+	`
+	if _, err := s.db.Load().ExecContext(ctx, query, report.ID, report.Tenant, report.Subject, report.RequestedAt, reportJSON); err != nil {
+		return fmt.Errorf("failed to save deletion report: %w", err)
+	}
+	return nil
+}
+
+// GetDeletion retrieves a deletion report by ID
+func (s *PostgresStore) GetDeletion(ctx context.Context, id string) (*privacy.Report, error) {
+	var reportJSON []byte
+	err := s.db.Load().QueryRowContext(ctx, `SELECT report FROM privacy_deletions WHERE id = $1`, id).Scan(&reportJSON)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.Newf(apperrors.NotFound, "deletion not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deletion report: %w", err)
+	}
+
+	var report privacy.Report
+	if err := json.Unmarshal(reportJSON, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal deletion report: %w", err)
+	}
+	return &report, nil
+}
+
+// DeletedSince reports whether the tenant's, or the subject's, data was
+// requested deleted at or after submittedAt. Jobs still queued when a
+// deletion is requested are discarded rather than stored.
+func (s *PostgresStore) DeletedSince(ctx context.Context, tenant, subject string, submittedAt time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM privacy_deletions
+			WHERE tenant = $1 AND (subject_id = '' OR subject_id = $2) AND requested_at >= $3
+		)
+	`
+	var deleted bool
+	if err := s.db.Load().QueryRowContext(ctx, query, tenant, subject, submittedAt).Scan(&deleted); err != nil {
+		return false, fmt.Errorf("failed to check deletions: %w", err)
+	}
+	return deleted, nil
+}
+
+// Ping verifies the database connection
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	return s.db.Load().PingContext(ctx)
+}
+
 // Close closes the database connection
 func (s *PostgresStore) Close() error {
-	return s.db.Close()
+	return s.db.Load().Close()
 }
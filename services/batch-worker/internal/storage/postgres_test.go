@@ -31,6 +31,7 @@ func TestJobStatus_Values(t *testing.T) {
 	assert.Equal(t, JobStatus("processing"), StatusProcessing)
 	assert.Equal(t, JobStatus("completed"), StatusCompleted)
 	assert.Equal(t, JobStatus("failed"), StatusFailed)
+	assert.Equal(t, JobStatus("cancelled"), StatusCancelled)
 }
 
 // Integration test - requires PostgreSQL
@@ -54,6 +55,7 @@ func TestPostgresStore_Integration(t *testing.T) {
 	// Test create job
 	job := &BatchJob{
 		ID:         "test-job-integration",
+		UserID:     "alice",
 		Model:      "resnet18",
 		Version:    "v1",
 		Inputs:     []map[string]interface{}{{"data": []float64{1.0, 2.0}}},
@@ -71,6 +73,15 @@ func TestPostgresStore_Integration(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, job.ID, retrieved.ID)
 	assert.Equal(t, job.Model, retrieved.Model)
+	assert.Equal(t, "alice", retrieved.UserID)
+
+	// Test list jobs
+	listed, err := store.ListJobs(ctx, JobFilter{Model: job.Model, Status: StatusPending, Limit: 10})
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, listed) {
+		assert.Equal(t, job.ID, listed[0].ID)
+		assert.Nil(t, listed[0].Inputs)
+	}
 
 	// Test update progress
 	err = store.UpdateJobProgress(ctx, job.ID, 1, 1.0)
@@ -6,10 +6,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/yourusername/ai-platform/batch-worker/internal/callback"
 	"github.com/yourusername/ai-platform/batch-worker/internal/storage"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/pricing"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+	"github.com/yourusername/ai-platform/pkg/usage"
+	"github.com/yourusername/ai-platform/pkg/webhooks"
 	"go.uber.org/zap"
 )
 
@@ -26,6 +36,10 @@ type InferenceResult struct {
 	Prediction map[string]interface{} `json:"prediction"`
 	Latency    int64                  `json:"latency_ms"`
 	Error      string                 `json:"error,omitempty"`
+	Code       apperrors.Code         `json:"code,omitempty"`
+	// EstimatedCost is the inference's cost at the worker's model pricing;
+	// failed inferences cost nothing
+	EstimatedCost float64 `json:"estimated_cost,omitempty"`
 }
 
 // PostgresStoreInterface defines the interface for Postgres operations
@@ -42,6 +56,11 @@ type MinIOStoreInterface interface {
 	UploadResults(ctx context.Context, jobID string, results []map[string]interface{}) (string, error)
 }
 
+// TenantAuthorizer reports whether a tenant may run jobs and with what limits
+type TenantAuthorizer interface {
+	Authorize(ctx context.Context, id string) (*tenancy.Tenant, error)
+}
+
 // Pool represents a worker pool for processing batch jobs
 type Pool struct {
 	size            int
@@ -50,6 +69,16 @@ type Pool struct {
 	minioStore      MinIOStoreInterface
 	logger          *zap.Logger
 	httpClient      *http.Client
+	usage           *usage.Recorder
+	events          *events.Emitter
+	tenants         TenantAuthorizer
+	callbacks       *callback.Notifier
+	webhooks        *webhooks.Dispatcher
+	pricing         *pricing.Table
+
+	mu sync.Mutex
+	// running holds a stop channel per job being processed, closed to cancel it
+	running map[string]chan struct{}
 }
 
 // NewPool creates a new worker pool
@@ -63,17 +92,138 @@ func NewPool(size int, orchestratorURL string, pgStore PostgresStoreInterface, m
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		running: make(map[string]chan struct{}),
+	}
+}
+
+// SetHTTPClient replaces the client used to call the orchestrator, e.g. with
+// an mTLS client. Its timeout applies to inferences without a deadline.
+func (p *Pool) SetHTTPClient(client *http.Client) {
+	p.httpClient = client
+}
+
+// SetUsageRecorder meters the inferences of each processed job for billing
+func (p *Pool) SetUsageRecorder(recorder *usage.Recorder) {
+	p.usage = recorder
+}
+
+// SetEventEmitter announces finished jobs to the notification service
+func (p *Pool) SetEventEmitter(emitter *events.Emitter) {
+	p.events = emitter
+}
+
+// SetTenants checks each job's tenant and limits before the job runs
+func (p *Pool) SetTenants(tenants TenantAuthorizer) {
+	p.tenants = tenants
+}
+
+// SetCallbacks posts the results of async inferences to their callback URLs
+func (p *Pool) SetCallbacks(notifier *callback.Notifier) {
+	p.callbacks = notifier
+}
+
+// SetWebhooks delivers finished jobs to the webhook endpoints subscribing to them
+func (p *Pool) SetWebhooks(dispatcher *webhooks.Dispatcher) {
+	p.webhooks = dispatcher
+}
+
+// SetPricing estimates the cost of each inference, returned with the job's
+// results and metered with its usage
+func (p *Pool) SetPricing(table *pricing.Table) {
+	p.pricing = table
+}
+
+// Cancel stops a job this pool is running from dispatching its remaining
+// inputs. Inputs already sent to the orchestrator finish, and the job is then
+// marked cancelled. It reports whether the job was running here.
+func (p *Pool) Cancel(jobID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stop, ok := p.running[jobID]
+	if !ok {
+		return false
 	}
+	delete(p.running, jobID)
+	close(stop)
+	return true
+}
+
+// track registers a job as running and returns the channel closed when it is cancelled
+func (p *Pool) track(jobID string) <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stop := make(chan struct{})
+	p.running[jobID] = stop
+	return stop
+}
+
+func (p *Pool) untrack(jobID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.running, jobID)
+}
+
+// stopped reports whether stop has been closed
+func stopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// admit returns why a job's tenant may not run it. Jobs are admitted when the
+// tenant service cannot be reached, since the gateway checked them on submission.
+func (p *Pool) admit(ctx context.Context, job *storage.BatchJob) error {
+	if p.tenants == nil || job.Tenant == "" {
+		return nil
+	}
+
+	tenant, err := p.tenants.Authorize(ctx, job.Tenant)
+	if apperrors.Is(err, apperrors.PermissionDenied) {
+		return err
+	}
+	if err != nil {
+		logging.With(ctx, p.logger).Warn("admitting job without checking its tenant", zap.Error(err))
+		return nil
+	}
+
+	if max := tenant.Limits.MaxBatchItems; max > 0 && job.TotalItems > max {
+		return apperrors.Newf(apperrors.InvalidArgument, "batch has %d inputs; the tenant limit is %d", job.TotalItems, max)
+	}
+	return nil
 }
 
 // ProcessJob processes a batch job with worker pool
 func (p *Pool) ProcessJob(ctx context.Context, job *storage.BatchJob) error {
-	p.logger.Info("processing batch job",
-		zap.String("job_id", job.ID),
+	ctx = logging.WithJobID(ctx, job.ID)
+	// Batch inferences are bulk traffic, served after real-time requests
+	// whatever the job's priority among other jobs
+	ctx = logging.WithPriority(ctx, schema.PriorityLow)
+	logger := logging.With(ctx, p.logger)
+
+	// Jobs their tenant may not run fail without being retried
+	if err := p.admit(ctx, job); err != nil {
+		logger.Warn("rejecting batch job", zap.Error(err))
+		if err := p.pgStore.UpdateJobStatus(ctx, job.ID, storage.StatusFailed, "", err.Error()); err != nil {
+			return fmt.Errorf("failed to update job status: %w", err)
+		}
+		p.emitFinished(ctx, job, storage.StatusFailed, 0, "", err.Error())
+		p.callBack(ctx, job, storage.StatusFailed, nil, err.Error())
+		return nil
+	}
+
+	logger.Info("processing batch job",
 		zap.Int("total_items", job.TotalItems),
 		zap.Int("workers", p.size),
 	)
 
+	stop := p.track(job.ID)
+	defer p.untrack(job.ID)
+
 	// Update status to processing
 	if err := p.pgStore.UpdateJobStatus(ctx, job.ID, storage.StatusProcessing, "", ""); err != nil {
 		return fmt.Errorf("failed to update job status: %w", err)
@@ -93,7 +243,7 @@ func (p *Pool) ProcessJob(ctx context.Context, job *storage.BatchJob) error {
 	var wg sync.WaitGroup
 	for i := 0; i < p.size; i++ {
 		wg.Add(1)
-		go p.worker(ctx, &wg, job, inputChan, resultChan)
+		go p.worker(ctx, &wg, job, stop, inputChan, resultChan)
 	}
 
 	// Send inputs to workers
@@ -115,6 +265,8 @@ func (p *Pool) ProcessJob(ctx context.Context, job *storage.BatchJob) error {
 	results := make([]map[string]interface{}, len(job.Inputs))
 	completed := 0
 	errorCount := 0
+	var latencyMs int64
+	var cost float64
 
 	go func() {
 		wg.Wait()
@@ -124,6 +276,8 @@ func (p *Pool) ProcessJob(ctx context.Context, job *storage.BatchJob) error {
 	// Process results as they come in
 	for result := range resultChan {
 		completed++
+		latencyMs += result.result.Latency
+		cost += result.result.EstimatedCost
 		progress := float64(completed) / float64(job.TotalItems)
 
 		// Store result
@@ -132,9 +286,13 @@ func (p *Pool) ProcessJob(ctx context.Context, job *storage.BatchJob) error {
 			"prediction": result.result.Prediction,
 			"latency_ms": result.result.Latency,
 		}
+		if result.result.EstimatedCost > 0 {
+			resultData["estimated_cost"] = result.result.EstimatedCost
+		}
 
 		if result.result.Error != "" {
 			resultData["error"] = result.result.Error
+			resultData["code"] = result.result.Code
 			errorCount++
 		}
 
@@ -143,11 +301,10 @@ func (p *Pool) ProcessJob(ctx context.Context, job *storage.BatchJob) error {
 		// Update progress every 10% or on completion
 		if completed%max(1, job.TotalItems/10) == 0 || completed == job.TotalItems {
 			if err := p.pgStore.UpdateJobProgress(ctx, job.ID, completed, progress); err != nil {
-				p.logger.Error("failed to update progress", zap.Error(err))
+				logger.Error("failed to update progress", zap.Error(err))
 			}
 
-			p.logger.Info("batch job progress",
-				zap.String("job_id", job.ID),
+			logger.Info("batch job progress",
 				zap.Int("completed", completed),
 				zap.Int("total", job.TotalItems),
 				zap.Float64("progress", progress),
@@ -155,13 +312,20 @@ func (p *Pool) ProcessJob(ctx context.Context, job *storage.BatchJob) error {
 		}
 	}
 
+	// Cancelled jobs keep the results of the inputs they finished
+	if completed < job.TotalItems && stopped(stop) {
+		return p.finishCancelled(ctx, job, completed, errorCount, latencyMs, cost)
+	}
+
 	// Upload results to MinIO
 	resultURL, err := p.minioStore.UploadResults(ctx, job.ID, results)
 	if err != nil {
-		p.logger.Error("failed to upload results", zap.Error(err))
+		logger.Error("failed to upload results", zap.Error(err))
 		if err := p.pgStore.UpdateJobStatus(ctx, job.ID, storage.StatusFailed, "", err.Error()); err != nil {
-			p.logger.Error("failed to update job status", zap.Error(err))
+			logger.Error("failed to update job status", zap.Error(err))
 		}
+		p.emitFinished(ctx, job, storage.StatusFailed, errorCount, "", err.Error())
+		p.callBack(ctx, job, storage.StatusFailed, results, err.Error())
 		return fmt.Errorf("failed to upload results: %w", err)
 	}
 
@@ -180,8 +344,11 @@ func (p *Pool) ProcessJob(ctx context.Context, job *storage.BatchJob) error {
 		return fmt.Errorf("failed to update final status: %w", err)
 	}
 
-	p.logger.Info("batch job completed",
-		zap.String("job_id", job.ID),
+	p.recordUsage(ctx, job, job.TotalItems, errorCount, latencyMs, cost)
+	p.emitFinished(ctx, job, finalStatus, errorCount, resultURL, errorMsg)
+	p.callBack(ctx, job, finalStatus, results, "")
+
+	logger.Info("batch job completed",
 		zap.String("status", string(finalStatus)),
 		zap.Int("total", job.TotalItems),
 		zap.Int("errors", errorCount),
@@ -191,11 +358,111 @@ func (p *Pool) ProcessJob(ctx context.Context, job *storage.BatchJob) error {
 	return nil
 }
 
+// finishCancelled marks a job cancelled after completed of its inputs were
+// inferred, and bills those inputs
+func (p *Pool) finishCancelled(ctx context.Context, job *storage.BatchJob, completed, errorCount int, latencyMs int64, cost float64) error {
+	logger := logging.With(ctx, p.logger)
+
+	progress := float64(completed) / float64(job.TotalItems)
+	if err := p.pgStore.UpdateJobProgress(ctx, job.ID, completed, progress); err != nil {
+		logger.Error("failed to update progress", zap.Error(err))
+	}
+	errorMsg := fmt.Sprintf("cancelled after %d/%d items", completed, job.TotalItems)
+	if err := p.pgStore.UpdateJobStatus(ctx, job.ID, storage.StatusCancelled, "", errorMsg); err != nil {
+		return fmt.Errorf("failed to update final status: %w", err)
+	}
+
+	p.recordUsage(ctx, job, completed, errorCount, latencyMs, cost)
+	p.callBack(ctx, job, storage.StatusCancelled, nil, errorMsg)
+
+	logger.Info("batch job cancelled",
+		zap.Int("completed", completed),
+		zap.Int("total", job.TotalItems),
+	)
+	return nil
+}
+
+// recordUsage bills the inferences a job ran
+func (p *Pool) recordUsage(ctx context.Context, job *storage.BatchJob, requests, errorCount int, latencyMs int64, cost float64) {
+	// Keyed by job so a redelivered job message is only billed once
+	p.usage.Record(ctx, usage.Event{
+		ID:            "batch:" + job.ID,
+		Kind:          usage.KindBatch,
+		Model:         job.Model,
+		Version:       job.Version,
+		Requests:      int64(requests),
+		Errors:        int64(errorCount),
+		LatencyMs:     latencyMs,
+		EstimatedCost: pricing.Round(cost),
+	})
+}
+
+// emitFinished announces that a job completed or failed
+func (p *Pool) emitFinished(ctx context.Context, job *storage.BatchJob, status storage.JobStatus, errorCount int, resultURL, errorMsg string) {
+	event := events.Event{
+		// Keyed by job so a redelivered job message is only announced once
+		ID:       "job:" + job.ID,
+		Type:     events.JobCompleted,
+		Severity: events.SeverityInfo,
+		Tenant:   job.Tenant,
+		Subject:  job.ID,
+		Attributes: map[string]string{
+			"model":       job.Model,
+			"version":     job.Version,
+			"total_items": strconv.Itoa(job.TotalItems),
+			"errors":      strconv.Itoa(errorCount),
+		},
+	}
+	if status == storage.StatusFailed {
+		event.Type = events.JobFailed
+		event.Severity = events.SeverityWarning
+	}
+	if resultURL != "" {
+		event.Attributes["result_url"] = resultURL
+	}
+	if errorMsg != "" {
+		event.Attributes["error"] = errorMsg
+	}
+	p.events.Emit(ctx, event)
+	p.webhooks.Notify(ctx, event)
+}
+
+// callBack posts an async inference's result to its callback URL. Jobs
+// without a callback, or whose callbacks cannot be signed, are left to be
+// polled.
+func (p *Pool) callBack(ctx context.Context, job *storage.BatchJob, status storage.JobStatus, results []map[string]interface{}, errorMsg string) {
+	if job.CallbackURL == "" {
+		return
+	}
+	logger := logging.With(ctx, p.logger)
+	if p.callbacks == nil {
+		logger.Warn("not posting callback: callbacks are not configured")
+		return
+	}
+
+	payload := callback.Payload{
+		JobID:       job.ID,
+		Status:      string(status),
+		Model:       job.Model,
+		Version:     job.Version,
+		Error:       errorMsg,
+		CompletedAt: time.Now().UTC(),
+	}
+	// Async inferences carry a single input
+	if len(results) == 1 {
+		payload.Result = results[0]
+	}
+	if err := p.callbacks.Deliver(ctx, job.CallbackURL, payload); err != nil {
+		logger.Error("failed to post callback", zap.Error(err))
+	}
+}
+
 // worker processes individual inference requests
 func (p *Pool) worker(
 	ctx context.Context,
 	wg *sync.WaitGroup,
 	job *storage.BatchJob,
+	stop <-chan struct{},
 	inputChan <-chan struct {
 		index int
 		input map[string]interface{}
@@ -215,6 +482,10 @@ func (p *Pool) worker(
 			if !ok {
 				return
 			}
+			// Inputs still queued when the job is cancelled are not sent
+			if stopped(stop) {
+				return
+			}
 
 			// Process inference
 			result := p.processInference(ctx, job.Model, job.Version, work.input)
@@ -247,6 +518,7 @@ func (p *Pool) processInference(ctx context.Context, model, version string, inpu
 		return InferenceResult{
 			Input: input,
 			Error: fmt.Sprintf("failed to marshal request: %v", err),
+			Code:  apperrors.Internal,
 		}
 	}
 
@@ -255,25 +527,30 @@ func (p *Pool) processInference(ctx context.Context, model, version string, inpu
 		return InferenceResult{
 			Input: input,
 			Error: fmt.Sprintf("failed to create request: %v", err),
+			Code:  apperrors.Internal,
 		}
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	logging.Inject(ctx, httpReq)
 
-	resp, err := p.httpClient.Do(httpReq)
+	resp, err := logging.ClientFor(ctx, p.httpClient).Do(httpReq)
 	if err != nil {
 		return InferenceResult{
 			Input:   input,
 			Error:   fmt.Sprintf("request failed: %v", err),
+			Code:    apperrors.FromTransportError(err, "inference-orchestrator").Code,
 			Latency: time.Since(start).Milliseconds(),
 		}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		orchestratorErr := apperrors.FromHTTPResponse(resp, "inference-orchestrator")
 		return InferenceResult{
 			Input:   input,
-			Error:   fmt.Sprintf("inference failed with status %d", resp.StatusCode),
+			Error:   fmt.Sprintf("inference failed with status %d: %s", resp.StatusCode, orchestratorErr.Message),
+			Code:    orchestratorErr.Code,
 			Latency: time.Since(start).Milliseconds(),
 		}
 	}
@@ -283,14 +560,17 @@ func (p *Pool) processInference(ctx context.Context, model, version string, inpu
 		return InferenceResult{
 			Input:   input,
 			Error:   fmt.Sprintf("failed to decode response: %v", err),
+			Code:    apperrors.Internal,
 			Latency: time.Since(start).Milliseconds(),
 		}
 	}
 
+	latency := time.Since(start).Milliseconds()
 	return InferenceResult{
-		Input:      input,
-		Prediction: prediction,
-		Latency:    time.Since(start).Milliseconds(),
+		Input:         input,
+		Prediction:    prediction,
+		Latency:       latency,
+		EstimatedCost: p.pricing.Estimate(model, latency, int64(len(reqBody))),
 	}
 }
 
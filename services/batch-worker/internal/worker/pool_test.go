@@ -2,13 +2,23 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/ai-platform/batch-worker/internal/callback"
 	"github.com/yourusername/ai-platform/batch-worker/internal/storage"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/pricing"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+	"github.com/yourusername/ai-platform/pkg/usage"
+	"github.com/yourusername/ai-platform/pkg/webhooks"
 	"go.uber.org/zap"
 )
 
@@ -208,6 +218,7 @@ func TestPool_ProcessInference_Timeout(t *testing.T) {
 
 	assert.NotEmpty(t, result.Error)
 	assert.Contains(t, result.Error, "request failed")
+	assert.Equal(t, apperrors.DeadlineExceeded, result.Code)
 }
 
 func TestPool_ProcessInference_InvalidResponse(t *testing.T) {
@@ -228,3 +239,351 @@ func TestPool_ProcessInference_InvalidResponse(t *testing.T) {
 	assert.NotEmpty(t, result.Error)
 	assert.Contains(t, result.Error, "failed to decode response")
 }
+
+func TestPool_ProcessJob_PropagatesCorrelationHeaders(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	pgStore := NewMockPostgresStore()
+	minioStore := NewMockMinIOStore()
+
+	var jobHeader, requestHeader, priorityHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobHeader = r.Header.Get(logging.HeaderJobID)
+		requestHeader = r.Header.Get(logging.HeaderRequestID)
+		priorityHeader = r.Header.Get(logging.HeaderPriority)
+		w.Write([]byte(`{"prediction": [0.1, 0.9]}`))
+	}))
+	defer server.Close()
+
+	pool := NewPool(1, server.URL, pgStore, minioStore, logger)
+
+	job := &storage.BatchJob{
+		ID:         "test-job-headers",
+		Model:      "resnet18",
+		Version:    "v1",
+		Inputs:     []map[string]interface{}{{"data": []float64{1.0}}},
+		Status:     storage.StatusPending,
+		TotalItems: 1,
+	}
+
+	ctx := logging.WithPriority(logging.WithRequestID(context.Background(), "req-1"), "high")
+	assert.NoError(t, pool.ProcessJob(ctx, job))
+
+	assert.Equal(t, "test-job-headers", jobHeader)
+	assert.Equal(t, "req-1", requestHeader)
+	assert.Equal(t, "low", priorityHeader, "batch inferences are bulk traffic")
+}
+
+func TestPool_ProcessJob_RecordsUsage(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	pgStore := NewMockPostgresStore()
+	minioStore := NewMockMinIOStore()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req InferenceRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Input["fail"] == true {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"prediction": [0.1, 0.9]}`))
+	}))
+	defer server.Close()
+
+	var events []usage.Event
+	recorder := usage.NewRecorder("batch-worker", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event usage.Event
+		json.Unmarshal(value, &event)
+		events = append(events, event)
+		return nil
+	}), 10, logger)
+
+	table, err := pricing.Parse(`{"resnet18":{"per_request":0.001}}`)
+	assert.NoError(t, err)
+
+	pool := NewPool(2, server.URL, pgStore, minioStore, logger)
+	pool.SetUsageRecorder(recorder)
+	pool.SetPricing(table)
+
+	job := &storage.BatchJob{
+		ID:         "test-job-usage",
+		Model:      "resnet18",
+		Version:    "v1",
+		Inputs:     []map[string]interface{}{{"data": []float64{1.0}}, {"fail": true}, {"data": []float64{2.0}}},
+		Status:     storage.StatusPending,
+		TotalItems: 3,
+	}
+
+	ctx := logging.WithTenant(context.Background(), "acme")
+	assert.NoError(t, pool.ProcessJob(ctx, job))
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(runCtx)
+
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "batch:test-job-usage", events[0].ID)
+		assert.Equal(t, "acme", events[0].Tenant)
+		assert.Equal(t, usage.KindBatch, events[0].Kind)
+		assert.Equal(t, int64(3), events[0].Requests)
+		assert.Equal(t, int64(1), events[0].Errors)
+		assert.Equal(t, 0.002, events[0].EstimatedCost, "failed inferences cost nothing")
+	}
+
+	results := minioStore.uploadedResults["test-job-usage"]
+	if assert.Len(t, results, 3) {
+		assert.Equal(t, 0.001, results[0]["estimated_cost"])
+		assert.NotContains(t, results[1], "estimated_cost")
+	}
+}
+
+func TestProcessJob_EmitsFinishedEvent(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var published []events.Event
+	emitter := events.NewEmitter("batch-worker", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event events.Event
+		json.Unmarshal(value, &event)
+		published = append(published, event)
+		return nil
+	}), 10, logger)
+
+	pool := NewPool(2, server.URL, NewMockPostgresStore(), NewMockMinIOStore(), logger)
+	pool.SetEventEmitter(emitter)
+
+	job := &storage.BatchJob{
+		ID:         "test-job-events",
+		Tenant:     "acme",
+		Model:      "resnet18",
+		Version:    "v1",
+		Inputs:     []map[string]interface{}{{"data": []float64{1.0}}, {"data": []float64{2.0}}},
+		Status:     storage.StatusPending,
+		TotalItems: 2,
+	}
+	assert.NoError(t, pool.ProcessJob(context.Background(), job))
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	emitter.Run(runCtx)
+
+	if assert.Len(t, published, 1) {
+		assert.Equal(t, "job:test-job-events", published[0].ID)
+		assert.Equal(t, events.JobFailed, published[0].Type)
+		assert.Equal(t, events.SeverityWarning, published[0].Severity)
+		assert.Equal(t, "acme", published[0].Tenant)
+		assert.Equal(t, "2", published[0].Attributes["errors"])
+	}
+}
+
+func TestPool_ProcessJob_DeliversWebhook(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"prediction": map[string]interface{}{"class": "cat"}})
+	}))
+	defer server.Close()
+
+	var delivered []events.Event
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event events.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		delivered = append(delivered, event)
+	}))
+	defer receiver.Close()
+
+	dispatcher := webhooks.NewDispatcher("batch-worker", receiver.Client(), nil, 10, logger)
+	dispatcher.SetEndpoints([]webhooks.Endpoint{
+		{ID: "acme-ci", URL: receiver.URL, Secret: "s3cret", Tenant: "acme", Events: []events.Type{events.JobCompleted}},
+		{ID: "globex", URL: receiver.URL, Secret: "s3cret", Tenant: "globex"},
+	})
+
+	pool := NewPool(2, server.URL, NewMockPostgresStore(), NewMockMinIOStore(), logger)
+	pool.SetWebhooks(dispatcher)
+
+	job := &storage.BatchJob{
+		ID:         "test-job-webhook",
+		Tenant:     "acme",
+		Model:      "resnet18",
+		Version:    "v1",
+		Inputs:     []map[string]interface{}{{"data": []float64{1.0}}},
+		Status:     storage.StatusPending,
+		TotalItems: 1,
+	}
+	assert.NoError(t, pool.ProcessJob(context.Background(), job))
+
+	// Only the acme endpoint subscribes to the job
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dispatcher.Run(runCtx, 1)
+
+	if assert.Len(t, delivered, 1) {
+		assert.Equal(t, "job:test-job-webhook", delivered[0].ID)
+		assert.Equal(t, events.JobCompleted, delivered[0].Type)
+		assert.Equal(t, "resnet18", delivered[0].Attributes["model"])
+	}
+}
+
+func TestPool_ProcessJob_PostsCallback(t *testing.T) {
+	logger := zap.NewNop()
+	orchestrator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"class": "cat"})
+	}))
+	defer orchestrator.Close()
+
+	payloads := make(chan callback.Payload, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload callback.Payload
+		json.NewDecoder(r.Body).Decode(&payload)
+		payloads <- payload
+	}))
+	defer receiver.Close()
+
+	pool := NewPool(1, orchestrator.URL, NewMockPostgresStore(), NewMockMinIOStore(), logger)
+	pool.SetCallbacks(callback.NewNotifier(receiver.Client(), func() string { return "s3cret" }, logger))
+
+	job := &storage.BatchJob{
+		ID:          "test-job-async",
+		Model:       "resnet18",
+		Version:     "v1",
+		Inputs:      []map[string]interface{}{{"data": []float64{1.0}}},
+		CallbackURL: receiver.URL,
+		Status:      storage.StatusPending,
+		TotalItems:  1,
+	}
+	assert.NoError(t, pool.ProcessJob(context.Background(), job))
+
+	select {
+	case payload := <-payloads:
+		assert.Equal(t, "test-job-async", payload.JobID)
+		assert.Equal(t, string(storage.StatusCompleted), payload.Status)
+		assert.Equal(t, map[string]interface{}{"class": "cat"}, payload.Result["prediction"])
+	default:
+		t.Fatal("callback was not posted")
+	}
+}
+
+// tenantsFunc adapts a function to TenantAuthorizer
+type tenantsFunc func(ctx context.Context, id string) (*tenancy.Tenant, error)
+
+func (f tenantsFunc) Authorize(ctx context.Context, id string) (*tenancy.Tenant, error) {
+	return f(ctx, id)
+}
+
+func TestPool_ProcessJob_EnforcesTenant(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"prediction": []float64{0.9}})
+	}))
+	defer server.Close()
+
+	tenants := tenantsFunc(func(ctx context.Context, id string) (*tenancy.Tenant, error) {
+		switch id {
+		case "acme":
+			return &tenancy.Tenant{ID: id, Status: tenancy.StatusActive, Limits: tenancy.Limits{MaxBatchItems: 2}}, nil
+		case "frozen":
+			return nil, apperrors.Newf(apperrors.PermissionDenied, "tenant %s is suspended", id)
+		}
+		return nil, apperrors.New(apperrors.Unavailable, "tenant-service unavailable")
+	})
+
+	tests := []struct {
+		name       string
+		tenant     string
+		items      int
+		wantStatus storage.JobStatus
+		wantError  string
+	}{
+		{"within limit", "acme", 2, storage.StatusCompleted, ""},
+		{"over batch limit", "acme", 3, storage.StatusFailed, "the tenant limit is 2"},
+		{"suspended tenant", "frozen", 1, storage.StatusFailed, "suspended"},
+		{"tenant service down", "other", 3, storage.StatusCompleted, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgStore := NewMockPostgresStore()
+			minioStore := NewMockMinIOStore()
+			pool := NewPool(2, server.URL, pgStore, minioStore, zap.NewNop())
+			pool.SetTenants(tenants)
+
+			job := &storage.BatchJob{
+				ID:         "job-" + tt.tenant,
+				Tenant:     tt.tenant,
+				Model:      "resnet18",
+				Version:    "v1",
+				Status:     storage.StatusPending,
+				TotalItems: tt.items,
+			}
+			for i := 0; i < tt.items; i++ {
+				job.Inputs = append(job.Inputs, map[string]interface{}{"data": []float64{float64(i)}})
+			}
+			pgStore.CreateJob(context.Background(), job)
+
+			assert.NoError(t, pool.ProcessJob(context.Background(), job))
+			assert.Equal(t, tt.wantStatus, job.Status)
+			assert.Contains(t, job.ErrorMsg, tt.wantError)
+			_, ran := minioStore.uploadedResults[job.ID]
+			assert.Equal(t, tt.wantError == "", ran)
+		})
+	}
+}
+
+func TestPool_Cancel_StopsDispatching(t *testing.T) {
+	pgStore := NewMockPostgresStore()
+	minioStore := NewMockMinIOStore()
+
+	// The first inference waits until the job has been cancelled
+	started := make(chan struct{}, 5)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.Write([]byte(`{"prediction": [0.1, 0.9]}`))
+	}))
+	defer server.Close()
+
+	var events []usage.Event
+	recorder := usage.NewRecorder("batch-worker", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event usage.Event
+		json.Unmarshal(value, &event)
+		events = append(events, event)
+		return nil
+	}), 10, zap.NewNop())
+
+	pool := NewPool(1, server.URL, pgStore, minioStore, zap.NewNop())
+	pool.SetUsageRecorder(recorder)
+	assert.False(t, pool.Cancel("job-cancel"))
+
+	job := &storage.BatchJob{
+		ID:         "job-cancel",
+		Model:      "resnet18",
+		Version:    "v1",
+		Inputs:     make([]map[string]interface{}, 5),
+		Status:     storage.StatusPending,
+		TotalItems: 5,
+	}
+	pgStore.CreateJob(context.Background(), job)
+
+	done := make(chan error)
+	go func() { done <- pool.ProcessJob(context.Background(), job) }()
+
+	<-started
+	assert.True(t, pool.Cancel("job-cancel"))
+	close(release)
+
+	assert.NoError(t, <-done)
+	assert.Len(t, started, 0, "no input is sent after the job is cancelled")
+	assert.Equal(t, storage.StatusCancelled, job.Status)
+	assert.Equal(t, 1, job.Completed)
+	assert.Equal(t, "cancelled after 1/5 items", job.ErrorMsg)
+	assert.Empty(t, minioStore.uploadedResults)
+	assert.False(t, pool.Cancel("job-cancel"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, int64(1), events[0].Requests)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/ai-platform/datalake-writer/internal/config"
+	"github.com/yourusername/ai-platform/datalake-writer/internal/consumer"
+	"github.com/yourusername/ai-platform/datalake-writer/internal/lake"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/privacy"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/secrets"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// Initialize logger
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize logger: %v", err))
+	}
+	defer logger.Sync()
+
+	// Load configuration
+	cfg := config.Load()
+	logger.Info("configuration loaded",
+		zap.String("service", cfg.ServiceName),
+		zap.String("topic", cfg.InferenceLogTopic),
+		zap.String("bucket", cfg.Bucket),
+	)
+
+	// Resolve credentials from Vault or a mounted secret store when configured
+	secretProvider, err := secrets.FromEnv(logger)
+	if err != nil {
+		logger.Fatal("failed to initialize secret provider", zap.Error(err))
+	}
+	secretManager := secrets.NewManager(secretProvider, logger)
+	defer secretManager.Close()
+
+	minioKeyRefs := []string{cfg.SecretsPath + "#minio_access_key", cfg.SecretsPath + "#minio_secret_key"}
+	cfg.MinIOAccessKey = secretManager.LookupOr(context.Background(), minioKeyRefs[0], cfg.MinIOAccessKey)
+	cfg.MinIOSecretKey = secretManager.LookupOr(context.Background(), minioKeyRefs[1], cfg.MinIOSecretKey)
+	if cfg.MinIOAccessKey == "" || cfg.MinIOSecretKey == "" {
+		logger.Fatal("minio credentials are not configured; set MINIO_ACCESS_KEY/MINIO_SECRET_KEY or provide them via VAULT_ADDR")
+	}
+
+	// Initialize MinIO store
+	store, err := lake.NewMinIOStore(
+		cfg.MinIOEndpoint,
+		cfg.MinIOAccessKey,
+		cfg.MinIOSecretKey,
+		cfg.Bucket,
+		logger,
+	)
+	if err != nil {
+		logger.Fatal("failed to initialize minio store", zap.Error(err))
+	}
+	logger.Info("connected to MinIO")
+
+	// Use the new keys when the MinIO credentials rotate
+	secretManager.WatchAll(context.Background(), minioKeyRefs, 5*time.Minute, func(keys []string) {
+		if err := store.SetCredentials(keys[0], keys[1]); err != nil {
+			logger.Error("failed to update minio credentials", zap.Error(err))
+		}
+	})
+
+	// Expire lake files with the rest of the platform's inference data
+	retention, err := privacy.RetentionFromEnv()
+	if err != nil {
+		logger.Fatal("invalid retention configuration", zap.Error(err))
+	}
+	if retention > 0 {
+		days := int(retention / (24 * time.Hour))
+		if err := store.SetRetention(context.Background(), cfg.Prefix, days); err != nil {
+			logger.Fatal("failed to configure lake retention", zap.Error(err))
+		}
+	}
+
+	// Readiness requires the bucket and the brokers records are read from
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("minio", store.Ping)
+	checker.Add("kafka", health.TCPCheck(cfg.KafkaBrokers...))
+
+	// Write sampled inference records to Parquet partitions
+	kafkaConsumer, err := consumer.NewKafkaConsumer(
+		cfg.KafkaBrokers,
+		cfg.InferenceLogTopic,
+		cfg.ConsumerGroup,
+		lake.NewWriter(store, cfg.Prefix),
+		cfg.FlushSize,
+		cfg.FlushInterval,
+		logger,
+	)
+	if err != nil {
+		logger.Fatal("failed to create kafka consumer", zap.Error(err))
+	}
+
+	// Check records against their registered schemas when a registry is
+	// configured; registering the contract read also caches its schema ID
+	schemaCodec := schema.FromEnv()
+	if err := schemaCodec.Register(context.Background(), schema.InferenceLogs); err != nil {
+		logger.Warn("failed to register message schemas", zap.Error(err))
+	}
+	kafkaConsumer.SetCodec(schemaCodec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	consumerDone := make(chan struct{})
+	go func() {
+		defer close(consumerDone)
+		if err := kafkaConsumer.Start(ctx); err != nil {
+			logger.Error("kafka consumer error", zap.Error(err))
+		}
+	}()
+
+	// Serve health probes
+	mux := http.NewServeMux()
+	mux.Handle("/health", checker.LivenessHandler())
+	mux.Handle(health.LivenessPath, checker.LivenessHandler())
+	mux.Handle(health.ReadinessPath, checker.ReadinessHandler())
+	healthSrv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: mux,
+	}
+	go func() {
+		if err := healthSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("health server error", zap.Error(err))
+		}
+	}()
+
+	logger.Info("datalake writer started", zap.String("port", cfg.Port))
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("shutting down datalake writer...")
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	healthSrv.Shutdown(shutdownCtx)
+
+	// Buffered records are written before the consumer returns
+	<-consumerDone
+
+	logger.Info("datalake writer exited")
+}
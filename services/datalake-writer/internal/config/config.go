@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the data lake writer configuration
+type Config struct {
+	ServiceName       string
+	Port              string
+	KafkaBrokers      []string
+	InferenceLogTopic string
+	ConsumerGroup     string
+	MinIOEndpoint     string
+	MinIOAccessKey    string
+	MinIOSecretKey    string
+	SecretsPath       string
+	LogLevel          string
+
+	// Lake layout
+	Bucket string
+	Prefix string
+
+	// Batching; each flush writes one file per partition
+	FlushSize     int
+	FlushInterval time.Duration
+}
+
+// Load loads configuration from environment variables
+func Load() *Config {
+	return &Config{
+		ServiceName:       getEnv("SERVICE_NAME", "datalake-writer"),
+		Port:              getEnv("PORT", "8086"),
+		KafkaBrokers:      strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ","),
+		InferenceLogTopic: getEnv("INFERENCE_LOG_TOPIC", "inference-logs"),
+		ConsumerGroup:     getEnv("CONSUMER_GROUP", "datalake-writer"),
+		MinIOEndpoint:     getEnv("MINIO_ENDPOINT", "localhost:9000"),
+		MinIOAccessKey:    getEnv("MINIO_ACCESS_KEY", ""),
+		MinIOSecretKey:    getEnv("MINIO_SECRET_KEY", ""),
+		SecretsPath:       getEnv("SECRETS_PATH", "secret/data/datalake-writer"),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
+
+		Bucket: getEnv("LAKE_BUCKET", "inference-lake"),
+		Prefix: getEnv("LAKE_PREFIX", "inference-logs"),
+
+		FlushSize:     getEnvInt("LAKE_FLUSH_SIZE", 1000),
+		FlushInterval: getEnvDuration("LAKE_FLUSH_INTERVAL", time.Minute),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,228 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"go.uber.org/zap"
+)
+
+// Writer stores a batch of records as files named name
+type Writer interface {
+	Write(ctx context.Context, records []inferencelog.Record, name string) ([]string, error)
+}
+
+// KafkaConsumer writes inference records from Kafka to the data lake
+type KafkaConsumer struct {
+	consumer sarama.ConsumerGroup
+	topic    string
+	handler  *consumerGroupHandler
+	logger   *zap.Logger
+}
+
+// NewKafkaConsumer creates a consumer that writes to the lake every flushSize
+// records or flushInterval, whichever comes first
+func NewKafkaConsumer(
+	brokers []string,
+	topic string,
+	groupID string,
+	writer Writer,
+	flushSize int,
+	flushInterval time.Duration,
+	logger *zap.Logger,
+) (*KafkaConsumer, error) {
+	config := sarama.NewConfig()
+	config.Version = sarama.V3_3_0_0
+	config.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRoundRobin
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.Return.Errors = true
+
+	consumer, err := sarama.NewConsumerGroup(brokers, groupID, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	return &KafkaConsumer{
+		consumer: consumer,
+		topic:    topic,
+		handler: &consumerGroupHandler{
+			writer:        writer,
+			flushSize:     flushSize,
+			flushInterval: flushInterval,
+			logger:        logger,
+		},
+		logger: logger,
+	}, nil
+}
+
+// SetCodec checks inference records against their registered schemas
+func (c *KafkaConsumer) SetCodec(codec *schema.Codec) {
+	c.handler.codec = codec
+}
+
+// Start consumes inference records until ctx is cancelled
+func (c *KafkaConsumer) Start(ctx context.Context) error {
+	c.logger.Info("starting inference log consumer", zap.String("topic", c.topic))
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("shutting down inference log consumer")
+			return c.consumer.Close()
+		default:
+			if err := c.consumer.Consume(ctx, []string{c.topic}, c.handler); err != nil {
+				c.logger.Error("consumer error", zap.Error(err))
+				return err
+			}
+		}
+	}
+}
+
+// consumerGroupHandler implements sarama.ConsumerGroupHandler
+type consumerGroupHandler struct {
+	writer        Writer
+	flushSize     int
+	flushInterval time.Duration
+	codec         *schema.Codec
+	logger        *zap.Logger
+}
+
+// Setup is run at the beginning of a new session
+func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// Cleanup is run at the end of a session
+func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim buffers a partition's records and marks them consumed only
+// once they are written, so a crash replays rather than loses records
+func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	pending := &batch{}
+	ticker := time.NewTicker(h.flushInterval)
+	defer ticker.Stop()
+
+	mark := func(message *sarama.ConsumerMessage) {
+		session.MarkMessage(message, "")
+	}
+
+	for {
+		// Stop reading while a full batch cannot be written
+		for pending.len() >= h.flushSize && !h.flush(session.Context(), pending, mark) {
+			select {
+			case <-session.Context().Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+
+		select {
+		case <-session.Context().Done():
+			h.drain(pending, mark)
+			return nil
+		case <-ticker.C:
+			h.flush(session.Context(), pending, mark)
+		case message, ok := <-claim.Messages():
+			if !ok {
+				h.drain(pending, mark)
+				return nil
+			}
+			if message == nil {
+				continue
+			}
+			if err := h.add(session.Context(), pending, message); err != nil {
+				// End the session so the message is redelivered
+				h.logger.Error("failed to decode inference record", zap.Error(err))
+				h.drain(pending, mark)
+				return err
+			}
+		}
+	}
+}
+
+// drain writes what is buffered when the session ends; unwritten records are redelivered
+func (h *consumerGroupHandler) drain(pending *batch, mark func(*sarama.ConsumerMessage)) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	h.flush(ctx, pending, mark)
+}
+
+// add buffers a message's record. Records that break the contract are
+// discarded; other decoding errors, such as an unreachable schema registry,
+// are returned without consuming the message.
+func (h *consumerGroupHandler) add(ctx context.Context, pending *batch, message *sarama.ConsumerMessage) error {
+	var record inferencelog.Record
+	err := h.codec.Decode(ctx, schema.InferenceLogs, message.Value, &record)
+	if err != nil && !apperrors.Is(err, apperrors.InvalidArgument) {
+		return err
+	}
+
+	if pending.first == nil {
+		pending.first = message
+	}
+	pending.last = message
+	if err != nil {
+		h.logger.Error("discarding malformed inference record",
+			zap.Int32("partition", message.Partition),
+			zap.Int64("offset", message.Offset),
+			zap.Error(err),
+		)
+		return nil
+	}
+	pending.records = append(pending.records, record)
+	return nil
+}
+
+// flush writes the pending records and marks the last message consumed.
+// Files are named after the batch's first partition and offset, so a batch
+// redelivered after a crash overwrites its earlier files. It reports false
+// when the write failed and the records are kept for retry.
+func (h *consumerGroupHandler) flush(ctx context.Context, pending *batch, mark func(*sarama.ConsumerMessage)) bool {
+	if pending.last == nil {
+		return true
+	}
+
+	if len(pending.records) > 0 {
+		name := fmt.Sprintf("%d-%d", pending.first.Partition, pending.first.Offset)
+		objects, err := h.writer.Write(ctx, pending.records, name)
+		if err != nil {
+			h.logger.Error("failed to write inference records",
+				zap.Int("records", len(pending.records)),
+				zap.Error(err),
+			)
+			return false
+		}
+		h.logger.Debug("wrote inference records",
+			zap.Int("records", len(pending.records)),
+			zap.Int("files", len(objects)),
+		)
+	}
+
+	mark(pending.last)
+	pending.reset()
+	return true
+}
+
+// batch holds the records read from a partition since the last flush
+type batch struct {
+	records []inferencelog.Record
+	first   *sarama.ConsumerMessage
+	last    *sarama.ConsumerMessage
+}
+
+func (b *batch) len() int {
+	return len(b.records)
+}
+
+func (b *batch) reset() {
+	b.records = b.records[:0]
+	b.first = nil
+	b.last = nil
+}
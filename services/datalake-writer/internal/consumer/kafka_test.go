@@ -0,0 +1,74 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"go.uber.org/zap"
+)
+
+type fakeWriter struct {
+	err   error
+	names []string
+	sizes []int
+}
+
+func (w *fakeWriter) Write(ctx context.Context, records []inferencelog.Record, name string) ([]string, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	w.names = append(w.names, name)
+	w.sizes = append(w.sizes, len(records))
+	return []string{name}, nil
+}
+
+func message(t *testing.T, offset int64, record inferencelog.Record) *sarama.ConsumerMessage {
+	value, err := json.Marshal(record)
+	assert.NoError(t, err)
+	return &sarama.ConsumerMessage{Partition: 2, Offset: offset, Value: value}
+}
+
+func TestFlush_WritesBatchNamedByFirstOffset(t *testing.T) {
+	writer := &fakeWriter{}
+	h := &consumerGroupHandler{writer: writer, logger: zap.NewNop()}
+	pending := &batch{}
+
+	h.add(context.Background(), pending, message(t, 10, inferencelog.Record{ID: "a", Model: "resnet"}))
+	h.add(context.Background(), pending, &sarama.ConsumerMessage{Partition: 2, Offset: 11, Value: []byte("not json")})
+	h.add(context.Background(), pending, message(t, 12, inferencelog.Record{ID: "b", Model: "resnet"}))
+
+	var marked []int64
+	ok := h.flush(context.Background(), pending, func(m *sarama.ConsumerMessage) {
+		marked = append(marked, m.Offset)
+	})
+
+	assert.True(t, ok)
+	assert.Equal(t, []int64{12}, marked)
+	assert.Equal(t, []string{"2-10"}, writer.names)
+	assert.Equal(t, []int{2}, writer.sizes)
+	assert.Zero(t, pending.len())
+}
+
+func TestFlush_KeepsRecordsWhenWriteFails(t *testing.T) {
+	writer := &fakeWriter{err: errors.New("minio unavailable")}
+	h := &consumerGroupHandler{writer: writer, logger: zap.NewNop()}
+	pending := &batch{}
+	h.add(context.Background(), pending, message(t, 1, inferencelog.Record{ID: "a", Model: "resnet"}))
+
+	marked := false
+	ok := h.flush(context.Background(), pending, func(*sarama.ConsumerMessage) { marked = true })
+
+	assert.False(t, ok)
+	assert.False(t, marked)
+	assert.Equal(t, 1, pending.len())
+
+	writer.err = nil
+	assert.True(t, h.flush(context.Background(), pending, func(*sarama.ConsumerMessage) { marked = true }))
+	assert.True(t, marked)
+	assert.Equal(t, []string{"2-1"}, writer.names)
+}
@@ -0,0 +1,145 @@
+package lake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/yourusername/ai-platform/datalake-writer/internal/parquet"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+)
+
+// defaultTenant partitions records that carry no tenant, as metering does
+const defaultTenant = "default"
+
+// createdBy is recorded in the metadata of every file written
+const createdBy = "ai-platform datalake-writer"
+
+// columns is the schema of inference log files
+var columns = []parquet.Column{
+	{Name: "id", Type: parquet.String},
+	{Name: "timestamp", Type: parquet.Timestamp},
+	{Name: "service", Type: parquet.String},
+	{Name: "tenant", Type: parquet.String},
+	{Name: "request_id", Type: parquet.String},
+	{Name: "model", Type: parquet.String},
+	{Name: "version", Type: parquet.String},
+	{Name: "input", Type: parquet.String},
+	{Name: "output", Type: parquet.String},
+	{Name: "latency_ms", Type: parquet.Int64},
+	{Name: "error", Type: parquet.String},
+	{Name: "truncated", Type: parquet.Bool},
+	{Name: "shadow_of", Type: parquet.String},
+	{Name: "primary_output", Type: parquet.String},
+}
+
+// ObjectStore stores lake files
+type ObjectStore interface {
+	PutObject(ctx context.Context, name string, data []byte) error
+}
+
+// Writer writes inference records to Hive-style partitions of Parquet files:
+//
+//	{prefix}/tenant={tenant}/model={model}/version={version}/date={YYYY-MM-DD}/hour={HH}/{name}.parquet
+//
+// Tenant leads the path so a tenant's data can be deleted by prefix.
+type Writer struct {
+	store  ObjectStore
+	prefix string
+}
+
+// NewWriter creates a writer for objects under prefix
+func NewWriter(store ObjectStore, prefix string) *Writer {
+	return &Writer{store: store, prefix: prefix}
+}
+
+// Write groups records by partition and stores one file per partition named
+// name. Reusing a name for the same records overwrites rather than duplicates,
+// so redelivered batches are idempotent. It returns the objects written.
+func (w *Writer) Write(ctx context.Context, records []inferencelog.Record, name string) ([]string, error) {
+	partitions := make(map[string]*parquet.Writer)
+	for _, record := range records {
+		path := w.partition(record)
+		file, ok := partitions[path]
+		if !ok {
+			file = parquet.NewWriter(columns...)
+			partitions[path] = file
+		}
+
+		input, err := encodePayload(record.Input)
+		if err != nil {
+			return nil, fmt.Errorf("record %s: %w", record.ID, err)
+		}
+		output, err := encodePayload(record.Output)
+		if err != nil {
+			return nil, fmt.Errorf("record %s: %w", record.ID, err)
+		}
+		primaryOutput, err := encodePayload(record.PrimaryOutput)
+		if err != nil {
+			return nil, fmt.Errorf("record %s: %w", record.ID, err)
+		}
+		if err := file.Write(
+			record.ID, record.Timestamp.UTC(), record.Service, tenantOf(record), record.RequestID,
+			record.Model, record.Version, input, output, record.LatencyMs, record.Error, record.Truncated,
+			record.ShadowOf, primaryOutput,
+		); err != nil {
+			return nil, fmt.Errorf("record %s: %w", record.ID, err)
+		}
+	}
+
+	paths := make([]string, 0, len(partitions))
+	for path := range partitions {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	objects := make([]string, 0, len(paths))
+	for _, path := range paths {
+		data, err := partitions[path].Bytes(createdBy)
+		if err != nil {
+			return objects, fmt.Errorf("failed to encode %s: %w", path, err)
+		}
+		object := fmt.Sprintf("%s/%s.parquet", path, name)
+		if err := w.store.PutObject(ctx, object, data); err != nil {
+			return objects, fmt.Errorf("failed to store %s: %w", object, err)
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+func (w *Writer) partition(record inferencelog.Record) string {
+	ts := record.Timestamp.UTC()
+	if ts.IsZero() {
+		ts = time.Now().UTC()
+	}
+	return fmt.Sprintf("%smodel=%s/version=%s/date=%s/hour=%02d",
+		inferencelog.TenantPrefix(w.prefix, tenantOf(record)),
+		url.PathEscape(record.Model),
+		url.PathEscape(record.Version),
+		ts.Format("2006-01-02"),
+		ts.Hour(),
+	)
+}
+
+func tenantOf(record inferencelog.Record) string {
+	if record.Tenant == "" {
+		return defaultTenant
+	}
+	return record.Tenant
+}
+
+// encodePayload stores inputs and outputs as JSON strings; their shape varies by model
+func encodePayload(payload map[string]interface{}) (string, error) {
+	if payload == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,62 @@
+package lake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+)
+
+type fakeStore struct {
+	err     error
+	objects map[string][]byte
+}
+
+func (s *fakeStore) PutObject(ctx context.Context, name string, data []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.objects == nil {
+		s.objects = map[string][]byte{}
+	}
+	s.objects[name] = data
+	return nil
+}
+
+func TestWriter_Write_PartitionsRecords(t *testing.T) {
+	store := &fakeStore{}
+	w := NewWriter(store, "inference-logs")
+	ts := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	objects, err := w.Write(context.Background(), []inferencelog.Record{
+		{ID: "a", Timestamp: ts, Tenant: "acme", Model: "resnet", Version: "v1", Input: map[string]interface{}{"x": 1.0}},
+		{ID: "b", Timestamp: ts.Add(time.Minute), Tenant: "acme", Model: "resnet", Version: "v1", Error: "timeout"},
+		{ID: "c", Timestamp: ts.Add(time.Hour), Model: "bert", Version: "v2"},
+		{ID: "d", Timestamp: ts, Model: "bert", Version: "v3", ShadowOf: "v2", PrimaryOutput: map[string]interface{}{"label": "cat"}},
+	}, "0-42")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"inference-logs/tenant=acme/model=resnet/version=v1/date=2026-10-16/hour=09/0-42.parquet",
+		"inference-logs/tenant=default/model=bert/version=v2/date=2026-10-16/hour=10/0-42.parquet",
+		"inference-logs/tenant=default/model=bert/version=v3/date=2026-10-16/hour=09/0-42.parquet",
+	}, objects)
+	for _, object := range objects {
+		data := store.objects[object]
+		assert.Equal(t, "PAR1", string(data[:4]))
+		assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+	}
+}
+
+func TestWriter_Write_StoreError(t *testing.T) {
+	w := NewWriter(&fakeStore{err: errors.New("unavailable")}, "inference-logs")
+
+	_, err := w.Write(context.Background(), []inferencelog.Record{
+		{ID: "a", Timestamp: time.Now(), Model: "resnet", Version: "v1"},
+	}, "0-1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type IDs used by the Parquet metadata structs
+const (
+	typeI32    byte = 5
+	typeI64    byte = 6
+	typeBinary byte = 8
+	typeList   byte = 9
+	typeStruct byte = 12
+)
+
+// compactWriter encodes the subset of the Thrift compact protocol needed to
+// write Parquet page headers and file metadata
+type compactWriter struct {
+	buf    bytes.Buffer
+	lastID int16
+	stack  []int16
+}
+
+func (w *compactWriter) fieldHeader(typ byte, id int16) {
+	if delta := id - w.lastID; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(int64(id))
+	}
+	w.lastID = id
+}
+
+func (w *compactWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+// varint writes a zigzag-encoded integer
+func (w *compactWriter) varint(v int64) {
+	w.uvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *compactWriter) bytes(v []byte) {
+	w.uvarint(uint64(len(v)))
+	w.buf.Write(v)
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.fieldHeader(typeI32, id)
+	w.varint(int64(v))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.fieldHeader(typeI64, id)
+	w.varint(v)
+}
+
+func (w *compactWriter) string(id int16, v string) {
+	w.fieldHeader(typeBinary, id)
+	w.bytes([]byte(v))
+}
+
+// structField writes a nested struct whose fields are written by fields
+func (w *compactWriter) structField(id int16, fields func()) {
+	w.fieldHeader(typeStruct, id)
+	w.structBody(fields)
+}
+
+func (w *compactWriter) structBody(fields func()) {
+	w.stack = append(w.stack, w.lastID)
+	w.lastID = 0
+	fields()
+	w.buf.WriteByte(0) // stop
+	w.lastID = w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+}
+
+func (w *compactWriter) listHeader(id int16, elemType byte, size int) {
+	w.fieldHeader(typeList, id)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elemType)
+	w.uvarint(uint64(size))
+}
+
+func (w *compactWriter) i32List(id int16, values []int32) {
+	w.listHeader(id, typeI32, len(values))
+	for _, v := range values {
+		w.varint(int64(v))
+	}
+}
+
+func (w *compactWriter) stringList(id int16, values []string) {
+	w.listHeader(id, typeBinary, len(values))
+	for _, v := range values {
+		w.bytes([]byte(v))
+	}
+}
+
+// structList writes n structs, the i-th written by fields(i)
+func (w *compactWriter) structList(id int16, n int, fields func(i int)) {
+	w.listHeader(id, typeStruct, n)
+	for i := 0; i < n; i++ {
+		w.structBody(func() { fields(i) })
+	}
+}
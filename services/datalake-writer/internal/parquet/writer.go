@@ -0,0 +1,255 @@
+// Package parquet writes flat, single row group Parquet files. Columns are
+// required, PLAIN encoded and GZIP compressed, which every Parquet reader
+// supports; it covers what the data lake needs without a cgo or Arrow
+// dependency.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+const magic = "PAR1"
+
+// Type is the logical type of a column
+type Type int
+
+const (
+	// String is a UTF-8 BYTE_ARRAY
+	String Type = iota
+	// Int64 is an INT64
+	Int64
+	// Timestamp is an INT64 of milliseconds since the Unix epoch, UTC
+	Timestamp
+	// Bool is a BOOLEAN
+	Bool
+)
+
+// Parquet physical types, encodings and codecs from parquet.thrift
+const (
+	physicalBoolean   = 0
+	physicalInt64     = 2
+	physicalByteArray = 6
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	repetitionRequired = 0
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecGzip = 2
+
+	pageTypeData = 0
+)
+
+// Column describes one column of a file
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Writer buffers rows in memory and encodes them as one Parquet file
+type Writer struct {
+	columns []Column
+	values  []bytes.Buffer
+	bools   [][]bool
+	rows    int
+}
+
+// NewWriter creates a writer for the given columns
+func NewWriter(columns ...Column) *Writer {
+	return &Writer{
+		columns: columns,
+		values:  make([]bytes.Buffer, len(columns)),
+		bools:   make([][]bool, len(columns)),
+	}
+}
+
+// Rows returns the number of rows written
+func (w *Writer) Rows() int {
+	return w.rows
+}
+
+// Write appends a row. Values must match the column types: string, int64,
+// time.Time and bool respectively.
+func (w *Writer) Write(row ...interface{}) error {
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("row has %d values, want %d", len(row), len(w.columns))
+	}
+	for i, column := range w.columns {
+		var ok bool
+		switch column.Type {
+		case String:
+			_, ok = row[i].(string)
+		case Int64:
+			_, ok = row[i].(int64)
+		case Timestamp:
+			_, ok = row[i].(time.Time)
+		case Bool:
+			_, ok = row[i].(bool)
+		}
+		if !ok {
+			return fmt.Errorf("column %s: unexpected value of type %T", column.Name, row[i])
+		}
+	}
+
+	for i, column := range w.columns {
+		buf := &w.values[i]
+		switch column.Type {
+		case String:
+			v := row[i].(string)
+			binary.Write(buf, binary.LittleEndian, uint32(len(v)))
+			buf.WriteString(v)
+		case Int64:
+			binary.Write(buf, binary.LittleEndian, row[i].(int64))
+		case Timestamp:
+			binary.Write(buf, binary.LittleEndian, row[i].(time.Time).UnixMilli())
+		case Bool:
+			w.bools[i] = append(w.bools[i], row[i].(bool))
+		}
+	}
+	w.rows++
+	return nil
+}
+
+// Bytes encodes the rows written so far as a Parquet file
+func (w *Writer) Bytes(createdBy string) ([]byte, error) {
+	var file bytes.Buffer
+	file.WriteString(magic)
+
+	chunks := make([]chunkMeta, len(w.columns))
+	for i, column := range w.columns {
+		data := w.values[i].Bytes()
+		if column.Type == Bool {
+			data = packBools(w.bools[i])
+		}
+
+		compressed, err := gzipBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column.Name, err)
+		}
+
+		header := &compactWriter{}
+		header.structBody(func() {
+			header.i32(1, pageTypeData)
+			header.i32(2, int32(len(data)))
+			header.i32(3, int32(len(compressed)))
+			header.structField(5, func() {
+				header.i32(1, int32(w.rows))
+				header.i32(2, encodingPlain)
+				header.i32(3, encodingRLE)
+				header.i32(4, encodingRLE)
+			})
+		})
+
+		chunks[i] = chunkMeta{
+			offset:       int64(file.Len()),
+			uncompressed: int64(header.buf.Len() + len(data)),
+			compressed:   int64(header.buf.Len() + len(compressed)),
+		}
+		file.Write(header.buf.Bytes())
+		file.Write(compressed)
+	}
+
+	footer := w.footer(chunks, createdBy)
+	file.Write(footer)
+	binary.Write(&file, binary.LittleEndian, uint32(len(footer)))
+	file.WriteString(magic)
+	return file.Bytes(), nil
+}
+
+type chunkMeta struct {
+	offset       int64
+	uncompressed int64
+	compressed   int64
+}
+
+// footer encodes the FileMetaData struct
+func (w *Writer) footer(chunks []chunkMeta, createdBy string) []byte {
+	var totalSize int64
+	for _, chunk := range chunks {
+		totalSize += chunk.uncompressed
+	}
+
+	meta := &compactWriter{}
+	meta.structBody(func() {
+		meta.i32(1, 1) // version
+		meta.structList(2, len(w.columns)+1, func(i int) {
+			if i == 0 {
+				meta.string(4, "schema")
+				meta.i32(5, int32(len(w.columns)))
+				return
+			}
+			column := w.columns[i-1]
+			meta.i32(1, physicalType(column.Type))
+			meta.i32(3, repetitionRequired)
+			meta.string(4, column.Name)
+			switch column.Type {
+			case String:
+				meta.i32(6, convertedUTF8)
+			case Timestamp:
+				meta.i32(6, convertedTimestampMillis)
+			}
+		})
+		meta.i64(3, int64(w.rows))
+		meta.structList(4, 1, func(int) {
+			meta.structList(1, len(w.columns), func(i int) {
+				column, chunk := w.columns[i], chunks[i]
+				meta.i64(2, chunk.offset)
+				meta.structField(3, func() {
+					meta.i32(1, physicalType(column.Type))
+					meta.i32List(2, []int32{encodingPlain, encodingRLE})
+					meta.stringList(3, []string{column.Name})
+					meta.i32(4, codecGzip)
+					meta.i64(5, int64(w.rows))
+					meta.i64(6, chunk.uncompressed)
+					meta.i64(7, chunk.compressed)
+					meta.i64(9, chunk.offset)
+				})
+			})
+			meta.i64(2, totalSize)
+			meta.i64(3, int64(w.rows))
+		})
+		meta.string(6, createdBy)
+	})
+	return meta.buf.Bytes()
+}
+
+func physicalType(t Type) int32 {
+	switch t {
+	case Int64, Timestamp:
+		return physicalInt64
+	case Bool:
+		return physicalBoolean
+	default:
+		return physicalByteArray
+	}
+}
+
+// packBools PLAIN-encodes booleans as bits, least significant first
+func packBools(values []bool) []byte {
+	packed := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return packed
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
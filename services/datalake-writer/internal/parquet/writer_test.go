@@ -0,0 +1,126 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compactReader decodes Thrift compact structs into maps of field ID to value
+type compactReader struct {
+	r *bytes.Reader
+}
+
+func (c *compactReader) uvarint() uint64 {
+	v, _ := binary.ReadUvarint(c.r)
+	return v
+}
+
+func (c *compactReader) varint() int64 {
+	v := c.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (c *compactReader) value(typ byte) interface{} {
+	switch typ {
+	case typeI32, typeI64:
+		return c.varint()
+	case typeBinary:
+		b := make([]byte, c.uvarint())
+		io.ReadFull(c.r, b)
+		return string(b)
+	case typeList:
+		header, _ := c.r.ReadByte()
+		size, elemType := int(header>>4), header&0x0f
+		if size == 15 {
+			size = int(c.uvarint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = c.value(elemType)
+		}
+		return list
+	case typeStruct:
+		return c.readStruct()
+	}
+	panic("unexpected thrift type")
+}
+
+func (c *compactReader) readStruct() map[int16]interface{} {
+	fields := map[int16]interface{}{}
+	var lastID int16
+	for {
+		header, _ := c.r.ReadByte()
+		if header == 0 {
+			return fields
+		}
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(c.varint())
+		}
+		fields[id] = c.value(header & 0x0f)
+		lastID = id
+	}
+}
+
+func TestWriter_Bytes(t *testing.T) {
+	w := NewWriter(
+		Column{Name: "id", Type: String},
+		Column{Name: "timestamp", Type: Timestamp},
+		Column{Name: "latency_ms", Type: Int64},
+		Column{Name: "truncated", Type: Bool},
+	)
+	ts := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, w.Write("a", ts, int64(12), false))
+	require.NoError(t, w.Write("bc", ts, int64(30), true))
+	assert.Error(t, w.Write("c", ts, 12, false), "int is not int64")
+	assert.Equal(t, 2, w.Rows())
+
+	data, err := w.Bytes("test")
+	require.NoError(t, err)
+
+	require.Equal(t, magic, string(data[:4]))
+	require.Equal(t, magic, string(data[len(data)-4:]))
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := data[len(data)-8-footerLen : len(data)-8]
+
+	meta := (&compactReader{r: bytes.NewReader(footer)}).readStruct()
+	assert.Equal(t, int64(1), meta[1])
+	assert.Equal(t, int64(2), meta[3])
+	assert.Equal(t, "test", meta[6])
+
+	schema := meta[2].([]interface{})
+	require.Len(t, schema, 5)
+	assert.Equal(t, int64(4), schema[0].(map[int16]interface{})[5])
+	assert.Equal(t, "timestamp", schema[2].(map[int16]interface{})[4])
+	assert.Equal(t, int64(convertedTimestampMillis), schema[2].(map[int16]interface{})[6])
+
+	rowGroup := meta[4].([]interface{})[0].(map[int16]interface{})
+	columns := rowGroup[1].([]interface{})
+	require.Len(t, columns, 4)
+
+	// Read back the string column's page
+	chunk := columns[0].(map[int16]interface{})[3].(map[int16]interface{})
+	offset := chunk[9].(int64)
+	page := bytes.NewReader(data[offset:])
+	header := (&compactReader{r: page}).readStruct()
+	assert.Equal(t, int64(2), header[5].(map[int16]interface{})[1])
+
+	compressed := make([]byte, header[3].(int64))
+	io.ReadFull(page, compressed)
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	values, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 0, 0, 0, 'a', 2, 0, 0, 0, 'b', 'c'}, values)
+	assert.Equal(t, header[2].(int64), int64(len(values)))
+
+	// Booleans are bit-packed
+	assert.Equal(t, []byte{0b10}, packBools([]bool{false, true}))
+}
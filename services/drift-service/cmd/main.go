@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/drift-service/internal/baseline"
+	"github.com/yourusername/ai-platform/drift-service/internal/config"
+	"github.com/yourusername/ai-platform/drift-service/internal/consumer"
+	"github.com/yourusername/ai-platform/drift-service/internal/detector"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/transport"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// Initialize logger
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize logger: %v", err))
+	}
+	defer logger.Sync()
+
+	// Load configuration
+	cfg := config.Load()
+	logger.Info("configuration loaded",
+		zap.String("service", cfg.ServiceName),
+		zap.String("port", cfg.Port),
+		zap.Duration("window", cfg.Window),
+		zap.Float64("threshold", cfg.Threshold),
+	)
+
+	// Load the SPIFFE workload identity for mTLS between services
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	metadataClient := &http.Client{Timeout: 5 * time.Second}
+	healthClient := &http.Client{Timeout: health.DefaultTimeout}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+		metadataClient = identity.HTTPClient("metadata-service", 5*time.Second)
+		healthClient = identity.HTTPClient("metadata-service", health.DefaultTimeout)
+	}
+
+	// Publish drift events for alerting
+	producer, err := events.NewKafkaProducer(cfg.KafkaBrokers)
+	if err != nil {
+		logger.Fatal("failed to initialize kafka producer", zap.Error(err))
+	}
+	defer producer.Close()
+
+	// Check records and frame drift events with their registered schemas when
+	// a registry is configured
+	schemaCodec := schema.FromEnv()
+	if err := schemaCodec.Register(context.Background(), schema.InferenceLogs, schema.DriftEvents); apperrors.Is(err, apperrors.FailedPrecondition) {
+		logger.Fatal("message schema is incompatible with the registry", zap.Error(err))
+	} else if err != nil {
+		logger.Warn("failed to register message schemas", zap.Error(err))
+	}
+
+	driftDetector := detector.NewDetector(
+		baseline.NewClient(cfg.MetadataServiceURL, metadataClient, cfg.BaselineCacheTTL),
+		publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+			value, err := schemaCodec.Frame(ctx, schema.DriftEvents, value)
+			if err != nil {
+				return err
+			}
+			_, _, err = producer.SendMessage(&sarama.ProducerMessage{
+				Topic: cfg.DriftTopic,
+				Key:   sarama.StringEncoder(key),
+				Value: sarama.ByteEncoder(value),
+			})
+			return err
+		}),
+		cfg.Threshold,
+		cfg.MinSamples,
+		logger,
+	)
+
+	// Readiness requires the brokers and the registry holding baselines
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("kafka", health.TCPCheck(cfg.KafkaBrokers...))
+	checker.Add("metadata-service", health.HTTPCheck(healthClient, cfg.MetadataServiceURL+health.LivenessPath))
+
+	kafkaConsumer, err := consumer.NewKafkaConsumer(
+		cfg.KafkaBrokers,
+		cfg.InferenceLogTopic,
+		cfg.ConsumerGroup,
+		driftDetector,
+		logger,
+	)
+	if err != nil {
+		logger.Fatal("failed to create kafka consumer", zap.Error(err))
+	}
+	kafkaConsumer.SetCodec(schemaCodec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		if err := kafkaConsumer.Start(ctx); err != nil {
+			logger.Error("kafka consumer error", zap.Error(err))
+		}
+	}()
+	go driftDetector.Run(ctx, cfg.Window)
+
+	// Setup router
+	if cfg.LogLevel == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Health checks
+	router.GET("/health", gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.LivenessPath, gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.ReadinessPath, gin.WrapH(checker.ReadinessHandler()))
+
+	// Latest drift report per model version
+	router.GET("/v1/drift", func(c *gin.Context) {
+		reports := driftDetector.Reports(c.Query("model"))
+		c.JSON(http.StatusOK, gin.H{"reports": reports, "count": len(reports)})
+	})
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      logging.Middleware(router),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	go func() {
+		logger.Info("starting drift service", zap.String("port", cfg.Port))
+		if err := transport.ListenAndServe(srv, identity); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("failed to start server", zap.Error(err))
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("shutting down server...")
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Fatal("server forced to shutdown", zap.Error(err))
+	}
+
+	logger.Info("server exited")
+}
@@ -0,0 +1,99 @@
+package baseline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/drift"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Client reads training baselines from the metadata service and caches them,
+// including the absence of one, so each record does not cost a lookup
+type Client struct {
+	baseURL string
+	client  *http.Client
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	baseline  *drift.Baseline
+	fetchedAt time.Time
+}
+
+// NewClient creates a client for the metadata service at baseURL that caches
+// baselines for ttl
+func NewClient(baseURL string, client *http.Client, ttl time.Duration) *Client {
+	return &Client{
+		baseURL: baseURL,
+		client:  client,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]entry),
+	}
+}
+
+// Baseline returns the baseline of a model version, or nil when none is
+// registered. A cached baseline is served past its TTL while the metadata
+// service is unreachable.
+func (c *Client) Baseline(ctx context.Context, model, version string) (*drift.Baseline, error) {
+	key := model + ":" + version
+
+	c.mu.Lock()
+	cached, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Sub(cached.fetchedAt) < c.ttl {
+		return cached.baseline, nil
+	}
+
+	baseline, err := c.fetch(ctx, model, version)
+	if err != nil {
+		if ok {
+			return cached.baseline, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = entry{baseline: baseline, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return baseline, nil
+}
+
+func (c *Client) fetch(ctx context.Context, model, version string) (*drift.Baseline, error) {
+	endpoint := fmt.Sprintf("%s/v1/models/by-name/%s/%s/baseline", c.baseURL, url.PathEscape(model), url.PathEscape(version))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	logging.Inject(ctx, req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, "metadata-service")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, apperrors.FromHTTPResponse(resp, "metadata-service")
+	}
+
+	var baseline drift.Baseline
+	if err := json.NewDecoder(resp.Body).Decode(&baseline); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata-service response: %w", err)
+	}
+	return &baseline, nil
+}
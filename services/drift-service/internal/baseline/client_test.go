@@ -0,0 +1,57 @@
+package baseline
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ai-platform/pkg/drift"
+)
+
+func TestClient_Baseline_CachesResults(t *testing.T) {
+	var requests int
+	available := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case !available:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/v1/models/by-name/resnet/v1/baseline":
+			json.NewEncoder(w).Encode(drift.Baseline{Model: "resnet", Version: "v1"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	client := NewClient(server.URL, server.Client(), time.Minute)
+	client.now = func() time.Time { return now }
+
+	baseline, err := client.Baseline(context.Background(), "resnet", "v1")
+	require.NoError(t, err)
+	assert.Equal(t, "resnet", baseline.Model)
+
+	missing, err := client.Baseline(context.Background(), "bert", "v1")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	client.Baseline(context.Background(), "resnet", "v1")
+	client.Baseline(context.Background(), "bert", "v1")
+	assert.Equal(t, 2, requests)
+
+	// Stale baselines are served while the registry is unavailable
+	available = false
+	now = now.Add(2 * time.Minute)
+	baseline, err = client.Baseline(context.Background(), "resnet", "v1")
+	require.NoError(t, err)
+	assert.Equal(t, "resnet", baseline.Model)
+
+	_, err = client.Baseline(context.Background(), "gpt", "v1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the drift service configuration
+type Config struct {
+	ServiceName        string
+	Port               string
+	KafkaBrokers       []string
+	InferenceLogTopic  string
+	ConsumerGroup      string
+	DriftTopic         string
+	MetadataServiceURL string
+	SecretsPath        string
+	JaegerEndpoint     string
+	LogLevel           string
+
+	// Detection
+	Window           time.Duration
+	MinSamples       int64
+	Threshold        float64
+	BaselineCacheTTL time.Duration
+}
+
+// Load loads configuration from environment variables
+func Load() *Config {
+	return &Config{
+		ServiceName:        getEnv("SERVICE_NAME", "drift-service"),
+		Port:               getEnv("PORT", "8087"),
+		KafkaBrokers:       strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ","),
+		InferenceLogTopic:  getEnv("INFERENCE_LOG_TOPIC", "inference-logs"),
+		ConsumerGroup:      getEnv("CONSUMER_GROUP", "drift-service"),
+		DriftTopic:         getEnv("DRIFT_TOPIC", "drift-events"),
+		MetadataServiceURL: getEnv("METADATA_SERVICE_URL", "http://localhost:8083"),
+		SecretsPath:        getEnv("SECRETS_PATH", "secret/data/drift-service"),
+		JaegerEndpoint:     getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
+		LogLevel:           getEnv("LOG_LEVEL", "info"),
+
+		Window:           getEnvDuration("DRIFT_WINDOW", time.Hour),
+		MinSamples:       int64(getEnvInt("DRIFT_MIN_SAMPLES", 100)),
+		Threshold:        getEnvFloat("DRIFT_THRESHOLD", 0.2),
+		BaselineCacheTTL: getEnvDuration("BASELINE_CACHE_TTL", 5*time.Minute),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
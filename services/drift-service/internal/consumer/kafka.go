@@ -0,0 +1,127 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"go.uber.org/zap"
+)
+
+// Observer accumulates inference records
+type Observer interface {
+	Observe(ctx context.Context, record inferencelog.Record) error
+}
+
+// KafkaConsumer feeds inference records from Kafka to the drift detector
+type KafkaConsumer struct {
+	consumer sarama.ConsumerGroup
+	topic    string
+	handler  *consumerGroupHandler
+	logger   *zap.Logger
+}
+
+// NewKafkaConsumer creates a consumer for the inference log topic
+func NewKafkaConsumer(brokers []string, topic, groupID string, observer Observer, logger *zap.Logger) (*KafkaConsumer, error) {
+	config := sarama.NewConfig()
+	config.Version = sarama.V3_3_0_0
+	config.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRoundRobin
+	// Drift is measured on current traffic; a new group skips the backlog
+	config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	config.Consumer.Return.Errors = true
+
+	consumer, err := sarama.NewConsumerGroup(brokers, groupID, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	return &KafkaConsumer{
+		consumer: consumer,
+		topic:    topic,
+		handler:  &consumerGroupHandler{observer: observer, logger: logger},
+		logger:   logger,
+	}, nil
+}
+
+// SetCodec checks inference records against their registered schemas
+func (c *KafkaConsumer) SetCodec(codec *schema.Codec) {
+	c.handler.codec = codec
+}
+
+// Start consumes inference records until ctx is cancelled
+func (c *KafkaConsumer) Start(ctx context.Context) error {
+	c.logger.Info("starting inference log consumer", zap.String("topic", c.topic))
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("shutting down inference log consumer")
+			return c.consumer.Close()
+		default:
+			if err := c.consumer.Consume(ctx, []string{c.topic}, c.handler); err != nil {
+				c.logger.Error("consumer error", zap.Error(err))
+				return err
+			}
+		}
+	}
+}
+
+// consumerGroupHandler implements sarama.ConsumerGroupHandler
+type consumerGroupHandler struct {
+	observer Observer
+	codec    *schema.Codec
+	logger   *zap.Logger
+}
+
+// Setup is run at the beginning of a new session
+func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// Cleanup is run at the end of a session
+func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim observes each record and marks it consumed. Window counts live
+// in memory, so records that fail to be observed are not retried: drift is a
+// statistical signal and tolerates the loss.
+func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case <-session.Context().Done():
+			return nil
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if message == nil {
+				continue
+			}
+			h.handle(session.Context(), message)
+			session.MarkMessage(message, "")
+		}
+	}
+}
+
+func (h *consumerGroupHandler) handle(ctx context.Context, message *sarama.ConsumerMessage) {
+	var record inferencelog.Record
+	if err := h.codec.Decode(ctx, schema.InferenceLogs, message.Value, &record); err != nil {
+		h.logger.Error("discarding malformed inference record",
+			zap.Int32("partition", message.Partition),
+			zap.Int64("offset", message.Offset),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if err := h.observer.Observe(ctx, record); err != nil {
+		h.logger.Warn("failed to observe inference record",
+			zap.String("model", record.Model),
+			zap.String("version", record.Version),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,274 @@
+package detector
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/drift"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"go.uber.org/zap"
+)
+
+// BaselineSource returns the baseline of a model version, or nil when none is registered
+type BaselineSource interface {
+	Baseline(ctx context.Context, model, version string) (*drift.Baseline, error)
+}
+
+// Report is the latest comparison of a model version against its baseline
+type Report struct {
+	Model       string         `json:"model"`
+	Version     string         `json:"version"`
+	WindowStart time.Time      `json:"window_start"`
+	WindowEnd   time.Time      `json:"window_end"`
+	Samples     int64          `json:"samples"`
+	Drifted     bool           `json:"drifted"`
+	Results     []drift.Result `json:"results"`
+}
+
+// Detector bins the inputs and outputs of sampled inferences per model
+// version and, at the end of each window, compares them with the model's
+// training baseline using the population stability index
+type Detector struct {
+	baselines  BaselineSource
+	publisher  publish.Publisher
+	threshold  float64
+	minSamples int64
+	logger     *zap.Logger
+
+	mu      sync.Mutex
+	windows map[string]*window
+	reports map[string]*Report
+}
+
+// window accumulates bin counts for one model version
+type window struct {
+	model       string
+	version     string
+	baseline    *drift.Baseline
+	start       time.Time
+	samples     int64
+	features    [][]int64
+	predictions [][]int64
+}
+
+// NewDetector creates a detector that raises events, published keyed by
+// model, when the stability index of a distribution exceeds threshold.
+// Windows with fewer than minSamples records are carried over rather than
+// compared.
+func NewDetector(baselines BaselineSource, publisher publish.Publisher, threshold float64, minSamples int64, logger *zap.Logger) *Detector {
+	return &Detector{
+		baselines:  baselines,
+		publisher:  publisher,
+		threshold:  threshold,
+		minSamples: minSamples,
+		logger:     logger,
+		windows:    make(map[string]*window),
+		reports:    make(map[string]*Report),
+	}
+}
+
+// Observe adds a record to its model version's window. Failed and truncated
+// records carry no outputs or inputs to compare and are skipped, as are
+// models without a baseline.
+func (d *Detector) Observe(ctx context.Context, record inferencelog.Record) error {
+	if record.Error != "" || record.Truncated {
+		return nil
+	}
+
+	baseline, err := d.baselines.Baseline(ctx, record.Model, record.Version)
+	if err != nil || baseline == nil {
+		return err
+	}
+
+	key := record.Model + ":" + record.Version
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	w := d.windows[key]
+	if w == nil || !w.baseline.UpdatedAt.Equal(baseline.UpdatedAt) {
+		// A new or replaced baseline starts a new window
+		w = newWindow(record.Model, record.Version, baseline, record.Timestamp)
+		d.windows[key] = w
+	}
+
+	w.samples++
+	for i, dist := range baseline.Features {
+		observe(dist, w.features[i], record.Input[dist.Name])
+	}
+	for i, dist := range baseline.Predictions {
+		observe(dist, w.predictions[i], record.Output[dist.Name])
+	}
+	return nil
+}
+
+func newWindow(model, version string, baseline *drift.Baseline, start time.Time) *window {
+	w := &window{
+		model:       model,
+		version:     version,
+		baseline:    baseline,
+		start:       start,
+		features:    make([][]int64, len(baseline.Features)),
+		predictions: make([][]int64, len(baseline.Predictions)),
+	}
+	if w.start.IsZero() {
+		w.start = time.Now().UTC()
+	}
+	for i, dist := range baseline.Features {
+		w.features[i] = make([]int64, dist.Bins())
+	}
+	for i, dist := range baseline.Predictions {
+		w.predictions[i] = make([]int64, dist.Bins())
+	}
+	return w
+}
+
+// observe counts a value, or each element of a list value such as a tensor
+func observe(dist drift.Distribution, counts []int64, value interface{}) {
+	if values, ok := value.([]interface{}); ok {
+		for _, v := range values {
+			observe(dist, counts, v)
+		}
+		return
+	}
+	if bin, ok := dist.Bin(value); ok {
+		counts[bin]++
+	}
+}
+
+// Evaluate compares every window with enough samples against its baseline,
+// records the reports, starts new windows and returns the drift events raised
+func (d *Detector) Evaluate(now time.Time) []drift.Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var events []drift.Event
+	for key, w := range d.windows {
+		if w.samples < d.minSamples {
+			continue
+		}
+
+		report := &Report{
+			Model:       w.model,
+			Version:     w.version,
+			WindowStart: w.start,
+			WindowEnd:   now,
+			Samples:     w.samples,
+		}
+		report.Results = append(report.Results, d.compare(drift.KindFeature, w.baseline.Features, w.features)...)
+		report.Results = append(report.Results, d.compare(drift.KindPrediction, w.baseline.Predictions, w.predictions)...)
+
+		var drifted []drift.Result
+		for _, result := range report.Results {
+			if result.Drifted {
+				drifted = append(drifted, result)
+			}
+		}
+		if len(drifted) > 0 {
+			report.Drifted = true
+			events = append(events, drift.Event{
+				ID:          publish.NewID(),
+				Model:       w.model,
+				Version:     w.version,
+				WindowStart: w.start,
+				WindowEnd:   now,
+				Threshold:   d.threshold,
+				Drifted:     drifted,
+			})
+		}
+
+		d.reports[key] = report
+		d.windows[key] = newWindow(w.model, w.version, w.baseline, now)
+	}
+	return events
+}
+
+func (d *Detector) compare(kind string, dists []drift.Distribution, counts [][]int64) []drift.Result {
+	results := make([]drift.Result, 0, len(dists))
+	for i, dist := range dists {
+		var samples int64
+		for _, n := range counts[i] {
+			samples += n
+		}
+		if samples == 0 {
+			// The field never appeared; nothing to compare
+			continue
+		}
+		psi := drift.PSI(dist.Frequencies, counts[i])
+		results = append(results, drift.Result{
+			Kind:    kind,
+			Name:    dist.Name,
+			PSI:     psi,
+			Samples: samples,
+			Drifted: psi > d.threshold,
+		})
+	}
+	return results
+}
+
+// Reports returns the latest report of each model version, optionally
+// filtered by model, ordered by model and version
+func (d *Detector) Reports(model string) []Report {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	reports := make([]Report, 0, len(d.reports))
+	for _, report := range d.reports {
+		if model == "" || report.Model == model {
+			reports = append(reports, *report)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Model != reports[j].Model {
+			return reports[i].Model < reports[j].Model
+		}
+		return reports[i].Version < reports[j].Version
+	})
+	return reports
+}
+
+// Run evaluates windows every interval until ctx is cancelled and publishes
+// the drift events raised
+func (d *Detector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, event := range d.Evaluate(now.UTC()) {
+				d.publish(ctx, event)
+			}
+		}
+	}
+}
+
+func (d *Detector) publish(ctx context.Context, event drift.Event) {
+	names := make([]string, 0, len(event.Drifted))
+	for _, result := range event.Drifted {
+		names = append(names, result.Kind+":"+result.Name)
+	}
+	d.logger.Warn("drift detected",
+		zap.String("model", event.Model),
+		zap.String("version", event.Version),
+		zap.Strings("drifted", names),
+	)
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		d.logger.Error("failed to encode drift event", zap.Error(err))
+		return
+	}
+	if err := d.publisher.Publish(ctx, event.Model, value); err != nil {
+		d.logger.Error("failed to publish drift event",
+			zap.String("event_id", event.ID),
+			zap.String("model", event.Model),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,122 @@
+package detector
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ai-platform/pkg/drift"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"go.uber.org/zap"
+)
+
+type staticBaselines map[string]*drift.Baseline
+
+func (s staticBaselines) Baseline(ctx context.Context, model, version string) (*drift.Baseline, error) {
+	return s[model+":"+version], nil
+}
+
+func newTestDetector(published *[]drift.Event) *Detector {
+	baselines := staticBaselines{
+		"resnet:v1": {
+			Model:   "resnet",
+			Version: "v1",
+			Features: []drift.Distribution{
+				{Name: "pixels", Edges: []float64{0.5}, Frequencies: []float64{0.5, 0.5}},
+			},
+			Predictions: []drift.Distribution{
+				{Name: "label", Categories: []string{"cat", "dog"}, Frequencies: []float64{0.5, 0.5, 0}},
+			},
+		},
+	}
+	publisher := publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event drift.Event
+		if err := json.Unmarshal(value, &event); err != nil {
+			return err
+		}
+		*published = append(*published, event)
+		return nil
+	})
+	return NewDetector(baselines, publisher, drift.DefaultThreshold, 10, zap.NewNop())
+}
+
+func record(pixels []interface{}, label string) inferencelog.Record {
+	return inferencelog.Record{
+		Model:     "resnet",
+		Version:   "v1",
+		Timestamp: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		Input:     map[string]interface{}{"pixels": pixels},
+		Output:    map[string]interface{}{"label": label},
+	}
+}
+
+func TestDetector_StableDistribution(t *testing.T) {
+	var published []drift.Event
+	d := newTestDetector(&published)
+
+	for i := 0; i < 10; i++ {
+		label := "cat"
+		if i%2 == 1 {
+			label = "dog"
+		}
+		require.NoError(t, d.Observe(context.Background(), record([]interface{}{0.2, 0.8}, label)))
+	}
+
+	events := d.Evaluate(time.Now())
+	assert.Empty(t, events)
+
+	reports := d.Reports("")
+	require.Len(t, reports, 1)
+	assert.False(t, reports[0].Drifted)
+	assert.Equal(t, int64(10), reports[0].Samples)
+	require.Len(t, reports[0].Results, 2)
+	assert.Equal(t, int64(20), reports[0].Results[0].Samples, "each tensor element is counted")
+}
+
+func TestDetector_PredictionDrift(t *testing.T) {
+	var published []drift.Event
+	d := newTestDetector(&published)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, d.Observe(context.Background(), record([]interface{}{0.2, 0.8}, "bird")))
+	}
+	// Failed inferences and models without baselines are ignored
+	d.Observe(context.Background(), inferencelog.Record{Model: "resnet", Version: "v1", Error: "timeout"})
+	d.Observe(context.Background(), inferencelog.Record{Model: "bert", Version: "v1"})
+
+	events := d.Evaluate(time.Now())
+	require.Len(t, events, 1)
+	assert.Equal(t, "resnet", events[0].Model)
+	require.Len(t, events[0].Drifted, 1)
+	assert.Equal(t, drift.KindPrediction, events[0].Drifted[0].Kind)
+	assert.Equal(t, "label", events[0].Drifted[0].Name)
+
+	for _, event := range events {
+		d.publish(context.Background(), event)
+	}
+	assert.Len(t, published, 1)
+
+	// The next window starts empty
+	assert.Empty(t, d.Evaluate(time.Now()))
+}
+
+func TestDetector_CarriesOverSmallWindows(t *testing.T) {
+	var published []drift.Event
+	d := newTestDetector(&published)
+
+	for i := 0; i < 5; i++ {
+		d.Observe(context.Background(), record([]interface{}{0.9}, "bird"))
+	}
+	assert.Empty(t, d.Evaluate(time.Now()))
+	assert.Empty(t, d.Reports("resnet"))
+
+	for i := 0; i < 5; i++ {
+		d.Observe(context.Background(), record([]interface{}{0.9}, "bird"))
+	}
+	assert.Len(t, d.Evaluate(time.Now()), 1)
+	assert.Len(t, d.Reports("resnet"), 1)
+}
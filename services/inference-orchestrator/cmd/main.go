@@ -9,12 +9,37 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/IBM/sarama"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/backend"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/batching"
 	"github.com/yourusername/ai-platform/inference-orchestrator/internal/config"
 	"github.com/yourusername/ai-platform/inference-orchestrator/internal/handlers"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/lease"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/metadata"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/middleware"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/observability"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/onnx"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/openai"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/scheduler"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/torchserve"
 	"github.com/yourusername/ai-platform/inference-orchestrator/internal/triton"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/tritonmetrics"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/compress"
+	"github.com/yourusername/ai-platform/pkg/faults"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/inferencecache"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/tiered"
+	"github.com/yourusername/ai-platform/pkg/transport"
+	"github.com/yourusername/ai-platform/pkg/usage"
 )
 
 func main() {
@@ -27,8 +52,43 @@ func main() {
 	cfg := config.Load()
 	logger.Info("configuration loaded", zap.String("port", cfg.Port))
 
+	// Load the SPIFFE workload identity for mTLS between services
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	metadataClient := &http.Client{Timeout: 2 * time.Second}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+		metadataClient = identity.HTTPClient("metadata-service", 2*time.Second)
+	}
+
+	// Initialize tracing; spans continue the caller's trace, and ensemble
+	// stages get spans of their own
+	shutdown, err := observability.InitTracing(cfg.ServiceName, cfg.JaegerEndpoint)
+	if err != nil {
+		logger.Fatal("failed to initialize tracing", zap.Error(err))
+	}
+	defer shutdown(context.Background())
+
 	// Initialize Triton client
 	tritonClient := triton.NewClient(logger, cfg.TritonURL)
+	if cfg.TritonGRPCURL != "" {
+		if err := tritonClient.SetGRPC(cfg.TritonGRPCURL, cfg.TritonGRPCConnections); err != nil {
+			logger.Fatal("failed to create triton gRPC client", zap.Error(err))
+		}
+		defer tritonClient.Close()
+	}
+
+	// Readiness requires Triton to report ready
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("triton", tritonClient.HealthCheck)
+
+	// Inject faults for resilience testing; a no-op unless rules are configured
+	faultInjector, err := faults.FromEnv(context.Background(), cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load fault injection rules", zap.Error(err))
+	}
 
 	// Setup router
 	if cfg.LogLevel == "production" {
@@ -36,29 +96,212 @@ func main() {
 	}
 	r := gin.New()
 	r.Use(gin.Recovery())
+	r.Use(middleware.Tracing())
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
-	})
+	r.GET("/health", gin.WrapH(checker.LivenessHandler()))
+	r.GET(health.LivenessPath, gin.WrapH(checker.LivenessHandler()))
+	r.GET(health.ReadinessPath, gin.WrapH(checker.ReadinessHandler()))
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	inferHandler := handlers.NewInferenceHandler(logger, tritonClient)
+	inferHandler.SetTimeout(cfg.InferenceTimeout)
+
+	// Shape inputs to the signatures model versions are registered with
+	models := metadata.NewClient(cfg.MetadataServiceURL, metadataClient, cfg.ModelMetadataTTL)
+	inferHandler.SetModels(models)
+
+	// Run model versions registered as TorchServe model archives on TorchServe
+	var torchServe *torchserve.Client
+	if cfg.TorchServeURL != "" {
+		torchServe = torchserve.NewClient(logger, cfg.TorchServeURL, cfg.TorchServeManagementURL)
+		inferHandler.SetBackend(backend.TorchServe, torchServe)
+		checker.AddOptional("torchserve", torchServe.HealthCheck)
+	}
+
+	// Run model versions registered to the onnx backend on an ONNX Runtime
+	// server, or in-process when none is configured
+	switch {
+	case cfg.ONNXRuntimeURL != "":
+		server := onnx.NewServer(logger, cfg.ONNXRuntimeURL)
+		inferHandler.SetBackend(backend.ONNX, server)
+		checker.AddOptional("onnx", server.HealthCheck)
+	case cfg.ONNXModelDir != "":
+		inProcess := onnx.NewRuntime(logger, cfg.ONNXModelDir, cfg.ONNXMaxModelBytes)
+		inferHandler.SetBackend(backend.ONNX, inProcess)
+		checker.AddOptional("onnx", inProcess.HealthCheck)
+	}
+
+	// Run LLMs registered to the openai backend, or as vLLM or TGI models, on
+	// an OpenAI-compatible server
+	if cfg.OpenAIURL != "" {
+		llm := openai.NewClient(logger, cfg.OpenAIURL, cfg.OpenAIAPIKey)
+		inferHandler.SetBackend(backend.OpenAI, llm)
+		checker.AddOptional("openai", llm.HealthCheck)
+	}
+
+	// Single-item requests to models that take batches are coalesced into
+	// one Triton inference per BatchWindow
+	if cfg.BatchWindow > 0 {
+		inferHandler.SetBatcher(batching.New(logger, cfg.BatchWindow, cfg.BatchMaxSize))
+	}
+
+	// Duplicate requests to deterministic models are answered from each
+	// replica's recent results and Redis; Redis is not needed to serve
+	if cfg.RedisHost != "" {
+		redisClient := config.NewRedisClient(cfg.RedisHost)
+		defer redisClient.Close()
+		results := inferencecache.New("inference:result", tiered.Redis(redisClient), inferencecache.TTLsFunc(models.CacheTTL), logger)
+		results.SetObserver(func(model, result string) {
+			observability.ResultCacheLookups.WithLabelValues(model, result).Inc()
+		})
+		inferHandler.SetResultCache(results)
+	}
+
+	// Triton's GPU utilization, queue time and batch sizes are re-exported
+	// per model version and added to the load report
+	metricsCtx, stopMetrics := context.WithCancel(context.Background())
+	defer stopMetrics()
+	if cfg.TritonMetricsURL != "" {
+		scraper := tritonmetrics.NewScraper(logger, cfg.TritonMetricsURL, cfg.TritonMetricsInterval)
+		inferHandler.SetTritonMetrics(scraper)
+		go scraper.Run(metricsCtx)
+	}
+
+	// High-priority requests are served ahead of bulk traffic once Triton
+	// has MaxInFlight inferences running
+	if cfg.MaxInFlight > 0 {
+		inferHandler.SetScheduler(scheduler.New(scheduler.Config{
+			MaxInFlight: cfg.MaxInFlight,
+			QueueSizes: map[string]int{
+				schema.PriorityHigh:   cfg.QueueSizeHigh,
+				schema.PriorityNormal: cfg.QueueSizeNormal,
+				schema.PriorityLow:    cfg.QueueSizeLow,
+			},
+			MaxWait: cfg.QueueMaxWait,
+		}))
+	}
+
+	producer, err := config.NewKafkaProducer(cfg.KafkaBrokers)
+	if err != nil {
+		logger.Fatal("failed to initialize kafka producer", zap.Error(err))
+	}
+	defer producer.Close()
+
+	// Frame messages with their registered schemas when a registry is configured
+	schemaCodec := schema.FromEnv()
+	if err := schemaCodec.Register(context.Background(), schema.UsageEvents, schema.InferenceLogs); apperrors.Is(err, apperrors.FailedPrecondition) {
+		logger.Fatal("message schema is incompatible with the registry", zap.Error(err))
+	} else if err != nil {
+		logger.Warn("failed to register message schemas", zap.Error(err))
+	}
+
+	// Meter Triton execution time per tenant and model for cost attribution
+	usageRecorder := usage.NewRecorder(cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		value, err := schemaCodec.Frame(ctx, schema.UsageEvents, value)
+		if err != nil {
+			return err
+		}
+		_, _, err = producer.SendMessage(&sarama.ProducerMessage{
+			Topic: cfg.UsageTopic,
+			Key:   sarama.StringEncoder(key),
+			Value: sarama.ByteEncoder(value),
+		})
+		return err
+	}), 10000, logger)
+	inferHandler.SetUsageRecorder(usageRecorder, cfg.NodePool)
+	usageCtx, stopUsage := context.WithCancel(context.Background())
+	usageDone := make(chan struct{})
+	go func() {
+		usageRecorder.Run(usageCtx)
+		close(usageDone)
+	}()
+
+	// Ship sampled, redacted inputs and outputs to the data lake when enabled
+	inferenceLogCfg, err := inferencelog.ConfigFromEnv()
+	if err != nil {
+		logger.Fatal("invalid inference log configuration", zap.Error(err))
+	}
+	logCtx, stopInferenceLog := context.WithCancel(context.Background())
+	inferenceLogDone := make(chan struct{})
+	if inferenceLogCfg.Enabled {
+		capture := inferencelog.NewCapture(inferenceLogCfg, cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+			value, err := schemaCodec.Frame(ctx, schema.InferenceLogs, value)
+			if err != nil {
+				return err
+			}
+			_, _, err = producer.SendMessage(&sarama.ProducerMessage{
+				Topic: inferenceLogCfg.Topic,
+				Key:   sarama.StringEncoder(key),
+				Value: sarama.ByteEncoder(value),
+			})
+			return err
+		}), 1000, logger)
+		inferHandler.SetInferenceLog(capture)
+		logger.Info("inference logging enabled",
+			zap.String("topic", inferenceLogCfg.Topic),
+			zap.Float64("sample_rate", inferenceLogCfg.SampleRate),
+		)
+
+		go func() {
+			capture.Run(logCtx)
+			close(inferenceLogDone)
+		}()
+	} else {
+		close(inferenceLogDone)
+	}
+
+	backendHandler := handlers.NewBackendHandler(logger, tritonClient, cfg.TritonURL, cfg.NodePool)
+	backendHandler.SetModels(models)
+	backendHandler.SetLoadTimeout(cfg.ModelLoadTimeout)
+	if torchServe != nil && cfg.TorchServeManagementURL != "" {
+		backendHandler.SetTorchServe(torchServe)
+	}
 	v1 := r.Group("/v1")
 	{
 		v1.POST("/infer", inferHandler.Infer)
+		v1.POST("/infer/stream", inferHandler.StreamInfer)
+		v1.GET("/load", inferHandler.Load)
+		v1.GET("/models", backendHandler.Models)
+		v1.GET("/models/:model", backendHandler.Model)
+		v1.POST("/models/:model/load", backendHandler.LoadModel)
+		v1.POST("/models/:model/unload", backendHandler.UnloadModel)
+	}
+
+	if faultInjector.Enabled() {
+		r.Any(faults.AdminPath, gin.WrapH(faultInjector.AdminHandler()))
 	}
 
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
-		Handler: r,
+		Handler: logging.Middleware(faultInjector.Middleware(compress.Middleware(r, cfg.CompressMinBytes))),
 	}
 
 	go func() {
 		logger.Info("starting inference orchestrator", zap.String("port", cfg.Port))
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := transport.ListenAndServe(srv, identity); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("failed to start server", zap.Error(err))
 		}
 	}()
 
+	// Register with the model router as a backend of the served model
+	// versions, renewing the leases for as long as the orchestrator runs
+	var heartbeat *lease.Heartbeat
+	heartbeatCtx, stopHeartbeat := context.WithCancel(context.Background())
+	defer stopHeartbeat()
+	if cfg.ModelRouterURL != "" && cfg.AdvertiseURL != "" {
+		versions, err := lease.ParseVersions(cfg.ServedModels)
+		if err != nil {
+			logger.Fatal("invalid SERVED_MODELS", zap.Error(err))
+		}
+		routerClient := &http.Client{Timeout: 5 * time.Second}
+		if identity != nil {
+			routerClient = identity.HTTPClient("model-router", 5*time.Second)
+		}
+		heartbeat = lease.NewHeartbeat(logger, routerClient, cfg.ModelRouterURL, cfg.AdvertiseURL, versions, cfg.BackendLeaseTTL)
+		heartbeat.SetClass(cfg.BackendClass)
+		go heartbeat.Run(heartbeatCtx)
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -67,9 +310,21 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// Leave the routing table before draining in-flight requests
+	if heartbeat != nil {
+		stopHeartbeat()
+		if err := heartbeat.Unregister(ctx); err != nil {
+			logger.Warn("failed to unregister from the model router", zap.Error(err))
+		}
+	}
+
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Fatal("server forced to shutdown", zap.Error(err))
 	}
+	stopInferenceLog()
+	<-inferenceLogDone
+	stopUsage()
+	<-usageDone
 
 	logger.Info("server exited")
 }
@@ -0,0 +1,70 @@
+// Package backend describes the model servers the orchestrator runs
+// inferences on and picks the one each model version is served by from how
+// the version is registered with the metadata service.
+package backend
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/metadata"
+)
+
+// Kinds of model server
+const (
+	Triton     = "triton"
+	TorchServe = "torchserve"
+	// ONNX runs ONNX models in-process or on an ONNX Runtime server. Triton
+	// runs ONNX models too, so versions only run on it when their Key
+	// metadata names it.
+	ONNX = "onnx"
+	// OpenAI runs generations on LLM servers with an OpenAI-compatible API,
+	// such as vLLM and Text Generation Inference
+	OpenAI = "openai"
+)
+
+// Key is the model metadata key naming the kind of model server a version
+// is served by, overriding the one its framework and format imply
+const Key = "backend"
+
+// Backend runs inferences on a model server
+type Backend interface {
+	// Infer runs an inference with the input's fields as the model's
+	// inputs, bounded by ctx
+	Infer(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error)
+	HealthCheck(ctx context.Context) error
+}
+
+// Streamer is a Backend that can pass on a generation's output as it is
+// produced
+type Streamer interface {
+	Backend
+	InferStream(ctx context.Context, model, version string, input map[string]interface{}, emit func(chunk map[string]interface{}) error) error
+}
+
+// Tensors reports whether a kind of model server takes inputs as KServe v2
+// tensors, which are shaped to the signature a version is registered with
+func Tensors(kind string) bool {
+	return kind == Triton || kind == ONNX
+}
+
+// Kind returns the kind of model server a model version is served by: the
+// one its Key metadata names, TorchServe for TorchServe model archives
+// (format "mar" or framework "torchserve"), OpenAI for LLMs served by vLLM
+// or TGI (framework "vllm" or "tgi"), and Triton for anything else,
+// including versions that are not registered
+func Kind(model *metadata.Model) string {
+	if model == nil {
+		return Triton
+	}
+	if kind := model.Metadata[Key]; kind != "" {
+		return strings.ToLower(kind)
+	}
+	if strings.EqualFold(model.Format, "mar") || strings.EqualFold(model.Framework, TorchServe) {
+		return TorchServe
+	}
+	if strings.EqualFold(model.Framework, "vllm") || strings.EqualFold(model.Framework, "tgi") {
+		return OpenAI
+	}
+	return Triton
+}
@@ -0,0 +1,37 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/metadata"
+)
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		name  string
+		model *metadata.Model
+		want  string
+	}{
+		{name: "unregistered", model: nil, want: Triton},
+		{name: "onnx", model: &metadata.Model{Framework: "onnx", Format: "onnx"}, want: Triton},
+		{name: "torchscript", model: &metadata.Model{Framework: "pytorch", Format: "torchscript"}, want: Triton},
+		{name: "model archive", model: &metadata.Model{Framework: "pytorch", Format: "MAR"}, want: TorchServe},
+		{name: "torchserve framework", model: &metadata.Model{Framework: "torchserve"}, want: TorchServe},
+		{name: "vllm", model: &metadata.Model{Framework: "vLLM", Format: "safetensors"}, want: OpenAI},
+		{name: "tgi", model: &metadata.Model{Framework: "tgi"}, want: OpenAI},
+		{name: "onnx override", model: &metadata.Model{Format: "onnx", Metadata: map[string]string{Key: "onnx"}}, want: ONNX},
+		{
+			name:  "metadata override",
+			model: &metadata.Model{Format: "mar", Metadata: map[string]string{Key: "Triton"}},
+			want:  Triton,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Kind(tt.model))
+		})
+	}
+}
@@ -0,0 +1,342 @@
+// Package batching coalesces concurrent single-item inferences on the same
+// model version into one batched inference. Each request waits at most a
+// window for others to join it, so a GPU serving a high-QPS small model runs
+// one batch of many items rather than many batches of one.
+package batching
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/backend"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/kserve"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/metadata"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/observability"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Key is the model metadata key giving the largest batch a model version
+// takes, as its Triton configuration's max_batch_size; versions without it
+// are not batched
+const Key = "max_batch_size"
+
+// Limit returns the largest batch a registered model version takes, or 0
+// when it is not registered to take batches
+func Limit(model *metadata.Model) int {
+	if model == nil {
+		return 0
+	}
+	limit, err := strconv.Atoi(model.Metadata[Key])
+	if err != nil || limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// Batcher coalesces single-item requests into batches of up to maxSize,
+// each sent once its first request has waited window or it is full
+type Batcher struct {
+	logger  *zap.Logger
+	window  time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	pending map[string]*batch
+}
+
+// call is a request waiting in a batch for its share of the result
+type call struct {
+	ctx   context.Context
+	input map[string]interface{}
+	done  chan outcome
+}
+
+type outcome struct {
+	result map[string]interface{}
+	err    error
+}
+
+// batch is the requests of one model version whose inputs stack together
+type batch struct {
+	key     string
+	server  backend.Backend
+	model   string
+	version string
+	limit   int
+	calls   []*call
+	timer   *time.Timer
+}
+
+// New creates a batcher
+func New(logger *zap.Logger, window time.Duration, maxSize int) *Batcher {
+	return &Batcher{
+		logger:  logger,
+		window:  window,
+		maxSize: maxSize,
+		pending: make(map[string]*batch),
+	}
+}
+
+// Infer runs input on server, batched with other requests for the same
+// model version, up to limit of them, when it is a single item: every input
+// is a tensor in the KServe v2 JSON form with a leading dimension of 1.
+// Other requests, and every request when limit is below 2, run on their
+// own. A request whose ctx ends while it waits returns at once; the batch
+// runs without it.
+func (b *Batcher) Infer(ctx context.Context, server backend.Backend, model, version string, input map[string]interface{}, limit int) (map[string]interface{}, error) {
+	if limit > b.maxSize {
+		limit = b.maxSize
+	}
+	signature, ok := itemSignature(input)
+	if !ok || limit < 2 || b.window <= 0 {
+		return server.Infer(ctx, model, version, input)
+	}
+	key := model + "\x00" + version + "\x00" + signature
+	c := &call{ctx: ctx, input: input, done: make(chan outcome, 1)}
+
+	b.mu.Lock()
+	pending := b.pending[key]
+	if pending == nil {
+		pending = &batch{key: key, server: server, model: model, version: version, limit: limit}
+		b.pending[key] = pending
+		pending.timer = time.AfterFunc(b.window, func() { b.flush(pending) })
+	}
+	pending.calls = append(pending.calls, c)
+	full := len(pending.calls) >= pending.limit
+	if full {
+		delete(b.pending, key)
+		pending.timer.Stop()
+	}
+	b.mu.Unlock()
+	if full {
+		go b.run(pending)
+	}
+
+	select {
+	case out := <-c.done:
+		return out.result, out.err
+	case <-ctx.Done():
+		return nil, apperrors.FromTransportError(ctx.Err(), "triton")
+	}
+}
+
+// flush runs a batch once its window is up, unless it filled up first
+func (b *Batcher) flush(pending *batch) {
+	b.mu.Lock()
+	if b.pending[pending.key] != pending {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, pending.key)
+	b.mu.Unlock()
+	b.run(pending)
+}
+
+// run sends a batch's requests that are still waiting as one inference and
+// hands each its share of the result
+func (b *Batcher) run(pending *batch) {
+	calls := make([]*call, 0, len(pending.calls))
+	for _, c := range pending.calls {
+		if c.ctx.Err() == nil {
+			calls = append(calls, c)
+		}
+	}
+	switch len(calls) {
+	case 0:
+		return
+	case 1:
+		result, err := pending.server.Infer(calls[0].ctx, pending.model, pending.version, calls[0].input)
+		calls[0].done <- outcome{result, err}
+		return
+	}
+
+	inputs := make([]map[string]interface{}, len(calls))
+	for i, c := range calls {
+		inputs[i] = c.input
+	}
+	ctx, cancel := batchContext(calls)
+	defer cancel()
+	observability.BatchSize.WithLabelValues(pending.model).Observe(float64(len(calls)))
+
+	result, err := pending.server.Infer(ctx, pending.model, pending.version, stack(inputs))
+	var results []map[string]interface{}
+	if err == nil {
+		if results, err = split(result, len(calls)); err != nil {
+			err = apperrors.Wrap(err, apperrors.Internal, "invalid batched response")
+		}
+	}
+	if err != nil {
+		logging.With(ctx, b.logger).Warn("batched inference failed",
+			zap.String("model", pending.model),
+			zap.Int("batch_size", len(calls)),
+			zap.Error(err),
+		)
+		for _, c := range calls {
+			c.done <- outcome{err: err}
+		}
+		return
+	}
+	for i, c := range calls {
+		// Each caller sees its own request ID where Triton echoes the batch's
+		if _, ok := results[i]["id"]; ok {
+			results[i]["id"] = logging.RequestID(c.ctx)
+		}
+		c.done <- outcome{result: results[i]}
+	}
+}
+
+// batchContext returns the context a batch runs in: the first request's,
+// so it is logged and traced as that request, but not canceled with it,
+// and bounded by the latest deadline of the requests when they all have one
+func batchContext(calls []*call) (context.Context, context.CancelFunc) {
+	ctx := context.WithoutCancel(calls[0].ctx)
+	var latest time.Time
+	for _, c := range calls {
+		deadline, ok := c.ctx.Deadline()
+		if !ok {
+			return context.WithCancel(ctx)
+		}
+		if deadline.After(latest) {
+			latest = deadline
+		}
+	}
+	return context.WithDeadline(ctx, latest)
+}
+
+// itemSignature returns what a single-item input's batch must share with
+// it, its inputs' names, datatypes and shapes past the batch dimension, and
+// false when the input is not a single item
+func itemSignature(input map[string]interface{}) (string, bool) {
+	if len(input) == 0 {
+		return "", false
+	}
+	names := make([]string, 0, len(input))
+	for name := range input {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var signature strings.Builder
+	for _, name := range names {
+		object, ok := input[name].(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		datatype, _ := object["datatype"].(string)
+		shape, ok := shapeOf(object["shape"])
+		if !ok || datatype == "" || len(shape) == 0 || shape[0] != 1 {
+			return "", false
+		}
+		data, ok := object["data"].([]interface{})
+		if !ok || int64(len(data)) != size(shape) {
+			return "", false
+		}
+		fmt.Fprintf(&signature, "%s:%s:%v;", name, datatype, shape[1:])
+	}
+	return signature.String(), true
+}
+
+// stack concatenates single-item inputs along their batch dimension
+func stack(inputs []map[string]interface{}) map[string]interface{} {
+	stacked := make(map[string]interface{}, len(inputs[0]))
+	for name, first := range inputs[0] {
+		object := first.(map[string]interface{})
+		shape, _ := shapeOf(object["shape"])
+		dims := make([]interface{}, len(shape))
+		dims[0] = float64(len(inputs))
+		for i, dim := range shape[1:] {
+			dims[i+1] = float64(dim)
+		}
+
+		var data []interface{}
+		for _, input := range inputs {
+			data = append(data, input[name].(map[string]interface{})["data"].([]interface{})...)
+		}
+		stacked[name] = map[string]interface{}{
+			"datatype": object["datatype"],
+			"shape":    dims,
+			"data":     data,
+		}
+	}
+	return stacked
+}
+
+// split divides a batched result into the results of its n items, each
+// output's batch dimension going back to 1
+func split(result map[string]interface{}, n int) ([]map[string]interface{}, error) {
+	outputs, _ := result["outputs"].([]interface{})
+	items := make([][]interface{}, n)
+	for _, output := range outputs {
+		object, ok := output.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("output %v is not a tensor", output)
+		}
+		shape, ok := shapeOf(object["shape"])
+		data, _ := object["data"].([]interface{})
+		if !ok || len(shape) == 0 || shape[0] != int64(n) || int64(len(data)) != size(shape) {
+			return nil, fmt.Errorf("output %v is not a batch of %d", object["name"], n)
+		}
+
+		per := len(data) / n
+		itemShape := append([]int64{1}, shape[1:]...)
+		for i := range items {
+			items[i] = append(items[i], map[string]interface{}{
+				"name":     object["name"],
+				"datatype": object["datatype"],
+				"shape":    itemShape,
+				"data":     data[i*per : (i+1)*per],
+			})
+		}
+	}
+
+	results := make([]map[string]interface{}, n)
+	for i, itemOutputs := range items {
+		values, err := kserve.Values(itemOutputs)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = make(map[string]interface{}, len(result))
+		for k, v := range result {
+			results[i][k] = v
+		}
+		results[i]["outputs"] = itemOutputs
+		results[i]["values"] = values
+	}
+	return results, nil
+}
+
+// shapeOf reads a shape sent as []int64 or as JSON numbers
+func shapeOf(v interface{}) ([]int64, bool) {
+	switch shape := v.(type) {
+	case []int64:
+		return shape, true
+	case []interface{}:
+		out := make([]int64, len(shape))
+		for i, dim := range shape {
+			d, ok := dim.(float64)
+			if !ok || d < 0 || d != math.Trunc(d) {
+				return nil, false
+			}
+			out[i] = int64(d)
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+func size(shape []int64) int64 {
+	n := int64(1)
+	for _, dim := range shape {
+		n *= dim
+	}
+	return n
+}
@@ -0,0 +1,139 @@
+package batching
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/metadata"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// doubler is a backend doubling its "x" input into a "y" output, recording
+// the batch size of each inference
+type doubler struct {
+	mu      sync.Mutex
+	batches []int64
+	fail    error
+}
+
+func (d *doubler) Infer(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	x := input["x"].(map[string]interface{})
+	shape := x["shape"].([]interface{})
+	d.mu.Lock()
+	d.batches = append(d.batches, int64(shape[0].(float64)))
+	d.mu.Unlock()
+	if d.fail != nil {
+		return nil, d.fail
+	}
+
+	var data []interface{}
+	for _, v := range x["data"].([]interface{}) {
+		data = append(data, v.(float64)*2)
+	}
+	return map[string]interface{}{
+		"model_name": model,
+		"outputs": []interface{}{map[string]interface{}{
+			"name": "y", "datatype": "FP32", "shape": []int64{int64(shape[0].(float64)), 2}, "data": data,
+		}},
+	}, nil
+}
+
+func (d *doubler) HealthCheck(context.Context) error { return nil }
+
+func item(a, b float64) map[string]interface{} {
+	return map[string]interface{}{"x": map[string]interface{}{
+		"datatype": "FP32", "shape": []interface{}{1.0, 2.0}, "data": []interface{}{a, b},
+	}}
+}
+
+func TestBatcher_CoalescesConcurrentItems(t *testing.T) {
+	server := &doubler{}
+	batcher := New(zap.NewNop(), time.Second, 32)
+
+	results := make([]map[string]interface{}, 4)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := batcher.Infer(context.Background(), server, "mlp", "1", item(float64(i), 1), 4)
+			require.NoError(t, err)
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+
+	// The batch ran as soon as it was full rather than after the window
+	assert.Equal(t, []int64{4}, server.batches)
+	for i, result := range results {
+		assert.Equal(t, map[string]interface{}{"y": []interface{}{[]interface{}{float64(2 * i), 2.0}}}, result["values"])
+		assert.Equal(t, []int64{1, 2}, result["outputs"].([]interface{})[0].(map[string]interface{})["shape"])
+	}
+}
+
+func TestBatcher_FlushesAfterWindow(t *testing.T) {
+	server := &doubler{}
+	batcher := New(zap.NewNop(), 10*time.Millisecond, 32)
+
+	result, err := batcher.Infer(context.Background(), server, "mlp", "1", item(1, 2), 8)
+	require.NoError(t, err)
+	// A request left alone in its batch runs as it was sent
+	assert.Equal(t, []interface{}{2.0, 4.0}, result["outputs"].([]interface{})[0].(map[string]interface{})["data"])
+	assert.Equal(t, []int64{1}, server.batches)
+}
+
+func TestBatcher_RunsOtherRequestsAlone(t *testing.T) {
+	server := &doubler{}
+	batcher := New(zap.NewNop(), time.Hour, 32)
+
+	// Neither unbatched models nor requests of several items wait
+	_, err := batcher.Infer(context.Background(), server, "mlp", "1", item(1, 2), 0)
+	require.NoError(t, err)
+	pair := map[string]interface{}{"x": map[string]interface{}{
+		"datatype": "FP32", "shape": []interface{}{2.0, 1.0}, "data": []interface{}{1.0, 2.0},
+	}}
+	_, err = batcher.Infer(context.Background(), server, "mlp", "1", pair, 8)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, server.batches)
+}
+
+func TestBatcher_SharesFailures(t *testing.T) {
+	server := &doubler{fail: apperrors.New(apperrors.ResourceExhausted, "out of memory")}
+	batcher := New(zap.NewNop(), time.Second, 2)
+
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = batcher.Infer(context.Background(), server, "mlp", "1", item(1, 2), 8)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.True(t, apperrors.Is(err, apperrors.ResourceExhausted))
+	}
+}
+
+func TestBatcher_CallerLeaves(t *testing.T) {
+	batcher := New(zap.NewNop(), time.Hour, 32)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := batcher.Infer(ctx, &doubler{}, "mlp", "1", item(1, 2), 8)
+	assert.True(t, apperrors.Is(err, apperrors.DeadlineExceeded))
+}
+
+func TestLimit(t *testing.T) {
+	assert.Equal(t, 0, Limit(nil))
+	assert.Equal(t, 0, Limit(&metadata.Model{}))
+	assert.Equal(t, 0, Limit(&metadata.Model{Metadata: map[string]string{Key: "many"}}))
+	assert.Equal(t, 16, Limit(&metadata.Model{Metadata: map[string]string{Key: "16"}}))
+}
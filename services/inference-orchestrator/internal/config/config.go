@@ -1,13 +1,104 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/redis/go-redis/v9"
+)
 
 type Config struct {
 	ServiceName    string
 	Port           string
 	LogLevel       string
 	TritonURL      string
+	KafkaBrokers   []string
+	UsageTopic     string
+	NodePool       string
 	JaegerEndpoint string
+
+	// TritonGRPCURL is Triton's gRPC API, which inferences are sent to over
+	// TritonGRPCConnections pooled connections; inferences go over HTTP to
+	// TritonURL when it is empty
+	TritonGRPCURL         string
+	TritonGRPCConnections int
+
+	// TritonMetricsURL is Triton's metrics endpoint, "host:port", scraped
+	// every TritonMetricsInterval for GPU utilization, queue time and batch
+	// sizes; it is not scraped when empty
+	TritonMetricsURL      string
+	TritonMetricsInterval time.Duration
+
+	// TorchServeURL is the inference API of the TorchServe that model
+	// versions registered as model archives run on, and
+	// TorchServeManagementURL its management API; neither is used when
+	// empty
+	TorchServeURL           string
+	TorchServeManagementURL string
+
+	// ONNXRuntimeURL is the ONNX Runtime server model versions registered
+	// to run on the onnx backend are sent to; when it is empty they run
+	// in-process from the repository at ONNXModelDir, refusing models over
+	// ONNXMaxModelBytes. Neither is used when both are empty.
+	ONNXRuntimeURL    string
+	ONNXModelDir      string
+	ONNXMaxModelBytes int64
+
+	// OpenAIURL is the OpenAI-compatible server (vLLM, TGI) LLMs registered
+	// to run on the openai backend are sent to, with OpenAIAPIKey as a
+	// bearer token when set; it is not used when empty
+	OpenAIURL    string
+	OpenAIAPIKey string
+
+	// MetadataServiceURL is where the input signatures model versions are
+	// shaped to are registered; lookups are cached for ModelMetadataTTL
+	MetadataServiceURL string
+	ModelMetadataTTL   time.Duration
+
+	// InferenceTimeout bounds inferences whose caller sent no deadline and
+	// whose version is registered without a timeout
+	InferenceTimeout time.Duration
+
+	// ModelLoadTimeout bounds how long a model loaded through the
+	// repository control API takes to load on Triton
+	ModelLoadTimeout time.Duration
+
+	// BatchWindow is how long a single-item request to a Triton model
+	// version registered with a max_batch_size waits for others to batch
+	// with, up to BatchMaxSize requests; zero batches nothing
+	BatchWindow  time.Duration
+	BatchMaxSize int
+
+	// RedisHost caches the results of model versions registered with a
+	// cache_ttl, shared by orchestrator replicas; nothing is cached when it
+	// is empty
+	RedisHost string
+
+	// Responses smaller than this are sent uncompressed
+	CompressMinBytes int
+
+	// Inferences run on Triton at once; the overflow waits in a bounded
+	// queue per priority class for at most QueueMaxWait. Zero runs every
+	// request at once.
+	MaxInFlight     int
+	QueueSizeHigh   int
+	QueueSizeNormal int
+	QueueSizeLow    int
+	QueueMaxWait    time.Duration
+
+	// ModelRouterURL is the model router the orchestrator registers with as
+	// a backend of ServedModels ("model/version" pairs) at AdvertiseURL,
+	// renewing the lease every third of BackendLeaseTTL; BackendClass is
+	// the hardware class it registers under, if any. Nothing is registered
+	// when ModelRouterURL or AdvertiseURL is empty.
+	ModelRouterURL  string
+	AdvertiseURL    string
+	ServedModels    []string
+	BackendClass    string
+	BackendLeaseTTL time.Duration
 }
 
 func Load() *Config {
@@ -15,14 +106,100 @@ func Load() *Config {
 		ServiceName:    getEnv("SERVICE_NAME", "inference-orchestrator"),
 		Port:           getEnv("PORT", "8082"),
 		LogLevel:       getEnv("LOG_LEVEL", "info"),
-		TritonURL:      getEnv("TRITON_URL", "localhost:8001"),
+		TritonURL:      getEnv("TRITON_URL", "localhost:8000"),
+		KafkaBrokers:   strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ","),
+		UsageTopic:     getEnv("USAGE_TOPIC", "usage-events"),
+		NodePool:       getEnv("NODE_POOL", "default"),
 		JaegerEndpoint: getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
+
+		TritonGRPCURL:         getEnv("TRITON_GRPC_URL", "localhost:8001"),
+		TritonGRPCConnections: getEnvInt("TRITON_GRPC_CONNECTIONS", 4),
+
+		TritonMetricsURL:      getEnv("TRITON_METRICS_URL", "localhost:8002"),
+		TritonMetricsInterval: getEnvDuration("TRITON_METRICS_INTERVAL", 15*time.Second),
+
+		TorchServeURL:           getEnv("TORCHSERVE_URL", ""),
+		TorchServeManagementURL: getEnv("TORCHSERVE_MANAGEMENT_URL", ""),
+
+		ONNXRuntimeURL:    getEnv("ONNX_RUNTIME_URL", ""),
+		ONNXModelDir:      getEnv("ONNX_MODEL_DIR", ""),
+		ONNXMaxModelBytes: int64(getEnvInt("ONNX_MAX_MODEL_BYTES", 64<<20)),
+
+		OpenAIURL:    getEnv("OPENAI_URL", ""),
+		OpenAIAPIKey: getEnv("OPENAI_API_KEY", ""),
+
+		MetadataServiceURL: getEnv("METADATA_SERVICE_URL", "http://localhost:8083"),
+		ModelMetadataTTL:   getEnvDuration("MODEL_METADATA_TTL", time.Minute),
+
+		InferenceTimeout: getEnvDuration("INFERENCE_TIMEOUT", 30*time.Second),
+		ModelLoadTimeout: getEnvDuration("MODEL_LOAD_TIMEOUT", 5*time.Minute),
+
+		BatchWindow:  getEnvDuration("BATCH_WINDOW", 0),
+		BatchMaxSize: getEnvInt("BATCH_MAX_SIZE", 32),
+
+		RedisHost: getEnv("REDIS_HOST", ""),
+
+		CompressMinBytes: getEnvInt("COMPRESS_MIN_BYTES", 1024),
+
+		MaxInFlight:     getEnvInt("MAX_IN_FLIGHT", 64),
+		QueueSizeHigh:   getEnvInt("QUEUE_SIZE_HIGH", 64),
+		QueueSizeNormal: getEnvInt("QUEUE_SIZE_NORMAL", 256),
+		QueueSizeLow:    getEnvInt("QUEUE_SIZE_LOW", 1024),
+		QueueMaxWait:    getEnvDuration("QUEUE_MAX_WAIT", 10*time.Second),
+
+		ModelRouterURL:  getEnv("MODEL_ROUTER_URL", ""),
+		AdvertiseURL:    getEnv("ADVERTISE_URL", ""),
+		ServedModels:    getEnvList("SERVED_MODELS"),
+		BackendClass:    getEnv("BACKEND_CLASS", ""),
+		BackendLeaseTTL: getEnvDuration("BACKEND_LEASE_TTL", 30*time.Second),
 	}
 }
 
+// NewRedisClient creates a new Redis client
+func NewRedisClient(addr string) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+}
+
+// NewKafkaProducer creates a producer for usage events and inference log records
+func NewKafkaProducer(brokers []string) (sarama.SyncProducer, error) {
+	config := sarama.NewConfig()
+	config.Producer.RequiredAcks = sarama.WaitForLocal
+	config.Producer.Retry.Max = 3
+	config.Producer.Return.Successes = true
+
+	return sarama.NewSyncProducer(brokers, config)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvList(key string) []string {
+	if value := os.Getenv(key); value != "" {
+		return strings.Split(value, ",")
+	}
+	return nil
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,190 @@
+// Package decode turns the binary inputs of a request, images and audio
+// clips carried as media.Blob values, into the tensors Triton expects.
+package decode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/media"
+)
+
+// MaxPixels bounds the size of decoded images, so a small compressed file
+// cannot expand into gigabytes of pixels
+const MaxPixels = 4096 * 4096
+
+// Tensor is a decoded input in Triton's KServe v2 form
+type Tensor struct {
+	Shape      []int                  `json:"shape"`
+	Datatype   string                 `json:"datatype"`
+	Data       interface{}            `json:"data"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// Inputs returns input with each blob replaced by its tensor: images become
+// UINT8 tensors of shape [height, width, 3] in RGB order, and WAV clips FP32
+// tensors of shape [frames, channels] with samples in [-1, 1] and the clip's
+// sample_rate as a parameter. Other inputs are kept as they are, and input is
+// not modified. Undecodable blobs are InvalidArgument errors.
+func Inputs(input map[string]interface{}) (map[string]interface{}, error) {
+	var decoded map[string]interface{}
+	for name, value := range input {
+		blob, ok, err := media.FromValue(value)
+		if !ok {
+			continue
+		}
+		var tensor *Tensor
+		if err == nil {
+			tensor, err = Blob(blob)
+		}
+		if err != nil {
+			return nil, apperrors.New(apperrors.InvalidArgument, "invalid input").WithDetails(fmt.Sprintf("%s: %v", name, err))
+		}
+
+		if decoded == nil {
+			decoded = make(map[string]interface{}, len(input))
+			for k, v := range input {
+				decoded[k] = v
+			}
+		}
+		decoded[name] = tensor
+	}
+	if decoded == nil {
+		return input, nil
+	}
+	return decoded, nil
+}
+
+// Blob decodes a binary input
+func Blob(blob media.Blob) (*Tensor, error) {
+	switch blob.ContentType {
+	case media.JPEG, media.PNG:
+		return Image(blob.Data)
+	case media.WAV:
+		return WAV(blob.Data)
+	}
+	return nil, fmt.Errorf("unsupported media type %q", blob.ContentType)
+}
+
+// Image decodes a JPEG or PNG image
+func Image(data []byte) (*Tensor, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, fmt.Errorf("image of %dx%d pixels is larger than %d pixels", config.Width, config.Height, MaxPixels)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	pixels := make([]int, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			pixels = append(pixels, int(c.R), int(c.G), int(c.B))
+		}
+	}
+	return &Tensor{
+		Shape:    []int{bounds.Dy(), bounds.Dx(), 3},
+		Datatype: "UINT8",
+		Data:     pixels,
+	}, nil
+}
+
+// WAV format codes
+const (
+	wavPCM        = 1
+	wavFloat      = 3
+	wavExtensible = 0xfffe
+)
+
+var errInvalidWAV = errors.New("invalid wav file")
+
+// WAV decodes an uncompressed WAV clip of 8, 16, 24 or 32-bit integer or
+// 32-bit float samples
+func WAV(data []byte) (*Tensor, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, errInvalidWAV
+	}
+
+	var format, channels, bits uint16
+	var sampleRate uint32
+	var samples []byte
+	for chunks := data[12:]; len(chunks) >= 8; {
+		id, size := string(chunks[0:4]), binary.LittleEndian.Uint32(chunks[4:8])
+		chunks = chunks[8:]
+		if uint64(size) > uint64(len(chunks)) {
+			return nil, errInvalidWAV
+		}
+		body := chunks[:size]
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, errInvalidWAV
+			}
+			format = binary.LittleEndian.Uint16(body[0:2])
+			channels = binary.LittleEndian.Uint16(body[2:4])
+			sampleRate = binary.LittleEndian.Uint32(body[4:8])
+			bits = binary.LittleEndian.Uint16(body[14:16])
+			// Extensible files name the real format in their sub-format GUID
+			if format == wavExtensible && size >= 26 {
+				format = binary.LittleEndian.Uint16(body[24:26])
+			}
+		case "data":
+			samples = body
+		}
+
+		// Chunks are padded to an even size
+		if size%2 == 1 && int(size) < len(chunks) {
+			size++
+		}
+		chunks = chunks[size:]
+	}
+	if channels == 0 || samples == nil {
+		return nil, errInvalidWAV
+	}
+
+	var sample func([]byte) float32
+	switch {
+	case format == wavPCM && bits == 8:
+		sample = func(b []byte) float32 { return (float32(b[0]) - 128) / 128 }
+	case format == wavPCM && bits == 16:
+		sample = func(b []byte) float32 { return float32(int16(binary.LittleEndian.Uint16(b))) / (1 << 15) }
+	case format == wavPCM && bits == 24:
+		sample = func(b []byte) float32 {
+			return float32(int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24)>>8) / (1 << 23)
+		}
+	case format == wavPCM && bits == 32:
+		sample = func(b []byte) float32 { return float32(int32(binary.LittleEndian.Uint32(b))) / (1 << 31) }
+	case format == wavFloat && bits == 32:
+		sample = func(b []byte) float32 { return math.Float32frombits(binary.LittleEndian.Uint32(b)) }
+	default:
+		return nil, fmt.Errorf("unsupported wav encoding: format %d with %d-bit samples", format, bits)
+	}
+
+	width := int(bits) / 8
+	frames := len(samples) / (width * int(channels))
+	values := make([]float32, 0, frames*int(channels))
+	for i := 0; i < frames*int(channels); i++ {
+		values = append(values, sample(samples[i*width:]))
+	}
+	return &Tensor{
+		Shape:      []int{frames, int(channels)},
+		Datatype:   "FP32",
+		Data:       values,
+		Parameters: map[string]interface{}{"sample_rate": sampleRate},
+	}, nil
+}
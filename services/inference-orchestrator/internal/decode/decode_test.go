@@ -0,0 +1,90 @@
+package decode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/media"
+)
+
+func encodePNG(t *testing.T) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	img.Set(1, 0, color.NRGBA{G: 10, B: 20, A: 255})
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// encodeWAV writes a 16-bit PCM clip
+func encodeWAV(channels uint16, sampleRate uint32, samples []int16) []byte {
+	var buf bytes.Buffer
+	dataSize := uint32(2 * len(samples))
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	for _, field := range []interface{}{uint32(16), uint16(wavPCM), channels, sampleRate, sampleRate * uint32(channels) * 2, channels * 2, uint16(16)} {
+		binary.Write(&buf, binary.LittleEndian, field)
+	}
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	binary.Write(&buf, binary.LittleEndian, samples)
+	return buf.Bytes()
+}
+
+func TestImage(t *testing.T) {
+	tensor, err := Image(encodePNG(t))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, tensor.Shape)
+	assert.Equal(t, "UINT8", tensor.Datatype)
+	assert.Equal(t, []int{255, 0, 0, 0, 10, 20}, tensor.Data)
+
+	_, err = Image([]byte("not an image"))
+	assert.Error(t, err)
+}
+
+func TestWAV(t *testing.T) {
+	tensor, err := WAV(encodeWAV(2, 16000, []int16{0, 16384, -32768, 32767}))
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 2}, tensor.Shape)
+	assert.Equal(t, "FP32", tensor.Datatype)
+	assert.Equal(t, uint32(16000), tensor.Parameters["sample_rate"])
+
+	values := tensor.Data.([]float32)
+	require.Len(t, values, 4)
+	assert.Equal(t, float32(0), values[0])
+	assert.Equal(t, float32(0.5), values[1])
+	assert.Equal(t, float32(-1), values[2])
+	assert.InDelta(t, 1, values[3], 0.001)
+
+	_, err = WAV([]byte("RIFF\x04\x00\x00\x00WAVE"))
+	assert.Error(t, err)
+}
+
+func TestInputs(t *testing.T) {
+	input := map[string]interface{}{
+		"image":  media.Blob{ContentType: media.PNG, Data: encodePNG(t)}.Value(),
+		"top_k":  3.0,
+		"labels": []interface{}{"cat"},
+	}
+
+	decoded, err := Inputs(input)
+	require.NoError(t, err)
+	assert.IsType(t, &Tensor{}, decoded["image"])
+	assert.Equal(t, 3.0, decoded["top_k"])
+	assert.Equal(t, []interface{}{"cat"}, decoded["labels"])
+
+	// The request's input is left as sent, for the inference log
+	assert.IsType(t, map[string]interface{}{}, input["image"])
+
+	_, err = Inputs(map[string]interface{}{"audio": media.Blob{ContentType: media.WAV, Data: []byte("garbage")}.Value()})
+	assert.Equal(t, apperrors.InvalidArgument, apperrors.CodeOf(err))
+}
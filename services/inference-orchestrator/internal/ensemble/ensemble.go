@@ -0,0 +1,391 @@
+// Package ensemble runs model versions registered as ensembles: graphs of
+// stages, each an inference on another model version, whose outputs are the
+// inputs of the stages after them, such as a detector whose detections a
+// classifier labels. An ensemble runs as one inference, each stage as soon
+// as the stages it reads from are done, with a span and a timing of its own.
+package ensemble
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/metadata"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/observability"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Key is the model metadata key holding an ensemble's Graph, as JSON
+const Key = "ensemble"
+
+// requestInputs is the stage name references to the request's inputs use
+const requestInputs = "input"
+
+// Stage is an inference on one model version of an ensemble
+type Stage struct {
+	Name  string `json:"name"`
+	Model string `json:"model"`
+	// Version defaults to "1"
+	Version string `json:"version,omitempty"`
+	// Inputs maps each input the stage's model takes to where it comes
+	// from: "input.<name>" for an input of the request and
+	// "<stage>.<output>" for an output of another stage
+	Inputs map[string]string `json:"inputs"`
+}
+
+// Graph is the stages of an ensemble and the outputs it returns
+type Graph struct {
+	Stages []Stage `json:"stages"`
+	// Outputs maps each output the ensemble returns to the stage output,
+	// "<stage>.<output>", it is. When left out the ensemble returns the
+	// result of its last stage.
+	Outputs map[string]string `json:"outputs,omitempty"`
+
+	name string
+	// after holds the indices of the stages each stage reads from
+	after [][]int
+}
+
+// reference is where a stage input or ensemble output comes from
+type reference struct {
+	stage  string
+	output string
+}
+
+func parseReference(ref string) (reference, error) {
+	stage, output, ok := strings.Cut(ref, ".")
+	if !ok || stage == "" || output == "" {
+		return reference{}, fmt.Errorf("%q is not <stage>.<output> or input.<name>", ref)
+	}
+	return reference{stage: stage, output: output}, nil
+}
+
+// FromModel returns the graph a model version is registered with, nil when
+// it is not an ensemble. Graphs that cannot be read, refer to stages that do
+// not exist or have cycles fail with FailedPrecondition.
+func FromModel(model *metadata.Model) (*Graph, error) {
+	if model == nil || model.Metadata[Key] == "" {
+		return nil, nil
+	}
+	g, err := parse(model.Metadata[Key])
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.FailedPrecondition,
+			fmt.Sprintf("model %s version %s has an invalid ensemble: %v", model.Name, model.Version, err))
+	}
+	g.name = model.Name
+	return g, nil
+}
+
+func parse(raw string) (*Graph, error) {
+	var g Graph
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&g); err != nil {
+		return nil, err
+	}
+	if len(g.Stages) == 0 {
+		return nil, fmt.Errorf("no stages")
+	}
+
+	index := make(map[string]int, len(g.Stages))
+	for i, stage := range g.Stages {
+		switch {
+		case stage.Name == "" || strings.Contains(stage.Name, "."):
+			return nil, fmt.Errorf("stage %d has an invalid name %q", i, stage.Name)
+		case stage.Name == requestInputs:
+			return nil, fmt.Errorf("stage name %q is reserved for the request's inputs", requestInputs)
+		case stage.Model == "":
+			return nil, fmt.Errorf("stage %s has no model", stage.Name)
+		}
+		if _, ok := index[stage.Name]; ok {
+			return nil, fmt.Errorf("stage %s is declared twice", stage.Name)
+		}
+		index[stage.Name] = i
+		if g.Stages[i].Version == "" {
+			g.Stages[i].Version = "1"
+		}
+	}
+
+	g.after = make([][]int, len(g.Stages))
+	for i, stage := range g.Stages {
+		for _, ref := range stage.Inputs {
+			r, err := parseReference(ref)
+			if err != nil {
+				return nil, fmt.Errorf("stage %s: %v", stage.Name, err)
+			}
+			if r.stage == requestInputs {
+				continue
+			}
+			j, ok := index[r.stage]
+			if !ok {
+				return nil, fmt.Errorf("stage %s reads from unknown stage %s", stage.Name, r.stage)
+			}
+			g.after[i] = append(g.after[i], j)
+		}
+	}
+	for name, ref := range g.Outputs {
+		r, err := parseReference(ref)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %v", name, err)
+		}
+		if _, ok := index[r.stage]; !ok {
+			return nil, fmt.Errorf("output %s comes from unknown stage %s", name, r.stage)
+		}
+	}
+	if err := g.checkAcyclic(); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// checkAcyclic fails when stages read from each other in a cycle, which
+// would never run
+func (g *Graph) checkAcyclic() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(g.Stages))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("stage %s depends on itself", g.Stages[i].Name)
+		case visited:
+			return nil
+		}
+		state[i] = visiting
+		for _, j := range g.after[i] {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		return nil
+	}
+	for i := range g.Stages {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Runner runs an inference on a stage's model version
+type Runner func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error)
+
+// Execute runs the ensemble on a request's input, each stage on run once
+// the stages it reads from are done, stages that do not read from each
+// other at the same time. The result holds the ensemble's outputs as
+// "values", or is its last stage's, and the timing of each stage as
+// "stages". The first stage to fail cancels the others and fails the
+// ensemble with its error.
+func (g *Graph) Execute(ctx context.Context, input map[string]interface{}, run Runner) (map[string]interface{}, error) {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once    sync.Once
+		failure error
+		wg      sync.WaitGroup
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			failure = err
+			cancel()
+		})
+	}
+	start := time.Now()
+	results := make([]map[string]interface{}, len(g.Stages))
+	timings := make([]interface{}, len(g.Stages))
+	done := make([]chan struct{}, len(g.Stages))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	for i := range g.Stages {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer close(done[i])
+			for _, j := range g.after[i] {
+				select {
+				case <-done[j]:
+				case <-ctx.Done():
+					return
+				}
+			}
+			// A stage read from failed
+			if ctx.Err() != nil {
+				return
+			}
+
+			stage := g.Stages[i]
+			stageInput, err := g.inputOf(stage, input, results)
+			if err != nil {
+				fail(err)
+				return
+			}
+			began := time.Now()
+			result, err := g.runStage(ctx, stage, stageInput, run)
+			elapsed := time.Since(began)
+			observability.StageDuration.WithLabelValues(g.name, stage.Name).Observe(elapsed.Seconds())
+			if err != nil {
+				fail(stageError(stage.Name, err))
+				return
+			}
+			results[i] = result
+			timings[i] = map[string]interface{}{
+				"name":       stage.Name,
+				"model":      stage.Model,
+				"version":    stage.Version,
+				"started_ms": began.Sub(start).Milliseconds(),
+				"latency_ms": elapsed.Milliseconds(),
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if failure == nil && parent.Err() != nil {
+		failure = apperrors.FromTransportError(parent.Err(), "ensemble")
+	}
+	if failure != nil {
+		return nil, failure
+	}
+
+	out := make(map[string]interface{})
+	if len(g.Outputs) == 0 {
+		for k, v := range results[len(results)-1] {
+			out[k] = v
+		}
+	} else {
+		values := make(map[string]interface{}, len(g.Outputs))
+		for name, ref := range g.Outputs {
+			r, _ := parseReference(ref)
+			value, err := g.output(r, results)
+			if err != nil {
+				return nil, err
+			}
+			values[name] = value
+		}
+		out["values"] = values
+	}
+	out["stages"] = timings
+	return out, nil
+}
+
+// runStage runs a stage in a span of its own, the parent of the model
+// server's
+func (g *Graph) runStage(ctx context.Context, stage Stage, input map[string]interface{}, run Runner) (map[string]interface{}, error) {
+	ctx, span := otel.Tracer("inference-orchestrator").Start(ctx, "stage "+stage.Name,
+		trace.WithAttributes(
+			attribute.String("ensemble", g.name),
+			attribute.String("ensemble.stage", stage.Name),
+			attribute.String("model", stage.Model),
+			attribute.String("model.version", stage.Version),
+		),
+	)
+	defer span.End()
+	if sc := span.SpanContext(); sc.IsValid() {
+		ctx = logging.WithTrace(ctx, sc.TraceID().String(), sc.SpanID().String())
+	}
+
+	result, err := run(ctx, stage.Model, stage.Version, input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
+
+// inputOf gathers a stage's inputs from the request and the stages before it
+func (g *Graph) inputOf(stage Stage, input map[string]interface{}, results []map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(stage.Inputs))
+	for name, ref := range stage.Inputs {
+		r, _ := parseReference(ref)
+		if r.stage == requestInputs {
+			value, ok := input[r.output]
+			if !ok {
+				return nil, apperrors.Newf(apperrors.InvalidArgument, "missing input %q for stage %s", r.output, stage.Name)
+			}
+			out[name] = value
+			continue
+		}
+		value, err := g.output(r, results)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = value
+	}
+	return out, nil
+}
+
+// output returns a stage's output: what the stage's postprocessing steps
+// made of it when they ran, the tensor in the KServe v2 JSON form, or a
+// field of the result of a model server that does not return tensors
+func (g *Graph) output(r reference, results []map[string]interface{}) (interface{}, error) {
+	var result map[string]interface{}
+	for i, stage := range g.Stages {
+		if stage.Name == r.stage {
+			result = results[i]
+		}
+	}
+
+	var value interface{}
+	if predictions, ok := result["predictions"].(map[string]interface{}); ok {
+		value = predictions[r.output]
+	}
+	if value == nil {
+		outputs, _ := result["outputs"].([]interface{})
+		for _, output := range outputs {
+			if tensor, ok := output.(map[string]interface{}); ok && tensor["name"] == r.output {
+				value = map[string]interface{}{
+					"datatype": tensor["datatype"],
+					"shape":    tensor["shape"],
+					"data":     tensor["data"],
+				}
+			}
+		}
+	}
+	if value == nil {
+		value = result[r.output]
+	}
+	if value == nil {
+		return nil, apperrors.Newf(apperrors.FailedPrecondition, "stage %s has no output %q", r.stage, r.output)
+	}
+
+	// Outputs go on as they would be read from JSON, as the inputs of the
+	// next stage's model server or preprocessing steps are
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Internal, fmt.Sprintf("invalid output %q of stage %s", r.output, r.stage))
+	}
+	var plain interface{}
+	if err := json.Unmarshal(raw, &plain); err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Internal, fmt.Sprintf("invalid output %q of stage %s", r.output, r.stage))
+	}
+	return plain, nil
+}
+
+// stageError names the stage an ensemble failed at in the error it returns,
+// keeping the stage's error code
+func stageError(name string, err error) error {
+	message := err.Error()
+	var details string
+	if e, ok := apperrors.As(err); ok {
+		message, details = e.Message, e.Details
+	}
+	return apperrors.Wrap(err, apperrors.CodeOf(err), fmt.Sprintf("stage %s: %s", name, message)).WithDetails(details)
+}
@@ -0,0 +1,210 @@
+package ensemble
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/metadata"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+func graph(t *testing.T, raw string) *Graph {
+	g, err := FromModel(&metadata.Model{Name: "pipeline", Version: "1", Metadata: map[string]string{Key: raw}})
+	require.NoError(t, err)
+	return g
+}
+
+// tensorOf returns a result in the KServe v2 JSON form with one output
+func tensorOf(name string, data ...interface{}) map[string]interface{} {
+	return map[string]interface{}{"outputs": []interface{}{map[string]interface{}{
+		"name": name, "datatype": "FP32", "shape": []int64{int64(len(data))}, "data": data,
+	}}}
+}
+
+func TestFromModel(t *testing.T) {
+	g, err := FromModel(&metadata.Model{Name: "resnet18", Version: "1"})
+	require.NoError(t, err)
+	assert.Nil(t, g)
+
+	g = graph(t, `{"stages": [{"name": "detect", "model": "yolo", "inputs": {"images": "input.image"}}]}`)
+	assert.Equal(t, "1", g.Stages[0].Version)
+
+	for name, raw := range map[string]string{
+		"not json":       `[`,
+		"unknown field":  `{"stages": [{"name": "a", "model": "m", "timeout": 1}]}`,
+		"no stages":      `{"stages": []}`,
+		"no model":       `{"stages": [{"name": "a"}]}`,
+		"reserved name":  `{"stages": [{"name": "input", "model": "m"}]}`,
+		"dotted name":    `{"stages": [{"name": "a.b", "model": "m"}]}`,
+		"declared twice": `{"stages": [{"name": "a", "model": "m"}, {"name": "a", "model": "n"}]}`,
+		"bad reference":  `{"stages": [{"name": "a", "model": "m", "inputs": {"x": "image"}}]}`,
+		"unknown stage":  `{"stages": [{"name": "a", "model": "m", "inputs": {"x": "b.y"}}]}`,
+		"unknown output": `{"stages": [{"name": "a", "model": "m"}], "outputs": {"y": "b.y"}}`,
+		"self cycle":     `{"stages": [{"name": "a", "model": "m", "inputs": {"x": "a.y"}}]}`,
+		"cycle": `{"stages": [
+			{"name": "a", "model": "m", "inputs": {"x": "b.y"}},
+			{"name": "b", "model": "n", "inputs": {"y": "a.x"}}
+		]}`,
+	} {
+		_, err := FromModel(&metadata.Model{Name: "pipeline", Version: "1", Metadata: map[string]string{Key: raw}})
+		assert.True(t, apperrors.Is(err, apperrors.FailedPrecondition), name)
+	}
+}
+
+func TestExecute_ChainsStages(t *testing.T) {
+	g := graph(t, `{
+		"stages": [
+			{"name": "classify", "model": "resnet", "inputs": {"crops": "detect.boxes"}},
+			{"name": "detect", "model": "yolo", "version": "2", "inputs": {"images": "input.image"}}
+		],
+		"outputs": {"label": "classify.label", "boxes": "detect.boxes"}
+	}`)
+
+	var calls []string
+	run := func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+		calls = append(calls, model+":"+version)
+		switch model {
+		case "yolo":
+			assert.Equal(t, map[string]interface{}{"images": "cat.jpg"}, input)
+			return tensorOf("boxes", 1.0, 2.0), nil
+		default:
+			// Outputs are passed on as read from JSON
+			assert.Equal(t, map[string]interface{}{"crops": map[string]interface{}{
+				"datatype": "FP32", "shape": []interface{}{2.0}, "data": []interface{}{1.0, 2.0},
+			}}, input)
+			return map[string]interface{}{"predictions": map[string]interface{}{"label": "tabby"}}, nil
+		}
+	}
+
+	result, err := g.Execute(context.Background(), map[string]interface{}{"image": "cat.jpg"}, run)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"yolo:2", "resnet:1"}, calls)
+	values := result["values"].(map[string]interface{})
+	assert.Equal(t, "tabby", values["label"])
+	assert.Equal(t, []interface{}{1.0, 2.0}, values["boxes"].(map[string]interface{})["data"])
+
+	stages := result["stages"].([]interface{})
+	require.Len(t, stages, 2)
+	assert.Equal(t, "classify", stages[0].(map[string]interface{})["name"])
+	assert.Equal(t, "2", stages[1].(map[string]interface{})["version"])
+}
+
+func TestExecute_ReturnsLastStageWithoutOutputs(t *testing.T) {
+	g := graph(t, `{"stages": [{"name": "generate", "model": "llama", "inputs": {"prompt": "input.text"}}]}`)
+	result, err := g.Execute(context.Background(), map[string]interface{}{"text": "hi"},
+		func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+			return map[string]interface{}{"text_output": "hello"}, nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", result["text_output"])
+	assert.Len(t, result["stages"], 1)
+}
+
+func TestExecute_RunsIndependentStagesAtOnce(t *testing.T) {
+	g := graph(t, `{"stages": [
+		{"name": "a", "model": "m", "inputs": {"x": "input.x"}},
+		{"name": "b", "model": "n", "inputs": {"x": "input.x"}},
+		{"name": "c", "model": "o", "inputs": {"a": "a.y", "b": "b.y"}}
+	]}`)
+
+	// Neither a nor b returns until both have started
+	var started sync.WaitGroup
+	started.Add(2)
+	run := func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+		if model != "o" {
+			started.Done()
+			started.Wait()
+			return map[string]interface{}{"y": model}, nil
+		}
+		return input, nil
+	}
+
+	result, err := g.Execute(context.Background(), map[string]interface{}{"x": 1.0}, run)
+	require.NoError(t, err)
+	assert.Equal(t, "m", result["a"])
+	assert.Equal(t, "n", result["b"])
+}
+
+func TestExecute_FirstFailureCancelsOthers(t *testing.T) {
+	g := graph(t, `{"stages": [
+		{"name": "slow", "model": "m", "inputs": {"x": "input.x"}},
+		{"name": "broken", "model": "n", "inputs": {"x": "input.x"}},
+		{"name": "after", "model": "o", "inputs": {"x": "slow.y"}}
+	]}`)
+
+	var ran []string
+	var mu sync.Mutex
+	run := func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+		mu.Lock()
+		ran = append(ran, model)
+		mu.Unlock()
+		switch model {
+		case "n":
+			return nil, apperrors.New(apperrors.ResourceExhausted, "out of memory").WithDetails("model n")
+		case "m":
+			select {
+			case <-ctx.Done():
+				return nil, apperrors.FromTransportError(ctx.Err(), "triton")
+			case <-time.After(time.Second):
+				return map[string]interface{}{"y": 1.0}, nil
+			}
+		}
+		return input, nil
+	}
+
+	_, err := g.Execute(context.Background(), map[string]interface{}{"x": 1.0}, run)
+	failure, ok := apperrors.As(err)
+	require.True(t, ok)
+	assert.Equal(t, apperrors.ResourceExhausted, failure.Code)
+	assert.Equal(t, "stage broken: out of memory", failure.Message)
+	assert.Equal(t, "model n", failure.Details)
+	assert.NotContains(t, ran, "o")
+}
+
+func TestExecute_MissingInputs(t *testing.T) {
+	g := graph(t, `{"stages": [
+		{"name": "a", "model": "m", "inputs": {"x": "input.x"}},
+		{"name": "b", "model": "n", "inputs": {"x": "a.missing"}}
+	]}`)
+	run := func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"y": 1.0}, nil
+	}
+
+	_, err := g.Execute(context.Background(), map[string]interface{}{}, run)
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+
+	_, err = g.Execute(context.Background(), map[string]interface{}{"x": 1.0}, run)
+	assert.True(t, apperrors.Is(err, apperrors.FailedPrecondition))
+}
+
+func TestExecute_TracesEachStage(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	g := graph(t, `{"stages": [{"name": "detect", "model": "yolo", "inputs": {"x": "input.x"}}]}`)
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "POST /v1/infer")
+	var fields logging.Fields
+	_, err := g.Execute(ctx, map[string]interface{}{"x": 1.0},
+		func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+			fields = logging.FieldsFromContext(ctx)
+			return input, nil
+		})
+	require.NoError(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	stage := spans[0]
+	assert.Equal(t, "stage detect", stage.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), stage.Parent().SpanID())
+	assert.Equal(t, stage.SpanContext().SpanID().String(), fields.SpanID, "the model server is called under the stage's span")
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/backend"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/torchserve"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/triton"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// BackendHandler describes the Triton backend this orchestrator executes on
+type BackendHandler struct {
+	logger       *zap.Logger
+	tritonClient *triton.Client
+	torchServe   *torchserve.Client
+	backend      string
+	pool         string
+	models       ModelSource
+	loadTimeout  time.Duration
+}
+
+// DefaultLoadTimeout bounds how long a model takes to load on Triton
+const DefaultLoadTimeout = 5 * time.Minute
+
+// NewBackendHandler creates a handler for the backend at address, running in node pool
+func NewBackendHandler(logger *zap.Logger, tritonClient *triton.Client, address, pool string) *BackendHandler {
+	return &BackendHandler{
+		logger:       logger,
+		tritonClient: tritonClient,
+		backend:      address,
+		pool:         pool,
+		loadTimeout:  DefaultLoadTimeout,
+	}
+}
+
+// SetModels refuses to load model versions registered to run on another
+// kind of model server than Triton
+func (h *BackendHandler) SetModels(models ModelSource) {
+	h.models = models
+}
+
+// SetLoadTimeout bounds how long a model takes to load on Triton
+func (h *BackendHandler) SetLoadTimeout(timeout time.Duration) {
+	h.loadTimeout = timeout
+}
+
+// SetTorchServe also reports the model versions registered with TorchServe
+func (h *BackendHandler) SetTorchServe(client *torchserve.Client) {
+	h.torchServe = client
+}
+
+// Models reports the backend, its node pool and the model versions in its
+// repository with their load state, followed by those registered with
+// TorchServe when it is configured. TorchServe models are left out while it
+// is unreachable.
+func (h *BackendHandler) Models(c *gin.Context) {
+	models, err := h.tritonClient.Models(c.Request.Context())
+	if err != nil {
+		logging.With(c.Request.Context(), h.logger).Warn("failed to list backend models", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, apperrors.Ensure(err, apperrors.Unavailable, "failed to list backend models"))
+		return
+	}
+
+	if h.torchServe != nil {
+		registered, err := h.torchServe.Models(c.Request.Context())
+		if err != nil {
+			logging.With(c.Request.Context(), h.logger).Warn("failed to list torchserve models", zap.Error(err))
+		}
+		for _, model := range registered {
+			models = append(models, triton.ModelState(model))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"backend": h.backend,
+		"pool":    h.pool,
+		"models":  models,
+	})
+}
+
+// Model reports the versions of a model in Triton's repository with their
+// load state
+func (h *BackendHandler) Model(c *gin.Context) {
+	versions, err := h.versions(c.Request.Context(), c.Param("model"))
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+	if len(versions) == 0 {
+		apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.NotFound, "model %s is not in the repository", c.Param("model")))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"model": c.Param("model"), "versions": versions})
+}
+
+// LoadRequest names the registered version a load is for, which is refused
+// when the version runs on another kind of model server than Triton. Triton
+// loads the versions the model's configuration names whichever it is.
+type LoadRequest struct {
+	Version string `json:"version"`
+}
+
+// LoadModel loads a model from Triton's repository, or reloads it, and
+// reports the state of its versions once it is loaded
+func (h *BackendHandler) LoadModel(c *gin.Context) {
+	ctx := c.Request.Context()
+	model := c.Param("model")
+	var req LoadRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+			return
+		}
+	}
+	if req.Version != "" && h.models != nil {
+		registered, err := h.models.Model(ctx, model, req.Version)
+		if err != nil {
+			logging.With(ctx, h.logger).Warn("model metadata lookup failed", zap.Error(err))
+		} else if kind := backend.Kind(registered); kind != backend.Triton {
+			apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.FailedPrecondition,
+				"model %s version %s is served by %s, not triton", model, req.Version, kind))
+			return
+		}
+	}
+
+	loadCtx, cancel := context.WithTimeout(ctx, h.loadTimeout)
+	defer cancel()
+	start := time.Now()
+	if err := h.tritonClient.LoadModel(loadCtx, model); err != nil {
+		logging.With(ctx, h.logger).Warn("failed to load model", zap.String("model", model), zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, apperrors.Ensure(err, apperrors.Internal, "failed to load model"))
+		return
+	}
+	logging.With(ctx, h.logger).Info("model loaded",
+		zap.String("model", model),
+		zap.Duration("duration", time.Since(start)),
+	)
+
+	versions, err := h.versions(ctx, model)
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"model": model, "versions": versions})
+}
+
+// UnloadModel unloads a model from Triton, and the models only it depends
+// on when unload_dependents=true
+func (h *BackendHandler) UnloadModel(c *gin.Context) {
+	ctx := c.Request.Context()
+	model := c.Param("model")
+	if err := h.tritonClient.UnloadModel(ctx, model, c.Query("unload_dependents") == "true"); err != nil {
+		logging.With(ctx, h.logger).Warn("failed to unload model", zap.String("model", model), zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, apperrors.Ensure(err, apperrors.Internal, "failed to unload model"))
+		return
+	}
+	logging.With(ctx, h.logger).Info("model unloaded", zap.String("model", model))
+	c.JSON(http.StatusOK, gin.H{"model": model, "unloaded": true})
+}
+
+// versions returns the versions of a model in Triton's repository
+func (h *BackendHandler) versions(ctx context.Context, model string) ([]triton.ModelState, error) {
+	models, err := h.tritonClient.Models(ctx)
+	if err != nil {
+		logging.With(ctx, h.logger).Warn("failed to list backend models", zap.Error(err))
+		return nil, apperrors.Ensure(err, apperrors.Unavailable, "failed to list backend models")
+	}
+	versions := []triton.ModelState{}
+	for _, state := range models {
+		if state.Name == model {
+			versions = append(versions, state)
+		}
+	}
+	return versions, nil
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/torchserve"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/triton"
+
+	"github.com/yourusername/ai-platform/pkg/servicetest"
+)
+
+func TestModels_ReportsBackendRepository(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tritonServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"resnet18","version":"1","state":"READY"}]`))
+	}))
+	defer tritonServer.Close()
+
+	handler := NewBackendHandler(zap.NewNop(), triton.NewClient(zap.NewNop(), tritonServer.URL[7:]), "triton:8001", "gpu-a100")
+	router := gin.New()
+	router.GET("/v1/models", handler.Models)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/models", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Backend string              `json:"backend"`
+		Pool    string              `json:"pool"`
+		Models  []triton.ModelState `json:"models"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "triton:8001", body.Backend)
+	assert.Equal(t, "gpu-a100", body.Pool)
+	assert.Equal(t, []triton.ModelState{{Name: "resnet18", Version: "1", State: "READY"}}, body.Models)
+}
+
+func TestModels_IncludesTorchServe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tritonServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"resnet18","version":"1","state":"READY"}]`))
+	}))
+	defer tritonServer.Close()
+	management := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/models":
+			w.Write([]byte(`{"models":[{"modelName":"densenet"}]}`))
+		case "/models/densenet/all":
+			w.Write([]byte(`[{"modelName":"densenet","modelVersion":"1.0","workers":[{"status":"READY"}]}]`))
+		}
+	}))
+	defer management.Close()
+
+	handler := NewBackendHandler(zap.NewNop(), triton.NewClient(zap.NewNop(), tritonServer.URL[7:]), "triton:8001", "cpu")
+	handler.SetTorchServe(torchserve.NewClient(zap.NewNop(), "torchserve:8080", management.URL[7:]))
+	router := gin.New()
+	router.GET("/v1/models", handler.Models)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/models", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Models []triton.ModelState `json:"models"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, []triton.ModelState{
+		{Name: "resnet18", Version: "1", State: "READY"},
+		{Name: "densenet", Version: "1.0", State: "READY"},
+	}, body.Models)
+}
+
+func TestModels_BackendUnreachable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	address := servicetest.Down(t).URL[7:]
+
+	handler := NewBackendHandler(zap.NewNop(), triton.NewClient(zap.NewNop(), address), address, "default")
+	router := gin.New()
+	router.GET("/v1/models", handler.Models)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/models", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestModelRepositoryControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var loaded []string
+	tritonServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/repository/index":
+			w.Write([]byte(`[{"name":"resnet18","version":"1","state":"READY"},{"name":"bert","version":"1","state":"UNAVAILABLE"}]`))
+		case "/v2/repository/models/resnet18/load", "/v2/repository/models/resnet18/unload":
+			loaded = append(loaded, r.URL.Path)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"failed to load 'missing'"}`))
+		}
+	}))
+	defer tritonServer.Close()
+
+	handler := NewBackendHandler(zap.NewNop(), triton.NewClient(zap.NewNop(), tritonServer.URL[7:]), "triton:8001", "gpu-a100")
+	handler.SetModels(modelSource{
+		"densenet:1": {Name: "densenet", Version: "1", Format: "mar"},
+	})
+	router := gin.New()
+	router.GET("/v1/models/:model", handler.Model)
+	router.POST("/v1/models/:model/load", handler.LoadModel)
+	router.POST("/v1/models/:model/unload", handler.UnloadModel)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := serve("POST", "/v1/models/resnet18/load", `{"version":"1"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body struct {
+		Model    string              `json:"model"`
+		Versions []triton.ModelState `json:"versions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, []triton.ModelState{{Name: "resnet18", Version: "1", State: "READY"}}, body.Versions)
+
+	assert.Equal(t, http.StatusOK, serve("POST", "/v1/models/resnet18/unload", "").Code)
+	assert.Equal(t, []string{"/v2/repository/models/resnet18/load", "/v2/repository/models/resnet18/unload"}, loaded)
+
+	// Triton's failures, and versions served elsewhere, are preconditions
+	assert.Equal(t, http.StatusPreconditionFailed, serve("POST", "/v1/models/missing/load", "").Code)
+	assert.Equal(t, http.StatusPreconditionFailed, serve("POST", "/v1/models/densenet/load", `{"version":"1"}`).Code)
+	assert.Len(t, loaded, 2)
+
+	assert.Equal(t, http.StatusOK, serve("GET", "/v1/models/bert", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve("GET", "/v1/models/missing", "").Code)
+}
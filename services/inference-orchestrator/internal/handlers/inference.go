@@ -1,23 +1,272 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/backend"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/batching"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/decode"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/ensemble"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/kserve"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/metadata"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/observability"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/pipeline"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/scheduler"
 	"github.com/yourusername/ai-platform/inference-orchestrator/internal/triton"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/tritonmetrics"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/inferencecache"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/scaling"
+	"github.com/yourusername/ai-platform/pkg/sse"
+	"github.com/yourusername/ai-platform/pkg/usage"
 )
 
 type InferenceHandler struct {
 	logger       *zap.Logger
-	tritonClient *triton.Client
+	backends     map[string]backend.Backend
+	inferenceLog *inferencelog.Capture
+	load         *scaling.Tracker
+	usage        *usage.Recorder
+	pool         string
+	scheduler    *scheduler.Scheduler
+	models       ModelSource
+	batcher      *batching.Batcher
+	results      *inferencecache.Cache
+	triton       *tritonmetrics.Scraper
+	timeout      time.Duration
+}
+
+// ModelSource looks up how a model version is registered, returning nil when
+// it is not
+type ModelSource interface {
+	Model(ctx context.Context, name, version string) (*metadata.Model, error)
 }
 
 func NewInferenceHandler(logger *zap.Logger, tritonClient *triton.Client) *InferenceHandler {
 	return &InferenceHandler{
-		logger:       logger,
-		tritonClient: tritonClient,
+		logger:   logger,
+		backends: map[string]backend.Backend{backend.Triton: tritonClient},
+		load:     scaling.NewTracker("inference-orchestrator", scaling.DefaultRateWindow),
+	}
+}
+
+// SetTimeout bounds inferences whose caller sent no deadline and whose
+// version is registered without a timeout; until it is set, or when it is
+// zero, they are unbounded
+func (h *InferenceHandler) SetTimeout(timeout time.Duration) {
+	h.timeout = timeout
+}
+
+// SetInferenceLog captures sampled inputs and outputs for the data lake
+func (h *InferenceHandler) SetInferenceLog(capture *inferencelog.Capture) {
+	h.inferenceLog = capture
+}
+
+// SetUsageRecorder meters Triton execution time, attributed to pool, so the
+// node pool's cost can be split between tenants and models
+func (h *InferenceHandler) SetUsageRecorder(recorder *usage.Recorder, pool string) {
+	h.usage = recorder
+	h.pool = pool
+}
+
+// SetScheduler bounds the inferences run on Triton at once, queueing the
+// overflow by priority class
+func (h *InferenceHandler) SetScheduler(s *scheduler.Scheduler) {
+	h.scheduler = s
+}
+
+// SetModels runs each model version on the backend its registration names
+// and shapes inputs to the signature it is registered with, so callers can
+// send flat arrays and bare values
+func (h *InferenceHandler) SetModels(models ModelSource) {
+	h.models = models
+}
+
+// SetBatcher coalesces concurrent single-item requests to Triton model
+// versions registered with a max_batch_size into batches
+func (h *InferenceHandler) SetBatcher(b *batching.Batcher) {
+	h.batcher = b
+}
+
+// SetResultCache answers duplicate requests to model versions registered
+// with a cache TTL from cache
+func (h *InferenceHandler) SetResultCache(cache *inferencecache.Cache) {
+	h.results = cache
+}
+
+// SetTritonMetrics adds what Triton's metrics say of queue time, batch sizes
+// and GPU utilization to the load report
+func (h *InferenceHandler) SetTritonMetrics(scraper *tritonmetrics.Scraper) {
+	h.triton = scraper
+}
+
+// SetBackend runs the model versions served by kind of model server on b
+func (h *InferenceHandler) SetBackend(kind string, b backend.Backend) {
+	h.backends[kind] = b
+}
+
+// registered returns how a model version is registered, or nil when it is
+// not or the metadata service is unreachable, in which case the version
+// runs on Triton with its inputs as sent
+func (h *InferenceHandler) registered(ctx context.Context, model, version string) *metadata.Model {
+	if h.models == nil {
+		return nil
+	}
+	registered, err := h.models.Model(ctx, model, version)
+	if err != nil {
+		logging.With(ctx, h.logger).Warn("model metadata lookup failed", zap.Error(err))
+		return nil
+	}
+	return registered
+}
+
+// backendFor returns the kind of model server a registered model version is
+// served by and its backend
+func (h *InferenceHandler) backendFor(registered *metadata.Model) (string, backend.Backend, error) {
+	kind := backend.Kind(registered)
+	b, ok := h.backends[kind]
+	if !ok {
+		return kind, nil, apperrors.Newf(apperrors.FailedPrecondition, "model is served by %s, which is not configured", kind)
+	}
+	return kind, b, nil
+}
+
+// conform shapes input to a registered model version's signature. Inputs go
+// as sent when the version is not registered or its signature cannot be
+// read; inputs that do not fit the signature are errors.
+func (h *InferenceHandler) conform(ctx context.Context, registered *metadata.Model, input map[string]interface{}) (map[string]interface{}, error) {
+	if registered == nil {
+		return input, nil
+	}
+	sig, err := registered.Signature()
+	if err != nil {
+		logging.With(ctx, h.logger).Warn("invalid registered model signature",
+			zap.String("model", registered.Name),
+			zap.String("version", registered.Version),
+			zap.Error(err),
+		)
+		return input, nil
+	}
+	return kserve.Conform(input, sig)
+}
+
+// prepare picks the backend a registered model version runs on and, for
+// tensor models, runs the preprocessing steps it is registered with on input
+// and shapes the result to its signature. It returns the steps to run on the
+// version's result.
+func (h *InferenceHandler) prepare(ctx context.Context, registered *metadata.Model, input map[string]interface{}) (string, backend.Backend, *pipeline.Pipeline, map[string]interface{}, error) {
+	kind, server, err := h.backendFor(registered)
+	if err != nil {
+		return kind, nil, nil, nil, err
+	}
+	var steps *pipeline.Pipeline
+	if backend.Tensors(kind) {
+		if steps, err = pipeline.FromModel(registered); err == nil {
+			if input, err = steps.Preprocess(input); err == nil {
+				input, err = h.conform(ctx, registered, input)
+			}
+		}
+	}
+	return kind, server, steps, input, err
+}
+
+// withTimeout bounds ctx by the timeout a model version is registered with,
+// or by fallback when neither the version nor the caller sets one. The
+// caller's remaining budget still ends the inference if it is sooner.
+func (h *InferenceHandler) withTimeout(ctx context.Context, registered *metadata.Model, fallback time.Duration) (context.Context, context.CancelFunc) {
+	timeout := registered.Timeout()
+	if timeout == 0 {
+		if _, ok := ctx.Deadline(); ok || fallback <= 0 {
+			return context.WithCancel(ctx)
+		}
+		timeout = fallback
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// execute runs an inference on server, batched with other requests when the
+// version is registered to take batches
+func (h *InferenceHandler) execute(ctx context.Context, kind string, server backend.Backend, registered *metadata.Model, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	if h.batcher != nil && kind == backend.Triton {
+		return h.batcher.Infer(ctx, server, model, version, input, batching.Limit(registered))
+	}
+	return server.Infer(ctx, model, version, input)
+}
+
+// runStage runs a stage of an ensemble as Infer runs a model version, with
+// the steps the stage's version is registered with
+func (h *InferenceHandler) runStage(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	registered := h.registered(ctx, model, version)
+	if registered != nil && registered.Metadata[ensemble.Key] != "" {
+		return nil, apperrors.Newf(apperrors.FailedPrecondition, "model %s is an ensemble, which cannot be a stage", model)
+	}
+	ctx, cancel := h.withTimeout(ctx, registered, 0)
+	defer cancel()
+	kind, server, steps, input, err := h.prepare(ctx, registered, input)
+	if err != nil {
+		return nil, err
+	}
+	result, err := h.execute(ctx, kind, server, registered, model, version, input)
+	if err != nil {
+		return nil, err
+	}
+	return steps.Postprocess(result)
+}
+
+// cachedResult returns the cached result of req when its version is cached,
+// and says in X-Cache whether it was a hit. Requests sent with
+// Cache-Control: no-cache always run, and refresh the entry.
+func (h *InferenceHandler) cachedResult(c *gin.Context, req InferRequest) (map[string]interface{}, bool) {
+	if !h.results.Enabled(c.Request.Context(), req.Model, req.Version) {
+		return nil, false
+	}
+	if !strings.Contains(c.GetHeader("Cache-Control"), "no-cache") {
+		if result, ok := h.results.Get(c.Request.Context(), req.Model, req.Version, req.Input); ok {
+			c.Header("X-Cache", "HIT")
+			return result, true
+		}
+	}
+	c.Header("X-Cache", "MISS")
+	return nil, false
+}
+
+// admit waits for a slot on Triton in the request's priority class. It
+// writes the error and returns false when the request is turned away.
+func (h *InferenceHandler) admit(c *gin.Context, ctx context.Context) (func(), bool) {
+	class := scheduler.Class(logging.FieldsFromContext(ctx).Priority)
+	release, waited, err := h.scheduler.Acquire(ctx, class)
+	h.recordQueue()
+	if err != nil {
+		reason := "cancelled"
+		if errors.Is(err, scheduler.ErrQueueFull) {
+			reason = "queue_full"
+		} else if errors.Is(err, scheduler.ErrQueueTimeout) {
+			reason = "timeout"
+		}
+		observability.QueueRejected.WithLabelValues(class, reason).Inc()
+		logging.With(ctx, h.logger).Warn("inference turned away by queue", zap.String("reason", reason))
+		apperrors.Write(c.Writer, c.Request, err)
+		return nil, false
+	}
+	observability.QueueWait.WithLabelValues(class).Observe(waited.Seconds())
+	return func() {
+		release()
+		h.recordQueue()
+	}, true
+}
+
+func (h *InferenceHandler) recordQueue() {
+	for class, queued := range h.scheduler.Queued() {
+		observability.QueueDepth.WithLabelValues(class).Set(float64(queued))
 	}
 }
 
@@ -30,7 +279,7 @@ type InferRequest struct {
 func (h *InferenceHandler) Infer(c *gin.Context) {
 	var req InferRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
 		return
 	}
 
@@ -38,17 +287,241 @@ func (h *InferenceHandler) Infer(c *gin.Context) {
 		req.Version = "1"
 	}
 
-	h.logger.Info("processing inference",
+	ctx := c.Request.Context()
+	logger := logging.With(ctx, h.logger)
+
+	logger.Info("processing inference",
 		zap.String("model", req.Model),
 		zap.String("version", req.Version),
 	)
 
-	result, err := h.tritonClient.Infer(c.Request.Context(), req.Model, req.Version, req.Input)
+	input, err := decode.Inputs(req.Input)
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+	registered := h.registered(ctx, req.Model, req.Version)
+	// The inference, including its wait for a turn on Triton, has the
+	// version's timeout or the default, within the caller's budget
+	ctx, cancel := h.withTimeout(ctx, registered, h.timeout)
+	defer cancel()
+	// Duplicate requests to deterministic versions never reach a model server
+	if result, ok := h.cachedResult(c, req); ok {
+		logger.Debug("inference served from cache", zap.String("model", req.Model))
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	// Ensembles run their stages in place of an inference of their own;
+	// tensor models run the steps they are registered with around the
+	// inference, their preprocessed inputs then shaped to their signature
+	graph, err := ensemble.FromModel(registered)
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+	kind := "ensemble"
+	var server backend.Backend
+	var steps *pipeline.Pipeline
+	if graph == nil {
+		if kind, server, steps, input, err = h.prepare(ctx, registered, input); err != nil {
+			apperrors.Write(c.Writer, c.Request, err)
+			return
+		}
+	}
+
+	// Requests waiting on Triton, or for a turn to run on it, are the
+	// orchestrator's queue for the model
+	done := h.load.Start(req.Model, req.Version)
+	release, ok := h.admit(c, ctx)
+	if !ok {
+		done()
+		return
+	}
+	start := time.Now()
+	var result map[string]interface{}
+	if graph != nil {
+		// The stages hold the request's one slot between them
+		if result, err = graph.Execute(ctx, input, h.runStage); err == nil {
+			result["model_name"] = req.Model
+			result["model_version"] = req.Version
+		}
+	} else {
+		result, err = h.execute(ctx, kind, server, registered, req.Model, req.Version, input)
+	}
+	release()
+	done()
+	elapsed := time.Since(start).Milliseconds()
+	record := inferencelog.Record{
+		Model:     req.Model,
+		Version:   req.Version,
+		Input:     req.Input,
+		Output:    result,
+		LatencyMs: elapsed,
+	}
+
+	// Failed executions occupied the GPU too
+	execution := usage.Event{
+		Kind:      usage.KindCompute,
+		Model:     req.Model,
+		Version:   req.Version,
+		Requests:  1,
+		ComputeMs: elapsed,
+		Pool:      h.pool,
+	}
+	if err != nil {
+		execution.Errors = 1
+	}
+	h.usage.Record(ctx, execution)
+
+	if err == nil {
+		result, err = steps.Postprocess(result)
+	}
 	if err != nil {
-		h.logger.Error("inference failed", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "inference failed"})
+		record.Error = err.Error()
+		h.inferenceLog.Record(ctx, record)
+
+		logger.Error("inference failed", zap.String("backend", kind), zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, apperrors.Ensure(err, apperrors.Internal, "inference failed"))
 		return
 	}
+	h.inferenceLog.Record(ctx, record)
+	h.results.Set(ctx, req.Model, req.Version, req.Input, result)
 
 	c.JSON(http.StatusOK, result)
 }
+
+// StreamInfer runs a generation, sending each output chunk to the caller as a
+// partial event as the model server produces it. Failures before the first chunk are
+// returned as a regular error response; later ones end the stream with an
+// error event.
+func (h *InferenceHandler) StreamInfer(c *gin.Context) {
+	var req InferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+
+	if req.Version == "" {
+		req.Version = "1"
+	}
+
+	ctx := c.Request.Context()
+	logger := logging.With(ctx, h.logger)
+
+	logger.Info("processing streamed inference",
+		zap.String("model", req.Model),
+		zap.String("version", req.Version),
+	)
+
+	input, err := decode.Inputs(req.Input)
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+
+	registered := h.registered(ctx, req.Model, req.Version)
+	if registered != nil && registered.Metadata[ensemble.Key] != "" {
+		apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.Unimplemented, "ensemble %s does not stream", req.Model))
+		return
+	}
+	// Generations run as long as the caller waits, unless the version is
+	// registered with a timeout
+	ctx, cancel := h.withTimeout(ctx, registered, 0)
+	defer cancel()
+	kind, server, err := h.backendFor(registered)
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+	streamer, ok := server.(backend.Streamer)
+	if !ok {
+		apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.Unimplemented, "%s does not stream", kind))
+		return
+	}
+
+	var stream *sse.Writer
+	var chunks []interface{}
+	done := h.load.Start(req.Model, req.Version)
+	release, ok := h.admit(c, ctx)
+	if !ok {
+		done()
+		return
+	}
+	start := time.Now()
+	err = streamer.InferStream(ctx, req.Model, req.Version, input, func(chunk map[string]interface{}) error {
+		if stream == nil {
+			var err error
+			if stream, err = sse.NewWriter(c.Writer); err != nil {
+				return err
+			}
+		}
+		chunks = append(chunks, chunk)
+		return stream.Send(sse.EventPartial, chunk)
+	})
+	release()
+	done()
+	elapsed := time.Since(start).Milliseconds()
+
+	execution := usage.Event{
+		Kind:      usage.KindCompute,
+		Model:     req.Model,
+		Version:   req.Version,
+		Requests:  1,
+		ComputeMs: elapsed,
+		Pool:      h.pool,
+	}
+	record := inferencelog.Record{
+		Model:     req.Model,
+		Version:   req.Version,
+		Input:     req.Input,
+		Output:    map[string]interface{}{"chunks": chunks},
+		LatencyMs: elapsed,
+	}
+	if err != nil {
+		execution.Errors = 1
+		record.Error = err.Error()
+	}
+	h.usage.Record(ctx, execution)
+	h.inferenceLog.Record(ctx, record)
+
+	if err != nil {
+		logger.Error("streamed inference failed", zap.Error(err))
+		err = apperrors.Ensure(err, apperrors.Internal, "inference failed")
+		if stream == nil {
+			apperrors.Write(c.Writer, c.Request, err)
+			return
+		}
+		_, body := apperrors.ToHTTP(err)
+		stream.Send(sse.EventError, body)
+		return
+	}
+
+	// A generation may complete without producing any output
+	if stream == nil {
+		if stream, err = sse.NewWriter(c.Writer); err != nil {
+			apperrors.Write(c.Writer, c.Request, apperrors.Wrap(err, apperrors.Internal, "streaming unsupported"))
+			return
+		}
+	}
+	stream.Send(sse.EventDone, gin.H{
+		"model":      req.Model,
+		"version":    req.Version,
+		"chunks":     len(chunks),
+		"latency_ms": elapsed,
+	})
+}
+
+// Load reports the requests waiting on Triton per model version, with Triton's
+// queue time, batch sizes and GPU utilization when its metrics are scraped,
+// for the autoscaler
+func (h *InferenceHandler) Load(c *gin.Context) {
+	report := h.load.Report(time.Now().UTC())
+	report.GPUUtilization, _ = h.triton.GPUUtilization()
+	for i, l := range report.Loads {
+		if stats, ok := h.triton.Model(l.Model, l.Version); ok {
+			report.Loads[i].QueueSeconds = stats.QueueSeconds
+			report.Loads[i].BatchSize = stats.BatchSize
+		}
+	}
+	c.JSON(http.StatusOK, report)
+}
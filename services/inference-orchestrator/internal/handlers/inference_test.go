@@ -0,0 +1,577 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/backend"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/ensemble"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/metadata"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/pipeline"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/scheduler"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/torchserve"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/triton"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/inferencecache"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/scaling"
+	"github.com/yourusername/ai-platform/pkg/sse"
+	"github.com/yourusername/ai-platform/pkg/tiered"
+	"github.com/yourusername/ai-platform/pkg/usage"
+)
+
+// tritonInfer is a Triton answering inferences over HTTP with the
+// inputs it was sent, after a few milliseconds of compute
+func tritonInfer(t *testing.T) *triton.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req triton.InferRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		time.Sleep(5 * time.Millisecond)
+		json.NewEncoder(w).Encode(map[string]interface{}{"model_name": "resnet18", "outputs": req.Inputs})
+	}))
+	t.Cleanup(server.Close)
+	return triton.NewClient(zap.NewNop(), server.URL[len("http://"):])
+}
+
+func TestInfer_CapturesInferenceLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var records []inferencelog.Record
+	publisher := publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var record inferencelog.Record
+		require.NoError(t, json.Unmarshal(value, &record))
+		records = append(records, record)
+		return nil
+	})
+	cfg := inferencelog.Config{Enabled: true, SampleRate: 1, Redact: []string{"email"}, MaxPayloadBytes: 1 << 10}
+	capture := inferencelog.NewCapture(cfg, "inference-orchestrator", publisher, 10, zap.NewNop())
+
+	handler := NewInferenceHandler(zap.NewNop(), tritonInfer(t))
+	handler.SetInferenceLog(capture)
+
+	router := gin.New()
+	router.POST("/v1/infer", handler.Infer)
+
+	body := `{"model":"resnet18","version":"1","input":{"data":[1,2],"email":"jane@example.com"}}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	capture.Run(ctx)
+
+	require.Len(t, records, 1)
+	assert.Equal(t, "resnet18", records[0].Model)
+	assert.Equal(t, inferencelog.Redacted, records[0].Input["email"])
+	assert.NotEmpty(t, records[0].Output)
+	assert.Positive(t, records[0].LatencyMs)
+}
+
+func TestInfer_MetersComputeTime(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var events []usage.Event
+	recorder := usage.NewRecorder("inference-orchestrator", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event usage.Event
+		require.NoError(t, json.Unmarshal(value, &event))
+		events = append(events, event)
+		return nil
+	}), 10, zap.NewNop())
+
+	handler := NewInferenceHandler(zap.NewNop(), tritonInfer(t))
+	handler.SetUsageRecorder(recorder, "gpu-a100")
+
+	router := gin.New()
+	router.POST("/v1/infer", handler.Infer)
+
+	body := `{"model":"resnet18","version":"1","input":{"data":[1,2]}}`
+	req := httptest.NewRequest("POST", "/v1/infer", strings.NewReader(body))
+	req.Header.Set(logging.HeaderTenant, "acme")
+	w := httptest.NewRecorder()
+	logging.Middleware(router).ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, usage.KindCompute, events[0].Kind)
+	assert.Equal(t, "acme", events[0].Tenant)
+	assert.Equal(t, "gpu-a100", events[0].Pool)
+	assert.Equal(t, int64(1), events[0].Requests)
+	assert.Positive(t, events[0].ComputeMs)
+}
+
+func TestLoad_ReportsServedModels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewInferenceHandler(zap.NewNop(), tritonInfer(t))
+	router := gin.New()
+	router.POST("/v1/infer", handler.Infer)
+	router.GET("/v1/load", handler.Load)
+
+	body := `{"model":"resnet18","version":"1","input":{"data":[1,2]}}`
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/v1/infer", strings.NewReader(body)))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/load", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report scaling.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, "inference-orchestrator", report.Service)
+	require.Len(t, report.Loads, 1)
+	assert.Equal(t, "resnet18", report.Loads[0].Model)
+	assert.Zero(t, report.Loads[0].InFlight)
+}
+
+func tritonStream(t *testing.T, events ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/models/llama/versions/1/generate_stream", r.URL.Path)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			w.Write([]byte("data: " + event + "\n\n"))
+		}
+	}))
+}
+
+func readEvents(t *testing.T, body io.Reader) []sse.Event {
+	var events []sse.Event
+	reader := sse.NewReader(body)
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return events
+		}
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+}
+
+func TestStreamInfer_RelaysChunks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := tritonStream(t, `{"text_output":"Hel"}`, `{"text_output":"lo"}`)
+	defer server.Close()
+
+	var events []usage.Event
+	recorder := usage.NewRecorder("inference-orchestrator", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event usage.Event
+		require.NoError(t, json.Unmarshal(value, &event))
+		events = append(events, event)
+		return nil
+	}), 10, zap.NewNop())
+
+	handler := NewInferenceHandler(zap.NewNop(), triton.NewClient(zap.NewNop(), server.URL[7:]))
+	handler.SetUsageRecorder(recorder, "gpu-a100")
+	router := gin.New()
+	router.POST("/v1/infer/stream", handler.StreamInfer)
+
+	body := `{"model":"llama","version":"v1","input":{"text_input":"Hi"}}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer/stream", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, sse.ContentType, w.Header().Get("Content-Type"))
+
+	streamed := readEvents(t, w.Body)
+	require.Len(t, streamed, 3)
+	assert.Equal(t, sse.Event{Name: sse.EventPartial, Data: []byte(`{"text_output":"Hel"}`)}, streamed[0])
+	assert.Equal(t, sse.EventPartial, streamed[1].Name)
+	assert.Equal(t, sse.EventDone, streamed[2].Name)
+	assert.Contains(t, string(streamed[2].Data), `"chunks":2`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+	require.Len(t, events, 1)
+	assert.Equal(t, usage.KindCompute, events[0].Kind)
+	assert.Zero(t, events[0].Errors)
+}
+
+func TestStreamInfer_FailsBeforeOutput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"unknown model"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	handler := NewInferenceHandler(zap.NewNop(), triton.NewClient(zap.NewNop(), server.URL[7:]))
+	router := gin.New()
+	router.POST("/v1/infer/stream", handler.StreamInfer)
+
+	body := `{"model":"llama","input":{"text_input":"Hi"}}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer/stream", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, apperrors.ContentType, w.Header().Get("Content-Type"))
+}
+
+func TestStreamInfer_FailsMidStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := tritonStream(t, `{"text_output":"Hel"}`, `{"error":"out of memory"}`)
+	defer server.Close()
+
+	handler := NewInferenceHandler(zap.NewNop(), triton.NewClient(zap.NewNop(), server.URL[7:]))
+	router := gin.New()
+	router.POST("/v1/infer/stream", handler.StreamInfer)
+
+	body := `{"model":"llama","version":"1","input":{"text_input":"Hi"}}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer/stream", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	streamed := readEvents(t, w.Body)
+	require.Len(t, streamed, 2)
+	assert.Equal(t, sse.EventPartial, streamed[0].Name)
+	assert.Equal(t, sse.EventError, streamed[1].Name)
+
+	var failure apperrors.Response
+	require.NoError(t, json.Unmarshal(streamed[1].Data, &failure))
+	assert.Equal(t, apperrors.Internal, failure.Code)
+	assert.Equal(t, "out of memory", failure.Details)
+}
+
+func TestInfer_RejectsUndecodableInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewInferenceHandler(zap.NewNop(), triton.NewClient(zap.NewNop(), "localhost:8001"))
+	router := gin.New()
+	router.POST("/v1/infer", handler.Infer)
+
+	body := `{"model":"resnet18","input":{"image":{"$blob":"bm90IGFuIGltYWdl","content_type":"image/png"}}}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "image: invalid image")
+}
+
+// modelSource serves registered models from a map keyed "name:version"
+type modelSource map[string]*metadata.Model
+
+func (s modelSource) Model(ctx context.Context, name, version string) (*metadata.Model, error) {
+	return s[name+":"+version], nil
+}
+
+func TestInfer_ShapesInputToSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewInferenceHandler(zap.NewNop(), tritonInfer(t))
+	handler.SetModels(modelSource{"mnist:1": {
+		Name:       "mnist",
+		Version:    "1",
+		InputShape: "[-1, 2, 2]",
+		Metadata:   map[string]string{metadata.InputNameKey: "pixels", metadata.InputDatatypeKey: "FP64"},
+	}})
+	router := gin.New()
+	router.POST("/v1/infer", handler.Infer)
+
+	body := `{"model":"mnist","input":{"x":[0,0.5,0.5,1]}}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var result struct {
+		Outputs []map[string]interface{} `json:"outputs"`
+		Values  map[string]interface{}   `json:"values"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result.Outputs, 1)
+	assert.Equal(t, "pixels", result.Outputs[0]["name"])
+	assert.Equal(t, "FP64", result.Outputs[0]["datatype"])
+	assert.Equal(t, []interface{}{1.0, 2.0, 2.0}, result.Outputs[0]["shape"])
+	assert.Equal(t, []interface{}{[]interface{}{[]interface{}{0.0, 0.5}, []interface{}{0.5, 1.0}}}, result.Values["pixels"])
+
+	// Inputs that cannot take the registered shape are the caller's error
+	body = `{"model":"mnist","input":{"x":[0,0.5,0.5]}}`
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", strings.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestInfer_RunsRegisteredPipeline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewInferenceHandler(zap.NewNop(), tritonInfer(t))
+	handler.SetModels(modelSource{
+		"scorer:1": {Name: "scorer", Version: "1", Metadata: map[string]string{
+			pipeline.PreprocessKey:  `[{"op": "normalize", "mean": [1], "std": [2]}, {"op": "batch"}]`,
+			pipeline.PostprocessKey: `[{"op": "softmax"}, {"op": "top_k", "k": 1, "labels": ["cat", "dog"]}]`,
+		}},
+		"broken:1": {Name: "broken", Version: "1", Metadata: map[string]string{pipeline.PreprocessKey: `[{"op": "blur"}]`}},
+	})
+	router := gin.New()
+	router.POST("/v1/infer", handler.Infer)
+
+	// The echoed input is the normalized [[0, 1]]
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", strings.NewReader(`{"model":"scorer","input":{"logits":[1,3]}}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result struct {
+		Values      map[string]interface{} `json:"values"`
+		Predictions map[string]interface{} `json:"predictions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []interface{}{[]interface{}{0.0, 1.0}}, result.Values["logits"])
+	best := result.Predictions["logits"].([]interface{})[0].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "dog", best["label"])
+	assert.InDelta(t, math.E/(1+math.E), best["score"], 1e-9)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", strings.NewReader(`{"model":"broken","input":{"x":[1]}}`)))
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+func TestInfer_RunsOnRegisteredBackend(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	torchServe := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/predictions/densenet/1", r.URL.Path)
+		w.Write([]byte(`{"tabby": 0.9}`))
+	}))
+	defer torchServe.Close()
+
+	handler := NewInferenceHandler(zap.NewNop(), tritonInfer(t))
+	handler.SetModels(modelSource{
+		"densenet:1": {Name: "densenet", Version: "1", Framework: "pytorch", Format: "mar", InputShape: "[3]"},
+		"resnet18:1": {Name: "resnet18", Version: "1", Framework: "onnx", Format: "onnx"},
+	})
+	router := gin.New()
+	router.POST("/v1/infer", handler.Infer)
+	infer := func(model string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		body := `{"model":"` + model + `","input":{"data":[1,2]}}`
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", strings.NewReader(body)))
+		return w
+	}
+
+	// Model archives need a TorchServe
+	w := infer("densenet")
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code, w.Body.String())
+
+	handler.SetBackend(backend.TorchServe, torchserve.NewClient(zap.NewNop(), torchServe.URL[len("http://"):], ""))
+	w = infer("densenet")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"predictions":{"tabby":0.9}`)
+
+	w = infer("resnet18")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"outputs"`)
+}
+
+func TestInfer_QueuesByPriority(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	queue := scheduler.New(scheduler.Config{
+		MaxInFlight: 1,
+		QueueSizes:  map[string]int{"high": 1},
+		MaxWait:     time.Second,
+	})
+	handler := NewInferenceHandler(zap.NewNop(), tritonInfer(t))
+	handler.SetScheduler(queue)
+	router := gin.New()
+	router.POST("/v1/infer", handler.Infer)
+
+	infer := func(priority string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/v1/infer", strings.NewReader(`{"model":"resnet18","input":{"data":[1]}}`))
+		req.Header.Set(logging.HeaderPriority, priority)
+		w := httptest.NewRecorder()
+		logging.Middleware(router).ServeHTTP(w, req)
+		return w
+	}
+
+	// Triton is busy; bulk requests have no room to wait
+	release, _, err := queue.Acquire(context.Background(), "normal")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, infer("low").Code)
+
+	done := make(chan int)
+	go func() { done <- infer("high").Code }()
+	require.Eventually(t, func() bool { return queue.Queued()["high"] == 1 }, time.Second, time.Millisecond)
+	release()
+	assert.Equal(t, http.StatusOK, <-done)
+}
+
+func TestInfer_RunsRegisteredEnsemble(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewInferenceHandler(zap.NewNop(), tritonInfer(t))
+	handler.SetModels(modelSource{
+		"chain:1": {Name: "chain", Version: "1", Metadata: map[string]string{ensemble.Key: `{
+			"stages": [
+				{"name": "detect", "model": "detector", "inputs": {"image": "input.image"}},
+				{"name": "classify", "model": "classifier", "inputs": {"crops": "detect.image"}}
+			],
+			"outputs": {"scores": "classify.crops"}
+		}`}},
+		"detector:1":   {Name: "detector", Version: "1", Metadata: map[string]string{pipeline.PreprocessKey: `[{"op": "normalize", "mean": [1], "std": [2]}, {"op": "batch"}]`}},
+		"classifier:1": {Name: "classifier", Version: "1", Metadata: map[string]string{pipeline.PostprocessKey: `[{"op": "softmax"}]`}},
+		"loop:1": {Name: "loop", Version: "1", Metadata: map[string]string{ensemble.Key: `{"stages": [
+			{"name": "a", "model": "detector", "inputs": {"x": "a.x"}}
+		]}`}},
+	})
+	router := gin.New()
+	router.POST("/v1/infer", handler.Infer)
+	router.POST("/v1/infer/stream", handler.StreamInfer)
+
+	// The detector's echo of the normalized [[0, 1]] is the classifier's input
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", strings.NewReader(`{"model":"chain","input":{"image":[1,3]}}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result struct {
+		ModelName string                   `json:"model_name"`
+		Values    map[string]interface{}   `json:"values"`
+		Stages    []map[string]interface{} `json:"stages"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, "chain", result.ModelName)
+	scores := result.Values["scores"].([]interface{})[0].([]interface{})
+	assert.InDelta(t, math.E/(1+math.E), scores[1], 1e-9)
+	require.Len(t, result.Stages, 2)
+	assert.Equal(t, "detector", result.Stages[0]["model"])
+	assert.Equal(t, "classifier", result.Stages[1]["model"])
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", strings.NewReader(`{"model":"loop","input":{"x":[1]}}`)))
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer/stream", strings.NewReader(`{"model":"chain","input":{"image":[1,3]}}`)))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+// mapStore is a result cache store in memory
+type mapStore map[string][]byte
+
+func (s mapStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, ok := s[key]
+	if !ok {
+		return nil, tiered.ErrMiss
+	}
+	return value, nil
+}
+
+func (s mapStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s[key] = value
+	return nil
+}
+
+func (s mapStore) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(s, key)
+	}
+	return nil
+}
+
+func TestInfer_CachesDeterministicResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"model_name": "embed", "outputs": []}`))
+	}))
+	defer server.Close()
+
+	handler := NewInferenceHandler(zap.NewNop(), triton.NewClient(zap.NewNop(), server.URL[len("http://"):]))
+	models := modelSource{
+		"embed:1": {Name: "embed", Version: "1", Metadata: map[string]string{metadata.CacheTTLKey: "1h"}},
+	}
+	handler.SetModels(models)
+	ttls := func(ctx context.Context, model, version string) time.Duration {
+		return models[model+":"+version].CacheTTL()
+	}
+	handler.SetResultCache(inferencecache.New("inference:result", mapStore{}, inferencecache.TTLsFunc(ttls), zap.NewNop()))
+	router := gin.New()
+	router.POST("/v1/infer", handler.Infer)
+	infer := func(model, cacheControl string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/v1/infer", strings.NewReader(`{"model":"`+model+`","input":{"text":"hello"}}`))
+		req.Header.Set("Cache-Control", cacheControl)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return w
+	}
+
+	assert.Equal(t, "MISS", infer("embed", "").Header().Get("X-Cache"))
+	w := infer("embed", "")
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.Contains(t, w.Body.String(), `"model_name":"embed"`)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// no-cache reaches the model and refreshes the entry
+	assert.Equal(t, "MISS", infer("embed", "no-cache").Header().Get("X-Cache"))
+	assert.Equal(t, int32(2), calls.Load())
+
+	// Versions without a TTL are never cached
+	w = infer("other", "")
+	infer("other", "")
+	assert.Empty(t, w.Header().Get("X-Cache"))
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestInfer_BoundsByRegisteredTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	timeouts := make(chan float64, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req triton.InferRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		timeouts <- req.Parameters["timeout"].(float64)
+		if strings.Contains(r.URL.Path, "/slow/") {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"outputs": req.Inputs})
+	}))
+	defer server.Close()
+
+	handler := NewInferenceHandler(zap.NewNop(), triton.NewClient(zap.NewNop(), server.URL[len("http://"):]))
+	handler.SetModels(modelSource{
+		"llama:1": {Name: "llama", Version: "1", Metadata: map[string]string{metadata.TimeoutKey: "2m"}},
+		"slow:1":  {Name: "slow", Version: "1", Metadata: map[string]string{metadata.TimeoutKey: "50ms"}},
+	})
+	handler.SetTimeout(30 * time.Second)
+	router := gin.New()
+	router.POST("/v1/infer", handler.Infer)
+	infer := func(ctx context.Context, model string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		body := `{"model":"` + model + `","input":{"data":[1]}}`
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/infer", strings.NewReader(body)).WithContext(ctx))
+		return w
+	}
+
+	// Triton is sent the time left in microseconds: the default without a
+	// registered timeout, and a registered one past the client's 30s
+	w := infer(context.Background(), "resnet18")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.InDelta(t, (30 * time.Second).Microseconds(), <-timeouts, 1e6)
+	w = infer(context.Background(), "llama")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.InDelta(t, (2 * time.Minute).Microseconds(), <-timeouts, 1e6)
+
+	// The caller's remaining budget wins when it is sooner
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	w = infer(ctx, "llama")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.LessOrEqual(t, <-timeouts, float64(time.Second.Microseconds()))
+
+	start := time.Now()
+	w = infer(context.Background(), "slow")
+	<-timeouts
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code, w.Body.String())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,205 @@
+// Package kserve calls Triton over the KServe v2 gRPC inference protocol,
+// with the messages generated in pkg/kservepb that the model router also
+// speaks to the model servers it routes to directly.
+package kserve
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/kservepb"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Client calls one KServe v2 gRPC server
+type Client struct {
+	conn   *grpc.ClientConn
+	client kservepb.GRPCInferenceServiceClient
+}
+
+// Dial returns a client of the server at target, "host:port", connecting
+// over TLS when secure. It connects on the first call rather than at once.
+func Dial(target string, secure bool) (*Client, error) {
+	conn, err := kservepb.Dial(target, secure)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, client: kservepb.NewGRPCInferenceServiceClient(conn)}, nil
+}
+
+// Close closes the client's connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Infer runs model version on input, whose entries are the model's input
+// tensors by name, and returns the server's answer with its outputs in the
+// KServe v2 JSON form. The call is bounded by ctx's deadline, which Triton is
+// sent as the call's timeout and as the request's timeout parameter.
+func (c *Client) Infer(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	req, err := kservepb.NewInferRequest(model, version, logging.RequestID(ctx), input)
+	if err != nil {
+		return nil, err
+	}
+	if timeout, ok := Timeout(ctx); ok {
+		req.Parameters = kservepb.NewParameters(map[string]interface{}{TimeoutParameter: timeout})
+	}
+
+	resp, err := c.client.ModelInfer(outgoing(ctx), req)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	result, err := kservepb.Response(resp)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Internal, "invalid response from triton")
+	}
+	return result, nil
+}
+
+// Parameters Triton is asked to flag a decoupled model's last response
+// with, and flags it with
+const (
+	enableFinalResponse = "triton_enable_empty_final_response"
+	finalResponse       = "triton_final_response"
+)
+
+// InferStream runs model version on input as Infer does, on a decoupled
+// model, which may answer with any number of responses, passing each to
+// emit as it arrives. A "parameters" object in input is sent as the
+// request's parameters rather than as a tensor. Responses are passed on
+// with their outputs by name, a single element as itself and more as a flat
+// array, as Triton's generate extension answers. The stream is bounded by
+// ctx; failures before the first response are returned without emitting
+// anything.
+func (c *Client) InferStream(ctx context.Context, model, version string, input map[string]interface{}, emit func(chunk map[string]interface{}) error) error {
+	parameters, ok := input["parameters"].(map[string]interface{})
+	if ok && parameters["datatype"] == nil {
+		tensors := make(map[string]interface{}, len(input))
+		for name, value := range input {
+			if name != "parameters" {
+				tensors[name] = value
+			}
+		}
+		input = tensors
+	} else {
+		parameters = nil
+	}
+
+	req, err := kservepb.NewInferRequest(model, version, logging.RequestID(ctx), input)
+	if err != nil {
+		return err
+	}
+	requested := map[string]interface{}{enableFinalResponse: true}
+	for name, value := range parameters {
+		requested[name] = value
+	}
+	req.Parameters = kservepb.NewParameters(requested)
+
+	// Leaving before the stream ends releases it
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.client.ModelStreamInfer(outgoing(ctx))
+	if err != nil {
+		return fromStatus(err)
+	}
+	// A failed send is reported by the receive that follows
+	if err := stream.Send(req); err == nil {
+		stream.CloseSend()
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fromStatus(err)
+		}
+		if resp.ErrorMessage != "" {
+			return apperrors.New(apperrors.Internal, "generation failed").WithDetails(resp.ErrorMessage)
+		}
+		if resp.InferResponse == nil {
+			continue
+		}
+
+		if len(resp.InferResponse.Outputs) > 0 {
+			outputs, err := kservepb.Outputs(resp.InferResponse)
+			if err != nil {
+				return apperrors.Wrap(err, apperrors.Internal, "invalid response from triton")
+			}
+			chunk := map[string]interface{}{
+				"model_name":    resp.InferResponse.ModelName,
+				"model_version": resp.InferResponse.ModelVersion,
+			}
+			for _, output := range outputs {
+				data := output["data"].([]interface{})
+				if len(data) == 1 {
+					chunk[output["name"].(string)] = data[0]
+				} else {
+					chunk[output["name"].(string)] = data
+				}
+			}
+			if err := emit(chunk); err != nil {
+				return err
+			}
+		}
+		if final, _ := resp.InferResponse.Parameters[finalResponse].Value().(bool); final {
+			return nil
+		}
+	}
+}
+
+// TimeoutParameter is the request parameter Triton reads a request's timeout
+// from, in microseconds. Models whose queue policy allows it drop requests
+// still queued once it passes rather than run them for a caller that has
+// given up.
+const TimeoutParameter = "timeout"
+
+// Timeout returns the time left before ctx's deadline in microseconds, as
+// TimeoutParameter is given
+func Timeout(ctx context.Context) (int64, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	remaining := time.Until(deadline).Microseconds()
+	if remaining < 1 {
+		remaining = 1
+	}
+	return remaining, true
+}
+
+// Ready fails unless the server is ready for inferencing
+func (c *Client) Ready(ctx context.Context) error {
+	resp, err := c.client.ServerReady(ctx, &kservepb.ServerReadyRequest{})
+	if err != nil {
+		return fromStatus(err)
+	}
+	if !resp.Ready {
+		return apperrors.New(apperrors.Unavailable, "triton is not ready")
+	}
+	return nil
+}
+
+// outgoing returns ctx carrying the correlation fields as call metadata
+func outgoing(ctx context.Context) context.Context {
+	pairs := make([]string, 0, 8)
+	for key, value := range logging.Headers(ctx) {
+		pairs = append(pairs, key, value)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// fromStatus classifies a failed call by its gRPC status
+func fromStatus(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return apperrors.FromTransportError(err, "triton")
+	}
+	return apperrors.Wrap(err, apperrors.FromGRPCCode(uint32(s.Code())), s.Message())
+}
@@ -0,0 +1,200 @@
+package kserve
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/kservepb"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// fakeServer is a KServe v2 server answering ModelInfer with infer and
+// ModelStreamInfer with stream
+type fakeServer struct {
+	kservepb.UnimplementedGRPCInferenceServiceServer
+	ready  bool
+	infer  func(ctx context.Context, req *kservepb.ModelInferRequest) (*kservepb.ModelInferResponse, error)
+	stream func(req *kservepb.ModelInferRequest, send func(*kservepb.ModelStreamInferResponse) error) error
+}
+
+func (s *fakeServer) ServerReady(context.Context, *kservepb.ServerReadyRequest) (*kservepb.ServerReadyResponse, error) {
+	return &kservepb.ServerReadyResponse{Ready: s.ready}, nil
+}
+
+func (s *fakeServer) ModelInfer(ctx context.Context, req *kservepb.ModelInferRequest) (*kservepb.ModelInferResponse, error) {
+	return s.infer(ctx, req)
+}
+
+func (s *fakeServer) ModelStreamInfer(stream kservepb.GRPCInferenceService_ModelStreamInferServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	return s.stream(req, stream.Send)
+}
+
+// serve starts the server and returns a client of it
+func (s *fakeServer) serve(t *testing.T) *Client {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	kservepb.RegisterGRPCInferenceServiceServer(server, s)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	client, err := Dial(listener.Addr().String(), false)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestClient_Infer(t *testing.T) {
+	server := &fakeServer{infer: func(ctx context.Context, req *kservepb.ModelInferRequest) (*kservepb.ModelInferResponse, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		assert.Equal(t, []string{"req-1"}, md.Get(logging.HeaderRequestID))
+		_, ok := ctx.Deadline()
+		assert.True(t, ok, "the caller's deadline is propagated")
+		assert.Equal(t, "resnet18", req.ModelName)
+		assert.Equal(t, "1", req.ModelVersion)
+		assert.Equal(t, "req-1", req.Id)
+		assert.Greater(t, req.Parameters[TimeoutParameter].GetInt64Param(), int64(0))
+
+		// Inputs are sent in name order with their data in raw
+		require.Len(t, req.Inputs, 2)
+		assert.Equal(t, "image", req.Inputs[0].Name)
+		assert.Equal(t, []int64{2, 2}, req.Inputs[0].Shape)
+		assert.Equal(t, float32(0.5), math.Float32frombits(binary.LittleEndian.Uint32(req.RawInputContents[0][12:])))
+		assert.Equal(t, "label", req.Inputs[1].Name)
+		assert.Equal(t, []byte{7, 0, 0, 0, 0, 0, 0, 0}, req.RawInputContents[1])
+
+		probs := binary.LittleEndian.AppendUint32(nil, math.Float32bits(0.25))
+		probs = binary.LittleEndian.AppendUint32(probs, math.Float32bits(0.75))
+		classes := binary.LittleEndian.AppendUint32(nil, 3)
+		classes = append(classes, "cat"...)
+		return &kservepb.ModelInferResponse{
+			ModelName:    req.ModelName,
+			ModelVersion: req.ModelVersion,
+			Id:           req.Id,
+			Outputs: []*kservepb.ModelInferResponse_InferOutputTensor{
+				{Name: "probs", Datatype: "FP32", Shape: []int64{1, 2}},
+				{Name: "class", Datatype: "BYTES", Shape: []int64{1}},
+			},
+			RawOutputContents: [][]byte{probs, classes},
+		}, nil
+	}}
+	client := server.serve(t)
+
+	ctx, cancel := context.WithTimeout(logging.WithRequestID(context.Background(), "req-1"), time.Minute)
+	defer cancel()
+	result, err := client.Infer(ctx, "resnet18", "1", map[string]interface{}{
+		"image": []interface{}{[]interface{}{0.0, 0.0}, []interface{}{0.0, 0.5}},
+		"label": map[string]interface{}{"datatype": "INT64", "shape": []interface{}{1.0}, "data": []interface{}{7.0}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "resnet18", result["model_name"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "probs", "datatype": "FP32", "shape": []int64{1, 2}, "data": []interface{}{0.25, 0.75}},
+		map[string]interface{}{"name": "class", "datatype": "BYTES", "shape": []int64{1}, "data": []interface{}{"cat"}},
+	}, result["outputs"])
+}
+
+func TestClient_InferStream(t *testing.T) {
+	server := &fakeServer{stream: func(req *kservepb.ModelInferRequest, send func(*kservepb.ModelStreamInferResponse) error) error {
+		assert.Equal(t, "llama", req.ModelName)
+		parameters := make(map[string]interface{}, len(req.Parameters))
+		for name, p := range req.Parameters {
+			parameters[name] = p.Value()
+		}
+		assert.Equal(t, map[string]interface{}{enableFinalResponse: true, "max_tokens": int64(8), "temperature": 0.5}, parameters)
+		require.Len(t, req.Inputs, 1)
+		assert.Equal(t, "text_input", req.Inputs[0].Name)
+		assert.Equal(t, []int64{1}, req.Inputs[0].Shape)
+
+		for _, token := range []string{"Hel", "lo"} {
+			raw := binary.LittleEndian.AppendUint32(nil, uint32(len(token)))
+			if err := send(&kservepb.ModelStreamInferResponse{InferResponse: &kservepb.ModelInferResponse{
+				ModelName:         req.ModelName,
+				ModelVersion:      req.ModelVersion,
+				Outputs:           []*kservepb.ModelInferResponse_InferOutputTensor{{Name: "text_output", Datatype: "BYTES", Shape: []int64{1}}},
+				RawOutputContents: [][]byte{append(raw, token...)},
+			}}); err != nil {
+				return err
+			}
+		}
+		final := kservepb.NewParameters(map[string]interface{}{finalResponse: true})
+		if err := send(&kservepb.ModelStreamInferResponse{InferResponse: &kservepb.ModelInferResponse{Parameters: final}}); err != nil {
+			return err
+		}
+		// Nothing after the final response is read
+		return send(&kservepb.ModelStreamInferResponse{ErrorMessage: "unreachable"})
+	}}
+	client := server.serve(t)
+
+	var chunks []map[string]interface{}
+	err := client.InferStream(context.Background(), "llama", "1", map[string]interface{}{
+		"text_input": "Hi",
+		"parameters": map[string]interface{}{"max_tokens": 8.0, "temperature": 0.5},
+	}, func(chunk map[string]interface{}) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"model_name": "llama", "model_version": "1", "text_output": "Hel"},
+		{"model_name": "llama", "model_version": "1", "text_output": "lo"},
+	}, chunks)
+}
+
+func TestClient_InferStreamFails(t *testing.T) {
+	server := &fakeServer{stream: func(req *kservepb.ModelInferRequest, send func(*kservepb.ModelStreamInferResponse) error) error {
+		return send(&kservepb.ModelStreamInferResponse{ErrorMessage: "model is not decoupled"})
+	}}
+	client := server.serve(t)
+
+	err := client.InferStream(context.Background(), "resnet18", "1", map[string]interface{}{"x": 1.0}, func(map[string]interface{}) error {
+		t.Fatal("nothing is emitted")
+		return nil
+	})
+	var failure *apperrors.Error
+	require.ErrorAs(t, err, &failure)
+	assert.Equal(t, apperrors.Internal, failure.Code)
+	assert.Equal(t, "model is not decoupled", failure.Details)
+
+	server.stream = func(*kservepb.ModelInferRequest, func(*kservepb.ModelStreamInferResponse) error) error {
+		return status.Error(codes.NotFound, "unknown model")
+	}
+	err = client.InferStream(context.Background(), "missing", "1", map[string]interface{}{"x": 1.0}, nil)
+	assert.True(t, apperrors.Is(err, apperrors.NotFound))
+}
+
+func TestClient_MapsStatus(t *testing.T) {
+	client := (&fakeServer{infer: func(context.Context, *kservepb.ModelInferRequest) (*kservepb.ModelInferResponse, error) {
+		return nil, status.Error(codes.ResourceExhausted, "queue full")
+	}}).serve(t)
+
+	_, err := client.Infer(context.Background(), "resnet18", "1", map[string]interface{}{"x": 1.0})
+	assert.True(t, apperrors.Is(err, apperrors.ResourceExhausted))
+}
+
+func TestClient_Ready(t *testing.T) {
+	server := &fakeServer{}
+	client := server.serve(t)
+	assert.True(t, apperrors.Is(client.Ready(context.Background()), apperrors.Unavailable))
+
+	server.ready = true
+	assert.NoError(t, client.Ready(context.Background()))
+}
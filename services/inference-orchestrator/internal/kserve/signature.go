@@ -0,0 +1,159 @@
+package kserve
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/kservepb"
+)
+
+// Signature is the input a model is registered to take: the name and
+// datatype of its input tensor, either empty to keep the request's, and its
+// shape, in which -1 marks a dimension of any size
+type Signature struct {
+	Name     string
+	Datatype string
+	Shape    []int64
+}
+
+// IsZero reports whether the signature describes nothing to conform to
+func (s Signature) IsZero() bool {
+	return s.Name == "" && s.Datatype == "" && len(s.Shape) == 0
+}
+
+// ParseShape parses a registered shape such as "[1, 3, 224, 224]",
+// "1x3x224x224" or "[-1, 768]", in which -1, "?" and "None" mark dynamic
+// dimensions. An empty shape parses as nil.
+func ParseShape(s string) ([]int64, error) {
+	s = strings.Trim(strings.TrimSpace(s), "[]()")
+	if s == "" {
+		return nil, nil
+	}
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == 'x' || r == 'X' || r == ' '
+	})
+	shape := make([]int64, 0, len(fields))
+	for _, field := range fields {
+		switch field {
+		case "?", "None", "-1":
+			shape = append(shape, -1)
+			continue
+		}
+		dim, err := strconv.ParseInt(field, 10, 64)
+		if err != nil || dim < 0 {
+			return nil, apperrors.Newf(apperrors.InvalidArgument, "invalid dimension %q in shape %q", field, s)
+		}
+		shape = append(shape, dim)
+	}
+	return shape, nil
+}
+
+// Conform returns input with the tensor the signature describes named, typed
+// and shaped as the model takes it. That tensor is the input named like the
+// signature or, failing that, the request's only input; requests with other
+// inputs are returned as they are. The tensor's elements, in row-major
+// order, must fill the signature's shape, a dynamic dimension taking up the
+// rest. Inputs in the KServe v2 JSON form that declare a shape keep it. input
+// is not modified.
+func Conform(input map[string]interface{}, sig Signature) (map[string]interface{}, error) {
+	if sig.IsZero() {
+		return input, nil
+	}
+	key := sig.Name
+	if _, ok := input[key]; !ok {
+		if len(input) != 1 {
+			return input, nil
+		}
+		for name := range input {
+			key = name
+		}
+	}
+	name := key
+	if sig.Name != "" {
+		name = sig.Name
+	}
+
+	v, err := kservepb.Plain(input[key])
+	if err != nil {
+		return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q: %v", key, err)
+	}
+	conformed := map[string]interface{}{}
+	if object, ok := v.(map[string]interface{}); ok {
+		for k, field := range object {
+			conformed[k] = field
+		}
+		v = object["data"]
+	}
+	data, own, err := kservepb.Flatten(v)
+	if err != nil {
+		return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q: %v", key, err)
+	}
+	conformed["data"] = data
+
+	if _, ok := conformed["datatype"]; !ok {
+		datatype := sig.Datatype
+		if datatype == "" {
+			datatype = kservepb.DatatypeOf(data)
+		}
+		conformed["datatype"] = datatype
+	}
+	if _, ok := conformed["shape"]; !ok {
+		shape, ok := resolve(sig.Shape, int64(len(data)), own)
+		if !ok {
+			return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q has %d elements, which do not fit the model's shape %v", key, len(data), sig.Shape)
+		}
+		dims := make([]interface{}, len(shape))
+		for i, dim := range shape {
+			dims[i] = float64(dim)
+		}
+		conformed["shape"] = dims
+	}
+
+	out := make(map[string]interface{}, len(input))
+	for k, field := range input {
+		if k != key {
+			out[k] = field
+		}
+	}
+	out[name] = conformed
+	return out, nil
+}
+
+// resolve returns the shape n elements take under the registered shape, and
+// false when they do not fit it; own is the shape they were sent in
+func resolve(registered []int64, n int64, own []int64) ([]int64, bool) {
+	if len(registered) == 0 {
+		return own, true
+	}
+
+	fixed, dynamic := int64(1), 0
+	for _, dim := range registered {
+		if dim < 0 {
+			dynamic++
+		} else {
+			fixed *= dim
+		}
+	}
+	shape := append([]int64(nil), registered...)
+	switch {
+	case dynamic == 0 && fixed == n:
+		return shape, true
+	case dynamic == 1 && fixed > 0 && n%fixed == 0:
+		for i, dim := range shape {
+			if dim < 0 {
+				shape[i] = n / fixed
+			}
+		}
+		return shape, true
+	case dynamic > 1 && len(own) == len(registered):
+		// Several dynamic dimensions are only known from the input's nesting
+		for i, dim := range registered {
+			if dim >= 0 && own[i] != dim {
+				return nil, false
+			}
+		}
+		return own, true
+	}
+	return nil, false
+}
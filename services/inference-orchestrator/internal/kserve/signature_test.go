@@ -0,0 +1,109 @@
+package kserve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/kservepb"
+)
+
+func TestParseShape(t *testing.T) {
+	for s, want := range map[string][]int64{
+		"[1, 3, 224, 224]": {1, 3, 224, 224},
+		"1x3x224x224":      {1, 3, 224, 224},
+		"[-1, 768]":        {-1, 768},
+		"(None, 10)":       {-1, 10},
+		"[?]":              {-1},
+		"":                 nil,
+	} {
+		shape, err := ParseShape(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, shape, s)
+	}
+
+	_, err := ParseShape("[1, three]")
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+}
+
+func TestConform(t *testing.T) {
+	sig := Signature{Name: "input__0", Datatype: "FP64", Shape: []int64{-1, 2, 2}}
+
+	// A flat array sent under any name fills the registered shape
+	conformed, err := Conform(map[string]interface{}{"x": []interface{}{1.0, 2.0, 3.0, 4.0, 5.0, 6.0, 7.0, 8.0}}, sig)
+	require.NoError(t, err)
+	inputs, err := kservepb.Inputs(conformed)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{
+		"name":     "input__0",
+		"datatype": "FP64",
+		"shape":    []int64{2, 2, 2},
+		"data":     []interface{}{1.0, 2.0, 3.0, 4.0, 5.0, 6.0, 7.0, 8.0},
+	}}, inputs)
+
+	// Declared shapes and datatypes are kept
+	declared := map[string]interface{}{"datatype": "FP32", "shape": []interface{}{4.0}, "data": []interface{}{1.0, 2.0, 3.0, 4.0}}
+	conformed, err = Conform(map[string]interface{}{"x": declared}, sig)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"input__0": declared}, conformed)
+
+	// Requests with several inputs none of which the signature names are
+	// left to Triton
+	several := map[string]interface{}{"a": 1.0, "b": 2.0}
+	conformed, err = Conform(several, sig)
+	require.NoError(t, err)
+	assert.Equal(t, several, conformed)
+
+	_, err = Conform(map[string]interface{}{"x": []interface{}{1.0, 2.0, 3.0}}, sig)
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+}
+
+func TestConform_DecodedTensor(t *testing.T) {
+	type decoded struct {
+		Shape    []int       `json:"shape"`
+		Datatype string      `json:"datatype"`
+		Data     interface{} `json:"data"`
+	}
+	conformed, err := Conform(map[string]interface{}{
+		"image": decoded{Shape: []int{1, 2, 3}, Datatype: "UINT8", Data: []int{1, 2, 3, 4, 5, 6}},
+	}, Signature{Shape: []int64{-1, -1, 3}})
+	require.NoError(t, err)
+
+	inputs, err := kservepb.Inputs(conformed)
+	require.NoError(t, err)
+	require.Len(t, inputs, 1)
+	assert.Equal(t, "UINT8", inputs[0]["datatype"])
+	assert.Equal(t, []int64{1, 2, 3}, inputs[0]["shape"])
+}
+
+func TestValues(t *testing.T) {
+	values, err := Values([]interface{}{
+		// As decoded from Triton's HTTP API
+		map[string]interface{}{
+			"name": "logits", "datatype": "FP32",
+			"shape": []interface{}{2.0, 2.0}, "data": []interface{}{0.1, 0.9, 0.7, 0.3},
+		},
+		map[string]interface{}{
+			"name": "labels", "datatype": "INT64",
+			"shape": []interface{}{2.0}, "data": []interface{}{1.0, 0.0},
+		},
+		// As decoded from gRPC
+		map[string]interface{}{
+			"name": "found", "datatype": "BOOL",
+			"shape": []int64{}, "data": []interface{}{true},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"logits": []interface{}{[]interface{}{0.1, 0.9}, []interface{}{0.7, 0.3}},
+		"labels": []interface{}{int64(1), int64(0)},
+		"found":  true,
+	}, values)
+
+	_, err = Values([]interface{}{map[string]interface{}{
+		"name": "logits", "datatype": "FP32", "shape": []interface{}{3.0}, "data": []interface{}{0.1},
+	}})
+	assert.Error(t, err)
+}
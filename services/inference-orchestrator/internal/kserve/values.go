@@ -0,0 +1,105 @@
+package kserve
+
+import (
+	"fmt"
+	"math"
+)
+
+// Values returns the output tensors of a response, in the KServe v2 JSON
+// form, by name, each nested to its shape with elements typed by its
+// datatype: signed integers as int64, unsigned as uint64, floating point as
+// float64, BOOL as bool and BYTES as string. Outputs whose data does not fill
+// their shape are errors.
+func Values(outputs []interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(outputs))
+	for _, output := range outputs {
+		object, ok := output.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("output %v is not a tensor", output)
+		}
+		name, _ := object["name"].(string)
+		datatype, _ := object["datatype"].(string)
+		shape, err := dims(object["shape"])
+		if err != nil {
+			return nil, fmt.Errorf("output %q: %w", name, err)
+		}
+		data, _ := object["data"].([]interface{})
+
+		n := int64(1)
+		for _, dim := range shape {
+			n *= dim
+		}
+		if int64(len(data)) != n {
+			return nil, fmt.Errorf("output %q has %d elements, not the %d of shape %v", name, len(data), n, shape)
+		}
+		typed := make([]interface{}, len(data))
+		for i, v := range data {
+			if typed[i], err = typedElement(datatype, v); err != nil {
+				return nil, fmt.Errorf("output %q: %w", name, err)
+			}
+		}
+		values[name] = nest(typed, shape)
+	}
+	return values, nil
+}
+
+// dims reads a shape sent as []int64, by gRPC, or as JSON numbers
+func dims(v interface{}) ([]int64, error) {
+	switch shape := v.(type) {
+	case []int64:
+		return shape, nil
+	case []interface{}:
+		out := make([]int64, len(shape))
+		for i, dim := range shape {
+			d, ok := dim.(float64)
+			if !ok || d < 0 || d != math.Trunc(d) {
+				return nil, fmt.Errorf("invalid shape %v", v)
+			}
+			out[i] = int64(d)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("invalid shape %v", v)
+}
+
+// typedElement converts an element decoded from JSON, where every number is
+// a float64, to the Go type of its datatype; elements decoded from raw
+// contents already have it
+func typedElement(datatype string, v interface{}) (interface{}, error) {
+	f, isNumber := v.(float64)
+	switch datatype {
+	case "BOOL":
+		if isNumber {
+			return f != 0, nil
+		}
+	case "INT8", "INT16", "INT32", "INT64":
+		if isNumber {
+			return int64(f), nil
+		}
+	case "UINT8", "UINT16", "UINT32", "UINT64":
+		if isNumber {
+			if f < 0 {
+				return nil, fmt.Errorf("%v is not unsigned", f)
+			}
+			return uint64(f), nil
+		}
+	}
+	return v, nil
+}
+
+// nest arranges row-major data in nested arrays of shape; data of an empty
+// shape is a scalar
+func nest(data []interface{}, shape []int64) interface{} {
+	if len(shape) == 0 {
+		return data[0]
+	}
+	if len(shape) == 1 || shape[0] == 0 {
+		return data
+	}
+	stride := len(data) / int(shape[0])
+	nested := make([]interface{}, shape[0])
+	for i := range nested {
+		nested[i] = nest(data[i*stride:(i+1)*stride], shape[1:])
+	}
+	return nested
+}
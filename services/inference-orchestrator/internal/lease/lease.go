@@ -0,0 +1,152 @@
+// Package lease registers the orchestrator with the model router as a
+// backend of the model versions it serves, and renews the leases that keep
+// it in the routing table until it shuts down.
+package lease
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Path is the model router's backend lease endpoint
+const Path = "/v1/backends"
+
+// backendRequest is the body of the router's lease endpoint
+type backendRequest struct {
+	Model      string `json:"model"`
+	Version    string `json:"version"`
+	URL        string `json:"url"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+	Class      string `json:"class,omitempty"`
+}
+
+// Version is a model version the orchestrator serves
+type Version struct {
+	Model   string
+	Version string
+}
+
+// ParseVersions parses "model/version" pairs
+func ParseVersions(pairs []string) ([]Version, error) {
+	versions := make([]Version, 0, len(pairs))
+	for _, pair := range pairs {
+		model, version, ok := strings.Cut(strings.TrimSpace(pair), "/")
+		if !ok || model == "" || version == "" {
+			return nil, fmt.Errorf("invalid model version %q; expected model/version", pair)
+		}
+		versions = append(versions, Version{Model: model, Version: version})
+	}
+	return versions, nil
+}
+
+// Heartbeat keeps the orchestrator registered with the model router at url
+// for each of its versions
+type Heartbeat struct {
+	routerURL string
+	url       string
+	class     string
+	versions  []Version
+	ttl       time.Duration
+	client    *http.Client
+	logger    *zap.Logger
+}
+
+// NewHeartbeat creates a heartbeat that registers url with the router at
+// routerURL as a backend of versions, each lease lasting ttl
+func NewHeartbeat(logger *zap.Logger, client *http.Client, routerURL, url string, versions []Version, ttl time.Duration) *Heartbeat {
+	return &Heartbeat{
+		routerURL: strings.TrimSuffix(routerURL, "/"),
+		url:       url,
+		versions:  versions,
+		ttl:       ttl,
+		client:    client,
+		logger:    logger,
+	}
+}
+
+// SetClass registers the backend under a cost class, gpu or cpu
+func (h *Heartbeat) SetClass(class string) {
+	h.class = class
+}
+
+// Run renews the leases every third of their TTL until ctx is done, so that
+// a lost heartbeat or two does not let them lapse. Failed renewals are
+// logged and retried on the next tick.
+func (h *Heartbeat) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.ttl / 3)
+	defer ticker.Stop()
+	for {
+		if err := h.Renew(ctx); err != nil && ctx.Err() == nil {
+			h.logger.Warn("failed to renew backend lease", zap.String("router", h.routerURL), zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Renew registers the backend for every version, or renews its leases
+func (h *Heartbeat) Renew(ctx context.Context) error {
+	for _, v := range h.versions {
+		if err := h.send(ctx, http.MethodPut, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unregister removes the backend from the routing table, so that the router
+// stops sending it requests before its leases lapse
+func (h *Heartbeat) Unregister(ctx context.Context) error {
+	var firstErr error
+	for _, v := range h.versions {
+		if err := h.send(ctx, http.MethodDelete, v); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (h *Heartbeat) send(ctx context.Context, method string, v Version) error {
+	body, err := json.Marshal(backendRequest{
+		Model:      v.Model,
+		Version:    v.Version,
+		URL:        h.url,
+		TTLSeconds: int(h.ttl / time.Second),
+		Class:      h.class,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal backend: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, h.routerURL+Path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "model-router")
+	}
+	defer resp.Body.Close()
+	// A backend the router has already dropped needs no unregistering
+	if method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		return apperrors.FromHTTPResponse(resp, "model-router")
+	}
+	return nil
+}
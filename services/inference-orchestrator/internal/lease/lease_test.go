@@ -0,0 +1,102 @@
+package lease
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeRouter records the backend requests it receives by method
+type fakeRouter struct {
+	mu       sync.Mutex
+	requests map[string][]backendRequest
+}
+
+func newFakeRouter(t *testing.T) (*fakeRouter, *httptest.Server) {
+	router := &fakeRouter{requests: make(map[string][]backendRequest)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, Path, r.URL.Path)
+		var req backendRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		router.mu.Lock()
+		router.requests[r.Method] = append(router.requests[r.Method], req)
+		router.mu.Unlock()
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+	return router, server
+}
+
+func (r *fakeRouter) received(method string) []backendRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]backendRequest(nil), r.requests[method]...)
+}
+
+func TestParseVersions(t *testing.T) {
+	versions, err := ParseVersions([]string{"resnet18/1", " bert/2"})
+	require.NoError(t, err)
+	assert.Equal(t, []Version{{Model: "resnet18", Version: "1"}, {Model: "bert", Version: "2"}}, versions)
+
+	_, err = ParseVersions([]string{"resnet18"})
+	assert.Error(t, err)
+}
+
+func TestHeartbeat_RenewsUntilStopped(t *testing.T) {
+	router, server := newFakeRouter(t)
+	versions := []Version{{Model: "resnet18", Version: "1"}, {Model: "bert", Version: "2"}}
+	heartbeat := NewHeartbeat(zap.NewNop(), server.Client(), server.URL+"/", "http://orchestrator-0:8082", versions, 30*time.Millisecond)
+	heartbeat.SetClass("gpu")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		heartbeat.Run(ctx)
+		close(done)
+	}()
+
+	// A lease lasting 30ms is renewed every 10ms
+	assert.Eventually(t, func() bool { return len(router.received(http.MethodPut)) >= 6 }, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+
+	renewal := router.received(http.MethodPut)[0]
+	assert.Equal(t, backendRequest{Model: "resnet18", Version: "1", URL: "http://orchestrator-0:8082", Class: "gpu"}, renewal)
+
+	require.NoError(t, heartbeat.Unregister(context.Background()))
+	unregistered := router.received(http.MethodDelete)
+	require.Len(t, unregistered, 2)
+	assert.Equal(t, "bert", unregistered[1].Model)
+}
+
+func TestHeartbeat_SendsTTLInSeconds(t *testing.T) {
+	router, server := newFakeRouter(t)
+	heartbeat := NewHeartbeat(zap.NewNop(), server.Client(), server.URL, "http://orchestrator-0:8082", []Version{{Model: "resnet18", Version: "1"}}, 30*time.Second)
+
+	require.NoError(t, heartbeat.Renew(context.Background()))
+	assert.Equal(t, 30, router.received(http.MethodPut)[0].TTLSeconds)
+}
+
+func TestHeartbeat_UnregisterIgnoresDroppedBackends(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	heartbeat := NewHeartbeat(zap.NewNop(), server.Client(), server.URL, "http://orchestrator-0:8082", []Version{{Model: "resnet18", Version: "1"}}, 30*time.Second)
+
+	assert.NoError(t, heartbeat.Unregister(context.Background()))
+	assert.Error(t, heartbeat.Renew(context.Background()))
+}
@@ -0,0 +1,179 @@
+// Package metadata reads the registered description of model versions from
+// the metadata service: the shape of their inputs and outputs, and the
+// key-value metadata that tunes how the orchestrator runs them.
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/kserve"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Model metadata keys naming the input tensor, and its datatype, that the
+// single input of a request to the model is sent as, for callers that do not
+// know the model's input by name or send numbers to a model that does not
+// take FP32
+const (
+	InputNameKey     = "input_name"
+	InputDatatypeKey = "input_datatype"
+)
+
+// TimeoutKey is the model metadata key giving how long an inference of the
+// version may take, as a duration such as "5s"
+const TimeoutKey = "timeout"
+
+// CacheTTLKey is the model metadata key giving how long the version's
+// results are cached, as a duration such as "24h"; versions without it, as
+// any nondeterministic one should be, are not cached
+const CacheTTLKey = "cache_ttl"
+
+// Model is a model version as registered with the metadata service
+type Model struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	Framework   string            `json:"framework"`
+	Format      string            `json:"format"`
+	InputShape  string            `json:"input_shape"`
+	OutputShape string            `json:"output_shape"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Signature returns the input the model version is registered to take
+func (m *Model) Signature() (kserve.Signature, error) {
+	shape, err := kserve.ParseShape(m.InputShape)
+	if err != nil {
+		return kserve.Signature{}, err
+	}
+	return kserve.Signature{
+		Name:     m.Metadata[InputNameKey],
+		Datatype: m.Metadata[InputDatatypeKey],
+		Shape:    shape,
+	}, nil
+}
+
+// Timeout returns how long an inference of the model version may take, or
+// 0 when it is not registered with a timeout
+func (m *Model) Timeout() time.Duration {
+	if m == nil {
+		return 0
+	}
+	timeout, err := time.ParseDuration(m.Metadata[TimeoutKey])
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// CacheTTL returns how long the model version's results are cached, or 0
+// when they are not
+func (m *Model) CacheTTL() time.Duration {
+	if m == nil {
+		return 0
+	}
+	ttl, err := time.ParseDuration(m.Metadata[CacheTTLKey])
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+// Client reads model versions from the metadata service and caches them,
+// including the absence of one, so each inference does not cost a lookup
+type Client struct {
+	baseURL string
+	client  *http.Client
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	model     *Model
+	fetchedAt time.Time
+}
+
+// NewClient creates a client for the metadata service at baseURL that caches
+// model versions for ttl
+func NewClient(baseURL string, client *http.Client, ttl time.Duration) *Client {
+	return &Client{
+		baseURL: baseURL,
+		client:  client,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]entry),
+	}
+}
+
+// Model returns a model version, or nil when none is registered. A cached
+// model is served past its TTL while the metadata service is unreachable.
+func (c *Client) Model(ctx context.Context, name, version string) (*Model, error) {
+	key := name + ":" + version
+
+	c.mu.Lock()
+	cached, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Sub(cached.fetchedAt) < c.ttl {
+		return cached.model, nil
+	}
+
+	model, err := c.fetch(ctx, name, version)
+	if err != nil {
+		if ok {
+			return cached.model, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = entry{model: model, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return model, nil
+}
+
+// CacheTTL returns how long the results of a model version are cached, or 0
+// when they are not or the version cannot be looked up
+func (c *Client) CacheTTL(ctx context.Context, name, version string) time.Duration {
+	model, err := c.Model(ctx, name, version)
+	if err != nil {
+		return 0
+	}
+	return model.CacheTTL()
+}
+
+func (c *Client) fetch(ctx context.Context, name, version string) (*Model, error) {
+	endpoint := fmt.Sprintf("%s/v1/models/by-name/%s/%s", c.baseURL, url.PathEscape(name), url.PathEscape(version))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	logging.Inject(ctx, req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, "metadata-service")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, apperrors.FromHTTPResponse(resp, "metadata-service")
+	}
+
+	var model Model
+	if err := json.NewDecoder(resp.Body).Decode(&model); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata-service response: %w", err)
+	}
+	return &model, nil
+}
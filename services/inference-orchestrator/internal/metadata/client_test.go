@@ -0,0 +1,79 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Model_CachesResults(t *testing.T) {
+	var requests int
+	available := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case !available:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/v1/models/by-name/resnet/v1":
+			json.NewEncoder(w).Encode(Model{Name: "resnet", Version: "v1", InputShape: "[1, 3, 224, 224]"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	client := NewClient(server.URL, server.Client(), time.Minute)
+	client.now = func() time.Time { return now }
+
+	model, err := client.Model(context.Background(), "resnet", "v1")
+	require.NoError(t, err)
+	assert.Equal(t, "[1, 3, 224, 224]", model.InputShape)
+
+	missing, err := client.Model(context.Background(), "bert", "v1")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	client.Model(context.Background(), "resnet", "v1")
+	client.Model(context.Background(), "bert", "v1")
+	assert.Equal(t, 2, requests)
+
+	// Stale models are served while the metadata service is unavailable
+	available = false
+	now = now.Add(2 * time.Minute)
+	model, err = client.Model(context.Background(), "resnet", "v1")
+	require.NoError(t, err)
+	assert.Equal(t, "resnet", model.Name)
+
+	_, err = client.Model(context.Background(), "gpt", "v1")
+	assert.Error(t, err)
+}
+
+func TestModel_Signature(t *testing.T) {
+	model := &Model{
+		InputShape: "[-1, 768]",
+		Metadata:   map[string]string{InputNameKey: "embeddings", InputDatatypeKey: "FP64"},
+	}
+	sig, err := model.Signature()
+	require.NoError(t, err)
+	assert.Equal(t, "embeddings", sig.Name)
+	assert.Equal(t, "FP64", sig.Datatype)
+	assert.Equal(t, []int64{-1, 768}, sig.Shape)
+
+	_, err = (&Model{InputShape: "[1, three]"}).Signature()
+	assert.Error(t, err)
+}
+
+func TestModel_Timeout(t *testing.T) {
+	var unregistered *Model
+	assert.Equal(t, time.Duration(0), unregistered.Timeout())
+	assert.Equal(t, time.Duration(0), (&Model{}).Timeout())
+	assert.Equal(t, time.Duration(0), (&Model{Metadata: map[string]string{TimeoutKey: "soon"}}).Timeout())
+	assert.Equal(t, 90*time.Second, (&Model{Metadata: map[string]string{TimeoutKey: "90s"}}).Timeout())
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+func TestTracing_ContinuesCallerTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	var fields logging.Fields
+	engine := gin.New()
+	engine.Use(Tracing())
+	engine.POST("/v1/infer", func(c *gin.Context) {
+		fields = logging.FieldsFromContext(c.Request.Context())
+	})
+
+	const traceID, callerSpan = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	req := httptest.NewRequest(http.MethodPost, "/v1/infer", nil)
+	req.Header.Set(logging.HeaderTraceParent, "00-"+traceID+"-"+callerSpan+"-01")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "POST /v1/infer", spans[0].Name())
+	assert.Equal(t, traceID, spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, callerSpan, spans[0].Parent().SpanID().String())
+	assert.Equal(t, traceID, fields.TraceID)
+	assert.Equal(t, spans[0].SpanContext().SpanID().String(), fields.SpanID, "model servers are called under the orchestrator's span")
+}
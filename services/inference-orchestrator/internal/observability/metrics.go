@@ -0,0 +1,119 @@
+package observability
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// QueueWait tracks how long inferences waited for a slot on Triton
+	QueueWait = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "inference_queue_wait_seconds",
+			Help:    "Time inference requests waited in the orchestrator's queue, by priority class",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		},
+		[]string{"priority"},
+	)
+
+	// QueueDepth tracks the inferences waiting for a slot
+	QueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "inference_queue_depth",
+			Help: "Inference requests waiting in the orchestrator's queue, by priority class",
+		},
+		[]string{"priority"},
+	)
+
+	// QueueRejected counts inferences turned away by the queue
+	QueueRejected = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_queue_rejected_total",
+			Help: "Total number of inference requests the orchestrator's queue turned away, by priority class",
+		},
+		[]string{"priority", "reason"},
+	)
+
+	// TritonFallbacks counts inferences sent over HTTP because Triton's
+	// gRPC API was unavailable
+	TritonFallbacks = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_triton_http_fallbacks_total",
+			Help: "Total number of inferences sent to Triton over HTTP because its gRPC API was unavailable, by model",
+		},
+		[]string{"model"},
+	)
+
+	// BatchSize tracks how many requests each batch sent to Triton coalesced
+	BatchSize = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "inference_batch_size",
+			Help:    "Requests coalesced into each batched inference, by model",
+			Buckets: []float64{1, 2, 4, 8, 16, 32, 64, 128},
+		},
+		[]string{"model"},
+	)
+
+	// StageDuration tracks the latency of each stage of an ensemble
+	StageDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ensemble_stage_duration_seconds",
+			Help:    "Duration of each ensemble stage's inference, by ensemble and stage",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		},
+		[]string{"ensemble", "stage"},
+	)
+
+	// ResultCacheLookups counts result cache lookups by model and result
+	ResultCacheLookups = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_result_cache_lookups_total",
+			Help: "Total number of inference result cache lookups by result: hit, miss or error",
+		},
+		[]string{"model", "result"},
+	)
+
+	// TritonGPUUtilization re-exports the utilization of Triton's GPUs
+	TritonGPUUtilization = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "triton_gpu_utilization",
+			Help: "Utilization of each of Triton's GPUs between 0 and 1, as last scraped",
+		},
+		[]string{"gpu"},
+	)
+
+	// TritonGPUMemoryUsed re-exports the memory used on Triton's GPUs
+	TritonGPUMemoryUsed = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "triton_gpu_memory_used_bytes",
+			Help: "Memory used on each of Triton's GPUs, as last scraped",
+		},
+		[]string{"gpu"},
+	)
+
+	// TritonQueueTime tracks how long requests waited in Triton's own queue
+	TritonQueueTime = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "triton_queue_seconds",
+			Help: "Average time requests waited in the model server's queue between the last two scrapes, by model version and backend",
+		},
+		[]string{"model", "version", "backend"},
+	)
+
+	// TritonBatchSize tracks the batches Triton executed
+	TritonBatchSize = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "triton_batch_size",
+			Help: "Average inferences per model execution between the last two scrapes, by model version and backend",
+		},
+		[]string{"model", "version", "backend"},
+	)
+
+	// TritonScrapeErrors counts failed scrapes of Triton's metrics
+	TritonScrapeErrors = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "triton_metrics_scrape_errors_total",
+			Help: "Total number of failed scrapes of Triton's metrics endpoint",
+		},
+	)
+)
@@ -0,0 +1,158 @@
+package onnx
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative onnxpb/onnx.proto
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/onnx/onnxpb"
+)
+
+var errMalformed = errors.New("malformed ONNX model")
+
+// graph is the computation graph of an ONNX model, in the form the
+// operators run on
+type graph struct {
+	// opset is the version of the default operator set the model targets
+	opset        int64
+	nodes        []*node
+	initializers map[string]*tensor
+	// inputs are the graph inputs that are not initializers
+	inputs  []string
+	outputs []string
+}
+
+type node struct {
+	op      string
+	domain  string
+	inputs  []string
+	outputs []string
+	attrs   map[string]*attribute
+}
+
+type attribute struct {
+	f      float64
+	i      int64
+	floats []float64
+	ints   []int64
+	t      *tensor
+}
+
+// parseModel decodes a serialized ModelProto. Models using operators that
+// cannot run in-process are errors.
+func parseModel(b []byte) (*graph, error) {
+	var model onnxpb.ModelProto
+	if err := proto.Unmarshal(b, &model); err != nil {
+		return nil, errMalformed
+	}
+	if model.Graph == nil {
+		return nil, fmt.Errorf("model has no graph: %w", errMalformed)
+	}
+
+	g := &graph{initializers: make(map[string]*tensor)}
+	for _, opset := range model.GetOpsetImport() {
+		if domain := opset.GetDomain(); domain == "" || domain == "ai.onnx" {
+			g.opset = opset.GetVersion()
+		}
+	}
+	for _, n := range model.Graph.GetNode() {
+		nd, err := newNode(n)
+		if err != nil {
+			return nil, err
+		}
+		g.nodes = append(g.nodes, nd)
+	}
+	for _, t := range model.Graph.GetInitializer() {
+		initializer, err := newTensor(t)
+		if err != nil {
+			return nil, err
+		}
+		g.initializers[t.GetName()] = initializer
+	}
+	// Models of older IR versions list their initializers as inputs too
+	for _, input := range model.Graph.GetInput() {
+		if _, ok := g.initializers[input.GetName()]; !ok {
+			g.inputs = append(g.inputs, input.GetName())
+		}
+	}
+	for _, output := range model.Graph.GetOutput() {
+		g.outputs = append(g.outputs, output.GetName())
+	}
+
+	for _, n := range g.nodes {
+		if _, ok := operators[n.op]; !ok || (n.domain != "" && n.domain != "ai.onnx") {
+			return nil, fmt.Errorf("operator %s is not supported in-process", qualified(n))
+		}
+	}
+	return g, nil
+}
+
+func qualified(n *node) string {
+	if n.domain == "" {
+		return n.op
+	}
+	return n.domain + "." + n.op
+}
+
+func newNode(n *onnxpb.NodeProto) (*node, error) {
+	nd := &node{
+		op:      n.GetOpType(),
+		domain:  n.GetDomain(),
+		inputs:  n.GetInput(),
+		outputs: n.GetOutput(),
+		attrs:   make(map[string]*attribute),
+	}
+	for _, a := range n.GetAttribute() {
+		attr := &attribute{f: float64(a.GetF()), i: a.GetI(), ints: a.GetInts()}
+		for _, f := range a.GetFloats() {
+			attr.floats = append(attr.floats, float64(f))
+		}
+		if a.T != nil {
+			t, err := newTensor(a.T)
+			if err != nil {
+				return nil, err
+			}
+			attr.t = t
+		}
+		nd.attrs[a.GetName()] = attr
+	}
+	return nd, nil
+}
+
+// newTensor converts a TensorProto with its data inline, in raw or in the
+// typed field its data type uses
+func newTensor(t *onnxpb.TensorProto) (*tensor, error) {
+	name := t.GetName()
+	if t.GetDataLocation() == onnxpb.TensorProto_EXTERNAL {
+		return nil, fmt.Errorf("tensor %q: external data is not supported in-process", name)
+	}
+
+	out := &tensor{dtype: int(t.GetDataType()), shape: t.GetDims()}
+	if t.RawData != nil {
+		var err error
+		if out.data, err = decodeRaw(out.dtype, t.RawData); err != nil {
+			return nil, fmt.Errorf("tensor %q: %w", name, err)
+		}
+	} else {
+		for _, v := range t.GetFloatData() {
+			out.data = append(out.data, float64(v))
+		}
+		for _, v := range t.GetInt32Data() {
+			out.data = append(out.data, float64(v))
+		}
+		for _, v := range t.GetInt64Data() {
+			out.data = append(out.data, float64(v))
+		}
+		out.data = append(out.data, t.GetDoubleData()...)
+		for _, v := range t.GetUint64Data() {
+			out.data = append(out.data, float64(v))
+		}
+	}
+	if int64(len(out.data)) != out.size() {
+		return nil, fmt.Errorf("tensor %q has %d elements, not the %d of shape %v", name, len(out.data), out.size(), out.shape)
+	}
+	return out, nil
+}
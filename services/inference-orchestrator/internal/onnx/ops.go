@@ -0,0 +1,618 @@
+package onnx
+
+import (
+	"fmt"
+	"math"
+)
+
+// operator computes a node's outputs from its inputs, of which optional ones
+// left out are nil. opset is the version of the operator set the model
+// targets, for operators whose defaults changed.
+type operator func(n *node, in []*tensor, opset int64) ([]*tensor, error)
+
+// operators are the ONNX operators small CPU models such as MLPs and linear
+// classifiers are built from
+var operators = map[string]operator{
+	"Identity":  unary(func(x float64) float64 { return x }),
+	"Relu":      unary(func(x float64) float64 { return math.Max(x, 0) }),
+	"Sigmoid":   unary(func(x float64) float64 { return 1 / (1 + math.Exp(-x)) }),
+	"Tanh":      unary(math.Tanh),
+	"Exp":       unary(math.Exp),
+	"Add":       binaryOp(func(a, b float64) float64 { return a + b }),
+	"Sub":       binaryOp(func(a, b float64) float64 { return a - b }),
+	"Mul":       binaryOp(func(a, b float64) float64 { return a * b }),
+	"Div":       div,
+	"LeakyRelu": leakyRelu,
+	"MatMul":    matMul,
+	"Gemm":      gemm,
+	"Softmax":   softmax,
+	"Flatten":   flatten,
+	"Reshape":   reshape,
+	"Squeeze":   squeeze,
+	"Unsqueeze": unsqueeze,
+	"Transpose": transpose,
+	"Concat":    concat,
+	"ArgMax":    argMax,
+	"Cast":      cast,
+}
+
+func (n *node) int(name string, def int64) int64 {
+	if a, ok := n.attrs[name]; ok {
+		return a.i
+	}
+	return def
+}
+
+func (n *node) float(name string, def float64) float64 {
+	if a, ok := n.attrs[name]; ok {
+		return a.f
+	}
+	return def
+}
+
+// needInputs fails unless the node has at least count inputs
+func needInputs(n *node, in []*tensor, count int) error {
+	if len(in) < count {
+		return fmt.Errorf("%s takes %d inputs, not %d", n.op, count, len(in))
+	}
+	for _, t := range in[:count] {
+		if t == nil {
+			return fmt.Errorf("%s is missing a required input", n.op)
+		}
+	}
+	return nil
+}
+
+// axis resolves a possibly negative axis of a tensor of rank dimensions
+func axis(a int64, rank int) (int, error) {
+	if a < 0 {
+		a += int64(rank)
+	}
+	if a < 0 || a >= int64(rank) {
+		return 0, fmt.Errorf("axis %d is out of range for rank %d", a, rank)
+	}
+	return int(a), nil
+}
+
+func product(dims []int64) int64 {
+	n := int64(1)
+	for _, dim := range dims {
+		n *= dim
+	}
+	return n
+}
+
+func unary(f func(float64) float64) operator {
+	return func(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+		if err := needInputs(n, in, 1); err != nil {
+			return nil, err
+		}
+		x := in[0]
+		out := &tensor{dtype: x.dtype, shape: x.shape, data: make([]float64, len(x.data))}
+		for i, v := range x.data {
+			out.data[i] = f(v)
+		}
+		return []*tensor{out}, nil
+	}
+}
+
+func leakyRelu(n *node, in []*tensor, opset int64) ([]*tensor, error) {
+	alpha := n.float("alpha", 0.01)
+	return unary(func(x float64) float64 {
+		if x < 0 {
+			return alpha * x
+		}
+		return x
+	})(n, in, opset)
+}
+
+func binaryOp(f func(a, b float64) float64) operator {
+	return func(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+		if err := needInputs(n, in, 2); err != nil {
+			return nil, err
+		}
+		out, err := elementwise(in[0], in[1], f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", n.op, err)
+		}
+		return []*tensor{out}, nil
+	}
+}
+
+// div divides integers with truncation, as ONNX Runtime does
+func div(n *node, in []*tensor, opset int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 2); err != nil {
+		return nil, err
+	}
+	integer := in[0].integer()
+	return binaryOp(func(a, b float64) float64 {
+		if integer {
+			return math.Trunc(a / b)
+		}
+		return a / b
+	})(n, in, opset)
+}
+
+// broadcastShape returns the shape two tensors broadcast to under
+// numpy-style broadcasting
+func broadcastShape(a, b []int64) ([]int64, error) {
+	rank := len(a)
+	if len(b) > rank {
+		rank = len(b)
+	}
+	shape := make([]int64, rank)
+	for i := range shape {
+		da, db := int64(1), int64(1)
+		if j := i - (rank - len(a)); j >= 0 {
+			da = a[j]
+		}
+		if j := i - (rank - len(b)); j >= 0 {
+			db = b[j]
+		}
+		switch {
+		case da == db || db == 1:
+			shape[i] = da
+		case da == 1:
+			shape[i] = db
+		default:
+			return nil, fmt.Errorf("shapes %v and %v do not broadcast", a, b)
+		}
+	}
+	return shape, nil
+}
+
+// broadcastStrides returns the strides that step through a tensor of shape
+// as a tensor of the given rank steps through the shape it broadcasts to,
+// zero along dimensions it is broadcast over
+func broadcastStrides(shape []int64, rank int) []int64 {
+	strides := make([]int64, rank)
+	stride := int64(1)
+	for i := len(shape) - 1; i >= 0; i-- {
+		if shape[i] != 1 {
+			strides[i+rank-len(shape)] = stride
+		}
+		stride *= shape[i]
+	}
+	return strides
+}
+
+// eachBroadcast calls f with each offset into a tensor of shape and the
+// offsets, under strides sa and sb, of the elements broadcast to it
+func eachBroadcast(shape, sa, sb []int64, f func(i int, ia, ib int64)) {
+	index := make([]int64, len(shape))
+	var ia, ib int64
+	for i := 0; i < int(product(shape)); i++ {
+		f(i, ia, ib)
+		for d := len(shape) - 1; d >= 0; d-- {
+			index[d]++
+			ia += sa[d]
+			ib += sb[d]
+			if index[d] < shape[d] {
+				break
+			}
+			ia -= sa[d] * shape[d]
+			ib -= sb[d] * shape[d]
+			index[d] = 0
+		}
+	}
+}
+
+func elementwise(a, b *tensor, f func(a, b float64) float64) (*tensor, error) {
+	shape, err := broadcastShape(a.shape, b.shape)
+	if err != nil {
+		return nil, err
+	}
+	out := &tensor{dtype: a.dtype, shape: shape, data: make([]float64, product(shape))}
+	sa, sb := broadcastStrides(a.shape, len(shape)), broadcastStrides(b.shape, len(shape))
+	eachBroadcast(shape, sa, sb, func(i int, ia, ib int64) {
+		out.data[i] = f(a.data[ia], b.data[ib])
+	})
+	return out, nil
+}
+
+// matMul multiplies matrices as numpy.matmul does, broadcasting over any
+// leading batch dimensions
+func matMul(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 2); err != nil {
+		return nil, err
+	}
+	a, b := in[0], in[1]
+	aShape, bShape := a.shape, b.shape
+	if len(aShape) == 1 {
+		aShape = []int64{1, aShape[0]}
+	}
+	if len(bShape) == 1 {
+		bShape = []int64{bShape[0], 1}
+	}
+	if len(aShape) < 2 || len(bShape) < 2 {
+		return nil, fmt.Errorf("MatMul of scalars")
+	}
+	rows, inner, cols := aShape[len(aShape)-2], aShape[len(aShape)-1], bShape[len(bShape)-1]
+	if bShape[len(bShape)-2] != inner {
+		return nil, fmt.Errorf("MatMul: shapes %v and %v do not multiply", a.shape, b.shape)
+	}
+
+	batchA, batchB := aShape[:len(aShape)-2], bShape[:len(bShape)-2]
+	batch, err := broadcastShape(batchA, batchB)
+	if err != nil {
+		return nil, fmt.Errorf("MatMul: %w", err)
+	}
+	out := &tensor{dtype: a.dtype, data: make([]float64, product(batch)*rows*cols)}
+	sa, sb := broadcastStrides(batchA, len(batch)), broadcastStrides(batchB, len(batch))
+	eachBroadcast(batch, sa, sb, func(i int, ia, ib int64) {
+		x := a.data[ia*rows*inner:]
+		y := b.data[ib*inner*cols:]
+		z := out.data[int64(i)*rows*cols:]
+		for r := int64(0); r < rows; r++ {
+			for c := int64(0); c < cols; c++ {
+				var sum float64
+				for k := int64(0); k < inner; k++ {
+					sum += x[r*inner+k] * y[k*cols+c]
+				}
+				z[r*cols+c] = sum
+			}
+		}
+	})
+
+	// Vectors promoted to matrices lose the dimension they gained
+	out.shape = append([]int64{}, batch...)
+	if len(a.shape) > 1 {
+		out.shape = append(out.shape, rows)
+	}
+	if len(b.shape) > 1 {
+		out.shape = append(out.shape, cols)
+	}
+	return []*tensor{out}, nil
+}
+
+// gemm computes alpha*A*B + beta*C on matrices, either of A and B transposed
+func gemm(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 2); err != nil {
+		return nil, err
+	}
+	a, b := in[0], in[1]
+	if len(a.shape) != 2 || len(b.shape) != 2 {
+		return nil, fmt.Errorf("Gemm takes matrices, not shapes %v and %v", a.shape, b.shape)
+	}
+	alpha, beta := n.float("alpha", 1), n.float("beta", 1)
+	transA, transB := n.int("transA", 0) != 0, n.int("transB", 0) != 0
+
+	rows, inner := a.shape[0], a.shape[1]
+	if transA {
+		rows, inner = inner, rows
+	}
+	innerB, cols := b.shape[0], b.shape[1]
+	if transB {
+		innerB, cols = cols, innerB
+	}
+	if inner != innerB {
+		return nil, fmt.Errorf("Gemm: shapes %v and %v do not multiply", a.shape, b.shape)
+	}
+
+	at := func(r, k int64) float64 {
+		if transA {
+			return a.data[k*rows+r]
+		}
+		return a.data[r*inner+k]
+	}
+	bt := func(k, c int64) float64 {
+		if transB {
+			return b.data[c*inner+k]
+		}
+		return b.data[k*cols+c]
+	}
+	out := &tensor{dtype: a.dtype, shape: []int64{rows, cols}, data: make([]float64, rows*cols)}
+	for r := int64(0); r < rows; r++ {
+		for c := int64(0); c < cols; c++ {
+			var sum float64
+			for k := int64(0); k < inner; k++ {
+				sum += at(r, k) * bt(k, c)
+			}
+			out.data[r*cols+c] = alpha * sum
+		}
+	}
+
+	if len(in) > 2 && in[2] != nil && beta != 0 {
+		var err error
+		if out, err = elementwise(out, in[2], func(y, c float64) float64 { return y + beta*c }); err != nil {
+			return nil, fmt.Errorf("Gemm: %w", err)
+		}
+		if len(out.shape) != 2 {
+			return nil, fmt.Errorf("Gemm: C does not broadcast to %dx%d", rows, cols)
+		}
+	}
+	return []*tensor{out}, nil
+}
+
+// softmax normalizes along an axis. Before opset 13 the input is treated as
+// a matrix of the dimensions before the axis by those from it.
+func softmax(n *node, in []*tensor, opset int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 1); err != nil {
+		return nil, err
+	}
+	x := in[0]
+	def := int64(-1)
+	if opset < 13 {
+		def = 1
+	}
+	ax, err := axis(n.int("axis", def), len(x.shape))
+	if err != nil {
+		return nil, fmt.Errorf("Softmax: %w", err)
+	}
+
+	// Normalize runs of length elements, stride apart, starting at each of
+	// the outer blocks' offsets
+	outer, length, stride := product(x.shape[:ax]), x.shape[ax], product(x.shape[ax+1:])
+	if opset < 13 {
+		length, stride = product(x.shape[ax:]), 1
+	}
+	out := &tensor{dtype: x.dtype, shape: x.shape, data: make([]float64, len(x.data))}
+	for o := int64(0); o < outer; o++ {
+		for s := int64(0); s < stride; s++ {
+			base := o*length*stride + s
+			max := math.Inf(-1)
+			for i := int64(0); i < length; i++ {
+				max = math.Max(max, x.data[base+i*stride])
+			}
+			var sum float64
+			for i := int64(0); i < length; i++ {
+				e := math.Exp(x.data[base+i*stride] - max)
+				out.data[base+i*stride] = e
+				sum += e
+			}
+			for i := int64(0); i < length; i++ {
+				out.data[base+i*stride] /= sum
+			}
+		}
+	}
+	return []*tensor{out}, nil
+}
+
+func flatten(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 1); err != nil {
+		return nil, err
+	}
+	x := in[0]
+	a := n.int("axis", 1)
+	if a < 0 {
+		a += int64(len(x.shape))
+	}
+	if a < 0 || a > int64(len(x.shape)) {
+		return nil, fmt.Errorf("Flatten: axis %d is out of range for rank %d", a, len(x.shape))
+	}
+	shape := []int64{product(x.shape[:a]), product(x.shape[a:])}
+	return []*tensor{{dtype: x.dtype, shape: shape, data: x.data}}, nil
+}
+
+// reshape takes the new shape from its second input, in which 0 keeps the
+// input's dimension and -1 takes up the rest
+func reshape(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 2); err != nil {
+		return nil, err
+	}
+	x := in[0]
+	shape := make([]int64, len(in[1].data))
+	inferred := -1
+	known := int64(1)
+	for i, v := range in[1].data {
+		dim := int64(v)
+		switch {
+		case dim == 0 && i < len(x.shape):
+			dim = x.shape[i]
+		case dim == -1 && inferred < 0:
+			inferred = i
+			continue
+		case dim < 0:
+			return nil, fmt.Errorf("Reshape: invalid shape %v", in[1].data)
+		}
+		shape[i] = dim
+		known *= dim
+	}
+	if inferred >= 0 {
+		if known == 0 || x.size()%known != 0 {
+			return nil, fmt.Errorf("Reshape: %v does not fit shape %v", x.shape, in[1].data)
+		}
+		shape[inferred] = x.size() / known
+	}
+	if product(shape) != x.size() {
+		return nil, fmt.Errorf("Reshape: %v does not fit shape %v", x.shape, shape)
+	}
+	return []*tensor{{dtype: x.dtype, shape: shape, data: x.data}}, nil
+}
+
+// axesOf returns the axes a node is given, as an input from opset 13 and as
+// an attribute before
+func axesOf(n *node, in []*tensor) []int64 {
+	if len(in) > 1 && in[1] != nil {
+		axes := make([]int64, len(in[1].data))
+		for i, v := range in[1].data {
+			axes[i] = int64(v)
+		}
+		return axes
+	}
+	if a, ok := n.attrs["axes"]; ok {
+		return a.ints
+	}
+	return nil
+}
+
+func squeeze(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 1); err != nil {
+		return nil, err
+	}
+	x := in[0]
+	drop := make(map[int]bool)
+	axes := axesOf(n, in)
+	for _, a := range axes {
+		ax, err := axis(a, len(x.shape))
+		if err != nil || x.shape[ax] != 1 {
+			return nil, fmt.Errorf("Squeeze: cannot squeeze axis %d of %v", a, x.shape)
+		}
+		drop[ax] = true
+	}
+	shape := []int64{}
+	for i, dim := range x.shape {
+		if drop[i] || (len(axes) == 0 && dim == 1) {
+			continue
+		}
+		shape = append(shape, dim)
+	}
+	return []*tensor{{dtype: x.dtype, shape: shape, data: x.data}}, nil
+}
+
+func unsqueeze(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 1); err != nil {
+		return nil, err
+	}
+	x := in[0]
+	rank := len(x.shape) + len(axesOf(n, in))
+	insert := make(map[int]bool)
+	for _, a := range axesOf(n, in) {
+		ax, err := axis(a, rank)
+		if err != nil {
+			return nil, fmt.Errorf("Unsqueeze: %w", err)
+		}
+		insert[ax] = true
+	}
+	shape := make([]int64, 0, rank)
+	rest := x.shape
+	for i := 0; i < rank; i++ {
+		if insert[i] {
+			shape = append(shape, 1)
+		} else if len(rest) > 0 {
+			shape = append(shape, rest[0])
+			rest = rest[1:]
+		}
+	}
+	return []*tensor{{dtype: x.dtype, shape: shape, data: x.data}}, nil
+}
+
+func transpose(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 1); err != nil {
+		return nil, err
+	}
+	x := in[0]
+	rank := len(x.shape)
+	perm := make([]int64, rank)
+	if a, ok := n.attrs["perm"]; ok && len(a.ints) == rank {
+		copy(perm, a.ints)
+	} else {
+		for i := range perm {
+			perm[i] = int64(rank - 1 - i)
+		}
+	}
+
+	strides := broadcastStrides(x.shape, rank)
+	shape := make([]int64, rank)
+	permuted := make([]int64, rank)
+	for i, p := range perm {
+		if p < 0 || int(p) >= rank {
+			return nil, fmt.Errorf("Transpose: invalid permutation %v", perm)
+		}
+		shape[i] = x.shape[p]
+		permuted[i] = strides[p]
+	}
+	out := &tensor{dtype: x.dtype, shape: shape, data: make([]float64, len(x.data))}
+	eachBroadcast(shape, permuted, make([]int64, rank), func(i int, ix, _ int64) {
+		out.data[i] = x.data[ix]
+	})
+	return []*tensor{out}, nil
+}
+
+func concat(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 1); err != nil {
+		return nil, err
+	}
+	first := in[0]
+	ax, err := axis(n.int("axis", 0), len(first.shape))
+	if err != nil {
+		return nil, fmt.Errorf("Concat: %w", err)
+	}
+	shape := append([]int64{}, first.shape...)
+	shape[ax] = 0
+	for _, t := range in {
+		if t == nil || len(t.shape) != len(shape) {
+			return nil, fmt.Errorf("Concat: inputs differ in rank")
+		}
+		for i, dim := range t.shape {
+			if i != ax && dim != first.shape[i] {
+				return nil, fmt.Errorf("Concat: shapes %v and %v differ off axis %d", first.shape, t.shape, ax)
+			}
+		}
+		shape[ax] += t.shape[ax]
+	}
+
+	out := &tensor{dtype: first.dtype, shape: shape, data: make([]float64, 0, product(shape))}
+	outer := product(shape[:ax])
+	for o := int64(0); o < outer; o++ {
+		for _, t := range in {
+			chunk := product(t.shape[ax:])
+			out.data = append(out.data, t.data[o*chunk:(o+1)*chunk]...)
+		}
+	}
+	return []*tensor{out}, nil
+}
+
+// argMax returns the index of the first largest element along an axis
+func argMax(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 1); err != nil {
+		return nil, err
+	}
+	x := in[0]
+	ax, err := axis(n.int("axis", 0), len(x.shape))
+	if err != nil {
+		return nil, fmt.Errorf("ArgMax: %w", err)
+	}
+	outer, length, stride := product(x.shape[:ax]), x.shape[ax], product(x.shape[ax+1:])
+
+	out := &tensor{dtype: typeInt64, data: make([]float64, outer*stride)}
+	for o := int64(0); o < outer; o++ {
+		for s := int64(0); s < stride; s++ {
+			base := o*length*stride + s
+			best := int64(0)
+			for i := int64(1); i < length; i++ {
+				if x.data[base+i*stride] > x.data[base+best*stride] {
+					best = i
+				}
+			}
+			out.data[o*stride+s] = float64(best)
+		}
+	}
+
+	out.shape = append([]int64{}, x.shape...)
+	if n.int("keepdims", 1) != 0 {
+		out.shape[ax] = 1
+	} else {
+		out.shape = append(out.shape[:ax], out.shape[ax+1:]...)
+	}
+	return []*tensor{out}, nil
+}
+
+// cast converts to the data type named by the "to" attribute, truncating
+// toward zero for integer types
+func cast(n *node, in []*tensor, _ int64) ([]*tensor, error) {
+	if err := needInputs(n, in, 1); err != nil {
+		return nil, err
+	}
+	x := in[0]
+	to := int(n.int("to", typeFloat))
+	if _, ok := datatypes[to]; !ok || to == typeString {
+		return nil, fmt.Errorf("Cast to type %d is not supported in-process", to)
+	}
+	out := &tensor{dtype: to, shape: x.shape, data: make([]float64, len(x.data))}
+	for i, v := range x.data {
+		switch {
+		case to == typeBool && v != 0:
+			out.data[i] = 1
+		case to == typeBool:
+			out.data[i] = 0
+		case to == typeFloat:
+			out.data[i] = float64(float32(v))
+		case to == typeDouble:
+			out.data[i] = v
+		default:
+			out.data[i] = math.Trunc(v)
+		}
+	}
+	return []*tensor{out}, nil
+}
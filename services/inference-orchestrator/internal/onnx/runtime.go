@@ -0,0 +1,189 @@
+// Package onnx runs ONNX models for model versions too light to be worth a
+// Triton: either in-process on the CPU, for small models built from common
+// operators, or on an ONNX Runtime server.
+package onnx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/kserve"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/kservepb"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// DefaultMaxModelBytes bounds the size of the models run in-process
+const DefaultMaxModelBytes = 64 << 20
+
+// Runtime runs ONNX models in-process. Each model version is loaded from
+// model.onnx in its directory of a Triton-style repository,
+// <dir>/<model>/<version>/model.onnx, on first use and kept loaded.
+type Runtime struct {
+	logger        *zap.Logger
+	dir           string
+	maxModelBytes int64
+
+	mu     sync.Mutex
+	graphs map[string]*graph
+}
+
+// NewRuntime creates a runtime for the models in the repository at dir,
+// refusing models larger than maxModelBytes
+func NewRuntime(logger *zap.Logger, dir string, maxModelBytes int64) *Runtime {
+	return &Runtime{
+		logger:        logger,
+		dir:           dir,
+		maxModelBytes: maxModelBytes,
+		graphs:        make(map[string]*graph),
+	}
+}
+
+// Infer runs an inference in-process. The input's fields are the model's
+// input tensors by name, in the KServe v2 JSON form or as bare arrays, and
+// the result holds its output tensors in the same form, as "outputs", and
+// as typed arrays nested to their shape by name, as "values".
+func (r *Runtime) Infer(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	start := time.Now()
+	// Versions are numbered in the repository where the platform names them ("v1")
+	version = strings.TrimPrefix(version, "v")
+	g, err := r.load(model, version)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, err := kservepb.Inputs(input)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]*tensor, len(g.initializers)+len(inputs))
+	for name, t := range g.initializers {
+		values[name] = t
+	}
+	for _, in := range inputs {
+		t, err := fromInput(in)
+		if err != nil {
+			return nil, err
+		}
+		values[in["name"].(string)] = t
+	}
+	for _, name := range g.inputs {
+		if values[name] == nil {
+			return nil, apperrors.Newf(apperrors.InvalidArgument, "missing input %q", name)
+		}
+	}
+
+	for _, n := range g.nodes {
+		if err := ctx.Err(); err != nil {
+			return nil, apperrors.FromTransportError(err, "onnx runtime")
+		}
+		in := make([]*tensor, len(n.inputs))
+		for i, name := range n.inputs {
+			// Optional inputs left out have empty names
+			if name != "" {
+				if in[i] = values[name]; in[i] == nil {
+					return nil, apperrors.Newf(apperrors.FailedPrecondition, "model %s: %s reads %q before it is computed", model, n.op, name)
+				}
+			}
+		}
+		out, err := operators[n.op](n, in, g.opset)
+		if err != nil {
+			return nil, apperrors.Wrap(err, apperrors.InvalidArgument, err.Error())
+		}
+		for i, name := range n.outputs {
+			if i < len(out) && name != "" {
+				values[name] = out[i]
+			}
+		}
+	}
+
+	outputs := make([]interface{}, 0, len(g.outputs))
+	for _, name := range g.outputs {
+		t := values[name]
+		if t == nil {
+			return nil, apperrors.Newf(apperrors.FailedPrecondition, "model %s does not compute its output %q", model, name)
+		}
+		outputs = append(outputs, t.output(name))
+	}
+	typed, err := kserve.Values(outputs)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Internal, "invalid model output")
+	}
+
+	logging.With(ctx, r.logger).Info("in-process inference completed",
+		zap.String("model", model),
+		zap.String("version", version),
+		zap.Int64("latency_ms", time.Since(start).Milliseconds()),
+	)
+	return map[string]interface{}{
+		"model_name":    model,
+		"model_version": version,
+		"outputs":       outputs,
+		"values":        typed,
+	}, nil
+}
+
+// load returns a model version's graph, loading it on first use
+func (r *Runtime) load(model, version string) (*graph, error) {
+	for _, part := range []string{model, version} {
+		if part == "" || part != filepath.Base(part) || strings.HasPrefix(part, ".") {
+			return nil, apperrors.Newf(apperrors.InvalidArgument, "invalid model %q version %q", model, version)
+		}
+	}
+	key := model + "/" + version
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if g, ok := r.graphs[key]; ok {
+		return g, nil
+	}
+
+	path := filepath.Join(r.dir, model, version, "model.onnx")
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, apperrors.Newf(apperrors.NotFound, "model %s version %s not found", model, version)
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Internal, "failed to read model")
+	}
+	if info.Size() > r.maxModelBytes {
+		return nil, apperrors.Newf(apperrors.FailedPrecondition, "model %s version %s is too large to run in-process (%d bytes)", model, version, info.Size())
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Internal, "failed to read model")
+	}
+	g, err := parseModel(b)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.FailedPrecondition, fmt.Sprintf("model %s version %s cannot run in-process: %v", model, version, err))
+	}
+	r.graphs[key] = g
+	r.logger.Info("loaded ONNX model",
+		zap.String("model", model),
+		zap.String("version", version),
+		zap.Int("nodes", len(g.nodes)),
+	)
+	return g, nil
+}
+
+// HealthCheck checks that the model repository can be read
+func (r *Runtime) HealthCheck(ctx context.Context) error {
+	info, err := os.Stat(r.dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", r.dir)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package onnx
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/onnx/onnxpb"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// encodeModel encodes a ModelProto of the given opset and graph
+func encodeModel(opset int64, nodes []*onnxpb.NodeProto, initializers []*onnxpb.TensorProto, inputs, outputs []string) []byte {
+	graph := &onnxpb.GraphProto{Node: nodes, Initializer: initializers}
+	for _, name := range inputs {
+		graph.Input = append(graph.Input, &onnxpb.ValueInfoProto{Name: proto.String(name)})
+	}
+	for _, name := range outputs {
+		graph.Output = append(graph.Output, &onnxpb.ValueInfoProto{Name: proto.String(name)})
+	}
+	b, _ := proto.Marshal(&onnxpb.ModelProto{
+		IrVersion:   proto.Int64(8),
+		Graph:       graph,
+		OpsetImport: []*onnxpb.OperatorSetIdProto{{Version: proto.Int64(opset)}},
+	})
+	return b
+}
+
+func encodeNode(op string, inputs, outputs []string, attrs ...*onnxpb.AttributeProto) *onnxpb.NodeProto {
+	return &onnxpb.NodeProto{OpType: proto.String(op), Input: inputs, Output: outputs, Attribute: attrs}
+}
+
+func intAttr(name string, v int64) *onnxpb.AttributeProto {
+	return &onnxpb.AttributeProto{Name: proto.String(name), I: proto.Int64(v)}
+}
+
+// floatTensor builds an FP32 TensorProto with its data in raw or in
+// float_data
+func floatTensor(name string, dims []int64, data []float32, raw bool) *onnxpb.TensorProto {
+	t := &onnxpb.TensorProto{Name: proto.String(name), Dims: dims, DataType: proto.Int32(typeFloat)}
+	if !raw {
+		t.FloatData = data
+		return t
+	}
+	t.RawData = []byte{}
+	for _, v := range data {
+		t.RawData = binary.LittleEndian.AppendUint32(t.RawData, math.Float32bits(v))
+	}
+	return t
+}
+
+// classifier is a linear classifier over three features: softmax(relu(x·W + B))
+// and the index of the likeliest of its two classes
+func classifier() []byte {
+	return encodeModel(13,
+		[]*onnxpb.NodeProto{
+			encodeNode("Gemm", []string{"x", "W", "B"}, []string{"logits"}),
+			encodeNode("Relu", []string{"logits"}, []string{"hidden"}),
+			encodeNode("Softmax", []string{"hidden"}, []string{"probs"}, intAttr("axis", 1)),
+			encodeNode("ArgMax", []string{"probs"}, []string{"label"}, intAttr("axis", 1), intAttr("keepdims", 0)),
+		},
+		[]*onnxpb.TensorProto{
+			floatTensor("W", []int64{3, 2}, []float32{1, 0, 0, 1, 1, -1}, true),
+			floatTensor("B", []int64{2}, []float32{0, 1}, false),
+		},
+		[]string{"x", "W", "B"},
+		[]string{"probs", "label"},
+	)
+}
+
+func repository(t *testing.T, model, version string, b []byte) string {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, model, version), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, model, version, "model.onnx"), b, 0o644))
+	return dir
+}
+
+func TestRuntime_Infer(t *testing.T) {
+	runtime := NewRuntime(zap.NewNop(), repository(t, "classifier", "1", classifier()), DefaultMaxModelBytes)
+
+	result, err := runtime.Infer(context.Background(), "classifier", "v1", map[string]interface{}{
+		"x": []interface{}{[]interface{}{1.0, 2.0, 3.0}, []interface{}{0.0, 0.0, 0.0}},
+	})
+	require.NoError(t, err)
+
+	// Logits [4, 0] and [0, 1]
+	e := math.Exp(1)
+	values := result["values"].(map[string]interface{})
+	probs := values["probs"].([]interface{})
+	assert.InDeltaSlice(t, []interface{}{1 / (1 + math.Exp(-4)), math.Exp(-4) / (1 + math.Exp(-4))}, probs[0], 1e-9)
+	assert.InDeltaSlice(t, []interface{}{1 / (1 + e), e / (1 + e)}, probs[1], 1e-9)
+	assert.Equal(t, []interface{}{int64(0), int64(1)}, values["label"])
+
+	outputs := result["outputs"].([]interface{})
+	require.Len(t, outputs, 2)
+	assert.Equal(t, "FP32", outputs[0].(map[string]interface{})["datatype"])
+	assert.Equal(t, "INT64", outputs[1].(map[string]interface{})["datatype"])
+}
+
+func TestRuntime_Errors(t *testing.T) {
+	unsupported := encodeModel(13, []*onnxpb.NodeProto{encodeNode("Conv", []string{"x", "W"}, []string{"y"})}, nil, []string{"x"}, []string{"y"})
+	dir := repository(t, "classifier", "1", classifier())
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "cnn", "1"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cnn", "1", "model.onnx"), unsupported, 0o644))
+	runtime := NewRuntime(zap.NewNop(), dir, DefaultMaxModelBytes)
+	ctx := context.Background()
+
+	_, err := runtime.Infer(ctx, "missing", "1", map[string]interface{}{"x": 1.0})
+	assert.True(t, apperrors.Is(err, apperrors.NotFound))
+
+	_, err = runtime.Infer(ctx, "..", "1", map[string]interface{}{"x": 1.0})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+
+	_, err = runtime.Infer(ctx, "cnn", "1", map[string]interface{}{"x": 1.0})
+	assert.True(t, apperrors.Is(err, apperrors.FailedPrecondition))
+	assert.Contains(t, err.Error(), "operator Conv is not supported")
+
+	_, err = runtime.Infer(ctx, "classifier", "1", map[string]interface{}{"y": 1.0})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+
+	_, err = runtime.Infer(ctx, "classifier", "1", map[string]interface{}{"x": []interface{}{1.0, 2.0}})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+
+	small := NewRuntime(zap.NewNop(), dir, 16)
+	_, err = small.Infer(ctx, "classifier", "1", map[string]interface{}{"x": 1.0})
+	assert.True(t, apperrors.Is(err, apperrors.FailedPrecondition))
+}
+
+func TestOperators(t *testing.T) {
+	matrix := &tensor{dtype: typeFloat, shape: []int64{2, 3}, data: []float64{1, 2, 3, 4, 5, 6}}
+	run := func(op string, in []*tensor, attrs map[string]*attribute) *tensor {
+		if attrs == nil {
+			attrs = map[string]*attribute{}
+		}
+		out, err := operators[op](&node{op: op, attrs: attrs}, in, 13)
+		require.NoError(t, err, op)
+		return out[0]
+	}
+
+	// Rows broadcast against a matrix
+	sum := run("Add", []*tensor{matrix, {dtype: typeFloat, shape: []int64{3}, data: []float64{10, 20, 30}}}, nil)
+	assert.Equal(t, []float64{11, 22, 33, 14, 25, 36}, sum.data)
+
+	product := run("MatMul", []*tensor{matrix, {dtype: typeFloat, shape: []int64{3}, data: []float64{1, 0, 1}}}, nil)
+	assert.Equal(t, []int64{2}, product.shape)
+	assert.Equal(t, []float64{4, 10}, product.data)
+
+	transposed := run("Transpose", []*tensor{matrix}, nil)
+	assert.Equal(t, []int64{3, 2}, transposed.shape)
+	assert.Equal(t, []float64{1, 4, 2, 5, 3, 6}, transposed.data)
+
+	reshaped := run("Reshape", []*tensor{matrix, {dtype: typeInt64, shape: []int64{2}, data: []float64{-1, 2}}}, nil)
+	assert.Equal(t, []int64{3, 2}, reshaped.shape)
+
+	joined := run("Concat", []*tensor{matrix, matrix}, map[string]*attribute{"axis": {i: 1}})
+	assert.Equal(t, []int64{2, 6}, joined.shape)
+	assert.Equal(t, []float64{1, 2, 3, 1, 2, 3, 4, 5, 6, 4, 5, 6}, joined.data)
+
+	unsqueezed := run("Unsqueeze", []*tensor{matrix, {dtype: typeInt64, shape: []int64{1}, data: []float64{0}}}, nil)
+	assert.Equal(t, []int64{1, 2, 3}, unsqueezed.shape)
+	assert.Equal(t, []int64{2, 3}, run("Squeeze", []*tensor{unsqueezed}, nil).shape)
+
+	_, err := operators["Add"](&node{op: "Add"}, []*tensor{matrix, {dtype: typeFloat, shape: []int64{2}, data: []float64{1, 2}}}, 13)
+	assert.Error(t, err)
+}
@@ -0,0 +1,235 @@
+package onnx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/kserve"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/kservepb"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Server calls the HTTP prediction API of an ONNX Runtime server
+type Server struct {
+	logger     *zap.Logger
+	address    string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewServer creates a client for the ONNX Runtime server at address
+func NewServer(logger *zap.Logger, address string) *Server {
+	return &Server{
+		logger:  logger,
+		address: address,
+		baseURL: "http://" + address,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// tensorJSON is an ONNX TensorProto in its JSON mapping, as the server's
+// PredictRequest and PredictResponse carry tensors
+type tensorJSON struct {
+	Dims       []string  `json:"dims"`
+	DataType   int       `json:"dataType"`
+	RawData    []byte    `json:"rawData,omitempty"`
+	FloatData  []float64 `json:"floatData,omitempty"`
+	DoubleData []float64 `json:"doubleData,omitempty"`
+	Int32Data  []int64   `json:"int32Data,omitempty"`
+	Int64Data  []string  `json:"int64Data,omitempty"`
+	Uint64Data []string  `json:"uint64Data,omitempty"`
+	StringData [][]byte  `json:"stringData,omitempty"`
+}
+
+// Infer runs an inference on the server. The input's fields are the model's
+// input tensors by name, in the KServe v2 JSON form or as bare arrays, and
+// the result holds its output tensors in the same form, as "outputs", and
+// as typed arrays nested to their shape by name, as "values".
+func (s *Server) Infer(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	start := time.Now()
+	version = strings.TrimPrefix(version, "v")
+
+	inputs, err := kservepb.Inputs(input)
+	if err != nil {
+		return nil, err
+	}
+	request := map[string]map[string]tensorJSON{"inputs": {}}
+	for _, in := range inputs {
+		t, err := toJSON(in)
+		if err != nil {
+			return nil, err
+		}
+		request["inputs"][in["name"].(string)] = t
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/models/%s/versions/%s:predict", s.baseURL, url.PathEscape(model), url.PathEscape(version))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	logging.Inject(ctx, req)
+
+	resp, err := logging.ClientFor(ctx, s.httpClient).Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, "onnx runtime")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apperrors.FromHTTPResponse(resp, "onnx runtime")
+	}
+
+	var response struct {
+		Outputs map[string]tensorJSON `json:"outputs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Internal, "invalid response from onnx runtime")
+	}
+
+	names := make([]string, 0, len(response.Outputs))
+	for name := range response.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	outputs := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		output, err := fromJSON(name, response.Outputs[name])
+		if err != nil {
+			return nil, apperrors.Wrap(err, apperrors.Internal, "invalid response from onnx runtime")
+		}
+		outputs = append(outputs, output)
+	}
+	typed, err := kserve.Values(outputs)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Internal, "invalid response from onnx runtime")
+	}
+
+	logging.With(ctx, s.logger).Info("inference completed",
+		zap.String("model", model),
+		zap.Int64("latency_ms", time.Since(start).Milliseconds()),
+	)
+	return map[string]interface{}{
+		"model_name":    model,
+		"model_version": version,
+		"outputs":       outputs,
+		"values":        typed,
+	}, nil
+}
+
+// HealthCheck checks that the server accepts connections; ONNX Runtime
+// servers have no health API
+func (s *Server) HealthCheck(ctx context.Context) error {
+	return health.TCPCheck(s.address)(ctx)
+}
+
+// toJSON converts an input in the KServe v2 JSON form to a TensorProto
+func toJSON(input map[string]interface{}) (tensorJSON, error) {
+	name, _ := input["name"].(string)
+	datatype, _ := input["datatype"].(string)
+	t := tensorJSON{DataType: dataType(datatype)}
+	shape, _ := input["shape"].([]int64)
+	for _, dim := range shape {
+		t.Dims = append(t.Dims, strconv.FormatInt(dim, 10))
+	}
+
+	data, _ := input["data"].([]interface{})
+	if t.DataType == typeString {
+		for _, v := range data {
+			s, ok := v.(string)
+			if !ok {
+				return t, apperrors.Newf(apperrors.InvalidArgument, "input %q: element %v is not a string", name, v)
+			}
+			t.StringData = append(t.StringData, []byte(s))
+		}
+		return t, nil
+	}
+
+	numeric, err := fromInput(input)
+	if err != nil {
+		return t, err
+	}
+	for _, v := range numeric.data {
+		switch t.DataType {
+		case typeFloat:
+			t.FloatData = append(t.FloatData, v)
+		case typeDouble:
+			t.DoubleData = append(t.DoubleData, v)
+		case typeInt64:
+			t.Int64Data = append(t.Int64Data, strconv.FormatInt(int64(v), 10))
+		case typeUint32, typeUint64:
+			t.Uint64Data = append(t.Uint64Data, strconv.FormatUint(uint64(v), 10))
+		default:
+			t.Int32Data = append(t.Int32Data, int64(v))
+		}
+	}
+	return t, nil
+}
+
+// fromJSON converts an output TensorProto to the KServe v2 JSON form
+func fromJSON(name string, t tensorJSON) (map[string]interface{}, error) {
+	if _, ok := datatypes[t.DataType]; !ok {
+		return nil, fmt.Errorf("output %q has unsupported data type %d", name, t.DataType)
+	}
+	shape := make([]int64, len(t.Dims))
+	for i, dim := range t.Dims {
+		d, err := strconv.ParseInt(dim, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("output %q has invalid dims %v", name, t.Dims)
+		}
+		shape[i] = d
+	}
+
+	if t.DataType == typeString {
+		data := make([]interface{}, len(t.StringData))
+		for i, s := range t.StringData {
+			data[i] = string(s)
+		}
+		return map[string]interface{}{"name": name, "datatype": "BYTES", "shape": shape, "data": data}, nil
+	}
+
+	out := &tensor{dtype: t.DataType, shape: shape}
+	switch {
+	case t.RawData != nil:
+		var err error
+		if out.data, err = decodeRaw(t.DataType, t.RawData); err != nil {
+			return nil, fmt.Errorf("output %q: %w", name, err)
+		}
+	case t.FloatData != nil:
+		out.data = t.FloatData
+	case t.DoubleData != nil:
+		out.data = t.DoubleData
+	case t.Int32Data != nil:
+		for _, v := range t.Int32Data {
+			out.data = append(out.data, float64(v))
+		}
+	default:
+		for _, v := range append(t.Int64Data, t.Uint64Data...) {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("output %q has invalid element %q", name, v)
+			}
+			out.data = append(out.data, f)
+		}
+	}
+	return out.output(name), nil
+}
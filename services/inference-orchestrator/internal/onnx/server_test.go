@@ -0,0 +1,69 @@
+package onnx
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestServer_Infer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/models/mnist/versions/1:predict", r.URL.Path)
+
+		var req struct {
+			Inputs map[string]tensorJSON `json:"inputs"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, tensorJSON{Dims: []string{"1", "2"}, DataType: typeFloat, FloatData: []float64{0.5, 1}}, req.Inputs["pixels"])
+
+		var raw []byte
+		for _, v := range []float32{0.25, 0.75} {
+			raw = binary.LittleEndian.AppendUint32(raw, math.Float32bits(v))
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"outputs": map[string]tensorJSON{
+			"probs": {Dims: []string{"1", "2"}, DataType: typeFloat, RawData: raw},
+			"label": {Dims: []string{"1"}, DataType: typeInt64, Int64Data: []string{"1"}},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewServer(zap.NewNop(), server.URL[len("http://"):])
+	result, err := client.Infer(context.Background(), "mnist", "v1", map[string]interface{}{
+		"pixels": []interface{}{[]interface{}{0.5, 1.0}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"label": []interface{}{int64(1)},
+		"probs": []interface{}{[]interface{}{0.25, 0.75}},
+	}, result["values"])
+
+	assert.NoError(t, client.HealthCheck(context.Background()))
+}
+
+func TestServer_MapsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error_code": 400, "error_message": "Missing input"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewServer(zap.NewNop(), server.URL[len("http://"):])
+	_, err := client.Infer(context.Background(), "mnist", "1", map[string]interface{}{"pixels": 1.0})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+	assert.Error(t, NewServer(zap.NewNop(), address).HealthCheck(context.Background()))
+}
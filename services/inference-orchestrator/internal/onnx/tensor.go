@@ -0,0 +1,179 @@
+package onnx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// ONNX TensorProto data types and the KServe v2 datatypes they correspond to
+const (
+	typeFloat  = 1
+	typeUint8  = 2
+	typeInt8   = 3
+	typeUint16 = 4
+	typeInt16  = 5
+	typeInt32  = 6
+	typeInt64  = 7
+	typeString = 8
+	typeBool   = 9
+	typeDouble = 11
+	typeUint32 = 12
+	typeUint64 = 13
+)
+
+var datatypes = map[int]string{
+	typeFloat:  "FP32",
+	typeUint8:  "UINT8",
+	typeInt8:   "INT8",
+	typeUint16: "UINT16",
+	typeInt16:  "INT16",
+	typeInt32:  "INT32",
+	typeInt64:  "INT64",
+	typeString: "BYTES",
+	typeBool:   "BOOL",
+	typeDouble: "FP64",
+	typeUint32: "UINT32",
+	typeUint64: "UINT64",
+}
+
+// dataType returns the ONNX data type of a KServe v2 datatype, or 0
+func dataType(datatype string) int {
+	for t, name := range datatypes {
+		if name == datatype {
+			return t
+		}
+	}
+	return 0
+}
+
+// tensor is a numeric tensor in row-major order. Every element type is
+// computed on as float64, which holds all the integers small models use
+// exactly; dtype records the ONNX type the values stand for.
+type tensor struct {
+	dtype int
+	shape []int64
+	data  []float64
+}
+
+func (t *tensor) size() int64 {
+	n := int64(1)
+	for _, dim := range t.shape {
+		n *= dim
+	}
+	return n
+}
+
+// integer reports whether the tensor holds integers or booleans
+func (t *tensor) integer() bool {
+	return t.dtype != typeFloat && t.dtype != typeDouble
+}
+
+// fromInput converts an input in the KServe v2 JSON form, as built by
+// kservepb.Inputs, to a tensor
+func fromInput(input map[string]interface{}) (*tensor, error) {
+	name, _ := input["name"].(string)
+	datatype, _ := input["datatype"].(string)
+	t := &tensor{dtype: dataType(datatype)}
+	if t.dtype == 0 || t.dtype == typeString {
+		return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q: datatype %s is not supported", name, datatype)
+	}
+	t.shape, _ = input["shape"].([]int64)
+
+	data, _ := input["data"].([]interface{})
+	t.data = make([]float64, len(data))
+	for i, v := range data {
+		switch v := v.(type) {
+		case float64:
+			t.data[i] = v
+		case int64:
+			t.data[i] = float64(v)
+		case uint64:
+			t.data[i] = float64(v)
+		case bool:
+			if v {
+				t.data[i] = 1
+			}
+		default:
+			return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q: element %v is not a number", name, v)
+		}
+	}
+	if int64(len(t.data)) != t.size() {
+		return nil, apperrors.Newf(apperrors.InvalidArgument, "input %q has %d elements, not the %d of shape %v", name, len(t.data), t.size(), t.shape)
+	}
+	return t, nil
+}
+
+// output returns the tensor in the KServe v2 JSON form, with elements typed
+// by its data type
+func (t *tensor) output(name string) map[string]interface{} {
+	data := make([]interface{}, len(t.data))
+	for i, v := range t.data {
+		switch t.dtype {
+		case typeFloat, typeDouble:
+			data[i] = v
+		case typeBool:
+			data[i] = v != 0
+		case typeUint8, typeUint16, typeUint32, typeUint64:
+			data[i] = uint64(v)
+		default:
+			data[i] = int64(v)
+		}
+	}
+	shape := t.shape
+	if shape == nil {
+		shape = []int64{}
+	}
+	return map[string]interface{}{
+		"name":     name,
+		"datatype": datatypes[t.dtype],
+		"shape":    shape,
+		"data":     data,
+	}
+}
+
+// decodeRaw decodes little-endian raw tensor contents of an ONNX data type
+func decodeRaw(dtype int, raw []byte) ([]float64, error) {
+	sizes := map[int]int{
+		typeFloat: 4, typeDouble: 8, typeBool: 1,
+		typeInt8: 1, typeInt16: 2, typeInt32: 4, typeInt64: 8,
+		typeUint8: 1, typeUint16: 2, typeUint32: 4, typeUint64: 8,
+	}
+	size, ok := sizes[dtype]
+	if !ok {
+		return nil, fmt.Errorf("raw data of type %d is not supported", dtype)
+	}
+	if len(raw)%size != 0 {
+		return nil, fmt.Errorf("raw data of %d bytes does not hold elements of %d", len(raw), size)
+	}
+
+	data := make([]float64, len(raw)/size)
+	for i := range data {
+		b := raw[i*size:]
+		switch dtype {
+		case typeFloat:
+			data[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+		case typeDouble:
+			data[i] = math.Float64frombits(binary.LittleEndian.Uint64(b))
+		case typeBool, typeUint8:
+			data[i] = float64(b[0])
+		case typeInt8:
+			data[i] = float64(int8(b[0]))
+		case typeInt16:
+			data[i] = float64(int16(binary.LittleEndian.Uint16(b)))
+		case typeUint16:
+			data[i] = float64(binary.LittleEndian.Uint16(b))
+		case typeInt32:
+			data[i] = float64(int32(binary.LittleEndian.Uint32(b)))
+		case typeUint32:
+			data[i] = float64(binary.LittleEndian.Uint32(b))
+		case typeInt64:
+			data[i] = float64(int64(binary.LittleEndian.Uint64(b)))
+		case typeUint64:
+			data[i] = float64(binary.LittleEndian.Uint64(b))
+		}
+	}
+	return data, nil
+}
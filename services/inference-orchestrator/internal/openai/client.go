@@ -0,0 +1,313 @@
+// Package openai runs generations on LLM servers with an OpenAI-compatible
+// API, such as vLLM and Text Generation Inference, for model versions
+// registered to be served by one.
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/sse"
+)
+
+// Client calls the chat completions and completions APIs of an
+// OpenAI-compatible server
+type Client struct {
+	logger     *zap.Logger
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the server at address, which is a URL or
+// a host:port reached over plain HTTP. Requests carry apiKey as a bearer
+// token unless it is empty.
+func NewClient(logger *zap.Logger, address, apiKey string) *Client {
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	return &Client{
+		logger:  logger,
+		baseURL: strings.TrimSuffix(address, "/"),
+		apiKey:  apiKey,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// choice is a completion choice, or a chunk of one when streamed
+type choice struct {
+	Index   int `json:"index"`
+	Message *struct {
+		Content string `json:"content"`
+	} `json:"message,omitempty"`
+	Delta *struct {
+		Content string `json:"content"`
+	} `json:"delta,omitempty"`
+	Text         string `json:"text"`
+	FinishReason string `json:"finish_reason"`
+}
+
+// content returns the text the choice holds, whichever API it is from
+func (c choice) content() string {
+	switch {
+	case c.Message != nil:
+		return c.Message.Content
+	case c.Delta != nil:
+		return c.Delta.Content
+	}
+	return c.Text
+}
+
+// completion is a response of either API, or a chunk of one when streamed
+type completion struct {
+	Choices []choice               `json:"choices"`
+	Usage   map[string]interface{} `json:"usage,omitempty"`
+	Error   json.RawMessage        `json:"error,omitempty"`
+}
+
+// Infer runs a generation. An input with "messages" is sent to the chat
+// completions API and one with a "prompt" (or a Triton-style "text_input")
+// to the completions API, with its other fields, and those of its
+// "parameters", as the request's sampling parameters. The result holds the
+// first choice's text as "text_output", as Triton's generate extension
+// answers, along with every choice and the token usage. The server serves a
+// single version of each model, so version is only reported back.
+func (c *Client) Infer(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	start := time.Now()
+	logger := logging.With(ctx, c.logger)
+	logger.Info("executing generation on openai backend",
+		zap.String("model", model),
+		zap.String("version", version),
+	)
+
+	path, body, err := request(model, input)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.post(ctx, logging.ClientFor(ctx, c.httpClient), path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response completion
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Internal, "invalid response from openai backend")
+	}
+	if len(response.Choices) == 0 {
+		return nil, apperrors.New(apperrors.Internal, "openai backend returned no choices")
+	}
+
+	choices := make([]interface{}, len(response.Choices))
+	for i, ch := range response.Choices {
+		choices[i] = map[string]interface{}{
+			"index":         ch.Index,
+			"text":          ch.content(),
+			"finish_reason": ch.FinishReason,
+		}
+	}
+
+	logger.Info("generation completed",
+		zap.String("model", model),
+		zap.Int64("latency_ms", time.Since(start).Milliseconds()),
+	)
+	return map[string]interface{}{
+		"model_name":    model,
+		"model_version": strings.TrimPrefix(version, "v"),
+		"text_output":   response.Choices[0].content(),
+		"finish_reason": response.Choices[0].FinishReason,
+		"choices":       choices,
+		"usage":         response.Usage,
+	}, nil
+}
+
+// InferStream runs a generation as Infer does, passing each chunk of text
+// to emit as it is generated, as "text_output" along with the "index" of
+// its choice and, on a choice's last chunk, its "finish_reason". Failures
+// before the first chunk are returned without emitting anything.
+func (c *Client) InferStream(ctx context.Context, model, version string, input map[string]interface{}, emit func(chunk map[string]interface{}) error) error {
+	path, body, err := request(model, input)
+	if err != nil {
+		return err
+	}
+	body["stream"] = true
+
+	// Generations outlive the client timeout; the caller's context bounds them
+	streamClient := *c.httpClient
+	streamClient.Timeout = 0
+	resp, err := c.post(ctx, &streamClient, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	reader := sse.NewReader(resp.Body)
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return apperrors.FromTransportError(err, "openai backend")
+		}
+		if string(bytes.TrimSpace(event.Data)) == "[DONE]" {
+			return nil
+		}
+
+		var chunk completion
+		if err := json.Unmarshal(event.Data, &chunk); err != nil {
+			return fmt.Errorf("failed to decode generation chunk: %w", err)
+		}
+		// Servers report failures mid-generation in a chunk of their own
+		if len(chunk.Error) > 0 {
+			return apperrors.New(apperrors.Internal, "generation failed").WithDetails(message(chunk.Error))
+		}
+		for _, ch := range chunk.Choices {
+			text := ch.content()
+			// Chat streams open with a chunk that only carries the role
+			if text == "" && ch.FinishReason == "" {
+				continue
+			}
+			out := map[string]interface{}{"index": ch.Index, "text_output": text}
+			if ch.FinishReason != "" {
+				out["finish_reason"] = ch.FinishReason
+			}
+			if err := emit(out); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// HealthCheck checks that the server is healthy
+func (c *Client) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/health", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("openai backend not healthy: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// post sends body to the API at path, returning the response when it
+// succeeds
+func (c *Client) post(ctx context.Context, httpClient *http.Client, path string, body map[string]interface{}) (*http.Response, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.InvalidArgument, "input is not valid JSON")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if body["stream"] == true {
+		req.Header.Set("Accept", sse.ContentType)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	logging.Inject(ctx, req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, "openai backend")
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, errorFrom(resp)
+	}
+	return resp, nil
+}
+
+// request returns the API path and body of the request running a
+// generation of model on input
+func request(model string, input map[string]interface{}) (string, map[string]interface{}, error) {
+	body := make(map[string]interface{}, len(input)+1)
+	// Sampling parameters may be nested as Triton's generate extension takes them
+	if parameters, ok := input["parameters"].(map[string]interface{}); ok {
+		for k, v := range parameters {
+			body[k] = v
+		}
+	}
+	for k, v := range input {
+		if k != "parameters" {
+			body[k] = v
+		}
+	}
+	if text, ok := body["text_input"]; ok {
+		delete(body, "text_input")
+		if _, ok := body["prompt"]; !ok {
+			body["prompt"] = text
+		}
+	}
+	// The model is the one requested, and streaming is the caller's choice
+	body["model"] = model
+	delete(body, "stream")
+	delete(body, "stream_options")
+
+	if _, ok := body["messages"].([]interface{}); ok {
+		delete(body, "prompt")
+		return "/v1/chat/completions", body, nil
+	}
+	switch body["prompt"].(type) {
+	case string, []interface{}:
+		return "/v1/completions", body, nil
+	}
+	return "", nil, apperrors.New(apperrors.InvalidArgument, "input must have messages or a prompt")
+}
+
+// errorFrom maps an error response to an apperror, keeping the server's
+// message whichever of the shapes OpenAI, vLLM and TGI answer with it has
+func errorFrom(resp *http.Response) error {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var body struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+	}
+	text := http.StatusText(resp.StatusCode)
+	if err := json.Unmarshal(raw, &body); err == nil {
+		if m := message(body.Error); m != "" {
+			text = m
+		} else if body.Message != "" {
+			text = body.Message
+		}
+	}
+	return apperrors.Wrap(fmt.Errorf("openai backend returned status %d: %s", resp.StatusCode, raw),
+		apperrors.FromHTTPStatus(resp.StatusCode), text)
+}
+
+// message returns the message of an error, which is either an object with
+// a message (OpenAI, vLLM) or the message itself (TGI)
+func message(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var object struct {
+		Message string `json:"message"`
+	}
+	json.Unmarshal(raw, &object)
+	return object.Message
+}
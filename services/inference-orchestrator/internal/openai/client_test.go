@@ -0,0 +1,144 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestInfer_Completion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/completions", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{
+			"model":       "llama",
+			"prompt":      "Once upon a time",
+			"max_tokens":  16.0,
+			"temperature": 0.2,
+		}, body)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []interface{}{map[string]interface{}{"index": 0, "text": " there was", "finish_reason": "length"}},
+			"usage":   map[string]interface{}{"prompt_tokens": 4, "completion_tokens": 16},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(zap.NewNop(), server.URL, "secret")
+	result, err := client.Infer(context.Background(), "llama", "v1", map[string]interface{}{
+		"text_input": "Once upon a time",
+		"parameters": map[string]interface{}{"max_tokens": 16, "temperature": 0.2},
+		"stream":     true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, " there was", result["text_output"])
+	assert.Equal(t, "length", result["finish_reason"])
+	assert.Equal(t, "1", result["model_version"])
+	assert.Equal(t, 16.0, result["usage"].(map[string]interface{})["completion_tokens"])
+}
+
+func TestInfer_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "llama", body["model"])
+		assert.Len(t, body["messages"], 1)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []interface{}{map[string]interface{}{
+				"index":         0,
+				"message":       map[string]interface{}{"role": "assistant", "content": "Hello!"},
+				"finish_reason": "stop",
+			}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(zap.NewNop(), server.URL[len("http://"):], "")
+	result, err := client.Infer(context.Background(), "llama", "1", map[string]interface{}{
+		"messages": []interface{}{map[string]interface{}{"role": "user", "content": "Hi"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello!", result["text_output"])
+}
+
+func TestInferStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, true, body["stream"])
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"choices":[{"index":0,"delta":{"role":"assistant"}}]}`,
+			`{"choices":[{"index":0,"delta":{"content":"Hel"}}]}`,
+			`{"choices":[{"index":0,"delta":{"content":"lo"},"finish_reason":"stop"}]}`,
+			`[DONE]`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(zap.NewNop(), server.URL, "")
+	var chunks []map[string]interface{}
+	err := client.InferStream(context.Background(), "llama", "1", map[string]interface{}{
+		"messages": []interface{}{map[string]interface{}{"role": "user", "content": "Hi"}},
+	}, func(chunk map[string]interface{}) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"index": 0, "text_output": "Hel"},
+		{"index": 0, "text_output": "lo", "finish_reason": "stop"},
+	}, chunks)
+}
+
+func TestErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/completions":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"message": "max_tokens is too large", "type": "BadRequestError"}}`))
+		case "/v1/chat/completions":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"error\": \"CUDA out of memory\"}\n\n")
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(zap.NewNop(), server.URL, "")
+	ctx := context.Background()
+
+	_, err := client.Infer(ctx, "llama", "1", map[string]interface{}{"prompt": "Hi", "max_tokens": 1e9})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+	assert.Contains(t, err.Error(), "max_tokens is too large")
+
+	_, err = client.Infer(ctx, "llama", "1", map[string]interface{}{"image": []interface{}{1.0}})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+
+	err = client.InferStream(ctx, "llama", "1", map[string]interface{}{"messages": []interface{}{}}, func(map[string]interface{}) error {
+		t.Fatal("nothing is emitted")
+		return nil
+	})
+	assert.True(t, apperrors.Is(err, apperrors.Internal))
+
+	assert.Error(t, client.HealthCheck(ctx))
+}
@@ -0,0 +1,196 @@
+// Package pipeline runs the preprocessing and postprocessing steps a model
+// version is registered with around its inference: resizing and normalizing
+// images or tokenizing text into the tensors the model takes, and turning
+// the scores it returns into probabilities, top-k classes and labels.
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/metadata"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// Model metadata keys holding a version's steps, each a JSON array of Step
+const (
+	PreprocessKey  = "preprocess"
+	PostprocessKey = "postprocess"
+)
+
+// Step is a preprocessing or postprocessing step. Op names what it does and
+// the other fields are its parameters, those that do not apply to Op being
+// left out.
+type Step struct {
+	Op string `json:"op"`
+	// Tensor is the input a preprocessing step reads, which may be left out
+	// when the request has one, or the output a postprocessing step reads,
+	// every output when left out
+	Tensor string `json:"tensor,omitempty"`
+	// Output renames the input a preprocessing step writes
+	Output string `json:"output,omitempty"`
+
+	// resize
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+
+	// normalize computes (x*scale - mean) / std per channel, the last
+	// dimension; scale defaults to 1 and std to 1s
+	Scale float64   `json:"scale,omitempty"`
+	Mean  []float64 `json:"mean,omitempty"`
+	Std   []float64 `json:"std,omitempty"`
+
+	// tokenize maps the words of a text to their index in Vocab, Unknown's
+	// for those not in it, or to its UTF-8 bytes when Vocab is empty, and
+	// pads or truncates them to MaxLength, writing the attention mask to
+	// Mask when it is set
+	Vocab     []string `json:"vocab,omitempty"`
+	Unknown   string   `json:"unknown,omitempty"`
+	Lowercase bool     `json:"lowercase,omitempty"`
+	MaxLength int      `json:"max_length,omitempty"`
+	Mask      string   `json:"mask,omitempty"`
+
+	// top_k keeps the K best scores of each row
+	K int `json:"k,omitempty"`
+	// Labels name the classes top_k and labels return by index
+	Labels []string `json:"labels,omitempty"`
+}
+
+// Pipeline is the steps run around a model version's inference. A nil
+// Pipeline runs none.
+type Pipeline struct {
+	pre  []Step
+	post []Step
+}
+
+// FromModel returns the pipeline a model version is registered with, nil
+// when it has none. Steps that cannot be read fail with FailedPrecondition.
+func FromModel(model *metadata.Model) (*Pipeline, error) {
+	if model == nil {
+		return nil, nil
+	}
+	pre, err := parse(model, PreprocessKey, func(op string) bool {
+		_, ok := preprocessors[op]
+		return ok
+	})
+	if err != nil {
+		return nil, err
+	}
+	post, err := parse(model, PostprocessKey, func(op string) bool {
+		_, ok := postprocessors[op]
+		return ok
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pre) == 0 && len(post) == 0 {
+		return nil, nil
+	}
+	return &Pipeline{pre: pre, post: post}, nil
+}
+
+// parse reads the steps under a metadata key, each of an op known to be
+// one of its kind
+func parse(model *metadata.Model, key string, known func(op string) bool) ([]Step, error) {
+	raw := model.Metadata[key]
+	if raw == "" {
+		return nil, nil
+	}
+	var steps []Step
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&steps)
+	for i := 0; err == nil && i < len(steps); i++ {
+		if !known(steps[i].Op) {
+			err = fmt.Errorf("unknown op %q", steps[i].Op)
+		}
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.FailedPrecondition,
+			fmt.Sprintf("model %s version %s has an invalid %s pipeline: %v", model.Name, model.Version, key, err))
+	}
+	return steps, nil
+}
+
+// Preprocess runs the preprocessing steps on a request's decoded input,
+// returning the input the model is sent. Inputs the steps cannot take fail
+// with InvalidArgument. input is not modified.
+func (p *Pipeline) Preprocess(input map[string]interface{}) (map[string]interface{}, error) {
+	if p == nil || len(p.pre) == 0 {
+		return input, nil
+	}
+	out := make(map[string]interface{}, len(input))
+	for name, value := range input {
+		out[name] = value
+	}
+	for _, step := range p.pre {
+		name := step.Tensor
+		if name == "" {
+			if len(out) != 1 {
+				return nil, apperrors.Newf(apperrors.InvalidArgument, "%s needs a single input, not %d", step.Op, len(out))
+			}
+			for only := range out {
+				name = only
+			}
+		}
+		value, ok := out[name]
+		if !ok {
+			return nil, apperrors.Newf(apperrors.InvalidArgument, "missing input %q", name)
+		}
+		written, extra, err := preprocessors[step.Op](step, value)
+		if err != nil {
+			return nil, apperrors.Newf(apperrors.InvalidArgument, "%s input %q: %v", step.Op, name, err)
+		}
+		if step.Output != "" {
+			delete(out, name)
+			name = step.Output
+		}
+		out[name] = written.value()
+		for other, t := range extra {
+			out[other] = t.value()
+		}
+	}
+	return out, nil
+}
+
+// Postprocess runs the postprocessing steps on an inference's result,
+// reading its outputs from "values" and adding what the steps make of them,
+// by output name, as "predictions". Outputs the steps cannot take fail with
+// FailedPrecondition. result is not modified.
+func (p *Pipeline) Postprocess(result map[string]interface{}) (map[string]interface{}, error) {
+	if p == nil || len(p.post) == 0 {
+		return result, nil
+	}
+	values, _ := result["values"].(map[string]interface{})
+	predictions := make(map[string]interface{})
+	for _, step := range p.post {
+		names := []string{step.Tensor}
+		if step.Tensor == "" {
+			names = names[:0]
+			for name := range values {
+				names = append(names, name)
+			}
+		}
+		for _, name := range names {
+			value, ok := predictions[name]
+			if !ok {
+				if value, ok = values[name]; !ok {
+					return nil, apperrors.Newf(apperrors.FailedPrecondition, "model has no output %q to %s", name, step.Op)
+				}
+			}
+			processed, err := postprocessors[step.Op](step, value)
+			if err != nil {
+				return nil, apperrors.Newf(apperrors.FailedPrecondition, "%s output %q: %v", step.Op, name, err)
+			}
+			predictions[name] = processed
+		}
+	}
+
+	out := make(map[string]interface{}, len(result)+1)
+	for k, v := range result {
+		out[k] = v
+	}
+	out["predictions"] = predictions
+	return out, nil
+}
@@ -0,0 +1,112 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/decode"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/metadata"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func model(pre, post string) *metadata.Model {
+	return &metadata.Model{Name: "m", Version: "1", Metadata: map[string]string{PreprocessKey: pre, PostprocessKey: post}}
+}
+
+func TestFromModel(t *testing.T) {
+	p, err := FromModel(nil)
+	require.NoError(t, err)
+	assert.Nil(t, p)
+	p, err = FromModel(&metadata.Model{})
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	for _, broken := range []*metadata.Model{
+		model(`[{"op": "blur"}]`, ""),
+		model("", `[{"op": "resize"}]`),
+		model(`[{"op": "resize", "widht": 2}]`, ""),
+		model(`{"op": "resize"}`, ""),
+	} {
+		_, err := FromModel(broken)
+		assert.True(t, apperrors.Is(err, apperrors.FailedPrecondition), broken.Metadata)
+	}
+}
+
+func TestPreprocess_Image(t *testing.T) {
+	p, err := FromModel(model(`[
+		{"op": "resize", "width": 1, "height": 1},
+		{"op": "normalize", "scale": 0.5, "mean": [0, 10, 20], "std": [1, 2, 4]},
+		{"op": "to_chw"},
+		{"op": "batch", "output": "pixels"}
+	]`, ""))
+	require.NoError(t, err)
+
+	// A 2x2 image averages down to one pixel of (10, 40, 80)
+	image := &decode.Tensor{Datatype: "UINT8", Shape: []int{2, 2, 3}, Data: []int{
+		0, 20, 40, 20, 60, 120,
+		0, 20, 40, 20, 60, 120,
+	}}
+	input, err := p.Preprocess(map[string]interface{}{"image": image})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"pixels": map[string]interface{}{
+		"datatype": "FP32",
+		"shape":    []interface{}{1.0, 3.0, 1.0, 1.0},
+		"data":     []interface{}{5.0, 5.0, 5.0},
+	}}, input)
+
+	_, err = p.Preprocess(map[string]interface{}{"image": []interface{}{1.0, 2.0}})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+	_, err = p.Preprocess(map[string]interface{}{"a": image, "b": image})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+}
+
+func TestPreprocess_Tokenize(t *testing.T) {
+	p, err := FromModel(model(`[{"op": "tokenize", "tensor": "text", "output": "input_ids", "mask": "attention_mask",
+		"vocab": ["[UNK]", "hello", "world", "!"], "unknown": "[UNK]", "lowercase": true, "max_length": 5}]`, ""))
+	require.NoError(t, err)
+
+	input, err := p.Preprocess(map[string]interface{}{"text": "Hello, world!"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1.0, 0.0, 2.0, 3.0, 0.0}, input["input_ids"].(map[string]interface{})["data"])
+	assert.Equal(t, []interface{}{1.0, 1.0, 1.0, 1.0, 0.0}, input["attention_mask"].(map[string]interface{})["data"])
+	assert.Equal(t, "INT64", input["input_ids"].(map[string]interface{})["datatype"])
+	assert.NotContains(t, input, "text")
+
+	bytes, err := FromModel(model(`[{"op": "tokenize"}]`, ""))
+	require.NoError(t, err)
+	input, err = bytes.Preprocess(map[string]interface{}{"text": []interface{}{"hi"}})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{104.0, 105.0}, input["text"].(map[string]interface{})["data"])
+}
+
+func TestPostprocess(t *testing.T) {
+	p, err := FromModel(model("", `[
+		{"op": "argmax", "tensor": "logits"},
+		{"op": "labels", "tensor": "logits", "labels": ["cat", "dog", "fox"]},
+		{"op": "sigmoid", "tensor": "spam"},
+		{"op": "top_k", "tensor": "scores", "k": 2}
+	]`))
+	require.NoError(t, err)
+
+	result := map[string]interface{}{"values": map[string]interface{}{
+		"logits": []interface{}{[]interface{}{0.1, 2.0, 0.3}, []interface{}{3.0, 0.0, 0.0}},
+		"spam":   []interface{}{0.0},
+		"scores": []interface{}{int64(1), int64(3), int64(2)},
+	}}
+	out, err := p.Postprocess(result)
+	require.NoError(t, err)
+	assert.NotContains(t, result, "predictions")
+
+	predictions := out["predictions"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"dog", "cat"}, predictions["logits"])
+	assert.Equal(t, []interface{}{0.5}, predictions["spam"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"index": int64(1), "score": 3.0},
+		map[string]interface{}{"index": int64(2), "score": 2.0},
+	}, predictions["scores"])
+
+	_, err = p.Postprocess(map[string]interface{}{"values": map[string]interface{}{"logits": []interface{}{"a"}}})
+	assert.True(t, apperrors.Is(err, apperrors.FailedPrecondition))
+}
@@ -0,0 +1,222 @@
+package pipeline
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// postprocessor runs a step on an output, as nested arrays, or on what the
+// steps before it made of it
+type postprocessor func(step Step, value interface{}) (interface{}, error)
+
+var postprocessors = map[string]postprocessor{
+	"softmax": softmax,
+	"sigmoid": sigmoid,
+	"argmax":  argmax,
+	"top_k":   topK,
+	"labels":  labels,
+}
+
+// rows reads an output's scores, nested arrays of numbers, as rows over
+// its last dimension, returning the shape of the rest
+func rows(value interface{}) ([][]float64, []int, error) {
+	var out [][]float64
+	var outer []int
+	var walk func(v interface{}, depth int) error
+	walk = func(v interface{}, depth int) error {
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("output is not an array of scores")
+		}
+		if len(items) > 0 {
+			if _, nested := items[0].([]interface{}); nested {
+				if depth == len(outer) {
+					outer = append(outer, len(items))
+				}
+				for _, item := range items {
+					if err := walk(item, depth+1); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		}
+		row := make([]float64, len(items))
+		for i, item := range items {
+			switch x := item.(type) {
+			case float64:
+				row[i] = x
+			case int64:
+				row[i] = float64(x)
+			case uint64:
+				row[i] = float64(x)
+			default:
+				return fmt.Errorf("element %v is not a number", item)
+			}
+		}
+		out = append(out, row)
+		return nil
+	}
+	if err := walk(value, 0); err != nil {
+		return nil, nil, err
+	}
+	return out, outer, nil
+}
+
+// nest arranges one value per row back into the shape rows were read from
+func nest(values []interface{}, shape []int) interface{} {
+	if len(shape) == 0 {
+		return values[0]
+	}
+	stride := len(values) / shape[0]
+	out := make([]interface{}, shape[0])
+	for i := range out {
+		out[i] = nest(values[i*stride:(i+1)*stride], shape[1:])
+	}
+	return out
+}
+
+// eachRow applies f to every row of scores, keeping the output's shape
+func eachRow(value interface{}, f func(row []float64) interface{}) (interface{}, error) {
+	scores, outer, err := rows(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) == 0 {
+		return value, nil
+	}
+	out := make([]interface{}, len(scores))
+	for i, row := range scores {
+		out[i] = f(row)
+	}
+	return nest(out, outer), nil
+}
+
+func toArray(row []float64) []interface{} {
+	out := make([]interface{}, len(row))
+	for i, v := range row {
+		out[i] = v
+	}
+	return out
+}
+
+// softmax turns each row of logits into probabilities
+func softmax(step Step, value interface{}) (interface{}, error) {
+	return eachRow(value, func(row []float64) interface{} {
+		max := math.Inf(-1)
+		for _, v := range row {
+			max = math.Max(max, v)
+		}
+		out := make([]float64, len(row))
+		sum := 0.0
+		for i, v := range row {
+			out[i] = math.Exp(v - max)
+			sum += out[i]
+		}
+		for i := range out {
+			out[i] /= sum
+		}
+		return toArray(out)
+	})
+}
+
+// sigmoid turns each logit into a probability
+func sigmoid(step Step, value interface{}) (interface{}, error) {
+	return eachRow(value, func(row []float64) interface{} {
+		out := make([]float64, len(row))
+		for i, v := range row {
+			out[i] = 1 / (1 + math.Exp(-v))
+		}
+		return toArray(out)
+	})
+}
+
+// argmax returns the index of each row's best score
+func argmax(step Step, value interface{}) (interface{}, error) {
+	return eachRow(value, func(row []float64) interface{} {
+		best := 0
+		for i, v := range row {
+			if v > row[best] {
+				best = i
+			}
+		}
+		return int64(best)
+	})
+}
+
+// topK returns each row's K best scores, best first, with their index and,
+// when the step has them, their label
+func topK(step Step, value interface{}) (interface{}, error) {
+	if step.K <= 0 {
+		return nil, fmt.Errorf("top_k needs a k")
+	}
+	return eachRow(value, func(row []float64) interface{} {
+		order := make([]int, len(row))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return row[order[a]] > row[order[b]] })
+		if len(order) > step.K {
+			order = order[:step.K]
+		}
+		out := make([]interface{}, len(order))
+		for i, index := range order {
+			class := map[string]interface{}{"index": int64(index), "score": row[index]}
+			if index < len(step.Labels) {
+				class["label"] = step.Labels[index]
+			}
+			out[i] = class
+		}
+		return out
+	})
+}
+
+// labels replaces class indices, as argmax returns them, with their labels,
+// and labels the classes top_k returns
+func labels(step Step, value interface{}) (interface{}, error) {
+	label := func(index interface{}) (string, error) {
+		var i int64
+		switch x := index.(type) {
+		case int64:
+			i = x
+		case uint64:
+			i = int64(x)
+		case float64:
+			i = int64(x)
+			if float64(i) != x {
+				return "", fmt.Errorf("%v is not a class index", x)
+			}
+		default:
+			return "", fmt.Errorf("%v is not a class index", index)
+		}
+		if i < 0 || i >= int64(len(step.Labels)) {
+			return "", fmt.Errorf("class %d has no label", i)
+		}
+		return step.Labels[i], nil
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			var err error
+			if out[i], err = labels(step, item); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case map[string]interface{}:
+		l, err := label(v["index"])
+		if err != nil {
+			return nil, err
+		}
+		out := make(map[string]interface{}, len(v)+1)
+		for k, field := range v {
+			out[k] = field
+		}
+		out["label"] = l
+		return out, nil
+	}
+	return label(value)
+}
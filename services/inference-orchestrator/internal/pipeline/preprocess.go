@@ -0,0 +1,344 @@
+package pipeline
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/decode"
+)
+
+// tensor is a numeric tensor in row-major order, its elements held as
+// float64 whatever datatype they stand for
+type tensor struct {
+	datatype string
+	shape    []int
+	data     []float64
+}
+
+// value returns the tensor in the KServe v2 JSON form
+func (t *tensor) value() map[string]interface{} {
+	shape := make([]interface{}, len(t.shape))
+	for i, dim := range t.shape {
+		shape[i] = float64(dim)
+	}
+	data := make([]interface{}, len(t.data))
+	for i, v := range t.data {
+		data[i] = v
+	}
+	return map[string]interface{}{"datatype": t.datatype, "shape": shape, "data": data}
+}
+
+// preprocessor runs a step on an input, returning the tensor it becomes
+// and any other inputs the step adds, by name
+type preprocessor func(step Step, value interface{}) (*tensor, map[string]*tensor, error)
+
+var preprocessors = map[string]preprocessor{
+	"resize":    resize,
+	"normalize": normalize,
+	"to_chw":    toCHW,
+	"batch":     batch,
+	"tokenize":  tokenize,
+}
+
+// toTensor reads an input decoded from a blob, sent in the KServe v2 JSON
+// form, or sent as a nested array of numbers
+func toTensor(value interface{}) (*tensor, error) {
+	switch v := value.(type) {
+	case *decode.Tensor:
+		t := &tensor{datatype: v.Datatype, shape: v.Shape}
+		switch data := v.Data.(type) {
+		case []int:
+			for _, x := range data {
+				t.data = append(t.data, float64(x))
+			}
+		case []float32:
+			for _, x := range data {
+				t.data = append(t.data, float64(x))
+			}
+		case []float64:
+			t.data = data
+		default:
+			return nil, fmt.Errorf("unsupported data %T", v.Data)
+		}
+		return t, nil
+	case map[string]interface{}:
+		datatype, _ := v["datatype"].(string)
+		t, err := toTensor(v["data"])
+		if err != nil {
+			return nil, err
+		}
+		if datatype != "" {
+			t.datatype = datatype
+		}
+		if declared, ok := v["shape"].([]interface{}); ok {
+			t.shape = make([]int, len(declared))
+			for i, dim := range declared {
+				d, ok := dim.(float64)
+				if !ok || d < 0 {
+					return nil, fmt.Errorf("invalid shape %v", declared)
+				}
+				t.shape[i] = int(d)
+			}
+		}
+		if len(t.data) != size(t.shape) {
+			return nil, fmt.Errorf("%d elements do not fill shape %v", len(t.data), t.shape)
+		}
+		return t, nil
+	}
+
+	t := &tensor{datatype: "FP32"}
+	var walk func(v interface{}, depth int) error
+	walk = func(v interface{}, depth int) error {
+		switch v := v.(type) {
+		case float64:
+			if depth != len(t.shape) {
+				return fmt.Errorf("array is not rectangular")
+			}
+			t.data = append(t.data, v)
+			return nil
+		case []interface{}:
+			if depth == len(t.shape) && len(t.data) == 0 {
+				t.shape = append(t.shape, len(v))
+			} else if depth >= len(t.shape) || t.shape[depth] != len(v) {
+				return fmt.Errorf("array is not rectangular")
+			}
+			for _, item := range v {
+				if err := walk(item, depth+1); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return fmt.Errorf("unsupported element %v", v)
+	}
+	if err := walk(value, 0); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func size(shape []int) int {
+	n := 1
+	for _, dim := range shape {
+		n *= dim
+	}
+	return n
+}
+
+// resize scales an image of shape [height, width, channels] to the step's
+// height and width, interpolating bilinearly between pixel centers
+func resize(step Step, value interface{}) (*tensor, map[string]*tensor, error) {
+	in, err := toTensor(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(in.shape) != 3 {
+		return nil, nil, fmt.Errorf("image of shape %v is not [height, width, channels]", in.shape)
+	}
+	if step.Width <= 0 || step.Height <= 0 {
+		return nil, nil, fmt.Errorf("resize needs a width and height")
+	}
+	h, w, c := in.shape[0], in.shape[1], in.shape[2]
+	if h == 0 || w == 0 {
+		return nil, nil, fmt.Errorf("image is empty")
+	}
+
+	out := &tensor{datatype: in.datatype, shape: []int{step.Height, step.Width, c}}
+	out.data = make([]float64, step.Height*step.Width*c)
+	// source returns the pixels either side of an output coordinate and how
+	// far it is between them
+	source := func(i, from, to int) (int, int, float64) {
+		x := (float64(i)+0.5)*float64(from)/float64(to) - 0.5
+		x = math.Max(0, math.Min(x, float64(from-1)))
+		lo := int(x)
+		hi := lo + 1
+		if hi >= from {
+			hi = from - 1
+		}
+		return lo, hi, x - float64(lo)
+	}
+	for y := 0; y < step.Height; y++ {
+		y0, y1, fy := source(y, h, step.Height)
+		for x := 0; x < step.Width; x++ {
+			x0, x1, fx := source(x, w, step.Width)
+			for ch := 0; ch < c; ch++ {
+				at := func(y, x int) float64 { return in.data[(y*w+x)*c+ch] }
+				top := at(y0, x0)*(1-fx) + at(y0, x1)*fx
+				bottom := at(y1, x0)*(1-fx) + at(y1, x1)*fx
+				v := top*(1-fy) + bottom*fy
+				if in.datatype == "UINT8" {
+					v = math.Round(v)
+				}
+				out.data[(y*step.Width+x)*c+ch] = v
+			}
+		}
+	}
+	return out, nil, nil
+}
+
+// normalize scales an input and standardizes each channel, its last
+// dimension, to FP32
+func normalize(step Step, value interface{}) (*tensor, map[string]*tensor, error) {
+	in, err := toTensor(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	channels := 1
+	if len(in.shape) > 0 {
+		channels = in.shape[len(in.shape)-1]
+	}
+	per := func(values []float64, fallback float64) ([]float64, error) {
+		switch len(values) {
+		case 0:
+			values = []float64{fallback}
+			fallthrough
+		case 1:
+			out := make([]float64, channels)
+			for i := range out {
+				out[i] = values[0]
+			}
+			return out, nil
+		case channels:
+			return values, nil
+		}
+		return nil, fmt.Errorf("%d values for %d channels", len(values), channels)
+	}
+	mean, err := per(step.Mean, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	std, err := per(step.Std, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	scale := step.Scale
+	if scale == 0 {
+		scale = 1
+	}
+
+	out := &tensor{datatype: "FP32", shape: in.shape, data: make([]float64, len(in.data))}
+	for i, v := range in.data {
+		ch := i % channels
+		if std[ch] == 0 {
+			return nil, nil, fmt.Errorf("std of channel %d is 0", ch)
+		}
+		out.data[i] = (v*scale - mean[ch]) / std[ch]
+	}
+	return out, nil, nil
+}
+
+// toCHW moves an image's channels from its last dimension to its first,
+// as most vision models take them
+func toCHW(step Step, value interface{}) (*tensor, map[string]*tensor, error) {
+	in, err := toTensor(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(in.shape) != 3 {
+		return nil, nil, fmt.Errorf("image of shape %v is not [height, width, channels]", in.shape)
+	}
+	h, w, c := in.shape[0], in.shape[1], in.shape[2]
+	out := &tensor{datatype: in.datatype, shape: []int{c, h, w}, data: make([]float64, len(in.data))}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			for ch := 0; ch < c; ch++ {
+				out.data[(ch*h+y)*w+x] = in.data[(y*w+x)*c+ch]
+			}
+		}
+	}
+	return out, nil, nil
+}
+
+// batch adds a leading batch dimension of 1
+func batch(step Step, value interface{}) (*tensor, map[string]*tensor, error) {
+	in, err := toTensor(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	in.shape = append([]int{1}, in.shape...)
+	return in, nil, nil
+}
+
+// tokenize turns a text into INT64 token IDs of shape [1, length]
+func tokenize(step Step, value interface{}) (*tensor, map[string]*tensor, error) {
+	text, ok := value.(string)
+	if !ok {
+		if object, isObject := value.(map[string]interface{}); isObject {
+			value = object["data"]
+		}
+		if items, isArray := value.([]interface{}); isArray && len(items) == 1 {
+			text, ok = items[0].(string)
+		}
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("input is not a text")
+	}
+	if step.Lowercase {
+		text = strings.ToLower(text)
+	}
+
+	var ids []float64
+	if len(step.Vocab) == 0 {
+		for _, b := range []byte(text) {
+			ids = append(ids, float64(b))
+		}
+	} else {
+		index := make(map[string]int, len(step.Vocab))
+		for i, token := range step.Vocab {
+			index[token] = i
+		}
+		unknown, hasUnknown := index[step.Unknown]
+		for _, word := range words(text) {
+			id, ok := index[word]
+			if !ok {
+				if !hasUnknown {
+					return nil, nil, fmt.Errorf("%q is not in the vocabulary", word)
+				}
+				id = unknown
+			}
+			ids = append(ids, float64(id))
+		}
+	}
+
+	length := len(ids)
+	if step.MaxLength > 0 {
+		length = step.MaxLength
+	}
+	out := &tensor{datatype: "INT64", shape: []int{1, length}, data: make([]float64, length)}
+	mask := &tensor{datatype: "INT64", shape: []int{1, length}, data: make([]float64, length)}
+	for i := 0; i < length && i < len(ids); i++ {
+		out.data[i] = ids[i]
+		mask.data[i] = 1
+	}
+	if step.Mask == "" {
+		return out, nil, nil
+	}
+	return out, map[string]*tensor{step.Mask: mask}, nil
+}
+
+// words splits a text at spaces, each punctuation mark a word of its own
+func words(text string) []string {
+	var out []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			out = append(out, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case unicode.IsPunct(r):
+			flush()
+			out = append(out, string(r))
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return out
+}
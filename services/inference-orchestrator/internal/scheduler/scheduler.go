@@ -0,0 +1,152 @@
+// Package scheduler bounds the inferences the orchestrator runs on Triton at
+// once. Requests over the bound wait in a queue of their priority class, and
+// each freed slot goes to the oldest request of the highest class waiting,
+// so bulk traffic delays interactive requests by no more than the inferences
+// already running.
+package scheduler
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+// Classes are the priority classes, highest first
+var Classes = schema.Priorities
+
+// Config tunes a Scheduler
+type Config struct {
+	// MaxInFlight is the most inferences run at once
+	MaxInFlight int
+	// QueueSizes bounds the requests waiting in each class; classes left
+	// out queue nothing once every slot is taken
+	QueueSizes map[string]int
+	// MaxWait is the longest a request waits in its queue
+	MaxWait time.Duration
+}
+
+// Errors for requests turned away
+var (
+	ErrQueueFull    = apperrors.New(apperrors.ResourceExhausted, "inference queue is full")
+	ErrQueueTimeout = apperrors.New(apperrors.ResourceExhausted, "timed out waiting in the inference queue")
+)
+
+// Class returns the priority class of a request's X-Priority value; anything
+// but high and low is normal
+func Class(priority string) string {
+	switch priority {
+	case schema.PriorityHigh, schema.PriorityLow:
+		return priority
+	}
+	return schema.PriorityNormal
+}
+
+// Scheduler admits inferences up to MaxInFlight. A nil Scheduler admits
+// every request.
+type Scheduler struct {
+	config Config
+
+	mu       sync.Mutex
+	inFlight int
+	queues   map[string]*list.List
+}
+
+// New creates a scheduler
+func New(config Config) *Scheduler {
+	queues := make(map[string]*list.List, len(Classes))
+	for _, class := range Classes {
+		queues[class] = list.New()
+	}
+	return &Scheduler{config: config, queues: queues}
+}
+
+// Acquire waits for a slot for a request of a priority class. It returns how
+// long the request waited and a release func to call once the inference is
+// done. Requests finding their queue full fail with ErrQueueFull, those
+// waiting past MaxWait with ErrQueueTimeout, and those whose ctx ends while
+// queued with its error.
+func (s *Scheduler) Acquire(ctx context.Context, class string) (func(), time.Duration, error) {
+	if s == nil {
+		return func() {}, 0, nil
+	}
+	class = Class(class)
+
+	s.mu.Lock()
+	if s.inFlight < s.config.MaxInFlight && s.queued() == 0 {
+		s.inFlight++
+		s.mu.Unlock()
+		return s.release, 0, nil
+	}
+	queue := s.queues[class]
+	if queue.Len() >= s.config.QueueSizes[class] {
+		s.mu.Unlock()
+		return nil, 0, ErrQueueFull
+	}
+	ready := make(chan struct{})
+	waiter := queue.PushBack(ready)
+	s.mu.Unlock()
+
+	start := time.Now()
+	timer := time.NewTimer(s.config.MaxWait)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-ready:
+		return s.release, time.Since(start), nil
+	case <-timer.C:
+		err = ErrQueueTimeout
+	case <-ctx.Done():
+		err = apperrors.FromTransportError(ctx.Err(), "inference queue")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-ready:
+		// The slot was handed over as the wait ended
+		return s.release, time.Since(start), nil
+	default:
+		queue.Remove(waiter)
+		return nil, time.Since(start), err
+	}
+}
+
+// Queued reports the requests waiting in each class
+func (s *Scheduler) Queued() map[string]int {
+	queued := make(map[string]int, len(Classes))
+	if s == nil {
+		return queued
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for class, queue := range s.queues {
+		queued[class] = queue.Len()
+	}
+	return queued
+}
+
+// release hands the slot to the first waiter of the highest class waiting
+func (s *Scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, class := range Classes {
+		if queue := s.queues[class]; queue.Len() > 0 {
+			close(queue.Remove(queue.Front()).(chan struct{}))
+			return
+		}
+	}
+	s.inFlight--
+}
+
+// queued counts the waiting requests; s.mu must be held
+func (s *Scheduler) queued() int {
+	n := 0
+	for _, queue := range s.queues {
+		n += queue.Len()
+	}
+	return n
+}
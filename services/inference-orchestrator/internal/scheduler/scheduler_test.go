@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	return Config{
+		MaxInFlight: 1,
+		QueueSizes:  map[string]int{"high": 2, "normal": 2, "low": 2},
+		MaxWait:     time.Second,
+	}
+}
+
+func TestScheduler_ServesHighPriorityFirst(t *testing.T) {
+	s := New(testConfig())
+	release, waited, err := s.Acquire(context.Background(), "normal")
+	require.NoError(t, err)
+	assert.Zero(t, waited)
+
+	order := make(chan string, 2)
+	acquire := func(class string) {
+		release, _, err := s.Acquire(context.Background(), class)
+		if err == nil {
+			order <- class
+			release()
+		}
+	}
+	go acquire("low")
+	require.Eventually(t, func() bool { return s.Queued()["low"] == 1 }, time.Second, time.Millisecond)
+	go acquire("high")
+	require.Eventually(t, func() bool { return s.Queued()["high"] == 1 }, time.Second, time.Millisecond)
+
+	release()
+	assert.Equal(t, "high", <-order)
+	assert.Equal(t, "low", <-order)
+}
+
+func TestScheduler_BoundsEachQueue(t *testing.T) {
+	config := testConfig()
+	config.QueueSizes = map[string]int{"high": 1}
+	s := New(config)
+	release, _, err := s.Acquire(context.Background(), "high")
+	require.NoError(t, err)
+	defer release()
+
+	_, _, err = s.Acquire(context.Background(), "low")
+	assert.ErrorIs(t, err, ErrQueueFull, "bulk traffic has no queue")
+
+	go s.Acquire(context.Background(), "high")
+	require.Eventually(t, func() bool { return s.Queued()["high"] == 1 }, time.Second, time.Millisecond)
+	_, _, err = s.Acquire(context.Background(), "high")
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
+
+func TestScheduler_TimesOut(t *testing.T) {
+	config := testConfig()
+	config.MaxWait = 10 * time.Millisecond
+	s := New(config)
+	release, _, err := s.Acquire(context.Background(), "normal")
+	require.NoError(t, err)
+
+	_, waited, err := s.Acquire(context.Background(), "normal")
+	assert.ErrorIs(t, err, ErrQueueTimeout)
+	assert.GreaterOrEqual(t, waited, 10*time.Millisecond)
+	assert.Zero(t, s.Queued()["normal"])
+
+	// The timed out request left no slot taken
+	release()
+	release, _, err = s.Acquire(context.Background(), "normal")
+	require.NoError(t, err)
+	release()
+}
+
+func TestScheduler_Nil(t *testing.T) {
+	var s *Scheduler
+	release, _, err := s.Acquire(context.Background(), "low")
+	require.NoError(t, err)
+	release()
+	assert.Empty(t, s.Queued())
+}
+
+func TestClass(t *testing.T) {
+	assert.Equal(t, "high", Class("high"))
+	assert.Equal(t, "low", Class("low"))
+	assert.Equal(t, "normal", Class(""))
+	assert.Equal(t, "normal", Class("urgent"))
+}
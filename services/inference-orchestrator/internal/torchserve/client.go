@@ -0,0 +1,227 @@
+// Package torchserve calls TorchServe's inference and management APIs, for
+// model versions registered as TorchServe model archives.
+package torchserve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Client calls TorchServe's inference API and, when its address is given,
+// its management API
+type Client struct {
+	logger        *zap.Logger
+	baseURL       string
+	managementURL string
+	httpClient    *http.Client
+}
+
+// NewClient creates a client for the TorchServe inference API at address
+// and management API at managementAddress, which may be empty
+func NewClient(logger *zap.Logger, address, managementAddress string) *Client {
+	c := &Client{
+		logger:  logger,
+		baseURL: "http://" + address,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+	if managementAddress != "" {
+		c.managementURL = "http://" + managementAddress
+	}
+	return c
+}
+
+// Infer runs an inference with TorchServe's predictions API. The input is
+// sent as the JSON body the model's handler receives, and the result holds
+// what the handler returns as "predictions". The call is bounded by ctx.
+func (c *Client) Infer(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	start := time.Now()
+	logger := logging.With(ctx, c.logger)
+	logger.Info("executing inference on torchserve",
+		zap.String("model", model),
+		zap.String("version", version),
+	)
+
+	endpoint := fmt.Sprintf("%s/predictions/%s", c.baseURL, url.PathEscape(model))
+	// TorchServe numbers versions where the platform names them ("v1")
+	if version = strings.TrimPrefix(version, "v"); version != "" {
+		endpoint += "/" + url.PathEscape(version)
+	}
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.InvalidArgument, "input is not valid JSON")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	logging.Inject(ctx, req)
+
+	resp, err := logging.ClientFor(ctx, c.httpClient).Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, "torchserve")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorFrom(resp)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, "torchserve")
+	}
+	// Handlers may answer with plain text rather than JSON
+	var predictions interface{}
+	if err := json.Unmarshal(raw, &predictions); err != nil {
+		predictions = string(raw)
+	}
+
+	logger.Info("inference completed",
+		zap.String("model", model),
+		zap.Int64("latency_ms", time.Since(start).Milliseconds()),
+	)
+	return map[string]interface{}{
+		"model_name":    model,
+		"model_version": version,
+		"predictions":   predictions,
+	}, nil
+}
+
+// HealthCheck checks that TorchServe is healthy
+func (c *Client) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/ping", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("torchserve not healthy: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ModelState is a model version registered with TorchServe and whether it
+// has a worker ready to serve it
+type ModelState struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	State   string `json:"state,omitempty"` // READY or UNAVAILABLE
+	Reason  string `json:"reason,omitempty"`
+}
+
+// description is a model version as described by the management API
+type description struct {
+	ModelName    string `json:"modelName"`
+	ModelVersion string `json:"modelVersion"`
+	Workers      []struct {
+		Status string `json:"status"`
+	} `json:"workers"`
+}
+
+// Models lists the model versions registered with TorchServe and whether
+// each has a worker ready. It fails when no management API is configured.
+func (c *Client) Models(ctx context.Context) ([]ModelState, error) {
+	if c.managementURL == "" {
+		return nil, apperrors.New(apperrors.FailedPrecondition, "torchserve management API not configured")
+	}
+
+	var names []string
+	for token := ""; ; {
+		var page struct {
+			Models []struct {
+				ModelName string `json:"modelName"`
+			} `json:"models"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		endpoint := c.managementURL + "/models?limit=100"
+		if token != "" {
+			endpoint += "&next_page_token=" + url.QueryEscape(token)
+		}
+		if err := c.get(ctx, endpoint, &page); err != nil {
+			return nil, err
+		}
+		for _, model := range page.Models {
+			names = append(names, model.ModelName)
+		}
+		if token = page.NextPageToken; token == "" {
+			break
+		}
+	}
+
+	models := make([]ModelState, 0, len(names))
+	for _, name := range names {
+		var versions []description
+		if err := c.get(ctx, fmt.Sprintf("%s/models/%s/all", c.managementURL, url.PathEscape(name)), &versions); err != nil {
+			return nil, err
+		}
+		for _, version := range versions {
+			state := ModelState{Name: version.ModelName, Version: version.ModelVersion, State: "UNAVAILABLE", Reason: "no worker ready"}
+			for _, worker := range version.Workers {
+				if worker.Status == "READY" {
+					state.State, state.Reason = "READY", ""
+					break
+				}
+			}
+			models = append(models, state)
+		}
+	}
+	return models, nil
+}
+
+func (c *Client) get(ctx context.Context, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "torchserve")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errorFrom(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode torchserve response: %w", err)
+	}
+	return nil
+}
+
+// errorFrom classifies a TorchServe error response, which carries its
+// message as {"code": 404, "type": "ModelNotFoundException", "message": ...}
+func errorFrom(resp *http.Response) error {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var body struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil || body.Message == "" {
+		body.Message = http.StatusText(resp.StatusCode)
+	}
+	return apperrors.Wrap(fmt.Errorf("torchserve returned status %d: %s", resp.StatusCode, raw),
+		apperrors.FromHTTPStatus(resp.StatusCode), body.Message)
+}
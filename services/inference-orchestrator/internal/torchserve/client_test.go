@@ -0,0 +1,97 @@
+package torchserve
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+func TestClient_Infer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/predictions/densenet/1", r.URL.Path)
+		assert.Equal(t, "req-1", r.Header.Get(logging.HeaderRequestID))
+
+		var input map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, map[string]interface{}{"data": []interface{}{1.0, 2.0}}, input)
+		w.Write([]byte(`{"tabby": 0.9, "tiger_cat": 0.1}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(zap.NewNop(), server.URL[len("http://"):], "")
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	result, err := client.Infer(ctx, "densenet", "v1", map[string]interface{}{"data": []interface{}{1.0, 2.0}})
+	require.NoError(t, err)
+	assert.Equal(t, "densenet", result["model_name"])
+	assert.Equal(t, map[string]interface{}{"tabby": 0.9, "tiger_cat": 0.1}, result["predictions"])
+}
+
+func TestClient_Infer_MapsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": 404, "type": "ModelNotFoundException", "message": "Model not found: densenet"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(zap.NewNop(), server.URL[len("http://"):], "")
+	_, err := client.Infer(context.Background(), "densenet", "1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.NotFound))
+	assert.Contains(t, err.Error(), "Model not found: densenet")
+}
+
+func TestClient_Models(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/models":
+			if r.URL.Query().Get("next_page_token") == "" {
+				w.Write([]byte(`{"models": [{"modelName": "densenet"}], "nextPageToken": "1"}`))
+				return
+			}
+			w.Write([]byte(`{"models": [{"modelName": "bert"}]}`))
+		case "/models/densenet/all":
+			w.Write([]byte(`[{"modelName": "densenet", "modelVersion": "1.0", "workers": [{"status": "UNLOADING"}, {"status": "READY"}]}]`))
+		case "/models/bert/all":
+			w.Write([]byte(`[{"modelName": "bert", "modelVersion": "2.0", "workers": []}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(zap.NewNop(), "localhost:8080", server.URL[len("http://"):])
+	models, err := client.Models(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []ModelState{
+		{Name: "densenet", Version: "1.0", State: "READY"},
+		{Name: "bert", Version: "2.0", State: "UNAVAILABLE", Reason: "no worker ready"},
+	}, models)
+
+	_, err = NewClient(zap.NewNop(), "localhost:8080", "").Models(context.Background())
+	assert.True(t, apperrors.Is(err, apperrors.FailedPrecondition))
+}
+
+func TestClient_HealthCheck(t *testing.T) {
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ping", r.URL.Path)
+		if !healthy {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(zap.NewNop(), server.URL[len("http://"):], "")
+	assert.NoError(t, client.HealthCheck(context.Background()))
+
+	healthy = false
+	assert.Error(t, client.HealthCheck(context.Background()))
+}
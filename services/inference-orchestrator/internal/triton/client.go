@@ -7,16 +7,32 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/kserve"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/observability"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/kservepb"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/sse"
 )
 
-// Client wraps Triton Inference Server HTTP client
+// Client calls Triton Inference Server. Inferences go over its KServe v2
+// gRPC API once SetGRPC has been called, and over its HTTP API otherwise;
+// the repository, health and generate APIs are always called over HTTP.
 type Client struct {
 	logger     *zap.Logger
 	baseURL    string
 	httpClient *http.Client
+
+	// grpc holds the pooled connections inferences are spread over, picked
+	// in turn by next
+	grpc []*kserve.Client
+	next atomic.Uint64
 }
 
 // NewClient creates a new Triton client
@@ -30,11 +46,11 @@ func NewClient(logger *zap.Logger, tritonURL string) *Client {
 	}
 }
 
-// InferRequest represents a Triton inference request
+// InferRequest represents a Triton inference request in the KServe v2 JSON form
 type InferRequest struct {
-	Model   string                 `json:"model"`
-	Version string                 `json:"version,omitempty"`
-	Inputs  []map[string]interface{} `json:"inputs"`
+	ID         string                   `json:"id,omitempty"`
+	Inputs     []map[string]interface{} `json:"inputs"`
+	Parameters map[string]interface{}   `json:"parameters,omitempty"`
 }
 
 // InferResponse represents a Triton inference response
@@ -44,45 +60,98 @@ type InferResponse struct {
 	Outputs      []map[string]interface{} `json:"outputs"`
 }
 
-// Infer performs inference using Triton
+// SetGRPC sends inferences to Triton's gRPC API at target, "host:port", over
+// connections pooled connections. Connections are made on first use, so
+// Triton need not be up yet.
+func (c *Client) SetGRPC(target string, connections int) error {
+	if connections < 1 {
+		connections = 1
+	}
+	pool := make([]*kserve.Client, 0, connections)
+	for i := 0; i < connections; i++ {
+		conn, err := kserve.Dial(target, false)
+		if err != nil {
+			for _, open := range pool {
+				open.Close()
+			}
+			return err
+		}
+		pool = append(pool, conn)
+	}
+	c.grpc = pool
+	return nil
+}
+
+// Close releases the client's gRPC connections
+func (c *Client) Close() {
+	for _, conn := range c.grpc {
+		conn.Close()
+	}
+}
+
+// Infer runs an inference on Triton. The input's fields are the model's
+// input tensors by name, and the result holds its output tensors in the
+// KServe v2 JSON form, as "outputs", and as typed arrays nested to their
+// shape by name, as "values". Inferences go over gRPC when it is configured, and
+// fall back to HTTP while gRPC is unavailable. The call is bounded by ctx.
 func (c *Client) Infer(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
 	start := time.Now()
-
-	// For demo purposes, return mock response
-	// In production, this would make actual gRPC/HTTP calls to Triton
-	c.logger.Info("executing inference",
+	logger := logging.With(ctx, c.logger)
+	logger.Info("executing inference",
 		zap.String("model", model),
 		zap.String("version", version),
 	)
 
-	// Simulate inference latency
-	time.Sleep(50 * time.Millisecond)
-
-	// Mock response
-	result := map[string]interface{}{
-		"model_name":    model,
-		"model_version": version,
-		"prediction": map[string]interface{}{
-			"class":       "cat",
-			"confidence":  0.95,
-			"latency_ms":  time.Since(start).Milliseconds(),
-		},
+	result, err := c.infer(ctx, model, version, input)
+	if err != nil {
+		return nil, err
+	}
+	outputs, _ := result["outputs"].([]interface{})
+	if result["values"], err = kserve.Values(outputs); err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Internal, "invalid response from triton")
 	}
 
-	c.logger.Info("inference completed",
+	logger.Info("inference completed",
 		zap.String("model", model),
 		zap.Int64("latency_ms", time.Since(start).Milliseconds()),
 	)
-
 	return result, nil
 }
 
-// InferHTTP performs inference using Triton HTTP API
+func (c *Client) infer(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	if len(c.grpc) == 0 {
+		return c.InferHTTP(ctx, model, version, input)
+	}
+
+	conn := c.grpc[c.next.Add(1)%uint64(len(c.grpc))]
+	result, err := conn.Infer(ctx, model, tritonVersion(version), input)
+	if !apperrors.Is(err, apperrors.Unavailable) || ctx.Err() != nil {
+		return result, err
+	}
+
+	logging.With(ctx, c.logger).Warn("triton gRPC unavailable, falling back to HTTP",
+		zap.String("model", model),
+		zap.Error(err),
+	)
+	observability.TritonFallbacks.WithLabelValues(model).Inc()
+	return c.InferHTTP(ctx, model, version, input)
+}
+
+// InferHTTP runs an inference over Triton's HTTP API, bounded by ctx's
+// deadline, which Triton is sent as the request's timeout parameter
 func (c *Client) InferHTTP(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
-	url := fmt.Sprintf("%s/v2/models/%s/infer", c.baseURL, model)
+	url := c.modelURL(model, version, "infer")
 
-	reqBody := map[string]interface{}{
-		"inputs": []map[string]interface{}{input},
+	inputs, err := kservepb.Inputs(input)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := InferRequest{
+		ID:     logging.RequestID(ctx),
+		Inputs: inputs,
+	}
+	if timeout, ok := kserve.Timeout(ctx); ok {
+		reqBody.Parameters = map[string]interface{}{kserve.TimeoutParameter: timeout}
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
@@ -96,25 +165,123 @@ func (c *Client) InferHTTP(ctx context.Context, model, version string, input map
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := logging.ClientFor(ctx, c.httpClient).Do(req)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.FromTransportError(err, "triton")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("triton returned status %d: %s", resp.StatusCode, string(body))
+		return nil, apperrors.FromHTTPResponse(resp, "triton")
 	}
 
 	var result map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+		return nil, apperrors.Wrap(err, apperrors.Internal, "invalid response from triton")
 	}
 
 	return result, nil
 }
 
+// InferStream runs a generation on a decoupled model, passing each of its
+// responses to emit as it arrives. The input's fields are the model's
+// inputs. Generations go over gRPC's ModelStreamInfer when it is configured,
+// and fall back to HTTP while gRPC is unavailable and nothing has been
+// emitted. Failures before the first response are returned without
+// emitting anything.
+func (c *Client) InferStream(ctx context.Context, model, version string, input map[string]interface{}, emit func(chunk map[string]interface{}) error) error {
+	if len(c.grpc) == 0 {
+		return c.GenerateStream(ctx, model, version, input, emit)
+	}
+
+	conn := c.grpc[c.next.Add(1)%uint64(len(c.grpc))]
+	emitted := false
+	err := conn.InferStream(ctx, model, tritonVersion(version), input, func(chunk map[string]interface{}) error {
+		emitted = true
+		return emit(chunk)
+	})
+	if emitted || !apperrors.Is(err, apperrors.Unavailable) || ctx.Err() != nil {
+		return err
+	}
+
+	logging.With(ctx, c.logger).Warn("triton gRPC unavailable, falling back to HTTP",
+		zap.String("model", model),
+		zap.Error(err),
+	)
+	observability.TritonFallbacks.WithLabelValues(model).Inc()
+	return c.GenerateStream(ctx, model, version, input, emit)
+}
+
+// GenerateStream runs a generation on a decoupled model with Triton's
+// generate_stream extension over HTTP, passing each response to emit as it
+// arrives
+func (c *Client) GenerateStream(ctx context.Context, model, version string, input map[string]interface{}, emit func(chunk map[string]interface{}) error) error {
+	url := c.modelURL(model, version, "generate_stream")
+
+	bodyBytes, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", sse.ContentType)
+
+	// Generations outlive the client timeout; the caller's context bounds them
+	streamClient := *c.httpClient
+	streamClient.Timeout = 0
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "triton")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.FromHTTPResponse(resp, "triton")
+	}
+
+	reader := sse.NewReader(resp.Body)
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return apperrors.FromTransportError(err, "triton")
+		}
+
+		var chunk map[string]interface{}
+		if err := json.Unmarshal(event.Data, &chunk); err != nil {
+			return fmt.Errorf("failed to decode generation response: %w", err)
+		}
+		// Triton reports failures mid-generation in the response itself
+		if message, ok := chunk["error"].(string); ok {
+			return apperrors.New(apperrors.Internal, "generation failed").WithDetails(message)
+		}
+		if err := emit(chunk); err != nil {
+			return err
+		}
+	}
+}
+
+// tritonVersion returns the number Triton knows a model version by, where
+// the platform names versions "v1"
+func tritonVersion(version string) string {
+	return strings.TrimPrefix(version, "v")
+}
+
+// modelURL returns the URL of endpoint of model's version, or of the model
+// when the version is empty
+func (c *Client) modelURL(model, version, endpoint string) string {
+	if version = tritonVersion(version); version != "" {
+		return fmt.Sprintf("%s/v2/models/%s/versions/%s/%s", c.baseURL, model, version, endpoint)
+	}
+	return fmt.Sprintf("%s/v2/models/%s/%s", c.baseURL, model, endpoint)
+}
+
 // HealthCheck checks if Triton is healthy
 func (c *Client) HealthCheck(ctx context.Context) error {
 	url := fmt.Sprintf("%s/v2/health/ready", c.baseURL)
@@ -136,3 +303,90 @@ func (c *Client) HealthCheck(ctx context.Context) error {
 
 	return nil
 }
+
+// ModelState is a model version in Triton's repository and whether it is loaded
+type ModelState struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	State   string `json:"state,omitempty"` // READY, UNAVAILABLE, LOADING or UNLOADING
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Models lists the model versions in Triton's repository with their load state
+func (c *Client) Models(ctx context.Context) ([]ModelState, error) {
+	url := fmt.Sprintf("%s/v2/repository/index", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBufferString("{}"))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, "triton")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apperrors.FromHTTPResponse(resp, "triton")
+	}
+
+	var models []ModelState
+	if err := json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return nil, fmt.Errorf("failed to decode repository index: %w", err)
+	}
+	return models, nil
+}
+
+// LoadModel loads a model from Triton's repository, or reloads it when it
+// is loaded, with the versions its configuration's version policy names.
+// Loads outlive the client timeout; ctx bounds them.
+func (c *Client) LoadModel(ctx context.Context, model string) error {
+	return c.repository(ctx, model, "load", map[string]interface{}{})
+}
+
+// UnloadModel unloads a model from Triton and, when dependents is set, the
+// models only it depends on, such as the steps of a Triton ensemble
+func (c *Client) UnloadModel(ctx context.Context, model string, dependents bool) error {
+	body := map[string]interface{}{}
+	if dependents {
+		body["parameters"] = map[string]interface{}{"unload_dependents": true}
+	}
+	return c.repository(ctx, model, "unload", body)
+}
+
+// repository runs an action of Triton's model repository API on a model.
+// Triton answers actions it cannot take, such as loading a model missing
+// from the repository, with 400; the request being only a model name, they
+// fail with FailedPrecondition.
+func (c *Client) repository(ctx context.Context, model, action string, body map[string]interface{}) error {
+	url := fmt.Sprintf("%s/v2/repository/models/%s/%s", c.baseURL, model, action)
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	repositoryClient := *c.httpClient
+	repositoryClient.Timeout = 0
+	resp, err := repositoryClient.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "triton")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		failure := apperrors.FromHTTPResponse(resp, "triton")
+		if failure.Code == apperrors.InvalidArgument {
+			failure.Code = apperrors.FailedPrecondition
+		}
+		return failure
+	}
+	return nil
+}
@@ -2,13 +2,19 @@ package triton
 
 import (
 	"context"
+	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
 )
 
 func TestNewClient(t *testing.T) {
@@ -49,24 +55,81 @@ func TestClient_BaseURL(t *testing.T) {
 	}
 }
 
-func TestClient_Infer(t *testing.T) {
-	logger, _ := zap.NewDevelopment()
-	client := NewClient(logger, "localhost:8001")
+func TestClient_Infer_FallsBackToHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/models/resnet18/versions/1/infer", r.URL.Path)
+		w.Write([]byte(`{"model_name":"resnet18","model_version":"1","outputs":[]}`))
+	}))
+	defer server.Close()
 
-	ctx := context.Background()
-	input := map[string]interface{}{"data": []float64{1.0, 2.0, 3.0}}
+	client := NewClient(zap.NewNop(), server.URL[7:])
+	// Nothing listens for gRPC, so every inference falls back to HTTP
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	target := listener.Addr().String()
+	listener.Close()
+	require.NoError(t, client.SetGRPC(target, 2))
+	defer client.Close()
+	assert.Len(t, client.grpc, 2)
+
+	for i := 0; i < 2; i++ {
+		result, err := client.Infer(context.Background(), "resnet18", "v1", map[string]interface{}{"data": []interface{}{1.0}})
+		require.NoError(t, err)
+		assert.Equal(t, "resnet18", result["model_name"])
+	}
+}
 
-	result, err := client.Infer(ctx, "resnet18", "v1", input)
-	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, "resnet18", result["model_name"])
-	assert.Equal(t, "v1", result["model_version"])
-	assert.NotNil(t, result["prediction"])
+func TestClient_InferStream_FallsBackToHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/models/llama/versions/1/generate_stream", r.URL.Path)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"text_output\":\"Hello\"}\n\n"))
+	}))
+	defer server.Close()
+
+	client := NewClient(zap.NewNop(), server.URL[7:])
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	target := listener.Addr().String()
+	listener.Close()
+	require.NoError(t, client.SetGRPC(target, 1))
+	defer client.Close()
+
+	var chunks []map[string]interface{}
+	err = client.InferStream(context.Background(), "llama", "v1", map[string]interface{}{"text_input": "Hi"}, func(chunk map[string]interface{}) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"text_output": "Hello"}}, chunks)
+}
+
+func TestClient_InferHTTP_SendsV2Request(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req InferRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "req-1", req.ID)
+		assert.Equal(t, []map[string]interface{}{
+			{"name": "data", "datatype": "FP32", "shape": []interface{}{2.0, 2.0}, "data": []interface{}{1.0, 2.0, 3.0, 4.0}},
+		}, req.Inputs)
+		w.Write([]byte(`{"model_name":"resnet18","outputs":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(zap.NewNop(), server.URL[7:])
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	_, err := client.InferHTTP(ctx, "resnet18", "", map[string]interface{}{
+		"data": []interface{}{[]interface{}{1.0, 2.0}, []interface{}{3.0, 4.0}},
+	})
+	require.NoError(t, err)
+
+	_, err = client.InferHTTP(ctx, "resnet18", "", map[string]interface{}{"data": []interface{}{1.0, "two"}})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument), "malformed inputs are not sent")
 }
 
 func TestClient_InferHTTP_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "/v2/models/resnet18/infer", r.URL.Path)
+		assert.Equal(t, "/v2/models/resnet18/versions/1/infer", r.URL.Path)
 		assert.Equal(t, "POST", r.Method)
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(`{"model_name":"resnet18","outputs":[{"class":"cat"}]}`))
@@ -77,7 +140,7 @@ func TestClient_InferHTTP_Success(t *testing.T) {
 	client := NewClient(logger, server.URL[7:]) // Remove "http://" prefix
 
 	ctx := context.Background()
-	input := map[string]interface{}{"data": []float64{1.0}}
+	input := map[string]interface{}{"data": []interface{}{1.0}}
 
 	result, err := client.InferHTTP(ctx, "resnet18", "v1", input)
 	assert.NoError(t, err)
@@ -95,7 +158,7 @@ func TestClient_InferHTTP_Error(t *testing.T) {
 	client := NewClient(logger, server.URL[7:])
 
 	ctx := context.Background()
-	input := map[string]interface{}{"data": []float64{1.0}}
+	input := map[string]interface{}{"data": []interface{}{1.0}}
 
 	_, err := client.InferHTTP(ctx, "unknown", "v1", input)
 	assert.Error(t, err)
@@ -140,3 +203,52 @@ func TestClient_ContextCancellation(t *testing.T) {
 	err := client.HealthCheck(ctx)
 	assert.Error(t, err)
 }
+
+func TestClient_Models(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/repository/index", r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+		w.Write([]byte(`[{"name":"resnet18","version":"1","state":"READY"},{"name":"bert","version":"1","state":"UNAVAILABLE","reason":"unloaded"}]`))
+	}))
+	defer server.Close()
+
+	logger, _ := zap.NewDevelopment()
+	client := NewClient(logger, server.URL[7:])
+
+	models, err := client.Models(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []ModelState{
+		{Name: "resnet18", Version: "1", State: "READY"},
+		{Name: "bert", Version: "1", State: "UNAVAILABLE", Reason: "unloaded"},
+	}, models)
+}
+
+func TestClient_LoadAndUnloadModel(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		switch r.URL.Path {
+		case "/v2/repository/models/resnet18/load", "/v2/repository/models/resnet18/unload":
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"failed to load 'missing', failed to poll from model repository"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(zap.NewNop(), server.URL[7:])
+	require.NoError(t, client.LoadModel(context.Background(), "resnet18"))
+	require.NoError(t, client.UnloadModel(context.Background(), "resnet18", true))
+	assert.Equal(t, []map[string]interface{}{
+		{},
+		{"parameters": map[string]interface{}{"unload_dependents": true}},
+	}, bodies)
+
+	// A model Triton cannot load is not the caller's mistake
+	err := client.LoadModel(context.Background(), "missing")
+	assert.True(t, apperrors.Is(err, apperrors.FailedPrecondition))
+	assert.Contains(t, err.Error(), "failed to poll from model repository")
+}
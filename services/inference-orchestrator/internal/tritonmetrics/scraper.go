@@ -0,0 +1,323 @@
+// Package tritonmetrics scrapes Triton's Prometheus endpoint for GPU
+// utilization, queue time and batch sizes, and re-exports them under the
+// platform's model, version and backend labels so routing and autoscaling
+// can read them from the orchestrator.
+package tritonmetrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/backend"
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/observability"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// DefaultInterval is how often Triton's metrics are scraped
+const DefaultInterval = 15 * time.Second
+
+// Triton's metrics read by the scraper
+const (
+	gpuUtilizationMetric  = "nv_gpu_utilization"
+	gpuMemoryUsedMetric   = "nv_gpu_memory_used_bytes"
+	requestSuccessMetric  = "nv_inference_request_success"
+	inferenceCountMetric  = "nv_inference_count"
+	executionCountMetric  = "nv_inference_exec_count"
+	queueDurationUsMetric = "nv_inference_queue_duration_us"
+)
+
+// ModelStats is what Triton reports of a model version between the last
+// two scrapes; both are 0 when it ran nothing
+type ModelStats struct {
+	// QueueSeconds is the average time a request waited in Triton's queue
+	QueueSeconds float64
+	// BatchSize is the average number of inferences in each execution
+	BatchSize float64
+}
+
+type modelKey struct {
+	model   string
+	version string
+}
+
+// counters are Triton's cumulative counters of a model version
+type counters struct {
+	requests   float64
+	inferences float64
+	executions float64
+	queueUs    float64
+}
+
+// gpu is what Triton reports of one GPU
+type gpu struct {
+	utilization float64
+	memoryUsed  float64
+}
+
+// Scraper periodically scrapes a Triton metrics endpoint. Queue time and
+// batch sizes are Triton counters, so they are measured between scrapes and
+// known from the second scrape on. A nil Scraper reports nothing.
+type Scraper struct {
+	url      string
+	client   *http.Client
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu       sync.RWMutex
+	counters map[modelKey]counters
+	stats    map[modelKey]ModelStats
+	gpus     map[string]gpu
+}
+
+// NewScraper creates a scraper of the Triton metrics endpoint at tritonURL,
+// "host:port", that scrapes every interval
+func NewScraper(logger *zap.Logger, tritonURL string, interval time.Duration) *Scraper {
+	return &Scraper{
+		url:      "http://" + tritonURL + "/metrics",
+		client:   &http.Client{Timeout: 10 * time.Second},
+		interval: interval,
+		logger:   logger,
+		counters: make(map[modelKey]counters),
+		stats:    make(map[modelKey]ModelStats),
+		gpus:     make(map[string]gpu),
+	}
+}
+
+// Run scrapes Triton every interval until ctx is done. Failed scrapes are
+// logged and leave the last values in place.
+func (s *Scraper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Scrape(ctx); err != nil && ctx.Err() == nil {
+			observability.TritonScrapeErrors.Inc()
+			s.logger.Warn("failed to scrape triton metrics", zap.String("url", s.url), zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scrape reads Triton's metrics once and re-exports them
+func (s *Scraper) Scrape(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "triton")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.FromHTTPResponse(resp, "triton")
+	}
+
+	models := make(map[modelKey]counters)
+	gpus := make(map[string]gpu)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		sample, ok := parseSample(scanner.Text())
+		if !ok {
+			continue
+		}
+		switch sample.name {
+		case gpuUtilizationMetric, gpuMemoryUsedMetric:
+			id := sample.labels["gpu_uuid"]
+			g := gpus[id]
+			if sample.name == gpuUtilizationMetric {
+				g.utilization = sample.value
+			} else {
+				g.memoryUsed = sample.value
+			}
+			gpus[id] = g
+		case requestSuccessMetric, inferenceCountMetric, executionCountMetric, queueDurationUsMetric:
+			key := modelKey{model: sample.labels["model"], version: sample.labels["version"]}
+			c := models[key]
+			switch sample.name {
+			case requestSuccessMetric:
+				c.requests = sample.value
+			case inferenceCountMetric:
+				c.inferences = sample.value
+			case executionCountMetric:
+				c.executions = sample.value
+			default:
+				c.queueUs = sample.value
+			}
+			models[key] = c
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read triton metrics: %w", err)
+	}
+
+	s.update(models, gpus)
+	return nil
+}
+
+// update measures each model version against the previous scrape and
+// re-exports the result, dropping the series of models and GPUs Triton no
+// longer reports
+func (s *Scraper) update(models map[modelKey]counters, gpus map[string]gpu) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make(map[modelKey]ModelStats, len(models))
+	for key, current := range models {
+		previous, ok := s.counters[key]
+		// Counters restart with Triton; the next scrape measures again
+		if !ok || current.requests < previous.requests || current.executions < previous.executions {
+			continue
+		}
+		var st ModelStats
+		if requests := current.requests - previous.requests; requests > 0 {
+			st.QueueSeconds = (current.queueUs - previous.queueUs) / requests / 1e6
+		}
+		if executions := current.executions - previous.executions; executions > 0 {
+			st.BatchSize = (current.inferences - previous.inferences) / executions
+		}
+		stats[key] = st
+		observability.TritonQueueTime.WithLabelValues(key.model, key.version, backend.Triton).Set(st.QueueSeconds)
+		observability.TritonBatchSize.WithLabelValues(key.model, key.version, backend.Triton).Set(st.BatchSize)
+	}
+	for key := range s.stats {
+		if _, ok := stats[key]; !ok {
+			observability.TritonQueueTime.DeleteLabelValues(key.model, key.version, backend.Triton)
+			observability.TritonBatchSize.DeleteLabelValues(key.model, key.version, backend.Triton)
+		}
+	}
+
+	for id, g := range gpus {
+		observability.TritonGPUUtilization.WithLabelValues(id).Set(g.utilization)
+		observability.TritonGPUMemoryUsed.WithLabelValues(id).Set(g.memoryUsed)
+	}
+	for id := range s.gpus {
+		if _, ok := gpus[id]; !ok {
+			observability.TritonGPUUtilization.DeleteLabelValues(id)
+			observability.TritonGPUMemoryUsed.DeleteLabelValues(id)
+		}
+	}
+
+	s.counters = models
+	s.stats = stats
+	s.gpus = gpus
+}
+
+// Model returns what Triton last reported of a model version
+func (s *Scraper) Model(model, version string) (ModelStats, bool) {
+	if s == nil {
+		return ModelStats{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	st, ok := s.stats[modelKey{model: model, version: version}]
+	return st, ok
+}
+
+// GPUUtilization returns the average utilization of Triton's GPUs, between
+// 0 and 1
+func (s *Scraper) GPUUtilization() (float64, bool) {
+	if s == nil {
+		return 0, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.gpus) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, g := range s.gpus {
+		sum += g.utilization
+	}
+	return sum / float64(len(s.gpus)), true
+}
+
+// sample is one line of the Prometheus text format
+type sample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+// parseSample parses a Prometheus text-format line, skipping comments
+func parseSample(line string) (sample, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] == '#' {
+		return sample{}, false
+	}
+	end := strings.IndexAny(line, "{ ")
+	if end <= 0 {
+		return sample{}, false
+	}
+	s := sample{name: line[:end], labels: make(map[string]string)}
+	rest := line[end:]
+	if rest[0] == '{' {
+		n, ok := parseLabels(rest[1:], s.labels)
+		if !ok {
+			return sample{}, false
+		}
+		rest = rest[1+n:]
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return sample{}, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return sample{}, false
+	}
+	s.value = value
+	return s, true
+}
+
+// parseLabels reads `name="value",...}` into labels, returning how much of
+// text it read
+func parseLabels(text string, labels map[string]string) (int, bool) {
+	i := 0
+	for {
+		for i < len(text) && (text[i] == ' ' || text[i] == ',') {
+			i++
+		}
+		if i >= len(text) {
+			return 0, false
+		}
+		if text[i] == '}' {
+			return i + 1, true
+		}
+		eq := strings.IndexByte(text[i:], '=')
+		if eq < 0 || i+eq+1 >= len(text) || text[i+eq+1] != '"' {
+			return 0, false
+		}
+		name := strings.TrimSpace(text[i : i+eq])
+		i += eq + 2
+
+		var value strings.Builder
+		for ; i < len(text) && text[i] != '"'; i++ {
+			if text[i] == '\\' && i+1 < len(text) {
+				i++
+				if text[i] == 'n' {
+					value.WriteByte('\n')
+					continue
+				}
+			}
+			value.WriteByte(text[i])
+		}
+		if i >= len(text) {
+			return 0, false
+		}
+		i++
+		labels[name] = value.String()
+	}
+}
@@ -0,0 +1,100 @@
+package tritonmetrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/inference-orchestrator/internal/observability"
+)
+
+// tritonMetrics renders Triton's metrics for resnet18 version 1 and two GPUs
+func tritonMetrics(requests, inferences, executions, queueUs float64) string {
+	return fmt.Sprintf(`# HELP nv_inference_request_success Number of successful inference requests, all batch sizes
+# TYPE nv_inference_request_success counter
+nv_inference_request_success{model="resnet18",version="1"} %g
+nv_inference_count{model="resnet18",version="1"} %g
+nv_inference_exec_count{model="resnet18",version="1"} %g
+nv_inference_queue_duration_us{model="resnet18",version="1"} %g
+# HELP nv_gpu_utilization GPU utilization rate [0.0 - 1.0)
+# TYPE nv_gpu_utilization gauge
+nv_gpu_utilization{gpu_uuid="GPU-a"} 0.5
+nv_gpu_utilization{gpu_uuid="GPU-b"} 0.9
+nv_gpu_memory_used_bytes{gpu_uuid="GPU-a"} 1024
+`, requests, inferences, executions, queueUs)
+}
+
+func TestScraper_MeasuresBetweenScrapes(t *testing.T) {
+	body := tritonMetrics(10, 40, 5, 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/metrics", r.URL.Path)
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	scraper := NewScraper(zap.NewNop(), strings.TrimPrefix(server.URL, "http://"), time.Minute)
+	require.NoError(t, scraper.Scrape(context.Background()))
+	_, ok := scraper.Model("resnet18", "1")
+	assert.False(t, ok, "counters are not measured until the second scrape")
+	utilization, ok := scraper.GPUUtilization()
+	require.True(t, ok)
+	assert.InDelta(t, 0.7, utilization, 1e-9)
+
+	// 20 requests waited 0.5s in all; 80 inferences ran in 10 executions
+	body = tritonMetrics(30, 120, 15, 501000)
+	require.NoError(t, scraper.Scrape(context.Background()))
+	stats, ok := scraper.Model("resnet18", "1")
+	require.True(t, ok)
+	assert.InDelta(t, 0.025, stats.QueueSeconds, 1e-9)
+	assert.InDelta(t, 8, stats.BatchSize, 1e-9)
+	assert.InDelta(t, 8, testutil.ToFloat64(observability.TritonBatchSize.WithLabelValues("resnet18", "1", "triton")), 1e-9)
+	assert.Equal(t, 1024.0, testutil.ToFloat64(observability.TritonGPUMemoryUsed.WithLabelValues("GPU-a")))
+
+	// Triton restarted: its counters start over
+	body = tritonMetrics(2, 2, 2, 10)
+	require.NoError(t, scraper.Scrape(context.Background()))
+	_, ok = scraper.Model("resnet18", "1")
+	assert.False(t, ok)
+}
+
+func TestScraper_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	scraper := NewScraper(zap.NewNop(), strings.TrimPrefix(server.URL, "http://"), time.Minute)
+	assert.Error(t, scraper.Scrape(context.Background()))
+
+	var nilScraper *Scraper
+	_, ok := nilScraper.Model("resnet18", "1")
+	assert.False(t, ok)
+	_, ok = nilScraper.GPUUtilization()
+	assert.False(t, ok)
+}
+
+func TestParseSample(t *testing.T) {
+	s, ok := parseSample(`nv_inference_count{model="a \"quoted\", model",version="1"} 42 1700000000000`)
+	require.True(t, ok)
+	assert.Equal(t, "nv_inference_count", s.name)
+	assert.Equal(t, map[string]string{"model": `a "quoted", model`, "version": "1"}, s.labels)
+	assert.Equal(t, 42.0, s.value)
+
+	s, ok = parseSample("nv_energy_consumption 1.5e3")
+	require.True(t, ok)
+	assert.Equal(t, 1500.0, s.value)
+
+	for _, line := range []string{"", "# TYPE nv_inference_count counter", `nv_x{model="a} 1`, "nv_x{} NaNa"} {
+		_, ok := parseSample(line)
+		assert.False(t, ok, line)
+	}
+}
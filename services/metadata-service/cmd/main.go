@@ -9,12 +9,26 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/IBM/sarama"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/yourusername/ai-platform/metadata-service/internal/cache"
 	"github.com/yourusername/ai-platform/metadata-service/internal/config"
 	"github.com/yourusername/ai-platform/metadata-service/internal/handlers"
+	"github.com/yourusername/ai-platform/metadata-service/internal/replication"
 	"github.com/yourusername/ai-platform/metadata-service/internal/repository"
+	"github.com/yourusername/ai-platform/metadata-service/internal/serving"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/faults"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/secrets"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+	"github.com/yourusername/ai-platform/pkg/tiered"
+	"github.com/yourusername/ai-platform/pkg/transport"
 	"go.uber.org/zap"
 )
 
@@ -33,28 +47,147 @@ func main() {
 		zap.String("port", cfg.Port),
 	)
 
+	// Resolve credentials from Vault or a mounted secret store when configured
+	secretProvider, err := secrets.FromEnv(logger)
+	if err != nil {
+		logger.Fatal("failed to initialize secret provider", zap.Error(err))
+	}
+	secretManager := secrets.NewManager(secretProvider, logger)
+	defer secretManager.Close()
+
+	postgresURLRef := cfg.SecretsPath + "#postgres_url"
+	cfg.PostgresURL = secretManager.LookupOr(context.Background(), postgresURLRef, cfg.PostgresURL)
+	if cfg.PostgresURL == "" {
+		logger.Fatal("postgres is not configured; set POSTGRES_URL or provide it via VAULT_ADDR")
+	}
+
+	// Load the SPIFFE workload identity for mTLS between services
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+	}
+
 	// Initialize PostgreSQL repository
 	repo, err := repository.NewModelRepository(cfg.PostgresURL, logger)
 	if err != nil {
 		logger.Fatal("failed to initialize repository", zap.Error(err))
 	}
 	defer repo.Close()
+	repo.SetRegion(cfg.Region)
 	logger.Info("connected to PostgreSQL")
 
+	// Reconnect when the database credentials rotate
+	secretManager.Watch(context.Background(), postgresURLRef, 5*time.Minute, func(url string) {
+		if err := repo.Reconnect(url); err != nil {
+			logger.Error("failed to reconnect to postgres with rotated credentials", zap.Error(err))
+		}
+	})
+
 	// Initialize Redis cache
 	redisClient := config.NewRedisClient(cfg.RedisHost)
 	defer redisClient.Close()
 
-	// Test Redis connection
+	// Test Redis connection; lookups fall back to the local cache tier while
+	// Redis is unavailable, so it is not required to start
 	if err := redisClient.Ping(context.Background()).Err(); err != nil {
-		logger.Fatal("failed to connect to redis", zap.Error(err))
+		logger.Warn("redis unavailable, caching locally until it recovers", zap.Error(err))
+	} else {
+		logger.Info("connected to Redis")
 	}
-	logger.Info("connected to Redis")
 
-	modelCache := cache.NewModelCache(redisClient, logger)
+	modelCache := cache.NewModelCacheWithOptions(redisClient, tiered.Options{
+		LocalSize: cfg.CacheLocalSize,
+		LocalTTL:  cfg.CacheLocalTTL,
+		StaleTTL:  cfg.CacheStaleTTL,
+		RemoteTTL: 15 * time.Minute,
+	}, logger)
+
+	// Readiness requires the database; a Redis outage only degrades the cache
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("postgres", repo.Ping)
+
+	// Pull registry changes from peer regions; lookups the local registry cannot
+	// answer fall back to peers in the configured affinity order
+	peers, err := replication.ParsePeers(cfg.ReplicationPeers)
+	if err != nil {
+		logger.Fatal("invalid replication peers", zap.Error(err))
+	}
+	var replicator *replication.Replicator
+	if len(peers) > 0 {
+		replicator = replication.NewReplicator(cfg.Region, peers, repo, modelCache, cfg.ReplicationInterval, logger)
+		if identity != nil {
+			replicator.SetHTTPClient(identity.HTTPClient("metadata-service", 10*time.Second))
+		}
+		logger.Info("replicating model registry",
+			zap.String("region", cfg.Region),
+			zap.Int("peers", len(peers)),
+		)
+	}
 
 	// Initialize handlers
 	modelHandler := handlers.NewModelHandler(repo, modelCache, logger)
+	if replicator != nil {
+		modelHandler.SetFallback(replicator)
+	}
+
+	// Announce model promotions to the notification service when Kafka is configured
+	var eventEmitter *events.Emitter
+	if len(cfg.KafkaBrokers) > 0 {
+		kafkaProducer, err := events.NewKafkaProducer(cfg.KafkaBrokers)
+		if err != nil {
+			logger.Fatal("failed to initialize kafka producer", zap.Error(err))
+		}
+		defer kafkaProducer.Close()
+
+		schemaCodec := schema.FromEnv()
+		if err := schemaCodec.Register(context.Background(), schema.PlatformEvents); apperrors.Is(err, apperrors.FailedPrecondition) {
+			logger.Fatal("message schema is incompatible with the registry", zap.Error(err))
+		} else if err != nil {
+			logger.Warn("failed to register message schemas", zap.Error(err))
+		}
+
+		eventEmitter = events.NewEmitter(cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+			value, err := schemaCodec.Frame(ctx, schema.PlatformEvents, value)
+			if err != nil {
+				return err
+			}
+			_, _, err = kafkaProducer.SendMessage(&sarama.ProducerMessage{
+				Topic: cfg.EventTopic,
+				Key:   sarama.StringEncoder(key),
+				Value: sarama.ByteEncoder(value),
+			})
+			return err
+		}), 1000, logger)
+		modelHandler.SetEventEmitter(eventEmitter)
+	}
+
+	// Check tenants and their model quota when a tenant service is configured
+	if cfg.TenantServiceURL != "" {
+		tenantClient := &http.Client{Timeout: 5 * time.Second}
+		if identity != nil {
+			tenantClient = identity.HTTPClient("tenant-service", 5*time.Second)
+		}
+		modelHandler.SetTenants(tenancy.NewClient(cfg.TenantServiceURL, tenantClient, cfg.TenantCacheTTL))
+	}
+
+	// Load versions on the serving cluster as they are registered or activated
+	if len(cfg.OrchestratorURLs) > 0 {
+		loader := serving.NewLoader(cfg.OrchestratorURLs, logger)
+		if identity != nil {
+			loader.SetHTTPClient(identity.HTTPClient("inference-orchestrator", serving.DefaultTimeout))
+		}
+		modelHandler.SetLoader(loader)
+	}
+	replicationHandler := handlers.NewReplicationHandler(repo, replicator, cfg.Region, logger)
+
+	// Inject faults for resilience testing; a no-op unless rules are configured
+	faultInjector, err := faults.FromEnv(context.Background(), cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load fault injection rules", zap.Error(err))
+	}
 
 	// Setup router
 	if cfg.LogLevel == "production" {
@@ -64,8 +197,10 @@ func main() {
 	router.Use(gin.Recovery())
 	router.Use(gin.Logger())
 
-	// Health check
-	router.GET("/health", modelHandler.HealthCheck)
+	// Health checks
+	router.GET("/health", gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.LivenessPath, gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.ReadinessPath, gin.WrapH(checker.ReadinessHandler()))
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	// API v1 routes
@@ -80,13 +215,51 @@ func main() {
 			models.PUT("/:id", modelHandler.UpdateModel)
 			models.DELETE("/:id", modelHandler.DeleteModel)
 			models.GET("/by-name/:name/:version", modelHandler.GetModelByNameVersion)
+			models.PUT("/:id/baseline", modelHandler.SetBaseline)
+			models.GET("/:id/baseline", modelHandler.GetBaseline)
+			models.GET("/by-name/:name/:version/baseline", modelHandler.GetBaselineByNameVersion)
+		}
+
+		// Autoscaling policies per model
+		policies := v1.Group("/scaling-policies")
+		{
+			policies.GET("", modelHandler.ListScalingPolicies)
+			policies.GET("/:model", modelHandler.GetScalingPolicy)
+			policies.PUT("/:model", modelHandler.SetScalingPolicy)
+			policies.DELETE("/:model", modelHandler.DeleteScalingPolicy)
 		}
+
+		// Canary traffic splits between a model's versions
+		splits := v1.Group("/traffic-splits")
+		{
+			splits.GET("", modelHandler.ListTrafficSplits)
+			splits.GET("/:model", modelHandler.GetTrafficSplit)
+			splits.PUT("/:model", modelHandler.SetTrafficSplit)
+			splits.DELETE("/:model", modelHandler.DeleteTrafficSplit)
+		}
+
+		// Version aliases such as "stable", routed to the version they point at
+		aliases := v1.Group("/aliases")
+		{
+			aliases.GET("", modelHandler.ListAliases)
+			aliases.GET("/:model/:alias", modelHandler.GetAlias)
+			aliases.PUT("/:model/:alias", modelHandler.SetAlias)
+			aliases.DELETE("/:model/:alias", modelHandler.DeleteAlias)
+		}
+
+		// Multi-region replication
+		v1.GET("/replication/changes", replicationHandler.Changes)
+		v1.GET("/replication/status", replicationHandler.Status)
+	}
+
+	if faultInjector.Enabled() {
+		router.Any(faults.AdminPath, gin.WrapH(faultInjector.AdminHandler()))
 	}
 
 	// Create HTTP server
 	srv := &http.Server{
 		Addr:         ":" + cfg.Port,
-		Handler:      router,
+		Handler:      logging.Middleware(faultInjector.Middleware(router)),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -95,11 +268,25 @@ func main() {
 	// Start server in goroutine
 	go func() {
 		logger.Info("starting metadata service", zap.String("port", cfg.Port))
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := transport.ListenAndServe(srv, identity); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("failed to start server", zap.Error(err))
 		}
 	}()
 
+	replicationCtx, stopReplication := context.WithCancel(context.Background())
+	defer stopReplication()
+	if replicator != nil {
+		replicator.Start(replicationCtx)
+	}
+	eventCtx, stopEvents := context.WithCancel(context.Background())
+	eventsDone := make(chan struct{})
+	go func() {
+		if eventEmitter != nil {
+			eventEmitter.Run(eventCtx)
+		}
+		close(eventsDone)
+	}()
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -114,6 +301,8 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Fatal("server forced to shutdown", zap.Error(err))
 	}
+	stopEvents()
+	<-eventsDone
 
 	logger.Info("server exited")
 }
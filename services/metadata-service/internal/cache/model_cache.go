@@ -8,34 +8,42 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/yourusername/ai-platform/metadata-service/internal/models"
+	"github.com/yourusername/ai-platform/pkg/tiered"
 	"go.uber.org/zap"
 )
 
-// ModelCache handles Redis caching for model metadata
+// ModelCache caches model metadata in a local tier in front of Redis. When
+// Redis is unavailable lookups are served from the local tier, so an outage
+// degrades to slightly stale metadata rather than every lookup hitting
+// PostgreSQL.
 type ModelCache struct {
 	client *redis.Client
-	ttl    time.Duration
+	tiers  *tiered.Cache
 	logger *zap.Logger
 }
 
-// NewModelCache creates a new model cache
+// NewModelCache creates a new model cache with the default tier options
 func NewModelCache(client *redis.Client, logger *zap.Logger) *ModelCache {
+	return NewModelCacheWithOptions(client, tiered.Options{
+		RemoteTTL: 15 * time.Minute, // Cache for 15 minutes
+	}, logger)
+}
+
+// NewModelCacheWithOptions creates a model cache with the given tier options
+func NewModelCacheWithOptions(client *redis.Client, opts tiered.Options, logger *zap.Logger) *ModelCache {
 	return &ModelCache{
 		client: client,
-		ttl:    15 * time.Minute, // Cache for 15 minutes
+		tiers:  tiered.New(tiered.Redis(client), opts, logger),
 		logger: logger,
 	}
 }
 
 // Get retrieves a model from cache
 func (c *ModelCache) Get(ctx context.Context, key string) (*models.ModelMetadata, error) {
-	data, err := c.client.Get(ctx, c.modelKey(key)).Bytes()
-	if err == redis.Nil {
+	data, ok := c.tiers.Get(ctx, c.modelKey(key))
+	if !ok {
 		return nil, nil // Cache miss
 	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to get from cache: %w", err)
-	}
 
 	var model models.ModelMetadata
 	if err := json.Unmarshal(data, &model); err != nil {
@@ -54,9 +62,7 @@ func (c *ModelCache) Set(ctx context.Context, key string, model *models.ModelMet
 		return fmt.Errorf("failed to marshal model: %w", err)
 	}
 
-	if err := c.client.Set(ctx, c.modelKey(key), data, c.ttl).Err(); err != nil {
-		return fmt.Errorf("failed to set cache: %w", err)
-	}
+	c.tiers.Set(ctx, c.modelKey(key), data)
 
 	c.logger.Debug("cache set", zap.String("key", key))
 
@@ -65,17 +71,18 @@ func (c *ModelCache) Set(ctx context.Context, key string, model *models.ModelMet
 
 // Delete removes a model from cache
 func (c *ModelCache) Delete(ctx context.Context, key string) error {
-	if err := c.client.Del(ctx, c.modelKey(key)).Err(); err != nil {
-		return fmt.Errorf("failed to delete from cache: %w", err)
-	}
+	c.tiers.Delete(ctx, c.modelKey(key))
 
 	c.logger.Debug("cache deleted", zap.String("key", key))
 
 	return nil
 }
 
-// DeleteByPattern deletes all keys matching a pattern
+// DeleteByPattern deletes all keys matching a pattern. The local tier cannot
+// be matched against a Redis pattern, so it is dropped entirely.
 func (c *ModelCache) DeleteByPattern(ctx context.Context, pattern string) error {
+	c.tiers.Clear()
+
 	iter := c.client.Scan(ctx, 0, c.modelKey(pattern), 0).Iterator()
 	for iter.Next(ctx) {
 		if err := c.client.Del(ctx, iter.Val()).Err(); err != nil {
@@ -90,6 +97,11 @@ func (c *ModelCache) DeleteByPattern(ctx context.Context, pattern string) error
 	return nil
 }
 
+// Degraded reports whether Redis is being bypassed after failing
+func (c *ModelCache) Degraded() bool {
+	return c.tiers.Degraded()
+}
+
 // modelKey generates a cache key for a model
 func (c *ModelCache) modelKey(key string) string {
 	return fmt.Sprintf("model:%s", key)
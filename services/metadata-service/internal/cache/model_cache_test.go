@@ -99,3 +99,30 @@ func TestModelCache_KeyGeneration(t *testing.T) {
 	key := cache.modelKey("test-id")
 	assert.Equal(t, "model:test-id", key)
 }
+
+func TestModelCache_RedisUnavailable(t *testing.T) {
+	client := redis.NewClient(&redis.Options{
+		Addr:       "127.0.0.1:1",
+		MaxRetries: -1,
+	})
+	defer client.Close()
+
+	cache := NewModelCache(client, zap.NewNop())
+	ctx := context.Background()
+
+	model := &models.ModelMetadata{ID: "test-model-1", Name: "resnet18", Version: "v1"}
+	assert.NoError(t, cache.Set(ctx, model.ID, model))
+	assert.True(t, cache.Degraded())
+
+	// Lookups are served from the local tier while Redis is down
+	retrieved, err := cache.Get(ctx, model.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, retrieved) {
+		assert.Equal(t, model.Name, retrieved.Name)
+	}
+
+	assert.NoError(t, cache.Delete(ctx, model.ID))
+	retrieved, err = cache.Get(ctx, model.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, retrieved)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/traffic"
+	"go.uber.org/zap"
+)
+
+// SetAlias points one of a model's version aliases at a version
+func (h *ModelHandler) SetAlias(c *gin.Context) {
+	model, name := c.Param("model"), c.Param("alias")
+
+	var alias traffic.Alias
+	if err := c.ShouldBindJSON(&alias); err != nil {
+		c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error())))
+		return
+	}
+
+	saved, err := h.repo.SetAlias(c.Request.Context(), model, name, &alias)
+	if err != nil {
+		h.log(c).Error("failed to set alias", zap.String("model", model), zap.String("alias", name), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to set alias")))
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// GetAlias returns the version a model's alias points at
+func (h *ModelHandler) GetAlias(c *gin.Context) {
+	model, name := c.Param("model"), c.Param("alias")
+
+	alias, err := h.repo.GetAlias(c.Request.Context(), model, name)
+	if err != nil {
+		h.log(c).Warn("failed to get alias", zap.String("model", model), zap.String("alias", name), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to get alias")))
+		return
+	}
+
+	c.JSON(http.StatusOK, alias)
+}
+
+// ListAliases returns every version alias
+func (h *ModelHandler) ListAliases(c *gin.Context) {
+	aliases, err := h.repo.ListAliases(c.Request.Context())
+	if err != nil {
+		h.log(c).Error("failed to list aliases", zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to list aliases")))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"aliases": aliases,
+		"count":   len(aliases),
+	})
+}
+
+// DeleteAlias removes a model's version alias
+func (h *ModelHandler) DeleteAlias(c *gin.Context) {
+	model, name := c.Param("model"), c.Param("alias")
+
+	if err := h.repo.DeleteAlias(c.Request.Context(), model, name); err != nil {
+		h.log(c).Error("failed to delete alias", zap.String("model", model), zap.String("alias", name), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to delete alias")))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "alias deleted successfully"})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/drift"
+	"go.uber.org/zap"
+)
+
+// SetBaseline registers the training distributions drift is measured against
+func (h *ModelHandler) SetBaseline(c *gin.Context) {
+	id := c.Param("id")
+
+	var baseline drift.Baseline
+	if err := c.ShouldBindJSON(&baseline); err != nil {
+		c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error())))
+		return
+	}
+
+	saved, err := h.repo.SetBaseline(c.Request.Context(), id, &baseline)
+	if err != nil {
+		h.log(c).Error("failed to set baseline", zap.String("id", id), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to set baseline")))
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// GetBaseline returns a model's training baseline
+func (h *ModelHandler) GetBaseline(c *gin.Context) {
+	id := c.Param("id")
+
+	baseline, err := h.repo.GetBaseline(c.Request.Context(), id)
+	if err != nil {
+		h.log(c).Warn("failed to get baseline", zap.String("id", id), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to get baseline")))
+		return
+	}
+
+	c.JSON(http.StatusOK, baseline)
+}
+
+// GetBaselineByNameVersion returns a model version's training baseline
+func (h *ModelHandler) GetBaselineByNameVersion(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	baseline, err := h.repo.GetBaselineByNameVersion(c.Request.Context(), name, version)
+	if err != nil {
+		h.log(c).Warn("failed to get baseline",
+			zap.String("name", name),
+			zap.String("version", version),
+			zap.Error(err),
+		)
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to get baseline")))
+		return
+	}
+
+	c.JSON(http.StatusOK, baseline)
+}
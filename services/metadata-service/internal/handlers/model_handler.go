@@ -1,21 +1,52 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/ai-platform/metadata-service/internal/cache"
 	"github.com/yourusername/ai-platform/metadata-service/internal/models"
+	"github.com/yourusername/ai-platform/metadata-service/internal/replication"
 	"github.com/yourusername/ai-platform/metadata-service/internal/repository"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
 	"go.uber.org/zap"
 )
 
+// ServedRegionHeader names the peer region that answered a lookup the local registry could not
+const ServedRegionHeader = "X-Served-Region"
+
+// QuarantinedStatus marks models whose uploaded artifact has not passed a scan
+const QuarantinedStatus = "quarantined"
+
+// ModelResolver looks models up outside the local registry, e.g. in peer regions
+type ModelResolver interface {
+	Resolve(ctx context.Context, name, version string) (*models.ModelMetadata, string, error)
+}
+
+// TenantAuthorizer reports whether a tenant may use the platform and with what limits
+type TenantAuthorizer interface {
+	Authorize(ctx context.Context, id string) (*tenancy.Tenant, error)
+}
+
+// ModelLoader loads model versions on the serving cluster
+type ModelLoader interface {
+	Load(ctx context.Context, model *models.ModelMetadata)
+}
+
 // ModelHandler handles model metadata HTTP requests
 type ModelHandler struct {
-	repo   *repository.ModelRepository
-	cache  *cache.ModelCache
-	logger *zap.Logger
+	repo     *repository.ModelRepository
+	cache    *cache.ModelCache
+	logger   *zap.Logger
+	fallback ModelResolver
+	events   *events.Emitter
+	tenants  TenantAuthorizer
+	loader   ModelLoader
 }
 
 // NewModelHandler creates a new model handler
@@ -27,25 +58,104 @@ func NewModelHandler(repo *repository.ModelRepository, cache *cache.ModelCache,
 	}
 }
 
+// SetFallback enables resolving models through other regions when the local
+// registry misses (replication lag) or is unavailable (regional outage)
+func (h *ModelHandler) SetFallback(resolver ModelResolver) {
+	h.fallback = resolver
+}
+
+// SetEventEmitter sets where model promotions are announced
+func (h *ModelHandler) SetEventEmitter(emitter *events.Emitter) {
+	h.events = emitter
+}
+
+// SetLoader loads model versions on the serving cluster as they are
+// registered active or activated
+func (h *ModelHandler) SetLoader(loader ModelLoader) {
+	h.loader = loader
+}
+
+// SetTenants checks tenants and their model quota before they register models
+func (h *ModelHandler) SetTenants(tenants TenantAuthorizer) {
+	h.tenants = tenants
+}
+
+// callerTenant returns the tenant a request acts for. Platform services and
+// operators act for none.
+func callerTenant(c *gin.Context) string {
+	return logging.FieldsFromContext(c.Request.Context()).Tenant
+}
+
+// visibleTo reports whether a tenant may see a model: shared models are
+// visible to every tenant, and a tenant's own models only to that tenant
+func visibleTo(tenant string, model *models.ModelMetadata) bool {
+	return tenant == "" || model.Tenant == "" || model.Tenant == tenant
+}
+
+// ownedBy reports whether a tenant may change a model; shared models are
+// managed by the platform
+func ownedBy(tenant string, model *models.ModelMetadata) bool {
+	return tenant == "" || model.Tenant == tenant
+}
+
+// admitModel checks that a tenant may register another model
+func (h *ModelHandler) admitModel(ctx context.Context, tenant string) error {
+	if h.tenants == nil || tenant == "" {
+		return nil
+	}
+
+	t, err := h.tenants.Authorize(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	if max := t.Limits.MaxModels; max > 0 {
+		count, err := h.repo.CountByTenant(ctx, tenant)
+		if err != nil {
+			return err
+		}
+		if count >= max {
+			return apperrors.Newf(apperrors.ResourceExhausted, "tenant %s has reached its limit of %d models", tenant, max)
+		}
+	}
+	return nil
+}
+
+// log returns the handler logger annotated with the request's correlation fields
+func (h *ModelHandler) log(c *gin.Context) *zap.Logger {
+	return logging.With(c.Request.Context(), h.logger)
+}
+
 // CreateModel creates a new model
 func (h *ModelHandler) CreateModel(c *gin.Context) {
 	var req models.CreateModelRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": err.Error()})
+		c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error())))
+		return
+	}
+
+	// Models registered for a tenant belong to it
+	req.Tenant = callerTenant(c)
+	if err := h.admitModel(c.Request.Context(), req.Tenant); err != nil {
+		h.log(c).Warn("rejected model registration", zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to check tenant")))
 		return
 	}
 
 	model, err := h.repo.Create(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.Error("failed to create model", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create model"})
+		h.log(c).Error("failed to create model", zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to create model")))
 		return
 	}
 
 	// Cache the new model
 	cacheKey := model.ID
 	if err := h.cache.Set(c.Request.Context(), cacheKey, model); err != nil {
-		h.logger.Warn("failed to cache model", zap.Error(err))
+		h.log(c).Warn("failed to cache model", zap.Error(err))
+	}
+
+	if h.loader != nil && model.Status == "active" {
+		h.loader.Load(c.Request.Context(), model)
 	}
 
 	c.JSON(http.StatusCreated, model)
@@ -58,24 +168,30 @@ func (h *ModelHandler) GetModel(c *gin.Context) {
 	// Try cache first
 	model, err := h.cache.Get(c.Request.Context(), id)
 	if err != nil {
-		h.logger.Warn("cache error", zap.Error(err))
+		h.log(c).Warn("cache error", zap.Error(err))
 	}
 
 	if model == nil {
 		// Cache miss, get from database
 		model, err = h.repo.GetByID(c.Request.Context(), id)
 		if err != nil {
-			h.logger.Error("failed to get model", zap.String("id", id), zap.Error(err))
-			c.JSON(http.StatusNotFound, gin.H{"error": "model not found"})
+			h.log(c).Error("failed to get model", zap.String("id", id), zap.Error(err))
+			c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to get model")))
 			return
 		}
 
 		// Cache the result
 		if err := h.cache.Set(c.Request.Context(), id, model); err != nil {
-			h.logger.Warn("failed to cache model", zap.Error(err))
+			h.log(c).Warn("failed to cache model", zap.Error(err))
 		}
 	}
 
+	// Other tenants' models are reported as missing
+	if !visibleTo(callerTenant(c), model) {
+		c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.NotFound, "model not found")))
+		return
+	}
+
 	c.JSON(http.StatusOK, model)
 }
 
@@ -89,33 +205,78 @@ func (h *ModelHandler) GetModelByNameVersion(c *gin.Context) {
 	// Try cache first
 	model, err := h.cache.Get(c.Request.Context(), cacheKey)
 	if err != nil {
-		h.logger.Warn("cache error", zap.Error(err))
+		h.log(c).Warn("cache error", zap.Error(err))
 	}
 
 	if model == nil {
 		// Cache miss, get from database
 		model, err = h.repo.GetByNameVersion(c.Request.Context(), name, version)
+		if err != nil && h.resolveRemote(c, name, version, err) {
+			return
+		}
 		if err != nil {
-			h.logger.Error("failed to get model",
+			h.log(c).Error("failed to get model",
 				zap.String("name", name),
 				zap.String("version", version),
 				zap.Error(err),
 			)
-			c.JSON(http.StatusNotFound, gin.H{"error": "model not found"})
+			c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to get model")))
 			return
 		}
 
 		// Cache the result
 		if err := h.cache.Set(c.Request.Context(), cacheKey, model); err != nil {
-			h.logger.Warn("failed to cache model", zap.Error(err))
+			h.log(c).Warn("failed to cache model", zap.Error(err))
 		}
 	}
 
+	// Other tenants' models are reported as missing
+	if !visibleTo(callerTenant(c), model) {
+		c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.NotFound, "model not found")))
+		return
+	}
+
 	c.JSON(http.StatusOK, model)
 }
 
+// resolveRemote answers a by-name lookup from a peer region after a local failure.
+// Remote answers are not cached; replication brings the model in locally.
+func (h *ModelHandler) resolveRemote(c *gin.Context, name, version string, localErr error) bool {
+	if h.fallback == nil || c.GetHeader(replication.ForwardedHeader) != "" {
+		return false
+	}
+	switch apperrors.CodeOf(localErr) {
+	case apperrors.Canceled, apperrors.DeadlineExceeded:
+		return false
+	}
+
+	model, region, err := h.fallback.Resolve(c.Request.Context(), name, version)
+	if err == nil && !visibleTo(callerTenant(c), model) {
+		err = apperrors.New(apperrors.NotFound, "model not found")
+	}
+	if err != nil {
+		h.log(c).Warn("peer regions could not resolve model",
+			zap.String("name", name),
+			zap.String("version", version),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	h.log(c).Info("resolved model from peer region",
+		zap.String("name", name),
+		zap.String("version", version),
+		zap.String("region", region),
+		zap.NamedError("local_error", localErr),
+	)
+	c.Header(ServedRegionHeader, region)
+	c.JSON(http.StatusOK, model)
+	return true
+}
+
 // ListModels lists all models with optional filtering
 func (h *ModelHandler) ListModels(c *gin.Context) {
+	name := c.Query("name")
 	status := c.DefaultQuery("status", "")
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
@@ -124,10 +285,10 @@ func (h *ModelHandler) ListModels(c *gin.Context) {
 		limit = 100
 	}
 
-	models, err := h.repo.List(c.Request.Context(), status, limit, offset)
+	models, err := h.repo.List(c.Request.Context(), name, status, callerTenant(c), limit, offset)
 	if err != nil {
-		h.logger.Error("failed to list models", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list models"})
+		h.log(c).Error("failed to list models", zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to list models")))
 		return
 	}
 
@@ -145,31 +306,87 @@ func (h *ModelHandler) UpdateModel(c *gin.Context) {
 
 	var req models.UpdateModelRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": err.Error()})
+		c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error())))
 		return
 	}
 
+	// Tenants may only change their own models
+	tenant := callerTenant(c)
+	if tenant != "" {
+		previous, err := h.repo.GetByID(c.Request.Context(), id)
+		if err == nil && !ownedBy(tenant, previous) {
+			err = apperrors.Newf(apperrors.PermissionDenied, "model %s does not belong to tenant %s", id, tenant)
+		}
+		if err == nil && releasesQuarantine(previous, &req) {
+			err = apperrors.Newf(apperrors.PermissionDenied, "model %s is quarantined until its artifact passes a scan", id)
+		}
+		if err != nil {
+			c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to get model")))
+			return
+		}
+	}
+
+	// Remember the previous status so promotions can be announced, and the
+	// promoted version loaded
+	previousStatus := ""
+	if (h.events != nil || h.loader != nil) && req.Status != nil {
+		if previous, err := h.repo.GetByID(c.Request.Context(), id); err == nil {
+			previousStatus = previous.Status
+		}
+	}
+
 	model, err := h.repo.Update(c.Request.Context(), id, &req)
 	if err != nil {
-		h.logger.Error("failed to update model", zap.String("id", id), zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update model"})
+		h.log(c).Error("failed to update model", zap.String("id", id), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to update model")))
 		return
 	}
 
+	if event, ok := promotionEvent(previousStatus, model); ok {
+		h.events.Emit(c.Request.Context(), event)
+		if h.loader != nil {
+			h.loader.Load(c.Request.Context(), model)
+		}
+	}
+
 	// Invalidate cache
 	if err := h.cache.Delete(c.Request.Context(), id); err != nil {
-		h.logger.Warn("failed to invalidate cache", zap.Error(err))
+		h.log(c).Warn("failed to invalidate cache", zap.Error(err))
 	}
 
 	// Also invalidate name:version cache
 	cacheKey := model.Name + ":" + model.Version
 	if err := h.cache.Delete(c.Request.Context(), cacheKey); err != nil {
-		h.logger.Warn("failed to invalidate cache", zap.Error(err))
+		h.log(c).Warn("failed to invalidate cache", zap.Error(err))
 	}
 
 	c.JSON(http.StatusOK, model)
 }
 
+// releasesQuarantine reports whether an update moves a model out of
+// quarantine. Only the artifact scanner, acting as the platform, may do so
+// once the model's artifact passes.
+func releasesQuarantine(previous *models.ModelMetadata, req *models.UpdateModelRequest) bool {
+	return previous.Status == QuarantinedStatus && req.Status != nil && *req.Status != QuarantinedStatus
+}
+
+// promotionEvent describes a model that became active, if it did
+func promotionEvent(previousStatus string, model *models.ModelMetadata) (events.Event, bool) {
+	if previousStatus == "" || previousStatus == "active" || model.Status != "active" {
+		return events.Event{}, false
+	}
+	return events.Event{
+		Type:    events.ModelPromoted,
+		Subject: model.Name + "/" + model.Version,
+		Attributes: map[string]string{
+			"id":              model.ID,
+			"model":           model.Name,
+			"version":         model.Version,
+			"previous_status": previousStatus,
+		},
+	}, true
+}
+
 // DeleteModel deletes a model
 func (h *ModelHandler) DeleteModel(c *gin.Context) {
 	id := c.Param("id")
@@ -177,31 +394,29 @@ func (h *ModelHandler) DeleteModel(c *gin.Context) {
 	// Get model first to invalidate caches
 	model, _ := h.repo.GetByID(c.Request.Context(), id)
 
+	// Tenants may only delete their own models
+	if tenant := callerTenant(c); model != nil && !ownedBy(tenant, model) {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.PermissionDenied, "model %s does not belong to tenant %s", id, tenant)))
+		return
+	}
+
 	if err := h.repo.Delete(c.Request.Context(), id); err != nil {
-		h.logger.Error("failed to delete model", zap.String("id", id), zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete model"})
+		h.log(c).Error("failed to delete model", zap.String("id", id), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to delete model")))
 		return
 	}
 
 	// Invalidate caches
 	if err := h.cache.Delete(c.Request.Context(), id); err != nil {
-		h.logger.Warn("failed to invalidate cache", zap.Error(err))
+		h.log(c).Warn("failed to invalidate cache", zap.Error(err))
 	}
 
 	if model != nil {
 		cacheKey := model.Name + ":" + model.Version
 		if err := h.cache.Delete(c.Request.Context(), cacheKey); err != nil {
-			h.logger.Warn("failed to invalidate cache", zap.Error(err))
+			h.log(c).Warn("failed to invalidate cache", zap.Error(err))
 		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "model deleted successfully"})
 }
-
-// HealthCheck returns service health status
-func (h *ModelHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status": "healthy",
-		"service": "metadata-service",
-	})
-}
@@ -10,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/yourusername/ai-platform/metadata-service/internal/models"
+	"github.com/yourusername/ai-platform/pkg/events"
 )
 
 func TestCreateModel_Success(t *testing.T) {
@@ -103,3 +104,56 @@ func TestHealthCheck_Handler(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "healthy")
 	assert.Contains(t, w.Body.String(), "metadata-service")
 }
+
+func TestPromotionEvent(t *testing.T) {
+	model := &models.ModelMetadata{ID: "m-1", Name: "resnet18", Version: "v2", Status: "active"}
+
+	event, ok := promotionEvent("deprecated", model)
+	assert.True(t, ok)
+	assert.Equal(t, events.ModelPromoted, event.Type)
+	assert.Equal(t, "resnet18/v2", event.Subject)
+	assert.Equal(t, "deprecated", event.Attributes["previous_status"])
+
+	_, ok = promotionEvent("active", model)
+	assert.False(t, ok, "already active")
+	_, ok = promotionEvent("", model)
+	assert.False(t, ok, "previous status unknown")
+	_, ok = promotionEvent("active", &models.ModelMetadata{Status: "archived"})
+	assert.False(t, ok)
+}
+
+func TestTenantAccess(t *testing.T) {
+	shared := &models.ModelMetadata{Name: "resnet18"}
+	owned := &models.ModelMetadata{Name: "custom", Tenant: "acme"}
+
+	tests := []struct {
+		name        string
+		tenant      string
+		model       *models.ModelMetadata
+		wantVisible bool
+		wantOwned   bool
+	}{
+		{"platform sees shared", "", shared, true, true},
+		{"platform sees tenant model", "", owned, true, true},
+		{"tenant sees shared read-only", "acme", shared, true, false},
+		{"tenant owns its model", "acme", owned, true, true},
+		{"other tenant sees nothing", "globex", owned, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantVisible, visibleTo(tt.tenant, tt.model))
+			assert.Equal(t, tt.wantOwned, ownedBy(tt.tenant, tt.model))
+		})
+	}
+}
+
+func TestReleasesQuarantine(t *testing.T) {
+	status := func(s string) *models.UpdateModelRequest { return &models.UpdateModelRequest{Status: &s} }
+	quarantined := &models.ModelMetadata{Status: QuarantinedStatus}
+
+	assert.True(t, releasesQuarantine(quarantined, status("active")))
+	assert.False(t, releasesQuarantine(quarantined, status(QuarantinedStatus)))
+	assert.False(t, releasesQuarantine(quarantined, &models.UpdateModelRequest{}), "status unchanged")
+	assert.False(t, releasesQuarantine(&models.ModelMetadata{Status: "active"}, status("deprecated")))
+}
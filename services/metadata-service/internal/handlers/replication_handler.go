@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/metadata-service/internal/replication"
+	"github.com/yourusername/ai-platform/metadata-service/internal/repository"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// maxChangesPage bounds the page size a peer may request
+const maxChangesPage = 1000
+
+// ReplicationHandler serves the registry change feed to peer regions
+type ReplicationHandler struct {
+	repo       *repository.ModelRepository
+	replicator *replication.Replicator
+	region     string
+	logger     *zap.Logger
+}
+
+// NewReplicationHandler creates a replication handler. replicator may be nil when
+// this region does not pull from any peer.
+func NewReplicationHandler(repo *repository.ModelRepository, replicator *replication.Replicator, region string, logger *zap.Logger) *ReplicationHandler {
+	return &ReplicationHandler{
+		repo:       repo,
+		replicator: replicator,
+		region:     region,
+		logger:     logger,
+	}
+}
+
+// Changes returns models and tombstones revised since the given cursor
+func (h *ReplicationHandler) Changes(c *gin.Context) {
+	var since time.Time
+	if value := c.Query("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.InvalidArgument, "invalid since cursor").WithDetails(err.Error())))
+			return
+		}
+		since = parsed
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "200"))
+	if limit <= 0 || limit > maxChangesPage {
+		limit = maxChangesPage
+	}
+
+	changes, err := h.repo.Changes(c.Request.Context(), since, c.Query("exclude_region"), limit)
+	if err != nil {
+		logging.With(c.Request.Context(), h.logger).Error("failed to list changes", zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to list changes")))
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// Status reports this region and its replication progress from each peer
+func (h *ReplicationHandler) Status(c *gin.Context) {
+	peers := []replication.PeerStatus{}
+	if h.replicator != nil {
+		peers = h.replicator.Status()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"region": h.region,
+		"peers":  peers,
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/scaling"
+	"go.uber.org/zap"
+)
+
+// SetScalingPolicy sets how the autoscaler sizes the deployment serving a model
+func (h *ModelHandler) SetScalingPolicy(c *gin.Context) {
+	model := c.Param("model")
+
+	var policy scaling.Policy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error())))
+		return
+	}
+
+	saved, err := h.repo.SetScalingPolicy(c.Request.Context(), model, &policy)
+	if err != nil {
+		h.log(c).Error("failed to set scaling policy", zap.String("model", model), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to set scaling policy")))
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// GetScalingPolicy returns a model's scaling policy
+func (h *ModelHandler) GetScalingPolicy(c *gin.Context) {
+	model := c.Param("model")
+
+	policy, err := h.repo.GetScalingPolicy(c.Request.Context(), model)
+	if err != nil {
+		h.log(c).Warn("failed to get scaling policy", zap.String("model", model), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to get scaling policy")))
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// ListScalingPolicies returns every scaling policy
+func (h *ModelHandler) ListScalingPolicies(c *gin.Context) {
+	policies, err := h.repo.ListScalingPolicies(c.Request.Context())
+	if err != nil {
+		h.log(c).Error("failed to list scaling policies", zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to list scaling policies")))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"policies": policies,
+		"count":    len(policies),
+	})
+}
+
+// DeleteScalingPolicy stops the autoscaler sizing a model's deployment
+func (h *ModelHandler) DeleteScalingPolicy(c *gin.Context) {
+	model := c.Param("model")
+
+	if err := h.repo.DeleteScalingPolicy(c.Request.Context(), model); err != nil {
+		h.log(c).Error("failed to delete scaling policy", zap.String("model", model), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to delete scaling policy")))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "scaling policy deleted successfully"})
+}
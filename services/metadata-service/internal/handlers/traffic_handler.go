@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/traffic"
+	"go.uber.org/zap"
+)
+
+// SetTrafficSplit sets how the model router splits a model's requests between versions
+func (h *ModelHandler) SetTrafficSplit(c *gin.Context) {
+	model := c.Param("model")
+
+	var split traffic.Split
+	if err := c.ShouldBindJSON(&split); err != nil {
+		c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error())))
+		return
+	}
+
+	saved, err := h.repo.SetTrafficSplit(c.Request.Context(), model, &split)
+	if err != nil {
+		h.log(c).Error("failed to set traffic split", zap.String("model", model), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to set traffic split")))
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// GetTrafficSplit returns a model's traffic split
+func (h *ModelHandler) GetTrafficSplit(c *gin.Context) {
+	model := c.Param("model")
+
+	split, err := h.repo.GetTrafficSplit(c.Request.Context(), model)
+	if err != nil {
+		h.log(c).Warn("failed to get traffic split", zap.String("model", model), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to get traffic split")))
+		return
+	}
+
+	c.JSON(http.StatusOK, split)
+}
+
+// ListTrafficSplits returns every traffic split
+func (h *ModelHandler) ListTrafficSplits(c *gin.Context) {
+	splits, err := h.repo.ListTrafficSplits(c.Request.Context())
+	if err != nil {
+		h.log(c).Error("failed to list traffic splits", zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to list traffic splits")))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"splits": splits,
+		"count":  len(splits),
+	})
+}
+
+// DeleteTrafficSplit ends a model's traffic split
+func (h *ModelHandler) DeleteTrafficSplit(c *gin.Context) {
+	model := c.Param("model")
+
+	if err := h.repo.DeleteTrafficSplit(c.Request.Context(), model); err != nil {
+		h.log(c).Error("failed to delete traffic split", zap.String("model", model), zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to delete traffic split")))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "traffic split deleted successfully"})
+}
@@ -22,6 +22,27 @@ type ModelMetadata struct {
 	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time         `json:"updated_at" db:"updated_at"`
 	Metadata        map[string]string `json:"metadata" db:"metadata"` // Additional key-value pairs
+	OriginRegion    string            `json:"origin_region" db:"origin_region"` // Region of the last definition change
+	RevisedAt       time.Time         `json:"revised_at" db:"revised_at"`       // Time of the last definition change; stats updates do not bump it
+	Tenant          string            `json:"tenant,omitempty" db:"tenant"`     // Owning tenant; empty for models shared by every tenant
+}
+
+// Tombstone records a deleted model so the deletion replicates to other regions
+type Tombstone struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Version      string    `json:"version"`
+	OriginRegion string    `json:"origin_region"`
+	RevisedAt    time.Time `json:"revised_at"`
+}
+
+// ChangeSet is a page of registry changes served to replicating regions
+type ChangeSet struct {
+	Region     string           `json:"region"`
+	Models     []*ModelMetadata `json:"models"`
+	Tombstones []Tombstone      `json:"tombstones"`
+	Cursor     time.Time        `json:"cursor"`
+	More       bool             `json:"more"`
 }
 
 // CreateModelRequest represents a request to create a new model
@@ -37,6 +58,8 @@ type CreateModelRequest struct {
 	BackendURL  string            `json:"backend_url" binding:"required"`
 	CreatedBy   string            `json:"created_by"`
 	Metadata    map[string]string `json:"metadata"`
+	// Tenant is the caller's tenant, taken from the request rather than the body
+	Tenant string `json:"-"`
 }
 
 // UpdateModelRequest represents a request to update a model
@@ -0,0 +1,324 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/metadata-service/internal/models"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"go.uber.org/zap"
+)
+
+// ChangesPath serves registry changes to replicating regions
+const ChangesPath = "/v1/replication/changes"
+
+// pageSize bounds the number of models and tombstones fetched per request
+const pageSize = 200
+
+// ForwardedHeader marks a lookup forwarded from another region; it is answered
+// locally only, so regions never forward a lookup in a loop
+const ForwardedHeader = "X-Forwarded-Region"
+
+// Peer is the metadata service of another region
+type Peer struct {
+	Region string `json:"region"`
+	URL    string `json:"url"`
+}
+
+// ParsePeers parses "region=url" pairs separated by commas, in affinity order
+func ParsePeers(value string) ([]Peer, error) {
+	var peers []Peer
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		region, peerURL, ok := strings.Cut(entry, "=")
+		if !ok || region == "" || peerURL == "" {
+			return nil, fmt.Errorf("invalid replication peer %q, expected region=url", entry)
+		}
+		peers = append(peers, Peer{Region: region, URL: strings.TrimRight(peerURL, "/")})
+	}
+	return peers, nil
+}
+
+// Store applies replicated changes to the local registry
+type Store interface {
+	Apply(ctx context.Context, model *models.ModelMetadata) (bool, error)
+	ApplyTombstone(ctx context.Context, tombstone models.Tombstone) (bool, error)
+}
+
+// Cache is invalidated when a replicated change lands
+type Cache interface {
+	Delete(ctx context.Context, key string) error
+}
+
+// PeerStatus reports replication progress from one peer
+type PeerStatus struct {
+	Region    string    `json:"region"`
+	URL       string    `json:"url"`
+	Cursor    time.Time `json:"cursor"`
+	LastSync  time.Time `json:"last_sync"`
+	LastError string    `json:"last_error,omitempty"`
+	Applied   int64     `json:"applied"`
+	// Skipped counts changes already present locally or superseded by a newer local revision
+	Skipped int64 `json:"skipped"`
+}
+
+// Replicator pulls registry changes from peer regions and applies them locally.
+// Cursors are kept in memory, so a restart replays each peer's full history;
+// applying changes is idempotent.
+type Replicator struct {
+	region   string
+	peers    []Peer
+	store    Store
+	cache    Cache
+	client   *http.Client
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu     sync.RWMutex
+	status map[string]*PeerStatus
+}
+
+// NewReplicator creates a replicator for region that polls peers every interval
+func NewReplicator(region string, peers []Peer, store Store, cache Cache, interval time.Duration, logger *zap.Logger) *Replicator {
+	status := make(map[string]*PeerStatus, len(peers))
+	for _, peer := range peers {
+		status[peer.Region] = &PeerStatus{Region: peer.Region, URL: peer.URL}
+	}
+	return &Replicator{
+		region:   region,
+		peers:    peers,
+		store:    store,
+		cache:    cache,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		interval: interval,
+		logger:   logger,
+		status:   status,
+	}
+}
+
+// SetHTTPClient replaces the client used to call peers, e.g. with an mTLS client
+func (r *Replicator) SetHTTPClient(client *http.Client) {
+	r.client = client
+}
+
+// Start polls every peer until ctx is cancelled
+func (r *Replicator) Start(ctx context.Context) {
+	for _, peer := range r.peers {
+		go r.run(ctx, peer)
+	}
+}
+
+func (r *Replicator) run(ctx context.Context, peer Peer) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.SyncPeer(ctx, peer); err != nil && ctx.Err() == nil {
+			r.logger.Warn("replication from peer failed",
+				zap.String("peer_region", peer.Region),
+				zap.Error(err),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncPeer applies every change the peer has made since the last sync
+func (r *Replicator) SyncPeer(ctx context.Context, peer Peer) error {
+	cursor := r.cursor(peer.Region)
+	var applied, skipped int64
+
+	for {
+		changes, err := r.fetch(ctx, peer, cursor)
+		if err != nil {
+			r.record(peer.Region, cursor, applied, skipped, err)
+			return err
+		}
+
+		for _, model := range changes.Models {
+			ok, err := r.store.Apply(ctx, model)
+			if err != nil {
+				r.record(peer.Region, cursor, applied, skipped, err)
+				return fmt.Errorf("failed to apply model %s:%s: %w", model.Name, model.Version, err)
+			}
+			if !ok {
+				skipped++
+				continue
+			}
+			applied++
+			r.invalidate(ctx, model.ID, model.Name, model.Version)
+		}
+
+		for _, tombstone := range changes.Tombstones {
+			ok, err := r.store.ApplyTombstone(ctx, tombstone)
+			if err != nil {
+				r.record(peer.Region, cursor, applied, skipped, err)
+				return fmt.Errorf("failed to apply deletion of %s:%s: %w", tombstone.Name, tombstone.Version, err)
+			}
+			if !ok {
+				skipped++
+				continue
+			}
+			applied++
+			r.invalidate(ctx, tombstone.ID, tombstone.Name, tombstone.Version)
+		}
+
+		// Stop when the peer has nothing newer, or cannot advance past a page of equal timestamps
+		advanced := changes.Cursor.After(cursor)
+		if advanced {
+			cursor = changes.Cursor
+		}
+		if !changes.More || !advanced {
+			break
+		}
+	}
+
+	if applied > 0 {
+		r.logger.Info("replicated registry changes",
+			zap.String("peer_region", peer.Region),
+			zap.Int64("applied", applied),
+			zap.Int64("skipped", skipped),
+		)
+	}
+	r.record(peer.Region, cursor, applied, skipped, nil)
+	return nil
+}
+
+func (r *Replicator) fetch(ctx context.Context, peer Peer, since time.Time) (*models.ChangeSet, error) {
+	query := url.Values{}
+	query.Set("since", since.UTC().Format(time.RFC3339Nano))
+	query.Set("exclude_region", r.region)
+	query.Set("limit", strconv.Itoa(pageSize))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", peer.URL+ChangesPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	service := "metadata-service (" + peer.Region + ")"
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, service)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apperrors.FromHTTPResponse(resp, service)
+	}
+
+	var changes models.ChangeSet
+	if err := json.NewDecoder(resp.Body).Decode(&changes); err != nil {
+		return nil, fmt.Errorf("failed to decode changes from %s: %w", peer.Region, err)
+	}
+	return &changes, nil
+}
+
+func (r *Replicator) invalidate(ctx context.Context, id, name, version string) {
+	for _, key := range []string{id, name + ":" + version} {
+		if err := r.cache.Delete(ctx, key); err != nil {
+			r.logger.Warn("failed to invalidate cache", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+func (r *Replicator) cursor(region string) time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status[region].Cursor
+}
+
+func (r *Replicator) record(region string, cursor time.Time, applied, skipped int64, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := r.status[region]
+	status.Cursor = cursor
+	status.Applied += applied
+	status.Skipped += skipped
+	if err != nil {
+		status.LastError = err.Error()
+		return
+	}
+	status.LastSync = time.Now().UTC()
+	status.LastError = ""
+}
+
+// Status returns the replication progress for every peer, in affinity order
+func (r *Replicator) Status() []PeerStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]PeerStatus, 0, len(r.peers))
+	for _, peer := range r.peers {
+		statuses = append(statuses, *r.status[peer.Region])
+	}
+	return statuses
+}
+
+// Resolve looks a model up in peer regions, in affinity order, for when the local
+// registry cannot answer. It returns the model and the region that served it.
+func (r *Replicator) Resolve(ctx context.Context, name, version string) (*models.ModelMetadata, string, error) {
+	var lastErr error = apperrors.New(apperrors.NotFound, "model not found")
+
+	for _, peer := range r.peers {
+		model, err := r.lookup(ctx, peer, name, version)
+		if err == nil {
+			return model, peer.Region, nil
+		}
+		if ctx.Err() != nil {
+			return nil, "", err
+		}
+
+		// A peer that is up but lacks the model must not mask an outage elsewhere
+		if !apperrors.Is(err, apperrors.NotFound) || apperrors.Is(lastErr, apperrors.NotFound) {
+			lastErr = err
+		}
+		r.logger.Debug("peer lookup failed",
+			zap.String("peer_region", peer.Region),
+			zap.Error(err),
+		)
+	}
+
+	return nil, "", lastErr
+}
+
+func (r *Replicator) lookup(ctx context.Context, peer Peer, name, version string) (*models.ModelMetadata, error) {
+	path := "/v1/models/by-name/" + url.PathEscape(name) + "/" + url.PathEscape(version)
+	req, err := http.NewRequestWithContext(ctx, "GET", peer.URL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set(ForwardedHeader, r.region)
+
+	service := "metadata-service (" + peer.Region + ")"
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, apperrors.FromTransportError(err, service)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apperrors.FromHTTPResponse(resp, service)
+	}
+
+	var model models.ModelMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&model); err != nil {
+		return nil, fmt.Errorf("failed to decode model from %s: %w", peer.Region, err)
+	}
+	return &model, nil
+}
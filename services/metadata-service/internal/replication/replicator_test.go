@@ -0,0 +1,162 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/ai-platform/metadata-service/internal/models"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/servicetest"
+	"go.uber.org/zap"
+)
+
+// fakeStore applies changes last-writer-wins like the Postgres repository
+type fakeStore struct {
+	models map[string]*models.ModelMetadata
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{models: make(map[string]*models.ModelMetadata)}
+}
+
+func (s *fakeStore) Apply(ctx context.Context, model *models.ModelMetadata) (bool, error) {
+	key := model.Name + ":" + model.Version
+	if existing, ok := s.models[key]; ok && !existing.RevisedAt.Before(model.RevisedAt) {
+		return false, nil
+	}
+	s.models[key] = model
+	return true, nil
+}
+
+func (s *fakeStore) ApplyTombstone(ctx context.Context, tombstone models.Tombstone) (bool, error) {
+	key := tombstone.Name + ":" + tombstone.Version
+	if existing, ok := s.models[key]; ok && !existing.RevisedAt.After(tombstone.RevisedAt) {
+		delete(s.models, key)
+		return true, nil
+	}
+	return false, nil
+}
+
+type fakeCache struct {
+	deleted []string
+}
+
+func (c *fakeCache) Delete(ctx context.Context, key string) error {
+	c.deleted = append(c.deleted, key)
+	return nil
+}
+
+func TestParsePeers(t *testing.T) {
+	peers, err := ParsePeers("eu-west=http://metadata.eu-west:8083/, us-east=http://metadata.us-east:8083")
+	assert.NoError(t, err)
+	assert.Equal(t, []Peer{
+		{Region: "eu-west", URL: "http://metadata.eu-west:8083"},
+		{Region: "us-east", URL: "http://metadata.us-east:8083"},
+	}, peers)
+
+	peers, err = ParsePeers("")
+	assert.NoError(t, err)
+	assert.Empty(t, peers)
+
+	_, err = ParsePeers("http://metadata.eu-west:8083")
+	assert.Error(t, err)
+}
+
+func TestSyncPeer_PagesAndResolvesConflicts(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pages := map[string]models.ChangeSet{
+		"": {
+			Models: []*models.ModelMetadata{
+				{ID: "a", Name: "resnet18", Version: "v1", RevisedAt: t0.Add(time.Second)},
+				{ID: "b", Name: "resnet18", Version: "v2", RevisedAt: t0.Add(2 * time.Second)},
+			},
+			Cursor: t0.Add(2 * time.Second),
+			More:   true,
+		},
+		t0.Add(2 * time.Second).Format(time.RFC3339Nano): {
+			Tombstones: []models.Tombstone{{ID: "a", Name: "resnet18", Version: "v1", RevisedAt: t0.Add(3 * time.Second)}},
+			Cursor:     t0.Add(3 * time.Second),
+		},
+	}
+
+	var excluded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, ChangesPath, r.URL.Path)
+		excluded = r.URL.Query().Get("exclude_region")
+
+		since := r.URL.Query().Get("since")
+		if since == (time.Time{}).Format(time.RFC3339Nano) {
+			since = ""
+		}
+		json.NewEncoder(w).Encode(pages[since])
+	}))
+	defer server.Close()
+
+	store := newFakeStore()
+	// The local copy of v2 is newer and must survive
+	store.models["resnet18:v2"] = &models.ModelMetadata{ID: "b", Name: "resnet18", Version: "v2", RevisedAt: t0.Add(time.Hour)}
+	cache := &fakeCache{}
+
+	peer := Peer{Region: "eu-west", URL: server.URL}
+	replicator := NewReplicator("us-east", []Peer{peer}, store, cache, time.Minute, zap.NewNop())
+
+	assert.NoError(t, replicator.SyncPeer(context.Background(), peer))
+
+	assert.Equal(t, "us-east", excluded)
+	assert.NotContains(t, store.models, "resnet18:v1")
+	assert.Equal(t, t0.Add(time.Hour), store.models["resnet18:v2"].RevisedAt)
+	assert.Contains(t, cache.deleted, "resnet18:v1")
+
+	status := replicator.Status()[0]
+	assert.Equal(t, t0.Add(3*time.Second), status.Cursor)
+	assert.Equal(t, int64(2), status.Applied)
+	assert.Equal(t, int64(1), status.Skipped)
+	assert.Empty(t, status.LastError)
+}
+
+func TestResolve_FallsBackInAffinityOrder(t *testing.T) {
+	downURL := servicetest.Down(t).URL
+
+	var forwarded string
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get(ForwardedHeader)
+		assert.Equal(t, "/v1/models/by-name/resnet18/v1", r.URL.Path)
+		json.NewEncoder(w).Encode(models.ModelMetadata{ID: "a", Name: "resnet18", Version: "v1"})
+	}))
+	defer up.Close()
+
+	replicator := NewReplicator("us-east", []Peer{
+		{Region: "eu-west", URL: downURL},
+		{Region: "ap-south", URL: up.URL},
+	}, newFakeStore(), &fakeCache{}, time.Minute, zap.NewNop())
+
+	model, region, err := replicator.Resolve(context.Background(), "resnet18", "v1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ap-south", region)
+	assert.Equal(t, "a", model.ID)
+	assert.Equal(t, "us-east", forwarded)
+}
+
+func TestResolve_ReportsOutageOverNotFound(t *testing.T) {
+	missing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apperrors.WriteHTTP(w, apperrors.New(apperrors.NotFound, "model not found"))
+	}))
+	defer missing.Close()
+
+	downURL := servicetest.Down(t).URL
+
+	replicator := NewReplicator("us-east", []Peer{
+		{Region: "eu-west", URL: downURL},
+		{Region: "ap-south", URL: missing.URL},
+	}, newFakeStore(), &fakeCache{}, time.Minute, zap.NewNop())
+
+	_, _, err := replicator.Resolve(context.Background(), "resnet18", "v1")
+
+	assert.Equal(t, apperrors.Unavailable, apperrors.CodeOf(err))
+}
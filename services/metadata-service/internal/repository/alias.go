@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/traffic"
+	"go.uber.org/zap"
+)
+
+// Version aliases are regional like traffic splits: a version is promoted
+// region by region, so aliases do not replicate.
+
+// SetAlias points a model's alias at a version, which must be registered.
+// An alias may not be named like a registered version, which it would hide.
+func (r *ModelRepository) SetAlias(ctx context.Context, model, name string, alias *traffic.Alias) (*traffic.Alias, error) {
+	alias.Model = model
+	alias.Name = name
+	if err := alias.Validate(); err != nil {
+		return nil, err
+	}
+
+	registered := func(version string) (bool, error) {
+		var exists bool
+		err := r.db.Load().QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM models WHERE name = $1 AND version = $2)`, model, version).Scan(&exists)
+		if err != nil {
+			return false, fmt.Errorf("failed to look up model: %w", err)
+		}
+		return exists, nil
+	}
+	if ok, err := registered(alias.Version); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, apperrors.Newf(apperrors.NotFound, "model not found: %s/%s", model, alias.Version)
+	}
+	if ok, err := registered(name); err != nil {
+		return nil, err
+	} else if ok {
+		return nil, apperrors.Newf(apperrors.AlreadyExists, "%s/%s is a registered version, not an alias", model, name)
+	}
+
+	alias.UpdatedAt = time.Now().UTC()
+	_, err := r.db.Load().ExecContext(ctx, `
+		INSERT INTO version_aliases (model, alias, version, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (model, alias) DO UPDATE SET version = EXCLUDED.version, updated_at = EXCLUDED.updated_at
+	`, model, name, alias.Version, alias.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save alias: %w", err)
+	}
+
+	logging.With(ctx, r.logger).Info("set version alias",
+		zap.String("model", model),
+		zap.String("alias", name),
+		zap.String("version", alias.Version),
+	)
+
+	return alias, nil
+}
+
+// GetAlias returns a model's alias
+func (r *ModelRepository) GetAlias(ctx context.Context, model, name string) (*traffic.Alias, error) {
+	alias := traffic.Alias{Model: model, Name: name}
+	err := r.db.Load().QueryRowContext(ctx, `SELECT version, updated_at FROM version_aliases WHERE model = $1 AND alias = $2`, model, name).
+		Scan(&alias.Version, &alias.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.New(apperrors.NotFound, "alias not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alias: %w", err)
+	}
+	return &alias, nil
+}
+
+// ListAliases returns every alias, ordered by model and name
+func (r *ModelRepository) ListAliases(ctx context.Context) ([]*traffic.Alias, error) {
+	rows, err := r.db.Load().QueryContext(ctx, `SELECT model, alias, version, updated_at FROM version_aliases ORDER BY model, alias`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := make([]*traffic.Alias, 0)
+	for rows.Next() {
+		var alias traffic.Alias
+		if err := rows.Scan(&alias.Model, &alias.Name, &alias.Version, &alias.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alias: %w", err)
+		}
+		aliases = append(aliases, &alias)
+	}
+	return aliases, rows.Err()
+}
+
+// DeleteAlias removes a model's alias, so requests naming it are no longer
+// routed
+func (r *ModelRepository) DeleteAlias(ctx context.Context, model, name string) error {
+	result, err := r.db.Load().ExecContext(ctx, `DELETE FROM version_aliases WHERE model = $1 AND alias = $2`, model, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete alias: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return apperrors.New(apperrors.NotFound, "alias not found")
+	}
+
+	logging.With(ctx, r.logger).Info("deleted version alias", zap.String("model", model), zap.String("alias", name))
+	return nil
+}
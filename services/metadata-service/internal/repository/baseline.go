@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/drift"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// Baselines are regional: they are not part of a model's definition and do
+// not replicate, so each region's drift service compares against the
+// baseline registered there.
+
+// SetBaseline replaces the training baseline of a model. The baseline's model
+// name and version are taken from the registry.
+func (r *ModelRepository) SetBaseline(ctx context.Context, id string, baseline *drift.Baseline) (*drift.Baseline, error) {
+	if err := baseline.Validate(); err != nil {
+		return nil, err
+	}
+
+	model, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	baseline.Model = model.Name
+	baseline.Version = model.Version
+	baseline.UpdatedAt = time.Now().UTC()
+
+	data, err := json.Marshal(baseline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+
+	_, err = r.db.Load().ExecContext(ctx, `
+		INSERT INTO model_baselines (model_id, baseline, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (model_id) DO UPDATE SET baseline = EXCLUDED.baseline, updated_at = EXCLUDED.updated_at
+	`, id, data, baseline.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save baseline: %w", err)
+	}
+
+	logging.With(ctx, r.logger).Info("set model baseline",
+		zap.String("id", id),
+		zap.Int("features", len(baseline.Features)),
+		zap.Int("predictions", len(baseline.Predictions)),
+	)
+
+	return baseline, nil
+}
+
+// GetBaseline returns the training baseline of a model
+func (r *ModelRepository) GetBaseline(ctx context.Context, id string) (*drift.Baseline, error) {
+	return r.scanBaseline(r.db.Load().QueryRowContext(ctx,
+		`SELECT baseline FROM model_baselines WHERE model_id = $1`, id))
+}
+
+// GetBaselineByNameVersion returns the training baseline of a model version
+func (r *ModelRepository) GetBaselineByNameVersion(ctx context.Context, name, version string) (*drift.Baseline, error) {
+	return r.scanBaseline(r.db.Load().QueryRowContext(ctx, `
+		SELECT b.baseline
+		FROM model_baselines b
+		JOIN models m ON m.id = b.model_id
+		WHERE m.name = $1 AND m.version = $2
+	`, name, version))
+}
+
+func (r *ModelRepository) scanBaseline(row *sql.Row) (*drift.Baseline, error) {
+	var data []byte
+	err := row.Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.New(apperrors.NotFound, "baseline not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get baseline: %w", err)
+	}
+
+	var baseline drift.Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal baseline: %w", err)
+	}
+	return &baseline, nil
+}
@@ -5,23 +5,51 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 	"github.com/lib/pq"
 	"github.com/yourusername/ai-platform/metadata-service/internal/models"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
 	"go.uber.org/zap"
 )
 
+// pqUniqueViolation is the Postgres error code for a unique constraint violation
+const pqUniqueViolation = "23505"
+
 // ModelRepository handles database operations for models
 type ModelRepository struct {
-	db     *sql.DB
+	db     atomic.Pointer[sql.DB]
 	logger *zap.Logger
+
+	// region is recorded as the origin of definition changes made here
+	region string
 }
 
 // NewModelRepository creates a new model repository
 func NewModelRepository(connectionURL string, logger *zap.Logger) (*ModelRepository, error) {
+	db, err := openDB(connectionURL)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &ModelRepository{
+		logger: logger,
+	}
+	repo.db.Store(db)
+
+	if err := repo.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	return repo, nil
+}
+
+// openDB opens a connection pool and checks that the database is reachable
+func openDB(connectionURL string) (*sql.DB, error) {
 	db, err := sql.Open("postgres", connectionURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -35,17 +63,26 @@ func NewModelRepository(connectionURL string, logger *zap.Logger) (*ModelReposit
 	db.SetMaxOpenConns(25)
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(5 * time.Minute)
+	return db, nil
+}
 
-	repo := &ModelRepository{
-		db:     db,
-		logger: logger,
+// Reconnect replaces the connection pool with one opened with connectionURL,
+// typically after the database credentials rotate. Queries already running
+// on the old pool finish before it closes.
+func (r *ModelRepository) Reconnect(connectionURL string) error {
+	db, err := openDB(connectionURL)
+	if err != nil {
+		return err
 	}
-
-	if err := repo.initSchema(); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	if old := r.db.Swap(db); old != nil {
+		return old.Close()
 	}
+	return nil
+}
 
-	return repo, nil
+// SetRegion sets the region recorded on models created, updated or deleted through this repository
+func (r *ModelRepository) SetRegion(region string) {
+	r.region = region
 }
 
 // initSchema creates the models table
@@ -76,9 +113,52 @@ func (r *ModelRepository) initSchema() error {
 	CREATE INDEX IF NOT EXISTS idx_models_name ON models(name);
 	CREATE INDEX IF NOT EXISTS idx_models_status ON models(status);
 	CREATE INDEX IF NOT EXISTS idx_models_created_at ON models(created_at);
+
+	ALTER TABLE models ADD COLUMN IF NOT EXISTS origin_region VARCHAR(64) NOT NULL DEFAULT '';
+	ALTER TABLE models ADD COLUMN IF NOT EXISTS revised_at TIMESTAMP NOT NULL DEFAULT NOW();
+	CREATE INDEX IF NOT EXISTS idx_models_revised_at ON models(revised_at);
+
+	ALTER TABLE models ADD COLUMN IF NOT EXISTS tenant VARCHAR(64) NOT NULL DEFAULT '';
+	CREATE INDEX IF NOT EXISTS idx_models_tenant ON models(tenant);
+
+	CREATE TABLE IF NOT EXISTS model_tombstones (
+		id VARCHAR(255) PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		version VARCHAR(50) NOT NULL,
+		origin_region VARCHAR(64) NOT NULL,
+		revised_at TIMESTAMP NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_model_tombstones_revised_at ON model_tombstones(revised_at);
+
+	CREATE TABLE IF NOT EXISTS model_baselines (
+		model_id VARCHAR(255) PRIMARY KEY REFERENCES models(id) ON DELETE CASCADE,
+		baseline JSONB NOT NULL,
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+	);
+
+	CREATE TABLE IF NOT EXISTS scaling_policies (
+		model VARCHAR(255) PRIMARY KEY,
+		policy JSONB NOT NULL,
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+	);
+
+	CREATE TABLE IF NOT EXISTS traffic_splits (
+		model VARCHAR(255) PRIMARY KEY,
+		split JSONB NOT NULL,
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+	);
+
+	CREATE TABLE IF NOT EXISTS version_aliases (
+		model VARCHAR(255) NOT NULL,
+		alias VARCHAR(50) NOT NULL,
+		version VARCHAR(50) NOT NULL,
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (model, alias)
+	);
 	`
 
-	_, err := r.db.Exec(query)
+	_, err := r.db.Load().Exec(query)
 	return err
 }
 
@@ -96,39 +176,45 @@ func (r *ModelRepository) Create(ctx context.Context, req *models.CreateModelReq
 		INSERT INTO models (
 			id, name, version, framework, format, description,
 			input_shape, output_shape, tags, status, backend_url,
-			created_by, created_at, updated_at, metadata
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			created_by, created_at, updated_at, metadata, origin_region, revised_at, tenant
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 		RETURNING id, created_at, updated_at
 	`
 
 	model := &models.ModelMetadata{
-		ID:          id,
-		Name:        req.Name,
-		Version:     req.Version,
-		Framework:   req.Framework,
-		Format:      req.Format,
-		Description: req.Description,
-		InputShape:  req.InputShape,
-		OutputShape: req.OutputShape,
-		Tags:        req.Tags,
-		Status:      "active",
-		BackendURL:  req.BackendURL,
-		CreatedBy:   req.CreatedBy,
-		Metadata:    req.Metadata,
-	}
-
-	err = r.db.QueryRowContext(ctx, query,
+		ID:           id,
+		Name:         req.Name,
+		Version:      req.Version,
+		Framework:    req.Framework,
+		Format:       req.Format,
+		Description:  req.Description,
+		InputShape:   req.InputShape,
+		OutputShape:  req.OutputShape,
+		Tags:         req.Tags,
+		Status:       "active",
+		BackendURL:   req.BackendURL,
+		CreatedBy:    req.CreatedBy,
+		Metadata:     req.Metadata,
+		OriginRegion: r.region,
+		RevisedAt:    now,
+		Tenant:       req.Tenant,
+	}
+
+	err = r.db.Load().QueryRowContext(ctx, query,
 		id, req.Name, req.Version, req.Framework, req.Format,
 		req.Description, req.InputShape, req.OutputShape,
 		pq.Array(req.Tags), "active", req.BackendURL,
-		req.CreatedBy, now, now, metadataJSON,
+		req.CreatedBy, now, now, metadataJSON, r.region, now, req.Tenant,
 	).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
 
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqUniqueViolation {
+		return nil, apperrors.Wrap(err, apperrors.AlreadyExists, fmt.Sprintf("model %s:%s already exists", req.Name, req.Version))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create model: %w", err)
 	}
 
-	r.logger.Info("created model",
+	logging.With(ctx, r.logger).Info("created model",
 		zap.String("id", model.ID),
 		zap.String("name", model.Name),
 		zap.String("version", model.Version),
@@ -143,12 +229,12 @@ func (r *ModelRepository) GetByID(ctx context.Context, id string) (*models.Model
 		SELECT id, name, version, framework, format, description,
 		       input_shape, output_shape, tags, status, backend_url,
 		       avg_latency_ms, request_count, error_rate,
-		       created_by, created_at, updated_at, metadata
+		       created_by, created_at, updated_at, metadata, origin_region, revised_at, tenant
 		FROM models
 		WHERE id = $1
 	`
 
-	return r.scanModel(r.db.QueryRowContext(ctx, query, id))
+	return r.scanModel(r.db.Load().QueryRowContext(ctx, query, id))
 }
 
 // GetByNameVersion retrieves a model by name and version
@@ -157,28 +243,31 @@ func (r *ModelRepository) GetByNameVersion(ctx context.Context, name, version st
 		SELECT id, name, version, framework, format, description,
 		       input_shape, output_shape, tags, status, backend_url,
 		       avg_latency_ms, request_count, error_rate,
-		       created_by, created_at, updated_at, metadata
+		       created_by, created_at, updated_at, metadata, origin_region, revised_at, tenant
 		FROM models
 		WHERE name = $1 AND version = $2
 	`
 
-	return r.scanModel(r.db.QueryRowContext(ctx, query, name, version))
+	return r.scanModel(r.db.Load().QueryRowContext(ctx, query, name, version))
 }
 
-// List retrieves all models with optional filtering
-func (r *ModelRepository) List(ctx context.Context, status string, limit, offset int) ([]*models.ModelMetadata, error) {
+// List retrieves all models with optional filtering. A tenant sees the shared
+// models and its own; an empty tenant sees every model.
+func (r *ModelRepository) List(ctx context.Context, name, status, tenant string, limit, offset int) ([]*models.ModelMetadata, error) {
 	query := `
 		SELECT id, name, version, framework, format, description,
 		       input_shape, output_shape, tags, status, backend_url,
 		       avg_latency_ms, request_count, error_rate,
-		       created_by, created_at, updated_at, metadata
+		       created_by, created_at, updated_at, metadata, origin_region, revised_at, tenant
 		FROM models
 		WHERE ($1 = '' OR status = $1)
+		  AND ($2 = '' OR tenant = '' OR tenant = $2)
+		  AND ($5 = '' OR name = $5)
 		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		LIMIT $3 OFFSET $4
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
+	rows, err := r.db.Load().QueryContext(ctx, query, status, tenant, limit, offset, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list models: %w", err)
 	}
@@ -196,12 +285,22 @@ func (r *ModelRepository) List(ctx context.Context, status string, limit, offset
 	return models, rows.Err()
 }
 
+// CountByTenant returns how many models a tenant owns
+func (r *ModelRepository) CountByTenant(ctx context.Context, tenant string) (int, error) {
+	var count int
+	err := r.db.Load().QueryRowContext(ctx, `SELECT COUNT(*) FROM models WHERE tenant = $1`, tenant).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count models: %w", err)
+	}
+	return count, nil
+}
+
 // Update updates a model
 func (r *ModelRepository) Update(ctx context.Context, id string, req *models.UpdateModelRequest) (*models.ModelMetadata, error) {
-	// Build dynamic update query
-	query := `UPDATE models SET updated_at = $1`
-	args := []interface{}{time.Now()}
-	argCount := 2
+	// Build dynamic update query; every update is a definition change that replicates
+	query := `UPDATE models SET updated_at = $1, revised_at = $1, origin_region = $2`
+	args := []interface{}{time.Now(), r.region}
+	argCount := 3
 
 	if req.Description != nil {
 		query += fmt.Sprintf(", description = $%d", argCount)
@@ -240,35 +339,43 @@ func (r *ModelRepository) Update(ctx context.Context, id string, req *models.Upd
 	query += fmt.Sprintf(" WHERE id = $%d", argCount)
 	args = append(args, id)
 
-	_, err := r.db.ExecContext(ctx, query, args...)
+	_, err := r.db.Load().ExecContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update model: %w", err)
 	}
 
-	r.logger.Info("updated model", zap.String("id", id))
+	logging.With(ctx, r.logger).Info("updated model", zap.String("id", id))
 
 	return r.GetByID(ctx, id)
 }
 
-// Delete deletes a model
+// Delete deletes a model and records a tombstone so the deletion replicates
 func (r *ModelRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM models WHERE id = $1`
+	tx, err := r.db.Load().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	tombstone := models.Tombstone{ID: id, OriginRegion: r.region, RevisedAt: time.Now()}
+	err = tx.QueryRowContext(ctx, `DELETE FROM models WHERE id = $1 RETURNING name, version`, id).
+		Scan(&tombstone.Name, &tombstone.Version)
+	if err == sql.ErrNoRows {
+		return apperrors.Newf(apperrors.NotFound, "model not found: %s", id)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete model: %w", err)
 	}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
+	if err := upsertTombstone(ctx, tx, tombstone); err != nil {
 		return err
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("model not found: %s", id)
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit delete: %w", err)
 	}
 
-	r.logger.Info("deleted model", zap.String("id", id))
+	logging.With(ctx, r.logger).Info("deleted model", zap.String("id", id))
 
 	return nil
 }
@@ -284,7 +391,7 @@ func (r *ModelRepository) UpdateStats(ctx context.Context, id string, latencyMs
 		WHERE id = $4
 	`
 
-	_, err := r.db.ExecContext(ctx, query, latencyMs, success, time.Now(), id)
+	_, err := r.db.Load().ExecContext(ctx, query, latencyMs, success, time.Now(), id)
 	return err
 }
 
@@ -300,10 +407,11 @@ func (r *ModelRepository) scanModel(row *sql.Row) (*models.ModelMetadata, error)
 		pq.Array(&model.Tags), &model.Status, &model.BackendURL,
 		&model.AvgLatencyMs, &model.RequestCount, &model.ErrorRate,
 		&createdBy, &model.CreatedAt, &model.UpdatedAt, &metadataJSON,
+		&model.OriginRegion, &model.RevisedAt, &model.Tenant,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("model not found")
+		return nil, apperrors.New(apperrors.NotFound, "model not found")
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan model: %w", err)
@@ -343,6 +451,7 @@ func (r *ModelRepository) scanModelFromRows(rows *sql.Rows) (*models.ModelMetada
 		pq.Array(&model.Tags), &model.Status, &model.BackendURL,
 		&model.AvgLatencyMs, &model.RequestCount, &model.ErrorRate,
 		&createdBy, &model.CreatedAt, &model.UpdatedAt, &metadataJSON,
+		&model.OriginRegion, &model.RevisedAt, &model.Tenant,
 	)
 
 	if err != nil {
@@ -371,7 +480,12 @@ func (r *ModelRepository) scanModelFromRows(rows *sql.Rows) (*models.ModelMetada
 	return &model, nil
 }
 
+// Ping verifies the database connection
+func (r *ModelRepository) Ping(ctx context.Context) error {
+	return r.db.Load().PingContext(ctx)
+}
+
 // Close closes the database connection
 func (r *ModelRepository) Close() error {
-	return r.db.Close()
+	return r.db.Load().Close()
 }
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yourusername/ai-platform/metadata-service/internal/models"
+)
+
+// Conflicts between regions are resolved last-writer-wins on (revised_at, origin_region):
+// the later definition change wins and the region name breaks exact ties, so every
+// region converges on the same row regardless of the order changes arrive in.
+
+// Changes returns models and tombstones revised at or after since, oldest first.
+// Changes that originated in excludeRegion are skipped so a peer is not sent its own writes.
+// Boundary rows may be returned again on the next page; applying them is idempotent.
+func (r *ModelRepository) Changes(ctx context.Context, since time.Time, excludeRegion string, limit int) (*models.ChangeSet, error) {
+	query := `
+		SELECT id, name, version, framework, format, description,
+		       input_shape, output_shape, tags, status, backend_url,
+		       avg_latency_ms, request_count, error_rate,
+		       created_by, created_at, updated_at, metadata, origin_region, revised_at, tenant
+		FROM models
+		WHERE revised_at >= $1 AND ($2 = '' OR origin_region <> $2)
+		ORDER BY revised_at
+		LIMIT $3
+	`
+
+	rows, err := r.db.Load().QueryContext(ctx, query, since, excludeRegion, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list model changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := &models.ChangeSet{
+		Region:     r.region,
+		Models:     []*models.ModelMetadata{},
+		Tombstones: []models.Tombstone{},
+		Cursor:     since,
+	}
+	for rows.Next() {
+		model, err := r.scanModelFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes.Models = append(changes.Models, model)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list model changes: %w", err)
+	}
+
+	tombstoneRows, err := r.db.Load().QueryContext(ctx, `
+		SELECT id, name, version, origin_region, revised_at
+		FROM model_tombstones
+		WHERE revised_at >= $1 AND ($2 = '' OR origin_region <> $2)
+		ORDER BY revised_at
+		LIMIT $3
+	`, since, excludeRegion, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tombstones: %w", err)
+	}
+	defer tombstoneRows.Close()
+
+	for tombstoneRows.Next() {
+		var t models.Tombstone
+		if err := tombstoneRows.Scan(&t.ID, &t.Name, &t.Version, &t.OriginRegion, &t.RevisedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tombstone: %w", err)
+		}
+		changes.Tombstones = append(changes.Tombstones, t)
+	}
+	if err := tombstoneRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tombstones: %w", err)
+	}
+
+	changes.Cursor, changes.More = nextCursor(since, changes, limit)
+	return changes, nil
+}
+
+// nextCursor returns where the next page starts. When either list was truncated the
+// cursor stops at the earliest truncation point so no change is skipped.
+func nextCursor(since time.Time, changes *models.ChangeSet, limit int) (time.Time, bool) {
+	cursor := since
+	var truncatedAt *time.Time
+
+	if n := len(changes.Models); n > 0 {
+		last := changes.Models[n-1].RevisedAt
+		if last.After(cursor) {
+			cursor = last
+		}
+		if n >= limit {
+			truncatedAt = &last
+		}
+	}
+	if n := len(changes.Tombstones); n > 0 {
+		last := changes.Tombstones[n-1].RevisedAt
+		if last.After(cursor) {
+			cursor = last
+		}
+		if n >= limit && (truncatedAt == nil || last.Before(*truncatedAt)) {
+			truncatedAt = &last
+		}
+	}
+
+	if truncatedAt != nil {
+		return *truncatedAt, true
+	}
+	return cursor, false
+}
+
+// Apply stores a model replicated from another region. It returns false when the
+// local copy or a tombstone for it is at least as recent, leaving the registry unchanged.
+func (r *ModelRepository) Apply(ctx context.Context, model *models.ModelMetadata) (bool, error) {
+	metadataJSON, err := json.Marshal(model.Metadata)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	tx, err := r.db.Load().BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var deleted bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM model_tombstones WHERE id = $1 AND revised_at >= $2)`,
+		model.ID, model.RevisedAt,
+	).Scan(&deleted)
+	if err != nil {
+		return false, fmt.Errorf("failed to check tombstones: %w", err)
+	}
+	if deleted {
+		return false, nil
+	}
+
+	// Registry statistics are regional and are never overwritten by replication
+	query := `
+		INSERT INTO models (
+			id, name, version, framework, format, description,
+			input_shape, output_shape, tags, status, backend_url,
+			created_by, created_at, updated_at, metadata, origin_region, revised_at, tenant
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (name, version) DO UPDATE SET
+			id = EXCLUDED.id,
+			framework = EXCLUDED.framework,
+			format = EXCLUDED.format,
+			description = EXCLUDED.description,
+			input_shape = EXCLUDED.input_shape,
+			output_shape = EXCLUDED.output_shape,
+			tags = EXCLUDED.tags,
+			status = EXCLUDED.status,
+			backend_url = EXCLUDED.backend_url,
+			created_by = EXCLUDED.created_by,
+			created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at,
+			metadata = EXCLUDED.metadata,
+			origin_region = EXCLUDED.origin_region,
+			revised_at = EXCLUDED.revised_at,
+			tenant = EXCLUDED.tenant
+		WHERE (models.revised_at, models.origin_region) < (EXCLUDED.revised_at, EXCLUDED.origin_region)
+	`
+
+	result, err := tx.ExecContext(ctx, query,
+		model.ID, model.Name, model.Version, model.Framework, model.Format,
+		model.Description, model.InputShape, model.OutputShape,
+		pq.Array(model.Tags), model.Status, model.BackendURL,
+		model.CreatedBy, model.CreatedAt, model.UpdatedAt, metadataJSON,
+		model.OriginRegion, model.RevisedAt, model.Tenant,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply model: %w", err)
+	}
+
+	applied, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit model: %w", err)
+	}
+	return applied > 0, nil
+}
+
+// ApplyTombstone deletes a model removed in another region unless it was revised
+// locally after the deletion. It returns true when a local row was deleted.
+func (r *ModelRepository) ApplyTombstone(ctx context.Context, tombstone models.Tombstone) (bool, error) {
+	tx, err := r.db.Load().BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := upsertTombstone(ctx, tx, tombstone); err != nil {
+		return false, err
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM models WHERE id = $1 AND revised_at <= $2`,
+		tombstone.ID, tombstone.RevisedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply tombstone: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit tombstone: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+func upsertTombstone(ctx context.Context, tx *sql.Tx, tombstone models.Tombstone) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO model_tombstones (id, name, version, origin_region, revised_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			origin_region = EXCLUDED.origin_region,
+			revised_at = EXCLUDED.revised_at
+		WHERE model_tombstones.revised_at < EXCLUDED.revised_at
+	`, tombstone.ID, tombstone.Name, tombstone.Version, tombstone.OriginRegion, tombstone.RevisedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record tombstone: %w", err)
+	}
+	return nil
+}
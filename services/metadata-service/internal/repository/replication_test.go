@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/ai-platform/metadata-service/internal/models"
+)
+
+func TestNextCursor(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return t0.Add(time.Duration(seconds) * time.Second) }
+
+	// Nothing new keeps the cursor where it was
+	cursor, more := nextCursor(t0, &models.ChangeSet{}, 2)
+	assert.Equal(t, t0, cursor)
+	assert.False(t, more)
+
+	// Complete pages advance to the newest change
+	cursor, more = nextCursor(t0, &models.ChangeSet{
+		Models:     []*models.ModelMetadata{{RevisedAt: at(1)}},
+		Tombstones: []models.Tombstone{{RevisedAt: at(5)}},
+	}, 2)
+	assert.Equal(t, at(5), cursor)
+	assert.False(t, more)
+
+	// A truncated model page holds the cursor back so later models are not skipped
+	cursor, more = nextCursor(t0, &models.ChangeSet{
+		Models:     []*models.ModelMetadata{{RevisedAt: at(1)}, {RevisedAt: at(2)}},
+		Tombstones: []models.Tombstone{{RevisedAt: at(5)}},
+	}, 2)
+	assert.Equal(t, at(2), cursor)
+	assert.True(t, more)
+}
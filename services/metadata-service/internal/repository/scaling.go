@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/scaling"
+	"go.uber.org/zap"
+)
+
+// Scaling policies are regional like baselines: each region sizes its own
+// deployments, so policies do not replicate.
+
+// SetScalingPolicy replaces the scaling policy of a registered model
+func (r *ModelRepository) SetScalingPolicy(ctx context.Context, model string, policy *scaling.Policy) (*scaling.Policy, error) {
+	policy.Model = model
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	var registered bool
+	err := r.db.Load().QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM models WHERE name = $1)`, model).Scan(&registered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up model: %w", err)
+	}
+	if !registered {
+		return nil, apperrors.Newf(apperrors.NotFound, "model not found: %s", model)
+	}
+
+	policy.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal scaling policy: %w", err)
+	}
+
+	_, err = r.db.Load().ExecContext(ctx, `
+		INSERT INTO scaling_policies (model, policy, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (model) DO UPDATE SET policy = EXCLUDED.policy, updated_at = EXCLUDED.updated_at
+	`, model, data, policy.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save scaling policy: %w", err)
+	}
+
+	logging.With(ctx, r.logger).Info("set scaling policy",
+		zap.String("model", model),
+		zap.String("deployment", policy.Deployment),
+		zap.Int32("min_replicas", policy.MinReplicas),
+		zap.Int32("max_replicas", policy.MaxReplicas),
+	)
+
+	return policy, nil
+}
+
+// GetScalingPolicy returns the scaling policy of a model
+func (r *ModelRepository) GetScalingPolicy(ctx context.Context, model string) (*scaling.Policy, error) {
+	var data []byte
+	err := r.db.Load().QueryRowContext(ctx, `SELECT policy FROM scaling_policies WHERE model = $1`, model).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.New(apperrors.NotFound, "scaling policy not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scaling policy: %w", err)
+	}
+
+	var policy scaling.Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scaling policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// ListScalingPolicies returns every scaling policy, ordered by model
+func (r *ModelRepository) ListScalingPolicies(ctx context.Context) ([]*scaling.Policy, error) {
+	rows, err := r.db.Load().QueryContext(ctx, `SELECT policy FROM scaling_policies ORDER BY model`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scaling policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := make([]*scaling.Policy, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan scaling policy: %w", err)
+		}
+		var policy scaling.Policy
+		if err := json.Unmarshal(data, &policy); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal scaling policy: %w", err)
+		}
+		policies = append(policies, &policy)
+	}
+	return policies, rows.Err()
+}
+
+// DeleteScalingPolicy removes the scaling policy of a model, leaving its
+// deployment at its current size
+func (r *ModelRepository) DeleteScalingPolicy(ctx context.Context, model string) error {
+	result, err := r.db.Load().ExecContext(ctx, `DELETE FROM scaling_policies WHERE model = $1`, model)
+	if err != nil {
+		return fmt.Errorf("failed to delete scaling policy: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return apperrors.New(apperrors.NotFound, "scaling policy not found")
+	}
+
+	logging.With(ctx, r.logger).Info("deleted scaling policy", zap.String("model", model))
+	return nil
+}
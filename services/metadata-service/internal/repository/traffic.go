@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/traffic"
+	"go.uber.org/zap"
+)
+
+// Traffic splits are regional like scaling policies: a canary is rolled out
+// region by region, so splits do not replicate.
+
+// SetTrafficSplit replaces the traffic split of a model. Every version it
+// sends requests to must be registered.
+func (r *ModelRepository) SetTrafficSplit(ctx context.Context, model string, split *traffic.Split) (*traffic.Split, error) {
+	split.Model = model
+	if err := split.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, version := range append(split.Versions(), split.Version) {
+		var registered bool
+		err := r.db.Load().QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM models WHERE name = $1 AND version = $2)`, model, version).Scan(&registered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up model: %w", err)
+		}
+		if !registered {
+			return nil, apperrors.Newf(apperrors.NotFound, "model not found: %s/%s", model, version)
+		}
+	}
+
+	split.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(split)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal traffic split: %w", err)
+	}
+
+	_, err = r.db.Load().ExecContext(ctx, `
+		INSERT INTO traffic_splits (model, split, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (model) DO UPDATE SET split = EXCLUDED.split, updated_at = EXCLUDED.updated_at
+	`, model, data, split.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save traffic split: %w", err)
+	}
+
+	logging.With(ctx, r.logger).Info("set traffic split",
+		zap.String("model", model),
+		zap.String("version", split.Version),
+		zap.Any("weights", split.Weights),
+	)
+
+	return split, nil
+}
+
+// GetTrafficSplit returns the traffic split of a model
+func (r *ModelRepository) GetTrafficSplit(ctx context.Context, model string) (*traffic.Split, error) {
+	var data []byte
+	err := r.db.Load().QueryRowContext(ctx, `SELECT split FROM traffic_splits WHERE model = $1`, model).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.New(apperrors.NotFound, "traffic split not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get traffic split: %w", err)
+	}
+
+	var split traffic.Split
+	if err := json.Unmarshal(data, &split); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal traffic split: %w", err)
+	}
+	return &split, nil
+}
+
+// ListTrafficSplits returns every traffic split, ordered by model
+func (r *ModelRepository) ListTrafficSplits(ctx context.Context) ([]*traffic.Split, error) {
+	rows, err := r.db.Load().QueryContext(ctx, `SELECT split FROM traffic_splits ORDER BY model`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list traffic splits: %w", err)
+	}
+	defer rows.Close()
+
+	splits := make([]*traffic.Split, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan traffic split: %w", err)
+		}
+		var split traffic.Split
+		if err := json.Unmarshal(data, &split); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal traffic split: %w", err)
+		}
+		splits = append(splits, &split)
+	}
+	return splits, rows.Err()
+}
+
+// DeleteTrafficSplit removes the traffic split of a model, so every request
+// is served by the version it names
+func (r *ModelRepository) DeleteTrafficSplit(ctx context.Context, model string) error {
+	result, err := r.db.Load().ExecContext(ctx, `DELETE FROM traffic_splits WHERE model = $1`, model)
+	if err != nil {
+		return fmt.Errorf("failed to delete traffic split: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return apperrors.New(apperrors.NotFound, "traffic split not found")
+	}
+
+	logging.With(ctx, r.logger).Info("deleted traffic split", zap.String("model", model))
+	return nil
+}
@@ -0,0 +1,125 @@
+// Package serving loads model versions on the serving cluster as they are
+// registered or activated, through the model repository control API of each
+// inference orchestrator, so the first requests to a new version do not
+// fail on a model Triton has not loaded.
+package serving
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/metadata-service/internal/models"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// DefaultTimeout bounds how long an orchestrator takes to load a model
+const DefaultTimeout = 5 * time.Minute
+
+// Loader asks inference orchestrators to load model versions. Loads run in
+// the background so registering a model never waits for, or fails with, a
+// load; failures are logged. A nil Loader loads nothing.
+type Loader struct {
+	orchestrators []string
+	client        *http.Client
+	logger        *zap.Logger
+
+	wg sync.WaitGroup
+}
+
+// NewLoader creates a loader for the orchestrators at urls
+func NewLoader(urls []string, logger *zap.Logger) *Loader {
+	orchestrators := make([]string, 0, len(urls))
+	for _, u := range urls {
+		if u = strings.TrimRight(strings.TrimSpace(u), "/"); u != "" {
+			orchestrators = append(orchestrators, u)
+		}
+	}
+	return &Loader{
+		orchestrators: orchestrators,
+		client:        &http.Client{Timeout: DefaultTimeout},
+		logger:        logger,
+	}
+}
+
+// SetHTTPClient replaces the client orchestrators are called with, e.g.
+// with one presenting the service's mTLS identity
+func (l *Loader) SetHTTPClient(client *http.Client) {
+	l.client = client
+}
+
+// Load asks every orchestrator to load model's version on its Triton
+func (l *Loader) Load(ctx context.Context, model *models.ModelMetadata) {
+	if l == nil {
+		return
+	}
+	// Loads outlive the registration request but keep its request ID
+	ctx = context.WithoutCancel(ctx)
+	for _, orchestrator := range l.orchestrators {
+		l.wg.Add(1)
+		go func(orchestrator string) {
+			defer l.wg.Done()
+			l.load(ctx, orchestrator, model)
+		}(orchestrator)
+	}
+}
+
+// Wait waits for the loads in progress to finish
+func (l *Loader) Wait() {
+	if l != nil {
+		l.wg.Wait()
+	}
+}
+
+func (l *Loader) load(ctx context.Context, orchestrator string, model *models.ModelMetadata) {
+	logger := logging.With(ctx, l.logger).With(
+		zap.String("orchestrator", orchestrator),
+		zap.String("model", model.Name),
+		zap.String("version", model.Version),
+	)
+	err := l.post(ctx, orchestrator, model)
+	switch {
+	case err == nil:
+		logger.Info("model loaded on serving cluster")
+	case apperrors.Is(err, apperrors.FailedPrecondition):
+		// Versions served by TorchServe or an LLM server are not loaded
+		// through Triton, and Triton cannot load models missing from its
+		// repository
+		logger.Info("model not loaded on serving cluster", zap.Error(err))
+	default:
+		logger.Warn("failed to load model on serving cluster", zap.Error(err))
+	}
+}
+
+func (l *Loader) post(ctx context.Context, orchestrator string, model *models.ModelMetadata) error {
+	body, err := json.Marshal(map[string]string{"version": model.Version})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/v1/models/%s/load", orchestrator, url.PathEscape(model.Name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	logging.Inject(ctx, req)
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "inference-orchestrator")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.FromHTTPResponse(resp, "inference-orchestrator")
+	}
+	return nil
+}
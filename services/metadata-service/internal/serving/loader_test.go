@@ -0,0 +1,51 @@
+package serving
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/metadata-service/internal/models"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+func TestLoader_LoadsOnEveryOrchestrator(t *testing.T) {
+	var mu sync.Mutex
+	var loads []string
+	orchestrator := func(status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			mu.Lock()
+			loads = append(loads, r.URL.Path+"@"+body["version"]+" "+r.Header.Get(logging.HeaderRequestID))
+			mu.Unlock()
+			w.WriteHeader(status)
+		}))
+	}
+	healthy := orchestrator(http.StatusOK)
+	defer healthy.Close()
+	failing := orchestrator(http.StatusServiceUnavailable)
+	defer failing.Close()
+
+	loader := NewLoader([]string{healthy.URL + "/", " ", failing.URL}, zap.NewNop())
+	ctx, cancel := context.WithCancel(logging.WithRequestID(context.Background(), "req-1"))
+	loader.Load(ctx, &models.ModelMetadata{Name: "resnet18", Version: "2"})
+	// Loads outlive the registration request
+	cancel()
+	loader.Wait()
+
+	assert.Equal(t, []string{"/v1/models/resnet18/load@2 req-1", "/v1/models/resnet18/load@2 req-1"}, loads)
+}
+
+func TestLoader_Nil(t *testing.T) {
+	var loader *Loader
+	loader.Load(context.Background(), &models.ModelMetadata{Name: "resnet18", Version: "1"})
+	loader.Wait()
+}
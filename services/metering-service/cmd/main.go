@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/metering-service/internal/config"
+	"github.com/yourusername/ai-platform/metering-service/internal/consumer"
+	"github.com/yourusername/ai-platform/metering-service/internal/costs"
+	"github.com/yourusername/ai-platform/metering-service/internal/handlers"
+	"github.com/yourusername/ai-platform/metering-service/internal/store"
+	"github.com/yourusername/ai-platform/pkg/faults"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/secrets"
+	"github.com/yourusername/ai-platform/pkg/transport"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// Initialize logger
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize logger: %v", err))
+	}
+	defer logger.Sync()
+
+	// Load configuration
+	cfg := config.Load()
+	logger.Info("configuration loaded",
+		zap.String("service", cfg.ServiceName),
+		zap.String("port", cfg.Port),
+		zap.String("topic", cfg.UsageTopic),
+	)
+
+	// Resolve credentials from Vault or a mounted secret store when configured
+	secretProvider, err := secrets.FromEnv(logger)
+	if err != nil {
+		logger.Fatal("failed to initialize secret provider", zap.Error(err))
+	}
+	secretManager := secrets.NewManager(secretProvider, logger)
+	defer secretManager.Close()
+
+	postgresURLRef := cfg.SecretsPath + "#postgres_url"
+	minioKeyRefs := []string{cfg.SecretsPath + "#minio_access_key", cfg.SecretsPath + "#minio_secret_key"}
+	cfg.PostgresURL = secretManager.LookupOr(context.Background(), postgresURLRef, cfg.PostgresURL)
+	if cfg.MinIOEndpoint != "" {
+		cfg.MinIOAccessKey = secretManager.LookupOr(context.Background(), minioKeyRefs[0], cfg.MinIOAccessKey)
+		cfg.MinIOSecretKey = secretManager.LookupOr(context.Background(), minioKeyRefs[1], cfg.MinIOSecretKey)
+	}
+	if cfg.PostgresURL == "" {
+		logger.Fatal("postgres is not configured; set POSTGRES_URL or provide it via VAULT_ADDR")
+	}
+
+	// Load the SPIFFE workload identity for mTLS between services
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+	}
+
+	// Initialize PostgreSQL store
+	usageStore, err := store.NewPostgresStore(cfg.PostgresURL, logger)
+	if err != nil {
+		logger.Fatal("failed to initialize postgres store", zap.Error(err))
+	}
+	defer usageStore.Close()
+	logger.Info("connected to PostgreSQL")
+
+	// Reconnect when the database credentials rotate
+	secretManager.Watch(context.Background(), postgresURLRef, 5*time.Minute, func(url string) {
+		if err := usageStore.Reconnect(url); err != nil {
+			logger.Error("failed to reconnect to postgres with rotated credentials", zap.Error(err))
+		}
+	})
+
+	// Readiness requires the database and the brokers usage is read from
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("postgres", usageStore.Ping)
+	checker.Add("kafka", health.TCPCheck(cfg.KafkaBrokers...))
+
+	// Aggregate usage events from every service into daily totals
+	usageConsumer, err := consumer.NewKafkaConsumer(
+		cfg.KafkaBrokers,
+		cfg.UsageTopic,
+		cfg.ConsumerGroup,
+		usageStore,
+		cfg.FlushSize,
+		cfg.FlushInterval,
+		logger,
+	)
+	if err != nil {
+		logger.Fatal("failed to create kafka consumer", zap.Error(err))
+	}
+
+	// Check usage events against their registered schemas when a registry is
+	// configured; registering the contract read also caches its schema ID
+	schemaCodec := schema.FromEnv()
+	if err := schemaCodec.Register(context.Background(), schema.UsageEvents); err != nil {
+		logger.Warn("failed to register message schemas", zap.Error(err))
+	}
+	usageConsumer.SetCodec(schemaCodec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		if err := usageConsumer.Start(ctx); err != nil {
+			logger.Error("kafka consumer error", zap.Error(err))
+		}
+	}()
+
+	// Forget the IDs of old events once redelivery is no longer possible
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pruned, err := usageStore.PruneEvents(ctx, time.Now().Add(-cfg.EventRetention))
+				if err != nil {
+					logger.Error("failed to prune usage events", zap.Error(err))
+					continue
+				}
+				logger.Info("pruned usage events", zap.Int64("count", pruned))
+			}
+		}
+	}()
+
+	// Attribute GPU node costs to tenants and models, exporting the reports to
+	// object storage when MinIO is configured
+	costReporter := costs.NewReporter(usageStore, cfg.Currency)
+	if cfg.MinIOEndpoint != "" {
+		exportStore, err := store.NewMinIOStore(cfg.MinIOEndpoint, cfg.MinIOAccessKey, cfg.MinIOSecretKey, cfg.CostExportBucket, logger)
+		if err != nil {
+			logger.Fatal("failed to initialize minio store", zap.Error(err))
+		}
+		checker.Add("minio", exportStore.Ping)
+		secretManager.WatchAll(ctx, minioKeyRefs, 5*time.Minute, func(keys []string) {
+			if err := exportStore.SetCredentials(keys[0], keys[1]); err != nil {
+				logger.Error("failed to update minio credentials", zap.Error(err))
+			}
+		})
+		exporter := costs.NewExporter(costReporter, exportStore, cfg.CostExportPrefix, logger)
+		go exporter.Run(ctx, cfg.CostExportInterval)
+		logger.Info("exporting cost reports",
+			zap.String("bucket", cfg.CostExportBucket),
+			zap.Duration("interval", cfg.CostExportInterval),
+		)
+	}
+
+	// Inject faults for resilience testing; a no-op unless rules are configured
+	faultInjector, err := faults.FromEnv(ctx, cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load fault injection rules", zap.Error(err))
+	}
+
+	// Setup router
+	if cfg.LogLevel == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Health checks
+	router.GET("/health", gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.LivenessPath, gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.ReadinessPath, gin.WrapH(checker.ReadinessHandler()))
+
+	// Usage, billing export and cost attribution
+	usageHandler := handlers.NewUsageHandler(usageStore, logger)
+	costHandler := handlers.NewCostHandler(costReporter, usageStore, logger)
+	v1 := router.Group("/v1")
+	{
+		v1.GET("/usage", usageHandler.GetUsage)
+		v1.GET("/billing/export", usageHandler.ExportBilling)
+		v1.GET("/costs", costHandler.GetCosts)
+		v1.PUT("/costs/nodes", costHandler.PutNodeCosts)
+	}
+
+	if faultInjector.Enabled() {
+		router.Any(faults.AdminPath, gin.WrapH(faultInjector.AdminHandler()))
+	}
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      logging.Middleware(faultInjector.Middleware(router)),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 60 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	go func() {
+		logger.Info("starting metering service", zap.String("port", cfg.Port))
+		if err := transport.ListenAndServe(srv, identity); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("failed to start server", zap.Error(err))
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("shutting down server...")
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Fatal("server forced to shutdown", zap.Error(err))
+	}
+
+	logger.Info("server exited")
+}
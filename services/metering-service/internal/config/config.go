@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the metering service configuration
+type Config struct {
+	ServiceName    string
+	Port           string
+	PostgresURL    string
+	KafkaBrokers   []string
+	UsageTopic     string
+	ConsumerGroup  string
+	SecretsPath    string
+	JaegerEndpoint string
+	LogLevel       string
+
+	// Aggregation
+	FlushSize     int
+	FlushInterval time.Duration
+	// EventRetention bounds how long event IDs are kept to discard redeliveries
+	EventRetention time.Duration
+
+	// Cost attribution; reports are exported when a MinIO endpoint is set
+	Currency           string
+	MinIOEndpoint      string
+	MinIOAccessKey     string
+	MinIOSecretKey     string
+	CostExportBucket   string
+	CostExportPrefix   string
+	CostExportInterval time.Duration
+}
+
+// Load loads configuration from environment variables
+func Load() *Config {
+	return &Config{
+		ServiceName:    getEnv("SERVICE_NAME", "metering-service"),
+		Port:           getEnv("PORT", "8085"),
+		PostgresURL:    getEnv("POSTGRES_URL", ""),
+		KafkaBrokers:   strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ","),
+		UsageTopic:     getEnv("USAGE_TOPIC", "usage-events"),
+		ConsumerGroup:  getEnv("CONSUMER_GROUP", "metering-service"),
+		SecretsPath:    getEnv("SECRETS_PATH", "secret/data/metering-service"),
+		JaegerEndpoint: getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
+		LogLevel:       getEnv("LOG_LEVEL", "info"),
+
+		FlushSize:      getEnvInt("USAGE_FLUSH_SIZE", 500),
+		FlushInterval:  getEnvDuration("USAGE_FLUSH_INTERVAL", 5*time.Second),
+		EventRetention: getEnvDuration("USAGE_EVENT_RETENTION", 7*24*time.Hour),
+
+		Currency:           getEnv("COST_CURRENCY", "USD"),
+		MinIOEndpoint:      getEnv("MINIO_ENDPOINT", ""),
+		MinIOAccessKey:     getEnv("MINIO_ACCESS_KEY", ""),
+		MinIOSecretKey:     getEnv("MINIO_SECRET_KEY", ""),
+		CostExportBucket:   getEnv("COST_EXPORT_BUCKET", "cost-reports"),
+		CostExportPrefix:   getEnv("COST_EXPORT_PREFIX", "costs"),
+		CostExportInterval: getEnvDuration("COST_EXPORT_INTERVAL", time.Hour),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,221 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/usage"
+	"go.uber.org/zap"
+)
+
+// Store applies batches of usage events
+type Store interface {
+	Apply(ctx context.Context, events []usage.Event) (int, error)
+}
+
+// KafkaConsumer aggregates usage events from Kafka into the store
+type KafkaConsumer struct {
+	consumer sarama.ConsumerGroup
+	topic    string
+	handler  *consumerGroupHandler
+	logger   *zap.Logger
+}
+
+// NewKafkaConsumer creates a consumer that writes to store every flushSize
+// events or flushInterval, whichever comes first
+func NewKafkaConsumer(
+	brokers []string,
+	topic string,
+	groupID string,
+	store Store,
+	flushSize int,
+	flushInterval time.Duration,
+	logger *zap.Logger,
+) (*KafkaConsumer, error) {
+	config := sarama.NewConfig()
+	config.Version = sarama.V3_3_0_0
+	config.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRoundRobin
+	// Usage must not be lost when the group is first created
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.Return.Errors = true
+
+	consumer, err := sarama.NewConsumerGroup(brokers, groupID, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	return &KafkaConsumer{
+		consumer: consumer,
+		topic:    topic,
+		handler: &consumerGroupHandler{
+			store:         store,
+			flushSize:     flushSize,
+			flushInterval: flushInterval,
+			logger:        logger,
+		},
+		logger: logger,
+	}, nil
+}
+
+// SetCodec checks usage events against their registered schemas
+func (c *KafkaConsumer) SetCodec(codec *schema.Codec) {
+	c.handler.codec = codec
+}
+
+// Start consumes usage events until ctx is cancelled
+func (c *KafkaConsumer) Start(ctx context.Context) error {
+	c.logger.Info("starting usage consumer", zap.String("topic", c.topic))
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("shutting down usage consumer")
+			return c.consumer.Close()
+		default:
+			if err := c.consumer.Consume(ctx, []string{c.topic}, c.handler); err != nil {
+				c.logger.Error("consumer error", zap.Error(err))
+				return err
+			}
+		}
+	}
+}
+
+// consumerGroupHandler implements sarama.ConsumerGroupHandler
+type consumerGroupHandler struct {
+	store         Store
+	flushSize     int
+	flushInterval time.Duration
+	codec         *schema.Codec
+	logger        *zap.Logger
+}
+
+// Setup is run at the beginning of a new session
+func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// Cleanup is run at the end of a session
+func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim buffers a partition's events and marks them consumed only once
+// they are stored, so a crash replays rather than loses usage
+func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	pending := &batch{}
+	ticker := time.NewTicker(h.flushInterval)
+	defer ticker.Stop()
+
+	mark := func(message *sarama.ConsumerMessage) {
+		session.MarkMessage(message, "")
+	}
+
+	for {
+		// Stop reading while a full batch cannot be stored
+		for pending.len() >= h.flushSize && !h.flush(session.Context(), pending, mark) {
+			select {
+			case <-session.Context().Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+
+		select {
+		case <-session.Context().Done():
+			h.drain(pending, mark)
+			return nil
+		case <-ticker.C:
+			h.flush(session.Context(), pending, mark)
+		case message, ok := <-claim.Messages():
+			if !ok {
+				h.drain(pending, mark)
+				return nil
+			}
+			if message == nil {
+				continue
+			}
+			if err := h.add(session.Context(), pending, message); err != nil {
+				// End the session so the message is redelivered
+				h.logger.Error("failed to decode usage event", zap.Error(err))
+				h.drain(pending, mark)
+				return err
+			}
+		}
+	}
+}
+
+// drain stores what is buffered when the session ends; unstored events are redelivered
+func (h *consumerGroupHandler) drain(pending *batch, mark func(*sarama.ConsumerMessage)) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	h.flush(ctx, pending, mark)
+}
+
+// add buffers a message's event. Events that break the contract are
+// discarded; other decoding errors, such as an unreachable schema registry,
+// are returned without consuming the message.
+func (h *consumerGroupHandler) add(ctx context.Context, pending *batch, message *sarama.ConsumerMessage) error {
+	var event usage.Event
+	err := h.codec.Decode(ctx, schema.UsageEvents, message.Value, &event)
+	if err != nil && !apperrors.Is(err, apperrors.InvalidArgument) {
+		return err
+	}
+
+	pending.last = message
+	if err != nil {
+		h.logger.Error("discarding malformed usage event",
+			zap.Int32("partition", message.Partition),
+			zap.Int64("offset", message.Offset),
+			zap.Error(err),
+		)
+		return nil
+	}
+	pending.events = append(pending.events, event)
+	return nil
+}
+
+// flush stores the pending events and marks the last message consumed.
+// It reports false when the store failed and the events are kept for retry.
+func (h *consumerGroupHandler) flush(ctx context.Context, pending *batch, mark func(*sarama.ConsumerMessage)) bool {
+	if pending.last == nil {
+		return true
+	}
+
+	if len(pending.events) > 0 {
+		applied, err := h.store.Apply(ctx, pending.events)
+		if err != nil {
+			h.logger.Error("failed to store usage events",
+				zap.Int("events", len(pending.events)),
+				zap.Error(err),
+			)
+			return false
+		}
+		h.logger.Debug("stored usage events",
+			zap.Int("events", len(pending.events)),
+			zap.Int("applied", applied),
+		)
+	}
+
+	mark(pending.last)
+	pending.reset()
+	return true
+}
+
+// batch holds the events read from a partition since the last flush
+type batch struct {
+	events []usage.Event
+	last   *sarama.ConsumerMessage
+}
+
+func (b *batch) len() int {
+	return len(b.events)
+}
+
+func (b *batch) reset() {
+	b.events = b.events[:0]
+	b.last = nil
+}
@@ -0,0 +1,95 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/usage"
+	"go.uber.org/zap"
+)
+
+type fakeStore struct {
+	err     error
+	batches [][]usage.Event
+}
+
+func (s *fakeStore) Apply(ctx context.Context, events []usage.Event) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.batches = append(s.batches, append([]usage.Event(nil), events...))
+	return len(events), nil
+}
+
+func message(t *testing.T, offset int64, event usage.Event) *sarama.ConsumerMessage {
+	value, err := json.Marshal(event)
+	assert.NoError(t, err)
+	return &sarama.ConsumerMessage{Offset: offset, Value: value}
+}
+
+func TestFlush_MarksLastMessageAfterStoring(t *testing.T) {
+	store := &fakeStore{}
+	h := &consumerGroupHandler{store: store, logger: zap.NewNop()}
+	pending := &batch{}
+
+	h.add(context.Background(), pending, message(t, 1, usage.Event{ID: "a", Tenant: "acme", Kind: usage.KindRealtime, Requests: 1}))
+	h.add(context.Background(), pending, &sarama.ConsumerMessage{Offset: 2, Value: []byte("not json")})
+	h.add(context.Background(), pending, message(t, 3, usage.Event{ID: "b", Tenant: "acme", Kind: usage.KindRealtime, Requests: 1}))
+
+	var marked []int64
+	ok := h.flush(context.Background(), pending, func(m *sarama.ConsumerMessage) {
+		marked = append(marked, m.Offset)
+	})
+
+	assert.True(t, ok)
+	assert.Equal(t, []int64{3}, marked)
+	assert.Len(t, store.batches, 1)
+	assert.Len(t, store.batches[0], 2)
+	assert.Zero(t, pending.len())
+}
+
+func TestFlush_KeepsEventsWhenStoreFails(t *testing.T) {
+	store := &fakeStore{err: errors.New("database unavailable")}
+	h := &consumerGroupHandler{store: store, logger: zap.NewNop()}
+	pending := &batch{}
+	h.add(context.Background(), pending, message(t, 1, usage.Event{ID: "a", Tenant: "acme", Kind: usage.KindRealtime, Requests: 1}))
+
+	marked := false
+	ok := h.flush(context.Background(), pending, func(*sarama.ConsumerMessage) { marked = true })
+
+	assert.False(t, ok)
+	assert.False(t, marked)
+	assert.Equal(t, 1, pending.len())
+
+	store.err = nil
+	assert.True(t, h.flush(context.Background(), pending, func(*sarama.ConsumerMessage) { marked = true }))
+	assert.True(t, marked)
+}
+
+func TestAdd_LeavesMessageWhenRegistryUnavailable(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer registry.Close()
+
+	h := &consumerGroupHandler{
+		codec:  schema.NewCodec(schema.NewRegistry(registry.URL, registry.Client())),
+		logger: zap.NewNop(),
+	}
+	pending := &batch{}
+
+	// A framed message whose schema ID must be looked up
+	value := append([]byte{0, 0, 0, 0, 7}, message(t, 1, usage.Event{ID: "a", Kind: usage.KindRealtime}).Value...)
+	err := h.add(context.Background(), pending, &sarama.ConsumerMessage{Offset: 1, Value: value})
+
+	assert.Error(t, err)
+	assert.Nil(t, pending.last)
+	assert.Zero(t, pending.len())
+}
@@ -0,0 +1,104 @@
+package costs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/metering-service/internal/store"
+)
+
+// ObjectStore keeps exported reports
+type ObjectStore interface {
+	PutObject(ctx context.Context, name string, data []byte, contentType string) error
+}
+
+// Exporter writes cost reports to object storage on a schedule. Each run
+// exports the previous UTC day and that day's month to date, replacing
+// earlier exports of the same period, so node costs recorded late are
+// picked up by the next run and replicas exporting concurrently agree.
+type Exporter struct {
+	reporter *Reporter
+	objects  ObjectStore
+	prefix   string
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+// NewExporter creates an exporter writing reports under prefix
+func NewExporter(reporter *Reporter, objects ObjectStore, prefix string, logger *zap.Logger) *Exporter {
+	return &Exporter{
+		reporter: reporter,
+		objects:  objects,
+		prefix:   prefix,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Run exports immediately and then every interval until ctx is cancelled
+func (e *Exporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := e.Export(ctx); err != nil {
+			e.logger.Error("failed to export cost reports", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Export writes the reports for the previous UTC day and its month to date
+func (e *Exporter) Export(ctx context.Context) error {
+	today := e.now().UTC().Truncate(24 * time.Hour)
+	day := today.AddDate(0, 0, -1)
+	month := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	periods := []struct {
+		name     string
+		from, to time.Time
+	}{
+		{"daily/" + day.Format(store.DayFormat), day, today},
+		{"monthly/" + day.Format("2006-01"), month, today},
+	}
+	for _, period := range periods {
+		report, err := e.reporter.Report(ctx, store.Filter{From: period.from, To: period.to}, ByTenantModel)
+		if err != nil {
+			return fmt.Errorf("failed to build %s report: %w", period.name, err)
+		}
+		if err := e.write(ctx, e.prefix+"/"+period.name, report); err != nil {
+			return err
+		}
+		e.logger.Info("exported cost report",
+			zap.String("period", period.name),
+			zap.Float64("total", report.Total),
+			zap.Int("lines", len(report.Lines)),
+		)
+	}
+	return nil
+}
+
+// write stores the report as JSON and as CSV
+func (e *Exporter) write(ctx context.Context, name string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cost report: %w", err)
+	}
+	if err := e.objects.PutObject(ctx, name+".json", data, "application/json"); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		return err
+	}
+	return e.objects.PutObject(ctx, name+".csv", buf.Bytes(), "text/csv")
+}
@@ -0,0 +1,243 @@
+// Package costs attributes GPU node pool spend to tenants and models.
+//
+// Each node pool's cost for a day is split between the tenants and models
+// that ran on it in proportion to their share of the pool's backend
+// execution time that day, as metered by the inference orchestrator. Spend on
+// days a pool ran nothing is reported as unattributed rather than spread
+// arbitrarily. Metered requests from the gateway and batch worker give the
+// cost per thousand requests.
+package costs
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/ai-platform/metering-service/internal/store"
+)
+
+// Groupings of a cost report
+const (
+	ByTenantModel = "tenant_model"
+	ByTenant      = "tenant"
+	ByModel       = "model"
+)
+
+// ValidGrouping reports whether groupBy is a supported grouping
+func ValidGrouping(groupBy string) bool {
+	return groupBy == ByTenantModel || groupBy == ByTenant || groupBy == ByModel
+}
+
+// Line is the cost attributed to a tenant, a model or a tenant's model
+type Line struct {
+	Tenant       string  `json:"tenant,omitempty"`
+	Model        string  `json:"model,omitempty"`
+	Cost         float64 `json:"cost"`
+	ComputeHours float64 `json:"compute_hours"`
+	Executions   int64   `json:"executions"`
+	Requests     int64   `json:"requests"`
+	// CostPer1K is the cost of a thousand metered requests
+	CostPer1K float64 `json:"cost_per_1k_requests,omitempty"`
+}
+
+// Pool summarises one node pool over the period
+type Pool struct {
+	Pool         string  `json:"pool"`
+	Cost         float64 `json:"cost"`
+	Unattributed float64 `json:"unattributed"`
+	ComputeHours float64 `json:"compute_hours"`
+	GPUHours     float64 `json:"gpu_hours,omitempty"`
+	// Utilization is execution time as a share of the GPU time provided,
+	// when the pool's GPU hours are known
+	Utilization float64 `json:"utilization,omitempty"`
+}
+
+// Report is the spend of a period and who it is attributed to
+type Report struct {
+	From         string    `json:"from"`
+	To           string    `json:"to"`
+	Currency     string    `json:"currency"`
+	GroupBy      string    `json:"group_by"`
+	GeneratedAt  time.Time `json:"generated_at"`
+	Total        float64   `json:"total"`
+	Unattributed float64   `json:"unattributed"`
+	Pools        []Pool    `json:"pools"`
+	Lines        []Line    `json:"lines"`
+}
+
+// Store reads the inputs of a cost report
+type Store interface {
+	Totals(ctx context.Context, filter store.Filter) ([]store.BillingLine, error)
+	ComputeDaily(ctx context.Context, filter store.Filter) ([]store.ComputeUsage, error)
+	NodeCosts(ctx context.Context, filter store.Filter) ([]store.NodeCost, error)
+}
+
+// Reporter builds cost reports from the metering store
+type Reporter struct {
+	store    Store
+	currency string
+	now      func() time.Time
+}
+
+// NewReporter creates a reporter whose costs are in currency
+func NewReporter(store Store, currency string) *Reporter {
+	return &Reporter{store: store, currency: currency, now: time.Now}
+}
+
+// Report attributes the spend in filter's period. Shares are computed over
+// every tenant and model, so filtering by tenant or model only selects lines.
+func (r *Reporter) Report(ctx context.Context, filter store.Filter, groupBy string) (*Report, error) {
+	period := store.Filter{From: filter.From, To: filter.To}
+	nodeCosts, err := r.store.NodeCosts(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	compute, err := r.store.ComputeDaily(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := r.store.Totals(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+
+	report := Build(nodeCosts, compute, requests, groupBy)
+	report.From = filter.From.Format(store.DayFormat)
+	report.To = filter.To.AddDate(0, 0, -1).Format(store.DayFormat)
+	report.Currency = r.currency
+	report.GeneratedAt = r.now().UTC()
+
+	if filter.Tenant != "" || filter.Model != "" {
+		lines := report.Lines[:0]
+		for _, line := range report.Lines {
+			if (filter.Tenant == "" || line.Tenant == filter.Tenant) && (filter.Model == "" || line.Model == filter.Model) {
+				lines = append(lines, line)
+			}
+		}
+		report.Lines = lines
+	}
+	return report, nil
+}
+
+// Build attributes node costs to the tenants and models that ran on each
+// pool, grouped by groupBy. Versions are combined, since the versions the
+// orchestrator executes are not those callers request.
+func Build(nodeCosts []store.NodeCost, compute []store.ComputeUsage, requests []store.BillingLine, groupBy string) *Report {
+	type poolDay struct{ pool, day string }
+	type group struct{ tenant, model string }
+	keyOf := func(tenant, model string) group {
+		switch groupBy {
+		case ByTenant:
+			return group{tenant: tenant}
+		case ByModel:
+			return group{model: model}
+		default:
+			return group{tenant, model}
+		}
+	}
+
+	executed := make(map[poolDay]int64)
+	for _, row := range compute {
+		executed[poolDay{row.Pool, row.Day}] += row.ComputeMs
+	}
+	costs := make(map[poolDay]float64, len(nodeCosts))
+	pools := make(map[string]*Pool)
+	pool := func(name string) *Pool {
+		if pools[name] == nil {
+			pools[name] = &Pool{Pool: name}
+		}
+		return pools[name]
+	}
+
+	report := &Report{GroupBy: groupBy, Pools: []Pool{}, Lines: []Line{}}
+	for _, cost := range nodeCosts {
+		key := poolDay{cost.Pool, cost.Day}
+		costs[key] += cost.Cost
+		p := pool(cost.Pool)
+		p.Cost += cost.Cost
+		p.GPUHours += cost.GPUHours
+		report.Total += cost.Cost
+		if executed[key] == 0 {
+			p.Unattributed += cost.Cost
+			report.Unattributed += cost.Cost
+		}
+	}
+
+	lines := make(map[group]*Line)
+	line := func(key group) *Line {
+		if lines[key] == nil {
+			lines[key] = &Line{Tenant: key.tenant, Model: key.model}
+		}
+		return lines[key]
+	}
+	for _, row := range compute {
+		key := poolDay{row.Pool, row.Day}
+		l := line(keyOf(row.Tenant, row.Model))
+		l.Cost += costs[key] * float64(row.ComputeMs) / float64(executed[key])
+		l.ComputeHours += hours(row.ComputeMs)
+		l.Executions += row.Executions
+		pool(row.Pool).ComputeHours += hours(row.ComputeMs)
+	}
+	for _, billed := range requests {
+		line(keyOf(billed.Tenant, billed.Model)).Requests += billed.Requests
+	}
+
+	for _, l := range lines {
+		if l.Requests > 0 {
+			l.CostPer1K = l.Cost / float64(l.Requests) * 1000
+		}
+		report.Lines = append(report.Lines, *l)
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		return a.Model < b.Model
+	})
+
+	for _, p := range pools {
+		if p.GPUHours > 0 {
+			p.Utilization = p.ComputeHours / p.GPUHours
+		}
+		report.Pools = append(report.Pools, *p)
+	}
+	sort.Slice(report.Pools, func(i, j int) bool { return report.Pools[i].Pool < report.Pools[j].Pool })
+	return report
+}
+
+func hours(ms int64) float64 {
+	return float64(ms) / float64(time.Hour/time.Millisecond)
+}
+
+// WriteCSV writes the report's lines as CSV
+func (r *Report) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"period_start", "period_end", "tenant", "model", "currency", "cost", "compute_hours", "executions", "requests", "cost_per_1k_requests"})
+	for _, line := range r.Lines {
+		out.Write([]string{
+			r.From, r.To, line.Tenant, line.Model, r.Currency,
+			money(line.Cost),
+			strconv.FormatFloat(line.ComputeHours, 'f', 4, 64),
+			strconv.FormatInt(line.Executions, 10),
+			strconv.FormatInt(line.Requests, 10),
+			money(line.CostPer1K),
+		})
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return fmt.Errorf("failed to write cost report: %w", err)
+	}
+	return nil
+}
+
+func money(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 4, 64)
+}
@@ -0,0 +1,142 @@
+package costs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/metering-service/internal/store"
+)
+
+const hourMs = int64(time.Hour / time.Millisecond)
+
+var (
+	nodeCosts = []store.NodeCost{
+		{Day: "2026-10-14", Pool: "gpu-a100", Cost: 300, GPUHours: 24},
+		{Day: "2026-10-14", Pool: "gpu-t4", Cost: 50},
+		{Day: "2026-10-15", Pool: "gpu-a100", Cost: 300, GPUHours: 24},
+	}
+	compute = []store.ComputeUsage{
+		{Tenant: "acme", Model: "llama", Version: "1", Pool: "gpu-a100", Day: "2026-10-14", Executions: 100, ComputeMs: 4 * hourMs},
+		{Tenant: "globex", Model: "llama", Version: "1", Pool: "gpu-a100", Day: "2026-10-14", Executions: 50, ComputeMs: 2 * hourMs},
+		{Tenant: "acme", Model: "resnet18", Version: "1", Pool: "gpu-a100", Day: "2026-10-15", Executions: 10, ComputeMs: hourMs},
+	}
+	billed = []store.BillingLine{
+		{Tenant: "acme", Model: "llama", Version: "v1", Quantities: store.Quantities{Requests: 1000}},
+		{Tenant: "acme", Model: "llama", Version: "v2", Quantities: store.Quantities{Requests: 1000}},
+		{Tenant: "globex", Model: "llama", Version: "v1", Quantities: store.Quantities{Requests: 500}},
+	}
+)
+
+func TestBuild_AttributesPoolCostByExecutionTime(t *testing.T) {
+	report := Build(nodeCosts, compute, billed, ByTenantModel)
+
+	assert.Equal(t, 650.0, report.Total)
+	assert.Equal(t, 50.0, report.Unattributed, "the t4 pool ran nothing")
+
+	require.Len(t, report.Lines, 3)
+	// Ordered by cost
+	assert.Equal(t, Line{Tenant: "acme", Model: "resnet18", Cost: 300, ComputeHours: 1, Executions: 10}, report.Lines[0])
+	assert.Equal(t, "acme", report.Lines[1].Tenant)
+	assert.InDelta(t, 200, report.Lines[1].Cost, 1e-9)
+	assert.Equal(t, int64(2000), report.Lines[1].Requests, "versions are combined")
+	assert.InDelta(t, 100, report.Lines[1].CostPer1K, 1e-9)
+	assert.Equal(t, "globex", report.Lines[2].Tenant)
+	assert.InDelta(t, 100, report.Lines[2].Cost, 1e-9)
+
+	require.Len(t, report.Pools, 2)
+	assert.Equal(t, "gpu-a100", report.Pools[0].Pool)
+	assert.InDelta(t, 7.0/48, report.Pools[0].Utilization, 1e-9)
+	assert.Equal(t, Pool{Pool: "gpu-t4", Cost: 50, Unattributed: 50}, report.Pools[1])
+}
+
+func TestBuild_Groupings(t *testing.T) {
+	byTenant := Build(nodeCosts, compute, billed, ByTenant)
+	require.Len(t, byTenant.Lines, 2)
+	assert.Equal(t, "acme", byTenant.Lines[0].Tenant)
+	assert.Empty(t, byTenant.Lines[0].Model)
+	assert.InDelta(t, 500, byTenant.Lines[0].Cost, 1e-9)
+
+	byModel := Build(nodeCosts, compute, billed, ByModel)
+	require.Len(t, byModel.Lines, 2)
+	assert.Equal(t, "llama", byModel.Lines[0].Model)
+	assert.InDelta(t, 300, byModel.Lines[0].Cost, 1e-9)
+	assert.Equal(t, int64(2500), byModel.Lines[0].Requests)
+}
+
+type fakeStore struct{}
+
+func (fakeStore) Totals(ctx context.Context, filter store.Filter) ([]store.BillingLine, error) {
+	return billed, nil
+}
+
+func (fakeStore) ComputeDaily(ctx context.Context, filter store.Filter) ([]store.ComputeUsage, error) {
+	if filter.Tenant != "" {
+		panic("shares must be computed over every tenant")
+	}
+	return compute, nil
+}
+
+func (fakeStore) NodeCosts(ctx context.Context, filter store.Filter) ([]store.NodeCost, error) {
+	return nodeCosts, nil
+}
+
+func TestReporter_FiltersLinesAfterAttribution(t *testing.T) {
+	reporter := NewReporter(fakeStore{}, "USD")
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	report, err := reporter.Report(context.Background(), store.Filter{Tenant: "globex", From: from, To: from.AddDate(0, 1, 0)}, ByTenantModel)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-10-01", report.From)
+	assert.Equal(t, "2026-10-31", report.To)
+	assert.Equal(t, "USD", report.Currency)
+	require.Len(t, report.Lines, 1)
+	assert.InDelta(t, 100, report.Lines[0].Cost, 1e-9)
+}
+
+type fakeObjects map[string][]byte
+
+func (o fakeObjects) PutObject(ctx context.Context, name string, data []byte, contentType string) error {
+	o[name] = data
+	return nil
+}
+
+func TestExporter_WritesDailyAndMonthToDate(t *testing.T) {
+	objects := fakeObjects{}
+	exporter := NewExporter(NewReporter(fakeStore{}, "USD"), objects, "costs", zap.NewNop())
+	exporter.now = func() time.Time { return time.Date(2026, 11, 1, 3, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, exporter.Export(context.Background()))
+
+	assert.Len(t, objects, 4)
+	var daily Report
+	require.NoError(t, json.Unmarshal(objects["costs/daily/2026-10-31.json"], &daily))
+	assert.Equal(t, "2026-10-31", daily.From)
+	assert.Equal(t, "2026-10-31", daily.To)
+
+	var monthly Report
+	require.NoError(t, json.Unmarshal(objects["costs/monthly/2026-10.json"], &monthly))
+	assert.Equal(t, "2026-10-01", monthly.From)
+
+	csv := string(objects["costs/monthly/2026-10.csv"])
+	assert.True(t, strings.HasPrefix(csv, "period_start,period_end,tenant,model,currency,cost"))
+	assert.Contains(t, csv, "2026-10-01,2026-10-31,acme,resnet18,USD,300.0000,1.0000,10,0,0.0000")
+}
+
+func TestReport_WriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	report := &Report{From: "2026-10-01", To: "2026-10-31", Currency: "EUR", Lines: []Line{{Tenant: "acme", Model: "llama", Cost: 12.5, Requests: 2000, CostPer1K: 6.25}}}
+	require.NoError(t, report.WriteCSV(&buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "2026-10-01,2026-10-31,acme,llama,EUR,12.5000,0.0000,0,2000,6.2500", lines[1])
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/metering-service/internal/costs"
+	"github.com/yourusername/ai-platform/metering-service/internal/store"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// maxNodeCosts bounds the node costs recorded in one request
+const maxNodeCosts = 10000
+
+// NodeCostStore records GPU node pool costs
+type NodeCostStore interface {
+	PutNodeCosts(ctx context.Context, costs []store.NodeCost) error
+}
+
+// CostHandler serves cost attribution reports and accepts node pool costs
+type CostHandler struct {
+	reporter *costs.Reporter
+	store    NodeCostStore
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+// NewCostHandler creates a new cost handler
+func NewCostHandler(reporter *costs.Reporter, store NodeCostStore, logger *zap.Logger) *CostHandler {
+	return &CostHandler{
+		reporter: reporter,
+		store:    store,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// GetCosts returns the spend of a period attributed per tenant and model,
+// per tenant or per model (group_by), as JSON or, with format=csv, as a CSV
+// download. The period is selected as for usage queries.
+func (h *CostHandler) GetCosts(c *gin.Context) {
+	filter, err := parseFilter(c, h.now())
+	if err != nil {
+		c.JSON(apperrors.ToHTTP(err))
+		return
+	}
+
+	groupBy := c.DefaultQuery("group_by", costs.ByTenantModel)
+	if !costs.ValidGrouping(groupBy) {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "unsupported grouping %q", groupBy)))
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "unsupported export format %q", format)))
+		return
+	}
+
+	report, err := h.reporter.Report(c.Request.Context(), filter, groupBy)
+	if err != nil {
+		logging.With(c.Request.Context(), h.logger).Error("failed to build cost report", zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to build cost report")))
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="costs-%s-%s.csv"`, report.From, report.To))
+	c.Status(http.StatusOK)
+	report.WriteCSV(c.Writer)
+}
+
+// NodeCostsRequest records what GPU node pools cost, typically from a cloud
+// billing export
+type NodeCostsRequest struct {
+	Costs []store.NodeCost `json:"costs" binding:"required"`
+}
+
+// PutNodeCosts records node pool costs per UTC day, replacing those recorded
+// earlier for the same day and pool
+func (h *CostHandler) PutNodeCosts(c *gin.Context) {
+	var req NodeCostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error())))
+		return
+	}
+	if len(req.Costs) > maxNodeCosts {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "at most %d costs may be recorded at once", maxNodeCosts)))
+		return
+	}
+	for i, cost := range req.Costs {
+		if _, err := time.Parse(store.DayFormat, cost.Day); err != nil {
+			c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "costs[%d]: day must be YYYY-MM-DD", i)))
+			return
+		}
+		if cost.Pool == "" || cost.Cost < 0 || cost.GPUHours < 0 {
+			c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "costs[%d]: pool is required and amounts must not be negative", i)))
+			return
+		}
+	}
+
+	if err := h.store.PutNodeCosts(c.Request.Context(), req.Costs); err != nil {
+		logging.With(c.Request.Context(), h.logger).Error("failed to record node costs", zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to record node costs")))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recorded": len(req.Costs)})
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ai-platform/metering-service/internal/costs"
+	"github.com/yourusername/ai-platform/metering-service/internal/store"
+	"go.uber.org/zap"
+)
+
+type fakeCostStore struct {
+	recorded []store.NodeCost
+}
+
+func (s *fakeCostStore) Totals(ctx context.Context, filter store.Filter) ([]store.BillingLine, error) {
+	return []store.BillingLine{{Tenant: "acme", Model: "resnet18", Version: "v1", Quantities: store.Quantities{Requests: 4000}}}, nil
+}
+
+func (s *fakeCostStore) ComputeDaily(ctx context.Context, filter store.Filter) ([]store.ComputeUsage, error) {
+	return []store.ComputeUsage{
+		{Tenant: "acme", Model: "resnet18", Version: "1", Pool: "gpu", Day: "2026-10-01", Executions: 30, ComputeMs: 3000},
+		{Tenant: "globex", Model: "resnet18", Version: "1", Pool: "gpu", Day: "2026-10-01", Executions: 10, ComputeMs: 1000},
+	}, nil
+}
+
+func (s *fakeCostStore) NodeCosts(ctx context.Context, filter store.Filter) ([]store.NodeCost, error) {
+	return []store.NodeCost{{Day: "2026-10-01", Pool: "gpu", Cost: 40}}, nil
+}
+
+func (s *fakeCostStore) PutNodeCosts(ctx context.Context, costs []store.NodeCost) error {
+	s.recorded = append(s.recorded, costs...)
+	return nil
+}
+
+func setupCostRouter(costStore *fakeCostStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewCostHandler(costs.NewReporter(costStore, "USD"), costStore, zap.NewNop())
+	handler.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+
+	router := gin.New()
+	router.GET("/v1/costs", handler.GetCosts)
+	router.PUT("/v1/costs/nodes", handler.PutNodeCosts)
+	return router
+}
+
+func TestGetCosts(t *testing.T) {
+	router := setupCostRouter(&fakeCostStore{})
+
+	w := get(router, "/v1/costs?group_by=tenant&tenant=acme")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report costs.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, "2026-10-01", report.From)
+	assert.Equal(t, "2026-10-31", report.To)
+	assert.Equal(t, 40.0, report.Total)
+	require.Len(t, report.Lines, 1)
+	assert.Equal(t, costs.Line{Tenant: "acme", Cost: 30, ComputeHours: 3000.0 / 3600000, Executions: 30, Requests: 4000, CostPer1K: 7.5}, report.Lines[0])
+}
+
+func TestGetCosts_CSV(t *testing.T) {
+	router := setupCostRouter(&fakeCostStore{})
+
+	w := get(router, "/v1/costs?month=2026-10&format=csv")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "costs-2026-10-01-2026-10-31.csv")
+	assert.Len(t, strings.Split(strings.TrimSpace(w.Body.String()), "\n"), 3)
+}
+
+func TestGetCosts_InvalidQuery(t *testing.T) {
+	router := setupCostRouter(&fakeCostStore{})
+
+	for _, query := range []string{"?group_by=version", "?format=xml", "?month=october"} {
+		w := get(router, "/v1/costs"+query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestPutNodeCosts(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"valid", `{"costs":[{"day":"2026-10-01","pool":"gpu","cost":40,"gpu_hours":24}]}`, http.StatusOK},
+		{"missing costs", `{}`, http.StatusBadRequest},
+		{"bad day", `{"costs":[{"day":"01/10/2026","pool":"gpu","cost":40}]}`, http.StatusBadRequest},
+		{"missing pool", `{"costs":[{"day":"2026-10-01","cost":40}]}`, http.StatusBadRequest},
+		{"negative cost", `{"costs":[{"day":"2026-10-01","pool":"gpu","cost":-1}]}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			costStore := &fakeCostStore{}
+			router := setupCostRouter(costStore)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("PUT", "/v1/costs/nodes", bytes.NewBufferString(tt.body)))
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, []store.NodeCost{{Day: "2026-10-01", Pool: "gpu", Cost: 40, GPUHours: 24}}, costStore.recorded)
+			} else {
+				assert.Empty(t, costStore.recorded)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/metering-service/internal/store"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// maxPeriod bounds the range a single query or export may cover
+const maxPeriod = 366 * 24 * time.Hour
+
+// UsageStore reads aggregated usage
+type UsageStore interface {
+	Daily(ctx context.Context, filter store.Filter) ([]store.DailyUsage, error)
+	Totals(ctx context.Context, filter store.Filter) ([]store.BillingLine, error)
+}
+
+// UsageHandler serves usage queries and billing exports
+type UsageHandler struct {
+	store  UsageStore
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(store UsageStore, logger *zap.Logger) *UsageHandler {
+	return &UsageHandler{
+		store:  store,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// GetUsage returns daily usage, optionally for one tenant or model
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	filter, err := parseFilter(c, h.now())
+	if err != nil {
+		c.JSON(apperrors.ToHTTP(err))
+		return
+	}
+
+	rows, err := h.store.Daily(c.Request.Context(), filter)
+	if err != nil {
+		logging.With(c.Request.Context(), h.logger).Error("failed to query usage", zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to query usage")))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":  filter.From.Format(store.DayFormat),
+		"to":    lastDay(filter),
+		"usage": rows,
+	})
+}
+
+// ExportBilling returns usage totals per tenant and model version for a billing
+// period, as JSON or, with format=csv, as a CSV download
+func (h *UsageHandler) ExportBilling(c *gin.Context) {
+	filter, err := parseFilter(c, h.now())
+	if err != nil {
+		c.JSON(apperrors.ToHTTP(err))
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "unsupported export format %q", format)))
+		return
+	}
+
+	lines, err := h.store.Totals(c.Request.Context(), filter)
+	if err != nil {
+		logging.With(c.Request.Context(), h.logger).Error("failed to export billing", zap.Error(err))
+		c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, "failed to export billing")))
+		return
+	}
+
+	from, to := filter.From.Format(store.DayFormat), lastDay(filter)
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{
+			"from":         from,
+			"to":           to,
+			"generated_at": h.now().UTC(),
+			"lines":        lines,
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="usage-%s-%s.csv"`, from, to))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"period_start", "period_end", "tenant", "model", "version", "requests", "errors", "latency_ms", "input_bytes", "output_bytes", "estimated_cost"})
+	for _, line := range lines {
+		w.Write([]string{
+			from, to, line.Tenant, line.Model, line.Version,
+			strconv.FormatInt(line.Requests, 10),
+			strconv.FormatInt(line.Errors, 10),
+			strconv.FormatInt(line.LatencyMs, 10),
+			strconv.FormatInt(line.InputBytes, 10),
+			strconv.FormatInt(line.OutputBytes, 10),
+			strconv.FormatFloat(line.EstimatedCost, 'f', -1, 64),
+		})
+	}
+	w.Flush()
+}
+
+// parseFilter reads tenant, model and the period. The period is either
+// month=YYYY-MM or from/to as inclusive YYYY-MM-DD days; it defaults to the
+// month of now.
+func parseFilter(c *gin.Context, now time.Time) (store.Filter, error) {
+	filter := store.Filter{
+		Tenant: c.Query("tenant"),
+		Model:  c.Query("model"),
+	}
+
+	if month := c.Query("month"); month != "" {
+		start, err := time.Parse("2006-01", month)
+		if err != nil {
+			return filter, apperrors.New(apperrors.InvalidArgument, "month must be YYYY-MM")
+		}
+		filter.From, filter.To = start, start.AddDate(0, 1, 0)
+		return filter, nil
+	}
+
+	now = now.UTC()
+	filter.From = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	filter.To = filter.From.AddDate(0, 1, 0)
+
+	if from := c.Query("from"); from != "" {
+		day, err := time.Parse(store.DayFormat, from)
+		if err != nil {
+			return filter, apperrors.New(apperrors.InvalidArgument, "from must be YYYY-MM-DD")
+		}
+		filter.From = day
+	}
+	if to := c.Query("to"); to != "" {
+		day, err := time.Parse(store.DayFormat, to)
+		if err != nil {
+			return filter, apperrors.New(apperrors.InvalidArgument, "to must be YYYY-MM-DD")
+		}
+		filter.To = day.AddDate(0, 0, 1)
+	}
+
+	if !filter.To.After(filter.From) {
+		return filter, apperrors.New(apperrors.InvalidArgument, "to must not be before from")
+	}
+	if filter.To.Sub(filter.From) > maxPeriod {
+		return filter, apperrors.New(apperrors.InvalidArgument, "period must not exceed one year")
+	}
+	return filter, nil
+}
+
+// lastDay returns the inclusive end of the filter's period
+func lastDay(filter store.Filter) string {
+	return filter.To.AddDate(0, 0, -1).Format(store.DayFormat)
+}
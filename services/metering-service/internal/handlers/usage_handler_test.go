@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ai-platform/metering-service/internal/store"
+	"go.uber.org/zap"
+)
+
+type fakeUsageStore struct {
+	filter store.Filter
+	lines  []store.BillingLine
+}
+
+func (s *fakeUsageStore) Daily(ctx context.Context, filter store.Filter) ([]store.DailyUsage, error) {
+	s.filter = filter
+	return []store.DailyUsage{}, nil
+}
+
+func (s *fakeUsageStore) Totals(ctx context.Context, filter store.Filter) ([]store.BillingLine, error) {
+	s.filter = filter
+	return s.lines, nil
+}
+
+func setupRouter(usageStore *fakeUsageStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewUsageHandler(usageStore, zap.NewNop())
+	handler.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+
+	router := gin.New()
+	router.GET("/v1/usage", handler.GetUsage)
+	router.GET("/v1/billing/export", handler.ExportBilling)
+	return router
+}
+
+func get(router *gin.Engine, url string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+	return w
+}
+
+func TestGetUsage_Period(t *testing.T) {
+	usageStore := &fakeUsageStore{}
+	router := setupRouter(usageStore)
+
+	tests := []struct {
+		name     string
+		query    string
+		from, to string
+	}{
+		{"default is current month", "", "2026-10-01", "2026-11-01"},
+		{"month", "?month=2026-02", "2026-02-01", "2026-03-01"},
+		{"inclusive range", "?from=2026-09-01&to=2026-09-15", "2026-09-01", "2026-09-16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(router, "/v1/usage"+tt.query)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.from, usageStore.filter.From.Format(store.DayFormat))
+			assert.Equal(t, tt.to, usageStore.filter.To.Format(store.DayFormat))
+		})
+	}
+}
+
+func TestGetUsage_InvalidPeriod(t *testing.T) {
+	router := setupRouter(&fakeUsageStore{})
+
+	for _, query := range []string{"?month=october", "?from=2026-09-10&to=2026-09-01", "?from=2024-01-01&to=2026-01-01"} {
+		w := get(router, "/v1/usage"+query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestExportBilling(t *testing.T) {
+	usageStore := &fakeUsageStore{lines: []store.BillingLine{
+		{Tenant: "acme", Model: "resnet18", Version: "v1", Quantities: store.Quantities{Requests: 120, Errors: 2, LatencyMs: 4800, EstimatedCost: 0.24}},
+	}}
+	router := setupRouter(usageStore)
+
+	w := get(router, "/v1/billing/export?month=2026-09&tenant=acme")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "acme", usageStore.filter.Tenant)
+
+	var body struct {
+		From  string              `json:"from"`
+		To    string              `json:"to"`
+		Lines []store.BillingLine `json:"lines"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "2026-09-01", body.From)
+	assert.Equal(t, "2026-09-30", body.To)
+	assert.Equal(t, usageStore.lines, body.Lines)
+
+	w = get(router, "/v1/billing/export?month=2026-09&format=csv")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "usage-2026-09-01-2026-09-30.csv")
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Equal(t, []string{
+		"period_start,period_end,tenant,model,version,requests,errors,latency_ms,input_bytes,output_bytes,estimated_cost",
+		"2026-09-01,2026-09-30,acme,resnet18,v1,120,2,4800,0,0,0.24",
+	}, lines)
+
+	w = get(router, "/v1/billing/export?format=xml")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
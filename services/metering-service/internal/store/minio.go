@@ -0,0 +1,108 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.uber.org/zap"
+)
+
+// MinIOStore stores exported reports in a MinIO bucket
+type MinIOStore struct {
+	client   atomic.Pointer[minio.Client]
+	endpoint string
+	bucket   string
+	logger   *zap.Logger
+}
+
+// NewMinIOStore creates a new MinIO store, creating the bucket if needed
+func NewMinIOStore(endpoint, accessKey, secretKey, bucket string, logger *zap.Logger) (*MinIOStore, error) {
+	client, err := newClient(endpoint, accessKey, secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &MinIOStore{
+		endpoint: endpoint,
+		bucket:   bucket,
+		logger:   logger,
+	}
+	store.client.Store(client)
+
+	if err := store.ensureBucket(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to ensure bucket: %w", err)
+	}
+
+	return store, nil
+}
+
+// newClient creates a MinIO client that signs requests with the given keys
+func newClient(endpoint, accessKey, secretKey string) (*minio.Client, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: false, // Set to true for HTTPS
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create minio client: %w", err)
+	}
+	return client, nil
+}
+
+// SetCredentials replaces the MinIO client with one using new keys, typically
+// after the MinIO credentials rotate
+func (s *MinIOStore) SetCredentials(accessKey, secretKey string) error {
+	client, err := newClient(s.endpoint, accessKey, secretKey)
+	if err != nil {
+		return err
+	}
+	s.client.Store(client)
+	return nil
+}
+
+// Ping verifies that MinIO is reachable and the export bucket exists
+func (s *MinIOStore) Ping(ctx context.Context) error {
+	exists, err := s.client.Load().BucketExists(ctx, s.bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", s.bucket)
+	}
+	return nil
+}
+
+func (s *MinIOStore) ensureBucket(ctx context.Context) error {
+	exists, err := s.client.Load().BucketExists(ctx, s.bucket)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if err := s.client.Load().MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{}); err != nil {
+			return err
+		}
+		s.logger.Info("created bucket", zap.String("bucket", s.bucket))
+	}
+
+	return nil
+}
+
+// PutObject stores data as name, replacing any earlier version
+func (s *MinIOStore) PutObject(ctx context.Context, name string, data []byte, contentType string) error {
+	_, err := s.client.Load().PutObject(
+		ctx,
+		s.bucket,
+		name,
+		bytes.NewReader(data),
+		int64(len(data)),
+		minio.PutObjectOptions{ContentType: contentType},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+	return nil
+}
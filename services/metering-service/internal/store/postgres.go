@@ -0,0 +1,517 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/yourusername/ai-platform/pkg/pricing"
+	"github.com/yourusername/ai-platform/pkg/usage"
+	"go.uber.org/zap"
+)
+
+// DayFormat is how usage days are written in the API and exports
+const DayFormat = "2006-01-02"
+
+// Quantities are the metered amounts billing is computed from
+type Quantities struct {
+	Requests    int64 `json:"requests"`
+	Errors      int64 `json:"errors"`
+	LatencyMs   int64 `json:"latency_ms"`
+	InputBytes  int64 `json:"input_bytes"`
+	OutputBytes int64 `json:"output_bytes"`
+	// EstimatedCost totals the costs the services estimated at their model
+	// pricing
+	EstimatedCost float64 `json:"estimated_cost"`
+}
+
+func (q *Quantities) add(event usage.Event) {
+	q.Requests += event.Requests
+	q.Errors += event.Errors
+	q.LatencyMs += event.LatencyMs
+	q.InputBytes += event.InputBytes
+	q.OutputBytes += event.OutputBytes
+	q.EstimatedCost = pricing.Round(q.EstimatedCost + event.EstimatedCost)
+}
+
+// DailyUsage is a tenant's usage of one model version on one UTC day
+type DailyUsage struct {
+	Tenant  string `json:"tenant"`
+	Model   string `json:"model"`
+	Version string `json:"version"`
+	Day     string `json:"day"`
+	Quantities
+}
+
+// BillingLine is a tenant's usage of one model version over a billing period
+type BillingLine struct {
+	Tenant  string `json:"tenant"`
+	Model   string `json:"model"`
+	Version string `json:"version"`
+	Quantities
+}
+
+// ComputeUsage is backend execution time spent on a tenant's model version
+// on one node pool on one UTC day
+type ComputeUsage struct {
+	Tenant     string `json:"tenant"`
+	Model      string `json:"model"`
+	Version    string `json:"version"`
+	Pool       string `json:"pool"`
+	Day        string `json:"day"`
+	Executions int64  `json:"executions"`
+	ComputeMs  int64  `json:"compute_ms"`
+}
+
+// NodeCost is what a GPU node pool cost on one UTC day
+type NodeCost struct {
+	Day  string  `json:"day"`
+	Pool string  `json:"pool"`
+	Cost float64 `json:"cost"`
+	// GPUHours is the GPU time the pool provided, zero when unknown
+	GPUHours float64 `json:"gpu_hours,omitempty"`
+}
+
+// Filter selects usage between From (inclusive) and To (exclusive).
+// Empty tenant or model match all.
+type Filter struct {
+	Tenant string
+	Model  string
+	From   time.Time
+	To     time.Time
+}
+
+// PostgresStore keeps daily usage aggregates and the IDs of applied events
+type PostgresStore struct {
+	db     atomic.Pointer[sql.DB]
+	logger *zap.Logger
+}
+
+// NewPostgresStore creates a new PostgreSQL store
+func NewPostgresStore(connectionURL string, logger *zap.Logger) (*PostgresStore, error) {
+	db, err := openDB(connectionURL)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &PostgresStore{
+		logger: logger,
+	}
+	store.db.Store(db)
+
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	return store, nil
+}
+
+// openDB opens a connection pool and checks that the database is reachable
+func openDB(connectionURL string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", connectionURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(5 * time.Minute)
+	return db, nil
+}
+
+// Reconnect replaces the connection pool with one opened with connectionURL,
+// typically after the database credentials rotate. Queries already running
+// on the old pool finish before it closes.
+func (s *PostgresStore) Reconnect(connectionURL string) error {
+	db, err := openDB(connectionURL)
+	if err != nil {
+		return err
+	}
+	if old := s.db.Swap(db); old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// initSchema creates the usage tables if they don't exist
+func (s *PostgresStore) initSchema() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS usage_daily (
+		tenant VARCHAR(255) NOT NULL,
+		model VARCHAR(255) NOT NULL,
+		version VARCHAR(50) NOT NULL,
+		day DATE NOT NULL,
+		requests BIGINT NOT NULL DEFAULT 0,
+		errors BIGINT NOT NULL DEFAULT 0,
+		latency_ms BIGINT NOT NULL DEFAULT 0,
+		input_bytes BIGINT NOT NULL DEFAULT 0,
+		output_bytes BIGINT NOT NULL DEFAULT 0,
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (tenant, model, version, day)
+	);
+
+	ALTER TABLE usage_daily ADD COLUMN IF NOT EXISTS estimated_cost DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+	CREATE INDEX IF NOT EXISTS idx_usage_daily_day ON usage_daily(day);
+
+	CREATE TABLE IF NOT EXISTS usage_events (
+		id VARCHAR(255) PRIMARY KEY,
+		received_at TIMESTAMP NOT NULL DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_usage_events_received_at ON usage_events(received_at);
+
+	CREATE TABLE IF NOT EXISTS compute_daily (
+		tenant VARCHAR(255) NOT NULL,
+		model VARCHAR(255) NOT NULL,
+		version VARCHAR(50) NOT NULL,
+		pool VARCHAR(255) NOT NULL,
+		day DATE NOT NULL,
+		executions BIGINT NOT NULL DEFAULT 0,
+		compute_ms BIGINT NOT NULL DEFAULT 0,
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (tenant, model, version, pool, day)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_compute_daily_day ON compute_daily(day);
+
+	CREATE TABLE IF NOT EXISTS node_costs (
+		day DATE NOT NULL,
+		pool VARCHAR(255) NOT NULL,
+		cost DOUBLE PRECISION NOT NULL,
+		gpu_hours DOUBLE PRECISION NOT NULL DEFAULT 0,
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (day, pool)
+	);
+	`
+
+	_, err := s.db.Load().Exec(query)
+	return err
+}
+
+// Aggregate sums billable events per tenant, model, version and UTC day.
+// Compute events are left to AggregateCompute.
+func Aggregate(events []usage.Event) []DailyUsage {
+	type key struct{ tenant, model, version, day string }
+	totals := make(map[key]*DailyUsage)
+
+	for _, event := range events {
+		if event.Kind == usage.KindCompute {
+			continue
+		}
+		k := key{event.Tenant, event.Model, event.Version, event.Timestamp.UTC().Format(DayFormat)}
+		row, ok := totals[k]
+		if !ok {
+			row = &DailyUsage{Tenant: k.tenant, Model: k.model, Version: k.version, Day: k.day}
+			totals[k] = row
+		}
+		row.add(event)
+	}
+
+	rows := make([]DailyUsage, 0, len(totals))
+	for _, row := range totals {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Version < b.Version
+	})
+	return rows
+}
+
+// AggregateCompute sums the execution time of compute events per tenant,
+// model, version, node pool and UTC day
+func AggregateCompute(events []usage.Event) []ComputeUsage {
+	type key struct{ tenant, model, version, pool, day string }
+	totals := make(map[key]*ComputeUsage)
+
+	for _, event := range events {
+		if event.Kind != usage.KindCompute {
+			continue
+		}
+		k := key{event.Tenant, event.Model, event.Version, event.Pool, event.Timestamp.UTC().Format(DayFormat)}
+		row, ok := totals[k]
+		if !ok {
+			row = &ComputeUsage{Tenant: k.tenant, Model: k.model, Version: k.version, Pool: k.pool, Day: k.day}
+			totals[k] = row
+		}
+		row.Executions += event.Requests
+		row.ComputeMs += event.ComputeMs
+	}
+
+	rows := make([]ComputeUsage, 0, len(totals))
+	for _, row := range totals {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Pool != b.Pool {
+			return a.Pool < b.Pool
+		}
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Version < b.Version
+	})
+	return rows
+}
+
+// Apply adds events to the daily aggregates in one transaction. Events already
+// applied are skipped, so redelivered Kafka messages are not billed twice.
+// It returns the number of events applied.
+func (s *PostgresStore) Apply(ctx context.Context, events []usage.Event) (int, error) {
+	tx, err := s.db.Load().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	fresh := make([]usage.Event, 0, len(events))
+	for _, event := range events {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO usage_events (id) VALUES ($1) ON CONFLICT (id) DO NOTHING`,
+			event.ID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to record usage event: %w", err)
+		}
+		if n, err := result.RowsAffected(); err == nil && n > 0 {
+			fresh = append(fresh, event)
+		}
+	}
+
+	for _, row := range Aggregate(fresh) {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO usage_daily (
+				tenant, model, version, day,
+				requests, errors, latency_ms, input_bytes, output_bytes, estimated_cost
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (tenant, model, version, day) DO UPDATE SET
+				requests = usage_daily.requests + EXCLUDED.requests,
+				errors = usage_daily.errors + EXCLUDED.errors,
+				latency_ms = usage_daily.latency_ms + EXCLUDED.latency_ms,
+				input_bytes = usage_daily.input_bytes + EXCLUDED.input_bytes,
+				output_bytes = usage_daily.output_bytes + EXCLUDED.output_bytes,
+				estimated_cost = usage_daily.estimated_cost + EXCLUDED.estimated_cost,
+				updated_at = NOW()
+		`, row.Tenant, row.Model, row.Version, row.Day,
+			row.Requests, row.Errors, row.LatencyMs, row.InputBytes, row.OutputBytes, row.EstimatedCost,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update daily usage: %w", err)
+		}
+	}
+
+	for _, row := range AggregateCompute(fresh) {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO compute_daily (
+				tenant, model, version, pool, day, executions, compute_ms
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (tenant, model, version, pool, day) DO UPDATE SET
+				executions = compute_daily.executions + EXCLUDED.executions,
+				compute_ms = compute_daily.compute_ms + EXCLUDED.compute_ms,
+				updated_at = NOW()
+		`, row.Tenant, row.Model, row.Version, row.Pool, row.Day, row.Executions, row.ComputeMs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update daily compute: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit usage: %w", err)
+	}
+	return len(fresh), nil
+}
+
+// PruneEvents forgets event IDs received before cutoff. Redeliveries older than
+// the cutoff would be billed again, so it should exceed the Kafka retention.
+func (s *PostgresStore) PruneEvents(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := s.db.Load().ExecContext(ctx, `DELETE FROM usage_events WHERE received_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune usage events: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// Daily returns the daily usage rows matching filter, oldest first
+func (s *PostgresStore) Daily(ctx context.Context, filter Filter) ([]DailyUsage, error) {
+	rows, err := s.db.Load().QueryContext(ctx, `
+		SELECT tenant, model, version, day,
+		       requests, errors, latency_ms, input_bytes, output_bytes, estimated_cost
+		FROM usage_daily
+		WHERE day >= $1 AND day < $2
+		  AND ($3 = '' OR tenant = $3)
+		  AND ($4 = '' OR model = $4)
+		ORDER BY day, tenant, model, version
+	`, filter.From, filter.To, filter.Tenant, filter.Model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily usage: %w", err)
+	}
+	defer rows.Close()
+
+	result := []DailyUsage{}
+	for rows.Next() {
+		var row DailyUsage
+		var day time.Time
+		if err := rows.Scan(
+			&row.Tenant, &row.Model, &row.Version, &day,
+			&row.Requests, &row.Errors, &row.LatencyMs, &row.InputBytes, &row.OutputBytes, &row.EstimatedCost,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan daily usage: %w", err)
+		}
+		row.Day = day.Format(DayFormat)
+		row.EstimatedCost = pricing.Round(row.EstimatedCost)
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// Totals returns usage matching filter summed per tenant and model version
+func (s *PostgresStore) Totals(ctx context.Context, filter Filter) ([]BillingLine, error) {
+	rows, err := s.db.Load().QueryContext(ctx, `
+		SELECT tenant, model, version,
+		       SUM(requests), SUM(errors), SUM(latency_ms), SUM(input_bytes), SUM(output_bytes),
+		       SUM(estimated_cost)
+		FROM usage_daily
+		WHERE day >= $1 AND day < $2
+		  AND ($3 = '' OR tenant = $3)
+		  AND ($4 = '' OR model = $4)
+		GROUP BY tenant, model, version
+		ORDER BY tenant, model, version
+	`, filter.From, filter.To, filter.Tenant, filter.Model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage totals: %w", err)
+	}
+	defer rows.Close()
+
+	result := []BillingLine{}
+	for rows.Next() {
+		var line BillingLine
+		if err := rows.Scan(
+			&line.Tenant, &line.Model, &line.Version,
+			&line.Requests, &line.Errors, &line.LatencyMs, &line.InputBytes, &line.OutputBytes, &line.EstimatedCost,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan usage totals: %w", err)
+		}
+		// Sums of doubles carry floating point noise
+		line.EstimatedCost = pricing.Round(line.EstimatedCost)
+		result = append(result, line)
+	}
+	return result, rows.Err()
+}
+
+// ComputeDaily returns the daily execution time rows matching filter, oldest first
+func (s *PostgresStore) ComputeDaily(ctx context.Context, filter Filter) ([]ComputeUsage, error) {
+	rows, err := s.db.Load().QueryContext(ctx, `
+		SELECT tenant, model, version, pool, day, executions, compute_ms
+		FROM compute_daily
+		WHERE day >= $1 AND day < $2
+		  AND ($3 = '' OR tenant = $3)
+		  AND ($4 = '' OR model = $4)
+		ORDER BY day, pool, tenant, model, version
+	`, filter.From, filter.To, filter.Tenant, filter.Model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily compute: %w", err)
+	}
+	defer rows.Close()
+
+	result := []ComputeUsage{}
+	for rows.Next() {
+		var row ComputeUsage
+		var day time.Time
+		if err := rows.Scan(&row.Tenant, &row.Model, &row.Version, &row.Pool, &day, &row.Executions, &row.ComputeMs); err != nil {
+			return nil, fmt.Errorf("failed to scan daily compute: %w", err)
+		}
+		row.Day = day.Format(DayFormat)
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// PutNodeCosts records node pool costs, replacing those already recorded for
+// the same day and pool
+func (s *PostgresStore) PutNodeCosts(ctx context.Context, costs []NodeCost) error {
+	tx, err := s.db.Load().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, cost := range costs {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO node_costs (day, pool, cost, gpu_hours)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (day, pool) DO UPDATE SET
+				cost = EXCLUDED.cost,
+				gpu_hours = EXCLUDED.gpu_hours,
+				updated_at = NOW()
+		`, cost.Day, cost.Pool, cost.Cost, cost.GPUHours)
+		if err != nil {
+			return fmt.Errorf("failed to record node cost: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit node costs: %w", err)
+	}
+	return nil
+}
+
+// NodeCosts returns the node pool costs in filter's period, oldest first
+func (s *PostgresStore) NodeCosts(ctx context.Context, filter Filter) ([]NodeCost, error) {
+	rows, err := s.db.Load().QueryContext(ctx, `
+		SELECT day, pool, cost, gpu_hours
+		FROM node_costs
+		WHERE day >= $1 AND day < $2
+		ORDER BY day, pool
+	`, filter.From, filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query node costs: %w", err)
+	}
+	defer rows.Close()
+
+	result := []NodeCost{}
+	for rows.Next() {
+		var cost NodeCost
+		var day time.Time
+		if err := rows.Scan(&day, &cost.Pool, &cost.Cost, &cost.GPUHours); err != nil {
+			return nil, fmt.Errorf("failed to scan node cost: %w", err)
+		}
+		cost.Day = day.Format(DayFormat)
+		result = append(result, cost)
+	}
+	return result, rows.Err()
+}
+
+// Ping checks the database connection for readiness probes
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	return s.db.Load().PingContext(ctx)
+}
+
+// Close closes the database connection
+func (s *PostgresStore) Close() error {
+	return s.db.Load().Close()
+}
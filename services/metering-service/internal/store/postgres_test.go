@@ -0,0 +1,55 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/ai-platform/pkg/usage"
+)
+
+func TestAggregate(t *testing.T) {
+	day1 := time.Date(2026, 9, 30, 23, 59, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Minute)
+
+	rows := Aggregate([]usage.Event{
+		{Tenant: "acme", Model: "resnet18", Version: "v1", Requests: 1, LatencyMs: 20, EstimatedCost: 0.1, Timestamp: day1},
+		{Tenant: "acme", Model: "resnet18", Version: "v1", Requests: 1, Errors: 1, LatencyMs: 30, EstimatedCost: 0.2, Timestamp: day1},
+		{Tenant: "acme", Model: "resnet18", Version: "v1", Requests: 10, Timestamp: day2},
+		{Tenant: "globex", Model: "resnet18", Version: "v1", Requests: 1, Timestamp: day1},
+	})
+
+	assert.Equal(t, []DailyUsage{
+		{Tenant: "acme", Model: "resnet18", Version: "v1", Day: "2026-09-30", Quantities: Quantities{Requests: 2, Errors: 1, LatencyMs: 50, EstimatedCost: 0.3}},
+		{Tenant: "globex", Model: "resnet18", Version: "v1", Day: "2026-09-30", Quantities: Quantities{Requests: 1}},
+		{Tenant: "acme", Model: "resnet18", Version: "v1", Day: "2026-10-01", Quantities: Quantities{Requests: 10}},
+	}, rows)
+}
+
+func TestAggregate_UsesUTCDay(t *testing.T) {
+	local := time.FixedZone("UTC+2", 2*60*60)
+	rows := Aggregate([]usage.Event{
+		{Tenant: "acme", Model: "resnet18", Version: "v1", Requests: 1, Timestamp: time.Date(2026, 10, 1, 1, 0, 0, 0, local)},
+	})
+
+	assert.Equal(t, "2026-09-30", rows[0].Day)
+}
+
+func TestAggregateCompute(t *testing.T) {
+	day := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	events := []usage.Event{
+		{Kind: usage.KindCompute, Tenant: "acme", Model: "llama", Version: "1", Pool: "gpu-a100", Requests: 1, ComputeMs: 120, Timestamp: day},
+		{Kind: usage.KindCompute, Tenant: "acme", Model: "llama", Version: "1", Pool: "gpu-a100", Requests: 1, Errors: 1, ComputeMs: 30, Timestamp: day},
+		{Kind: usage.KindCompute, Tenant: "acme", Model: "llama", Version: "1", Pool: "gpu-t4", Requests: 1, ComputeMs: 400, Timestamp: day},
+		{Kind: usage.KindRealtime, Tenant: "acme", Model: "llama", Version: "v1", Requests: 1, LatencyMs: 200, Timestamp: day},
+	}
+
+	assert.Equal(t, []ComputeUsage{
+		{Tenant: "acme", Model: "llama", Version: "1", Pool: "gpu-a100", Day: "2026-10-14", Executions: 2, ComputeMs: 150},
+		{Tenant: "acme", Model: "llama", Version: "1", Pool: "gpu-t4", Day: "2026-10-14", Executions: 1, ComputeMs: 400},
+	}, AggregateCompute(events))
+
+	rows := Aggregate(events)
+	assert.Len(t, rows, 1, "compute events are not billed as requests")
+	assert.Equal(t, "v1", rows[0].Version)
+}
@@ -6,15 +6,36 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/IBM/sarama"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 
+	"github.com/yourusername/ai-platform/model-router/internal/audit"
 	"github.com/yourusername/ai-platform/model-router/internal/config"
+	"github.com/yourusername/ai-platform/model-router/internal/experiments"
 	"github.com/yourusername/ai-platform/model-router/internal/handlers"
+	"github.com/yourusername/ai-platform/model-router/internal/middleware"
+	"github.com/yourusername/ai-platform/model-router/internal/observability"
+	"github.com/yourusername/ai-platform/model-router/internal/registry"
 	"github.com/yourusername/ai-platform/model-router/internal/router"
+	"github.com/yourusername/ai-platform/model-router/internal/rules"
+	"github.com/yourusername/ai-platform/model-router/internal/shadow"
+	"github.com/yourusername/ai-platform/model-router/internal/state"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/faults"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/secrets"
+	"github.com/yourusername/ai-platform/pkg/transport"
 )
 
 func main() {
@@ -29,12 +50,229 @@ func main() {
 	cfg := config.Load()
 	logger.Info("configuration loaded", zap.String("port", cfg.Port))
 
+	// Initialize tracing; spans continue the caller's trace
+	shutdown, err := observability.InitTracing(cfg.ServiceName, cfg.JaegerEndpoint)
+	if err != nil {
+		logger.Fatal("failed to initialize tracing", zap.Error(err))
+	}
+	defer shutdown(context.Background())
+
 	// Initialize model router
 	modelRouter := router.NewModelRouter(logger, cfg.OrchestratorURL)
+	defaultStrategy, err := router.ParseStrategy(cfg.RoutingStrategy)
+	if err != nil {
+		logger.Fatal("invalid ROUTING_STRATEGY", zap.Error(err))
+	}
+	modelStrategies := make(map[string]router.Strategy, len(cfg.ModelRoutingStrategies))
+	for model, name := range cfg.ModelRoutingStrategies {
+		if modelStrategies[model], err = router.ParseStrategy(name); err != nil {
+			logger.Fatal("invalid MODEL_ROUTING_STRATEGIES", zap.String("model", model), zap.Error(err))
+		}
+	}
+	modelRouter.SetStrategies(defaultStrategy, modelStrategies)
+	modelRouter.SetFailoverAttempts(cfg.FailoverAttempts)
+	modelLimits := make(map[string]int, len(cfg.ModelBackendConcurrency))
+	for model, value := range cfg.ModelBackendConcurrency {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			logger.Fatal("invalid MODEL_BACKEND_CONCURRENCY", zap.String("model", model), zap.String("limit", value))
+		}
+		modelLimits[model] = limit
+	}
+	modelRouter.SetConcurrencyLimits(cfg.BackendMaxConcurrency, modelLimits)
+	modelRouter.SetQueue(cfg.BackendQueueDepth, cfg.BackendQueueTimeout)
+	backendRates := make(map[string]float64, len(cfg.BackendRateLimits))
+	for url, value := range cfg.BackendRateLimits {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 {
+			logger.Fatal("invalid BACKEND_RATE_LIMITS", zap.String("url", url), zap.String("rate", value))
+		}
+		backendRates[url] = rate
+	}
+	modelRouter.SetRateLimits(cfg.BackendMaxRPS, backendRates)
+	modelRouter.SetWarmup(cfg.WarmupRequests, cfg.WarmupMaxLatency)
+	for url, class := range cfg.BackendClasses {
+		if class != router.ClassGPU && class != router.ClassCPU {
+			logger.Fatal("invalid BACKEND_CLASSES", zap.String("url", url), zap.String("class", class))
+		}
+	}
+	modelRouter.SetBackendClasses(cfg.BackendClasses)
+	modelRouter.SetRelaxedDeadline(cfg.RelaxedDeadline)
+
+	// Resolve the backend token from Vault or a mounted secret store when configured
+	secretProvider, err := secrets.FromEnv(logger)
+	if err != nil {
+		logger.Fatal("failed to initialize secret provider", zap.Error(err))
+	}
+	secretManager := secrets.NewManager(secretProvider, logger)
+	defer secretManager.Close()
 
-	// Register models (in production, this would come from metadata service)
-	modelRouter.RegisterBackend("resnet18", "v1", cfg.OrchestratorURL)
-	modelRouter.RegisterBackend("resnet18", "v2", cfg.OrchestratorURL)
+	backendTokenRef := cfg.SecretsPath + "#backend_token"
+	backendToken := secrets.NewValue(secretManager.LookupOr(context.Background(), backendTokenRef, cfg.BackendToken))
+	secretManager.Watch(context.Background(), backendTokenRef, 5*time.Minute, backendToken.Store)
+	modelRouter.SetAuthToken(backendToken.Load)
+
+	// Load the SPIFFE workload identity for mTLS between services
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	orchestratorClient := &http.Client{Timeout: health.DefaultTimeout}
+	metadataClient := &http.Client{Timeout: 10 * time.Second}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+		modelRouter.SetHTTPClient(identity.HTTPClient("inference-orchestrator", 30*time.Second))
+		orchestratorClient = identity.HTTPClient("inference-orchestrator", health.DefaultTimeout)
+		metadataClient = identity.HTTPClient("metadata-service", 10*time.Second)
+	}
+
+	// Route the active models registered with the metadata service, reloaded
+	// every sync interval
+	modelSyncer := registry.NewSyncer(registry.MetadataSource(metadataClient, cfg.MetadataURL), modelRouter, cfg.ModelSyncInterval, logger)
+	syncCtx, stopSync := context.WithCancel(context.Background())
+	defer stopSync()
+	go modelSyncer.Run(syncCtx)
+	// Backends registered by heartbeat are dropped once their lease lapses
+	go modelRouter.ExpireLeases(syncCtx, cfg.BackendLeaseTTL)
+	// Backends failing their readiness probes get no requests until they pass
+	if cfg.HealthCheckInterval > 0 {
+		go modelRouter.ProbeBackends(syncCtx, cfg.HealthCheckInterval)
+	}
+	// Outliers are compared with their peers, and re-admitted after probation
+	if cfg.OutlierInterval > 0 {
+		modelRouter.SetOutlierDetection(cfg.OutlierFailures, cfg.OutlierLatencyFactor, cfg.OutlierEjectionTime)
+		go modelRouter.DetectOutliers(syncCtx, cfg.OutlierInterval)
+	}
+	// Replicas share what they learn about backends so that they route alike;
+	// states of replicas missing a few syncs are dropped
+	if cfg.RedisHost != "" {
+		redisClient := config.NewRedisClient(cfg.RedisHost)
+		defer redisClient.Close()
+		modelRouter.SetStateStore(state.NewStore(redisClient, 3*cfg.StateSyncInterval), cfg.ReplicaID)
+		go modelRouter.ShareState(syncCtx, cfg.StateSyncInterval)
+		logger.Info("sharing backend state with other replicas", zap.String("replica", cfg.ReplicaID))
+	}
+
+	// Readiness requires the orchestrator to be reachable and the models to
+	// have been loaded once; later metadata outages keep the last table
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("inference-orchestrator", health.HTTPCheck(orchestratorClient, cfg.OrchestratorURL+health.LivenessPath))
+	checker.Add("model-registry", modelSyncer.Ready)
+
+	// Mirror a share of requests to shadow candidates, whose answers are
+	// recorded in the inference log next to the production answer
+	shadowRules, err := shadow.ParseRules(cfg.ShadowTraffic)
+	if err != nil {
+		logger.Fatal("invalid SHADOW_TRAFFIC", zap.Error(err))
+	}
+	if len(shadowRules) > 0 && len(cfg.KafkaBrokers) == 0 {
+		logger.Fatal("SHADOW_TRAFFIC requires KAFKA_BROKERS to record shadow inferences")
+	}
+	inferenceLogCfg, err := inferencelog.ConfigFromEnv()
+	if err != nil {
+		logger.Fatal("invalid inference log configuration", zap.Error(err))
+	}
+	// The router records nothing but shadow inferences, which are never sampled
+	inferenceLogCfg.Enabled = len(shadowRules) > 0
+
+	// Record how each request is routed, to Kafka or the service log
+	var auditLog *audit.Log
+	switch cfg.RoutingAuditSink {
+	case "":
+	case audit.SinkLog:
+		auditLog = audit.NewLog(cfg.ServiceName, audit.LogPublisher(logger), 1000, logger)
+	case audit.SinkKafka:
+		if len(cfg.KafkaBrokers) == 0 {
+			logger.Fatal("ROUTING_AUDIT_SINK=kafka requires KAFKA_BROKERS")
+		}
+	default:
+		logger.Fatal("invalid ROUTING_AUDIT_SINK", zap.String("sink", cfg.RoutingAuditSink))
+	}
+
+	// Announce tripped circuit breakers to the notification service when Kafka is configured
+	var eventEmitter *events.Emitter
+	var capture *inferencelog.Capture
+	if len(cfg.KafkaBrokers) > 0 {
+		kafkaProducer, err := events.NewKafkaProducer(cfg.KafkaBrokers)
+		if err != nil {
+			logger.Fatal("failed to initialize kafka producer", zap.Error(err))
+		}
+		defer kafkaProducer.Close()
+
+		schemaCodec := schema.FromEnv()
+		if err := schemaCodec.Register(context.Background(), schema.PlatformEvents, schema.InferenceLogs, schema.RoutingDecisions); apperrors.Is(err, apperrors.FailedPrecondition) {
+			logger.Fatal("message schema is incompatible with the registry", zap.Error(err))
+		} else if err != nil {
+			logger.Warn("failed to register message schemas", zap.Error(err))
+		}
+
+		eventEmitter = events.NewEmitter(cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+			value, err := schemaCodec.Frame(ctx, schema.PlatformEvents, value)
+			if err != nil {
+				return err
+			}
+			_, _, err = kafkaProducer.SendMessage(&sarama.ProducerMessage{
+				Topic: cfg.EventTopic,
+				Key:   sarama.StringEncoder(key),
+				Value: sarama.ByteEncoder(value),
+			})
+			return err
+		}), 1000, logger)
+		modelRouter.SetEventEmitter(eventEmitter)
+
+		capture = inferencelog.NewCapture(inferenceLogCfg, cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+			value, err := schemaCodec.Frame(ctx, schema.InferenceLogs, value)
+			if err != nil {
+				return err
+			}
+			_, _, err = kafkaProducer.SendMessage(&sarama.ProducerMessage{
+				Topic: inferenceLogCfg.Topic,
+				Key:   sarama.StringEncoder(key),
+				Value: sarama.ByteEncoder(value),
+			})
+			return err
+		}), 1000, logger)
+
+		if cfg.RoutingAuditSink == audit.SinkKafka {
+			auditLog = audit.NewLog(cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+				value, err := schemaCodec.Frame(ctx, schema.RoutingDecisions, value)
+				if err != nil {
+					return err
+				}
+				_, _, err = kafkaProducer.SendMessage(&sarama.ProducerMessage{
+					Topic: cfg.RoutingAuditTopic,
+					Key:   sarama.StringEncoder(key),
+					Value: sarama.ByteEncoder(value),
+				})
+				return err
+			}), 1000, logger)
+		}
+	}
+	modelRouter.SetAuditLog(auditLog)
+	eventCtx, stopEvents := context.WithCancel(context.Background())
+	eventsDone := make(chan struct{})
+	go func() {
+		if eventEmitter != nil {
+			eventEmitter.Run(eventCtx)
+		}
+		close(eventsDone)
+	}()
+	captureDone := make(chan struct{})
+	go func() {
+		capture.Run(eventCtx)
+		close(captureDone)
+	}()
+	auditDone := make(chan struct{})
+	go func() {
+		auditLog.Run(eventCtx)
+		close(auditDone)
+	}()
+
+	// Inject faults for resilience testing; a no-op unless rules are configured
+	faultInjector, err := faults.FromEnv(context.Background(), cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load fault injection rules", zap.Error(err))
+	}
 
 	// Setup HTTP router
 	if cfg.LogLevel == "production" {
@@ -42,29 +280,97 @@ func main() {
 	}
 	r := gin.New()
 	r.Use(gin.Recovery())
+	r.Use(middleware.Tracing())
 
-	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
-	})
+	// Health checks
+	r.GET("/health", handlers.Health(checker, modelRouter))
+	r.GET(health.LivenessPath, gin.WrapH(checker.LivenessHandler()))
+	r.GET(health.ReadinessPath, gin.WrapH(checker.ReadinessHandler()))
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	// Routing endpoints
 	routeHandler := handlers.NewRouteHandler(logger, modelRouter)
+	routeHandler.SetLeaseTTL(cfg.BackendLeaseTTL)
+	routeHandler.SetBatchLimits(cfg.RouteBatchMaxSize, cfg.RouteBatchConcurrency)
+	experimentSet, err := experiments.Parse(cfg.Experiments)
+	if err != nil {
+		logger.Fatal("invalid EXPERIMENTS", zap.Error(err))
+	}
+	routeHandler.SetExperiments(experimentSet)
+	routingRules, err := rules.Parse(cfg.RoutingRules)
+	if err != nil {
+		logger.Fatal("invalid ROUTING_RULES", zap.Error(err))
+	}
+	routeHandler.SetRules(routingRules)
+	modelRouter.SetPools(routingRules.Pools())
+	routeHandler.SetShadow(shadow.NewMirror(shadowRules, modelRouter, capture, cfg.ShadowConcurrency, cfg.ShadowTimeout, logger))
+	for _, rule := range shadowRules {
+		logger.Info("shadowing model version",
+			zap.String("model", rule.Model),
+			zap.String("version", rule.Version),
+			zap.String("candidate", rule.Candidate),
+			zap.Float64("percent", rule.Percent),
+		)
+	}
 	v1 := r.Group("/v1")
 	{
 		v1.POST("/route", routeHandler.RouteInference)
+		v1.POST("/route/stream", routeHandler.RouteStream)
+		v1.POST("/route/batch", routeHandler.RouteBatch)
+		v1.GET("/backends", routeHandler.ListBackends)
+		v1.PUT("/backends", routeHandler.Heartbeat)
+		v1.DELETE("/backends", routeHandler.Unregister)
+		v1.GET("/traffic-splits", routeHandler.ListSplits)
+		v1.GET("/aliases", routeHandler.ListAliases)
+		v1.GET("/fallbacks", routeHandler.ListFallbacks)
+		v1.GET("/experiments", routeHandler.ListExperiments)
+		v1.GET("/routing-rules", routeHandler.ListRules)
+		v1.GET("/load", routeHandler.Load)
+	}
+
+	// Runtime changes to the routing table by operators. They reach the
+	// router through the gateway's admin API, which authenticates them, so
+	// the routes are only served to the gateway over mTLS and not at all
+	// without a workload identity.
+	if identity != nil {
+		gateway := transport.AuthorizeIDs(transport.ServiceID(identity.TrustDomain(), "api-gateway"))
+		adminHandler := handlers.NewAdminHandler(logger, modelRouter)
+		admin := r.Group("/admin")
+		admin.Use(func(c *gin.Context) {
+			authorized := false
+			transport.RequirePeerPolicy(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				authorized = true
+			}), gateway).ServeHTTP(c.Writer, c.Request)
+			if !authorized {
+				c.Abort()
+			}
+		})
+		{
+			admin.GET("/backends", adminHandler.ListBackends)
+			admin.POST("/backends", adminHandler.AddBackend)
+			admin.DELETE("/backends", adminHandler.RemoveBackend)
+			admin.PUT("/backends/drain", adminHandler.Drain)
+			admin.GET("/backends/drain", adminHandler.DrainStatus)
+			admin.DELETE("/backends/drain", adminHandler.Undrain)
+		}
+	} else {
+		logger.Warn("router admin API is disabled; it is only served to the gateway over mTLS")
+	}
+
+	if faultInjector.Enabled() {
+		r.Any(faults.AdminPath, gin.WrapH(faultInjector.AdminHandler()))
 	}
 
 	// Create HTTP server
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
-		Handler: r,
+		Handler: logging.Middleware(faultInjector.Middleware(r)),
 	}
 
 	// Start server
 	go func() {
 		logger.Info("starting model router", zap.String("port", cfg.Port))
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := transport.ListenAndServe(srv, identity); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("failed to start server", zap.Error(err))
 		}
 	}()
@@ -81,6 +387,10 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Fatal("server forced to shutdown", zap.Error(err))
 	}
+	stopEvents()
+	<-eventsDone
+	<-captureDone
+	<-auditDone
 
 	logger.Info("server exited")
 }
@@ -0,0 +1,120 @@
+// Package audit records how the router chose the backend of each request:
+// the backends it could pick from, those it tried, the circuit breaker state
+// of the one that answered and how long it took. Records go to Kafka or the
+// service log, so "why did this request go to that replica" can be answered
+// after the fact.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+)
+
+// DefaultTopic is the Kafka topic routing decisions are published to
+const DefaultTopic = "routing-decisions"
+
+// Sinks routing decisions may be recorded to
+const (
+	SinkKafka = "kafka"
+	SinkLog   = "log"
+)
+
+// Decision describes how one request was routed
+type Decision struct {
+	ID        string    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Service   string    `json:"service"`
+	RequestID string    `json:"request_id,omitempty"`
+	TraceID   string    `json:"trace_id,omitempty"`
+	Tenant    string    `json:"tenant,omitempty"`
+	Model     string    `json:"model"`
+	Version   string    `json:"version"`
+	// Strategy picked among the Candidates backends of the request's Pool
+	Strategy   string `json:"strategy,omitempty"`
+	Pool       string `json:"pool,omitempty"`
+	Candidates int    `json:"candidates"`
+	// Backend is the last backend tried, which answered unless the request
+	// failed; Tried lists every backend tried, in order
+	Backend      string   `json:"backend,omitempty"`
+	Tried        []string `json:"tried,omitempty"`
+	Attempts     int      `json:"attempts"`
+	CircuitState string   `json:"circuit_state,omitempty"`
+	Class        string   `json:"class,omitempty"`
+	LatencyMs    int64    `json:"latency_ms"`
+	// Status is "ok", or the error code the request failed with
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// LogPublisher writes decisions to the service log rather than Kafka
+func LogPublisher(logger *zap.Logger) publish.Publisher {
+	return publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		logger.Info("routing decision", zap.Reflect("decision", json.RawMessage(value)))
+		return nil
+	})
+}
+
+// Log publishes routing decisions in the background, so recording never
+// adds latency to or fails a request. Decisions are dropped when the buffer
+// is full. A nil Log records nothing.
+type Log struct {
+	service string
+	queue   *publish.Queue[Decision]
+}
+
+// NewLog creates a log for service that buffers up to size decisions,
+// published keyed by model so a model's decisions stay ordered
+func NewLog(service string, publisher publish.Publisher, size int, logger *zap.Logger) *Log {
+	return &Log{
+		service: service,
+		queue: publish.NewQueue("routing decision", publisher, func(decision Decision) string {
+			return decision.Model
+		}, size, logger),
+	}
+}
+
+// Record queues a decision. ID, service, request ID, trace ID and tenant are
+// filled in from the log and the request context.
+func (l *Log) Record(ctx context.Context, decision Decision) {
+	if l == nil {
+		return
+	}
+
+	fields := logging.FieldsFromContext(ctx)
+	decision.ID = publish.NewID()
+	decision.Service = l.service
+	if decision.RequestID == "" {
+		decision.RequestID = fields.RequestID
+	}
+	if decision.TraceID == "" {
+		decision.TraceID = fields.TraceID
+	}
+	if decision.Tenant == "" {
+		decision.Tenant = fields.Tenant
+	}
+	if decision.Timestamp.IsZero() {
+		decision.Timestamp = time.Now().UTC()
+	}
+
+	l.queue.Enqueue(decision)
+}
+
+// Dropped returns how many decisions were discarded because the buffer was full
+func (l *Log) Dropped() int64 {
+	return l.queue.Dropped()
+}
+
+// Run publishes queued decisions until ctx is cancelled, then flushes what
+// is left
+func (l *Log) Run(ctx context.Context) {
+	if l == nil {
+		return
+	}
+	l.queue.Run(ctx)
+}
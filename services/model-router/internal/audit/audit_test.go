@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+type recordingPublisher struct {
+	keys   []string
+	values [][]byte
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, key string, value []byte) error {
+	p.keys = append(p.keys, key)
+	p.values = append(p.values, value)
+	return nil
+}
+
+// drain publishes the queued decisions
+func drain(l *Log) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.Run(ctx)
+}
+
+func TestLog_RecordsDecisions(t *testing.T) {
+	publisher := &recordingPublisher{}
+	log := NewLog("model-router", publisher, 10, zap.NewNop())
+
+	ctx := logging.NewContext(context.Background(), logging.Fields{RequestID: "req-1", TraceID: "trace-1", Tenant: "acme"})
+	log.Record(ctx, Decision{
+		Model:        "llama",
+		Version:      "v1",
+		Strategy:     "latency",
+		Candidates:   3,
+		Backend:      "http://gpu-1:8082",
+		Tried:        []string{"http://gpu-2:8082", "http://gpu-1:8082"},
+		Attempts:     2,
+		CircuitState: "closed",
+		LatencyMs:    40,
+		Status:       "ok",
+	})
+	drain(log)
+
+	require.Len(t, publisher.values, 1)
+	assert.Equal(t, "llama", publisher.keys[0])
+	assert.NoError(t, schema.RoutingDecisions.Validate(publisher.values[0]))
+
+	var decision Decision
+	require.NoError(t, json.Unmarshal(publisher.values[0], &decision))
+	assert.NotEmpty(t, decision.ID)
+	assert.False(t, decision.Timestamp.IsZero())
+	assert.Equal(t, "model-router", decision.Service)
+	assert.Equal(t, "req-1", decision.RequestID)
+	assert.Equal(t, "trace-1", decision.TraceID)
+	assert.Equal(t, "acme", decision.Tenant)
+	assert.Equal(t, []string{"http://gpu-2:8082", "http://gpu-1:8082"}, decision.Tried)
+}
+
+func TestLog_DropsWhenFull(t *testing.T) {
+	log := NewLog("model-router", &recordingPublisher{}, 1, zap.NewNop())
+	log.Record(context.Background(), Decision{Model: "llama"})
+	log.Record(context.Background(), Decision{Model: "llama"})
+	assert.Equal(t, int64(1), log.Dropped())
+
+	var nilLog *Log
+	nilLog.Record(context.Background(), Decision{Model: "llama"})
+	nilLog.Run(context.Background())
+}
+
+func TestLogPublisher(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	log := NewLog("model-router", LogPublisher(zap.New(core)), 10, zap.NewNop())
+	log.Record(context.Background(), Decision{Model: "llama", Version: "v1", Status: "ok"})
+	drain(log)
+
+	entries := logs.FilterMessage("routing decision").All()
+	require.Len(t, entries, 1)
+	encoded, err := json.Marshal(entries[0].ContextMap()["decision"])
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"model":"llama"`)
+}
@@ -1,6 +1,13 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
 
 type Config struct {
 	ServiceName     string
@@ -9,6 +16,102 @@ type Config struct {
 	OrchestratorURL string
 	MetadataURL     string
 	JaegerEndpoint  string
+	BackendToken    string
+	SecretsPath     string
+
+	// ModelSyncInterval is how often the routing table is reloaded from the
+	// active models in the metadata service
+	ModelSyncInterval time.Duration
+
+	// BackendLeaseTTL is how long a backend registered by heartbeat is
+	// routed to without renewing its lease
+	BackendLeaseTTL time.Duration
+
+	// HealthCheckInterval is how often backends' readiness is probed; zero
+	// disables probing
+	HealthCheckInterval time.Duration
+
+	// Backends failing OutlierFailures requests in a row, or whose latency
+	// average exceeds OutlierLatencyFactor times their version's median, are
+	// ejected for OutlierEjectionTime; zero disables either check. Latency
+	// is compared, and outliers re-admitted, every OutlierInterval; zero
+	// disables outlier detection.
+	OutlierFailures      int
+	OutlierLatencyFactor float64
+	OutlierEjectionTime  time.Duration
+	OutlierInterval      time.Duration
+
+	// WarmupRequests sample requests warm up new backends of model versions
+	// with a sample input before they get traffic, the last answered within
+	// WarmupMaxLatency; zero disables warm-up
+	WarmupRequests   int
+	WarmupMaxLatency time.Duration
+
+	// RoutingStrategy picks backends for models without one of their own in
+	// ModelRoutingStrategies
+	RoutingStrategy        string
+	ModelRoutingStrategies map[string]string
+
+	// FailoverAttempts is how many backends a request a backend rejects is
+	// tried on; 1 disables failover
+	FailoverAttempts int
+
+	// BackendMaxConcurrency caps the requests in flight per backend of
+	// models without a cap of their own in ModelBackendConcurrency; zero
+	// means no cap. While every backend of a version is at its cap, up to
+	// BackendQueueDepth requests wait up to BackendQueueTimeout for a slot.
+	BackendMaxConcurrency   int
+	ModelBackendConcurrency map[string]string
+	BackendQueueDepth       int
+	BackendQueueTimeout     time.Duration
+
+	// BackendMaxRPS caps the requests per second sent to each backend URL
+	// without a ceiling of its own in BackendRateLimits; zero means no cap
+	BackendMaxRPS     float64
+	BackendRateLimits map[string]string
+
+	// BackendClasses tags backends with their cost class, gpu or cpu, by
+	// URL. Requests from batch jobs or whose deadline leaves at least
+	// RelaxedDeadline are sent to CPU backends, others kept on GPUs.
+	BackendClasses  map[string]string
+	RelaxedDeadline time.Duration
+
+	// RoutingRules is a JSON object of the rules routing requests by tenant,
+	// headers and input to model versions and pools of dedicated backends
+	RoutingRules string
+
+	// Experiments is a JSON array of the A/B experiments users are
+	// assigned to
+	Experiments string
+
+	// ShadowTraffic is a JSON array of the model versions whose requests are
+	// mirrored to candidate versions, and the percentage mirrored. At most
+	// ShadowConcurrency mirrored requests are in flight, each given
+	// ShadowTimeout.
+	ShadowTraffic     string
+	ShadowConcurrency int
+	ShadowTimeout     time.Duration
+
+	// RouteBatchMaxSize caps the inputs of a /v1/route/batch request, of
+	// which at most RouteBatchConcurrency are routed at once
+	RouteBatchMaxSize     int
+	RouteBatchConcurrency int
+
+	// RedisHost holds the backends' state shared by router replicas, synced
+	// every StateSyncInterval and published as ReplicaID; replicas do not
+	// share state when it is empty
+	RedisHost         string
+	StateSyncInterval time.Duration
+	ReplicaID         string
+
+	// RoutingAuditSink records how each request was routed: to Kafka's
+	// RoutingAuditTopic, to the service log, or nowhere when empty
+	RoutingAuditSink  string
+	RoutingAuditTopic string
+
+	// Platform events are only published when brokers are configured
+	KafkaBrokers []string
+	EventTopic   string
 }
 
 func Load() *Config {
@@ -19,12 +122,123 @@ func Load() *Config {
 		OrchestratorURL: getEnv("ORCHESTRATOR_SERVICE_URL", "http://localhost:8082"),
 		MetadataURL:     getEnv("METADATA_SERVICE_URL", "http://localhost:8083"),
 		JaegerEndpoint:  getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
+		BackendToken:    getEnv("BACKEND_TOKEN", ""),
+		SecretsPath:     getEnv("SECRETS_PATH", "secret/data/model-router"),
+		KafkaBrokers:    getEnvList("KAFKA_BROKERS"),
+		EventTopic:      getEnv("EVENT_TOPIC", "platform-events"),
+
+		RoutingAuditSink:  getEnv("ROUTING_AUDIT_SINK", ""),
+		RoutingAuditTopic: getEnv("ROUTING_AUDIT_TOPIC", "routing-decisions"),
+
+		ModelSyncInterval: getEnvDuration("MODEL_SYNC_INTERVAL", 30*time.Second),
+		BackendLeaseTTL:   getEnvDuration("BACKEND_LEASE_TTL", 30*time.Second),
+
+		HealthCheckInterval: getEnvDuration("HEALTH_CHECK_INTERVAL", 10*time.Second),
+
+		OutlierFailures:      getEnvInt("OUTLIER_CONSECUTIVE_FAILURES", 5),
+		OutlierLatencyFactor: getEnvFloat("OUTLIER_LATENCY_FACTOR", 3),
+		OutlierEjectionTime:  getEnvDuration("OUTLIER_EJECTION_TIME", 30*time.Second),
+		OutlierInterval:      getEnvDuration("OUTLIER_DETECTION_INTERVAL", 10*time.Second),
+
+		WarmupRequests:   getEnvInt("WARMUP_REQUESTS", 3),
+		WarmupMaxLatency: getEnvDuration("WARMUP_MAX_LATENCY", 5*time.Second),
+
+		RoutingStrategy:        getEnv("ROUTING_STRATEGY", "latency"),
+		ModelRoutingStrategies: getEnvMap("MODEL_ROUTING_STRATEGIES"),
+		FailoverAttempts:       getEnvInt("FAILOVER_MAX_ATTEMPTS", 3),
+
+		BackendMaxConcurrency:   getEnvInt("BACKEND_MAX_CONCURRENCY", 0),
+		ModelBackendConcurrency: getEnvMap("MODEL_BACKEND_CONCURRENCY"),
+		BackendQueueDepth:       getEnvInt("BACKEND_QUEUE_DEPTH", 100),
+		BackendQueueTimeout:     getEnvDuration("BACKEND_QUEUE_TIMEOUT", time.Second),
+
+		BackendMaxRPS:     getEnvFloat("BACKEND_MAX_RPS", 0),
+		BackendRateLimits: getEnvMap("BACKEND_RATE_LIMITS"),
+
+		BackendClasses:  getEnvMap("BACKEND_CLASSES"),
+		RelaxedDeadline: getEnvDuration("COST_ROUTING_MIN_DEADLINE", 30*time.Second),
+
+		RoutingRules: getEnv("ROUTING_RULES", ""),
+		Experiments:  getEnv("EXPERIMENTS", ""),
+
+		ShadowTraffic:     getEnv("SHADOW_TRAFFIC", ""),
+		ShadowConcurrency: getEnvInt("SHADOW_CONCURRENCY", 16),
+		ShadowTimeout:     getEnvDuration("SHADOW_TIMEOUT", 30*time.Second),
+
+		RouteBatchMaxSize:     getEnvInt("ROUTE_BATCH_MAX_SIZE", 256),
+		RouteBatchConcurrency: getEnvInt("ROUTE_BATCH_CONCURRENCY", 8),
+
+		RedisHost:         getEnv("REDIS_HOST", ""),
+		StateSyncInterval: getEnvDuration("STATE_SYNC_INTERVAL", 5*time.Second),
+		ReplicaID:         getEnv("REPLICA_ID", hostname()),
 	}
 }
 
+// NewRedisClient creates a new Redis client
+func NewRedisClient(addr string) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil && i >= 0 {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil && f >= 0 {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// getEnvMap parses pairs given as "llama=least_connections,bert=latency"
+func getEnvMap(key string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && name != "" {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+func getEnvList(key string) []string {
+	if value := os.Getenv(key); value != "" {
+		return strings.Split(value, ",")
+	}
+	return nil
+}
+
+// hostname names the replica after its host, the pod name on Kubernetes
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "model-router"
+	}
+	return name
+}
@@ -0,0 +1,130 @@
+// Package experiments assigns users to the variants of A/B experiments on
+// models. Assignment hashes the user ID, so a user sees the same variant on
+// every request and replica and model changes are evaluated on stable groups.
+package experiments
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+)
+
+// Variant is one arm of an experiment, served by a model version
+type Variant struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Weight is the percentage of users assigned to the variant
+	Weight int `json:"weight"`
+}
+
+// Experiment splits the users requesting Version of Model between variants.
+// Requests naming another version, or made by no user, are not part of it.
+type Experiment struct {
+	Name     string    `json:"name"`
+	Model    string    `json:"model"`
+	Version  string    `json:"version"`
+	Variants []Variant `json:"variants"`
+}
+
+// Set holds the experiments running, at most one per model. A nil Set
+// assigns nobody.
+type Set struct {
+	byModel map[string]Experiment
+}
+
+// Parse parses a JSON array of experiments
+func Parse(value string) (*Set, error) {
+	set := &Set{byModel: make(map[string]Experiment)}
+	if value == "" {
+		return set, nil
+	}
+
+	var experiments []Experiment
+	if err := json.Unmarshal([]byte(value), &experiments); err != nil {
+		return nil, fmt.Errorf("invalid experiments: %w", err)
+	}
+	for _, experiment := range experiments {
+		if err := experiment.validate(); err != nil {
+			return nil, fmt.Errorf("invalid experiment %q: %w", experiment.Name, err)
+		}
+		if running, ok := set.byModel[experiment.Model]; ok {
+			return nil, fmt.Errorf("invalid experiment %q: %s is already in experiment %q", experiment.Name, experiment.Model, running.Name)
+		}
+		set.byModel[experiment.Model] = experiment
+	}
+	return set, nil
+}
+
+func (e Experiment) validate() error {
+	if e.Name == "" || e.Model == "" || e.Version == "" {
+		return fmt.Errorf("name, model and version are required")
+	}
+	if len(e.Variants) == 0 {
+		return fmt.Errorf("no variants")
+	}
+
+	names := make(map[string]bool, len(e.Variants))
+	total := 0
+	for _, variant := range e.Variants {
+		if variant.Name == "" || variant.Version == "" {
+			return fmt.Errorf("variants need a name and a version")
+		}
+		if names[variant.Name] {
+			return fmt.Errorf("variant %q is listed twice", variant.Name)
+		}
+		names[variant.Name] = true
+		if variant.Weight < 0 {
+			return fmt.Errorf("variant %q has a negative weight", variant.Name)
+		}
+		total += variant.Weight
+	}
+	if total != 100 {
+		return fmt.Errorf("variant weights add up to %d, not 100", total)
+	}
+	return nil
+}
+
+// Assign returns the experiment on model/version and the variant user is
+// assigned to, or false when the request is not part of an experiment
+func (s *Set) Assign(model, version, user string) (Experiment, Variant, bool) {
+	if s == nil || user == "" {
+		return Experiment{}, Variant{}, false
+	}
+	experiment, ok := s.byModel[model]
+	if !ok || experiment.Version != version {
+		return Experiment{}, Variant{}, false
+	}
+
+	bucket := int(hash(experiment.Name, user) % 100)
+	for _, variant := range experiment.Variants {
+		if bucket < variant.Weight {
+			return experiment, variant, true
+		}
+		bucket -= variant.Weight
+	}
+	return Experiment{}, Variant{}, false
+}
+
+// List returns the experiments running, ordered by model
+func (s *Set) List() []Experiment {
+	if s == nil {
+		return []Experiment{}
+	}
+	experiments := make([]Experiment, 0, len(s.byModel))
+	for _, experiment := range s.byModel {
+		experiments = append(experiments, experiment)
+	}
+	sort.Slice(experiments, func(i, j int) bool { return experiments[i].Model < experiments[j].Model })
+	return experiments
+}
+
+// hash buckets users per experiment, so a user's variants in different
+// experiments are independent
+func hash(experiment, user string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(experiment))
+	h.Write([]byte{0})
+	h.Write([]byte(user))
+	return h.Sum32()
+}
@@ -0,0 +1,74 @@
+package experiments
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const resnetExperiment = `[{
+	"name": "resnet18-v2",
+	"model": "resnet18",
+	"version": "v1",
+	"variants": [
+		{"name": "control", "version": "v1", "weight": 50},
+		{"name": "treatment", "version": "v2", "weight": 50}
+	]
+}]`
+
+func TestSet_AssignIsDeterministic(t *testing.T) {
+	set, err := Parse(resnetExperiment)
+	require.NoError(t, err)
+
+	assigned := make(map[string]int)
+	for i := 0; i < 200; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		experiment, variant, ok := set.Assign("resnet18", "v1", user)
+		require.True(t, ok)
+		assert.Equal(t, "resnet18-v2", experiment.Name)
+		assigned[variant.Name]++
+
+		_, again, _ := set.Assign("resnet18", "v1", user)
+		assert.Equal(t, variant, again, "a user keeps their variant")
+	}
+	assert.Greater(t, assigned["control"], 50)
+	assert.Greater(t, assigned["treatment"], 50)
+}
+
+func TestSet_AssignOutsideExperiment(t *testing.T) {
+	set, err := Parse(resnetExperiment)
+	require.NoError(t, err)
+
+	_, _, ok := set.Assign("resnet18", "v1", "")
+	assert.False(t, ok, "anonymous requests")
+	_, _, ok = set.Assign("resnet18", "v2", "user-1")
+	assert.False(t, ok, "other versions")
+	_, _, ok = set.Assign("bert", "v1", "user-1")
+	assert.False(t, ok, "other models")
+
+	var nilSet *Set
+	_, _, ok = nilSet.Assign("resnet18", "v1", "user-1")
+	assert.False(t, ok)
+}
+
+func TestParse_RejectsInvalidExperiments(t *testing.T) {
+	for name, value := range map[string]string{
+		"not json":      `{"name": "x"}`,
+		"no variants":   `[{"name": "x", "model": "resnet18", "version": "v1"}]`,
+		"short of 100":  `[{"name": "x", "model": "resnet18", "version": "v1", "variants": [{"name": "a", "version": "v1", "weight": 60}]}]`,
+		"duplicate arm": `[{"name": "x", "model": "resnet18", "version": "v1", "variants": [{"name": "a", "version": "v1", "weight": 50}, {"name": "a", "version": "v2", "weight": 50}]}]`,
+		"same model twice": `[
+			{"name": "x", "model": "resnet18", "version": "v1", "variants": [{"name": "a", "version": "v1", "weight": 100}]},
+			{"name": "y", "model": "resnet18", "version": "v1", "variants": [{"name": "a", "version": "v1", "weight": 100}]}
+		]`,
+	} {
+		_, err := Parse(value)
+		assert.Error(t, err, name)
+	}
+
+	set, err := Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, set.List())
+}
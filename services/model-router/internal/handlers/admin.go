@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/router"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// AdminHandler lets operators change the routing table at runtime, without
+// going through the metadata service or redeploying
+type AdminHandler struct {
+	logger *zap.Logger
+	router *router.ModelRouter
+}
+
+func NewAdminHandler(logger *zap.Logger, modelRouter *router.ModelRouter) *AdminHandler {
+	return &AdminHandler{
+		logger: logger,
+		router: modelRouter,
+	}
+}
+
+// DrainRequest names the backends at URL to drain; an empty model or
+// version drains the URL for all of them. Drains may wait up to WaitSeconds
+// for the requests in flight to finish.
+type DrainRequest struct {
+	Model       string `json:"model"`
+	Version     string `json:"version"`
+	URL         string `json:"url" binding:"required"`
+	WaitSeconds int    `json:"wait_seconds" binding:"omitempty,min=0,max=600"`
+}
+
+// ListBackends reports the routing table with health, latency and circuit
+// breaker state
+func (h *AdminHandler) ListBackends(c *gin.Context) {
+	backends := h.router.Backends()
+	c.JSON(http.StatusOK, gin.H{
+		"backends": backends,
+		"count":    len(backends),
+	})
+}
+
+// AddBackend registers a backend that is kept until removed
+func (h *AdminHandler) AddBackend(c *gin.Context) {
+	var req BackendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+
+	if req.Class != "" {
+		h.router.SetBackendClass(req.URL, req.Class)
+	}
+	status := http.StatusOK
+	if h.router.AddBackend(req.Model, req.Version, req.URL) {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{
+		"model":   req.Model,
+		"version": req.Version,
+		"url":     req.URL,
+	})
+}
+
+// RemoveBackend removes a backend from the routing table, however it was
+// registered. Backends synced from the metadata service come back on the
+// next reload unless deregistered there.
+func (h *AdminHandler) RemoveBackend(c *gin.Context) {
+	var req BackendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+
+	if !h.router.UnregisterBackend(req.Model, req.Version, req.URL) {
+		apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.NotFound, "backend not found: %s/%s at %s", req.Model, req.Version, req.URL))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Drain stops new requests to a backend and reports its requests in flight,
+// and whether it is drained: with none left, its server can be restarted
+func (h *AdminHandler) Drain(c *gin.Context) {
+	h.setDraining(c, true)
+}
+
+// DrainStatus reports whether the backends at the url query parameter, for
+// the model and version parameters if given, are drained
+func (h *AdminHandler) DrainStatus(c *gin.Context) {
+	url := c.Query("url")
+	if url == "" {
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "url is required"))
+		return
+	}
+
+	backends := h.router.DrainStatus(c.Query("model"), c.Query("version"), url)
+	if len(backends) == 0 {
+		apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.NotFound, "backend not found at %s", url))
+		return
+	}
+	c.JSON(http.StatusOK, drainResponse(backends))
+}
+
+// Undrain resumes requests to a drained backend
+func (h *AdminHandler) Undrain(c *gin.Context) {
+	h.setDraining(c, false)
+}
+
+func (h *AdminHandler) setDraining(c *gin.Context, drain bool) {
+	var req DrainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+
+	backends := h.router.DrainBackend(req.Model, req.Version, req.URL, drain)
+	if len(backends) == 0 {
+		apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.NotFound, "backend not found at %s", req.URL))
+		return
+	}
+	if drain && req.WaitSeconds > 0 {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(req.WaitSeconds)*time.Second)
+		defer cancel()
+		backends = h.router.WaitDrained(ctx, req.Model, req.Version, req.URL)
+	}
+	c.JSON(http.StatusOK, drainResponse(backends))
+}
+
+func drainResponse(backends []router.BackendStatus) gin.H {
+	return gin.H{
+		"backends": backends,
+		"count":    len(backends),
+		"drained":  router.Drained(backends),
+	}
+}
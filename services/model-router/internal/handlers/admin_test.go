@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/router"
+)
+
+func TestAdminHandler_ManagesBackends(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	modelRouter := router.NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	handler := NewAdminHandler(zap.NewNop(), modelRouter)
+
+	engine := gin.New()
+	engine.GET("/admin/backends", handler.ListBackends)
+	engine.POST("/admin/backends", handler.AddBackend)
+	engine.DELETE("/admin/backends", handler.RemoveBackend)
+	engine.PUT("/admin/backends/drain", handler.Drain)
+	engine.GET("/admin/backends/drain", handler.DrainStatus)
+	engine.DELETE("/admin/backends/drain", handler.Undrain)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+		return w
+	}
+
+	backend := `{"model": "resnet18", "version": "v1", "url": "http://gpu-1:8082"}`
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/admin/backends", backend).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/admin/backends", backend).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/admin/backends", `{"model": "resnet18"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/admin/backends", `{"model": "resnet18", "version": "v1", "url": "http://gpu-1:8082", "class": "tpu"}`).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/admin/backends", `{"model": "resnet18", "version": "v1", "url": "http://gpu-1:8082", "class": "cpu"}`).Code)
+	assert.Equal(t, router.ClassCPU, modelRouter.Backends()[0].Class)
+
+	w := do(http.MethodPut, "/admin/backends/drain", `{"url": "http://gpu-1:8082"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var drained struct {
+		Backends []router.BackendStatus `json:"backends"`
+		Drained  bool                   `json:"drained"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &drained))
+	require.Len(t, drained.Backends, 1)
+	assert.True(t, drained.Backends[0].Draining)
+	assert.True(t, drained.Drained, "a backend without requests in flight is drained at once")
+
+	w = do(http.MethodPut, "/admin/backends/drain", `{"url": "http://gpu-1:8082", "wait_seconds": 1}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"drained":true`)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/admin/backends/drain", `{"url": "http://gpu-1:8082", "wait_seconds": -1}`).Code)
+
+	w = do(http.MethodGet, "/admin/backends/drain?url=http://gpu-1:8082", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"drained":true`)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/admin/backends/drain", "").Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/admin/backends/drain?url=http://gpu-2:8082", "").Code)
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPut, "/admin/backends/drain", `{"url": "http://gpu-2:8082"}`).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodDelete, "/admin/backends/drain", `{"url": "http://gpu-1:8082"}`).Code)
+	assert.False(t, modelRouter.Backends()[0].Draining)
+	assert.Contains(t, do(http.MethodGet, "/admin/backends/drain?url=http://gpu-1:8082", "").Body.String(), `"drained":false`)
+
+	w = do(http.MethodGet, "/admin/backends", "")
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/admin/backends", backend).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/admin/backends", backend).Code)
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/router"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Defaults for the inputs a batch may hold and how many of them are routed
+// at once
+const (
+	DefaultBatchMaxSize     = 256
+	DefaultBatchConcurrency = 8
+)
+
+// SetBatchLimits caps the inputs of a batch at maxSize and routes at most
+// concurrency of them at once; zero keeps the default
+func (h *RouteHandler) SetBatchLimits(maxSize, concurrency int) {
+	if maxSize > 0 {
+		h.batchMaxSize = maxSize
+	}
+	if concurrency > 0 {
+		h.batchConcurrency = concurrency
+	}
+}
+
+// BatchRouteRequest asks for inferences on several inputs of one model
+// version. Each input is routed as a request of its own, so inputs spread
+// over the version's backends and fail independently.
+type BatchRouteRequest struct {
+	RequestID string                   `json:"request_id"`
+	Model     string                   `json:"model" binding:"required"`
+	Version   string                   `json:"version"`
+	Inputs    []map[string]interface{} `json:"inputs" binding:"required,min=1"`
+	UserID    string                   `json:"user_id"`
+	SessionID string                   `json:"session_id"`
+}
+
+// BatchItemResult is the outcome of one input of a batch: its output, or
+// the problem document it failed with
+type BatchItemResult struct {
+	Index  int                    `json:"index"`
+	Output map[string]interface{} `json:"output,omitempty"`
+	Error  *apperrors.Response    `json:"error,omitempty"`
+}
+
+// RouteBatch routes each input of a batch in parallel and answers with the
+// result of every input, in order. Routing rules and experiments apply to
+// the batch as a whole, matched against its first input. The response is
+// 200 whenever the batch was routed, even if some of its inputs failed.
+func (h *RouteHandler) RouteBatch(c *gin.Context) {
+	var req BatchRouteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+	if len(req.Inputs) > h.batchMaxSize {
+		apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.InvalidArgument, "batch of %d inputs exceeds the limit of %d", len(req.Inputs), h.batchMaxSize))
+		return
+	}
+
+	if req.Version == "" {
+		req.Version = "v1"
+	}
+	req.Version = h.router.ResolveAlias(req.Model, req.Version)
+
+	ctx := c.Request.Context()
+	if req.RequestID != "" {
+		ctx = logging.WithRequestID(ctx, req.RequestID)
+	}
+	logger := logging.With(ctx, h.logger)
+	routed := RouteRequest{
+		Model:     req.Model,
+		Version:   req.Version,
+		Input:     req.Inputs[0],
+		UserID:    req.UserID,
+		SessionID: req.SessionID,
+	}
+	ctx, record := h.dispatch(ctx, c, &routed)
+
+	logger.Info("routing batch inference request",
+		zap.String("model", req.Model),
+		zap.String("version", routed.Version),
+		zap.Int("inputs", len(req.Inputs)),
+	)
+
+	results := h.routeBatch(ctx, req.Model, routed.Version, req.Inputs)
+	failed := 0
+	var firstErr error
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = apperrors.New(result.Error.Code, result.Error.Detail)
+			}
+		}
+	}
+	record(firstErr)
+	if failed > 0 {
+		logger.Warn("batch inputs failed",
+			zap.Int("failed", failed),
+			zap.Int("inputs", len(req.Inputs)),
+		)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"model":   req.Model,
+		"version": routed.Version,
+		"results": results,
+		"count":   len(results),
+		"failed":  failed,
+	})
+}
+
+// routeBatch routes the inputs with at most the handler's batch concurrency
+// in flight, and returns their results in the order of the inputs
+func (h *RouteHandler) routeBatch(ctx context.Context, model, version string, inputs []map[string]interface{}) []BatchItemResult {
+	results := make([]BatchItemResult, len(inputs))
+	slots := make(chan struct{}, h.batchConcurrency)
+	var wg sync.WaitGroup
+	for i, input := range inputs {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, input map[string]interface{}) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = h.routeItem(ctx, model, version, i, input)
+		}(i, input)
+	}
+	wg.Wait()
+	return results
+}
+
+func (h *RouteHandler) routeItem(ctx context.Context, model, version string, index int, input map[string]interface{}) BatchItemResult {
+	result, err := h.router.RouteRequest(ctx, model, version, input)
+	if err != nil {
+		_, problem := apperrors.Problem(ctx, err)
+		return BatchItemResult{Index: index, Error: &problem}
+	}
+
+	// Fallback answers are not the version's own to compare candidates with
+	if result[router.ServedByFallbackKey] != true {
+		h.shadow.Mirror(ctx, model, version, input, result)
+	}
+	return BatchItemResult{Index: index, Output: result}
+}
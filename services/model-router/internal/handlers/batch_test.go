@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/router"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+type batchResponse struct {
+	Results []BatchItemResult `json:"results"`
+	Count   int               `json:"count"`
+	Failed  int               `json:"failed"`
+}
+
+func TestRouteBatch_RoutesEachInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var served atomic.Int64
+	echo := func() *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Input map[string]interface{} `json:"input"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			if req.Input["n"] == "bad" {
+				http.Error(w, "malformed input", http.StatusBadRequest)
+				return
+			}
+			served.Add(1)
+			json.NewEncoder(w).Encode(map[string]interface{}{"n": req.Input["n"]})
+		}))
+	}
+	first, second := echo(), echo()
+	defer first.Close()
+	defer second.Close()
+
+	modelRouter := router.NewModelRouter(zap.NewNop(), first.URL)
+	modelRouter.RegisterBackend("llama", "v1", first.URL)
+	modelRouter.RegisterBackend("llama", "v1", second.URL)
+	handler := NewRouteHandler(zap.NewNop(), modelRouter)
+	handler.SetBatchLimits(10, 2)
+
+	engine := gin.New()
+	engine.POST("/v1/route/batch", handler.RouteBatch)
+	inputs := make([]string, 8)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf(`{"n": "%d"}`, i)
+	}
+	inputs[5] = `{"n": "bad"}`
+	body := fmt.Sprintf(`{"model": "llama", "inputs": [%s]}`, strings.Join(inputs, ","))
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/route/batch", bytes.NewBufferString(body)))
+	require.Equal(t, http.StatusOK, w.Code, "failed inputs do not fail the batch")
+
+	var resp batchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Results, 8)
+	assert.Equal(t, 8, resp.Count)
+	assert.Equal(t, 1, resp.Failed)
+	for i, result := range resp.Results {
+		assert.Equal(t, i, result.Index, "results keep the order of the inputs")
+		if i == 5 {
+			require.NotNil(t, result.Error)
+			assert.Equal(t, apperrors.InvalidArgument, result.Error.Code)
+			assert.Nil(t, result.Output)
+			continue
+		}
+		assert.Nil(t, result.Error)
+		assert.Equal(t, fmt.Sprint(i), result.Output["n"])
+	}
+	assert.Equal(t, int64(7), served.Load(), "each input is routed once")
+}
+
+func TestRouteBatch_RejectsInvalidBatches(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewRouteHandler(zap.NewNop(), router.NewModelRouter(zap.NewNop(), "http://localhost:8082"))
+	handler.SetBatchLimits(2, 0)
+	engine := gin.New()
+	engine.POST("/v1/route/batch", handler.RouteBatch)
+
+	for name, body := range map[string]string{
+		"empty":     `{"model": "llama", "inputs": []}`,
+		"no model":  `{"inputs": [{}]}`,
+		"too large": `{"model": "llama", "inputs": [{}, {}, {}]}`,
+	} {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/route/batch", bytes.NewBufferString(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/ai-platform/model-router/internal/router"
+	"github.com/yourusername/ai-platform/pkg/health"
+)
+
+// Health returns a handler reporting the router healthy, degraded or
+// unhealthy with the result of each dependency check and the health of every
+// model version's backends. The router is unhealthy, and answered with 503,
+// when a required dependency fails or no model version has a backend
+// serving; it is degraded when an optional dependency fails or any version
+// is short of backends.
+func Health(checker *health.Checker, modelRouter *router.ModelRouter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := checker.Run(c.Request.Context())
+		versions := modelRouter.Health()
+
+		overall := router.OverallHealth(versions)
+		switch {
+		case !report.Ready():
+			overall = router.Unhealthy
+		case report.Health() == router.Degraded && overall == router.Healthy:
+			overall = router.Degraded
+		}
+
+		status := http.StatusOK
+		if overall == router.Unhealthy {
+			status = http.StatusServiceUnavailable
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(status, gin.H{
+			"status":   overall,
+			"service":  report.Service,
+			"checks":   report.Checks,
+			"backends": versions,
+		})
+	}
+}
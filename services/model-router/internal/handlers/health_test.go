@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/router"
+	"github.com/yourusername/ai-platform/pkg/health"
+)
+
+func TestHealth_ReportsBackends(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	modelRouter := router.NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	modelRouter.RegisterBackend("llama", "v1", "http://gpu-1:8082")
+	checker := health.NewChecker("model-router", health.DefaultTimeout)
+	failing := false
+	checker.Add("metadata-service", func(ctx context.Context) error {
+		if failing {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	engine := gin.New()
+	engine.GET("/health", Health(checker, modelRouter))
+	get := func() (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body
+	}
+
+	code, body := get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "healthy", body["status"])
+	backends := body["backends"].(map[string]interface{})
+	llama := backends["llama/v1"].(map[string]interface{})
+	assert.Equal(t, "healthy", llama["status"])
+	backend := llama["backends"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "http://gpu-1:8082", backend["url"])
+	assert.Equal(t, "closed", backend["circuit_state"])
+	assert.Contains(t, backend, "last_check")
+	assert.Contains(t, backend, "avg_latency_ms")
+
+	failing = true
+	code, body = get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unhealthy", body["status"])
+	assert.Contains(t, body["checks"], "metadata-service")
+}
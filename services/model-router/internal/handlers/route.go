@@ -1,56 +1,386 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/yourusername/ai-platform/model-router/internal/experiments"
+	"github.com/yourusername/ai-platform/model-router/internal/observability"
 	"github.com/yourusername/ai-platform/model-router/internal/router"
+	"github.com/yourusername/ai-platform/model-router/internal/rules"
+	"github.com/yourusername/ai-platform/model-router/internal/shadow"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/sse"
 )
 
+// Headers naming the experiment a response was routed within and the
+// variant its user is assigned to
+const (
+	ExperimentHeader = "X-Experiment"
+	VariantHeader    = "X-Experiment-Variant"
+)
+
+// RuleHeader names the routing rule a response was routed by
+const RuleHeader = "X-Routing-Rule"
+
+// FallbackHeader flags responses served by the fallback of the model
+// version requested
+const FallbackHeader = "X-Served-By-Fallback"
+
 type RouteHandler struct {
-	logger *zap.Logger
-	router *router.ModelRouter
+	logger      *zap.Logger
+	router      *router.ModelRouter
+	leaseTTL    time.Duration
+	experiments *experiments.Set
+	rules       *rules.Engine
+	shadow      *shadow.Mirror
+	// batchMaxSize caps the inputs of a batch, batchConcurrency how many
+	// of them are routed at once
+	batchMaxSize     int
+	batchConcurrency int
 }
 
-func NewRouteHandler(logger *zap.Logger, router *router.ModelRouter) *RouteHandler {
+func NewRouteHandler(logger *zap.Logger, modelRouter *router.ModelRouter) *RouteHandler {
 	return &RouteHandler{
-		logger: logger,
-		router: router,
+		logger:   logger,
+		router:   modelRouter,
+		leaseTTL: router.DefaultLeaseTTL,
+
+		batchMaxSize:     DefaultBatchMaxSize,
+		batchConcurrency: DefaultBatchConcurrency,
 	}
 }
 
+// SetLeaseTTL sets the lease of heartbeats that name no TTL
+func (h *RouteHandler) SetLeaseTTL(ttl time.Duration) {
+	h.leaseTTL = ttl
+}
+
+// SetExperiments sets the A/B experiments users are assigned to
+func (h *RouteHandler) SetExperiments(set *experiments.Set) {
+	h.experiments = set
+}
+
+// SetRules sets the rules routing requests by tenant, headers and input
+func (h *RouteHandler) SetRules(engine *rules.Engine) {
+	h.rules = engine
+}
+
+// SetShadow sets the mirror answered requests are shadowed through
+func (h *RouteHandler) SetShadow(mirror *shadow.Mirror) {
+	h.shadow = mirror
+}
+
 type RouteRequest struct {
 	RequestID string                 `json:"request_id"`
 	Model     string                 `json:"model" binding:"required"`
 	Version   string                 `json:"version"`
 	Input     map[string]interface{} `json:"input" binding:"required"`
+	// UserID assigns the request to its user's variant of any experiment
+	// on the model version
+	UserID string `json:"user_id"`
+	// SessionID keeps the requests of a session on one backend for models
+	// routed by consistent hashing; requests without one use UserID
+	SessionID string `json:"session_id"`
+}
+
+// affinity returns the key that routes the request to a backend under
+// consistent hashing
+func (r RouteRequest) affinity() string {
+	if r.SessionID != "" {
+		return r.SessionID
+	}
+	return r.UserID
+}
+
+// route applies the first routing rule the request matches, if any: the
+// rule's version is served whatever the model's traffic split or
+// experiments, and by the backends of the rule's pool. It reports whether a
+// rule chose the version.
+func (h *RouteHandler) route(ctx context.Context, c *gin.Context, req *RouteRequest) (context.Context, bool) {
+	rule, ok := h.rules.Match(req.Model, rules.Request{
+		Tenant: logging.FieldsFromContext(ctx).Tenant,
+		Header: c.Request.Header,
+		Input:  req.Input,
+	})
+	if !ok {
+		return ctx, false
+	}
+
+	observability.RuleMatches.WithLabelValues(rule.Name).Inc()
+	c.Header(RuleHeader, rule.Name)
+	if rule.Pool != "" {
+		ctx = router.WithPool(ctx, rule.Pool)
+	}
+	if rule.Version == "" {
+		return ctx, false
+	}
+	req.Version = h.router.ResolveAlias(req.Model, rule.Version)
+	return router.Pin(ctx), true
+}
+
+// assign sends the request to the version of the variant its user is
+// assigned to, if an experiment runs on the model version, and names the
+// experiment and variant in the response headers. The returned func records
+// the request's outcome in the variant's metrics.
+func (h *RouteHandler) assign(ctx context.Context, c *gin.Context, req *RouteRequest) (context.Context, func(error)) {
+	experiment, variant, ok := h.experiments.Assign(req.Model, req.Version, req.UserID)
+	if !ok {
+		return ctx, func(error) {}
+	}
+
+	req.Version = variant.Version
+	c.Header(ExperimentHeader, experiment.Name)
+	c.Header(VariantHeader, variant.Name)
+
+	start := time.Now()
+	// The variant's version is served whatever the model's traffic split
+	return router.Pin(ctx), func(err error) {
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		observability.ExperimentRequests.WithLabelValues(experiment.Name, variant.Name, status).Inc()
+		observability.ExperimentLatency.WithLabelValues(experiment.Name, variant.Name).Observe(time.Since(start).Seconds())
+	}
+}
+
+// dispatch applies the routing rules, experiments and session affinity to
+// the request. The returned func records its outcome.
+func (h *RouteHandler) dispatch(ctx context.Context, c *gin.Context, req *RouteRequest) (context.Context, func(error)) {
+	ctx, chosen := h.route(router.WithAffinity(ctx, req.affinity()), c, req)
+	if chosen {
+		return ctx, func(error) {}
+	}
+	return h.assign(ctx, c, req)
 }
 
 func (h *RouteHandler) RouteInference(c *gin.Context) {
 	var req RouteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
 		return
 	}
 
 	if req.Version == "" {
 		req.Version = "v1"
 	}
+	// Experiments and shadows apply to the version an alias points at
+	req.Version = h.router.ResolveAlias(req.Model, req.Version)
+
+	// Callers that predate header propagation send the request ID in the body
+	ctx := c.Request.Context()
+	if req.RequestID != "" {
+		ctx = logging.WithRequestID(ctx, req.RequestID)
+	}
+	logger := logging.With(ctx, h.logger)
+	ctx, record := h.dispatch(ctx, c, &req)
 
-	h.logger.Info("routing inference request",
-		zap.String("request_id", req.RequestID),
+	logger.Info("routing inference request",
 		zap.String("model", req.Model),
 		zap.String("version", req.Version),
 	)
 
-	result, err := h.router.RouteRequest(c.Request.Context(), req.Model, req.Version, req.Input)
+	result, err := h.router.RouteRequest(ctx, req.Model, req.Version, req.Input)
+	record(err)
 	if err != nil {
-		h.logger.Error("routing failed", zap.Error(err))
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		logger.Error("routing failed", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, err)
 		return
 	}
 
+	// Fallback answers are not the version's own to compare candidates with
+	if result[router.ServedByFallbackKey] == true {
+		c.Header(FallbackHeader, "true")
+	} else {
+		h.shadow.Mirror(ctx, req.Model, req.Version, req.Input, result)
+	}
 	c.JSON(http.StatusOK, result)
 }
+
+// RouteStream routes a streamed inference and relays the orchestrator's
+// events to the caller as they arrive
+func (h *RouteHandler) RouteStream(c *gin.Context) {
+	var req RouteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+
+	if req.Version == "" {
+		req.Version = "v1"
+	}
+	// Experiments and shadows apply to the version an alias points at
+	req.Version = h.router.ResolveAlias(req.Model, req.Version)
+
+	ctx := c.Request.Context()
+	if req.RequestID != "" {
+		ctx = logging.WithRequestID(ctx, req.RequestID)
+	}
+	logger := logging.With(ctx, h.logger)
+	ctx, record := h.dispatch(ctx, c, &req)
+
+	logger.Info("routing streamed inference request",
+		zap.String("model", req.Model),
+		zap.String("version", req.Version),
+	)
+
+	body, err := h.router.RouteStream(ctx, req.Model, req.Version, req.Input)
+	record(err)
+	if err != nil {
+		logger.Error("routing failed", zap.Error(err))
+		apperrors.Write(c.Writer, c.Request, err)
+		return
+	}
+	defer body.Close()
+
+	stream, err := sse.NewWriter(c.Writer)
+	if err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.Wrap(err, apperrors.Internal, "streaming unsupported"))
+		return
+	}
+
+	reader := sse.NewReader(body)
+	for {
+		event, err := reader.Next()
+		if err != nil {
+			// The orchestrator went away before ending the stream
+			if ctx.Err() == nil {
+				logger.Error("stream interrupted", zap.Error(err))
+				_, failure := apperrors.ToHTTP(apperrors.FromTransportError(err, "inference-orchestrator"))
+				stream.Send(sse.EventError, failure)
+			}
+			return
+		}
+		if err := stream.Write(event); err != nil {
+			logger.Warn("caller went away during stream", zap.Error(err))
+			return
+		}
+		if event.Name == sse.EventDone || event.Name == sse.EventError {
+			return
+		}
+	}
+}
+
+// ListBackends reports the routing table with health and circuit breaker state
+func (h *RouteHandler) ListBackends(c *gin.Context) {
+	backends := h.router.Backends()
+	c.JSON(http.StatusOK, gin.H{
+		"backends": backends,
+		"count":    len(backends),
+	})
+}
+
+// ListSplits reports the traffic splits synced from the metadata service
+func (h *RouteHandler) ListSplits(c *gin.Context) {
+	splits := h.router.Splits()
+	c.JSON(http.StatusOK, gin.H{
+		"splits": splits,
+		"count":  len(splits),
+	})
+}
+
+// ListAliases reports the version aliases synced from the metadata service
+func (h *RouteHandler) ListAliases(c *gin.Context) {
+	aliases := h.router.Aliases()
+	c.JSON(http.StatusOK, gin.H{
+		"aliases": aliases,
+		"count":   len(aliases),
+	})
+}
+
+// ListFallbacks reports the fallbacks of model versions synced from the
+// metadata service
+func (h *RouteHandler) ListFallbacks(c *gin.Context) {
+	fallbacks := h.router.Fallbacks()
+	c.JSON(http.StatusOK, gin.H{
+		"fallbacks": fallbacks,
+		"count":     len(fallbacks),
+	})
+}
+
+// ListExperiments reports the A/B experiments users are assigned to
+func (h *RouteHandler) ListExperiments(c *gin.Context) {
+	running := h.experiments.List()
+	c.JSON(http.StatusOK, gin.H{
+		"experiments": running,
+		"count":       len(running),
+	})
+}
+
+// ListRules reports the routing rules, in the order they are tried, and
+// the backend pools they route to
+func (h *RouteHandler) ListRules(c *gin.Context) {
+	routing := h.rules.Rules()
+	c.JSON(http.StatusOK, gin.H{
+		"rules": routing,
+		"pools": h.rules.Pools(),
+		"count": len(routing),
+	})
+}
+
+// BackendRequest names a backend of a model version
+type BackendRequest struct {
+	Model   string `json:"model" binding:"required"`
+	Version string `json:"version" binding:"required"`
+	URL     string `json:"url" binding:"required"`
+	// TTLSeconds is how long a heartbeat's lease lasts; the handler's lease
+	// TTL when zero
+	TTLSeconds int `json:"ttl_seconds" binding:"gte=0"`
+	// Class tags the backend with its cost class, gpu or cpu, when given
+	Class string `json:"class" binding:"omitempty,oneof=gpu cpu"`
+}
+
+// Heartbeat registers a backend, or renews its lease. Backends that stop
+// heartbeating are dropped from the routing table once their lease lapses.
+func (h *RouteHandler) Heartbeat(c *gin.Context) {
+	var req BackendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+
+	ttl := h.leaseTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+
+	if req.Class != "" {
+		h.router.SetBackendClass(req.URL, req.Class)
+	}
+	status := http.StatusOK
+	if h.router.RenewBackend(req.Model, req.Version, req.URL, ttl) {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{
+		"model":         req.Model,
+		"version":       req.Version,
+		"url":           req.URL,
+		"lease_expires": time.Now().Add(ttl).UTC(),
+	})
+}
+
+// Unregister removes a backend from the routing table
+func (h *RouteHandler) Unregister(c *gin.Context) {
+	var req BackendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Write(c.Writer, c.Request, apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error()))
+		return
+	}
+
+	if !h.router.UnregisterBackend(req.Model, req.Version, req.URL) {
+		apperrors.Write(c.Writer, c.Request, apperrors.Newf(apperrors.NotFound, "backend not found: %s/%s at %s", req.Model, req.Version, req.URL))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Load reports the requests being routed per model version
+func (h *RouteHandler) Load(c *gin.Context) {
+	c.JSON(http.StatusOK, h.router.Load(time.Now().UTC()))
+}
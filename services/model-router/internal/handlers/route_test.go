@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/experiments"
+	"github.com/yourusername/ai-platform/model-router/internal/router"
+	"github.com/yourusername/ai-platform/model-router/internal/rules"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/traffic"
+)
+
+func TestRouteInference_AssignsExperimentVariants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Version string `json:"version"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		json.NewEncoder(w).Encode(map[string]string{"version": req.Version})
+	}))
+	defer backend.Close()
+
+	modelRouter := router.NewModelRouter(zap.NewNop(), backend.URL)
+	modelRouter.RegisterBackend("resnet18", "v1", backend.URL)
+	modelRouter.RegisterBackend("resnet18", "v2", backend.URL)
+	// Experiments are not diluted by the model's traffic split
+	modelRouter.SetSplits([]traffic.Split{
+		{Model: "resnet18", Version: "v1", Weights: map[string]int{"v1": 50, "v2": 50}},
+	})
+
+	set, err := experiments.Parse(`[{"name": "resnet18-v2", "model": "resnet18", "version": "v1", "variants": [
+		{"name": "control", "version": "v1", "weight": 50},
+		{"name": "treatment", "version": "v2", "weight": 50}
+	]}]`)
+	require.NoError(t, err)
+	handler := NewRouteHandler(zap.NewNop(), modelRouter)
+	handler.SetExperiments(set)
+
+	engine := gin.New()
+	engine.POST("/v1/route", handler.RouteInference)
+	route := func(user string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"model": "resnet18", "version": "v1", "input": {}, "user_id": %q}`, user)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/route", bytes.NewBufferString(body)))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w
+	}
+
+	versions := map[string]string{"control": `"v1"`, "treatment": `"v2"`}
+	for i := 0; i < 20; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		w := route(user)
+		assert.Equal(t, "resnet18-v2", w.Header().Get(ExperimentHeader))
+		variant := w.Header().Get(VariantHeader)
+		assert.Contains(t, w.Body.String(), versions[variant])
+		assert.Equal(t, variant, route(user).Header().Get(VariantHeader), "a user keeps their variant")
+	}
+
+	w := route("")
+	assert.Empty(t, w.Header().Get(ExperimentHeader), "anonymous requests are not in the experiment")
+}
+
+func TestRouteInference_AppliesRoutingRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	echo := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Version string `json:"version"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			json.NewEncoder(w).Encode(map[string]string{"backend": name, "version": req.Version})
+		}))
+	}
+	shared, dedicated := echo("shared"), echo("dedicated")
+	defer shared.Close()
+	defer dedicated.Close()
+
+	engine, err := rules.Parse(fmt.Sprintf(`{
+		"pools": {"acme": [%q]},
+		"rules": [
+			{"name": "acme-dedicated", "match": {"tenants": ["acme"]}, "pool": "acme"},
+			{"name": "beta", "model": "llama", "match": {"headers": {"X-Beta": "*"}}, "version": "v2"}
+		]
+	}`, dedicated.URL))
+	require.NoError(t, err)
+
+	modelRouter := router.NewModelRouter(zap.NewNop(), shared.URL)
+	modelRouter.RegisterBackend("llama", "v1", shared.URL)
+	modelRouter.RegisterBackend("llama", "v1", dedicated.URL)
+	modelRouter.RegisterBackend("llama", "v2", shared.URL)
+	modelRouter.SetPools(engine.Pools())
+	handler := NewRouteHandler(zap.NewNop(), modelRouter)
+	handler.SetRules(engine)
+
+	routes := gin.New()
+	routes.POST("/v1/route", handler.RouteInference)
+	server := logging.Middleware(routes)
+	route := func(header http.Header) (*httptest.ResponseRecorder, map[string]string) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/route", bytes.NewBufferString(`{"model": "llama", "input": {}}`))
+		for name, values := range header {
+			req.Header.Set(name, values[0])
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var result map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return w, result
+	}
+
+	for i := 0; i < 10; i++ {
+		w, result := route(http.Header{logging.HeaderTenant: {"acme"}})
+		assert.Equal(t, "acme-dedicated", w.Header().Get(RuleHeader))
+		assert.Equal(t, "dedicated", result["backend"], "tenants are pinned to their pool")
+
+		w, result = route(http.Header{logging.HeaderTenant: {"globex"}})
+		assert.Empty(t, w.Header().Get(RuleHeader))
+		assert.Equal(t, "shared", result["backend"], "other tenants avoid dedicated backends")
+	}
+
+	w, result := route(http.Header{"X-Beta": {"1"}})
+	assert.Equal(t, "beta", w.Header().Get(RuleHeader))
+	assert.Equal(t, "v2", result["version"])
+}
+
+func TestRouteRequest_Affinity(t *testing.T) {
+	assert.Equal(t, "session-1", RouteRequest{SessionID: "session-1", UserID: "user-1"}.affinity())
+	assert.Equal(t, "user-1", RouteRequest{UserID: "user-1"}.affinity())
+	assert.Empty(t, RouteRequest{}.affinity())
+}
@@ -0,0 +1,91 @@
+// Package kserve calls model servers, such as Triton, over the KServe v2
+// gRPC inference protocol, so the router can send requests to them directly
+// rather than through an inference orchestrator. Messages are those
+// generated in pkg/kservepb.
+package kserve
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/kservepb"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Client calls one KServe v2 gRPC server
+type Client struct {
+	conn   *grpc.ClientConn
+	client kservepb.GRPCInferenceServiceClient
+}
+
+// Dial returns a client of the server at target, "host:port", connecting
+// over TLS when secure. It connects on the first call rather than at once.
+func Dial(target string, secure bool) (*Client, error) {
+	conn, err := kservepb.Dial(target, secure)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, client: kservepb.NewGRPCInferenceServiceClient(conn)}, nil
+}
+
+// Close closes the client's connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Infer runs model version on input, whose entries are the model's input
+// tensors by name, and returns the server's answer with its outputs in the
+// KServe v2 JSON form. token, if any, is sent as a bearer token.
+func (c *Client) Infer(ctx context.Context, model, version string, input map[string]interface{}, token string) (map[string]interface{}, error) {
+	req, err := kservepb.NewInferRequest(model, version, logging.RequestID(ctx), input)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.ModelInfer(outgoing(ctx, token), req)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	result, err := kservepb.Response(resp)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.Internal, "invalid response from model server")
+	}
+	return result, nil
+}
+
+// Ready fails unless the server is ready for inferencing
+func (c *Client) Ready(ctx context.Context) error {
+	resp, err := c.client.ServerReady(ctx, &kservepb.ServerReadyRequest{})
+	if err != nil {
+		return fromStatus(err)
+	}
+	if !resp.Ready {
+		return apperrors.New(apperrors.Unavailable, "model server is not ready")
+	}
+	return nil
+}
+
+// outgoing returns ctx carrying the correlation fields and token as call
+// metadata
+func outgoing(ctx context.Context, token string) context.Context {
+	pairs := make([]string, 0, 10)
+	for key, value := range logging.Headers(ctx) {
+		pairs = append(pairs, key, value)
+	}
+	if token != "" {
+		pairs = append(pairs, "authorization", "Bearer "+token)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// fromStatus classifies a failed call by its gRPC status
+func fromStatus(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return apperrors.FromTransportError(err, "model server")
+	}
+	return apperrors.Wrap(err, apperrors.FromGRPCCode(uint32(s.Code())), s.Message())
+}
@@ -0,0 +1,116 @@
+package kserve
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/kservepb"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// fakeServer is a KServe v2 server answering ModelInfer with infer
+type fakeServer struct {
+	kservepb.UnimplementedGRPCInferenceServiceServer
+	ready bool
+	infer func(ctx context.Context, req *kservepb.ModelInferRequest) (*kservepb.ModelInferResponse, error)
+}
+
+func (s *fakeServer) ServerReady(context.Context, *kservepb.ServerReadyRequest) (*kservepb.ServerReadyResponse, error) {
+	return &kservepb.ServerReadyResponse{Ready: s.ready}, nil
+}
+
+func (s *fakeServer) ModelInfer(ctx context.Context, req *kservepb.ModelInferRequest) (*kservepb.ModelInferResponse, error) {
+	return s.infer(ctx, req)
+}
+
+// serve starts the server and returns a client of it
+func (s *fakeServer) serve(t *testing.T) *Client {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	kservepb.RegisterGRPCInferenceServiceServer(server, s)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	client, err := Dial(listener.Addr().String(), false)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestClient_Infer(t *testing.T) {
+	server := &fakeServer{infer: func(ctx context.Context, req *kservepb.ModelInferRequest) (*kservepb.ModelInferResponse, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		assert.Equal(t, []string{"Bearer secret"}, md.Get("authorization"))
+		assert.Equal(t, "resnet18", req.ModelName)
+		assert.Equal(t, "1", req.ModelVersion)
+		assert.Equal(t, "req-1", req.Id)
+
+		// Inputs are sent in name order with their data in raw
+		require.Len(t, req.Inputs, 2)
+		assert.Equal(t, "image", req.Inputs[0].Name)
+		assert.Equal(t, []int64{2, 2}, req.Inputs[0].Shape)
+		assert.Equal(t, float32(0.5), math.Float32frombits(binary.LittleEndian.Uint32(req.RawInputContents[0][12:])))
+		assert.Equal(t, "label", req.Inputs[1].Name)
+		assert.Equal(t, []byte{7, 0, 0, 0, 0, 0, 0, 0}, req.RawInputContents[1])
+
+		probs := binary.LittleEndian.AppendUint32(nil, math.Float32bits(0.25))
+		probs = binary.LittleEndian.AppendUint32(probs, math.Float32bits(0.75))
+		classes := binary.LittleEndian.AppendUint32(nil, 3)
+		classes = append(classes, "cat"...)
+		return &kservepb.ModelInferResponse{
+			ModelName:    req.ModelName,
+			ModelVersion: req.ModelVersion,
+			Id:           req.Id,
+			Outputs: []*kservepb.ModelInferResponse_InferOutputTensor{
+				{Name: "probs", Datatype: "FP32", Shape: []int64{1, 2}},
+				{Name: "class", Datatype: "BYTES", Shape: []int64{1}},
+			},
+			RawOutputContents: [][]byte{probs, classes},
+		}, nil
+	}}
+	client := server.serve(t)
+
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	result, err := client.Infer(ctx, "resnet18", "1", map[string]interface{}{
+		"image": []interface{}{[]interface{}{0.0, 0.0}, []interface{}{0.0, 0.5}},
+		"label": map[string]interface{}{"datatype": "INT64", "shape": []interface{}{1.0}, "data": []interface{}{7.0}},
+	}, "secret")
+	require.NoError(t, err)
+
+	assert.Equal(t, "resnet18", result["model_name"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "probs", "datatype": "FP32", "shape": []int64{1, 2}, "data": []interface{}{0.25, 0.75}},
+		map[string]interface{}{"name": "class", "datatype": "BYTES", "shape": []int64{1}, "data": []interface{}{"cat"}},
+	}, result["outputs"])
+}
+
+func TestClient_MapsStatus(t *testing.T) {
+	client := (&fakeServer{infer: func(context.Context, *kservepb.ModelInferRequest) (*kservepb.ModelInferResponse, error) {
+		return nil, status.Error(codes.ResourceExhausted, "queue full")
+	}}).serve(t)
+
+	_, err := client.Infer(context.Background(), "resnet18", "1", map[string]interface{}{"x": 1.0}, "")
+	assert.True(t, apperrors.Is(err, apperrors.ResourceExhausted))
+}
+
+func TestClient_Ready(t *testing.T) {
+	server := &fakeServer{}
+	client := server.serve(t)
+	assert.True(t, apperrors.Is(client.Ready(context.Background()), apperrors.Unavailable))
+
+	server.ready = true
+	assert.NoError(t, client.Ready(context.Background()))
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Tracing middleware adds OpenTelemetry tracing to requests, continuing the
+// trace of the caller's traceparent header, so the router's spans join the
+// gateway's in one end-to-end trace
+func Tracing() gin.HandlerFunc {
+	tracer := otel.Tracer("model-router")
+
+	return func(c *gin.Context) {
+		parent := propagation.TraceContext{}.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracer.Start(
+			parent,
+			c.Request.Method+" "+c.FullPath(),
+			trace.WithSpanKind(trace.SpanKindServer),
+		)
+		defer span.End()
+
+		span.SetAttributes(
+			attribute.String("http.method", c.Request.Method),
+			attribute.String("http.url", c.Request.URL.String()),
+			attribute.String("http.user_agent", c.Request.UserAgent()),
+		)
+
+		// Backends are called with the router's span as their parent
+		if sc := span.SpanContext(); sc.IsValid() {
+			ctx = logging.WithTrace(ctx, sc.TraceID().String(), sc.SpanID().String())
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		span.SetAttributes(attribute.Int("http.status_code", c.Writer.Status()))
+	}
+}
@@ -0,0 +1,159 @@
+package observability
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// ExperimentRequests counts the requests routed within experiments
+	ExperimentRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_router_experiment_requests_total",
+			Help: "Total number of requests routed within A/B experiments, by experiment, variant and status",
+		},
+		[]string{"experiment", "variant", "status"},
+	)
+
+	// RuleMatches counts the requests routed by each routing rule
+	RuleMatches = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_router_rule_matches_total",
+			Help: "Total number of requests matching a routing rule, by rule",
+		},
+		[]string{"rule"},
+	)
+
+	// ExperimentLatency tracks how long the requests of each variant took to
+	// be answered, or for streams to start
+	ExperimentLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "model_router_experiment_request_duration_seconds",
+			Help:    "Latency of requests routed within A/B experiments, by experiment and variant",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"experiment", "variant"},
+	)
+
+	// ShadowRequests counts the requests mirrored to shadow candidates;
+	// "dropped" requests were not sent because too many were in flight
+	ShadowRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_router_shadow_requests_total",
+			Help: "Total number of requests mirrored to shadow candidate versions, by model, candidate and status",
+		},
+		[]string{"model", "candidate", "status"},
+	)
+
+	// ShadowLatency tracks how long shadow candidates took to answer
+	ShadowLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "model_router_shadow_request_duration_seconds",
+			Help:    "Latency of requests mirrored to shadow candidate versions, by model and candidate",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"model", "candidate"},
+	)
+
+	// Failovers counts the requests retried on another backend after theirs
+	// rejected them
+	Failovers = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_router_failovers_total",
+			Help: "Total number of requests retried on another backend of their model version, by model and version",
+		},
+		[]string{"model", "version"},
+	)
+
+	// FallbackRequests counts the requests served by their model version's
+	// fallback while none of its backends could
+	FallbackRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_router_fallback_requests_total",
+			Help: "Total number of requests served by a fallback model version, by model, version and fallback",
+		},
+		[]string{"model", "version", "fallback"},
+	)
+
+	// ConcurrencyRejections counts the requests that found every backend at
+	// its concurrency limit and could not wait, or waited in vain, for a slot
+	ConcurrencyRejections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_router_concurrency_rejections_total",
+			Help: "Total number of requests rejected by backend concurrency limits, by model and version",
+		},
+		[]string{"model", "version"},
+	)
+
+	// QueueDepth is the number of requests waiting for a backend to free a
+	// slot
+	QueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "model_router_queue_depth",
+			Help: "Requests queued for a backend slot, by model and version",
+		},
+		[]string{"model", "version"},
+	)
+
+	// QueueWait tracks how long queued requests waited for a slot
+	QueueWait = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "model_router_queue_wait_seconds",
+			Help:    "Time requests waited in queue for a backend slot, by model and version",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"model", "version"},
+	)
+
+	// BackendRequests counts the requests routed to each backend by outcome:
+	// "failure" when the backend failed them, "error" when they were the
+	// caller's mistake, "circuit_open" when its breaker turned them away and
+	// "rate_limited" when they were over its request rate ceiling
+	BackendRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_router_backend_requests_total",
+			Help: "Total number of requests routed to each backend, by model, version, backend and status",
+		},
+		[]string{"model", "version", "backend", "status"},
+	)
+
+	// BackendLatency tracks how long backends took to answer requests
+	BackendLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "model_router_backend_request_duration_seconds",
+			Help:    "Latency of requests answered by each backend, by model, version and backend",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"model", "version", "backend"},
+	)
+
+	// OutlierEjections counts the backends ejected for answering worse than
+	// their version's other backends, by reason
+	OutlierEjections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_router_outlier_ejections_total",
+			Help: "Total number of backends ejected as outliers, by model, version, backend and reason",
+		},
+		[]string{"model", "version", "backend", "reason"},
+	)
+
+	// CircuitState is the state of each backend's circuit breaker: 0 closed,
+	// 1 half-open and 2 open
+	CircuitState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "model_router_circuit_breaker_state",
+			Help: "State of each backend's circuit breaker (0 closed, 1 half-open, 2 open), by model, version and backend",
+		},
+		[]string{"model", "version", "backend"},
+	)
+
+	// CircuitTransitions counts the state changes of backends' circuit
+	// breakers
+	CircuitTransitions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_router_circuit_breaker_transitions_total",
+			Help: "Total number of circuit breaker state changes, by model, version, backend and the states changed from and to",
+		},
+		[]string{"model", "version", "backend", "from", "to"},
+	)
+)
@@ -0,0 +1,274 @@
+// Package registry keeps the model router's routing table in step with the
+// active models, traffic splits and version aliases registered with the
+// metadata service, so models created, updated or deleted there are routed,
+// moved or dropped, canaries started or ended, and aliases repointed, without
+// a restart.
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/traffic"
+)
+
+// pageSize is the most models the metadata service lists per request
+const pageSize = 100
+
+// WarmupInputKey is the model metadata key holding the JSON sample input
+// new backends of the model version are warmed up with
+const WarmupInputKey = "warmup_input"
+
+// FallbackKey is the model metadata key naming the model version, as
+// "model/version", that serves the model version's requests while none of
+// its own backends can
+const FallbackKey = "fallback"
+
+// Model is an active model version and the backend serving it
+type Model struct {
+	Name       string            `json:"name"`
+	Version    string            `json:"version"`
+	BackendURL string            `json:"backend_url"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// Source lists every active model version, traffic split and version alias
+type Source interface {
+	ActiveModels(ctx context.Context) ([]Model, error)
+	TrafficSplits(ctx context.Context) ([]traffic.Split, error)
+	Aliases(ctx context.Context) ([]traffic.Alias, error)
+}
+
+// Table is the routing table kept in step with the source
+type Table interface {
+	SyncBackends(table map[string]map[string][]string) (added, removed int)
+	SetSplits(splits []traffic.Split)
+	SetAliases(aliases []traffic.Alias)
+	SetWarmupInputs(inputs map[string]map[string]map[string]interface{})
+	SetFallbacks(fallbacks map[string]map[string]traffic.Fallback)
+}
+
+// Syncer loads the active models from a source into a routing table, at
+// startup and then every interval. A failed sync leaves the table as it was,
+// so the router keeps serving while the metadata service is unreachable.
+type Syncer struct {
+	source   Source
+	table    Table
+	interval time.Duration
+	logger   *zap.Logger
+
+	synced atomic.Bool
+}
+
+// NewSyncer creates a syncer that reloads table from source every interval
+func NewSyncer(source Source, table Table, interval time.Duration, logger *zap.Logger) *Syncer {
+	return &Syncer{
+		source:   source,
+		table:    table,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run syncs immediately and then every interval until ctx is cancelled
+func (s *Syncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("failed to sync models from metadata service", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync replaces the routing table with the source's active models, traffic
+// splits and aliases, and the fallbacks named in the models' metadata.
+// Models without a backend URL are skipped. The sample inputs in the
+// models' metadata are set before the backends, so new backends are warmed
+// up with them.
+func (s *Syncer) Sync(ctx context.Context) error {
+	models, err := s.source.ActiveModels(ctx)
+	if err != nil {
+		return err
+	}
+	splits, err := s.source.TrafficSplits(ctx)
+	if err != nil {
+		return err
+	}
+	aliases, err := s.source.Aliases(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := make(map[string]map[string][]string)
+	inputs := make(map[string]map[string]map[string]interface{})
+	fallbacks := make(map[string]map[string]traffic.Fallback)
+	for _, model := range models {
+		if model.BackendURL == "" {
+			continue
+		}
+		if table[model.Name] == nil {
+			table[model.Name] = make(map[string][]string)
+		}
+		table[model.Name][model.Version] = append(table[model.Name][model.Version], model.BackendURL)
+
+		if name, ok := model.Metadata[FallbackKey]; ok {
+			fallback, err := traffic.ParseFallback(name)
+			if err != nil {
+				s.logger.Warn("ignoring invalid fallback",
+					zap.String("model", model.Name),
+					zap.String("version", model.Version),
+					zap.Error(err),
+				)
+			} else {
+				if fallbacks[model.Name] == nil {
+					fallbacks[model.Name] = make(map[string]traffic.Fallback)
+				}
+				fallbacks[model.Name][model.Version] = fallback
+			}
+		}
+
+		if sample, ok := model.Metadata[WarmupInputKey]; ok {
+			var input map[string]interface{}
+			if err := json.Unmarshal([]byte(sample), &input); err != nil {
+				s.logger.Warn("ignoring invalid warm-up input",
+					zap.String("model", model.Name),
+					zap.String("version", model.Version),
+					zap.Error(err),
+				)
+				continue
+			}
+			if inputs[model.Name] == nil {
+				inputs[model.Name] = make(map[string]map[string]interface{})
+			}
+			inputs[model.Name][model.Version] = input
+		}
+	}
+
+	s.table.SetWarmupInputs(inputs)
+	added, removed := s.table.SyncBackends(table)
+	s.table.SetSplits(splits)
+	s.table.SetAliases(aliases)
+	s.table.SetFallbacks(fallbacks)
+	if added > 0 || removed > 0 {
+		s.logger.Info("routing table synced",
+			zap.Int("models", len(models)),
+			zap.Int("added", added),
+			zap.Int("removed", removed),
+		)
+	}
+	s.synced.Store(true)
+	return nil
+}
+
+// Ready fails until the routing table has been loaded once
+func (s *Syncer) Ready(ctx context.Context) error {
+	if !s.synced.Load() {
+		return fmt.Errorf("models not yet loaded from metadata service")
+	}
+	return nil
+}
+
+// MetadataSource lists the active models registered with the metadata
+// service at baseURL
+func MetadataSource(client *http.Client, baseURL string) Source {
+	return &metadataSource{client: client, baseURL: baseURL}
+}
+
+type metadataSource struct {
+	client  *http.Client
+	baseURL string
+}
+
+// ActiveModels pages through the models of every tenant; the router routes
+// them all, acting for no tenant
+func (s *metadataSource) ActiveModels(ctx context.Context) ([]Model, error) {
+	var models []Model
+	for offset := 0; ; offset += pageSize {
+		page, err := s.page(ctx, offset)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, page...)
+		if len(page) < pageSize {
+			return models, nil
+		}
+	}
+}
+
+// TrafficSplits lists the traffic split of every model that has one
+func (s *metadataSource) TrafficSplits(ctx context.Context) ([]traffic.Split, error) {
+	var body struct {
+		Splits []traffic.Split `json:"splits"`
+	}
+	if err := s.get(ctx, "/v1/traffic-splits", &body); err != nil {
+		return nil, err
+	}
+	return body.Splits, nil
+}
+
+// Aliases lists the version aliases of every model
+func (s *metadataSource) Aliases(ctx context.Context) ([]traffic.Alias, error) {
+	var body struct {
+		Aliases []traffic.Alias `json:"aliases"`
+	}
+	if err := s.get(ctx, "/v1/aliases", &body); err != nil {
+		return nil, err
+	}
+	return body.Aliases, nil
+}
+
+func (s *metadataSource) page(ctx context.Context, offset int) ([]Model, error) {
+	query := url.Values{
+		"status": {"active"},
+		"limit":  {strconv.Itoa(pageSize)},
+		"offset": {strconv.Itoa(offset)},
+	}
+	var body struct {
+		Models []Model `json:"models"`
+	}
+	if err := s.get(ctx, "/v1/models?"+query.Encode(), &body); err != nil {
+		return nil, err
+	}
+	return body.Models, nil
+}
+
+// get decodes the metadata service's response to a GET of path into body
+func (s *metadataSource) get(ctx context.Context, path string, body interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	logging.Inject(ctx, req)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return apperrors.FromTransportError(err, "metadata-service")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.FromHTTPResponse(resp, "metadata-service")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(body); err != nil {
+		return fmt.Errorf("failed to decode metadata-service response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/traffic"
+)
+
+type fakeSource struct {
+	models  []Model
+	splits  []traffic.Split
+	aliases []traffic.Alias
+	err     error
+}
+
+func (s *fakeSource) ActiveModels(ctx context.Context) ([]Model, error) {
+	return s.models, s.err
+}
+
+func (s *fakeSource) TrafficSplits(ctx context.Context) ([]traffic.Split, error) {
+	return s.splits, s.err
+}
+
+func (s *fakeSource) Aliases(ctx context.Context) ([]traffic.Alias, error) {
+	return s.aliases, s.err
+}
+
+type fakeTable struct {
+	table     map[string]map[string][]string
+	splits    []traffic.Split
+	aliases   []traffic.Alias
+	inputs    map[string]map[string]map[string]interface{}
+	fallbacks map[string]map[string]traffic.Fallback
+	syncs     int
+}
+
+func (t *fakeTable) SetWarmupInputs(inputs map[string]map[string]map[string]interface{}) {
+	t.inputs = inputs
+}
+
+func (t *fakeTable) SetFallbacks(fallbacks map[string]map[string]traffic.Fallback) {
+	t.fallbacks = fallbacks
+}
+
+func (t *fakeTable) SetSplits(splits []traffic.Split) {
+	t.splits = splits
+}
+
+func (t *fakeTable) SetAliases(aliases []traffic.Alias) {
+	t.aliases = aliases
+}
+
+func (t *fakeTable) SyncBackends(table map[string]map[string][]string) (int, int) {
+	t.table = table
+	t.syncs++
+	return 0, 0
+}
+
+func TestSyncer_Sync(t *testing.T) {
+	source := &fakeSource{models: []Model{
+		{Name: "resnet18", Version: "v1", BackendURL: "http://orchestrator-a:8082", Metadata: map[string]string{
+			FallbackKey: "resnet18-cpu/v1",
+		}},
+		{Name: "resnet18", Version: "v2", BackendURL: "http://orchestrator-b:8082", Metadata: map[string]string{
+			WarmupInputKey: `{"image": "sample.jpg"}`,
+		}},
+		{Name: "bert", Version: "v1", BackendURL: "http://orchestrator-a:8082", Metadata: map[string]string{
+			WarmupInputKey: `not json`,
+			FallbackKey:    "bert-cpu",
+		}},
+		{Name: "unserved", Version: "v1"},
+	}, splits: []traffic.Split{
+		{Model: "resnet18", Version: "v1", Weights: map[string]int{"v1": 95, "v2": 5}},
+	}, aliases: []traffic.Alias{
+		{Model: "resnet18", Name: "stable", Version: "v1"},
+	}}
+	table := &fakeTable{}
+	syncer := NewSyncer(source, table, 0, zap.NewNop())
+
+	assert.Error(t, syncer.Ready(context.Background()), "not ready before the first sync")
+	require.NoError(t, syncer.Sync(context.Background()))
+	assert.NoError(t, syncer.Ready(context.Background()))
+
+	assert.Equal(t, map[string]map[string][]string{
+		"resnet18": {"v1": {"http://orchestrator-a:8082"}, "v2": {"http://orchestrator-b:8082"}},
+		"bert":     {"v1": {"http://orchestrator-a:8082"}},
+	}, table.table)
+	assert.Equal(t, source.splits, table.splits)
+	assert.Equal(t, source.aliases, table.aliases)
+	assert.Equal(t, map[string]map[string]map[string]interface{}{
+		"resnet18": {"v2": {"image": "sample.jpg"}},
+	}, table.inputs, "invalid sample inputs are skipped")
+	assert.Equal(t, map[string]map[string]traffic.Fallback{
+		"resnet18": {"v1": {Model: "resnet18-cpu", Version: "v1"}},
+	}, table.fallbacks, "invalid fallbacks are skipped")
+}
+
+func TestSyncer_KeepsTableWhenSourceFails(t *testing.T) {
+	source := &fakeSource{err: errors.New("connection refused")}
+	table := &fakeTable{}
+	syncer := NewSyncer(source, table, 0, zap.NewNop())
+
+	assert.Error(t, syncer.Sync(context.Background()))
+	assert.Zero(t, table.syncs)
+	assert.Error(t, syncer.Ready(context.Background()))
+}
+
+func TestMetadataSource_Pages(t *testing.T) {
+	const total = pageSize + 3
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/models", r.URL.Path)
+		assert.Equal(t, "active", r.URL.Query().Get("status"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+		w.Write([]byte(`{"models":[`))
+		for i := offset; i < total && i < offset+pageSize; i++ {
+			if i > offset {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"name":"model-%d","version":"v1","backend_url":"http://orchestrator:8082"}`, i)
+		}
+		w.Write([]byte(`]}`))
+	}))
+	defer server.Close()
+
+	models, err := MetadataSource(server.Client(), server.URL).ActiveModels(context.Background())
+	require.NoError(t, err)
+	require.Len(t, models, total)
+	assert.Equal(t, Model{Name: "model-0", Version: "v1", BackendURL: "http://orchestrator:8082"}, models[0])
+}
+
+func TestMetadataSource_TrafficSplits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traffic-splits", r.URL.Path)
+		w.Write([]byte(`{"splits":[{"model":"resnet18","version":"v1","weights":{"v1":95,"v2":5}}],"count":1}`))
+	}))
+	defer server.Close()
+
+	splits, err := MetadataSource(server.Client(), server.URL).TrafficSplits(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []traffic.Split{
+		{Model: "resnet18", Version: "v1", Weights: map[string]int{"v1": 95, "v2": 5}},
+	}, splits)
+}
+
+func TestMetadataSource_Aliases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/aliases", r.URL.Path)
+		w.Write([]byte(`{"aliases":[{"model":"resnet18","name":"stable","version":"v2"}],"count":1}`))
+	}))
+	defer server.Close()
+
+	aliases, err := MetadataSource(server.Client(), server.URL).Aliases(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []traffic.Alias{{Model: "resnet18", Name: "stable", Version: "v2"}}, aliases)
+}
+
+func TestMetadataSource_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := MetadataSource(server.Client(), server.URL).ActiveModels(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AddBackend registers a backend for a model version on an operator's
+// behalf. Unlike backends synced from the metadata service it is kept when
+// the table is reloaded, until removed. It reports whether the backend was
+// newly registered; adding one already registered keeps it on reloads.
+func (r *ModelRouter) AddBackend(model, version, url string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, backend := range r.backends[model][version] {
+		if backend.URL == url {
+			backend.manual = true
+			return false
+		}
+	}
+
+	if r.backends[model] == nil {
+		r.backends[model] = make(map[string][]*Backend)
+	}
+	backend := r.newBackend(model, version, url)
+	backend.manual = true
+	r.backends[model][version] = append(r.backends[model][version], backend)
+	r.logger.Info("registered backend by admin request",
+		zap.String("model", model),
+		zap.String("version", version),
+		zap.String("url", url),
+	)
+	return true
+}
+
+// DrainBackend stops or resumes sending new requests to the backends at url.
+// Requests in flight on a draining backend finish normally, so it can be
+// removed once its in-flight count reaches zero. An empty model or version
+// matches every one, so a node can be drained for all the models it serves.
+// It returns the status of the backends matched.
+func (r *ModelRouter) DrainBackend(model, version, url string, drain bool) []BackendStatus {
+	r.mu.RLock()
+	matched := 0
+	for m, versions := range r.backends {
+		if model != "" && m != model {
+			continue
+		}
+		for v, backends := range versions {
+			if version != "" && v != version {
+				continue
+			}
+			for _, backend := range backends {
+				if backend.URL != url {
+					continue
+				}
+				backend.mu.Lock()
+				backend.draining = drain
+				backend.mu.Unlock()
+				matched++
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	if matched > 0 {
+		r.logger.Info("set backend draining",
+			zap.String("url", url),
+			zap.String("model", model),
+			zap.String("version", version),
+			zap.Bool("draining", drain),
+			zap.Int("backends", matched),
+		)
+	}
+
+	return r.DrainStatus(model, version, url)
+}
+
+// DrainStatus returns the status of the backends at url, for model and
+// version or, when empty, every one
+func (r *ModelRouter) DrainStatus(model, version, url string) []BackendStatus {
+	statuses := make([]BackendStatus, 0)
+	for _, status := range r.Backends() {
+		if status.URL == url && (model == "" || status.Model == model) && (version == "" || status.Version == version) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// Drained reports whether backends are all draining with no request in
+// flight, so that their server can be restarted
+func Drained(backends []BackendStatus) bool {
+	if len(backends) == 0 {
+		return false
+	}
+	for _, backend := range backends {
+		if !backend.Draining || backend.InFlight > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// drainPoll is how often WaitDrained checks the requests in flight
+const drainPoll = 50 * time.Millisecond
+
+// WaitDrained waits until the backends at url matching model and version
+// are drained, or ctx is done, and returns their status
+func (r *ModelRouter) WaitDrained(ctx context.Context, model, version, url string) []BackendStatus {
+	ticker := time.NewTicker(drainPoll)
+	defer ticker.Stop()
+
+	for {
+		statuses := r.DrainStatus(model, version, url)
+		if len(statuses) == 0 || Drained(statuses) {
+			return statuses
+		}
+		select {
+		case <-ctx.Done():
+			return statuses
+		case <-ticker.C:
+		}
+	}
+}
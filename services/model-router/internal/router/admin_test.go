@@ -0,0 +1,89 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestAddBackend_SurvivesSync(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+
+	assert.True(t, router.AddBackend("resnet18", "v1", "http://manual:8082"))
+	assert.False(t, router.AddBackend("resnet18", "v1", "http://manual:8082"), "adding twice keeps one backend")
+
+	router.SyncBackends(map[string]map[string][]string{
+		"resnet18": {"v1": {"http://synced:8082"}},
+	})
+
+	var urls []string
+	for _, backend := range router.Backends() {
+		urls = append(urls, backend.URL)
+	}
+	assert.ElementsMatch(t, []string{"http://synced:8082", "http://manual:8082"}, urls)
+
+	assert.True(t, router.UnregisterBackend("resnet18", "v1", "http://manual:8082"))
+	router.SyncBackends(map[string]map[string][]string{
+		"resnet18": {"v1": {"http://synced:8082"}},
+	})
+	assert.Len(t, router.Backends(), 1)
+}
+
+func TestDrainBackend(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.RegisterBackend("resnet18", "v1", "http://a:8082")
+	router.RegisterBackend("resnet18", "v1", "http://b:8082")
+	router.RegisterBackend("bert", "v1", "http://b:8082")
+
+	// Without a model the URL is drained for every model it serves
+	statuses := router.DrainBackend("", "", "http://b:8082", true)
+	require.Len(t, statuses, 2)
+	for _, status := range statuses {
+		assert.True(t, status.Draining)
+	}
+
+	backends, err := router.lookup("resnet18", "v1")
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	assert.Equal(t, "http://a:8082", backends[0].URL)
+
+	_, err = router.RouteRequest(context.Background(), "bert", "v1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable), "a version with every backend draining is unavailable")
+
+	router.DrainBackend("bert", "", "http://b:8082", false)
+	_, err = router.lookup("bert", "v1")
+	assert.NoError(t, err)
+	backends, err = router.lookup("resnet18", "v1")
+	require.NoError(t, err)
+	assert.Len(t, backends, 1, "only the model named is resumed")
+
+	assert.Empty(t, router.DrainBackend("", "", "http://unknown:8082", true))
+}
+
+func TestWaitDrained(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.RegisterBackend("resnet18", "v1", "http://a:8082")
+	backends, err := router.lookup("resnet18", "v1")
+	require.NoError(t, err)
+
+	release := backends[0].acquire()
+	statuses := router.DrainBackend("", "", "http://a:8082", true)
+	assert.False(t, Drained(statuses), "a backend with requests in flight is not drained yet")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.False(t, Drained(router.WaitDrained(ctx, "", "", "http://a:8082")), "waiting gives up with ctx")
+
+	time.AfterFunc(20*time.Millisecond, release)
+	assert.True(t, Drained(router.WaitDrained(context.Background(), "", "", "http://a:8082")))
+
+	router.DrainBackend("", "", "http://a:8082", false)
+	assert.False(t, Drained(router.DrainStatus("", "", "http://a:8082")), "resumed backends are not drained")
+	assert.False(t, Drained(nil))
+}
@@ -0,0 +1,62 @@
+package router
+
+import (
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/traffic"
+)
+
+// SetAliases replaces the version aliases requests may name versions by;
+// aliases left out are no longer routed. Requests resolve an alias when
+// they are routed, so repointing one moves every later request at once.
+func (r *ModelRouter) SetAliases(aliases []traffic.Alias) {
+	byModel := make(map[string]map[string]traffic.Alias)
+	for _, alias := range aliases {
+		if err := alias.Validate(); err != nil {
+			r.logger.Warn("ignoring invalid version alias", zap.String("model", alias.Model), zap.Error(err))
+			continue
+		}
+		if byModel[alias.Model] == nil {
+			byModel[alias.Model] = make(map[string]traffic.Alias)
+		}
+		byModel[alias.Model][alias.Name] = alias
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.aliases = byModel
+}
+
+// Aliases returns the version aliases in effect, ordered by model and name
+func (r *ModelRouter) Aliases() []traffic.Alias {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	aliases := make([]traffic.Alias, 0)
+	for _, byName := range r.aliases {
+		for _, alias := range byName {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		if aliases[i].Model != aliases[j].Model {
+			return aliases[i].Model < aliases[j].Model
+		}
+		return aliases[i].Name < aliases[j].Name
+	})
+	return aliases
+}
+
+// ResolveAlias returns the version an alias of model points at, or version
+// itself when it is no alias
+func (r *ModelRouter) ResolveAlias(model, version string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if alias, ok := r.aliases[model][version]; ok {
+		return alias.Version
+	}
+	return version
+}
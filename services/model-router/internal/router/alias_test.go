@@ -0,0 +1,60 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/traffic"
+)
+
+func TestResolveAlias(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.SetAliases([]traffic.Alias{
+		{Model: "resnet18", Name: "stable", Version: "v1"},
+		{Model: "resnet18", Name: "latest", Version: "v2"},
+		{Model: "resnet18", Name: "broken"},
+	})
+
+	assert.Equal(t, "v1", router.ResolveAlias("resnet18", "stable"))
+	assert.Equal(t, "v2", router.ResolveAlias("resnet18", "latest"))
+	assert.Equal(t, "v3", router.ResolveAlias("resnet18", "v3"), "versions resolve to themselves")
+	assert.Equal(t, "stable", router.ResolveAlias("bert", "stable"), "aliases belong to their model")
+	assert.Equal(t, "broken", router.ResolveAlias("resnet18", "broken"), "invalid aliases are ignored")
+
+	aliases := router.Aliases()
+	require.Len(t, aliases, 2)
+	assert.Equal(t, "latest", aliases[0].Name)
+}
+
+func TestRouteRequest_FollowsRepointedAlias(t *testing.T) {
+	serve := func(output string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"output": "` + output + `"}`))
+		}))
+	}
+	v1, v2 := serve("v1"), serve("v2")
+	defer v1.Close()
+	defer v2.Close()
+
+	router := NewModelRouter(zap.NewNop(), v1.URL)
+	router.RegisterBackend("resnet18", "v1", v1.URL)
+	router.RegisterBackend("resnet18", "v2", v2.URL)
+
+	route := func() interface{} {
+		result, err := router.RouteRequest(context.Background(), "resnet18", "stable", map[string]interface{}{})
+		require.NoError(t, err)
+		return result["output"]
+	}
+
+	router.SetAliases([]traffic.Alias{{Model: "resnet18", Name: "stable", Version: "v1"}})
+	assert.Equal(t, "v1", route())
+
+	router.SetAliases([]traffic.Alias{{Model: "resnet18", Name: "stable", Version: "v2"}})
+	assert.Equal(t, "v2", route())
+}
@@ -0,0 +1,70 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/ai-platform/model-router/internal/audit"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// SetAuditLog sets where the routing decision of each request is recorded
+func (r *ModelRouter) SetAuditLog(log *audit.Log) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.auditLog = log
+}
+
+// newDecision starts the audit record of a request to model/version, to be
+// picked among backends
+func (r *ModelRouter) newDecision(ctx context.Context, model, version string, backends []*Backend) *audit.Decision {
+	r.mu.RLock()
+	log := r.auditLog
+	r.mu.RUnlock()
+	if log == nil {
+		return nil
+	}
+	return &audit.Decision{
+		Timestamp:  time.Now().UTC(),
+		Model:      model,
+		Version:    version,
+		Strategy:   string(r.strategy(model)),
+		Pool:       poolOf(ctx),
+		Candidates: len(backends),
+	}
+}
+
+// tried notes a backend the request was tried on
+func tried(decision *audit.Decision, backend *Backend) {
+	if decision == nil {
+		return
+	}
+	decision.Backend = backend.URL
+	decision.Tried = append(decision.Tried, backend.URL)
+	decision.Attempts++
+}
+
+// recordDecision completes the audit record of a request that ended with
+// err and records it
+func (r *ModelRouter) recordDecision(ctx context.Context, decision *audit.Decision, backend *Backend, err error) {
+	if decision == nil {
+		return
+	}
+	decision.LatencyMs = time.Since(decision.Timestamp).Milliseconds()
+	decision.Status = "ok"
+	if err != nil {
+		decision.Status = string(apperrors.CodeOf(err))
+		decision.Error = err.Error()
+	}
+
+	r.mu.RLock()
+	log := r.auditLog
+	if backend != nil {
+		decision.Class = r.classOf(backend.URL)
+	}
+	r.mu.RUnlock()
+	if backend != nil && backend.CircuitBreaker != nil {
+		decision.CircuitState = backend.CircuitBreaker.State().String()
+	}
+	log.Record(ctx, *decision)
+}
@@ -0,0 +1,101 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/audit"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+)
+
+// auditedDecisions publishes the decisions queued in log and returns them
+func auditedDecisions(t *testing.T, log *audit.Log, published *[][]byte) []audit.Decision {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	log.Run(ctx)
+
+	decisions := make([]audit.Decision, 0, len(*published))
+	for _, value := range *published {
+		var decision audit.Decision
+		require.NoError(t, json.Unmarshal(value, &decision))
+		decisions = append(decisions, decision)
+	}
+	return decisions
+}
+
+func TestRouteRequest_RecordsDecisions(t *testing.T) {
+	down, _ := countingServer(http.StatusServiceUnavailable, `{"error": "draining"}`)
+	defer down.Close()
+	up, _ := countingServer(http.StatusOK, `{"output": "ok"}`)
+	defer up.Close()
+
+	var published [][]byte
+	log := audit.NewLog("model-router", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		published = append(published, value)
+		return nil
+	}), 100, zap.NewNop())
+
+	router := NewModelRouter(zap.NewNop(), up.URL)
+	router.SetAuditLog(log)
+	router.RegisterBackend("resnet18", "v1", down.URL)
+	router.RegisterBackend("resnet18", "v1", up.URL)
+
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	for i := 0; i < 20; i++ {
+		_, err := router.RouteRequest(ctx, "resnet18", "v1", map[string]interface{}{})
+		require.NoError(t, err)
+	}
+
+	decisions := auditedDecisions(t, log, &published)
+	require.Len(t, decisions, 20)
+	failedOver := 0
+	for _, decision := range decisions {
+		assert.Equal(t, "req-1", decision.RequestID)
+		assert.Equal(t, "resnet18", decision.Model)
+		assert.Equal(t, "v1", decision.Version)
+		assert.Equal(t, string(LatencyAware), decision.Strategy)
+		assert.Equal(t, 2, decision.Candidates)
+		assert.Equal(t, up.URL, decision.Backend)
+		assert.Equal(t, "ok", decision.Status)
+		assert.Equal(t, "closed", decision.CircuitState)
+		assert.Equal(t, ClassGPU, decision.Class)
+		assert.Equal(t, len(decision.Tried), decision.Attempts)
+		if decision.Attempts == 2 {
+			assert.Equal(t, []string{down.URL, up.URL}, decision.Tried)
+			failedOver++
+		}
+	}
+	assert.Greater(t, failedOver, 0, "failovers are recorded with every backend tried")
+}
+
+func TestRouteRequest_RecordsFailedDecisions(t *testing.T) {
+	down, _ := countingServer(http.StatusServiceUnavailable, `{"error": "draining"}`)
+	defer down.Close()
+
+	var published [][]byte
+	log := audit.NewLog("model-router", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		published = append(published, value)
+		return nil
+	}), 100, zap.NewNop())
+
+	router := NewModelRouter(zap.NewNop(), down.URL)
+	router.SetAuditLog(log)
+	router.RegisterBackend("resnet18", "v1", down.URL)
+
+	_, err := router.RouteRequest(context.Background(), "resnet18", "v1", map[string]interface{}{})
+	require.Error(t, err)
+
+	decisions := auditedDecisions(t, log, &published)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, string(apperrors.Unavailable), decisions[0].Status)
+	assert.NotEmpty(t, decisions[0].Error)
+	assert.Equal(t, 1, decisions[0].Attempts)
+}
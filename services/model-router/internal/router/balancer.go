@@ -0,0 +1,257 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+const (
+	// ewmaWeight is the weight of the latest request in a backend's moving
+	// averages; about the last ten requests dominate them
+	ewmaWeight = 0.2
+
+	// errorPenalty inflates a backend's latency by its error rate, so one
+	// failing half its requests ranks as if three times as slow
+	errorPenalty = 4
+
+	// loadFactor bounds consistent hashing: a backend takes no more than
+	// this multiple of the average requests in flight before its keys spill
+	// over to the next backend in their order
+	loadFactor = 1.25
+)
+
+// Strategy is how a request picks one of its model version's backends
+type Strategy string
+
+const (
+	// LatencyAware favours backends by their latency and error rate
+	LatencyAware Strategy = "latency"
+	// LeastConnections picks the backend with the fewest requests in flight,
+	// which keeps backends of unequal capacity equally busy
+	LeastConnections Strategy = "least_connections"
+	// ConsistentHash sends the requests sharing an affinity key to the same
+	// backend, for models keeping per-session state such as KV caches
+	ConsistentHash Strategy = "consistent_hash"
+)
+
+// ParseStrategy returns the strategy named name
+func ParseStrategy(name string) (Strategy, error) {
+	switch strategy := Strategy(name); strategy {
+	case LatencyAware, LeastConnections, ConsistentHash:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown routing strategy %q", name)
+}
+
+// backendFailed reports whether err says the backend is unhealthy; caller
+// mistakes say nothing about backend health
+func backendFailed(err error) bool {
+	switch apperrors.CodeOf(err) {
+	case "", apperrors.InvalidArgument, apperrors.NotFound, apperrors.Canceled:
+		return false
+	}
+	return true
+}
+
+// observe folds a request's outcome into the backend's moving averages.
+// A zero latency records only whether the request failed. It returns how
+// many requests in a row the backend has failed.
+func (b *Backend) observe(latency time.Duration, failed bool) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if latency > 0 {
+		if b.AvgLatency == 0 {
+			b.AvgLatency = latency
+		} else {
+			b.AvgLatency += time.Duration(ewmaWeight * float64(latency-b.AvgLatency))
+		}
+	}
+	sample := 0.0
+	if failed {
+		sample = 1
+		b.failuresInRow++
+	} else {
+		b.failuresInRow = 0
+	}
+	b.ErrorRate += ewmaWeight * (sample - b.ErrorRate)
+	return b.failuresInRow
+}
+
+// score ranks the backend for selection, lower being better: its latency
+// average in milliseconds, inflated by its error rate. Backends not yet
+// measured score as fast ones so they get tried, and backends whose circuit
+// breaker is open score infinity.
+func (b *Backend) score() float64 {
+	if b.CircuitBreaker != nil && b.CircuitBreaker.State() == gobreaker.StateOpen {
+		return math.Inf(1)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	latency := math.Max(float64(b.AvgLatency)/float64(time.Millisecond), 1)
+	return latency * (1 + errorPenalty*b.ErrorRate)
+}
+
+// acquire counts a request in flight on the backend until the returned
+// func is called
+func (b *Backend) acquire() func() {
+	b.inFlight.Add(1)
+	return func() { b.inFlight.Add(-1) }
+}
+
+type affinityKey struct{}
+
+// WithAffinity returns ctx whose requests carry key, such as a session or
+// user ID, for models routed by consistent hashing
+func WithAffinity(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, affinityKey{}, key)
+}
+
+func affinity(ctx context.Context) string {
+	key, _ := ctx.Value(affinityKey{}).(string)
+	return key
+}
+
+// selectBackend picks a backend for a request to model by the model's
+// strategy, among those below their concurrency limit and request rate
+// ceiling if any are. Consistent hashing needs a key; requests without one
+// are balanced by latency.
+func (r *ModelRouter) selectBackend(model, key string, backends []*Backend) *Backend {
+	backends = r.underRate(withCapacity(backends))
+	if len(backends) == 1 {
+		return backends[0]
+	}
+	switch r.strategy(model) {
+	case LeastConnections:
+		return leastConnections(backends)
+	case ConsistentHash:
+		if key != "" {
+			return consistentHash(key, backends)
+		}
+	}
+	return powerOfTwoChoices(backends)
+}
+
+// strategy returns the strategy set for model, or the default one
+func (r *ModelRouter) strategy(model string) Strategy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if strategy, ok := r.strategies[model]; ok {
+		return strategy
+	}
+	return r.defaultStrategy
+}
+
+// powerOfTwoChoices picks the better scoring of two backends chosen at random,
+// so slow or failing replicas get proportionally less traffic without every
+// request herding onto the single best one
+func powerOfTwoChoices(backends []*Backend) *Backend {
+	i := rand.Intn(len(backends))
+	j := rand.Intn(len(backends) - 1)
+	if j >= i {
+		j++
+	}
+	if backends[j].score() < backends[i].score() {
+		return backends[j]
+	}
+	return backends[i]
+}
+
+// leastConnections picks the backend with the fewest requests in flight,
+// passing over those whose circuit breaker is open while any other remains.
+// Ties go to whichever comes first from a random offset, so idle backends
+// share requests evenly.
+func leastConnections(backends []*Backend) *Backend {
+	offset := rand.Intn(len(backends))
+	var best *Backend
+	var bestLoad int64
+	bestOpen := false
+	for i := range backends {
+		backend := backends[(offset+i)%len(backends)]
+		load := backend.inFlight.Load()
+		open := math.IsInf(backend.score(), 1)
+		if best == nil || (bestOpen && !open) || (bestOpen == open && load < bestLoad) {
+			best, bestLoad, bestOpen = backend, load, open
+		}
+	}
+	return best
+}
+
+// consistentHash ranks the backends for key by rendezvous hashing and picks
+// the first in that order that is under its load bound and whose circuit
+// breaker is closed. A key keeps its backend while the backend set changes,
+// except when its own backend leaves, and the bound spills hot keys over to
+// their next backend rather than overloading one replica.
+func consistentHash(key string, backends []*Backend) *Backend {
+	ranked := make([]*Backend, len(backends))
+	copy(ranked, backends)
+	ranks := make(map[*Backend]uint64, len(ranked))
+	var total int64
+	for _, backend := range ranked {
+		ranks[backend] = rendezvous(key, backend.URL)
+		total += backend.inFlight.Load()
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranks[ranked[i]] > ranks[ranked[j]] })
+
+	// The bound counts the request being placed, so it is never below one
+	bound := int64(math.Ceil(loadFactor * float64(total+1) / float64(len(ranked))))
+	var fallback *Backend
+	for _, backend := range ranked {
+		if math.IsInf(backend.score(), 1) {
+			continue
+		}
+		if backend.inFlight.Load() < bound {
+			return backend
+		}
+		if fallback == nil {
+			fallback = backend
+		}
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return ranked[0]
+}
+
+// rendezvous is the rank of a backend for key. FNV alone barely mixes URLs
+// that differ in a few characters, so its hash is finalized as in SplitMix64.
+func rendezvous(key, url string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h.Write([]byte{0})
+	h.Write([]byte(url))
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// weights estimates the share of requests each backend receives, in
+// proportion to the inverse of its score
+func weights(backends []*Backend) []float64 {
+	shares := make([]float64, len(backends))
+	total := 0.0
+	for i, backend := range backends {
+		shares[i] = 1 / backend.score()
+		total += shares[i]
+	}
+	for i := range shares {
+		if total > 0 {
+			shares[i] /= total
+		}
+	}
+	return shares
+}
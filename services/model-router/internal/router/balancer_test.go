@@ -0,0 +1,246 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestBackendFailed(t *testing.T) {
+	assert.False(t, backendFailed(nil))
+	assert.False(t, backendFailed(apperrors.New(apperrors.InvalidArgument, "bad input")))
+	assert.False(t, backendFailed(apperrors.New(apperrors.NotFound, "no such model")))
+	assert.True(t, backendFailed(apperrors.New(apperrors.Unavailable, "backend down")))
+	assert.True(t, backendFailed(errors.New("connection reset")))
+}
+
+func TestBackend_Observe(t *testing.T) {
+	backend := &Backend{}
+
+	backend.observe(100*time.Millisecond, false)
+	assert.Equal(t, 100*time.Millisecond, backend.AvgLatency, "first sample seeds the average")
+
+	backend.observe(200*time.Millisecond, false)
+	assert.Equal(t, 120*time.Millisecond, backend.AvgLatency)
+	assert.Zero(t, backend.ErrorRate)
+
+	backend.observe(0, true)
+	assert.Equal(t, 120*time.Millisecond, backend.AvgLatency, "failures leave the latency average alone")
+	assert.InDelta(t, 0.2, backend.ErrorRate, 1e-9)
+}
+
+func TestSelectBackend_PrefersFastHealthyBackends(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+
+	fast := &Backend{URL: "http://fast:8082", AvgLatency: 10 * time.Millisecond}
+	slow := &Backend{URL: "http://slow:8082", AvgLatency: 200 * time.Millisecond}
+	failing := &Backend{URL: "http://failing:8082", AvgLatency: 10 * time.Millisecond, ErrorRate: 0.9}
+	backends := []*Backend{fast, slow, failing}
+
+	selected := make(map[string]int)
+	for i := 0; i < 300; i++ {
+		selected[router.selectBackend("resnet18", "", backends).URL]++
+	}
+
+	// The worst backend loses every comparison it is drawn into
+	assert.Zero(t, selected[slow.URL])
+	assert.Greater(t, selected[fast.URL], selected[failing.URL])
+	assert.Greater(t, selected[failing.URL], 0)
+}
+
+func TestSelectBackend_AvoidsOpenCircuits(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+
+	open := &Backend{URL: "http://open:8082", CircuitBreaker: gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		ReadyToTrip: func(counts gobreaker.Counts) bool { return true },
+	})}
+	open.CircuitBreaker.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	healthy := &Backend{URL: "http://healthy:8082", AvgLatency: time.Second}
+
+	for i := 0; i < 20; i++ {
+		assert.Same(t, healthy, router.selectBackend("resnet18", "", []*Backend{open, healthy}))
+	}
+}
+
+func TestWeights(t *testing.T) {
+	backends := []*Backend{
+		{AvgLatency: 10 * time.Millisecond},
+		{AvgLatency: 30 * time.Millisecond},
+	}
+
+	shares := weights(backends)
+	assert.InDelta(t, 0.75, shares[0], 1e-9)
+	assert.InDelta(t, 0.25, shares[1], 1e-9)
+}
+
+func TestParseStrategy(t *testing.T) {
+	strategy, err := ParseStrategy("least_connections")
+	assert.NoError(t, err)
+	assert.Equal(t, LeastConnections, strategy)
+
+	_, err = ParseStrategy("round_robin")
+	assert.Error(t, err)
+}
+
+func TestSelectBackend_LeastConnections(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.SetStrategies(LatencyAware, map[string]Strategy{"llama": LeastConnections})
+
+	// The busy backend is the faster one, which the latency strategy favours
+	busy := &Backend{URL: "http://busy:8082", AvgLatency: 10 * time.Millisecond}
+	idle := &Backend{URL: "http://idle:8082", AvgLatency: 200 * time.Millisecond}
+	busy.acquire()
+	release := busy.acquire()
+	backends := []*Backend{busy, idle}
+
+	for i := 0; i < 20; i++ {
+		assert.Same(t, idle, router.selectBackend("llama", "", backends))
+		assert.Same(t, busy, router.selectBackend("resnet18", "", backends))
+	}
+
+	// Requests follow the load once the other backend is the busier
+	idle.acquire()
+	idle.acquire()
+	release()
+	assert.Same(t, busy, router.selectBackend("llama", "", backends))
+}
+
+func TestSelectBackend_LeastConnectionsAvoidsOpenCircuits(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.SetStrategies(LeastConnections, nil)
+
+	open := &Backend{URL: "http://open:8082", CircuitBreaker: gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		ReadyToTrip: func(counts gobreaker.Counts) bool { return true },
+	})}
+	open.CircuitBreaker.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	busy := &Backend{URL: "http://busy:8082"}
+	busy.acquire()
+
+	for i := 0; i < 20; i++ {
+		assert.Same(t, busy, router.selectBackend("resnet18", "", []*Backend{open, busy}))
+	}
+}
+
+func TestRouteRequest_CountsInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte(`{"output": "ok"}`))
+	}))
+	defer server.Close()
+
+	router := NewModelRouter(zap.NewNop(), server.URL)
+	router.RegisterBackend("resnet18", "v1", server.URL)
+
+	done := make(chan error)
+	go func() {
+		_, err := router.RouteRequest(context.Background(), "resnet18", "v1", map[string]interface{}{})
+		done <- err
+	}()
+
+	<-started
+	assert.Equal(t, int64(1), router.Backends()[0].InFlight)
+	close(release)
+	assert.NoError(t, <-done)
+	assert.Zero(t, router.Backends()[0].InFlight)
+}
+
+func TestSelectBackend_ConsistentHashIsSticky(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.SetStrategies(LatencyAware, map[string]Strategy{"llama": ConsistentHash})
+
+	backends := []*Backend{{URL: "http://a:8082"}, {URL: "http://b:8082"}, {URL: "http://c:8082"}}
+	chosen := make(map[string]*Backend)
+	for i := 0; i < 300; i++ {
+		session := fmt.Sprintf("session-%d", i)
+		chosen[session] = router.selectBackend("llama", session, backends)
+		for j := 0; j < 5; j++ {
+			assert.Same(t, chosen[session], router.selectBackend("llama", session, backends))
+		}
+	}
+
+	// Sessions spread across backends
+	counts := make(map[*Backend]int)
+	for _, backend := range chosen {
+		counts[backend]++
+	}
+	for _, backend := range backends {
+		assert.Greater(t, counts[backend], 50, backend.URL)
+	}
+
+	// Removing a backend moves only the sessions it served
+	remaining := backends[:2]
+	for session, backend := range chosen {
+		if backend != backends[2] {
+			assert.Same(t, backend, router.selectBackend("llama", session, remaining))
+		}
+	}
+}
+
+func TestConsistentHash_SpillsOverLoadBound(t *testing.T) {
+	backends := []*Backend{{URL: "http://a:8082"}, {URL: "http://b:8082"}}
+	home := consistentHash("session-1", backends)
+
+	// Two requests in flight on the session's backend put it at the bound
+	// of ceil(1.25 * 3 / 2) = 2, so the next request spills over
+	home.acquire()
+	release := home.acquire()
+	assert.NotSame(t, home, consistentHash("session-1", backends))
+
+	release()
+	assert.Same(t, home, consistentHash("session-1", backends))
+}
+
+func TestConsistentHash_AvoidsOpenCircuits(t *testing.T) {
+	open := &Backend{URL: "http://open:8082", CircuitBreaker: gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		ReadyToTrip: func(counts gobreaker.Counts) bool { return true },
+	})}
+	open.CircuitBreaker.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	healthy := &Backend{URL: "http://healthy:8082"}
+
+	for i := 0; i < 20; i++ {
+		assert.Same(t, healthy, consistentHash(fmt.Sprintf("session-%d", i), []*Backend{open, healthy}))
+	}
+}
+
+func TestRouteRequest_ConsistentHashFollowsAffinity(t *testing.T) {
+	hits := make(map[string]int)
+	var mu sync.Mutex
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			hits[name]++
+			mu.Unlock()
+			w.Write([]byte(`{"output": "ok"}`))
+		}
+	}
+	a := httptest.NewServer(handler("a"))
+	defer a.Close()
+	b := httptest.NewServer(handler("b"))
+	defer b.Close()
+
+	router := NewModelRouter(zap.NewNop(), a.URL)
+	router.SetStrategies(ConsistentHash, nil)
+	router.RegisterBackend("llama", "v1", a.URL)
+	router.RegisterBackend("llama", "v1", b.URL)
+
+	ctx := WithAffinity(context.Background(), "session-1")
+	for i := 0; i < 10; i++ {
+		_, err := router.RouteRequest(ctx, "llama", "v1", map[string]interface{}{})
+		assert.NoError(t, err)
+	}
+	assert.Len(t, hits, 1, "every request of the session reached one backend")
+}
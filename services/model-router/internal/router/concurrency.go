@@ -0,0 +1,94 @@
+package router
+
+import (
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// SetConcurrencyLimits caps the requests in flight on each backend, so a
+// spike cannot run a GPU out of memory: to perModel's limit for its models
+// and defaultLimit for others, zero meaning no limit. Requests finding
+// every backend full wait in their version's queue. Limits apply to
+// backends already registered too; requests already in flight keep theirs.
+func (r *ModelRouter) SetConcurrencyLimits(defaultLimit int, perModel map[string]int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.defaultConcurrency = defaultLimit
+	r.concurrency = perModel
+	for model, versions := range r.backends {
+		for _, backends := range versions {
+			for _, backend := range backends {
+				backend.setLimit(r.concurrencyLimit(model))
+			}
+		}
+	}
+}
+
+// concurrencyLimit returns the limit of model's backends; r.mu must be held
+func (r *ModelRouter) concurrencyLimit(model string) int {
+	if limit, ok := r.concurrency[model]; ok {
+		return limit
+	}
+	return r.defaultConcurrency
+}
+
+// setLimit replaces the backend's slots with limit of them
+func (b *Backend) setLimit(limit int) {
+	var slots chan struct{}
+	if limit > 0 {
+		slots = make(chan struct{}, limit)
+	}
+	b.mu.Lock()
+	b.slots = slots
+	b.mu.Unlock()
+}
+
+// full reports whether the backend is at its concurrency limit
+func (b *Backend) full() bool {
+	b.mu.RLock()
+	slots := b.slots
+	b.mu.RUnlock()
+	return slots != nil && len(slots) == cap(slots)
+}
+
+// reserve takes one of the backend's slots and counts the request in flight
+// until the returned func is called, which hands the slot to the request
+// queued longest for the backend's version. It fails with ResourceExhausted
+// when another request took the last slot since the backend was selected,
+// which fails the request over to another backend.
+func (b *Backend) reserve() (func(), error) {
+	b.mu.RLock()
+	slots := b.slots
+	b.mu.RUnlock()
+	if slots == nil {
+		return b.acquire(), nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+	default:
+		return nil, apperrors.Newf(apperrors.ResourceExhausted, "backend %s is at its limit of %d concurrent requests", b.URL, cap(slots))
+	}
+
+	release := b.acquire()
+	return func() {
+		release()
+		<-slots
+		b.queue.wake()
+	}, nil
+}
+
+// withCapacity returns the backends below their concurrency limit, or all
+// of them when every one is full and the request has to wait
+func withCapacity(backends []*Backend) []*Backend {
+	open := make([]*Backend, 0, len(backends))
+	for _, backend := range backends {
+		if !backend.full() {
+			open = append(open, backend)
+		}
+	}
+	if len(open) == 0 {
+		return backends
+	}
+	return open
+}
@@ -0,0 +1,96 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestBackend_Reserve(t *testing.T) {
+	backend := &Backend{URL: "http://gpu-1:8082"}
+	backend.setLimit(1)
+
+	release, err := backend.reserve()
+	require.NoError(t, err)
+	assert.True(t, backend.full())
+	assert.Equal(t, int64(1), backend.inFlight.Load())
+
+	_, err = backend.reserve()
+	assert.True(t, apperrors.Is(err, apperrors.ResourceExhausted))
+
+	release()
+	assert.False(t, backend.full())
+	assert.Zero(t, backend.inFlight.Load())
+}
+
+func TestBackend_ReserveUnlimited(t *testing.T) {
+	backend := &Backend{URL: "http://gpu-1:8082"}
+	for i := 0; i < 100; i++ {
+		_, err := backend.reserve()
+		require.NoError(t, err)
+	}
+	assert.False(t, backend.full())
+}
+
+func TestSetConcurrencyLimits_PerModel(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.RegisterBackend("llama", "v1", "http://gpu-1:8082")
+	router.SetConcurrencyLimits(8, map[string]int{"llama": 2})
+	router.RegisterBackend("resnet18", "v1", "http://gpu-1:8082")
+
+	limits := make(map[string]int)
+	for _, backend := range router.Backends() {
+		limits[backend.Model] = backend.MaxInFlight
+	}
+	assert.Equal(t, map[string]int{"llama": 2, "resnet18": 8}, limits)
+}
+
+func TestRouteRequest_SpillsOverFullBackends(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.Write([]byte(`{"output": "slow"}`))
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"output": "fast"}`))
+	}))
+	defer fast.Close()
+
+	router := NewModelRouter(zap.NewNop(), slow.URL)
+	router.SetConcurrencyLimits(1, nil)
+	router.SetQueue(0, time.Second)
+	router.RegisterBackend("llama", "v1", slow.URL)
+
+	done := make(chan error)
+	go func() {
+		_, err := router.RouteRequest(context.Background(), "llama", "v1", map[string]interface{}{})
+		done <- err
+	}()
+	<-started
+
+	// The only backend is full, and requests fail at once without a queue
+	_, err := router.RouteRequest(context.Background(), "llama", "v1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.ResourceExhausted))
+
+	// With another backend, requests go to the one with a free slot
+	router.RegisterBackend("llama", "v1", fast.URL)
+	for i := 0; i < 5; i++ {
+		result, err := router.RouteRequest(context.Background(), "llama", "v1", map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, "fast", result["output"])
+	}
+
+	close(release)
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,114 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+// Backend classes, by cost. Backends are GPU backends unless tagged otherwise.
+const (
+	ClassGPU = "gpu"
+	ClassCPU = "cpu"
+)
+
+// DefaultRelaxedDeadline is the least time a request's deadline must leave
+// for it to be served by cheaper backends
+const DefaultRelaxedDeadline = 30 * time.Second
+
+// SetBackendClasses tags backends with their class, by URL, replacing the
+// classes tagged before. Untagged backends are GPU backends.
+func (r *ModelRouter) SetBackendClasses(classes map[string]string) {
+	tagged := make(map[string]string, len(classes))
+	for url, class := range classes {
+		tagged[url] = class
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.classes = tagged
+}
+
+// SetBackendClass tags the backend at url with its class
+func (r *ModelRouter) SetBackendClass(url, class string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.classes == nil {
+		r.classes = make(map[string]string)
+	}
+	r.classes[url] = class
+}
+
+// SetRelaxedDeadline sets the least time a request's deadline must leave for
+// it to be served by cheaper backends; zero routes only batch requests to them
+func (r *ModelRouter) SetRelaxedDeadline(deadline time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.relaxedDeadline = deadline
+}
+
+// classOf returns the class of the backend at url; r.mu must be held
+func (r *ModelRouter) classOf(url string) string {
+	if class, ok := r.classes[url]; ok {
+		return class
+	}
+	return ClassGPU
+}
+
+// latencyInsensitive reports whether a request can wait for a cheaper
+// backend: it comes from a batch job or at low priority, or its deadline
+// leaves at least relaxed
+func latencyInsensitive(ctx context.Context, relaxed time.Duration) bool {
+	fields := logging.FieldsFromContext(ctx)
+	if fields.JobID != "" || fields.Priority == schema.PriorityLow {
+		return true
+	}
+	deadline, ok := ctx.Deadline()
+	return ok && relaxed > 0 && time.Until(deadline) >= relaxed
+}
+
+// byCost returns the backends of the class a request is routed to:
+// latency-insensitive requests go to CPU backends that are below their
+// concurrency limit and answer within the request's deadline on average,
+// and other requests stay on GPU backends. When none qualifies, all of the
+// backends are returned.
+func (r *ModelRouter) byCost(ctx context.Context, backends []*Backend) []*Backend {
+	r.mu.RLock()
+	relaxed := r.relaxedDeadline
+	classes := make([]string, len(backends))
+	cheap := 0
+	for i, backend := range backends {
+		classes[i] = r.classOf(backend.URL)
+		if classes[i] == ClassCPU {
+			cheap++
+		}
+	}
+	r.mu.RUnlock()
+	if cheap == 0 {
+		return backends
+	}
+
+	insensitive := latencyInsensitive(ctx, relaxed)
+	deadline, hasDeadline := ctx.Deadline()
+	matched := make([]*Backend, 0, len(backends))
+	for i, backend := range backends {
+		if insensitive != (classes[i] == ClassCPU) {
+			continue
+		}
+		if insensitive {
+			backend.mu.RLock()
+			latency := backend.AvgLatency
+			backend.mu.RUnlock()
+			if backend.full() || (hasDeadline && time.Until(deadline) < latency) {
+				continue
+			}
+		}
+		matched = append(matched, backend)
+	}
+	if len(matched) == 0 {
+		return backends
+	}
+	return matched
+}
@@ -0,0 +1,64 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/schema"
+)
+
+func TestResolve_ByCost(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.RegisterBackend("llama", "v1", "http://gpu-1:8082")
+	router.RegisterBackend("llama", "v1", "http://cpu-1:8082")
+	router.SetBackendClasses(map[string]string{"http://cpu-1:8082": ClassCPU})
+
+	urls := func(ctx context.Context) []string {
+		_, backends, err := router.resolve(ctx, "llama", "v1")
+		require.NoError(t, err)
+		var urls []string
+		for _, backend := range backends {
+			urls = append(urls, backend.URL)
+		}
+		return urls
+	}
+
+	gpu, cpu := []string{"http://gpu-1:8082"}, []string{"http://cpu-1:8082"}
+	assert.Equal(t, gpu, urls(context.Background()), "interactive requests stay on GPUs")
+
+	tight, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, gpu, urls(tight))
+
+	relaxed, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	assert.Equal(t, cpu, urls(relaxed), "requests with a generous deadline go to CPUs")
+	assert.Equal(t, cpu, urls(logging.WithJobID(context.Background(), "job-1")), "batch requests go to CPUs")
+	assert.Equal(t, cpu, urls(logging.WithPriority(context.Background(), schema.PriorityLow)))
+
+	// CPU backends too slow for the deadline, or full, are passed over
+	backends, err := router.lookup("llama", "v1")
+	require.NoError(t, err)
+	for _, backend := range backends {
+		if backend.URL == "http://cpu-1:8082" {
+			backend.mu.Lock()
+			backend.AvgLatency = 2 * time.Minute
+			backend.mu.Unlock()
+		}
+	}
+	assert.Len(t, urls(relaxed), 2)
+
+	for _, status := range router.Backends() {
+		if status.URL == "http://cpu-1:8082" {
+			assert.Equal(t, ClassCPU, status.Class)
+		} else {
+			assert.Equal(t, ClassGPU, status.Class)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package router
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/observability"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// DefaultFailoverAttempts is how many backends a request is tried on
+const DefaultFailoverAttempts = 3
+
+// SetFailoverAttempts sets how many backends of its version a request is
+// tried on before its failure is returned; 1 disables failover
+func (r *ModelRouter) SetFailoverAttempts(attempts int) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failoverAttempts = attempts
+}
+
+// failoverable reports whether a request that failed with err may be tried
+// on another backend. Only rejections are: an open circuit, an unreachable
+// backend or one shedding load has not run the inference. Timed out requests
+// may still be running and have used up their deadline, and other failures
+// would fail the same way anywhere.
+func failoverable(err error) bool {
+	switch apperrors.CodeOf(err) {
+	case apperrors.Unavailable, apperrors.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+// failover calls try with a backend selected from backends until it
+// succeeds, fails in a way another backend would not help, or the request
+// has been tried on as many backends as allowed. Each backend is tried once.
+// The backends tried are recorded in the audit log, if any.
+func (r *ModelRouter) failover(ctx context.Context, model, version string, backends []*Backend, try func(*Backend) error) error {
+	r.mu.RLock()
+	attempts := r.failoverAttempts
+	r.mu.RUnlock()
+
+	decision := r.newDecision(ctx, model, version, backends)
+	key := affinity(ctx)
+	for attempt := 1; ; attempt++ {
+		backend := r.selectBackend(model, key, backends)
+		tried(decision, backend)
+		err := try(backend)
+		if err == nil || attempt >= attempts || !failoverable(err) || ctx.Err() != nil {
+			r.recordDecision(ctx, decision, backend, err)
+			return err
+		}
+
+		backends = without(backends, backend)
+		if len(backends) == 0 {
+			r.recordDecision(ctx, decision, backend, err)
+			return err
+		}
+		observability.Failovers.WithLabelValues(model, version).Inc()
+		logging.With(ctx, r.logger).Warn("backend failed, failing over",
+			zap.String("model", model),
+			zap.String("version", version),
+			zap.String("backend", backend.URL),
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+		)
+	}
+}
+
+// without returns backends less one, leaving backends itself unchanged
+func without(backends []*Backend, backend *Backend) []*Backend {
+	rest := make([]*Backend, 0, len(backends))
+	for _, b := range backends {
+		if b != backend {
+			rest = append(rest, b)
+		}
+	}
+	return rest
+}
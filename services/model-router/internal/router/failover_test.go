@@ -0,0 +1,114 @@
+package router
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// countingServer answers every request with status and body, counting them
+func countingServer(status int, body string) (*httptest.Server, *atomic.Int64) {
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	return server, &hits
+}
+
+func TestFailoverable(t *testing.T) {
+	assert.True(t, failoverable(apperrors.New(apperrors.Unavailable, "backend down")))
+	assert.True(t, failoverable(apperrors.New(apperrors.ResourceExhausted, "queue full")))
+	assert.False(t, failoverable(apperrors.New(apperrors.DeadlineExceeded, "timed out")))
+	assert.False(t, failoverable(apperrors.New(apperrors.InvalidArgument, "bad input")))
+	assert.False(t, failoverable(apperrors.New(apperrors.Internal, "model crashed")))
+}
+
+func TestRouteRequest_FailsOverToHealthyBackend(t *testing.T) {
+	down, downHits := countingServer(http.StatusServiceUnavailable, `{"error": "draining"}`)
+	defer down.Close()
+	up, upHits := countingServer(http.StatusOK, `{"output": "ok"}`)
+	defer up.Close()
+
+	router := NewModelRouter(zap.NewNop(), up.URL)
+	router.RegisterBackend("resnet18", "v1", down.URL)
+	router.RegisterBackend("resnet18", "v1", up.URL)
+
+	for i := 0; i < 20; i++ {
+		result, err := router.RouteRequest(context.Background(), "resnet18", "v1", map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result["output"])
+	}
+	assert.Equal(t, int64(20), upHits.Load())
+	assert.Greater(t, downHits.Load(), int64(0), "the failing backend was tried")
+}
+
+func TestRouteRequest_DoesNotFailOverCallerErrors(t *testing.T) {
+	a, aHits := countingServer(http.StatusBadRequest, `{"error": "bad input"}`)
+	defer a.Close()
+	b, bHits := countingServer(http.StatusBadRequest, `{"error": "bad input"}`)
+	defer b.Close()
+
+	router := NewModelRouter(zap.NewNop(), a.URL)
+	router.RegisterBackend("resnet18", "v1", a.URL)
+	router.RegisterBackend("resnet18", "v1", b.URL)
+
+	_, err := router.RouteRequest(context.Background(), "resnet18", "v1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+	assert.Equal(t, int64(1), aHits.Load()+bHits.Load())
+}
+
+func TestRouteRequest_BoundsFailoverAttempts(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	var hits []*atomic.Int64
+	for i := 0; i < 5; i++ {
+		server, serverHits := countingServer(http.StatusServiceUnavailable, `{"error": "draining"}`)
+		defer server.Close()
+		hits = append(hits, serverHits)
+		router.RegisterBackend("resnet18", "v1", server.URL)
+	}
+	router.SetFailoverAttempts(2)
+
+	_, err := router.RouteRequest(context.Background(), "resnet18", "v1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable))
+
+	total := int64(0)
+	for _, serverHits := range hits {
+		assert.LessOrEqual(t, serverHits.Load(), int64(1), "each backend is tried once")
+		total += serverHits.Load()
+	}
+	assert.Equal(t, int64(2), total)
+}
+
+func TestRouteStream_FailsOverBeforeStarting(t *testing.T) {
+	down, _ := countingServer(http.StatusServiceUnavailable, `{"error": "draining"}`)
+	defer down.Close()
+	up, _ := countingServer(http.StatusOK, "data: {\"token\": \"hi\"}\n\n")
+	defer up.Close()
+
+	router := NewModelRouter(zap.NewNop(), up.URL)
+	router.RegisterBackend("llama", "v1", down.URL)
+	router.RegisterBackend("llama", "v1", up.URL)
+
+	for i := 0; i < 10; i++ {
+		body, err := router.RouteStream(context.Background(), "llama", "v1", map[string]interface{}{})
+		require.NoError(t, err)
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "hi")
+		body.Close()
+	}
+	for _, backend := range router.Backends() {
+		assert.Zero(t, backend.InFlight, backend.URL)
+	}
+}
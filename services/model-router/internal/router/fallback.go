@@ -0,0 +1,106 @@
+package router
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sony/gobreaker"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/observability"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/traffic"
+)
+
+// ServedByFallbackKey flags the responses served by a fallback model version
+const ServedByFallbackKey = "served_by_fallback"
+
+// SetFallbacks replaces the fallbacks serving model versions' requests while
+// none of their backends can, by model and version
+func (r *ModelRouter) SetFallbacks(fallbacks map[string]map[string]traffic.Fallback) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallbacks = fallbacks
+}
+
+// Fallbacks returns the fallback of each model version that has one,
+// ordered by model and version
+func (r *ModelRouter) Fallbacks() []FallbackStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]FallbackStatus, 0)
+	for model, versions := range r.fallbacks {
+		for version, fallback := range versions {
+			statuses = append(statuses, FallbackStatus{Model: model, Version: version, Fallback: fallback})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Model != statuses[j].Model {
+			return statuses[i].Model < statuses[j].Model
+		}
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses
+}
+
+// FallbackStatus names the fallback of a model version
+type FallbackStatus struct {
+	Model    string           `json:"model"`
+	Version  string           `json:"version"`
+	Fallback traffic.Fallback `json:"fallback"`
+}
+
+func (r *ModelRouter) fallback(model, version string) (traffic.Fallback, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fallback, ok := r.fallbacks[model][version]
+	return fallback, ok
+}
+
+// down reports whether none of backends can serve a request: each is
+// unhealthy, ejected by health checks or has its circuit open, here or on
+// another replica
+func down(backends []*Backend) bool {
+	for _, backend := range backends {
+		backend.mu.RLock()
+		usable := backend.HealthStatus && !backend.ejected && !backend.peerOpen
+		backend.mu.RUnlock()
+		if usable && backend.CircuitBreaker.State() != gobreaker.StateOpen {
+			return false
+		}
+	}
+	return true
+}
+
+// serveFallback answers a request for model/version, which failed with
+// cause, with the version's fallback, flagging the response as served by
+// it. Requests the fallback cannot serve either fail with cause.
+func (r *ModelRouter) serveFallback(ctx context.Context, model, version string, fallback traffic.Fallback, input map[string]interface{}, cause error) (map[string]interface{}, error) {
+	backends, err := r.lookup(fallback.Model, fallback.Version)
+	if err != nil {
+		return nil, cause
+	}
+
+	logging.With(ctx, r.logger).Warn("model version unavailable, serving its fallback",
+		zap.String("model", model),
+		zap.String("version", version),
+		zap.String("fallback", fallback.String()),
+		zap.NamedError("cause", cause),
+	)
+	observability.FallbackRequests.WithLabelValues(model, version, fallback.String()).Inc()
+
+	result, err := r.serve(ctx, fallback.Model, fallback.Version, r.candidates(ctx, backends), input)
+	if err != nil {
+		return nil, err
+	}
+	result[ServedByFallbackKey] = true
+	return result, nil
+}
+
+// unavailable is the error of a request whose model version's backends are
+// all down
+func unavailable(model, version string) error {
+	return apperrors.Newf(apperrors.Unavailable, "every backend of %s/%s is unhealthy or has its circuit open", model, version)
+}
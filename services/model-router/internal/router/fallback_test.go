@@ -0,0 +1,87 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/traffic"
+)
+
+func TestRouteRequest_ServesFallback(t *testing.T) {
+	var primaryCalls atomic.Int64
+	status := atomic.Int64{}
+	status.Store(http.StatusServiceUnavailable)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls.Add(1)
+		w.WriteHeader(int(status.Load()))
+		w.Write([]byte(`{"error": {"code": "unavailable", "message": "out of GPU memory"}}`))
+	}))
+	defer primary.Close()
+	cpu := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"output": "cpu"}`))
+	}))
+	defer cpu.Close()
+
+	router := NewModelRouter(zap.NewNop(), primary.URL)
+	router.RegisterBackend("resnet18", "v1", primary.URL)
+	router.RegisterBackend("resnet18-cpu", "v1", cpu.URL)
+	router.SetFallbacks(map[string]map[string]traffic.Fallback{
+		"resnet18": {"v1": {Model: "resnet18-cpu", Version: "v1"}},
+	})
+
+	result, err := router.RouteRequest(context.Background(), "resnet18", "v1", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "cpu", result["output"])
+	assert.Equal(t, true, result[ServedByFallbackKey])
+	assert.Equal(t, int64(1), primaryCalls.Load())
+
+	// Backends known to be down are not tried at all
+	backends, err := router.lookup("resnet18", "v1")
+	require.NoError(t, err)
+	backends[0].mu.Lock()
+	backends[0].HealthStatus = false
+	backends[0].mu.Unlock()
+	_, err = router.RouteRequest(context.Background(), "resnet18", "v1", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), primaryCalls.Load())
+
+	// Caller mistakes are the caller's to fix
+	backends[0].mu.Lock()
+	backends[0].HealthStatus = true
+	backends[0].mu.Unlock()
+	status.Store(http.StatusBadRequest)
+	_, err = router.RouteRequest(context.Background(), "resnet18", "v1", map[string]interface{}{})
+	assert.Error(t, err)
+	assert.False(t, apperrors.Is(err, apperrors.Unavailable))
+}
+
+func TestRouteRequest_FallbackUnavailable(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	router := NewModelRouter(zap.NewNop(), primary.URL)
+	router.RegisterBackend("resnet18", "v1", primary.URL)
+
+	_, err := router.RouteRequest(context.Background(), "resnet18", "v1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable), "versions without a fallback fail")
+
+	router.SetFallbacks(map[string]map[string]traffic.Fallback{
+		"resnet18": {"v1": {Model: "resnet18-cpu", Version: "v1"}},
+	})
+	_, err = router.RouteRequest(context.Background(), "resnet18", "v1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable), "fallbacks without backends leave the request's error")
+
+	assert.Equal(t, []FallbackStatus{
+		{Model: "resnet18", Version: "v1", Fallback: traffic.Fallback{Model: "resnet18-cpu", Version: "v1"}},
+	}, router.Fallbacks())
+}
@@ -0,0 +1,120 @@
+package router
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/kserve"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// gRPC backends are model servers such as Triton called directly over the
+// KServe v2 gRPC protocol, registered as grpc://host:port, or grpcs://
+// over TLS, rather than inference orchestrators called over HTTP.
+const (
+	grpcScheme  = "grpc://"
+	grpcsScheme = "grpcs://"
+)
+
+// grpcTarget returns the address a gRPC backend URL dials and whether it
+// uses TLS; ok is false for URLs of other backends
+func grpcTarget(url string) (target string, secure, ok bool) {
+	switch {
+	case strings.HasPrefix(url, grpcScheme):
+		return strings.TrimSuffix(strings.TrimPrefix(url, grpcScheme), "/"), false, true
+	case strings.HasPrefix(url, grpcsScheme):
+		return strings.TrimSuffix(strings.TrimPrefix(url, grpcsScheme), "/"), true, true
+	}
+	return "", false, false
+}
+
+// dial creates the gRPC client of a backend with a gRPC URL. Backends whose
+// client cannot be created fail their requests rather than being dropped.
+func (r *ModelRouter) dial(backend *Backend) {
+	target, secure, ok := grpcTarget(backend.URL)
+	if !ok {
+		return
+	}
+	client, err := kserve.Dial(target, secure)
+	if err != nil {
+		r.logger.Warn("failed to create gRPC client for backend", zap.String("backend", backend.URL), zap.Error(err))
+		return
+	}
+	backend.grpc = client
+}
+
+// isGRPC reports whether the backend is called over gRPC
+func (b *Backend) isGRPC() bool {
+	_, _, ok := grpcTarget(b.URL)
+	return ok
+}
+
+// close releases the backend's gRPC connection, if any, once it is removed
+func (b *Backend) close() {
+	if b.grpc != nil {
+		b.grpc.Close()
+	}
+}
+
+// ready asks a gRPC backend's server whether it is ready for requests
+func (b *Backend) ready(ctx context.Context) error {
+	if b.grpc == nil {
+		return apperrors.Newf(apperrors.Unavailable, "no gRPC connection to backend %s", b.URL)
+	}
+	return b.grpc.Ready(ctx)
+}
+
+// executeGRPC runs an inference on a gRPC backend, bounded by the HTTP
+// client's timeout when ctx has no deadline of its own
+func (r *ModelRouter) executeGRPC(ctx context.Context, backend *Backend, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	if backend.grpc == nil {
+		return nil, apperrors.Newf(apperrors.Unavailable, "no gRPC connection to backend %s", backend.URL)
+	}
+
+	r.mu.RLock()
+	authToken := r.authToken
+	timeout := r.client.Timeout
+	r.mu.RUnlock()
+	var token string
+	if authToken != nil {
+		token = authToken()
+	}
+	if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result, err := backend.grpc.Infer(ctx, model, version, input, token)
+	if err != nil {
+		// Unavailable is gRPC's failure to reach the server
+		if apperrors.Is(err, apperrors.Unavailable) {
+			backend.mu.Lock()
+			backend.HealthStatus = false
+			backend.mu.Unlock()
+		}
+		return nil, err
+	}
+
+	backend.mu.Lock()
+	backend.HealthStatus = true
+	backend.LastCheck = time.Now()
+	backend.mu.Unlock()
+
+	return result, nil
+}
+
+// streamable returns the backends that can serve streams: those called over
+// HTTP, as the KServe v2 protocol has no streamed inference
+func streamable(backends []*Backend) []*Backend {
+	http := make([]*Backend, 0, len(backends))
+	for _, backend := range backends {
+		if !backend.isGRPC() {
+			http = append(http, backend)
+		}
+	}
+	return http
+}
@@ -0,0 +1,62 @@
+package router
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestGRPCTarget(t *testing.T) {
+	target, secure, ok := grpcTarget("grpc://triton:8001")
+	assert.Equal(t, "triton:8001", target)
+	assert.False(t, secure)
+	assert.True(t, ok)
+
+	target, secure, ok = grpcTarget("grpcs://triton:8001/")
+	assert.Equal(t, "triton:8001", target)
+	assert.True(t, secure)
+	assert.True(t, ok)
+
+	_, _, ok = grpcTarget("http://gpu-1:8082")
+	assert.False(t, ok)
+}
+
+// closedPort returns the address of a port nothing listens on
+func closedPort(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+	return addr
+}
+
+func TestRouteRequest_GRPCBackend(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.RegisterBackend("resnet18", "v1", "grpc://"+closedPort(t))
+
+	backends, err := router.lookup("resnet18", "v1")
+	require.NoError(t, err)
+	require.NotNil(t, backends[0].grpc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = router.RouteRequest(ctx, "resnet18", "v1", map[string]interface{}{"input": []interface{}{1.0}})
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable), "unreachable servers are unavailable: %v", err)
+	assert.False(t, router.Backends()[0].Healthy)
+
+	_, err = router.RouteStream(context.Background(), "resnet18", "v1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.Unimplemented))
+
+	router.Probe(context.Background())
+	router.Probe(context.Background())
+	assert.True(t, router.Backends()[0].Ejected, "gRPC backends are probed over gRPC")
+
+	assert.True(t, router.UnregisterBackend("resnet18", "v1", backends[0].URL))
+}
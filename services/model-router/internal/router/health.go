@@ -0,0 +1,105 @@
+package router
+
+import (
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// Health of a model version, or of the router as a whole: healthy when
+// every backend is serving, degraded when only some are, unhealthy when none
+// are
+const (
+	Healthy   = "healthy"
+	Degraded  = "degraded"
+	Unhealthy = "unhealthy"
+)
+
+// BackendHealth is the health of one backend of a model version
+type BackendHealth struct {
+	URL string `json:"url"`
+	// Serving is false while the backend fails requests or probes, is
+	// ejected, draining or warming up, or its circuit is open here or on
+	// another router replica
+	Serving      bool      `json:"serving"`
+	Healthy      bool      `json:"healthy"`
+	CircuitState string    `json:"circuit_state"`
+	LastCheck    time.Time `json:"last_check"`
+	AvgLatencyMs int64     `json:"avg_latency_ms"`
+	ErrorRate    float64   `json:"error_rate"`
+}
+
+// VersionHealth is the health of a model version and its backends
+type VersionHealth struct {
+	Status   string          `json:"status"`
+	Backends []BackendHealth `json:"backends"`
+}
+
+// Health returns the health of every model version, keyed "model/version"
+func (r *ModelRouter) Health() map[string]VersionHealth {
+	versions := make(map[string]VersionHealth)
+	for _, status := range r.Backends() {
+		key := status.Model + "/" + status.Version
+		version := versions[key]
+		version.Backends = append(version.Backends, BackendHealth{
+			URL:          status.URL,
+			Serving:      serving(status),
+			Healthy:      status.Healthy,
+			CircuitState: status.CircuitState,
+			LastCheck:    status.LastCheck,
+			AvgLatencyMs: status.AvgLatencyMs,
+			ErrorRate:    status.ErrorRate,
+		})
+		versions[key] = version
+	}
+
+	for key, version := range versions {
+		up := 0
+		for _, backend := range version.Backends {
+			if backend.Serving {
+				up++
+			}
+		}
+		version.Status = rollUp(up, len(version.Backends))
+		versions[key] = version
+	}
+	return versions
+}
+
+// OverallHealth is the router's health given its model versions': degraded
+// when any version is not healthy, unhealthy when none is serving at all. A
+// router with no backends yet is healthy.
+func OverallHealth(versions map[string]VersionHealth) string {
+	up, degraded := 0, false
+	for _, version := range versions {
+		if version.Status != Unhealthy {
+			up++
+		}
+		if version.Status != Healthy {
+			degraded = true
+		}
+	}
+	if len(versions) > 0 && up == 0 {
+		return Unhealthy
+	}
+	if degraded {
+		return Degraded
+	}
+	return Healthy
+}
+
+// serving reports whether a backend is taking its share of requests
+func serving(status BackendStatus) bool {
+	return status.Healthy && !status.Ejected && !status.Outlier && !status.Draining && !status.Warming &&
+		!status.PeerOpen && status.CircuitState != gobreaker.StateOpen.String()
+}
+
+func rollUp(up, total int) string {
+	switch {
+	case up == total:
+		return Healthy
+	case up > 0:
+		return Degraded
+	}
+	return Unhealthy
+}
@@ -0,0 +1,50 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHealth_PerVersion(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	assert.Equal(t, Healthy, OverallHealth(router.Health()), "a router with no backends yet is healthy")
+
+	router.RegisterBackend("llama", "v1", "http://gpu-1:8082")
+	router.RegisterBackend("llama", "v1", "http://gpu-2:8082")
+	router.RegisterBackend("bert", "v1", "http://cpu-1:8082")
+	versions := router.Health()
+	require.Len(t, versions, 2)
+	assert.Equal(t, Healthy, versions["llama/v1"].Status)
+	assert.Equal(t, Healthy, OverallHealth(versions))
+
+	router.mu.RLock()
+	llama, bert := router.backends["llama"]["v1"], router.backends["bert"]["v1"]
+	router.mu.RUnlock()
+	llama[0].mu.Lock()
+	llama[0].ejected = true
+	llama[0].mu.Unlock()
+
+	versions = router.Health()
+	assert.Equal(t, Degraded, versions["llama/v1"].Status)
+	assert.Equal(t, Healthy, versions["bert/v1"].Status)
+	assert.Equal(t, Degraded, OverallHealth(versions))
+	for _, backend := range versions["llama/v1"].Backends {
+		assert.Equal(t, backend.URL != llama[0].URL, backend.Serving)
+		assert.Equal(t, "closed", backend.CircuitState)
+	}
+
+	bert[0].mu.Lock()
+	bert[0].HealthStatus = false
+	bert[0].mu.Unlock()
+	versions = router.Health()
+	assert.Equal(t, Unhealthy, versions["bert/v1"].Status)
+	assert.Equal(t, Degraded, OverallHealth(versions), "llama is still served")
+
+	llama[1].mu.Lock()
+	llama[1].draining = true
+	llama[1].mu.Unlock()
+	assert.Equal(t, Unhealthy, OverallHealth(router.Health()))
+}
@@ -0,0 +1,45 @@
+package router
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	"github.com/yourusername/ai-platform/model-router/internal/observability"
+)
+
+// recordRequest counts a request routed to a backend by its outcome, and
+// how long the backend took to answer it unless latency is zero
+func recordRequest(model, version, url string, latency time.Duration, err error) {
+	status := "success"
+	switch {
+	case errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests):
+		status = "circuit_open"
+	case err != nil && backendFailed(err):
+		status = "failure"
+	case err != nil:
+		status = "error"
+	}
+	observability.BackendRequests.WithLabelValues(model, version, url, status).Inc()
+	if latency > 0 && status != "circuit_open" {
+		observability.BackendLatency.WithLabelValues(model, version, url).Observe(latency.Seconds())
+	}
+}
+
+// recordCircuit publishes a change of a backend's circuit breaker state
+func recordCircuit(model, version, url string, from, to gobreaker.State) {
+	observability.CircuitState.WithLabelValues(model, version, url).Set(float64(to))
+	observability.CircuitTransitions.WithLabelValues(model, version, url, from.String(), to.String()).Inc()
+}
+
+// trackBackend publishes a new backend's circuit breaker as closed
+func trackBackend(model, version, url string) {
+	observability.CircuitState.WithLabelValues(model, version, url).Set(float64(gobreaker.StateClosed))
+}
+
+// forgetBackend stops publishing the circuit breaker state of a backend
+// that was removed
+func forgetBackend(model, version, url string) {
+	observability.CircuitState.DeleteLabelValues(model, version, url)
+}
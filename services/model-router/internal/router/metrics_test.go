@@ -0,0 +1,50 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/observability"
+)
+
+func TestRouteRequest_RecordsMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	router := NewModelRouter(zap.NewNop(), server.URL)
+	router.SetFailoverAttempts(1)
+	router.RegisterBackend("metrics-model", "v1", server.URL)
+	state := observability.CircuitState.WithLabelValues("metrics-model", "v1", server.URL)
+	assert.Equal(t, float64(gobreaker.StateClosed), testutil.ToFloat64(state))
+
+	// Three failures trip the breaker, which turns the next request away
+	for i := 0; i < 4; i++ {
+		router.RouteRequest(context.Background(), "metrics-model", "v1", map[string]interface{}{})
+	}
+
+	requests := func(status string) float64 {
+		return testutil.ToFloat64(observability.BackendRequests.WithLabelValues("metrics-model", "v1", server.URL, status))
+	}
+	assert.Equal(t, 3.0, requests("failure"))
+	assert.Equal(t, 1.0, requests("circuit_open"))
+	assert.Equal(t, float64(gobreaker.StateOpen), testutil.ToFloat64(state))
+	assert.Equal(t, 1.0, testutil.ToFloat64(observability.CircuitTransitions.WithLabelValues("metrics-model", "v1", server.URL, "closed", "open")))
+
+	var latency dto.Metric
+	assert.NoError(t, observability.BackendLatency.WithLabelValues("metrics-model", "v1", server.URL).(prometheus.Metric).Write(&latency))
+	assert.Equal(t, uint64(3), latency.GetHistogram().GetSampleCount(), "requests turned away are not timed")
+
+	router.UnregisterBackend("metrics-model", "v1", server.URL)
+	assert.False(t, observability.CircuitState.DeleteLabelValues("metrics-model", "v1", server.URL), "removed backends' state is not published")
+}
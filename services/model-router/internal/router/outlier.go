@@ -0,0 +1,182 @@
+package router
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/observability"
+)
+
+// Outliers are backends answering worse than the rest of their version:
+// failing requests in a row, or much slower than its other backends. They
+// are ejected from selection for a probation period, then re-admitted with
+// their averages reset. Unlike the circuit breaker, which judges a backend
+// by its own failure ratio, outlier detection compares it with its peers.
+const (
+	OutlierFailures = "consecutive_failures"
+	OutlierLatency  = "latency"
+)
+
+// SetOutlierDetection ejects backends for ejectionTime after failing
+// failures requests in a row, or whose latency average exceeds latencyFactor
+// times the median of their version's backends; zero disables either check.
+// At most half of a version's backends are ejected as outliers at once.
+func (r *ModelRouter) SetOutlierDetection(failures int, latencyFactor float64, ejectionTime time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outlierFailures = failures
+	r.outlierLatencyFactor = latencyFactor
+	r.outlierEjectionTime = ejectionTime
+}
+
+// observe folds a request's outcome into the backend's averages, and ejects
+// the backend once it has failed too many requests in a row
+func (r *ModelRouter) observe(model, version string, backend *Backend, latency time.Duration, failed bool) {
+	failures := backend.observe(latency, failed)
+
+	r.mu.RLock()
+	limit := r.outlierFailures
+	r.mu.RUnlock()
+	if limit > 0 && failures >= limit {
+		r.eject(model, version, backend, OutlierFailures)
+	}
+}
+
+// eject takes an outlier out of selection until its probation ends, unless
+// half of its version's backends are already out
+func (r *ModelRouter) eject(model, version string, backend *Backend, reason string) {
+	r.mu.RLock()
+	siblings := r.backends[model][version]
+	until := r.now().Add(r.outlierEjectionTime)
+	r.mu.RUnlock()
+
+	ejected := 0
+	for _, sibling := range siblings {
+		sibling.mu.RLock()
+		if sibling.outlier {
+			ejected++
+		}
+		sibling.mu.RUnlock()
+	}
+	if 2*(ejected+1) > len(siblings) {
+		return
+	}
+
+	backend.mu.Lock()
+	if backend.outlier {
+		backend.mu.Unlock()
+		return
+	}
+	backend.outlier = true
+	backend.outlierUntil = until
+	backend.failuresInRow = 0
+	backend.mu.Unlock()
+
+	observability.OutlierEjections.WithLabelValues(model, version, backend.URL, reason).Inc()
+	r.logger.Warn("ejecting outlier backend",
+		zap.String("model", model),
+		zap.String("version", version),
+		zap.String("backend", backend.URL),
+		zap.String("reason", reason),
+		zap.Time("until", until),
+	)
+}
+
+// DetectOutliers checks backends' latency against their version's and
+// re-admits outliers whose probation ended every interval until ctx is
+// cancelled
+func (r *ModelRouter) DetectOutliers(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.SweepOutliers()
+		}
+	}
+}
+
+// SweepOutliers re-admits the outliers whose probation ended, then ejects
+// the backends whose latency average exceeds the latency factor times their
+// version's median. Versions with fewer than three measured backends have
+// no meaningful median and are left alone.
+func (r *ModelRouter) SweepOutliers() {
+	r.mu.RLock()
+	now := r.now()
+	factor := r.outlierLatencyFactor
+	versions := make(map[[2]string][]*Backend)
+	for model, byVersion := range r.backends {
+		for version, backends := range byVersion {
+			versions[[2]string{model, version}] = backends
+		}
+	}
+	r.mu.RUnlock()
+
+	for key, backends := range versions {
+		model, version := key[0], key[1]
+		for _, backend := range backends {
+			r.readmit(model, version, backend, now)
+		}
+		if factor <= 0 {
+			continue
+		}
+
+		latencies := make(map[*Backend]time.Duration, len(backends))
+		for _, backend := range backends {
+			backend.mu.RLock()
+			if !backend.outlier && backend.AvgLatency > 0 {
+				latencies[backend] = backend.AvgLatency
+			}
+			backend.mu.RUnlock()
+		}
+		if len(latencies) < 3 {
+			continue
+		}
+		limit := time.Duration(factor * float64(median(latencies)))
+		for backend, latency := range latencies {
+			if latency > limit {
+				r.eject(model, version, backend, OutlierLatency)
+			}
+		}
+	}
+}
+
+// readmit puts an outlier whose probation ended back in selection, with its
+// averages reset so that it is judged on its requests from now on
+func (r *ModelRouter) readmit(model, version string, backend *Backend, now time.Time) {
+	backend.mu.Lock()
+	if !backend.outlier || now.Before(backend.outlierUntil) {
+		backend.mu.Unlock()
+		return
+	}
+	backend.outlier = false
+	backend.outlierUntil = time.Time{}
+	backend.AvgLatency, backend.ErrorRate = 0, 0
+	backend.mu.Unlock()
+
+	r.logger.Info("re-admitting outlier backend after probation",
+		zap.String("model", model),
+		zap.String("version", version),
+		zap.String("backend", backend.URL),
+	)
+}
+
+// median returns the median of latencies
+func median(latencies map[*Backend]time.Duration) time.Duration {
+	sorted := make([]time.Duration, 0, len(latencies))
+	for _, latency := range latencies {
+		sorted = append(sorted, latency)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
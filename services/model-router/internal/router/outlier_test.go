@@ -0,0 +1,117 @@
+package router
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// outlierRouter returns a router whose llama/v1 has n backends, the
+// backends and the router's clock
+func outlierRouter(t *testing.T, n int) (*ModelRouter, []*Backend, *time.Time) {
+	now := time.Now()
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.now = func() time.Time { return now }
+	router.SetOutlierDetection(3, 3, 30*time.Second)
+	for i := 0; i < n; i++ {
+		router.RegisterBackend("llama", "v1", fmt.Sprintf("http://gpu-%d:8082", i+1))
+	}
+	router.mu.RLock()
+	backends := router.backends["llama"]["v1"]
+	router.mu.RUnlock()
+	require.Len(t, backends, n)
+	return router, backends, &now
+}
+
+func outlier(backend *Backend) bool {
+	backend.mu.RLock()
+	defer backend.mu.RUnlock()
+	return backend.outlier
+}
+
+func TestObserve_EjectsAfterConsecutiveFailures(t *testing.T) {
+	router, backends, now := outlierRouter(t, 3)
+
+	router.observe("llama", "v1", backends[0], 0, true)
+	router.observe("llama", "v1", backends[0], 0, true)
+	router.observe("llama", "v1", backends[0], 10*time.Millisecond, false)
+	router.observe("llama", "v1", backends[0], 0, true)
+	router.observe("llama", "v1", backends[0], 0, true)
+	assert.False(t, outlier(backends[0]), "a success resets the count")
+
+	router.observe("llama", "v1", backends[0], 0, true)
+	assert.True(t, outlier(backends[0]))
+	assert.Len(t, inRotation(backends), 2)
+	for _, status := range router.Backends() {
+		assert.Equal(t, status.URL == backends[0].URL, status.Outlier)
+	}
+
+	// Probation ends on the first sweep after the ejection time
+	*now = now.Add(29 * time.Second)
+	router.SweepOutliers()
+	assert.True(t, outlier(backends[0]))
+	*now = now.Add(time.Second)
+	router.SweepOutliers()
+	assert.False(t, outlier(backends[0]))
+	assert.Len(t, inRotation(backends), 3)
+}
+
+func TestObserve_EjectsAtMostHalf(t *testing.T) {
+	router, backends, _ := outlierRouter(t, 2)
+
+	for _, backend := range backends {
+		for i := 0; i < 3; i++ {
+			router.observe("llama", "v1", backend, 0, true)
+		}
+	}
+	assert.True(t, outlier(backends[0]))
+	assert.False(t, outlier(backends[1]), "ejecting both would leave none to compare with")
+}
+
+func TestSweepOutliers_EjectsSlowBackends(t *testing.T) {
+	router, backends, now := outlierRouter(t, 4)
+
+	for i, latency := range []time.Duration{100, 120, 110, 500} {
+		backends[i].observe(latency*time.Millisecond, false)
+	}
+	router.SweepOutliers()
+	assert.False(t, outlier(backends[0]))
+	assert.False(t, outlier(backends[1]))
+	assert.False(t, outlier(backends[2]))
+	assert.True(t, outlier(backends[3]), "500ms is over three times the 115ms median")
+
+	// Re-admitted backends start over rather than being ejected again at once
+	*now = now.Add(time.Minute)
+	router.SweepOutliers()
+	assert.False(t, outlier(backends[3]))
+	backends[3].mu.RLock()
+	assert.Zero(t, backends[3].AvgLatency)
+	backends[3].mu.RUnlock()
+}
+
+func TestSweepOutliers_NeedsEnoughBackends(t *testing.T) {
+	router, backends, _ := outlierRouter(t, 2)
+
+	backends[0].observe(100*time.Millisecond, false)
+	backends[1].observe(time.Second, false)
+	router.SweepOutliers()
+	assert.False(t, outlier(backends[1]), "two backends have no meaningful median")
+}
+
+func TestOutlierDetection_Disabled(t *testing.T) {
+	router, backends, _ := outlierRouter(t, 3)
+	router.SetOutlierDetection(0, 0, 30*time.Second)
+
+	for i := 0; i < 10; i++ {
+		router.observe("llama", "v1", backends[0], 0, true)
+	}
+	backends[1].observe(time.Millisecond, false)
+	backends[2].observe(time.Millisecond, false)
+	backends[0].observe(time.Hour, false)
+	router.SweepOutliers()
+	assert.False(t, outlier(backends[0]))
+}
@@ -0,0 +1,58 @@
+package router
+
+import (
+	"context"
+)
+
+// SetPools dedicates backends to the requests routed to their pool, by pool
+// name: other requests avoid them while other backends remain. Pools left
+// out are released to every request.
+func (r *ModelRouter) SetPools(pools map[string][]string) {
+	byURL := make(map[string]string)
+	for pool, urls := range pools {
+		for _, url := range urls {
+			byURL[url] = pool
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pools = byURL
+}
+
+type poolKey struct{}
+
+// WithPool returns ctx whose requests are served by the backends of pool
+func WithPool(ctx context.Context, pool string) context.Context {
+	return context.WithValue(ctx, poolKey{}, pool)
+}
+
+func poolOf(ctx context.Context) string {
+	pool, _ := ctx.Value(poolKey{}).(string)
+	return pool
+}
+
+// inPool returns the backends of the pool the request is routed to, or the
+// backends in no pool for requests routed to none. When none of the backends
+// qualifies, all of them are returned: a request served by shared or another
+// tenant's capacity beats one that fails.
+func (r *ModelRouter) inPool(ctx context.Context, backends []*Backend) []*Backend {
+	pool := poolOf(ctx)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.pools) == 0 {
+		return backends
+	}
+
+	matched := make([]*Backend, 0, len(backends))
+	for _, backend := range backends {
+		if r.pools[backend.URL] == pool {
+			matched = append(matched, backend)
+		}
+	}
+	if len(matched) == 0 {
+		return backends
+	}
+	return matched
+}
@@ -0,0 +1,42 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestResolve_Pools(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.RegisterBackend("llama", "v1", "http://gpu-1:8082")
+	router.RegisterBackend("llama", "v1", "http://gpu-9:8082")
+	router.RegisterBackend("llama", "v2", "http://gpu-1:8082")
+	router.SetPools(map[string][]string{"acme": {"http://gpu-9:8082"}})
+
+	urls := func(ctx context.Context, version string) []string {
+		_, backends, err := router.resolve(ctx, "llama", version)
+		require.NoError(t, err)
+		var urls []string
+		for _, backend := range backends {
+			urls = append(urls, backend.URL)
+		}
+		return urls
+	}
+
+	acme := WithPool(context.Background(), "acme")
+	assert.Equal(t, []string{"http://gpu-9:8082"}, urls(acme, "v1"))
+	assert.Equal(t, []string{"http://gpu-1:8082"}, urls(context.Background(), "v1"), "dedicated backends serve only their pool")
+	assert.Equal(t, []string{"http://gpu-1:8082"}, urls(acme, "v2"), "pools without backends of the version use shared ones")
+
+	for _, status := range router.Backends() {
+		if status.URL == "http://gpu-9:8082" {
+			assert.Equal(t, "acme", status.Pool)
+		}
+	}
+
+	router.SetPools(nil)
+	assert.Len(t, urls(context.Background(), "v1"), 2)
+}
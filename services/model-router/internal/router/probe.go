@@ -0,0 +1,119 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/health"
+)
+
+// ejectAfter is how many probes in a row a backend fails before it is taken
+// out of selection; one passing probe puts it back
+const ejectAfter = 2
+
+// ProbeBackends checks the readiness of every registered backend every
+// interval until ctx is cancelled
+func (r *ModelRouter) ProbeBackends(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Probe(ctx)
+		}
+	}
+}
+
+// Probe checks the readiness endpoint of each backend URL once, in parallel,
+// or asks gRPC backends whether their server is ready, and records the
+// outcome on every model version the URL serves. Backends failing
+// ejectAfter probes in a row get no requests until one passes.
+func (r *ModelRouter) Probe(ctx context.Context) {
+	r.mu.RLock()
+	byURL := make(map[string][]*Backend)
+	for _, versions := range r.backends {
+		for _, backends := range versions {
+			for _, backend := range backends {
+				byURL[backend.URL] = append(byURL[backend.URL], backend)
+			}
+		}
+	}
+	client := r.client
+	r.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for url, backends := range byURL {
+		wg.Add(1)
+		go func(url string, backends []*Backend) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, health.DefaultTimeout)
+			defer cancel()
+
+			var err error
+			if backends[0].isGRPC() {
+				err = backends[0].ready(probeCtx)
+			} else {
+				err = health.HTTPCheck(client, url+health.ReadinessPath)(probeCtx)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			for _, backend := range backends {
+				r.recordProbe(backend, err)
+			}
+		}(url, backends)
+	}
+	wg.Wait()
+}
+
+// recordProbe updates the backend's health with a probe's outcome
+func (r *ModelRouter) recordProbe(backend *Backend, err error) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	backend.HealthStatus = err == nil
+	backend.LastCheck = r.now()
+	if err == nil {
+		if backend.ejected {
+			r.logger.Info("backend passed its health check, resuming requests", zap.String("backend", backend.URL))
+		}
+		backend.probeFailures, backend.ejected = 0, false
+		return
+	}
+
+	backend.probeFailures++
+	if backend.probeFailures >= ejectAfter && !backend.ejected {
+		backend.ejected = true
+		r.logger.Warn("backend failed its health checks, ejecting it from selection",
+			zap.String("backend", backend.URL),
+			zap.Int("failures", backend.probeFailures),
+			zap.Error(err),
+		)
+	}
+}
+
+// inRotation returns the backends neither warming up, ejected by health
+// checks or as outliers nor with their circuit open on another replica, or
+// all of them when none is: a request to a possibly cold or unhealthy
+// backend beats certain failure
+func inRotation(backends []*Backend) []*Backend {
+	healthy := make([]*Backend, 0, len(backends))
+	for _, backend := range backends {
+		backend.mu.RLock()
+		excluded := backend.ejected || backend.warming || backend.peerOpen || backend.outlier
+		backend.mu.RUnlock()
+		if !excluded {
+			healthy = append(healthy, backend)
+		}
+	}
+	if len(healthy) == 0 {
+		return backends
+	}
+	return healthy
+}
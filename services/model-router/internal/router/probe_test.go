@@ -0,0 +1,94 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/health"
+)
+
+// probedServer answers readiness probes as ready says and inferences with ok
+func probedServer(ready *atomic.Bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == health.ReadinessPath {
+			if !ready.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			return
+		}
+		w.Write([]byte(`{"output": "ok"}`))
+	}))
+}
+
+func TestProbe_EjectsAndRestoresBackends(t *testing.T) {
+	var aReady, bReady atomic.Bool
+	aReady.Store(true)
+	bReady.Store(true)
+	a := probedServer(&aReady)
+	defer a.Close()
+	b := probedServer(&bReady)
+	defer b.Close()
+
+	router := NewModelRouter(zap.NewNop(), a.URL)
+	router.RegisterBackend("resnet18", "v1", a.URL)
+	router.RegisterBackend("resnet18", "v1", b.URL)
+	router.RegisterBackend("bert", "v1", b.URL)
+
+	status := func(model, url string) BackendStatus {
+		for _, backend := range router.Backends() {
+			if backend.Model == model && backend.URL == url {
+				return backend
+			}
+		}
+		t.Fatalf("no backend %s for %s", url, model)
+		return BackendStatus{}
+	}
+
+	bReady.Store(false)
+	router.Probe(context.Background())
+	assert.False(t, status("resnet18", b.URL).Healthy)
+	assert.False(t, status("resnet18", b.URL).Ejected, "one failed probe does not eject")
+
+	router.Probe(context.Background())
+	assert.True(t, status("resnet18", b.URL).Ejected)
+	assert.True(t, status("bert", b.URL).Ejected, "every model version on the URL is ejected")
+	assert.False(t, status("resnet18", a.URL).Ejected)
+
+	backends, err := router.lookup("resnet18", "v1")
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	assert.Equal(t, a.URL, backends[0].URL)
+
+	// A version whose backends are all ejected is still served
+	backends, err = router.lookup("bert", "v1")
+	require.NoError(t, err)
+	assert.Len(t, backends, 1)
+
+	bReady.Store(true)
+	router.Probe(context.Background())
+	assert.True(t, status("resnet18", b.URL).Healthy)
+	assert.False(t, status("resnet18", b.URL).Ejected)
+	backends, err = router.lookup("resnet18", "v1")
+	require.NoError(t, err)
+	assert.Len(t, backends, 2)
+}
+
+func TestProbe_UnreachableBackend(t *testing.T) {
+	var ready atomic.Bool
+	server := probedServer(&ready)
+	server.Close()
+
+	router := NewModelRouter(zap.NewNop(), server.URL)
+	router.RegisterBackend("resnet18", "v1", server.URL)
+	for i := 0; i < ejectAfter; i++ {
+		router.Probe(context.Background())
+	}
+	assert.True(t, router.Backends()[0].Ejected)
+}
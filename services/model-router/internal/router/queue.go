@@ -0,0 +1,174 @@
+package router
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/model-router/internal/observability"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+const (
+	// DefaultQueueDepth is how many requests may wait for each model version
+	DefaultQueueDepth = 100
+
+	// DefaultQueueTimeout is the longest a request waits in its queue
+	DefaultQueueTimeout = time.Second
+)
+
+// SetQueue bounds the queue each model version's requests wait in while all
+// its backends are at their concurrency limit: at most depth requests wait,
+// each for no longer than maxWait
+func (r *ModelRouter) SetQueue(depth int, maxWait time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queueDepth = depth
+	r.queueTimeout = maxWait
+}
+
+// requestQueue holds the requests for a model version waiting for one of its
+// backends to free a slot, oldest first
+type requestQueue struct {
+	model, version string
+
+	mu      sync.Mutex
+	waiters *list.List // of chan struct{}
+}
+
+func newRequestQueue(model, version string) *requestQueue {
+	return &requestQueue{model: model, version: version, waiters: list.New()}
+}
+
+// wake hands a freed slot to the request waiting longest, if any
+func (q *requestQueue) wake() {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if front := q.waiters.Front(); front != nil {
+		q.waiters.Remove(front)
+		close(front.Value.(chan struct{}))
+		observability.QueueDepth.WithLabelValues(q.model, q.version).Set(float64(q.waiters.Len()))
+	}
+}
+
+// wait blocks until a slot is handed to the request, or fails once depth
+// requests are already waiting, after maxWait, or when ctx ends. Requests
+// that lost a freed slot to a newcomer wait again at the front.
+func (q *requestQueue) wait(ctx context.Context, depth int, maxWait time.Duration, front bool) error {
+	q.mu.Lock()
+	if q.waiters.Len() >= depth && !front {
+		q.mu.Unlock()
+		return apperrors.Newf(apperrors.ResourceExhausted, "%d requests for %s/%s are already queued", depth, q.model, q.version)
+	}
+	woken := make(chan struct{})
+	var element *list.Element
+	if front {
+		element = q.waiters.PushFront(woken)
+	} else {
+		element = q.waiters.PushBack(woken)
+	}
+	observability.QueueDepth.WithLabelValues(q.model, q.version).Set(float64(q.waiters.Len()))
+	q.mu.Unlock()
+
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+	select {
+	case <-woken:
+		return nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	select {
+	case <-woken:
+		// Woken as it gave up; the slot is taken rather than lost
+		return nil
+	default:
+	}
+	q.waiters.Remove(element)
+	observability.QueueDepth.WithLabelValues(q.model, q.version).Set(float64(q.waiters.Len()))
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return apperrors.Newf(apperrors.ResourceExhausted, "no backend of %s/%s freed up within %s", q.model, q.version, maxWait)
+}
+
+// queue returns the queue of a model version; r.mu must be held for writing
+func (r *ModelRouter) queue(model, version string) *requestQueue {
+	key := model + "/" + version
+	q, ok := r.queues[key]
+	if !ok {
+		q = newRequestQueue(model, version)
+		r.queues[key] = q
+	}
+	return q
+}
+
+// admit holds a request while every one of its backends is at its
+// concurrency limit, until one frees a slot. It waits no longer than the
+// request's deadline leaves for the backend to answer, judged by the
+// fastest backend's latency, so requests that could not be answered in time
+// fail at once rather than after queueing.
+func (r *ModelRouter) admit(ctx context.Context, model, version string, backends []*Backend) error {
+	if !allFull(backends) {
+		return nil
+	}
+
+	r.mu.RLock()
+	depth, maxWait := r.queueDepth, r.queueTimeout
+	r.mu.RUnlock()
+	q := backends[0].queue
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if budget := time.Until(deadline) - fastest(backends); budget < maxWait {
+			maxWait = budget
+		}
+	}
+
+	start := time.Now()
+	for front := false; allFull(backends); front = true {
+		remaining := maxWait - time.Since(start)
+		if q == nil || depth <= 0 || remaining <= 0 {
+			observability.ConcurrencyRejections.WithLabelValues(model, version).Inc()
+			return apperrors.Newf(apperrors.ResourceExhausted, "every backend of %s/%s is at its concurrency limit", model, version)
+		}
+		if err := q.wait(ctx, depth, remaining, front); err != nil {
+			if apperrors.Is(err, apperrors.ResourceExhausted) {
+				observability.ConcurrencyRejections.WithLabelValues(model, version).Inc()
+			}
+			return err
+		}
+	}
+	observability.QueueWait.WithLabelValues(model, version).Observe(time.Since(start).Seconds())
+	return nil
+}
+
+// allFull reports whether every backend is at its concurrency limit
+func allFull(backends []*Backend) bool {
+	for _, backend := range backends {
+		if !backend.full() {
+			return false
+		}
+	}
+	return true
+}
+
+// fastest returns the lowest latency average among backends
+func fastest(backends []*Backend) time.Duration {
+	var best time.Duration
+	for i, backend := range backends {
+		backend.mu.RLock()
+		latency := backend.AvgLatency
+		backend.mu.RUnlock()
+		if i == 0 || latency < best {
+			best = latency
+		}
+	}
+	return best
+}
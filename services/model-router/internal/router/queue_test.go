@@ -0,0 +1,139 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func queued(q *requestQueue) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.waiters.Len()
+}
+
+func TestRequestQueue_WakesOldestFirst(t *testing.T) {
+	q := newRequestQueue("llama", "v1")
+
+	order := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		go func(i int) {
+			if q.wait(context.Background(), 10, time.Second, false) == nil {
+				order <- i
+			}
+		}(i)
+		require.Eventually(t, func() bool { return queued(q) == i+1 }, time.Second, time.Millisecond)
+	}
+
+	for i := 0; i < 3; i++ {
+		q.wake()
+		assert.Equal(t, i, <-order)
+	}
+}
+
+func TestRequestQueue_Rejects(t *testing.T) {
+	q := newRequestQueue("llama", "v1")
+
+	go q.wait(context.Background(), 1, time.Second, false)
+	require.Eventually(t, func() bool { return queued(q) == 1 }, time.Second, time.Millisecond)
+
+	err := q.wait(context.Background(), 1, time.Second, false)
+	assert.True(t, apperrors.Is(err, apperrors.ResourceExhausted), "requests beyond the depth are rejected")
+
+	err = q.wait(context.Background(), 2, 10*time.Millisecond, false)
+	assert.True(t, apperrors.Is(err, apperrors.ResourceExhausted), "requests give up after waiting maxWait")
+	assert.Equal(t, 1, queued(q))
+	q.wake()
+}
+
+// saturated returns a router whose only llama backend is serving a request
+// that blocks until the returned func is called
+func saturated(t *testing.T) (*ModelRouter, func()) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+			<-release
+		default:
+		}
+		w.Write([]byte(`{"output": "ok"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	router := NewModelRouter(zap.NewNop(), server.URL)
+	router.SetConcurrencyLimits(1, nil)
+	router.RegisterBackend("llama", "v1", server.URL)
+
+	done := make(chan error)
+	go func() {
+		_, err := router.RouteRequest(context.Background(), "llama", "v1", map[string]interface{}{})
+		done <- err
+	}()
+	<-started
+
+	return router, func() {
+		close(release)
+		assert.NoError(t, <-done)
+	}
+}
+
+func TestRouteRequest_QueuesForFreeSlot(t *testing.T) {
+	router, finish := saturated(t)
+	router.SetQueue(10, 5*time.Second)
+
+	result := make(chan error)
+	go func() {
+		_, err := router.RouteRequest(context.Background(), "llama", "v1", map[string]interface{}{})
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		t.Fatalf("request finished before a slot was free: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	finish()
+	assert.NoError(t, <-result)
+}
+
+func TestRouteRequest_QueueTimeout(t *testing.T) {
+	router, finish := saturated(t)
+	defer finish()
+	router.SetQueue(10, 20*time.Millisecond)
+
+	start := time.Now()
+	_, err := router.RouteRequest(context.Background(), "llama", "v1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.ResourceExhausted))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestRouteRequest_FailsFastPastDeadline(t *testing.T) {
+	router, finish := saturated(t)
+	defer finish()
+	router.SetQueue(10, 5*time.Second)
+
+	backends, err := router.lookup("llama", "v1")
+	require.NoError(t, err)
+	backends[0].mu.Lock()
+	backends[0].AvgLatency = time.Second
+	backends[0].mu.Unlock()
+
+	// The backend takes longer to answer than the deadline leaves
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = router.RouteRequest(ctx, "llama", "v1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.ResourceExhausted))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
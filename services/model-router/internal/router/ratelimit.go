@@ -0,0 +1,145 @@
+package router
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/model-router/internal/observability"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// SetRateLimits caps the requests per second sent to each backend URL, over
+// every model it serves, so that one hot model cannot overwhelm a server
+// such as Triton shared with others: to perBackend's ceiling for its URLs
+// and defaultRate for others, zero meaning no ceiling. Bursts of up to a
+// second's worth of requests pass. Requests beyond the ceiling go to another
+// backend, or fail with ResourceExhausted when none has room.
+func (r *ModelRouter) SetRateLimits(defaultRate float64, perBackend map[string]float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.defaultRate = defaultRate
+	r.rates = perBackend
+	r.limiters = make(map[string]*rateLimiter)
+	for _, versions := range r.backends {
+		for _, backends := range versions {
+			for _, backend := range backends {
+				backend.setRateLimiter(r.rateLimiter(backend.URL))
+			}
+		}
+	}
+}
+
+// rateLimiter returns the limiter shared by the backends at url, nil when
+// their rate is not limited; r.mu must be held for writing
+func (r *ModelRouter) rateLimiter(url string) *rateLimiter {
+	rate, ok := r.rates[url]
+	if !ok {
+		rate = r.defaultRate
+	}
+	if rate <= 0 {
+		return nil
+	}
+	if limiter, ok := r.limiters[url]; ok {
+		return limiter
+	}
+	if r.limiters == nil {
+		r.limiters = make(map[string]*rateLimiter)
+	}
+	limiter := newRateLimiter(rate, r.now())
+	r.limiters[url] = limiter
+	return limiter
+}
+
+// setRateLimiter replaces the limiter of the backend's request rate
+func (b *Backend) setRateLimiter(limiter *rateLimiter) {
+	b.mu.Lock()
+	b.limiter = limiter
+	b.mu.Unlock()
+}
+
+// rateLimit returns the ceiling of the backend's request rate, zero when
+// not capped; b.mu must be held
+func (b *Backend) rateLimit() float64 {
+	if b.limiter == nil {
+		return 0
+	}
+	return b.limiter.rate
+}
+
+// throttle takes one of the backend's requests per second, failing with
+// ResourceExhausted when it is at its ceiling
+func (r *ModelRouter) throttle(model, version string, backend *Backend) error {
+	backend.mu.RLock()
+	limiter := backend.limiter
+	backend.mu.RUnlock()
+	if limiter == nil || limiter.allow(r.now()) {
+		return nil
+	}
+	observability.BackendRequests.WithLabelValues(model, version, backend.URL, "rate_limited").Inc()
+	return apperrors.Newf(apperrors.ResourceExhausted, "backend %s is at its limit of %g requests per second", backend.URL, limiter.rate)
+}
+
+// underRate returns the backends below their request rate ceiling, or all
+// of them when every one is at it
+func (r *ModelRouter) underRate(backends []*Backend) []*Backend {
+	now := r.now()
+	open := make([]*Backend, 0, len(backends))
+	for _, backend := range backends {
+		backend.mu.RLock()
+		limiter := backend.limiter
+		backend.mu.RUnlock()
+		if limiter == nil || limiter.available(now) {
+			open = append(open, backend)
+		}
+	}
+	if len(open) == 0 {
+		return backends
+	}
+	return open
+}
+
+// rateLimiter is a token bucket refilled at rate tokens per second, holding
+// up to a second's worth
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, now time.Time) *rateLimiter {
+	burst := math.Max(rate, 1)
+	return &rateLimiter{rate: rate, burst: burst, tokens: burst, last: now}
+}
+
+// refill adds the tokens accrued since the last refill; l.mu must be held
+func (l *rateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+elapsed.Seconds()*l.rate)
+		l.last = now
+	}
+}
+
+// allow takes a token if one is left
+func (l *rateLimiter) allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(now)
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// available reports whether a token is left, without taking it
+func (l *rateLimiter) available(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(now)
+	return l.tokens >= 1
+}
@@ -0,0 +1,92 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestRateLimiter_Refills(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiter(2, now)
+
+	assert.True(t, limiter.allow(now))
+	assert.True(t, limiter.allow(now))
+	assert.False(t, limiter.available(now), "bursts are capped at a second's worth")
+	assert.False(t, limiter.allow(now))
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, limiter.allow(now))
+	assert.False(t, limiter.allow(now))
+
+	now = now.Add(time.Hour)
+	assert.True(t, limiter.allow(now))
+	assert.True(t, limiter.allow(now))
+	assert.False(t, limiter.allow(now), "idle time does not build up a larger burst")
+}
+
+func TestRouteRequest_RateLimitedBackend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"output": "ok"}`))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	router := NewModelRouter(zap.NewNop(), server.URL)
+	router.now = func() time.Time { return now }
+	router.SetRateLimits(0, map[string]float64{server.URL: 2})
+	router.RegisterBackend("llama", "v1", server.URL)
+	router.RegisterBackend("bert", "v1", server.URL)
+
+	// The ceiling holds over every model the backend serves
+	_, err := router.RouteRequest(context.Background(), "llama", "v1", map[string]interface{}{})
+	require.NoError(t, err)
+	_, err = router.RouteRequest(context.Background(), "bert", "v1", map[string]interface{}{})
+	require.NoError(t, err)
+	_, err = router.RouteRequest(context.Background(), "llama", "v1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.ResourceExhausted), "requests over the ceiling are shed")
+
+	now = now.Add(time.Second)
+	_, err = router.RouteRequest(context.Background(), "bert", "v1", map[string]interface{}{})
+	assert.NoError(t, err)
+
+	for _, status := range router.Backends() {
+		assert.Equal(t, 2.0, status.RateLimit)
+	}
+}
+
+func TestRouteRequest_SpillsOverRateLimitedBackends(t *testing.T) {
+	hot := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"output": "hot"}`))
+	}))
+	defer hot.Close()
+	spare := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"output": "spare"}`))
+	}))
+	defer spare.Close()
+
+	now := time.Now()
+	router := NewModelRouter(zap.NewNop(), hot.URL)
+	router.now = func() time.Time { return now }
+	router.RegisterBackend("llama", "v1", hot.URL)
+	router.RegisterBackend("llama", "v1", spare.URL)
+	router.SetRateLimits(1, map[string]float64{spare.URL: 0})
+	router.SetFailoverAttempts(1)
+
+	outputs := make(map[interface{}]int)
+	for i := 0; i < 10; i++ {
+		result, err := router.RouteRequest(context.Background(), "llama", "v1", map[string]interface{}{})
+		require.NoError(t, err)
+		outputs[result["output"]]++
+	}
+	assert.LessOrEqual(t, outputs["hot"], 1, "requests go to backends below their ceiling")
+	assert.GreaterOrEqual(t, outputs["spare"], 9)
+}
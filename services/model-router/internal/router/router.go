@@ -4,15 +4,26 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"math/rand"
 	"net/http"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sony/gobreaker"
 	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/audit"
+	"github.com/yourusername/ai-platform/model-router/internal/kserve"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/scaling"
+	"github.com/yourusername/ai-platform/pkg/sse"
+	"github.com/yourusername/ai-platform/pkg/traffic"
 )
 
 // Backend represents a model serving backend
@@ -21,8 +32,69 @@ type Backend struct {
 	CircuitBreaker *gobreaker.CircuitBreaker
 	HealthStatus   bool
 	LastCheck      time.Time
-	AvgLatency     time.Duration
-	mu             sync.RWMutex
+	// AvgLatency and ErrorRate are moving averages of the backend's recent
+	// requests, which selectBackend favours fast and healthy backends by
+	AvgLatency time.Duration
+	ErrorRate  float64
+	mu         sync.RWMutex
+
+	// grpc calls backends with grpc:// or grpcs:// URLs; nil for HTTP backends
+	grpc *kserve.Client
+
+	// inFlight counts the requests and open streams routed to the backend
+	inFlight atomic.Int64
+	// slots holds a token per request in flight when the backend's
+	// concurrency is limited; nil otherwise. Guarded by mu. Freed slots go
+	// to the requests waiting in queue, shared by the version's backends.
+	slots chan struct{}
+	queue *requestQueue
+
+	// limiter caps the requests per second sent to the backend's URL, shared
+	// by the backends of every model it serves; nil when not capped. Guarded
+	// by mu.
+	limiter *rateLimiter
+
+	// probeFailures counts the health checks failed in a row; backends
+	// failing enough of them are ejected from selection until one passes.
+	// Both are guarded by mu.
+	probeFailures int
+	ejected       bool
+
+	// draining backends get no new requests; guarded by mu
+	draining bool
+
+	// failuresInRow counts the requests the backend failed in a row;
+	// outliers are out of selection until outlierUntil. Guarded by mu.
+	failuresInRow int
+	outlier       bool
+	outlierUntil  time.Time
+
+	// peerOpen is set while another router replica has the backend's circuit
+	// open, keeping it out of rotation here too; guarded by mu
+	peerOpen bool
+
+	// warming backends are being sent warm-up requests and get no others
+	// while warm backends remain; guarded by mu
+	warming bool
+
+	// manual backends were added through the admin API and are kept when
+	// the table is reloaded; guarded by the router's mutex
+	manual bool
+
+	// leaseExpires is when a backend registered by heartbeat stops being
+	// routed to unless renewed; zero for backends without a lease. It is
+	// guarded by the router's mutex.
+	leaseExpires time.Time
+}
+
+// leased reports whether the backend is kept by heartbeats
+func (b *Backend) leased() bool {
+	return !b.leaseExpires.IsZero()
+}
+
+// expired reports whether the backend's lease ended before now
+func (b *Backend) expired(now time.Time) bool {
+	return b.leased() && !now.Before(b.leaseExpires)
 }
 
 // ModelRouter handles intelligent routing of inference requests
@@ -31,6 +103,78 @@ type ModelRouter struct {
 	backends map[string]map[string][]*Backend // model -> version -> backends
 	mu       sync.RWMutex
 	client   *http.Client
+	load     *scaling.Tracker
+	events   *events.Emitter
+	auditLog *audit.Log // Records how each request was routed; nil when not audited
+	now      func() time.Time
+
+	// strategies holds the routing strategy of models that do not use
+	// defaultStrategy
+	defaultStrategy Strategy
+	strategies      map[string]Strategy
+
+	// splits canary model versions, by model
+	splits map[string]traffic.Split
+
+	// aliases name model versions, by model and alias
+	aliases map[string]map[string]traffic.Alias
+
+	// pools names the pool of backends dedicated to some requests, by URL
+	pools map[string]string
+
+	// classes tags backends by cost class, by URL; requests whose deadline
+	// leaves at least relaxedDeadline, or from batch jobs, go to cheaper
+	// backends
+	classes         map[string]string
+	relaxedDeadline time.Duration
+
+	// fallbacks serve model versions' requests while none of their backends
+	// can, by model and version
+	fallbacks map[string]map[string]traffic.Fallback
+
+	// failoverAttempts is how many backends a request is tried on
+	failoverAttempts int
+
+	// concurrency limits the requests in flight per backend of models that
+	// do not use defaultConcurrency
+	defaultConcurrency int
+	concurrency        map[string]int
+
+	// queues hold the requests waiting for a model version's backends to
+	// free a slot, by "model/version": up to queueDepth each, for up to
+	// queueTimeout
+	queues       map[string]*requestQueue
+	queueDepth   int
+	queueTimeout time.Duration
+
+	// rates caps the requests per second sent to backends, by URL, and
+	// defaultRate those of URLs not in it; limiters holds the token bucket
+	// of each capped URL
+	defaultRate float64
+	rates       map[string]float64
+	limiters    map[string]*rateLimiter
+
+	// Backends failing outlierFailures requests in a row, or slower than
+	// outlierLatencyFactor times their version's median, are ejected for
+	// outlierEjectionTime; zero disables either check
+	outlierFailures      int
+	outlierLatencyFactor float64
+	outlierEjectionTime  time.Duration
+
+	// warmupInputs holds the sample input new backends of a model version
+	// are warmed up with, by model and version; warmupRequests of them must
+	// succeed, the last within warmupMaxLatency
+	warmupInputs     map[string]map[string]map[string]interface{}
+	warmupRequests   int
+	warmupMaxLatency time.Duration
+
+	// authToken returns the bearer token sent to backends, if any
+	authToken func() string
+
+	// stateStore shares the state of backends with the other router
+	// replicas, publishing this one's view as replica; nil when not shared
+	stateStore StateStore
+	replica    string
 }
 
 // NewModelRouter creates a new model router
@@ -41,6 +185,16 @@ func NewModelRouter(logger *zap.Logger, defaultURL string) *ModelRouter {
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		load:             scaling.NewTracker("model-router", scaling.DefaultRateWindow),
+		now:              time.Now,
+		defaultStrategy:  LatencyAware,
+		failoverAttempts: DefaultFailoverAttempts,
+		queues:           make(map[string]*requestQueue),
+		queueDepth:       DefaultQueueDepth,
+		queueTimeout:     DefaultQueueTimeout,
+		warmupRequests:   DefaultWarmupRequests,
+		warmupMaxLatency: DefaultWarmupMaxLatency,
+		relaxedDeadline:  DefaultRelaxedDeadline,
 	}
 }
 
@@ -53,6 +207,225 @@ func (r *ModelRouter) RegisterBackend(model, version, url string) {
 		r.backends[model] = make(map[string][]*Backend)
 	}
 
+	r.backends[model][version] = append(r.backends[model][version], r.newBackend(model, version, url))
+	r.logger.Info("registered backend",
+		zap.String("model", model),
+		zap.String("version", version),
+		zap.String("url", url),
+	)
+}
+
+// SyncBackends replaces the routing table with table, which maps models to
+// versions to backend URLs. Backends already registered keep their circuit
+// breaker and latency; others are added, and those missing from table are
+// dropped unless they hold a lease or were added through the admin API. It
+// returns how many backends were added and removed.
+func (r *ModelRouter) SyncBackends(table map[string]map[string][]string) (added, removed int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	backends := make(map[string]map[string][]*Backend, len(table))
+	for model, versions := range table {
+		for version, urls := range versions {
+			existing := make(map[string]*Backend)
+			for _, backend := range r.backends[model][version] {
+				existing[backend.URL] = backend
+			}
+
+			var synced []*Backend
+			seen := make(map[string]bool, len(urls))
+			for _, url := range urls {
+				// Listing a URL twice does not double its share of requests
+				if seen[url] {
+					continue
+				}
+				seen[url] = true
+
+				backend, ok := existing[url]
+				if !ok {
+					backend = r.newBackend(model, version, url)
+					added++
+					r.logger.Info("registered backend",
+						zap.String("model", model),
+						zap.String("version", version),
+						zap.String("url", url),
+					)
+				}
+				synced = append(synced, backend)
+			}
+			if len(synced) == 0 {
+				continue
+			}
+			if backends[model] == nil {
+				backends[model] = make(map[string][]*Backend)
+			}
+			backends[model][version] = synced
+		}
+	}
+
+	for model, versions := range r.backends {
+		for version, current := range versions {
+			for _, backend := range current {
+				if contains(backends[model][version], backend) {
+					continue
+				}
+				// Leased backends are kept by their heartbeats, and manual ones
+				// until removed
+				if backend.leased() || backend.manual {
+					if backends[model] == nil {
+						backends[model] = make(map[string][]*Backend)
+					}
+					backends[model][version] = append(backends[model][version], backend)
+				} else {
+					removed++
+					forgetBackend(model, version, backend.URL)
+					backend.close()
+					r.logger.Info("removed backend",
+						zap.String("model", model),
+						zap.String("version", version),
+						zap.String("url", backend.URL),
+					)
+				}
+			}
+		}
+	}
+
+	r.backends = backends
+	return added, removed
+}
+
+// DefaultLeaseTTL is how long a backend registered by heartbeat is routed
+// to without renewing its lease
+const DefaultLeaseTTL = 30 * time.Second
+
+// RenewBackend registers a backend for a model version, or renews the one
+// registered at url, leased until ttl from now. Backends not renewed in time
+// stop receiving requests and are dropped by ExpireBackends. It reports
+// whether the backend was newly registered.
+func (r *ModelRouter) RenewBackend(model, version, url string, ttl time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expires := r.now().Add(ttl)
+	for _, backend := range r.backends[model][version] {
+		if backend.URL == url {
+			backend.leaseExpires = expires
+			return false
+		}
+	}
+
+	if r.backends[model] == nil {
+		r.backends[model] = make(map[string][]*Backend)
+	}
+	backend := r.newBackend(model, version, url)
+	backend.leaseExpires = expires
+	r.backends[model][version] = append(r.backends[model][version], backend)
+	r.logger.Info("registered leased backend",
+		zap.String("model", model),
+		zap.String("version", version),
+		zap.String("url", url),
+		zap.Duration("ttl", ttl),
+	)
+	return true
+}
+
+// UnregisterBackend removes the backend at url from a model version. It
+// reports whether one was registered.
+func (r *ModelRouter) UnregisterBackend(model, version, url string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := r.removeBackends(model, version, func(backend *Backend) bool {
+		return backend.URL == url
+	})
+	if removed == 0 {
+		return false
+	}
+	r.logger.Info("unregistered backend",
+		zap.String("model", model),
+		zap.String("version", version),
+		zap.String("url", url),
+	)
+	return true
+}
+
+// ExpireBackends drops backends whose lease ended before now and returns
+// how many were dropped
+func (r *ModelRouter) ExpireBackends(now time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expired := 0
+	for model, versions := range r.backends {
+		for version := range versions {
+			expired += r.removeBackends(model, version, func(backend *Backend) bool {
+				if !backend.expired(now) {
+					return false
+				}
+				r.logger.Warn("backend lease expired",
+					zap.String("model", model),
+					zap.String("version", version),
+					zap.String("url", backend.URL),
+				)
+				return true
+			})
+		}
+	}
+	return expired
+}
+
+// ExpireLeases drops backends whose lease lapsed every interval until ctx
+// is cancelled
+func (r *ModelRouter) ExpireLeases(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.ExpireBackends(now)
+		}
+	}
+}
+
+// removeBackends drops the backends of a model version matching remove,
+// forgetting versions and models left without any; r.mu must be held
+func (r *ModelRouter) removeBackends(model, version string, remove func(*Backend) bool) int {
+	current := r.backends[model][version]
+	kept := make([]*Backend, 0, len(current))
+	for _, backend := range current {
+		if remove(backend) {
+			forgetBackend(model, version, backend.URL)
+			backend.close()
+		} else {
+			kept = append(kept, backend)
+		}
+	}
+
+	if len(kept) > 0 {
+		r.backends[model][version] = kept
+	} else if len(current) > 0 {
+		delete(r.backends[model], version)
+		if len(r.backends[model]) == 0 {
+			delete(r.backends, model)
+		}
+	}
+	return len(current) - len(kept)
+}
+
+func contains(backends []*Backend, backend *Backend) bool {
+	for _, b := range backends {
+		if b == backend {
+			return true
+		}
+	}
+	return false
+}
+
+// newBackend creates a backend for a model version with its own circuit breaker
+func (r *ModelRouter) newBackend(model, version, url string) *Backend {
 	cb := gobreaker.NewCircuitBreaker(gobreaker.Settings{
 		Name:        fmt.Sprintf("%s-%s", model, version),
 		MaxRequests: 3,
@@ -62,7 +435,18 @@ func (r *ModelRouter) RegisterBackend(model, version, url string) {
 			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
 			return counts.Requests >= 3 && failureRatio >= 0.6
 		},
+		// Caller mistakes say nothing about backend health
+		IsSuccessful: func(err error) bool {
+			return !backendFailed(err)
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			recordCircuit(model, version, url, from, to)
+			if to == gobreaker.StateOpen {
+				r.circuitOpened(model, version, url)
+			}
+		},
 	})
+	trackBackend(model, version, url)
 
 	backend := &Backend{
 		URL:            url,
@@ -70,55 +454,399 @@ func (r *ModelRouter) RegisterBackend(model, version, url string) {
 		HealthStatus:   true,
 		LastCheck:      time.Now(),
 	}
+	r.dial(backend)
+	backend.setLimit(r.concurrencyLimit(model))
+	backend.setRateLimiter(r.rateLimiter(url))
+	backend.queue = r.queue(model, version)
+	r.startWarmup(model, version, backend)
+	return backend
+}
 
-	r.backends[model][version] = append(r.backends[model][version], backend)
-	r.logger.Info("registered backend",
+// SetHTTPClient replaces the client used to call backends, e.g. with an mTLS client
+func (r *ModelRouter) SetHTTPClient(client *http.Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.client = client
+}
+
+// SetAuthToken sets the source of the bearer token attached to backend requests
+func (r *ModelRouter) SetAuthToken(token func() string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.authToken = token
+}
+
+// SetStrategies sets the routing strategy of every model, overridden for the
+// models in perModel
+func (r *ModelRouter) SetStrategies(defaultStrategy Strategy, perModel map[string]Strategy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultStrategy = defaultStrategy
+	r.strategies = perModel
+}
+
+// SetEventEmitter sets where tripped circuit breakers are announced
+func (r *ModelRouter) SetEventEmitter(emitter *events.Emitter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = emitter
+}
+
+func (r *ModelRouter) circuitOpened(model, version, url string) {
+	r.logger.Warn("circuit breaker opened",
 		zap.String("model", model),
 		zap.String("version", version),
 		zap.String("url", url),
 	)
+
+	r.mu.RLock()
+	emitter := r.events
+	r.mu.RUnlock()
+	emitter.Emit(context.Background(), events.Event{
+		Type:     events.CircuitOpened,
+		Severity: events.SeverityCritical,
+		Subject:  model + "/" + version,
+		Attributes: map[string]string{
+			"model":   model,
+			"version": version,
+			"url":     url,
+		},
+	})
 }
 
-// RouteRequest routes an inference request to the appropriate backend
+// RouteRequest routes an inference request to the appropriate backend. A
+// traffic split on the model may serve it with another version. Requests
+// wait in queue while every backend is at its concurrency limit, and those a
+// backend rejects fail over to another backend of the version. While every
+// backend of the version is unhealthy or has its circuit open, or none is
+// available, requests are served by the version's fallback, if it has one.
 func (r *ModelRouter) RouteRequest(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	version, backends, err := r.resolve(ctx, model, version)
+	fallback, hasFallback := r.fallback(model, version)
+	if hasFallback {
+		if err == nil && down(backends) {
+			err = unavailable(model, version)
+		}
+		if apperrors.Is(err, apperrors.Unavailable) {
+			return r.serveFallback(ctx, model, version, fallback, input, err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.serve(ctx, model, version, backends, input)
+	if hasFallback && apperrors.Is(err, apperrors.Unavailable) {
+		return r.serveFallback(ctx, model, version, fallback, input, err)
+	}
+	return result, err
+}
+
+// serve sends a request for model/version to one of its backends
+func (r *ModelRouter) serve(ctx context.Context, model, version string, backends []*Backend, input map[string]interface{}) (map[string]interface{}, error) {
+	defer r.load.Start(model, version)()
+	if err := r.admit(ctx, model, version, backends); err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	err := r.failover(ctx, model, version, backends, func(backend *Backend) error {
+		if err := r.throttle(model, version, backend); err != nil {
+			return err
+		}
+		release, err := backend.reserve()
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		// Execute request through circuit breaker
+		start := time.Now()
+		spanCtx, span := startSpan(ctx, "backend.infer", model, version, backend)
+		response, err := backend.CircuitBreaker.Execute(func() (interface{}, error) {
+			return r.executeRequest(spanCtx, backend, model, version, input)
+		})
+		endSpan(span, err)
+		recordRequest(model, version, backend.URL, time.Since(start), err)
+
+		if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+			return apperrors.Wrap(err, apperrors.Unavailable, fmt.Sprintf("backend for %s/%s is unavailable", model, version))
+		}
+		if err != nil {
+			r.observe(model, version, backend, 0, backendFailed(err))
+			return err
+		}
+		r.observe(model, version, backend, time.Since(start), false)
+
+		result = response.(map[string]interface{})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RouteStream starts a streamed inference on a backend for the model version
+// and returns the orchestrator's event stream, which the caller must close.
+// The circuit breaker judges the backend by whether the stream starts;
+// failures after that are reported to the caller within the stream. Streams
+// fail over to another backend only while they have not started. Backends
+// called over gRPC serve no streams.
+func (r *ModelRouter) RouteStream(ctx context.Context, model, version string, input map[string]interface{}) (io.ReadCloser, error) {
+	version, backends, err := r.resolve(ctx, model, version)
+	if err != nil {
+		return nil, err
+	}
+	if backends = streamable(backends); len(backends) == 0 {
+		return nil, apperrors.Newf(apperrors.Unimplemented, "backends of %s/%s are called over gRPC, which does not stream", model, version)
+	}
+
+	finished := r.load.Start(model, version)
+	if err := r.admit(ctx, model, version, backends); err != nil {
+		finished()
+		return nil, err
+	}
+
+	var body io.ReadCloser
+	err = r.failover(ctx, model, version, backends, func(backend *Backend) error {
+		if err := r.throttle(model, version, backend); err != nil {
+			return err
+		}
+		release, err := backend.reserve()
+		if err != nil {
+			return err
+		}
+		// The span covers starting the stream, which the caller's context
+		// bounds afterwards
+		spanCtx, span := startSpan(ctx, "backend.stream", model, version, backend)
+		result, err := backend.CircuitBreaker.Execute(func() (interface{}, error) {
+			return r.openStream(spanCtx, backend, model, version, input)
+		})
+		endSpan(span, err)
+		// A stream's duration depends on its output, so only whether it started
+		// is averaged
+		recordRequest(model, version, backend.URL, 0, err)
+
+		if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+			release()
+			return apperrors.Wrap(err, apperrors.Unavailable, fmt.Sprintf("backend for %s/%s is unavailable", model, version))
+		}
+		r.observe(model, version, backend, 0, err != nil && backendFailed(err))
+		if err != nil {
+			release()
+			return err
+		}
+
+		body = &stream{ReadCloser: result.(io.ReadCloser), done: func() {
+			release()
+			finished()
+		}}
+		return nil
+	})
+	if err != nil {
+		finished()
+		return nil, err
+	}
+	return body, nil
+}
+
+// stream is a routed event stream, counted as in flight until it is closed
+type stream struct {
+	io.ReadCloser
+	done func()
+	once sync.Once
+}
+
+func (s *stream) Close() error {
+	s.once.Do(s.done)
+	return s.ReadCloser.Close()
+}
+
+// lookup returns the backends registered for a model version. Backends
+// whose lease lapsed get no requests even before ExpireBackends drops them,
+// nor do draining backends, nor backends warming up or ejected by health
+// checks while others remain.
+func (r *ModelRouter) lookup(model, version string) ([]*Backend, error) {
 	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	versions, ok := r.backends[model]
 	if !ok {
-		r.mu.RUnlock()
-		return nil, fmt.Errorf("model not found: %s", model)
+		return nil, apperrors.Newf(apperrors.NotFound, "model not found: %s", model)
 	}
 
-	backends, ok := versions[version]
-	if !ok || len(backends) == 0 {
-		r.mu.RUnlock()
-		return nil, fmt.Errorf("version not found: %s/%s", model, version)
+	backends := versions[version]
+	now := r.now()
+	live := make([]*Backend, 0, len(backends))
+	draining := 0
+	for _, backend := range backends {
+		if backend.expired(now) {
+			continue
+		}
+		backend.mu.RLock()
+		drained := backend.draining
+		backend.mu.RUnlock()
+		if drained {
+			draining++
+			continue
+		}
+		live = append(live, backend)
 	}
-	r.mu.RUnlock()
+	if len(live) == 0 && draining > 0 {
+		return nil, apperrors.Newf(apperrors.Unavailable, "every backend of %s/%s is draining", model, version)
+	}
+	if len(live) == 0 {
+		return nil, apperrors.Newf(apperrors.NotFound, "version not found: %s/%s", model, version)
+	}
+	return inRotation(live), nil
+}
+
+// BackendStatus is a point-in-time view of a registered backend
+type BackendStatus struct {
+	Model        string    `json:"model"`
+	Version      string    `json:"version"`
+	URL          string    `json:"url"`
+	Weight       float64   `json:"weight"` // Estimated share of the version's requests sent to this backend
+	Healthy      bool      `json:"healthy"`
+	Ejected      bool      `json:"ejected"` // Failed its health checks and gets no requests
+	Outlier      bool      `json:"outlier"` // Ejected for answering worse than its peers, until its probation ends
+	Draining     bool      `json:"draining"`
+	Warming      bool      `json:"warming"`        // Being warmed up and gets no requests while warm backends remain
+	Pool         string    `json:"pool,omitempty"` // Pool of backends dedicated to the requests routed there
+	Class        string    `json:"class"`          // Cost class: gpu, or cpu for backends serving latency-insensitive requests
+	CircuitState string    `json:"circuit_state"`
+	PeerOpen     bool      `json:"peer_circuit_open,omitempty"` // Circuit open on another router replica
+	AvgLatencyMs int64     `json:"avg_latency_ms"`
+	ErrorRate    float64   `json:"error_rate"`
+	InFlight     int64     `json:"in_flight"`
+	MaxInFlight  int       `json:"max_in_flight,omitempty"` // Concurrency limit, if any
+	RateLimit    float64   `json:"rate_limit,omitempty"`    // Requests per second ceiling of the backend's URL, if any
+	LastCheck    time.Time `json:"last_check"`
+	// LeaseExpires is when a backend registered by heartbeat is dropped
+	// unless renewed
+	LeaseExpires *time.Time `json:"lease_expires,omitempty"`
+}
+
+// Backends returns the status of every registered backend, ordered by model and version
+func (r *ModelRouter) Backends() []BackendStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	// Select backend using round-robin (could be enhanced with latency-based routing)
-	backend := r.selectBackend(backends)
+	statuses := make([]BackendStatus, 0)
+	for model, versions := range r.backends {
+		for version, backends := range versions {
+			shares := weights(backends)
+			for i, backend := range backends {
+				var leaseExpires *time.Time
+				if backend.leased() {
+					expires := backend.leaseExpires
+					leaseExpires = &expires
+				}
+				backend.mu.RLock()
+				statuses = append(statuses, BackendStatus{
+					Model:        model,
+					Version:      version,
+					URL:          backend.URL,
+					Weight:       shares[i],
+					Healthy:      backend.HealthStatus,
+					Ejected:      backend.ejected,
+					Outlier:      backend.outlier,
+					Draining:     backend.draining,
+					Warming:      backend.warming,
+					Pool:         r.pools[backend.URL],
+					Class:        r.classOf(backend.URL),
+					CircuitState: backend.CircuitBreaker.State().String(),
+					PeerOpen:     backend.peerOpen,
+					AvgLatencyMs: backend.AvgLatency.Milliseconds(),
+					ErrorRate:    backend.ErrorRate,
+					InFlight:     backend.inFlight.Load(),
+					MaxInFlight:  cap(backend.slots),
+					RateLimit:    backend.rateLimit(),
+					LastCheck:    backend.LastCheck,
+					LeaseExpires: leaseExpires,
+				})
+				backend.mu.RUnlock()
+			}
+		}
+	}
 
-	// Execute request through circuit breaker
-	result, err := backend.CircuitBreaker.Execute(func() (interface{}, error) {
-		return r.executeRequest(ctx, backend, model, version, input)
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Model != statuses[j].Model {
+			return statuses[i].Model < statuses[j].Model
+		}
+		if statuses[i].Version != statuses[j].Version {
+			return statuses[i].Version < statuses[j].Version
+		}
+		return statuses[i].URL < statuses[j].URL
 	})
+	return statuses
+}
 
+// Load reports the requests being routed per model version, for the autoscaler
+func (r *ModelRouter) Load(now time.Time) scaling.Report {
+	return r.load.Report(now)
+}
+
+// openStream starts a streamed inference on the backend
+func (r *ModelRouter) openStream(ctx context.Context, backend *Backend, model, version string, input map[string]interface{}) (io.ReadCloser, error) {
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"model":   model,
+		"version": version,
+		"input":   input,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return result.(map[string]interface{}), nil
-}
+	req, err := http.NewRequestWithContext(ctx, "POST", backend.URL+"/v1/infer/stream", bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", sse.ContentType)
+	logging.Inject(ctx, req)
 
-// selectBackend selects a backend using round-robin strategy
-func (r *ModelRouter) selectBackend(backends []*Backend) *Backend {
-	// Simple random selection (in production, use weighted round-robin based on latency)
-	return backends[rand.Intn(len(backends))]
+	r.mu.RLock()
+	authToken := r.authToken
+	streamClient := *r.client
+	r.mu.RUnlock()
+	if authToken != nil {
+		if token := authToken(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	// Streams outlive the client timeout; the caller's context bounds them
+	streamClient.Timeout = 0
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		backend.mu.Lock()
+		backend.HealthStatus = false
+		backend.mu.Unlock()
+		return nil, apperrors.FromTransportError(err, "inference-orchestrator")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, apperrors.FromHTTPResponse(resp, "inference-orchestrator")
+	}
+
+	backend.mu.Lock()
+	backend.HealthStatus = true
+	backend.LastCheck = time.Now()
+	backend.mu.Unlock()
+
+	return resp.Body, nil
 }
 
-// executeRequest executes the actual HTTP request to the backend
+// executeRequest executes the actual HTTP request to the backend, or the
+// gRPC call to a gRPC backend. Requests with a deadline are bounded by it,
+// and pass what is left of it on, rather than by the client's timeout.
 func (r *ModelRouter) executeRequest(ctx context.Context, backend *Backend, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
-	start := time.Now()
+	if backend.isGRPC() {
+		return r.executeGRPC(ctx, backend, model, version, input)
+	}
 
 	reqBody := map[string]interface{}{
 		"model":   model,
@@ -136,19 +864,29 @@ func (r *ModelRouter) executeRequest(ctx context.Context, backend *Backend, mode
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	logging.Inject(ctx, req)
+
+	r.mu.RLock()
+	authToken := r.authToken
+	client := r.client
+	r.mu.RUnlock()
+	if authToken != nil {
+		if token := authToken(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
 
-	resp, err := r.client.Do(req)
+	resp, err := logging.ClientFor(ctx, client).Do(req)
 	if err != nil {
 		backend.mu.Lock()
 		backend.HealthStatus = false
 		backend.mu.Unlock()
-		return nil, err
+		return nil, apperrors.FromTransportError(err, "inference-orchestrator")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("backend returned status %d: %s", resp.StatusCode, string(body))
+		return nil, apperrors.FromHTTPResponse(resp, "inference-orchestrator")
 	}
 
 	var result map[string]interface{}
@@ -156,11 +894,8 @@ func (r *ModelRouter) executeRequest(ctx context.Context, backend *Backend, mode
 		return nil, err
 	}
 
-	// Update backend metrics
-	latency := time.Since(start)
 	backend.mu.Lock()
 	backend.HealthStatus = true
-	backend.AvgLatency = latency
 	backend.LastCheck = time.Now()
 	backend.mu.Unlock()
 
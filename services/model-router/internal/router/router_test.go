@@ -2,12 +2,21 @@ package router
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/sse"
 )
 
 func TestNewModelRouter(t *testing.T) {
@@ -89,6 +98,26 @@ func TestRouteRequest_Success(t *testing.T) {
 	assert.Contains(t, result, "prediction")
 }
 
+func TestRouteRequest_BoundedByDeadline(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.SetHTTPClient(&http.Client{Timeout: 20 * time.Millisecond})
+
+	// A slow model answers within the caller's budget, past the client's timeout
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get(logging.HeaderTimeout))
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{"prediction": [0.1, 0.9]}`))
+	}))
+	defer server.Close()
+	router.RegisterBackend("llama", "v1", server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	result, err := router.RouteRequest(ctx, "llama", "v1", map[string]interface{}{"text": "hi"})
+	require.NoError(t, err)
+	assert.Contains(t, result, "prediction")
+}
+
 func TestCircuitBreaker_TripsOnFailures(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	router := NewModelRouter(logger, "http://localhost:8082")
@@ -114,7 +143,41 @@ func TestCircuitBreaker_TripsOnFailures(t *testing.T) {
 	assert.Greater(t, failCount, 0)
 }
 
-func TestSelectBackend_RoundRobin(t *testing.T) {
+func TestCircuitBreaker_EmitsOpenedEvent(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+
+	var published []events.Event
+	emitter := events.NewEmitter("model-router", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event events.Event
+		require.NoError(t, json.Unmarshal(value, &event))
+		published = append(published, event)
+		return nil
+	}), 10, zap.NewNop())
+	router.SetEventEmitter(emitter)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	router.RegisterBackend("resnet18", "v1", server.URL)
+
+	input := map[string]interface{}{"data": []float64{1.0}}
+	for i := 0; i < 5; i++ {
+		router.RouteRequest(context.Background(), "resnet18", "v1", input)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	emitter.Run(ctx)
+
+	require.Len(t, published, 1)
+	assert.Equal(t, events.CircuitOpened, published[0].Type)
+	assert.Equal(t, events.SeverityCritical, published[0].Severity)
+	assert.Equal(t, "resnet18/v1", published[0].Subject)
+	assert.Equal(t, server.URL, published[0].Attributes["url"])
+}
+
+func TestSelectBackend_SpreadsAcrossEqualBackends(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	router := NewModelRouter(logger, "http://localhost:8082")
 
@@ -127,10 +190,173 @@ func TestSelectBackend_RoundRobin(t *testing.T) {
 	// Select multiple times
 	selected := make(map[string]int)
 	for i := 0; i < 30; i++ {
-		backend := router.selectBackend(backends)
+		backend := router.selectBackend("resnet18", "", backends)
 		selected[backend.URL]++
 	}
 
-	// All backends should be selected (random distribution)
-	assert.Greater(t, len(selected), 0)
+	// Unmeasured backends score alike, so each gets a share
+	assert.Len(t, selected, 3)
+}
+
+func TestBackends(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	router := NewModelRouter(logger, "http://localhost:8082")
+
+	router.RegisterBackend("resnet18", "v2", "http://backend2:8082")
+	router.RegisterBackend("resnet18", "v1", "http://backend1:8082")
+	router.RegisterBackend("resnet18", "v2", "http://backend3:8082")
+
+	backends := router.Backends()
+
+	assert.Len(t, backends, 3)
+	assert.Equal(t, 1.0, backends[0].Weight)
+	assert.Equal(t, 0.5, backends[1].Weight)
+	assert.Equal(t, "v1", backends[0].Version)
+	assert.Equal(t, "http://backend1:8082", backends[0].URL)
+	assert.True(t, backends[0].Healthy)
+	assert.Equal(t, "closed", backends[0].CircuitState)
+}
+
+func TestLoad_CountsRoutedRequests(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	router := NewModelRouter(logger, "http://localhost:8082")
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"prediction": [0.1, 0.9]}`))
+	}))
+	defer server.Close()
+	router.RegisterBackend("resnet18", "v1", server.URL)
+
+	routed := make(chan error)
+	go func() {
+		_, err := router.RouteRequest(context.Background(), "resnet18", "v1", map[string]interface{}{})
+		routed <- err
+	}()
+
+	assert.Eventually(t, func() bool {
+		loads := router.Load(time.Now()).Loads
+		return len(loads) == 1 && loads[0].InFlight == 1
+	}, time.Second, 10*time.Millisecond)
+
+	close(release)
+	assert.NoError(t, <-routed)
+	assert.Zero(t, router.Load(time.Now()).Loads[0].InFlight)
+}
+
+func TestRouteStream_RelaysUntilClosed(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	router := NewModelRouter(logger, "http://localhost:8082")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/infer/stream", r.URL.Path)
+		w.Header().Set("Content-Type", sse.ContentType)
+		w.Write([]byte("event: partial\ndata: {\"text_output\":\"Hi\"}\n\nevent: done\ndata: {}\n\n"))
+	}))
+	defer server.Close()
+	router.RegisterBackend("llama", "v1", server.URL)
+
+	body, err := router.RouteStream(context.Background(), "llama", "v1", map[string]interface{}{"text_input": "Hi"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), router.Load(time.Now()).Loads[0].InFlight, "in flight until closed")
+
+	event, err := sse.NewReader(body).Next()
+	require.NoError(t, err)
+	assert.Equal(t, sse.EventPartial, event.Name)
+
+	require.NoError(t, body.Close())
+	assert.Zero(t, router.Load(time.Now()).Loads[0].InFlight)
+}
+
+func TestRouteStream_BackendError(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	router := NewModelRouter(logger, "http://localhost:8082")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":"triton unavailable","code":"unavailable"}`))
+	}))
+	defer server.Close()
+	router.RegisterBackend("llama", "v1", server.URL)
+
+	_, err := router.RouteStream(context.Background(), "llama", "v1", map[string]interface{}{})
+	assert.Equal(t, apperrors.Unavailable, apperrors.CodeOf(err))
+	assert.Zero(t, router.Load(time.Now()).Loads[0].InFlight)
+
+	_, err = router.RouteStream(context.Background(), "llama", "v2", map[string]interface{}{})
+	assert.Equal(t, apperrors.NotFound, apperrors.CodeOf(err))
+}
+
+func TestSyncBackends(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.RegisterBackend("resnet18", "v1", "http://backend1:8082")
+	router.RegisterBackend("bert", "v1", "http://backend1:8082")
+	kept := router.backends["resnet18"]["v1"][0]
+
+	added, removed := router.SyncBackends(map[string]map[string][]string{
+		"resnet18": {
+			"v1": {"http://backend1:8082", "http://backend2:8082", "http://backend2:8082"},
+			"v2": {"http://backend3:8082"},
+		},
+	})
+
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 1, removed, "bert was deleted")
+	require.Len(t, router.backends["resnet18"]["v1"], 2)
+	assert.Same(t, kept, router.backends["resnet18"]["v1"][0], "existing backends keep their breaker and latency")
+	assert.Len(t, router.backends["resnet18"]["v2"], 1)
+
+	_, err := router.RouteRequest(context.Background(), "bert", "v1", map[string]interface{}{})
+	assert.True(t, apperrors.Is(err, apperrors.NotFound))
+}
+
+func TestUnregisterBackend(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.RegisterBackend("resnet18", "v1", "http://backend1:8082")
+	router.RegisterBackend("resnet18", "v1", "http://backend2:8082")
+
+	assert.True(t, router.UnregisterBackend("resnet18", "v1", "http://backend1:8082"))
+	assert.False(t, router.UnregisterBackend("resnet18", "v1", "http://backend1:8082"))
+	require.Len(t, router.backends["resnet18"]["v1"], 1)
+
+	assert.True(t, router.UnregisterBackend("resnet18", "v1", "http://backend2:8082"))
+	assert.Empty(t, router.backends, "models without backends are forgotten")
+	assert.False(t, router.UnregisterBackend("bert", "v1", "http://backend1:8082"))
+}
+
+func TestRenewBackend_ExpiresWithoutHeartbeats(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	now := time.Now()
+	router.now = func() time.Time { return now }
+
+	assert.True(t, router.RenewBackend("resnet18", "v1", "http://backend1:8082", 30*time.Second))
+	assert.False(t, router.RenewBackend("resnet18", "v1", "http://backend1:8082", 30*time.Second), "heartbeats renew")
+	require.Len(t, router.backends["resnet18"]["v1"], 1)
+	assert.NotNil(t, router.Backends()[0].LeaseExpires)
+
+	now = now.Add(20 * time.Second)
+	router.RenewBackend("resnet18", "v1", "http://backend1:8082", 30*time.Second)
+	now = now.Add(20 * time.Second)
+	_, err := router.lookup("resnet18", "v1")
+	require.NoError(t, err, "the renewed lease holds")
+
+	now = now.Add(20 * time.Second)
+	_, err = router.lookup("resnet18", "v1")
+	assert.True(t, apperrors.Is(err, apperrors.NotFound), "lapsed backends get no requests before they are dropped")
+
+	assert.Equal(t, 1, router.ExpireBackends(now))
+	assert.Empty(t, router.backends)
+}
+
+func TestSyncBackends_KeepsLeasedBackends(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.RenewBackend("bert", "v1", "http://backend1:8082", time.Minute)
+	router.RegisterBackend("bert", "v1", "http://backend2:8082")
+
+	_, removed := router.SyncBackends(map[string]map[string][]string{})
+
+	assert.Equal(t, 1, removed)
+	require.Len(t, router.backends["bert"]["v1"], 1)
+	assert.Equal(t, "http://backend1:8082", router.backends["bert"]["v1"][0].URL)
 }
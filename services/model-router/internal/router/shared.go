@@ -0,0 +1,183 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/state"
+)
+
+// StateStore shares the routing state of backends between router replicas
+type StateStore interface {
+	Publish(ctx context.Context, backends []state.Backend) error
+	Load(ctx context.Context) ([]state.Backend, error)
+}
+
+// SetStateStore shares the state of backends with the other replicas through
+// store, as replica. Without a store each replica learns on its own.
+func (r *ModelRouter) SetStateStore(store StateStore, replica string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stateStore = store
+	r.replica = replica
+}
+
+// ShareState syncs the state of backends with the other replicas every
+// interval until ctx is cancelled
+func (r *ModelRouter) ShareState(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.SyncState(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Warn("failed to share backend state with other replicas", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncState merges the other replicas' view of the backends into this one's,
+// then publishes the result. If the store is down each replica keeps routing
+// on what it learned itself.
+func (r *ModelRouter) SyncState(ctx context.Context) error {
+	r.mu.RLock()
+	store, replica := r.stateStore, r.replica
+	r.mu.RUnlock()
+	if store == nil {
+		return nil
+	}
+
+	shared, err := store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	r.mergeState(replica, shared)
+	return store.Publish(ctx, r.snapshot(replica))
+}
+
+// peerStates gathers the other replicas' views of a backend
+type peerStates struct {
+	latency   time.Duration
+	latencies int
+	errorRate float64
+	// newest is the view with the latest health check
+	newest      state.Backend
+	circuitOpen bool
+	count       int
+}
+
+// mergeState folds the other replicas' views of the backends into this one's:
+// latency and error rate averages are blended with theirs, the most recent
+// health check wins, and a circuit open on any replica keeps the backend out
+// of rotation here too
+func (r *ModelRouter) mergeState(replica string, shared []state.Backend) {
+	peers := make(map[string]*peerStates)
+	for _, backend := range shared {
+		if backend.Replica == replica {
+			continue
+		}
+		key := backend.Model + "|" + backend.Version + "|" + backend.URL
+		p := peers[key]
+		if p == nil {
+			p = &peerStates{}
+			peers[key] = p
+		}
+		if backend.AvgLatency > 0 {
+			p.latency += backend.AvgLatency
+			p.latencies++
+		}
+		p.errorRate += backend.ErrorRate
+		if backend.LastCheck.After(p.newest.LastCheck) {
+			p.newest = backend
+		}
+		p.circuitOpen = p.circuitOpen || backend.CircuitOpen
+		p.count++
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for model, versions := range r.backends {
+		for version, backends := range versions {
+			for _, backend := range backends {
+				p := peers[model+"|"+version+"|"+backend.URL]
+				backend.mu.Lock()
+				if p == nil {
+					backend.peerOpen = false
+				} else {
+					r.mergeBackend(backend, p)
+				}
+				backend.mu.Unlock()
+			}
+		}
+	}
+}
+
+// mergeBackend folds the other replicas' views into a backend; backend.mu
+// must be held
+func (r *ModelRouter) mergeBackend(backend *Backend, p *peerStates) {
+	if p.latencies > 0 {
+		latency := p.latency / time.Duration(p.latencies)
+		if backend.AvgLatency == 0 {
+			backend.AvgLatency = latency
+		} else {
+			backend.AvgLatency = (backend.AvgLatency + latency) / 2
+		}
+	}
+	backend.ErrorRate = (backend.ErrorRate + p.errorRate/float64(p.count)) / 2
+
+	if p.newest.LastCheck.After(backend.LastCheck) {
+		if p.newest.Ejected != backend.ejected {
+			r.logger.Info("backend health changed on another replica",
+				zap.String("backend", backend.URL),
+				zap.String("replica", p.newest.Replica),
+				zap.Bool("ejected", p.newest.Ejected),
+			)
+		}
+		backend.HealthStatus = p.newest.Healthy
+		backend.LastCheck = p.newest.LastCheck
+		backend.ejected = p.newest.Ejected
+		backend.probeFailures = 0
+		if backend.ejected {
+			backend.probeFailures = ejectAfter
+		}
+	}
+	backend.peerOpen = p.circuitOpen
+}
+
+// snapshot returns this replica's view of its backends
+func (r *ModelRouter) snapshot(replica string) []state.Backend {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	backends := make([]state.Backend, 0)
+	for model, versions := range r.backends {
+		for version, current := range versions {
+			for _, backend := range current {
+				backend.mu.RLock()
+				backends = append(backends, state.Backend{
+					Replica:     replica,
+					Model:       model,
+					Version:     version,
+					URL:         backend.URL,
+					Healthy:     backend.HealthStatus,
+					Ejected:     backend.ejected,
+					LastCheck:   backend.LastCheck,
+					AvgLatency:  backend.AvgLatency,
+					ErrorRate:   backend.ErrorRate,
+					CircuitOpen: backend.CircuitBreaker.State() == gobreaker.StateOpen,
+					UpdatedAt:   now,
+				})
+				backend.mu.RUnlock()
+			}
+		}
+	}
+	return backends
+}
@@ -0,0 +1,185 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/state"
+)
+
+// fakeStateStore keeps the replicas' views in memory, by replica and backend
+type fakeStateStore struct {
+	mu     sync.Mutex
+	states map[string]state.Backend
+	err    error
+}
+
+func (s *fakeStateStore) Publish(ctx context.Context, backends []state.Backend) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if s.states == nil {
+		s.states = make(map[string]state.Backend)
+	}
+	for _, backend := range backends {
+		s.states[backend.Replica+"|"+backend.Model+"|"+backend.Version+"|"+backend.URL] = backend
+	}
+	return nil
+}
+
+func (s *fakeStateStore) Load(ctx context.Context) ([]state.Backend, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	backends := make([]state.Backend, 0, len(s.states))
+	for _, backend := range s.states {
+		backends = append(backends, backend)
+	}
+	return backends, nil
+}
+
+// replicas returns two routers of the same backends sharing store
+func replicas(store StateStore) (*ModelRouter, *ModelRouter) {
+	a := NewModelRouter(zap.NewNop(), "http://gpu-1:8082")
+	a.SetStateStore(store, "router-a")
+	b := NewModelRouter(zap.NewNop(), "http://gpu-1:8082")
+	b.SetStateStore(store, "router-b")
+	for _, router := range []*ModelRouter{a, b} {
+		router.RegisterBackend("llama", "v1", "http://gpu-1:8082")
+		router.RegisterBackend("llama", "v1", "http://gpu-2:8082")
+	}
+	return a, b
+}
+
+// backendOf returns the router's backend of llama/v1 at url, in rotation or not
+func backendOf(t *testing.T, router *ModelRouter, url string) *Backend {
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+	for _, backend := range router.backends["llama"]["v1"] {
+		if backend.URL == url {
+			return backend
+		}
+	}
+	t.Fatalf("no backend %s", url)
+	return nil
+}
+
+func TestSyncState_SharesHealthChecks(t *testing.T) {
+	store := &fakeStateStore{}
+	a, b := replicas(store)
+	ctx := context.Background()
+
+	gpu1 := backendOf(t, a, "http://gpu-1:8082")
+	for i := 0; i < ejectAfter; i++ {
+		a.recordProbe(gpu1, errors.New("connection refused"))
+	}
+	require.NoError(t, a.SyncState(ctx))
+	require.NoError(t, b.SyncState(ctx))
+
+	peer := backendOf(t, b, "http://gpu-1:8082")
+	peer.mu.RLock()
+	assert.True(t, peer.ejected, "a backend ejected by one replica is ejected by the others")
+	assert.False(t, peer.HealthStatus)
+	assert.Equal(t, ejectAfter, peer.probeFailures)
+	peer.mu.RUnlock()
+
+	// The most recent check wins: the backend passes one on b
+	b.recordProbe(peer, nil)
+	require.NoError(t, b.SyncState(ctx))
+	require.NoError(t, a.SyncState(ctx))
+	gpu1.mu.RLock()
+	assert.False(t, gpu1.ejected)
+	assert.True(t, gpu1.HealthStatus)
+	gpu1.mu.RUnlock()
+}
+
+func TestSyncState_BlendsAverages(t *testing.T) {
+	store := &fakeStateStore{}
+	a, b := replicas(store)
+	ctx := context.Background()
+
+	gpu1 := backendOf(t, a, "http://gpu-1:8082")
+	gpu1.mu.Lock()
+	gpu1.AvgLatency, gpu1.ErrorRate = 200*time.Millisecond, 0.4
+	gpu1.mu.Unlock()
+	require.NoError(t, a.SyncState(ctx))
+
+	// A new replica starts from what the others learned
+	require.NoError(t, b.SyncState(ctx))
+	peer := backendOf(t, b, "http://gpu-1:8082")
+	peer.mu.RLock()
+	assert.Equal(t, 200*time.Millisecond, peer.AvgLatency)
+	assert.InDelta(t, 0.2, peer.ErrorRate, 1e-9)
+	peer.mu.RUnlock()
+
+	// Replicas that learned on their own meet halfway
+	peer.mu.Lock()
+	peer.AvgLatency = 100 * time.Millisecond
+	peer.mu.Unlock()
+	require.NoError(t, b.SyncState(ctx))
+	peer.mu.RLock()
+	assert.Equal(t, 150*time.Millisecond, peer.AvgLatency)
+	peer.mu.RUnlock()
+}
+
+func TestSyncState_PeerCircuitOpen(t *testing.T) {
+	store := &fakeStateStore{}
+	a, b := replicas(store)
+	ctx := context.Background()
+
+	gpu1 := backendOf(t, a, "http://gpu-1:8082")
+	for i := 0; i < 5; i++ {
+		gpu1.CircuitBreaker.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	}
+	require.Equal(t, gobreaker.StateOpen, gpu1.CircuitBreaker.State())
+	require.NoError(t, a.SyncState(ctx))
+	require.NoError(t, b.SyncState(ctx))
+
+	backends := []*Backend{backendOf(t, b, "http://gpu-1:8082"), backendOf(t, b, "http://gpu-2:8082")}
+	rotation := inRotation(backends)
+	require.Len(t, rotation, 1, "a circuit open on another replica keeps the backend out of rotation")
+	assert.Equal(t, "http://gpu-2:8082", rotation[0].URL)
+	for _, status := range b.Backends() {
+		assert.Equal(t, status.URL == "http://gpu-1:8082", status.PeerOpen)
+	}
+
+	// Once no replica reports it open the backend is back
+	store.mu.Lock()
+	delete(store.states, "router-a|llama|v1|http://gpu-1:8082")
+	store.mu.Unlock()
+	require.NoError(t, b.SyncState(ctx))
+	assert.Len(t, inRotation(backends), 2)
+}
+
+func TestSyncState_IgnoresOwnStateAndStoreFailures(t *testing.T) {
+	store := &fakeStateStore{}
+	a, _ := replicas(store)
+	ctx := context.Background()
+
+	gpu1 := backendOf(t, a, "http://gpu-1:8082")
+	gpu1.mu.Lock()
+	gpu1.AvgLatency = 200 * time.Millisecond
+	gpu1.mu.Unlock()
+	require.NoError(t, a.SyncState(ctx))
+	require.NoError(t, a.SyncState(ctx))
+	gpu1.mu.RLock()
+	assert.Equal(t, 200*time.Millisecond, gpu1.AvgLatency, "a replica does not merge its own view")
+	gpu1.mu.RUnlock()
+
+	store.err = errors.New("connection refused")
+	assert.Error(t, a.SyncState(ctx))
+
+	assert.NoError(t, NewModelRouter(zap.NewNop(), "http://gpu-1:8082").SyncState(ctx), "nothing is shared without a store")
+}
@@ -0,0 +1,86 @@
+package router
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/traffic"
+)
+
+// SetSplits replaces the traffic splits canarying model versions; models
+// left out are no longer split
+func (r *ModelRouter) SetSplits(splits []traffic.Split) {
+	bySplit := make(map[string]traffic.Split, len(splits))
+	for _, split := range splits {
+		if err := split.Validate(); err != nil {
+			r.logger.Warn("ignoring invalid traffic split", zap.String("model", split.Model), zap.Error(err))
+			continue
+		}
+		bySplit[split.Model] = split
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.splits = bySplit
+}
+
+// Splits returns the traffic splits in effect, ordered by model
+func (r *ModelRouter) Splits() []traffic.Split {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	splits := make([]traffic.Split, 0, len(r.splits))
+	for _, split := range r.splits {
+		splits = append(splits, split)
+	}
+	sort.Slice(splits, func(i, j int) bool { return splits[i].Model < splits[j].Model })
+	return splits
+}
+
+type pinnedKey struct{}
+
+// Pin returns ctx whose requests are served by the version they name,
+// whatever the model's traffic split, e.g. because an experiment chose it
+func Pin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pinnedKey{}, true)
+}
+
+func pinned(ctx context.Context) bool {
+	pin, _ := ctx.Value(pinnedKey{}).(bool)
+	return pin
+}
+
+// resolve picks the version serving a request for model/version, or the
+// version it aliases, under the model's traffic split and returns its live
+// backends that may serve the request. When the version picked has none, the
+// request is served by the version it names.
+func (r *ModelRouter) resolve(ctx context.Context, model, version string) (string, []*Backend, error) {
+	version = r.ResolveAlias(model, version)
+
+	r.mu.RLock()
+	split, ok := r.splits[model]
+	r.mu.RUnlock()
+
+	if ok && split.Version == version && !pinned(ctx) {
+		if target := split.Pick(rand.Intn(100)); target != version {
+			if backends, err := r.lookup(model, target); err == nil {
+				return target, r.candidates(ctx, backends), nil
+			}
+		}
+	}
+
+	backends, err := r.lookup(model, version)
+	if err != nil {
+		return version, nil, err
+	}
+	return version, r.candidates(ctx, backends), nil
+}
+
+// candidates narrows a version's backends to those of the request's pool,
+// then to those of the class its latency needs call for
+func (r *ModelRouter) candidates(ctx context.Context, backends []*Backend) []*Backend {
+	return r.byCost(ctx, r.inPool(ctx, backends))
+}
@@ -0,0 +1,96 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/traffic"
+)
+
+// versionServer answers inferences with the version they were sent for
+func versionServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Version string `json:"version"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		json.NewEncoder(w).Encode(map[string]string{"version": req.Version})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRouteRequest_SplitsTrafficBetweenVersions(t *testing.T) {
+	server := versionServer(t)
+	router := NewModelRouter(zap.NewNop(), server.URL)
+	router.RegisterBackend("resnet18", "v1", server.URL)
+	router.RegisterBackend("resnet18", "v2", server.URL)
+	router.SetSplits([]traffic.Split{
+		{Model: "resnet18", Version: "v1", Weights: map[string]int{"v1": 50, "v2": 50}},
+	})
+
+	served := make(map[interface{}]int)
+	for i := 0; i < 100; i++ {
+		result, err := router.RouteRequest(context.Background(), "resnet18", "v1", nil)
+		require.NoError(t, err)
+		served[result["version"]]++
+	}
+	assert.Greater(t, served["v1"], 0)
+	assert.Greater(t, served["v2"], 0)
+
+	// Requests naming the canary itself are not split
+	for i := 0; i < 20; i++ {
+		result, err := router.RouteRequest(context.Background(), "resnet18", "v2", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "v2", result["version"])
+	}
+}
+
+func TestRouteRequest_SplitFallsBackWithoutCanaryBackends(t *testing.T) {
+	server := versionServer(t)
+	router := NewModelRouter(zap.NewNop(), server.URL)
+	router.RegisterBackend("resnet18", "v1", server.URL)
+	router.SetSplits([]traffic.Split{
+		{Model: "resnet18", Version: "v1", Weights: map[string]int{"v2": 100}},
+	})
+
+	result, err := router.RouteRequest(context.Background(), "resnet18", "v1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", result["version"])
+}
+
+func TestSetSplits_IgnoresInvalidSplits(t *testing.T) {
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.SetSplits([]traffic.Split{
+		{Model: "resnet18", Version: "v1", Weights: map[string]int{"v1": 95, "v2": 5}},
+		{Model: "bert", Version: "v1", Weights: map[string]int{"v2": 50}},
+	})
+
+	splits := router.Splits()
+	require.Len(t, splits, 1)
+	assert.Equal(t, "resnet18", splits[0].Model)
+
+	router.SetSplits(nil)
+	assert.Empty(t, router.Splits())
+}
+
+func TestRouteRequest_PinnedRequestsSkipSplits(t *testing.T) {
+	server := versionServer(t)
+	router := NewModelRouter(zap.NewNop(), server.URL)
+	router.RegisterBackend("resnet18", "v1", server.URL)
+	router.RegisterBackend("resnet18", "v2", server.URL)
+	router.SetSplits([]traffic.Split{
+		{Model: "resnet18", Version: "v1", Weights: map[string]int{"v2": 100}},
+	})
+
+	result, err := router.RouteRequest(Pin(context.Background()), "resnet18", "v1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", result["version"])
+}
@@ -0,0 +1,39 @@
+package router
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// startSpan starts a client span around a call to a backend of
+// model/version. The returned context carries the span's IDs, so the
+// traceparent sent to the backend names it as the parent of the backend's.
+func startSpan(ctx context.Context, name, model, version string, backend *Backend) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer("model-router").Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("model", model),
+			attribute.String("model.version", version),
+			attribute.String("backend", backend.URL),
+		),
+	)
+	if sc := span.SpanContext(); sc.IsValid() {
+		ctx = logging.WithTrace(ctx, sc.TraceID().String(), sc.SpanID().String())
+	}
+	return ctx, span
+}
+
+// endSpan ends a backend call's span, recording its error if it failed
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,48 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+func TestRouteRequest_PropagatesTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get(logging.HeaderTraceParent)
+		w.Write([]byte(`{"output": "ok"}`))
+	}))
+	defer server.Close()
+
+	router := NewModelRouter(zap.NewNop(), server.URL)
+	router.RegisterBackend("resnet18", "v1", server.URL)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "route")
+	_, err := router.RouteRequest(ctx, "resnet18", "v1", map[string]interface{}{})
+	require.NoError(t, err)
+	parent.End()
+
+	var call sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "backend.infer" {
+			call = span
+		}
+	}
+	require.NotNil(t, call, "backend calls are traced")
+	assert.Equal(t, parent.SpanContext().SpanID(), call.Parent().SpanID())
+	assert.Equal(t, "00-"+call.SpanContext().TraceID().String()+"-"+call.SpanContext().SpanID().String()+"-01", traceparent,
+		"the backend's spans are children of the call's")
+}
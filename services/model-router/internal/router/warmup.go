@@ -0,0 +1,109 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultWarmupRequests is how many sample requests warm up a new backend
+	DefaultWarmupRequests = 3
+
+	// DefaultWarmupMaxLatency is the longest the last warm-up request may take
+	// for the backend to be deemed warm
+	DefaultWarmupMaxLatency = 5 * time.Second
+
+	// warmupRetry is how long a backend that failed its warm-up waits before
+	// the next one
+	warmupRetry = 10 * time.Second
+)
+
+// SetWarmup sets how new backends of model versions with a sample input are
+// warmed up before they get requests: requests sample requests are sent one
+// after another, and the backend is put in rotation once all succeed and the
+// last took no longer than maxLatency. Zero requests disables warm-up.
+func (r *ModelRouter) SetWarmup(requests int, maxLatency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.warmupRequests = requests
+	r.warmupMaxLatency = maxLatency
+}
+
+// SetWarmupInputs replaces the sample inputs new backends are warmed up
+// with, by model and version; versions left out are not warmed up
+func (r *ModelRouter) SetWarmupInputs(inputs map[string]map[string]map[string]interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.warmupInputs = inputs
+}
+
+// startWarmup holds a new backend out of rotation while it is warmed up, if
+// its model version has a sample input; r.mu must be held
+func (r *ModelRouter) startWarmup(model, version string, backend *Backend) {
+	input, ok := r.warmupInputs[model][version]
+	if !ok || r.warmupRequests <= 0 {
+		return
+	}
+
+	backend.warming = true
+	backend.HealthStatus = false
+	go r.warmUp(model, version, backend, input, r.warmupRequests, r.warmupMaxLatency)
+}
+
+// warmUp sends warm-up rounds to the backend until one succeeds, then puts
+// it in rotation. It gives up once the backend is no longer registered.
+func (r *ModelRouter) warmUp(model, version string, backend *Backend, input map[string]interface{}, requests int, maxLatency time.Duration) {
+	logger := r.logger.With(
+		zap.String("model", model),
+		zap.String("version", version),
+		zap.String("url", backend.URL),
+	)
+
+	for {
+		err := r.warmupRound(model, version, backend, input, requests, maxLatency)
+		if err == nil {
+			break
+		}
+		logger.Warn("backend warm-up failed, retrying", zap.Error(err), zap.Duration("retry_in", warmupRetry))
+
+		time.Sleep(warmupRetry)
+		if !r.registered(model, version, backend) {
+			return
+		}
+	}
+
+	backend.mu.Lock()
+	backend.warming = false
+	backend.HealthStatus = true
+	backend.mu.Unlock()
+	logger.Info("backend warmed up, accepting requests")
+}
+
+// warmupRound sends requests sample requests to the backend one after
+// another; the first ones absorb its cold start, and the last must be
+// answered within maxLatency
+func (r *ModelRouter) warmupRound(model, version string, backend *Backend, input map[string]interface{}, requests int, maxLatency time.Duration) error {
+	var latency time.Duration
+	for i := 0; i < requests; i++ {
+		start := time.Now()
+		if _, err := r.executeRequest(context.Background(), backend, model, version, input); err != nil {
+			return err
+		}
+		latency = time.Since(start)
+	}
+	if latency > maxLatency {
+		return fmt.Errorf("warm-up request took %s, longer than %s", latency, maxLatency)
+	}
+	return nil
+}
+
+// registered reports whether the backend is still registered for the model
+// version
+func (r *ModelRouter) registered(model, version string, backend *Backend) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return contains(r.backends[model][version], backend)
+}
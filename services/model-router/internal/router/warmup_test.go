@@ -0,0 +1,86 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWarmup_HoldsNewBackendsOutOfRotation(t *testing.T) {
+	var warmups atomic.Int32
+	release := make(chan struct{})
+	cold := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input map[string]interface{} `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "sample.jpg", req.Input["image"])
+		if warmups.Add(1) == 1 {
+			<-release
+		}
+		w.Write([]byte(`{"output": "ok"}`))
+	}))
+	defer cold.Close()
+
+	router := NewModelRouter(zap.NewNop(), "http://localhost:8082")
+	router.RegisterBackend("resnet18", "v1", "http://warm:8082")
+	router.SetWarmup(2, time.Second)
+	router.SetWarmupInputs(map[string]map[string]map[string]interface{}{
+		"resnet18": {"v1": {"image": "sample.jpg"}},
+	})
+	router.RegisterBackend("resnet18", "v1", cold.URL)
+
+	urls := func() []string {
+		backends, err := router.lookup("resnet18", "v1")
+		require.NoError(t, err)
+		var urls []string
+		for _, backend := range backends {
+			urls = append(urls, backend.URL)
+		}
+		return urls
+	}
+	assert.Equal(t, []string{"http://warm:8082"}, urls(), "warming backends get no requests")
+	for _, status := range router.Backends() {
+		assert.Equal(t, status.URL == cold.URL, status.Warming)
+	}
+
+	close(release)
+	require.Eventually(t, func() bool { return len(urls()) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(2), warmups.Load())
+}
+
+func TestWarmup_FailedBackendsStayOut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.Write([]byte(`{"output": "ok"}`))
+	}))
+	defer server.Close()
+
+	router := NewModelRouter(zap.NewNop(), server.URL)
+	router.SetWarmup(1, time.Millisecond)
+	router.SetWarmupInputs(map[string]map[string]map[string]interface{}{
+		"resnet18": {"v1": {}},
+	})
+	router.RegisterBackend("resnet18", "v1", server.URL)
+	router.RegisterBackend("resnet18", "v1", "http://other:8082")
+	defer router.UnregisterBackend("resnet18", "v1", server.URL)
+
+	time.Sleep(50 * time.Millisecond)
+	statuses := router.Backends()
+	require.Len(t, statuses, 2)
+	for _, status := range statuses {
+		assert.True(t, status.Warming, "%s answered too slowly to be warm", status.URL)
+	}
+
+	// With every backend warming, requests are served cold rather than failed
+	backends, err := router.lookup("resnet18", "v1")
+	require.NoError(t, err)
+	assert.Len(t, backends, 2)
+}
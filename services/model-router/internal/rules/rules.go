@@ -0,0 +1,164 @@
+// Package rules routes requests by who sends them and what they carry. The
+// first rule a request matches may send it to another version of its model
+// and to a pool of backends dedicated to the requests routed there, so
+// enterprise tenants can be pinned to capacity of their own.
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Match selects requests. Every condition given must hold: the tenant is one
+// of Tenants, each header in Headers has the value given, or any value for
+// "*", and each top-level input field in Input has the value given.
+type Match struct {
+	Tenants []string          `json:"tenants,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Input   map[string]string `json:"input,omitempty"`
+}
+
+// Rule sends the requests for Model, or for every model when empty, that
+// match to Version and to the backends of Pool; either may be left out
+type Rule struct {
+	Name    string `json:"name"`
+	Model   string `json:"model,omitempty"`
+	Match   Match  `json:"match"`
+	Version string `json:"version,omitempty"`
+	Pool    string `json:"pool,omitempty"`
+}
+
+// Config is the declarative form of an Engine: pools of backend URLs by
+// name, and the rules in the order they are tried
+type Config struct {
+	Pools map[string][]string `json:"pools,omitempty"`
+	Rules []Rule              `json:"rules"`
+}
+
+// Request is what rules match a request on
+type Request struct {
+	Tenant string
+	Header http.Header
+	Input  map[string]interface{}
+}
+
+// Engine matches requests against rules in order. A nil Engine matches
+// nothing.
+type Engine struct {
+	config Config
+}
+
+// Parse parses a JSON Config
+func Parse(value string) (*Engine, error) {
+	engine := &Engine{config: Config{Pools: map[string][]string{}, Rules: []Rule{}}}
+	if value == "" {
+		return engine, nil
+	}
+
+	var config Config
+	if err := json.Unmarshal([]byte(value), &config); err != nil {
+		return nil, fmt.Errorf("invalid routing rules: %w", err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if config.Pools != nil {
+		engine.config.Pools = config.Pools
+	}
+	if config.Rules != nil {
+		engine.config.Rules = config.Rules
+	}
+	return engine, nil
+}
+
+func (c Config) validate() error {
+	pooled := make(map[string]string)
+	for name, urls := range c.Pools {
+		if len(urls) == 0 {
+			return fmt.Errorf("invalid pool %q: no backends", name)
+		}
+		for _, url := range urls {
+			if other, ok := pooled[url]; ok && other != name {
+				return fmt.Errorf("invalid pool %q: %s is already in pool %q", name, url, other)
+			}
+			pooled[url] = name
+		}
+	}
+
+	names := make(map[string]bool, len(c.Rules))
+	for _, rule := range c.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("invalid routing rule: name is required")
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("invalid routing rule %q: listed twice", rule.Name)
+		}
+		names[rule.Name] = true
+		if rule.Version == "" && rule.Pool == "" {
+			return fmt.Errorf("invalid routing rule %q: a version or pool is required", rule.Name)
+		}
+		if _, ok := c.Pools[rule.Pool]; rule.Pool != "" && !ok {
+			return fmt.Errorf("invalid routing rule %q: unknown pool %q", rule.Name, rule.Pool)
+		}
+	}
+	return nil
+}
+
+// Match returns the first rule the request for model matches, or false when
+// none does
+func (e *Engine) Match(model string, req Request) (Rule, bool) {
+	if e == nil {
+		return Rule{}, false
+	}
+	for _, rule := range e.config.Rules {
+		if (rule.Model == "" || rule.Model == model) && rule.Match.matches(req) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (m Match) matches(req Request) bool {
+	if len(m.Tenants) > 0 && !contains(m.Tenants, req.Tenant) {
+		return false
+	}
+	for name, want := range m.Headers {
+		got := req.Header.Get(name)
+		if got == "" || (want != "*" && got != want) {
+			return false
+		}
+	}
+	for field, want := range m.Input {
+		value, ok := req.Input[field]
+		if !ok || value == nil || fmt.Sprint(value) != want {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Rules returns the rules in the order they are tried
+func (e *Engine) Rules() []Rule {
+	if e == nil {
+		return []Rule{}
+	}
+	return e.config.Rules
+}
+
+// Pools returns the backend URLs of each pool
+func (e *Engine) Pools() map[string][]string {
+	if e == nil {
+		return map[string][]string{}
+	}
+	return e.config.Pools
+}
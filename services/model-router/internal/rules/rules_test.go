@@ -0,0 +1,67 @@
+package rules
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const enterpriseRules = `{
+	"pools": {"acme": ["http://gpu-9:8082"]},
+	"rules": [
+		{"name": "acme-dedicated", "match": {"tenants": ["acme"]}, "pool": "acme"},
+		{"name": "beta-llama", "model": "llama", "match": {"headers": {"X-Beta": "*"}}, "version": "v3"},
+		{"name": "long-prompts", "model": "llama", "match": {"input": {"max_tokens": "4096"}}, "version": "v2-long"}
+	]
+}`
+
+func TestEngine_MatchesFirstRule(t *testing.T) {
+	engine, err := Parse(enterpriseRules)
+	require.NoError(t, err)
+
+	rule, ok := engine.Match("llama", Request{Tenant: "acme", Header: http.Header{"X-Beta": {"1"}}})
+	require.True(t, ok)
+	assert.Equal(t, "acme-dedicated", rule.Name, "rules are tried in order")
+
+	rule, ok = engine.Match("llama", Request{Tenant: "globex", Header: http.Header{"X-Beta": {"1"}}})
+	require.True(t, ok)
+	assert.Equal(t, "v3", rule.Version)
+
+	rule, ok = engine.Match("llama", Request{Input: map[string]interface{}{"max_tokens": 4096.0}})
+	require.True(t, ok)
+	assert.Equal(t, "long-prompts", rule.Name, "input fields are compared as text")
+
+	_, ok = engine.Match("resnet18", Request{Header: http.Header{"X-Beta": {"1"}}})
+	assert.False(t, ok, "rules on other models")
+	_, ok = engine.Match("llama", Request{Tenant: "globex", Header: http.Header{}})
+	assert.False(t, ok)
+
+	var nilEngine *Engine
+	_, ok = nilEngine.Match("llama", Request{Tenant: "acme"})
+	assert.False(t, ok)
+	assert.Empty(t, nilEngine.Rules())
+}
+
+func TestParse_Empty(t *testing.T) {
+	engine, err := Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, engine.Rules())
+	assert.Empty(t, engine.Pools())
+}
+
+func TestParse_RejectsInvalidRules(t *testing.T) {
+	for name, value := range map[string]string{
+		"not json":     `[{"name": "x"}]`,
+		"no name":      `{"rules": [{"version": "v2"}]}`,
+		"no action":    `{"rules": [{"name": "x", "match": {"tenants": ["acme"]}}]}`,
+		"unknown pool": `{"rules": [{"name": "x", "pool": "acme"}]}`,
+		"empty pool":   `{"pools": {"acme": []}, "rules": []}`,
+		"shared url":   `{"pools": {"acme": ["http://gpu-9:8082"], "globex": ["http://gpu-9:8082"]}, "rules": []}`,
+		"listed twice": `{"rules": [{"name": "x", "version": "v2"}, {"name": "x", "version": "v3"}]}`,
+	} {
+		_, err := Parse(value)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,161 @@
+// Package shadow mirrors a share of production requests to candidate model
+// versions in the background. Candidates' answers are recorded alongside the
+// production answer for offline comparison and never reach the caller, so a
+// new version can be validated under real load without risk.
+package shadow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/model-router/internal/observability"
+	"github.com/yourusername/ai-platform/model-router/internal/router"
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/logging"
+)
+
+// Rule mirrors Percent of the requests for Version of Model to Candidate
+type Rule struct {
+	Model     string  `json:"model"`
+	Version   string  `json:"version"`
+	Candidate string  `json:"candidate"`
+	Percent   float64 `json:"percent"`
+}
+
+// ParseRules parses a JSON array of rules, at most one per model version
+func ParseRules(value string) ([]Rule, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, fmt.Errorf("invalid shadow traffic: %w", err)
+	}
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		switch {
+		case rule.Model == "" || rule.Version == "" || rule.Candidate == "":
+			return nil, fmt.Errorf("invalid shadow traffic: model, version and candidate are required")
+		case rule.Candidate == rule.Version:
+			return nil, fmt.Errorf("invalid shadow traffic: %s/%s is mirrored to itself", rule.Model, rule.Version)
+		case rule.Percent <= 0 || rule.Percent > 100:
+			return nil, fmt.Errorf("invalid shadow traffic: percent %.2f for %s/%s is not in (0, 100]", rule.Percent, rule.Model, rule.Version)
+		case seen[rule.Model+"/"+rule.Version]:
+			return nil, fmt.Errorf("invalid shadow traffic: %s/%s is mirrored twice", rule.Model, rule.Version)
+		}
+		seen[rule.Model+"/"+rule.Version] = true
+	}
+	return rules, nil
+}
+
+// Router serves mirrored requests
+type Router interface {
+	RouteRequest(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error)
+}
+
+// Mirror sends requests to candidates in the background, at most a fixed
+// number at once; requests mirrored while all are busy are dropped, so
+// shadows never hold up or pile onto production. A nil Mirror mirrors nothing.
+type Mirror struct {
+	rules   map[string]Rule
+	target  Router
+	capture *inferencelog.Capture
+	timeout time.Duration
+	slots   chan struct{}
+	sample  func() float64
+	logger  *zap.Logger
+	dropped atomic.Int64
+}
+
+// NewMirror creates a mirror that sends up to concurrency requests to
+// candidates at once, each given up to timeout, and records their answers
+// in capture. It returns nil when there are no rules.
+func NewMirror(rules []Rule, target Router, capture *inferencelog.Capture, concurrency int, timeout time.Duration, logger *zap.Logger) *Mirror {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	byVersion := make(map[string]Rule, len(rules))
+	for _, rule := range rules {
+		byVersion[rule.Model+"/"+rule.Version] = rule
+	}
+	return &Mirror{
+		rules:   byVersion,
+		target:  target,
+		capture: capture,
+		timeout: timeout,
+		slots:   make(chan struct{}, concurrency),
+		sample:  rand.Float64,
+		logger:  logger,
+	}
+}
+
+// Mirror samples a request for model/version that was answered with output
+// and, if picked, sends it to the version's candidate in the background
+func (m *Mirror) Mirror(ctx context.Context, model, version string, input, output map[string]interface{}) {
+	if m == nil {
+		return
+	}
+	rule, ok := m.rules[model+"/"+version]
+	if !ok || m.sample()*100 >= rule.Percent {
+		return
+	}
+
+	select {
+	case m.slots <- struct{}{}:
+	default:
+		observability.ShadowRequests.WithLabelValues(rule.Model, rule.Candidate, "dropped").Inc()
+		if m.dropped.Add(1)%100 == 1 {
+			m.logger.Warn("shadow requests saturated, dropping mirrored requests", zap.Int64("dropped", m.dropped.Load()))
+		}
+		return
+	}
+
+	// The caller's request ends with its response; the shadow keeps only its
+	// correlation fields
+	shadowCtx := logging.NewContext(context.Background(), logging.FieldsFromContext(ctx))
+	go func() {
+		defer func() { <-m.slots }()
+		m.send(shadowCtx, rule, input, output)
+	}()
+}
+
+func (m *Mirror) send(ctx context.Context, rule Rule, input, output map[string]interface{}) {
+	// Candidates are named explicitly, so traffic splits must not redirect them
+	ctx, cancel := context.WithTimeout(router.Pin(ctx), m.timeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := m.target.RouteRequest(ctx, rule.Model, rule.Candidate, input)
+	latency := time.Since(start)
+
+	record := inferencelog.Record{
+		Model:         rule.Model,
+		Version:       rule.Candidate,
+		Input:         input,
+		Output:        result,
+		LatencyMs:     latency.Milliseconds(),
+		ShadowOf:      rule.Version,
+		PrimaryOutput: output,
+	}
+	status := "success"
+	if err != nil {
+		status = "error"
+		record.Error = err.Error()
+		logging.With(ctx, m.logger).Warn("shadow request failed",
+			zap.String("model", rule.Model),
+			zap.String("candidate", rule.Candidate),
+			zap.Error(err),
+		)
+	}
+	observability.ShadowRequests.WithLabelValues(rule.Model, rule.Candidate, status).Inc()
+	observability.ShadowLatency.WithLabelValues(rule.Model, rule.Candidate).Observe(latency.Seconds())
+	m.capture.Record(ctx, record)
+}
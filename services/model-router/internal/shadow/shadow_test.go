@@ -0,0 +1,174 @@
+package shadow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/inferencelog"
+	"github.com/yourusername/ai-platform/pkg/publish"
+)
+
+type routerFunc func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error)
+
+func (f routerFunc) RouteRequest(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+	return f(ctx, model, version, input)
+}
+
+// recorder runs a capture that publishes records to a channel
+func recorder(t *testing.T) (*inferencelog.Capture, <-chan inferencelog.Record) {
+	records := make(chan inferencelog.Record, 10)
+	capture := inferencelog.NewCapture(inferencelog.Config{Enabled: true, MaxPayloadBytes: 1 << 20}, "model-router",
+		publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+			var record inferencelog.Record
+			require.NoError(t, json.Unmarshal(value, &record))
+			records <- record
+			return nil
+		}), 10, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go capture.Run(ctx)
+	return capture, records
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(`[{"model": "resnet18", "version": "v1", "candidate": "v2", "percent": 10}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{{Model: "resnet18", Version: "v1", Candidate: "v2", Percent: 10}}, rules)
+
+	rules, err = ParseRules("")
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+
+	for name, value := range map[string]string{
+		"not json":           `resnet18=v2`,
+		"no candidate":       `[{"model": "resnet18", "version": "v1", "percent": 10}]`,
+		"mirrored to itself": `[{"model": "resnet18", "version": "v1", "candidate": "v1", "percent": 10}]`,
+		"no percent":         `[{"model": "resnet18", "version": "v1", "candidate": "v2"}]`,
+		"above 100 percent":  `[{"model": "resnet18", "version": "v1", "candidate": "v2", "percent": 150}]`,
+		"mirrored twice": `[{"model": "resnet18", "version": "v1", "candidate": "v2", "percent": 10},
+			{"model": "resnet18", "version": "v1", "candidate": "v3", "percent": 10}]`,
+	} {
+		_, err := ParseRules(value)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestMirror_RecordsCandidateNextToPrimary(t *testing.T) {
+	capture, records := recorder(t)
+	candidate := routerFunc(func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+		assert.Equal(t, "resnet18", model)
+		assert.Equal(t, "v2", version)
+		return map[string]interface{}{"label": "dog"}, nil
+	})
+
+	mirror := NewMirror([]Rule{{Model: "resnet18", Version: "v1", Candidate: "v2", Percent: 100}}, candidate, capture, 1, time.Second, zap.NewNop())
+	mirror.Mirror(context.Background(), "resnet18", "v1", map[string]interface{}{"image": "cat.png"}, map[string]interface{}{"label": "cat"})
+
+	select {
+	case record := <-records:
+		assert.Equal(t, "resnet18", record.Model)
+		assert.Equal(t, "v2", record.Version)
+		assert.Equal(t, "v1", record.ShadowOf)
+		assert.Equal(t, map[string]interface{}{"image": "cat.png"}, record.Input)
+		assert.Equal(t, map[string]interface{}{"label": "dog"}, record.Output)
+		assert.Equal(t, map[string]interface{}{"label": "cat"}, record.PrimaryOutput)
+		assert.Empty(t, record.Error)
+	case <-time.After(time.Second):
+		t.Fatal("shadow inference was not recorded")
+	}
+}
+
+func TestMirror_RecordsCandidateFailures(t *testing.T) {
+	capture, records := recorder(t)
+	candidate := routerFunc(func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+		return nil, errors.New("candidate down")
+	})
+
+	mirror := NewMirror([]Rule{{Model: "resnet18", Version: "v1", Candidate: "v2", Percent: 100}}, candidate, capture, 1, time.Second, zap.NewNop())
+	mirror.Mirror(context.Background(), "resnet18", "v1", map[string]interface{}{}, map[string]interface{}{"label": "cat"})
+
+	select {
+	case record := <-records:
+		assert.Equal(t, "candidate down", record.Error)
+		assert.Equal(t, "v1", record.ShadowOf)
+	case <-time.After(time.Second):
+		t.Fatal("shadow failure was not recorded")
+	}
+}
+
+func TestMirror_SamplesAndSkipsOtherVersions(t *testing.T) {
+	calls := make(chan string, 10)
+	candidate := routerFunc(func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+		calls <- model + "/" + version
+		return nil, nil
+	})
+
+	mirror := NewMirror([]Rule{{Model: "resnet18", Version: "v1", Candidate: "v2", Percent: 10}}, candidate, nil, 1, time.Second, zap.NewNop())
+	mirror.sample = func() float64 { return 0.5 }
+	mirror.Mirror(context.Background(), "resnet18", "v1", nil, nil)
+	mirror.Mirror(context.Background(), "resnet18", "v3", nil, nil)
+	mirror.Mirror(context.Background(), "bert", "v1", nil, nil)
+
+	mirror.sample = func() float64 { return 0.05 }
+	mirror.Mirror(context.Background(), "resnet18", "v1", nil, nil)
+
+	assert.Equal(t, "resnet18/v2", <-calls)
+	select {
+	case call := <-calls:
+		t.Fatalf("unexpected shadow request to %s", call)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMirror_DropsWhenSaturated(t *testing.T) {
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	candidate := routerFunc(func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+		started <- struct{}{}
+		<-release
+		return nil, nil
+	})
+
+	mirror := NewMirror([]Rule{{Model: "resnet18", Version: "v1", Candidate: "v2", Percent: 100}}, candidate, nil, 1, time.Second, zap.NewNop())
+	mirror.Mirror(context.Background(), "resnet18", "v1", nil, nil)
+	<-started
+	mirror.Mirror(context.Background(), "resnet18", "v1", nil, nil)
+	close(release)
+
+	assert.Equal(t, int64(1), mirror.dropped.Load())
+	select {
+	case <-started:
+		t.Fatal("request mirrored while saturated was sent")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMirror_OutlivesTheRequest(t *testing.T) {
+	done := make(chan error, 1)
+	candidate := routerFunc(func(ctx context.Context, model, version string, input map[string]interface{}) (map[string]interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		done <- ctx.Err()
+		return nil, nil
+	})
+
+	mirror := NewMirror([]Rule{{Model: "resnet18", Version: "v1", Candidate: "v2", Percent: 100}}, candidate, nil, 1, time.Second, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	mirror.Mirror(ctx, "resnet18", "v1", nil, nil)
+	cancel()
+
+	assert.NoError(t, <-done)
+}
+
+func TestNewMirror_NoRules(t *testing.T) {
+	mirror := NewMirror(nil, nil, nil, 1, time.Second, zap.NewNop())
+	assert.Nil(t, mirror)
+	mirror.Mirror(context.Background(), "resnet18", "v1", nil, nil)
+}
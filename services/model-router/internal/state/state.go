@@ -0,0 +1,97 @@
+// Package state shares what router replicas learn about their backends
+// through Redis: health, ejections, latency and error rate averages and
+// circuit breaker states. Each replica publishes its view of every backend
+// and merges the others', so that replicas route alike and a new replica
+// starts from what the others already know.
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Key is the Redis hash the backends' states are kept in, one field per
+// replica and backend
+const Key = "model-router:backends"
+
+// Backend is a replica's view of one backend of a model version
+type Backend struct {
+	Replica     string        `json:"replica"`
+	Model       string        `json:"model"`
+	Version     string        `json:"version"`
+	URL         string        `json:"url"`
+	Healthy     bool          `json:"healthy"`
+	Ejected     bool          `json:"ejected"`
+	LastCheck   time.Time     `json:"last_check"`
+	AvgLatency  time.Duration `json:"avg_latency_ns"`
+	ErrorRate   float64       `json:"error_rate"`
+	CircuitOpen bool          `json:"circuit_open"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// field is the hash field of the backend's state
+func (b Backend) field() string {
+	return strings.Join([]string{b.Replica, b.Model, b.Version, b.URL}, "|")
+}
+
+// Store keeps the backends' states in Redis. States not updated within
+// staleAfter, such as those of replicas that stopped, are dropped.
+type Store struct {
+	redisClient *redis.Client
+	staleAfter  time.Duration
+}
+
+// NewStore creates a store of the backends' states in Redis
+func NewStore(redisClient *redis.Client, staleAfter time.Duration) *Store {
+	return &Store{redisClient: redisClient, staleAfter: staleAfter}
+}
+
+// Publish stores the replica's view of its backends
+func (s *Store) Publish(ctx context.Context, backends []Backend) error {
+	if len(backends) == 0 {
+		return nil
+	}
+	values := make([]interface{}, 0, 2*len(backends))
+	for _, backend := range backends {
+		data, err := json.Marshal(backend)
+		if err != nil {
+			return err
+		}
+		values = append(values, backend.field(), data)
+	}
+	return s.redisClient.HSet(ctx, Key, values...).Err()
+}
+
+// Load returns every replica's view of its backends, removing the stale ones
+func (s *Store) Load(ctx context.Context) ([]Backend, error) {
+	fields, err := s.redisClient.HGetAll(ctx, Key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	backends := make([]Backend, 0, len(fields))
+	var stale []string
+	for field, value := range fields {
+		var backend Backend
+		if err := json.Unmarshal([]byte(value), &backend); err != nil || s.stale(backend) {
+			stale = append(stale, field)
+			continue
+		}
+		backends = append(backends, backend)
+	}
+	if len(stale) > 0 {
+		if err := s.redisClient.HDel(ctx, Key, stale...).Err(); err != nil {
+			return nil, err
+		}
+	}
+	return backends, nil
+}
+
+// stale reports whether the backend's state is too old to be trusted
+func (s *Store) stale(backend Backend) bool {
+	return s.staleAfter > 0 && time.Since(backend.UpdatedAt) > s.staleAfter
+}
@@ -0,0 +1,53 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_PublishLoad(t *testing.T) {
+	client := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not available:", err)
+	}
+	ctx := context.Background()
+	client.Del(ctx, Key)
+	defer client.Del(ctx, Key)
+
+	store := NewStore(client, time.Minute)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	fresh := Backend{
+		Replica: "router-a", Model: "llama", Version: "v1", URL: "http://gpu-1:8082",
+		Healthy: true, LastCheck: now, AvgLatency: 120 * time.Millisecond, ErrorRate: 0.1, UpdatedAt: now,
+	}
+	stale := Backend{
+		Replica: "router-b", Model: "llama", Version: "v1", URL: "http://gpu-1:8082",
+		UpdatedAt: now.Add(-time.Hour),
+	}
+	require.NoError(t, store.Publish(ctx, []Backend{fresh, stale}))
+
+	backends, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, backends, 1, "stale states are dropped")
+	assert.True(t, fresh.LastCheck.Equal(backends[0].LastCheck))
+	backends[0].LastCheck, backends[0].UpdatedAt = fresh.LastCheck, fresh.UpdatedAt
+	assert.Equal(t, fresh, backends[0])
+
+	fields, err := client.HKeys(ctx, Key).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"router-a|llama|v1|http://gpu-1:8082"}, fields, "stale states are removed from Redis")
+}
+
+func TestStore_Stale(t *testing.T) {
+	store := NewStore(nil, time.Minute)
+	assert.False(t, store.stale(Backend{UpdatedAt: time.Now()}))
+	assert.True(t, store.stale(Backend{UpdatedAt: time.Now().Add(-2 * time.Minute)}))
+	assert.False(t, NewStore(nil, 0).stale(Backend{}), "states never go stale without staleAfter")
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/notification-service/internal/config"
+	"github.com/yourusername/ai-platform/notification-service/internal/consumer"
+	"github.com/yourusername/ai-platform/notification-service/internal/notifier"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/transport"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// Initialize logger
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize logger: %v", err))
+	}
+	defer logger.Sync()
+
+	// Load configuration
+	cfg := config.Load()
+	logger.Info("configuration loaded",
+		zap.String("service", cfg.ServiceName),
+		zap.String("port", cfg.Port),
+		zap.String("rules_file", cfg.RulesFile),
+	)
+
+	// Channels and routing rules
+	rules, err := notifier.LoadRules(cfg.RulesFile)
+	if err != nil {
+		logger.Fatal("failed to load notification rules", zap.Error(err))
+	}
+	logger.Info("notification rules loaded",
+		zap.Int("channels", len(rules.Channels)),
+		zap.Int("rules", len(rules.Rules)),
+	)
+
+	// Load the SPIFFE workload identity for mTLS between services
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+	}
+
+	// Channels are outside the mesh, so deliveries use a plain client
+	eventNotifier := notifier.NewNotifier(
+		rules,
+		notifier.NewHTTPSender(&http.Client{Timeout: cfg.DeliveryTimeout}),
+		cfg.DeliveryAttempts,
+		cfg.DeliveryBackoff,
+		logger,
+	)
+
+	// Check events against their registered schema when a registry is configured
+	schemaCodec := schema.FromEnv()
+	if err := schemaCodec.Register(context.Background(), schema.PlatformEvents); apperrors.Is(err, apperrors.FailedPrecondition) {
+		logger.Fatal("message schema is incompatible with the registry", zap.Error(err))
+	} else if err != nil {
+		logger.Warn("failed to register message schemas", zap.Error(err))
+	}
+
+	// Readiness requires the brokers
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("kafka", health.TCPCheck(cfg.KafkaBrokers...))
+
+	kafkaConsumer, err := consumer.NewKafkaConsumer(
+		cfg.KafkaBrokers,
+		cfg.EventTopic,
+		cfg.ConsumerGroup,
+		eventNotifier,
+		logger,
+	)
+	if err != nil {
+		logger.Fatal("failed to create kafka consumer", zap.Error(err))
+	}
+	kafkaConsumer.SetCodec(schemaCodec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		if err := kafkaConsumer.Start(ctx); err != nil {
+			logger.Error("kafka consumer error", zap.Error(err))
+		}
+	}()
+
+	// Setup router
+	if cfg.LogLevel == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Health checks
+	router.GET("/health", gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.LivenessPath, gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.ReadinessPath, gin.WrapH(checker.ReadinessHandler()))
+
+	// Recent deliveries, for checking rules and channels
+	router.GET("/v1/deliveries", gin.WrapH(eventNotifier))
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      logging.Middleware(router),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	go func() {
+		logger.Info("starting notification service", zap.String("port", cfg.Port))
+		if err := transport.ListenAndServe(srv, identity); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("failed to start server", zap.Error(err))
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("shutting down server...")
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Fatal("server forced to shutdown", zap.Error(err))
+	}
+
+	logger.Info("server exited")
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the notification service configuration
+type Config struct {
+	ServiceName    string
+	Port           string
+	KafkaBrokers   []string
+	EventTopic     string
+	ConsumerGroup  string
+	RulesFile      string
+	JaegerEndpoint string
+	LogLevel       string
+
+	// Delivery
+	DeliveryTimeout  time.Duration
+	DeliveryAttempts int
+	DeliveryBackoff  time.Duration
+}
+
+// Load loads configuration from environment variables
+func Load() *Config {
+	return &Config{
+		ServiceName:    getEnv("SERVICE_NAME", "notification-service"),
+		Port:           getEnv("PORT", "8089"),
+		KafkaBrokers:   strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ","),
+		EventTopic:     getEnv("EVENT_TOPIC", "platform-events"),
+		ConsumerGroup:  getEnv("CONSUMER_GROUP", "notification-service"),
+		RulesFile:      getEnv("NOTIFICATION_RULES_FILE", "/etc/notification-service/rules.json"),
+		JaegerEndpoint: getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
+		LogLevel:       getEnv("LOG_LEVEL", "info"),
+
+		DeliveryTimeout:  getEnvDuration("DELIVERY_TIMEOUT", 10*time.Second),
+		DeliveryAttempts: getEnvInt("DELIVERY_ATTEMPTS", 3),
+		DeliveryBackoff:  getEnvDuration("DELIVERY_BACKOFF", time.Second),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,122 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"go.uber.org/zap"
+)
+
+// Notifier delivers platform events to their channels
+type Notifier interface {
+	Notify(ctx context.Context, event events.Event) error
+}
+
+// KafkaConsumer feeds platform events from Kafka to the notifier
+type KafkaConsumer struct {
+	consumer sarama.ConsumerGroup
+	topic    string
+	handler  *consumerGroupHandler
+	logger   *zap.Logger
+}
+
+// NewKafkaConsumer creates a consumer for the platform event topic
+func NewKafkaConsumer(brokers []string, topic, groupID string, notifier Notifier, logger *zap.Logger) (*KafkaConsumer, error) {
+	config := sarama.NewConfig()
+	config.Version = sarama.V3_3_0_0
+	config.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRoundRobin
+	// Notifications are about what is happening now; a new group skips the backlog
+	config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	config.Consumer.Return.Errors = true
+
+	consumer, err := sarama.NewConsumerGroup(brokers, groupID, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	return &KafkaConsumer{
+		consumer: consumer,
+		topic:    topic,
+		handler:  &consumerGroupHandler{notifier: notifier, logger: logger},
+		logger:   logger,
+	}, nil
+}
+
+// SetCodec checks platform events against their registered schema
+func (c *KafkaConsumer) SetCodec(codec *schema.Codec) {
+	c.handler.codec = codec
+}
+
+// Start consumes platform events until ctx is cancelled
+func (c *KafkaConsumer) Start(ctx context.Context) error {
+	c.logger.Info("starting platform event consumer", zap.String("topic", c.topic))
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("shutting down platform event consumer")
+			return c.consumer.Close()
+		default:
+			if err := c.consumer.Consume(ctx, []string{c.topic}, c.handler); err != nil {
+				c.logger.Error("consumer error", zap.Error(err))
+				return err
+			}
+		}
+	}
+}
+
+// consumerGroupHandler implements sarama.ConsumerGroupHandler
+type consumerGroupHandler struct {
+	notifier Notifier
+	codec    *schema.Codec
+	logger   *zap.Logger
+}
+
+// Setup is run at the beginning of a new session
+func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// Cleanup is run at the end of a session
+func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim notifies each event and marks it consumed. The notifier
+// already retries each channel, so an event whose deliveries still fail is
+// not redelivered: holding the partition would delay every later event.
+func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case <-session.Context().Done():
+			return nil
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if message == nil {
+				continue
+			}
+			h.handle(session.Context(), message)
+			session.MarkMessage(message, "")
+		}
+	}
+}
+
+func (h *consumerGroupHandler) handle(ctx context.Context, message *sarama.ConsumerMessage) {
+	var event events.Event
+	if err := h.codec.Decode(ctx, schema.PlatformEvents, message.Value, &event); err != nil {
+		h.logger.Error("discarding malformed platform event",
+			zap.Int32("partition", message.Partition),
+			zap.Int64("offset", message.Offset),
+			zap.Error(err),
+		)
+		return
+	}
+
+	// Failed deliveries are logged by the notifier
+	h.notifier.Notify(ctx, event)
+}
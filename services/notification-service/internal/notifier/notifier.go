@@ -0,0 +1,238 @@
+// Package notifier routes platform events to notification channels
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"go.uber.org/zap"
+)
+
+// maxRecent bounds how many deliveries are kept for inspection
+const maxRecent = 200
+
+// Delivery outcomes
+const (
+	StatusSent       = "sent"
+	StatusFailed     = "failed"
+	StatusSuppressed = "suppressed"
+)
+
+// Delivery records one attempt to notify a channel about an event
+type Delivery struct {
+	EventID  string      `json:"event_id"`
+	Type     events.Type `json:"type"`
+	Tenant   string      `json:"tenant,omitempty"`
+	Subject  string      `json:"subject"`
+	Channel  string      `json:"channel"`
+	Status   string      `json:"status"`
+	Attempts int         `json:"attempts,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	At       time.Time   `json:"at"`
+}
+
+// Notifier fans events out to the channels their rules select. Events
+// redelivered by Kafka are recognised by ID, and repeats of the same
+// notification to a channel are suppressed for the rules' dedup window.
+type Notifier struct {
+	rules    *Rules
+	sender   Sender
+	attempts int
+	backoff  time.Duration
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu     sync.Mutex
+	events map[string]time.Time // event ID -> first seen
+	sent   map[string]time.Time // channel, type, tenant and subject -> last sent
+	pruned time.Time
+	recent []Delivery
+}
+
+// NewNotifier creates a notifier that tries each delivery up to attempts
+// times, doubling backoff between tries
+func NewNotifier(rules *Rules, sender Sender, attempts int, backoff time.Duration, logger *zap.Logger) *Notifier {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &Notifier{
+		rules:    rules,
+		sender:   sender,
+		attempts: attempts,
+		backoff:  backoff,
+		logger:   logger,
+		now:      time.Now,
+		events:   make(map[string]time.Time),
+		sent:     make(map[string]time.Time),
+	}
+}
+
+// Notify delivers an event to every channel routed to it. Channels are tried
+// independently; the returned error joins the deliveries that failed.
+func (n *Notifier) Notify(ctx context.Context, event events.Event) error {
+	now := n.now()
+	window := time.Duration(n.rules.DedupWindow)
+
+	n.mu.Lock()
+	n.prune(now, window)
+	if _, ok := n.events[event.ID]; ok && event.ID != "" {
+		n.mu.Unlock()
+		return nil
+	}
+	n.events[event.ID] = now
+	n.mu.Unlock()
+
+	var errs []error
+	for _, channel := range n.rules.Route(event) {
+		delivery := Delivery{
+			EventID: event.ID,
+			Type:    event.Type,
+			Tenant:  event.Tenant,
+			Subject: event.Subject,
+			Channel: channel.Name,
+		}
+
+		key := channel.Name + "|" + string(event.Type) + "|" + event.Tenant + "|" + event.Subject
+		if !n.reserve(key, now, window) {
+			delivery.Status = StatusSuppressed
+			n.record(delivery)
+			continue
+		}
+
+		attempts, err := n.deliver(ctx, channel, event)
+		delivery.Attempts = attempts
+		if err != nil {
+			// A later repeat may try this channel again
+			n.release(key)
+			delivery.Status = StatusFailed
+			delivery.Error = err.Error()
+			n.logger.Error("failed to deliver notification",
+				zap.String("event_id", event.ID),
+				zap.String("type", string(event.Type)),
+				zap.String("channel", channel.Name),
+				zap.Int("attempts", attempts),
+				zap.Error(err),
+			)
+			errs = append(errs, err)
+		} else {
+			delivery.Status = StatusSent
+		}
+		n.record(delivery)
+	}
+	return errors.Join(errs...)
+}
+
+// deliver sends with retries, returning how many attempts were made
+func (n *Notifier) deliver(ctx context.Context, channel *Channel, event events.Event) (int, error) {
+	message, err := channel.Render(event)
+	if err != nil {
+		return 0, err
+	}
+
+	backoff := n.backoff
+	for attempt := 1; ; attempt++ {
+		err = n.sender.Send(ctx, channel, event, message)
+		if err == nil || attempt == n.attempts || !retryable(err) {
+			return attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// retryable reports whether a delivery may succeed if tried again; receivers
+// that reject the request outright are not retried
+func retryable(err error) bool {
+	return apperrors.Retryable(err) || apperrors.Is(err, apperrors.Internal)
+}
+
+// reserve claims a notification key unless it was sent within the window
+func (n *Notifier) reserve(key string, now time.Time, window time.Duration) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if last, ok := n.sent[key]; ok && now.Sub(last) < window {
+		return false
+	}
+	n.sent[key] = now
+	return true
+}
+
+func (n *Notifier) release(key string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.sent, key)
+}
+
+// prune forgets events and notifications older than the window, at most once a minute
+func (n *Notifier) prune(now time.Time, window time.Duration) {
+	if now.Sub(n.pruned) < time.Minute {
+		return
+	}
+	n.pruned = now
+	for id, seen := range n.events {
+		if now.Sub(seen) >= window {
+			delete(n.events, id)
+		}
+	}
+	for key, sent := range n.sent {
+		if now.Sub(sent) >= window {
+			delete(n.sent, key)
+		}
+	}
+}
+
+func (n *Notifier) record(delivery Delivery) {
+	delivery.At = n.now()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.recent = append(n.recent, delivery)
+	if len(n.recent) > maxRecent {
+		n.recent = n.recent[len(n.recent)-maxRecent:]
+	}
+}
+
+// Deliveries returns the most recent deliveries, newest first
+func (n *Notifier) Deliveries() []Delivery {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	deliveries := make([]Delivery, len(n.recent))
+	for i, delivery := range n.recent {
+		deliveries[len(n.recent)-1-i] = delivery
+	}
+	return deliveries
+}
+
+// ServeHTTP lists recent deliveries, optionally filtered by channel or status
+func (n *Notifier) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apperrors.WriteHTTP(w, apperrors.New(apperrors.Unimplemented, "method not allowed"))
+		return
+	}
+
+	channel, status := r.URL.Query().Get("channel"), r.URL.Query().Get("status")
+	deliveries := make([]Delivery, 0)
+	for _, delivery := range n.Deliveries() {
+		if (channel == "" || delivery.Channel == channel) && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	})
+}
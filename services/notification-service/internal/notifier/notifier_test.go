@@ -0,0 +1,128 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+)
+
+type sent struct {
+	channel string
+	message string
+}
+
+type fakeSender struct {
+	sent     []sent
+	failures []error
+}
+
+func (f *fakeSender) Send(ctx context.Context, channel *Channel, event events.Event, message string) error {
+	if len(f.failures) > 0 {
+		err := f.failures[0]
+		f.failures = f.failures[1:]
+		return err
+	}
+	f.sent = append(f.sent, sent{channel: channel.Name, message: message})
+	return nil
+}
+
+func TestNotifier_DeduplicatesAndRoutes(t *testing.T) {
+	rules, err := ParseRules([]byte(testRules))
+	require.NoError(t, err)
+	sender := &fakeSender{}
+	n := NewNotifier(rules, sender, 3, time.Millisecond, zap.NewNop())
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	n.now = func() time.Time { return now }
+
+	opened := events.Event{ID: "e1", Type: events.CircuitOpened, Severity: events.SeverityCritical, Subject: "resnet18/v1"}
+	require.NoError(t, n.Notify(context.Background(), opened))
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "[critical] circuit.opened resnet18/v1", sender.sent[0].message)
+
+	// Kafka redelivery of the same event
+	require.NoError(t, n.Notify(context.Background(), opened))
+	// The breaker trips again within the window
+	opened.ID = "e2"
+	require.NoError(t, n.Notify(context.Background(), opened))
+	assert.Len(t, sender.sent, 1)
+
+	// After the window the repeat is worth sending
+	now = now.Add(6 * time.Minute)
+	opened.ID = "e3"
+	require.NoError(t, n.Notify(context.Background(), opened))
+	assert.Len(t, sender.sent, 2)
+
+	deliveries := n.Deliveries()
+	require.Len(t, deliveries, 3)
+	assert.Equal(t, StatusSent, deliveries[0].Status)
+	assert.Equal(t, StatusSuppressed, deliveries[1].Status)
+	assert.Equal(t, "e2", deliveries[1].EventID)
+}
+
+func TestNotifier_RetriesTransientFailures(t *testing.T) {
+	rules, err := ParseRules([]byte(testRules))
+	require.NoError(t, err)
+	sender := &fakeSender{failures: []error{
+		apperrors.New(apperrors.Unavailable, "bad gateway"),
+		apperrors.New(apperrors.ResourceExhausted, "slow down"),
+	}}
+	n := NewNotifier(rules, sender, 3, time.Millisecond, zap.NewNop())
+
+	failed := events.Event{ID: "e1", Type: events.JobFailed, Severity: events.SeverityWarning, Tenant: "acme", Subject: "job-1"}
+	require.NoError(t, n.Notify(context.Background(), failed))
+	assert.Len(t, sender.sent, 2, "acme-hook after two retries, then oncall")
+	assert.Equal(t, 3, n.Deliveries()[1].Attempts)
+
+	// Rejected requests are not retried, and the failure does not suppress a later repeat
+	sender.sent = nil
+	sender.failures = []error{apperrors.New(apperrors.PermissionDenied, "forbidden")}
+	completed := events.Event{ID: "e2", Type: events.JobCompleted, Tenant: "acme", Subject: "job-2"}
+	assert.Error(t, n.Notify(context.Background(), completed))
+	assert.Empty(t, sender.sent)
+	assert.Equal(t, 1, n.Deliveries()[0].Attempts)
+
+	completed.ID = "e3"
+	require.NoError(t, n.Notify(context.Background(), completed))
+	assert.Len(t, sender.sent, 1)
+}
+
+func TestHTTPSender(t *testing.T) {
+	var slack map[string]string
+	var hook webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slack":
+			json.NewDecoder(r.Body).Decode(&slack)
+		case "/hook":
+			assert.Equal(t, "secret", r.Header.Get("X-Token"))
+			json.NewDecoder(r.Body).Decode(&hook)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	sender := NewHTTPSender(server.Client())
+	event := events.Event{ID: "e1", Type: events.ModelPromoted, Subject: "resnet18/v2"}
+
+	require.NoError(t, sender.Send(context.Background(), &Channel{Name: "s", Type: Slack, URL: server.URL + "/slack"}, event, "promoted"))
+	assert.Equal(t, "promoted", slack["text"])
+
+	hookChannel := &Channel{Name: "h", Type: Webhook, URL: server.URL + "/hook", Headers: map[string]string{"X-Token": "secret"}}
+	require.NoError(t, sender.Send(context.Background(), hookChannel, event, "promoted"))
+	assert.Equal(t, "e1", hook.Event.ID)
+	assert.Equal(t, "promoted", hook.Message)
+
+	err := sender.Send(context.Background(), &Channel{Name: "x", Type: Webhook, URL: server.URL + "/missing"}, event, "promoted")
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable))
+}
@@ -0,0 +1,197 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/events"
+)
+
+// ChannelType says how a channel's messages are encoded
+type ChannelType string
+
+const (
+	// Webhook posts the event and the rendered message as JSON
+	Webhook ChannelType = "webhook"
+	// Slack posts the rendered message to an incoming webhook
+	Slack ChannelType = "slack"
+)
+
+// defaultTemplate renders events for channels that do not set their own
+const defaultTemplate = `[{{.Severity}}] {{.Type}} {{.Subject}}{{if .Tenant}} (tenant {{.Tenant}}){{end}}`
+
+// defaultDedupWindow suppresses repeats of the same event when rules leave it unset
+const defaultDedupWindow = 10 * time.Minute
+
+// Duration is a time.Duration that encodes as a string such as "10m"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"10m\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Channel is a destination for notifications
+type Channel struct {
+	Name     string            `json:"name"`
+	Type     ChannelType       `json:"type"`
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Template string            `json:"template,omitempty"` // text/template over the event
+
+	tmpl *template.Template
+}
+
+// Render formats an event with the channel's template
+func (c *Channel) Render(event events.Event) (string, error) {
+	var buf bytes.Buffer
+	if err := c.tmpl.Execute(&buf, event); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", c.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// Rule routes matching events to channels. Empty selectors match everything.
+type Rule struct {
+	Tenant      string          `json:"tenant,omitempty"`
+	Types       []events.Type   `json:"types,omitempty"`
+	MinSeverity events.Severity `json:"min_severity,omitempty"`
+	Channels    []string        `json:"channels"`
+}
+
+// Matches reports whether the rule applies to an event
+func (r *Rule) Matches(event events.Event) bool {
+	if r.Tenant != "" && r.Tenant != event.Tenant {
+		return false
+	}
+	if event.Severity.Rank() < r.MinSeverity.Rank() {
+		return false
+	}
+	if len(r.Types) == 0 {
+		return true
+	}
+	for _, t := range r.Types {
+		if t == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// Rules configures where events are delivered. Identical notifications about
+// the same subject are sent at most once per dedup window.
+type Rules struct {
+	Channels    []*Channel `json:"channels"`
+	Rules       []Rule     `json:"rules"`
+	DedupWindow Duration   `json:"dedup_window,omitempty"`
+
+	byName map[string]*Channel
+}
+
+// Validate checks that channels are usable and rules only name known channels,
+// and fills in defaults
+func (r *Rules) Validate() error {
+	r.byName = make(map[string]*Channel, len(r.Channels))
+	for i, channel := range r.Channels {
+		if channel.Name == "" {
+			return fmt.Errorf("channel %d needs a name", i)
+		}
+		if _, ok := r.byName[channel.Name]; ok {
+			return fmt.Errorf("channel %q is defined twice", channel.Name)
+		}
+		switch channel.Type {
+		case Webhook, Slack:
+		default:
+			return fmt.Errorf("channel %q has unknown type %q", channel.Name, channel.Type)
+		}
+		if !strings.HasPrefix(channel.URL, "http://") && !strings.HasPrefix(channel.URL, "https://") {
+			return fmt.Errorf("channel %q needs an http(s) url", channel.Name)
+		}
+
+		text := channel.Template
+		if text == "" {
+			text = defaultTemplate
+		}
+		tmpl, err := template.New(channel.Name).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return fmt.Errorf("channel %q template: %w", channel.Name, err)
+		}
+		channel.tmpl = tmpl
+		r.byName[channel.Name] = channel
+	}
+
+	for i, rule := range r.Rules {
+		if len(rule.Channels) == 0 {
+			return fmt.Errorf("rule %d names no channels", i)
+		}
+		for _, name := range rule.Channels {
+			if _, ok := r.byName[name]; !ok {
+				return fmt.Errorf("rule %d names unknown channel %q", i, name)
+			}
+		}
+	}
+
+	if r.DedupWindow == 0 {
+		r.DedupWindow = Duration(defaultDedupWindow)
+	}
+	if r.DedupWindow < 0 {
+		return fmt.Errorf("dedup window must not be negative")
+	}
+	return nil
+}
+
+// Route returns the channels an event is delivered to, each at most once, in rule order
+func (r *Rules) Route(event events.Event) []*Channel {
+	var channels []*Channel
+	seen := make(map[string]bool)
+	for i := range r.Rules {
+		if !r.Rules[i].Matches(event) {
+			continue
+		}
+		for _, name := range r.Rules[i].Channels {
+			if !seen[name] {
+				seen[name] = true
+				channels = append(channels, r.byName[name])
+			}
+		}
+	}
+	return channels
+}
+
+// ParseRules decodes and validates notification rules
+func ParseRules(data []byte) (*Rules, error) {
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse notification rules: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// LoadRules reads notification rules from a JSON file
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification rules: %w", err)
+	}
+	return ParseRules(data)
+}
@@ -0,0 +1,62 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/events"
+)
+
+const testRules = `{
+	"channels": [
+		{"name": "oncall", "type": "slack", "url": "https://hooks.slack.test/oncall"},
+		{"name": "acme-hook", "type": "webhook", "url": "https://acme.test/hook", "template": "{{.Type}} for {{.Attributes.model}}"}
+	],
+	"rules": [
+		{"min_severity": "critical", "channels": ["oncall"]},
+		{"tenant": "acme", "types": ["job.completed", "job.failed"], "channels": ["acme-hook"]},
+		{"tenant": "acme", "types": ["job.failed"], "channels": ["oncall", "acme-hook"]}
+	],
+	"dedup_window": "5m"
+}`
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(testRules))
+	require.NoError(t, err)
+	assert.Equal(t, Duration(5*time.Minute), rules.DedupWindow)
+
+	names := func(channels []*Channel) []string {
+		var out []string
+		for _, c := range channels {
+			out = append(out, c.Name)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"oncall"}, names(rules.Route(events.Event{Type: events.CircuitOpened, Severity: events.SeverityCritical})))
+	assert.Empty(t, rules.Route(events.Event{Type: events.CircuitOpened, Severity: events.SeverityWarning}))
+	assert.Equal(t, []string{"acme-hook"}, names(rules.Route(events.Event{Type: events.JobCompleted, Tenant: "acme"})))
+	assert.Empty(t, rules.Route(events.Event{Type: events.JobCompleted, Tenant: "globex"}))
+	assert.Equal(t, []string{"acme-hook", "oncall"}, names(rules.Route(events.Event{Type: events.JobFailed, Tenant: "acme"})), "each channel once")
+
+	message, err := rules.byName["acme-hook"].Render(events.Event{Type: events.JobCompleted, Attributes: map[string]string{"model": "resnet18"}})
+	require.NoError(t, err)
+	assert.Equal(t, "job.completed for resnet18", message)
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown channel":  `{"channels": [], "rules": [{"channels": ["oncall"]}]}`,
+		"unknown type":     `{"channels": [{"name": "a", "type": "pager", "url": "https://a.test"}]}`,
+		"missing url":      `{"channels": [{"name": "a", "type": "slack"}]}`,
+		"duplicate":        `{"channels": [{"name": "a", "type": "slack", "url": "https://a.test"}, {"name": "a", "type": "slack", "url": "https://a.test"}]}`,
+		"bad template":     `{"channels": [{"name": "a", "type": "slack", "url": "https://a.test", "template": "{{.Type"}]}`,
+		"numeric duration": `{"channels": [], "dedup_window": 300}`,
+	} {
+		_, err := ParseRules([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
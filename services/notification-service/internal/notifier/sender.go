@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+)
+
+// Sender delivers a rendered notification to a channel
+type Sender interface {
+	Send(ctx context.Context, channel *Channel, event events.Event, message string) error
+}
+
+// webhookPayload is posted to webhook channels
+type webhookPayload struct {
+	Event   events.Event `json:"event"`
+	Message string       `json:"message"`
+}
+
+// slackPayload is posted to Slack incoming webhooks
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// HTTPSender posts notifications to webhook and Slack channels
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender creates a sender using client
+func NewHTTPSender(client *http.Client) *HTTPSender {
+	return &HTTPSender{client: client}
+}
+
+// Send posts one notification. Failures carry the code matching the
+// receiver's response so callers can tell transient failures from bad channels.
+func (s *HTTPSender) Send(ctx context.Context, channel *Channel, event events.Event, message string) error {
+	var payload interface{} = webhookPayload{Event: event, Message: message}
+	if channel.Type == Slack {
+		payload = slackPayload{Text: message}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.Internal, "failed to encode notification")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.URL, bytes.NewReader(body))
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.InvalidArgument, "failed to create notification request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range channel.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.Unavailable, fmt.Sprintf("failed to reach channel %s", channel.Name))
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return apperrors.Newf(apperrors.FromHTTPStatus(resp.StatusCode), "channel %s returned status %d", channel.Name, resp.StatusCode)
+	}
+	return nil
+}
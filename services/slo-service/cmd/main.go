@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/publish"
+	"github.com/yourusername/ai-platform/pkg/schema"
+	"github.com/yourusername/ai-platform/pkg/transport"
+	"github.com/yourusername/ai-platform/slo-service/internal/config"
+	"github.com/yourusername/ai-platform/slo-service/internal/handlers"
+	"github.com/yourusername/ai-platform/slo-service/internal/slo"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// Initialize logger
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize logger: %v", err))
+	}
+	defer logger.Sync()
+
+	// Load configuration
+	cfg := config.Load()
+	logger.Info("configuration loaded",
+		zap.String("service", cfg.ServiceName),
+		zap.String("port", cfg.Port),
+		zap.String("prometheus_url", cfg.PrometheusURL),
+		zap.String("definitions_file", cfg.DefinitionsFile),
+	)
+
+	// Objectives to evaluate
+	definitions, err := slo.LoadDefinitions(cfg.DefinitionsFile)
+	if err != nil {
+		logger.Fatal("failed to load SLO definitions", zap.Error(err))
+	}
+	logger.Info("SLO definitions loaded", zap.Int("objectives", len(definitions.Objectives)))
+
+	// Load the SPIFFE workload identity for mTLS between services
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+	}
+
+	// Prometheus is outside the mesh, so queries use a plain client
+	prometheusClient := &http.Client{Timeout: 10 * time.Second}
+	evaluator := slo.NewEvaluator(definitions, slo.NewPrometheusClient(cfg.PrometheusURL, prometheusClient), logger)
+
+	// Readiness requires Prometheus
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("prometheus", health.HTTPCheck(prometheusClient, cfg.PrometheusURL+"/-/ready"))
+
+	// Announce burning SLOs to the notification service when Kafka is configured
+	var eventEmitter *events.Emitter
+	if len(cfg.KafkaBrokers) > 0 {
+		kafkaProducer, err := events.NewKafkaProducer(cfg.KafkaBrokers)
+		if err != nil {
+			logger.Fatal("failed to initialize kafka producer", zap.Error(err))
+		}
+		defer kafkaProducer.Close()
+
+		schemaCodec := schema.FromEnv()
+		if err := schemaCodec.Register(context.Background(), schema.PlatformEvents); apperrors.Is(err, apperrors.FailedPrecondition) {
+			logger.Fatal("message schema is incompatible with the registry", zap.Error(err))
+		} else if err != nil {
+			logger.Warn("failed to register message schemas", zap.Error(err))
+		}
+
+		eventEmitter = events.NewEmitter(cfg.ServiceName, publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+			value, err := schemaCodec.Frame(ctx, schema.PlatformEvents, value)
+			if err != nil {
+				return err
+			}
+			_, _, err = kafkaProducer.SendMessage(&sarama.ProducerMessage{
+				Topic: cfg.EventTopic,
+				Key:   sarama.StringEncoder(key),
+				Value: sarama.ByteEncoder(value),
+			})
+			return err
+		}), 100, logger)
+		evaluator.SetEventEmitter(eventEmitter)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventsDone := make(chan struct{})
+	go func() {
+		if eventEmitter != nil {
+			eventEmitter.Run(ctx)
+		}
+		close(eventsDone)
+	}()
+
+	evaluatorDone := make(chan struct{})
+	go func() {
+		evaluator.Run(ctx, cfg.EvalInterval)
+		close(evaluatorDone)
+	}()
+
+	// Setup router
+	if cfg.LogLevel == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Health checks
+	router.GET("/health", gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.LivenessPath, gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.ReadinessPath, gin.WrapH(checker.ReadinessHandler()))
+
+	// Error budgets and burn rates, scraped by Prometheus for alerting
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// SLO statuses and burn rates
+	sloHandler := handlers.NewSLOHandler(evaluator, logger)
+	sloHandler.Register(router.Group("/v1"))
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      logging.Middleware(router),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	go func() {
+		logger.Info("starting SLO service", zap.String("port", cfg.Port))
+		if err := transport.ListenAndServe(srv, identity); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("failed to start server", zap.Error(err))
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("shutting down server...")
+	cancel()
+	<-evaluatorDone
+	<-eventsDone
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Fatal("server forced to shutdown", zap.Error(err))
+	}
+
+	logger.Info("server exited")
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// Config holds the SLO service configuration
+type Config struct {
+	ServiceName     string
+	Port            string
+	PrometheusURL   string
+	DefinitionsFile string
+	EvalInterval    time.Duration
+	JaegerEndpoint  string
+	LogLevel        string
+
+	// slo.burning events are only published when brokers are configured
+	KafkaBrokers []string
+	EventTopic   string
+}
+
+// Load loads configuration from environment variables
+func Load() *Config {
+	return &Config{
+		ServiceName:     getEnv("SERVICE_NAME", "slo-service"),
+		Port:            getEnv("PORT", "8091"),
+		PrometheusURL:   getEnv("PROMETHEUS_URL", "http://localhost:9090"),
+		DefinitionsFile: getEnv("SLO_DEFINITIONS_FILE", "/etc/slo-service/slos.json"),
+		EvalInterval:    getEnvDuration("SLO_EVAL_INTERVAL", time.Minute),
+		JaegerEndpoint:  getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
+		LogLevel:        getEnv("LOG_LEVEL", "info"),
+
+		KafkaBrokers: getEnvList("KAFKA_BROKERS"),
+		EventTopic:   getEnv("EVENT_TOPIC", "platform-events"),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvList(key string) []string {
+	if value := os.Getenv(key); value != "" {
+		return strings.Split(value, ",")
+	}
+	return nil
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/slo-service/internal/slo"
+)
+
+// Evaluator reports SLO statuses and burn rates
+type Evaluator interface {
+	Statuses() []slo.Status
+	Status(name string) (slo.Status, error)
+	BurnRate(ctx context.Context, name string, window time.Duration, now time.Time) (*slo.BurnRate, error)
+}
+
+// SLOHandler serves SLO statuses and error budgets
+type SLOHandler struct {
+	evaluator Evaluator
+	logger    *zap.Logger
+	now       func() time.Time
+}
+
+// NewSLOHandler creates a new SLO handler
+func NewSLOHandler(evaluator Evaluator, logger *zap.Logger) *SLOHandler {
+	return &SLOHandler{
+		evaluator: evaluator,
+		logger:    logger,
+		now:       func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Register adds the SLO routes to a router group
+func (h *SLOHandler) Register(group *gin.RouterGroup) {
+	group.GET("/slos", h.ListSLOs)
+	group.GET("/slos/:name", h.GetSLO)
+	group.GET("/slos/:name/burn-rate", h.GetBurnRate)
+}
+
+// ListSLOs returns the latest status of every SLO
+func (h *SLOHandler) ListSLOs(c *gin.Context) {
+	statuses := h.evaluator.Statuses()
+	c.JSON(http.StatusOK, gin.H{
+		"slos":  statuses,
+		"count": len(statuses),
+	})
+}
+
+// GetSLO returns the latest status of one SLO
+func (h *SLOHandler) GetSLO(c *gin.Context) {
+	status, err := h.evaluator.Status(c.Param("name"))
+	if err != nil {
+		c.JSON(apperrors.ToHTTP(err))
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// GetBurnRate measures an SLO's burn rate over the window query parameter, 1h by default
+func (h *SLOHandler) GetBurnRate(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	window, err := time.ParseDuration(c.DefaultQuery("window", "1h"))
+	if err != nil {
+		c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.InvalidArgument, "window must be a duration such as 1h").WithDetails(err.Error())))
+		return
+	}
+
+	rate, err := h.evaluator.BurnRate(ctx, c.Param("name"), window, h.now())
+	if err != nil {
+		if !apperrors.Is(err, apperrors.NotFound) && !apperrors.Is(err, apperrors.InvalidArgument) {
+			logging.With(ctx, h.logger).Error("failed to measure burn rate", zap.String("slo", c.Param("name")), zap.Error(err))
+		}
+		c.JSON(apperrors.ToHTTP(err))
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/slo-service/internal/slo"
+)
+
+// fakeEvaluator serves fixed statuses and a burn rate of two
+type fakeEvaluator struct {
+	statuses map[string]slo.Status
+	windows  []time.Duration
+}
+
+func (e *fakeEvaluator) Statuses() []slo.Status {
+	statuses := []slo.Status{}
+	for _, status := range e.statuses {
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func (e *fakeEvaluator) Status(name string) (slo.Status, error) {
+	status, ok := e.statuses[name]
+	if !ok {
+		return slo.Status{}, apperrors.Newf(apperrors.NotFound, "SLO not found: %s", name)
+	}
+	return status, nil
+}
+
+func (e *fakeEvaluator) BurnRate(ctx context.Context, name string, window time.Duration, now time.Time) (*slo.BurnRate, error) {
+	if _, ok := e.statuses[name]; !ok {
+		return nil, apperrors.Newf(apperrors.NotFound, "SLO not found: %s", name)
+	}
+	e.windows = append(e.windows, window)
+	return &slo.BurnRate{SLO: name, Window: window.String(), ErrorRatio: 0.002, BurnRate: 2, At: now}, nil
+}
+
+func setupRouter(evaluator Evaluator) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewSLOHandler(evaluator, zap.NewNop())
+	router := gin.New()
+	handler.Register(router.Group("/v1"))
+	return router
+}
+
+func get(router *gin.Engine, url string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+	return w
+}
+
+func TestSLOHandler(t *testing.T) {
+	evaluator := &fakeEvaluator{statuses: map[string]slo.Status{
+		"platform": {
+			Objective:       slo.Objective{Name: "platform", Kind: slo.Availability, Target: 0.999, Window: slo.Duration(720 * time.Hour)},
+			BudgetRemaining: 0.75,
+			State:           slo.StateOK,
+		},
+	}}
+	router := setupRouter(evaluator)
+
+	w := get(router, "/v1/slos")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	w = get(router, "/v1/slos/platform")
+	require.Equal(t, http.StatusOK, w.Code)
+	var status map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, "platform", status["name"])
+	assert.Equal(t, "720h0m0s", status["window"])
+	assert.Equal(t, 0.75, status["budget_remaining"])
+	assert.Equal(t, "ok", status["state"])
+
+	assert.Equal(t, http.StatusNotFound, get(router, "/v1/slos/missing").Code)
+}
+
+func TestSLOHandler_BurnRate(t *testing.T) {
+	evaluator := &fakeEvaluator{statuses: map[string]slo.Status{"platform": {}}}
+	router := setupRouter(evaluator)
+
+	w := get(router, "/v1/slos/platform/burn-rate")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"burn_rate":2`)
+
+	require.Equal(t, http.StatusOK, get(router, "/v1/slos/platform/burn-rate?window=6h").Code)
+	assert.Equal(t, []time.Duration{time.Hour, 6 * time.Hour}, evaluator.windows)
+
+	assert.Equal(t, http.StatusBadRequest, get(router, "/v1/slos/platform/burn-rate?window=soon").Code)
+	assert.Equal(t, http.StatusNotFound, get(router, "/v1/slos/missing/burn-rate").Code)
+}
@@ -0,0 +1,187 @@
+// Package slo evaluates service level objectives against the request metrics
+// the API gateway records in Prometheus and tracks their error budgets.
+package slo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind says which requests an objective counts as good
+type Kind string
+
+const (
+	// Availability counts inference requests that succeed
+	Availability Kind = "availability"
+	// Latency counts inference requests served within the objective's threshold
+	Latency Kind = "latency"
+)
+
+// Metrics recorded by the API gateway for every forwarded inference request
+const (
+	requestsMetric = "inference_requests_total"
+	durationMetric = "inference_request_duration_seconds"
+)
+
+// latencyBuckets are the bucket bounds of the gateway's latency histogram;
+// latency thresholds must be one of them
+var latencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// defaultWindow is the compliance window of objectives that leave it unset
+const defaultWindow = 30 * 24 * time.Hour
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Duration is a time.Duration that encodes as a string such as "720h"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"720h\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Objective is a target share of good inference requests over a rolling
+// window, for one model or, without a model, the whole platform
+type Objective struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Model       string  `json:"model,omitempty"`
+	Kind        Kind    `json:"kind"`
+	Target      float64 `json:"target"`
+	// Threshold is the slowest good response of a latency objective
+	Threshold Duration `json:"threshold,omitempty"`
+	Window    Duration `json:"window,omitempty"`
+}
+
+// ErrorBudget is the share of requests that may be bad over the window
+func (o *Objective) ErrorBudget() float64 {
+	return 1 - o.Target
+}
+
+// ErrorRatioQuery returns the PromQL expression for the share of bad
+// requests over window. It evaluates to NaN or nothing without traffic.
+func (o *Objective) ErrorRatioQuery(window time.Duration) string {
+	rng := fmt.Sprintf("[%ds]", int64(window/time.Second))
+	switch o.Kind {
+	case Latency:
+		return fmt.Sprintf("1 - sum(increase(%s_bucket%s%s)) / sum(increase(%s_count%s%s))",
+			durationMetric, o.selector(bucketMatcher(time.Duration(o.Threshold))), rng,
+			durationMetric, o.selector(), rng)
+	default:
+		return fmt.Sprintf(`sum(increase(%s%s%s)) / sum(increase(%s%s%s))`,
+			requestsMetric, o.selector(`status="error"`), rng,
+			requestsMetric, o.selector(), rng)
+	}
+}
+
+// selector restricts a metric to the objective's model
+func (o *Objective) selector(matchers ...string) string {
+	if o.Model != "" {
+		matchers = append(matchers, "model="+strconv.Quote(o.Model))
+	}
+	if len(matchers) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(matchers, ",") + "}"
+}
+
+// bucketMatcher matches the histogram bucket bounded by threshold. Whole
+// numbers are written "1" or "1.0" depending on the Prometheus version.
+func bucketMatcher(threshold time.Duration) string {
+	le := strconv.FormatFloat(threshold.Seconds(), 'f', -1, 64)
+	if !strings.Contains(le, ".") {
+		return fmt.Sprintf(`le=~"%s|%s\\.0"`, le, le)
+	}
+	return fmt.Sprintf(`le="%s"`, le)
+}
+
+// Definitions are the objectives the service evaluates
+type Definitions struct {
+	Objectives []*Objective `json:"objectives"`
+}
+
+// Validate checks every objective and fills in defaults
+func (d *Definitions) Validate() error {
+	seen := make(map[string]bool, len(d.Objectives))
+	for i, o := range d.Objectives {
+		if !namePattern.MatchString(o.Name) {
+			return fmt.Errorf("objective %d needs a name of lowercase letters, digits, '-' and '_'", i)
+		}
+		if seen[o.Name] {
+			return fmt.Errorf("objective %q is defined twice", o.Name)
+		}
+		seen[o.Name] = true
+
+		if o.Target <= 0 || o.Target >= 1 {
+			return fmt.Errorf("objective %q needs a target between 0 and 1", o.Name)
+		}
+		switch o.Kind {
+		case Availability:
+			if o.Threshold != 0 {
+				return fmt.Errorf("objective %q is an availability objective and takes no threshold", o.Name)
+			}
+		case Latency:
+			if !isBucket(time.Duration(o.Threshold)) {
+				return fmt.Errorf("objective %q needs a threshold at a latency bucket bound (%v seconds)", o.Name, latencyBuckets)
+			}
+		default:
+			return fmt.Errorf("objective %q has unknown kind %q", o.Name, o.Kind)
+		}
+
+		if o.Window == 0 {
+			o.Window = Duration(defaultWindow)
+		}
+		if time.Duration(o.Window) < 6*time.Hour {
+			return fmt.Errorf("objective %q needs a window of at least 6h", o.Name)
+		}
+	}
+	return nil
+}
+
+func isBucket(threshold time.Duration) bool {
+	for _, bound := range latencyBuckets {
+		if threshold == time.Duration(math.Round(bound*float64(time.Second))) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDefinitions decodes and validates SLO definitions
+func ParseDefinitions(data []byte) (*Definitions, error) {
+	var definitions Definitions
+	if err := json.Unmarshal(data, &definitions); err != nil {
+		return nil, fmt.Errorf("failed to parse SLO definitions: %w", err)
+	}
+	if err := definitions.Validate(); err != nil {
+		return nil, err
+	}
+	return &definitions, nil
+}
+
+// LoadDefinitions reads SLO definitions from a JSON file
+func LoadDefinitions(path string) (*Definitions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SLO definitions: %w", err)
+	}
+	return ParseDefinitions(data)
+}
@@ -0,0 +1,70 @@
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDefinitions(t *testing.T) {
+	definitions, err := ParseDefinitions([]byte(`{
+		"objectives": [
+			{"name": "platform-availability", "kind": "availability", "target": 0.999},
+			{"name": "resnet18-latency", "model": "resnet18", "kind": "latency", "target": 0.99, "threshold": "500ms", "window": "168h"}
+		]
+	}`))
+	require.NoError(t, err)
+	require.Len(t, definitions.Objectives, 2)
+
+	assert.Equal(t, Duration(30*24*time.Hour), definitions.Objectives[0].Window)
+	assert.InDelta(t, 0.001, definitions.Objectives[0].ErrorBudget(), 1e-9)
+	assert.Equal(t, Duration(500*time.Millisecond), definitions.Objectives[1].Threshold)
+}
+
+func TestParseDefinitions_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"bad name", `{"objectives": [{"name": "Platform SLO", "kind": "availability", "target": 0.99}]}`, "needs a name"},
+		{"duplicate", `{"objectives": [{"name": "a", "kind": "availability", "target": 0.99}, {"name": "a", "kind": "availability", "target": 0.9}]}`, "defined twice"},
+		{"target of one", `{"objectives": [{"name": "a", "kind": "availability", "target": 1}]}`, "target between 0 and 1"},
+		{"unknown kind", `{"objectives": [{"name": "a", "kind": "throughput", "target": 0.99}]}`, "unknown kind"},
+		{"threshold between buckets", `{"objectives": [{"name": "a", "kind": "latency", "target": 0.99, "threshold": "300ms"}]}`, "latency bucket"},
+		{"availability threshold", `{"objectives": [{"name": "a", "kind": "availability", "target": 0.99, "threshold": "1s"}]}`, "takes no threshold"},
+		{"short window", `{"objectives": [{"name": "a", "kind": "availability", "target": 0.99, "window": "1h"}]}`, "at least 6h"},
+		{"numeric duration", `{"objectives": [{"name": "a", "kind": "availability", "target": 0.99, "window": 3600}]}`, "such as"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDefinitions([]byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestErrorRatioQuery(t *testing.T) {
+	platform := &Objective{Name: "platform", Kind: Availability, Target: 0.999}
+	assert.Equal(t,
+		`sum(increase(inference_requests_total{status="error"}[3600s])) / sum(increase(inference_requests_total[3600s]))`,
+		platform.ErrorRatioQuery(time.Hour))
+
+	model := &Objective{Name: "resnet18", Model: "resnet18", Kind: Availability, Target: 0.999}
+	assert.Equal(t,
+		`sum(increase(inference_requests_total{status="error",model="resnet18"}[300s])) / sum(increase(inference_requests_total{model="resnet18"}[300s]))`,
+		model.ErrorRatioQuery(5*time.Minute))
+
+	latency := &Objective{Name: "fast", Kind: Latency, Target: 0.99, Threshold: Duration(250 * time.Millisecond)}
+	assert.Equal(t,
+		`1 - sum(increase(inference_request_duration_seconds_bucket{le="0.25"}[3600s])) / sum(increase(inference_request_duration_seconds_count[3600s]))`,
+		latency.ErrorRatioQuery(time.Hour))
+
+	// Whole seconds are matched in both of the forms Prometheus writes them
+	latency.Threshold = Duration(time.Second)
+	assert.Contains(t, latency.ErrorRatioQuery(time.Hour), `{le=~"1|1\\.0"}`)
+}
@@ -0,0 +1,329 @@
+package slo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+)
+
+// Querier evaluates an instant PromQL query, returning NaN for no series
+type Querier interface {
+	Query(ctx context.Context, query string, at time.Time) (float64, error)
+}
+
+// State is how an objective is doing against its error budget
+type State string
+
+const (
+	StateOK       State = "ok"
+	StateWarning  State = "warning"
+	StateCritical State = "critical"
+	// StateUnknown means the objective has not been evaluated or its metrics could not be read
+	StateUnknown State = "unknown"
+)
+
+// rank orders states by urgency
+func (s State) rank() int {
+	switch s {
+	case StateWarning:
+		return 1
+	case StateCritical:
+		return 2
+	}
+	return 0
+}
+
+// burnAlert fires when both its long and short window burn faster than
+// spending budgetSpent of the error budget over the long window. The short
+// window stops the alert soon after the burn does.
+type burnAlert struct {
+	state       State
+	long, short time.Duration
+	budgetSpent float64
+}
+
+// burnAlerts are the multiwindow alerts of the SRE workbook: 2% of a 30 day
+// budget within an hour is critical and 5% within six hours a warning
+var burnAlerts = []burnAlert{
+	{state: StateCritical, long: time.Hour, short: 5 * time.Minute, budgetSpent: 0.02},
+	{state: StateWarning, long: 6 * time.Hour, short: 30 * time.Minute, budgetSpent: 0.05},
+}
+
+// burnWindows are the windows burn rates are reported over
+var burnWindows = []time.Duration{5 * time.Minute, 30 * time.Minute, time.Hour, 6 * time.Hour}
+
+// threshold is the burn rate above which the alert fires for an objective
+func (a burnAlert) threshold(o *Objective) float64 {
+	return a.budgetSpent * float64(o.Window) / float64(a.long)
+}
+
+// Status is the latest evaluation of an objective. Burn rates are how many
+// times faster than sustainable the error budget is being spent.
+type Status struct {
+	Objective
+	ErrorRatio      float64            `json:"error_ratio"`
+	Attainment      float64            `json:"attainment"`
+	BudgetRemaining float64            `json:"budget_remaining"` // share of the window's budget left; negative once overspent
+	BurnRates       map[string]float64 `json:"burn_rates,omitempty"`
+	State           State              `json:"state"`
+	Error           string             `json:"error,omitempty"`
+	EvaluatedAt     time.Time          `json:"evaluated_at,omitempty"`
+}
+
+// BurnRate is the error budget burn of an objective over one window
+type BurnRate struct {
+	SLO        string    `json:"slo"`
+	Window     string    `json:"window"`
+	ErrorRatio float64   `json:"error_ratio"`
+	BurnRate   float64   `json:"burn_rate"`
+	At         time.Time `json:"at"`
+}
+
+// Evaluator periodically evaluates every objective, exports the results as
+// metrics for alerting, and announces objectives that start burning their
+// budget as slo.burning events
+type Evaluator struct {
+	objectives []*Objective
+	byName     map[string]*Objective
+	querier    Querier
+	emitter    *events.Emitter
+	logger     *zap.Logger
+
+	mu       sync.RWMutex
+	statuses map[string]Status
+	// known is the last state each objective was evaluated in
+	known map[string]State
+}
+
+// NewEvaluator creates an evaluator for the defined objectives
+func NewEvaluator(definitions *Definitions, querier Querier, logger *zap.Logger) *Evaluator {
+	e := &Evaluator{
+		objectives: definitions.Objectives,
+		byName:     make(map[string]*Objective, len(definitions.Objectives)),
+		querier:    querier,
+		logger:     logger,
+		statuses:   make(map[string]Status, len(definitions.Objectives)),
+		known:      make(map[string]State, len(definitions.Objectives)),
+	}
+	for _, o := range definitions.Objectives {
+		e.byName[o.Name] = o
+		e.statuses[o.Name] = Status{Objective: *o, State: StateUnknown}
+		objectiveTarget.WithLabelValues(o.Name, o.Model).Set(o.Target)
+		for _, alert := range burnAlerts {
+			burnRateThreshold.WithLabelValues(o.Name, o.Model, string(alert.state)).Set(alert.threshold(o))
+		}
+	}
+	return e
+}
+
+// SetEventEmitter announces objectives that start burning their budget
+func (e *Evaluator) SetEventEmitter(emitter *events.Emitter) {
+	e.emitter = emitter
+}
+
+// Evaluate evaluates every objective once. It is not safe for concurrent use.
+func (e *Evaluator) Evaluate(ctx context.Context, now time.Time) {
+	for _, o := range e.objectives {
+		status := e.evaluate(ctx, o, now)
+
+		e.mu.Lock()
+		e.statuses[o.Name] = status
+		e.mu.Unlock()
+
+		if status.State == StateUnknown {
+			e.logger.Warn("failed to evaluate SLO", zap.String("slo", o.Name), zap.String("error", status.Error))
+			continue
+		}
+		e.export(status)
+
+		// Announce only worsening states, so a burn is reported once as a
+		// warning and once more if it becomes critical
+		if status.State.rank() > e.known[o.Name].rank() {
+			e.announce(ctx, status)
+		}
+		e.known[o.Name] = status.State
+	}
+}
+
+// evaluate measures an objective's budget and burn rates and decides its state
+func (e *Evaluator) evaluate(ctx context.Context, o *Objective, now time.Time) Status {
+	status := Status{
+		Objective:   *o,
+		BurnRates:   make(map[string]float64, len(burnWindows)),
+		State:       StateOK,
+		EvaluatedAt: now,
+	}
+
+	ratio, err := e.errorRatio(ctx, o, time.Duration(o.Window), now)
+	if err != nil {
+		status.State = StateUnknown
+		status.Error = err.Error()
+		return status
+	}
+	status.ErrorRatio = ratio
+	status.Attainment = 1 - ratio
+	status.BudgetRemaining = 1 - ratio/o.ErrorBudget()
+
+	for _, window := range burnWindows {
+		ratio, err := e.errorRatio(ctx, o, window, now)
+		if err != nil {
+			status.State = StateUnknown
+			status.Error = err.Error()
+			return status
+		}
+		status.BurnRates[windowName(window)] = ratio / o.ErrorBudget()
+	}
+
+	for _, alert := range burnAlerts {
+		threshold := alert.threshold(o)
+		if status.BurnRates[windowName(alert.long)] > threshold && status.BurnRates[windowName(alert.short)] > threshold {
+			status.State = alert.state
+			break
+		}
+	}
+	return status
+}
+
+// errorRatio is the share of bad requests over window; no traffic is no errors
+func (e *Evaluator) errorRatio(ctx context.Context, o *Objective, window time.Duration, now time.Time) (float64, error) {
+	ratio, err := e.querier.Query(ctx, o.ErrorRatioQuery(window), now)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(ratio) || math.IsInf(ratio, 0) {
+		return 0, nil
+	}
+	return math.Max(ratio, 0), nil
+}
+
+// export publishes a status as metrics for Prometheus alerting rules
+func (e *Evaluator) export(status Status) {
+	errorBudgetRemaining.WithLabelValues(status.Name, status.Model).Set(status.BudgetRemaining)
+	attainment.WithLabelValues(status.Name, status.Model).Set(status.Attainment)
+	for window, rate := range status.BurnRates {
+		burnRate.WithLabelValues(status.Name, status.Model, window).Set(rate)
+	}
+}
+
+// announce emits an slo.burning event for an objective whose state worsened
+func (e *Evaluator) announce(ctx context.Context, status Status) {
+	severity := events.SeverityWarning
+	if status.State == StateCritical {
+		severity = events.SeverityCritical
+	}
+
+	attributes := map[string]string{
+		"kind":             string(status.Kind),
+		"target":           formatFloat(status.Target),
+		"budget_remaining": formatFloat(status.BudgetRemaining),
+	}
+	if status.Model != "" {
+		attributes["model"] = status.Model
+	}
+	for window, rate := range status.BurnRates {
+		attributes["burn_rate_"+window] = formatFloat(rate)
+	}
+
+	e.logger.Warn("SLO is burning its error budget",
+		zap.String("slo", status.Name),
+		zap.String("state", string(status.State)),
+		zap.Float64("budget_remaining", status.BudgetRemaining),
+	)
+	e.emitter.Emit(ctx, events.Event{
+		Type:       events.SLOBurning,
+		Severity:   severity,
+		Subject:    status.Name,
+		Attributes: attributes,
+	})
+}
+
+// Statuses returns the latest status of every objective, ordered by name
+func (e *Evaluator) Statuses() []Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(e.statuses))
+	for _, status := range e.statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Status returns the latest status of an objective
+func (e *Evaluator) Status(name string) (Status, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	status, ok := e.statuses[name]
+	if !ok {
+		return Status{}, apperrors.Newf(apperrors.NotFound, "SLO not found: %s", name)
+	}
+	return status, nil
+}
+
+// BurnRate measures how fast an objective spent its error budget over the
+// window ending now
+func (e *Evaluator) BurnRate(ctx context.Context, name string, window time.Duration, now time.Time) (*BurnRate, error) {
+	o, ok := e.byName[name]
+	if !ok {
+		return nil, apperrors.Newf(apperrors.NotFound, "SLO not found: %s", name)
+	}
+	if window < time.Minute || window > time.Duration(o.Window) {
+		return nil, apperrors.Newf(apperrors.InvalidArgument, "window must be between 1m and the SLO window of %s", windowName(time.Duration(o.Window)))
+	}
+
+	ratio, err := e.errorRatio(ctx, o, window, now)
+	if err != nil {
+		return nil, err
+	}
+	return &BurnRate{
+		SLO:        name,
+		Window:     windowName(window),
+		ErrorRatio: ratio,
+		BurnRate:   ratio / o.ErrorBudget(),
+		At:         now,
+	}, nil
+}
+
+// Run evaluates every interval until ctx is cancelled
+func (e *Evaluator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		evalCtx, cancel := context.WithTimeout(ctx, interval)
+		e.Evaluate(evalCtx, time.Now().UTC())
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// windowName formats a window as Prometheus does, such as "5m" or "6h"
+func windowName(window time.Duration) string {
+	name := window.String()
+	if strings.HasSuffix(name, "m0s") {
+		name = strings.TrimSuffix(name, "0s")
+	}
+	if strings.HasSuffix(name, "h0m") {
+		name = strings.TrimSuffix(name, "0m")
+	}
+	return name
+}
+
+func formatFloat(f float64) string {
+	return fmt.Sprintf("%.4g", f)
+}
@@ -0,0 +1,186 @@
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/events"
+	"github.com/yourusername/ai-platform/pkg/publish"
+)
+
+// fakeQuerier answers error ratio queries by their range
+type fakeQuerier struct {
+	ratios map[time.Duration]float64
+	err    error
+}
+
+func (q *fakeQuerier) Query(ctx context.Context, query string, at time.Time) (float64, error) {
+	if q.err != nil {
+		return 0, q.err
+	}
+	for window, ratio := range q.ratios {
+		if strings.Contains(query, fmt.Sprintf("[%ds]", int64(window/time.Second))) {
+			return ratio, nil
+		}
+	}
+	return math.NaN(), nil
+}
+
+// capture collects the events an emitter publishes
+type capture struct {
+	events []events.Event
+}
+
+func (c *capture) emitter() *events.Emitter {
+	return events.NewEmitter("slo-service", publish.PublisherFunc(func(ctx context.Context, key string, value []byte) error {
+		var event events.Event
+		if err := json.Unmarshal(value, &event); err != nil {
+			return err
+		}
+		c.events = append(c.events, event)
+		return nil
+	}), 10, zap.NewNop())
+}
+
+// flush publishes what the emitter has queued
+func flush(emitter *events.Emitter) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	emitter.Run(ctx)
+}
+
+func newTestEvaluator(querier Querier) *Evaluator {
+	definitions := &Definitions{Objectives: []*Objective{
+		{Name: "resnet18-availability", Model: "resnet18", Kind: Availability, Target: 0.999},
+	}}
+	if err := definitions.Validate(); err != nil {
+		panic(err)
+	}
+	return NewEvaluator(definitions, querier, zap.NewNop())
+}
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	querier := &fakeQuerier{ratios: map[time.Duration]float64{
+		30 * 24 * time.Hour: 0.0005,
+		6 * time.Hour:       0.002,
+		time.Hour:           0.02,
+		30 * time.Minute:    0.01,
+		5 * time.Minute:     0.03,
+	}}
+	evaluator := newTestEvaluator(querier)
+	var published capture
+	emitter := published.emitter()
+	evaluator.SetEventEmitter(emitter)
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	evaluator.Evaluate(context.Background(), now)
+	flush(emitter)
+
+	status, err := evaluator.Status("resnet18-availability")
+	require.NoError(t, err)
+	assert.Equal(t, StateCritical, status.State)
+	assert.InDelta(t, 0.9995, status.Attainment, 1e-9)
+	assert.InDelta(t, 0.5, status.BudgetRemaining, 1e-9)
+	assert.InDelta(t, 20, status.BurnRates["1h"], 1e-9)
+	assert.InDelta(t, 30, status.BurnRates["5m"], 1e-9)
+	assert.InDelta(t, 2, status.BurnRates["6h"], 1e-9)
+	assert.Equal(t, now, status.EvaluatedAt)
+
+	require.Len(t, published.events, 1)
+	event := published.events[0]
+	assert.Equal(t, events.SLOBurning, event.Type)
+	assert.Equal(t, events.SeverityCritical, event.Severity)
+	assert.Equal(t, "resnet18-availability", event.Subject)
+	assert.Equal(t, "resnet18", event.Attributes["model"])
+	assert.Equal(t, "20", event.Attributes["burn_rate_1h"])
+
+	// A burn that continues is not announced again
+	evaluator.Evaluate(context.Background(), now.Add(time.Minute))
+	flush(emitter)
+	assert.Len(t, published.events, 1)
+}
+
+func TestEvaluator_AnnouncesWorseningStates(t *testing.T) {
+	querier := &fakeQuerier{ratios: map[time.Duration]float64{}}
+	evaluator := newTestEvaluator(querier)
+	var published capture
+	emitter := published.emitter()
+	evaluator.SetEventEmitter(emitter)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	// No traffic is no errors
+	evaluator.Evaluate(context.Background(), now)
+	status, _ := evaluator.Status("resnet18-availability")
+	assert.Equal(t, StateOK, status.State)
+	assert.Equal(t, 1.0, status.BudgetRemaining)
+
+	// 6x over six hours and half an hour is a warning
+	querier.ratios = map[time.Duration]float64{6 * time.Hour: 0.007, 30 * time.Minute: 0.007}
+	evaluator.Evaluate(context.Background(), now)
+	status, _ = evaluator.Status("resnet18-availability")
+	assert.Equal(t, StateWarning, status.State)
+
+	// Prometheus going away neither clears nor repeats the warning
+	querier.err = apperrors.New(apperrors.Unavailable, "prometheus unavailable")
+	evaluator.Evaluate(context.Background(), now)
+	status, _ = evaluator.Status("resnet18-availability")
+	assert.Equal(t, StateUnknown, status.State)
+	assert.Contains(t, status.Error, "prometheus unavailable")
+	querier.err = nil
+	evaluator.Evaluate(context.Background(), now)
+
+	// Becoming critical is announced again
+	querier.ratios[time.Hour] = 0.02
+	querier.ratios[5*time.Minute] = 0.02
+	evaluator.Evaluate(context.Background(), now)
+	flush(emitter)
+
+	require.Len(t, published.events, 2)
+	assert.Equal(t, events.SeverityWarning, published.events[0].Severity)
+	assert.Equal(t, events.SeverityCritical, published.events[1].Severity)
+}
+
+func TestEvaluator_BurnRate(t *testing.T) {
+	evaluator := newTestEvaluator(&fakeQuerier{ratios: map[time.Duration]float64{2 * time.Hour: 0.005}})
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	rate, err := evaluator.BurnRate(context.Background(), "resnet18-availability", 2*time.Hour, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2h", rate.Window)
+	assert.InDelta(t, 0.005, rate.ErrorRatio, 1e-9)
+	assert.InDelta(t, 5, rate.BurnRate, 1e-9)
+
+	_, err = evaluator.BurnRate(context.Background(), "resnet18-availability", time.Second, now)
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+
+	_, err = evaluator.BurnRate(context.Background(), "missing", time.Hour, now)
+	assert.True(t, apperrors.Is(err, apperrors.NotFound))
+}
+
+func TestEvaluator_StatusesBeforeEvaluation(t *testing.T) {
+	evaluator := newTestEvaluator(&fakeQuerier{})
+
+	statuses := evaluator.Statuses()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, StateUnknown, statuses[0].State)
+	assert.Equal(t, "resnet18-availability", statuses[0].Name)
+}
+
+func TestWindowName(t *testing.T) {
+	assert.Equal(t, "5m", windowName(5*time.Minute))
+	assert.Equal(t, "30m", windowName(30*time.Minute))
+	assert.Equal(t, "1h", windowName(time.Hour))
+	assert.Equal(t, "1h30m", windowName(90*time.Minute))
+	assert.Equal(t, "720h", windowName(30*24*time.Hour))
+	assert.Equal(t, "45s", windowName(45*time.Second))
+}
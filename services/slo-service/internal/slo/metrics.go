@@ -0,0 +1,48 @@
+package slo
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	objectiveTarget = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "slo_objective",
+			Help: "Target share of good requests of each SLO",
+		},
+		[]string{"slo", "model"},
+	)
+
+	attainment = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "slo_attainment",
+			Help: "Share of good requests over each SLO's window",
+		},
+		[]string{"slo", "model"},
+	)
+
+	errorBudgetRemaining = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "slo_error_budget_remaining",
+			Help: "Share of each SLO's error budget left in its window; negative once overspent",
+		},
+		[]string{"slo", "model"},
+	)
+
+	burnRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "slo_burn_rate",
+			Help: "How many times faster than sustainable each SLO's error budget is spent over a window",
+		},
+		[]string{"slo", "model", "window"},
+	)
+
+	burnRateThreshold = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "slo_burn_rate_threshold",
+			Help: "Burn rate above which each SLO alerts at a severity",
+		},
+		[]string{"slo", "model", "severity"},
+	)
+)
@@ -0,0 +1,100 @@
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+// PrometheusClient runs instant queries against the Prometheus HTTP API
+type PrometheusClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewPrometheusClient creates a client for the Prometheus server at baseURL
+func NewPrometheusClient(baseURL string, client *http.Client) *PrometheusClient {
+	return &PrometheusClient{baseURL: strings.TrimRight(baseURL, "/"), client: client}
+}
+
+// queryResponse is the part of a Prometheus query response the client reads
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// Query evaluates query at the given time and returns its single value, or
+// NaN when the query matches no series
+func (c *PrometheusClient) Query(ctx context.Context, query string, at time.Time) (float64, error) {
+	params := url.Values{
+		"query": {query},
+		"time":  {strconv.FormatFloat(float64(at.UnixMilli())/1000, 'f', 3, 64)},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/query?"+params.Encode(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, apperrors.FromTransportError(err, "prometheus")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, apperrors.FromHTTPResponse(resp, "prometheus")
+	}
+
+	var body queryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, apperrors.Wrap(err, apperrors.Unavailable, "failed to decode prometheus response")
+	}
+	if body.Status != "success" {
+		return 0, apperrors.Newf(apperrors.Internal, "prometheus query failed: %s", body.Error)
+	}
+
+	// Samples are [timestamp, "value"] pairs
+	var sample []interface{}
+	switch body.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(body.Data.Result, &sample); err != nil {
+			return 0, apperrors.Wrap(err, apperrors.Internal, "failed to decode prometheus scalar")
+		}
+	case "vector":
+		var vector []struct {
+			Value []interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(body.Data.Result, &vector); err != nil {
+			return 0, apperrors.Wrap(err, apperrors.Internal, "failed to decode prometheus vector")
+		}
+		if len(vector) == 0 {
+			return math.NaN(), nil
+		}
+		if len(vector) > 1 {
+			return 0, apperrors.Newf(apperrors.Internal, "prometheus query returned %d series, expected one", len(vector))
+		}
+		sample = vector[0].Value
+	default:
+		return 0, apperrors.Newf(apperrors.Internal, "prometheus query returned a %s, expected a vector", body.Data.ResultType)
+	}
+
+	if len(sample) != 2 {
+		return 0, apperrors.New(apperrors.Internal, "malformed prometheus sample")
+	}
+	text, _ := sample[1].(string)
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, apperrors.Wrap(err, apperrors.Internal, "malformed prometheus sample value")
+	}
+	return value, nil
+}
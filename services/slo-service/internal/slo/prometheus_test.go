@@ -0,0 +1,71 @@
+package slo
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+)
+
+func TestPrometheusClient_Query(t *testing.T) {
+	var gotQuery, gotTime string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		gotQuery = r.URL.Query().Get("query")
+		gotTime = r.URL.Query().Get("time")
+
+		switch gotQuery {
+		case "ratio":
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1792152000,"0.0125"]}]}}`))
+		case "empty":
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		case "nan":
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1792152000,"NaN"]}]}}`))
+		case "scalar":
+			w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1792152000,"2"]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewPrometheusClient(server.URL+"/", server.Client())
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	value, err := client.Query(context.Background(), "ratio", at)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0125, value)
+	assert.Equal(t, "1792152000.000", gotTime)
+
+	value, err = client.Query(context.Background(), "empty", at)
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(value))
+
+	value, err = client.Query(context.Background(), "nan", at)
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(value))
+
+	value, err = client.Query(context.Background(), "scalar", at)
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, value)
+
+	_, err = client.Query(context.Background(), "sum(", at)
+	assert.True(t, apperrors.Is(err, apperrors.InvalidArgument))
+	assert.Contains(t, err.Error(), "parse error")
+}
+
+func TestPrometheusClient_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	_, err := NewPrometheusClient(server.URL, server.Client()).Query(context.Background(), "up", time.Now())
+	assert.True(t, apperrors.Is(err, apperrors.Unavailable))
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ai-platform/pkg/faults"
+	"github.com/yourusername/ai-platform/pkg/health"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/secrets"
+	"github.com/yourusername/ai-platform/pkg/transport"
+	"github.com/yourusername/ai-platform/tenant-service/internal/config"
+	"github.com/yourusername/ai-platform/tenant-service/internal/handlers"
+	"github.com/yourusername/ai-platform/tenant-service/internal/store"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// Initialize logger
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize logger: %v", err))
+	}
+	defer logger.Sync()
+
+	// Load configuration
+	cfg := config.Load()
+	logger.Info("configuration loaded",
+		zap.String("service", cfg.ServiceName),
+		zap.String("port", cfg.Port),
+	)
+
+	// Resolve credentials from Vault or a mounted secret store when configured
+	secretProvider, err := secrets.FromEnv(logger)
+	if err != nil {
+		logger.Fatal("failed to initialize secret provider", zap.Error(err))
+	}
+	secretManager := secrets.NewManager(secretProvider, logger)
+	defer secretManager.Close()
+
+	postgresURLRef := cfg.SecretsPath + "#postgres_url"
+	cfg.PostgresURL = secretManager.LookupOr(context.Background(), postgresURLRef, cfg.PostgresURL)
+	if cfg.PostgresURL == "" {
+		logger.Fatal("postgres is not configured; set POSTGRES_URL or provide it via VAULT_ADDR")
+	}
+
+	// Load the SPIFFE workload identity for mTLS between services
+	identity, err := transport.IdentityFromEnv(cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load workload identity", zap.Error(err))
+	}
+	if identity != nil {
+		identity.Watch(context.Background(), 10*time.Minute)
+	}
+
+	// Initialize PostgreSQL store
+	tenantStore, err := store.NewPostgresStore(cfg.PostgresURL, logger)
+	if err != nil {
+		logger.Fatal("failed to initialize postgres store", zap.Error(err))
+	}
+	defer tenantStore.Close()
+	logger.Info("connected to PostgreSQL")
+
+	// Reconnect when the database credentials rotate
+	secretManager.Watch(context.Background(), postgresURLRef, 5*time.Minute, func(url string) {
+		if err := tenantStore.Reconnect(url); err != nil {
+			logger.Error("failed to reconnect to postgres with rotated credentials", zap.Error(err))
+		}
+	})
+
+	checker := health.NewChecker(cfg.ServiceName, health.DefaultTimeout)
+	checker.Add("postgres", tenantStore.Ping)
+
+	// Inject faults for resilience testing; a no-op unless rules are configured
+	faultInjector, err := faults.FromEnv(context.Background(), cfg.ServiceName, logger)
+	if err != nil {
+		logger.Fatal("failed to load fault injection rules", zap.Error(err))
+	}
+
+	// Setup router
+	if cfg.LogLevel == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Health checks
+	router.GET("/health", gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.LivenessPath, gin.WrapH(checker.LivenessHandler()))
+	router.GET(health.ReadinessPath, gin.WrapH(checker.ReadinessHandler()))
+
+	// Tenant management and the lookups services authorize requests with
+	tenantHandler := handlers.NewTenantHandler(tenantStore, logger)
+	tenantHandler.Register(router.Group("/v1"))
+
+	if faultInjector.Enabled() {
+		router.Any(faults.AdminPath, gin.WrapH(faultInjector.AdminHandler()))
+	}
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      logging.Middleware(faultInjector.Middleware(router)),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	go func() {
+		logger.Info("starting tenant service", zap.String("port", cfg.Port))
+		if err := transport.ListenAndServe(srv, identity); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("failed to start server", zap.Error(err))
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("shutting down server...")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Fatal("server forced to shutdown", zap.Error(err))
+	}
+
+	logger.Info("server exited")
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+)
+
+// Config holds the tenant service configuration
+type Config struct {
+	ServiceName    string
+	Port           string
+	PostgresURL    string
+	SecretsPath    string
+	JaegerEndpoint string
+	LogLevel       string
+}
+
+// Load loads configuration from environment variables
+func Load() *Config {
+	return &Config{
+		ServiceName:    getEnv("SERVICE_NAME", "tenant-service"),
+		Port:           getEnv("PORT", "8090"),
+		PostgresURL:    getEnv("POSTGRES_URL", ""),
+		SecretsPath:    getEnv("SECRETS_PATH", "secret/data/tenant-service"),
+		JaegerEndpoint: getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
+		LogLevel:       getEnv("LOG_LEVEL", "info"),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
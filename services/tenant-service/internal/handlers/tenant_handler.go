@@ -0,0 +1,455 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/logging"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+	"go.uber.org/zap"
+)
+
+// TenantStore persists tenants and what belongs to them
+type TenantStore interface {
+	CreateTenant(ctx context.Context, tenant *tenancy.Tenant) error
+	GetTenant(ctx context.Context, id string) (*tenancy.Tenant, error)
+	ListTenants(ctx context.Context) ([]*tenancy.Tenant, error)
+	UpdateTenant(ctx context.Context, tenant *tenancy.Tenant) error
+	DeleteTenant(ctx context.Context, id string) error
+
+	CreateProject(ctx context.Context, project *tenancy.Project) error
+	ListProjects(ctx context.Context, tenantID string) ([]*tenancy.Project, error)
+	DeleteProject(ctx context.Context, tenantID, id string) error
+
+	PutMember(ctx context.Context, member *tenancy.Member) error
+	GetMember(ctx context.Context, tenantID, userID string) (*tenancy.Member, error)
+	ListMembers(ctx context.Context, tenantID string) ([]*tenancy.Member, error)
+	DeleteMember(ctx context.Context, tenantID, userID string) error
+
+	CreateKey(ctx context.Context, key *tenancy.APIKey, hash string) error
+	ListKeys(ctx context.Context, tenantID string) ([]*tenancy.APIKey, error)
+	RevokeKey(ctx context.Context, tenantID, id string, at time.Time) error
+	KeyByHash(ctx context.Context, hash string) (*tenancy.APIKey, error)
+}
+
+// CreateTenantRequest creates a tenant, optionally with its first owner
+type CreateTenantRequest struct {
+	ID     string         `json:"id" binding:"required"`
+	Name   string         `json:"name" binding:"required"`
+	Tier   tenancy.Tier   `json:"tier"`
+	Quotas tenancy.Limits `json:"quotas"`
+	Owner  string         `json:"owner,omitempty"`
+}
+
+// UpdateTenantRequest changes the fields that are set
+type UpdateTenantRequest struct {
+	Name   *string         `json:"name,omitempty"`
+	Tier   *tenancy.Tier   `json:"tier,omitempty"`
+	Status *tenancy.Status `json:"status,omitempty"`
+	Quotas *tenancy.Limits `json:"quotas,omitempty"`
+}
+
+// CreateProjectRequest creates a project within a tenant
+type CreateProjectRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// PutMemberRequest sets a member's role
+type PutMemberRequest struct {
+	Role tenancy.Role `json:"role" binding:"required"`
+}
+
+// CreateKeyRequest issues an API key, scoped to a project when one is given
+type CreateKeyRequest struct {
+	Name      string       `json:"name" binding:"required"`
+	Role      tenancy.Role `json:"role"`
+	ProjectID string       `json:"project_id,omitempty"`
+}
+
+// CreateKeyResponse carries the new key's secret, which is not shown again
+type CreateKeyResponse struct {
+	*tenancy.APIKey
+	Key string `json:"key"`
+}
+
+// VerifyKeyRequest asks who an API key acts as
+type VerifyKeyRequest struct {
+	Key string `json:"key" binding:"required"`
+}
+
+// TenantHandler serves tenant, project, membership and API key management
+type TenantHandler struct {
+	store  TenantStore
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewTenantHandler creates a new tenant handler
+func NewTenantHandler(store TenantStore, logger *zap.Logger) *TenantHandler {
+	return &TenantHandler{
+		store:  store,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Register adds the management and verification routes to a group
+func (h *TenantHandler) Register(group *gin.RouterGroup) {
+	group.POST("/tenants", h.CreateTenant)
+	group.GET("/tenants", h.ListTenants)
+	group.GET("/tenants/:id", h.GetTenant)
+	group.PATCH("/tenants/:id", h.UpdateTenant)
+	group.DELETE("/tenants/:id", h.DeleteTenant)
+
+	group.POST("/tenants/:id/projects", h.CreateProject)
+	group.GET("/tenants/:id/projects", h.ListProjects)
+	group.DELETE("/tenants/:id/projects/:project", h.DeleteProject)
+
+	group.GET("/tenants/:id/members", h.ListMembers)
+	group.GET("/tenants/:id/members/:user", h.GetMember)
+	group.PUT("/tenants/:id/members/:user", h.PutMember)
+	group.DELETE("/tenants/:id/members/:user", h.DeleteMember)
+
+	group.POST("/tenants/:id/keys", h.CreateKey)
+	group.GET("/tenants/:id/keys", h.ListKeys)
+	group.DELETE("/tenants/:id/keys/:key", h.RevokeKey)
+	group.POST("/keys/verify", h.VerifyKey)
+}
+
+func (h *TenantHandler) log(c *gin.Context) *zap.Logger {
+	return logging.With(c.Request.Context(), h.logger)
+}
+
+// fail logs unexpected errors and writes the error response
+func (h *TenantHandler) fail(c *gin.Context, err error, message string) {
+	if apperrors.CodeOf(err) == apperrors.Internal {
+		h.log(c).Error(message, zap.Error(err))
+	}
+	c.JSON(apperrors.ToHTTP(apperrors.Ensure(err, apperrors.Internal, message)))
+}
+
+func invalid(c *gin.Context, err error) {
+	c.JSON(apperrors.ToHTTP(apperrors.New(apperrors.InvalidArgument, "invalid request").WithDetails(err.Error())))
+}
+
+// withLimits fills in the limits in effect for a tenant
+func withLimits(tenant *tenancy.Tenant) *tenancy.Tenant {
+	tenant.Limits = tenant.Effective()
+	return tenant
+}
+
+// CreateTenant creates a tenant on the free tier unless another is given
+func (h *TenantHandler) CreateTenant(c *gin.Context) {
+	var req CreateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalid(c, err)
+		return
+	}
+	if !tenancy.ValidID(req.ID) {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "tenant id %q must be 2-63 lowercase letters, digits or dashes", req.ID)))
+		return
+	}
+	if req.Tier == "" {
+		req.Tier = tenancy.TierFree
+	}
+	if !req.Tier.Valid() {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "unknown tier %q", req.Tier)))
+		return
+	}
+
+	now := h.now().UTC()
+	tenant := &tenancy.Tenant{
+		ID:        req.ID,
+		Name:      req.Name,
+		Tier:      req.Tier,
+		Status:    tenancy.StatusActive,
+		Quotas:    req.Quotas,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := h.store.CreateTenant(c.Request.Context(), tenant); err != nil {
+		h.fail(c, err, "failed to create tenant")
+		return
+	}
+
+	if req.Owner != "" {
+		owner := &tenancy.Member{TenantID: tenant.ID, UserID: req.Owner, Role: tenancy.RoleOwner, CreatedAt: now}
+		if err := h.store.PutMember(c.Request.Context(), owner); err != nil {
+			h.fail(c, err, "failed to add tenant owner")
+			return
+		}
+	}
+
+	h.log(c).Info("created tenant", zap.String("tenant", tenant.ID), zap.String("tier", string(tenant.Tier)))
+	c.JSON(http.StatusCreated, withLimits(tenant))
+}
+
+// ListTenants lists every tenant
+func (h *TenantHandler) ListTenants(c *gin.Context) {
+	tenants, err := h.store.ListTenants(c.Request.Context())
+	if err != nil {
+		h.fail(c, err, "failed to list tenants")
+		return
+	}
+	for _, tenant := range tenants {
+		withLimits(tenant)
+	}
+	c.JSON(http.StatusOK, gin.H{"tenants": tenants, "count": len(tenants)})
+}
+
+// GetTenant returns a tenant with the limits in effect
+func (h *TenantHandler) GetTenant(c *gin.Context) {
+	tenant, err := h.store.GetTenant(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err, "failed to get tenant")
+		return
+	}
+	c.JSON(http.StatusOK, withLimits(tenant))
+}
+
+// UpdateTenant renames a tenant or changes its tier, status or quotas
+func (h *TenantHandler) UpdateTenant(c *gin.Context) {
+	var req UpdateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalid(c, err)
+		return
+	}
+	if req.Tier != nil && !req.Tier.Valid() {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "unknown tier %q", *req.Tier)))
+		return
+	}
+	if req.Status != nil && !req.Status.Valid() {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "unknown status %q", *req.Status)))
+		return
+	}
+
+	tenant, err := h.store.GetTenant(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err, "failed to get tenant")
+		return
+	}
+	if req.Name != nil {
+		tenant.Name = *req.Name
+	}
+	if req.Tier != nil {
+		tenant.Tier = *req.Tier
+	}
+	if req.Status != nil {
+		tenant.Status = *req.Status
+	}
+	if req.Quotas != nil {
+		tenant.Quotas = *req.Quotas
+	}
+	tenant.UpdatedAt = h.now().UTC()
+
+	if err := h.store.UpdateTenant(c.Request.Context(), tenant); err != nil {
+		h.fail(c, err, "failed to update tenant")
+		return
+	}
+
+	h.log(c).Info("updated tenant",
+		zap.String("tenant", tenant.ID),
+		zap.String("tier", string(tenant.Tier)),
+		zap.String("status", string(tenant.Status)),
+	)
+	c.JSON(http.StatusOK, withLimits(tenant))
+}
+
+// DeleteTenant deletes a tenant with its projects, members and keys
+func (h *TenantHandler) DeleteTenant(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.store.DeleteTenant(c.Request.Context(), id); err != nil {
+		h.fail(c, err, "failed to delete tenant")
+		return
+	}
+	h.log(c).Info("deleted tenant", zap.String("tenant", id))
+	c.JSON(http.StatusOK, gin.H{"message": "tenant deleted successfully"})
+}
+
+// CreateProject creates a project within a tenant
+func (h *TenantHandler) CreateProject(c *gin.Context) {
+	var req CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalid(c, err)
+		return
+	}
+
+	project := &tenancy.Project{
+		ID:        uuid.New().String(),
+		TenantID:  c.Param("id"),
+		Name:      req.Name,
+		CreatedAt: h.now().UTC(),
+	}
+	if err := h.store.CreateProject(c.Request.Context(), project); err != nil {
+		h.fail(c, err, "failed to create project")
+		return
+	}
+	c.JSON(http.StatusCreated, project)
+}
+
+// ListProjects lists a tenant's projects
+func (h *TenantHandler) ListProjects(c *gin.Context) {
+	projects, err := h.store.ListProjects(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err, "failed to list projects")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"projects": projects, "count": len(projects)})
+}
+
+// DeleteProject deletes a project and the keys scoped to it
+func (h *TenantHandler) DeleteProject(c *gin.Context) {
+	if err := h.store.DeleteProject(c.Request.Context(), c.Param("id"), c.Param("project")); err != nil {
+		h.fail(c, err, "failed to delete project")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "project deleted successfully"})
+}
+
+// ListMembers lists a tenant's members
+func (h *TenantHandler) ListMembers(c *gin.Context) {
+	members, err := h.store.ListMembers(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err, "failed to list members")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"members": members, "count": len(members)})
+}
+
+// GetMember returns one user's membership; services use it to authorize users
+func (h *TenantHandler) GetMember(c *gin.Context) {
+	member, err := h.store.GetMember(c.Request.Context(), c.Param("id"), c.Param("user"))
+	if err != nil {
+		h.fail(c, err, "failed to get member")
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// PutMember adds a user to a tenant or changes their role
+func (h *TenantHandler) PutMember(c *gin.Context) {
+	var req PutMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalid(c, err)
+		return
+	}
+	if !req.Role.Valid() {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "unknown role %q", req.Role)))
+		return
+	}
+
+	member := &tenancy.Member{
+		TenantID:  c.Param("id"),
+		UserID:    c.Param("user"),
+		Role:      req.Role,
+		CreatedAt: h.now().UTC(),
+	}
+	if err := h.store.PutMember(c.Request.Context(), member); err != nil {
+		h.fail(c, err, "failed to save member")
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// DeleteMember removes a user from a tenant
+func (h *TenantHandler) DeleteMember(c *gin.Context) {
+	if err := h.store.DeleteMember(c.Request.Context(), c.Param("id"), c.Param("user")); err != nil {
+		h.fail(c, err, "failed to delete member")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "member removed successfully"})
+}
+
+// CreateKey issues an API key with the member role unless another is given.
+// The key is returned once; only its hash is stored.
+func (h *TenantHandler) CreateKey(c *gin.Context) {
+	var req CreateKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalid(c, err)
+		return
+	}
+	if req.Role == "" {
+		req.Role = tenancy.RoleMember
+	}
+	if !req.Role.Valid() {
+		c.JSON(apperrors.ToHTTP(apperrors.Newf(apperrors.InvalidArgument, "unknown role %q", req.Role)))
+		return
+	}
+
+	secret, err := tenancy.NewKey()
+	if err != nil {
+		h.fail(c, err, "failed to generate api key")
+		return
+	}
+	key := &tenancy.APIKey{
+		ID:        uuid.New().String(),
+		TenantID:  c.Param("id"),
+		ProjectID: req.ProjectID,
+		Name:      req.Name,
+		Prefix:    tenancy.DisplayPrefix(secret),
+		Role:      req.Role,
+		CreatedAt: h.now().UTC(),
+	}
+	if err := h.store.CreateKey(c.Request.Context(), key, tenancy.HashKey(secret)); err != nil {
+		h.fail(c, err, "failed to create api key")
+		return
+	}
+
+	h.log(c).Info("created api key",
+		zap.String("tenant", key.TenantID),
+		zap.String("key_id", key.ID),
+		zap.String("role", string(key.Role)),
+	)
+	c.JSON(http.StatusCreated, CreateKeyResponse{APIKey: key, Key: secret})
+}
+
+// ListKeys lists a tenant's API keys without their secrets
+func (h *TenantHandler) ListKeys(c *gin.Context) {
+	keys, err := h.store.ListKeys(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err, "failed to list api keys")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"keys": keys, "count": len(keys)})
+}
+
+// RevokeKey revokes an API key; services stop accepting it once their caches expire
+func (h *TenantHandler) RevokeKey(c *gin.Context) {
+	tenantID, id := c.Param("id"), c.Param("key")
+	if err := h.store.RevokeKey(c.Request.Context(), tenantID, id, h.now().UTC()); err != nil {
+		h.fail(c, err, "failed to revoke api key")
+		return
+	}
+	h.log(c).Info("revoked api key", zap.String("tenant", tenantID), zap.String("key_id", id))
+	c.JSON(http.StatusOK, gin.H{"message": "api key revoked successfully"})
+}
+
+// VerifyKey resolves an API key to the tenant, project and role it acts as.
+// Unknown and revoked keys are both reported as not found.
+func (h *TenantHandler) VerifyKey(c *gin.Context) {
+	var req VerifyKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalid(c, err)
+		return
+	}
+
+	key, err := h.store.KeyByHash(c.Request.Context(), tenancy.HashKey(req.Key))
+	if err == nil && key.RevokedAt != nil {
+		err = apperrors.New(apperrors.NotFound, "api key not found")
+	}
+	if err != nil {
+		h.fail(c, err, "failed to verify api key")
+		return
+	}
+
+	c.JSON(http.StatusOK, tenancy.Principal{
+		KeyID:     key.ID,
+		TenantID:  key.TenantID,
+		ProjectID: key.ProjectID,
+		Role:      key.Role,
+	})
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+	"go.uber.org/zap"
+)
+
+// fakeTenantStore keeps tenants in memory
+type fakeTenantStore struct {
+	tenants  map[string]*tenancy.Tenant
+	projects map[string]*tenancy.Project
+	members  map[string]*tenancy.Member
+	keys     map[string]*tenancy.APIKey // by hash
+}
+
+func newFakeTenantStore() *fakeTenantStore {
+	return &fakeTenantStore{
+		tenants:  make(map[string]*tenancy.Tenant),
+		projects: make(map[string]*tenancy.Project),
+		members:  make(map[string]*tenancy.Member),
+		keys:     make(map[string]*tenancy.APIKey),
+	}
+}
+
+func (s *fakeTenantStore) CreateTenant(ctx context.Context, tenant *tenancy.Tenant) error {
+	if _, ok := s.tenants[tenant.ID]; ok {
+		return apperrors.Newf(apperrors.AlreadyExists, "tenant %s already exists", tenant.ID)
+	}
+	copied := *tenant
+	s.tenants[tenant.ID] = &copied
+	return nil
+}
+
+func (s *fakeTenantStore) GetTenant(ctx context.Context, id string) (*tenancy.Tenant, error) {
+	tenant, ok := s.tenants[id]
+	if !ok {
+		return nil, apperrors.Newf(apperrors.NotFound, "tenant not found: %s", id)
+	}
+	copied := *tenant
+	return &copied, nil
+}
+
+func (s *fakeTenantStore) ListTenants(ctx context.Context) ([]*tenancy.Tenant, error) {
+	tenants := []*tenancy.Tenant{}
+	for _, tenant := range s.tenants {
+		copied := *tenant
+		tenants = append(tenants, &copied)
+	}
+	return tenants, nil
+}
+
+func (s *fakeTenantStore) UpdateTenant(ctx context.Context, tenant *tenancy.Tenant) error {
+	copied := *tenant
+	s.tenants[tenant.ID] = &copied
+	return nil
+}
+
+func (s *fakeTenantStore) DeleteTenant(ctx context.Context, id string) error {
+	delete(s.tenants, id)
+	return nil
+}
+
+func (s *fakeTenantStore) CreateProject(ctx context.Context, project *tenancy.Project) error {
+	s.projects[project.ID] = project
+	return nil
+}
+
+func (s *fakeTenantStore) ListProjects(ctx context.Context, tenantID string) ([]*tenancy.Project, error) {
+	return nil, nil
+}
+
+func (s *fakeTenantStore) DeleteProject(ctx context.Context, tenantID, id string) error {
+	return nil
+}
+
+func (s *fakeTenantStore) PutMember(ctx context.Context, member *tenancy.Member) error {
+	if _, ok := s.tenants[member.TenantID]; !ok {
+		return apperrors.Newf(apperrors.NotFound, "tenant not found: %s", member.TenantID)
+	}
+	s.members[member.TenantID+"/"+member.UserID] = member
+	return nil
+}
+
+func (s *fakeTenantStore) GetMember(ctx context.Context, tenantID, userID string) (*tenancy.Member, error) {
+	member, ok := s.members[tenantID+"/"+userID]
+	if !ok {
+		return nil, apperrors.Newf(apperrors.NotFound, "member not found: %s", userID)
+	}
+	return member, nil
+}
+
+func (s *fakeTenantStore) ListMembers(ctx context.Context, tenantID string) ([]*tenancy.Member, error) {
+	return nil, nil
+}
+
+func (s *fakeTenantStore) DeleteMember(ctx context.Context, tenantID, userID string) error {
+	delete(s.members, tenantID+"/"+userID)
+	return nil
+}
+
+func (s *fakeTenantStore) CreateKey(ctx context.Context, key *tenancy.APIKey, hash string) error {
+	s.keys[hash] = key
+	return nil
+}
+
+func (s *fakeTenantStore) ListKeys(ctx context.Context, tenantID string) ([]*tenancy.APIKey, error) {
+	keys := []*tenancy.APIKey{}
+	for _, key := range s.keys {
+		if key.TenantID == tenantID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *fakeTenantStore) RevokeKey(ctx context.Context, tenantID, id string, at time.Time) error {
+	for _, key := range s.keys {
+		if key.TenantID == tenantID && key.ID == id {
+			key.RevokedAt = &at
+			return nil
+		}
+	}
+	return apperrors.Newf(apperrors.NotFound, "api key not found: %s", id)
+}
+
+func (s *fakeTenantStore) KeyByHash(ctx context.Context, hash string) (*tenancy.APIKey, error) {
+	key, ok := s.keys[hash]
+	if !ok {
+		return nil, apperrors.New(apperrors.NotFound, "api key not found")
+	}
+	return key, nil
+}
+
+func setupRouter(tenantStore *fakeTenantStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewTenantHandler(tenantStore, zap.NewNop())
+	handler.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+
+	router := gin.New()
+	handler.Register(router.Group("/v1"))
+	return router
+}
+
+func do(router *gin.Engine, method, url, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, url, strings.NewReader(body)))
+	return w
+}
+
+func TestCreateTenant(t *testing.T) {
+	tenantStore := newFakeTenantStore()
+	router := setupRouter(tenantStore)
+
+	w := do(router, "POST", "/v1/tenants", `{"id":"acme","name":"Acme","owner":"alice","quotas":{"max_models":-1}}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var tenant tenancy.Tenant
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tenant))
+	assert.Equal(t, tenancy.TierFree, tenant.Tier)
+	assert.Equal(t, tenancy.StatusActive, tenant.Status)
+	assert.Equal(t, tenancy.Limits{RequestsPerMinute: 60, MaxBatchItems: 100, MonthlyRequests: 100000, MonthlyBatchItems: 10000}, tenant.Limits)
+	assert.Equal(t, tenancy.RoleOwner, tenantStore.members["acme/alice"].Role)
+
+	w = do(router, "POST", "/v1/tenants", `{"id":"acme","name":"Again"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = do(router, "POST", "/v1/tenants", `{"id":"Not Valid","name":"Bad"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do(router, "POST", "/v1/tenants", `{"id":"gold","name":"Gold","tier":"platinum"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateTenant(t *testing.T) {
+	tenantStore := newFakeTenantStore()
+	router := setupRouter(tenantStore)
+	require.Equal(t, http.StatusCreated, do(router, "POST", "/v1/tenants", `{"id":"acme","name":"Acme"}`).Code)
+
+	w := do(router, "PATCH", "/v1/tenants/acme", `{"tier":"pro","status":"suspended","quotas":{"max_batch_items":20000}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var tenant tenancy.Tenant
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tenant))
+	assert.Equal(t, tenancy.StatusSuspended, tenant.Status)
+	assert.Equal(t, 20000, tenant.Limits.MaxBatchItems)
+	assert.Equal(t, 600, tenant.Limits.RequestsPerMinute)
+	assert.Equal(t, "Acme", tenantStore.tenants["acme"].Name)
+
+	w = do(router, "PATCH", "/v1/tenants/acme", `{"status":"deleted"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do(router, "PATCH", "/v1/tenants/nobody", `{"tier":"pro"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMembers(t *testing.T) {
+	tenantStore := newFakeTenantStore()
+	router := setupRouter(tenantStore)
+	require.Equal(t, http.StatusCreated, do(router, "POST", "/v1/tenants", `{"id":"acme","name":"Acme"}`).Code)
+
+	assert.Equal(t, http.StatusBadRequest, do(router, "PUT", "/v1/tenants/acme/members/bob", `{"role":"root"}`).Code)
+	assert.Equal(t, http.StatusNotFound, do(router, "PUT", "/v1/tenants/nobody/members/bob", `{"role":"viewer"}`).Code)
+	require.Equal(t, http.StatusOK, do(router, "PUT", "/v1/tenants/acme/members/bob", `{"role":"viewer"}`).Code)
+
+	w := do(router, "GET", "/v1/tenants/acme/members/bob", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var member tenancy.Member
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &member))
+	assert.Equal(t, tenancy.RoleViewer, member.Role)
+
+	assert.Equal(t, http.StatusNotFound, do(router, "GET", "/v1/tenants/acme/members/carol", "").Code)
+}
+
+func TestKeys_CreateVerifyRevoke(t *testing.T) {
+	tenantStore := newFakeTenantStore()
+	router := setupRouter(tenantStore)
+	require.Equal(t, http.StatusCreated, do(router, "POST", "/v1/tenants", `{"id":"acme","name":"Acme"}`).Code)
+
+	w := do(router, "POST", "/v1/tenants/acme/keys", `{"name":"ci"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		ID     string       `json:"id"`
+		Key    string       `json:"key"`
+		Prefix string       `json:"prefix"`
+		Role   tenancy.Role `json:"role"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.True(t, tenancy.IsKey(created.Key))
+	assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+	assert.Equal(t, tenancy.RoleMember, created.Role)
+
+	// Only the hash is stored and listings never carry the key
+	_, stored := tenantStore.keys[tenancy.HashKey(created.Key)]
+	assert.True(t, stored)
+	w = do(router, "GET", "/v1/tenants/acme/keys", "")
+	assert.NotContains(t, w.Body.String(), created.Key)
+
+	w = do(router, "POST", "/v1/keys/verify", `{"key":"`+created.Key+`"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var principal tenancy.Principal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &principal))
+	assert.Equal(t, tenancy.Principal{KeyID: created.ID, TenantID: "acme", Role: tenancy.RoleMember}, principal)
+
+	assert.Equal(t, http.StatusNotFound, do(router, "POST", "/v1/keys/verify", `{"key":"aip_unknown"}`).Code)
+
+	require.Equal(t, http.StatusOK, do(router, "DELETE", "/v1/tenants/acme/keys/"+created.ID, "").Code)
+	assert.Equal(t, http.StatusNotFound, do(router, "POST", "/v1/keys/verify", `{"key":"`+created.Key+`"}`).Code)
+}
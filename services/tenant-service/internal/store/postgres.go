@@ -0,0 +1,463 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yourusername/ai-platform/pkg/apperrors"
+	"github.com/yourusername/ai-platform/pkg/tenancy"
+	"go.uber.org/zap"
+)
+
+// Postgres error codes mapped to API errors
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+)
+
+// PostgresStore keeps tenants, their projects, members and API keys
+type PostgresStore struct {
+	db     atomic.Pointer[sql.DB]
+	logger *zap.Logger
+}
+
+// NewPostgresStore creates a new PostgreSQL store
+func NewPostgresStore(connectionURL string, logger *zap.Logger) (*PostgresStore, error) {
+	db, err := openDB(connectionURL)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &PostgresStore{
+		logger: logger,
+	}
+	store.db.Store(db)
+
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	return store, nil
+}
+
+// openDB opens a connection pool and checks that the database is reachable
+func openDB(connectionURL string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", connectionURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(5 * time.Minute)
+	return db, nil
+}
+
+// Reconnect replaces the connection pool with one opened with connectionURL,
+// typically after the database credentials rotate. Queries already running
+// on the old pool finish before it closes.
+func (s *PostgresStore) Reconnect(connectionURL string) error {
+	db, err := openDB(connectionURL)
+	if err != nil {
+		return err
+	}
+	if old := s.db.Swap(db); old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// initSchema creates the tenant tables if they don't exist
+func (s *PostgresStore) initSchema() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS tenants (
+		id VARCHAR(64) PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		tier VARCHAR(32) NOT NULL,
+		status VARCHAR(32) NOT NULL,
+		quotas JSONB NOT NULL DEFAULT '{}',
+		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+	);
+
+	CREATE TABLE IF NOT EXISTS projects (
+		id VARCHAR(255) PRIMARY KEY,
+		tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+		name VARCHAR(255) NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		UNIQUE(tenant_id, name)
+	);
+
+	CREATE TABLE IF NOT EXISTS members (
+		tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+		user_id VARCHAR(255) NOT NULL,
+		role VARCHAR(32) NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (tenant_id, user_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_members_user_id ON members(user_id);
+
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id VARCHAR(255) PRIMARY KEY,
+		tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+		project_id VARCHAR(255) REFERENCES projects(id) ON DELETE CASCADE,
+		name VARCHAR(255) NOT NULL,
+		prefix VARCHAR(32) NOT NULL,
+		key_hash VARCHAR(64) NOT NULL UNIQUE,
+		role VARCHAR(32) NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		revoked_at TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_api_keys_tenant_id ON api_keys(tenant_id);
+	`
+
+	_, err := s.db.Load().Exec(query)
+	return err
+}
+
+// classify maps constraint violations to API errors
+func classify(err error, exists, missing string) error {
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			return apperrors.Wrap(err, apperrors.AlreadyExists, exists)
+		case pqForeignKeyViolation:
+			return apperrors.Wrap(err, apperrors.NotFound, missing)
+		}
+	}
+	return err
+}
+
+// CreateTenant stores a new tenant
+func (s *PostgresStore) CreateTenant(ctx context.Context, tenant *tenancy.Tenant) error {
+	quotas, err := json.Marshal(tenant.Quotas)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quotas: %w", err)
+	}
+
+	_, err = s.db.Load().ExecContext(ctx, `
+		INSERT INTO tenants (id, name, tier, status, quotas, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, tenant.ID, tenant.Name, tenant.Tier, tenant.Status, quotas, tenant.CreatedAt, tenant.UpdatedAt)
+	if err != nil {
+		err = classify(err, fmt.Sprintf("tenant %s already exists", tenant.ID), "")
+		return apperrors.Ensure(err, apperrors.Internal, "failed to create tenant")
+	}
+	return nil
+}
+
+// GetTenant returns a tenant by ID
+func (s *PostgresStore) GetTenant(ctx context.Context, id string) (*tenancy.Tenant, error) {
+	row := s.db.Load().QueryRowContext(ctx, `
+		SELECT id, name, tier, status, quotas, created_at, updated_at
+		FROM tenants
+		WHERE id = $1
+	`, id)
+
+	tenant, err := scanTenant(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.Newf(apperrors.NotFound, "tenant not found: %s", id)
+	}
+	return tenant, err
+}
+
+// ListTenants returns every tenant, oldest first
+func (s *PostgresStore) ListTenants(ctx context.Context) ([]*tenancy.Tenant, error) {
+	rows, err := s.db.Load().QueryContext(ctx, `
+		SELECT id, name, tier, status, quotas, created_at, updated_at
+		FROM tenants
+		ORDER BY created_at, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	defer rows.Close()
+
+	tenants := []*tenancy.Tenant{}
+	for rows.Next() {
+		tenant, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
+
+// UpdateTenant saves a tenant's name, tier, status and quotas
+func (s *PostgresStore) UpdateTenant(ctx context.Context, tenant *tenancy.Tenant) error {
+	quotas, err := json.Marshal(tenant.Quotas)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quotas: %w", err)
+	}
+
+	result, err := s.db.Load().ExecContext(ctx, `
+		UPDATE tenants
+		SET name = $2, tier = $3, status = $4, quotas = $5, updated_at = $6
+		WHERE id = $1
+	`, tenant.ID, tenant.Name, tenant.Tier, tenant.Status, quotas, tenant.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update tenant: %w", err)
+	}
+	return expectRow(result, "tenant not found: "+tenant.ID)
+}
+
+// DeleteTenant removes a tenant with its projects, members and keys
+func (s *PostgresStore) DeleteTenant(ctx context.Context, id string) error {
+	result, err := s.db.Load().ExecContext(ctx, `DELETE FROM tenants WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	return expectRow(result, "tenant not found: "+id)
+}
+
+// CreateProject stores a new project
+func (s *PostgresStore) CreateProject(ctx context.Context, project *tenancy.Project) error {
+	_, err := s.db.Load().ExecContext(ctx, `
+		INSERT INTO projects (id, tenant_id, name, created_at)
+		VALUES ($1, $2, $3, $4)
+	`, project.ID, project.TenantID, project.Name, project.CreatedAt)
+	if err != nil {
+		err = classify(err,
+			fmt.Sprintf("project %s already exists", project.Name),
+			fmt.Sprintf("tenant not found: %s", project.TenantID),
+		)
+		return apperrors.Ensure(err, apperrors.Internal, "failed to create project")
+	}
+	return nil
+}
+
+// ListProjects returns a tenant's projects by name
+func (s *PostgresStore) ListProjects(ctx context.Context, tenantID string) ([]*tenancy.Project, error) {
+	rows, err := s.db.Load().QueryContext(ctx, `
+		SELECT id, tenant_id, name, created_at
+		FROM projects
+		WHERE tenant_id = $1
+		ORDER BY name
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []*tenancy.Project{}
+	for rows.Next() {
+		var project tenancy.Project
+		if err := rows.Scan(&project.ID, &project.TenantID, &project.Name, &project.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, &project)
+	}
+	return projects, rows.Err()
+}
+
+// DeleteProject removes a project and the keys scoped to it
+func (s *PostgresStore) DeleteProject(ctx context.Context, tenantID, id string) error {
+	result, err := s.db.Load().ExecContext(ctx, `DELETE FROM projects WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	return expectRow(result, "project not found: "+id)
+}
+
+// PutMember adds a member or changes their role
+func (s *PostgresStore) PutMember(ctx context.Context, member *tenancy.Member) error {
+	_, err := s.db.Load().ExecContext(ctx, `
+		INSERT INTO members (tenant_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`, member.TenantID, member.UserID, member.Role, member.CreatedAt)
+	if err != nil {
+		err = classify(err, "", fmt.Sprintf("tenant not found: %s", member.TenantID))
+		return apperrors.Ensure(err, apperrors.Internal, "failed to save member")
+	}
+	return nil
+}
+
+// GetMember returns a user's membership of a tenant
+func (s *PostgresStore) GetMember(ctx context.Context, tenantID, userID string) (*tenancy.Member, error) {
+	var member tenancy.Member
+	err := s.db.Load().QueryRowContext(ctx, `
+		SELECT tenant_id, user_id, role, created_at
+		FROM members
+		WHERE tenant_id = $1 AND user_id = $2
+	`, tenantID, userID).Scan(&member.TenantID, &member.UserID, &member.Role, &member.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.Newf(apperrors.NotFound, "member not found: %s", userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member: %w", err)
+	}
+	return &member, nil
+}
+
+// ListMembers returns a tenant's members by user ID
+func (s *PostgresStore) ListMembers(ctx context.Context, tenantID string) ([]*tenancy.Member, error) {
+	rows, err := s.db.Load().QueryContext(ctx, `
+		SELECT tenant_id, user_id, role, created_at
+		FROM members
+		WHERE tenant_id = $1
+		ORDER BY user_id
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []*tenancy.Member{}
+	for rows.Next() {
+		var member tenancy.Member
+		if err := rows.Scan(&member.TenantID, &member.UserID, &member.Role, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		members = append(members, &member)
+	}
+	return members, rows.Err()
+}
+
+// DeleteMember removes a user from a tenant
+func (s *PostgresStore) DeleteMember(ctx context.Context, tenantID, userID string) error {
+	result, err := s.db.Load().ExecContext(ctx, `DELETE FROM members WHERE tenant_id = $1 AND user_id = $2`, tenantID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete member: %w", err)
+	}
+	return expectRow(result, "member not found: "+userID)
+}
+
+// CreateKey stores an API key by the hash of its secret
+func (s *PostgresStore) CreateKey(ctx context.Context, key *tenancy.APIKey, hash string) error {
+	_, err := s.db.Load().ExecContext(ctx, `
+		INSERT INTO api_keys (id, tenant_id, project_id, name, prefix, key_hash, role, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, key.ID, key.TenantID, sql.NullString{String: key.ProjectID, Valid: key.ProjectID != ""},
+		key.Name, key.Prefix, hash, key.Role, key.CreatedAt,
+	)
+	if err != nil {
+		err = classify(err, "api key already exists", "tenant or project not found")
+		return apperrors.Ensure(err, apperrors.Internal, "failed to create api key")
+	}
+	return nil
+}
+
+// ListKeys returns a tenant's API keys, newest first, including revoked ones
+func (s *PostgresStore) ListKeys(ctx context.Context, tenantID string) ([]*tenancy.APIKey, error) {
+	rows, err := s.db.Load().QueryContext(ctx, `
+		SELECT id, tenant_id, project_id, name, prefix, role, created_at, revoked_at
+		FROM api_keys
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []*tenancy.APIKey{}
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeKey marks an API key revoked; revoking twice keeps the first time
+func (s *PostgresStore) RevokeKey(ctx context.Context, tenantID, id string, at time.Time) error {
+	result, err := s.db.Load().ExecContext(ctx, `
+		UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $3)
+		WHERE tenant_id = $1 AND id = $2
+	`, tenantID, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return expectRow(result, "api key not found: "+id)
+}
+
+// KeyByHash returns the API key whose secret hashes to hash
+func (s *PostgresStore) KeyByHash(ctx context.Context, hash string) (*tenancy.APIKey, error) {
+	row := s.db.Load().QueryRowContext(ctx, `
+		SELECT id, tenant_id, project_id, name, prefix, role, created_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = $1
+	`, hash)
+
+	key, err := scanKey(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.New(apperrors.NotFound, "api key not found")
+	}
+	return key, err
+}
+
+// scanner is satisfied by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTenant(row scanner) (*tenancy.Tenant, error) {
+	var tenant tenancy.Tenant
+	var quotas []byte
+	err := row.Scan(&tenant.ID, &tenant.Name, &tenant.Tier, &tenant.Status, &quotas, &tenant.CreatedAt, &tenant.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan tenant: %w", err)
+	}
+	if err := json.Unmarshal(quotas, &tenant.Quotas); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quotas: %w", err)
+	}
+	return &tenant, nil
+}
+
+func scanKey(row scanner) (*tenancy.APIKey, error) {
+	var key tenancy.APIKey
+	var projectID sql.NullString
+	var revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.TenantID, &projectID, &key.Name, &key.Prefix, &key.Role, &key.CreatedAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan api key: %w", err)
+	}
+	key.ProjectID = projectID.String
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return &key, nil
+}
+
+// expectRow reports NotFound when a statement matched no rows
+func expectRow(result sql.Result, message string) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if n == 0 {
+		return apperrors.New(apperrors.NotFound, message)
+	}
+	return nil
+}
+
+// Ping checks the database connection for readiness probes
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	return s.db.Load().PingContext(ctx)
+}
+
+// Close closes the database connection
+func (s *PostgresStore) Close() error {
+	return s.db.Load().Close()
+}
@@ -0,0 +1,84 @@
+// Command loadgen runs a load profile against an API gateway and reports
+// latency percentiles and error budget consumption. It exits non-zero when
+// the run misses the profile's budget, so it can gate a deployment.
+//
+//	go run ./load/cmd/loadgen -profile load/profiles/ramp.json -url https://gateway.staging
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/ai-platform/tests/load"
+)
+
+func main() {
+	profilePath := flag.String("profile", "", "JSON load profile (required)")
+	baseURL := flag.String("url", getEnv("API_GATEWAY_URL", "http://localhost:8080"), "API gateway base URL")
+	token := flag.String("token", getEnv("LOAD_TOKEN", "demo-token"), "bearer token sent with every request")
+	timeout := flag.Duration("timeout", 30*time.Second, "per-request timeout")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed of the traffic mix, for reproducible runs")
+	format := flag.String("format", "text", "report format: text or json")
+	flag.Parse()
+
+	if *profilePath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	profile, err := load.LoadProfile(*profilePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// Interrupting a run still reports on the requests made so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := &http.Client{
+		Timeout: *timeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        profile.MaxInFlight,
+			MaxIdleConnsPerHost: profile.MaxInFlight,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+	fmt.Fprintf(os.Stderr, "running profile %s against %s for %v\n", profile.Name, *baseURL, profile.Duration())
+
+	report, err := load.NewRunner(*baseURL, *token, client, *seed).Run(ctx, profile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	default:
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if !report.Passed() {
+		os.Exit(1)
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,142 @@
+package load
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile_Rate(t *testing.T) {
+	profile := &Profile{Stages: []Stage{
+		{Duration: Duration(10 * time.Second), RPS: 100},
+		{Duration: Duration(10 * time.Second), RPS: 100},
+		{Duration: Duration(10 * time.Second), RPS: 0},
+	}}
+
+	rate, stage := profile.rate(5 * time.Second)
+	assert.InDelta(t, 50, rate, 0.001, "the first stage ramps up from zero")
+	assert.Equal(t, 0, stage)
+
+	rate, stage = profile.rate(15 * time.Second)
+	assert.InDelta(t, 100, rate, 0.001)
+	assert.Equal(t, 1, stage)
+
+	rate, _ = profile.rate(25 * time.Second)
+	assert.InDelta(t, 50, rate, 0.001)
+
+	_, stage = profile.rate(30 * time.Second)
+	assert.Equal(t, -1, stage)
+	assert.Equal(t, 30*time.Second, profile.Duration())
+}
+
+func TestLoadProfile_Shipped(t *testing.T) {
+	paths, err := filepath.Glob("profiles/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		profile, err := LoadProfile(path)
+		require.NoError(t, err, path)
+		assert.NotEmpty(t, profile.Name, path)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	assert.Equal(t, Duration(50*time.Millisecond), percentile(latencies, 0.50))
+	assert.Equal(t, Duration(95*time.Millisecond), percentile(latencies, 0.95))
+	assert.Equal(t, Duration(100*time.Millisecond), percentile(latencies, 1))
+	assert.Zero(t, percentile(nil, 0.5))
+}
+
+func TestRunner_Run(t *testing.T) {
+	var realtime, batch atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		switch {
+		case req["model"] == "flaky":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/v1/batch":
+			batch.Add(1)
+			assert.Len(t, req["inputs"], 3)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			realtime.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	profile := &Profile{
+		Name:   "test",
+		Stages: []Stage{{Duration: Duration(500 * time.Millisecond), RPS: 400}},
+		Models: []Model{
+			{Model: "resnet18", Version: "v1", Weight: 3},
+			{Model: "flaky", Version: "v1", Weight: 1},
+		},
+		PayloadSizes: []int{2, 8},
+		Batch:        Batch{Ratio: 0.2, Size: 3},
+		Budget:       Budget{MaxErrorRate: 0.01, P99: Duration(time.Minute)},
+	}
+
+	report, err := NewRunner(server.URL, "token", server.Client(), 1).Run(context.Background(), profile)
+	require.NoError(t, err)
+
+	// Ramping from 0 to 400 rps over half a second schedules about 100 requests
+	assert.InDelta(t, 100, report.Total.Requests, 20)
+	assert.Equal(t, report.Total.Requests, report.Kinds[KindRealtime].Requests+report.Kinds[KindBatch].Requests)
+	assert.Positive(t, realtime.Load())
+	assert.Positive(t, batch.Load())
+	assert.Positive(t, report.Total.Failures["503"])
+	assert.InDelta(t, 0.25, report.Total.ErrorRate, 0.15)
+	require.Len(t, report.Stages, 1)
+	assert.Equal(t, report.Total.Requests, report.Stages[0].Requests)
+
+	assert.False(t, report.Passed())
+	require.Len(t, report.Violations, 1, "only the error rate is exceeded")
+	assert.Contains(t, report.Violations[0], "error rate")
+	assert.Greater(t, report.BudgetUsed, 1.0)
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteText(&out))
+	assert.True(t, strings.Contains(out.String(), "FAIL error rate"), out.String())
+}
+
+func TestRunner_DropsBeyondMaxInFlight(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	profile := &Profile{
+		Stages:      []Stage{{Duration: Duration(200 * time.Millisecond), RPS: 500}},
+		Models:      []Model{{Model: "resnet18"}},
+		MaxInFlight: 5,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(300*time.Millisecond, cancel)
+	report, err := NewRunner(server.URL, "", server.Client(), 1).Run(ctx, profile)
+	require.NoError(t, err)
+
+	assert.Positive(t, report.Total.Dropped)
+	assert.Zero(t, report.Total.Requests, "requests interrupted by the end of the run are not counted")
+}
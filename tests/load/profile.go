@@ -0,0 +1,159 @@
+// Package load generates open-loop load against the API gateway and reports
+// latency percentiles and error budget consumption.
+//
+// A profile describes the load as a sequence of stages, each ramping the
+// request rate linearly from the previous stage's rate to its own, so one
+// format covers smoke runs, ramps to find the saturation point and
+// hours-long soaks at a constant rate. Requests are issued at the scheduled
+// rate regardless of how quickly the platform answers, so a slow system
+// shows up as latency and errors rather than as a lower request rate.
+package load
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Duration is a time.Duration that encodes as a Go duration string such as "30s"
+type Duration time.Duration
+
+// MarshalJSON encodes d as a duration string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Stage ramps the request rate linearly to RPS over Duration
+type Stage struct {
+	Duration Duration `json:"duration"`
+	RPS      float64  `json:"rps"`
+}
+
+// Model is a model version in the traffic mix, chosen in proportion to Weight
+type Model struct {
+	Model   string  `json:"model"`
+	Version string  `json:"version"`
+	Weight  float64 `json:"weight"`
+}
+
+// Batch configures batch job submissions mixed into the traffic
+type Batch struct {
+	// Ratio is the fraction of requests that submit a batch job
+	Ratio float64 `json:"ratio"`
+	// Size is the number of inputs per job
+	Size int `json:"size"`
+}
+
+// Budget is the error budget and latency objectives a run is checked against.
+// Zero values are not checked.
+type Budget struct {
+	MaxErrorRate float64  `json:"max_error_rate"`
+	P50          Duration `json:"p50"`
+	P95          Duration `json:"p95"`
+	P99          Duration `json:"p99"`
+}
+
+// Profile describes a load run
+type Profile struct {
+	Name   string  `json:"name"`
+	Stages []Stage `json:"stages"`
+	Models []Model `json:"models"`
+	// PayloadSizes are the numbers of input values per inference, chosen
+	// uniformly per request
+	PayloadSizes []int `json:"payload_sizes"`
+	Batch        Batch `json:"batch"`
+	// MaxInFlight bounds outstanding requests; requests scheduled while it is
+	// reached are counted as dropped rather than delayed
+	MaxInFlight int    `json:"max_in_flight"`
+	Budget      Budget `json:"budget"`
+}
+
+// LoadProfile reads a JSON profile
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+	var profile Profile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %s: %w", path, err)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %w", path, err)
+	}
+	return &profile, nil
+}
+
+// Validate checks the profile and fills in defaults
+func (p *Profile) Validate() error {
+	if len(p.Stages) == 0 {
+		return fmt.Errorf("at least one stage is required")
+	}
+	for i, stage := range p.Stages {
+		if stage.Duration <= 0 || stage.RPS < 0 {
+			return fmt.Errorf("stage %d needs a positive duration and a non-negative rate", i)
+		}
+	}
+	if len(p.Models) == 0 {
+		return fmt.Errorf("at least one model is required")
+	}
+	for _, model := range p.Models {
+		if model.Model == "" || model.Weight < 0 {
+			return fmt.Errorf("models need a name and a non-negative weight")
+		}
+	}
+	if p.Batch.Ratio < 0 || p.Batch.Ratio > 1 {
+		return fmt.Errorf("batch ratio must be between 0 and 1")
+	}
+	if p.Batch.Size <= 0 {
+		p.Batch.Size = 10
+	}
+	if len(p.PayloadSizes) == 0 {
+		p.PayloadSizes = []int{4}
+	}
+	if p.MaxInFlight <= 0 {
+		p.MaxInFlight = 1000
+	}
+	return nil
+}
+
+// Duration returns the total length of the run
+func (p *Profile) Duration() time.Duration {
+	var total time.Duration
+	for _, stage := range p.Stages {
+		total += time.Duration(stage.Duration)
+	}
+	return total
+}
+
+// rate returns the scheduled request rate at elapsed time into the run and
+// the index of the stage it falls in, or -1 once the run is over
+func (p *Profile) rate(elapsed time.Duration) (float64, int) {
+	var start time.Duration
+	var from float64
+	for i, stage := range p.Stages {
+		length := time.Duration(stage.Duration)
+		if elapsed < start+length {
+			progress := float64(elapsed-start) / float64(length)
+			return from + (stage.RPS-from)*progress, i
+		}
+		start += length
+		from = stage.RPS
+	}
+	return 0, -1
+}
@@ -0,0 +1,267 @@
+package load
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Percentiles summarises a latency distribution
+type Percentiles struct {
+	P50 Duration `json:"p50"`
+	P90 Duration `json:"p90"`
+	P95 Duration `json:"p95"`
+	P99 Duration `json:"p99"`
+	Max Duration `json:"max"`
+}
+
+// Stats summarises a set of requests. Responses other than 2xx and transport
+// errors are errors; requests dropped because MaxInFlight was reached count
+// against the error rate too, since they mean the platform is not keeping up.
+type Stats struct {
+	Requests  int64       `json:"requests"`
+	Errors    int64       `json:"errors"`
+	Dropped   int64       `json:"dropped"`
+	ErrorRate float64     `json:"error_rate"`
+	Latency   Percentiles `json:"latency"`
+	// Failures counts errors by status code, or "transport" for requests
+	// that got no response
+	Failures map[string]int64 `json:"failures,omitempty"`
+}
+
+// StageReport is the outcome of one stage of a profile
+type StageReport struct {
+	Stage int     `json:"stage"`
+	RPS   float64 `json:"rps"`
+	Stats
+}
+
+// Report is the outcome of a run
+type Report struct {
+	Profile string   `json:"profile"`
+	Elapsed Duration `json:"elapsed"`
+	// Throughput is the rate of completed requests
+	Throughput float64           `json:"throughput"`
+	Total      Stats             `json:"total"`
+	Kinds      map[string]*Stats `json:"kinds"`
+	Stages     []StageReport     `json:"stages"`
+	Budget     Budget            `json:"budget"`
+	// BudgetUsed is the error rate as a fraction of the budget's maximum
+	BudgetUsed float64 `json:"budget_used,omitempty"`
+	// Violations lists the budget objectives the run missed
+	Violations []string `json:"violations,omitempty"`
+}
+
+// Passed reports whether the run stayed within its budget
+func (r *Report) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// check fills in the budget consumption and violations
+func (r *Report) check() {
+	budget := r.Budget
+	if budget.MaxErrorRate > 0 {
+		r.BudgetUsed = r.Total.ErrorRate / budget.MaxErrorRate
+		if r.Total.ErrorRate > budget.MaxErrorRate {
+			r.Violations = append(r.Violations, fmt.Sprintf("error rate %.2f%% exceeds %.2f%%", r.Total.ErrorRate*100, budget.MaxErrorRate*100))
+		}
+	}
+	for _, objective := range []struct {
+		name         string
+		got, allowed Duration
+	}{
+		{"p50", r.Total.Latency.P50, budget.P50},
+		{"p95", r.Total.Latency.P95, budget.P95},
+		{"p99", r.Total.Latency.P99, budget.P99},
+	} {
+		if objective.allowed > 0 && objective.got > objective.allowed {
+			r.Violations = append(r.Violations, fmt.Sprintf("%s latency %v exceeds %v",
+				objective.name, time.Duration(objective.got), time.Duration(objective.allowed)))
+		}
+	}
+}
+
+// WriteText writes the report as tables
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "profile %s: %d requests in %v (%.1f/s)\n\n", r.Profile, r.Total.Requests, time.Duration(r.Elapsed).Round(time.Millisecond), r.Throughput)
+
+	fmt.Fprintln(tw, "\trequests\terrors\tdropped\terror rate\tp50\tp90\tp95\tp99\tmax")
+	row := func(name string, s Stats) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.2f%%\t%v\t%v\t%v\t%v\t%v\n", name, s.Requests, s.Errors, s.Dropped, s.ErrorRate*100,
+			round(s.Latency.P50), round(s.Latency.P90), round(s.Latency.P95), round(s.Latency.P99), round(s.Latency.Max))
+	}
+	for _, stage := range r.Stages {
+		row(fmt.Sprintf("stage %d (%g rps)", stage.Stage+1, stage.RPS), stage.Stats)
+	}
+	kinds := make([]string, 0, len(r.Kinds))
+	for kind := range r.Kinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		row(kind, *r.Kinds[kind])
+	}
+	row("total", r.Total)
+
+	if len(r.Total.Failures) > 0 {
+		fmt.Fprintln(tw, "\nfailures")
+		reasons := make([]string, 0, len(r.Total.Failures))
+		for reason := range r.Total.Failures {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			fmt.Fprintf(tw, "%s\t%d\n", reason, r.Total.Failures[reason])
+		}
+	}
+
+	if r.Budget.MaxErrorRate > 0 {
+		fmt.Fprintf(tw, "\nerror budget used: %.0f%%\n", r.BudgetUsed*100)
+	}
+	for _, violation := range r.Violations {
+		fmt.Fprintf(tw, "FAIL %s\n", violation)
+	}
+	return tw.Flush()
+}
+
+func round(d Duration) time.Duration {
+	return time.Duration(d).Round(100 * time.Microsecond)
+}
+
+// sample accumulates the requests of one stage, kind or the whole run
+type sample struct {
+	requests  int64
+	errors    int64
+	dropped   int64
+	failures  map[string]int64
+	latencies []time.Duration
+}
+
+func (s *sample) add(status int, latency time.Duration, err error) {
+	s.requests++
+	switch {
+	case err != nil:
+		s.errors++
+		s.failures["transport"]++
+		return
+	case status < 200 || status > 299:
+		s.errors++
+		s.failures[strconv.Itoa(status)]++
+	}
+	s.latencies = append(s.latencies, latency)
+}
+
+func (s *sample) stats() Stats {
+	stats := Stats{Requests: s.requests, Errors: s.errors, Dropped: s.dropped}
+	if attempted := s.requests + s.dropped; attempted > 0 {
+		stats.ErrorRate = float64(s.errors+s.dropped) / float64(attempted)
+	}
+	if len(s.failures) > 0 {
+		stats.Failures = s.failures
+	}
+
+	sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+	stats.Latency = Percentiles{
+		P50: percentile(s.latencies, 0.50),
+		P90: percentile(s.latencies, 0.90),
+		P95: percentile(s.latencies, 0.95),
+		P99: percentile(s.latencies, 0.99),
+		Max: percentile(s.latencies, 1),
+	}
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return Duration(sorted[rank])
+}
+
+// recorder collects the outcomes of a run's requests
+type recorder struct {
+	profile *Profile
+
+	mu     sync.Mutex
+	total  *sample
+	kinds  map[string]*sample
+	stages []*sample
+}
+
+func newRecorder(profile *Profile) *recorder {
+	r := &recorder{
+		profile: profile,
+		total:   newSample(),
+		kinds:   make(map[string]*sample),
+		stages:  make([]*sample, len(profile.Stages)),
+	}
+	for i := range r.stages {
+		r.stages[i] = newSample()
+	}
+	return r
+}
+
+func newSample() *sample {
+	return &sample{failures: make(map[string]int64)}
+}
+
+func (r *recorder) samples(stage int, kind string) []*sample {
+	if r.kinds[kind] == nil {
+		r.kinds[kind] = newSample()
+	}
+	return []*sample{r.total, r.kinds[kind], r.stages[stage]}
+}
+
+func (r *recorder) record(stage int, kind string, status int, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.samples(stage, kind) {
+		s.add(status, latency, err)
+	}
+}
+
+func (r *recorder) drop(stage int, kind string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.samples(stage, kind) {
+		s.dropped++
+	}
+}
+
+func (r *recorder) report(elapsed time.Duration) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &Report{
+		Profile: r.profile.Name,
+		Elapsed: Duration(elapsed),
+		Total:   r.total.stats(),
+		Kinds:   make(map[string]*Stats, len(r.kinds)),
+		Budget:  r.profile.Budget,
+	}
+	if elapsed > 0 {
+		report.Throughput = float64(report.Total.Requests) / elapsed.Seconds()
+	}
+	for kind, s := range r.kinds {
+		stats := s.stats()
+		report.Kinds[kind] = &stats
+	}
+	for i, s := range r.stages {
+		report.Stages = append(report.Stages, StageReport{Stage: i, RPS: r.profile.Stages[i].RPS, Stats: s.stats()})
+	}
+	report.check()
+	return report
+}
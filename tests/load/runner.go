@@ -0,0 +1,168 @@
+package load
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tick is how often the scheduler issues the requests that have come due
+const tick = 5 * time.Millisecond
+
+// Kinds of request
+const (
+	KindRealtime = "realtime"
+	KindBatch    = "batch"
+)
+
+// Runner issues the requests of a profile against an API gateway
+type Runner struct {
+	baseURL string
+	token   string
+	client  *http.Client
+	rand    *rand.Rand
+}
+
+// NewRunner creates a runner for the gateway at baseURL that authenticates
+// with token. Seeding makes the traffic mix of a run reproducible.
+func NewRunner(baseURL, token string, client *http.Client, seed int64) *Runner {
+	return &Runner{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  client,
+		rand:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Run executes the profile until its last stage ends or ctx is cancelled, and
+// reports on the requests completed
+func (r *Runner) Run(ctx context.Context, profile *Profile) (*Report, error) {
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+
+	recorder := newRecorder(profile)
+	inFlight := make(chan struct{}, profile.MaxInFlight)
+	var wg sync.WaitGroup
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	start := time.Now()
+	last := start
+	var due float64
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case now := <-ticker.C:
+			rate, stage := profile.rate(now.Sub(start))
+			if stage < 0 {
+				break loop
+			}
+			due += rate * now.Sub(last).Seconds()
+			last = now
+
+			for ; due >= 1; due-- {
+				kind, path, body := r.next(profile)
+				select {
+				case inFlight <- struct{}{}:
+				default:
+					recorder.drop(stage, kind)
+					continue
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-inFlight }()
+					status, latency, err := r.send(ctx, path, body)
+					if err != nil && ctx.Err() != nil {
+						// Interrupted by the end of the run, not failed
+						return
+					}
+					recorder.record(stage, kind, status, latency, err)
+				}()
+			}
+		}
+	}
+
+	wg.Wait()
+	return recorder.report(time.Since(start)), nil
+}
+
+// next picks the kind, model and payload of the next request
+func (r *Runner) next(profile *Profile) (kind, path string, body []byte) {
+	model := r.pickModel(profile.Models)
+	input := func() map[string]interface{} {
+		size := profile.PayloadSizes[r.rand.Intn(len(profile.PayloadSizes))]
+		data := make([]float64, size)
+		for i := range data {
+			data[i] = r.rand.Float64()
+		}
+		return map[string]interface{}{"data": data}
+	}
+
+	request := map[string]interface{}{"model": model.Model, "version": model.Version}
+	if r.rand.Float64() < profile.Batch.Ratio {
+		inputs := make([]map[string]interface{}, profile.Batch.Size)
+		for i := range inputs {
+			inputs[i] = input()
+		}
+		request["inputs"] = inputs
+		kind, path = KindBatch, "/v1/batch"
+	} else {
+		request["input"] = input()
+		kind, path = KindRealtime, "/v1/infer"
+	}
+
+	body, _ = json.Marshal(request)
+	return kind, path, body
+}
+
+func (r *Runner) pickModel(models []Model) Model {
+	var total float64
+	for _, model := range models {
+		total += model.Weight
+	}
+	if total == 0 {
+		return models[r.rand.Intn(len(models))]
+	}
+	pick := r.rand.Float64() * total
+	for _, model := range models {
+		if pick < model.Weight {
+			return model
+		}
+		pick -= model.Weight
+	}
+	return models[len(models)-1]
+}
+
+// send issues a request and returns its status and latency
+func (r *Runner) send(ctx context.Context, path string, body []byte) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, time.Since(start), err
+	}
+	defer resp.Body.Close()
+	// Latency includes reading the response, as a client would
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, time.Since(start), nil
+}
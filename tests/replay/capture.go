@@ -0,0 +1,107 @@
+// Package replay replays captured gateway traffic against another deployment
+// and diffs the responses and latencies per model.
+//
+// Captures are the JSON lines files the API gateway writes when CAPTURE_PATH
+// is set: one inference log record per real-time inference, holding the
+// request's model, version and input along with the prediction or error code
+// the caller received. A replay sends the records, in capture order, to
+// /v1/infer on the target and compares each outcome with the captured one.
+// Predictions are compared structurally, with a tolerance for numbers, and
+// fields that legitimately differ between runs can be ignored.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// maxLine bounds a capture line; the gateway caps records well below it
+const maxLine = 16 << 20
+
+// Record is a captured inference, as written by the gateway's capture mode
+type Record struct {
+	ID        string                 `json:"id"`
+	Timestamp time.Time              `json:"timestamp"`
+	Tenant    string                 `json:"tenant,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Model     string                 `json:"model"`
+	Version   string                 `json:"version"`
+	Input     map[string]interface{} `json:"input,omitempty"`
+	Output    map[string]interface{} `json:"output,omitempty"`
+	LatencyMs int64                  `json:"latency_ms"`
+	// Error is the error code the caller received; empty for successes
+	Error string `json:"error,omitempty"`
+	// Truncated records lost their payloads to the capture size limit
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Capture is a set of records to replay
+type Capture struct {
+	Records []Record
+	// Skipped counts records that cannot be replayed because their input
+	// was not captured
+	Skipped int
+}
+
+// LoadCapture reads capture files and orders their records by capture time,
+// so a replay issues them in the order production saw them. Only records for
+// models are kept when any are given.
+func LoadCapture(paths []string, models []string) (*Capture, error) {
+	keep := make(map[string]bool, len(models))
+	for _, model := range models {
+		keep[model] = true
+	}
+
+	capture := &Capture{}
+	for _, path := range paths {
+		if err := capture.read(path, keep); err != nil {
+			return nil, err
+		}
+	}
+
+	// Ties are broken by ID so the order does not depend on file order
+	sort.SliceStable(capture.Records, func(i, j int) bool {
+		a, b := capture.Records[i], capture.Records[j]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.Before(b.Timestamp)
+		}
+		return a.ID < b.ID
+	})
+	return capture, nil
+}
+
+func (c *Capture) read(path string, keep map[string]bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open capture: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64<<10), maxLine)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("%s:%d: invalid record: %w", path, line, err)
+		}
+		if len(keep) > 0 && !keep[record.Model] {
+			continue
+		}
+		if record.Model == "" || record.Truncated || record.Input == nil {
+			c.Skipped++
+			continue
+		}
+		c.Records = append(c.Records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read capture %s: %w", path, err)
+	}
+	return nil
+}
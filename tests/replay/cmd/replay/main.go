@@ -0,0 +1,112 @@
+// Command replay replays traffic captured by the API gateway against another
+// deployment and reports per model how many responses differ and how latency
+// moved. It exits non-zero when the replay misses its budget, so it can gate
+// a deployment.
+//
+//	go run ./replay/cmd/replay -url https://gateway.staging capture-*.jsonl
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/ai-platform/tests/replay"
+)
+
+func main() {
+	baseURL := flag.String("url", getEnv("API_GATEWAY_URL", "http://localhost:8080"), "API gateway base URL of the target deployment")
+	token := flag.String("token", getEnv("REPLAY_TOKEN", "demo-token"), "bearer token sent with every request")
+	timeout := flag.Duration("timeout", 30*time.Second, "per-request timeout")
+	concurrency := flag.Int("concurrency", 1, "requests in flight")
+	models := flag.String("models", "", "comma-separated models to replay (default all)")
+	ignore := flag.String("ignore", "", "comma-separated prediction fields not compared")
+	tolerance := flag.Float64("tolerance", 1e-6, "relative drift allowed in numeric predictions")
+	maxMismatch := flag.Float64("max-mismatch-rate", 0, "fraction of responses per model that may differ")
+	maxRegression := flag.Float64("max-latency-regression", 0, "relative p95 latency growth allowed per model, 0 to skip")
+	format := flag.String("format", "text", "report format: text or json")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] capture.jsonl...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	capture, err := replay.LoadCapture(flag.Args(), split(*models))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if len(capture.Records) == 0 {
+		fmt.Fprintln(os.Stderr, "no replayable records in capture")
+		os.Exit(2)
+	}
+
+	// Interrupting a replay still reports on the requests made so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := &http.Client{
+		Timeout: *timeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        *concurrency,
+			MaxIdleConnsPerHost: *concurrency,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+	fmt.Fprintf(os.Stderr, "replaying %d records against %s\n", len(capture.Records), *baseURL)
+
+	replayer := replay.NewReplayer(*baseURL, *token, client, replay.Options{
+		Concurrency: *concurrency,
+		Tolerance:   *tolerance,
+		Ignore:      split(*ignore),
+	})
+	report := replayer.Run(ctx, capture, replay.Budget{
+		MaxMismatchRate:      *maxMismatch,
+		MaxLatencyRegression: *maxRegression,
+	})
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	default:
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if !report.Passed() {
+		os.Exit(1)
+	}
+}
+
+func split(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,116 @@
+package replay
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Difference is a value that differs between the captured and replayed response
+type Difference struct {
+	Path     string      `json:"path"`
+	Captured interface{} `json:"captured"`
+	Replayed interface{} `json:"replayed"`
+}
+
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: %v != %v", path, describe(d.Captured), describe(d.Replayed))
+}
+
+// differ compares decoded JSON values
+type differ struct {
+	// tolerance is how far numbers may drift, relative to their magnitude
+	// and absolute below 1
+	tolerance float64
+	// ignore names fields, at any depth, that are not compared
+	ignore map[string]bool
+}
+
+// diff returns the differences between captured and replayed, ordered by path
+func (d *differ) diff(captured, replayed interface{}) []Difference {
+	var out []Difference
+	d.walk("", captured, replayed, &out)
+	return out
+}
+
+func (d *differ) walk(path string, captured, replayed interface{}, out *[]Difference) {
+	switch c := captured.(type) {
+	case map[string]interface{}:
+		r, ok := replayed.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(c)+len(r))
+		for key := range c {
+			keys = append(keys, key)
+		}
+		for key := range r {
+			if _, ok := c[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if d.ignore[key] {
+				continue
+			}
+			d.walk(join(path, key), c[key], r[key], out)
+		}
+		return
+	case []interface{}:
+		r, ok := replayed.([]interface{})
+		if !ok || len(r) != len(c) {
+			break
+		}
+		for i := range c {
+			d.walk(path+"["+strconv.Itoa(i)+"]", c[i], r[i], out)
+		}
+		return
+	case float64:
+		if r, ok := replayed.(float64); ok && d.close(c, r) {
+			return
+		}
+	default:
+		if reflect.DeepEqual(captured, replayed) {
+			return
+		}
+	}
+	*out = append(*out, Difference{Path: path, Captured: captured, Replayed: replayed})
+}
+
+func (d *differ) close(a, b float64) bool {
+	if a == b {
+		return true
+	}
+	scale := math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	return math.Abs(a-b) <= d.tolerance*scale
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// describe shortens values for the text report
+func describe(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "missing"
+	case map[string]interface{}:
+		return fmt.Sprintf("object(%d fields)", len(v))
+	case []interface{}:
+		return fmt.Sprintf("array(%d)", len(v))
+	case string:
+		return strconv.Quote(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,178 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCapture(t *testing.T, records ...Record) string {
+	var buf bytes.Buffer
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		require.NoError(t, err)
+		buf.Write(append(line, '\n'))
+	}
+	path := filepath.Join(t.TempDir(), "capture.jsonl")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+	return path
+}
+
+func TestLoadCapture_OrdersAndFilters(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	input := map[string]interface{}{"data": []interface{}{1.0}}
+	first := writeCapture(t,
+		Record{ID: "c", Timestamp: base.Add(2 * time.Second), Model: "resnet18", Version: "v1", Input: input},
+		Record{ID: "b", Timestamp: base, Model: "resnet18", Version: "v1", Input: input},
+		Record{ID: "t", Timestamp: base, Model: "resnet18", Version: "v1", Truncated: true},
+	)
+	second := writeCapture(t,
+		Record{ID: "a", Timestamp: base, Model: "resnet18", Version: "v1", Input: input},
+		Record{ID: "z", Timestamp: base, Model: "bert", Version: "v1", Input: input},
+	)
+
+	capture, err := LoadCapture([]string{first, second}, []string{"resnet18"})
+	require.NoError(t, err)
+
+	var ids []string
+	for _, record := range capture.Records {
+		ids = append(ids, record.ID)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+	assert.Equal(t, 1, capture.Skipped, "truncated records cannot be replayed")
+}
+
+func TestDiffer(t *testing.T) {
+	d := &differ{tolerance: 1e-3, ignore: map[string]bool{"took_ms": true}}
+	decode := func(s string) interface{} {
+		var v interface{}
+		require.NoError(t, json.Unmarshal([]byte(s), &v))
+		return v
+	}
+
+	assert.Empty(t, d.diff(
+		decode(`{"class":"cat","scores":[0.9,0.1],"took_ms":12}`),
+		decode(`{"class":"cat","scores":[0.90001,0.1],"took_ms":40}`),
+	))
+
+	differences := d.diff(
+		decode(`{"class":"cat","scores":[0.9,0.1],"meta":{"a":1}}`),
+		decode(`{"class":"dog","scores":[0.5,0.1],"extra":true,"meta":{"a":1}}`),
+	)
+	var paths []string
+	for _, difference := range differences {
+		paths = append(paths, difference.Path)
+	}
+	assert.Equal(t, []string{"class", "extra", "scores[0]"}, paths)
+	assert.Equal(t, `class: "cat" != "dog"`, differences[0].String())
+	assert.Equal(t, "extra: missing != true", differences[1].String())
+
+	differences = d.diff(decode(`{"scores":[1,2]}`), decode(`{"scores":[1,2,3]}`))
+	require.Len(t, differences, 1)
+	assert.Equal(t, "scores: array(2) != array(3)", differences[0].String())
+}
+
+// fakeGateway answers inferences with a prediction per model, or fails them
+func fakeGateway(t *testing.T, predictions map[string]string, latencyMs int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/infer", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("X-Request-ID"), "replay-"))
+
+		var req struct {
+			Model string `json:"model"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		prediction, ok := predictions[req.Model]
+		if !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"code":"unavailable","error":"backend unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"request_id":"x","model":"` + req.Model + `","prediction":` + prediction + `,"latency_ms":` + jsonInt(latencyMs) + `}`))
+	}))
+}
+
+func jsonInt(v int64) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func TestReplayer_ReportsMismatchesPerModel(t *testing.T) {
+	input := map[string]interface{}{"data": []interface{}{1.0}}
+	capture := &Capture{Records: []Record{
+		{ID: "1", Model: "resnet18", Version: "v1", Input: input, Output: map[string]interface{}{"class": "cat"}, LatencyMs: 10},
+		{ID: "2", Model: "resnet18", Version: "v1", Input: input, Output: map[string]interface{}{"class": "cat"}, LatencyMs: 10},
+		{ID: "3", Model: "bert", Version: "v1", Input: input, Output: map[string]interface{}{"label": "positive"}, LatencyMs: 20},
+		{ID: "4", Model: "gpt2", Version: "v1", Input: input, Error: "unavailable", LatencyMs: 5},
+	}}
+	server := fakeGateway(t, map[string]string{
+		"resnet18": `{"class":"cat"}`,
+		"bert":     `{"label":"negative"}`,
+	}, 12)
+	defer server.Close()
+
+	report := NewReplayer(server.URL, "token", server.Client(), Options{Concurrency: 3}).
+		Run(context.Background(), capture, Budget{})
+
+	assert.Equal(t, 4, report.Requests)
+	assert.Equal(t, 1, report.Mismatches)
+	require.Len(t, report.Models, 3)
+	assert.Equal(t, "bert/v1", report.Models[0].Model)
+	assert.Equal(t, 1, report.Models[0].Mismatches)
+	assert.Equal(t, "gpt2/v1", report.Models[1].Model)
+	assert.Zero(t, report.Models[1].Mismatches, "a request failing the same way matches")
+	assert.Equal(t, "resnet18/v1", report.Models[2].Model)
+	assert.Zero(t, report.Models[2].Mismatches)
+
+	// Latency comes from the gateway's own measurement
+	assert.Equal(t, 12*time.Millisecond, time.Duration(report.Models[2].Replayed.P95))
+	assert.InDelta(t, 0.2, report.Models[2].P95Change, 0.001)
+
+	require.Len(t, report.Examples, 1)
+	assert.Equal(t, 2, report.Examples[0].Index)
+	assert.Equal(t, "label", report.Examples[0].Differences[0].Path)
+
+	assert.False(t, report.Passed())
+	require.Len(t, report.Violations, 1)
+	assert.Contains(t, report.Violations[0], "bert/v1")
+
+	var text bytes.Buffer
+	require.NoError(t, report.WriteText(&text))
+	assert.Contains(t, text.String(), `#2 bert/v1  label: "positive" != "negative"`)
+	assert.Contains(t, text.String(), "FAIL bert/v1")
+}
+
+func TestReplayer_BudgetAndErrorChanges(t *testing.T) {
+	input := map[string]interface{}{"data": []interface{}{1.0}}
+	var records []Record
+	for i := 0; i < 4; i++ {
+		records = append(records, Record{ID: jsonInt(int64(i)), Model: "resnet18", Version: "v1", Input: input, Output: map[string]interface{}{"class": "cat"}, LatencyMs: 10})
+	}
+	// Succeeded in production, fails on the target
+	records = append(records, Record{ID: "9", Model: "bert", Version: "v1", Input: input, Output: map[string]interface{}{"label": "positive"}, LatencyMs: 10})
+	server := fakeGateway(t, map[string]string{"resnet18": `{"class":"cat"}`}, 15)
+	defer server.Close()
+
+	report := NewReplayer(server.URL, "token", server.Client(), Options{}).
+		Run(context.Background(), &Capture{Records: records}, Budget{MaxMismatchRate: 0.5, MaxLatencyRegression: 0.2})
+
+	require.Len(t, report.Models, 2)
+	assert.Equal(t, 1, report.Models[0].ErrorChanges)
+	assert.Equal(t, "unavailable", report.Examples[0].ReplayedError)
+
+	// bert differs on every request and resnet18 got 50% slower
+	require.Len(t, report.Violations, 2)
+	assert.Contains(t, report.Violations[0], "bert/v1: 1 of 1 responses differ")
+	assert.Contains(t, report.Violations[1], "resnet18/v1: p95 latency 15ms is 50% above")
+}
@@ -0,0 +1,211 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/ai-platform/tests/load"
+)
+
+// Outcomes recorded as the replayed error besides the platform's error codes
+const (
+	ErrorTransport = "transport"
+	ErrorDecode    = "invalid_response"
+)
+
+// Options configure how responses are compared
+type Options struct {
+	// Concurrency is how many requests are in flight; results do not depend
+	// on it, but a target under more load may answer more slowly
+	Concurrency int
+	// Tolerance is how far numbers in predictions may drift, relative to
+	// their magnitude and absolute below 1
+	Tolerance float64
+	// Ignore names prediction fields, at any depth, that are not compared,
+	// such as timings or backend identifiers
+	Ignore []string
+}
+
+// Result is the outcome of replaying one record
+type Result struct {
+	Index    int    `json:"index"`
+	RecordID string `json:"record_id,omitempty"`
+	Model    string `json:"model"`
+	Version  string `json:"version"`
+	// CapturedError and ReplayedError are the error codes of the two
+	// responses, empty for successes
+	CapturedError   string        `json:"captured_error,omitempty"`
+	ReplayedError   string        `json:"replayed_error,omitempty"`
+	CapturedLatency load.Duration `json:"captured_latency"`
+	ReplayedLatency load.Duration `json:"replayed_latency"`
+	Differences     []Difference  `json:"differences,omitempty"`
+}
+
+// Mismatch reports whether the replayed response differs from the captured one
+func (r *Result) Mismatch() bool {
+	return r.CapturedError != r.ReplayedError || len(r.Differences) > 0
+}
+
+// Replayer sends captured records to an API gateway
+type Replayer struct {
+	baseURL string
+	token   string
+	client  *http.Client
+	opts    Options
+	differ  *differ
+}
+
+// NewReplayer creates a replayer for the gateway at baseURL that
+// authenticates with token
+func NewReplayer(baseURL, token string, client *http.Client, opts Options) *Replayer {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	ignore := make(map[string]bool, len(opts.Ignore))
+	for _, field := range opts.Ignore {
+		ignore[field] = true
+	}
+	return &Replayer{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  client,
+		opts:    opts,
+		differ:  &differ{tolerance: opts.Tolerance, ignore: ignore},
+	}
+}
+
+// Run replays the capture in order until it is exhausted or ctx is cancelled,
+// and reports on the records replayed against budget
+func (r *Replayer) Run(ctx context.Context, capture *Capture, budget Budget) *Report {
+	results := make([]*Result, len(capture.Records))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < r.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				result := r.replay(ctx, index, capture.Records[index])
+				if result.ReplayedError == ErrorTransport && ctx.Err() != nil {
+					// Interrupted by the end of the run, not failed
+					continue
+				}
+				results[index] = result
+			}
+		}()
+	}
+
+	start := time.Now()
+issue:
+	for i := range capture.Records {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break issue
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	completed := make([]*Result, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			completed = append(completed, result)
+		}
+	}
+	return newReport(r.baseURL, completed, capture.Skipped, time.Since(start), budget)
+}
+
+// replay sends one record and compares the response with the captured one
+func (r *Replayer) replay(ctx context.Context, index int, record Record) *Result {
+	result := &Result{
+		Index:           index,
+		RecordID:        record.ID,
+		Model:           record.Model,
+		Version:         record.Version,
+		CapturedError:   record.Error,
+		CapturedLatency: load.Duration(time.Duration(record.LatencyMs) * time.Millisecond),
+	}
+
+	prediction, latency, code := r.send(ctx, record)
+	result.ReplayedLatency = load.Duration(latency)
+	result.ReplayedError = code
+	if code == "" && record.Error == "" {
+		result.Differences = r.differ.diff(record.Output, prediction)
+	}
+	return result
+}
+
+// send issues the record's inference and returns the prediction, the
+// latency and the error code of the response. The gateway's own latency
+// measurement is used when the response has one, so the comparison with
+// captured latencies is not skewed by where the replay runs from.
+func (r *Replayer) send(ctx context.Context, record Record) (map[string]interface{}, time.Duration, string) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model":   record.Model,
+		"version": record.Version,
+		"input":   record.Input,
+	})
+	if err != nil {
+		return nil, 0, ErrorDecode
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+"/v1/infer", bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, ErrorTransport
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	// Replayed requests can be found in the target's logs by capture record
+	if record.ID != "" {
+		req.Header.Set("X-Request-ID", "replay-"+record.ID)
+	}
+
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, time.Since(start), ErrorTransport
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	latency := time.Since(start)
+	if err != nil {
+		return nil, latency, ErrorTransport
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Code string `json:"code"`
+		}
+		if json.Unmarshal(raw, &failure) != nil || failure.Code == "" {
+			return nil, latency, "http_" + strconv.Itoa(resp.StatusCode)
+		}
+		return nil, latency, failure.Code
+	}
+
+	var response struct {
+		Prediction map[string]interface{} `json:"prediction"`
+		LatencyMs  *int64                 `json:"latency_ms"`
+	}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, latency, ErrorDecode
+	}
+	if response.LatencyMs != nil {
+		latency = time.Duration(*response.LatencyMs) * time.Millisecond
+	}
+	return response.Prediction, latency, ""
+}
+
+// key identifies a model version in reports
+func key(model, version string) string {
+	return fmt.Sprintf("%s/%s", model, version)
+}
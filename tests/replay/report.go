@@ -0,0 +1,202 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yourusername/ai-platform/tests/load"
+)
+
+// maxExamples bounds the mismatched requests listed in a report
+const maxExamples = 20
+
+// Budget is what a replay is checked against, per model version
+type Budget struct {
+	// MaxMismatchRate is the fraction of requests whose response may differ
+	// from the captured one; zero allows none
+	MaxMismatchRate float64 `json:"max_mismatch_rate"`
+	// MaxLatencyRegression is how much p95 latency may grow relative to the
+	// capture, e.g. 0.2 for 20%; zero is not checked
+	MaxLatencyRegression float64 `json:"max_latency_regression"`
+}
+
+// Latency summarises a latency distribution
+type Latency struct {
+	P50 load.Duration `json:"p50"`
+	P95 load.Duration `json:"p95"`
+	P99 load.Duration `json:"p99"`
+}
+
+// ModelReport compares the replay of one model version with its capture
+type ModelReport struct {
+	Model        string  `json:"model"`
+	Requests     int     `json:"requests"`
+	Mismatches   int     `json:"mismatches"`
+	MismatchRate float64 `json:"mismatch_rate"`
+	// ErrorChanges counts requests that succeeded in one run and failed, or
+	// failed differently, in the other
+	ErrorChanges int     `json:"error_changes"`
+	Captured     Latency `json:"captured_latency"`
+	Replayed     Latency `json:"replayed_latency"`
+	// P95Change is the relative change of p95 latency
+	P95Change float64 `json:"p95_change"`
+}
+
+// Report is the outcome of a replay
+type Report struct {
+	Target       string        `json:"target"`
+	Elapsed      load.Duration `json:"elapsed"`
+	Requests     int           `json:"requests"`
+	Skipped      int           `json:"skipped"`
+	Mismatches   int           `json:"mismatches"`
+	MismatchRate float64       `json:"mismatch_rate"`
+	Models       []ModelReport `json:"models"`
+	// Examples are the first mismatched requests, in capture order
+	Examples []*Result `json:"examples,omitempty"`
+	Budget   Budget    `json:"budget"`
+	// Violations lists the models and objectives the replay missed
+	Violations []string `json:"violations,omitempty"`
+}
+
+// Passed reports whether the replay stayed within its budget
+func (r *Report) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+func newReport(target string, results []*Result, skipped int, elapsed time.Duration, budget Budget) *Report {
+	report := &Report{
+		Target:   target,
+		Elapsed:  load.Duration(elapsed),
+		Requests: len(results),
+		Skipped:  skipped,
+		Budget:   budget,
+	}
+
+	byModel := make(map[string][]*Result)
+	for _, result := range results {
+		model := key(result.Model, result.Version)
+		byModel[model] = append(byModel[model], result)
+		if result.Mismatch() {
+			report.Mismatches++
+			if len(report.Examples) < maxExamples {
+				report.Examples = append(report.Examples, result)
+			}
+		}
+	}
+	if report.Requests > 0 {
+		report.MismatchRate = float64(report.Mismatches) / float64(report.Requests)
+	}
+
+	models := make([]string, 0, len(byModel))
+	for model := range byModel {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	for _, model := range models {
+		report.Models = append(report.Models, modelReport(model, byModel[model]))
+	}
+	report.check()
+	return report
+}
+
+func modelReport(model string, results []*Result) ModelReport {
+	m := ModelReport{Model: model, Requests: len(results)}
+	captured := make([]time.Duration, 0, len(results))
+	replayed := make([]time.Duration, 0, len(results))
+	for _, result := range results {
+		if result.Mismatch() {
+			m.Mismatches++
+		}
+		if result.CapturedError != result.ReplayedError {
+			m.ErrorChanges++
+		}
+		captured = append(captured, time.Duration(result.CapturedLatency))
+		replayed = append(replayed, time.Duration(result.ReplayedLatency))
+	}
+	m.MismatchRate = float64(m.Mismatches) / float64(m.Requests)
+	m.Captured = latency(captured)
+	m.Replayed = latency(replayed)
+	if m.Captured.P95 > 0 {
+		m.P95Change = float64(m.Replayed.P95-m.Captured.P95) / float64(m.Captured.P95)
+	}
+	return m
+}
+
+// check fills in the violations
+func (r *Report) check() {
+	for _, m := range r.Models {
+		if m.MismatchRate > r.Budget.MaxMismatchRate {
+			r.Violations = append(r.Violations, fmt.Sprintf("%s: %d of %d responses differ (%.2f%%, allowed %.2f%%)",
+				m.Model, m.Mismatches, m.Requests, m.MismatchRate*100, r.Budget.MaxMismatchRate*100))
+		}
+		if r.Budget.MaxLatencyRegression > 0 && m.Captured.P95 > 0 && m.P95Change > r.Budget.MaxLatencyRegression {
+			r.Violations = append(r.Violations, fmt.Sprintf("%s: p95 latency %v is %.0f%% above the captured %v (allowed %.0f%%)",
+				m.Model, time.Duration(m.Replayed.P95), m.P95Change*100, time.Duration(m.Captured.P95), r.Budget.MaxLatencyRegression*100))
+		}
+	}
+}
+
+// WriteText writes the report as tables
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "replayed %d requests against %s in %v (%d skipped)\n\n", r.Requests, r.Target, time.Duration(r.Elapsed).Round(time.Millisecond), r.Skipped)
+
+	fmt.Fprintln(tw, "model\trequests\tmismatches\terror changes\tcaptured p50\tp95\treplayed p50\tp95\tp95 change")
+	for _, m := range r.Models {
+		fmt.Fprintf(tw, "%s\t%d\t%d (%.2f%%)\t%d\t%v\t%v\t%v\t%v\t%+.0f%%\n", m.Model, m.Requests, m.Mismatches, m.MismatchRate*100, m.ErrorChanges,
+			time.Duration(m.Captured.P50), time.Duration(m.Captured.P95), time.Duration(m.Replayed.P50), time.Duration(m.Replayed.P95), m.P95Change*100)
+	}
+
+	if len(r.Examples) > 0 {
+		fmt.Fprintf(tw, "\nmismatches (first %d)\n", len(r.Examples))
+		for _, result := range r.Examples {
+			name := fmt.Sprintf("#%d %s", result.Index, key(result.Model, result.Version))
+			if result.CapturedError != result.ReplayedError {
+				fmt.Fprintf(tw, "%s\toutcome: %s != %s\n", name, outcome(result.CapturedError), outcome(result.ReplayedError))
+				continue
+			}
+			for _, difference := range result.Differences {
+				fmt.Fprintf(tw, "%s\t%s\n", name, difference)
+			}
+		}
+	}
+
+	for _, violation := range r.Violations {
+		fmt.Fprintf(tw, "FAIL %s\n", violation)
+	}
+	return tw.Flush()
+}
+
+func outcome(code string) string {
+	if code == "" {
+		return "ok"
+	}
+	return code
+}
+
+// latency summarises durations with nearest-rank percentiles
+func latency(durations []time.Duration) Latency {
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return Latency{
+		P50: percentile(durations, 0.50),
+		P95: percentile(durations, 0.95),
+		P99: percentile(durations, 0.99),
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) load.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return load.Duration(sorted[rank])
+}